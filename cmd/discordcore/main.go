@@ -12,10 +12,12 @@ import (
 var runDiscordMain = discordcoreapp.RunWithOptions
 
 func main() {
-	if err := run(os.Args[1:], os.Stderr); err != nil {
-		slog.Error("Fatal", "err", err)
-		os.Exit(1)
+	err := run(os.Args[1:], os.Stderr)
+	code := runtimecmd.ExitCode(err)
+	if code != runtimecmd.ExitOK {
+		slog.Error("Fatal", "err", err, "exit_code", code)
 	}
+	os.Exit(code)
 }
 
 func run(args []string, output io.Writer) error {
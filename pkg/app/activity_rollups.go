@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/system"
+)
+
+const (
+	// activityRollupOffset is how long after midnight UTC the nightly
+	// rollup runs, leaving the last writes of the day time to land.
+	activityRollupOffset = 30 * time.Minute
+
+	// activityRollupLookback bounds how far back rollups are kept filled:
+	// a bit over a year of weeks and months.
+	activityRollupLookback = 400 * 24 * time.Hour
+)
+
+// activityRollupMaintainer fills the weekly and monthly activity rollups of
+// the guilds this instance logs every night. Only missing rollups are
+// computed, so a pass after a backfill recomputes just the periods the
+// backfill invalidated.
+type activityRollupMaintainer struct {
+	instanceID    string
+	store         system.ActivityRollupRepository
+	configManager *files.ConfigManager
+	now           func() time.Time
+}
+
+func newActivityRollupMaintainer(instanceID string, store system.ActivityRollupRepository, configManager *files.ConfigManager) *activityRollupMaintainer {
+	return &activityRollupMaintainer{
+		instanceID:    instanceID,
+		store:         store,
+		configManager: configManager,
+		now:           time.Now,
+	}
+}
+
+// nextActivityRollup returns the first nightly rollup time after now.
+func nextActivityRollup(now time.Time) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Add(activityRollupOffset)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+func (m *activityRollupMaintainer) pass(ctx context.Context) {
+	cfg := m.configManager.Config()
+	if cfg == nil {
+		return
+	}
+	now := m.now()
+	spans := system.CompleteRollups(now.Add(-activityRollupLookback), now)
+	for _, guild := range files.GuildsForBotInstanceFeature(cfg, m.instanceID, "logging") {
+		if ctx.Err() != nil {
+			return
+		}
+		written, err := m.store.RefreshActivityRollups(ctx, guild.GuildID, spans)
+		if err != nil {
+			slog.Warn("Mitigated service degradation: Activity rollups not refreshed",
+				slog.String("botInstanceID", m.instanceID),
+				slog.String("guildID", guild.GuildID),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		if written > 0 {
+			slog.Info("Architectural state transition: Activity rollups refreshed",
+				slog.String("botInstanceID", m.instanceID),
+				slog.String("guildID", guild.GuildID),
+				slog.Int64("rollups", written),
+			)
+		}
+	}
+}
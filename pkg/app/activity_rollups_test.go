@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/system"
+)
+
+type fakeActivityRollupStore struct {
+	spans map[string][]system.ActivitySpan
+}
+
+func (f *fakeActivityRollupStore) RefreshActivityRollups(_ context.Context, guildID string, spans []system.ActivitySpan) (int64, error) {
+	f.spans[guildID] = spans
+	return int64(len(spans)), nil
+}
+
+func (f *fakeActivityRollupStore) InvalidateActivityRollups(context.Context, string, time.Time) error {
+	return nil
+}
+
+func TestNextActivityRollup(t *testing.T) {
+	t.Parallel()
+	before := time.Date(2026, 10, 16, 0, 10, 0, 0, time.UTC)
+	if got := nextActivityRollup(before); !got.Equal(time.Date(2026, 10, 16, 0, 30, 0, 0, time.UTC)) {
+		t.Fatalf("nextActivityRollup(%v) = %v", before, got)
+	}
+	after := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	if got := nextActivityRollup(after); !got.Equal(time.Date(2026, 10, 17, 0, 30, 0, 0, time.UTC)) {
+		t.Fatalf("nextActivityRollup(%v) = %v", after, got)
+	}
+}
+
+func TestActivityRollupMaintainerPass(t *testing.T) {
+	t.Parallel()
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{{GuildID: "1"}}})
+
+	store := &fakeActivityRollupStore{spans: map[string][]system.ActivitySpan{}}
+	maintainer := newActivityRollupMaintainer("", store, cfgMgr)
+	maintainer.now = func() time.Time { return time.Date(2026, 10, 16, 0, 30, 0, 0, time.UTC) }
+	maintainer.pass(context.Background())
+
+	spans := store.spans["1"]
+	if len(spans) == 0 {
+		t.Fatal("expected rollups to be refreshed for guild 1")
+	}
+	var lastMonth, lastWeek system.ActivitySpan
+	for _, span := range spans {
+		switch span.Period {
+		case system.RollupMonth:
+			lastMonth = span
+		case system.RollupWeek:
+			lastWeek = span
+		default:
+			t.Fatalf("unexpected span %+v", span)
+		}
+	}
+	// Only complete periods are rolled up: September, not October, and the
+	// week of October 5, not the current one.
+	if !lastMonth.Start.Equal(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("last month rolled up starts %v", lastMonth.Start)
+	}
+	if !lastWeek.Start.Equal(time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)) || lastWeek.Start.Weekday() != time.Monday {
+		t.Fatalf("last week rolled up starts %v", lastWeek.Start)
+	}
+}
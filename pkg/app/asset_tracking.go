@@ -0,0 +1,171 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/assets"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+)
+
+// Audit log actions for stickers and soundboard sounds, which arikawa does
+// not name.
+const (
+	auditStickerCreate discord.AuditLogEvent = 90
+	auditStickerUpdate discord.AuditLogEvent = 91
+	auditStickerDelete discord.AuditLogEvent = 92
+	auditSoundCreate   discord.AuditLogEvent = 130
+	auditSoundUpdate   discord.AuditLogEvent = 131
+	auditSoundDelete   discord.AuditLogEvent = 132
+)
+
+// assetTracker reports stickers and soundboard sounds being uploaded, edited
+// and deleted to the sink, and submits new stickers to the guild's approval
+// flow. It reads the audit log, whose entries name who made each change and
+// carry the asset's fields, so no sticker or sound state has to be kept.
+type assetTracker struct {
+	instanceID    string
+	sink          members.AssetSink
+	client        assets.Client
+	configManager *files.ConfigManager
+}
+
+func newAssetTracker(instanceID string, sink members.AssetSink, client assets.Client, configManager *files.ConfigManager) *assetTracker {
+	return &assetTracker{instanceID: instanceID, sink: sink, client: client, configManager: configManager}
+}
+
+func (t *assetTracker) attach(st *state.State) {
+	st.AddHandler(perf.GuardGatewayHandler("assets.audit_log_entry", t.handleAuditLogEntry))
+}
+
+func (t *assetTracker) handleAuditLogEntry(e *auditLogEntryCreateEvent) {
+	if e == nil || !e.GuildID.IsValid() {
+		return
+	}
+	kind, action, ok := assetAuditAction(e.ActionType)
+	if !ok {
+		return
+	}
+	guild := t.configManager.GuildConfig(e.GuildID.String())
+	if guild == nil {
+		return
+	}
+	if id, _ := files.ResolveFeatureBotInstanceID(*guild, "logging"); id != t.instanceID {
+		return
+	}
+
+	beforeName, afterName := auditStringChange(e.Changes, "name")
+	intent := members.AssetIntent{
+		GuildID: e.GuildID.String(),
+		AssetID: e.TargetID.String(),
+		Kind:    kind,
+		Action:  action,
+		Name:    afterName,
+	}
+	if e.UserID.IsValid() {
+		intent.ActorID = e.UserID.String()
+	}
+	switch action {
+	case members.AssetUpdated:
+		intent.PreviousName = beforeName
+	case members.AssetDeleted:
+		intent.Name = beforeName
+	}
+
+	var sticker discord.Sticker
+	if kind == members.AssetSticker {
+		sticker = auditSticker(e)
+		intent.ImageURL = assets.StickerImageURL(sticker)
+	}
+	t.sink.OnAssetChange(context.Background(), intent)
+
+	if kind == members.AssetSticker && action == members.AssetCreated && guild.Assets.Governed() && t.client != nil {
+		if err := assets.SubmitSticker(t.client, guild.Assets, sticker); err != nil {
+			slog.Warn("Mitigated service degradation: Could not submit a new sticker for review",
+				slog.String("botInstanceID", t.instanceID),
+				slog.String("guildID", e.GuildID.String()),
+				slog.String("stickerID", intent.AssetID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// assetAuditAction maps an audit log action to the asset and change it is
+// about, reporting false for actions on anything else.
+func assetAuditAction(action discord.AuditLogEvent) (members.AssetKind, members.AssetAction, bool) {
+	switch action {
+	case auditStickerCreate:
+		return members.AssetSticker, members.AssetCreated, true
+	case auditStickerUpdate:
+		return members.AssetSticker, members.AssetUpdated, true
+	case auditStickerDelete:
+		return members.AssetSticker, members.AssetDeleted, true
+	case auditSoundCreate:
+		return members.AssetSound, members.AssetCreated, true
+	case auditSoundUpdate:
+		return members.AssetSound, members.AssetUpdated, true
+	case auditSoundDelete:
+		return members.AssetSound, members.AssetDeleted, true
+	default:
+		return "", "", false
+	}
+}
+
+// auditSticker rebuilds the sticker an audit log entry is about from its
+// changes: the new values when it was created or edited, the old ones when
+// it was deleted. The entry's author stands in as the uploader.
+func auditSticker(e *auditLogEntryCreateEvent) discord.Sticker {
+	pick := func(key discord.AuditLogChangeKey) string {
+		before, after := auditStringChange(e.Changes, key)
+		if e.ActionType == auditStickerDelete {
+			return before
+		}
+		return after
+	}
+	sticker := discord.Sticker{
+		ID:          discord.StickerID(e.TargetID),
+		GuildID:     e.GuildID,
+		Name:        pick("name"),
+		Description: pick("description"),
+		Tags:        pick("tags"),
+		Type:        discord.GuildSticker,
+	}
+	for _, change := range e.Changes {
+		if change.Key != "format_type" {
+			continue
+		}
+		raw := change.NewValue
+		if e.ActionType == auditStickerDelete {
+			raw = change.OldValue
+		}
+		if len(raw) > 0 {
+			_ = raw.UnmarshalTo(&sticker.FormatType)
+		}
+	}
+	if e.UserID.IsValid() {
+		sticker.User = &discord.User{ID: e.UserID}
+	}
+	return sticker
+}
+
+// auditStringChange returns the old and new values of the string key in
+// changes. Values the entry does not carry come back empty.
+func auditStringChange(changes []discord.AuditLogChange, key discord.AuditLogChangeKey) (before, after string) {
+	for _, change := range changes {
+		if change.Key != key {
+			continue
+		}
+		if len(change.OldValue) > 0 {
+			_ = change.OldValue.UnmarshalTo(&before)
+		}
+		if len(change.NewValue) > 0 {
+			_ = change.NewValue.UnmarshalTo(&after)
+		}
+	}
+	return before, after
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+)
+
+type recordingAssetSink struct {
+	events []members.AssetIntent
+}
+
+func (s *recordingAssetSink) OnAssetChange(_ context.Context, intent members.AssetIntent) {
+	s.events = append(s.events, intent)
+}
+
+type recordingMessageClient struct {
+	sent map[discord.ChannelID][]api.SendMessageData
+}
+
+func (c *recordingMessageClient) SendMessageComplex(channelID discord.ChannelID, data api.SendMessageData) (*discord.Message, error) {
+	if c.sent == nil {
+		c.sent = make(map[discord.ChannelID][]api.SendMessageData)
+	}
+	c.sent[channelID] = append(c.sent[channelID], data)
+	return &discord.Message{ChannelID: channelID}, nil
+}
+
+func auditEntry(t *testing.T, raw string) *auditLogEntryCreateEvent {
+	t.Helper()
+	var e auditLogEntryCreateEvent
+	if err := json.Unmarshal([]byte(raw), &e); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return &e
+}
+
+func TestAssetTracker(t *testing.T) {
+	t.Parallel()
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{{
+		GuildID: "1",
+		Assets:  files.AssetsConfig{RequireApproval: true, ApprovalChannelID: "50"},
+	}}})
+	sink := &recordingAssetSink{}
+	client := &recordingMessageClient{}
+	tracker := newAssetTracker("", sink, client, cfgMgr)
+
+	tracker.handleAuditLogEntry(auditEntry(t, `{"guild_id":"1","target_id":"5","user_id":"7","action_type":90,"changes":[
+		{"key":"name","new_value":"party"},{"key":"tags","new_value":"tada"},{"key":"format_type","new_value":1}]}`))
+	tracker.handleAuditLogEntry(auditEntry(t, `{"guild_id":"1","target_id":"5","user_id":"8","action_type":91,"changes":[
+		{"key":"name","old_value":"party","new_value":"party_time"}]}`))
+	tracker.handleAuditLogEntry(auditEntry(t, `{"guild_id":"1","target_id":"6","user_id":"8","action_type":132,"changes":[
+		{"key":"name","old_value":"airhorn"}]}`))
+	// Other audit entries and unlogged guilds are ignored.
+	tracker.handleAuditLogEntry(auditEntry(t, `{"guild_id":"1","target_id":"5","action_type":60}`))
+	tracker.handleAuditLogEntry(auditEntry(t, `{"guild_id":"2","target_id":"5","action_type":90}`))
+
+	want := []members.AssetIntent{
+		{GuildID: "1", AssetID: "5", Kind: members.AssetSticker, Action: members.AssetCreated, ActorID: "7", Name: "party", ImageURL: "https://cdn.discordapp.com/stickers/5.png"},
+		{GuildID: "1", AssetID: "5", Kind: members.AssetSticker, Action: members.AssetUpdated, ActorID: "8", Name: "party_time", PreviousName: "party"},
+		{GuildID: "1", AssetID: "6", Kind: members.AssetSound, Action: members.AssetDeleted, ActorID: "8", Name: "airhorn"},
+	}
+	if len(sink.events) != len(want) {
+		t.Fatalf("expected %d reports, got %+v", len(want), sink.events)
+	}
+	for i := range want {
+		if sink.events[i] != want[i] {
+			t.Fatalf("report %d: expected %+v, got %+v", i, want[i], sink.events[i])
+		}
+	}
+
+	requests := client.sent[50]
+	if len(client.sent) != 1 || len(requests) != 1 {
+		t.Fatalf("expected one approval request in the staff channel, got %+v", client.sent)
+	}
+	if embed := requests[0].Embeds[0]; embed.Title != "Sticker awaiting approval: party" || embed.Fields[0].Value != "<@7>" {
+		t.Fatalf("unexpected approval request %+v", embed)
+	}
+}
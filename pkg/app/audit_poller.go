@@ -0,0 +1,319 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	discordlogging "github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	applicationlogging "github.com/small-frappuccino/discordcore/pkg/logging"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/task"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const (
+	// defaultAuditPollInterval spaces the audit log checks when
+	// RuntimeConfig.AuditLogPollMinutes is unset.
+	defaultAuditPollInterval = 2 * time.Minute
+
+	// auditPollBatch bounds how many entries of each action one check reads
+	// per guild. Actions beyond it within one interval are not recorded.
+	auditPollBatch = 50
+)
+
+// Log moderation scopes, set by /logging warnings, name whose moderation
+// actions the guild records.
+const (
+	moderationScopeDiscordcore = "discordcore"
+	moderationScopeAllBots     = "all_bots"
+	moderationScopeAll         = "all"
+)
+
+// auditPolledActions are the audit log actions that become cases. Discord
+// sends no gateway event naming who kicked or pruned members, so all three
+// are read from the audit log.
+var auditPolledActions = []discord.AuditLogEvent{
+	discord.MemberBanAdd,
+	discord.MemberKick,
+	discord.MemberPrune,
+}
+
+// auditPollStore records the actions found as cases. *postgres.Store
+// satisfies it.
+type auditPollStore interface {
+	CreateModerationCase(ctx context.Context, c coremod.Case) (coremod.Case, error)
+	SetModerationCaseLogMessage(ctx context.Context, guildID string, caseNumber int64, channelID, messageID string) error
+}
+
+// auditPollClient is the part of *state.State the poller needs. Me names the
+// bot, whose own actions already have cases.
+type auditPollClient interface {
+	AuditLog(guildID discord.GuildID, data api.AuditLogData) (*discord.AuditLog, error)
+	Me() (*discord.User, error)
+}
+
+// auditPoller records bans, kicks and prunes made outside discordcore, by
+// people or other bots, as cases of the guilds this instance moderates, so
+// case numbers account for every moderation action. Guilds opt in through
+// their log moderation scope.
+//
+// Each guild's position in the audit log is kept in memory and starts when
+// the guild is first checked, so actions taken while the bot was down are
+// not recorded.
+type auditPoller struct {
+	instanceID    string
+	store         auditPollStore
+	client        auditPollClient
+	logs          discordlogging.Publisher
+	configManager *files.ConfigManager
+	interval      time.Duration
+	now           func() time.Time
+
+	cursors map[string]discord.AuditLogEntryID
+}
+
+// resolveAuditPollInterval maps RuntimeConfig.AuditLogPollMinutes to a
+// duration. Zero means the poller is disabled.
+func resolveAuditPollInterval(rc files.RuntimeConfig) time.Duration {
+	switch minutes := rc.AuditLogPollMinutes; {
+	case minutes < 0:
+		return 0
+	case minutes == 0:
+		return defaultAuditPollInterval
+	default:
+		return time.Duration(minutes) * time.Minute
+	}
+}
+
+func newAuditPoller(instanceID string, store auditPollStore, client auditPollClient, logs discordlogging.Publisher, configManager *files.ConfigManager, interval time.Duration) *auditPoller {
+	return &auditPoller{
+		instanceID:    instanceID,
+		store:         store,
+		client:        client,
+		logs:          logs,
+		configManager: configManager,
+		interval:      interval,
+		now:           time.Now,
+		cursors:       make(map[string]discord.AuditLogEntryID),
+	}
+}
+
+// auditPollTaskType is the task type of the audit log checks.
+const auditPollTaskType = "scheduled.audit_poll"
+
+// schedule checks the audit logs on router once per interval, the first
+// time right away to mark where each guild's audit log stands.
+func (p *auditPoller) schedule(router *task.TaskRouter) {
+	if p == nil || p.interval <= 0 {
+		return
+	}
+	router.RegisterHandler(auditPollTaskType, func(ctx context.Context, _ any) error {
+		p.pass(ctx)
+		return nil
+	})
+	router.ScheduleEvery(p.interval, task.Task{
+		Type:    auditPollTaskType,
+		Payload: task.EmptyPayload{},
+		Options: task.TaskOptions{GroupKey: auditPollTaskType},
+	})
+}
+
+// pass records the new external actions of each moderated guild that asks
+// for them.
+func (p *auditPoller) pass(ctx context.Context) {
+	cfg := p.configManager.Config()
+	if cfg == nil {
+		return
+	}
+	me, err := p.client.Me()
+	if err != nil {
+		slog.Warn("Mitigated service degradation: Audit log poll skipped without the bot user",
+			slog.String("botInstanceID", p.instanceID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	for _, guild := range files.GuildsForBotInstanceFeature(cfg, p.instanceID, "moderation") {
+		if ctx.Err() != nil {
+			return
+		}
+		scope := moderationScope(cfg, guild)
+		if scope != moderationScopeAllBots && scope != moderationScopeAll {
+			continue
+		}
+		p.poll(ctx, guild, scope, me.ID)
+	}
+}
+
+// poll records the actions guild's audit log gained since the last check,
+// oldest first. The cursor only moves past entries that became cases, so an
+// entry that fails is retried on the next check.
+func (p *auditPoller) poll(ctx context.Context, guild files.GuildConfig, scope string, selfID discord.UserID) {
+	guildID, err := discord.ParseSnowflake(guild.GuildID)
+	if err != nil {
+		return
+	}
+	cursor, seen := p.cursors[guild.GuildID]
+	if !seen {
+		cursor = discord.AuditLogEntryID(discord.NewSnowflake(p.now()))
+		p.cursors[guild.GuildID] = cursor
+	}
+
+	var (
+		entries []discord.AuditLogEntry
+		bots    = make(map[discord.UserID]bool)
+	)
+	for _, action := range auditPolledActions {
+		log, err := p.client.AuditLog(discord.GuildID(guildID), api.AuditLogData{ActionType: action, Limit: auditPollBatch})
+		if err != nil {
+			slog.Warn("Mitigated service degradation: Audit log could not be read",
+				slog.String("botInstanceID", p.instanceID),
+				slog.String("guildID", guild.GuildID),
+				slog.Int("actionType", int(action)),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+		for _, user := range log.Users {
+			bots[user.ID] = user.Bot
+		}
+		for _, entry := range log.Entries {
+			if entry.ID > cursor {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+		if entry.UserID != selfID && (scope == moderationScopeAll || bots[entry.UserID]) {
+			if !p.record(ctx, guild, entry) {
+				return
+			}
+		}
+		p.cursors[guild.GuildID] = entry.ID
+	}
+}
+
+// record turns entry into a case and logs it, reporting false when the case
+// could not be created.
+func (p *auditPoller) record(ctx context.Context, guild files.GuildConfig, entry discord.AuditLogEntry) bool {
+	c := auditCase(guild.GuildID, entry)
+	c, err := p.store.CreateModerationCase(ctx, c)
+	if err != nil {
+		slog.Warn("Mitigated service degradation: External moderation action could not be recorded",
+			slog.String("botInstanceID", p.instanceID),
+			slog.String("guildID", guild.GuildID),
+			slog.String("action", c.Action),
+			slog.String("entryID", entry.ID.String()),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+	p.post(ctx, guild, c)
+	slog.Info("Architectural state transition: External moderation action recorded",
+		slog.String("botInstanceID", p.instanceID),
+		slog.String("guildID", guild.GuildID),
+		slog.String("action", c.Action),
+		slog.String("userID", c.UserID),
+		slog.String("moderatorID", c.ModeratorID),
+		slog.Int64("caseNumber", c.CaseNumber),
+	)
+	return true
+}
+
+// post logs c to the guild's moderation case channel. The log is published
+// right away rather than queued, so the case keeps its location for later
+// edits.
+func (p *auditPoller) post(ctx context.Context, guild files.GuildConfig, c coremod.Case) {
+	channelID, err := discord.ParseSnowflake(strings.TrimSpace(guild.Channels.ModerationCase))
+	if err != nil || !channelID.IsValid() {
+		return
+	}
+	payload := discordmod.ModerationLogPayload{
+		Action:     c.Action,
+		TargetID:   c.UserID,
+		Reason:     c.Reason,
+		CaseNumber: c.CaseNumber,
+		ActorID:    c.ModeratorID,
+		Extra:      c.Extra,
+	}
+	if c.Action == coremod.CaseActionPrune {
+		payload.TargetLabel = "Inactive members"
+	}
+	msg, err := p.logs.Publish(ctx, discordlogging.LogPost{
+		GuildID:   c.GuildID,
+		ChannelID: discord.ChannelID(channelID),
+		EventType: applicationlogging.LogEventModerationCase,
+		UserID:    c.UserID,
+		ActorID:   c.ModeratorID,
+		Embed:     discordmod.BuildModerationEmbed(payload, discord.Color(theme.Danger()), c.CreatedAt),
+	})
+	if err != nil {
+		slog.Warn("Mitigated service degradation: External moderation case log could not be posted",
+			slog.String("botInstanceID", p.instanceID),
+			slog.String("guildID", c.GuildID),
+			slog.Int64("caseNumber", c.CaseNumber),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	if err := p.store.SetModerationCaseLogMessage(ctx, c.GuildID, c.CaseNumber, msg.ChannelID.String(), msg.ID.String()); err != nil {
+		slog.Warn("Mitigated service degradation: External moderation case log location could not be saved",
+			slog.String("botInstanceID", p.instanceID),
+			slog.String("guildID", c.GuildID),
+			slog.Int64("caseNumber", c.CaseNumber),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// auditCase builds the case an audit log entry records. Prunes name no
+// member; how many were removed goes in Extra.
+func auditCase(guildID string, entry discord.AuditLogEntry) coremod.Case {
+	c := coremod.Case{
+		GuildID:   guildID,
+		Reason:    entry.Reason,
+		Source:    coremod.CaseSourceExternal,
+		CreatedAt: entry.CreatedAt(),
+	}
+	if entry.UserID.IsValid() {
+		c.ModeratorID = entry.UserID.String()
+	}
+	switch entry.ActionType {
+	case discord.MemberBanAdd:
+		c.Action = coremod.CaseActionBan
+		c.UserID = entry.TargetID.String()
+	case discord.MemberKick:
+		c.Action = coremod.CaseActionKick
+		c.UserID = entry.TargetID.String()
+	case discord.MemberPrune:
+		c.Action = coremod.CaseActionPrune
+		c.Extra = fmt.Sprintf("Removed %s members inactive for %s days.", entry.Options.MembersRemoved, entry.Options.DeleteMemberDays)
+	}
+	return c
+}
+
+// moderationScope returns whose moderation actions guild records: its own
+// scope, or the runtime one when it sets none.
+func moderationScope(cfg *files.BotConfig, guild files.GuildConfig) string {
+	if scope := strings.TrimSpace(guild.LogModerationScope); scope != "" {
+		return scope
+	}
+	if scope := strings.TrimSpace(cfg.ResolveRuntimeConfig(guild.GuildID).LogModerationScope); scope != "" {
+		return scope
+	}
+	return moderationScopeDiscordcore
+}
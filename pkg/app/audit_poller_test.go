@@ -0,0 +1,128 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+type fakeAuditLogClient struct {
+	entries []discord.AuditLogEntry
+	users   []discord.User
+	fail    bool
+}
+
+func (f *fakeAuditLogClient) AuditLog(_ discord.GuildID, data api.AuditLogData) (*discord.AuditLog, error) {
+	if f.fail {
+		return nil, errors.New("missing access")
+	}
+	log := &discord.AuditLog{Users: f.users}
+	for _, entry := range f.entries {
+		if entry.ActionType == data.ActionType {
+			log.Entries = append(log.Entries, entry)
+		}
+	}
+	return log, nil
+}
+
+func (f *fakeAuditLogClient) Me() (*discord.User, error) {
+	return &discord.User{ID: 1, Bot: true}, nil
+}
+
+func TestAuditPollerPass(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{
+		{GuildID: "2", LogModerationScope: moderationScopeAll, Channels: files.ChannelsConfig{ModerationCase: "20"}},
+		{GuildID: "3", LogModerationScope: moderationScopeAllBots},
+		{GuildID: "4"},
+	}})
+	entryAt := func(minutes int) discord.AuditLogEntryID {
+		return discord.AuditLogEntryID(discord.NewSnowflake(start.Add(time.Duration(minutes) * time.Minute)))
+	}
+
+	store := &fakeBanPoolStore{}
+	logs := newFakeLogPublisher()
+	client := &fakeAuditLogClient{
+		users: []discord.User{{ID: 5}, {ID: 6, Bot: true}},
+		entries: []discord.AuditLogEntry{
+			{ID: entryAt(-5), ActionType: discord.MemberBanAdd, TargetID: 70, UserID: 5},
+		},
+	}
+	poller := newAuditPoller("", store, client, logs, cfgMgr, time.Minute)
+	poller.now = func() time.Time { return start }
+	poller.pass(context.Background())
+	if len(store.cases) != 0 {
+		t.Fatalf("actions from before the first check should not be recorded, got %+v", store.cases)
+	}
+
+	client.entries = append(client.entries,
+		discord.AuditLogEntry{ID: entryAt(3), ActionType: discord.MemberKick, TargetID: 72, UserID: 6},
+		discord.AuditLogEntry{ID: entryAt(1), ActionType: discord.MemberBanAdd, TargetID: 71, UserID: 5, Reason: "spam"},
+		discord.AuditLogEntry{ID: entryAt(2), ActionType: discord.MemberBanAdd, TargetID: 73, UserID: 1},
+		discord.AuditLogEntry{ID: entryAt(4), ActionType: discord.MemberPrune, UserID: 5,
+			Options: discord.AuditEntryInfo{MembersRemoved: "12", DeleteMemberDays: "30"}},
+	)
+	poller.pass(context.Background())
+
+	var all, bots []coremod.Case
+	for _, c := range store.cases {
+		switch c.GuildID {
+		case "2":
+			all = append(all, c)
+		case "3":
+			bots = append(bots, c)
+		default:
+			t.Fatalf("guild %s does not record external actions, got %+v", c.GuildID, c)
+		}
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected every external action in guild 2, got %+v", all)
+	}
+	if c := all[0]; c.Action != coremod.CaseActionBan || c.UserID != "71" || c.ModeratorID != "5" || c.Reason != "spam" || c.Source != coremod.CaseSourceExternal {
+		t.Fatalf("unexpected ban case %+v", c)
+	}
+	if c := all[1]; c.Action != coremod.CaseActionKick || c.UserID != "72" || c.ModeratorID != "6" {
+		t.Fatalf("unexpected kick case %+v", c)
+	}
+	if c := all[2]; c.Action != coremod.CaseActionPrune || c.UserID != "" || c.Extra != "Removed 12 members inactive for 30 days." {
+		t.Fatalf("unexpected prune case %+v", c)
+	}
+	if len(bots) != 1 || bots[0].Action != coremod.CaseActionKick {
+		t.Fatalf("expected only the other bot's kick in guild 3, got %+v", bots)
+	}
+	if len(logs.sent[20]) != 3 {
+		t.Fatalf("expected the cases to be posted to the case channel, got %+v", logs.sent)
+	}
+
+	poller.pass(context.Background())
+	if len(store.cases) != 4 {
+		t.Fatalf("entries already recorded should not be recorded again, got %d cases", len(store.cases))
+	}
+
+	client.fail = true
+	client.entries = append(client.entries, discord.AuditLogEntry{ID: entryAt(5), ActionType: discord.MemberKick, TargetID: 74, UserID: 5})
+	poller.pass(context.Background())
+	client.fail = false
+	poller.pass(context.Background())
+	if len(store.cases) != 5 || store.cases[4].UserID != "74" {
+		t.Fatalf("an entry missed while the audit log was unreadable should be picked up later, got %+v", store.cases)
+	}
+}
+
+func TestResolveAuditPollInterval(t *testing.T) {
+	t.Parallel()
+	for minutes, want := range map[int]time.Duration{-1: 0, 0: defaultAuditPollInterval, 5: 5 * time.Minute} {
+		if got := resolveAuditPollInterval(files.RuntimeConfig{AuditLogPollMinutes: minutes}); got != want {
+			t.Fatalf("resolveAuditPollInterval(%d) = %v, want %v", minutes, got, want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/clean"
+	discordclean "github.com/small-frappuccino/discordcore/pkg/discord/clean"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+// autoPurgeHourUTC is when the nightly auto-purge starts. Channels are
+// purged one after the other, so a run can take a while.
+const autoPurgeHourUTC = 4
+
+// channelPurger deletes what a purge policy selects in a channel.
+// *discordclean.Service satisfies it.
+type channelPurger interface {
+	Purge(ctx context.Context, guildID discord.GuildID, channelID discord.ChannelID, policy clean.PurgePolicy) (discordclean.PurgeResult, error)
+}
+
+// autoPurger applies the auto-purge policies of the guilds this instance
+// moderates once a night.
+type autoPurger struct {
+	instanceID    string
+	purger        channelPurger
+	configManager *files.ConfigManager
+	now           func() time.Time
+}
+
+func newAutoPurger(instanceID string, purger channelPurger, configManager *files.ConfigManager) *autoPurger {
+	return &autoPurger{
+		instanceID:    instanceID,
+		purger:        purger,
+		configManager: configManager,
+		now:           time.Now,
+	}
+}
+
+// nextAutoPurge returns the first autoPurgeHourUTC strictly after now.
+func nextAutoPurge(now time.Time) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), autoPurgeHourUTC, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// pass purges every configured channel and logs a summary per guild. A
+// failing channel is logged and skipped, and nothing is purged while the bot
+// lockdown is engaged.
+func (p *autoPurger) pass(ctx context.Context) {
+	cfg := p.configManager.Config()
+	if cfg == nil {
+		return
+	}
+	if cfg.LockdownActive() {
+		slog.Warn("Operational telemetry: Nightly auto-purge skipped while the bot lockdown is engaged",
+			slog.String("botInstanceID", p.instanceID),
+		)
+		return
+	}
+	for _, guild := range files.GuildsForBotInstanceFeature(cfg, p.instanceID, "moderation") {
+		if !guild.AutoPurge.Enabled() {
+			continue
+		}
+		guildID, err := discord.ParseSnowflake(guild.GuildID)
+		if err != nil {
+			continue
+		}
+		guildCtx := ctx
+		if guild.TestMode {
+			// A guild in test mode sees what would be purged in the log.
+			guildCtx = coremod.WithTestMode(ctx)
+		}
+		var total discordclean.PurgeResult
+		failedChannels := 0
+		for _, channel := range guild.AutoPurge.Channels {
+			if ctx.Err() != nil {
+				return
+			}
+			channelID, err := discord.ParseSnowflake(channel.ChannelID)
+			if err != nil {
+				continue
+			}
+			result, err := p.purger.Purge(guildCtx, discord.GuildID(guildID), discord.ChannelID(channelID), purgePolicy(guild.AutoPurge, channel))
+			if err != nil {
+				failedChannels++
+				slog.Warn("Mitigated service degradation: Auto-purge of a channel failed",
+					slog.String("botInstanceID", p.instanceID),
+					slog.String("guildID", guild.GuildID),
+					slog.String("channelID", channel.ChannelID),
+					slog.String("error", err.Error()),
+				)
+				continue
+			}
+			slog.Debug("Granular transient state inspection: Auto-purge of a channel completed",
+				slog.String("botInstanceID", p.instanceID),
+				slog.String("guildID", guild.GuildID),
+				slog.String("channelID", channel.ChannelID),
+				slog.Int("scanned", result.Scanned),
+				slog.Int("selected", result.Selected),
+				slog.Int("deleted", result.Deleted),
+				slog.Int("exempt", result.Exempt),
+				slog.Int("failed", result.Failed),
+			)
+			total.Scanned += result.Scanned
+			total.Selected += result.Selected
+			total.Deleted += result.Deleted
+			total.Exempt += result.Exempt
+			total.Failed += result.Failed
+		}
+		slog.Info("Architectural state transition: Nightly auto-purge completed",
+			slog.String("botInstanceID", p.instanceID),
+			slog.String("guildID", guild.GuildID),
+			slog.Int("channels", len(guild.AutoPurge.Channels)),
+			slog.Int("failed_channels", failedChannels),
+			slog.Int("scanned", total.Scanned),
+			slog.Int("selected", total.Selected),
+			slog.Int("deleted", total.Deleted),
+			slog.Int("exempt", total.Exempt),
+			slog.Int("failed", total.Failed),
+			slog.Bool("test_mode", guild.TestMode),
+		)
+	}
+}
+
+func purgePolicy(cfg files.AutoPurgeConfig, channel files.AutoPurgeChannelConfig) clean.PurgePolicy {
+	return clean.PurgePolicy{
+		MaxAge:        time.Duration(channel.MaxAgeDays) * 24 * time.Hour,
+		KeepLast:      channel.KeepLast,
+		ExemptUserIDs: cfg.ExemptUserIDs,
+		ExemptRoleIDs: cfg.ExemptRoleIDs,
+	}
+}
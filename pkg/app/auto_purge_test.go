@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/clean"
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	discordclean "github.com/small-frappuccino/discordcore/pkg/discord/clean"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+type fakeChannelPurger struct {
+	policies map[discord.ChannelID]clean.PurgePolicy
+}
+
+func (f *fakeChannelPurger) Purge(_ context.Context, _ discord.GuildID, channelID discord.ChannelID, policy clean.PurgePolicy) (discordclean.PurgeResult, error) {
+	if channelID == 666 {
+		return discordclean.PurgeResult{}, errors.New("missing access")
+	}
+	f.policies[channelID] = policy
+	return discordclean.PurgeResult{Scanned: 10, Deleted: 4}, nil
+}
+
+func TestNextAutoPurge(t *testing.T) {
+	t.Parallel()
+	before := time.Date(2026, 5, 1, 3, 59, 0, 0, time.UTC)
+	if got := nextAutoPurge(before); !got.Equal(time.Date(2026, 5, 1, autoPurgeHourUTC, 0, 0, 0, time.UTC)) {
+		t.Fatalf("nextAutoPurge(%v) = %v", before, got)
+	}
+	at := time.Date(2026, 5, 1, autoPurgeHourUTC, 0, 0, 0, time.UTC)
+	if got := nextAutoPurge(at); !got.Equal(at.AddDate(0, 0, 1)) {
+		t.Fatalf("a run at the purge hour must schedule the next night, got %v", got)
+	}
+}
+
+func TestAutoPurgerPass(t *testing.T) {
+	t.Parallel()
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{
+		{
+			GuildID: "1",
+			AutoPurge: files.AutoPurgeConfig{
+				Channels: []files.AutoPurgeChannelConfig{
+					{ChannelID: "666", KeepLast: 5},
+					{ChannelID: "10", MaxAgeDays: 7},
+					{ChannelID: "11", KeepLast: 50},
+				},
+				ExemptRoleIDs: []string{"7"},
+			},
+		},
+		{GuildID: "2"},
+	}})
+
+	purger := &fakeChannelPurger{policies: map[discord.ChannelID]clean.PurgePolicy{}}
+	newAutoPurger("", purger, cfgMgr).pass(context.Background())
+
+	if len(purger.policies) != 2 {
+		t.Fatalf("expected the failing channel to be skipped, got %+v", purger.policies)
+	}
+	if got := purger.policies[10]; got.MaxAge != 7*24*time.Hour || got.KeepLast != 0 || len(got.ExemptRoleIDs) != 1 {
+		t.Fatalf("unexpected policy for channel 10: %+v", got)
+	}
+	if got := purger.policies[11]; got.KeepLast != 50 || got.MaxAge != 0 {
+		t.Fatalf("unexpected policy for channel 11: %+v", got)
+	}
+}
+
+func TestAutoPurgerPassSkipsDuringLockdown(t *testing.T) {
+	t.Parallel()
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{
+		RuntimeConfig: files.RuntimeConfig{BotLockdown: true},
+		Guilds: []files.GuildConfig{{
+			GuildID:   "1",
+			AutoPurge: files.AutoPurgeConfig{Channels: []files.AutoPurgeChannelConfig{{ChannelID: "10", KeepLast: 5}}},
+		}},
+	})
+
+	purger := &fakeChannelPurger{policies: map[discord.ChannelID]clean.PurgePolicy{}}
+	newAutoPurger("", purger, cfgMgr).pass(context.Background())
+	if len(purger.policies) != 0 {
+		t.Fatalf("auto-purge ran during the lockdown: %+v", purger.policies)
+	}
+}
@@ -0,0 +1,304 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+	"github.com/small-frappuccino/discordgo"
+)
+
+const (
+	// avatarPollInterval spaces full avatar diff passes. Each pass lists every
+	// member of every tracked guild over REST, so it stays coarse; member and
+	// user update events cover the gaps in between.
+	avatarPollInterval = 30 * time.Minute
+	avatarEventTimeout = 10 * time.Second
+)
+
+// applyPrivilegedIntentGrants reconciles the requested intents with the
+// privileged intents granted to the application. Identifying with an
+// ungranted privileged intent closes the gateway, so Presences is dropped when
+// it is missing and avatar logging falls back to polling, picking up the
+// Server Members intent for GuildMemberUpdate events when that one is granted.
+func applyPrivilegedIntentGrants(caps botRuntimeCapabilities, flags discord.ApplicationFlags) botRuntimeCapabilities {
+	if caps.intents&discordgo.IntentsGuildPresences == 0 {
+		return caps
+	}
+	if flags&(discord.AppFlagGatewayPresence|discord.AppFlagGatewayPresenceLimited) != 0 {
+		return caps
+	}
+	caps.intents &^= discordgo.IntentsGuildPresences
+	// Watched presences have no other source.
+	caps.presenceWatch = false
+	if caps.avatarLogging {
+		caps.avatarPolling = true
+		if flags&(discord.AppFlagGatewayGuildMembers|discord.AppFlagGatewayGuildMembersLimited) != 0 {
+			caps.intents |= discordgo.IntentsGuildMembers
+		}
+	}
+	return caps
+}
+
+// resolveGrantedCapabilities looks up the application's privileged intent
+// grants and applies them to caps. When the lookup fails caps is returned
+// unchanged and the gateway reports any missing grant on Open.
+func resolveGrantedCapabilities(st *state.State, instanceID string, caps botRuntimeCapabilities) botRuntimeCapabilities {
+	app, err := st.CurrentApplication()
+	if err != nil {
+		slog.Warn("Mitigated service degradation: Could not read application intent grants, keeping requested intents",
+			slog.String("botInstanceID", instanceID),
+			slog.String("error", err.Error()),
+		)
+		return caps
+	}
+	resolved := applyPrivilegedIntentGrants(caps, app.Flags)
+	if resolved.avatarPolling {
+		slog.Info("Architectural state transition: Presences intent not granted, tracking avatars by polling",
+			slog.String("botInstanceID", instanceID),
+			slog.Duration("interval", avatarPollInterval),
+		)
+	}
+	return resolved
+}
+
+// avatarPoller detects avatar changes without the Presences intent by diffing
+// live members against their stored snapshots, periodically and whenever a
+// member or user update arrives. Username, global name and nickname changes
+// are found the same way when names is set.
+type avatarPoller struct {
+	instanceID    string
+	st            *state.State
+	store         members.AvatarSnapshotStore
+	names         members.NameSnapshotStore
+	sink          members.MemberSink
+	configManager *files.ConfigManager
+	interval      time.Duration
+}
+
+func newAvatarPoller(instanceID string, st *state.State, store members.AvatarSnapshotStore, names members.NameSnapshotStore, sink members.MemberSink, configManager *files.ConfigManager) *avatarPoller {
+	return &avatarPoller{
+		instanceID:    instanceID,
+		st:            st,
+		store:         store,
+		names:         names,
+		sink:          sink,
+		configManager: configManager,
+		interval:      avatarPollInterval,
+	}
+}
+
+func (p *avatarPoller) attach(st *state.State) {
+	st.AddHandler(perf.GuardGatewayHandler("avatars.member_update", p.handleMemberUpdate))
+	st.AddHandler(perf.GuardGatewayHandler("avatars.user_update", p.handleUserUpdate))
+}
+
+// run performs a pass right away, which also seeds snapshots for members seen
+// for the first time, then one per interval until ctx is done.
+func (p *avatarPoller) run(ctx context.Context) {
+	if p == nil || p.interval <= 0 {
+		return
+	}
+	p.pass(ctx)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.pass(ctx)
+		}
+	}
+}
+
+func (p *avatarPoller) pass(ctx context.Context) {
+	for _, guildID := range p.trackedGuilds() {
+		if ctx.Err() != nil {
+			return
+		}
+		p.diff(ctx, guildID, liveGuildAvatars(p.st, guildID))
+	}
+}
+
+func (p *avatarPoller) handleMemberUpdate(ev *gateway.GuildMemberUpdateEvent) {
+	if !ev.GuildID.IsValid() || !ev.User.ID.IsValid() {
+		return
+	}
+	guildID := ev.GuildID.String()
+	if !slices.Contains(p.trackedGuilds(), guildID) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), avatarEventTimeout)
+	defer cancel()
+	live := liveUserAvatar(ev.User)
+	live.Nick = ev.Nick
+	live.NickUnknown = false
+	p.diff(ctx, guildID, singleLiveAvatar(live))
+}
+
+// handleUserUpdate covers the bot's own user, which Discord reports through
+// UserUpdate rather than a member event.
+func (p *avatarPoller) handleUserUpdate(ev *gateway.UserUpdateEvent) {
+	if !ev.ID.IsValid() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), avatarEventTimeout)
+	defer cancel()
+	for _, guildID := range p.trackedGuilds() {
+		p.diff(ctx, guildID, singleLiveAvatar(liveUserAvatar(ev.User)))
+	}
+}
+
+func (p *avatarPoller) diff(ctx context.Context, guildID string, live iter.Seq2[members.LiveAvatar, error]) {
+	if p.names != nil {
+		// Both diffs walk the members, so a REST listing is only made once.
+		listed, err := collectLive(live)
+		if err != nil {
+			slog.Warn("Mitigated service degradation: Avatar diff pass failed",
+				slog.String("botInstanceID", p.instanceID),
+				slog.String("guildID", guildID),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+		live = replayLive(listed)
+		p.diffNames(ctx, guildID, live)
+	}
+	result, err := members.DiffAvatarSnapshots(ctx, p.store, p.sink, guildID, live, time.Now().UTC())
+	if err != nil {
+		slog.Warn("Mitigated service degradation: Avatar diff pass failed",
+			slog.String("botInstanceID", p.instanceID),
+			slog.String("guildID", guildID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	slog.Debug("Granular transient state inspection: Avatar diff pass completed",
+		slog.String("botInstanceID", p.instanceID),
+		slog.String("guildID", guildID),
+		slog.Int("checked", result.Checked),
+		slog.Int("changed", result.Changed),
+		slog.Int("seeded", result.Seeded),
+	)
+}
+
+func (p *avatarPoller) diffNames(ctx context.Context, guildID string, live iter.Seq2[members.LiveAvatar, error]) {
+	changed, err := members.DiffMemberNames(ctx, p.names, p.sink, guildID, live, time.Now().UTC())
+	if err != nil {
+		slog.Warn("Mitigated service degradation: Name diff pass failed",
+			slog.String("botInstanceID", p.instanceID),
+			slog.String("guildID", guildID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	if changed > 0 {
+		slog.Debug("Granular transient state inspection: Name diff pass completed",
+			slog.String("botInstanceID", p.instanceID),
+			slog.String("guildID", guildID),
+			slog.Int("changed", changed),
+		)
+	}
+}
+
+// trackedGuilds lists the guilds this instance logs avatar changes for.
+func (p *avatarPoller) trackedGuilds() []string {
+	cfg := p.configManager.Config()
+	if cfg == nil {
+		return nil
+	}
+	var guildIDs []string
+	for _, guild := range files.GuildsForBotInstanceFeature(cfg, p.instanceID, "logging") {
+		if !guildLogsEvent(guild, logging.LogEventAvatarChange) || cfg.ResolveRuntimeConfig(guild.GuildID).DisableUserLogs {
+			continue
+		}
+		// Without avatar history nothing is stored to diff against, so
+		// polling would list the members for nothing.
+		if !p.configManager.GuildPrivacy(guild.GuildID).AvatarHistory {
+			continue
+		}
+		guildIDs = append(guildIDs, guild.GuildID)
+	}
+	return guildIDs
+}
+
+// liveUserAvatar describes user outside of any guild, so without a nickname.
+func liveUserAvatar(user discord.User) members.LiveAvatar {
+	return members.LiveAvatar{
+		UserID:        user.ID.String(),
+		Username:      user.Username,
+		GlobalName:    user.DisplayName,
+		Discriminator: user.Discriminator,
+		Bot:           user.Bot,
+		AvatarHash:    string(user.Avatar),
+		NickUnknown:   true,
+	}
+}
+
+func singleLiveAvatar(live members.LiveAvatar) iter.Seq2[members.LiveAvatar, error] {
+	return func(yield func(members.LiveAvatar, error) bool) {
+		yield(live, nil)
+	}
+}
+
+func collectLive(live iter.Seq2[members.LiveAvatar, error]) ([]members.LiveAvatar, error) {
+	var out []members.LiveAvatar
+	for m, err := range live {
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+func replayLive(list []members.LiveAvatar) iter.Seq2[members.LiveAvatar, error] {
+	return func(yield func(members.LiveAvatar, error) bool) {
+		for _, m := range list {
+			if !yield(m, nil) {
+				return
+			}
+		}
+	}
+}
+
+// liveGuildAvatars lists the avatar of every member of guildID over REST.
+func liveGuildAvatars(st *state.State, guildID string) iter.Seq2[members.LiveAvatar, error] {
+	return func(yield func(members.LiveAvatar, error) bool) {
+		sf, err := discord.ParseSnowflake(guildID)
+		if err != nil {
+			yield(members.LiveAvatar{}, fmt.Errorf("parse guild id: %w", err))
+			return
+		}
+		list, err := st.Client.MembersAfter(discord.GuildID(sf), 0, 0)
+		if err != nil {
+			yield(members.LiveAvatar{}, err)
+			return
+		}
+		for _, m := range list {
+			if !yield(members.LiveAvatar{
+				UserID:        m.User.ID.String(),
+				Username:      m.User.Username,
+				GlobalName:    m.User.DisplayName,
+				Nick:          m.Nick,
+				Discriminator: m.User.Discriminator,
+				Bot:           m.User.Bot,
+				AvatarHash:    string(m.User.Avatar),
+			}, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordgo"
+)
+
+func TestApplyPrivilegedIntentGrants(t *testing.T) {
+	t.Parallel()
+
+	requested := botRuntimeCapabilities{
+		intents:       discordgo.IntentsGuilds | discordgo.IntentsGuildPresences,
+		avatarLogging: true,
+	}
+
+	tests := []struct {
+		name        string
+		caps        botRuntimeCapabilities
+		flags       discord.ApplicationFlags
+		wantIntents discordgo.Intent
+		wantPolling bool
+	}{
+		{
+			name:        "presence granted",
+			caps:        requested,
+			flags:       discord.AppFlagGatewayPresence,
+			wantIntents: discordgo.IntentsGuilds | discordgo.IntentsGuildPresences,
+		},
+		{
+			name:        "limited presence granted",
+			caps:        requested,
+			flags:       discord.AppFlagGatewayPresenceLimited,
+			wantIntents: discordgo.IntentsGuilds | discordgo.IntentsGuildPresences,
+		},
+		{
+			name:        "nothing granted",
+			caps:        requested,
+			wantIntents: discordgo.IntentsGuilds,
+			wantPolling: true,
+		},
+		{
+			name:        "members granted",
+			caps:        requested,
+			flags:       discord.AppFlagGatewayGuildMembersLimited,
+			wantIntents: discordgo.IntentsGuilds | discordgo.IntentsGuildMembers,
+			wantPolling: true,
+		},
+		{
+			name:        "presence watch only",
+			caps:        botRuntimeCapabilities{intents: discordgo.IntentsGuilds | discordgo.IntentsGuildPresences},
+			flags:       discord.AppFlagGatewayGuildMembers,
+			wantIntents: discordgo.IntentsGuilds,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := applyPrivilegedIntentGrants(tt.caps, tt.flags)
+			if got.intents != tt.wantIntents {
+				t.Fatalf("intents = %d, want %d", got.intents, tt.wantIntents)
+			}
+			if got.avatarPolling != tt.wantPolling {
+				t.Fatalf("avatarPolling = %v, want %v", got.avatarPolling, tt.wantPolling)
+			}
+		})
+	}
+}
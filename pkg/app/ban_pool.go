@@ -0,0 +1,276 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	discordlogging "github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	applicationlogging "github.com/small-frappuccino/discordcore/pkg/logging"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/permissions"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const (
+	// banPoolInterval spaces the checks for bans shared through ban pools.
+	banPoolInterval = time.Minute
+
+	// banPoolBatch bounds how many pooled bans one guild applies per check.
+	// A larger backlog drains over the next checks.
+	banPoolBatch = 25
+
+	// banPoolMaxAge bounds how far back a guild picks up pooled bans, so a
+	// guild that just joined a pool does not inherit its whole history. A
+	// ban Discord keeps rejecting is also given up on after it.
+	banPoolMaxAge = 24 * time.Hour
+
+	// maxAuditLogReason is the limit Discord enforces on audit log reasons.
+	maxAuditLogReason = 512
+)
+
+// banPoolStore finds the pooled bans a guild has yet to apply and records
+// them as cases. *postgres.Store satisfies it.
+type banPoolStore interface {
+	ListPendingPooledBans(ctx context.Context, guildID string, pools []string, since time.Time, limit int) ([]coremod.PooledBan, error)
+	MarkPooledBanDelivered(ctx context.Context, banID int64, guildID string, caseNumber int64, at time.Time) error
+	CreateModerationCase(ctx context.Context, c coremod.Case) (coremod.Case, error)
+	SetModerationCaseLogMessage(ctx context.Context, guildID string, caseNumber int64, channelID, messageID string) error
+}
+
+// banPoolClient is the part of *state.State the relay needs. Member serves
+// the protected role check and may fail for users outside the guild.
+type banPoolClient interface {
+	Ban(guildID discord.GuildID, userID discord.UserID, data api.BanData) error
+	Member(guildID discord.GuildID, userID discord.UserID) (*discord.Member, error)
+}
+
+// banPoolRelay applies bans shared through ban pools in the other guilds of
+// each pool this instance moderates. Every applied ban becomes a case of the
+// receiving guild naming the pool, server and case it came from.
+type banPoolRelay struct {
+	instanceID    string
+	store         banPoolStore
+	client        banPoolClient
+	logs          discordlogging.Publisher
+	configManager *files.ConfigManager
+	interval      time.Duration
+	now           func() time.Time
+}
+
+func newBanPoolRelay(instanceID string, store banPoolStore, client banPoolClient, logs discordlogging.Publisher, configManager *files.ConfigManager) *banPoolRelay {
+	return &banPoolRelay{
+		instanceID:    instanceID,
+		store:         store,
+		client:        client,
+		logs:          logs,
+		configManager: configManager,
+		interval:      banPoolInterval,
+		now:           time.Now,
+	}
+}
+
+// run performs a pass right away, applying what was shared while the bot was
+// down, then one per interval until ctx is done.
+func (r *banPoolRelay) run(ctx context.Context) {
+	if r == nil || r.interval <= 0 {
+		return
+	}
+	r.pass(ctx)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.pass(ctx)
+		}
+	}
+}
+
+// pass applies the pending pooled bans of each moderated guild in a pool. A
+// ban that fails stays pending and is retried on the next pass, as do the
+// bans of every guild while the bot lockdown is engaged and those of guilds
+// not entitled to cross guild sync.
+func (r *banPoolRelay) pass(ctx context.Context) {
+	cfg := r.configManager.Config()
+	if cfg == nil {
+		return
+	}
+	if cfg.LockdownActive() {
+		slog.Debug("Operational telemetry: Pooled bans left pending while the bot lockdown is engaged",
+			slog.String("botInstanceID", r.instanceID),
+		)
+		return
+	}
+	now := r.now()
+	for _, guild := range files.GuildsForBotInstanceFeature(cfg, r.instanceID, "moderation") {
+		if ctx.Err() != nil {
+			return
+		}
+		// A guild in test mode keeps its pooled bans pending; those still
+		// recent enough are applied once it leaves test mode.
+		if len(guild.BanPools) == 0 || guild.TestMode || !files.GuildEntitled(guild.GuildID, files.EntitlementCrossGuildSync) {
+			continue
+		}
+		bans, err := r.store.ListPendingPooledBans(ctx, guild.GuildID, guild.BanPools, now.Add(-banPoolMaxAge), banPoolBatch)
+		if err != nil {
+			slog.Warn("Mitigated service degradation: Pooled bans could not be listed",
+				slog.String("botInstanceID", r.instanceID),
+				slog.String("guildID", guild.GuildID),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		for _, ban := range bans {
+			if ctx.Err() != nil {
+				return
+			}
+			r.apply(ctx, guild, ban)
+		}
+	}
+}
+
+func (r *banPoolRelay) apply(ctx context.Context, guild files.GuildConfig, ban coremod.PooledBan) {
+	guildID, errG := discord.ParseSnowflake(guild.GuildID)
+	userID, errU := discord.ParseSnowflake(ban.UserID)
+	if errG != nil || errU != nil {
+		r.settle(ctx, guild.GuildID, ban, 0)
+		return
+	}
+	pool := ban.SharedPool(guild.BanPools)
+
+	protected := coremod.ProtectedTargets{UserIDs: guild.ModerationProtection.UserIDs, RoleIDs: guild.ModerationProtection.RoleIDs}
+	var member *coremod.Member
+	if m, err := r.client.Member(discord.GuildID(guildID), discord.UserID(userID)); err == nil {
+		member = permissions.MemberFromDiscord(*m)
+	}
+	if protected.Protects(ban.UserID, member) {
+		slog.Info("Architectural state transition: Pooled ban skipped for a protected member",
+			slog.String("botInstanceID", r.instanceID),
+			slog.String("guildID", guild.GuildID),
+			slog.String("userID", ban.UserID),
+			slog.String("pool", pool),
+		)
+		r.settle(ctx, guild.GuildID, ban, 0)
+		return
+	}
+
+	if err := r.client.Ban(discord.GuildID(guildID), discord.UserID(userID), api.BanData{
+		AuditLogReason: api.AuditLogReason(pooledBanAuditReason(pool, ban)),
+	}); err != nil {
+		slog.Warn("Mitigated service degradation: Pooled ban could not be applied",
+			slog.String("botInstanceID", r.instanceID),
+			slog.String("guildID", guild.GuildID),
+			slog.String("userID", ban.UserID),
+			slog.String("pool", pool),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	c, err := r.store.CreateModerationCase(ctx, coremod.Case{
+		GuildID:     guild.GuildID,
+		Action:      coremod.CaseActionBan,
+		UserID:      ban.UserID,
+		ModeratorID: ban.ModeratorID,
+		Reason:      ban.Reason,
+		Source:      coremod.CaseSourceBanPool,
+		Extra:       ban.Attribution(pool),
+		CreatedAt:   r.now(),
+	})
+	if err != nil {
+		slog.Warn("Mitigated service degradation: Pooled ban applied without a case",
+			slog.String("botInstanceID", r.instanceID),
+			slog.String("guildID", guild.GuildID),
+			slog.String("userID", ban.UserID),
+			slog.String("error", err.Error()),
+		)
+		r.settle(ctx, guild.GuildID, ban, 0)
+		return
+	}
+	r.post(ctx, guild, c)
+	r.settle(ctx, guild.GuildID, ban, c.CaseNumber)
+	slog.Info("Architectural state transition: Pooled ban applied",
+		slog.String("botInstanceID", r.instanceID),
+		slog.String("guildID", guild.GuildID),
+		slog.String("userID", ban.UserID),
+		slog.String("pool", pool),
+		slog.String("originGuildID", ban.OriginGuildID),
+		slog.Int64("caseNumber", c.CaseNumber),
+	)
+}
+
+// post logs the case of a pooled ban to the guild's moderation case channel,
+// right away so the case keeps the location of its log.
+func (r *banPoolRelay) post(ctx context.Context, guild files.GuildConfig, c coremod.Case) {
+	channelID, err := discord.ParseSnowflake(strings.TrimSpace(guild.Channels.ModerationCase))
+	if err != nil || !channelID.IsValid() {
+		return
+	}
+	embed := discordmod.BuildModerationEmbed(discordmod.ModerationLogPayload{
+		Action:     c.Action,
+		TargetID:   c.UserID,
+		Reason:     c.Reason,
+		CaseNumber: c.CaseNumber,
+		ActorID:    c.ModeratorID,
+		Extra:      c.Extra,
+	}, discord.Color(theme.Danger()), c.CreatedAt)
+	msg, err := r.logs.Publish(ctx, discordlogging.LogPost{
+		GuildID:   c.GuildID,
+		ChannelID: discord.ChannelID(channelID),
+		EventType: applicationlogging.LogEventModerationCase,
+		UserID:    c.UserID,
+		ActorID:   c.ModeratorID,
+		Embed:     embed,
+	})
+	if err != nil {
+		slog.Warn("Mitigated service degradation: Pooled ban case log could not be posted",
+			slog.String("botInstanceID", r.instanceID),
+			slog.String("guildID", c.GuildID),
+			slog.Int64("caseNumber", c.CaseNumber),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	if err := r.store.SetModerationCaseLogMessage(ctx, c.GuildID, c.CaseNumber, msg.ChannelID.String(), msg.ID.String()); err != nil {
+		slog.Warn("Mitigated service degradation: Pooled ban case log location could not be saved",
+			slog.String("botInstanceID", r.instanceID),
+			slog.String("guildID", c.GuildID),
+			slog.Int64("caseNumber", c.CaseNumber),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// settle marks ban as handled for guildID so it is not applied again.
+func (r *banPoolRelay) settle(ctx context.Context, guildID string, ban coremod.PooledBan, caseNumber int64) {
+	if err := r.store.MarkPooledBanDelivered(ctx, ban.ID, guildID, caseNumber, r.now()); err != nil {
+		slog.Warn("Mitigated service degradation: Pooled ban could not be settled",
+			slog.String("botInstanceID", r.instanceID),
+			slog.String("guildID", guildID),
+			slog.Int64("banID", ban.ID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// pooledBanAuditReason names the pool and origin of ban in the audit log,
+// cut to the length Discord accepts.
+func pooledBanAuditReason(pool string, ban coremod.PooledBan) string {
+	reason := fmt.Sprintf("Ban pool %s (server %s): %s", pool, ban.OriginGuildID, ban.Reason)
+	if utf8.RuneCountInString(reason) <= maxAuditLogReason {
+		return reason
+	}
+	return string([]rune(reason)[:maxAuditLogReason])
+}
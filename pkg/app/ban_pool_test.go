@@ -0,0 +1,147 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+type fakeBanPoolStore struct {
+	pending map[string][]coremod.PooledBan
+	cases   []coremod.Case
+	settled map[string]map[int64]int64
+}
+
+func (f *fakeBanPoolStore) ListPendingPooledBans(_ context.Context, guildID string, _ []string, _ time.Time, _ int) ([]coremod.PooledBan, error) {
+	var out []coremod.PooledBan
+	for _, ban := range f.pending[guildID] {
+		if _, done := f.settled[guildID][ban.ID]; !done {
+			out = append(out, ban)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeBanPoolStore) MarkPooledBanDelivered(_ context.Context, banID int64, guildID string, caseNumber int64, _ time.Time) error {
+	if f.settled[guildID] == nil {
+		f.settled[guildID] = map[int64]int64{}
+	}
+	f.settled[guildID][banID] = caseNumber
+	return nil
+}
+
+func (f *fakeBanPoolStore) CreateModerationCase(_ context.Context, c coremod.Case) (coremod.Case, error) {
+	c.CaseNumber = int64(len(f.cases) + 1)
+	f.cases = append(f.cases, c)
+	return c, nil
+}
+
+func (f *fakeBanPoolStore) SetModerationCaseLogMessage(context.Context, string, int64, string, string) error {
+	return nil
+}
+
+type fakeBanPoolClient struct {
+	banned  []string
+	reasons []string
+	members map[discord.UserID]discord.Member
+	failBan bool
+}
+
+func (f *fakeBanPoolClient) Ban(guildID discord.GuildID, userID discord.UserID, data api.BanData) error {
+	if f.failBan {
+		return errors.New("missing permissions")
+	}
+	f.banned = append(f.banned, guildID.String()+"/"+userID.String())
+	f.reasons = append(f.reasons, string(data.AuditLogReason))
+	return nil
+}
+
+func (f *fakeBanPoolClient) Member(_ discord.GuildID, userID discord.UserID) (*discord.Member, error) {
+	m, ok := f.members[userID]
+	if !ok {
+		return nil, errors.New("unknown member")
+	}
+	return &m, nil
+}
+
+func TestBanPoolRelayPass(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{
+		{
+			GuildID:              "2",
+			BanPools:             []string{"partners", "network"},
+			Channels:             files.ChannelsConfig{ModerationCase: "20"},
+			ModerationProtection: files.ModerationProtectionConfig{RoleIDs: []string{"99"}},
+		},
+		{GuildID: "3"},
+	}})
+
+	ban := coremod.PooledBan{ID: 1, Pools: []string{"network"}, OriginGuildID: "1", OriginCase: 4, UserID: "7", ModeratorID: "5", Reason: "raid"}
+	protected := coremod.PooledBan{ID: 2, Pools: []string{"network"}, OriginGuildID: "1", UserID: "8", ModeratorID: "5", Reason: "raid"}
+	store := &fakeBanPoolStore{
+		pending: map[string][]coremod.PooledBan{"2": {ban, protected}, "3": {ban}},
+		settled: map[string]map[int64]int64{},
+	}
+	client := &fakeBanPoolClient{
+		members: map[discord.UserID]discord.Member{8: {RoleIDs: []discord.RoleID{99}}},
+	}
+	logs := newFakeLogPublisher()
+	relay := newBanPoolRelay("", store, client, logs, cfgMgr)
+	relay.now = func() time.Time { return now }
+	relay.pass(context.Background())
+
+	if len(client.banned) != 1 || client.banned[0] != "2/7" {
+		t.Fatalf("banned %v, want only user 7 in guild 2", client.banned)
+	}
+	if !strings.HasPrefix(client.reasons[0], "Ban pool network (server 1): raid") {
+		t.Fatalf("audit log reason = %q", client.reasons[0])
+	}
+	if len(store.cases) != 1 {
+		t.Fatalf("recorded %d cases, want 1", len(store.cases))
+	}
+	c := store.cases[0]
+	if c.Source != coremod.CaseSourceBanPool || c.ModeratorID != "5" || !strings.Contains(c.Extra, "case #4") {
+		t.Fatalf("unexpected case %+v", c)
+	}
+	if len(logs.sent[20]) != 1 {
+		t.Fatalf("expected the case to be posted to the case channel, got %+v", logs.sent)
+	}
+	if store.settled["2"][1] != 1 {
+		t.Fatalf("ban 1 settled under case %d, want 1", store.settled["2"][1])
+	}
+	if number, ok := store.settled["2"][2]; !ok || number != 0 {
+		t.Fatalf("the protected member's ban should be settled without a case, got %v, %v", number, ok)
+	}
+	if len(store.settled["3"]) != 0 {
+		t.Fatal("guilds in no pool should be left alone")
+	}
+
+	cfgMgr.ApplyConfig(&files.BotConfig{
+		RuntimeConfig: files.RuntimeConfig{BotLockdown: true},
+		Guilds:        []files.GuildConfig{{GuildID: "2", BanPools: []string{"partners"}}},
+	})
+	store.pending["2"] = append(store.pending["2"], coremod.PooledBan{ID: 4, Pools: []string{"partners"}, OriginGuildID: "1", UserID: "10"})
+	relay.pass(context.Background())
+	if _, ok := store.settled["2"][4]; ok || len(client.banned) != 1 {
+		t.Fatal("pooled bans should stay pending while the bot lockdown is engaged")
+	}
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{{GuildID: "2", BanPools: []string{"partners"}}}})
+	store.pending["2"] = store.pending["2"][:len(store.pending["2"])-1]
+
+	client.failBan = true
+	store.pending["2"] = append(store.pending["2"], coremod.PooledBan{ID: 3, Pools: []string{"partners"}, OriginGuildID: "1", UserID: "9"})
+	relay.pass(context.Background())
+	if _, ok := store.settled["2"][3]; ok {
+		t.Fatal("a ban Discord rejected should stay pending")
+	}
+}
@@ -14,34 +14,47 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
 	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/small-frappuccino/discordcore/pkg/automod"
+	"github.com/small-frappuccino/discordcore/pkg/clean"
 	"github.com/small-frappuccino/discordcore/pkg/clock"
 	"github.com/small-frappuccino/discordcore/pkg/control"
 	discord_automod "github.com/small-frappuccino/discordcore/pkg/discord/automod"
 	"github.com/small-frappuccino/discordcore/pkg/discord/cache"
+	discordclean "github.com/small-frappuccino/discordcore/pkg/discord/clean"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/admin"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/assets"
 	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/guildconfig"
 	"github.com/small-frappuccino/discordcore/pkg/discord/commands/moderation"
 	"github.com/small-frappuccino/discordcore/pkg/discord/embeds"
 	"github.com/small-frappuccino/discordcore/pkg/discord/logging"
 	discordmembers "github.com/small-frappuccino/discordcore/pkg/discord/members"
 	discordmessages "github.com/small-frappuccino/discordcore/pkg/discord/messages"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
 	"github.com/small-frappuccino/discordcore/pkg/discord/partners"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
 	discordqotd "github.com/small-frappuccino/discordcore/pkg/discord/qotd"
 	"github.com/small-frappuccino/discordcore/pkg/discord/roles"
 	"github.com/small-frappuccino/discordcore/pkg/discord/session"
 	discordstats "github.com/small-frappuccino/discordcore/pkg/discord/stats"
 	"github.com/small-frappuccino/discordcore/pkg/discord/tickets"
 	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/keylock"
 	"github.com/small-frappuccino/discordcore/pkg/log"
+	applicationlogging "github.com/small-frappuccino/discordcore/pkg/logging"
 	"github.com/small-frappuccino/discordcore/pkg/members"
 
 	"github.com/small-frappuccino/discordcore/pkg/messages"
+	"github.com/small-frappuccino/discordcore/pkg/observability"
 	applicationqotd "github.com/small-frappuccino/discordcore/pkg/qotd"
 	"github.com/small-frappuccino/discordcore/pkg/runtimeapply"
 	"github.com/small-frappuccino/discordcore/pkg/service"
 	"github.com/small-frappuccino/discordcore/pkg/stats"
 	"github.com/small-frappuccino/discordcore/pkg/storage/postgres"
+	"github.com/small-frappuccino/discordcore/pkg/system"
 	"github.com/small-frappuccino/discordcore/pkg/task"
 	"github.com/small-frappuccino/discordgo"
 )
@@ -49,7 +62,15 @@ import (
 type botRuntimeCapabilities struct {
 	monitoring          bool
 	automod             bool
+	automodRules        bool
 	userPrune           bool
+	autoPurge           bool
+	transparencyReport  bool
+	healthReport        bool
+	caseExpiry          bool
+	raidMode            bool
+	banPool             bool
+	auditPoll           bool
 	qotdRuntime         bool
 	stats               bool
 	warmup              bool
@@ -57,11 +78,32 @@ type botRuntimeCapabilities struct {
 	hasCommands         bool
 	messageEventService bool
 	memberEventService  bool
+	avatarLogging       bool
+	threadLogging       bool
+	roleLogging         bool
+	voiceLogging        bool
+	assetLogging        bool
+	presenceWatch       bool
+	// avatarPolling is set once the Presences intent turns out not to be
+	// granted; avatar changes are then found by avatarPoller.
+	avatarPolling bool
 }
 
 // HasCommands reports whether any command catalog should be installed.
 func (c botRuntimeCapabilities) HasCommands() bool { return c.hasCommands }
 
+// readOnly keeps what a read-only standby may run: informational commands,
+// refused by ReadOnlyMiddleware when they would change anything, and the
+// cache they read from. Everything else stores events, posts logs or
+// moderates, and is left to the primary.
+func (c botRuntimeCapabilities) readOnly() botRuntimeCapabilities {
+	return botRuntimeCapabilities{
+		intents:     c.intents & (discordgo.IntentsGuilds | discordgo.IntentsGuildMembers),
+		hasCommands: c.hasCommands,
+		warmup:      c.warmup,
+	}
+}
+
 func resolveBotRuntimeCapabilities(
 	cfg *files.BotConfig,
 	botInstanceID string,
@@ -97,7 +139,7 @@ func resolveBotRuntimeCapabilities(
 				isStatsBot = true
 			}
 		}
-		if guild.Channels.AutomodAction != "" || guild.UserPrune.Enabled {
+		if guild.Channels.AutomodAction != "" || guild.ModeratesContent() || guild.JoinGate.Enabled || guild.UserPrune.Enabled || guild.AutoPurge.Enabled() {
 			if id, _ := files.ResolveFeatureBotInstanceID(guild, "moderation"); id == botInstanceID {
 				isModBot = true
 			}
@@ -126,11 +168,52 @@ func resolveBotRuntimeCapabilities(
 				capabilities.automod = true
 				capabilities.intents |= discordgo.IntentAutoModerationExecution
 			}
+			if guild.ModeratesContent() {
+				// Rules are checked by the message event service as
+				// messages arrive, whether or not this bot logs them.
+				capabilities.automodRules = true
+				capabilities.messageEventService = true
+				capabilities.intents |= discordgo.IntentsGuildMessages | discordgo.IntentMessageContent
+			}
+			if guild.JoinGate.Enabled {
+				// The gate screens members as the member event service
+				// receives their joins.
+				capabilities.memberEventService = true
+				capabilities.intents |= discordgo.IntentsGuildMembers
+			}
 			if guild.UserPrune.Enabled {
 				capabilities.userPrune = true
 				capabilities.intents |= discordgo.IntentsGuildMembers
 				capabilities.warmup = true
 			}
+			if guild.AutoPurge.Enabled() {
+				capabilities.autoPurge = true
+			}
+			if guild.Channels.Transparency != "" {
+				capabilities.transparencyReport = true
+			}
+			if guild.HealthReport.Enabled() {
+				capabilities.healthReport = true
+			}
+			if guild.Channels.ModerationCase != "" {
+				capabilities.caseExpiry = true
+			}
+			// /raidmode can be turned on at any time, so its expiry is
+			// always watched. Kicking new accounts needs join events.
+			capabilities.raidMode = true
+			if guild.RaidMode.MinAccountAgeDays > 0 {
+				capabilities.intents |= discordgo.IntentsGuildMembers
+			}
+			if len(guild.BanPools) > 0 {
+				capabilities.banPool = true
+			}
+		}
+		// Actions taken outside discordcore are read from the audit log, so
+		// recording them needs no other moderation feature.
+		if scope := moderationScope(cfg, guild); scope == moderationScopeAllBots || scope == moderationScopeAll {
+			if id, _ := files.ResolveFeatureBotInstanceID(guild, "moderation"); id == botInstanceID && resolveAuditPollInterval(cfg.RuntimeConfig) > 0 {
+				capabilities.auditPoll = true
+			}
 		}
 
 		if features.Services.Monitoring {
@@ -155,6 +238,32 @@ func resolveBotRuntimeCapabilities(
 						capabilities.intents |= discordgo.IntentsGuildPresences
 						capabilities.warmup = true
 					}
+					if isLoggingBot && !runtimeConfig.DisableUserLogs && guildLogsEvent(guild, applicationlogging.LogEventAvatarChange, applicationlogging.LogEventNameChange) {
+						capabilities.avatarLogging = true
+					}
+					if isLoggingBot && !runtimeConfig.DisableUserLogs && botRuntimeWatchesPresence(features, runtimeConfig, guild) && guildLogsEvent(guild, applicationlogging.LogEventPresenceChange) {
+						capabilities.presenceWatch = true
+					}
+					if isLoggingBot && !runtimeConfig.DisableMessageLogs && (guildLogsEvent(guild, applicationlogging.LogEventThreadChange) || guild.ThreadAutoJoin) {
+						// Thread events only need the Guilds intent.
+						capabilities.threadLogging = true
+					}
+					if isLoggingBot && guildLogsEvent(guild, applicationlogging.LogEventServerChange) {
+						// Role events only need the Guilds intent.
+						capabilities.roleLogging = true
+					}
+					if isLoggingBot && guildLogsEvent(guild, applicationlogging.LogEventVoiceChange) {
+						// Moves and disconnects are read from audit log
+						// entries, which need the Guild Moderation intent.
+						capabilities.voiceLogging = true
+						capabilities.intents |= discordgo.IntentGuildModeration
+					}
+					if isLoggingBot && (guildLogsEvent(guild, applicationlogging.LogEventAssetChange) || guild.Assets.Governed()) {
+						// Sticker and sound changes are read from the audit
+						// log too.
+						capabilities.assetLogging = true
+						capabilities.intents |= discordgo.IntentGuildModeration
+					}
 					if botRuntimeNeedsMessages(runtimeConfig, guild) {
 						capabilities.intents |= discordgo.IntentsGuildMessages | discordgo.IntentMessageContent
 					}
@@ -185,7 +294,17 @@ func botRuntimeNeedsMessages(runtimeConfig files.RuntimeConfig, guild files.Guil
 	if runtimeConfig.DisableMessageLogs {
 		return false
 	}
-	return guild.Channels.MessageEdit != "" || guild.Channels.MessageDelete != ""
+	return guildLogsEvent(guild, applicationlogging.LogEventMessageEdit, applicationlogging.LogEventMessageDelete)
+}
+
+// guildLogsEvent reports whether guild sends any of events to a log channel.
+func guildLogsEvent(guild files.GuildConfig, events ...applicationlogging.LogEventType) bool {
+	for _, event := range events {
+		if applicationlogging.ResolveGuildLogChannel(event, &guild) != "" {
+			return true
+		}
+	}
+	return false
 }
 
 func botRuntimeNeedsReactions(runtimeConfig files.RuntimeConfig) bool {
@@ -194,10 +313,16 @@ func botRuntimeNeedsReactions(runtimeConfig files.RuntimeConfig) bool {
 }
 
 func botRuntimeNeedsPresence(features files.ResolvedFeatureToggles, runtimeConfig files.RuntimeConfig, guild files.GuildConfig) bool {
-	if !runtimeConfig.DisableUserLogs && guild.Channels.AvatarLogging != "" {
+	if !runtimeConfig.DisableUserLogs && guildLogsEvent(guild, applicationlogging.LogEventAvatarChange, applicationlogging.LogEventNameChange) {
 		return true
 	}
-	if features.PresenceWatch.User && strings.TrimSpace(runtimeConfig.PresenceWatchUserID) != "" {
+	return botRuntimeWatchesPresence(features, runtimeConfig, guild)
+}
+
+// botRuntimeWatchesPresence reports whether guild watches anyone's presence:
+// members named by its watchlist or the runtime config, or the bot itself.
+func botRuntimeWatchesPresence(features files.ResolvedFeatureToggles, runtimeConfig files.RuntimeConfig, guild files.GuildConfig) bool {
+	if features.PresenceWatch.User && (strings.TrimSpace(runtimeConfig.PresenceWatchUserID) != "" || len(guild.PresenceWatch.UserIDs) > 0) {
 		return true
 	}
 	return features.PresenceWatch.Bot && runtimeConfig.PresenceWatchBot
@@ -208,10 +333,10 @@ func botRuntimeNeedsMemberData(
 	runtimeConfig files.RuntimeConfig,
 	guild files.GuildConfig,
 ) bool {
-	if !runtimeConfig.DisableUserLogs && guild.Channels.RoleUpdate != "" {
+	if !runtimeConfig.DisableUserLogs && guildLogsEvent(guild, applicationlogging.LogEventRoleChange) {
 		return true
 	}
-	if guild.Channels.MemberJoin != "" || guild.Channels.MemberLeave != "" {
+	if guildLogsEvent(guild, applicationlogging.LogEventMemberJoin, applicationlogging.LogEventMemberLeave) {
 		return true
 	}
 
@@ -266,7 +391,40 @@ type botRuntime struct {
 	serviceManager *service.ServiceManager
 	unifiedCache   *cache.UnifiedCache
 	taskRouter     *task.TaskRouter
+	eventLogger    *logging.Logger
 	commandHandler *CommandHandler
+	watchdog       *gatewayWatchdog
+	gatewayCapture *gatewayCapture
+	avatarPoller   *avatarPoller
+	autoPurger     *autoPurger
+
+	transparencyReporter *transparencyReporter
+	healthReporter       *healthReporter
+	caseExpiryAnnouncer  *caseExpiryAnnouncer
+	raidModeWatcher      *raidModeWatcher
+	banPoolRelay         *banPoolRelay
+	auditPoller          *auditPoller
+	presenceWatcher      *presenceWatcher
+	presenceReconciler   *memberPresenceReconciler
+	memberCountRecorder  *memberCountRecorder
+	rollupMaintainer     *activityRollupMaintainer
+
+	memberBatchOnce sync.Once
+	memberBatch     *cache.CachedSession
+}
+
+// moderationMemberTTL bounds how long members resolved in a batch for the
+// hierarchy checks of mass actions are reused. It stays short, as member
+// roles change far more often than the guild's role table.
+const moderationMemberTTL = 30 * time.Second
+
+// memberBatchResolver returns the session that resolves members in batches
+// for moderation, creating it on first use.
+func (r *botRuntime) memberBatchResolver() *cache.CachedSession {
+	r.memberBatchOnce.Do(func() {
+		r.memberBatch = cache.NewCachedSession(r.arikawaState.Client, cache.NewUnifiedCache(cache.CacheConfig{MemberTTL: moderationMemberTTL}))
+	})
+	return r.memberBatch
 }
 
 type botRuntimeResolver struct {
@@ -600,6 +758,12 @@ type botRuntimeOptions struct {
 	embedService          *embeds.EmbedService
 	rolePanelService      *roles.RolePanelService
 	partnerService        *partners.PartnerService
+	readOnly              bool
+	// cleanLocks is shared by every runtime and the app's /clean service.
+	cleanLocks *keylock.Mutex[discord.ChannelID]
+	// moderationService backs the app's moderation commands; see
+	// RunOptions.ModerationService.
+	moderationService *discordmod.Service
 }
 
 // NewBotRuntime instantiates a fully isolated bot runtime.
@@ -620,7 +784,14 @@ func NewBotRuntime(ctx context.Context, instance resolvedBotInstance, capabiliti
 	)
 
 	botToken := string(instance.Token)
+	liveGateway := !strings.Contains(botToken, "mock_token") && !strings.Contains(botToken, "Bot fake") && !strings.Contains(botToken, "token")
 	arikawaState := state.New("Bot " + botToken)
+	if liveGateway {
+		capabilities = resolveGrantedCapabilities(arikawaState, instance.ID, capabilities)
+	}
+	if opts.readOnly {
+		capabilities = capabilities.readOnly()
+	}
 	arikawaState.AddIntents(gateway.Intents(capabilities.intents))
 	arikawaState = arikawaState.WithContext(ctx)
 
@@ -628,16 +799,31 @@ func NewBotRuntime(ctx context.Context, instance resolvedBotInstance, capabiliti
 	defer cancel()
 
 	var meUsername, meDiscriminator string
-	if !strings.Contains(botToken, "mock_token") && !strings.Contains(botToken, "Bot fake") && !strings.Contains(botToken, "token") {
+	var watchdog *gatewayWatchdog
+	var capture *gatewayCapture
+	if liveGateway {
+		newGuildAccessGuard(instance.ID, opts.configManager, arikawaState).attach(arikawaState)
+		capture = startGatewayCapture(instance.ID, arikawaState, time.Now())
 		if err := arikawaState.Open(openCtx); err != nil {
+			_ = capture.close()
 			return nil, fmt.Errorf("open discord session for %s: %w", instance.ID, err)
 		}
 		me, err := arikawaState.Me()
 		if err != nil {
+			_ = capture.close()
 			return nil, fmt.Errorf("discord session state not properly initialized for %s: %w", instance.ID, err)
 		}
 		meUsername = me.Username
 		meDiscriminator = me.Discriminator
+
+		var runtimeConfig files.RuntimeConfig
+		if cfg := opts.configManager.Config(); cfg != nil {
+			runtimeConfig = cfg.RuntimeConfig
+		}
+		if idle := resolveGatewayIdleTimeout(runtimeConfig); idle > 0 {
+			watchdog = newGatewayWatchdog(instance.ID, arikawaState, idle)
+			watchdog.attach(arikawaState)
+		}
 	} else {
 		// Mock token detected, skipping gateway connection
 		slog.Warn("Mock token detected, bypassing Arikawa gateway Open() and Me()", slog.String("botInstanceID", instance.ID))
@@ -651,14 +837,20 @@ func NewBotRuntime(ctx context.Context, instance resolvedBotInstance, capabiliti
 	)
 
 	runtime := &botRuntime{
-		instanceID:    instance.ID,
-		capabilities:  capabilities,
-		legacySession: session.NewEmptySessionForCompat(botToken),
-		arikawaState:  arikawaState,
+		instanceID:     instance.ID,
+		capabilities:   capabilities,
+		legacySession:  session.NewEmptySessionForCompat(botToken),
+		arikawaState:   arikawaState,
+		watchdog:       watchdog,
+		gatewayCapture: capture,
 	}
 
 	if err := populateBotRuntimeServices(runtime, opts); err != nil {
+		if runtime.taskRouter != nil {
+			runtime.taskRouter.Close()
+		}
 		_ = arikawaState.Close()
+		_ = capture.close()
 		return nil, err
 	}
 
@@ -672,10 +864,23 @@ func populateBotRuntimeServices(runtime *botRuntime, opts botRuntimeOptions) err
 	}
 
 	routerConfig := newRuntimeTaskRouterConfig(cfg, runtime.instanceID, opts.runtimeCount)
-	_ = routerConfig // might be used by domain setups internally if passed
+	if opts.store != nil {
+		routerConfig.IdempotencyStore = opts.store
+	}
+	runtime.taskRouter = task.NewRouter(routerConfig)
 
 	runtime.serviceManager = service.NewServiceManager(slog.Default())
 
+	if runtime.capabilities.warmup && runtime.unifiedCache == nil {
+		runtime.unifiedCache = cache.NewUnifiedCache(cache.CacheConfig{
+			MemberTTL:  5 * time.Minute,
+			GuildTTL:   15 * time.Minute,
+			RolesTTL:   5 * time.Minute,
+			ChannelTTL: 15 * time.Minute,
+			Store:      opts.store,
+		})
+	}
+
 	if opts.runtimeApplier != nil {
 		opts.runtimeApplier.AddRuntime(runtime.serviceManager, nil)
 	}
@@ -683,17 +888,70 @@ func populateBotRuntimeServices(runtime *botRuntime, opts botRuntimeOptions) err
 	var eventLogger *logging.Logger
 	if runtime.arikawaState != nil && runtime.arikawaState.Session != nil {
 		eventLogger = logging.NewLogger(runtime.arikawaState.Session.Client, opts.configManager, runtime.arikawaState, gateway.Intents(runtime.capabilities.intents), slog.Default())
+		if runtime.unifiedCache != nil {
+			eventLogger.SetUnifiedCache(runtime.unifiedCache)
+		}
+		// The archive is a database write, so read-only instances skip it.
+		if opts.store != nil && !opts.readOnly {
+			eventLogger.SetArchive(opts.store)
+		}
+		runtime.eventLogger = eventLogger
+	}
+
+	// AutoMod actions, Discord's and the spam filter's, are logged and
+	// recorded as cases.
+	var automodSinks automod.MultiSink
+	if eventLogger != nil {
+		automodSinks = append(automodSinks, eventLogger)
+	}
+	if opts.store != nil && !opts.readOnly {
+		automodSinks = append(automodSinks, discord_automod.NewCaseSink(opts.store, opts.logger))
+	}
+
+	// Every store write goes through the guild's privacy profile.
+	var (
+		messageStore messages.Repository
+		memberStore  members.Repository
+		systemRepo   system.Repository
+		avatarStore  members.AvatarSnapshotStore
+		nameStore    members.NameSnapshotStore
+	)
+	contentSalt := files.ContentHashSalt()
+	if opts.store != nil {
+		privacy := opts.configManager.GuildPrivacy
+		messageStore = newPrivacyMessageStore(opts.store, privacy, contentSalt)
+		memberStore = newPrivacyMemberStore(opts.store, privacy)
+		systemRepo = newPrivacySystemRepo(opts.store, privacy)
+		avatarStore = newPrivacyAvatarStore(opts.store, privacy)
+		nameStore = newPrivacyNameStore(opts.store, privacy)
 	}
 
 	// Message Event Service
-	if runtime.capabilities.messageEventService {
+	if runtime.capabilities.messageEventService && !opts.readOnly {
+		var inspector messages.MessageCreateInspector
+		if runtime.capabilities.automodRules && runtime.arikawaState != nil {
+			var ruleStore discord_automod.RuleStore
+			if opts.store != nil {
+				ruleStore = opts.store
+			}
+			automodLogger := slog.With("domain", "automod")
+			engine := discord_automod.NewRuleEngine(runtime.arikawaState, ruleStore, automodSinks, automodLogger).
+				WithModeration(discordmod.NewService(runtime.arikawaState, automodLogger).
+					WithGuildContexts(discordmod.NewGuildContextCache(runtime.arikawaState, 0)))
+			if eventLogger != nil {
+				engine.WithLogs(eventLogger)
+			}
+			inspector = engine
+		}
 		msgSvc := messages.NewMessageEventServiceForBot(messages.EventServiceDeps{
 			ConfigManager:  opts.configManager,
 			BotInstanceID:  runtime.instanceID,
 			Logger:         slog.With("domain", "messages"),
 			DiscordAdapter: discordmessages.NewArikawaAdapter(runtime.arikawaState),
 			Sink:           eventLogger,
-			Store:          opts.store,
+			Inspector:      inspector,
+			Store:          messageStore,
+			ContentSalt:    contentSalt,
 		})
 		msgSvc.SetTaskRouter(runtime.taskRouter)
 		if err := runtime.serviceManager.Register(msgSvc); err != nil {
@@ -702,12 +960,12 @@ func populateBotRuntimeServices(runtime *botRuntime, opts botRuntimeOptions) err
 	}
 
 	// Member Event Service
-	if runtime.capabilities.memberEventService {
+	if runtime.capabilities.memberEventService && !opts.readOnly {
 		memSvc := members.NewMemberEventServiceForBot(members.EventServiceDeps{
 			ConfigManager:  opts.configManager,
 			Sink:           eventLogger,
-			MembersRepo:    opts.store,
-			SystemRepo:     opts.store,
+			MembersRepo:    memberStore,
+			SystemRepo:     systemRepo,
 			BotInstanceID:  runtime.instanceID,
 			Logger:         slog.With("domain", "members"),
 			DiscordAdapter: discordmembers.NewArikawaAdapter(runtime.arikawaState),
@@ -717,9 +975,77 @@ func populateBotRuntimeServices(runtime *botRuntime, opts botRuntimeOptions) err
 		}
 	}
 
+	if runtime.capabilities.avatarPolling && opts.store != nil && eventLogger != nil && !opts.readOnly {
+		runtime.avatarPoller = newAvatarPoller(runtime.instanceID, runtime.arikawaState, avatarStore, nameStore, eventLogger, opts.configManager)
+		runtime.avatarPoller.attach(runtime.arikawaState)
+	}
+	if runtime.capabilities.threadLogging && eventLogger != nil {
+		newThreadTracker(runtime.instanceID, eventLogger, runtime.arikawaState, opts.configManager).attach(runtime.arikawaState)
+	}
+	if runtime.capabilities.roleLogging && eventLogger != nil {
+		newRoleTracker(runtime.instanceID, eventLogger, runtime.arikawaState, opts.configManager).attach(runtime.arikawaState)
+	}
+	if runtime.capabilities.voiceLogging && eventLogger != nil {
+		newVoiceTracker(runtime.instanceID, eventLogger, opts.configManager).attach(runtime.arikawaState)
+	}
+	if runtime.capabilities.assetLogging && eventLogger != nil {
+		newAssetTracker(runtime.instanceID, eventLogger, runtime.arikawaState, opts.configManager).attach(runtime.arikawaState)
+	}
+	if runtime.capabilities.presenceWatch && eventLogger != nil {
+		runtime.presenceWatcher = newPresenceWatcher(runtime.instanceID, eventLogger, runtime.arikawaState, opts.configManager)
+		runtime.presenceWatcher.attach(runtime.arikawaState)
+	}
+
+	if runtime.capabilities.autoPurge && runtime.arikawaState != nil && !opts.readOnly {
+		var purgeOpts []discordclean.Option
+		if opts.cleanLocks != nil {
+			purgeOpts = append(purgeOpts, discordclean.WithChannelLocks(opts.cleanLocks))
+		}
+		if opts.store != nil {
+			// Purged messages stay exportable through /clean-export.
+			purgeOpts = append(purgeOpts, discordclean.WithArchiver(
+				newPrivacyArchiver(clean.StoreArchiver(opts.store, time.Now), opts.configManager.GuildPrivacy),
+			))
+		}
+		purger := discordclean.NewService(runtime.arikawaState, nil, slog.With("domain", "auto_purge"), purgeOpts...)
+		runtime.autoPurger = newAutoPurger(runtime.instanceID, purger, opts.configManager)
+	}
+
+	if runtime.capabilities.transparencyReport && runtime.arikawaState != nil && opts.store != nil && !opts.readOnly {
+		runtime.transparencyReporter = newTransparencyReporter(runtime.instanceID, opts.store, runtime.arikawaState, opts.configManager)
+	}
+	if runtime.capabilities.healthReport && runtime.arikawaState != nil && opts.store != nil && !opts.readOnly {
+		runtime.healthReporter = newHealthReporter(runtime.instanceID, opts.store, runtime.arikawaState, opts.configManager, opts.membersMetrics)
+	}
+	if runtime.capabilities.caseExpiry && eventLogger != nil && opts.store != nil && !opts.readOnly {
+		runtime.caseExpiryAnnouncer = newCaseExpiryAnnouncer(runtime.instanceID, opts.store, eventLogger, opts.configManager)
+	}
+	if runtime.capabilities.raidMode && runtime.arikawaState != nil && opts.store != nil && !opts.readOnly {
+		runtime.raidModeWatcher = newRaidModeWatcher(runtime.instanceID, opts.store, runtime.arikawaState, opts.configManager)
+		runtime.raidModeWatcher.attach(runtime.arikawaState)
+	}
+	if runtime.capabilities.banPool && runtime.arikawaState != nil && eventLogger != nil && opts.store != nil && !opts.readOnly {
+		runtime.banPoolRelay = newBanPoolRelay(runtime.instanceID, opts.store, runtime.arikawaState, eventLogger, opts.configManager)
+	}
+	if runtime.capabilities.auditPoll && runtime.arikawaState != nil && eventLogger != nil && opts.store != nil && !opts.readOnly {
+		runtime.auditPoller = newAuditPoller(runtime.instanceID, opts.store, runtime.arikawaState, eventLogger, opts.configManager, resolveAuditPollInterval(cfg.RuntimeConfig))
+	}
+	if runtime.capabilities.memberEventService && runtime.arikawaState != nil && opts.store != nil && !opts.readOnly {
+		st := runtime.arikawaState
+		runtime.presenceReconciler = newMemberPresenceReconciler(runtime.instanceID, opts.store,
+			func(guildID string) iter.Seq2[members.LiveMember, error] { return liveGuildMembers(st, guildID) },
+			opts.configManager)
+	}
+	if runtime.capabilities.memberEventService && opts.store != nil && !opts.readOnly {
+		runtime.memberCountRecorder = newMemberCountRecorder(runtime.instanceID, opts.store, opts.configManager)
+	}
+	if runtime.capabilities.messageEventService && opts.store != nil && !opts.readOnly {
+		runtime.rollupMaintainer = newActivityRollupMaintainer(runtime.instanceID, opts.store, opts.configManager)
+	}
+
 	// Automod Service
-	if runtime.capabilities.automod {
-		automodService := discord_automod.NewArikawaAdapter(runtime.arikawaState, eventLogger, opts.logger)
+	if runtime.capabilities.automod && !opts.readOnly {
+		automodService := discord_automod.NewArikawaAdapter(runtime.arikawaState, automodSinks, opts.logger)
 		if err := runtime.serviceManager.Register(automodService); err != nil {
 			return fmt.Errorf("service registration failure for %s: %w", runtime.instanceID, err)
 		}
@@ -754,7 +1080,14 @@ func populateBotRuntimeServices(runtime *botRuntime, opts botRuntimeOptions) err
 			caps |= CapStats
 		}
 
-		ticketService := tickets.NewService(runtime.arikawaState, slog.Default())
+		var ticketOpts []tickets.Option
+		if opts.store != nil {
+			// Deleted ticket channels stay exportable through /clean-export.
+			ticketOpts = append(ticketOpts, tickets.WithArchiver(
+				newPrivacyArchiver(clean.StoreArchiver(opts.store, time.Now), opts.configManager.GuildPrivacy),
+			))
+		}
+		ticketService := tickets.NewService(runtime.arikawaState, slog.Default(), ticketOpts...)
 
 		var statsService *stats.StatsService
 		for _, svc := range runtime.serviceManager.GetAllServices() {
@@ -764,7 +1097,24 @@ func populateBotRuntimeServices(runtime *botRuntime, opts botRuntimeOptions) err
 			}
 		}
 
-		cg := opts.commandGroups
+		// Owner-only admin commands ship with every bot instance.
+		var commandAudit system.CommandAuditRepository
+		adminOpts := []admin.Option{admin.WithErrorLog(observability.Errors())}
+		if opts.store != nil {
+			commandAudit = opts.store
+			adminOpts = append(adminOpts, admin.WithCommandAudit(opts.store))
+		}
+		cg := make([]cmd.CommandGroup, 0, len(opts.commandGroups)+3)
+		cg = append(cg, opts.commandGroups...)
+		if opts.configManager != nil {
+			adminOpts = append(adminOpts, admin.WithLockdown(opts.configManager), admin.WithRetention(opts.configManager))
+			if opts.moderationService != nil {
+				adminOpts = append(adminOpts, admin.WithLockoutLifter(opts.moderationService))
+			}
+			cg = append(cg, admin.NewCommandGroup(opts.configManager, slog.Default(), adminOpts...))
+			assetsGroup := assets.NewCommandGroup(opts.configManager, slog.Default())
+			cg = append(cg, assetsGroup, guildconfig.NewCommandGroup(opts.configManager, slog.Default(), assetsGroup))
+		}
 		deps := CommandHandlerDeps{
 			Session:             runtime.legacySession,
 			ConfigManager:       opts.configManager,
@@ -779,6 +1129,14 @@ func populateBotRuntimeServices(runtime *botRuntime, opts botRuntimeOptions) err
 			RolePanelService:    opts.rolePanelService,
 			PartnerService:      opts.partnerService,
 			TicketService:       ticketService,
+			CommandAudit:        commandAudit,
+			ReadOnly:            opts.readOnly,
+		}
+		if opts.moderationService != nil {
+			deps.ModerationLockouts = opts.moderationService
+		}
+		if eventLogger != nil {
+			deps.LogPublisher = eventLogger
 		}
 
 		commandHandler, err := NewCommandHandlerForBot(deps)
@@ -807,6 +1165,54 @@ func (r *botRuntime) Run(ctx context.Context, telemetryCh chan<- RuntimeTelemetr
 		opts:        opts,
 		egCtx:       egCtx,
 	}.execute)
+	if r.watchdog != nil {
+		eg.Go(func() error {
+			r.watchdog.run(egCtx)
+			return nil
+		})
+	}
+	if r.avatarPoller != nil {
+		eg.Go(func() error {
+			r.avatarPoller.run(egCtx)
+			return nil
+		})
+	}
+	if r.caseExpiryAnnouncer != nil {
+		eg.Go(func() error {
+			r.caseExpiryAnnouncer.run(egCtx)
+			return nil
+		})
+	}
+	if r.raidModeWatcher != nil {
+		eg.Go(func() error {
+			r.raidModeWatcher.run(egCtx)
+			return nil
+		})
+	}
+	if r.banPoolRelay != nil {
+		eg.Go(func() error {
+			r.banPoolRelay.run(egCtx)
+			return nil
+		})
+	}
+	if r.presenceWatcher != nil {
+		eg.Go(func() error {
+			r.presenceWatcher.run(egCtx)
+			return nil
+		})
+	}
+	if r.presenceReconciler != nil {
+		eg.Go(func() error {
+			r.presenceReconciler.run(egCtx)
+			return nil
+		})
+	}
+
+	var clock jobClock
+	if opts.store != nil {
+		clock = opts.store
+	}
+	r.scheduleJobs(egCtx, clock)
 
 	<-egCtx.Done()
 	select {
@@ -837,6 +1243,10 @@ type runtimeStartTask struct {
 }
 
 func (t runtimeStartTask) execute() error {
+	// Read the liveness marks before services start handling events and
+	// overwrite them.
+	followUps := t.storeFollowUps(time.Now())
+
 	if err := t.r.serviceManager.StartAll(); err != nil {
 		select {
 		case t.telemetryCh <- RuntimeTelemetryEvent{InstanceID: t.r.instanceID, State: TelemetryStateCriticalFailure, Error: err}:
@@ -848,10 +1258,37 @@ func (t runtimeStartTask) execute() error {
 	case t.telemetryCh <- RuntimeTelemetryEvent{InstanceID: t.r.instanceID, State: TelemetryStateConnected, Error: nil}:
 	default:
 	}
-	scheduleRuntimeWarmup(t.egCtx, t.r, t.opts.store, t.opts.startupTasks)
+	scheduleRuntimeWarmup(t.egCtx, t.r, t.opts.startupTasks)
+	for _, followUp := range followUps {
+		t.opts.startupTasks.Go(followUp)
+	}
 	return nil
 }
 
+// storeFollowUps returns the startup tasks that work from the store: the
+// downtime reconciliation when the runtime was offline at now, and the
+// startup report. Both write to the store or message the owner, so a
+// read-only instance runs neither.
+func (t runtimeStartTask) storeFollowUps(now time.Time) []BootstrapTask {
+	if t.opts.store == nil || t.opts.readOnly {
+		return nil
+	}
+	var tasks []BootstrapTask
+	if lastSeen, downtime := detectDowntime(t.egCtx, t.opts.store, t.r.instanceID, now); downtime {
+		tasks = append(tasks, DowntimeReconcileTask{
+			runtime:       t.r,
+			store:         t.opts.store,
+			configManager: t.opts.configManager,
+			lastSeen:      lastSeen,
+		})
+	}
+	return append(tasks, StartupReportTask{
+		runtime:       t.r,
+		store:         t.opts.store,
+		configManager: t.opts.configManager,
+	})
+}
+
 type runtimeTeardownServicesTask struct {
 	r *botRuntime
 }
@@ -862,6 +1299,18 @@ func (t runtimeTeardownServicesTask) execute() error {
 	if err := t.r.serviceManager.StopAll(stopCtx); err != nil {
 		slog.Error("Failed to cleanly stop service manager for runtime", slog.String("botInstanceID", t.r.instanceID), slog.Any("error", err))
 	}
+	// The services are stopped, so nothing dispatches any more.
+	if t.r.taskRouter != nil {
+		t.r.taskRouter.Close()
+	}
+	if t.r.eventLogger != nil {
+		if err := t.r.eventLogger.Close(stopCtx); err != nil {
+			slog.Warn("Mitigated service degradation: Log events still queued for the archive were dropped",
+				slog.String("botInstanceID", t.r.instanceID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
 	return nil
 }
 
@@ -884,20 +1333,20 @@ func (t runtimeTeardownStateTask) execute() error {
 	if t.r.arikawaState != nil {
 		_ = t.r.arikawaState.Close()
 	}
+	if err := t.r.gatewayCapture.close(); err != nil {
+		slog.Warn("Mitigated service degradation: Gateway capture was not written out",
+			slog.String("botInstanceID", t.r.instanceID),
+			slog.String("error", err.Error()),
+		)
+	}
 	return nil
 }
 
-func scheduleRuntimeWarmup(ctx context.Context, runtime *botRuntime, store *postgres.Store, startupTasks *StartupTaskOrchestrator) {
-	if runtime == nil || runtime.legacySession == nil || !runtime.capabilities.warmup || runtime.unifiedCache == nil {
-		return
-	}
-
-	unifiedCache := runtime.unifiedCache
-
-	if unifiedCache.WasWarmedUpRecently(10 * time.Minute) {
-		slog.Info("Architectural state bypass: Suppressing cache warmup sequence due to valid temporal TTL",
-			slog.String("botInstanceID", runtime.instanceID),
-		)
+// scheduleRuntimeWarmup installs a GuildCreate hook that hydrates the unified cache one guild at a time.
+// Warming lazily keeps startup memory and latency proportional to the guilds the gateway actually
+// delivers rather than to the full persistent cache.
+func scheduleRuntimeWarmup(ctx context.Context, runtime *botRuntime, startupTasks *StartupTaskOrchestrator) {
+	if runtime == nil || runtime.arikawaState == nil || !runtime.capabilities.warmup || runtime.unifiedCache == nil {
 		return
 	}
 
@@ -906,48 +1355,61 @@ func scheduleRuntimeWarmup(ctx context.Context, runtime *botRuntime, store *post
 		panic("hardware-aligned validation failure: startupTasks cannot be nil during runtime warmup phase")
 	}
 
-	slog.Debug("Delegating cache warmup to orchestrator scheduling queue",
+	unifiedCache := runtime.unifiedCache
+
+	slog.Debug("Delegating per-guild cache warmup to GuildCreate dispatch",
 		slog.String("botInstanceID", runtime.instanceID),
 	)
-	startupTasks.Go(RuntimeWarmupTask{
-		runtime: runtime,
-		store:   store,
-	})
+	warm := func(guildID string) {
+		if unifiedCache.WasGuildWarmedUpRecently(guildID, 10*time.Minute) {
+			slog.Debug("Architectural state bypass: Suppressing guild warmup due to valid temporal TTL",
+				slog.String("botInstanceID", runtime.instanceID),
+				slog.String("guildID", guildID),
+			)
+			return
+		}
+		startupTasks.Go(GuildWarmupTask{
+			runtime: runtime,
+			guildID: guildID,
+		})
+	}
+	runtime.arikawaState.AddHandler(perf.GuardGatewayHandler("runtime.guild_create", func(e *gateway.GuildCreateEvent) {
+		warm(e.ID.String())
+	}))
+	// The gateway opens before services start, so the guilds it announced
+	// meanwhile are only in the cabinet.
+	if guilds, err := runtime.arikawaState.Cabinet.Guilds(); err == nil {
+		for _, guild := range guilds {
+			warm(guild.ID.String())
+		}
+	}
+	runtime.arikawaState.AddHandler(perf.GuardGatewayHandler("runtime.guild_delete", func(e *gateway.GuildDeleteEvent) {
+		unifiedCache.ForgetGuild(e.ID.String())
+	}))
 }
 
-type RuntimeWarmupTask struct {
+// GuildWarmupTask hydrates the unified cache for a single guild announced via GuildCreate.
+type GuildWarmupTask struct {
 	runtime *botRuntime
-	store   *postgres.Store
+	guildID string
 }
 
-func (t RuntimeWarmupTask) Execute(taskCtx context.Context) error {
-	_, memberWarmupConfig := runtimeWarmupPhases()
-	if err := cache.IntelligentWarmupContext(taskCtx, t.runtime.legacySession, t.runtime.unifiedCache, t.store, memberWarmupConfig); err != nil {
+func (t GuildWarmupTask) Execute(taskCtx context.Context) error {
+	if err := t.runtime.unifiedCache.WarmupGuild(taskCtx, t.guildID); err != nil {
 		if taskCtx.Err() != nil {
 			return nil
 		}
-		slog.Warn("Mitigated service degradation: Orchestrated cache warmup failed, pipeline resumes",
+		slog.Warn("Mitigated service degradation: Guild cache warmup failed, pipeline resumes",
 			slog.String("botInstanceID", t.runtime.instanceID),
+			slog.String("guildID", t.guildID),
 			slog.String("error", err.Error()),
 		)
 	}
 	return nil
 }
 
-func (t RuntimeWarmupTask) Name() string {
-	return "cache_warmup:" + t.runtime.instanceID
-}
-
-func runtimeWarmupPhases() (cache.WarmupConfig, cache.WarmupConfig) {
-	base := cache.DefaultWarmupConfig()
-	base.FetchMissingMembers = false
-	base.MaxMembersPerGuild = 500
-
-	members := cache.DefaultWarmupConfig()
-	members.FetchMissingMembers = true
-	members.MaxMembersPerGuild = 500
-
-	return base, members
+func (t GuildWarmupTask) Name() string {
+	return "cache_warmup:" + t.runtime.instanceID + ":" + t.guildID
 }
 
 // shutdownBotRuntime removed as teardown is now handled natively by Run via Context cancellation
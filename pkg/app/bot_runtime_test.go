@@ -160,6 +160,103 @@ func TestBotRuntime_InitializationRouting(t *testing.T) {
 	}
 }
 
+func TestBotRuntime_ReadOnlyRegistersNothingMutating(t *testing.T) {
+	t.Parallel()
+	cfg := &files.BotConfig{
+		Guilds: []files.GuildConfig{
+			{
+				GuildID:            "1",
+				BotInstanceTokens:  map[string]files.EncryptedString{"main": "a"},
+				LogModerationScope: moderationScopeAll,
+				FeatureRouting: map[string]string{
+					"moderation": "main",
+					"logging":    "main",
+					"roles":      "main",
+					"stats":      "main",
+					"qotd":       "main",
+				},
+				Features: files.FeatureToggles{
+					Services: files.FeatureServiceToggles{
+						Commands:   new(bool(true)),
+						Monitoring: new(bool(true)),
+					},
+				},
+				Channels: files.ChannelsConfig{
+					AutomodAction:  "10",
+					MemberJoin:     "11",
+					MessageDelete:  "12",
+					ModerationCase: "13",
+					Transparency:   "14",
+				},
+				QOTD: files.QOTDConfig{
+					ActiveDeckID: "deck1",
+					Decks:        []files.QOTDDeckConfig{{ID: "deck1", Enabled: true, ChannelID: "15"}},
+				},
+				AutoPurge:    files.AutoPurgeConfig{Channels: []files.AutoPurgeChannelConfig{{ChannelID: "16", MaxAgeDays: 7}}},
+				HealthReport: files.HealthReportConfig{ChannelID: "17"},
+				BanPools:     []string{"pool"},
+			},
+		},
+	}
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(cfg)
+
+	caps := resolveBotRuntimeCapabilities(cfg, "main")
+	caps.avatarPolling = true
+	if !caps.messageEventService || !caps.automod || !caps.autoPurge || !caps.banPool || !caps.auditPoll {
+		t.Fatalf("expected the config to enable the mutating services, got %+v", caps)
+	}
+
+	rt, err := NewBotRuntime(context.Background(), resolvedBotInstance{ID: "main", Token: "Bot fake"}, caps, botRuntimeOptions{
+		runtimeCount:       1,
+		configManager:      cfgMgr,
+		store:              &postgres.Store{},
+		qotdCommandService: &qotd.Service{},
+		startupTasks:       NewStartupTaskOrchestrator(context.Background(), 1),
+		readOnly:           true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected init error: %v", err)
+	}
+
+	services := rt.serviceManager.GetAllServices()
+	for name := range services {
+		if name != "command-handler" {
+			t.Errorf("read-only runtime registered %q", name)
+		}
+	}
+	if rt.commandHandler == nil {
+		t.Error("expected informational commands to stay available")
+	}
+	workers := map[string]bool{
+		"avatarPoller":         rt.avatarPoller != nil,
+		"autoPurger":           rt.autoPurger != nil,
+		"transparencyReporter": rt.transparencyReporter != nil,
+		"healthReporter":       rt.healthReporter != nil,
+		"caseExpiryAnnouncer":  rt.caseExpiryAnnouncer != nil,
+		"raidModeWatcher":      rt.raidModeWatcher != nil,
+		"banPoolRelay":         rt.banPoolRelay != nil,
+		"auditPoller":          rt.auditPoller != nil,
+		"presenceWatcher":      rt.presenceWatcher != nil,
+		"presenceReconciler":   rt.presenceReconciler != nil,
+		"memberCountRecorder":  rt.memberCountRecorder != nil,
+		"rollupMaintainer":     rt.rollupMaintainer != nil,
+	}
+	for name, started := range workers {
+		if started {
+			t.Errorf("read-only runtime built %s", name)
+		}
+	}
+	if want := discordgo.IntentsGuilds | discordgo.IntentsGuildMembers; rt.capabilities.intents&^want != 0 {
+		t.Errorf("read-only runtime asked for event intents %d", rt.capabilities.intents&^want)
+	}
+
+	start := runtimeStartTask{r: rt, opts: botRuntimeOptions{store: &postgres.Store{}, configManager: cfgMgr, readOnly: true}, egCtx: context.Background()}
+	for _, followUp := range start.storeFollowUps(time.Now()) {
+		t.Errorf("read-only runtime scheduled %s", followUp.Name())
+	}
+}
+
 func TestBotRuntime_CapabilityBitmaskDerivation(t *testing.T) {
 	t.Parallel()
 
@@ -201,6 +298,62 @@ func TestBotRuntime_CapabilityBitmaskDerivation(t *testing.T) {
 			expectedCommands:   true,
 			expectedMonitoring: false,
 		},
+		{
+			name:          "Automod Rules Read Message Content",
+			botInstanceID: "main",
+			cfg: &files.BotConfig{
+				Guilds: []files.GuildConfig{
+					{
+						GuildID: "g1",
+						BotInstanceTokens: map[string]files.EncryptedString{
+							"main": "mock_token",
+						},
+						Features: files.FeatureToggles{
+							Services: files.FeatureServiceToggles{
+								Commands:   new(bool(true)),
+								Monitoring: new(bool(false)),
+							},
+						},
+						FeatureRouting: map[string]string{
+							"moderation": "main",
+						},
+						AutomodRules: []files.AutomodRule{
+							{Name: "invites", Pattern: "discord.gg", Actions: []string{files.AutomodActionDelete}},
+						},
+					},
+				},
+			},
+			expectedIntents:    discordgo.IntentsGuilds | discordgo.IntentsGuildMessages | discordgo.IntentMessageContent,
+			expectedCommands:   true,
+			expectedMonitoring: false,
+		},
+		{
+			name:          "Join Gate Receives Member Joins",
+			botInstanceID: "main",
+			cfg: &files.BotConfig{
+				Guilds: []files.GuildConfig{
+					{
+						GuildID: "g1",
+						BotInstanceTokens: map[string]files.EncryptedString{
+							"main": "mock_token",
+						},
+						Features: files.FeatureToggles{
+							Services: files.FeatureServiceToggles{
+								Commands:   new(bool(true)),
+								Monitoring: new(bool(false)),
+							},
+						},
+						FeatureRouting: map[string]string{
+							"moderation": "main",
+						},
+						JoinGate: files.JoinGateConfig{Enabled: true, RequireAvatar: true},
+					},
+				},
+			},
+			expectedIntents:    discordgo.IntentsGuilds | discordgo.IntentsGuildMembers,
+			expectedCommands:   true,
+			expectedMonitoring: false,
+		},
 	}
 
 	for _, tt := range tests {
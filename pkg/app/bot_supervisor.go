@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/diamondburned/arikawa/v3/discord"
@@ -59,6 +60,9 @@ type BotSupervisor struct {
 
 	commandCh   chan TopologyDelta
 	telemetryCh chan RuntimeTelemetryEvent
+
+	// rotations tracks instance IDs with a token rotation in flight.
+	rotations sync.Map
 }
 
 // NewBotSupervisor initializes a new BotSupervisor to manage bot runtimes.
@@ -106,6 +110,11 @@ func (s *BotSupervisor) Start() error {
 	s.group.Go(func() error {
 		return s.executionRing()
 	})
+	alerter := newOwnerAlerter(s.configManager, s.resolver)
+	s.group.Go(func() error {
+		alerter.run(s.groupCtx)
+		return nil
+	})
 	s.onConfigChanged(context.Background(), nil, nil) // trigger initial resolution
 	return nil
 }
@@ -387,7 +396,8 @@ func (s *BotSupervisor) onConfigChanged(ctx context.Context, oldCfg, newCfg *fil
 			} else if !reflect.DeepEqual(oldGuild.FeatureRouting, newGuild.FeatureRouting) ||
 				!reflect.DeepEqual(oldGuild.Features, newGuild.Features) ||
 				!reflect.DeepEqual(oldGuild.BotInstanceTokens, newGuild.BotInstanceTokens) ||
-				!reflect.DeepEqual(oldGuild.BotInstanceStatuses, newGuild.BotInstanceStatuses) {
+				!reflect.DeepEqual(oldGuild.BotInstanceStatuses, newGuild.BotInstanceStatuses) ||
+				!reflect.DeepEqual(oldGuild.DisabledCommands, newGuild.DisabledCommands) {
 				needsSync = true
 			}
 
@@ -407,6 +417,8 @@ func (s *BotSupervisor) onConfigChanged(ctx context.Context, oldCfg, newCfg *fil
 		}
 	}
 
+	s.scheduleTokenRotations(newCfg)
+
 	return s.reconcileTopology(ctx, TopologyDelta{
 		ActiveTokens:   currentTokens,
 		ActiveStatus:   currentStatuses,
@@ -451,7 +463,20 @@ func (s *BotSupervisor) executeGatewayUpdate(ctx context.Context, intent Gateway
 	return nil
 }
 
+// executeSyncTask syncs the guild commands of the instance again. The
+// registrar hashes every target, so only guilds whose commands changed, such
+// as one that disabled a command, cost a request.
 func (s *BotSupervisor) executeSyncTask(ctx context.Context, intent SyncTaskIntent) error {
-	// Syncing is now handled strictly via O(1) hashing inside CommandRegistrar during SetupCommands.
+	var rt *botRuntime
+	for rtID, runtime := range s.resolver.getRuntimes() {
+		if rtID == intent.InstanceID {
+			rt = runtime
+			break
+		}
+	}
+	if rt == nil || rt.commandHandler == nil {
+		return nil
+	}
+	rt.commandHandler.SyncGuildCommands()
 	return nil
 }
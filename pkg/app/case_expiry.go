@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	discordlogging "github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	applicationlogging "github.com/small-frappuccino/discordcore/pkg/logging"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+const (
+	// caseExpiryInterval spaces the checks for ended timeouts. Discord lifts
+	// the timeouts itself; the follow-up only has to land close to it.
+	caseExpiryInterval = time.Minute
+
+	// caseExpiryBatch bounds how many ended cases one guild follows up per
+	// check. A larger backlog drains over the next checks.
+	caseExpiryBatch = 50
+
+	// caseExpiryMaxAge keeps stale follow-ups out of the case channel: a case
+	// that ended longer ago, say before the channel was set, is settled
+	// silently.
+	caseExpiryMaxAge = 24 * time.Hour
+)
+
+// caseExpiryStore finds the timed cases that ended. *postgres.Store
+// satisfies it.
+type caseExpiryStore interface {
+	ListExpiredModerationCases(ctx context.Context, guildID string, now time.Time, limit int) ([]coremod.Case, error)
+	MarkModerationCaseExpiryLogged(ctx context.Context, guildID string, caseNumber int64, at time.Time) error
+}
+
+// caseExpiryAnnouncer follows up ended timeouts in the moderation case
+// channel, quoting the case that imposed them.
+type caseExpiryAnnouncer struct {
+	instanceID    string
+	store         caseExpiryStore
+	logs          discordlogging.Publisher
+	configManager *files.ConfigManager
+	interval      time.Duration
+	now           func() time.Time
+}
+
+func newCaseExpiryAnnouncer(instanceID string, store caseExpiryStore, logs discordlogging.Publisher, configManager *files.ConfigManager) *caseExpiryAnnouncer {
+	return &caseExpiryAnnouncer{
+		instanceID:    instanceID,
+		store:         store,
+		logs:          logs,
+		configManager: configManager,
+		interval:      caseExpiryInterval,
+		now:           time.Now,
+	}
+}
+
+// run performs a pass right away, covering what ended while the bot was
+// down, then one per interval until ctx is done.
+func (a *caseExpiryAnnouncer) run(ctx context.Context) {
+	if a == nil || a.interval <= 0 {
+		return
+	}
+	a.pass(ctx)
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.pass(ctx)
+		}
+	}
+}
+
+// pass follows up the cases of each moderated guild that ended since the
+// last pass. Each case is settled once, whether or not its follow-up could be
+// posted, so a guild without a case channel never builds up a backlog.
+func (a *caseExpiryAnnouncer) pass(ctx context.Context) {
+	cfg := a.configManager.Config()
+	if cfg == nil {
+		return
+	}
+	now := a.now()
+	for _, guild := range files.GuildsForBotInstanceFeature(cfg, a.instanceID, "moderation") {
+		if ctx.Err() != nil {
+			return
+		}
+		cases, err := a.store.ListExpiredModerationCases(ctx, guild.GuildID, now, caseExpiryBatch)
+		if err != nil {
+			slog.Warn("Mitigated service degradation: Ended moderation cases could not be listed",
+				slog.String("botInstanceID", a.instanceID),
+				slog.String("guildID", guild.GuildID),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		channelID, err := discord.ParseSnowflake(strings.TrimSpace(guild.Channels.ModerationCase))
+		hasChannel := err == nil && channelID.IsValid()
+		for _, c := range cases {
+			if hasChannel && now.Sub(c.ExpiresAt) <= caseExpiryMaxAge {
+				a.announce(ctx, discord.ChannelID(channelID), c)
+			}
+			if err := a.store.MarkModerationCaseExpiryLogged(ctx, c.GuildID, c.CaseNumber, now); err != nil {
+				slog.Warn("Mitigated service degradation: Moderation case expiry could not be settled",
+					slog.String("botInstanceID", a.instanceID),
+					slog.String("guildID", c.GuildID),
+					slog.Int64("caseNumber", c.CaseNumber),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}
+}
+
+// announce queues the follow-up of c on the log publisher, which reports
+// delivery failures itself.
+func (a *caseExpiryAnnouncer) announce(ctx context.Context, channelID discord.ChannelID, c coremod.Case) {
+	a.logs.Notify(ctx, discordlogging.LogPost{
+		GuildID:   c.GuildID,
+		ChannelID: channelID,
+		EventType: applicationlogging.LogEventModerationCase,
+		UserID:    c.UserID,
+		ActorID:   c.ModeratorID,
+		Embed:     discordmod.ExpiryEmbed(c),
+	})
+	slog.Info("Architectural state transition: Moderation case expiry queued",
+		slog.String("botInstanceID", a.instanceID),
+		slog.String("guildID", c.GuildID),
+		slog.Int64("caseNumber", c.CaseNumber),
+		slog.String("action", c.Action),
+	)
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	discordlogging "github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+type fakeCaseExpiryStore struct {
+	expired map[string][]coremod.Case
+	settled map[string][]int64
+}
+
+func (f *fakeCaseExpiryStore) ListExpiredModerationCases(_ context.Context, guildID string, _ time.Time, _ int) ([]coremod.Case, error) {
+	return f.expired[guildID], nil
+}
+
+func (f *fakeCaseExpiryStore) MarkModerationCaseExpiryLogged(_ context.Context, guildID string, caseNumber int64, _ time.Time) error {
+	f.settled[guildID] = append(f.settled[guildID], caseNumber)
+	return nil
+}
+
+// fakeLogPublisher collects the logs posted through it by channel.
+type fakeLogPublisher struct {
+	sent map[discord.ChannelID][]discord.Embed
+}
+
+func newFakeLogPublisher() *fakeLogPublisher {
+	return &fakeLogPublisher{sent: map[discord.ChannelID][]discord.Embed{}}
+}
+
+func (f *fakeLogPublisher) Notify(_ context.Context, post discordlogging.LogPost) {
+	f.sent[post.ChannelID] = append(f.sent[post.ChannelID], post.Embed)
+}
+
+func (f *fakeLogPublisher) Publish(ctx context.Context, post discordlogging.LogPost) (*discord.Message, error) {
+	f.Notify(ctx, post)
+	return &discord.Message{ID: discord.MessageID(len(f.sent[post.ChannelID])), ChannelID: post.ChannelID}, nil
+}
+
+func TestCaseExpiryAnnouncerPass(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{
+		{GuildID: "1", Channels: files.ChannelsConfig{ModerationCase: "10"}},
+		{GuildID: "2"},
+	}})
+
+	store := &fakeCaseExpiryStore{
+		expired: map[string][]coremod.Case{
+			"1": {
+				{GuildID: "1", CaseNumber: 4, Action: "timeout", UserID: "7", ExpiresAt: now.Add(-time.Minute)},
+				{GuildID: "1", CaseNumber: 2, Action: "timeout", UserID: "8", ExpiresAt: now.Add(-48 * time.Hour)},
+			},
+			"2": {{GuildID: "2", CaseNumber: 9, Action: "timeout", UserID: "7", ExpiresAt: now.Add(-time.Minute)}},
+		},
+		settled: map[string][]int64{},
+	}
+	logs := newFakeLogPublisher()
+	announcer := newCaseExpiryAnnouncer("", store, logs, cfgMgr)
+	announcer.now = func() time.Time { return now }
+	announcer.pass(context.Background())
+
+	if len(logs.sent) != 1 || len(logs.sent[10]) != 1 || logs.sent[10][0].Fields[0].Value != "#4" {
+		t.Fatalf("expected a follow-up for case #4 only, got %+v", logs.sent)
+	}
+	if len(store.settled["1"]) != 2 || len(store.settled["2"]) != 1 {
+		t.Fatalf("expected every ended case to be settled, got %+v", store.settled)
+	}
+}
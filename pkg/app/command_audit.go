@@ -0,0 +1,158 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	discordlogging "github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	applicationlogging "github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/system"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const (
+	commandAuditTimeout    = 5 * time.Second
+	commandAuditOutcomeOK  = "ok"
+	commandAuditOutcomeErr = "error"
+)
+
+// commandAuditor persists privileged command executions and mirrors them to
+// the guild's command audit channel when one is configured and logs are
+// published.
+type commandAuditor struct {
+	repo          system.CommandAuditRepository
+	logs          discordlogging.Publisher
+	configManager *files.ConfigManager
+}
+
+// RecordCommand fulfills CommandAuditSink.
+func (a *commandAuditor) RecordCommand(ctx *cmd.Context, started time.Time, handlerErr error) {
+	data, ok := ctx.Event.Data.(*discord.CommandInteraction)
+	if !ok {
+		return
+	}
+	rec := buildCommandAuditRecord(ctx.Event, data, started, handlerErr)
+
+	auditCtx, cancel := context.WithTimeout(context.Background(), commandAuditTimeout)
+	defer cancel()
+	if a.repo != nil {
+		if err := a.repo.AppendCommandAudit(auditCtx, rec); err != nil {
+			slog.Error("Failed to persist command audit record",
+				slog.String("command", rec.Command),
+				slog.String("guild_id", rec.GuildID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+	a.mirror(auditCtx, rec)
+}
+
+// mirror queues rec on the log publisher, which reports delivery failures
+// itself.
+func (a *commandAuditor) mirror(ctx context.Context, rec system.CommandAuditRecord) {
+	if a.logs == nil || a.configManager == nil || rec.GuildID == "" {
+		return
+	}
+	gcfg := a.configManager.GuildConfig(rec.GuildID)
+	if gcfg == nil {
+		return
+	}
+	channelID, err := discord.ParseSnowflake(strings.TrimSpace(gcfg.Channels.CommandAudit))
+	if err != nil || !channelID.IsValid() {
+		return
+	}
+	a.logs.Notify(ctx, discordlogging.LogPost{
+		GuildID:   rec.GuildID,
+		ChannelID: discord.ChannelID(channelID),
+		EventType: applicationlogging.LogEventCommandAudit,
+		ActorID:   rec.UserID,
+		Embed:     buildCommandAuditEmbed(rec),
+	})
+}
+
+func buildCommandAuditRecord(event *discord.InteractionEvent, data *discord.CommandInteraction, started time.Time, handlerErr error) system.CommandAuditRecord {
+	path, options := flattenCommandOptions(data.Name, data.Options)
+	rec := system.CommandAuditRecord{
+		At:       started,
+		Command:  path,
+		Options:  options,
+		Outcome:  commandAuditOutcomeOK,
+		Duration: time.Since(started),
+	}
+	if event.GuildID.IsValid() {
+		rec.GuildID = event.GuildID.String()
+	}
+	if event.ChannelID.IsValid() {
+		rec.ChannelID = event.ChannelID.String()
+	}
+	if sender := event.Sender(); sender != nil {
+		rec.UserID = sender.ID.String()
+	}
+	if handlerErr != nil {
+		rec.Outcome = commandAuditOutcomeErr
+		rec.Error = handlerErr.Error()
+	}
+	return rec
+}
+
+// flattenCommandOptions walks subcommand groups and subcommands into the
+// invoked path and returns the leaf options as a JSON object of raw values.
+func flattenCommandOptions(name string, opts []discord.CommandInteractionOption) (string, string) {
+	path := []string{name}
+	for len(opts) == 1 && (opts[0].Type == discord.SubcommandGroupOptionType || opts[0].Type == discord.SubcommandOptionType) {
+		path = append(path, opts[0].Name)
+		opts = opts[0].Options
+	}
+	values := make(map[string]json.RawMessage, len(opts))
+	for _, opt := range opts {
+		if len(opt.Value) == 0 {
+			continue
+		}
+		values[opt.Name] = json.RawMessage(opt.Value)
+	}
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		encoded = []byte("{}")
+	}
+	return strings.Join(path, " "), string(encoded)
+}
+
+func buildCommandAuditEmbed(rec system.CommandAuditRecord) discord.Embed {
+	color := theme.Info()
+	if rec.Outcome != commandAuditOutcomeOK {
+		color = theme.Error()
+	}
+	embed := discord.Embed{
+		Title:       "/" + rec.Command,
+		Description: fmt.Sprintf("Run by <@%s> in <#%s>", rec.UserID, rec.ChannelID),
+		Color:       discord.Color(color),
+		Fields: []discord.EmbedField{
+			{Name: "Outcome", Value: rec.Outcome, Inline: true},
+			{Name: "Duration", Value: rec.Duration.Round(time.Millisecond).String(), Inline: true},
+		},
+		Timestamp: discord.NewTimestamp(rec.At),
+	}
+	if rec.Options != "" && rec.Options != "{}" {
+		embed.Fields = append(embed.Fields, discord.EmbedField{Name: "Options", Value: "```json\n" + truncateAuditValue(rec.Options, 1000) + "\n```"})
+	}
+	if rec.Error != "" {
+		embed.Fields = append(embed.Fields, discord.EmbedField{Name: "Error", Value: truncateAuditValue(rec.Error, 1000)})
+	}
+	return embed
+}
+
+func truncateAuditValue(value string, limit int) string {
+	value = strings.ReplaceAll(value, "```", "'''")
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+	return string([]rune(value)[:limit-1]) + "…"
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+)
+
+type recordingAuditSink struct {
+	calls []error
+}
+
+func (s *recordingAuditSink) RecordCommand(_ *cmd.Context, _ time.Time, err error) {
+	s.calls = append(s.calls, err)
+}
+
+func TestFlattenCommandOptions(t *testing.T) {
+	t.Parallel()
+
+	path, options := flattenCommandOptions("admin", []discord.CommandInteractionOption{
+		{
+			Type: discord.SubcommandGroupOptionType,
+			Name: "token",
+			Options: []discord.CommandInteractionOption{
+				{Type: discord.SubcommandOptionType, Name: "rotate"},
+			},
+		},
+	})
+	if path != "admin token rotate" || options != "{}" {
+		t.Fatalf("got %q %s", path, options)
+	}
+
+	path, options = flattenCommandOptions("ban", []discord.CommandInteractionOption{
+		{Type: discord.UserOptionType, Name: "user", Value: json.Raw(`"123"`)},
+		{Type: discord.StringOptionType, Name: "reason", Value: json.Raw(`"spam"`)},
+	})
+	if path != "ban" || options != `{"reason":"spam","user":"123"}` {
+		t.Fatalf("got %q %s", path, options)
+	}
+}
+
+func TestAuditMiddlewareRecordsPrivilegedCommandsOnly(t *testing.T) {
+	t.Parallel()
+
+	sink := &recordingAuditSink{}
+	failure := errors.New("missing permissions")
+	handler := Chain(func(*cmd.Context) error { return failure }, AuditMiddleware(sink))
+
+	for _, name := range []string{"ban", "qotd"} {
+		ctx := cmd.NewContext(context.Background(), nil, &discord.InteractionEvent{
+			Data: &discord.CommandInteraction{Name: name},
+		}, nil, nil, nil)
+		if err := handler(ctx); !errors.Is(err, failure) {
+			t.Fatalf("expected handler error to pass through, got %v", err)
+		}
+	}
+	if len(sink.calls) != 1 || !errors.Is(sink.calls[0], failure) {
+		t.Fatalf("expected only /ban to be audited with its outcome, got %v", sink.calls)
+	}
+}
+
+func TestTruncateAuditValueKeepsRunesWhole(t *testing.T) {
+	t.Parallel()
+
+	got := truncateAuditValue(strings.Repeat("日", 20), 10)
+	if !utf8.ValidString(got) || utf8.RuneCountInString(got) != 10 {
+		t.Fatalf("expected 10 whole runes, got %q", got)
+	}
+}
@@ -18,13 +18,16 @@ import (
 	"github.com/small-frappuccino/discordcore/pkg/discord/commands/moderation"
 	qotdcmd "github.com/small-frappuccino/discordcore/pkg/discord/commands/qotd"
 	"github.com/small-frappuccino/discordcore/pkg/discord/embeds"
+	discordlogging "github.com/small-frappuccino/discordcore/pkg/discord/logging"
 	"github.com/small-frappuccino/discordcore/pkg/discord/partners"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
 	"github.com/small-frappuccino/discordcore/pkg/discord/roles"
 	"github.com/small-frappuccino/discordcore/pkg/discord/tickets"
 	"github.com/small-frappuccino/discordcore/pkg/files"
 	"github.com/small-frappuccino/discordcore/pkg/runtimeapply"
 	"github.com/small-frappuccino/discordcore/pkg/service"
 	"github.com/small-frappuccino/discordcore/pkg/stats"
+	"github.com/small-frappuccino/discordcore/pkg/system"
 	"github.com/small-frappuccino/discordgo"
 )
 
@@ -49,11 +52,19 @@ type CommandHandler struct {
 	rolePanelService  *roles.RolePanelService
 	partnerService    *partners.PartnerService
 	runtimeApplier    *runtimeapply.Manager
+	auditor           CommandAuditSink
+	lockouts          ModerationLockouts
+	logs              discordlogging.Publisher
+	readOnly          bool
 
 	mu           sync.RWMutex
 	running      bool
 	startTime    time.Time
 	dependencies []string
+	// syncClient and appID are where SetupCommands synced the commands,
+	// kept for SyncGuildCommands.
+	syncClient BulkOverwriteClient
+	appID      discord.AppID
 }
 
 // CommandHandlerDeps encapsulates all required invariants for the CommandHandler.
@@ -71,6 +82,18 @@ type CommandHandlerDeps struct {
 	EmbedService        *embeds.EmbedService
 	RolePanelService    *roles.RolePanelService
 	PartnerService      *partners.PartnerService
+	// CommandAudit, when set, receives every privileged command execution.
+	CommandAudit system.CommandAuditRepository
+	// ReadOnly restricts dispatch to informational commands; see
+	// ReadOnlyMiddleware.
+	ReadOnly bool
+	// ModerationLockouts, when set, keeps locked-out moderators from
+	// moderation commands; see ModerationLockoutMiddleware.
+	ModerationLockouts ModerationLockouts
+	// LogPublisher, when set, posts the logs of commands, such as case logs
+	// and command audit records. Handlers find it in their context through
+	// discordlogging.PublisherFrom.
+	LogPublisher discordlogging.Publisher
 }
 
 // NewCommandHandler creates a new CommandHandler instance
@@ -104,6 +127,10 @@ func NewCommandHandlerForBot(deps CommandHandlerDeps) (*CommandHandler, error) {
 		rolePanelService:    deps.RolePanelService,
 		partnerService:      deps.PartnerService,
 		runtimeApplier:      deps.RuntimeApplier,
+		auditor:             &commandAuditor{repo: deps.CommandAudit, logs: deps.LogPublisher, configManager: deps.ConfigManager},
+		lockouts:            deps.ModerationLockouts,
+		logs:                deps.LogPublisher,
+		readOnly:            deps.ReadOnly,
 	}, nil
 }
 
@@ -238,7 +265,7 @@ func (ch *CommandHandler) SetupCommands() error {
 
 	// Assume no explicit guildID or botProfileID is passed to CompileAndSync for global or default setup, or we use botInstanceID
 	// Compile the O(1) map and conditionally sync
-	routerMap, err := ch.registrar.CompileAndSync(apiClient, discord.AppID(appID), "", ch.botInstanceID, ch.commandGroups)
+	routerMap, err := ch.registrar.CompileAndSync(apiClient, discord.AppID(appID), "", ch.botInstanceID, ch.commandGroups, ch.commandRegistration())
 	if err != nil {
 		if shutdownErr := ch.Shutdown(); shutdownErr != nil {
 			slog.Error("fatal failure during command manager registration rollback",
@@ -251,6 +278,9 @@ func (ch *CommandHandler) SetupCommands() error {
 	}
 
 	ch.routerMap.Store(&routerMap)
+	ch.mu.Lock()
+	ch.syncClient, ch.appID = apiClient, discord.AppID(appID)
+	ch.mu.Unlock()
 
 	// Direct method injection strictly avoids inline closure allocation overhead.
 	ch.interactionCancel = ch.session.AddHandler(ch.handleInteractionCreate)
@@ -262,8 +292,57 @@ func (ch *CommandHandler) SetupCommands() error {
 	return nil
 }
 
+// commandRegistration reads where command scopes lead from the config: the
+// guilds this instance serves and the global registration settings.
+func (ch *CommandHandler) commandRegistration() CommandRegistration {
+	cfg := ch.configManager.Config()
+	if cfg == nil {
+		return CommandRegistration{}
+	}
+	reg := CommandRegistration{
+		DevGuildID: cfg.RuntimeConfig.CommandDevGuildID,
+		Override:   cmd.RegistrationScope(strings.TrimSpace(cfg.RuntimeConfig.CommandScope)),
+	}
+	if reg.Override != "" && !reg.Override.Valid() {
+		slog.Warn("Mitigated service degradation: Unknown command_scope ignored",
+			slog.String("botInstanceID", ch.botInstanceID),
+			slog.String("commandScope", string(reg.Override)),
+		)
+	}
+	for _, guild := range files.GuildsForBotInstance(cfg, ch.botInstanceID) {
+		reg.GuildIDs = append(reg.GuildIDs, guild.GuildID)
+	}
+	if joined, err := listBotGuildIDsFromSessionState(ch.session); err == nil {
+		reg.JoinedGuildIDs = joined
+	}
+	reg.DisabledCommands = func(guildID string) []string {
+		if gc := ch.configManager.GuildConfig(guildID); gc != nil {
+			return gc.DisabledCommands
+		}
+		return nil
+	}
+	return reg
+}
+
+// SyncGuildCommands syncs the guild commands again from the current config,
+// so a guild that disabled or re-enabled commands sees the change in its
+// command list. It does nothing before SetupCommands or after Shutdown.
+func (ch *CommandHandler) SyncGuildCommands() {
+	if ch.routerMap.Load() == nil {
+		return
+	}
+	ch.mu.RLock()
+	client, appID := ch.syncClient, ch.appID
+	ch.mu.RUnlock()
+	if client == nil {
+		return
+	}
+	ch.registrar.SyncGuilds(client, appID, ch.commandRegistration())
+}
+
 // handleInteractionCreate executes isolated runtime processing.
 func (ch *CommandHandler) handleInteractionCreate(s *discordgo.Session, rawEvent *discordgo.Event) {
+	defer perf.RecoverGatewayPanic("commands.interaction")
 	if rawEvent.Type != "INTERACTION_CREATE" {
 		return
 	}
@@ -314,14 +393,31 @@ func (ch *CommandHandler) handleInteractionCreate(s *discordgo.Session, rawEvent
 
 	// Inject custom cmd.Context
 	apiClient := api.NewClient(ch.session.Token)
+	trackInteractionAck(apiClient, &arikawaEvent, routePath, time.Now)
 	logger := slog.With("guildID", arikawaEvent.GuildID.String(), "routePath", routePath)
 
 	// Create context with DI
-	cmdCtx := cmd.NewContext(context.Background(), apiClient, &arikawaEvent, logger, ch, nil) // nil for Tx for now
+	baseCtx := context.Background()
+	if ch.logs != nil {
+		baseCtx = discordlogging.WithPublisher(baseCtx, ch.logs)
+	}
+	cmdCtx := cmd.NewContext(baseCtx, apiClient, &arikawaEvent, logger, ch, nil) // nil for Tx for now
 
 	// Wrap handler with Middleware
 	feature := commands.ResolveFeatureForCommandPath(routePath)
-	wrappedHandler := Chain(handler, RateLimitMiddleware(), PermissionsMiddleware(feature))
+	middlewares := []Middleware{RateLimitMiddleware(), DisabledCommandsMiddleware(ch.configManager.GuildConfig)}
+	if ch.readOnly {
+		// The audit trail is a database write, so read-only instances skip it.
+		middlewares = append(middlewares, ReadOnlyMiddleware(), PermissionsMiddleware(feature))
+	} else {
+		// The audit wraps the permission check so refused attempts on
+		// privileged commands are recorded too.
+		middlewares = append(middlewares, AuditMiddleware(ch.auditor), PermissionsMiddleware(feature))
+	}
+	if ch.lockouts != nil {
+		middlewares = append(middlewares, ModerationLockoutMiddleware(feature, ch.lockouts))
+	}
+	wrappedHandler := Chain(handler, middlewares...)
 
 	// Execute handler
 	if err := wrappedHandler(cmdCtx); err != nil {
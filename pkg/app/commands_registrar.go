@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/diamondburned/arikawa/v3/api"
@@ -14,8 +17,13 @@ import (
 
 // CommandRegistrar compiles command groups and hashes them for O(1) routing and state syncing.
 type CommandRegistrar struct {
-	mu           sync.RWMutex
-	syncedHashes map[discord.AppID]string
+	mu sync.RWMutex
+	// syncedHashes is keyed by sync target: the application ID for global
+	// commands, "appID/guildID" for a guild's commands.
+	syncedHashes map[string]string
+	// compiled holds the commands of the last CompileAndSync by scope, for
+	// SyncGuilds to sync again.
+	compiled map[cmd.RegistrationScope][]api.CreateCommandData
 }
 
 // CommandCatalogCapabilities defines a bitmask for capability requirements.
@@ -44,26 +52,54 @@ func (c CommandCatalogCapabilities) Has(target CommandCatalogCapabilities) bool
 // NewCommandRegistrar creates a new CommandRegistrar.
 func NewCommandRegistrar() *CommandRegistrar {
 	return &CommandRegistrar{
-		syncedHashes: make(map[discord.AppID]string),
+		syncedHashes: make(map[string]string),
 	}
 }
 
 // BulkOverwriteClient exposes the Arikawa API surface for syncing commands.
 type BulkOverwriteClient interface {
 	BulkOverwriteCommands(appID discord.AppID, commands []api.CreateCommandData) ([]discord.Command, error)
+	BulkOverwriteGuildCommands(appID discord.AppID, guildID discord.GuildID, commands []api.CreateCommandData) ([]discord.Command, error)
+}
+
+// CommandRegistration says where the scopes of compiled commands lead.
+type CommandRegistration struct {
+	// GuildIDs are the guilds the bot instance serves, where guild scoped
+	// commands are registered.
+	GuildIDs []string
+	// DevGuildID is the development guild. Without one, dev guild scoped
+	// commands are not registered.
+	DevGuildID string
+	// JoinedGuildIDs are the guilds the bot is a member of. Those that are
+	// neither served nor the development guild are synced with an empty
+	// list, so commands an earlier process left there, e.g. on a former
+	// development guild, are cleared after a restart too.
+	JoinedGuildIDs []string
+	// Override, when valid, replaces the scope of every command.
+	Override cmd.RegistrationScope
+	// DisabledCommands, when set, names the commands a guild turned off.
+	// They are left out of the guild's own commands. Global commands reach
+	// every guild alike, so those stay and are refused when invoked.
+	DisabledCommands func(guildID string) []string
 }
 
 // CompileAndSync consumes command groups, compiles an O(1) routing map, and conditionally syncs via hashing.
+//
+// Global commands are always synced, so commands moved to another scope are
+// removed. Served, joined and development guilds are synced too, with an
+// empty list when nothing is scoped to them, so commands left there by an
+// earlier scope are cleared; the hashes make that a request per guild once.
 func (r *CommandRegistrar) CompileAndSync(
 	client BulkOverwriteClient,
 	appID discord.AppID,
 	guildID string,
 	botProfileID string,
 	groups []cmd.CommandGroup,
+	reg CommandRegistration,
 ) (map[string]cmd.CommandHandler, error) {
 
 	routerMap := make(map[string]cmd.CommandHandler)
-	var allCreateData []api.CreateCommandData
+	sets := make(map[cmd.RegistrationScope][]api.CreateCommandData)
 
 	for _, g := range groups {
 		// Populate O(1) map
@@ -75,44 +111,156 @@ func (r *CommandRegistrar) CompileAndSync(
 			routerMap[name] = handler
 		}
 
-		// Collect AST tree for hashing
-		data := g.Register(guildID, botProfileID)
-		allCreateData = append(allCreateData, data...)
+		// Collect AST tree for hashing, split by where each command goes
+		for _, data := range g.Register(guildID, botProfileID) {
+			scope := commandScope(g, data.Name, reg.Override)
+			sets[scope] = append(sets[scope], data)
+		}
+	}
+
+	r.mu.Lock()
+	r.compiled = sets
+	r.mu.Unlock()
+
+	if err := r.sync(appID.String(), sets[cmd.ScopeGlobal], func(data []api.CreateCommandData) error {
+		_, err := client.BulkOverwriteCommands(appID, data)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(reg.DevGuildID) == "" && len(sets[cmd.ScopeDevGuild]) > 0 {
+		slog.Warn("Mitigated service degradation: Development guild commands not registered without command_dev_guild_id",
+			slog.String("appID", appID.String()),
+			slog.Int("commands", len(sets[cmd.ScopeDevGuild])),
+		)
+	}
+	r.syncGuilds(client, appID, sets, reg)
+	return routerMap, nil
+}
+
+// SyncGuilds syncs the guild commands compiled by the last CompileAndSync
+// again under reg, e.g. after a guild disabled or re-enabled commands.
+// Guilds whose commands did not change cost no request.
+func (r *CommandRegistrar) SyncGuilds(client BulkOverwriteClient, appID discord.AppID, reg CommandRegistration) {
+	r.mu.RLock()
+	sets := r.compiled
+	r.mu.RUnlock()
+	if sets == nil {
+		return
+	}
+	r.syncGuilds(client, appID, sets, reg)
+}
+
+// syncGuilds syncs the served guilds and the development guild, each
+// without the commands it disabled. Joined guilds and guilds synced before
+// that are neither any longer, e.g. a guild dropped from the config or a
+// former development guild, are cleared.
+func (r *CommandRegistrar) syncGuilds(client BulkOverwriteClient, appID discord.AppID, sets map[cmd.RegistrationScope][]api.CreateCommandData, reg CommandRegistration) {
+	devGuildID := strings.TrimSpace(reg.DevGuildID)
+	targets := make(map[string][]api.CreateCommandData)
+	prefix := appID.String() + "/"
+	r.mu.RLock()
+	for target := range r.syncedHashes {
+		if id, ok := strings.CutPrefix(target, prefix); ok {
+			targets[id] = nil
+		}
+	}
+	r.mu.RUnlock()
+	for _, id := range reg.JoinedGuildIDs {
+		targets[id] = nil
+	}
+	for _, id := range reg.GuildIDs {
+		targets[id] = sets[cmd.ScopeGuild]
+	}
+	if devGuildID != "" {
+		targets[devGuildID] = append(slices.Clone(targets[devGuildID]), sets[cmd.ScopeDevGuild]...)
+	}
+	for _, id := range slices.Sorted(maps.Keys(targets)) {
+		snowflake, err := discord.ParseSnowflake(id)
+		if err != nil {
+			continue
+		}
+		data := targets[id]
+		if reg.DisabledCommands != nil {
+			data = withoutCommands(data, reg.DisabledCommands(id))
+		}
+		// A guild that refuses the commands, e.g. because the bot was added
+		// without the applications.commands scope, leaves the others and the
+		// global commands working.
+		if err := r.sync(prefix+id, data, func(data []api.CreateCommandData) error {
+			_, err := client.BulkOverwriteGuildCommands(appID, discord.GuildID(snowflake), data)
+			return err
+		}); err != nil {
+			slog.Warn("Mitigated service degradation: Guild commands could not be synced",
+				slog.String("appID", appID.String()),
+				slog.String("guildID", id),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// withoutCommands returns data without the commands named in disabled.
+func withoutCommands(data []api.CreateCommandData, disabled []string) []api.CreateCommandData {
+	if len(disabled) == 0 {
+		return data
+	}
+	return slices.DeleteFunc(slices.Clone(data), func(d api.CreateCommandData) bool {
+		return slices.Contains(disabled, d.Name)
+	})
+}
+
+// commandScope returns where the command name of g is registered: override
+// when valid, else the scope g gives it. Unknown scopes fall back to global.
+func commandScope(g cmd.CommandGroup, name string, override cmd.RegistrationScope) cmd.RegistrationScope {
+	if override.Valid() {
+		return override
+	}
+	if scoped, ok := g.(cmd.ScopedCommandGroup); ok {
+		if scope := scoped.RegistrationScope(name); scope.Valid() {
+			return scope
+		}
 	}
+	return cmd.ScopeGlobal
+}
 
+// sync bulk overwrites the commands of target through overwrite unless data
+// matches what was last synced there.
+func (r *CommandRegistrar) sync(target string, data []api.CreateCommandData, overwrite func([]api.CreateCommandData) error) error {
 	// Compute deterministic hash (SHA-256) of the AST-generated command tree
-	bytes, err := json.Marshal(allCreateData)
+	bytes, err := json.Marshal(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal command tree for hashing: %w", err)
+		return fmt.Errorf("failed to marshal command tree for hashing: %w", err)
 	}
 	hash := fmt.Sprintf("%x", sha256.Sum256(bytes))
 
 	// Conditionally sync to Discord
 	r.mu.RLock()
-	lastHash, exists := r.syncedHashes[appID]
+	lastHash, exists := r.syncedHashes[target]
 	r.mu.RUnlock()
 
-	if !exists || lastHash != hash {
-		slog.Info("Command tree hash mismatch, executing Bulk Overwrite",
-			slog.String("appID", appID.String()),
-			slog.String("oldHash", lastHash),
-			slog.String("newHash", hash),
-		)
-
-		_, err := client.BulkOverwriteCommands(appID, allCreateData)
-		if err != nil {
-			return nil, fmt.Errorf("failed to bulk overwrite commands: %w", err)
-		}
-
-		r.mu.Lock()
-		r.syncedHashes[appID] = hash
-		r.mu.Unlock()
-	} else {
+	if exists && lastHash == hash {
 		slog.Debug("Command tree hash matches, skipping Bulk Overwrite",
-			slog.String("appID", appID.String()),
+			slog.String("target", target),
 			slog.String("hash", hash),
 		)
+		return nil
+	}
+	slog.Info("Command tree hash mismatch, executing Bulk Overwrite",
+		slog.String("target", target),
+		slog.String("oldHash", lastHash),
+		slog.String("newHash", hash),
+	)
+	if data == nil {
+		// An empty list, not null, is what clears a target.
+		data = []api.CreateCommandData{}
+	}
+	if err := overwrite(data); err != nil {
+		return fmt.Errorf("failed to bulk overwrite commands: %w", err)
 	}
 
-	return routerMap, nil
+	r.mu.Lock()
+	r.syncedHashes[target] = hash
+	r.mu.Unlock()
+	return nil
 }
@@ -0,0 +1,231 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+)
+
+type scopedGroup struct {
+	scopes map[string]cmd.RegistrationScope
+}
+
+func (g scopedGroup) Register(string, string) []api.CreateCommandData {
+	var data []api.CreateCommandData
+	for _, name := range []string{"ban", "stats", "beta"} {
+		if _, ok := g.scopes[name]; ok {
+			data = append(data, api.CreateCommandData{Name: name})
+		}
+	}
+	return data
+}
+
+func (g scopedGroup) Handle(string, string) map[string]cmd.CommandHandler {
+	return map[string]cmd.CommandHandler{}
+}
+
+func (g scopedGroup) RegistrationScope(name string) cmd.RegistrationScope {
+	return g.scopes[name]
+}
+
+type recordingOverwriteClient struct {
+	global []string
+	guilds map[discord.GuildID][]string
+	calls  int
+	fail   discord.GuildID
+}
+
+func names(data []api.CreateCommandData) []string {
+	out := []string{}
+	for _, d := range data {
+		out = append(out, d.Name)
+	}
+	return out
+}
+
+func (c *recordingOverwriteClient) BulkOverwriteCommands(_ discord.AppID, data []api.CreateCommandData) ([]discord.Command, error) {
+	c.calls++
+	c.global = names(data)
+	return nil, nil
+}
+
+func (c *recordingOverwriteClient) BulkOverwriteGuildCommands(_ discord.AppID, guildID discord.GuildID, data []api.CreateCommandData) ([]discord.Command, error) {
+	c.calls++
+	if guildID == c.fail {
+		return nil, errors.New("missing access")
+	}
+	if c.guilds == nil {
+		c.guilds = make(map[discord.GuildID][]string)
+	}
+	c.guilds[guildID] = names(data)
+	return nil, nil
+}
+
+func TestCompileAndSyncScopes(t *testing.T) {
+	t.Parallel()
+	groups := []cmd.CommandGroup{scopedGroup{scopes: map[string]cmd.RegistrationScope{
+		"ban":   cmd.ScopeGlobal,
+		"stats": cmd.ScopeGuild,
+		"beta":  cmd.ScopeDevGuild,
+	}}}
+	reg := CommandRegistration{GuildIDs: []string{"1", "2", "3"}, DevGuildID: "9"}
+
+	client := &recordingOverwriteClient{fail: 3}
+	registrar := NewCommandRegistrar()
+	if _, err := registrar.CompileAndSync(client, 100, "", "", groups, reg); err != nil {
+		t.Fatalf("a guild refusing its commands should not fail the sync: %v", err)
+	}
+	if len(client.global) != 1 || client.global[0] != "ban" {
+		t.Fatalf("global commands = %v, want [ban]", client.global)
+	}
+	for _, id := range []discord.GuildID{1, 2} {
+		if got := client.guilds[id]; len(got) != 1 || got[0] != "stats" {
+			t.Fatalf("guild %d commands = %v, want [stats]", id, got)
+		}
+	}
+	if got := client.guilds[9]; len(got) != 1 || got[0] != "beta" {
+		t.Fatalf("dev guild commands = %v, want [beta]", got)
+	}
+
+	calls := client.calls
+	if _, err := registrar.CompileAndSync(client, 100, "", "", groups, reg); err != nil {
+		t.Fatalf("resync: %v", err)
+	}
+	// Only the guild that failed is retried.
+	if client.calls != calls+1 {
+		t.Fatalf("expected only the failed guild to be synced again, got %d calls", client.calls-calls)
+	}
+
+	client = &recordingOverwriteClient{}
+	reg.Override = cmd.ScopeDevGuild
+	if _, err := NewCommandRegistrar().CompileAndSync(client, 100, "", "", groups, reg); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(client.global) != 0 || len(client.guilds[9]) != 3 {
+		t.Fatalf("expected every command in the dev guild, got global %v guilds %v", client.global, client.guilds)
+	}
+	for _, id := range []discord.GuildID{1, 2, 3} {
+		if got, ok := client.guilds[id]; !ok || len(got) != 0 {
+			t.Fatalf("guild %d commands = %v (synced %v), want them cleared", id, got, ok)
+		}
+	}
+}
+
+func TestSyncGuildsClearsFormerTargets(t *testing.T) {
+	t.Parallel()
+	groups := []cmd.CommandGroup{scopedGroup{scopes: map[string]cmd.RegistrationScope{
+		"ban":   cmd.ScopeGlobal,
+		"stats": cmd.ScopeGuild,
+		"beta":  cmd.ScopeDevGuild,
+	}}}
+	reg := CommandRegistration{GuildIDs: []string{"1", "2"}, DevGuildID: "9"}
+
+	client := &recordingOverwriteClient{}
+	registrar := NewCommandRegistrar()
+	if _, err := registrar.CompileAndSync(client, 100, "", "", groups, reg); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+
+	// Guild 2 leaves the config and the development guild moves.
+	reg = CommandRegistration{GuildIDs: []string{"1"}, DevGuildID: "8"}
+	registrar.SyncGuilds(client, 100, reg)
+	for _, id := range []discord.GuildID{2, 9} {
+		if got := client.guilds[id]; len(got) != 0 {
+			t.Fatalf("guild %d commands = %v, want them cleared", id, got)
+		}
+	}
+	if got := client.guilds[8]; len(got) != 1 || got[0] != "beta" {
+		t.Fatalf("new dev guild commands = %v, want [beta]", got)
+	}
+
+	// Switching everything back to global clears the remaining guilds.
+	reg.Override = cmd.ScopeGlobal
+	if _, err := registrar.CompileAndSync(client, 100, "", "", groups, reg); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(client.global) != 3 {
+		t.Fatalf("global commands = %v, want all three", client.global)
+	}
+	for _, id := range []discord.GuildID{1, 8} {
+		if got := client.guilds[id]; len(got) != 0 {
+			t.Fatalf("guild %d commands = %v, want them cleared", id, got)
+		}
+	}
+
+	calls := client.calls
+	registrar.SyncGuilds(client, 100, reg)
+	if client.calls != calls {
+		t.Fatalf("expected cleared guilds to be skipped, got %d calls", client.calls-calls)
+	}
+}
+
+func TestCompileAndSyncClearsJoinedGuildsAfterRestart(t *testing.T) {
+	t.Parallel()
+	groups := []cmd.CommandGroup{scopedGroup{scopes: map[string]cmd.RegistrationScope{"ban": cmd.ScopeGlobal}}}
+	// A fresh registrar knows nothing of guild 9, the development guild of
+	// an earlier process, but the bot is still in it.
+	reg := CommandRegistration{GuildIDs: []string{"1"}, JoinedGuildIDs: []string{"1", "9"}}
+
+	client := &recordingOverwriteClient{}
+	if _, err := NewCommandRegistrar().CompileAndSync(client, 100, "", "", groups, reg); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	for _, id := range []discord.GuildID{1, 9} {
+		got, ok := client.guilds[id]
+		if !ok || len(got) != 0 {
+			t.Fatalf("guild %d commands = %v (synced: %v), want them cleared", id, got, ok)
+		}
+	}
+}
+
+func TestCompileAndSyncLeavesOutDisabledCommands(t *testing.T) {
+	t.Parallel()
+	groups := []cmd.CommandGroup{scopedGroup{scopes: map[string]cmd.RegistrationScope{
+		"ban":   cmd.ScopeGlobal,
+		"stats": cmd.ScopeGuild,
+		"beta":  cmd.ScopeGuild,
+	}}}
+	disabled := map[string][]string{"1": {"stats", "ban"}}
+	reg := CommandRegistration{
+		GuildIDs:         []string{"1", "2"},
+		DisabledCommands: func(guildID string) []string { return disabled[guildID] },
+	}
+
+	client := &recordingOverwriteClient{}
+	registrar := NewCommandRegistrar()
+	if _, err := registrar.CompileAndSync(client, 100, "", "", groups, reg); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(client.global) != 1 || client.global[0] != "ban" {
+		t.Fatalf("global commands = %v, want [ban] whatever a guild disables", client.global)
+	}
+	if got := client.guilds[1]; len(got) != 1 || got[0] != "beta" {
+		t.Fatalf("guild 1 commands = %v, want [beta]", got)
+	}
+	if got := client.guilds[2]; len(got) != 2 {
+		t.Fatalf("guild 2 commands = %v, want [stats beta]", got)
+	}
+
+	calls := client.calls
+	delete(disabled, "1")
+	disabled["2"] = []string{"beta"}
+	registrar.SyncGuilds(client, 100, reg)
+	if client.calls != calls+2 {
+		t.Fatalf("expected both changed guilds to be synced again, got %d calls", client.calls-calls)
+	}
+	if got := client.guilds[1]; len(got) != 2 {
+		t.Fatalf("guild 1 commands after re-enabling = %v, want [stats beta]", got)
+	}
+	if got := client.guilds[2]; len(got) != 1 || got[0] != "stats" {
+		t.Fatalf("guild 2 commands = %v, want [stats]", got)
+	}
+
+	calls = client.calls
+	registrar.SyncGuilds(client, 100, reg)
+	if client.calls != calls {
+		t.Fatalf("expected unchanged guilds to be skipped, got %d calls", client.calls-calls)
+	}
+}
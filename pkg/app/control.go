@@ -14,7 +14,9 @@ import (
 	"github.com/small-frappuccino/discordcore/pkg/control"
 	"github.com/small-frappuccino/discordcore/pkg/control/localtls"
 	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
 	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/keylock"
 	"github.com/small-frappuccino/discordcore/pkg/log"
 
 	"github.com/diamondburned/arikawa/v3/discord"
@@ -50,6 +52,33 @@ type RunOptions struct {
 	DisableControl bool
 	Logger         *slog.Logger
 
+	// Entitlements gates premium features per guild. Nil entitles every
+	// guild to every feature.
+	Entitlements files.EntitlementProvider
+
+	// ReadOnly runs a standby against a replicated database: no migrations,
+	// no configuration writes, no background jobs, no event logging or
+	// moderation, and only informational commands. DISCORDCORE_READ_ONLY
+	// also enables it.
+	ReadOnly bool
+
+	// LeaderElection makes instances sharing a database compete for a lease;
+	// only the holder starts its bots, scheduled jobs and control plane.
+	// DISCORDCORE_LEADER_ELECTION also enables it. Ignored when ReadOnly.
+	LeaderElection bool
+
+	// CleanChannelLocks are the channel locks of the /clean service the
+	// embedding app builds (see discordclean.WithChannelLocks). Auto-purge
+	// takes its locks from the same set, so the two never clean one channel
+	// at once. Nil gives auto-purge a set of its own.
+	CleanChannelLocks *keylock.Mutex[discord.ChannelID]
+
+	// ModerationService is the service behind the app's moderation commands.
+	// The runtime attaches the guild contexts it authorizes actions against,
+	// read through the bot serving moderation in each guild; without them
+	// every moderation action is refused.
+	ModerationService *discordmod.Service
+
 	// Testing Hooks (Replacing globals)
 	StoreCloseHook          func(c interface{ Close() error }) error
 	DiscordSessionCloseHook func(c interface{ Close() error }) error
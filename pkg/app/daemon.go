@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/observability"
+	"github.com/small-frappuccino/discordcore/pkg/sys"
+)
+
+// systemd notify protocol states emitted across the daemon lifecycle. Units
+// opt in with Type=notify; without NOTIFY_SOCKET every notification is a no-op.
+const (
+	sdNotifyReady     = "READY=1"
+	sdNotifyReloading = "RELOADING=1"
+	sdNotifyStopping  = "STOPPING=1"
+)
+
+// notifyServiceManager forwards a readiness state to the supervising service
+// manager. Failures are logged and swallowed: readiness reporting must never
+// take the bot down.
+func (a *App) notifyServiceManager(state string) {
+	sent, err := sys.SdNotify(state)
+	if err != nil {
+		a.logger.Warn("Mitigated service degradation: Service manager readiness notification failed",
+			slog.String("state", state),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	if sent {
+		a.logger.Debug("Granular transient state inspection: Service manager notified",
+			slog.String("state", state),
+		)
+	}
+}
+
+// dumpRuntimeStats logs a point-in-time snapshot of process and service health.
+// It is triggered by SIGUSR1 so operators can inspect a live daemon through
+// journald without attaching a debugger or exposing the control server.
+func (a *App) dumpRuntimeStats() {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	attrs := []any{
+		slog.String("app_name", a.appName),
+		slog.Duration("uptime", time.Since(a.startedAt).Round(time.Second)),
+		slog.Int("goroutines", runtime.NumGoroutine()),
+		slog.Uint64("heap_alloc_bytes", mem.HeapAlloc),
+		slog.Uint64("sys_bytes", mem.Sys),
+		slog.Uint64("gc_cycles", uint64(mem.NumGC)),
+		slog.Any("recovered_panics", observability.SnapshotRecoveredPanics()),
+	}
+	if a.serviceManager != nil {
+		attrs = append(attrs, slog.Any("running_services", a.serviceManager.GetRunningServices()))
+	}
+	if a.configManager != nil {
+		if cfg := a.configManager.Config(); cfg != nil {
+			attrs = append(attrs, slog.Int("configured_guilds", len(cfg.Guilds)))
+		}
+	}
+	if a.membersMetrics != nil {
+		attrs = append(attrs, slog.Any("members", a.membersMetrics.Snapshot()))
+	}
+	if a.messagesMetrics != nil {
+		attrs = append(attrs, slog.Any("messages", a.messagesMetrics.Snapshot()))
+	}
+
+	a.logger.Info("Granular transient state inspection: Runtime statistics snapshot", attrs...)
+}
+
+// reloadConfigFromSignal re-reads the config store in response to SIGHUP and
+// applies it in place. The service manager sees RELOADING=1 for the duration
+// and READY=1 afterwards, whether or not the reload succeeded, because a failed
+// reload keeps serving the active baseline.
+func (a *App) reloadConfigFromSignal(ctx context.Context) {
+	a.notifyServiceManager(sdNotifyReloading)
+	defer a.notifyServiceManager(sdNotifyReady)
+
+	// Serialized mutation governed by CSP and bounded by strict timeout.
+	mutCtx, mutCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer mutCancel()
+
+	newCfg, needsSave, err := a.configManager.LoadConfigFromStore()
+	if err != nil {
+		slog.Warn("Mitigated service degradation: Live configuration mutation failed; enforcing active baseline",
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	if mutCtx.Err() != nil {
+		return
+	}
+
+	dupCount := a.configManager.ApplyConfig(newCfg)
+
+	if dupCount == 0 && !needsSave {
+		slog.Info("Architectural state transition: Configuration topology refreshed directly from disk")
+		return
+	}
+
+	if saveErr := a.configManager.SaveConfig(); saveErr != nil {
+		log.EmitBlockingError("Structural state failure: Volatile configuration drift blocks persistence flush", saveErr, log.GenerateRequestID())
+		return
+	}
+	slog.Info("Architectural state transition: Configuration topology updated and indexes rebuilt",
+		slog.Int("duplicates_purged", dupCount),
+	)
+}
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+	"github.com/small-frappuccino/discordcore/pkg/messages"
+	"github.com/small-frappuccino/discordcore/pkg/storage/postgres"
+)
+
+const (
+	// downtimeReconcileThreshold is the silence, measured from the last
+	// persisted event or heartbeat, that counts as downtime rather than a
+	// quick restart.
+	downtimeReconcileThreshold = 5 * time.Minute
+	// downtimeMessageLookback bounds how far before the outage cached
+	// messages are re-checked; older entries are unlikely to be touched and
+	// each check costs one REST call.
+	downtimeMessageLookback = 24 * time.Hour
+	downtimeMessageLimit    = 500
+	downtimeReconcileBudget = 15 * time.Minute
+)
+
+// activityClock reports the persisted liveness marks of a bot instance.
+type activityClock interface {
+	HeartbeatForBot(ctx context.Context, instanceID string) (time.Time, bool, error)
+	LastEventForBot(ctx context.Context, instanceID string) (time.Time, bool, error)
+}
+
+// detectDowntime returns the last moment instanceID was known to be alive
+// and whether the gap until now is long enough to warrant reconciliation. A
+// first start, with nothing persisted, is not downtime.
+func detectDowntime(ctx context.Context, repo activityClock, instanceID string, now time.Time) (time.Time, bool) {
+	if repo == nil {
+		return time.Time{}, false
+	}
+	var lastSeen time.Time
+	if at, ok, err := repo.LastEventForBot(ctx, instanceID); err == nil && ok {
+		lastSeen = at
+	}
+	if at, ok, err := repo.HeartbeatForBot(ctx, instanceID); err == nil && ok && at.After(lastSeen) {
+		lastSeen = at
+	}
+	if lastSeen.IsZero() {
+		return time.Time{}, false
+	}
+	return lastSeen, now.Sub(lastSeen) >= downtimeReconcileThreshold
+}
+
+// DowntimeReconcileTask repairs the persisted member and message snapshots of
+// one bot runtime after it was offline, so diff-based logs resume from the
+// real Discord state instead of reporting stale or missing changes.
+type DowntimeReconcileTask struct {
+	runtime       *botRuntime
+	store         *postgres.Store
+	configManager *files.ConfigManager
+	lastSeen      time.Time
+}
+
+func (t DowntimeReconcileTask) Execute(ctx context.Context) error {
+	if t.runtime == nil || t.runtime.arikawaState == nil || t.store == nil || t.configManager == nil {
+		return nil
+	}
+	cfg := t.configManager.Config()
+	if cfg == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, downtimeReconcileBudget)
+	defer cancel()
+
+	instanceID := t.runtime.instanceID
+	st := t.runtime.arikawaState
+	slog.Info("Architectural state transition: Reconciling state after downtime",
+		slog.String("botInstanceID", instanceID),
+		slog.Time("last_seen", t.lastSeen),
+		slog.Duration("downtime", time.Since(t.lastSeen).Round(time.Second)),
+	)
+
+	for _, guild := range files.GuildsForBotInstanceFeature(cfg, instanceID, "logging") {
+		if ctx.Err() != nil {
+			return nil
+		}
+		guildID := guild.GuildID
+
+		roles, err := members.ReconcileRoleSnapshots(ctx, t.store, guildID, liveGuildMembers(st, guildID), time.Now().UTC())
+		if err != nil {
+			slog.Warn("Mitigated service degradation: Member snapshot reconciliation failed",
+				slog.String("botInstanceID", instanceID),
+				slog.String("guildID", guildID),
+				slog.String("error", err.Error()),
+			)
+		}
+
+		cached, err := messages.ReconcileCachedMessages(ctx, t.store, guildID,
+			t.lastSeen.Add(-downtimeMessageLookback), downtimeMessageLimit, liveMessageFetcher(st))
+		if err != nil {
+			slog.Warn("Mitigated service degradation: Message cache reconciliation failed",
+				slog.String("botInstanceID", instanceID),
+				slog.String("guildID", guildID),
+				slog.String("error", err.Error()),
+			)
+		}
+
+		slog.Info("Architectural state transition: Guild state reconciled after downtime",
+			slog.String("botInstanceID", instanceID),
+			slog.String("guildID", guildID),
+			slog.Int("members_checked", roles.Checked),
+			slog.Int("roles_updated", roles.RolesUpdated),
+			slog.Int("members_marked_left", roles.MarkedLeft),
+			slog.Int("members_added", roles.Added),
+			slog.Int("messages_checked", cached.Checked),
+			slog.Int("messages_updated", cached.Updated),
+			slog.Int("messages_removed", cached.Removed),
+		)
+	}
+	return nil
+}
+
+func (t DowntimeReconcileTask) Name() string {
+	return "downtime_reconcile_" + t.runtime.instanceID
+}
+
+// liveGuildMembers lists every member of guildID over REST.
+func liveGuildMembers(st *state.State, guildID string) iter.Seq2[members.LiveMember, error] {
+	return func(yield func(members.LiveMember, error) bool) {
+		sf, err := discord.ParseSnowflake(guildID)
+		if err != nil {
+			yield(members.LiveMember{}, fmt.Errorf("parse guild id: %w", err))
+			return
+		}
+		list, err := st.Client.MembersAfter(discord.GuildID(sf), 0, 0)
+		if err != nil {
+			yield(members.LiveMember{}, err)
+			return
+		}
+		for _, m := range list {
+			roles := make([]string, len(m.RoleIDs))
+			for i, r := range m.RoleIDs {
+				roles[i] = r.String()
+			}
+			live := members.LiveMember{UserID: m.User.ID.String(), Roles: roles, JoinedAt: m.Joined.Time(), IsBot: m.User.Bot}
+			if !yield(live, nil) {
+				return
+			}
+		}
+	}
+}
+
+// liveMessageFetcher looks messages up over REST, bypassing the state cabinet
+// which cannot know about edits made while the bot was offline.
+func liveMessageFetcher(st *state.State) messages.MessageFetcher {
+	return func(ctx context.Context, channelID, messageID string) (messages.FetchResult, error) {
+		chSF, err := discord.ParseSnowflake(channelID)
+		if err != nil {
+			return messages.FetchResult{}, err
+		}
+		msgSF, err := discord.ParseSnowflake(messageID)
+		if err != nil {
+			return messages.FetchResult{}, err
+		}
+		msg, err := st.Client.WithContext(ctx).Message(discord.ChannelID(chSF), discord.MessageID(msgSF))
+		if err != nil {
+			var httpErr *httputil.HTTPError
+			if errors.As(err, &httpErr) && httpErr.Status == http.StatusNotFound {
+				return messages.FetchResult{}, nil
+			}
+			return messages.FetchResult{}, err
+		}
+		return messages.FetchResult{Exists: true, Content: msg.Content}, nil
+	}
+}
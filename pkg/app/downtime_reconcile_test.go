@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeActivityClock struct {
+	heartbeat, lastEvent time.Time
+}
+
+func (f fakeActivityClock) HeartbeatForBot(context.Context, string) (time.Time, bool, error) {
+	return f.heartbeat, !f.heartbeat.IsZero(), nil
+}
+
+func (f fakeActivityClock) LastEventForBot(context.Context, string) (time.Time, bool, error) {
+	return f.lastEvent, !f.lastEvent.IsZero(), nil
+}
+
+func TestDetectDowntime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	ctx := context.Background()
+
+	if _, down := detectDowntime(ctx, fakeActivityClock{}, "main", now); down {
+		t.Fatal("expected first start without liveness marks not to count as downtime")
+	}
+	if _, down := detectDowntime(ctx, fakeActivityClock{lastEvent: now.Add(-time.Minute)}, "main", now); down {
+		t.Fatal("expected a quick restart not to count as downtime")
+	}
+
+	clock := fakeActivityClock{lastEvent: now.Add(-2 * time.Hour), heartbeat: now.Add(-time.Hour)}
+	lastSeen, down := detectDowntime(ctx, clock, "main", now)
+	if !down || !lastSeen.Equal(clock.heartbeat) {
+		t.Fatalf("expected downtime from the latest liveness mark, got lastSeen=%s down=%v", lastSeen, down)
+	}
+}
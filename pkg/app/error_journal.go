@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/observability"
+	"github.com/small-frappuccino/discordcore/pkg/storage/postgres"
+)
+
+const (
+	errorJournalFlushInterval = 30 * time.Second
+	errorJournalPruneInterval = 6 * time.Hour
+	errorJournalRetention     = 30 * 24 * time.Hour
+)
+
+// scheduleErrorJournalFlush attaches store as the durable archive of the
+// process error journal and keeps it flushed and pruned until ctx ends.
+func scheduleErrorJournalFlush(ctx context.Context, store *postgres.Store) {
+	if store == nil {
+		return
+	}
+	journal := observability.Errors()
+	journal.SetArchive(store)
+
+	go func() {
+		flush := time.NewTicker(errorJournalFlushInterval)
+		defer flush.Stop()
+		prune := time.NewTicker(errorJournalPruneInterval)
+		defer prune.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-flush.C:
+				// Failures are logged below error level so they do not feed
+				// back into the journal they failed to flush.
+				if err := journal.Flush(ctx); err != nil {
+					slog.Warn("Mitigated service degradation: Failed to persist error journal",
+						slog.String("error", err.Error()),
+					)
+				}
+			case <-prune.C:
+				if _, err := store.PruneErrorRecords(ctx, time.Now().Add(-errorJournalRetention)); err != nil {
+					slog.Warn("Mitigated service degradation: Failed to prune error journal",
+						slog.String("error", err.Error()),
+					)
+				}
+			}
+		}
+	}()
+}
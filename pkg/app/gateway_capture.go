@@ -0,0 +1,89 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/small-frappuccino/discordcore/pkg/discord/replay"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// gatewayCaptureDirEnv names a directory every bot instance records its
+// gateway events to, for replay in tests. Captures hold message content and
+// member data, so it is meant for test bots; unset disables capturing.
+const gatewayCaptureDirEnv = "DISCORDCORE_GATEWAY_CAPTURE_DIR"
+
+// gatewayCapture records the dispatch events of one instance to a file of
+// its own in the capture directory.
+type gatewayCapture struct {
+	path     string
+	file     *os.File
+	buf      *bufio.Writer
+	recorder *replay.Recorder
+	detach   func()
+}
+
+// startGatewayCapture starts capturing st when gatewayCaptureDirEnv is set.
+// It returns nil when capturing is off or the capture file cannot be
+// created; the bot runs either way.
+func startGatewayCapture(instanceID string, st *state.State, now time.Time) *gatewayCapture {
+	dir := strings.TrimSpace(files.EnvString(gatewayCaptureDirEnv, ""))
+	if dir == "" {
+		return nil
+	}
+	c, err := openGatewayCapture(dir, instanceID, now)
+	if err != nil {
+		slog.Warn("Mitigated service degradation: Could not start gateway capture",
+			slog.String("botInstanceID", instanceID),
+			slog.String("error", err.Error()),
+		)
+		return nil
+	}
+	c.detach = c.recorder.Attach(st)
+	slog.Info("Architectural state transition: Capturing gateway events",
+		slog.String("botInstanceID", instanceID),
+		slog.String("path", c.path),
+	)
+	return c
+}
+
+func openGatewayCapture(dir, instanceID string, now time.Time) (*gatewayCapture, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("openGatewayCapture: %w", err)
+	}
+	if instanceID == "" {
+		instanceID = "default"
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.jsonl", instanceID, now.UTC().Format("20060102T150405Z")))
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("openGatewayCapture: %w", err)
+	}
+	buf := bufio.NewWriter(file)
+	return &gatewayCapture{path: path, file: file, buf: buf, recorder: replay.NewRecorder(buf)}, nil
+}
+
+// close stops capturing and writes out what is still buffered.
+func (c *gatewayCapture) close() error {
+	if c == nil {
+		return nil
+	}
+	if c.detach != nil {
+		c.detach()
+	}
+	recErr := c.recorder.Close()
+	flushErr := c.buf.Flush()
+	closeErr := c.file.Close()
+	for _, err := range []error{recErr, flushErr, closeErr} {
+		if err != nil {
+			return fmt.Errorf("gatewayCapture.close: %s: %w", c.path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/discord/replay"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/messages"
+)
+
+func TestGatewayCapture(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), "captures")
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	c, err := openGatewayCapture(dir, "alice", now)
+	if err != nil {
+		t.Fatalf("openGatewayCapture: %v", err)
+	}
+	if c.path != filepath.Join(dir, "alice-20261016T120000Z.jsonl") {
+		t.Fatalf("unexpected capture path %s", c.path)
+	}
+	if _, err := openGatewayCapture(dir, "alice", now); err == nil {
+		t.Fatal("expected an existing capture not to be overwritten")
+	}
+
+	st := state.New("Bot token")
+	c.detach = c.recorder.Attach(st)
+	st.Session.Call(&gateway.ThreadDeleteEvent{ID: 10, GuildID: 1, ParentID: 5})
+	if err := c.close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	f, err := os.Open(c.path)
+	if err != nil {
+		t.Fatalf("open capture: %v", err)
+	}
+	defer f.Close()
+	events, err := replay.ReadEvents(f)
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].(*gateway.ThreadDeleteEvent).ID != 10 {
+		t.Fatalf("expected the thread delete back, got %v", events)
+	}
+	var nilCapture *gatewayCapture
+	if err := nilCapture.close(); err != nil {
+		t.Fatalf("closing a disabled capture: %v", err)
+	}
+}
+
+type channelThreadSink chan messages.ThreadIntent
+
+func (s channelThreadSink) OnThreadEvent(_ context.Context, intent messages.ThreadIntent) {
+	s <- intent
+}
+
+// TestThreadTrackerReplay plays a captured thread lifecycle through the
+// handlers the runtime attaches, as the gateway would deliver it.
+func TestThreadTrackerReplay(t *testing.T) {
+	t.Parallel()
+	f, err := os.Open(filepath.Join("testdata", "threads.jsonl"))
+	if err != nil {
+		t.Fatalf("open capture: %v", err)
+	}
+	defer f.Close()
+	events, err := replay.ReadEvents(f)
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{
+		{GuildID: "1296000000000000001", ThreadAutoJoin: true},
+	}})
+	sink := make(channelThreadSink, 1)
+	joiner := &fakeThreadJoiner{}
+	st := state.New("Bot token")
+	newThreadTracker("", sink, joiner, cfgMgr).attach(st)
+
+	want := []messages.ThreadIntent{
+		{GuildID: "1296000000000000001", ThreadID: "1296000000000000010", ParentID: "1296000000000000005", OwnerID: "1296000000000000007", Name: "help", Action: messages.ThreadCreated, AutoArchive: 1440},
+		{GuildID: "1296000000000000001", ThreadID: "1296000000000000010", ParentID: "1296000000000000005", OwnerID: "1296000000000000007", Name: "help", Action: messages.ThreadArchived, AutoArchive: 1440},
+		{GuildID: "1296000000000000001", ThreadID: "1296000000000000010", ParentID: "1296000000000000005", OwnerID: "1296000000000000007", Name: "help", Action: messages.ThreadDeleted},
+	}
+	p := replay.NewPlayer(st, events)
+	for i, w := range want {
+		ev, ok := p.Next()
+		if !ok {
+			t.Fatalf("capture ran out after %d events", i)
+		}
+		select {
+		case got := <-sink:
+			if got != w {
+				t.Fatalf("%s: expected %+v, got %+v", ev.EventType(), w, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s was not reported", ev.EventType())
+		}
+	}
+	if len(joiner.joined) != 1 || joiner.joined[0] != discord.ChannelID(1296000000000000010) {
+		t.Fatalf("expected the new thread to be joined once, got %v", joiner.joined)
+	}
+}
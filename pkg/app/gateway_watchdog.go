@@ -0,0 +1,209 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/diamondburned/arikawa/v3/utils/ws"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+const (
+	minGatewayWatchdogCheck = 30 * time.Second
+	gatewayReconnectTimeout = 30 * time.Second
+	// gatewayAlertInterval spaces out owner DMs about forced reconnects, so
+	// a flapping gateway is reported once rather than on every reconnect.
+	gatewayAlertInterval = time.Hour
+
+	// gatewayDispatchOp is the gateway opcode carrying real events, as opposed
+	// to heartbeat ACKs and other control frames that keep flowing on a
+	// connection whose dispatch stream has stalled.
+	gatewayDispatchOp ws.OpCode = 0
+)
+
+// gatewaySession is the subset of *state.State the watchdog drives.
+type gatewaySession interface {
+	GatewayIsAlive() bool
+	Open(ctx context.Context) error
+}
+
+// gatewayWatchdog reconnects a session whose gateway still reports connected
+// but has stopped delivering events. The persisted heartbeat only reveals such
+// a zombie connection at the next startup; the watchdog catches it live.
+type gatewayWatchdog struct {
+	instanceID  string
+	session     gatewaySession
+	idleTimeout time.Duration
+	now         func() time.Time
+	alert       func(content string) error
+
+	lastEventNs atomic.Int64
+	reconnects  atomic.Int64
+	// lastAlert is when the owner was last alerted; only check touches it.
+	lastAlert time.Time
+}
+
+// resolveGatewayIdleTimeout maps RuntimeConfig.GatewayWatchdogIdleMinutes to
+// a duration. The watchdog is opt-in: zero, returned for an unset or
+// negative setting, means it is disabled.
+func resolveGatewayIdleTimeout(rc files.RuntimeConfig) time.Duration {
+	if minutes := rc.GatewayWatchdogIdleMinutes; minutes > 0 {
+		return time.Duration(minutes) * time.Minute
+	}
+	return 0
+}
+
+func newGatewayWatchdog(instanceID string, session gatewaySession, idleTimeout time.Duration) *gatewayWatchdog {
+	w := &gatewayWatchdog{
+		instanceID:  instanceID,
+		session:     session,
+		idleTimeout: idleTimeout,
+		now:         time.Now,
+	}
+	w.markEvent()
+	return w
+}
+
+// attach records dispatch activity from st and routes owner alerts through it.
+func (w *gatewayWatchdog) attach(st *state.State) {
+	st.AddHandler(perf.GuardGatewayHandler("gateway_watchdog.event", func(ev gateway.Event) {
+		if ev != nil && ev.Op() == gatewayDispatchOp {
+			w.markEvent()
+		}
+	}))
+	w.alert = func(content string) error {
+		return alertApplicationOwner(st, content)
+	}
+}
+
+func (w *gatewayWatchdog) markEvent() {
+	w.lastEventNs.Store(w.now().UnixNano())
+}
+
+// run polls the session until ctx is done.
+func (w *gatewayWatchdog) run(ctx context.Context) {
+	if w == nil || w.idleTimeout <= 0 {
+		return
+	}
+	interval := max(w.idleTimeout/4, minGatewayWatchdogCheck)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check(ctx)
+		}
+	}
+}
+
+// check reconnects the session when it has been idle past idleTimeout while
+// still claiming to be alive. A dead gateway is left to arikawa's own
+// reconnect logic. It reports whether a reconnect was attempted.
+func (w *gatewayWatchdog) check(ctx context.Context) bool {
+	idle := w.now().Sub(time.Unix(0, w.lastEventNs.Load()))
+	if idle < w.idleTimeout || !w.session.GatewayIsAlive() {
+		return false
+	}
+
+	attempt := w.reconnects.Add(1)
+	slog.Warn("Mitigated service degradation: Gateway connected but idle, forcing session reconnect",
+		slog.String("botInstanceID", w.instanceID),
+		slog.Duration("idle", idle.Round(time.Second)),
+		slog.Int64("attempt", attempt),
+	)
+
+	openCtx, cancel := context.WithTimeout(ctx, gatewayReconnectTimeout)
+	err := w.session.Open(openCtx)
+	cancel()
+	// Restart the idle window either way so a failing reconnect is retried
+	// at the idle cadence rather than on every tick.
+	w.markEvent()
+
+	content := fmt.Sprintf("Gateway for bot instance `%s` received no events for %s while connected; the session was reconnected.", w.instanceID, idle.Round(time.Second))
+	if err != nil {
+		slog.Error("Blocking structural failure: Gateway watchdog reconnect failed",
+			slog.String("botInstanceID", w.instanceID),
+			slog.String("error", err.Error()),
+		)
+		content = fmt.Sprintf("Gateway for bot instance `%s` received no events for %s while connected, and reconnecting failed: %v", w.instanceID, idle.Round(time.Second), err)
+	} else {
+		slog.Info("Architectural state transition: Gateway session reconnected by watchdog",
+			slog.String("botInstanceID", w.instanceID),
+		)
+	}
+
+	if w.alert != nil && w.alertDue() {
+		if alertErr := w.alert(content); alertErr != nil {
+			slog.Warn("Mitigated service degradation: Failed to alert application owner",
+				slog.String("botInstanceID", w.instanceID),
+				slog.String("error", alertErr.Error()),
+			)
+		}
+	}
+	return true
+}
+
+// alertDue reports whether the owner may be alerted now, at most once per
+// gatewayAlertInterval, and records the alert when so.
+func (w *gatewayWatchdog) alertDue() bool {
+	now := w.now()
+	if !w.lastAlert.IsZero() && now.Sub(w.lastAlert) < gatewayAlertInterval {
+		return false
+	}
+	w.lastAlert = now
+	return true
+}
+
+// alertApplicationOwner sends content as a direct message to the owner of the
+// bot application (the team owner for team-held applications).
+func alertApplicationOwner(st *state.State, content string) error {
+	if err := messageApplicationOwner(st, api.SendMessageData{Content: content}); err != nil {
+		return fmt.Errorf("alertApplicationOwner: %w", err)
+	}
+	return nil
+}
+
+// messageApplicationOwner sends data as a direct message to the application
+// owner.
+func messageApplicationOwner(st *state.State, data api.SendMessageData) error {
+	app, err := st.CurrentApplication()
+	if err != nil {
+		return fmt.Errorf("messageApplicationOwner: %w", err)
+	}
+	ownerID := applicationOwnerID(app)
+	if !ownerID.IsValid() {
+		return fmt.Errorf("messageApplicationOwner: application has no owner")
+	}
+	dm, err := st.CreatePrivateChannel(ownerID)
+	if err != nil {
+		return fmt.Errorf("messageApplicationOwner: %w", err)
+	}
+	if _, err := st.SendMessageComplex(dm.ID, data); err != nil {
+		return fmt.Errorf("messageApplicationOwner: %w", err)
+	}
+	return nil
+}
+
+func applicationOwnerID(app *discord.Application) discord.UserID {
+	switch {
+	case app == nil:
+		return 0
+	case app.Team != nil:
+		return app.Team.OwnerID
+	case app.Owner != nil:
+		return app.Owner.ID
+	default:
+		return 0
+	}
+}
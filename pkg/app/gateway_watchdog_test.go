@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+type fakeGatewaySession struct {
+	alive   bool
+	openErr error
+	opens   int
+}
+
+func (f *fakeGatewaySession) GatewayIsAlive() bool { return f.alive }
+
+func (f *fakeGatewaySession) Open(context.Context) error {
+	f.opens++
+	return f.openErr
+}
+
+func TestGatewayWatchdogReconnectsIdleSession(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	session := &fakeGatewaySession{alive: true}
+	w := newGatewayWatchdog("main", session, time.Minute)
+	w.now = func() time.Time { return now }
+	w.markEvent()
+
+	var alerts []string
+	w.alert = func(content string) error {
+		alerts = append(alerts, content)
+		return nil
+	}
+
+	now = now.Add(30 * time.Second)
+	if w.check(context.Background()) || session.opens != 0 {
+		t.Fatal("expected no reconnect inside the idle window")
+	}
+
+	now = now.Add(time.Minute)
+	if !w.check(context.Background()) || session.opens != 1 || len(alerts) != 1 {
+		t.Fatalf("expected one reconnect and alert, got opens=%d alerts=%d", session.opens, len(alerts))
+	}
+	if w.check(context.Background()) {
+		t.Fatal("expected reconnect to restart the idle window")
+	}
+
+	session.openErr = errors.New("dial failed")
+	now = now.Add(2 * time.Minute)
+	if !w.check(context.Background()) || session.opens != 2 || len(alerts) != 1 {
+		t.Fatalf("expected a reconnect without a second alert within the hour, got opens=%d alerts=%d", session.opens, len(alerts))
+	}
+
+	now = now.Add(gatewayAlertInterval)
+	if !w.check(context.Background()) || len(alerts) != 2 {
+		t.Fatal("expected failed reconnect to alert the owner once the interval passed")
+	}
+}
+
+func TestGatewayWatchdogIgnoresDeadGateway(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	session := &fakeGatewaySession{alive: false}
+	w := newGatewayWatchdog("main", session, time.Minute)
+	w.now = func() time.Time { return now.Add(time.Hour) }
+
+	if w.check(context.Background()) || session.opens != 0 {
+		t.Fatal("expected dead gateway to be left to arikawa's reconnect loop")
+	}
+}
+
+func TestResolveGatewayIdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	cases := map[int]time.Duration{
+		0:  0,
+		-1: 0,
+		3:  3 * time.Minute,
+	}
+	for minutes, want := range cases {
+		got := resolveGatewayIdleTimeout(files.RuntimeConfig{GatewayWatchdogIdleMinutes: minutes})
+		if got != want {
+			t.Fatalf("minutes=%d: expected %s, got %s", minutes, want, got)
+		}
+	}
+}
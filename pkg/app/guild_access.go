@@ -0,0 +1,62 @@
+package app
+
+import (
+	"log/slog"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// guildAccessGuard leaves every guild refused by the configured guild access
+// policy. It sees each guild Discord announces, including those delivered
+// while the session is being opened, so it must be attached before Open.
+type guildAccessGuard struct {
+	instanceID string
+	config     func() *files.BotConfig
+	leave      func(discord.GuildID) error
+}
+
+func newGuildAccessGuard(instanceID string, configManager *files.ConfigManager, st *state.State) *guildAccessGuard {
+	return &guildAccessGuard{
+		instanceID: instanceID,
+		config:     configManager.Config,
+		leave:      st.LeaveGuild,
+	}
+}
+
+func (g *guildAccessGuard) attach(st *state.State) {
+	st.AddHandler(perf.GuardGatewayHandler("runtime.guild_access", g.handleGuildCreate))
+}
+
+// handleGuildCreate reads the policy on every event so edits apply without a
+// restart.
+func (g *guildAccessGuard) handleGuildCreate(e *gateway.GuildCreateEvent) {
+	if e == nil || e.Unavailable || !e.ID.IsValid() {
+		return
+	}
+	reason, refused := g.config().GuildAccessRefusal(e.ID.String())
+	if !refused {
+		return
+	}
+
+	if err := g.leave(e.ID); err != nil {
+		slog.Error("Blocking structural failure: Could not leave guild refused by the guild access policy",
+			slog.String("botInstanceID", g.instanceID),
+			slog.String("guildID", e.ID.String()),
+			slog.String("guildName", e.Name),
+			slog.String("reason", reason),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	slog.Warn("Architectural state transition: Left guild refused by the guild access policy",
+		slog.String("botInstanceID", g.instanceID),
+		slog.String("guildID", e.ID.String()),
+		slog.String("guildName", e.Name),
+		slog.String("ownerID", e.OwnerID.String()),
+		slog.String("reason", reason),
+	)
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+func TestGuildAccessGuardLeavesRefusedGuilds(t *testing.T) {
+	t.Parallel()
+
+	cfg := &files.BotConfig{GuildAccess: files.GuildAccessPolicy{Allow: []string{"1"}}}
+	var left []discord.GuildID
+	g := &guildAccessGuard{
+		instanceID: "main",
+		config:     func() *files.BotConfig { return cfg },
+		leave: func(id discord.GuildID) error {
+			left = append(left, id)
+			return nil
+		},
+	}
+
+	announce := func(id discord.GuildID, unavailable bool) {
+		ev := &gateway.GuildCreateEvent{Unavailable: unavailable}
+		ev.ID = id
+		g.handleGuildCreate(ev)
+	}
+	announce(1, false)
+	announce(2, true)
+	if len(left) != 0 {
+		t.Fatalf("expected allowed and unavailable guilds to be kept, left %v", left)
+	}
+	announce(2, false)
+	if len(left) != 1 || left[0] != 2 {
+		t.Fatalf("expected guild 2 to be left, got %v", left)
+	}
+
+	cfg = &files.BotConfig{}
+	announce(3, false)
+	if len(left) != 1 {
+		t.Fatalf("expected an empty policy to keep every guild, left %v", left)
+	}
+}
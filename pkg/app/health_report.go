@@ -0,0 +1,245 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+	"github.com/small-frappuccino/discordcore/pkg/system"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const (
+	// healthReportWeekday and healthReportHourUTC set when the weekly health
+	// digests go out.
+	healthReportWeekday = time.Monday
+	healthReportHourUTC = 8
+
+	// healthReportTopChannels is how many of the busiest channels a digest
+	// lists.
+	healthReportTopChannels = 5
+)
+
+// healthReportSource reads what a health digest summarises. *postgres.Store
+// satisfies it.
+type healthReportSource interface {
+	system.ActivityRepository
+	members.RetentionRepository
+	discordmod.TransparencySource
+}
+
+// messageSender posts messages with attachments. *state.State satisfies it.
+type messageSender interface {
+	SendMessageComplex(channelID discord.ChannelID, data api.SendMessageData) (*discord.Message, error)
+}
+
+// healthReporter posts last week's server health digest to every guild with
+// a health report channel, once a week.
+type healthReporter struct {
+	instanceID    string
+	src           healthReportSource
+	sender        messageSender
+	configManager *files.ConfigManager
+	metrics       members.SnapshotProvider
+	now           func() time.Time
+
+	// last is the metrics snapshot of the previous pass, so each digest
+	// covers the API calls made since the one before.
+	mu   sync.Mutex
+	last members.MetricsSnapshot
+}
+
+func newHealthReporter(instanceID string, src healthReportSource, sender messageSender, configManager *files.ConfigManager, metrics members.Metrics) *healthReporter {
+	r := &healthReporter{
+		instanceID:    instanceID,
+		src:           src,
+		sender:        sender,
+		configManager: configManager,
+		now:           time.Now,
+	}
+	if provider, ok := metrics.(members.SnapshotProvider); ok {
+		r.metrics = provider
+	}
+	return r
+}
+
+// nextHealthReport returns the first digest time strictly after now.
+func nextHealthReport(now time.Time) time.Time {
+	now = now.UTC()
+	days := (int(healthReportWeekday) - int(now.Weekday()) + 7) % 7
+	next := time.Date(now.Year(), now.Month(), now.Day()+days, healthReportHourUTC, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
+
+// performance returns the bot's API use since the previous pass.
+func (r *healthReporter) performance() system.BotPerformance {
+	if r.metrics == nil {
+		return system.BotPerformance{}
+	}
+	snap := r.metrics.Snapshot()
+	r.mu.Lock()
+	last := r.last
+	r.last = snap
+	r.mu.Unlock()
+	return system.BotPerformance{
+		APICalls: (snap.GuildMemberCallsTotal - last.GuildMemberCallsTotal) +
+			(snap.AuditLogCallsTotal - last.AuditLogCallsTotal),
+		CacheHits: (snap.StateMemberHitsTotal - last.StateMemberHitsTotal) +
+			(snap.RolesMemoryHitsTotal - last.RolesMemoryHitsTotal) +
+			(snap.RolesStoreHitsTotal - last.RolesStoreHitsTotal) +
+			(snap.RolesAuditHitsTotal - last.RolesAuditHitsTotal),
+	}
+}
+
+// pass posts the digest of the seven days before today to each health
+// report channel. A failing guild is logged and skipped.
+func (r *healthReporter) pass(ctx context.Context) {
+	cfg := r.configManager.Config()
+	if cfg == nil {
+		return
+	}
+	now := r.now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -7)
+	performance := r.performance()
+
+	for _, guild := range files.GuildsForBotInstanceFeature(cfg, r.instanceID, "moderation") {
+		if ctx.Err() != nil {
+			return
+		}
+		channelID, err := discord.ParseSnowflake(strings.TrimSpace(guild.HealthReport.ChannelID))
+		if err != nil || !channelID.IsValid() {
+			continue
+		}
+		report, err := r.build(ctx, guild.GuildID, from, to)
+		if err == nil {
+			report.Performance = performance
+			err = r.send(discord.ChannelID(channelID), report, guild.HealthReport.AttachFile)
+		}
+		if err != nil {
+			slog.Warn("Mitigated service degradation: Weekly health report not posted",
+				slog.String("botInstanceID", r.instanceID),
+				slog.String("guildID", guild.GuildID),
+				slog.String("channelID", guild.HealthReport.ChannelID),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		slog.Info("Architectural state transition: Weekly health report posted",
+			slog.String("botInstanceID", r.instanceID),
+			slog.String("guildID", guild.GuildID),
+			slog.Time("from", from),
+			slog.Int64("messages", report.Week.Messages),
+		)
+	}
+}
+
+// build reads the activity of the report week and the one before it, and
+// the moderation recorded in the report week.
+func (r *healthReporter) build(ctx context.Context, guildID string, from, to time.Time) (system.HealthReport, error) {
+	activity, err := r.src.GuildActivity(ctx, guildID, from, to, healthReportTopChannels)
+	if err != nil {
+		return system.HealthReport{}, fmt.Errorf("healthReporter.build: %w", err)
+	}
+	// Only the totals of the week before are compared, and a complete week
+	// is read from its rollup.
+	previous, err := r.src.GuildActivityTotals(ctx, guildID, from.AddDate(0, 0, -7), from)
+	if err != nil {
+		return system.HealthReport{}, fmt.Errorf("healthReporter.build: %w", err)
+	}
+
+	retention, err := r.src.GuildMemberRetention(ctx, guildID, from, to)
+	if err != nil {
+		return system.HealthReport{}, fmt.Errorf("healthReporter.build: %w", err)
+	}
+	counts, err := r.src.ModerationActionCounts(ctx, guildID, from, to)
+	if err != nil {
+		return system.HealthReport{}, fmt.Errorf("healthReporter.build: %w", err)
+	}
+	report := system.NewHealthReport(guildID, from, to, activity)
+	report.PreviousWeek = previous
+	report.Members = retention.Members
+	report.NewMembers = retention.Joined
+	report.NewMembersRetained = retention.Retained
+	report.ModerationActions = counts.Warnings
+	for _, n := range counts.ByAction {
+		report.ModerationActions += n
+	}
+	return report, nil
+}
+
+func (r *healthReporter) send(channelID discord.ChannelID, report system.HealthReport, attach bool) error {
+	data := api.SendMessageData{Embeds: []discord.Embed{healthReportEmbed(report)}}
+	if attach {
+		body, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("healthReporter.send: %w", err)
+		}
+		data.Files = []sendpart.File{{
+			Name:   fmt.Sprintf("health-report-%s-%s.json", report.GuildID, report.From.Format("20060102")),
+			Reader: bytes.NewReader(body),
+		}}
+	}
+	if _, err := r.sender.SendMessageComplex(channelID, data); err != nil {
+		return fmt.Errorf("healthReporter.send: %w", err)
+	}
+	return nil
+}
+
+// healthReportEmbed renders report for the staff channel.
+func healthReportEmbed(report system.HealthReport) discord.Embed {
+	last := report.To.Add(-time.Nanosecond)
+	embed := discord.Embed{
+		Title: "Weekly server health report",
+		Description: fmt.Sprintf("Activity from %s to %s (UTC).",
+			report.From.Format("January 2"), last.Format("January 2, 2006")),
+		Color:     discord.Color(theme.Info()),
+		Timestamp: discord.NewTimestamp(report.To),
+	}
+
+	growth := fmt.Sprintf("Members: **%d**\nJoins: **%d**\nLeaves: **%d**\nNet: **%+d**",
+		report.Members, report.Week.Joins, report.Week.Leaves, report.Week.NetGrowth())
+	if rate, ok := report.Retention(); ok {
+		growth += fmt.Sprintf("\nNew members still here: **%.0f%%**", rate*100)
+	}
+	activity := fmt.Sprintf("Messages: **%d**", report.Week.Messages)
+	if change, ok := report.MessageTrend(); ok {
+		activity += fmt.Sprintf("\n%+.0f%% on the previous week", change*100)
+	}
+	embed.Fields = append(embed.Fields,
+		discord.EmbedField{Name: "Member growth", Value: growth, Inline: true},
+		discord.EmbedField{Name: "Activity", Value: activity, Inline: true},
+		discord.EmbedField{Name: "Moderation", Value: fmt.Sprintf("Actions: **%d**", report.ModerationActions), Inline: true},
+	)
+
+	if len(report.TopChannels) > 0 {
+		var b strings.Builder
+		for i, channel := range report.TopChannels {
+			fmt.Fprintf(&b, "%d. <#%s>: %d\n", i+1, channel.ChannelID, channel.Messages)
+		}
+		embed.Fields = append(embed.Fields, discord.EmbedField{Name: "Top channels", Value: b.String()})
+	}
+
+	perf := report.Performance
+	embed.Fields = append(embed.Fields, discord.EmbedField{
+		Name: "Bot performance",
+		Value: fmt.Sprintf("API calls: **%d**\nCache hit rate: **%.1f%%**",
+			perf.APICalls, perf.CacheHitRate()*100),
+	})
+	return embed
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/system"
+)
+
+type fakeHealthReportSource struct{}
+
+func (fakeHealthReportSource) GuildActivity(_ context.Context, guildID string, from, to time.Time, topChannels int) (system.GuildActivity, error) {
+	if guildID == "3" {
+		return system.GuildActivity{}, errors.New("db down")
+	}
+	activity := system.GuildActivity{Days: []system.DailyActivity{
+		{Day: to.AddDate(0, 0, -3), Messages: 150, Joins: 4, Leaves: 1},
+	}}
+	if topChannels > 0 {
+		activity.TopChannels = []system.ChannelActivity{{ChannelID: "77", Messages: 150}}
+	}
+	return activity, nil
+}
+
+func (fakeHealthReportSource) GuildActivityTotals(context.Context, string, time.Time, time.Time) (system.ActivityTotals, error) {
+	return system.ActivityTotals{Messages: 100, Joins: 1}, nil
+}
+
+func (fakeHealthReportSource) GuildMemberRetention(context.Context, string, time.Time, time.Time) (members.Retention, error) {
+	return members.Retention{Members: 420, Joined: 4, Retained: 3}, nil
+}
+
+func (fakeHealthReportSource) ModerationActionCounts(context.Context, string, time.Time, time.Time) (coremod.ActionCounts, error) {
+	return coremod.ActionCounts{ByAction: map[string]int{"ban": 1, "timeout": 2}, Warnings: 3}, nil
+}
+
+type fakeMessageSender struct {
+	sent map[discord.ChannelID][]api.SendMessageData
+}
+
+func (f *fakeMessageSender) SendMessageComplex(channelID discord.ChannelID, data api.SendMessageData) (*discord.Message, error) {
+	f.sent[channelID] = append(f.sent[channelID], data)
+	return &discord.Message{ChannelID: channelID}, nil
+}
+
+func TestNextHealthReport(t *testing.T) {
+	t.Parallel()
+	// 2026-10-14 is a Wednesday.
+	wednesday := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	if got := nextHealthReport(wednesday); !got.Equal(time.Date(2026, 10, 19, healthReportHourUTC, 0, 0, 0, time.UTC)) {
+		t.Fatalf("nextHealthReport(%v) = %v", wednesday, got)
+	}
+	monday := time.Date(2026, 10, 19, healthReportHourUTC, 0, 0, 0, time.UTC)
+	if got := nextHealthReport(monday); !got.Equal(monday.AddDate(0, 0, 7)) {
+		t.Fatalf("nextHealthReport(%v) = %v", monday, got)
+	}
+}
+
+func TestHealthReporterPass(t *testing.T) {
+	t.Parallel()
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{
+		{GuildID: "1", HealthReport: files.HealthReportConfig{ChannelID: "10", AttachFile: true}},
+		{GuildID: "2"},
+		{GuildID: "3", HealthReport: files.HealthReportConfig{ChannelID: "30"}},
+	}})
+
+	metrics := members.NewInMemoryMetrics()
+	sender := &fakeMessageSender{sent: map[discord.ChannelID][]api.SendMessageData{}}
+	reporter := newHealthReporter("", fakeHealthReportSource{}, sender, cfgMgr, metrics)
+	reporter.now = func() time.Time { return time.Date(2026, 10, 19, healthReportHourUTC, 0, 0, 0, time.UTC) }
+
+	metrics.RecordGuildMemberCall()
+	metrics.RecordStateMemberCacheHit()
+	metrics.RecordStateMemberCacheHit()
+	metrics.RecordRolesCacheMemoryHit()
+	reporter.pass(context.Background())
+
+	if len(sender.sent) != 1 || len(sender.sent[10]) != 1 {
+		t.Fatalf("expected one digest in channel 10, got %+v", sender.sent)
+	}
+	data := sender.sent[10][0]
+	if len(data.Embeds) != 1 || len(data.Files) != 1 {
+		t.Fatalf("expected an embed and a file, got %d embeds and %d files", len(data.Embeds), len(data.Files))
+	}
+	if !strings.HasPrefix(data.Files[0].Name, "health-report-1-20261012") {
+		t.Fatalf("unexpected file name %q", data.Files[0].Name)
+	}
+	var fields []string
+	for _, field := range data.Embeds[0].Fields {
+		fields = append(fields, field.Name+"="+field.Value)
+	}
+	joined := strings.Join(fields, "\n")
+	for _, want := range []string{"Messages: **150**", "+50% on the previous week", "Net: **+3**", "Members: **420**", "New members still here: **75%**", "Actions: **6**", "<#77>", "API calls: **1**", "Cache hit rate: **75.0%**"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("digest is missing %q:\n%s", want, joined)
+		}
+	}
+
+	// The next digest only counts calls made since this one.
+	reporter.pass(context.Background())
+	second := sender.sent[10][1].Embeds[0].Fields
+	if perf := second[len(second)-1].Value; !strings.Contains(perf, "API calls: **0**") {
+		t.Fatalf("expected no API calls since the last digest, got %q", perf)
+	}
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil/httpdriver"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
+)
+
+// trackInteractionAck measures how close the interaction comes to Discord's
+// acknowledgment deadline. client must be the one the handler responds
+// with: the first callback it sends for event is timed from the moment
+// Discord created the interaction, so gateway delivery counts as well as the
+// handler's own work. Later responses are follow-ups and are not timed.
+func trackInteractionAck(client *api.Client, event *discord.InteractionEvent, route string, now func() time.Time) {
+	created := event.ID.Time()
+	callback := "/interactions/" + event.ID.String() + "/"
+	var once sync.Once
+	client.Client.OnResponse = append(client.Client.OnResponse, func(req httpdriver.Request, _ httpdriver.Response) error {
+		path := req.GetPath()
+		if strings.Contains(path, callback) && strings.HasSuffix(path, "/callback") {
+			once.Do(func() { perf.ObserveInteractionAck(route, now().Sub(created)) })
+		}
+		return nil
+	})
+}
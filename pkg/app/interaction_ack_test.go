@@ -0,0 +1,44 @@
+package app
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil/httpdriver"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
+)
+
+func TestTrackInteractionAck(t *testing.T) {
+	t.Parallel()
+	const route = "test.track_ack"
+	created := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	event := &discord.InteractionEvent{ID: discord.InteractionID(discord.NewSnowflake(created)), Token: "token"}
+
+	client := api.NewClient("Bot token")
+	trackInteractionAck(client, event, route, func() time.Time { return created.Add(2500 * time.Millisecond) })
+	hook := client.Client.OnResponse[len(client.Client.OnResponse)-1]
+	respond := func(path string) {
+		req := httptest.NewRequest("POST", "https://discord.com/api/v10"+path, nil)
+		if err := hook((*httpdriver.DefaultRequest)(req), nil); err != nil {
+			t.Fatalf("hook: %v", err)
+		}
+	}
+
+	// The metrics are process-wide; compare against what earlier runs left.
+	before := perf.SnapshotInteractionMetrics()[route]
+	respond("/interactions/1/other/callback")
+	respond("/webhooks/2/token/messages/@original")
+	if got := perf.SnapshotInteractionMetrics()[route]; got.Latency.Count != before.Latency.Count {
+		t.Fatal("expected only this interaction's callback to be timed")
+	}
+
+	respond("/interactions/" + event.ID.String() + "/token/callback")
+	respond("/interactions/" + event.ID.String() + "/token/callback")
+	got := perf.SnapshotInteractionMetrics()[route]
+	if got.Latency.Count != before.Latency.Count+1 || got.Latency.MaxSeconds != 2.5 || got.Slow != before.Slow+1 {
+		t.Fatalf("expected one slow acknowledgment of 2.5s, got %+v", got)
+	}
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/leader"
+)
+
+const (
+	leaderElectionEnv = "DISCORDCORE_LEADER_ELECTION"
+	instanceIDEnv     = "DISCORDCORE_INSTANCE_ID"
+)
+
+// leaderHolderID identifies this process in the lease table. Operators can pin
+// it with DISCORDCORE_INSTANCE_ID; otherwise host name and PID keep two
+// instances on one machine apart.
+func leaderHolderID() string {
+	if id := files.EnvString(instanceIDEnv, ""); id != "" {
+		return id
+	}
+	host, err := os.Hostname()
+	if err != nil || strings.TrimSpace(host) == "" {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// acquireLeadership blocks a standby until it holds the application lease,
+// then keeps renewing it in the background. Losing the lease stops the whole
+// process so a supervisor restarts it as a follower; running on would let two
+// instances register commands and post logs at once.
+func (a *App) acquireLeadership() error {
+	if !a.opts.LeaderElection || a.opts.ReadOnly || a.store == nil {
+		return nil
+	}
+
+	elector := leader.NewElector(a.store, a.appName, leaderHolderID(), leader.WithLogger(a.logger))
+	a.logger.Info("Architectural state transition: Waiting for the leader lease",
+		slog.String("lease", a.appName),
+		slog.String("holder", elector.Holder()),
+	)
+
+	// Signals are only wired up by RunAndListen, so a follower parked here
+	// needs its own to shut down cleanly.
+	waitCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := elector.Await(waitCtx); err != nil {
+		return fmt.Errorf("App.acquireLeadership: %w", err)
+	}
+
+	a.elector = elector
+	a.serviceManager.RunBackground(func(ctx context.Context) {
+		if err := elector.Hold(ctx); err != nil {
+			a.logger.Error("Blocking structural failure: Leader lease lost; stopping so a follower can take over",
+				slog.String("lease", a.appName),
+				slog.String("error", err.Error()),
+			)
+			a.serviceManager.Fatal(err)
+		}
+	})
+	return nil
+}
+
+// releaseLeadership hands the lease over once every service has stopped.
+func (a *App) releaseLeadership() {
+	if a.elector == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.elector.Release(ctx); err != nil {
+		a.logger.Warn("Mitigated service degradation: Leader lease not released; followers wait for expiry",
+			slog.String("error", err.Error()),
+		)
+	}
+}
@@ -0,0 +1,20 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLeaderHolderID(t *testing.T) {
+	t.Setenv(instanceIDEnv, "standby-1")
+	if got := leaderHolderID(); got != "standby-1" {
+		t.Fatalf("leaderHolderID() = %q, want the pinned instance ID", got)
+	}
+
+	t.Setenv(instanceIDEnv, "")
+	if got := leaderHolderID(); !strings.HasSuffix(got, fmt.Sprintf("-%d", os.Getpid())) {
+		t.Fatalf("leaderHolderID() = %q, want a host-PID identity", got)
+	}
+}
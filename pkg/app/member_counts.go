@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+)
+
+// memberCountRecorder snapshots the member count of the guilds this instance
+// logs at the top of every hour. The daily count is rewritten each hour, so
+// it ends up holding the last snapshot of the day; hourly counts are kept
+// only for guilds that ask for them. Counts come from the stored membership
+// the member presence reconciler keeps accurate.
+type memberCountRecorder struct {
+	instanceID    string
+	store         members.MemberCountRepository
+	configManager *files.ConfigManager
+	now           func() time.Time
+}
+
+func newMemberCountRecorder(instanceID string, store members.MemberCountRepository, configManager *files.ConfigManager) *memberCountRecorder {
+	return &memberCountRecorder{
+		instanceID:    instanceID,
+		store:         store,
+		configManager: configManager,
+		now:           time.Now,
+	}
+}
+
+// nextMemberCount returns the top of the hour after now.
+func nextMemberCount(now time.Time) time.Time {
+	return now.Truncate(time.Hour).Add(time.Hour)
+}
+
+func (r *memberCountRecorder) pass(ctx context.Context) {
+	cfg := r.configManager.Config()
+	if cfg == nil {
+		return
+	}
+	now := r.now()
+	for _, guild := range files.GuildsForBotInstanceFeature(cfg, r.instanceID, "logging") {
+		if ctx.Err() != nil {
+			return
+		}
+		granularities := []string{members.CountDaily}
+		if guild.Stats.HourlyMemberCounts {
+			granularities = append(granularities, members.CountHourly)
+		}
+		for _, granularity := range granularities {
+			if _, err := r.store.RecordGuildMemberCount(ctx, guild.GuildID, granularity, now); err != nil {
+				slog.Warn("Mitigated service degradation: Member count not recorded",
+					slog.String("botInstanceID", r.instanceID),
+					slog.String("guildID", guild.GuildID),
+					slog.String("granularity", granularity),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}
+}
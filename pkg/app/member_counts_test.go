@@ -0,0 +1,45 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+)
+
+type fakeMemberCountStore struct {
+	recorded map[string][]string
+}
+
+func (f *fakeMemberCountStore) RecordGuildMemberCount(_ context.Context, guildID, granularity string, at time.Time) (members.MemberCount, error) {
+	f.recorded[guildID] = append(f.recorded[guildID], granularity)
+	return members.MemberCount{GuildID: guildID, Granularity: granularity, Bucket: members.CountBucket(granularity, at)}, nil
+}
+
+func (f *fakeMemberCountStore) ListGuildMemberCounts(context.Context, string, string, time.Time, time.Time) ([]members.MemberCount, error) {
+	return nil, nil
+}
+
+func TestMemberCountRecorderPass(t *testing.T) {
+	t.Parallel()
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{
+		{GuildID: "1"},
+		{GuildID: "2", Stats: files.StatsConfig{HourlyMemberCounts: true}},
+	}})
+
+	store := &fakeMemberCountStore{recorded: map[string][]string{}}
+	recorder := newMemberCountRecorder("", store, cfgMgr)
+	recorder.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+	recorder.pass(context.Background())
+
+	if got := store.recorded["1"]; len(got) != 1 || got[0] != members.CountDaily {
+		t.Fatalf("guild 1 recorded %v, want the daily count only", got)
+	}
+	if got := store.recorded["2"]; len(got) != 2 || got[1] != members.CountHourly {
+		t.Fatalf("guild 2 recorded %v, want daily and hourly counts", got)
+	}
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"iter"
+	"log/slog"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+)
+
+// memberPresenceInterval spaces the full membership reconciliations. Join
+// and leave events keep the stored membership current in between; the pass
+// catches the events the gateway dropped.
+const memberPresenceInterval = 6 * time.Hour
+
+// memberPresenceReconciler periodically brings the stored membership of the
+// guilds this instance logs in line with Discord, so member counts and
+// retention read from the store stay accurate whatever the state cache holds.
+type memberPresenceReconciler struct {
+	instanceID    string
+	store         members.RoleSnapshotStore
+	live          func(guildID string) iter.Seq2[members.LiveMember, error]
+	configManager *files.ConfigManager
+	interval      time.Duration
+	now           func() time.Time
+}
+
+func newMemberPresenceReconciler(instanceID string, store members.RoleSnapshotStore, live func(guildID string) iter.Seq2[members.LiveMember, error], configManager *files.ConfigManager) *memberPresenceReconciler {
+	return &memberPresenceReconciler{
+		instanceID:    instanceID,
+		store:         store,
+		live:          live,
+		configManager: configManager,
+		interval:      memberPresenceInterval,
+		now:           time.Now,
+	}
+}
+
+// run performs a pass every interval until ctx is done. Startup is covered
+// by the downtime reconciliation.
+func (r *memberPresenceReconciler) run(ctx context.Context) {
+	if r == nil || r.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.pass(ctx)
+		}
+	}
+}
+
+func (r *memberPresenceReconciler) pass(ctx context.Context) {
+	cfg := r.configManager.Config()
+	if cfg == nil {
+		return
+	}
+	for _, guild := range files.GuildsForBotInstanceFeature(cfg, r.instanceID, "logging") {
+		if ctx.Err() != nil {
+			return
+		}
+		result, err := members.ReconcileRoleSnapshots(ctx, r.store, guild.GuildID, r.live(guild.GuildID), r.now().UTC())
+		if err != nil {
+			slog.Warn("Mitigated service degradation: Member presence reconciliation failed",
+				slog.String("botInstanceID", r.instanceID),
+				slog.String("guildID", guild.GuildID),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		if result.MarkedLeft == 0 && result.Added == 0 && result.RolesUpdated == 0 {
+			continue
+		}
+		slog.Info("Architectural state transition: Member presence reconciled",
+			slog.String("botInstanceID", r.instanceID),
+			slog.String("guildID", guild.GuildID),
+			slog.Int("members_checked", result.Checked),
+			slog.Int("members_added", result.Added),
+			slog.Int("members_marked_left", result.MarkedLeft),
+			slog.Int("roles_updated", result.RolesUpdated),
+		)
+	}
+}
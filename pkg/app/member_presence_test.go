@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+)
+
+type fakePresenceStore struct {
+	active []members.CurrentState
+	added  []string
+	left   []string
+}
+
+func (f *fakePresenceStore) GetActiveGuildMemberStatesContext(context.Context, string) iter.Seq2[members.CurrentState, error] {
+	return func(yield func(members.CurrentState, error) bool) {
+		for _, state := range f.active {
+			if !yield(state, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (f *fakePresenceStore) UpsertMemberRoles(string, string, []string, time.Time) error { return nil }
+
+func (f *fakePresenceStore) UpsertMemberPresenceContext(_ context.Context, input members.PresenceInput) error {
+	f.added = append(f.added, input.UserID)
+	return nil
+}
+
+func (f *fakePresenceStore) MarkMemberLeftContext(_ context.Context, _, userID string, _ time.Time) error {
+	f.left = append(f.left, userID)
+	return nil
+}
+
+func TestMemberPresenceReconcilerPass(t *testing.T) {
+	t.Parallel()
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{{GuildID: "1"}}})
+
+	store := &fakePresenceStore{active: []members.CurrentState{{UserID: "stayed"}, {UserID: "gone"}}}
+	live := func(string) iter.Seq2[members.LiveMember, error] {
+		return func(yield func(members.LiveMember, error) bool) {
+			for _, m := range []members.LiveMember{{UserID: "stayed"}, {UserID: "joined"}} {
+				if !yield(m, nil) {
+					return
+				}
+			}
+		}
+	}
+	reconciler := newMemberPresenceReconciler("", store, live, cfgMgr)
+	reconciler.pass(context.Background())
+
+	if len(store.left) != 1 || store.left[0] != "gone" {
+		t.Fatalf("expected the departed member to be marked left, got %v", store.left)
+	}
+	if len(store.added) != 1 || store.added[0] != "joined" {
+		t.Fatalf("expected the unrecorded member to be added, got %v", store.added)
+	}
+}
@@ -3,8 +3,14 @@ package app
 import (
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
 	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	applicationlogging "github.com/small-frappuccino/discordcore/pkg/logging"
 )
 
 // Middleware defines a chainable interceptor for CommandHandlers.
@@ -45,3 +51,126 @@ func PermissionsMiddleware(feature string) Middleware {
 		}
 	}
 }
+
+// disabledCommandRefusal is shown when a guild turned the invoked command off.
+const disabledCommandRefusal = "This command is disabled on this server."
+
+// DisabledCommandsMiddleware refuses the slash commands a guild turned off
+// with /config commands, looking the guild up through guildConfig.
+// Autocomplete for them gets no suggestions; buttons and modals pass, as
+// they belong to messages posted while the command was still enabled.
+func DisabledCommandsMiddleware(guildConfig func(guildID string) *files.GuildConfig) Middleware {
+	return func(next cmd.CommandHandler) cmd.CommandHandler {
+		return func(ctx *cmd.Context) error {
+			if !ctx.GuildID.IsValid() || !commandDisabled(guildConfig(ctx.GuildID.String()), ctx.Event.Data) {
+				return next(ctx)
+			}
+			if _, ok := ctx.Event.Data.(*discord.AutocompleteInteraction); ok {
+				return nil
+			}
+			return ctx.RespondMessage(disabledCommandRefusal)
+		}
+	}
+}
+
+func commandDisabled(guild *files.GuildConfig, data discord.InteractionData) bool {
+	if guild == nil {
+		return false
+	}
+	switch data := data.(type) {
+	case *discord.CommandInteraction:
+		return guild.CommandDisabled(data.Name)
+	case *discord.AutocompleteInteraction:
+		return guild.CommandDisabled(data.Name)
+	default:
+		return false
+	}
+}
+
+// CommandAuditSink receives privileged command executions once their handler
+// has returned.
+type CommandAuditSink interface {
+	RecordCommand(ctx *cmd.Context, started time.Time, err error)
+}
+
+// AuditMiddleware reports slash command executions on privileged paths to
+// sink together with their outcome. Other interactions pass through untouched.
+func AuditMiddleware(sink CommandAuditSink) Middleware {
+	return func(next cmd.CommandHandler) cmd.CommandHandler {
+		return func(ctx *cmd.Context) error {
+			data, ok := ctx.Event.Data.(*discord.CommandInteraction)
+			if !ok || !commands.IsPrivilegedCommandPath(data.Name) {
+				return next(ctx)
+			}
+			started := time.Now()
+			err := next(ctx)
+			sink.RecordCommand(ctx, started, err)
+			return err
+		}
+	}
+}
+
+// readOnlyRefusal is shown when a read-only instance turns down an interaction.
+const readOnlyRefusal = "This instance is read-only. Try again once the primary instance is back."
+
+// ReadOnlyMiddleware refuses every interaction that could change state on an
+// instance running with RunOptions.ReadOnly. Informational slash commands and
+// autocomplete still run; buttons, select menus and modals never do.
+func ReadOnlyMiddleware() Middleware {
+	return func(next cmd.CommandHandler) cmd.CommandHandler {
+		return func(ctx *cmd.Context) error {
+			if !readOnlyAllows(ctx.Event.Data) {
+				return ctx.RespondMessage(readOnlyRefusal)
+			}
+			return next(ctx)
+		}
+	}
+}
+
+func readOnlyAllows(data discord.InteractionData) bool {
+	switch data := data.(type) {
+	case *discord.AutocompleteInteraction:
+		return true
+	case *discord.CommandInteraction:
+		path, _ := flattenCommandOptions(data.Name, data.Options)
+		return commands.IsReadOnlyCommandPath(path)
+	default:
+		return false
+	}
+}
+
+// ModerationLockouts reports moderators the destructive action limit locked
+// out. *discordmod.Service satisfies it.
+type ModerationLockouts interface {
+	LockedOut(guildID discord.GuildID, moderatorID discord.UserID) (time.Time, bool)
+}
+
+// moderationLockoutRefusal is shown to a locked-out moderator, with the
+// time the lockout ends.
+const moderationLockoutRefusal = "You have exceeded this server's limit on bans and kicks. Your moderation actions are paused until %s."
+
+// ModerationLockoutMiddleware refuses a moderator locked out by lockouts
+// every moderation interaction that could act, including the commands that
+// never count toward the limit. Informational commands, autocomplete and the
+// button cancelling a running mass action still pass.
+func ModerationLockoutMiddleware(feature string, lockouts ModerationLockouts) Middleware {
+	return func(next cmd.CommandHandler) cmd.CommandHandler {
+		return func(ctx *cmd.Context) error {
+			if feature != "moderation" || !ctx.GuildID.IsValid() || lockoutExempt(ctx.Event.Data) {
+				return next(ctx)
+			}
+			until, locked := lockouts.LockedOut(ctx.GuildID, ctx.UserID)
+			if !locked {
+				return next(ctx)
+			}
+			return ctx.RespondMessage(fmt.Sprintf(moderationLockoutRefusal, applicationlogging.DiscordTimestamp(until, applicationlogging.TimestampRelative)))
+		}
+	}
+}
+
+func lockoutExempt(data discord.InteractionData) bool {
+	if button, ok := data.(*discord.ButtonInteraction); ok {
+		return strings.HasPrefix(string(button.CustomID), "massaction:cancel|")
+	}
+	return readOnlyAllows(data)
+}
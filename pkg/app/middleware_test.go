@@ -0,0 +1,81 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+func TestReadOnlyAllows(t *testing.T) {
+	t.Parallel()
+
+	sub := func(name string) []discord.CommandInteractionOption {
+		return []discord.CommandInteractionOption{{Type: discord.SubcommandOptionType, Name: name}}
+	}
+	cases := []struct {
+		name string
+		data discord.InteractionData
+		want bool
+	}{
+		{"informational subcommand", &discord.CommandInteraction{Name: "case", Options: sub("view")}, true},
+		{"informational root", &discord.CommandInteraction{Name: "clean-export"}, true},
+		{"informational leaf of a mixed root", &discord.CommandInteraction{Name: "warnings", Options: sub("list")}, true},
+		{"mutating leaf of a mixed root", &discord.CommandInteraction{Name: "warnings", Options: sub("clear")}, false},
+		{"mutating subcommand", &discord.CommandInteraction{Name: "case", Options: sub("delete")}, false},
+		{"mutating root", &discord.CommandInteraction{Name: "ban"}, false},
+		{"autocomplete", &discord.AutocompleteInteraction{Name: "partner"}, true},
+		{"modal", &discord.ModalInteraction{CustomID: "moderation:reason|ban"}, false},
+		{"button", &discord.ButtonInteraction{CustomID: "rolepanel|1"}, false},
+	}
+	for _, tc := range cases {
+		if got := readOnlyAllows(tc.data); got != tc.want {
+			t.Errorf("%s: readOnlyAllows = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestCommandDisabled(t *testing.T) {
+	t.Parallel()
+
+	guild := &files.GuildConfig{DisabledCommands: []string{"stats"}}
+	cases := []struct {
+		name  string
+		guild *files.GuildConfig
+		data  discord.InteractionData
+		want  bool
+	}{
+		{"disabled command", guild, &discord.CommandInteraction{Name: "stats"}, true},
+		{"disabled autocomplete", guild, &discord.AutocompleteInteraction{Name: "stats"}, true},
+		{"enabled command", guild, &discord.CommandInteraction{Name: "qotd"}, false},
+		{"button", guild, &discord.ButtonInteraction{CustomID: "stats|1"}, false},
+		{"unknown guild", nil, &discord.CommandInteraction{Name: "stats"}, false},
+	}
+	for _, tc := range cases {
+		if got := commandDisabled(tc.guild, tc.data); got != tc.want {
+			t.Errorf("%s: commandDisabled = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestLockoutExempt(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		data discord.InteractionData
+		want bool
+	}{
+		{"action", &discord.CommandInteraction{Name: "ban"}, false},
+		{"informational", &discord.CommandInteraction{Name: "warnings", Options: []discord.CommandInteractionOption{{Type: discord.SubcommandOptionType, Name: "list"}}}, true},
+		{"clearing warnings", &discord.CommandInteraction{Name: "warnings", Options: []discord.CommandInteractionOption{{Type: discord.SubcommandOptionType, Name: "clear"}}}, false},
+		{"autocomplete", &discord.AutocompleteInteraction{Name: "ban"}, true},
+		{"reason modal", &discord.ModalInteraction{CustomID: "moderation:reason|ban"}, false},
+		{"mass action cancel", &discord.ButtonInteraction{CustomID: "massaction:cancel|1"}, true},
+	}
+	for _, tc := range cases {
+		if got := lockoutExempt(tc.data); got != tc.want {
+			t.Errorf("%s: lockoutExempt = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
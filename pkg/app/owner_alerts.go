@@ -0,0 +1,190 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/small-frappuccino/discordcore/pkg/discord/embeds"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/observability"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const (
+	// ownerAlertInterval is both the aggregation window and the rate limit:
+	// the owner receives at most one summary per interval.
+	ownerAlertInterval  = 5 * time.Minute
+	ownerAlertMaxFields = 20
+)
+
+// ownerAlertThresholds is the minimum number of reports per source within one
+// window before a kind is worth alerting on. A single failed send is routine;
+// repeated ones point at a broken channel or lost permissions. One slow
+// acknowledgment can be a network blip; a route that is slow again and again
+// needs to defer its response.
+var ownerAlertThresholds = map[observability.OperationalAlertKind]int64{
+	observability.AlertSendFailure:     3,
+	observability.AlertSlowInteraction: 5,
+}
+
+// ownerAlerter periodically drains the process-wide operational alerts and
+// posts a summary embed to the configured owner alert channel, or to the
+// application owner's DMs when none is configured.
+type ownerAlerter struct {
+	configManager *files.ConfigManager
+	resolver      *botRuntimeResolver
+	interval      time.Duration
+	deliver       func(embed discord.Embed) error
+}
+
+func newOwnerAlerter(configManager *files.ConfigManager, resolver *botRuntimeResolver) *ownerAlerter {
+	a := &ownerAlerter{
+		configManager: configManager,
+		resolver:      resolver,
+		interval:      ownerAlertInterval,
+	}
+	a.deliver = a.deliverToOwner
+	return a
+}
+
+func (a *ownerAlerter) run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.flush()
+		}
+	}
+}
+
+// flush sends one summary for the alerts gathered since the previous flush.
+// It reports whether a summary was sent.
+func (a *ownerAlerter) flush() bool {
+	summaries, dropped := observability.DrainOperationalAlerts()
+	summaries = filterOwnerAlerts(summaries)
+	if len(summaries) == 0 && dropped == 0 {
+		return false
+	}
+
+	if err := a.deliver(buildOwnerAlertEmbed(summaries, dropped, a.interval)); err != nil {
+		slog.Warn("Mitigated service degradation: Failed to deliver owner alert summary",
+			slog.Int("alerts", len(summaries)),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+	return true
+}
+
+func (a *ownerAlerter) deliverToOwner(embed discord.Embed) error {
+	st := a.alertState()
+	if st == nil {
+		return fmt.Errorf("deliverToOwner: no connected bot runtime")
+	}
+	return sendOwnerEmbed(st, a.configManager, embed)
+}
+
+// sendOwnerEmbed posts embed through st to the owner alert channel, or to
+// the application owner's DMs when none is configured.
+func sendOwnerEmbed(st *state.State, configManager *files.ConfigManager, embed discord.Embed) error {
+	data := api.SendMessageData{
+		Embeds:          []discord.Embed{embed},
+		AllowedMentions: &api.AllowedMentions{Parse: []api.AllowedMentionType{}},
+	}
+
+	var channelID string
+	if cfg := configManager.Config(); cfg != nil {
+		channelID = strings.TrimSpace(cfg.RuntimeConfig.OwnerAlertChannelID)
+	}
+	if channelID == "" {
+		return messageApplicationOwner(st, data)
+	}
+	sf, err := discord.ParseSnowflake(channelID)
+	if err != nil {
+		return fmt.Errorf("sendOwnerEmbed: parse owner alert channel: %w", err)
+	}
+	if _, err := st.SendMessageComplex(discord.ChannelID(sf), data); err != nil {
+		return fmt.Errorf("sendOwnerEmbed: %w", err)
+	}
+	return nil
+}
+
+// alertState picks the runtime that posts alerts: the default instance when
+// it is up, otherwise any connected one.
+func (a *ownerAlerter) alertState() *state.State {
+	var fallback *state.State
+	for id, runtime := range a.resolver.getRuntimes() {
+		if runtime == nil || runtime.arikawaState == nil {
+			continue
+		}
+		if id == files.NormalizeBotInstanceID("") {
+			return runtime.arikawaState
+		}
+		if fallback == nil {
+			fallback = runtime.arikawaState
+		}
+	}
+	return fallback
+}
+
+func filterOwnerAlerts(summaries []observability.OperationalAlertSummary) []observability.OperationalAlertSummary {
+	out := summaries[:0]
+	for _, summary := range summaries {
+		if summary.Count >= ownerAlertThresholds[summary.Kind] {
+			out = append(out, summary)
+		}
+	}
+	return out
+}
+
+func buildOwnerAlertEmbed(summaries []observability.OperationalAlertSummary, dropped int64, window time.Duration) discord.Embed {
+	ce := files.CustomEmbedConfig{
+		Title:       "Operational Alerts",
+		Description: fmt.Sprintf("%d alert source(s) reported problems in the last %s.", len(summaries), window),
+		Color:       theme.Danger(),
+	}
+	if len(summaries) == 0 {
+		ce.Color = theme.Warning()
+	}
+	for i, summary := range summaries {
+		if i == ownerAlertMaxFields {
+			ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+				Name:  "More",
+				Value: fmt.Sprintf("%d more source(s) not shown.", len(summaries)-i),
+			})
+			break
+		}
+		value := fmt.Sprintf("%d× since %s", summary.Count, logging.DiscordTimestamp(summary.FirstAt, logging.TimestampLongTime))
+		if summary.LastMessage != "" {
+			value += "\n`" + truncateAlertMessage(summary.LastMessage, 300) + "`"
+		}
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+			Name:  fmt.Sprintf("%s · %s", summary.Kind, summary.Source),
+			Value: value,
+		})
+	}
+	if dropped > 0 {
+		ce.FooterText = fmt.Sprintf("%d report(s) discarded: too many distinct sources.", dropped)
+	}
+	embed := embeds.Render(ce)
+	embed.Timestamp = discord.NowTimestamp()
+	return embed
+}
+
+func truncateAlertMessage(message string, limit int) string {
+	message = strings.ReplaceAll(message, "`", "'")
+	if len(message) <= limit {
+		return message
+	}
+	return message[:limit-1] + "…"
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/observability"
+)
+
+func TestOwnerAlerterFlushAppliesThresholds(t *testing.T) {
+	observability.DrainOperationalAlerts()
+	t.Cleanup(func() { observability.DrainOperationalAlerts() })
+
+	var sent []discord.Embed
+	alerter := &ownerAlerter{
+		interval: time.Minute,
+		deliver: func(embed discord.Embed) error {
+			sent = append(sent, embed)
+			return nil
+		},
+	}
+
+	observability.ReportOperationalAlert(observability.AlertSendFailure, "logging.notification_sender", errors.New("missing access"))
+	if alerter.flush() {
+		t.Fatal("expected a single send failure to stay below the alert threshold")
+	}
+
+	for range 3 {
+		observability.ReportOperationalAlert(observability.AlertSendFailure, "logging.notification_sender", errors.New("missing access"))
+	}
+	observability.ReportOperationalAlert(observability.AlertServiceCrash, "monitoring", errors.New("exceeded maximum restart attempts"))
+	if !alerter.flush() {
+		t.Fatal("expected alerts above threshold to be sent")
+	}
+	if len(sent) != 1 {
+		t.Fatalf("sent %d embeds, want 1", len(sent))
+	}
+	if got := len(sent[0].Fields); got != 2 {
+		t.Fatalf("summary has %d fields, want 2", got)
+	}
+	for _, field := range sent[0].Fields {
+		if strings.HasPrefix(field.Name, string(observability.AlertSendFailure)) && !strings.HasPrefix(field.Value, "3×") {
+			t.Fatalf("send failure field = %q, want count 3", field.Value)
+		}
+	}
+
+	if alerter.flush() {
+		t.Fatal("expected nothing to send after the window was drained")
+	}
+}
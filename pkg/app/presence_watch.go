@@ -0,0 +1,283 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+)
+
+const (
+	// presenceSettleDelay is how long a new presence must stay unchanged
+	// before it is logged, so a status typed letter by letter or a game
+	// restarted in a loop logs once.
+	presenceSettleDelay = 30 * time.Second
+
+	// presenceMinLogInterval is the least time between two logs about one
+	// member in one guild. Changes in between are coalesced into the next
+	// log, which compares against the previous one.
+	presenceMinLogInterval = 10 * time.Minute
+
+	// presenceFlushInterval spaces the checks for settled changes.
+	presenceFlushInterval = 10 * time.Second
+)
+
+// activityVerbs names each activity type the way Discord shows it.
+var activityVerbs = map[discord.ActivityType]string{
+	discord.GameActivity:      "Playing",
+	discord.StreamingActivity: "Streaming",
+	discord.ListeningActivity: "Listening to",
+	discord.WatchingActivity:  "Watching",
+	discord.CompetingActivity: "Competing in",
+}
+
+// presenceWatchClient names the bot, which presence_watch.bot watches.
+// *state.State satisfies it.
+type presenceWatchClient interface {
+	Me() (*discord.User, error)
+}
+
+type presenceKey struct {
+	guildID string
+	userID  string
+}
+
+// presenceSnapshot is the part of a presence that is logged.
+type presenceSnapshot struct {
+	customStatus string
+	activities   []members.PresenceActivity
+}
+
+func (s presenceSnapshot) equal(o presenceSnapshot) bool {
+	return s.customStatus == o.customStatus && slices.Equal(s.activities, o.activities)
+}
+
+// watchedPresence is what is known of one watched member in one guild:
+// the presence last logged, or first seen, and the change waiting to be.
+type watchedPresence struct {
+	username  string
+	bot       bool
+	logged    presenceSnapshot
+	loggedAt  time.Time
+	pending   *presenceSnapshot
+	changedAt time.Time
+}
+
+// presenceWatcher logs the custom status and activity changes of the
+// members each guild watches. Guilds opt in with the presence_watch feature
+// toggles: presence_watch.user covers the guild's watchlist and the runtime
+// presence_watch_user_id, presence_watch.bot the bot itself.
+//
+// A member's first presence seen is only remembered, so nothing is logged
+// at startup. Offline presences carry no activities and are ignored rather
+// than logged as everything being cleared.
+type presenceWatcher struct {
+	instanceID    string
+	sink          members.PresenceSink
+	client        presenceWatchClient
+	configManager *files.ConfigManager
+	now           func() time.Time
+
+	mu      sync.Mutex
+	members map[presenceKey]*watchedPresence
+}
+
+func newPresenceWatcher(instanceID string, sink members.PresenceSink, client presenceWatchClient, configManager *files.ConfigManager) *presenceWatcher {
+	return &presenceWatcher{
+		instanceID:    instanceID,
+		sink:          sink,
+		client:        client,
+		configManager: configManager,
+		now:           time.Now,
+		members:       make(map[presenceKey]*watchedPresence),
+	}
+}
+
+func (w *presenceWatcher) attach(st *state.State) {
+	st.AddHandler(perf.GuardGatewayHandler("presence_watch.guild_create", w.handleGuildCreate))
+	st.AddHandler(perf.GuardGatewayHandler("presence_watch.presence_update", w.handlePresenceUpdate))
+}
+
+// run logs the settled changes every presenceFlushInterval until ctx is
+// done.
+func (w *presenceWatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(presenceFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.flush(ctx)
+		}
+	}
+}
+
+// handleGuildCreate remembers the presences the guild arrives with, so the
+// first change after a reconnect is compared against them.
+func (w *presenceWatcher) handleGuildCreate(e *gateway.GuildCreateEvent) {
+	if e == nil {
+		return
+	}
+	for _, p := range e.Presences {
+		p.GuildID = e.ID
+		w.observe(&p)
+	}
+}
+
+func (w *presenceWatcher) handlePresenceUpdate(e *gateway.PresenceUpdateEvent) {
+	if e == nil {
+		return
+	}
+	w.observe(&e.Presence)
+}
+
+// observe records p if its member is watched in its guild, and forgets a
+// member who no longer is.
+func (w *presenceWatcher) observe(p *discord.Presence) {
+	if !p.GuildID.IsValid() || !p.User.ID.IsValid() {
+		return
+	}
+	key := presenceKey{guildID: p.GuildID.String(), userID: p.User.ID.String()}
+	if !w.watches(key) {
+		w.mu.Lock()
+		delete(w.members, key)
+		w.mu.Unlock()
+		return
+	}
+	if p.Status == discord.OfflineStatus || p.Status == discord.InvisibleStatus {
+		return
+	}
+
+	snapshot := snapshotPresence(p.Activities)
+	now := w.now()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	member, seen := w.members[key]
+	if !seen {
+		w.members[key] = &watchedPresence{username: p.User.Username, bot: p.User.Bot, logged: snapshot}
+		return
+	}
+	if p.User.Username != "" {
+		member.username = p.User.Username
+	}
+	switch {
+	case snapshot.equal(member.logged):
+		// Changed back before it was logged.
+		member.pending = nil
+	case member.pending == nil || !snapshot.equal(*member.pending):
+		member.pending = &snapshot
+		member.changedAt = now
+	}
+}
+
+// flush reports the changes that have settled, to members not logged
+// within presenceMinLogInterval.
+func (w *presenceWatcher) flush(ctx context.Context) {
+	now := w.now()
+	var due []members.PresenceIntent
+	w.mu.Lock()
+	for key, member := range w.members {
+		if member.pending == nil || now.Sub(member.changedAt) < presenceSettleDelay {
+			continue
+		}
+		if !member.loggedAt.IsZero() && now.Sub(member.loggedAt) < presenceMinLogInterval {
+			continue
+		}
+		due = append(due, members.PresenceIntent{
+			GuildID:              key.guildID,
+			UserID:               key.userID,
+			Username:             member.username,
+			Bot:                  member.bot,
+			CustomStatus:         member.pending.customStatus,
+			PreviousCustomStatus: member.logged.customStatus,
+			Activities:           member.pending.activities,
+			PreviousActivities:   member.logged.activities,
+		})
+		member.logged = *member.pending
+		member.loggedAt = now
+		member.pending = nil
+	}
+	w.mu.Unlock()
+
+	for _, intent := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		w.sink.OnPresenceChange(ctx, intent)
+	}
+}
+
+// watches reports whether the member of key is watched by this instance in
+// its guild.
+func (w *presenceWatcher) watches(key presenceKey) bool {
+	cfg := w.configManager.Config()
+	guild := w.configManager.GuildConfig(key.guildID)
+	if cfg == nil || guild == nil {
+		return false
+	}
+	if id, _ := files.ResolveFeatureBotInstanceID(*guild, "logging"); id != w.instanceID {
+		return false
+	}
+	features := cfg.ResolveFeatures(key.guildID)
+	rc := cfg.ResolveRuntimeConfig(key.guildID)
+	if rc.DisableUserLogs {
+		return false
+	}
+	if features.PresenceWatch.User && (guild.PresenceWatch.Watches(key.userID) || strings.TrimSpace(rc.PresenceWatchUserID) == key.userID) {
+		return true
+	}
+	if features.PresenceWatch.Bot && rc.PresenceWatchBot {
+		me, err := w.client.Me()
+		if err != nil {
+			slog.Debug("Presence watch could not name the bot user",
+				slog.String("botInstanceID", w.instanceID),
+				slog.String("error", err.Error()),
+			)
+			return false
+		}
+		return me.ID.String() == key.userID
+	}
+	return false
+}
+
+// snapshotPresence keeps the custom status and the type and name of every
+// other activity. Details such as the song playing or the time elapsed
+// change constantly and are left out.
+func snapshotPresence(activities []discord.Activity) presenceSnapshot {
+	var snapshot presenceSnapshot
+	for _, activity := range activities {
+		if activity.Type == discord.CustomActivity {
+			snapshot.customStatus = customStatusText(activity)
+			continue
+		}
+		verb, ok := activityVerbs[activity.Type]
+		if !ok || activity.Name == "" {
+			continue
+		}
+		snapshot.activities = append(snapshot.activities, members.PresenceActivity{Verb: verb, Name: activity.Name})
+	}
+	return snapshot
+}
+
+// customStatusText renders a custom status as its emoji and text. Custom
+// emojis are shown by name, since the bot may not share their server.
+func customStatusText(activity discord.Activity) string {
+	var emoji string
+	if activity.Emoji != nil && activity.Emoji.Name != "" {
+		emoji = activity.Emoji.Name
+		if activity.Emoji.ID.IsValid() {
+			emoji = ":" + emoji + ":"
+		}
+	}
+	return strings.TrimSpace(emoji + " " + activity.State)
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+)
+
+type recordingPresenceSink struct {
+	events []members.PresenceIntent
+}
+
+func (s *recordingPresenceSink) OnPresenceChange(_ context.Context, intent members.PresenceIntent) {
+	s.events = append(s.events, intent)
+}
+
+type fakeBotUser struct{}
+
+func (fakeBotUser) Me() (*discord.User, error) { return &discord.User{ID: 1, Bot: true}, nil }
+
+func presenceUpdate(guildID discord.GuildID, userID discord.UserID, status string, activities ...discord.Activity) *gateway.PresenceUpdateEvent {
+	return &gateway.PresenceUpdateEvent{Presence: discord.Presence{
+		GuildID:    guildID,
+		User:       discord.User{ID: userID, Username: "watched"},
+		Status:     discord.OnlineStatus,
+		Activities: append([]discord.Activity{{Type: discord.CustomActivity, State: status}}, activities...),
+	}}
+}
+
+func TestPresenceWatcher(t *testing.T) {
+	t.Parallel()
+	on := true
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{
+		{
+			GuildID:       "2",
+			Features:      files.FeatureToggles{PresenceWatch: files.FeaturePresenceWatchToggles{User: &on}},
+			PresenceWatch: files.PresenceWatchConfig{UserIDs: []string{"5"}},
+		},
+		// The watchlist does nothing without the feature.
+		{GuildID: "3", PresenceWatch: files.PresenceWatchConfig{UserIDs: []string{"5"}}},
+	}})
+	sink := &recordingPresenceSink{}
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	now := start
+	watcher := newPresenceWatcher("", sink, fakeBotUser{}, cfgMgr)
+	watcher.now = func() time.Time { return now }
+	at := func(d time.Duration) { now = start.Add(d) }
+
+	watcher.handlePresenceUpdate(presenceUpdate(2, 5, "working"))
+	watcher.handlePresenceUpdate(presenceUpdate(2, 6, "unwatched"))
+	watcher.handlePresenceUpdate(presenceUpdate(3, 5, "working"))
+	watcher.handlePresenceUpdate(presenceUpdate(2, 5, "away"))
+	at(10 * time.Second)
+	watcher.handlePresenceUpdate(presenceUpdate(2, 5, "away for lunch", discord.Activity{Type: discord.GameActivity, Name: "Chess"}))
+	at(30 * time.Second)
+	watcher.flush(context.Background())
+	if len(sink.events) != 0 {
+		t.Fatalf("expected nothing to be logged before the change settled, got %+v", sink.events)
+	}
+
+	at(40 * time.Second)
+	watcher.flush(context.Background())
+	want := members.PresenceIntent{
+		GuildID: "2", UserID: "5", Username: "watched",
+		CustomStatus: "away for lunch", PreviousCustomStatus: "working",
+		Activities: []members.PresenceActivity{{Verb: "Playing", Name: "Chess"}},
+	}
+	if len(sink.events) != 1 || sink.events[0].CustomStatus != want.CustomStatus || sink.events[0].PreviousCustomStatus != want.PreviousCustomStatus ||
+		len(sink.events[0].Activities) != 1 || sink.events[0].Activities[0] != want.Activities[0] || sink.events[0].GuildID != "2" {
+		t.Fatalf("expected one coalesced change, got %+v", sink.events)
+	}
+
+	// Going offline clears nothing, and a change reverted before it settles
+	// is not logged.
+	watcher.handlePresenceUpdate(&gateway.PresenceUpdateEvent{Presence: discord.Presence{GuildID: 2, User: discord.User{ID: 5}, Status: discord.OfflineStatus}})
+	watcher.handlePresenceUpdate(presenceUpdate(2, 5, "back"))
+	watcher.handlePresenceUpdate(presenceUpdate(2, 5, "away for lunch", discord.Activity{Type: discord.GameActivity, Name: "Chess"}))
+	at(5 * time.Minute)
+	watcher.flush(context.Background())
+	if len(sink.events) != 1 {
+		t.Fatalf("expected offline and reverted presences to be skipped, got %+v", sink.events)
+	}
+
+	// Another change waits for the minimum interval since the last log.
+	watcher.handlePresenceUpdate(presenceUpdate(2, 5, "back"))
+	at(6 * time.Minute)
+	watcher.flush(context.Background())
+	if len(sink.events) != 1 {
+		t.Fatalf("expected the rate limit to hold the change, got %+v", sink.events)
+	}
+	at(11 * time.Minute)
+	watcher.flush(context.Background())
+	if len(sink.events) != 2 || sink.events[1].CustomStatus != "back" || sink.events[1].PreviousCustomStatus != "away for lunch" || len(sink.events[1].Activities) != 0 {
+		t.Fatalf("expected the held change once the interval passed, got %+v", sink.events)
+	}
+}
+
+func TestSnapshotPresence(t *testing.T) {
+	t.Parallel()
+	snapshot := snapshotPresence([]discord.Activity{
+		{Type: discord.ListeningActivity, Name: "Spotify", Details: "A song"},
+		{Type: discord.CustomActivity, State: "busy", Emoji: &discord.Emoji{ID: 9, Name: "blob"}},
+		{Type: discord.GameActivity},
+	})
+	if snapshot.customStatus != ":blob: busy" {
+		t.Fatalf("custom status = %q", snapshot.customStatus)
+	}
+	if len(snapshot.activities) != 1 || snapshot.activities[0] != (members.PresenceActivity{Verb: "Listening to", Name: "Spotify"}) {
+		t.Fatalf("unexpected activities %+v", snapshot.activities)
+	}
+}
@@ -0,0 +1,216 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/clean"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+	"github.com/small-frappuccino/discordcore/pkg/messages"
+	"github.com/small-frappuccino/discordcore/pkg/system"
+)
+
+// privacyPolicyFunc resolves the privacy policy of a guild.
+type privacyPolicyFunc func(guildID string) files.PrivacyPolicy
+
+// The privacy stores sit between the event services and the database, so a
+// guild's privacy profile applies to every write without each service
+// checking it. Reads pass through untouched.
+
+// privacyMessageStore drops message text and activity counts for guilds
+// that opted out of them, or swaps the text for a salted hash. Message
+// metadata is still stored so delete and edit logs can name the author.
+type privacyMessageStore struct {
+	messages.Repository
+	policy privacyPolicyFunc
+	salt   []byte
+}
+
+func newPrivacyMessageStore(repo messages.Repository, policy privacyPolicyFunc, salt []byte) messages.Repository {
+	if repo == nil || policy == nil {
+		return repo
+	}
+	return &privacyMessageStore{Repository: repo, policy: policy, salt: salt}
+}
+
+func (s *privacyMessageStore) minimize(rec messages.Record) messages.Record {
+	policy := s.policy(rec.GuildID)
+	if policy.CacheMessageContent || rec.Content == "" {
+		return rec
+	}
+	if policy.HashMessageContent {
+		rec.ContentHash = messages.HashContent(s.salt, rec.GuildID, rec.Content)
+		rec.ContentLength = messages.ContentLength(rec.Content)
+	}
+	rec.Content = ""
+	return rec
+}
+
+func (s *privacyMessageStore) UpsertMessage(m messages.Record) error {
+	return s.Repository.UpsertMessage(s.minimize(m))
+}
+
+func (s *privacyMessageStore) UpsertMessagesContext(ctx context.Context, records []messages.Record) error {
+	out := make([]messages.Record, len(records))
+	for i, rec := range records {
+		out[i] = s.minimize(rec)
+	}
+	return s.Repository.UpsertMessagesContext(ctx, out)
+}
+
+func (s *privacyMessageStore) InsertMessageVersion(ctx context.Context, v messages.Version) error {
+	if !s.policy(v.GuildID).CacheMessageContent {
+		v.Content = ""
+	}
+	return s.Repository.InsertMessageVersion(ctx, v)
+}
+
+func (s *privacyMessageStore) InsertMessageVersionsMixedBatchContext(ctx context.Context, versions []messages.Version) error {
+	out := make([]messages.Version, len(versions))
+	for i, v := range versions {
+		if !s.policy(v.GuildID).CacheMessageContent {
+			v.Content = ""
+		}
+		out[i] = v
+	}
+	return s.Repository.InsertMessageVersionsMixedBatchContext(ctx, out)
+}
+
+func (s *privacyMessageStore) IncrementDailyMessageCount(ctx context.Context, guildID string) error {
+	if !s.policy(guildID).ActivityMetrics {
+		return nil
+	}
+	return s.Repository.IncrementDailyMessageCount(ctx, guildID)
+}
+
+func (s *privacyMessageStore) IncrementDailyMessageCountsContext(ctx context.Context, deltas []messages.DailyCountDelta) error {
+	kept := make([]messages.DailyCountDelta, 0, len(deltas))
+	for _, delta := range deltas {
+		if s.policy(delta.GuildID).ActivityMetrics {
+			kept = append(kept, delta)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return s.Repository.IncrementDailyMessageCountsContext(ctx, kept)
+}
+
+// newPrivacyArchiver keeps only message metadata in archives of guilds that
+// do not store message text.
+func newPrivacyArchiver(archiver clean.Archiver, policy privacyPolicyFunc) clean.Archiver {
+	if archiver == nil || policy == nil {
+		return archiver
+	}
+	return func(ctx context.Context, d clean.Deletion) error {
+		if !policy(d.GuildID).CacheMessageContent {
+			redacted := make([]clean.Message, len(d.Messages))
+			for i, m := range d.Messages {
+				m.Content = ""
+				m.AttachmentURLs = nil
+				redacted[i] = m
+			}
+			d.Messages = redacted
+		}
+		return archiver(ctx, d)
+	}
+}
+
+// privacyMemberStore drops avatar hashes for guilds without avatar history.
+// With no stored hash the avatar diff has nothing to compare against, so
+// avatar changes are never logged for those guilds either.
+type privacyMemberStore struct {
+	members.Repository
+	policy privacyPolicyFunc
+}
+
+func newPrivacyMemberStore(repo members.Repository, policy privacyPolicyFunc) members.Repository {
+	if repo == nil || policy == nil {
+		return repo
+	}
+	return &privacyMemberStore{Repository: repo, policy: policy}
+}
+
+func (s *privacyMemberStore) UpsertGuildMemberSnapshotsContext(ctx context.Context, guildID string, snapshots []members.Snapshot, updatedAt time.Time) error {
+	return s.Repository.UpsertGuildMemberSnapshotsContext(ctx, guildID, stripAvatars(s.policy(guildID), snapshots), updatedAt)
+}
+
+// privacyAvatarStore applies the same rule to the avatar poller's store.
+type privacyAvatarStore struct {
+	members.AvatarSnapshotStore
+	policy privacyPolicyFunc
+}
+
+func newPrivacyAvatarStore(store members.AvatarSnapshotStore, policy privacyPolicyFunc) members.AvatarSnapshotStore {
+	if store == nil || policy == nil {
+		return store
+	}
+	return &privacyAvatarStore{AvatarSnapshotStore: store, policy: policy}
+}
+
+func (s *privacyAvatarStore) UpsertGuildMemberSnapshotsContext(ctx context.Context, guildID string, snapshots []members.Snapshot, updatedAt time.Time) error {
+	return s.AvatarSnapshotStore.UpsertGuildMemberSnapshotsContext(ctx, guildID, stripAvatars(s.policy(guildID), snapshots), updatedAt)
+}
+
+// privacyNameStore keeps no names for guilds without avatar history, which
+// leaves name changes unlogged there as well.
+type privacyNameStore struct {
+	members.NameSnapshotStore
+	policy privacyPolicyFunc
+}
+
+func newPrivacyNameStore(store members.NameSnapshotStore, policy privacyPolicyFunc) members.NameSnapshotStore {
+	if store == nil || policy == nil {
+		return store
+	}
+	return &privacyNameStore{NameSnapshotStore: store, policy: policy}
+}
+
+func (s *privacyNameStore) UpsertMemberNames(ctx context.Context, guildID, userID string, names members.MemberNames, updatedAt time.Time) error {
+	if !s.policy(guildID).AvatarHistory {
+		return nil
+	}
+	return s.NameSnapshotStore.UpsertMemberNames(ctx, guildID, userID, names, updatedAt)
+}
+
+func stripAvatars(policy files.PrivacyPolicy, snapshots []members.Snapshot) []members.Snapshot {
+	if policy.AvatarHistory {
+		return snapshots
+	}
+	out := make([]members.Snapshot, len(snapshots))
+	for i, snap := range snapshots {
+		snap.AvatarHash = ""
+		snap.HasAvatar = false
+		out[i] = snap
+	}
+	return out
+}
+
+// privacySystemRepo drops join and leave counts for guilds without activity
+// metrics. Membership itself is still tracked by the members store.
+type privacySystemRepo struct {
+	system.Repository
+	policy privacyPolicyFunc
+}
+
+func newPrivacySystemRepo(repo system.Repository, policy privacyPolicyFunc) system.Repository {
+	if repo == nil || policy == nil {
+		return repo
+	}
+	return &privacySystemRepo{Repository: repo, policy: policy}
+}
+
+func (r *privacySystemRepo) IncrementDailyMemberJoinContext(ctx context.Context, guildID, userID string, timestamp time.Time) error {
+	if !r.policy(guildID).ActivityMetrics {
+		return nil
+	}
+	return r.Repository.IncrementDailyMemberJoinContext(ctx, guildID, userID, timestamp)
+}
+
+func (r *privacySystemRepo) IncrementDailyMemberLeaveContext(ctx context.Context, guildID, userID string, timestamp time.Time) error {
+	if !r.policy(guildID).ActivityMetrics {
+		return nil
+	}
+	return r.Repository.IncrementDailyMemberLeaveContext(ctx, guildID, userID, timestamp)
+}
@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/clean"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+	"github.com/small-frappuccino/discordcore/pkg/messages"
+)
+
+type recordingMessageStore struct {
+	messages.Repository
+	records []messages.Record
+	deltas  []messages.DailyCountDelta
+}
+
+func (s *recordingMessageStore) UpsertMessagesContext(_ context.Context, records []messages.Record) error {
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func (s *recordingMessageStore) IncrementDailyMessageCountsContext(_ context.Context, deltas []messages.DailyCountDelta) error {
+	s.deltas = append(s.deltas, deltas...)
+	return nil
+}
+
+type recordingAvatarStore struct {
+	members.AvatarSnapshotStore
+	snapshots []members.Snapshot
+}
+
+func (s *recordingAvatarStore) UpsertGuildMemberSnapshotsContext(_ context.Context, _ string, snapshots []members.Snapshot, _ time.Time) error {
+	s.snapshots = append(s.snapshots, snapshots...)
+	return nil
+}
+
+type recordingNameStore struct {
+	members.NameSnapshotStore
+	guilds []string
+}
+
+func (s *recordingNameStore) UpsertMemberNames(_ context.Context, guildID, _ string, _ members.MemberNames, _ time.Time) error {
+	s.guilds = append(s.guilds, guildID)
+	return nil
+}
+
+func minimalFor(guildID string) privacyPolicyFunc {
+	return func(id string) files.PrivacyPolicy {
+		if id == guildID {
+			return files.PrivacyMinimal.Policy()
+		}
+		return files.PrivacyStandard.Policy()
+	}
+}
+
+func TestPrivacyMessageStore(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingMessageStore{}
+	store := newPrivacyMessageStore(inner, minimalFor("private"), []byte("salt"))
+	ctx := context.Background()
+
+	records := []messages.Record{
+		{GuildID: "private", MessageID: "1", AuthorID: "u1", Content: "secret"},
+		{GuildID: "public", MessageID: "2", AuthorID: "u2", Content: "hello"},
+	}
+	if err := store.UpsertMessagesContext(ctx, records); err != nil {
+		t.Fatalf("UpsertMessagesContext: %v", err)
+	}
+	if got := inner.records[0]; got.Content != "" || got.AuthorID != "u1" {
+		t.Fatalf("private record = %+v, want metadata without content", got)
+	}
+	if got := inner.records[1].Content; got != "hello" {
+		t.Fatalf("public content = %q", got)
+	}
+	if records[0].Content != "secret" {
+		t.Fatal("caller's records were modified")
+	}
+
+	if err := store.IncrementDailyMessageCountsContext(ctx, []messages.DailyCountDelta{
+		{GuildID: "private", Count: 1},
+		{GuildID: "public", Count: 2},
+	}); err != nil {
+		t.Fatalf("IncrementDailyMessageCountsContext: %v", err)
+	}
+	if len(inner.deltas) != 1 || inner.deltas[0].GuildID != "public" {
+		t.Fatalf("stored deltas = %+v, want only the public guild", inner.deltas)
+	}
+}
+
+func TestPrivacyAvatarStore(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingAvatarStore{}
+	store := newPrivacyAvatarStore(inner, minimalFor("private"))
+	snaps := []members.Snapshot{{UserID: "u1", AvatarHash: "abc", HasAvatar: true, JoinedAt: time.Unix(1, 0)}}
+
+	if err := store.UpsertGuildMemberSnapshotsContext(context.Background(), "private", snaps, time.Now()); err != nil {
+		t.Fatalf("UpsertGuildMemberSnapshotsContext: %v", err)
+	}
+	if err := store.UpsertGuildMemberSnapshotsContext(context.Background(), "public", snaps, time.Now()); err != nil {
+		t.Fatalf("UpsertGuildMemberSnapshotsContext: %v", err)
+	}
+	if got := inner.snapshots[0]; got.HasAvatar || got.AvatarHash != "" || got.JoinedAt.IsZero() {
+		t.Fatalf("private snapshot = %+v, want join data without avatar", got)
+	}
+	if got := inner.snapshots[1]; got.AvatarHash != "abc" {
+		t.Fatalf("public snapshot = %+v", got)
+	}
+}
+
+func TestPrivacyNameStore(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingNameStore{}
+	store := newPrivacyNameStore(inner, minimalFor("private"))
+	names := members.MemberNames{Username: "alice", Nick: "Al"}
+	for _, guildID := range []string{"private", "public"} {
+		if err := store.UpsertMemberNames(context.Background(), guildID, "u1", names, time.Now()); err != nil {
+			t.Fatalf("UpsertMemberNames: %v", err)
+		}
+	}
+	if len(inner.guilds) != 1 || inner.guilds[0] != "public" {
+		t.Fatalf("stored names for %v, want only the public guild", inner.guilds)
+	}
+}
+
+func TestPrivacyArchiver(t *testing.T) {
+	t.Parallel()
+
+	var got []clean.Deletion
+	archive := newPrivacyArchiver(func(_ context.Context, d clean.Deletion) error {
+		got = append(got, d)
+		return nil
+	}, minimalFor("private"))
+	msgs := []clean.Message{{ID: "1", AuthorID: "u1", Content: "secret", AttachmentURLs: []string{"https://cdn.example/a.png"}}}
+
+	for _, guildID := range []string{"private", "public"} {
+		if err := archive(context.Background(), clean.Deletion{GuildID: guildID, Messages: msgs}); err != nil {
+			t.Fatalf("archive %s: %v", guildID, err)
+		}
+	}
+	if m := got[0].Messages[0]; m.Content != "" || m.AttachmentURLs != nil || m.AuthorID != "u1" {
+		t.Fatalf("private archive = %+v, want metadata only", m)
+	}
+	if m := got[1].Messages[0]; m.Content != "secret" {
+		t.Fatalf("public archive = %+v", m)
+	}
+	if msgs[0].Content != "secret" {
+		t.Fatal("the caller's messages were modified")
+	}
+}
+
+func TestPrivacyMessageStoreHashesContent(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingMessageStore{}
+	salt := []byte("salt")
+	store := newPrivacyMessageStore(inner, func(string) files.PrivacyPolicy {
+		return files.PrivacyHashed.Policy()
+	}, salt)
+
+	if err := store.UpsertMessagesContext(context.Background(), []messages.Record{
+		{GuildID: "g1", MessageID: "1", Content: "buy nitro"},
+		{GuildID: "g1", MessageID: "2", Content: "buy nitro"},
+		{GuildID: "g2", MessageID: "3", Content: "buy nitro"},
+	}); err != nil {
+		t.Fatalf("UpsertMessagesContext: %v", err)
+	}
+	first, second, other := inner.records[0], inner.records[1], inner.records[2]
+	if first.Content != "" || first.ContentLength != 9 || first.ContentHash == "" {
+		t.Fatalf("hashed record = %+v", first)
+	}
+	if first.ContentHash != second.ContentHash {
+		t.Fatal("equal text in one guild must hash equally")
+	}
+	if first.ContentHash == other.ContentHash {
+		t.Fatal("hashes must not match across guilds")
+	}
+	if first.ContentHash != messages.HashContent(salt, "g1", "buy nitro") {
+		t.Fatal("stored hash does not match HashContent")
+	}
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/state"
+
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+// raidModeInterval spaces the checks for raid modes that ran out.
+const raidModeInterval = time.Minute
+
+// raidModeWatcher lifts raid modes once their window ends and kicks accounts
+// that join a guild in raid mode too young.
+type raidModeWatcher struct {
+	instanceID    string
+	guard         *discordmod.RaidGuard
+	store         discordmod.RaidModeStore
+	configManager *files.ConfigManager
+	interval      time.Duration
+	now           func() time.Time
+}
+
+func newRaidModeWatcher(instanceID string, store discordmod.RaidModeStore, client discordmod.RaidModeClient, configManager *files.ConfigManager) *raidModeWatcher {
+	return &raidModeWatcher{
+		instanceID:    instanceID,
+		guard:         discordmod.NewRaidGuard(client, store, slog.With("domain", "raid_mode")),
+		store:         store,
+		configManager: configManager,
+		interval:      raidModeInterval,
+		now:           time.Now,
+	}
+}
+
+func (w *raidModeWatcher) attach(st *state.State) {
+	st.AddHandler(perf.GuardGatewayHandler("raid_mode.member_add", w.handleMemberAdd))
+}
+
+func (w *raidModeWatcher) handleMemberAdd(e *gateway.GuildMemberAddEvent) {
+	if e == nil {
+		return
+	}
+	ctx := context.Background()
+	if guild := w.configManager.GuildConfig(e.GuildID.String()); guild != nil && guild.TestMode {
+		ctx = coremod.WithTestMode(ctx)
+	}
+	kicked, err := w.guard.Screen(ctx, e.GuildID, e.User)
+	if err != nil {
+		slog.Warn("Mitigated service degradation: Raid mode could not screen a joining member",
+			slog.String("botInstanceID", w.instanceID),
+			slog.String("guildID", e.GuildID.String()),
+			slog.String("userID", e.User.ID.String()),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	if kicked {
+		slog.Info("Architectural state transition: Raid mode kicked a new account",
+			slog.String("botInstanceID", w.instanceID),
+			slog.String("guildID", e.GuildID.String()),
+			slog.String("userID", e.User.ID.String()),
+		)
+	}
+}
+
+// run performs a pass right away, lifting what ran out while the bot was
+// down, then one per interval until ctx is done.
+func (w *raidModeWatcher) run(ctx context.Context) {
+	if w == nil || w.interval <= 0 {
+		return
+	}
+	w.pass(ctx)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.pass(ctx)
+		}
+	}
+}
+
+// pass lifts the expired raid modes of the guilds this instance moderates.
+// Other guilds are left to their own instance.
+func (w *raidModeWatcher) pass(ctx context.Context) {
+	cfg := w.configManager.Config()
+	if cfg == nil {
+		return
+	}
+	modes, err := w.store.ListExpiredRaidModes(ctx, w.now())
+	if err != nil {
+		slog.Warn("Mitigated service degradation: Expired raid modes could not be listed",
+			slog.String("botInstanceID", w.instanceID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	if len(modes) == 0 {
+		return
+	}
+	moderated := make(map[string]bool)
+	for _, guild := range files.GuildsForBotInstanceFeature(cfg, w.instanceID, "moderation") {
+		moderated[guild.GuildID] = true
+	}
+	for _, mode := range modes {
+		if ctx.Err() != nil {
+			return
+		}
+		if !moderated[mode.GuildID] {
+			continue
+		}
+		if err := w.guard.Lift(ctx, mode, "Raid mode expired"); err != nil {
+			slog.Warn("Mitigated service degradation: Expired raid mode could not be fully lifted",
+				slog.String("botInstanceID", w.instanceID),
+				slog.String("guildID", mode.GuildID),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		slog.Info("Architectural state transition: Raid mode expired and was lifted",
+			slog.String("botInstanceID", w.instanceID),
+			slog.String("guildID", mode.GuildID),
+		)
+	}
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+type fakeRaidModeStore struct {
+	modes map[string]coremod.RaidMode
+}
+
+func (f *fakeRaidModeStore) SaveRaidMode(_ context.Context, mode coremod.RaidMode) (bool, error) {
+	f.modes[mode.GuildID] = mode
+	return true, nil
+}
+
+func (f *fakeRaidModeStore) GetRaidMode(_ context.Context, guildID string) (coremod.RaidMode, bool, error) {
+	mode, ok := f.modes[guildID]
+	return mode, ok, nil
+}
+
+func (f *fakeRaidModeStore) ListExpiredRaidModes(_ context.Context, now time.Time) ([]coremod.RaidMode, error) {
+	var out []coremod.RaidMode
+	for _, mode := range f.modes {
+		if mode.Expired(now) {
+			out = append(out, mode)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRaidModeStore) DeleteRaidMode(_ context.Context, guildID string) error {
+	delete(f.modes, guildID)
+	return nil
+}
+
+type fakeRaidModeClient struct {
+	restored map[discord.GuildID]discord.Verification
+}
+
+func (c *fakeRaidModeClient) Guild(discord.GuildID) (*discord.Guild, error) {
+	return &discord.Guild{Verification: discord.HighVerification}, nil
+}
+
+func (c *fakeRaidModeClient) ModifyGuild(guildID discord.GuildID, data api.ModifyGuildData) (*discord.Guild, error) {
+	c.restored[guildID] = *data.Verification
+	return &discord.Guild{}, nil
+}
+
+func (c *fakeRaidModeClient) Kick(discord.GuildID, discord.UserID, api.AuditLogReason) error {
+	return nil
+}
+
+func (c *fakeRaidModeClient) FastRequest(string, string, ...httputil.RequestOption) error {
+	return nil
+}
+
+func TestRaidModeWatcherPass(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{{GuildID: "1"}, {GuildID: "2"}}})
+
+	store := &fakeRaidModeStore{modes: map[string]coremod.RaidMode{
+		"1": {GuildID: "1", ExpiresAt: now.Add(-time.Minute), RaisedVerification: true, PreviousVerification: 1},
+		"2": {GuildID: "2", ExpiresAt: now.Add(time.Hour), RaisedVerification: true},
+		"3": {GuildID: "3", ExpiresAt: now.Add(-time.Minute), RaisedVerification: true},
+	}}
+	client := &fakeRaidModeClient{restored: map[discord.GuildID]discord.Verification{}}
+	watcher := newRaidModeWatcher("", store, client, cfgMgr)
+	watcher.now = func() time.Time { return now }
+	watcher.pass(context.Background())
+
+	if _, ok := store.modes["1"]; ok || client.restored[1] != discord.LowVerification {
+		t.Fatalf("expected guild 1 lifted and restored, got modes %+v, restored %+v", store.modes, client.restored)
+	}
+	if _, ok := store.modes["2"]; !ok {
+		t.Fatal("a raid mode still running must stay on")
+	}
+	if _, ok := store.modes["3"]; !ok || len(client.restored) != 1 {
+		t.Fatal("guilds of other instances must be left alone")
+	}
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/storage/postgres"
+	"github.com/small-frappuccino/discordcore/pkg/task"
+)
+
+const (
+	retentionTaskType = "maintenance.retention"
+
+	// retentionHourUTC runs the pruning at night for most of the guilds
+	// hosted, when deleting is least felt.
+	retentionHourUTC = 4
+)
+
+// retentionStore prunes the data past each guild's retention. *postgres.Store
+// satisfies it.
+type retentionStore interface {
+	PruneGuildRetention(ctx context.Context, guildID string, cutoffs postgres.RetentionCutoffs) (int64, error)
+	PruneRetentionExcept(ctx context.Context, guildIDs []string, cutoffs postgres.RetentionCutoffs) (int64, error)
+}
+
+// scheduleRetention prunes cached messages, avatar history and archived log
+// events past their retention once a day until ctx ends.
+func scheduleRetention(ctx context.Context, store *postgres.Store, configManager *files.ConfigManager) {
+	if store == nil || configManager == nil {
+		return
+	}
+	routerCfg := task.Defaults()
+	routerCfg.Logger = slog.Default()
+	router := task.NewRouter(routerCfg)
+	router.RegisterHandler(retentionTaskType, func(ctx context.Context, _ any) error {
+		return enforceRetention(ctx, store, configManager.Config(), time.Now())
+	})
+	router.ScheduleDailyAtUTC(retentionHourUTC, 0, task.Task{
+		Type:    retentionTaskType,
+		Payload: task.EmptyPayload{},
+		Options: task.TaskOptions{GroupKey: retentionTaskType},
+	})
+	go func() {
+		<-ctx.Done()
+		router.Close()
+	}()
+}
+
+// enforceRetention prunes each configured guild at its own retention, then
+// every other guild at the defaults.
+func enforceRetention(ctx context.Context, store retentionStore, cfg *files.BotConfig, now time.Time) error {
+	var (
+		removed  int64
+		failed   int
+		guildIDs []string
+	)
+	if cfg != nil {
+		for _, guild := range cfg.Guilds {
+			if guild.GuildID == "" {
+				continue
+			}
+			guildIDs = append(guildIDs, guild.GuildID)
+			n, err := store.PruneGuildRetention(ctx, guild.GuildID, retentionCutoffs(guild.Retention, now))
+			removed += n
+			if err != nil {
+				failed++
+				slog.Warn("Mitigated service degradation: Failed to prune guild data past retention",
+					slog.String("guild_id", guild.GuildID),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}
+
+	n, err := store.PruneRetentionExcept(ctx, guildIDs, retentionCutoffs(files.RetentionConfig{}, now))
+	removed += n
+	if removed > 0 {
+		slog.Info("Operational telemetry: Pruned data past retention",
+			slog.Int64("removed", removed),
+			slog.Int("guilds", len(guildIDs)),
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("enforceRetention: %w", err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("enforceRetention: %d guild(s) failed to prune", failed)
+	}
+	return nil
+}
+
+func retentionCutoffs(cfg files.RetentionConfig, now time.Time) postgres.RetentionCutoffs {
+	return postgres.RetentionCutoffs{
+		MessageCache:  now.Add(-cfg.Duration(files.RetentionMessageCache)),
+		AvatarHistory: now.Add(-cfg.Duration(files.RetentionAvatarHistory)),
+		LogEvents:     now.Add(-cfg.Duration(files.RetentionLogEvents)),
+	}
+}
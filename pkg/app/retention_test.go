@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/storage/postgres"
+)
+
+type fakeRetentionStore struct {
+	guilds    map[string]postgres.RetentionCutoffs
+	except    []string
+	defaults  postgres.RetentionCutoffs
+	failGuild string
+}
+
+func (f *fakeRetentionStore) PruneGuildRetention(_ context.Context, guildID string, cutoffs postgres.RetentionCutoffs) (int64, error) {
+	if guildID == f.failGuild {
+		return 0, errors.New("boom")
+	}
+	if f.guilds == nil {
+		f.guilds = make(map[string]postgres.RetentionCutoffs)
+	}
+	f.guilds[guildID] = cutoffs
+	return 1, nil
+}
+
+func (f *fakeRetentionStore) PruneRetentionExcept(_ context.Context, guildIDs []string, cutoffs postgres.RetentionCutoffs) (int64, error) {
+	f.except = guildIDs
+	f.defaults = cutoffs
+	return 2, nil
+}
+
+func TestEnforceRetention(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 6, 1, 4, 0, 0, 0, time.UTC)
+	cfg := &files.BotConfig{Guilds: []files.GuildConfig{
+		{GuildID: "g1", Retention: files.RetentionConfig{MessageCacheDays: 30}},
+		{GuildID: "g2"},
+	}}
+
+	store := &fakeRetentionStore{}
+	if err := enforceRetention(context.Background(), store, cfg, now); err != nil {
+		t.Fatalf("enforceRetention: %v", err)
+	}
+	if got := store.guilds["g1"]; !got.MessageCache.Equal(now.AddDate(0, 0, -30)) || !got.LogEvents.Equal(now.AddDate(0, 0, -180)) {
+		t.Fatalf("expected g1 pruned at its own retention, got %+v", got)
+	}
+	if got := store.defaults; !got.MessageCache.Equal(now.AddDate(0, 0, -7)) || !got.AvatarHistory.Equal(now.AddDate(0, 0, -90)) {
+		t.Fatalf("expected other guilds pruned at the defaults, got %+v", got)
+	}
+	if len(store.except) != 2 {
+		t.Fatalf("expected the configured guilds excluded from the default sweep, got %v", store.except)
+	}
+
+	store = &fakeRetentionStore{failGuild: "g1"}
+	if err := enforceRetention(context.Background(), store, cfg, now); err == nil {
+		t.Fatal("expected a failed guild to be reported")
+	}
+	if _, ok := store.guilds["g2"]; !ok || store.except == nil {
+		t.Fatal("expected the other guilds pruned despite the failure")
+	}
+}
@@ -0,0 +1,144 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+)
+
+// roleAuditLookback bounds how long before a role update its audit log entry
+// may have been written and still be credited with it.
+const roleAuditLookback = 30 * time.Second
+
+// roleAuditLog is the part of *api.Client roleTracker asks who changed a
+// role through.
+type roleAuditLog interface {
+	AuditLog(guildID discord.GuildID, data api.AuditLogData) (*discord.AuditLog, error)
+}
+
+// roleCabinet is the part of the state cabinet roleTracker reads a role
+// from before an update lands. store.Cabinet satisfies it.
+type roleCabinet interface {
+	Role(guildID discord.GuildID, roleID discord.RoleID) (*discord.Role, error)
+}
+
+// roleTracker reports changes to the name, color, icon, emoji, permissions,
+// hoisting and mentionability of roles to the sink. It reads the old role
+// from the cabinet in a pre-handler, before the state applies the update.
+type roleTracker struct {
+	instanceID    string
+	sink          members.RoleSettingsSink
+	audit         roleAuditLog
+	configManager *files.ConfigManager
+	cabinet       roleCabinet
+	now           func() time.Time
+}
+
+func newRoleTracker(instanceID string, sink members.RoleSettingsSink, audit roleAuditLog, configManager *files.ConfigManager) *roleTracker {
+	return &roleTracker{
+		instanceID:    instanceID,
+		sink:          sink,
+		audit:         audit,
+		configManager: configManager,
+		now:           time.Now,
+	}
+}
+
+func (t *roleTracker) attach(st *state.State) {
+	t.cabinet = st.Cabinet
+	st.PreHandler.AddSyncHandler(perf.GuardGatewayHandler("roles.role_update", func(e *gateway.GuildRoleUpdateEvent) {
+		if intent, ok := t.roleChange(e); ok {
+			// The audit log lookup must not hold up the gateway, which
+			// waits on pre-handlers.
+			go t.report(e.GuildID, e.Role.ID, intent)
+		}
+	}))
+}
+
+// roleChange compares the role in e with the cabinet's copy. A role missing
+// from the cabinet has nothing to compare against.
+func (t *roleTracker) roleChange(e *gateway.GuildRoleUpdateEvent) (members.RoleSettingsIntent, bool) {
+	if e == nil || t.cabinet == nil || !t.logs(e.GuildID.String()) {
+		return members.RoleSettingsIntent{}, false
+	}
+	old, err := t.cabinet.Role(e.GuildID, e.Role.ID)
+	if err != nil || old == nil {
+		return members.RoleSettingsIntent{}, false
+	}
+	before, after := roleSettings(*old), roleSettings(e.Role)
+	if before == after {
+		return members.RoleSettingsIntent{}, false
+	}
+	return members.RoleSettingsIntent{
+		GuildID: e.GuildID.String(),
+		RoleID:  e.Role.ID.String(),
+		Name:    e.Role.Name,
+		Before:  before,
+		After:   after,
+	}, true
+}
+
+// report credits intent to whoever the audit log names and hands it to the
+// sink.
+func (t *roleTracker) report(guildID discord.GuildID, roleID discord.RoleID, intent members.RoleSettingsIntent) {
+	intent.ActorID = t.resolveActor(guildID, roleID)
+	t.sink.OnRoleSettingsChange(context.Background(), intent)
+}
+
+// resolveActor returns who last updated roleID, when the audit log has a
+// recent enough entry for it. It is best-effort: without View Audit Log the
+// change is still reported, just without an actor.
+func (t *roleTracker) resolveActor(guildID discord.GuildID, roleID discord.RoleID) string {
+	if t.audit == nil {
+		return ""
+	}
+	log, err := t.audit.AuditLog(guildID, api.AuditLogData{ActionType: discord.RoleUpdate, Limit: 5})
+	if err != nil {
+		slog.Debug("Operational telemetry: Could not read the audit log for a role update",
+			slog.String("botInstanceID", t.instanceID),
+			slog.String("guildID", guildID.String()),
+			slog.String("roleID", roleID.String()),
+			slog.String("error", err.Error()),
+		)
+		return ""
+	}
+	for _, entry := range log.Entries {
+		if entry.ActionType != discord.RoleUpdate || entry.TargetID != discord.Snowflake(roleID) {
+			continue
+		}
+		if t.now().Sub(entry.CreatedAt()) > roleAuditLookback || !entry.UserID.IsValid() {
+			return ""
+		}
+		return entry.UserID.String()
+	}
+	return ""
+}
+
+func (t *roleTracker) logs(guildID string) bool {
+	guild := t.configManager.GuildConfig(guildID)
+	if guild == nil {
+		return false
+	}
+	id, _ := files.ResolveFeatureBotInstanceID(*guild, "logging")
+	return id == t.instanceID
+}
+
+func roleSettings(role discord.Role) members.RoleSettings {
+	return members.RoleSettings{
+		Name:        role.Name,
+		Color:       int(role.Color),
+		IconURL:     role.IconURL(),
+		Emoji:       role.UnicodeEmoji,
+		Permissions: uint64(role.Permissions),
+		Hoist:       role.Hoist,
+		Mentionable: role.Mentionable,
+	}
+}
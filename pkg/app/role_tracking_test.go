@@ -0,0 +1,126 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+)
+
+type recordingRoleSink struct {
+	events []members.RoleSettingsIntent
+}
+
+func (s *recordingRoleSink) OnRoleSettingsChange(_ context.Context, intent members.RoleSettingsIntent) {
+	s.events = append(s.events, intent)
+}
+
+type fakeRoleAuditLog struct {
+	entries []discord.AuditLogEntry
+	err     error
+}
+
+func (a *fakeRoleAuditLog) AuditLog(discord.GuildID, api.AuditLogData) (*discord.AuditLog, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	return &discord.AuditLog{Entries: a.entries}, nil
+}
+
+// fakeRoleCabinet holds roles as the state cabinet does before an update.
+type fakeRoleCabinet map[discord.RoleID]discord.Role
+
+func (c fakeRoleCabinet) Role(_ discord.GuildID, roleID discord.RoleID) (*discord.Role, error) {
+	role, ok := c[roleID]
+	if !ok {
+		return nil, errors.New("role not found")
+	}
+	return &role, nil
+}
+
+func TestRoleTracker(t *testing.T) {
+	t.Parallel()
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{{GuildID: "1"}}})
+	now := time.Now()
+	audit := &fakeRoleAuditLog{entries: []discord.AuditLogEntry{
+		{ID: discord.AuditLogEntryID(discord.NewSnowflake(now)), TargetID: 20, UserID: 9, ActionType: discord.RoleUpdate},
+		{ID: discord.AuditLogEntryID(discord.NewSnowflake(now)), TargetID: 10, UserID: 7, ActionType: discord.RoleUpdate},
+	}}
+	sink := &recordingRoleSink{}
+	cabinet := fakeRoleCabinet{}
+	tracker := newRoleTracker("", sink, audit, cfgMgr)
+	tracker.cabinet = cabinet
+	tracker.now = func() time.Time { return now }
+
+	// update plays a role update the way the pre-handler sees it: against
+	// the cabinet's copy, which the state then replaces.
+	update := func(guildID discord.GuildID, role discord.Role) {
+		t.Helper()
+		if intent, ok := tracker.roleChange(&gateway.GuildRoleUpdateEvent{GuildID: guildID, Role: role}); ok {
+			tracker.report(guildID, role.ID, intent)
+		}
+		cabinet[role.ID] = role
+	}
+
+	role := discord.Role{ID: 10, Name: "Mods", Color: 0x112233}
+	cabinet[role.ID] = role
+
+	// Updates that leave the settings alone are not reported.
+	update(1, role)
+	if len(sink.events) != 0 {
+		t.Fatalf("expected no report for an unchanged role, got %+v", sink.events)
+	}
+
+	renamed := role
+	renamed.Name = "Moderators"
+	update(1, renamed)
+	if len(sink.events) != 1 || sink.events[0].Before.Name != "Mods" || sink.events[0].After.Name != "Moderators" {
+		t.Fatalf("expected a report for the rename, got %+v", sink.events)
+	}
+
+	recolored := renamed
+	recolored.Color = 0x445566
+	recolored.UnicodeEmoji = "🛡️"
+	recolored.Permissions = discord.PermissionBanMembers
+	recolored.Hoist = true
+	update(1, recolored)
+	if len(sink.events) != 2 {
+		t.Fatalf("expected two reports, got %+v", sink.events)
+	}
+	got := sink.events[1]
+	if got.RoleID != "10" || got.Name != "Moderators" || got.ActorID != "7" {
+		t.Fatalf("unexpected report %+v", got)
+	}
+	if got.Before.Color != 0x112233 || got.After.Color != 0x445566 || got.After.Emoji != "🛡️" ||
+		got.Before.Permissions != 0 || got.After.Permissions != uint64(discord.PermissionBanMembers) || !got.After.Hoist {
+		t.Fatalf("unexpected settings %+v -> %+v", got.Before, got.After)
+	}
+
+	// A stale audit entry is not credited, and a failing audit log still
+	// reports the change.
+	tracker.now = func() time.Time { return now.Add(time.Minute) }
+	recolored.Color = 0x778899
+	update(1, recolored)
+	audit.err = errors.New("missing access")
+	recolored.Color = 0
+	update(1, recolored)
+	if len(sink.events) != 4 || sink.events[2].ActorID != "" || sink.events[3].ActorID != "" {
+		t.Fatalf("expected reports without an actor, got %+v", sink.events)
+	}
+
+	// Roles missing from the cabinet and unlogged guilds are ignored.
+	update(1, discord.Role{ID: 11, Color: 1})
+	cabinet[30] = discord.Role{ID: 30}
+	update(2, discord.Role{ID: 30, Color: 1})
+	if len(sink.events) != 4 {
+		t.Fatalf("expected no further reports, got %+v", sink.events)
+	}
+}
@@ -13,18 +13,23 @@ import (
 	"time"
 
 	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/small-frappuccino/discordcore/pkg/clock"
 	"github.com/small-frappuccino/discordcore/pkg/config"
 	"github.com/small-frappuccino/discordcore/pkg/control"
 	"github.com/small-frappuccino/discordcore/pkg/discord/cache"
 	"github.com/small-frappuccino/discordcore/pkg/discord/commands/moderation"
 	"github.com/small-frappuccino/discordcore/pkg/discord/embeds"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
 	"github.com/small-frappuccino/discordcore/pkg/discord/partners"
 	discordqotd "github.com/small-frappuccino/discordcore/pkg/discord/qotd"
 	"github.com/small-frappuccino/discordcore/pkg/discord/roles"
 	"github.com/small-frappuccino/discordcore/pkg/files"
 	"github.com/small-frappuccino/discordcore/pkg/idgen"
+	"github.com/small-frappuccino/discordcore/pkg/keylock"
+	"github.com/small-frappuccino/discordcore/pkg/leader"
 	"github.com/small-frappuccino/discordcore/pkg/log"
 	"github.com/small-frappuccino/discordcore/pkg/members"
 	"github.com/small-frappuccino/discordcore/pkg/messages"
@@ -33,6 +38,7 @@ import (
 	"github.com/small-frappuccino/discordcore/pkg/runtimeapply"
 	"github.com/small-frappuccino/discordcore/pkg/service"
 	"github.com/small-frappuccino/discordcore/pkg/storage/postgres"
+	"github.com/small-frappuccino/discordcore/pkg/sys"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -47,8 +53,12 @@ const (
 	controlDiscordOAuthSessionStorePathEnv        = "DISCORDCORE_CONTROL_DISCORD_OAUTH_SESSION_STORE_PATH"
 	controlTLSCertFileEnv                         = "DISCORDCORE_CONTROL_TLS_CERT_FILE"
 	controlTLSKeyFileEnv                          = "DISCORDCORE_CONTROL_TLS_KEY_FILE"
+	readOnlyEnv                                   = "DISCORDCORE_READ_ONLY"
 )
 
+// ErrRuntimePanic marks a run that ended because of an unhandled panic.
+var ErrRuntimePanic = stdErrors.New("panic recovered during runtime")
+
 // App encapsulates the state of the initializing application process, providing
 // a testable, instance-based context tree instead of procedural global variables.
 type App struct {
@@ -64,6 +74,7 @@ type App struct {
 	botSupervisor         *BotSupervisor
 	runtimeResolver       *botRuntimeResolver
 	runtimeApplier        *runtimeapply.Manager
+	elector               *leader.Elector
 
 	qotdService       *qotd.Service
 	moderationMetrics *moderation.InMemoryMetrics
@@ -71,6 +82,7 @@ type App struct {
 	messagesMetrics   *messages.InMemoryMetrics
 
 	cleanupCancel context.CancelFunc
+	startedAt     time.Time
 }
 
 // NewApp allocates the initial structural foundations for a bot runtime pipeline.
@@ -87,12 +99,22 @@ func NewApp(appName string, opts RunOptions) *App {
 	if opts.ShutdownDelay == 0 {
 		opts.ShutdownDelay = 100 * time.Millisecond
 	}
+	if opts.Entitlements != nil {
+		files.SetEntitlementProvider(opts.Entitlements)
+	}
+	if files.EnvBool(readOnlyEnv) {
+		opts.ReadOnly = true
+	}
+	if files.EnvBool(leaderElectionEnv) {
+		opts.LeaderElection = true
+	}
 
 	return &App{
 		appName:        appName,
 		opts:           opts,
 		serviceManager: service.NewServiceManager(opts.Logger),
 		logger:         opts.Logger,
+		startedAt:      time.Now(),
 	}
 }
 
@@ -109,7 +131,7 @@ func RunWithOptions(appName string, opts RunOptions) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			// Unmanaged panic requires aggressive interruption and memory dump.
-			errWrap := fmt.Errorf("panic recovered during runtime: %v", r)
+			errWrap := fmt.Errorf("%w: %v", ErrRuntimePanic, r)
 			log.EmitBlockingError("Critical pipeline failure: Unhandled panic intercepted", errWrap, log.GenerateRequestID())
 			notifyLifecycleEvent("fatal", errWrap.Error())
 			err = errWrap
@@ -159,7 +181,9 @@ func (a *App) Boot(ctx context.Context) error {
 		}
 
 		controlBearerToken := strings.TrimSpace(files.EnvString(controlBearerTokenEnv, ""))
-		scheduleStartupWebhookEmbedUpdates(a.startupTasks, a.configManager.Config(), a.runtimeResolver)
+		if !a.opts.ReadOnly {
+			scheduleStartupWebhookEmbedUpdates(a.startupTasks, a.configManager.Config(), a.runtimeResolver)
+		}
 		if !a.opts.DisableControl {
 			controlRuntime, err := resolveControlRuntime(egCtx, a.opts)
 			if err != nil {
@@ -278,8 +302,11 @@ func (a *App) InitializeIO(ctx context.Context) error {
 	if err := files.EnsureCacheDirs(); err != nil {
 		return fmt.Errorf("create cache directories: %w", err)
 	}
+	if err := files.CurrentDataPaths().Ensure(); err != nil {
+		return fmt.Errorf("create data directories: %w", err)
+	}
 
-	store, configManager, err := setupStorage(databaseBootstrap)
+	store, configManager, err := setupStorage(databaseBootstrap, a.opts.ReadOnly)
 	if err != nil {
 		return fmt.Errorf("InitializeIO setupStorage: %w", err)
 	}
@@ -288,8 +315,18 @@ func (a *App) InitializeIO(ctx context.Context) error {
 
 	applyConfiguredTheme(a.configManager)
 
+	if err := a.acquireLeadership(); err != nil {
+		return fmt.Errorf("InitializeIO: %w", err)
+	}
+
 	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
-	scheduleDBCleanup(cleanupCtx, a.store, a.configManager)
+	if a.opts.ReadOnly {
+		slog.Info("Architectural state bypass: Read-only instance skips database cleanup and error journal flushes")
+	} else {
+		scheduleDBCleanup(cleanupCtx, a.store, a.configManager)
+		scheduleErrorJournalFlush(cleanupCtx, a.store)
+		scheduleRetention(cleanupCtx, a.store, a.configManager)
+	}
 	a.cleanupCancel = cleanupCancel
 
 	return nil
@@ -354,6 +391,10 @@ func (a *App) ConstructServices(ctx context.Context) error {
 	embedService := embeds.NewEmbedService(a.configManager)
 	rolePanelService := roles.NewRolePanelService(a.configManager)
 	partnerService := partners.NewPartnerService(a.configManager)
+	cleanLocks := a.opts.CleanChannelLocks
+	if cleanLocks == nil {
+		cleanLocks = new(keylock.Mutex[discord.ChannelID])
+	}
 
 	botOpts := botRuntimeOptions{
 		runtimeCount:          runtimeCount,
@@ -373,10 +414,17 @@ func (a *App) ConstructServices(ctx context.Context) error {
 		embedService:          embedService,
 		rolePanelService:      rolePanelService,
 		partnerService:        partnerService,
+		readOnly:              a.opts.ReadOnly,
+		cleanLocks:            cleanLocks,
+		moderationService:     a.opts.ModerationService,
 	}
 
 	a.botSupervisor = NewBotSupervisor(a.configManager, botOpts)
 	qotdService.SetPublisher(discordqotd.NewPublisherRouter(qotdClientResolver{resolver: a.botSupervisor.GetResolver()}))
+	if a.opts.ModerationService != nil {
+		src := moderationContextSource{resolver: a.botSupervisor.GetResolver()}
+		a.opts.ModerationService.WithGuildContexts(discordmod.NewGuildContextCache(src, 0).WithBatchResolver(src))
+	}
 	a.configManager.AddSubscriber(a.botSupervisor.onConfigChanged)
 
 	a.botSupervisor.SetFatalCallback(func(err error) {
@@ -423,8 +471,19 @@ func (a *App) RunAndListen(ctx context.Context) error {
 	signal.Notify(sigHupCh, syscall.SIGHUP)
 	defer signal.Stop(sigHupCh)
 
+	// SIGUSR1 is absent on Windows; a nil channel simply never fires there.
+	var statsCh chan os.Signal
+	if statsSignals := sys.StatsDumpSignals(); len(statsSignals) > 0 {
+		statsCh = make(chan os.Signal, 1)
+		signal.Notify(statsCh, statsSignals...)
+		defer signal.Stop(statsCh)
+	}
+
 	eg, egCtx := errgroup.WithContext(rootCtx)
 
+	// Boot has completed by the time we listen, so the daemon is ready to serve.
+	a.notifyServiceManager(sdNotifyReady)
+
 	// Phase 2: SIGHUP Valve & Serialized Mutation Pipeline
 	// Dedicated resident worker executing continuous state routing with highly efficient resource utilization.
 	eg.Go(func() error {
@@ -432,42 +491,11 @@ func (a *App) RunAndListen(ctx context.Context) error {
 			select {
 			case <-egCtx.Done():
 				return nil
+			case <-statsCh:
+				a.dumpRuntimeStats()
 			case <-sigHupCh:
 				a.logger.Debug("Dynamic instruction intercepted: Emitting non-blocking intent trigger for configuration layer reload")
-
-				// Serialized mutation governed by CSP and bounded by strict timeout.
-				mutCtx, mutCancel := context.WithTimeout(egCtx, 30*time.Second)
-
-				newCfg, needsSave, err := a.configManager.LoadConfigFromStore()
-				if err != nil {
-					slog.Warn("Mitigated service degradation: Live configuration mutation failed; enforcing active baseline",
-						slog.String("error", err.Error()),
-					)
-					mutCancel()
-					continue
-				}
-
-				if mutCtx.Err() != nil {
-					mutCancel()
-					continue
-				}
-
-				dupCount := a.configManager.ApplyConfig(newCfg)
-
-				if dupCount == 0 && !needsSave {
-					slog.Info("Architectural state transition: Configuration topology refreshed directly from disk")
-					mutCancel()
-					continue
-				}
-
-				if saveErr := a.configManager.SaveConfig(); saveErr != nil {
-					log.EmitBlockingError("Structural state failure: Volatile configuration drift blocks persistence flush", saveErr, log.GenerateRequestID())
-				} else {
-					slog.Info("Architectural state transition: Configuration topology updated and indexes rebuilt",
-						slog.Int("duplicates_purged", dupCount),
-					)
-				}
-				mutCancel()
+				a.reloadConfigFromSignal(egCtx)
 			}
 		}
 	})
@@ -489,6 +517,7 @@ func (a *App) RunAndListen(ctx context.Context) error {
 		select {
 		case <-signalCtx.Done():
 			a.logger.Info("Architectural state transition: Process termination signal acknowledged. Initiating graceful teardown.")
+			a.notifyServiceManager(sdNotifyStopping)
 			rootCancel()
 			// Unblock a.serviceManager.Wait() dynamically by initiating the graceful stop sequence
 			return a.serviceManager.StopAll(context.Background())
@@ -537,6 +566,8 @@ func (a *App) Teardown(originalErr error) error {
 		err := a.serviceManager.StopAll(context.Background())
 		a.serviceManager.Wait() // Unconditional execution guarantees cleanup of zombie processes.
 
+		a.releaseLeadership()
+
 		if err != nil {
 			errWrap := fmt.Errorf("shutdown: %w", err)
 			log.EmitBlockingError("Structural teardown failure: Zombie sub-processes detected during stop iteration", errWrap, log.GenerateRequestID())
@@ -667,7 +698,10 @@ func formatStartupMessage(appName, appVersion, coreVersion string) string {
 	return msg + fmt.Sprintf(" (discordcore %s)...", coreVersion)
 }
 
-func setupStorage(dbb resolvedDatabaseBootstrap) (*postgres.Store, *files.ConfigManager, error) {
+// setupStorage opens the database and loads the configuration. A read-only
+// setup expects a schema that is already current: it verifies instead of
+// migrating and never writes the bootstrap database settings back.
+func setupStorage(dbb resolvedDatabaseBootstrap, readOnly bool) (*postgres.Store, *files.ConfigManager, error) {
 	dbCfg := dbb.Config
 	dbc := persistence.Config{
 		Driver:              dbCfg.Driver,
@@ -701,24 +735,34 @@ func setupStorage(dbb resolvedDatabaseBootstrap) (*postgres.Store, *files.Config
 		slog.String("driver", "postgres"),
 	)
 
-	migrateCtx, migrateCancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer migrateCancel()
-	migrator := persistence.NewPostgresMigrator(db)
-	if err := migrator.Up(migrateCtx); err != nil {
-		db.Close()
-		return nil, nil, fmt.Errorf("apply postgres migrations: %w", err)
+	if readOnly {
+		slog.Info("Architectural state bypass: Read-only instance leaves schema migrations to the primary",
+			slog.String("operation", "startup.database.migrate"),
+		)
+	} else {
+		migrateCtx, migrateCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer migrateCancel()
+		migrator := persistence.NewPostgresMigrator(db)
+		if err := migrator.Up(migrateCtx); err != nil {
+			db.Close()
+			return nil, nil, fmt.Errorf("apply postgres migrations: %w", err)
+		}
+		slog.Info("Architectural state transition: Schema schema deltas propagated successfully",
+			slog.String("operation", "startup.database.migrate"),
+			slog.String("driver", "postgres"),
+		)
 	}
-	slog.Info("Architectural state transition: Schema schema deltas propagated successfully",
-		slog.String("operation", "startup.database.migrate"),
-		slog.String("driver", "postgres"),
-	)
 
 	store, err := postgres.NewStore(db, slog.Default())
 	if err != nil {
 		db.Close()
 		return nil, nil, fmt.Errorf("create postgres store: %w", err)
 	}
-	if err := store.Init(); err != nil {
+	initStore := store.Init
+	if readOnly {
+		initStore = store.Verify
+	}
+	if err := initStore(); err != nil {
 		db.Close()
 		return nil, nil, fmt.Errorf("initialize postgres store: %w", err)
 	}
@@ -727,12 +771,16 @@ func setupStorage(dbb resolvedDatabaseBootstrap) (*postgres.Store, *files.Config
 		slog.String("driver", "postgres"),
 	)
 
-	configStore := config.NewPostgresConfigStore(db, config.DefaultPostgresConfigStoreKey, slog.Default())
+	configStore := resolveConfigStore(db)
 	configManager := files.NewConfigManagerWithStore(configStore, slog.Default())
+	configManager.SetReadOnly(readOnly)
 
 	slog.Debug("Executing cross-boundary extraction for master configuration tree")
 	if err := configManager.LoadConfig(); err != nil {
-		return nil, nil, fmt.Errorf("load config from postgres: %w", err)
+		return nil, nil, fmt.Errorf("load config from %s: %w", configStore.Describe(), err)
+	}
+	if readOnly {
+		return store, configManager, nil
 	}
 	if err := syncBootstrapDatabaseConfig(configManager, dbCfg); err != nil {
 		return nil, nil, fmt.Errorf("sync runtime database bootstrap config: %w", err)
@@ -741,6 +789,29 @@ func setupStorage(dbb resolvedDatabaseBootstrap) (*postgres.Store, *files.Config
 	return store, configManager, nil
 }
 
+// resolveConfigStore selects the configuration backend. Postgres is canonical; setting
+// DISCORDCORE_CONFIG_FILE switches to a local settings file with rotated backups. The
+// file format follows its extension unless DISCORDCORE_CONFIG_FORMAT names one.
+func resolveConfigStore(db *pgxpool.Pool) files.ConfigStore {
+	if path := files.EnvString(configFileEnv, ""); path != "" {
+		backups := int(files.EnvInt64(configFileBackupsEnv, config.DefaultFileConfigStoreBackups))
+		store := config.NewFileConfigStore(path, backups, slog.Default())
+		if name := files.EnvString(configFileFormatEnv, ""); name != "" {
+			format, err := config.ParseFormat(name)
+			if err != nil {
+				slog.Warn("Architectural state bypass: Ignoring unrecognized configuration file format override",
+					slog.String("format", name),
+					slog.String("detected", string(store.Format())),
+				)
+				return store
+			}
+			store.WithFormat(format)
+		}
+		return store
+	}
+	return config.NewPostgresConfigStore(db, config.DefaultPostgresConfigStoreKey, slog.Default())
+}
+
 type qotdClientResolver struct {
 	resolver *botRuntimeResolver
 }
@@ -760,3 +831,62 @@ func (r qotdClientResolver) ArikawaClientForGuild(guildID string) (*api.Client,
 
 	return state.Session.Client, nil
 }
+
+// moderationContextSource reads the guild contexts of moderation commands
+// through the bot that serves moderation in each guild.
+type moderationContextSource struct {
+	resolver *botRuntimeResolver
+}
+
+func (s moderationContextSource) state(guildID discord.GuildID) (*state.State, error) {
+	st, err := s.resolver.arikawaStateForGuild(guildID.String(), "moderation")
+	if err != nil {
+		return nil, fmt.Errorf("resolve arikawa state for guild %s: %w", guildID, err)
+	}
+	if st == nil {
+		return nil, fmt.Errorf("arikawa state evaluates to nil for guild %s", guildID)
+	}
+	return st, nil
+}
+
+func (s moderationContextSource) Guild(guildID discord.GuildID) (*discord.Guild, error) {
+	st, err := s.state(guildID)
+	if err != nil {
+		return nil, err
+	}
+	return st.Guild(guildID)
+}
+
+func (s moderationContextSource) Member(guildID discord.GuildID, userID discord.UserID) (*discord.Member, error) {
+	st, err := s.state(guildID)
+	if err != nil {
+		return nil, err
+	}
+	return st.Member(guildID, userID)
+}
+
+// ResolveMembers fulfills discordmod.MemberBatchResolver through the bot that
+// serves moderation in guildID.
+func (s moderationContextSource) ResolveMembers(ctx context.Context, guildID string, ids []string) (map[string]*discord.Member, error) {
+	runtime, _, err := s.resolver.runtimeForGuild(guildID, "moderation")
+	if err != nil {
+		return nil, fmt.Errorf("resolve runtime for guild %s: %w", guildID, err)
+	}
+	if runtime.arikawaState == nil {
+		return nil, fmt.Errorf("arikawa state evaluates to nil for guild %s", guildID)
+	}
+	return runtime.memberBatchResolver().ResolveMembers(ctx, guildID, ids)
+}
+
+// Me is never called: GuildBot names the bot of each guild.
+func (s moderationContextSource) Me() (*discord.User, error) {
+	return nil, stdErrors.New("moderation context source: the bot depends on the guild")
+}
+
+func (s moderationContextSource) GuildBot(guildID discord.GuildID) (*discord.User, error) {
+	st, err := s.state(guildID)
+	if err != nil {
+		return nil, err
+	}
+	return st.Me()
+}
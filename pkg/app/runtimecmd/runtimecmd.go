@@ -1,12 +1,15 @@
 package runtimecmd
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log/slog"
+	"os"
 
 	discordcoreapp "github.com/small-frappuccino/discordcore/pkg/app"
+	"github.com/small-frappuccino/discordcore/pkg/files"
 )
 
 // MainRuntimeAppName is the canonical identifier for the primary Discord bot process.
@@ -14,28 +17,88 @@ const (
 	MainRuntimeAppName = "discordmain"
 )
 
+// Process exit codes, following the BSD sysexits convention so service
+// managers can tell a misconfiguration (which restarting will not fix) apart
+// from a runtime failure.
+const (
+	ExitOK       = 0
+	ExitFailure  = 1
+	ExitUsage    = 64 // EX_USAGE: invalid command-line arguments
+	ExitSoftware = 70 // EX_SOFTWARE: unhandled panic
+	ExitConfig   = 78 // EX_CONFIG: configuration failed validation
+)
+
+// ErrUsage marks command-line parsing failures.
+var ErrUsage = errors.New("invalid command-line usage")
+
+// ExitCode maps the error returned by Run to a process exit status.
+func ExitCode(err error) int {
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.Is(err, ErrUsage):
+		return ExitUsage
+	case errors.Is(err, discordcoreapp.ErrRuntimePanic):
+		return ExitSoftware
+	case files.IsValidationError(err), errors.Is(err, ErrSetupRequired):
+		return ExitConfig
+	default:
+		return ExitFailure
+	}
+}
+
 // Spec describes a runtime entrypoint command: its name, and a factory that
-// builds the RunOptions.
+// builds the RunOptions. FirstRunSetup enables the interactive setup wizard
+// when the process is started without the settings it needs.
 type Spec struct {
 	CommandName     string
 	RuntimeAppName  string
 	BuildRunOptions func() discordcoreapp.RunOptions
+	FirstRunSetup   bool
 }
 
 // Runner starts a runtime app with the resolved name and options.
 // It is the injection seam that lets Run be tested without a live runtime.
 type Runner func(appName string, opts discordcoreapp.RunOptions) error
 
-// Run parses CLI flags, attempts to load a local .env file from the system PATH,
-// and invokes the provided runner with the resolved execution options.
+// Run parses CLI flags, applies -data-dir, loads the .env file written by the
+// setup wizard from the application support directory, runs the wizard when
+// the spec enables it and settings are missing, and invokes the provided
+// runner with the resolved execution options, marked read-only by -read-only.
 func Run(args []string, output io.Writer, spec Spec, runner Runner) error {
 	fs := flag.NewFlagSet(spec.CommandName, flag.ContinueOnError)
 	fs.SetOutput(output)
+	forceSetup := fs.Bool("setup", false, "run the interactive first-run setup before starting")
+	dataDir := fs.String("data-dir", "", "keep configuration, data, cache and logs under this directory instead of the platform defaults")
+	readOnly := fs.Bool("read-only", false, "run as a read-only standby: no migrations, configuration writes or state-changing commands")
 	if err := fs.Parse(args); err != nil {
-		return fmt.Errorf("Run: %w", err)
+		if errors.Is(err, flag.ErrHelp) {
+			return fmt.Errorf("Run: %w", err)
+		}
+		return fmt.Errorf("Run: %w: %w", ErrUsage, err)
+	}
+	if *dataDir != "" {
+		files.SetDataDir(*dataDir)
 	}
 
-	if err := runner(spec.RuntimeAppName, spec.BuildRunOptions()); err != nil {
+	if spec.FirstRunSetup {
+		envPath := setupEnvPath()
+		loadSetupEnv(envPath)
+		if *forceSetup || setupRequired(os.Getenv) {
+			if !stdinIsTerminal() {
+				return fmt.Errorf("Run: %w", ErrSetupRequired)
+			}
+			if err := runSetup(os.Stdin, output, envPath); err != nil {
+				return fmt.Errorf("Run: %w", err)
+			}
+		}
+	}
+
+	opts := spec.BuildRunOptions()
+	if *readOnly {
+		opts.ReadOnly = true
+	}
+	if err := runner(spec.RuntimeAppName, opts); err != nil {
 		slog.Error("Runner execution failed", slog.String("app_name", spec.RuntimeAppName), slog.Any("error", err))
 
 		return err
@@ -49,6 +112,7 @@ func MainSpec(commandName string) Spec {
 		CommandName:     commandName,
 		RuntimeAppName:  MainRuntimeAppName,
 		BuildRunOptions: buildMainRunOptions,
+		FirstRunSetup:   true,
 	}
 }
 
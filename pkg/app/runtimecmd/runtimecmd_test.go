@@ -0,0 +1,54 @@
+package runtimecmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	discordcoreapp "github.com/small-frappuccino/discordcore/pkg/app"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	noopRunner := func(string, discordcoreapp.RunOptions) error { return nil }
+	spec := Spec{CommandName: "test", BuildRunOptions: func() discordcoreapp.RunOptions { return discordcoreapp.RunOptions{} }}
+
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"help", Run([]string{"-h"}, io.Discard, spec, noopRunner), ExitOK},
+		{"usage", Run([]string{"-no-such-flag"}, io.Discard, spec, noopRunner), ExitUsage},
+		{"panic", fmt.Errorf("%w: boom", discordcoreapp.ErrRuntimePanic), ExitSoftware},
+		{"config", fmt.Errorf("load: %w", files.NewValidationError("guilds[0].profile", "x", "unknown")), ExitConfig},
+		{"other", errors.New("boom"), ExitFailure},
+	}
+	for _, tc := range cases {
+		if got := ExitCode(tc.err); got != tc.want {
+			t.Errorf("%s: ExitCode(%v) = %d, want %d", tc.name, tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestRunReadOnlyFlag(t *testing.T) {
+	t.Parallel()
+
+	spec := Spec{CommandName: "test", BuildRunOptions: func() discordcoreapp.RunOptions { return discordcoreapp.RunOptions{} }}
+	var got discordcoreapp.RunOptions
+	runner := func(_ string, opts discordcoreapp.RunOptions) error {
+		got = opts
+		return nil
+	}
+
+	if err := Run([]string{"-read-only"}, io.Discard, spec, runner); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !got.ReadOnly {
+		t.Fatal("expected -read-only to reach the run options")
+	}
+}
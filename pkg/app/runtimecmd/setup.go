@@ -0,0 +1,241 @@
+package runtimecmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// Environment variables the setup wizard writes. They mirror the names read
+// by pkg/app at startup.
+const (
+	databaseURLEnv = "DISCORDCORE_DATABASE_URL"
+	configFileEnv  = "DISCORDCORE_CONFIG_FILE"
+)
+
+// setupBotInstanceID names the bot instance the wizard registers the token
+// under.
+const setupBotInstanceID = "main"
+
+// ErrSetupRequired reports that the process is not configured and cannot ask
+// for the missing settings because stdin is not a terminal.
+var ErrSetupRequired = errors.New("first-run setup required: set " + databaseURLEnv + " or run with -setup in a terminal")
+
+// setupEnvPath is the env file written by the wizard and loaded on every
+// start. It lives in the application support directory so it is found
+// without any configuration.
+func setupEnvPath() string {
+	return filepath.Join(files.ApplicationSupportPath, ".env")
+}
+
+// loadSetupEnv loads the wizard's env file, if any, without overriding
+// variables already set in the environment.
+func loadSetupEnv(path string) {
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		_ = godotenv.Load(path)
+	}
+}
+
+// setupRequired reports whether startup would fail for lack of settings the
+// wizard provides.
+func setupRequired(getenv func(string) string) bool {
+	if strings.TrimSpace(getenv(databaseURLEnv)) == "" {
+		return true
+	}
+	if path := strings.TrimSpace(getenv(configFileEnv)); path != "" {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return true
+		}
+	}
+	return false
+}
+
+// stdinIsTerminal reports whether the wizard can prompt interactively.
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// setupAnswers holds everything the wizard collects.
+type setupAnswers struct {
+	DataDir     string
+	DatabaseURL string
+	TokenEnv    string
+	Token       string
+	GuildID     string
+}
+
+// setupWizard prompts for the settings a first run needs.
+type setupWizard struct {
+	in     *bufio.Scanner
+	out    io.Writer
+	getenv func(string) string
+}
+
+func newSetupWizard(in io.Reader, out io.Writer, getenv func(string) string) *setupWizard {
+	return &setupWizard{in: bufio.NewScanner(in), out: out, getenv: getenv}
+}
+
+// ask prints prompt and reads one answer, offering def when the answer is
+// empty and repeating the question until validate accepts it.
+func (w *setupWizard) ask(prompt, def string, validate func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(w.out, "%s [%s]: ", prompt, def)
+		} else {
+			fmt.Fprintf(w.out, "%s: ", prompt)
+		}
+		if !w.in.Scan() {
+			if err := w.in.Err(); err != nil {
+				return "", fmt.Errorf("read answer: %w", err)
+			}
+			return "", io.ErrUnexpectedEOF
+		}
+		answer := strings.TrimSpace(w.in.Text())
+		if answer == "" {
+			answer = def
+		}
+		if err := validate(answer); err != nil {
+			fmt.Fprintf(w.out, "  %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+func (w *setupWizard) run(defaultDataDir string) (setupAnswers, error) {
+	fmt.Fprintln(w.out, "discordcore is not configured yet. Answer a few questions to create the initial settings.")
+
+	var a setupAnswers
+	var err error
+	if a.DataDir, err = w.ask("Data directory", defaultDataDir, validateDataDir); err != nil {
+		return setupAnswers{}, err
+	}
+	if a.DatabaseURL, err = w.ask("Postgres connection URL", w.getenv(databaseURLEnv), validateDatabaseURL); err != nil {
+		return setupAnswers{}, err
+	}
+	if a.TokenEnv, err = w.ask("Environment variable holding the bot token", files.TokenEnvNames[0], validateTokenEnv); err != nil {
+		return setupAnswers{}, err
+	}
+	if token := strings.TrimSpace(w.getenv(a.TokenEnv)); token != "" {
+		fmt.Fprintf(w.out, "Using the bot token from %s.\n", a.TokenEnv)
+		a.Token = token
+	} else if a.Token, err = w.ask("Bot token", "", validateToken); err != nil {
+		return setupAnswers{}, err
+	}
+	if a.GuildID, err = w.ask("ID of the first server the bot serves", "", validateSnowflake); err != nil {
+		return setupAnswers{}, err
+	}
+	return a, nil
+}
+
+func validateDataDir(v string) error {
+	if v == "" {
+		return errors.New("a data directory is required")
+	}
+	if !filepath.IsAbs(v) {
+		return errors.New("use an absolute path")
+	}
+	return nil
+}
+
+func validateDatabaseURL(v string) error {
+	if !strings.HasPrefix(v, "postgres://") && !strings.HasPrefix(v, "postgresql://") {
+		return errors.New("expected a postgres:// or postgresql:// URL")
+	}
+	return nil
+}
+
+func validateTokenEnv(v string) error {
+	if !slices.Contains(files.TokenEnvNames, v) {
+		return fmt.Errorf("choose one of %s; the token also keys config encryption", strings.Join(files.TokenEnvNames, ", "))
+	}
+	return nil
+}
+
+func validateToken(v string) error {
+	if v == "" || !strings.Contains(v, ".") {
+		return errors.New("that does not look like a bot token")
+	}
+	return nil
+}
+
+func validateSnowflake(v string) error {
+	if _, err := strconv.ParseUint(v, 10, 64); err != nil {
+		return errors.New("expected a numeric Discord ID")
+	}
+	return nil
+}
+
+// writeSetupScaffold writes the env file and, unless one already exists, the
+// settings file. The token environment variable must already hold a.Token so
+// the token is encrypted with the key later runs derive.
+func writeSetupScaffold(a setupAnswers, envPath string) (settingsPath string, err error) {
+	if err := os.MkdirAll(a.DataDir, 0o700); err != nil {
+		return "", fmt.Errorf("create data directory: %w", err)
+	}
+	settingsPath = filepath.Join(a.DataDir, "settings.json")
+
+	if _, statErr := os.Stat(settingsPath); errors.Is(statErr, os.ErrNotExist) {
+		cfg := files.BotConfig{
+			Guilds: []files.GuildConfig{{
+				GuildID:           a.GuildID,
+				BotInstanceTokens: map[string]files.EncryptedString{setupBotInstanceID: files.EncryptedString(a.Token)},
+			}},
+		}
+		data, err := json.MarshalIndent(cfg, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("encode settings: %w", err)
+		}
+		if err := os.WriteFile(settingsPath, data, 0o600); err != nil {
+			return "", fmt.Errorf("write settings: %w", err)
+		}
+	}
+
+	env, err := godotenv.Marshal(map[string]string{
+		databaseURLEnv: a.DatabaseURL,
+		configFileEnv:  settingsPath,
+		a.TokenEnv:     a.Token,
+	})
+	if err != nil {
+		return "", fmt.Errorf("encode env file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(envPath), 0o700); err != nil {
+		return "", fmt.Errorf("create env directory: %w", err)
+	}
+	if err := os.WriteFile(envPath, []byte(env+"\n"), 0o600); err != nil {
+		return "", fmt.Errorf("write env file: %w", err)
+	}
+	return settingsPath, nil
+}
+
+// runSetup runs the wizard, writes its scaffolding and applies the new env
+// file to the current process.
+func runSetup(in io.Reader, out io.Writer, envPath string) error {
+	answers, err := newSetupWizard(in, out, os.Getenv).run(files.ApplicationSupportPath)
+	if err != nil {
+		return fmt.Errorf("setup wizard: %w", err)
+	}
+	if err := os.Setenv(answers.TokenEnv, answers.Token); err != nil {
+		return fmt.Errorf("setup wizard: %w", err)
+	}
+	settingsPath, err := writeSetupScaffold(answers, envPath)
+	if err != nil {
+		return fmt.Errorf("setup wizard: %w", err)
+	}
+	if err := godotenv.Overload(envPath); err != nil {
+		return fmt.Errorf("setup wizard: %w", err)
+	}
+	fmt.Fprintf(out, "Wrote %s and %s. Owner-only commands follow the owner of the Discord application.\n", settingsPath, envPath)
+	return nil
+}
@@ -0,0 +1,163 @@
+package runtimecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joho/godotenv"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+func TestSetupWizardRetriesInvalidAnswers(t *testing.T) {
+	t.Parallel()
+
+	dataDir := filepath.Join(t.TempDir(), "data")
+	input := strings.Join([]string{
+		"",                   // accept default data directory
+		"mysql://nope",       // rejected
+		"postgres://u@h/db",  // accepted
+		"SOME_TOKEN",         // rejected
+		"",                   // default token env
+		"no-dots",            // rejected
+		"abc.def.ghi",        // token
+		"not-a-number",       // rejected
+		"123456789012345678", // guild
+	}, "\n") + "\n"
+
+	var out strings.Builder
+	getenv := func(string) string { return "" }
+	answers, err := newSetupWizard(strings.NewReader(input), &out, getenv).run(dataDir)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	want := setupAnswers{
+		DataDir:     dataDir,
+		DatabaseURL: "postgres://u@h/db",
+		TokenEnv:    files.TokenEnvNames[0],
+		Token:       "abc.def.ghi",
+		GuildID:     "123456789012345678",
+	}
+	if answers != want {
+		t.Fatalf("answers = %+v, want %+v", answers, want)
+	}
+	if got := strings.Count(out.String(), ":   "); got != 4 {
+		t.Fatalf("expected 4 validation messages, got %d:\n%s", got, out.String())
+	}
+}
+
+func TestSetupWizardUsesTokenFromEnvironment(t *testing.T) {
+	t.Parallel()
+
+	getenv := func(key string) string {
+		if key == "BOT_TOKEN" {
+			return "from.env.token"
+		}
+		return ""
+	}
+	input := "/srv/discordcore\npostgres://h/db\nBOT_TOKEN\n42\n"
+	answers, err := newSetupWizard(strings.NewReader(input), io.Discard, getenv).run("/unused")
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if answers.Token != "from.env.token" || answers.GuildID != "42" {
+		t.Fatalf("unexpected answers %+v", answers)
+	}
+}
+
+func TestSetupWizardStopsAtEndOfInput(t *testing.T) {
+	t.Parallel()
+
+	_, err := newSetupWizard(strings.NewReader(""), io.Discard, func(string) string { return "" }).run("/data")
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("err = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestWriteSetupScaffold(t *testing.T) {
+	t.Setenv("PASTEBIN_ENCRYPTION_KEY", "")
+	t.Setenv("DISCORDCORE_TOKEN", "abc.def.ghi")
+
+	dir := t.TempDir()
+	answers := setupAnswers{
+		DataDir:     filepath.Join(dir, "data"),
+		DatabaseURL: "postgres://u@h/db",
+		TokenEnv:    "DISCORDCORE_TOKEN",
+		Token:       "abc.def.ghi",
+		GuildID:     "42",
+	}
+	envPath := filepath.Join(dir, "env", ".env")
+
+	settingsPath, err := writeSetupScaffold(answers, envPath)
+	if err != nil {
+		t.Fatalf("writeSetupScaffold: %v", err)
+	}
+
+	env, err := godotenv.Read(envPath)
+	if err != nil {
+		t.Fatalf("read env: %v", err)
+	}
+	if env[databaseURLEnv] != answers.DatabaseURL || env[configFileEnv] != settingsPath || env["DISCORDCORE_TOKEN"] != answers.Token {
+		t.Fatalf("unexpected env file %v", env)
+	}
+
+	raw, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatalf("read settings: %v", err)
+	}
+	if strings.Contains(string(raw), answers.Token) {
+		t.Fatal("settings file stores the token in plain text")
+	}
+	var cfg files.BotConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		t.Fatalf("decode settings: %v", err)
+	}
+	if len(cfg.Guilds) != 1 || cfg.Guilds[0].GuildID != "42" {
+		t.Fatalf("unexpected guilds %+v", cfg.Guilds)
+	}
+	if got := cfg.Guilds[0].BotInstanceTokens[setupBotInstanceID]; string(got) != answers.Token {
+		t.Fatalf("token = %q, want %q", got, answers.Token)
+	}
+
+	// A second run keeps the existing settings file.
+	if err := os.WriteFile(settingsPath, []byte(`{"guilds":[]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writeSetupScaffold(answers, envPath); err != nil {
+		t.Fatalf("second writeSetupScaffold: %v", err)
+	}
+	if raw, _ := os.ReadFile(settingsPath); string(raw) != `{"guilds":[]}` {
+		t.Fatalf("settings overwritten: %s", raw)
+	}
+}
+
+func TestSetupRequired(t *testing.T) {
+	t.Parallel()
+
+	existing := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(existing, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := func(values map[string]string) func(string) string {
+		return func(k string) string { return values[k] }
+	}
+	cases := []struct {
+		name   string
+		values map[string]string
+		want   bool
+	}{
+		{"empty", nil, true},
+		{"database only", map[string]string{databaseURLEnv: "postgres://h/db"}, false},
+		{"missing config file", map[string]string{databaseURLEnv: "postgres://h/db", configFileEnv: existing + ".missing"}, true},
+		{"existing config file", map[string]string{databaseURLEnv: "postgres://h/db", configFileEnv: existing}, false},
+	}
+	for _, tc := range cases {
+		if got := setupRequired(env(tc.values)); got != tc.want {
+			t.Errorf("%s: setupRequired = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
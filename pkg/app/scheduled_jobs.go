@@ -0,0 +1,189 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/task"
+)
+
+// scheduledJobTick is how often jobs without a fixed time of day are
+// checked. The router's cron runs on its cleanup interval, so ticks land up
+// to that much later.
+const scheduledJobTick = time.Minute
+
+// jobClock persists when each scheduled job last ran, so a run missed while
+// the bot was down is caught up at boot. *postgres.Store satisfies it.
+type jobClock interface {
+	Metadata(ctx context.Context, key string) (time.Time, bool, error)
+	SetMetadata(ctx context.Context, key string, at time.Time) error
+}
+
+// scheduledJob is a periodic pass of a bot runtime, run on the runtime's
+// task router. A run is due once the first scheduled time after the
+// previous run has come; ticks that find the job not due do nothing, so a
+// monthly job can tick daily.
+type scheduledJob struct {
+	name       string
+	instanceID string
+	next       func(now time.Time) time.Time
+	pass       func(ctx context.Context)
+	clock      jobClock
+	now        func() time.Time
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func newScheduledJob(name, instanceID string, next func(time.Time) time.Time, pass func(context.Context), clock jobClock) *scheduledJob {
+	return &scheduledJob{
+		name:       name,
+		instanceID: instanceID,
+		next:       next,
+		pass:       pass,
+		clock:      clock,
+		now:        time.Now,
+	}
+}
+
+func (j *scheduledJob) taskType() string {
+	return "scheduled." + j.name
+}
+
+func (j *scheduledJob) metadataKey() string {
+	return "job_" + j.name + "_" + j.instanceID
+}
+
+// register installs the job on router, hands its task to schedule for the
+// recurring ticks, and dispatches a catch-up run right away when a
+// scheduled time passed since the last run.
+func (j *scheduledJob) register(ctx context.Context, router *task.TaskRouter, schedule func(task.Task)) {
+	router.RegisterHandler(j.taskType(), j.handle)
+	t := task.Task{
+		Type:    j.taskType(),
+		Payload: task.EmptyPayload{},
+		Options: task.TaskOptions{GroupKey: j.taskType()},
+	}
+	schedule(t)
+
+	j.load(ctx)
+	if !j.due() {
+		return
+	}
+	slog.Info("Architectural state transition: Catching up a scheduled job missed while offline",
+		slog.String("botInstanceID", j.instanceID),
+		slog.String("job", j.name),
+		slog.Time("lastRun", j.lastRun()),
+	)
+	if err := router.Dispatch(ctx, t); err != nil {
+		slog.Warn("Mitigated service degradation: Scheduled job catch-up not dispatched",
+			slog.String("botInstanceID", j.instanceID),
+			slog.String("job", j.name),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// load reads the last run from the clock. A job that never ran counts from
+// now, so a first boot does not run every job at once.
+func (j *scheduledJob) load(ctx context.Context) {
+	now := j.now()
+	last := now
+	if j.clock != nil {
+		at, ok, err := j.clock.Metadata(ctx, j.metadataKey())
+		switch {
+		case err != nil:
+			slog.Warn("Mitigated service degradation: Last run of a scheduled job unknown, missed runs are not caught up",
+				slog.String("botInstanceID", j.instanceID),
+				slog.String("job", j.name),
+				slog.String("error", err.Error()),
+			)
+		case ok:
+			last = at
+		default:
+			j.record(ctx, now)
+		}
+	}
+	j.mu.Lock()
+	j.last = last
+	j.mu.Unlock()
+}
+
+func (j *scheduledJob) lastRun() time.Time {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.last
+}
+
+func (j *scheduledJob) due() bool {
+	return !j.next(j.lastRun()).After(j.now())
+}
+
+// handle runs the pass when it is due and records the run.
+func (j *scheduledJob) handle(ctx context.Context, _ any) error {
+	if !j.due() {
+		return nil
+	}
+	started := j.now()
+	j.pass(ctx)
+	j.mu.Lock()
+	j.last = started
+	j.mu.Unlock()
+	j.record(ctx, started)
+	return nil
+}
+
+func (j *scheduledJob) record(ctx context.Context, at time.Time) {
+	if j.clock == nil {
+		return
+	}
+	if err := j.clock.SetMetadata(ctx, j.metadataKey(), at); err != nil {
+		slog.Warn("Mitigated service degradation: Last run of a scheduled job not recorded",
+			slog.String("botInstanceID", j.instanceID),
+			slog.String("job", j.name),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// scheduleJobs puts the periodic passes of r on its task router. The router
+// is closed at teardown, which stops them.
+func (r *botRuntime) scheduleJobs(ctx context.Context, clock jobClock) {
+	router := r.taskRouter
+	if router == nil {
+		return
+	}
+	daily := func(hour, minute int) func(task.Task) {
+		return func(t task.Task) { router.ScheduleDailyAtUTC(hour, minute, t) }
+	}
+	every := func(interval time.Duration) func(task.Task) {
+		return func(t task.Task) { router.ScheduleEvery(interval, t) }
+	}
+
+	if r.autoPurger != nil {
+		newScheduledJob("auto_purge", r.instanceID, nextAutoPurge, r.autoPurger.pass, clock).
+			register(ctx, router, daily(autoPurgeHourUTC, 0))
+	}
+	if r.transparencyReporter != nil {
+		newScheduledJob("transparency_report", r.instanceID, nextTransparencyReport, r.transparencyReporter.pass, clock).
+			register(ctx, router, daily(transparencyReportHourUTC, 0))
+	}
+	if r.healthReporter != nil {
+		newScheduledJob("health_report", r.instanceID, nextHealthReport, r.healthReporter.pass, clock).
+			register(ctx, router, daily(healthReportHourUTC, 0))
+	}
+	if r.rollupMaintainer != nil {
+		offset := int(activityRollupOffset / time.Minute)
+		newScheduledJob("activity_rollups", r.instanceID, nextActivityRollup, r.rollupMaintainer.pass, clock).
+			register(ctx, router, daily(offset/60, offset%60))
+	}
+	if r.memberCountRecorder != nil {
+		newScheduledJob("member_counts", r.instanceID, nextMemberCount, r.memberCountRecorder.pass, clock).
+			register(ctx, router, every(scheduledJobTick))
+	}
+	if r.auditPoller != nil {
+		r.auditPoller.schedule(router)
+	}
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/task"
+)
+
+type memoryJobClock struct {
+	mu   sync.Mutex
+	runs map[string]time.Time
+}
+
+func (c *memoryJobClock) Metadata(_ context.Context, key string) (time.Time, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	at, ok := c.runs[key]
+	return at, ok, nil
+}
+
+func (c *memoryJobClock) SetMetadata(_ context.Context, key string, at time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.runs[key] = at
+	return nil
+}
+
+func TestScheduledJob_FirstBootDoesNotCatchUp(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	clock := &memoryJobClock{runs: make(map[string]time.Time)}
+	job := newScheduledJob("auto_purge", "bot-1", nextAutoPurge, func(context.Context) {
+		t.Error("pass ran on first boot")
+	}, clock)
+	job.now = func() time.Time { return now }
+
+	job.load(context.Background())
+	if job.due() {
+		t.Fatal("job due on first boot")
+	}
+	if at, ok, _ := clock.Metadata(context.Background(), job.metadataKey()); !ok || !at.Equal(now) {
+		t.Fatalf("recorded baseline = %v, %v; want %v", at, ok, now)
+	}
+}
+
+func TestScheduledJob_CatchesUpMissedRun(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	clock := &memoryJobClock{runs: make(map[string]time.Time)}
+	ran := make(chan struct{}, 1)
+	job := newScheduledJob("auto_purge", "bot-1", nextAutoPurge, func(context.Context) {
+		ran <- struct{}{}
+	}, clock)
+	job.now = func() time.Time { return now }
+	// The last run was two nights ago, so last night's was missed.
+	_ = clock.SetMetadata(context.Background(), job.metadataKey(), now.AddDate(0, 0, -2))
+
+	router := task.NewRouter(task.Defaults())
+	defer router.Close()
+	job.register(context.Background(), router, func(task.Task) {})
+
+	select {
+	case <-ran:
+	case <-time.After(5 * time.Second):
+		t.Fatal("missed run not caught up")
+	}
+	if job.due() {
+		t.Fatal("job still due after its run")
+	}
+}
+
+func TestScheduledJob_TickSkipsUntilDue(t *testing.T) {
+	t.Parallel()
+
+	// A monthly job ticking daily runs on the first of the month only.
+	now := time.Date(2026, 10, 2, transparencyReportHourUTC, 1, 0, 0, time.UTC)
+	runs := 0
+	job := newScheduledJob("transparency_report", "bot-1", nextTransparencyReport, func(context.Context) { runs++ }, nil)
+	job.now = func() time.Time { return now }
+	job.last = time.Date(2026, 10, 1, transparencyReportHourUTC, 1, 0, 0, time.UTC)
+
+	_ = job.handle(context.Background(), nil)
+	if runs != 0 {
+		t.Fatalf("pass ran %d times before the next month", runs)
+	}
+	now = time.Date(2026, 11, 1, transparencyReportHourUTC, 1, 0, 0, time.UTC)
+	_ = job.handle(context.Background(), nil)
+	_ = job.handle(context.Background(), nil)
+	if runs != 1 {
+		t.Fatalf("pass ran %d times on the first of the month, want 1", runs)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"runtime/debug"
 	"strings"
 	"sync/atomic"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/small-frappuccino/discordcore/pkg/discord/webhook"
 	"github.com/small-frappuccino/discordcore/pkg/files"
 	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/observability"
 	"github.com/small-frappuccino/discordcore/pkg/runtimeapply"
 	"golang.org/x/sync/errgroup"
 )
@@ -25,6 +27,9 @@ const (
 	databaseConnMaxLifetimeSecsEnv = "DISCORDCORE_DATABASE_CONN_MAX_LIFETIME_SECS"
 	databaseConnMaxIdleTimeSecsEnv = "DISCORDCORE_DATABASE_CONN_MAX_IDLE_TIME_SECS"
 	databasePingTimeoutMSEnv       = "DISCORDCORE_DATABASE_PING_TIMEOUT_MS"
+	configFileEnv                  = "DISCORDCORE_CONFIG_FILE"
+	configFileBackupsEnv           = "DISCORDCORE_CONFIG_BACKUPS"
+	configFileFormatEnv            = "DISCORDCORE_CONFIG_FORMAT"
 )
 
 type resolvedDatabaseBootstrap struct {
@@ -210,6 +215,10 @@ type StartupWebhookEmbedUpdatesTask struct {
 }
 
 func (t StartupWebhookEmbedUpdatesTask) Execute(taskCtx context.Context) error {
+	if t.cfg.LockdownActive() {
+		slog.Warn("Operational telemetry: Startup webhook embed updates skipped while the bot lockdown is engaged")
+		return nil
+	}
 	for _, item := range collectStartupWebhookEmbedUpdates(t.cfg) {
 		if err := taskCtx.Err(); err != nil {
 			return fmt.Errorf("scheduleStartupWebhookEmbedUpdates: %w", err)
@@ -406,7 +415,20 @@ func (o *StartupTaskOrchestrator) Go(task BootstrapTask) {
 		slog.String("task_name", name),
 	)
 
-	o.eg.Go(func() error {
+	o.eg.Go(func() (err error) {
+		// A panicking task is reported and dropped without cancelling its
+		// siblings through the errgroup context.
+		defer func() {
+			if r := recover(); r != nil {
+				observability.RecordRecoveredPanic("startup_task", name)
+				slog.Error("Blocking structural failure: Recovered panic in background task",
+					slog.String("task", name),
+					slog.String("panic", fmt.Sprint(r)),
+					slog.String("stack", string(debug.Stack())),
+				)
+				err = nil
+			}
+		}()
 		if err := task.Execute(o.ctx); err != nil {
 			if o.ctx.Err() != nil {
 				slog.Debug("Tracking complex conditional branch: Task execution halted via context cancellation",
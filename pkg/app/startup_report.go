@@ -0,0 +1,228 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/embeds"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const (
+	// startupReportBudget bounds the queries behind a startup report.
+	startupReportBudget = 30 * time.Second
+
+	// startupReportMaxFields keeps the posted report within Discord's embed
+	// field limit; the log line always lists every guild.
+	startupReportMaxFields = 20
+)
+
+// startupReportSource reads the scheduled work persisted across restarts.
+// *postgres.Store satisfies it.
+type startupReportSource interface {
+	CountPendingCaseExpiries(ctx context.Context, guildID string, now time.Time) (due, running int, err error)
+	GetRaidMode(ctx context.Context, guildID string) (coremod.RaidMode, bool, error)
+	CountTaskKeysByKind(ctx context.Context) (map[string]int64, error)
+}
+
+// guildRecoveredWork is the scheduled work of one guild found at startup.
+type guildRecoveredWork struct {
+	GuildID string
+	// CasesDue ended while the bot was down and are followed up by the first
+	// expiry pass; CasesRunning end later.
+	CasesDue     int
+	CasesRunning int
+	// RaidModeUntil is when the raid mode of the guild lifts, if it has one.
+	RaidModeUntil time.Time
+}
+
+func (g guildRecoveredWork) empty() bool {
+	return g.CasesDue == 0 && g.CasesRunning == 0 && g.RaidModeUntil.IsZero()
+}
+
+// startupReport summarises the scheduled work a runtime picked back up when
+// it started, so operators can check nothing was dropped on the way.
+type startupReport struct {
+	InstanceID string
+	Guilds     []guildRecoveredWork
+	// TaskKeys counts the claimed task idempotency keys by kind, such as
+	// backfill. Dispatches still holding a key were in flight or finished
+	// within its TTL, and are not repeated until it lapses.
+	TaskKeys map[string]int64
+	// Failures lists what could not be read, so a gap in the report is never
+	// mistaken for an absence of work.
+	Failures []string
+}
+
+// buildStartupReport reads the scheduled work of every guild instanceID
+// moderates.
+func buildStartupReport(ctx context.Context, src startupReportSource, cfg *files.BotConfig, instanceID string, now time.Time) startupReport {
+	report := startupReport{InstanceID: instanceID}
+	for _, guild := range files.GuildsForBotInstanceFeature(cfg, instanceID, "moderation") {
+		if ctx.Err() != nil {
+			report.Failures = append(report.Failures, "report interrupted: "+ctx.Err().Error())
+			return report
+		}
+		work := guildRecoveredWork{GuildID: guild.GuildID}
+		var err error
+		work.CasesDue, work.CasesRunning, err = src.CountPendingCaseExpiries(ctx, guild.GuildID, now)
+		if err != nil {
+			report.Failures = append(report.Failures, fmt.Sprintf("timed cases of %s: %v", guild.GuildID, err))
+		}
+		mode, ok, err := src.GetRaidMode(ctx, guild.GuildID)
+		if err != nil {
+			report.Failures = append(report.Failures, fmt.Sprintf("raid mode of %s: %v", guild.GuildID, err))
+		} else if ok {
+			work.RaidModeUntil = mode.ExpiresAt
+		}
+		if !work.empty() {
+			report.Guilds = append(report.Guilds, work)
+		}
+	}
+	keys, err := src.CountTaskKeysByKind(ctx)
+	if err != nil {
+		report.Failures = append(report.Failures, fmt.Sprintf("task keys: %v", err))
+	}
+	report.TaskKeys = keys
+	return report
+}
+
+// logTo writes the report to logger, one line for the runtime and one per
+// guild with recovered work.
+func (r startupReport) logTo(logger *slog.Logger) {
+	var due, running, raids int
+	for _, g := range r.Guilds {
+		due += g.CasesDue
+		running += g.CasesRunning
+		if !g.RaidModeUntil.IsZero() {
+			raids++
+		}
+	}
+	var keys int64
+	for _, n := range r.TaskKeys {
+		keys += n
+	}
+	logger.Info("Architectural state transition: Recovered scheduled work at startup",
+		slog.String("botInstanceID", r.InstanceID),
+		slog.Int("guilds", len(r.Guilds)),
+		slog.Int("timed_cases_due", due),
+		slog.Int("timed_cases_running", running),
+		slog.Int("raid_modes", raids),
+		slog.Int64("task_keys", keys),
+		slog.String("task_key_kinds", formatTaskKeyKinds(r.TaskKeys)),
+	)
+	for _, g := range r.Guilds {
+		attrs := []any{
+			slog.String("botInstanceID", r.InstanceID),
+			slog.String("guildID", g.GuildID),
+			slog.Int("timed_cases_due", g.CasesDue),
+			slog.Int("timed_cases_running", g.CasesRunning),
+		}
+		if !g.RaidModeUntil.IsZero() {
+			attrs = append(attrs, slog.Time("raid_mode_until", g.RaidModeUntil))
+		}
+		logger.Info("Architectural state transition: Recovered scheduled work for guild", attrs...)
+	}
+	for _, failure := range r.Failures {
+		logger.Warn("Mitigated service degradation: Startup report is missing scheduled work",
+			slog.String("botInstanceID", r.InstanceID),
+			slog.String("error", failure),
+		)
+	}
+}
+
+func formatTaskKeyKinds(kinds map[string]int64) string {
+	parts := make([]string, 0, len(kinds))
+	for _, kind := range slices.Sorted(maps.Keys(kinds)) {
+		parts = append(parts, fmt.Sprintf("%s=%d", kind, kinds[kind]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// embed renders the report for the owner alert destination.
+func (r startupReport) embed() discord.Embed {
+	ce := files.CustomEmbedConfig{
+		Title:       "Startup Report",
+		Description: fmt.Sprintf("Bot instance `%s` resumed scheduled work in %d guild(s).", r.InstanceID, len(r.Guilds)),
+		Color:       theme.Info(),
+	}
+	if len(r.Guilds) == 0 {
+		ce.Description = fmt.Sprintf("Bot instance `%s` found no scheduled moderation work to resume.", r.InstanceID)
+	}
+	for i, g := range r.Guilds {
+		if i == startupReportMaxFields {
+			ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+				Name:  "More",
+				Value: fmt.Sprintf("%d more guild(s) not shown.", len(r.Guilds)-i),
+			})
+			break
+		}
+		value := fmt.Sprintf("%d timed case(s) due, %d running", g.CasesDue, g.CasesRunning)
+		if !g.RaidModeUntil.IsZero() {
+			value += "\nRaid mode until " + logging.DiscordTimestamp(g.RaidModeUntil, logging.TimestampShortDateTime)
+		}
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{Name: "Guild " + g.GuildID, Value: value})
+	}
+	if len(r.TaskKeys) > 0 {
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+			Name:  "Task keys held",
+			Value: "`" + formatTaskKeyKinds(r.TaskKeys) + "`",
+		})
+	}
+	if len(r.Failures) > 0 {
+		ce.Color = theme.Warning()
+		ce.FooterText = fmt.Sprintf("%d source(s) could not be read; see the logs.", len(r.Failures))
+	}
+	embed := embeds.Render(ce)
+	embed.Timestamp = discord.NowTimestamp()
+	return embed
+}
+
+// StartupReportTask logs the scheduled work a bot runtime recovered when it
+// started and, if the runtime config asks for it, sends the summary to the
+// owner alert destination.
+type StartupReportTask struct {
+	runtime       *botRuntime
+	store         startupReportSource
+	configManager *files.ConfigManager
+}
+
+func (t StartupReportTask) Execute(ctx context.Context) error {
+	if t.runtime == nil || t.store == nil || t.configManager == nil {
+		return nil
+	}
+	cfg := t.configManager.Config()
+	if cfg == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, startupReportBudget)
+	defer cancel()
+
+	report := buildStartupReport(ctx, t.store, cfg, t.runtime.instanceID, time.Now())
+	report.logTo(slog.Default())
+
+	if !cfg.RuntimeConfig.StartupReportToOwner || t.runtime.arikawaState == nil {
+		return nil
+	}
+	if err := sendOwnerEmbed(t.runtime.arikawaState, t.configManager, report.embed()); err != nil {
+		slog.Warn("Mitigated service degradation: Startup report could not be sent",
+			slog.String("botInstanceID", t.runtime.instanceID),
+			slog.String("error", err.Error()),
+		)
+	}
+	return nil
+}
+
+func (t StartupReportTask) Name() string {
+	return "startup_report_" + t.runtime.instanceID
+}
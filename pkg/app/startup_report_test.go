@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+type fakeStartupReportSource struct {
+	cases    map[string][2]int
+	raids    map[string]coremod.RaidMode
+	raidErr  error
+	taskKeys map[string]int64
+}
+
+func (f *fakeStartupReportSource) CountPendingCaseExpiries(_ context.Context, guildID string, _ time.Time) (int, int, error) {
+	c := f.cases[guildID]
+	return c[0], c[1], nil
+}
+
+func (f *fakeStartupReportSource) GetRaidMode(_ context.Context, guildID string) (coremod.RaidMode, bool, error) {
+	if f.raidErr != nil {
+		return coremod.RaidMode{}, false, f.raidErr
+	}
+	mode, ok := f.raids[guildID]
+	return mode, ok, nil
+}
+
+func (f *fakeStartupReportSource) CountTaskKeysByKind(context.Context) (map[string]int64, error) {
+	return f.taskKeys, nil
+}
+
+func TestBuildStartupReport(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cfg := &files.BotConfig{Guilds: []files.GuildConfig{{GuildID: "1"}, {GuildID: "2"}, {GuildID: "3"}}}
+	src := &fakeStartupReportSource{
+		cases:    map[string][2]int{"1": {2, 3}},
+		raids:    map[string]coremod.RaidMode{"2": {GuildID: "2", ExpiresAt: now.Add(time.Hour)}},
+		taskKeys: map[string]int64{"backfill": 2, "purge": 1},
+	}
+
+	report := buildStartupReport(context.Background(), src, cfg, "", now)
+	if len(report.Guilds) != 2 || len(report.Failures) != 0 {
+		t.Fatalf("expected work in guilds 1 and 2 only, got %+v", report)
+	}
+	if g := report.Guilds[0]; g.GuildID != "1" || g.CasesDue != 2 || g.CasesRunning != 3 {
+		t.Fatalf("unexpected work for guild 1: %+v", g)
+	}
+	if g := report.Guilds[1]; g.GuildID != "2" || !g.RaidModeUntil.Equal(now.Add(time.Hour)) {
+		t.Fatalf("unexpected work for guild 2: %+v", g)
+	}
+	if got := formatTaskKeyKinds(report.TaskKeys); got != "backfill=2,purge=1" {
+		t.Fatalf("formatTaskKeyKinds = %q", got)
+	}
+
+	embed := report.embed()
+	if len(embed.Fields) != 3 || embed.Fields[2].Name != "Task keys held" {
+		t.Fatalf("expected two guild fields and the task keys, got %+v", embed.Fields)
+	}
+}
+
+func TestBuildStartupReportRecordsFailures(t *testing.T) {
+	t.Parallel()
+	cfg := &files.BotConfig{Guilds: []files.GuildConfig{{GuildID: "1"}}}
+	src := &fakeStartupReportSource{raidErr: errors.New("connection refused")}
+
+	report := buildStartupReport(context.Background(), src, cfg, "", time.Now())
+	if len(report.Failures) != 1 || !strings.Contains(report.Failures[0], "raid mode of 1") {
+		t.Fatalf("expected the raid mode failure to be recorded, got %+v", report.Failures)
+	}
+	if embed := report.embed(); embed.Footer == nil || !strings.Contains(embed.Footer.Text, "1 source(s)") {
+		t.Fatalf("expected the embed to flag the failure, got %+v", embed.Footer)
+	}
+}
@@ -56,6 +56,13 @@ func newRuntimeTaskRouterConfig(cfg *files.BotConfig, botInstanceID string, runt
 	routerCfg := task.Defaults()
 	routerCfg.GlobalMaxWorkers = workers
 	routerCfg.ExecutionLimiter = task.NewExecutionLimiter(workers)
+	routerCfg.GuildMaxParallel = guildTaskWorkerShare(workers)
 
 	return routerCfg
 }
+
+// guildTaskWorkerShare caps the workers one guild may hold at once to half
+// the budget, so a guild's backlog always leaves room for the others.
+func guildTaskWorkerShare(workers int) int {
+	return max(1, workers/2)
+}
@@ -73,4 +73,7 @@ func TestNewRuntimeTaskRouterConfigBuildsSharedLimiter(t *testing.T) {
 	if got := routerCfg.ExecutionLimiter.Capacity(); got != 5 {
 		t.Fatalf("expected limiter capacity 5, got %d", got)
 	}
+	if routerCfg.GuildMaxParallel != 2 {
+		t.Fatalf("expected one guild to be capped at 2 workers, got %d", routerCfg.GuildMaxParallel)
+	}
 }
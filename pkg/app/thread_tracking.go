@@ -0,0 +1,179 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/messages"
+)
+
+// threadJoiner is the part of *api.Client threadTracker joins threads
+// through.
+type threadJoiner interface {
+	JoinThread(threadID discord.ChannelID) error
+}
+
+// trackedThread is what threadTracker remembers of a thread, so deletions
+// can still name it and archiving is told apart from other updates.
+type trackedThread struct {
+	ownerID  discord.UserID
+	name     string
+	archived bool
+}
+
+// threadTracker reports threads being created, archived and deleted to the
+// sink, and joins new threads in guilds that ask for it so their messages
+// reach the message cache.
+type threadTracker struct {
+	instanceID    string
+	sink          messages.ThreadSink
+	joiner        threadJoiner
+	configManager *files.ConfigManager
+
+	mu      sync.Mutex
+	threads map[discord.ChannelID]trackedThread
+}
+
+func newThreadTracker(instanceID string, sink messages.ThreadSink, joiner threadJoiner, configManager *files.ConfigManager) *threadTracker {
+	return &threadTracker{
+		instanceID:    instanceID,
+		sink:          sink,
+		joiner:        joiner,
+		configManager: configManager,
+		threads:       make(map[discord.ChannelID]trackedThread),
+	}
+}
+
+func (t *threadTracker) attach(st *state.State) {
+	st.AddHandler(perf.GuardGatewayHandler("threads.guild_create", t.handleGuildCreate))
+	st.AddHandler(perf.GuardGatewayHandler("threads.thread_create", t.handleThreadCreate))
+	st.AddHandler(perf.GuardGatewayHandler("threads.thread_update", t.handleThreadUpdate))
+	st.AddHandler(perf.GuardGatewayHandler("threads.thread_delete", t.handleThreadDelete))
+}
+
+// handleGuildCreate remembers the active threads of a guild, which Discord
+// lists when the guild becomes available.
+func (t *threadTracker) handleGuildCreate(e *gateway.GuildCreateEvent) {
+	if e == nil || !t.logs(e.ID.String()) {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, ch := range e.Threads {
+		t.threads[ch.ID] = trackThread(ch)
+	}
+}
+
+func (t *threadTracker) handleThreadCreate(e *gateway.ThreadCreateEvent) {
+	if e == nil || !e.GuildID.IsValid() {
+		return
+	}
+	guild := t.guild(e.GuildID.String())
+	if guild == nil {
+		return
+	}
+	t.mu.Lock()
+	_, known := t.threads[e.ID]
+	t.threads[e.ID] = trackThread(e.Channel)
+	t.mu.Unlock()
+	// Discord also sends a create, carrying the bot's thread member, when
+	// the bot is added to an existing private thread. Only new threads are
+	// reported.
+	if known || e.Channel.ThreadMember != nil {
+		return
+	}
+
+	if guild.ThreadAutoJoin && !threadArchived(e.Channel) {
+		if err := t.joiner.JoinThread(e.ID); err != nil {
+			slog.Warn("Mitigated service degradation: Could not join a new thread",
+				slog.String("botInstanceID", t.instanceID),
+				slog.String("guildID", e.GuildID.String()),
+				slog.String("threadID", e.ID.String()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+	t.report(e.Channel, messages.ThreadCreated)
+}
+
+func (t *threadTracker) handleThreadUpdate(e *gateway.ThreadUpdateEvent) {
+	if e == nil || !e.GuildID.IsValid() || !t.logs(e.GuildID.String()) {
+		return
+	}
+	t.mu.Lock()
+	previous, known := t.threads[e.ID]
+	t.threads[e.ID] = trackThread(e.Channel)
+	t.mu.Unlock()
+	if threadArchived(e.Channel) && (!known || !previous.archived) {
+		t.report(e.Channel, messages.ThreadArchived)
+	}
+}
+
+func (t *threadTracker) handleThreadDelete(e *gateway.ThreadDeleteEvent) {
+	if e == nil || !e.GuildID.IsValid() || !t.logs(e.GuildID.String()) {
+		return
+	}
+	t.mu.Lock()
+	previous, known := t.threads[e.ID]
+	delete(t.threads, e.ID)
+	t.mu.Unlock()
+
+	ch := discord.Channel{ID: e.ID, GuildID: e.GuildID, Type: e.Type, ParentID: e.ParentID}
+	if known {
+		ch.OwnerID, ch.Name = previous.ownerID, previous.name
+	}
+	t.report(ch, messages.ThreadDeleted)
+}
+
+func (t *threadTracker) report(ch discord.Channel, action messages.ThreadAction) {
+	intent := messages.ThreadIntent{
+		GuildID:  ch.GuildID.String(),
+		ThreadID: ch.ID.String(),
+		Name:     ch.Name,
+		Action:   action,
+	}
+	if ch.ParentID.IsValid() {
+		intent.ParentID = ch.ParentID.String()
+	}
+	if ch.OwnerID.IsValid() {
+		intent.OwnerID = ch.OwnerID.String()
+	}
+	if ch.ThreadMetadata != nil {
+		intent.AutoArchive = int(ch.ThreadMetadata.AutoArchiveDuration)
+	}
+	t.sink.OnThreadEvent(context.Background(), intent)
+}
+
+// guild returns the configuration of guildID when this instance logs it.
+func (t *threadTracker) guild(guildID string) *files.GuildConfig {
+	guild := t.configManager.GuildConfig(guildID)
+	if guild == nil {
+		return nil
+	}
+	if id, _ := files.ResolveFeatureBotInstanceID(*guild, "logging"); id != t.instanceID {
+		return nil
+	}
+	return guild
+}
+
+func (t *threadTracker) logs(guildID string) bool {
+	return t.guild(guildID) != nil
+}
+
+func trackThread(ch discord.Channel) trackedThread {
+	return trackedThread{
+		ownerID:  ch.OwnerID,
+		name:     ch.Name,
+		archived: threadArchived(ch),
+	}
+}
+
+func threadArchived(ch discord.Channel) bool {
+	return ch.ThreadMetadata != nil && ch.ThreadMetadata.Archived
+}
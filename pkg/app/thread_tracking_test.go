@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/messages"
+)
+
+type recordingThreadSink struct {
+	events []messages.ThreadIntent
+}
+
+func (s *recordingThreadSink) OnThreadEvent(_ context.Context, intent messages.ThreadIntent) {
+	s.events = append(s.events, intent)
+}
+
+type fakeThreadJoiner struct {
+	joined []discord.ChannelID
+}
+
+func (j *fakeThreadJoiner) JoinThread(threadID discord.ChannelID) error {
+	j.joined = append(j.joined, threadID)
+	return nil
+}
+
+func TestThreadTracker(t *testing.T) {
+	t.Parallel()
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{
+		{GuildID: "1", ThreadAutoJoin: true},
+		{GuildID: "2"},
+	}})
+	sink := &recordingThreadSink{}
+	joiner := &fakeThreadJoiner{}
+	tracker := newThreadTracker("", sink, joiner, cfgMgr)
+
+	thread := discord.Channel{
+		ID: 10, GuildID: 1, ParentID: 5, OwnerID: 7, Name: "help",
+		ThreadMetadata: &discord.ThreadMetadata{AutoArchiveDuration: discord.OneDayArchive},
+	}
+	tracker.handleThreadCreate(&gateway.ThreadCreateEvent{Channel: thread})
+	if len(joiner.joined) != 1 || joiner.joined[0] != 10 {
+		t.Fatalf("expected the new thread to be joined, got %v", joiner.joined)
+	}
+	if len(sink.events) != 1 || sink.events[0].Action != messages.ThreadCreated || sink.events[0].ParentID != "5" || sink.events[0].OwnerID != "7" {
+		t.Fatalf("expected a creation with parent and owner, got %+v", sink.events)
+	}
+
+	// Being added to the thread repeats the create; it is not new.
+	added := thread
+	added.ThreadMember = &discord.ThreadMember{}
+	tracker.handleThreadCreate(&gateway.ThreadCreateEvent{Channel: added})
+	if len(sink.events) != 1 || len(joiner.joined) != 1 {
+		t.Fatalf("expected repeated creates to be ignored, got %d events", len(sink.events))
+	}
+
+	renamed := thread
+	renamed.Name = "help-wanted"
+	tracker.handleThreadUpdate(&gateway.ThreadUpdateEvent{Channel: renamed})
+	if len(sink.events) != 1 {
+		t.Fatal("updates other than archiving must not be logged")
+	}
+
+	archivedThread := renamed
+	archivedThread.ThreadMetadata = &discord.ThreadMetadata{Archived: true, AutoArchiveDuration: discord.OneDayArchive}
+	tracker.handleThreadUpdate(&gateway.ThreadUpdateEvent{Channel: archivedThread})
+	tracker.handleThreadUpdate(&gateway.ThreadUpdateEvent{Channel: archivedThread})
+	if len(sink.events) != 2 || sink.events[1].Action != messages.ThreadArchived || sink.events[1].AutoArchive != 1440 {
+		t.Fatalf("expected one archive event, got %+v", sink.events)
+	}
+
+	tracker.handleThreadDelete(&gateway.ThreadDeleteEvent{ID: 10, GuildID: 1, ParentID: 5})
+	if len(sink.events) != 3 || sink.events[2].Action != messages.ThreadDeleted || sink.events[2].Name != "help-wanted" || sink.events[2].OwnerID != "7" {
+		t.Fatalf("expected the deletion to carry the remembered name and owner, got %+v", sink.events)
+	}
+
+	tracker.handleThreadCreate(&gateway.ThreadCreateEvent{Channel: discord.Channel{ID: 20, GuildID: 2, Name: "chat"}})
+	if len(joiner.joined) != 1 || len(sink.events) != 4 {
+		t.Fatalf("expected guild 2 logged without joining, got %v joins, %d events", joiner.joined, len(sink.events))
+	}
+
+	tracker.handleThreadCreate(&gateway.ThreadCreateEvent{Channel: discord.Channel{ID: 30, GuildID: 3}})
+	if len(sink.events) != 4 {
+		t.Fatal("guilds without configuration must be ignored")
+	}
+}
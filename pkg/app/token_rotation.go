@@ -0,0 +1,109 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// tokenValidationTimeout bounds the REST round-trip used to prove a staged
+// token authenticates before it replaces the live one.
+const tokenValidationTimeout = 15 * time.Second
+
+// validateBotToken confirms token authenticates against the Discord REST API.
+// It is a variable so tests can stub the network call.
+var validateBotToken = func(ctx context.Context, token string) error {
+	_, err := api.NewClient("Bot " + token).WithContext(ctx).Me()
+	return err
+}
+
+// SupervisorTokenRotationTask validates a staged bot token and, when Discord
+// accepts it, promotes it into the config. Promotion changes the instance's
+// active token, which the supervisor reconciles by reconnecting that runtime.
+type SupervisorTokenRotationTask struct {
+	Supervisor *BotSupervisor
+	InstanceID string
+	Token      string
+}
+
+func (t SupervisorTokenRotationTask) Execute(ctx context.Context) error {
+	return t.Supervisor.executeTokenRotation(ctx, t.InstanceID, t.Token)
+}
+
+func (t SupervisorTokenRotationTask) Name() string {
+	return "token_rotation_" + t.InstanceID
+}
+
+// scheduleTokenRotations queues one rotation task per staged token, skipping
+// instances whose rotation is already in flight.
+func (s *BotSupervisor) scheduleTokenRotations(cfg *files.BotConfig) {
+	if cfg == nil || s.opts.startupTasks == nil {
+		return
+	}
+	for instanceID, pending := range cfg.PendingBotTokens {
+		token := string(pending)
+		if token == "" {
+			continue
+		}
+		if _, busy := s.rotations.LoadOrStore(instanceID, token); busy {
+			continue
+		}
+		s.opts.startupTasks.Go(SupervisorTokenRotationTask{
+			Supervisor: s,
+			InstanceID: instanceID,
+			Token:      token,
+		})
+	}
+}
+
+func (s *BotSupervisor) executeTokenRotation(ctx context.Context, instanceID, token string) error {
+	defer s.rotations.Delete(instanceID)
+
+	s.log().Info("Architectural state transition: Validating staged bot token before rotation",
+		slog.String("botInstanceID", instanceID),
+	)
+
+	validateCtx, cancel := context.WithTimeout(ctx, tokenValidationTimeout)
+	err := validateBotToken(validateCtx, token)
+	cancel()
+	if err != nil {
+		if ctx.Err() != nil {
+			// Shutdown interrupted validation; keep the token staged for the next boot.
+			return nil
+		}
+		s.log().Error("Blocking structural failure: Staged bot token rejected; active token retained",
+			slog.String("botInstanceID", instanceID),
+			slog.Any("error", err),
+		)
+		if discardErr := s.configManager.DiscardPendingBotToken(ctx, instanceID, token); discardErr != nil {
+			s.log().Warn("Mitigated service degradation: Failed to discard rejected bot token",
+				slog.String("botInstanceID", instanceID),
+				slog.Any("error", discardErr),
+			)
+		}
+		return nil
+	}
+
+	promoted, err := s.configManager.PromotePendingBotToken(ctx, instanceID, token)
+	if err != nil {
+		s.log().Error("Blocking structural failure: Failed to promote validated bot token",
+			slog.String("botInstanceID", instanceID),
+			slog.Any("error", err),
+		)
+		return nil
+	}
+	if !promoted {
+		s.log().Debug("Architectural state bypass: Staged bot token superseded before promotion",
+			slog.String("botInstanceID", instanceID),
+		)
+		return nil
+	}
+
+	s.log().Info("Architectural state transition: Bot token rotated; reconnecting runtime with the new credentials",
+		slog.String("botInstanceID", instanceID),
+	)
+	return nil
+}
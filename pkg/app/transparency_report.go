@@ -0,0 +1,90 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+// transparencyReportHourUTC is when the monthly transparency reports go out
+// on the first day of each month.
+const transparencyReportHourUTC = 9
+
+// embedSender posts embeds to a channel. *state.State satisfies it.
+type embedSender interface {
+	SendEmbeds(channelID discord.ChannelID, embeds ...discord.Embed) (*discord.Message, error)
+}
+
+// transparencyReporter posts last month's moderation transparency report to
+// every guild with a transparency channel, once a month.
+type transparencyReporter struct {
+	instanceID    string
+	src           discordmod.TransparencySource
+	sender        embedSender
+	configManager *files.ConfigManager
+	now           func() time.Time
+}
+
+func newTransparencyReporter(instanceID string, src discordmod.TransparencySource, sender embedSender, configManager *files.ConfigManager) *transparencyReporter {
+	return &transparencyReporter{
+		instanceID:    instanceID,
+		src:           src,
+		sender:        sender,
+		configManager: configManager,
+		now:           time.Now,
+	}
+}
+
+// nextTransparencyReport returns the first report time strictly after now.
+func nextTransparencyReport(now time.Time) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), 1, transparencyReportHourUTC, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 1, 0)
+	}
+	return next
+}
+
+// pass posts the report of the previous month to each transparency channel.
+// A failing guild is logged and skipped.
+func (r *transparencyReporter) pass(ctx context.Context) {
+	cfg := r.configManager.Config()
+	if cfg == nil {
+		return
+	}
+	from, to := coremod.PreviousMonth(r.now())
+	for _, guild := range files.GuildsForBotInstanceFeature(cfg, r.instanceID, "moderation") {
+		if ctx.Err() != nil {
+			return
+		}
+		channelID, err := discord.ParseSnowflake(strings.TrimSpace(guild.Channels.Transparency))
+		if err != nil || !channelID.IsValid() {
+			continue
+		}
+		report, err := discordmod.BuildTransparencyReport(ctx, r.src, guild.GuildID, from, to)
+		if err == nil {
+			_, err = r.sender.SendEmbeds(discord.ChannelID(channelID), discordmod.TransparencyEmbed(report))
+		}
+		if err != nil {
+			slog.Warn("Mitigated service degradation: Monthly transparency report not posted",
+				slog.String("botInstanceID", r.instanceID),
+				slog.String("guildID", guild.GuildID),
+				slog.String("channelID", guild.Channels.Transparency),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		slog.Info("Architectural state transition: Monthly transparency report posted",
+			slog.String("botInstanceID", r.instanceID),
+			slog.String("guildID", guild.GuildID),
+			slog.Time("from", from),
+			slog.Int("actions", report.Total()),
+		)
+	}
+}
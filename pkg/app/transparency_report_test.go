@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+type fakeTransparencySource struct {
+	periods map[string][2]time.Time
+}
+
+func (f *fakeTransparencySource) ModerationActionCounts(_ context.Context, guildID string, from, to time.Time) (coremod.ActionCounts, error) {
+	if guildID == "3" {
+		return coremod.ActionCounts{}, errors.New("db down")
+	}
+	f.periods[guildID] = [2]time.Time{from, to}
+	return coremod.ActionCounts{ByAction: map[string]int{"ban": 1}}, nil
+}
+
+type fakeEmbedSender struct {
+	sent map[discord.ChannelID][]discord.Embed
+}
+
+func (f *fakeEmbedSender) SendEmbeds(channelID discord.ChannelID, embeds ...discord.Embed) (*discord.Message, error) {
+	f.sent[channelID] = append(f.sent[channelID], embeds...)
+	return &discord.Message{ChannelID: channelID}, nil
+}
+
+func TestNextTransparencyReport(t *testing.T) {
+	t.Parallel()
+	before := time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)
+	if got := nextTransparencyReport(before); !got.Equal(time.Date(2026, 5, 1, transparencyReportHourUTC, 0, 0, 0, time.UTC)) {
+		t.Fatalf("nextTransparencyReport(%v) = %v", before, got)
+	}
+	after := time.Date(2026, 12, 1, transparencyReportHourUTC, 0, 0, 0, time.UTC)
+	if got := nextTransparencyReport(after); !got.Equal(time.Date(2027, 1, 1, transparencyReportHourUTC, 0, 0, 0, time.UTC)) {
+		t.Fatalf("nextTransparencyReport(%v) = %v", after, got)
+	}
+}
+
+func TestTransparencyReporterPass(t *testing.T) {
+	t.Parallel()
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{
+		{GuildID: "1", Channels: files.ChannelsConfig{Transparency: "10"}},
+		{GuildID: "2"},
+		{GuildID: "3", Channels: files.ChannelsConfig{Transparency: "30"}},
+	}})
+
+	src := &fakeTransparencySource{periods: map[string][2]time.Time{}}
+	sender := &fakeEmbedSender{sent: map[discord.ChannelID][]discord.Embed{}}
+	reporter := newTransparencyReporter("", src, sender, cfgMgr)
+	reporter.now = func() time.Time { return time.Date(2026, 5, 1, transparencyReportHourUTC, 0, 0, 0, time.UTC) }
+	reporter.pass(context.Background())
+
+	if len(sender.sent) != 1 || len(sender.sent[10]) != 1 {
+		t.Fatalf("expected one report in channel 10, got %+v", sender.sent)
+	}
+	period := src.periods["1"]
+	if !period[0].Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)) || !period[1].Equal(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected April to be reported, got %v", period)
+	}
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/diamondburned/arikawa/v3/utils/ws"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+)
+
+// Arikawa has no stage instance events, and its audit log entry event drops
+// the guild the entry belongs to. Registering these in its place lets
+// handlers receive both.
+func init() {
+	gateway.OpUnmarshalers.Add(
+		func() ws.Event { return new(stageInstanceCreateEvent) },
+		func() ws.Event { return new(stageInstanceDeleteEvent) },
+		func() ws.Event { return new(auditLogEntryCreateEvent) },
+	)
+}
+
+// stageInstanceCreateEvent is dispatched when a stage goes live.
+type stageInstanceCreateEvent struct {
+	discord.StageInstance
+}
+
+func (*stageInstanceCreateEvent) Op() ws.OpCode { return gatewayDispatchOp }
+func (*stageInstanceCreateEvent) EventType() ws.EventType {
+	return "STAGE_INSTANCE_CREATE"
+}
+
+// stageInstanceDeleteEvent is dispatched when a stage ends, whether a
+// moderator ended it or everyone left.
+type stageInstanceDeleteEvent struct {
+	discord.StageInstance
+}
+
+func (*stageInstanceDeleteEvent) Op() ws.OpCode { return gatewayDispatchOp }
+func (*stageInstanceDeleteEvent) EventType() ws.EventType {
+	return "STAGE_INSTANCE_DELETE"
+}
+
+// auditLogEntryCreateEvent is gateway.GuildAuditLogEntryCreateEvent with the
+// guild ID Discord sends alongside the entry.
+type auditLogEntryCreateEvent struct {
+	discord.AuditLogEntry
+	GuildID discord.GuildID `json:"guild_id"`
+}
+
+func (*auditLogEntryCreateEvent) Op() ws.OpCode { return gatewayDispatchOp }
+func (*auditLogEntryCreateEvent) EventType() ws.EventType {
+	return "GUILD_AUDIT_LOG_ENTRY_CREATE"
+}
+
+// voiceTracker reports stages starting and ending, and moderators moving
+// or disconnecting members from voice, to the sink. Moves and disconnects
+// have no gateway event of their own and are read from the audit log.
+type voiceTracker struct {
+	instanceID    string
+	sink          members.VoiceSink
+	configManager *files.ConfigManager
+}
+
+func newVoiceTracker(instanceID string, sink members.VoiceSink, configManager *files.ConfigManager) *voiceTracker {
+	return &voiceTracker{instanceID: instanceID, sink: sink, configManager: configManager}
+}
+
+func (t *voiceTracker) attach(st *state.State) {
+	st.AddHandler(perf.GuardGatewayHandler("voice.stage_create", t.handleStageCreate))
+	st.AddHandler(perf.GuardGatewayHandler("voice.stage_delete", t.handleStageDelete))
+	st.AddHandler(perf.GuardGatewayHandler("voice.audit_log_entry", t.handleAuditLogEntry))
+}
+
+func (t *voiceTracker) handleStageCreate(e *stageInstanceCreateEvent) {
+	if e == nil {
+		return
+	}
+	t.reportStage(e.StageInstance, members.VoiceStageStarted)
+}
+
+func (t *voiceTracker) handleStageDelete(e *stageInstanceDeleteEvent) {
+	if e == nil {
+		return
+	}
+	t.reportStage(e.StageInstance, members.VoiceStageEnded)
+}
+
+func (t *voiceTracker) reportStage(stage discord.StageInstance, action members.VoiceAction) {
+	if !stage.GuildID.IsValid() || !t.logs(stage.GuildID.String()) {
+		return
+	}
+	t.sink.OnVoiceAction(context.Background(), members.VoiceIntent{
+		GuildID:   stage.GuildID.String(),
+		ChannelID: stage.ChannelID.String(),
+		Action:    action,
+		Topic:     stage.Topic,
+	})
+}
+
+func (t *voiceTracker) handleAuditLogEntry(e *auditLogEntryCreateEvent) {
+	if e == nil || !e.GuildID.IsValid() {
+		return
+	}
+	var action members.VoiceAction
+	switch e.ActionType {
+	case discord.MemberMove:
+		action = members.VoiceMembersMoved
+	case discord.MemberDisconnect:
+		action = members.VoiceMembersDisconnected
+	default:
+		return
+	}
+	if !t.logs(e.GuildID.String()) {
+		return
+	}
+
+	intent := members.VoiceIntent{
+		GuildID: e.GuildID.String(),
+		Action:  action,
+	}
+	if e.UserID.IsValid() {
+		intent.ActorID = e.UserID.String()
+	}
+	if action == members.VoiceMembersMoved && e.Options.ChannelID.IsValid() {
+		intent.ChannelID = e.Options.ChannelID.String()
+	}
+	// Discord counts the members in a string.
+	if count, err := strconv.Atoi(e.Options.Count); err == nil {
+		intent.Count = count
+	}
+	t.sink.OnVoiceAction(context.Background(), intent)
+}
+
+func (t *voiceTracker) logs(guildID string) bool {
+	guild := t.configManager.GuildConfig(guildID)
+	if guild == nil {
+		return false
+	}
+	id, _ := files.ResolveFeatureBotInstanceID(*guild, "logging")
+	return id == t.instanceID
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/utils/ws"
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+)
+
+type recordingVoiceSink struct {
+	events []members.VoiceIntent
+}
+
+func (s *recordingVoiceSink) OnVoiceAction(_ context.Context, intent members.VoiceIntent) {
+	s.events = append(s.events, intent)
+}
+
+func TestVoiceEventsAreRegistered(t *testing.T) {
+	t.Parallel()
+	newEvent := gateway.OpUnmarshalers.Lookup(gatewayDispatchOp, "GUILD_AUDIT_LOG_ENTRY_CREATE")
+	if newEvent == nil {
+		t.Fatal("expected audit log entries to be registered")
+	}
+	ev, ok := newEvent().(*auditLogEntryCreateEvent)
+	if !ok {
+		t.Fatalf("expected audit log entries to keep their guild, got %T", newEvent())
+	}
+	raw := `{"guild_id":"1","id":"5","user_id":"7","action_type":26,"options":{"channel_id":"9","count":"2"}}`
+	if err := json.Unmarshal([]byte(raw), ev); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if ev.GuildID != 1 || ev.UserID != 7 || ev.ActionType != discord.MemberMove || ev.Options.ChannelID != 9 {
+		t.Fatalf("unexpected entry %+v", ev)
+	}
+	for _, name := range []string{"STAGE_INSTANCE_CREATE", "STAGE_INSTANCE_DELETE"} {
+		if gateway.OpUnmarshalers.Lookup(gatewayDispatchOp, ws.EventType(name)) == nil {
+			t.Fatalf("expected %s to be registered", name)
+		}
+	}
+}
+
+func TestVoiceTracker(t *testing.T) {
+	t.Parallel()
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{{GuildID: "1"}}})
+	sink := &recordingVoiceSink{}
+	tracker := newVoiceTracker("", sink, cfgMgr)
+
+	stage := discord.StageInstance{GuildID: 1, ChannelID: 4, Topic: "Town hall"}
+	tracker.handleStageCreate(&stageInstanceCreateEvent{StageInstance: stage})
+	tracker.handleStageDelete(&stageInstanceDeleteEvent{StageInstance: stage})
+	tracker.handleAuditLogEntry(&auditLogEntryCreateEvent{GuildID: 1, AuditLogEntry: discord.AuditLogEntry{
+		UserID: 7, ActionType: discord.MemberMove, Options: discord.AuditEntryInfo{ChannelID: 9, Count: "2"},
+	}})
+	tracker.handleAuditLogEntry(&auditLogEntryCreateEvent{GuildID: 1, AuditLogEntry: discord.AuditLogEntry{
+		UserID: 7, ActionType: discord.MemberDisconnect, Options: discord.AuditEntryInfo{Count: "1"},
+	}})
+	// Other audit entries and unlogged guilds are ignored.
+	tracker.handleAuditLogEntry(&auditLogEntryCreateEvent{GuildID: 1, AuditLogEntry: discord.AuditLogEntry{ActionType: discord.MemberKick}})
+	tracker.handleStageCreate(&stageInstanceCreateEvent{StageInstance: discord.StageInstance{GuildID: 2, ChannelID: 4}})
+
+	want := []members.VoiceIntent{
+		{GuildID: "1", ChannelID: "4", Action: members.VoiceStageStarted, Topic: "Town hall"},
+		{GuildID: "1", ChannelID: "4", Action: members.VoiceStageEnded, Topic: "Town hall"},
+		{GuildID: "1", ChannelID: "9", ActorID: "7", Action: members.VoiceMembersMoved, Count: 2},
+		{GuildID: "1", ActorID: "7", Action: members.VoiceMembersDisconnected, Count: 1},
+	}
+	if len(sink.events) != len(want) {
+		t.Fatalf("expected %d reports, got %+v", len(want), sink.events)
+	}
+	for i := range want {
+		if sink.events[i] != want[i] {
+			t.Fatalf("report %d: expected %+v, got %+v", i, want[i], sink.events[i])
+		}
+	}
+}
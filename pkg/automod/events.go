@@ -2,6 +2,14 @@ package automod
 
 import "github.com/diamondburned/arikawa/v3/discord"
 
+// AutoMod action types, as reported in ExecutionAction.Type.
+const (
+	ActionBlockMessage           = 1
+	ActionSendAlertMessage       = 2
+	ActionTimeout                = 3
+	ActionBlockMemberInteraction = 4
+)
+
 // ExecutionActionMetadata holds the metadata for an AutoMod action.
 type ExecutionActionMetadata struct {
 	ChannelID     discord.ChannelID `json:"channel_id"`
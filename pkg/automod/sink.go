@@ -19,3 +19,14 @@ type NopSink struct{}
 
 func (NopSink) OnAutomodBlock(ctx context.Context, guildID discord.GuildID, entry *ExecutionEvent) {
 }
+
+// MultiSink fans each event out to every non-nil sink in order.
+type MultiSink []Sink
+
+func (m MultiSink) OnAutomodBlock(ctx context.Context, guildID discord.GuildID, entry *ExecutionEvent) {
+	for _, sink := range m {
+		if sink != nil {
+			sink.OnAutomodBlock(ctx, guildID, entry)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package clean
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Archive is the file attached to the log channel before a /clean deletes
+// anything, so removed content stays reviewable.
+type Archive struct {
+	ChannelID   string         `json:"channel_id"`
+	RequestedBy string         `json:"requested_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Messages    []ArchiveEntry `json:"messages"`
+}
+
+// ArchiveEntry is one archived message.
+type ArchiveEntry struct {
+	ID          string    `json:"id"`
+	AuthorID    string    `json:"author_id"`
+	AuthorName  string    `json:"author_name,omitempty"`
+	Content     string    `json:"content"`
+	Attachments []string  `json:"attachments,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// NewArchive collects messages into an archive, oldest first.
+func NewArchive(channelID, requestedBy string, messages []Message, now time.Time) Archive {
+	archive := Archive{
+		ChannelID:   channelID,
+		RequestedBy: requestedBy,
+		CreatedAt:   now.UTC(),
+		Messages:    make([]ArchiveEntry, 0, len(messages)),
+	}
+	for _, m := range messages {
+		archive.Messages = append(archive.Messages, ArchiveEntry{
+			ID:          m.ID,
+			AuthorID:    m.AuthorID,
+			AuthorName:  m.AuthorName,
+			Content:     m.Content,
+			Attachments: m.AttachmentURLs,
+			Timestamp:   m.Timestamp.UTC(),
+		})
+	}
+	slices.SortFunc(archive.Messages, func(a, b ArchiveEntry) int {
+		return CompareSnowflakeIDs(a.ID, b.ID)
+	})
+	return archive
+}
+
+// BuildArchive serializes messages as indented JSON, oldest first.
+func BuildArchive(channelID, requestedBy string, messages []Message, now time.Time) ([]byte, error) {
+	data, err := json.MarshalIndent(NewArchive(channelID, requestedBy, messages, now), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("BuildArchive: %w", err)
+	}
+	return data, nil
+}
+
+// ArchiveFileName names the archive of a clean in channelID.
+func ArchiveFileName(channelID string, now time.Time) string {
+	return fmt.Sprintf("clean-%s-%s.json", channelID, now.UTC().Format("20060102-150405"))
+}
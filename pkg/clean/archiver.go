@@ -0,0 +1,47 @@
+package clean
+
+import (
+	"context"
+	"time"
+)
+
+// Deletion describes messages that are about to be deleted.
+type Deletion struct {
+	GuildID     string
+	ChannelID   string
+	RequestedBy string
+	Messages    []Message
+}
+
+// Archiver snapshots messages before they are deleted, into storage or a
+// transcript file. Callers keep the messages when it fails.
+type Archiver func(ctx context.Context, d Deletion) error
+
+// ArchiveStore persists archives. *postgres.Store satisfies it.
+type ArchiveStore interface {
+	SaveCleanLog(ctx context.Context, guildID string, archive Archive) error
+}
+
+// StoreArchiver archives deletions into store, where /clean-export finds
+// them.
+func StoreArchiver(store ArchiveStore, now func() time.Time) Archiver {
+	return func(ctx context.Context, d Deletion) error {
+		return store.SaveCleanLog(ctx, d.GuildID, NewArchive(d.ChannelID, d.RequestedBy, d.Messages, now()))
+	}
+}
+
+// ChainArchivers runs archivers in order and stops at the first failure.
+// Nil archivers are skipped.
+func ChainArchivers(archivers ...Archiver) Archiver {
+	return func(ctx context.Context, d Deletion) error {
+		for _, archive := range archivers {
+			if archive == nil {
+				continue
+			}
+			if err := archive(ctx, d); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,39 @@
+package clean
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type memoryArchiveStore struct {
+	guildID  string
+	archives []Archive
+}
+
+func (m *memoryArchiveStore) SaveCleanLog(_ context.Context, guildID string, archive Archive) error {
+	m.guildID = guildID
+	m.archives = append(m.archives, archive)
+	return nil
+}
+
+func TestChainArchivers(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 6, 20, 12, 0, 0, 0, time.UTC)
+	store := &memoryArchiveStore{}
+	deletion := Deletion{GuildID: "1", ChannelID: "10", RequestedBy: "99", Messages: []Message{{ID: "5", Content: "bye"}}}
+
+	if err := ChainArchivers(nil, StoreArchiver(store, func() time.Time { return now }))(context.Background(), deletion); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	if store.guildID != "1" || len(store.archives) != 1 || store.archives[0].Messages[0].Content != "bye" || !store.archives[0].CreatedAt.Equal(now) {
+		t.Fatalf("stored %q %+v", store.guildID, store.archives)
+	}
+
+	failing := func(context.Context, Deletion) error { return errors.New("disk full") }
+	err := ChainArchivers(failing, StoreArchiver(store, time.Now))(context.Background(), deletion)
+	if err == nil || len(store.archives) != 1 {
+		t.Fatalf("chain after a failure: err=%v, stored %d", err, len(store.archives))
+	}
+}
@@ -1,6 +1,9 @@
 package clean
 
 import (
+	"errors"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -14,15 +17,64 @@ const (
 	CleanSearchWindow = 1000
 	// CleanBulkDeleteMaxAge identifies Discord's 14-day hard boundary minus an operational 1-hour buffer.
 	CleanBulkDeleteMaxAge = (14 * 24 * time.Hour) - time.Hour
+	// CleanDeepMaxDeleteCount and CleanDeepSearchWindow replace the limits
+	// above for deep cleans. Old messages are deleted one at a time, so a deep
+	// clean of the maximum size still finishes within the interaction's
+	// 15-minute lifetime.
+	CleanDeepMaxDeleteCount = 500
+	CleanDeepSearchWindow   = 5000
 )
 
+// ErrChannelBusy is returned when a clean or purge of the channel is already
+// running. A paced deep clean can take minutes, and a second run over the
+// same messages would only race it to delete them.
+var ErrChannelBusy = errors.New("a clean of this channel is already running")
+
 // Message represents a normalized Discord message decoupled from any specific API implementation.
 type Message struct {
-	ID        string
-	AuthorID  string
-	Content   string
-	Timestamp time.Time
-	Pinned    bool
+	ID             string
+	AuthorID       string
+	AuthorName     string
+	Bot            bool
+	Content        string
+	HasAttachments bool
+	AttachmentURLs []string
+	HasEmbeds      bool
+	Timestamp      time.Time
+	Pinned         bool
+	// AuthorRoleIDs lists the author's roles. It is only resolved when the
+	// filter protects roles.
+	AuthorRoleIDs []string
+}
+
+// ContentKind names a kind of content a message can be required to carry.
+type ContentKind string
+
+// Content kinds accepted by Filter.Has.
+const (
+	ContentAttachments ContentKind = "attachments"
+	ContentLinks       ContentKind = "links"
+	ContentEmbeds      ContentKind = "embeds"
+)
+
+// MaxPatternLength bounds user-supplied regular expressions. Go's regexp runs
+// in linear time, so the limit only keeps compiled programs small.
+const MaxPatternLength = 200
+
+var linkPattern = regexp.MustCompile(`(?i)\bhttps?://\S`)
+
+// Has reports whether the message carries content of the given kind.
+func (m Message) Has(kind ContentKind) bool {
+	switch kind {
+	case ContentAttachments:
+		return m.HasAttachments
+	case ContentEmbeds:
+		return m.HasEmbeds
+	case ContentLinks:
+		return linkPattern.MatchString(m.Content)
+	default:
+		return false
+	}
 }
 
 // Filter models the bounding parameters extracted directly from the user's slash command payload.
@@ -32,6 +84,62 @@ type Filter struct {
 	Contains string
 	FromID   string
 	ToID     string
+	// BotsOnly restricts matches to messages sent by bots.
+	BotsOnly bool
+	// Pattern, when set, must match the message content.
+	Pattern *regexp.Regexp
+	// Has, when set, requires the message to carry that kind of content.
+	Has ContentKind
+	// ArchiveChannelID, when set, receives a JSON archive of the matched
+	// messages before any of them is deleted.
+	ArchiveChannelID string
+	// IncludePinned lets pinned messages match. They are skipped otherwise.
+	IncludePinned bool
+	// ProtectedUserIDs and ProtectedRoleIDs exempt messages whose author is
+	// listed or holds a listed role, whatever else matches.
+	ProtectedUserIDs []string
+	ProtectedRoleIDs []string
+	// GuildID scopes the member lookups behind ProtectedRoleIDs.
+	GuildID string
+	// Preview only reports the matches; nothing is archived or deleted.
+	Preview bool
+	// Deep raises the count and search limits and deletes messages past the
+	// bulk-delete age one at a time, paced to stay within rate limits.
+	Deep bool
+	// Progress, when set, is called after each paced deletion of a deep
+	// clean with the number of old messages handled so far and their total.
+	Progress func(done, total int)
+}
+
+// SearchWindow returns how many messages a clean with this filter may scan.
+func (f Filter) SearchWindow() int {
+	if f.Deep {
+		return CleanDeepSearchWindow
+	}
+	return CleanSearchWindow
+}
+
+// Outcome reports what a clean removed and what it left in place.
+type Outcome struct {
+	Deleted          int
+	SkippedPinned    int
+	SkippedProtected int
+	// Messages holds the deleted messages, or the matches of a preview.
+	Messages []Message
+}
+
+// Protects reports whether the filter exempts m as a protected author's
+// message.
+func (f Filter) Protects(m Message) bool {
+	if slices.Contains(f.ProtectedUserIDs, m.AuthorID) {
+		return true
+	}
+	for _, roleID := range m.AuthorRoleIDs {
+		if slices.Contains(f.ProtectedRoleIDs, roleID) {
+			return true
+		}
+	}
+	return false
 }
 
 // CompareSnowflakeIDs performs a deterministic chronological ordering validation on numeric snowflake identifiers.
@@ -101,9 +209,10 @@ func CategorizeMessages(messages []Message, now func() time.Time) CategorizedMes
 
 // FilterResult records the progression state of the linear filtering scan.
 type FilterResult struct {
-	Matched       []Message
-	SkippedPinned int
-	Scanned       int
+	Matched          []Message
+	SkippedPinned    int
+	SkippedProtected int
+	Scanned          int
 }
 
 // ApplyFilter systematically screens a slice of sequential messages against bounded rules.
@@ -133,10 +242,23 @@ func ApplyFilter(messages []Message, filter Filter, alreadyMatched int) FilterRe
 		if filter.Contains != "" && !strings.Contains(strings.ToLower(m.Content), containsNeedle) {
 			continue
 		}
-		if m.Pinned {
+		if filter.BotsOnly && !m.Bot {
+			continue
+		}
+		if filter.Pattern != nil && !filter.Pattern.MatchString(m.Content) {
+			continue
+		}
+		if filter.Has != "" && !m.Has(filter.Has) {
+			continue
+		}
+		if m.Pinned && !filter.IncludePinned {
 			result.SkippedPinned++
 			continue
 		}
+		if filter.Protects(m) {
+			result.SkippedProtected++
+			continue
+		}
 
 		result.Matched = append(result.Matched, m)
 	}
@@ -1,6 +1,8 @@
 package clean
 
 import (
+	"encoding/json"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -115,3 +117,94 @@ func TestApplyFilter(t *testing.T) {
 		}
 	}
 }
+
+func TestApplyFilterContentFilters(t *testing.T) {
+	t.Parallel()
+	messages := []Message{
+		{ID: "5", AuthorID: "bot", Bot: true, Content: "Daily digest"},
+		{ID: "4", AuthorID: "user1", Content: "see https://example.com"},
+		{ID: "3", AuthorID: "user1", Content: "screenshot", HasAttachments: true},
+		{ID: "2", AuthorID: "bot", Bot: true, Content: "", HasEmbeds: true},
+		{ID: "1", AuthorID: "user2", Content: "order #1234 shipped"},
+	}
+
+	ids := func(filter Filter) string {
+		filter.Count = 100
+		var got []string
+		for _, m := range ApplyFilter(messages, filter, 0).Matched {
+			got = append(got, m.ID)
+		}
+		return strings.Join(got, ",")
+	}
+
+	cases := []struct {
+		name   string
+		filter Filter
+		want   string
+	}{
+		{"bots", Filter{BotsOnly: true}, "5,2"},
+		{"links", Filter{Has: ContentLinks}, "4"},
+		{"attachments", Filter{Has: ContentAttachments}, "3"},
+		{"embeds", Filter{Has: ContentEmbeds}, "2"},
+		{"regex", Filter{Pattern: regexp.MustCompile(`#\d+`)}, "1"},
+		{"combined", Filter{BotsOnly: true, Has: ContentEmbeds}, "2"},
+	}
+	for _, tc := range cases {
+		if got := ids(tc.filter); got != tc.want {
+			t.Errorf("%s: matched %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestApplyFilterProtections(t *testing.T) {
+	t.Parallel()
+	messages := []Message{
+		{ID: "4", AuthorID: "mod", AuthorRoleIDs: []string{"staff"}},
+		{ID: "3", AuthorID: "owner"},
+		{ID: "2", AuthorID: "user1", Pinned: true},
+		{ID: "1", AuthorID: "user1"},
+	}
+
+	res := ApplyFilter(messages, Filter{
+		Count:            100,
+		ProtectedUserIDs: []string{"owner"},
+		ProtectedRoleIDs: []string{"staff"},
+	}, 0)
+	if len(res.Matched) != 1 || res.Matched[0].ID != "1" {
+		t.Fatalf("matched %+v, want only message 1", res.Matched)
+	}
+	if res.SkippedPinned != 1 || res.SkippedProtected != 2 {
+		t.Fatalf("skipped %d pinned and %d protected, want 1 and 2", res.SkippedPinned, res.SkippedProtected)
+	}
+
+	res = ApplyFilter(messages, Filter{Count: 100, IncludePinned: true}, 0)
+	if len(res.Matched) != 4 || res.SkippedPinned != 0 {
+		t.Fatalf("IncludePinned matched %d and skipped %d pinned", len(res.Matched), res.SkippedPinned)
+	}
+}
+
+func TestBuildArchive(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 6, 20, 12, 0, 0, 0, time.UTC)
+	data, err := BuildArchive("10", "99", []Message{
+		{ID: "20", AuthorID: "2", AuthorName: "bob", Content: "second", Timestamp: now},
+		{ID: "3", AuthorID: "1", Content: "first", AttachmentURLs: []string{"https://cdn.example/a.png"}, Timestamp: now.Add(-time.Minute)},
+	}, now)
+	if err != nil {
+		t.Fatalf("BuildArchive: %v", err)
+	}
+
+	var archive Archive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		t.Fatalf("archive is not valid JSON: %v", err)
+	}
+	if archive.ChannelID != "10" || archive.RequestedBy != "99" || len(archive.Messages) != 2 {
+		t.Fatalf("unexpected archive header: %+v", archive)
+	}
+	if archive.Messages[0].ID != "3" || archive.Messages[0].Attachments[0] != "https://cdn.example/a.png" {
+		t.Fatalf("expected the oldest message with its attachment first, got %+v", archive.Messages[0])
+	}
+	if got := ArchiveFileName("10", now); got != "clean-10-20260620-120000.json" {
+		t.Fatalf("ArchiveFileName = %q", got)
+	}
+}
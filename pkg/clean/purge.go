@@ -0,0 +1,52 @@
+package clean
+
+import (
+	"slices"
+	"time"
+)
+
+// Bounds of one automatic purge of a channel. Whatever is left over is
+// picked up by the next run.
+const (
+	PurgeMaxDeleteCount = 1000
+	PurgeSearchWindow   = 10000
+)
+
+// PurgePolicy selects the messages an automatic purge removes from a channel.
+// Exactly one of MaxAge and KeepLast is expected to be set.
+type PurgePolicy struct {
+	// MaxAge removes messages older than this.
+	MaxAge time.Duration
+	// KeepLast keeps only this many of the most recent messages.
+	KeepLast int
+	// ExemptUserIDs and ExemptRoleIDs keep the messages of these users and
+	// of members holding these roles.
+	ExemptUserIDs []string
+	ExemptRoleIDs []string
+}
+
+// Exempts reports whether m is kept whatever its age or position. Pinned
+// messages are always kept.
+func (p PurgePolicy) Exempts(m Message) bool {
+	if m.Pinned || slices.Contains(p.ExemptUserIDs, m.AuthorID) {
+		return true
+	}
+	for _, roleID := range m.AuthorRoleIDs {
+		if slices.Contains(p.ExemptRoleIDs, roleID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Selects reports whether a purge at now removes m, given how many purgeable
+// messages are newer than it. Exempt messages do not count towards KeepLast.
+func (p PurgePolicy) Selects(m Message, newer int, now time.Time) bool {
+	if p.Exempts(m) {
+		return false
+	}
+	if p.KeepLast > 0 && newer >= p.KeepLast {
+		return true
+	}
+	return p.MaxAge > 0 && !m.Timestamp.IsZero() && now.Sub(m.Timestamp) > p.MaxAge
+}
@@ -0,0 +1,37 @@
+package clean
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPurgePolicySelects(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 1, 4, 0, 0, 0, time.UTC)
+	old := Message{ID: "1", AuthorID: "u1", Timestamp: now.Add(-10 * 24 * time.Hour)}
+	recent := Message{ID: "2", AuthorID: "u1", Timestamp: now.Add(-time.Hour)}
+
+	tests := []struct {
+		name   string
+		policy PurgePolicy
+		msg    Message
+		newer  int
+		want   bool
+	}{
+		{name: "older than max age", policy: PurgePolicy{MaxAge: 7 * 24 * time.Hour}, msg: old, want: true},
+		{name: "within max age", policy: PurgePolicy{MaxAge: 7 * 24 * time.Hour}, msg: recent, want: false},
+		{name: "beyond keep last", policy: PurgePolicy{KeepLast: 3}, msg: recent, newer: 3, want: true},
+		{name: "within keep last", policy: PurgePolicy{KeepLast: 3}, msg: old, newer: 2, want: false},
+		{name: "pinned", policy: PurgePolicy{KeepLast: 1}, msg: Message{ID: "3", Pinned: true}, newer: 5, want: false},
+		{name: "exempt user", policy: PurgePolicy{MaxAge: time.Hour, ExemptUserIDs: []string{"u1"}}, msg: old, want: false},
+		{name: "exempt role", policy: PurgePolicy{MaxAge: time.Hour, ExemptRoleIDs: []string{"r1"}}, msg: Message{ID: "4", AuthorRoleIDs: []string{"r1"}, Timestamp: old.Timestamp}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.policy.Selects(tt.msg, tt.newer, now); got != tt.want {
+				t.Fatalf("Selects = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
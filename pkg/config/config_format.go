@@ -0,0 +1,302 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies the on-disk encoding of a settings document.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+// DetectFormat infers the settings encoding from the file extension.
+// Unknown or missing extensions fall back to FormatJSON.
+func DetectFormat(path string) Format {
+	switch strings.ToLower(filepath.Ext(strings.TrimSpace(path))) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	default:
+		return FormatJSON
+	}
+}
+
+// ParseFormat resolves an operator-supplied format name. Matching is
+// case-insensitive and accepts "yml" as an alias for YAML.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("unsupported config format %q", name)
+	}
+}
+
+// decodeBotConfig parses data in the given format into cfg, migrating the
+// document up to files.CurrentSchemaVersion first, and returns the schema
+// version it was written in.
+//
+// Every format is decoded into generic values and normalized through JSON so
+// that the migrations see one document shape and the json struct tags and
+// custom unmarshal hooks on files.BotConfig remain the single source of truth
+// for field naming.
+func decodeBotConfig(format Format, data []byte, cfg *files.BotConfig) (int, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return files.CurrentSchemaVersion, nil
+	}
+
+	var generic any
+	switch format {
+	case FormatJSON, "":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&generic); err != nil {
+			return 0, fmt.Errorf("failed to unmarshal json: %w", err)
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &generic); err != nil {
+			return 0, fmt.Errorf("failed to unmarshal yaml: %w", err)
+		}
+		generic = yamlValue(generic)
+	case FormatTOML:
+		if err := toml.Unmarshal(data, &generic); err != nil {
+			return 0, fmt.Errorf("failed to unmarshal toml: %w", err)
+		}
+	default:
+		return 0, fmt.Errorf("unsupported config format %q", format)
+	}
+
+	doc, ok := generic.(map[string]any)
+	if !ok {
+		return 0, fmt.Errorf("%s settings document is not an object", format)
+	}
+	from, err := files.MigrateSettings(doc)
+	if err != nil {
+		return from, err
+	}
+
+	normalized, err := json.Marshal(doc)
+	if err != nil {
+		return from, fmt.Errorf("failed to normalize %s document: %w", format, err)
+	}
+	if err := json.Unmarshal(normalized, cfg); err != nil {
+		return from, fmt.Errorf("failed to unmarshal %s document: %w", format, err)
+	}
+	return from, nil
+}
+
+// encodeBotConfig renders cfg in the given format. For YAML, comments found in
+// previous (the current on-disk revision) are carried over onto matching keys
+// and sequence entries. TOML output does not retain comments.
+//
+// The document is always stamped with files.CurrentSchemaVersion, the layout
+// it is written in.
+func encodeBotConfig(format Format, cfg *files.BotConfig, previous []byte) ([]byte, error) {
+	stamped := *cfg
+	stamped.SchemaVersion = files.CurrentSchemaVersion
+	jsonData, err := json.MarshalIndent(&stamped, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal json: %w", err)
+	}
+
+	switch format {
+	case FormatJSON, "":
+		return jsonData, nil
+	case FormatYAML:
+		return encodeYAML(jsonData, previous)
+	case FormatTOML:
+		return encodeTOML(jsonData)
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", format)
+	}
+}
+
+func encodeYAML(jsonData, previous []byte) ([]byte, error) {
+	// JSON is a subset of YAML, so parsing it into a node keeps the key order
+	// produced by the struct definitions instead of sorting map keys.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(jsonData, &doc); err != nil {
+		return nil, fmt.Errorf("failed to build yaml document: %w", err)
+	}
+	clearFlowStyle(&doc)
+
+	if len(bytes.TrimSpace(previous)) > 0 {
+		var prior yaml.Node
+		if err := yaml.Unmarshal(previous, &prior); err == nil {
+			transferComments(&prior, &doc)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to marshal yaml: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal yaml: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func clearFlowStyle(node *yaml.Node) {
+	if node == nil {
+		return
+	}
+	node.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle
+	for _, child := range node.Content {
+		clearFlowStyle(child)
+	}
+}
+
+// transferComments copies head, line and foot comments from src onto the
+// structurally matching nodes of dst. Mapping entries match by key, sequence
+// entries by index; anything without a counterpart is dropped.
+func transferComments(src, dst *yaml.Node) {
+	if src == nil || dst == nil {
+		return
+	}
+	dst.HeadComment = src.HeadComment
+	dst.LineComment = src.LineComment
+	dst.FootComment = src.FootComment
+
+	switch {
+	case src.Kind == yaml.DocumentNode && dst.Kind == yaml.DocumentNode:
+		if len(src.Content) > 0 && len(dst.Content) > 0 {
+			transferComments(src.Content[0], dst.Content[0])
+		}
+	case src.Kind == yaml.MappingNode && dst.Kind == yaml.MappingNode:
+		prior := make(map[string][2]*yaml.Node, len(src.Content)/2)
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			prior[src.Content[i].Value] = [2]*yaml.Node{src.Content[i], src.Content[i+1]}
+		}
+		for i := 0; i+1 < len(dst.Content); i += 2 {
+			pair, ok := prior[dst.Content[i].Value]
+			if !ok {
+				continue
+			}
+			transferComments(pair[0], dst.Content[i])
+			transferComments(pair[1], dst.Content[i+1])
+		}
+	case src.Kind == yaml.SequenceNode && dst.Kind == yaml.SequenceNode:
+		for i := 0; i < len(src.Content) && i < len(dst.Content); i++ {
+			transferComments(src.Content[i], dst.Content[i])
+		}
+	}
+}
+
+// yamlValue adapts a generic YAML value to the shape JSON decoding gives.
+// YAML keys need not be strings: an unquoted snowflake key decodes as a
+// number, leaving a map[interface{}]interface{} that JSON cannot marshal, so
+// every key is turned into its string form.
+func yamlValue(v any) any {
+	switch typed := v.(type) {
+	case map[string]any:
+		for key, value := range typed {
+			typed[key] = yamlValue(value)
+		}
+		return typed
+	case map[any]any:
+		out := make(map[string]any, len(typed))
+		for key, value := range typed {
+			out[fmt.Sprint(key)] = yamlValue(value)
+		}
+		return out
+	case []any:
+		for i, value := range typed {
+			typed[i] = yamlValue(value)
+		}
+		return typed
+	default:
+		return v
+	}
+}
+
+func encodeTOML(jsonData []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.UseNumber()
+	var generic map[string]any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("failed to build toml document: %w", err)
+	}
+
+	doc, err := tomlValue(generic, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to build toml document: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to marshal toml: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// tomlValue adapts a generic JSON value at path for the TOML encoder, and
+// narrows json.Number to int64 or float64. TOML has no null: a nil field is
+// left out, as if unset, but a nil array element cannot be left out without
+// shifting the others, so it fails.
+func tomlValue(v any, path string) (any, error) {
+	switch typed := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(typed))
+		for key, value := range typed {
+			if value == nil {
+				continue
+			}
+			converted, err := tomlValue(value, joinTOMLPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			out[key] = converted
+		}
+		return out, nil
+	case []any:
+		out := make([]any, 0, len(typed))
+		for i, value := range typed {
+			elementPath := fmt.Sprintf("%s[%d]", path, i)
+			if value == nil {
+				return nil, fmt.Errorf("tomlValue: %s is null, which TOML cannot represent", elementPath)
+			}
+			converted, err := tomlValue(value, elementPath)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, converted)
+		}
+		return out, nil
+	case json.Number:
+		if n, err := typed.Int64(); err == nil {
+			return n, nil
+		}
+		if f, err := typed.Float64(); err == nil {
+			return f, nil
+		}
+		return typed.String(), nil
+	default:
+		return v, nil
+	}
+}
+
+func joinTOMLPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// DefaultFileConfigStoreBackups is the number of rotated settings backups retained
+// when a FileConfigStore is constructed without an explicit count.
+const DefaultFileConfigStoreBackups = 3
+
+// FileConfigStore persists files.BotConfig as a JSON, YAML or TOML document on the
+// local filesystem. The format is inferred from the file extension unless overridden
+// with WithFormat. YAML saves keep the comments of the previous revision where the
+// surrounding keys still exist.
+//
+// Concurrency: Safe for concurrent use. Saves are serialized and land atomically via
+// temp-file + rename, so readers never observe a partially written settings file.
+// Every save rotates the previous document into numbered backups (settings.json.1 …
+// settings.json.N) so that operators can recover from a bad mutation.
+type FileConfigStore struct {
+	mu      sync.Mutex
+	path    string
+	format  Format
+	manager *files.JSONManager
+	logger  *slog.Logger
+}
+
+// NewFileConfigStore binds a file-backed config store to path, retaining up to backups
+// rotated copies. A non-positive backups value falls back to DefaultFileConfigStoreBackups.
+func NewFileConfigStore(path string, backups int, logger *slog.Logger) *FileConfigStore {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if backups <= 0 {
+		backups = DefaultFileConfigStoreBackups
+	}
+	path = strings.TrimSpace(path)
+
+	logger.Info("Architectural state transition: Coupling of local filesystem storage adapter for configuration parameters",
+		slog.String("path", path),
+		slog.Int("backups", backups),
+	)
+
+	return &FileConfigStore{
+		path:    path,
+		format:  DetectFormat(path),
+		manager: &files.JSONManager{FilePath: path, Backups: backups},
+		logger:  logger,
+	}
+}
+
+// WithFormat overrides the extension-derived settings format.
+func (s *FileConfigStore) WithFormat(format Format) *FileConfigStore {
+	s.mu.Lock()
+	s.format = format
+	s.mu.Unlock()
+	return s
+}
+
+// Format reports the encoding used for the settings document.
+func (s *FileConfigStore) Format() Format {
+	if s == nil {
+		return FormatJSON
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.format
+}
+
+// Load reads the settings file. A missing file yields an empty configuration.
+func (s *FileConfigStore) Load() (*files.BotConfig, error) {
+	cfg := &files.BotConfig{Guilds: []files.GuildConfig{}}
+	if s == nil || s.manager == nil {
+		return cfg, fmt.Errorf("file config store is not configured")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.readLocked()
+	if err != nil {
+		return &files.BotConfig{Guilds: []files.GuildConfig{}}, fmt.Errorf("FileConfigStore.Load: %w", err)
+	}
+	from, err := decodeBotConfig(s.format, data, cfg)
+	if err != nil {
+		return &files.BotConfig{Guilds: []files.GuildConfig{}}, fmt.Errorf("FileConfigStore.Load: %w", err)
+	}
+	if from < files.CurrentSchemaVersion {
+		if err := s.keepPreMigrationLocked(data, from); err != nil {
+			return &files.BotConfig{Guilds: []files.GuildConfig{}}, fmt.Errorf("FileConfigStore.Load: %w", err)
+		}
+	}
+	if cfg.Guilds == nil {
+		cfg.Guilds = []files.GuildConfig{}
+	}
+	return cfg, nil
+}
+
+// Save atomically replaces the settings file, rotating the previous revision into backups.
+func (s *FileConfigStore) Save(cfg *files.BotConfig) error {
+	if cfg == nil {
+		return fmt.Errorf("cannot save nil config")
+	}
+	if s == nil || s.manager == nil {
+		return fmt.Errorf("file config store is not configured")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var previous []byte
+	if s.format == FormatYAML {
+		// Best effort: an unreadable prior revision only costs its comments.
+		previous, _ = s.readLocked()
+	}
+	data, err := encodeBotConfig(s.format, cfg, previous)
+	if err != nil {
+		return fmt.Errorf("FileConfigStore.Save: %w", err)
+	}
+	if err := s.manager.SaveRaw(data); err != nil {
+		return fmt.Errorf("FileConfigStore.Save: %w", err)
+	}
+	return nil
+}
+
+// keepPreMigrationLocked copies the settings as read, before migrating them
+// from schema version from, next to the live file. The migrated settings only
+// reach disk on the next save, so a copy already taken for this version is
+// kept as is. Callers must hold s.mu.
+func (s *FileConfigStore) keepPreMigrationLocked(data []byte, from int) error {
+	backupPath := files.MigrationBackupPath(s.path, from)
+	if _, err := os.Stat(backupPath); err == nil {
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("stat pre-migration backup: %w", err)
+	}
+	fileMode := os.FileMode(0o644)
+	if info, err := os.Stat(s.path); err == nil {
+		fileMode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(backupPath, data, fileMode); err != nil {
+		return fmt.Errorf("write pre-migration backup: %w", err)
+	}
+	s.logger.Info("Architectural state transition: Settings migrated to the current schema, previous file kept",
+		slog.String("path", s.path),
+		slog.Int("from_version", from),
+		slog.Int("to_version", files.CurrentSchemaVersion),
+		slog.String("backup", backupPath),
+	)
+	return nil
+}
+
+// readLocked returns the raw settings bytes, or nil when the file does not exist.
+// Callers must hold s.mu.
+func (s *FileConfigStore) readLocked() ([]byte, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data, nil
+}
+
+// Exists reports whether the settings file is present on disk.
+func (s *FileConfigStore) Exists() (bool, error) {
+	if s == nil || s.path == "" {
+		return false, nil
+	}
+	if _, err := os.Stat(s.path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("FileConfigStore.Exists: %w", err)
+	}
+	return true, nil
+}
+
+// Describe returns the file URI of the settings document.
+func (s *FileConfigStore) Describe() string {
+	if s == nil {
+		return "file://"
+	}
+	return "file://" + s.path
+}
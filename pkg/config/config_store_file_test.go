@@ -0,0 +1,280 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFileConfigStoreRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "settings.json")
+	store := NewFileConfigStore(path, 2, nil)
+
+	exists, err := store.Exists()
+	if err != nil {
+		t.Fatalf("exists before save: %v", err)
+	}
+	if exists {
+		t.Fatal("expected missing settings file to report exists=false")
+	}
+
+	empty, err := store.Load()
+	if err != nil {
+		t.Fatalf("load missing file: %v", err)
+	}
+	if empty == nil || len(empty.Guilds) != 0 {
+		t.Fatalf("expected empty config for missing file, got %+v", empty)
+	}
+
+	for _, commands := range []string{"c1", "c2", "c3"} {
+		cfg := &files.BotConfig{Guilds: []files.GuildConfig{{
+			GuildID:  "g1",
+			Channels: files.ChannelsConfig{Commands: commands},
+		}}}
+		if err := store.Save(cfg); err != nil {
+			t.Fatalf("save %s: %v", commands, err)
+		}
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if len(loaded.Guilds) != 1 || loaded.Guilds[0].Channels.Commands != "c3" {
+		t.Fatalf("unexpected loaded config: %+v", loaded)
+	}
+
+	for _, n := range []int{1, 2} {
+		if _, err := os.Stat(files.BackupPath(path, n)); err != nil {
+			t.Fatalf("expected backup %d to exist: %v", n, err)
+		}
+	}
+	if _, err := os.Stat(files.BackupPath(path, 3)); !os.IsNotExist(err) {
+		t.Fatalf("expected rotation to cap backups at 2, stat err=%v", err)
+	}
+}
+
+func TestFileConfigStoreConcurrentSaves(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "settings.json")
+	store := NewFileConfigStore(path, 1, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cfg := &files.BotConfig{Guilds: []files.GuildConfig{{GuildID: "g1"}}}
+			if err := store.Save(cfg); err != nil {
+				t.Errorf("concurrent save: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("load after concurrent saves: %v", err)
+	}
+	if len(loaded.Guilds) != 1 || loaded.Guilds[0].GuildID != "g1" {
+		t.Fatalf("unexpected loaded config: %+v", loaded)
+	}
+}
+
+func TestFileConfigStoreAlternateFormatsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"settings.yaml", "settings.yml", "settings.toml"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), name)
+			store := NewFileConfigStore(path, 1, nil)
+
+			cfg := &files.BotConfig{Guilds: []files.GuildConfig{{
+				GuildID:  "123456789012345678",
+				Channels: files.ChannelsConfig{Commands: "c1"},
+			}}}
+			if err := store.Save(cfg); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read saved file: %v", err)
+			}
+			if strings.HasPrefix(strings.TrimSpace(string(raw)), "{") {
+				t.Fatalf("expected %s output, got JSON:\n%s", store.Format(), raw)
+			}
+
+			loaded, err := store.Load()
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if len(loaded.Guilds) != 1 ||
+				loaded.Guilds[0].GuildID != "123456789012345678" ||
+				loaded.Guilds[0].Channels.Commands != "c1" {
+				t.Fatalf("unexpected loaded config: %+v", loaded)
+			}
+		})
+	}
+}
+
+func TestFileConfigStoreYAMLPreservesComments(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	authored := `# operator managed settings
+guilds:
+  - guild_id: "g1" # primary guild
+    channels:
+      # where slash commands are allowed
+      commands: c1
+`
+	if err := os.WriteFile(path, []byte(authored), 0o644); err != nil {
+		t.Fatalf("write authored file: %v", err)
+	}
+
+	store := NewFileConfigStore(path, 1, nil)
+	cfg, err := store.Load()
+	if err != nil {
+		t.Fatalf("load authored file: %v", err)
+	}
+	if len(cfg.Guilds) != 1 || cfg.Guilds[0].Channels.Commands != "c1" {
+		t.Fatalf("unexpected authored config: %+v", cfg)
+	}
+
+	cfg.Guilds[0].Channels.Commands = "c2"
+	if err := store.Save(cfg); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read saved file: %v", err)
+	}
+	for _, want := range []string{
+		"# operator managed settings",
+		"# primary guild",
+		"# where slash commands are allowed",
+		"commands: c2",
+	} {
+		if !strings.Contains(string(raw), want) {
+			t.Fatalf("saved yaml missing %q:\n%s", want, raw)
+		}
+	}
+}
+
+func TestFileConfigStoreYAMLNumericKeys(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	authored := `guilds:
+  - guild_id: "g1"
+    bot_instance_statuses:
+      123456789012345678: disabled
+      7: online
+`
+	if err := os.WriteFile(path, []byte(authored), 0o644); err != nil {
+		t.Fatalf("write authored file: %v", err)
+	}
+
+	cfg, err := NewFileConfigStore(path, 1, nil).Load()
+	if err != nil {
+		t.Fatalf("load settings with unquoted numeric keys: %v", err)
+	}
+	statuses := cfg.Guilds[0].BotInstanceStatuses
+	if statuses["123456789012345678"] != "disabled" || statuses["7"] != "online" {
+		t.Fatalf("expected numeric keys read as strings, got %v", statuses)
+	}
+
+	// Migrations walk the generic document, so it must hold string keyed
+	// maps only, whatever the JSON encoder would tolerate.
+	var generic any
+	if err := yaml.Unmarshal([]byte(authored), &generic); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	guild := yamlValue(generic).(map[string]any)["guilds"].([]any)[0].(map[string]any)
+	if _, ok := guild["bot_instance_statuses"].(map[string]any); !ok {
+		t.Fatalf("expected numeric keyed map normalized, got %T", guild["bot_instance_statuses"])
+	}
+}
+
+func TestEncodeTOMLRefusesNullArrayElements(t *testing.T) {
+	t.Parallel()
+
+	_, err := encodeTOML([]byte(`{"guilds": [{"guild_id": "g1", "disabled_commands": ["stats", null]}]}`))
+	if err == nil || !strings.Contains(err.Error(), "guilds[0].disabled_commands[1]") {
+		t.Fatalf("expected the null element to be refused by path, got %v", err)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string]Format{"JSON": FormatJSON, " yml ": FormatYAML, "toml": FormatTOML} {
+		got, err := ParseFormat(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseFormat(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("ini"); err == nil {
+		t.Fatal("expected unsupported format to fail")
+	}
+}
+
+func TestFileConfigStoreMigratesUnversionedSettings(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "settings.json")
+	legacy := []byte(`{"config_version": 1, "guilds": [{"guild_id": "g1", "channels": {"commands": "c1"}}]}`)
+	if err := os.WriteFile(path, legacy, 0o600); err != nil {
+		t.Fatalf("write legacy settings: %v", err)
+	}
+	store := NewFileConfigStore(path, 1, nil)
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("load legacy settings: %v", err)
+	}
+	if len(loaded.Guilds) != 1 || loaded.Guilds[0].Channels.Commands != "c1" {
+		t.Fatalf("unexpected migrated config: %+v", loaded)
+	}
+	backupPath := files.MigrationBackupPath(path, 0)
+	backup, err := os.ReadFile(backupPath)
+	if err != nil || string(backup) != string(legacy) {
+		t.Fatalf("expected the pre-migration file kept at %s, got %q, %v", backupPath, backup, err)
+	}
+	if info, err := os.Stat(backupPath); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected the backup to keep the settings file mode, got %v, %v", info, err)
+	}
+
+	if err := store.Save(loaded); err != nil {
+		t.Fatalf("save migrated settings: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(raw), `"schema_version": 1`) {
+		t.Fatalf("expected saved settings stamped with the schema version, got %s, %v", raw, err)
+	}
+}
+
+func TestFileConfigStoreRefusesNewerSettings(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte(`{"schema_version": 999, "guilds": []}`), 0o644); err != nil {
+		t.Fatalf("write settings: %v", err)
+	}
+	if _, err := NewFileConfigStore(path, 1, nil).Load(); !errors.Is(err, files.ErrSettingsSchemaTooNew) {
+		t.Fatalf("expected newer settings to be refused, got %v", err)
+	}
+}
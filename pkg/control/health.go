@@ -3,6 +3,8 @@ package control
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/small-frappuccino/discordcore/pkg/observability"
 )
 
 // serveHealthRoute constructs an HTTP handler that evaluates a health resolver and securely serializes the operational state into JSON.
@@ -45,3 +47,13 @@ func (s *Server) cacheHealthResolver() interface{} {
 	}
 	return s.cacheObservability()
 }
+
+// panicsHealthResolver reports the panics recovered at handler boundaries.
+// A non-zero count means the process survived a bug that still needs fixing.
+func (s *Server) panicsHealthResolver() interface{} {
+	snapshot := observability.SnapshotRecoveredPanics()
+	return map[string]any{
+		"total":    snapshot.Total(),
+		"handlers": snapshot,
+	}
+}
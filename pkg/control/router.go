@@ -21,6 +21,7 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /v1/health/qotd", serveHealthRoute(s.qotdHealthResolver))
 	mux.HandleFunc("GET /v1/health/moderation", serveHealthRoute(s.moderationHealthResolver))
 	mux.HandleFunc("GET /v1/health/cache", serveHealthRoute(s.cacheHealthResolver))
+	mux.HandleFunc("GET /v1/health/panics", serveHealthRoute(s.panicsHealthResolver))
 
 	// OAuth Routes
 	mux.HandleFunc("GET /auth/discord/login", s.handleOAuthLogin)
@@ -103,7 +103,7 @@ func (a *ArikawaAdapter) Start(ctx context.Context) error {
 	a.startTime = time.Now()
 
 	if a.state != nil {
-		a.handlerCancel = a.state.AddHandler(a.handleRawOp)
+		a.handlerCancel = a.state.AddHandler(perf.GuardGatewayHandler("automod.raw_op", a.handleRawOp))
 	}
 	a.mu.Unlock()
 
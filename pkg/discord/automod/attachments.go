@@ -0,0 +1,114 @@
+package automod
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/messages"
+)
+
+// Names the attachment filter reports its checks under.
+const (
+	attachmentTypeRule = "attachment-type"
+	attachmentSizeRule = "attachment-size"
+)
+
+const (
+	// maxAttachmentHashSize bounds the files downloaded to be hashed; larger
+	// ones are logged by name and size alone.
+	maxAttachmentHashSize = 25 << 20
+
+	// attachmentHashTimeout bounds the download, which starts alongside the
+	// deletion of the message so the file is still there to fetch.
+	attachmentHashTimeout = 10 * time.Second
+)
+
+// attachmentCheck returns the first file the rules block, with the check it
+// fails and why.
+func attachmentCheck(rules files.AttachmentRules, attachments []messages.MessageAttachment) (f messages.MessageAttachment, rule, why string, ok bool) {
+	for _, f := range attachments {
+		switch {
+		case rules.BlocksExtension(f.Filename):
+			return f, attachmentTypeRule, "blocked extension " + strings.ToLower(path.Ext(f.Filename)), true
+		case rules.BlocksContentType(f.ContentType):
+			return f, attachmentTypeRule, "blocked content type " + f.ContentType, true
+		case rules.MaxSizeMB > 0 && f.Size > rules.MaxSizeBytes():
+			return f, attachmentSizeRule, fmt.Sprintf("%.1f MB (limit %d MB)", float64(f.Size)/(1<<20), rules.MaxSizeMB), true
+		}
+	}
+	return messages.MessageAttachment{}, "", "", false
+}
+
+// enforceAttachment deletes m for carrying f, going by its name and type, and
+// when the guild sets a timeout, times its author out. The file is hashed
+// meanwhile, and the filename and SHA-256 of f are reported as the matched
+// content, so the automod log identifies the file.
+func (e *RuleEngine) enforceAttachment(ctx context.Context, guild *files.GuildConfig, cfg files.AttachmentFilterConfig, m messages.MessageCreateIntent, f messages.MessageAttachment, rule, why string) {
+	if err := e.authorize(ctx, guild, m, e.botID()); err != nil {
+		e.logSkipped(rule, m, err)
+		return
+	}
+	filename := truncateRunes(f.Filename, maxRuleMatchLength)
+	detail := fmt.Sprintf("%s, %s", filename, why)
+	hashed := make(chan string, 1)
+	go func() {
+		sum, err := e.hashAttachment(ctx, f)
+		if err != nil {
+			e.logger.Debug("Automod attachment filter could not hash a file",
+				slog.String("guild_id", m.GuildID),
+				slog.String("filename", f.Filename),
+				slog.String("error", err.Error()),
+			)
+			hashed <- detail
+			return
+		}
+		hashed <- detail + "\nsha256 " + sum
+	}()
+	e.enforceFilter(ctx, "attachment filter", m, rule, detail, func() string { return <-hashed }, cfg.TimeoutMinutes)
+}
+
+// hashAttachment downloads f and returns its hex SHA-256.
+func (e *RuleEngine) hashAttachment(ctx context.Context, f messages.MessageAttachment) (string, error) {
+	if f.URL == "" {
+		return "", fmt.Errorf("attachment has no URL")
+	}
+	if f.Size > maxAttachmentHashSize {
+		return "", fmt.Errorf("file is larger than %d MiB", maxAttachmentHashSize>>20)
+	}
+	ctx, cancel := context.WithTimeout(ctx, attachmentHashTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("RuleEngine.hashAttachment: %w", err)
+	}
+	client := e.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("download returned status %d", resp.StatusCode)
+	}
+	h := sha256.New()
+	n, err := io.Copy(h, io.LimitReader(resp.Body, maxAttachmentHashSize+1))
+	if err != nil {
+		return "", fmt.Errorf("download failed: %w", err)
+	}
+	if n > maxAttachmentHashSize {
+		return "", fmt.Errorf("file is larger than %d MiB", maxAttachmentHashSize>>20)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,89 @@
+package automod
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/automod"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/messages"
+)
+
+func TestAttachmentCheck(t *testing.T) {
+	t.Parallel()
+
+	rules := files.AttachmentRules{BlockedExtensions: []string{"exe"}, BlockedContentTypes: []string{"video/*"}, MaxSizeMB: 8}
+	for name, tc := range map[string]struct {
+		file messages.MessageAttachment
+		rule string
+		why  string
+	}{
+		"extension":    {messages.MessageAttachment{Filename: "Setup.EXE"}, attachmentTypeRule, "blocked extension .exe"},
+		"content type": {messages.MessageAttachment{Filename: "clip", ContentType: "video/mp4"}, attachmentTypeRule, "blocked content type video/mp4"},
+		"size":         {messages.MessageAttachment{Filename: "a.png", Size: 12 << 20}, attachmentSizeRule, "12.0 MB (limit 8 MB)"},
+		"allowed":      {messages.MessageAttachment{Filename: "a.png", ContentType: "image/png", Size: 1 << 20}, "", ""},
+	} {
+		_, rule, why, ok := attachmentCheck(rules, []messages.MessageAttachment{tc.file})
+		if ok != (tc.rule != "") || rule != tc.rule || why != tc.why {
+			t.Errorf("%s: got rule %q, why %q, ok %v", name, rule, why, ok)
+		}
+	}
+}
+
+func TestRuleEngine_AttachmentFilter(t *testing.T) {
+	t.Parallel()
+	payload := []byte("MZ not really a program")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	client := &fakeRuleClient{}
+	sink := &recordingSink{}
+	engine := NewRuleEngine(client, &fakeRuleStore{}, sink, nil)
+	engine.httpClient = server.Client()
+	engine.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+
+	guild := &files.GuildConfig{GuildID: "100", AttachmentFilter: files.AttachmentFilterConfig{
+		BlockExecutables: true,
+		AttachmentRules:  files.AttachmentRules{MaxSizeMB: 8},
+		ChannelOverrides: []files.AttachmentFilterOverride{{ChannelID: "8", AttachmentRules: files.AttachmentRules{MaxSizeMB: 50}}},
+		TimeoutMinutes:   10,
+	}}
+	post := func(id, channelID string, f messages.MessageAttachment) {
+		engine.InspectMessageCreate(context.Background(), guild, messages.MessageCreateIntent{
+			GuildID: "100", ChannelID: channelID, MessageID: id, AuthorID: "42", Attachments: 1, Files: []messages.MessageAttachment{f},
+		})
+	}
+
+	post("1", "7", messages.MessageAttachment{Filename: "cat.png", ContentType: "image/png", Size: 1 << 20, URL: server.URL})
+	post("2", "8", messages.MessageAttachment{Filename: "video.mp4", ContentType: "video/mp4", Size: 20 << 20, URL: server.URL})
+	if len(client.deleted) != 0 || len(sink.events) != 0 {
+		t.Fatal("allowed files, and large files in the overriding channel, should be left alone")
+	}
+
+	post("3", "8", messages.MessageAttachment{Filename: "free-nitro.exe", Size: uint64(len(payload)), URL: server.URL})
+	if len(client.deleted) != 1 || len(client.timeouts) != 1 || len(sink.events) != 2 {
+		t.Fatalf("expected a delete and a timeout, got %d, %d, %d events", len(client.deleted), len(client.timeouts), len(sink.events))
+	}
+	sum := sha256.Sum256(payload)
+	block := sink.events[0]
+	if block.Action.Type != automod.ActionBlockMessage || block.MatchedKeyword != attachmentTypeRule ||
+		!strings.Contains(block.MatchedContent, "free-nitro.exe") || !strings.Contains(block.MatchedContent, hex.EncodeToString(sum[:])) {
+		t.Fatalf("expected the filename and hash to be reported, got %+v", block)
+	}
+
+	post("4", "7", messages.MessageAttachment{Filename: "video.mp4", ContentType: "video/mp4", Size: 30 << 20, URL: server.URL})
+	if len(client.deleted) != 2 || sink.events[2].MatchedKeyword != attachmentSizeRule {
+		t.Fatalf("expected the oversized file to be deleted, got %+v", sink.events[2:])
+	}
+	if strings.Contains(sink.events[2].MatchedContent, "sha256") {
+		t.Fatal("files too large to hash should be reported without a hash")
+	}
+}
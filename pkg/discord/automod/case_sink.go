@@ -0,0 +1,118 @@
+package automod
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/automod"
+	"github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+// Case actions recorded for AutoMod executions.
+const (
+	CaseActionBlock   = "automod_block"
+	CaseActionTimeout = "automod_timeout"
+)
+
+// CaseRecorder persists numbered moderation cases. *postgres.Store satisfies
+// it.
+type CaseRecorder interface {
+	CreateModerationCase(ctx context.Context, c moderation.Case) (moderation.Case, error)
+}
+
+// CaseSink records a moderation case for every message AutoMod blocks and
+// every timeout it applies. Alert actions are skipped: Discord reports each
+// action of a rule separately, and the alert duplicates the block. Actions
+// simulated in test mode record nothing.
+type CaseSink struct {
+	recorder CaseRecorder
+	logger   *slog.Logger
+}
+
+// NewCaseSink creates a CaseSink writing through recorder.
+func NewCaseSink(recorder CaseRecorder, logger *slog.Logger) *CaseSink {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &CaseSink{recorder: recorder, logger: logger}
+}
+
+// OnAutomodBlock implements automod.Sink.
+func (s *CaseSink) OnAutomodBlock(ctx context.Context, guildID discord.GuildID, entry *automod.ExecutionEvent) {
+	if s == nil || s.recorder == nil || entry == nil || !entry.UserID.IsValid() || moderation.InTestMode(ctx) {
+		return
+	}
+	c, ok := caseFromExecution(guildID, entry)
+	if !ok {
+		return
+	}
+	created, err := s.recorder.CreateModerationCase(ctx, c)
+	if err != nil {
+		s.logger.Warn("Mitigated service degradation: AutoMod case could not be recorded",
+			slog.String("guild_id", guildID.String()),
+			slog.String("user_id", entry.UserID.String()),
+			slog.String("rule_id", entry.RuleID.String()),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	s.logger.Info("Architectural state transition: AutoMod case recorded",
+		slog.String("guild_id", guildID.String()),
+		slog.String("user_id", entry.UserID.String()),
+		slog.Int64("case_number", created.CaseNumber),
+		slog.String("action", created.Action),
+	)
+}
+
+// caseFromExecution maps an execution event to the case it produces, if any.
+func caseFromExecution(guildID discord.GuildID, entry *automod.ExecutionEvent) (moderation.Case, bool) {
+	var action string
+	switch entry.Action.Type {
+	case automod.ActionBlockMessage:
+		action = CaseActionBlock
+	case automod.ActionTimeout:
+		action = CaseActionTimeout
+	default:
+		return moderation.Case{}, false
+	}
+
+	// Actions the bot takes itself, like those of the spam filter, have no
+	// rule ID.
+	reason := "AutoMod"
+	if entry.RuleID.IsValid() {
+		reason = fmt.Sprintf("AutoMod rule %s", entry.RuleID)
+	}
+	if entry.MatchedKeyword != "" {
+		reason += fmt.Sprintf(" matched %q", entry.MatchedKeyword)
+	}
+	if action == CaseActionTimeout && entry.Action.Metadata.DurationSecs > 0 {
+		reason += fmt.Sprintf(" (timeout %ds)", entry.Action.Metadata.DurationSecs)
+	}
+
+	c := moderation.Case{
+		GuildID:        guildID.String(),
+		Action:         action,
+		UserID:         entry.UserID.String(),
+		Reason:         reason,
+		Source:         moderation.CaseSourceAutomod,
+		MatchedKeyword: entry.MatchedKeyword,
+		MatchedContent: entry.MatchedContent,
+		Content:        entry.Content,
+	}
+	if entry.RuleID.IsValid() {
+		c.RuleID = entry.RuleID.String()
+	}
+	if entry.ChannelID.IsValid() {
+		c.ChannelID = entry.ChannelID.String()
+	}
+	if entry.MessageID.IsValid() {
+		c.MessageID = entry.MessageID.String()
+	}
+	if action == CaseActionTimeout && entry.Action.Metadata.DurationSecs > 0 {
+		c.ExpiresAt = time.Now().Add(time.Duration(entry.Action.Metadata.DurationSecs) * time.Second)
+	}
+	return c, true
+}
@@ -0,0 +1,57 @@
+package automod
+
+import (
+	"context"
+	"testing"
+
+	"github.com/small-frappuccino/discordcore/pkg/automod"
+	"github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+type fakeCaseRecorder struct {
+	cases []moderation.Case
+}
+
+func (f *fakeCaseRecorder) CreateModerationCase(ctx context.Context, c moderation.Case) (moderation.Case, error) {
+	c.CaseNumber = int64(len(f.cases) + 1)
+	f.cases = append(f.cases, c)
+	return c, nil
+}
+
+func TestCaseSink_RecordsBlocksAndTimeouts(t *testing.T) {
+	t.Parallel()
+	rec := &fakeCaseRecorder{}
+	sink := NewCaseSink(rec, nil)
+
+	base := automod.ExecutionEvent{
+		GuildID:        100,
+		RuleID:         5,
+		UserID:         42,
+		ChannelID:      7,
+		Content:        "buy cheap nitro",
+		MatchedKeyword: "nitro",
+		MatchedContent: "nitro",
+	}
+	for _, actionType := range []int{automod.ActionBlockMessage, automod.ActionSendAlertMessage, automod.ActionTimeout} {
+		e := base
+		e.Action.Type = actionType
+		sink.OnAutomodBlock(context.Background(), e.GuildID, &e)
+	}
+
+	if len(rec.cases) != 2 {
+		t.Fatalf("expected block and timeout cases, got %d", len(rec.cases))
+	}
+	block := rec.cases[0]
+	if block.Action != CaseActionBlock || block.Source != moderation.CaseSourceAutomod {
+		t.Fatalf("unexpected block case: %+v", block)
+	}
+	if block.RuleID != "5" || block.MatchedKeyword != "nitro" || block.Content != "buy cheap nitro" || block.ChannelID != "7" {
+		t.Fatalf("trigger details not recorded: %+v", block)
+	}
+	if block.MessageID != "" {
+		t.Fatalf("expected empty message ID, got %q", block.MessageID)
+	}
+	if rec.cases[1].Action != CaseActionTimeout {
+		t.Fatalf("unexpected second case: %+v", rec.cases[1])
+	}
+}
@@ -0,0 +1,119 @@
+package automod
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+const (
+	// inviteCacheTTL is how long the guild an invite points to is
+	// remembered. Invites rarely change guilds, but they do expire.
+	inviteCacheTTL = time.Hour
+
+	// maxCachedInvites bounds the invite cache; it is emptied when full.
+	maxCachedInvites = 1024
+
+	// maxInvitesPerMessage bounds the invites resolved for one message.
+	maxInvitesPerMessage = 5
+
+	// inviteLookupTimeout bounds resolving one invite, which holds up the
+	// inspection of the message.
+	inviteLookupTimeout = 3 * time.Second
+)
+
+var inviteLinkRE = regexp.MustCompile(files.InviteLinkPattern)
+
+// inviteTarget is what an invite code resolved to. Dead invites no longer
+// lead anywhere.
+type inviteTarget struct {
+	guildID    string
+	dead       bool
+	resolvedAt time.Time
+}
+
+type inviteCache struct {
+	mu      sync.Mutex
+	targets map[string]inviteTarget
+}
+
+// foreignInvite returns the first link in content to an invite of another
+// guild than guild that the filter does not allow. Invites that cannot be
+// resolved for a reason other than being dead, e.g. while Discord is slow or
+// rate limits the lookups, are let through rather than deleting messages
+// that may be fine.
+func (e *RuleEngine) foreignInvite(ctx context.Context, guild *files.GuildConfig, content string) (string, bool) {
+	for _, match := range inviteLinkRE.FindAllStringSubmatch(content, maxInvitesPerMessage) {
+		code := match[1]
+		if guild.InviteFilter.Allows(code) {
+			continue
+		}
+		target, err := e.resolveInvite(ctx, code)
+		if err != nil {
+			e.logger.Debug("Automod invite could not be resolved; letting it through",
+				slog.String("guild_id", guild.GuildID),
+				slog.String("code", code),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		if !target.dead && target.guildID != guild.GuildID {
+			return match[0], true
+		}
+	}
+	return "", false
+}
+
+// resolveInvite looks code up, from the cache when it is fresh.
+func (e *RuleEngine) resolveInvite(ctx context.Context, code string) (inviteTarget, error) {
+	now := e.now()
+	e.invites.mu.Lock()
+	target, ok := e.invites.targets[code]
+	e.invites.mu.Unlock()
+	if ok && now.Sub(target.resolvedAt) < inviteCacheTTL {
+		return target, nil
+	}
+
+	target = inviteTarget{resolvedAt: now}
+	invite, err := e.lookupInvite(ctx, code)
+	var httpErr *httputil.HTTPError
+	switch {
+	case errors.As(err, &httpErr) && httpErr.Status == http.StatusNotFound:
+		target.dead = true
+	case err != nil:
+		return inviteTarget{}, err
+	case invite.Guild == nil:
+		// Group DM invites belong to no guild.
+		target.dead = true
+	default:
+		target.guildID = invite.Guild.ID.String()
+	}
+
+	e.invites.mu.Lock()
+	if e.invites.targets == nil || len(e.invites.targets) >= maxCachedInvites {
+		e.invites.targets = make(map[string]inviteTarget)
+	}
+	e.invites.targets[code] = target
+	e.invites.mu.Unlock()
+	return target, nil
+}
+
+// lookupInvite asks Discord for code within inviteLookupTimeout. The timeout
+// only reaches clients that take a context, like *state.State.
+func (e *RuleEngine) lookupInvite(ctx context.Context, code string) (*discord.Invite, error) {
+	ctx, cancel := context.WithTimeout(ctx, inviteLookupTimeout)
+	defer cancel()
+	if s, ok := e.client.(*state.State); ok {
+		return s.WithContext(ctx).Invite(code)
+	}
+	return e.client.Invite(code)
+}
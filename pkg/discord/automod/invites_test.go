@@ -0,0 +1,75 @@
+package automod
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/messages"
+)
+
+func TestRuleEngine_InviteFilter(t *testing.T) {
+	t.Parallel()
+	client := &fakeRuleClient{invites: map[string]discord.GuildID{"home": 100, "Raid": 200, "partner": 300}}
+	store := &fakeRuleStore{}
+	engine := NewRuleEngine(client, store, nil, nil)
+
+	guild := &files.GuildConfig{GuildID: "100", InviteFilter: files.InviteFilterConfig{
+		Enabled:          true,
+		AllowedCodes:     []string{"partner"},
+		ExemptChannelIDs: []string{"8"},
+	}}
+	post := func(id, channelID, content string) {
+		engine.InspectMessageCreate(context.Background(), guild, messages.MessageCreateIntent{
+			GuildID: "100", ChannelID: channelID, MessageID: id, AuthorID: "42", Content: content,
+		})
+	}
+
+	post("1", "7", "come to https://discord.gg/home or discord.com/invite/partner")
+	post("2", "7", "this one expired: discord.gg/gone")
+	post("3", "8", "discord.gg/Raid")
+	if len(client.deleted) != 0 {
+		t.Fatalf("own, allowed, dead and exempt invites should be left alone, deleted %v", client.deleted)
+	}
+
+	post("4", "7", "join discord.gg/home and DISCORD.GG/Raid")
+	if len(client.deleted) != 1 || client.deleted[0] != 4 {
+		t.Fatalf("expected the foreign invite to be deleted, got %v", client.deleted)
+	}
+	if len(store.cases) != 1 || store.cases[0].RuleID != files.InviteFilterRuleName || store.cases[0].MatchedKeyword != "DISCORD.GG/Raid" {
+		t.Fatalf("unexpected cases %+v", store.cases)
+	}
+
+	lookups := client.lookups
+	post("5", "7", "discord.gg/Raid")
+	if client.lookups != lookups {
+		t.Fatal("a known invite should be served from the cache")
+	}
+}
+
+func TestRuleEngine_InviteFilterFailsOpen(t *testing.T) {
+	t.Parallel()
+	client := &fakeRuleClient{inviteErr: &httputil.HTTPError{Status: http.StatusTooManyRequests}}
+	engine := NewRuleEngine(client, &fakeRuleStore{}, nil, nil)
+
+	guild := &files.GuildConfig{GuildID: "100", InviteFilter: files.InviteFilterConfig{Enabled: true}}
+	engine.InspectMessageCreate(context.Background(), guild, messages.MessageCreateIntent{
+		GuildID: "100", ChannelID: "7", MessageID: "1", AuthorID: "42", Content: "discord.gg/Raid",
+	})
+	if len(client.deleted) != 0 {
+		t.Fatalf("an invite that could not be resolved should be let through, deleted %v", client.deleted)
+	}
+
+	// The failure is not cached, so the invite is enforced once it resolves.
+	client.inviteErr = nil
+	client.invites = map[string]discord.GuildID{"Raid": 200}
+	engine.InspectMessageCreate(context.Background(), guild, messages.MessageCreateIntent{
+		GuildID: "100", ChannelID: "7", MessageID: "2", AuthorID: "42", Content: "discord.gg/Raid",
+	})
+	if len(client.deleted) != 1 || client.deleted[0] != 2 {
+		t.Fatalf("expected the resolved foreign invite to be deleted, got %v", client.deleted)
+	}
+}
@@ -0,0 +1,490 @@
+package automod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/automod"
+	discordlogging "github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/messages"
+	"github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const (
+	// ruleCooldown keeps a member who trips a rule with several messages in a
+	// row from collecting a warning and a timeout for each. Deleting and
+	// flagging still happen for every message.
+	ruleCooldown = time.Minute
+
+	// maxRuleMatchLength bounds the matched text quoted in reasons and
+	// flags.
+	maxRuleMatchLength = 100
+
+	// maxCompiledRules bounds the compiled expression cache; it is emptied
+	// when edited rules leave it full of stale expressions.
+	maxCompiledRules = 512
+)
+
+// RuleClient is the part of *state.State the rule engine acts through.
+type RuleClient interface {
+	Me() (*discord.User, error)
+	DeleteMessage(channelID discord.ChannelID, messageID discord.MessageID, reason api.AuditLogReason) error
+	ModifyMember(guildID discord.GuildID, userID discord.UserID, data api.ModifyMemberData) error
+	Invite(code string) (*discord.Invite, error)
+}
+
+// RuleStore records what rules do as cases and warnings. *postgres.Store
+// satisfies it.
+type RuleStore interface {
+	CaseRecorder
+	CreateModerationWarningCase(ctx context.Context, c moderation.Case) (moderation.Warning, error)
+	CountModerationWarnings(ctx context.Context, guildID, userID string) (int, error)
+}
+
+// RuleEngine enforces the content rules guilds manage with /automod, the
+// invite filter, which acts as a rule of its own, and the spam and attachment
+// filters. Deleted messages and timeouts are recorded as cases like those of
+// Discord's native AutoMod, with the rule name as the rule ID; warnings are
+// recorded as regular warnings so they count toward escalation. The spam and
+// attachment filters report to the sink instead, as Discord's AutoMod does.
+type RuleEngine struct {
+	client RuleClient
+	store  RuleStore
+	// mod authorizes actions against the guild hierarchy and applies warning
+	// escalation. Without it only protected roles and users are spared, and
+	// automod warnings count toward the next escalation /warn applies.
+	mod *discordmod.Service
+	// logs posts rule flags. Without it rules flag nothing.
+	logs   discordlogging.Publisher
+	sink   automod.Sink
+	logger *slog.Logger
+	now    func() time.Time
+
+	// httpClient downloads the files the attachment filter hashes; nil uses
+	// http.DefaultClient.
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	selfID   string
+	compiled map[string]*regexp.Regexp
+	punished map[string]time.Time
+	invites  inviteCache
+}
+
+var _ messages.MessageCreateInspector = (*RuleEngine)(nil)
+
+// NewRuleEngine creates a RuleEngine acting through client. A nil store
+// skips recording cases and warnings, and a nil sink skips reporting what the
+// spam and attachment filters do.
+func NewRuleEngine(client RuleClient, store RuleStore, sink automod.Sink, logger *slog.Logger) *RuleEngine {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &RuleEngine{
+		client:   client,
+		store:    store,
+		sink:     sink,
+		logger:   logger,
+		now:      time.Now,
+		compiled: make(map[string]*regexp.Regexp),
+		punished: make(map[string]time.Time),
+	}
+}
+
+// WithModeration checks every action against the guild's role hierarchy and
+// protected members through svc, and escalates the warnings rules issue with
+// the same steps /warn applies.
+func (e *RuleEngine) WithModeration(svc *discordmod.Service) *RuleEngine {
+	e.mod = svc
+	return e
+}
+
+// WithLogs posts the flags of rules to their flag channels through pub,
+// with the guild's logging policies applied.
+func (e *RuleEngine) WithLogs(pub discordlogging.Publisher) *RuleEngine {
+	e.logs = pub
+	return e
+}
+
+// InspectMessageCreate implements messages.MessageCreateInspector. The
+// attachment, spam and invite filters go first; then the first rule m breaks
+// takes all of its actions.
+func (e *RuleEngine) InspectMessageCreate(ctx context.Context, guild *files.GuildConfig, m messages.MessageCreateIntent) {
+	if e == nil || guild == nil || m.AuthorBot {
+		return
+	}
+	if guild.TestMode {
+		ctx = moderation.WithTestMode(ctx)
+	}
+	if filter := guild.AttachmentFilter; len(m.Files) > 0 && filter.Enabled() && !filter.Exempts(m.ChannelID, m.CategoryID, m.AuthorRoleIDs) {
+		if f, rule, why, ok := attachmentCheck(filter.RulesFor(m.ChannelID, m.CategoryID), m.Files); ok {
+			e.enforceAttachment(ctx, guild, filter, m, f, rule, why)
+			return
+		}
+	}
+	if m.Content == "" {
+		return
+	}
+	if spam := guild.SpamFilter; spam.Enabled() && !spam.Exempts(m.ChannelID, m.CategoryID, m.AuthorRoleIDs) {
+		if rule, count, limit, ok := spamCheck(spam, m.Content); ok {
+			e.enforceSpam(ctx, guild, spam, m, rule, count, limit)
+			return
+		}
+	}
+	if guild.InviteFilter.Enabled {
+		rule := guild.InviteFilter.Rule()
+		if !rule.Exempts(m.ChannelID, m.CategoryID, m.AuthorRoleIDs) {
+			if link, ok := e.foreignInvite(ctx, guild, m.Content); ok {
+				e.enforce(ctx, guild, rule, m, link)
+				return
+			}
+		}
+	}
+	for _, rule := range guild.AutomodRules {
+		if rule.Exempts(m.ChannelID, m.CategoryID, m.AuthorRoleIDs) {
+			continue
+		}
+		re, err := e.expression(rule)
+		if err != nil {
+			e.logger.Warn("Mitigated service degradation: Automod rule skipped",
+				slog.String("guild_id", m.GuildID),
+				slog.String("rule", rule.Name),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		if loc := re.FindStringIndex(m.Content); loc != nil {
+			e.enforce(ctx, guild, rule, m, m.Content[loc[0]:loc[1]])
+			return
+		}
+	}
+}
+
+// expression returns the compiled expression of rule.
+func (e *RuleEngine) expression(rule files.AutomodRule) (*regexp.Regexp, error) {
+	expr := rule.Expression()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if re, ok := e.compiled[expr]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(e.compiled) >= maxCompiledRules {
+		clear(e.compiled)
+	}
+	e.compiled[expr] = re
+	return re, nil
+}
+
+// mayPunish reports whether the author of m may be warned or timed out for
+// the rule named rule, and starts the cooldown if so.
+func (e *RuleEngine) mayPunish(rule string, m messages.MessageCreateIntent, now time.Time) bool {
+	key := m.GuildID + "/" + m.AuthorID + "/" + rule
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if last, ok := e.punished[key]; ok && now.Sub(last) < ruleCooldown {
+		return false
+	}
+	for k, last := range e.punished {
+		if now.Sub(last) >= ruleCooldown {
+			delete(e.punished, k)
+		}
+	}
+	e.punished[key] = now
+	return true
+}
+
+func (e *RuleEngine) enforce(ctx context.Context, guild *files.GuildConfig, rule files.AutomodRule, m messages.MessageCreateIntent, match string) {
+	guildID, errG := discord.ParseSnowflake(m.GuildID)
+	channelID, errC := discord.ParseSnowflake(m.ChannelID)
+	messageID, errM := discord.ParseSnowflake(m.MessageID)
+	userID, errU := discord.ParseSnowflake(m.AuthorID)
+	if errG != nil || errC != nil || errM != nil || errU != nil {
+		return
+	}
+	match = truncateRunes(match, maxRuleMatchLength)
+	reason := fmt.Sprintf("Automod rule %s matched %q", rule.Name, match)
+	now := e.now()
+	botID := e.botID()
+	if err := e.authorize(ctx, guild, m, botID); err != nil {
+		e.logSkipped(rule.Name, m, err)
+		if rule.Takes(files.AutomodActionFlag) {
+			e.flag(ctx, rule, m, match, []string{"took no action: " + err.Error()}, false, now)
+		}
+		return
+	}
+	c := moderation.Case{
+		GuildID:        m.GuildID,
+		UserID:         m.AuthorID,
+		ModeratorID:    botID,
+		Reason:         reason,
+		Source:         moderation.CaseSourceAutomod,
+		ChannelID:      m.ChannelID,
+		MessageID:      m.MessageID,
+		RuleID:         rule.Name,
+		MatchedKeyword: match,
+		MatchedContent: match,
+		Content:        m.Content,
+		CreatedAt:      now,
+	}
+
+	var outcomes []string
+	deleted := false
+	if rule.Takes(files.AutomodActionDelete) {
+		if err := e.deleteMessage(ctx, discord.ChannelID(channelID), discord.MessageID(messageID), reason); err != nil {
+			e.logFailure("Automod rule could not delete a message", rule, m, err)
+			outcomes = append(outcomes, "could not delete the message")
+		} else {
+			deleted = true
+			outcomes = append(outcomes, "deleted the message")
+			blocked := c
+			blocked.Action = CaseActionBlock
+			e.record(ctx, rule, blocked)
+		}
+	}
+
+	punish := (rule.Takes(files.AutomodActionTimeout) || rule.Takes(files.AutomodActionWarn)) && e.mayPunish(rule.Name, m, now)
+	if punish && rule.Takes(files.AutomodActionTimeout) {
+		duration := time.Duration(rule.TimeoutMinutes) * time.Minute
+		until := discord.NewTimestamp(now.Add(duration))
+		if err := e.timeoutMember(ctx, discord.GuildID(guildID), discord.UserID(userID), until, reason); err != nil {
+			e.logFailure("Automod rule could not time out a member", rule, m, err)
+			outcomes = append(outcomes, "could not time out the member")
+		} else {
+			outcomes = append(outcomes, fmt.Sprintf("timed out the member for %s", duration))
+			timedOut := c
+			timedOut.Action = CaseActionTimeout
+			timedOut.ExpiresAt = now.Add(duration)
+			e.record(ctx, rule, timedOut)
+		}
+	}
+	if punish && rule.Takes(files.AutomodActionWarn) {
+		outcomes = append(outcomes, e.warnMember(ctx, guild, rule, m, c))
+	}
+
+	if rule.Takes(files.AutomodActionFlag) {
+		e.flag(ctx, rule, m, match, outcomes, deleted, now)
+	}
+	e.logger.Info("Architectural state transition: Automod rule enforced",
+		slog.String("guild_id", m.GuildID),
+		slog.String("user_id", m.AuthorID),
+		slog.String("channel_id", m.ChannelID),
+		slog.String("rule", rule.Name),
+		slog.String("outcome", strings.Join(outcomes, "; ")),
+	)
+}
+
+// botID returns the ID of the bot the engine acts as, looked up once.
+func (e *RuleEngine) botID() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.selfID == "" {
+		if me, err := e.client.Me(); err == nil {
+			e.selfID = me.ID.String()
+		}
+	}
+	return e.selfID
+}
+
+// authorize refuses to act on the author of m when they own the guild, are
+// protected, or outrank the bot. When the hierarchy cannot be read, the
+// protected users and roles are still checked against the roles m carries.
+func (e *RuleEngine) authorize(ctx context.Context, guild *files.GuildConfig, m messages.MessageCreateIntent, botID string) error {
+	protected := moderation.ProtectedTargets{UserIDs: guild.ModerationProtection.UserIDs, RoleIDs: guild.ModerationProtection.RoleIDs}
+	guildID, errG := discord.ParseSnowflake(m.GuildID)
+	userID, errU := discord.ParseSnowflake(m.AuthorID)
+	botSF, errB := discord.ParseSnowflake(botID)
+	if e.mod != nil && errG == nil && errU == nil && errB == nil {
+		err := e.mod.Authorize(ctx, discord.GuildID(guildID), discord.UserID(botSF), discord.UserID(userID), protected)
+		switch {
+		case err == nil,
+			errors.Is(err, discordmod.ErrTargetIsOwner),
+			errors.Is(err, discordmod.ErrTargetProtected),
+			errors.Is(err, discordmod.ErrActorOutranked),
+			errors.Is(err, discordmod.ErrBotOutranked):
+			return err
+		}
+		e.logger.Debug("Automod could not check the role hierarchy",
+			slog.String("guild_id", m.GuildID),
+			slog.String("user_id", m.AuthorID),
+			slog.String("error", err.Error()),
+		)
+	}
+	if protected.Protects(m.AuthorID, &moderation.Member{UserID: m.AuthorID, RoleIDs: m.AuthorRoleIDs}) {
+		return discordmod.ErrTargetProtected
+	}
+	return nil
+}
+
+// logSkipped notes a match the engine did not act on because authorize
+// refused.
+func (e *RuleEngine) logSkipped(rule string, m messages.MessageCreateIntent, err error) {
+	e.logger.Info("Operational telemetry: Automod match left alone",
+		slog.String("guild_id", m.GuildID),
+		slog.String("user_id", m.AuthorID),
+		slog.String("rule", rule),
+		slog.String("reason", err.Error()),
+	)
+}
+
+// warnMember records a warning for the author of m, along with its automod
+// case c, applies the escalation step the new count reaches and describes the
+// outcome.
+func (e *RuleEngine) warnMember(ctx context.Context, guild *files.GuildConfig, rule files.AutomodRule, m messages.MessageCreateIntent, c moderation.Case) string {
+	if moderation.InTestMode(ctx) {
+		return "warned the member"
+	}
+	if e.store == nil || c.ModeratorID == "" {
+		return "could not warn the member"
+	}
+	warning, err := e.store.CreateModerationWarningCase(ctx, c)
+	if err != nil {
+		e.logFailure("Automod rule could not warn a member", rule, m, err)
+		return "could not warn the member"
+	}
+	outcome := fmt.Sprintf("warned the member (case #%d)", warning.CaseNumber)
+	if e.mod == nil {
+		return outcome
+	}
+	count, err := e.store.CountModerationWarnings(ctx, m.GuildID, m.AuthorID)
+	if err != nil {
+		e.logFailure("Automod rule could not count a member's warnings", rule, m, err)
+		return outcome
+	}
+	step, ok := guild.WarningEscalationFor(count)
+	if !ok {
+		return outcome
+	}
+	return outcome + "; " + e.escalate(ctx, guild, rule, m, c, count, step)
+}
+
+// escalate applies step to the author of m, who now holds count warnings,
+// and records it as an automod case like c.
+func (e *RuleEngine) escalate(ctx context.Context, guild *files.GuildConfig, rule files.AutomodRule, m messages.MessageCreateIntent, c moderation.Case, count int, step files.WarningEscalationStep) string {
+	guildID, errG := discord.ParseSnowflake(m.GuildID)
+	userID, errU := discord.ParseSnowflake(m.AuthorID)
+	if errG != nil || errU != nil {
+		return "could not escalate"
+	}
+	deleteDays := min(max(guild.RuntimeConfig.BanDeleteMessageDays, 0), 7)
+	until, err := e.mod.Escalate(ctx, discord.GuildID(guildID), discord.UserID(userID), count, step, deleteDays)
+	if err != nil {
+		e.logFailure("Automod warning escalation failed", rule, m, err)
+		return fmt.Sprintf("could not escalate (%s)", step.Action)
+	}
+	escalated := c
+	escalated.Action = step.Action
+	escalated.Reason = discordmod.EscalationReason(count)
+	escalated.ExpiresAt = until
+	e.record(ctx, rule, escalated)
+	return fmt.Sprintf("escalated after %d warnings (%s)", count, step.Action)
+}
+
+// deleteMessage deletes a message the engine acts on. Under test mode it
+// only pretends to.
+func (e *RuleEngine) deleteMessage(ctx context.Context, channelID discord.ChannelID, messageID discord.MessageID, reason string) error {
+	if moderation.InTestMode(ctx) {
+		return nil
+	}
+	return e.client.DeleteMessage(channelID, messageID, api.AuditLogReason(reason))
+}
+
+// timeoutMember times a member out until until. Under test mode it only
+// pretends to.
+func (e *RuleEngine) timeoutMember(ctx context.Context, guildID discord.GuildID, userID discord.UserID, until discord.Timestamp, reason string) error {
+	if moderation.InTestMode(ctx) {
+		return nil
+	}
+	return e.client.ModifyMember(guildID, userID, api.ModifyMemberData{
+		CommunicationDisabledUntil: &until,
+		AuditLogReason:             api.AuditLogReason(reason),
+	})
+}
+
+func (e *RuleEngine) record(ctx context.Context, rule files.AutomodRule, c moderation.Case) {
+	if e.store == nil || moderation.InTestMode(ctx) {
+		return
+	}
+	if _, err := e.store.CreateModerationCase(ctx, c); err != nil {
+		e.logger.Warn("Mitigated service degradation: Automod rule case could not be recorded",
+			slog.String("guild_id", c.GuildID),
+			slog.String("user_id", c.UserID),
+			slog.String("rule", rule.Name),
+			slog.String("action", c.Action),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// flag posts the message and what the rule did about it to the rule's flag
+// channel for staff to review.
+func (e *RuleEngine) flag(ctx context.Context, rule files.AutomodRule, m messages.MessageCreateIntent, match string, outcomes []string, deleted bool, now time.Time) {
+	channelID, err := discord.ParseSnowflake(strings.TrimSpace(rule.FlagChannelID))
+	if e.logs == nil || err != nil || !channelID.IsValid() {
+		return
+	}
+	actions := "None"
+	if len(outcomes) > 0 {
+		actions = strings.Join(outcomes, "\n")
+	}
+	fields := []discord.EmbedField{
+		{Name: "Member", Value: "<@" + m.AuthorID + ">", Inline: true},
+		{Name: "Channel", Value: "<#" + m.ChannelID + ">", Inline: true},
+		{Name: "Rule", Value: rule.Name, Inline: true},
+		{Name: "Matched", Value: logging.InlineCode(match)},
+		{Name: "Actions", Value: actions},
+	}
+	if !deleted {
+		fields = append(fields, discord.EmbedField{
+			Name:  "Message",
+			Value: fmt.Sprintf("https://discord.com/channels/%s/%s/%s", m.GuildID, m.ChannelID, m.MessageID),
+		})
+	}
+	embed := discord.Embed{
+		Title:       "Automod rule matched",
+		Description: truncateRunes(m.Content, 1000),
+		Color:       discord.Color(theme.Warning()),
+		Fields:      fields,
+		Timestamp:   discord.NewTimestamp(now),
+	}
+	e.logs.Notify(ctx, discordlogging.LogPost{
+		GuildID:   m.GuildID,
+		ChannelID: discord.ChannelID(channelID),
+		EventType: logging.LogEventAutomodAction,
+		UserID:    m.AuthorID,
+		Embed:     embed,
+	})
+}
+
+func (e *RuleEngine) logFailure(msg string, rule files.AutomodRule, m messages.MessageCreateIntent, err error) {
+	e.logger.Warn("Mitigated service degradation: "+msg,
+		slog.String("guild_id", m.GuildID),
+		slog.String("user_id", m.AuthorID),
+		slog.String("rule", rule.Name),
+		slog.String("error", err.Error()),
+	)
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
@@ -0,0 +1,247 @@
+package automod
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	discordlogging "github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/messages"
+	"github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+type fakeRuleClient struct {
+	deleted  []discord.MessageID
+	timeouts []discord.UserID
+	invites  map[string]discord.GuildID
+	lookups  int
+	// inviteErr, when set, fails every invite lookup.
+	inviteErr error
+}
+
+func (f *fakeRuleClient) Me() (*discord.User, error) { return &discord.User{ID: 1}, nil }
+
+func (f *fakeRuleClient) DeleteMessage(_ discord.ChannelID, messageID discord.MessageID, _ api.AuditLogReason) error {
+	f.deleted = append(f.deleted, messageID)
+	return nil
+}
+
+func (f *fakeRuleClient) ModifyMember(_ discord.GuildID, userID discord.UserID, data api.ModifyMemberData) error {
+	if data.CommunicationDisabledUntil != nil {
+		f.timeouts = append(f.timeouts, userID)
+	}
+	return nil
+}
+
+func (f *fakeRuleClient) Invite(code string) (*discord.Invite, error) {
+	f.lookups++
+	if f.inviteErr != nil {
+		return nil, f.inviteErr
+	}
+	guildID, ok := f.invites[code]
+	if !ok {
+		return nil, &httputil.HTTPError{Status: http.StatusNotFound}
+	}
+	return &discord.Invite{Code: code, Guild: &discord.Guild{ID: guildID}}, nil
+}
+
+// fakeLogs collects the flags rules post, noting the ones posted in test
+// mode.
+type fakeLogs struct {
+	flags    []discord.Embed
+	testMode []bool
+}
+
+func (f *fakeLogs) Notify(ctx context.Context, post discordlogging.LogPost) {
+	f.flags = append(f.flags, post.Embed)
+	f.testMode = append(f.testMode, moderation.InTestMode(ctx))
+}
+
+func (f *fakeLogs) Publish(ctx context.Context, post discordlogging.LogPost) (*discord.Message, error) {
+	f.Notify(ctx, post)
+	return &discord.Message{ChannelID: post.ChannelID}, nil
+}
+
+type fakeRuleStore struct {
+	fakeCaseRecorder
+	warnings []moderation.Warning
+}
+
+func (f *fakeRuleStore) CreateModerationWarningCase(_ context.Context, c moderation.Case) (moderation.Warning, error) {
+	w := moderation.Warning{GuildID: c.GuildID, UserID: c.UserID, ModeratorID: c.ModeratorID, Reason: c.Reason, CaseNumber: int64(len(f.cases) + len(f.warnings) + 1)}
+	f.warnings = append(f.warnings, w)
+	return w, nil
+}
+
+func (f *fakeRuleStore) CountModerationWarnings(_ context.Context, _, userID string) (int, error) {
+	var n int
+	for _, w := range f.warnings {
+		if w.UserID == userID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// fakeModClient records the bans the moderation service escalates to.
+type fakeModClient struct {
+	bans []discord.UserID
+}
+
+func (f *fakeModClient) Ban(_ discord.GuildID, userID discord.UserID, _ api.BanData) error {
+	f.bans = append(f.bans, userID)
+	return nil
+}
+
+func (f *fakeModClient) Kick(discord.GuildID, discord.UserID, api.AuditLogReason) error { return nil }
+
+func (f *fakeModClient) Unban(discord.GuildID, discord.UserID, api.AuditLogReason) error {
+	return nil
+}
+
+func (f *fakeModClient) ModifyMember(discord.GuildID, discord.UserID, api.ModifyMemberData) error {
+	return nil
+}
+
+func TestRuleEngine_InspectMessageCreate(t *testing.T) {
+	t.Parallel()
+	client := &fakeRuleClient{}
+	store := &fakeRuleStore{}
+	logs := &fakeLogs{}
+	engine := NewRuleEngine(client, store, nil, nil).WithLogs(logs)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	engine.now = func() time.Time { return now }
+
+	guild := &files.GuildConfig{GuildID: "100", AutomodRules: []files.AutomodRule{
+		{Name: "links", Pattern: "https?://", Actions: []string{files.AutomodActionFlag}, FlagChannelID: "50"},
+		{
+			Name:           "invites",
+			Pattern:        `discord\.gg/\w+`,
+			Actions:        []string{files.AutomodActionDelete, files.AutomodActionWarn, files.AutomodActionTimeout, files.AutomodActionFlag},
+			TimeoutMinutes: 10,
+			FlagChannelID:  "50",
+			ExemptRoleIDs:  []string{"9"},
+		},
+	}}
+	msg := func(id, content string, roles ...string) messages.MessageCreateIntent {
+		return messages.MessageCreateIntent{GuildID: "100", ChannelID: "7", MessageID: id, AuthorID: "42", Content: content, AuthorRoleIDs: roles}
+	}
+
+	engine.InspectMessageCreate(context.Background(), guild, msg("1", "join discord.gg/abc"))
+	if len(client.deleted) != 1 || len(client.timeouts) != 1 || len(store.warnings) != 1 {
+		t.Fatalf("expected delete, timeout and warning, got %d, %d, %d", len(client.deleted), len(client.timeouts), len(store.warnings))
+	}
+	if len(store.cases) != 2 || store.cases[0].Action != CaseActionBlock || store.cases[1].Action != CaseActionTimeout {
+		t.Fatalf("unexpected cases %+v", store.cases)
+	}
+	if c := store.cases[1]; c.RuleID != "invites" || c.MatchedKeyword != "discord.gg/abc" || c.ModeratorID != "1" || !c.ExpiresAt.Equal(now.Add(10*time.Minute)) {
+		t.Fatalf("unexpected timeout case %+v", c)
+	}
+	if len(logs.flags) != 1 || !strings.Contains(logs.flags[0].Fields[4].Value, "warned the member") {
+		t.Fatalf("expected a flag listing the actions, got %+v", logs.flags)
+	}
+
+	engine.InspectMessageCreate(context.Background(), guild, msg("2", "again discord.gg/abc"))
+	if len(client.deleted) != 2 || len(client.timeouts) != 1 || len(store.warnings) != 1 {
+		t.Fatal("a repeat within the cooldown should be deleted without another warning or timeout")
+	}
+
+	engine.InspectMessageCreate(context.Background(), guild, msg("3", "discord.gg/abc", "9"))
+	engine.InspectMessageCreate(context.Background(), guild, msg("4", "nothing to see"))
+	if len(client.deleted) != 2 || len(logs.flags) != 2 {
+		t.Fatal("exempt members and clean messages should be left alone")
+	}
+
+	engine.InspectMessageCreate(context.Background(), guild, msg("5", "see https://discord.gg/abc"))
+	if len(client.deleted) != 2 || len(logs.flags) != 3 {
+		t.Fatal("only the first matching rule should act")
+	}
+}
+
+func TestRuleEngine_WarningsEscalate(t *testing.T) {
+	t.Parallel()
+	mod := &fakeModClient{}
+	store := &fakeRuleStore{warnings: []moderation.Warning{{GuildID: "100", UserID: "42", CaseNumber: 1}}}
+	engine := NewRuleEngine(&fakeRuleClient{}, store, nil, nil).WithModeration(discordmod.NewService(mod, nil))
+
+	guild := &files.GuildConfig{
+		GuildID:           "100",
+		WarningEscalation: []files.WarningEscalationStep{{Warnings: 2, Action: files.WarningEscalationBan}},
+		AutomodRules: []files.AutomodRule{{
+			Name:    "invites",
+			Pattern: `discord\.gg/\w+`,
+			Actions: []string{files.AutomodActionWarn},
+		}},
+	}
+	engine.InspectMessageCreate(context.Background(), guild, messages.MessageCreateIntent{
+		GuildID: "100", ChannelID: "7", MessageID: "1", AuthorID: "42", Content: "join discord.gg/abc",
+	})
+	if len(mod.bans) != 1 || mod.bans[0] != 42 {
+		t.Fatalf("expected the second warning to escalate to a ban, got %v", mod.bans)
+	}
+	if len(store.cases) != 1 || store.cases[0].Action != files.WarningEscalationBan || store.cases[0].RuleID != "invites" {
+		t.Fatalf("expected the escalation to be recorded as an automod case, got %+v", store.cases)
+	}
+}
+
+func TestRuleEngine_SparesProtectedMembers(t *testing.T) {
+	t.Parallel()
+	client := &fakeRuleClient{}
+	store := &fakeRuleStore{}
+	logs := &fakeLogs{}
+	engine := NewRuleEngine(client, store, nil, nil).WithLogs(logs)
+
+	guild := &files.GuildConfig{
+		GuildID:              "100",
+		ModerationProtection: files.ModerationProtectionConfig{RoleIDs: []string{"8"}},
+		AutomodRules: []files.AutomodRule{{
+			Name:           "invites",
+			Pattern:        `discord\.gg/\w+`,
+			Actions:        []string{files.AutomodActionDelete, files.AutomodActionWarn, files.AutomodActionTimeout, files.AutomodActionFlag},
+			TimeoutMinutes: 10,
+			FlagChannelID:  "50",
+		}},
+	}
+	engine.InspectMessageCreate(context.Background(), guild, messages.MessageCreateIntent{
+		GuildID: "100", ChannelID: "7", MessageID: "1", AuthorID: "42", Content: "join discord.gg/abc", AuthorRoleIDs: []string{"8"},
+	})
+	if len(client.deleted) != 0 || len(client.timeouts) != 0 || len(store.warnings) != 0 {
+		t.Fatalf("a protected member was acted on: %d deletes, %d timeouts, %d warnings", len(client.deleted), len(client.timeouts), len(store.warnings))
+	}
+	if len(logs.flags) != 1 || !strings.Contains(logs.flags[0].Fields[4].Value, "took no action") {
+		t.Fatalf("expected a flag saying no action was taken, got %+v", logs.flags)
+	}
+}
+
+func TestRuleEngine_TestMode(t *testing.T) {
+	t.Parallel()
+	client := &fakeRuleClient{}
+	store := &fakeRuleStore{}
+	logs := &fakeLogs{}
+	engine := NewRuleEngine(client, store, nil, nil).WithLogs(logs)
+
+	guild := &files.GuildConfig{GuildID: "100", TestMode: true, AutomodRules: []files.AutomodRule{{
+		Name:           "invites",
+		Pattern:        `discord\.gg/\w+`,
+		Actions:        []string{files.AutomodActionDelete, files.AutomodActionWarn, files.AutomodActionTimeout, files.AutomodActionFlag},
+		TimeoutMinutes: 10,
+		FlagChannelID:  "50",
+	}}}
+	engine.InspectMessageCreate(context.Background(), guild, messages.MessageCreateIntent{
+		GuildID: "100", ChannelID: "7", MessageID: "1", AuthorID: "42", Content: "join discord.gg/abc",
+	})
+	if len(client.deleted) != 0 || len(client.timeouts) != 0 || len(store.warnings) != 0 || len(store.cases) != 0 {
+		t.Fatalf("test mode must not act, got %d deletes, %d timeouts, %d warnings, %d cases",
+			len(client.deleted), len(client.timeouts), len(store.warnings), len(store.cases))
+	}
+	if len(logs.flags) != 1 || !logs.testMode[0] || !strings.Contains(logs.flags[0].Fields[4].Value, "deleted the message") {
+		t.Fatalf("expected a TEST flag listing the simulated actions, got %+v", logs.flags)
+	}
+}
@@ -0,0 +1,177 @@
+package automod
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/automod"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/messages"
+)
+
+// Names the spam filter reports its checks under, as the rule name does for
+// automod rules.
+const (
+	mentionSpamRule = "mention-spam"
+	emojiSpamRule   = "emoji-spam"
+)
+
+var (
+	mentionRE     = regexp.MustCompile(`<@[!&]?\d+>|@everyone|@here`)
+	customEmojiRE = regexp.MustCompile(`<a?:\w+:\d+>`)
+)
+
+// countMentions counts the distinct users and roles content mentions, plus
+// @everyone and @here.
+func countMentions(content string) int {
+	seen := make(map[string]struct{})
+	for _, mention := range mentionRE.FindAllString(content, -1) {
+		seen[strings.Replace(mention, "<@!", "<@", 1)] = struct{}{}
+	}
+	return len(seen)
+}
+
+// countEmojis counts the custom and Unicode emojis in content. Emojis joined
+// into one with zero width joiners, flags and skin tones count once.
+func countEmojis(content string) int {
+	count := len(customEmojiRE.FindAllStringIndex(content, -1))
+	joined, regional := false, 0
+	for _, r := range customEmojiRE.ReplaceAllString(content, "") {
+		switch {
+		case r == '\u200d':
+			joined = true
+			continue
+		case r >= 0x1f1e6 && r <= 0x1f1ff:
+			// Flags are pairs of regional indicators.
+			if regional++; regional%2 == 1 {
+				count++
+			}
+		case r >= 0x1f3fb && r <= 0x1f3ff:
+			// Skin tones modify the emoji before them.
+		case isEmojiRune(r):
+			if !joined {
+				count++
+			}
+		}
+		joined = false
+	}
+	return count
+}
+
+func isEmojiRune(r rune) bool {
+	switch {
+	case r >= 0x1f000 && r <= 0x1faff, r >= 0x2600 && r <= 0x27bf:
+		return true
+	case r == 0x2b50, r == 0x2b55, r == 0x2b1b, r == 0x2b1c:
+		return true
+	}
+	return false
+}
+
+// spamCheck returns the check of the spam filter m fails, with the count
+// and the limit it went over.
+func spamCheck(cfg files.SpamFilterConfig, content string) (rule string, count, limit int, ok bool) {
+	if cfg.MaxMentions > 0 {
+		if n := countMentions(content); n > cfg.MaxMentions {
+			return mentionSpamRule, n, cfg.MaxMentions, true
+		}
+	}
+	if cfg.MaxEmojis > 0 {
+		if n := countEmojis(content); n > cfg.MaxEmojis {
+			return emojiSpamRule, n, cfg.MaxEmojis, true
+		}
+	}
+	return "", 0, 0, false
+}
+
+// enforceSpam deletes m and, when the guild sets a timeout, times its author
+// out.
+func (e *RuleEngine) enforceSpam(ctx context.Context, guild *files.GuildConfig, cfg files.SpamFilterConfig, m messages.MessageCreateIntent, rule string, count, limit int) {
+	if err := e.authorize(ctx, guild, m, e.botID()); err != nil {
+		e.logSkipped(rule, m, err)
+		return
+	}
+	noun := "mentions"
+	if rule == emojiSpamRule {
+		noun = "emojis"
+	}
+	detail := fmt.Sprintf("%d %s (limit %d)", count, noun, limit)
+	e.enforceFilter(ctx, "spam filter", m, rule, detail, func() string { return detail }, cfg.TimeoutMinutes)
+}
+
+// enforceFilter deletes m for failing the check rule of filter and, when
+// timeoutMinutes is set, times its author out. Both are reported to the sink
+// the way Discord reports the actions of its own AutoMod, so they are logged
+// and recorded as cases alike. detail explains the deletion in the audit
+// log; what matched returns is reported as the matched content. matched is
+// only called once the message is deleted, so it may wait on work that
+// should not hold the deletion up.
+func (e *RuleEngine) enforceFilter(ctx context.Context, filter string, m messages.MessageCreateIntent, rule, detail string, matched func() string, timeoutMinutes int) {
+	guildID, errG := discord.ParseSnowflake(m.GuildID)
+	channelID, errC := discord.ParseSnowflake(m.ChannelID)
+	messageID, errM := discord.ParseSnowflake(m.MessageID)
+	userID, errU := discord.ParseSnowflake(m.AuthorID)
+	if errG != nil || errC != nil || errM != nil || errU != nil {
+		return
+	}
+	reason := fmt.Sprintf("Automod %s: %s", rule, detail)
+	event := automod.ExecutionEvent{
+		GuildID:        discord.GuildID(guildID),
+		UserID:         discord.UserID(userID),
+		ChannelID:      discord.ChannelID(channelID),
+		MessageID:      discord.MessageID(messageID),
+		Content:        m.Content,
+		MatchedKeyword: rule,
+	}
+	matchedContent := sync.OnceValue(matched)
+
+	var outcomes []string
+	if err := e.deleteMessage(ctx, discord.ChannelID(channelID), discord.MessageID(messageID), reason); err != nil {
+		e.logFailure("Automod "+filter+" could not delete a message", files.AutomodRule{Name: rule}, m, err)
+		outcomes = append(outcomes, "could not delete the message")
+	} else {
+		outcomes = append(outcomes, "deleted the message")
+		blocked := event
+		blocked.MatchedContent = matchedContent()
+		blocked.Action = automod.ExecutionAction{Type: automod.ActionBlockMessage}
+		e.report(ctx, &blocked)
+	}
+
+	now := e.now()
+	if timeoutMinutes > 0 && e.mayPunish(rule, m, now) {
+		duration := time.Duration(timeoutMinutes) * time.Minute
+		until := discord.NewTimestamp(now.Add(duration))
+		if err := e.timeoutMember(ctx, discord.GuildID(guildID), discord.UserID(userID), until, reason); err != nil {
+			e.logFailure("Automod "+filter+" could not time out a member", files.AutomodRule{Name: rule}, m, err)
+			outcomes = append(outcomes, "could not time out the member")
+		} else {
+			outcomes = append(outcomes, fmt.Sprintf("timed out the member for %s", duration))
+			timedOut := event
+			timedOut.MatchedContent = matchedContent()
+			timedOut.Action = automod.ExecutionAction{
+				Type:     automod.ActionTimeout,
+				Metadata: automod.ExecutionActionMetadata{DurationSecs: int(duration / time.Second)},
+			}
+			e.report(ctx, &timedOut)
+		}
+	}
+	e.logger.Info("Architectural state transition: Automod "+filter+" enforced",
+		slog.String("guild_id", m.GuildID),
+		slog.String("user_id", m.AuthorID),
+		slog.String("channel_id", m.ChannelID),
+		slog.String("rule", rule),
+		slog.String("outcome", strings.Join(outcomes, "; ")),
+	)
+}
+
+func (e *RuleEngine) report(ctx context.Context, event *automod.ExecutionEvent) {
+	if e.sink != nil {
+		e.sink.OnAutomodBlock(ctx, event.GuildID, event)
+	}
+}
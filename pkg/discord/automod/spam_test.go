@@ -0,0 +1,90 @@
+package automod
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/automod"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/messages"
+)
+
+type recordingSink struct {
+	events []automod.ExecutionEvent
+}
+
+func (s *recordingSink) OnAutomodBlock(_ context.Context, _ discord.GuildID, entry *automod.ExecutionEvent) {
+	s.events = append(s.events, *entry)
+}
+
+func TestCountMentionsAndEmojis(t *testing.T) {
+	t.Parallel()
+
+	if n := countMentions("<@1> <@!1> <@&2> <@3> @everyone @here"); n != 5 {
+		t.Errorf("countMentions = %d, want 5", n)
+	}
+	for text, want := range map[string]int{
+		"no emojis here ©":         0,
+		"<:pog:123> <a:dance:456>": 2,
+		"😀😀 ⭐":                     3,
+		"👨‍👩‍👧 👍🏽":                 2,
+		"🇧🇷🇯🇵":                     2,
+	} {
+		if got := countEmojis(text); got != want {
+			t.Errorf("countEmojis(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestRuleEngine_SpamFilter(t *testing.T) {
+	t.Parallel()
+	client := &fakeRuleClient{}
+	store := &fakeRuleStore{}
+	sink := &recordingSink{}
+	engine := NewRuleEngine(client, store, sink, nil)
+	engine.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+
+	guild := &files.GuildConfig{GuildID: "100", SpamFilter: files.SpamFilterConfig{
+		MaxMentions:    3,
+		MaxEmojis:      4,
+		TimeoutMinutes: 5,
+		ExemptRoleIDs:  []string{"9"},
+	}}
+	post := func(id, content string, roles ...string) {
+		engine.InspectMessageCreate(context.Background(), guild, messages.MessageCreateIntent{
+			GuildID: "100", ChannelID: "7", MessageID: id, AuthorID: "42", Content: content, AuthorRoleIDs: roles,
+		})
+	}
+
+	post("1", "<@1> <@2> <@3> 😀😀😀😀")
+	post("2", "<@1> <@2> <@3> <@4>", "9")
+	if len(client.deleted) != 0 || len(sink.events) != 0 {
+		t.Fatal("messages within the limits and exempt members should be left alone")
+	}
+
+	post("3", "<@1> <@2> <@3> <@4>")
+	if len(client.deleted) != 1 || len(client.timeouts) != 1 || len(sink.events) != 2 {
+		t.Fatalf("expected a delete and a timeout, got %d, %d, %d events", len(client.deleted), len(client.timeouts), len(sink.events))
+	}
+	block, timeout := sink.events[0], sink.events[1]
+	if block.Action.Type != automod.ActionBlockMessage || block.MatchedKeyword != mentionSpamRule || block.MatchedContent != "4 mentions (limit 3)" {
+		t.Fatalf("unexpected block event %+v", block)
+	}
+	if timeout.Action.Type != automod.ActionTimeout || timeout.Action.Metadata.DurationSecs != 300 || timeout.MessageID != 3 {
+		t.Fatalf("unexpected timeout event %+v", timeout)
+	}
+	if len(store.cases) != 0 {
+		t.Fatal("spam cases are left to the sink")
+	}
+
+	post("4", "😀😀😀😀😀")
+	if len(client.deleted) != 2 || len(client.timeouts) != 2 || sink.events[2].MatchedKeyword != emojiSpamRule {
+		t.Fatalf("expected the emoji flood to be handled on its own cooldown, got %+v", sink.events[2:])
+	}
+	post("5", "😀😀😀😀😀")
+	if len(client.deleted) != 3 || len(client.timeouts) != 2 {
+		t.Fatal("a repeat within the cooldown should be deleted without another timeout")
+	}
+}
@@ -8,12 +8,14 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"weak"
 
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/small-frappuccino/discordcore/pkg/discord/session"
 	"github.com/small-frappuccino/discordcore/pkg/storage/postgres"
+	"github.com/small-frappuccino/discordcore/pkg/system"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -166,8 +168,16 @@ type UnifiedCache struct {
 	guilds   *Segment[discord.Guild]
 	roles    *Segment[[]discord.Role]
 	channels *Segment[discord.Channel]
+	// userLogThreads maps guild:channel:user to the thread a member's logs
+	// are routed to under a log channel.
+	userLogThreads *Segment[discord.Channel]
 
 	store *postgres.Store
+
+	// lastWarmup records the unix-nano timestamp of the last full Warmup pass.
+	lastWarmup atomic.Int64
+	// warmedGuilds maps guild IDs to the time.Time of their last lazy WarmupGuild pass.
+	warmedGuilds sync.Map
 }
 
 // NewUnifiedCache instantiates a comprehensive caching layer bound to the provided TTL configurations.
@@ -182,6 +192,8 @@ func NewUnifiedCache(cfg CacheConfig) *UnifiedCache {
 		roles:    NewSegment[[]discord.Role](cfg.RolesTTL),
 		channels: NewSegment[discord.Channel](cfg.ChannelTTL),
 		store:    cfg.Store,
+
+		userLogThreads: NewSegment[discord.Channel](cfg.ChannelTTL),
 	}
 }
 
@@ -191,6 +203,7 @@ func (uc *UnifiedCache) Purge() {
 	uc.guilds.Purge()
 	uc.roles.Purge()
 	uc.channels.Purge()
+	uc.userLogThreads.Purge()
 }
 
 // Accessors
@@ -254,6 +267,21 @@ func (uc *UnifiedCache) InvalidateChannel(channelID string) {
 	uc.channels.Invalidate(channelID)
 }
 
+// GetUserLogThread retrieves the thread a member's logs are routed to under a log channel.
+func (uc *UnifiedCache) GetUserLogThread(guildID, channelID, userID string) (*discord.Channel, bool) {
+	return uc.userLogThreads.Get(guildID + ":" + channelID + ":" + userID)
+}
+
+// SetUserLogThread injects the thread a member's logs are routed to under a log channel.
+func (uc *UnifiedCache) SetUserLogThread(guildID, channelID, userID string, thread *discord.Channel) {
+	uc.userLogThreads.Set(guildID+":"+channelID+":"+userID, thread)
+}
+
+// InvalidateUserLogThread evicts the thread a member's logs are routed to under a log channel.
+func (uc *UnifiedCache) InvalidateUserLogThread(guildID, channelID, userID string) {
+	uc.userLogThreads.Invalidate(guildID + ":" + channelID + ":" + userID)
+}
+
 // Warmup recovery handling for corrupt JSON/Gob snapshots
 // Warmup reconstructs the transient in-memory state from the persistent Postgres store.
 func (uc *UnifiedCache) Warmup(ctx context.Context) error {
@@ -278,9 +306,103 @@ func (uc *UnifiedCache) Warmup(ctx context.Context) error {
 		uc.SetGuild(strings.TrimPrefix(entry.Key, "guild:"), &g)
 	}
 
+	uc.lastWarmup.Store(time.Now().UnixNano())
+	return nil
+}
+
+// WarmupGuild reconstructs the transient in-memory state for a single guild from the persistent Postgres store.
+// It is the lazy counterpart to Warmup: driven by GuildCreate, it bounds boot cost to the guilds that actually
+// come online instead of hydrating every persisted snapshot up front.
+func (uc *UnifiedCache) WarmupGuild(ctx context.Context, guildID string) error {
+	guildID = strings.TrimSpace(guildID)
+	if uc.store == nil || guildID == "" {
+		return nil
+	}
+
+	hydrated := 0
+	for entry, err := range uc.store.GetCacheEntriesByGuild(ctx, guildID) {
+		if err != nil {
+			return fmt.Errorf("warmup guild %s read: %w", guildID, err)
+		}
+		if uc.hydrateEntry(entry) {
+			hydrated++
+		}
+	}
+
+	uc.warmedGuilds.Store(guildID, time.Now())
+	slog.Debug("Granular transient state inspection: Guild-scoped warmup completed",
+		slog.String("guildID", guildID),
+		slog.Int("hydrated", hydrated),
+	)
 	return nil
 }
 
+// hydrateEntry decodes a guild-scoped persistent snapshot into the matching memory segment.
+// It reports whether the entry was recognized and successfully injected.
+func (uc *UnifiedCache) hydrateEntry(entry system.CacheEntryRecord) bool {
+	switch entry.CacheType {
+	case "guild":
+		g, ok := decodeSnapshot[discord.Guild](entry)
+		if ok {
+			uc.SetGuild(entry.GuildID, g)
+		}
+		return ok
+	case "roles":
+		roles, ok := decodeSnapshot[[]discord.Role](entry)
+		if ok {
+			uc.SetRoles(entry.GuildID, roles)
+		}
+		return ok
+	case "channel":
+		ch, ok := decodeSnapshot[discord.Channel](entry)
+		if ok {
+			uc.SetChannel(strings.TrimPrefix(entry.Key, "channel:"), ch)
+		}
+		return ok
+	case "member":
+		userID := entry.Key[strings.LastIndexByte(entry.Key, ':')+1:]
+		if userID == "" {
+			return false
+		}
+		m, ok := decodeSnapshot[discord.Member](entry)
+		if ok {
+			uc.SetMember(entry.GuildID, userID, m)
+		}
+		return ok
+	default:
+		return false
+	}
+}
+
+// decodeSnapshot unmarshals a persisted JSON snapshot, logging and discarding corrupt payloads.
+func decodeSnapshot[T any](entry system.CacheEntryRecord) (*T, bool) {
+	var v T
+	if err := json.Unmarshal([]byte(entry.Data), &v); err != nil {
+		slog.Warn("Mitigated service degradation: Aborted warmup for corrupted snapshot",
+			slog.String("request_id", "warmup"),
+			slog.String("cache_type", entry.CacheType),
+			slog.String("key", entry.Key),
+			slog.String("error", err.Error()),
+		)
+		return nil, false
+	}
+	return &v, true
+}
+
+// WasGuildWarmedUpRecently reports whether WarmupGuild completed for the guild within the specified duration window.
+func (uc *UnifiedCache) WasGuildWarmedUpRecently(guildID string, d time.Duration) bool {
+	v, ok := uc.warmedGuilds.Load(strings.TrimSpace(guildID))
+	if !ok {
+		return false
+	}
+	return time.Since(v.(time.Time)) < d
+}
+
+// ForgetGuild drops the warmup marker for a guild so that its next GuildCreate triggers a fresh hydration pass.
+func (uc *UnifiedCache) ForgetGuild(guildID string) {
+	uc.warmedGuilds.Delete(strings.TrimSpace(guildID))
+}
+
 // WarmupConfig encapsulates heuristic parameters for targeted cache pre-warming flows.
 type WarmupConfig struct {
 	FetchMissingMembers bool
@@ -299,7 +421,11 @@ func IntelligentWarmupContext(ctx context.Context, s *session.LegacySession, uc
 
 // WasWarmedUpRecently validates whether the cache layer received a hydration payload within the specified duration window.
 func (uc *UnifiedCache) WasWarmedUpRecently(d time.Duration) bool {
-	return false
+	last := uc.lastWarmup.Load()
+	if last == 0 {
+		return false
+	}
+	return time.Since(time.Unix(0, last)) < d
 }
 
 // SchedulePeriodicCleanup initializes a background goroutine to purge expired entries from the durable store.
@@ -2,11 +2,15 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"runtime"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/small-frappuccino/discordcore/pkg/storage/postgres"
 )
 
 // TestCache_GCEviction verifies that weak references are correctly garbage collected and evicted.
@@ -104,6 +108,39 @@ func BenchmarkCache_Shards(b *testing.B) {
 	})
 }
 
+// BenchmarkCache_MemberContention measures member lookups under the mix the
+// gateway produces, mostly reads with one write in ten, spread over many
+// members so goroutines contend across shards rather than on one key.
+func BenchmarkCache_MemberContention(b *testing.B) {
+	uc := NewUnifiedCache(CacheConfig{MemberTTL: time.Minute})
+
+	const memberCount = 4096
+	userIDs := make([]string, memberCount)
+	// The cache holds members weakly; pinned keeps them alive for the run.
+	pinned := make([]*discord.Member, memberCount)
+	for i := range userIDs {
+		userIDs[i] = strconv.Itoa(100000 + i)
+		pinned[i] = &discord.Member{User: discord.User{ID: discord.UserID(100000 + i)}}
+		uc.SetMember("1", userIDs[i], pinned[i])
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			n := i % memberCount
+			if i%10 == 0 {
+				uc.SetMember("1", userIDs[n], pinned[n])
+			} else {
+				uc.GetMember("1", userIDs[n])
+			}
+			i += 7
+		}
+	})
+	runtime.KeepAlive(pinned)
+}
+
 // TestCache_AsyncIO asserts the performance bounds of snapshot extraction under concurrent lock acquisition.
 func TestCache_AsyncIO(t *testing.T) {
 	t.Parallel()
@@ -132,3 +169,66 @@ func TestCache_CorruptRecovery(t *testing.T) {
 		t.Fatalf("Warmup should ignore nil store but got err: %v", err)
 	}
 }
+
+// TestCache_WarmupGuild verifies that lazy warmup only queries the announced guild and tracks its freshness marker.
+func TestCache_WarmupGuild(t *testing.T) {
+	t.Parallel()
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("pgxmock: %v", err)
+	}
+	defer mock.Close()
+	store, _ := postgres.NewStore(mock, nil)
+
+	rows := pgxmock.NewRows([]string{"cache_type", "cache_key", "data", "expires_at"}).
+		AddRow("guild", "guild:42", `{"id":"42"}`, time.Now().Add(time.Hour)).
+		AddRow("channel", "channel:7", `{corrupt`, time.Now().Add(time.Hour))
+	mock.ExpectQuery(`SELECT cache_type, cache_key, data, expires_at FROM persistent_cache WHERE guild_id=`).
+		WithArgs("42", pgxmock.AnyArg()).
+		WillReturnRows(rows)
+
+	uc := NewUnifiedCache(CacheConfig{GuildTTL: time.Minute, ChannelTTL: time.Minute, Store: store})
+	if uc.WasGuildWarmedUpRecently("42", time.Minute) {
+		t.Fatal("Expected guild to be cold before warmup")
+	}
+	if err := uc.WarmupGuild(context.Background(), "42"); err != nil {
+		t.Fatalf("WarmupGuild returned error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+
+	if !uc.WasGuildWarmedUpRecently("42", time.Minute) {
+		t.Fatal("Expected guild to be marked as warmed")
+	}
+	if uc.WasGuildWarmedUpRecently("43", time.Minute) {
+		t.Fatal("Expected unrelated guild to remain cold")
+	}
+
+	uc.ForgetGuild("42")
+	if uc.WasGuildWarmedUpRecently("42", time.Minute) {
+		t.Fatal("Expected ForgetGuild to reset the warmup marker")
+	}
+}
+
+// TestCache_WarmupGuildReadFailure ensures store failures surface without marking the guild as warmed.
+func TestCache_WarmupGuildReadFailure(t *testing.T) {
+	t.Parallel()
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("pgxmock: %v", err)
+	}
+	defer mock.Close()
+	store, _ := postgres.NewStore(mock, nil)
+
+	mock.ExpectQuery(`SELECT cache_type, cache_key, data, expires_at FROM persistent_cache`).
+		WillReturnError(errors.New("connection reset"))
+
+	uc := NewUnifiedCache(CacheConfig{Store: store})
+	if err := uc.WarmupGuild(context.Background(), "42"); err == nil {
+		t.Fatal("Expected read failure to propagate")
+	}
+	if uc.WasGuildWarmedUpRecently("42", time.Minute) {
+		t.Fatal("Expected failed warmup to leave guild cold")
+	}
+}
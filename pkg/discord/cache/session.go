@@ -1,18 +1,31 @@
 package cache
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"sync"
 
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/singleflight"
 )
 
+// sessionClient is the subset of *api.Client the session proxies.
+type sessionClient interface {
+	Member(guildID discord.GuildID, userID discord.UserID) (*discord.Member, error)
+	MembersAfter(guildID discord.GuildID, after discord.UserID, limit uint) ([]discord.Member, error)
+	Guild(guildID discord.GuildID) (*discord.Guild, error)
+}
+
 // CachedSession acts as a transparent, caching proxy layer wrapping an underlying Arikawa Discord API client.
 type CachedSession struct {
-	client *api.Client
+	client sessionClient
 	cache  *UnifiedCache
 	sf     singleflight.Group
 }
@@ -119,3 +132,126 @@ func (cs *CachedSession) HandleGuildRoleDelete(e *gateway.GuildRoleDeleteEvent)
 		cs.cache.SetRoles(e.GuildID.String(), &newRoles)
 	}
 }
+
+const (
+	// memberBatchListThreshold is the number of cache misses above which
+	// ResolveMembers pages through the guild member list instead of issuing
+	// one GuildMember call per user.
+	memberBatchListThreshold = 25
+	// memberBatchConcurrency bounds the parallel GuildMember calls made for
+	// small batches.
+	memberBatchConcurrency = 5
+)
+
+// ResolveMembers resolves many members of one guild at once. Cached members
+// are served locally; the remainder is fetched either with bounded parallel
+// GuildMember calls or, for large batches, by paging the member list in
+// 1000-member requests. Users who are not in the guild are absent from the
+// returned map rather than reported as errors.
+func (cs *CachedSession) ResolveMembers(ctx context.Context, guildID string, ids []string) (map[string]*discord.Member, error) {
+	found := make(map[string]*discord.Member, len(ids))
+	missing := make(map[discord.UserID]string)
+	for _, id := range ids {
+		if _, seen := found[id]; seen {
+			continue
+		}
+		if member, ok := cs.cache.GetMember(guildID, id); ok {
+			found[id] = member
+			continue
+		}
+		sf, err := discord.ParseSnowflake(id)
+		if err != nil || !sf.IsValid() {
+			continue
+		}
+		missing[discord.UserID(sf)] = id
+	}
+	if len(missing) == 0 {
+		return found, nil
+	}
+
+	var fetched map[string]*discord.Member
+	var err error
+	if len(missing) > memberBatchListThreshold {
+		fetched, err = cs.listMembers(ctx, guildID, missing)
+	} else {
+		fetched, err = cs.fetchMembers(ctx, guildID, missing)
+	}
+	for id, member := range fetched {
+		cs.cache.SetMember(guildID, id, member)
+		found[id] = member
+	}
+	if err != nil {
+		return found, fmt.Errorf("CachedSession.ResolveMembers: %w", err)
+	}
+	return found, nil
+}
+
+func (cs *CachedSession) fetchMembers(ctx context.Context, guildID string, missing map[discord.UserID]string) (map[string]*discord.Member, error) {
+	var (
+		mu  sync.Mutex
+		out = make(map[string]*discord.Member, len(missing))
+	)
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(memberBatchConcurrency)
+	gid, err := discord.ParseSnowflake(guildID)
+	if err != nil {
+		return nil, err
+	}
+	for userID, id := range missing {
+		eg.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			// Not-found answers are expected here, so the client is called
+			// directly rather than through GuildMember and its error logging.
+			member, err := cs.client.Member(discord.GuildID(gid), userID)
+			if err != nil {
+				var httpErr *httputil.HTTPError
+				if errors.As(err, &httpErr) && httpErr.Status == http.StatusNotFound {
+					return nil
+				}
+				return err
+			}
+			mu.Lock()
+			out[id] = member
+			mu.Unlock()
+			return nil
+		})
+	}
+	err = eg.Wait()
+	return out, err
+}
+
+func (cs *CachedSession) listMembers(ctx context.Context, guildID string, missing map[discord.UserID]string) (map[string]*discord.Member, error) {
+	gid, err := discord.ParseSnowflake(guildID)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]*discord.Member, len(missing))
+	var after discord.UserID
+	for len(out) < len(missing) {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+		page, err := cs.client.MembersAfter(discord.GuildID(gid), after, api.MaxMemberFetchLimit)
+		if err != nil {
+			return out, err
+		}
+		for i := range page {
+			if id, ok := missing[page[i].User.ID]; ok {
+				member := page[i]
+				out[id] = &member
+			}
+		}
+		if len(page) < api.MaxMemberFetchLimit {
+			break
+		}
+		after = page[len(page)-1].User.ID
+	}
+	slog.Debug("Granular transient state inspection: Resolved member batch from member list",
+		slog.String("guildID", guildID),
+		slog.Int("requested", len(missing)),
+		slog.Int("resolved", len(out)),
+	)
+	return out, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -173,3 +176,102 @@ func TestSession_RaceUpdate(t *testing.T) {
 		t.Fatalf("concurrency execution failed: %v", err)
 	}
 }
+
+type fakeSessionClient struct {
+	mu          sync.Mutex
+	members     map[discord.UserID]discord.Member
+	memberCalls int
+	listCalls   int
+}
+
+func (f *fakeSessionClient) Member(guildID discord.GuildID, userID discord.UserID) (*discord.Member, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.memberCalls++
+	m, ok := f.members[userID]
+	if !ok {
+		return nil, &httputil.HTTPError{Status: http.StatusNotFound}
+	}
+	return &m, nil
+}
+
+func (f *fakeSessionClient) MembersAfter(guildID discord.GuildID, after discord.UserID, limit uint) ([]discord.Member, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.listCalls++
+	ids := make([]discord.UserID, 0, len(f.members))
+	for id := range f.members {
+		if id > after {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	if len(ids) > int(limit) {
+		ids = ids[:limit]
+	}
+	out := make([]discord.Member, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, f.members[id])
+	}
+	return out, nil
+}
+
+func (f *fakeSessionClient) Guild(guildID discord.GuildID) (*discord.Guild, error) {
+	return &discord.Guild{ID: guildID}, nil
+}
+
+func newFakeSessionClient(n int) *fakeSessionClient {
+	f := &fakeSessionClient{members: make(map[discord.UserID]discord.Member, n)}
+	for i := 1; i <= n; i++ {
+		id := discord.UserID(i)
+		f.members[id] = discord.Member{User: discord.User{ID: id}}
+	}
+	return f
+}
+
+func TestSession_ResolveMembersSmallBatch(t *testing.T) {
+	t.Parallel()
+	uc := NewUnifiedCache(CacheConfig{MemberTTL: time.Minute})
+	client := newFakeSessionClient(10)
+	cs := &CachedSession{client: client, cache: uc}
+	cached := &discord.Member{User: discord.User{ID: 1}}
+	uc.SetMember("100", "1", cached)
+
+	got, err := cs.ResolveMembers(context.Background(), "100", []string{"1", "2", "3", "999", "not-an-id", "2"})
+	if err != nil {
+		t.Fatalf("ResolveMembers: %v", err)
+	}
+	if len(got) != 3 || got["1"] != cached || got["2"] == nil || got["3"] == nil {
+		t.Fatalf("unexpected result: %v", got)
+	}
+	if client.memberCalls != 3 || client.listCalls != 0 {
+		t.Fatalf("expected 3 member calls and no listing, got %d and %d", client.memberCalls, client.listCalls)
+	}
+	if m, ok := uc.GetMember("100", "3"); !ok || m != got["3"] {
+		t.Fatal("expected fetched member to be cached")
+	}
+}
+
+func TestSession_ResolveMembersLargeBatchPagesMemberList(t *testing.T) {
+	t.Parallel()
+	uc := NewUnifiedCache(CacheConfig{MemberTTL: time.Minute})
+	client := newFakeSessionClient(2500)
+	cs := &CachedSession{client: client, cache: uc}
+
+	ids := make([]string, 0, 40)
+	for i := 1; i <= 30; i++ {
+		ids = append(ids, fmt.Sprint(i*80))
+	}
+	ids = append(ids, "5000")
+
+	got, err := cs.ResolveMembers(context.Background(), "100", ids)
+	if err != nil {
+		t.Fatalf("ResolveMembers: %v", err)
+	}
+	if len(got) != 30 {
+		t.Fatalf("expected 30 resolved members, got %d", len(got))
+	}
+	if client.memberCalls != 0 || client.listCalls != 3 {
+		t.Fatalf("expected 3 list pages and no member calls, got %d and %d", client.listCalls, client.memberCalls)
+	}
+}
@@ -0,0 +1,120 @@
+package clean
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/clean"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+// PurgeResult summarizes one automatic purge of a channel.
+type PurgeResult struct {
+	Scanned int
+	Deleted int
+	// Exempt counts pinned messages and messages of exempt authors.
+	Exempt int
+	// Failed counts selected messages that were not deleted.
+	Failed int
+	// Selected counts the messages the policy selected. In test mode they
+	// are left in place.
+	Selected int
+}
+
+// Purge deletes the messages of channelID that policy selects, newest first
+// and at most clean.PurgeMaxDeleteCount of them. Messages past the bulk-delete
+// age go one at a time at the deep clean pace. The selection goes through
+// the archiver first; if it fails, nothing is deleted. A channel already
+// being cleaned is left alone with clean.ErrChannelBusy. Under a test mode
+// context the selection is only counted.
+func (s *Service) Purge(ctx context.Context, guildID discord.GuildID, channelID discord.ChannelID, policy clean.PurgePolicy) (PurgeResult, error) {
+	var result PurgeResult
+	unlock, ok := s.channels.TryLock(channelID)
+	if !ok {
+		return result, clean.ErrChannelBusy
+	}
+	defer unlock()
+	selected, err := s.selectPurge(ctx, guildID, channelID, policy, &result)
+	if err != nil {
+		return result, err
+	}
+	result.Selected = len(selected)
+	if len(selected) == 0 || coremod.InTestMode(ctx) {
+		return result, nil
+	}
+
+	if s.archiver != nil {
+		deletion := clean.Deletion{GuildID: guildID.String(), ChannelID: channelID.String(), Messages: selected}
+		if err := s.archiver(ctx, deletion); err != nil {
+			return result, fmt.Errorf("archive messages: %w", err)
+		}
+	}
+
+	categorized := clean.CategorizeMessages(selected, s.now)
+	tooOld := s.deleteBulk(channelID, categorized.BulkIDs, func(ids ...string) { result.Deleted += len(ids) })
+	categorized.SingleIDs = append(categorized.SingleIDs, tooOld...)
+	s.deletePaced(ctx, channelID, categorized.SingleIDs, nil, func(string) { result.Deleted++ })
+	result.Failed = len(selected) - result.Deleted
+	return result, nil
+}
+
+// selectPurge walks channelID from the newest message and returns what
+// policy selects.
+func (s *Service) selectPurge(ctx context.Context, guildID discord.GuildID, channelID discord.ChannelID, policy clean.PurgePolicy, result *PurgeResult) ([]clean.Message, error) {
+	var (
+		selected []clean.Message
+		before   discord.MessageID
+		newer    int
+	)
+	now := s.now()
+	roles := newAuthorRoles(s.client, guildID.String(), policy.ExemptRoleIDs)
+
+	for result.Scanned < clean.PurgeSearchWindow && len(selected) < clean.PurgeMaxDeleteCount {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		limit := uint(min(100, clean.PurgeSearchWindow-result.Scanned))
+		var (
+			page []discord.Message
+			err  error
+		)
+		if before.IsValid() {
+			page, err = s.client.MessagesBefore(channelID, before, limit)
+		} else {
+			page, err = s.client.Messages(channelID, limit)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fetch messages: %w", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		for _, m := range page {
+			result.Scanned++
+			authorRoles, err := roles.lookup(m)
+			if err != nil {
+				return nil, err
+			}
+			msg := MessageFrom(m)
+			msg.AuthorRoleIDs = authorRoles
+			if policy.Exempts(msg) {
+				result.Exempt++
+				continue
+			}
+			if policy.Selects(msg, newer, now) {
+				selected = append(selected, msg)
+				if len(selected) == clean.PurgeMaxDeleteCount {
+					break
+				}
+			}
+			newer++
+		}
+		before = page[len(page)-1].ID
+		if uint(len(page)) < limit {
+			break
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,108 @@
+package clean
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/clean"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+func TestPurge_KeepLast(t *testing.T) {
+	t.Parallel()
+	mockClock := time.Now()
+	at := func(age time.Duration) discord.Timestamp { return discord.NewTimestamp(mockClock.Add(-age)) }
+
+	client := &mockClient{
+		messagesFunc: func(limit uint) ([]discord.Message, error) {
+			return []discord.Message{
+				{ID: 6, Author: discord.User{ID: 1}, Timestamp: at(time.Minute)},
+				{ID: 5, Author: discord.User{ID: 9}, Timestamp: at(time.Hour)},
+				{ID: 4, Author: discord.User{ID: 1}, Timestamp: at(2 * time.Hour)},
+				{ID: 3, Author: discord.User{ID: 1}, Timestamp: at(3 * time.Hour), Pinned: true},
+				{ID: 2, Author: discord.User{ID: 1}, Timestamp: at(4 * time.Hour)},
+				{ID: 1, Author: discord.User{ID: 1}, Timestamp: at(30 * 24 * time.Hour)},
+			}, nil
+		},
+	}
+	var bulk []discord.MessageID
+	client.deleteMessagesFunc = func(ids []discord.MessageID) error {
+		bulk = append(bulk, ids...)
+		return nil
+	}
+	var archived []clean.Message
+	svc := NewService(client, &InMemoryMetrics{}, slog.Default(), WithDeepPace(0), WithArchiver(func(_ context.Context, d clean.Deletion) error {
+		archived = d.Messages
+		return nil
+	}))
+	svc.now = func() time.Time { return mockClock }
+
+	result, err := svc.Purge(context.Background(), 10, 20, clean.PurgePolicy{KeepLast: 2, ExemptUserIDs: []string{"9"}})
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if result.Scanned != 6 || result.Exempt != 2 || result.Deleted != 2 || result.Failed != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if len(archived) != 2 {
+		t.Fatalf("expected the selection to be archived first, got %+v", archived)
+	}
+	// A lone recent message cannot be bulk deleted, so both go one at a time.
+	if len(bulk) != 0 || !slices.Equal(client.deletedMsgs, []discord.MessageID{2, 1}) {
+		t.Fatalf("bulk deleted %v and single deleted %v", bulk, client.deletedMsgs)
+	}
+}
+
+func TestPurge_ArchiveFailureKeepsMessages(t *testing.T) {
+	t.Parallel()
+	mockClock := time.Now()
+	client := &mockClient{
+		messagesFunc: func(limit uint) ([]discord.Message, error) {
+			return []discord.Message{{ID: 1, Timestamp: discord.NewTimestamp(mockClock.Add(-48 * time.Hour))}}, nil
+		},
+		deleteMessagesFunc: func([]discord.MessageID) error {
+			t.Error("messages deleted although the archive failed")
+			return nil
+		},
+	}
+	svc := NewService(client, &InMemoryMetrics{}, slog.Default(), WithArchiver(func(context.Context, clean.Deletion) error {
+		return errors.New("database down")
+	}))
+	svc.now = func() time.Time { return mockClock }
+
+	if _, err := svc.Purge(context.Background(), 10, 20, clean.PurgePolicy{MaxAge: 24 * time.Hour}); err == nil {
+		t.Fatal("expected the archive failure to abort the purge")
+	}
+}
+
+func TestPurge_TestModeOnlyCounts(t *testing.T) {
+	t.Parallel()
+	mockClock := time.Now()
+	client := &mockClient{
+		messagesFunc: func(limit uint) ([]discord.Message, error) {
+			return []discord.Message{{ID: 1, Timestamp: discord.NewTimestamp(mockClock.Add(-48 * time.Hour))}}, nil
+		},
+		deleteMessagesFunc: func([]discord.MessageID) error {
+			t.Error("messages deleted in test mode")
+			return nil
+		},
+	}
+	svc := NewService(client, &InMemoryMetrics{}, slog.Default(), WithArchiver(func(context.Context, clean.Deletion) error {
+		t.Error("messages archived in test mode")
+		return nil
+	}))
+	svc.now = func() time.Time { return mockClock }
+
+	result, err := svc.Purge(coremod.WithTestMode(context.Background()), 10, 20, clean.PurgePolicy{MaxAge: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if result.Selected != 1 || result.Deleted != 0 || result.Failed != 0 || len(client.deletedMsgs) != 0 {
+		t.Fatalf("unexpected result %+v, deleted %v", result, client.deletedMsgs)
+	}
+}
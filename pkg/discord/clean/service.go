@@ -1,10 +1,12 @@
 package clean
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -12,7 +14,11 @@ import (
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
 	"github.com/small-frappuccino/discordcore/pkg/clean"
+	discordlogging "github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	"github.com/small-frappuccino/discordcore/pkg/keylock"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -40,87 +46,150 @@ type Client interface {
 	MessagesBefore(channelID discord.ChannelID, before discord.MessageID, limit uint) ([]discord.Message, error)
 	DeleteMessages(channelID discord.ChannelID, messageIDs []discord.MessageID, reason api.AuditLogReason) error
 	DeleteMessage(channelID discord.ChannelID, messageID discord.MessageID, reason api.AuditLogReason) error
-	SendMessageComplex(channelID discord.ChannelID, data api.SendMessageData) (*discord.Message, error)
+	Member(guildID discord.GuildID, userID discord.UserID) (*discord.Member, error)
 }
 
 // Service orchestrates the discord-facing lifecycle of a clean command operation, handling API pagination, batch fallback degradation, and telemetry.
 type Service struct {
-	client  Client
-	metrics Metrics
-	logger  *slog.Logger
-	now     func() time.Time
-	wg      sync.WaitGroup
+	client   Client
+	metrics  Metrics
+	logger   *slog.Logger
+	now      func() time.Time
+	archiver clean.Archiver
+	pace     time.Duration
+	channels *keylock.Mutex[discord.ChannelID]
+}
+
+// defaultDeepPace spaces the single deletions of a deep clean. Discord allows
+// only a handful of deletions of old messages per channel every few seconds.
+const defaultDeepPace = time.Second
+
+// Option configures optional Service behavior.
+type Option func(*Service)
+
+// WithArchiver runs archiver on every batch of matched messages before it is
+// deleted. A failing archiver aborts the clean.
+func WithArchiver(archiver clean.Archiver) Option {
+	return func(s *Service) { s.archiver = archiver }
+}
+
+// WithDeepPace overrides the delay between single deletions of a deep clean.
+func WithDeepPace(pace time.Duration) Option {
+	return func(s *Service) { s.pace = pace }
+}
+
+// WithChannelLocks makes the Service take its channel locks from locks. Services
+// sharing locks refuse to clean a channel another of them is cleaning, so a
+// /clean and an auto-purge exclude each other.
+func WithChannelLocks(locks *keylock.Mutex[discord.ChannelID]) Option {
+	return func(s *Service) { s.channels = locks }
 }
 
 // NewService initializes a Clean service bounded by the provided client and metrics adapters.
-func NewService(client Client, metrics Metrics, logger *slog.Logger) *Service {
+func NewService(client Client, metrics Metrics, logger *slog.Logger, opts ...Option) *Service {
 	if metrics == nil {
 		metrics = NopMetrics{}
 	}
 	if logger == nil {
 		logger = slog.Default()
 	}
-	return &Service{
-		client:  client,
-		metrics: metrics,
-		logger:  logger,
-		now:     time.Now,
+	s := &Service{
+		client:   client,
+		metrics:  metrics,
+		logger:   logger,
+		now:      time.Now,
+		pace:     defaultDeepPace,
+		channels: new(keylock.Mutex[discord.ChannelID]),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// Close gracefully waits for all pending async operations (like audit logging) to finish.
+// Close releases the Service. Audit logs are queued on the log publisher,
+// which delivers them on its own, so nothing is left to wait for.
 func (s *Service) Close() error {
-	s.wg.Wait()
 	return nil
 }
 
 // ExecuteClean computes and enacts the deletion payload. It guarantees that a failure during the deletion phase does not panic or infinitely block.
-func (s *Service) ExecuteClean(ctx context.Context, channelID discord.ChannelID, filter clean.Filter, auditChannelID discord.ChannelID, requestedBy string) (int, error) {
+// The archive and the audit log are posted through the log publisher ctx
+// carries; without one a clean that archives is refused.
+func (s *Service) ExecuteClean(ctx context.Context, channelID discord.ChannelID, filter clean.Filter, auditChannelID discord.ChannelID, requestedBy string) (clean.Outcome, error) {
 	s.metrics.RecordCleanAttempt()
 	start := s.now()
+	if !filter.Preview {
+		unlock, ok := s.channels.TryLock(channelID)
+		if !ok {
+			s.metrics.RecordCleanFailure("channel_busy", 0)
+			return clean.Outcome{}, clean.ErrChannelBusy
+		}
+		defer unlock()
+	}
 
-	messages, err := s.fetchAndFilter(channelID, filter)
+	messages, outcome, err := s.fetchAndFilter(channelID, filter)
 	if err != nil {
 		duration := s.now().Sub(start).Milliseconds()
 		s.metrics.RecordCleanFailure("fetch_failed", duration)
-		return 0, fmt.Errorf("fetch messages: %w", err)
+		return clean.Outcome{}, fmt.Errorf("fetch messages: %w", err)
 	}
 
 	if len(messages) == 0 {
-		return 0, nil
+		return outcome, nil
+	}
+	if filter.Preview {
+		outcome.Messages = messages
+		return outcome, nil
+	}
+
+	// Deleting without the archive would lose content the moderator asked to
+	// keep, so a failed upload aborts the clean.
+	var archivers []clean.Archiver
+	if filter.ArchiveChannelID != "" {
+		target, err := discord.ParseSnowflake(filter.ArchiveChannelID)
+		if err != nil {
+			s.metrics.RecordCleanFailure("archive_failed", s.now().Sub(start).Milliseconds())
+			return clean.Outcome{}, fmt.Errorf("parse archive channel: %w", err)
+		}
+		archivers = append(archivers, ChannelArchiver(discord.ChannelID(target), s.now))
+	}
+	if s.archiver != nil {
+		archivers = append(archivers, s.archiver)
+	}
+	if len(archivers) > 0 {
+		deletion := clean.Deletion{GuildID: filter.GuildID, ChannelID: channelID.String(), RequestedBy: requestedBy, Messages: messages}
+		if err := clean.ChainArchivers(archivers...)(ctx, deletion); err != nil {
+			s.metrics.RecordCleanFailure("archive_failed", s.now().Sub(start).Milliseconds())
+			return clean.Outcome{}, fmt.Errorf("archive messages: %w", err)
+		}
 	}
 
 	categorized := clean.CategorizeMessages(messages, s.now)
 
 	var deletedCount int32
-
-	if len(categorized.BulkIDs) > 0 {
-		bulkDiscordIDs := make([]discord.MessageID, 0, len(categorized.BulkIDs))
-		for _, id := range categorized.BulkIDs {
-			parsed, _ := discord.ParseSnowflake(id)
-			bulkDiscordIDs = append(bulkDiscordIDs, discord.MessageID(parsed))
-		}
-
-		err := s.client.DeleteMessages(channelID, bulkDiscordIDs, "")
-		if err != nil {
-			var httpErr *httputil.HTTPError
-			if errors.As(err, &httpErr) && httpErr.Code == 50034 {
-				// Operational annotation: Code 50034 indicates some targets exceed the 14-day bulk delete threshold.
-				// We intentionally swallow the error and gracefully cascade the failing payload directly into the single-deletion pipeline.
-				s.logger.Warn("Bulk delete failed with 50034, falling back to sequential", "channel_id", channelID)
-				categorized.SingleIDs = append(categorized.SingleIDs, categorized.BulkIDs...)
-			} else {
-				for i := 0; i < len(bulkDiscordIDs); i++ {
-					s.metrics.RecordCleanDeleteFailure("bulk_error")
-				}
-				s.logger.Error("Bulk delete failed", "error", err, "channel_id", channelID)
-			}
-		} else {
-			atomic.AddInt32(&deletedCount, int32(len(bulkDiscordIDs)))
+	var deletedMu sync.Mutex
+	deletedIDs := make(map[string]struct{}, len(messages))
+	markDeleted := func(ids ...string) {
+		deletedMu.Lock()
+		defer deletedMu.Unlock()
+		for _, id := range ids {
+			deletedIDs[id] = struct{}{}
 		}
 	}
 
-	if len(categorized.SingleIDs) > 0 {
+	tooOld := s.deleteBulk(channelID, categorized.BulkIDs, func(ids ...string) {
+		atomic.AddInt32(&deletedCount, int32(len(ids)))
+		markDeleted(ids...)
+	})
+	categorized.SingleIDs = append(categorized.SingleIDs, tooOld...)
+
+	if len(categorized.SingleIDs) > 0 && filter.Deep {
+		s.deletePaced(ctx, channelID, categorized.SingleIDs, filter.Progress, func(id string) {
+			atomic.AddInt32(&deletedCount, 1)
+			markDeleted(id)
+		})
+	} else if len(categorized.SingleIDs) > 0 {
 		eg, _ := errgroup.WithContext(ctx)
 		eg.SetLimit(10)
 
@@ -134,6 +203,7 @@ func (s *Service) ExecuteClean(ctx context.Context, channelID discord.ChannelID,
 					s.logger.Warn("Single delete failed", "error", err, "message_id", idStr)
 				} else {
 					atomic.AddInt32(&deletedCount, 1)
+					markDeleted(idStr)
 				}
 				return nil
 			})
@@ -146,27 +216,107 @@ func (s *Service) ExecuteClean(ctx context.Context, channelID discord.ChannelID,
 	s.metrics.RecordCleanSuccess(durationMs, finalDeleted)
 
 	if auditChannelID.IsValid() && finalDeleted > 0 {
-		// Operational annotation: Audit logging is intentionally asynchronous. A failure here is non-fatal
-		// and must not impact the primary execution loop's success report.
-		s.wg.Add(1)
-		go func() {
-			defer s.wg.Done()
-			s.dispatchAuditLog(auditChannelID, channelID, finalDeleted, filter, requestedBy)
-		}()
+		// The audit log is queued, so a failure to deliver it never affects
+		// the outcome reported for the clean.
+		s.dispatchAuditLog(ctx, auditChannelID, channelID, finalDeleted, filter, requestedBy)
+	}
+
+	outcome.Deleted = finalDeleted
+	for _, m := range messages {
+		if _, ok := deletedIDs[strings.TrimSpace(m.ID)]; ok {
+			outcome.Messages = append(outcome.Messages, m)
+		}
 	}
+	return outcome, nil
+}
+
+// deletePaced removes old messages one at a time, waiting s.pace between
+// requests, and stops early once ctx is done. Failures are counted and
+// skipped like in the concurrent path.
+func (s *Service) deletePaced(ctx context.Context, channelID discord.ChannelID, ids []string, progress func(done, total int), deleted func(id string)) {
+	for i, idStr := range ids {
+		if i > 0 && s.pace > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(s.pace):
+			}
+		}
+		parsed, _ := discord.ParseSnowflake(idStr)
+		if err := s.client.DeleteMessage(channelID, discord.MessageID(parsed), ""); err != nil {
+			s.metrics.RecordCleanDeleteFailure("single_error")
+			s.logger.Warn("Single delete failed", "error", err, "message_id", idStr)
+		} else {
+			deleted(idStr)
+		}
+		if progress != nil {
+			progress(i+1, len(ids))
+		}
+	}
+}
+
+// bulkDeleteLimit is the most messages one bulk delete request may carry.
+const bulkDeleteLimit = 100
+
+// deleteBulk removes ids in batches Discord accepts: 2 to bulkDeleteLimit
+// messages per bulk request, with a lone leftover deleted on its own.
+// deleted receives each batch that went through. Batches rejected with
+// 50034 (a message past the bulk-delete age) are returned so the caller can
+// delete them one at a time; other failures are counted and skipped.
+func (s *Service) deleteBulk(channelID discord.ChannelID, ids []string, deleted func(ids ...string)) (tooOld []string) {
+	for len(ids) > 0 {
+		batch := ids[:min(bulkDeleteLimit, len(ids))]
+		ids = ids[len(batch):]
+
+		if len(batch) == 1 {
+			parsed, _ := discord.ParseSnowflake(batch[0])
+			if err := s.client.DeleteMessage(channelID, discord.MessageID(parsed), ""); err != nil {
+				s.metrics.RecordCleanDeleteFailure("single_error")
+				s.logger.Warn("Single delete failed", "error", err, "message_id", batch[0])
+				continue
+			}
+			deleted(batch...)
+			continue
+		}
 
-	return finalDeleted, nil
+		messageIDs := make([]discord.MessageID, 0, len(batch))
+		for _, id := range batch {
+			parsed, _ := discord.ParseSnowflake(id)
+			messageIDs = append(messageIDs, discord.MessageID(parsed))
+		}
+		err := s.client.DeleteMessages(channelID, messageIDs, "")
+		var httpErr *httputil.HTTPError
+		switch {
+		case err == nil:
+			deleted(batch...)
+		case errors.As(err, &httpErr) && httpErr.Code == 50034:
+			// Operational annotation: Code 50034 indicates some targets exceed the 14-day bulk delete threshold.
+			// The batch cascades into the single-deletion pipeline instead of failing.
+			s.logger.Warn("Bulk delete failed with 50034, falling back to sequential", "channel_id", channelID)
+			tooOld = append(tooOld, batch...)
+		default:
+			for range batch {
+				s.metrics.RecordCleanDeleteFailure("bulk_error")
+			}
+			s.logger.Error("Bulk delete failed", "error", err, "channel_id", channelID)
+		}
+	}
+	return tooOld
 }
 
-func (s *Service) fetchAndFilter(channelID discord.ChannelID, filter clean.Filter) ([]clean.Message, error) {
+func (s *Service) fetchAndFilter(channelID discord.ChannelID, filter clean.Filter) ([]clean.Message, clean.Outcome, error) {
 	var allMessages []clean.Message
+	var outcome clean.Outcome
 	var before discord.MessageID
 	scanned := 0
+	roles := newAuthorRoles(s.client, filter.GuildID, filter.ProtectedRoleIDs)
 
-	for scanned < clean.CleanSearchWindow && len(allMessages) < filter.Count {
+	window := filter.SearchWindow()
+
+	for scanned < window && len(allMessages) < filter.Count {
 		limit := uint(100)
-		if clean.CleanSearchWindow-scanned < int(limit) {
-			limit = uint(clean.CleanSearchWindow - scanned)
+		if window-scanned < int(limit) {
+			limit = uint(window - scanned)
 		}
 
 		var page []discord.Message
@@ -178,7 +328,7 @@ func (s *Service) fetchAndFilter(channelID discord.ChannelID, filter clean.Filte
 		}
 
 		if err != nil {
-			return nil, err
+			return nil, clean.Outcome{}, err
 		}
 		if len(page) == 0 {
 			break
@@ -186,17 +336,19 @@ func (s *Service) fetchAndFilter(channelID discord.ChannelID, filter clean.Filte
 
 		var cleanPage []clean.Message
 		for _, m := range page {
-			cleanPage = append(cleanPage, clean.Message{
-				ID:        m.ID.String(),
-				AuthorID:  m.Author.ID.String(),
-				Content:   m.Content,
-				Timestamp: m.Timestamp.Time(),
-				Pinned:    m.Pinned,
-			})
+			authorRoles, err := roles.lookup(m)
+			if err != nil {
+				return nil, clean.Outcome{}, err
+			}
+			msg := MessageFrom(m)
+			msg.AuthorRoleIDs = authorRoles
+			cleanPage = append(cleanPage, msg)
 		}
 
 		result := clean.ApplyFilter(cleanPage, filter, len(allMessages))
 		allMessages = append(allMessages, result.Matched...)
+		outcome.SkippedPinned += result.SkippedPinned
+		outcome.SkippedProtected += result.SkippedProtected
 		scanned += result.Scanned
 
 		if len(page) > 0 {
@@ -208,10 +360,123 @@ func (s *Service) fetchAndFilter(channelID discord.ChannelID, filter clean.Filte
 		}
 	}
 
-	return allMessages, nil
+	return allMessages, outcome, nil
+}
+
+// authorRoles resolves message authors' roles for role protections, once per
+// author and clean.
+type authorRoles struct {
+	client  Client
+	guildID discord.GuildID
+	enabled bool
+	cache   map[discord.UserID][]string
+}
+
+// newAuthorRoles only looks roles up when some roles are protected.
+func newAuthorRoles(client Client, guildID string, protectedRoleIDs []string) *authorRoles {
+	parsed, _ := discord.ParseSnowflake(guildID)
+	return &authorRoles{
+		client:  client,
+		guildID: discord.GuildID(parsed),
+		enabled: len(protectedRoleIDs) > 0 && parsed.IsValid(),
+		cache:   make(map[discord.UserID][]string),
+	}
+}
+
+// lookup returns the role IDs of m's author. Webhook messages and authors
+// who left the guild have none. Any other failure aborts the clean: deleting
+// without knowing the roles could remove a protected member's messages.
+func (r *authorRoles) lookup(m discord.Message) ([]string, error) {
+	if !r.enabled || m.WebhookID.IsValid() {
+		return nil, nil
+	}
+	if cached, ok := r.cache[m.Author.ID]; ok {
+		return cached, nil
+	}
+	var roleIDs []string
+	member, err := r.client.Member(r.guildID, m.Author.ID)
+	if err != nil {
+		var httpErr *httputil.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != 10007 {
+			return nil, fmt.Errorf("resolve roles of %s: %w", m.Author.ID, err)
+		}
+	} else {
+		roleIDs = make([]string, 0, len(member.RoleIDs))
+		for _, id := range member.RoleIDs {
+			roleIDs = append(roleIDs, id.String())
+		}
+	}
+	r.cache[m.Author.ID] = roleIDs
+	return roleIDs, nil
+}
+
+// MessageFrom converts a Discord message for filtering and archiving.
+func MessageFrom(m discord.Message) clean.Message {
+	return clean.Message{
+		ID:             m.ID.String(),
+		AuthorID:       m.Author.ID.String(),
+		AuthorName:     m.Author.Username,
+		Bot:            m.Author.Bot,
+		Content:        m.Content,
+		HasAttachments: len(m.Attachments) > 0,
+		AttachmentURLs: attachmentURLs(m.Attachments),
+		HasEmbeds:      len(m.Embeds) > 0,
+		Timestamp:      m.Timestamp.Time(),
+		Pinned:         m.Pinned,
+	}
+}
+
+func attachmentURLs(attachments []discord.Attachment) []string {
+	if len(attachments) == 0 {
+		return nil
+	}
+	urls := make([]string, 0, len(attachments))
+	for _, a := range attachments {
+		urls = append(urls, a.URL)
+	}
+	return urls
+}
+
+// errNoPublisher reports a clean asked to archive with no log publisher in
+// its context to post the archive through.
+var errNoPublisher = errors.New("no log publisher")
+
+// ChannelArchiver uploads each deletion as a JSON transcript to target,
+// through the log publisher the clean's context carries.
+func ChannelArchiver(target discord.ChannelID, now func() time.Time) clean.Archiver {
+	return func(ctx context.Context, d clean.Deletion) error {
+		pub := discordlogging.PublisherFrom(ctx)
+		if pub == nil {
+			return errNoPublisher
+		}
+		at := now()
+		data, err := clean.BuildArchive(d.ChannelID, d.RequestedBy, d.Messages, at)
+		if err != nil {
+			return err
+		}
+		description := fmt.Sprintf("Archive of %d message(s) about to be deleted from <#%s>.", len(d.Messages), d.ChannelID)
+		if d.RequestedBy != "" {
+			description = fmt.Sprintf("Archive of %d message(s) about to be deleted from <#%s>, requested by <@%s>.", len(d.Messages), d.ChannelID, d.RequestedBy)
+		}
+		_, err = pub.Publish(ctx, discordlogging.LogPost{
+			GuildID:   d.GuildID,
+			ChannelID: target,
+			EventType: logging.LogEventCleanAction,
+			ActorID:   d.RequestedBy,
+			Embed:     discord.Embed{Description: description, Timestamp: discord.NewTimestamp(at)},
+			Files:     []sendpart.File{{Name: clean.ArchiveFileName(d.ChannelID, at), Reader: bytes.NewReader(data)}},
+		})
+		return err
+	}
 }
 
-func (s *Service) dispatchAuditLog(auditChannelID discord.ChannelID, targetChannelID discord.ChannelID, deleted int, filter clean.Filter, requestedBy string) {
+func (s *Service) dispatchAuditLog(ctx context.Context, auditChannelID discord.ChannelID, targetChannelID discord.ChannelID, deleted int, filter clean.Filter, requestedBy string) {
+	pub := discordlogging.PublisherFrom(ctx)
+	if pub == nil {
+		s.metrics.RecordCleanAuditLogFailure()
+		s.logger.Error("Failed to send clean audit log", "error", errNoPublisher, "audit_channel_id", auditChannelID)
+		return
+	}
 	embed := discord.Embed{
 		Title:       "Clean Command Executed",
 		Color:       0x3498db,
@@ -221,12 +486,11 @@ func (s *Service) dispatchAuditLog(auditChannelID discord.ChannelID, targetChann
 		},
 		Timestamp: discord.NewTimestamp(s.now()),
 	}
-
-	_, err := s.client.SendMessageComplex(auditChannelID, api.SendMessageData{
-		Embeds: []discord.Embed{embed},
+	pub.Notify(ctx, discordlogging.LogPost{
+		GuildID:   filter.GuildID,
+		ChannelID: auditChannelID,
+		EventType: logging.LogEventCleanAction,
+		ActorID:   requestedBy,
+		Embed:     embed,
 	})
-	if err != nil {
-		s.metrics.RecordCleanAuditLogFailure()
-		s.logger.Error("Failed to send clean audit log", "error", err, "audit_channel_id", auditChannelID)
-	}
 }
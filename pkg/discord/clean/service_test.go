@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"runtime"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -14,6 +15,9 @@ import (
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/utils/httputil"
 	"github.com/small-frappuccino/discordcore/pkg/clean"
+	discordlogging "github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	"github.com/small-frappuccino/discordcore/pkg/keylock"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
 )
 
 type InMemoryMetrics struct {
@@ -48,7 +52,7 @@ type mockClient struct {
 	messagesBeforeFunc func(before discord.MessageID, limit uint) ([]discord.Message, error)
 	deleteMessagesFunc func(messageIDs []discord.MessageID) error
 	deleteMessageFunc  func(messageID discord.MessageID) error
-	createMessageFunc  func(data api.SendMessageData) (*discord.Message, error)
+	memberFunc         func(userID discord.UserID) (*discord.Member, error)
 	deleteMsgErr       error
 	deletedMsgs        []discord.MessageID
 	createMsgErr       error
@@ -85,13 +89,33 @@ func (m *mockClient) DeleteMessage(channelID discord.ChannelID, messageID discor
 	return nil
 }
 
-func (m *mockClient) SendMessageComplex(channelID discord.ChannelID, data api.SendMessageData) (*discord.Message, error) {
-	if m.createMessageFunc != nil {
-		return m.createMessageFunc(data)
+// fakePublisher stands in for the log publisher a clean's context carries.
+type fakePublisher struct {
+	mu          sync.Mutex
+	publishFunc func(post discordlogging.LogPost) (*discord.Message, error)
+	notified    []discordlogging.LogPost
+}
+
+func (p *fakePublisher) Notify(_ context.Context, post discordlogging.LogPost) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.notified = append(p.notified, post)
+}
+
+func (p *fakePublisher) Publish(_ context.Context, post discordlogging.LogPost) (*discord.Message, error) {
+	if p.publishFunc != nil {
+		return p.publishFunc(post)
 	}
 	return &discord.Message{}, nil
 }
 
+func (m *mockClient) Member(guildID discord.GuildID, userID discord.UserID) (*discord.Member, error) {
+	if m.memberFunc != nil {
+		return m.memberFunc(userID)
+	}
+	return &discord.Member{}, nil
+}
+
 func TestExecuteClean_Pagination(t *testing.T) {
 	t.Parallel()
 	mockClock := time.Now()
@@ -129,12 +153,12 @@ func TestExecuteClean_Pagination(t *testing.T) {
 	svc.now = func() time.Time { return mockClock }
 
 	filter := clean.Filter{Count: 100}
-	deleted, err := svc.ExecuteClean(context.Background(), 1, filter, 0, "test")
+	outcome, err := svc.ExecuteClean(context.Background(), 1, filter, 0, "test")
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
-	if deleted != 100 {
-		t.Errorf("expected 100 deleted, got %d", deleted)
+	if outcome.Deleted != 100 {
+		t.Errorf("expected 100 deleted, got %d", outcome.Deleted)
 	}
 }
 
@@ -163,12 +187,12 @@ func TestExecuteClean_Degradation_50034(t *testing.T) {
 	svc.now = func() time.Time { return mockClock }
 
 	filter := clean.Filter{Count: 10}
-	deleted, err := svc.ExecuteClean(context.Background(), 1, filter, 0, "test")
+	outcome, err := svc.ExecuteClean(context.Background(), 1, filter, 0, "test")
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
-	if deleted != 10 {
-		t.Errorf("expected 10 deleted through fallback, got %d", deleted)
+	if outcome.Deleted != 10 {
+		t.Errorf("expected 10 deleted through fallback, got %d", outcome.Deleted)
 	}
 }
 
@@ -199,12 +223,12 @@ func TestExecuteClean_Concurrency_Race(t *testing.T) {
 	filter := clean.Filter{Count: 100}
 
 	t.Run("concurrency race test", func(t *testing.T) {
-		deleted, err := svc.ExecuteClean(context.Background(), 1, filter, 0, "test")
+		outcome, err := svc.ExecuteClean(context.Background(), 1, filter, 0, "test")
 		if err != nil {
 			t.Fatalf("unexpected err: %v", err)
 		}
-		if deleted != 100 {
-			t.Errorf("expected 100, got %d", deleted)
+		if outcome.Deleted != 100 {
+			t.Errorf("expected 100, got %d", outcome.Deleted)
 		}
 
 		metrics.mu.Lock()
@@ -218,38 +242,433 @@ func TestExecuteClean_Concurrency_Race(t *testing.T) {
 func TestExecuteClean_AuditDispatch(t *testing.T) {
 	t.Parallel()
 	mockClock := time.Now()
-	var auditLogged atomic.Bool
 
 	client := &mockClient{
 		messagesFunc: func(limit uint) ([]discord.Message, error) {
 			return []discord.Message{{ID: 1, Timestamp: discord.NewTimestamp(mockClock)}}, nil
 		},
 		deleteMessagesFunc: func(messageIDs []discord.MessageID) error { return nil },
-		createMessageFunc: func(data api.SendMessageData) (*discord.Message, error) {
-			if len(data.Embeds) > 0 {
-				auditLogged.Store(true)
-				if data.Embeds[0].Title != "Clean Command Executed" {
-					t.Errorf("unexpected embed title: %s", data.Embeds[0].Title)
-				}
-			}
-			return nil, errors.New("audit failure") // ensure it doesn't break execution
-		},
 	}
+	pub := &fakePublisher{}
 
 	metrics := &InMemoryMetrics{}
 	svc := NewService(client, metrics, slog.Default())
 	svc.now = func() time.Time { return mockClock }
 
-	deleted, err := svc.ExecuteClean(context.Background(), 1, clean.Filter{Count: 1}, 2, "tester")
+	ctx := discordlogging.WithPublisher(context.Background(), pub)
+	outcome, err := svc.ExecuteClean(ctx, 1, clean.Filter{Count: 1, GuildID: "9"}, 2, "tester")
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
-	if deleted != 1 {
-		t.Errorf("expected 1 deleted, got %d", deleted)
+	if outcome.Deleted != 1 {
+		t.Errorf("expected 1 deleted, got %d", outcome.Deleted)
+	}
+
+	if len(pub.notified) != 1 {
+		t.Fatalf("expected one audit log, got %+v", pub.notified)
+	}
+	if got := pub.notified[0]; got.Embed.Title != "Clean Command Executed" || got.ChannelID != 2 || got.GuildID != "9" || got.EventType != logging.LogEventCleanAction {
+		t.Errorf("unexpected audit log: %+v", got)
+	}
+}
+
+func TestExecuteClean_ArchiveBeforeDelete(t *testing.T) {
+	t.Parallel()
+	mockClock := time.Now()
+	var archived atomic.Bool
+
+	client := &mockClient{
+		messagesFunc: func(limit uint) ([]discord.Message, error) {
+			return []discord.Message{{ID: 1, Content: "gone", Timestamp: discord.NewTimestamp(mockClock)}}, nil
+		},
+		deleteMessagesFunc: func(messageIDs []discord.MessageID) error {
+			if !archived.Load() {
+				t.Error("messages deleted before the archive was posted")
+			}
+			return nil
+		},
+	}
+	pub := &fakePublisher{publishFunc: func(post discordlogging.LogPost) (*discord.Message, error) {
+		if len(post.Files) != 1 || post.ChannelID != 5 {
+			t.Errorf("expected one archive file in channel 5, got %d in %v", len(post.Files), post.ChannelID)
+		}
+		archived.Store(true)
+		return &discord.Message{}, nil
+	}}
+	svc := NewService(client, &InMemoryMetrics{}, slog.Default())
+	svc.now = func() time.Time { return mockClock }
+
+	ctx := discordlogging.WithPublisher(context.Background(), pub)
+	outcome, err := svc.ExecuteClean(ctx, 1, clean.Filter{Count: 1, ArchiveChannelID: "5"}, 0, "tester")
+	if err != nil || outcome.Deleted != 1 {
+		t.Fatalf("ExecuteClean = %+v, %v", outcome, err)
+	}
+
+	// A failed upload must leave every message in place.
+	pub.publishFunc = func(discordlogging.LogPost) (*discord.Message, error) { return nil, errors.New("upload failed") }
+	client.deleteMessagesFunc = func([]discord.MessageID) error {
+		t.Error("messages deleted although archiving failed")
+		return nil
+	}
+	if _, err := svc.ExecuteClean(ctx, 1, clean.Filter{Count: 1, ArchiveChannelID: "5"}, 0, "tester"); err == nil {
+		t.Fatal("expected archive failure to abort the clean")
+	}
+	// Without a publisher there is nowhere to keep the archive.
+	if _, err := svc.ExecuteClean(context.Background(), 1, clean.Filter{Count: 1, ArchiveChannelID: "5"}, 0, "tester"); err == nil {
+		t.Fatal("expected a clean without a log publisher to refuse archiving")
+	}
+}
+
+func TestExecuteClean_Protections(t *testing.T) {
+	t.Parallel()
+	mockClock := time.Now()
+	var lookups atomic.Int32
+
+	client := &mockClient{
+		messagesFunc: func(limit uint) ([]discord.Message, error) {
+			return []discord.Message{
+				{ID: 5, Author: discord.User{ID: 20}, Timestamp: discord.NewTimestamp(mockClock)},
+				{ID: 4, Author: discord.User{ID: 30}, Timestamp: discord.NewTimestamp(mockClock)},
+				{ID: 3, Author: discord.User{ID: 10}, Pinned: true, Timestamp: discord.NewTimestamp(mockClock)},
+				{ID: 2, Author: discord.User{ID: 10}, Timestamp: discord.NewTimestamp(mockClock)},
+				{ID: 1, Author: discord.User{ID: 40}, Timestamp: discord.NewTimestamp(mockClock)},
+			}, nil
+		},
+		memberFunc: func(userID discord.UserID) (*discord.Member, error) {
+			lookups.Add(1)
+			switch userID {
+			case 20:
+				return &discord.Member{RoleIDs: []discord.RoleID{7}}, nil
+			case 40:
+				return nil, &httputil.HTTPError{Code: 10007, Message: "Unknown Member"}
+			}
+			return &discord.Member{}, nil
+		},
+		deleteMessagesFunc: func(messageIDs []discord.MessageID) error {
+			if len(messageIDs) != 2 || messageIDs[0] != 2 || messageIDs[1] != 1 {
+				t.Errorf("deleted %v, want [2 1]", messageIDs)
+			}
+			return nil
+		},
+	}
+	svc := NewService(client, &InMemoryMetrics{}, slog.Default())
+	svc.now = func() time.Time { return mockClock }
+
+	outcome, err := svc.ExecuteClean(context.Background(), 1, clean.Filter{
+		Count:            10,
+		GuildID:          "9",
+		ProtectedRoleIDs: []string{"7"},
+		ProtectedUserIDs: []string{"30"},
+	}, 0, "tester")
+	if err != nil {
+		t.Fatalf("ExecuteClean: %v", err)
+	}
+	if outcome.Deleted != 2 || outcome.SkippedPinned != 1 || outcome.SkippedProtected != 2 {
+		t.Fatalf("outcome = %+v, want 2 deleted, 1 pinned and 2 protected", outcome)
+	}
+	if len(outcome.Messages) != 2 || outcome.Messages[0].ID != "2" || outcome.Messages[1].ID != "1" {
+		t.Fatalf("outcome messages = %+v, want the deleted messages 2 and 1", outcome.Messages)
+	}
+	if got := lookups.Load(); got != 4 {
+		t.Fatalf("expected one member lookup per author, got %d", got)
+	}
+
+	// Unknown roles must not be read as unprotected.
+	client.memberFunc = func(discord.UserID) (*discord.Member, error) { return nil, errors.New("gateway timeout") }
+	client.deleteMessagesFunc = func([]discord.MessageID) error {
+		t.Error("messages deleted although roles could not be resolved")
+		return nil
+	}
+	if _, err := svc.ExecuteClean(context.Background(), 1, clean.Filter{Count: 10, GuildID: "9", ProtectedRoleIDs: []string{"7"}}, 0, "tester"); err == nil {
+		t.Fatal("expected a failed role lookup to abort the clean")
+	}
+}
+
+func TestExecuteClean_Preview(t *testing.T) {
+	t.Parallel()
+	mockClock := time.Now()
+
+	client := &mockClient{
+		messagesFunc: func(limit uint) ([]discord.Message, error) {
+			return []discord.Message{
+				{ID: 2, Content: "spam", Timestamp: discord.NewTimestamp(mockClock)},
+				{ID: 1, Content: "hello", Timestamp: discord.NewTimestamp(mockClock)},
+			}, nil
+		},
+		deleteMessagesFunc: func([]discord.MessageID) error {
+			t.Error("a preview deleted messages")
+			return nil
+		},
+	}
+	pub := &fakePublisher{publishFunc: func(discordlogging.LogPost) (*discord.Message, error) {
+		t.Error("a preview posted an archive")
+		return &discord.Message{}, nil
+	}}
+	svc := NewService(client, &InMemoryMetrics{}, slog.Default())
+	svc.now = func() time.Time { return mockClock }
+
+	outcome, err := svc.ExecuteClean(discordlogging.WithPublisher(context.Background(), pub), 1, clean.Filter{Count: 10, Contains: "spam", Preview: true, ArchiveChannelID: "5"}, 2, "tester")
+	if err != nil {
+		t.Fatalf("ExecuteClean: %v", err)
+	}
+	if outcome.Deleted != 0 || len(outcome.Messages) != 1 || outcome.Messages[0].ID != "2" {
+		t.Fatalf("preview outcome = %+v", outcome)
+	}
+}
+
+func TestExecuteClean_ArchiverOption(t *testing.T) {
+	t.Parallel()
+	mockClock := time.Now()
+	var archived []clean.Deletion
+
+	client := &mockClient{
+		messagesFunc: func(limit uint) ([]discord.Message, error) {
+			return []discord.Message{{ID: 1, Content: "gone", Timestamp: discord.NewTimestamp(mockClock)}}, nil
+		},
+		deleteMessagesFunc: func([]discord.MessageID) error {
+			if len(archived) == 0 {
+				t.Error("messages deleted before the archiver ran")
+			}
+			return nil
+		},
+	}
+	fail := false
+	svc := NewService(client, &InMemoryMetrics{}, slog.Default(), WithArchiver(func(_ context.Context, d clean.Deletion) error {
+		if fail {
+			return errors.New("storage unavailable")
+		}
+		archived = append(archived, d)
+		return nil
+	}))
+	svc.now = func() time.Time { return mockClock }
+
+	outcome, err := svc.ExecuteClean(context.Background(), 1, clean.Filter{Count: 1, GuildID: "9"}, 0, "tester")
+	if err != nil || outcome.Deleted != 1 {
+		t.Fatalf("ExecuteClean = %+v, %v", outcome, err)
+	}
+	if len(archived) != 1 || archived[0].GuildID != "9" || archived[0].RequestedBy != "tester" || archived[0].Messages[0].Content != "gone" {
+		t.Fatalf("archived %+v", archived)
+	}
+
+	fail = true
+	client.deleteMessagesFunc = func([]discord.MessageID) error {
+		t.Error("messages deleted although the archiver failed")
+		return nil
+	}
+	if _, err := svc.ExecuteClean(context.Background(), 1, clean.Filter{Count: 1}, 0, "tester"); err == nil {
+		t.Fatal("expected archiver failure to abort the clean")
+	}
+}
+
+func TestExecuteClean_DeepPacesOldMessages(t *testing.T) {
+	t.Parallel()
+	mockClock := time.Now()
+	old := discord.NewTimestamp(mockClock.Add(-30 * 24 * time.Hour))
+
+	var bulk []discord.MessageID
+	client := &mockClient{
+		messagesFunc: func(limit uint) ([]discord.Message, error) {
+			return []discord.Message{
+				{ID: 5, Timestamp: discord.NewTimestamp(mockClock)},
+				{ID: 4, Timestamp: discord.NewTimestamp(mockClock)},
+				{ID: 3, Timestamp: old},
+				{ID: 2, Timestamp: old},
+				{ID: 1, Timestamp: old},
+			}, nil
+		},
+		deleteMessagesFunc: func(ids []discord.MessageID) error {
+			bulk = append(bulk, ids...)
+			return nil
+		},
+		deleteMessageFunc: func(id discord.MessageID) error {
+			if id == 2 {
+				return errors.New("unknown message")
+			}
+			return nil
+		},
+	}
+	svc := NewService(client, &InMemoryMetrics{}, slog.Default(), WithDeepPace(0))
+	svc.now = func() time.Time { return mockClock }
+
+	var progress [][2]int
+	filter := clean.Filter{Count: 10, Deep: true, Progress: func(done, total int) {
+		progress = append(progress, [2]int{done, total})
+	}}
+	outcome, err := svc.ExecuteClean(context.Background(), 1, filter, 0, "tester")
+	if err != nil {
+		t.Fatalf("ExecuteClean: %v", err)
+	}
+	if want := []discord.MessageID{5, 4}; !slices.Equal(bulk, want) {
+		t.Fatalf("recent messages must still be bulk deleted, got %v", bulk)
+	}
+	if want := []discord.MessageID{3, 2, 1}; !slices.Equal(client.deletedMsgs, want) {
+		t.Fatalf("old messages deleted as %v, want %v in order", client.deletedMsgs, want)
+	}
+	if outcome.Deleted != 4 || len(outcome.Messages) != 4 {
+		t.Fatalf("expected the failed deletion to be skipped, got %+v", outcome)
+	}
+	if want := [][2]int{{1, 3}, {2, 3}, {3, 3}}; !slices.Equal(progress, want) {
+		t.Fatalf("progress = %v, want %v", progress, want)
+	}
+}
+
+func TestExecuteClean_BulkDeletesInBatches(t *testing.T) {
+	t.Parallel()
+	mockClock := time.Now()
+	const total = 201
+
+	page := func(from, limit int) []discord.Message {
+		var msgs []discord.Message
+		for id := from; id > 0 && len(msgs) < limit; id-- {
+			msgs = append(msgs, discord.Message{ID: discord.MessageID(id), Timestamp: discord.NewTimestamp(mockClock)})
+		}
+		return msgs
+	}
+	var batches []int
+	client := &mockClient{
+		messagesFunc: func(limit uint) ([]discord.Message, error) {
+			return page(total, int(limit)), nil
+		},
+		messagesBeforeFunc: func(before discord.MessageID, limit uint) ([]discord.Message, error) {
+			return page(int(before)-1, int(limit)), nil
+		},
+		deleteMessagesFunc: func(ids []discord.MessageID) error {
+			batches = append(batches, len(ids))
+			if len(batches) == 2 {
+				return errors.New("boom")
+			}
+			return nil
+		},
+	}
+	svc := NewService(client, &InMemoryMetrics{}, slog.Default(), WithDeepPace(0))
+	svc.now = func() time.Time { return mockClock }
+
+	outcome, err := svc.ExecuteClean(context.Background(), 1, clean.Filter{Count: total, Deep: true}, 0, "tester")
+	if err != nil {
+		t.Fatalf("ExecuteClean: %v", err)
+	}
+	if want := []int{100, 100}; !slices.Equal(batches, want) {
+		t.Fatalf("bulk batches = %v, want %v", batches, want)
+	}
+	if want := []discord.MessageID{1}; !slices.Equal(client.deletedMsgs, want) {
+		t.Fatalf("the leftover message must go through a single delete, got %v", client.deletedMsgs)
+	}
+	if outcome.Deleted != 101 || len(outcome.Messages) != 101 {
+		t.Fatalf("only the batches that went through count, got %d deleted", outcome.Deleted)
+	}
+}
+
+func TestExecuteClean_DeepStopsWhenCancelled(t *testing.T) {
+	t.Parallel()
+	mockClock := time.Now()
+	old := discord.NewTimestamp(mockClock.Add(-30 * 24 * time.Hour))
+
+	client := &mockClient{
+		messagesFunc: func(limit uint) ([]discord.Message, error) {
+			return []discord.Message{{ID: 2, Timestamp: old}, {ID: 1, Timestamp: old}}, nil
+		},
+	}
+	svc := NewService(client, &InMemoryMetrics{}, slog.Default(), WithDeepPace(time.Hour))
+	svc.now = func() time.Time { return mockClock }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	filter := clean.Filter{Count: 10, Deep: true, Progress: func(int, int) { cancel() }}
+	outcome, err := svc.ExecuteClean(ctx, 1, filter, 0, "tester")
+	if err != nil {
+		t.Fatalf("ExecuteClean: %v", err)
+	}
+	if outcome.Deleted != 1 || len(client.deletedMsgs) != 1 {
+		t.Fatalf("expected the clean to stop after one deletion, got %+v", outcome)
+	}
+}
+
+func TestExecuteClean_ChannelBusy(t *testing.T) {
+	t.Parallel()
+	mockClock := time.Now()
+	deleting := make(chan struct{})
+	release := make(chan struct{})
+
+	client := &mockClient{
+		messagesFunc: func(limit uint) ([]discord.Message, error) {
+			return []discord.Message{{ID: 1, Content: "spam", Timestamp: discord.NewTimestamp(mockClock)}}, nil
+		},
+		deleteMessagesFunc: func([]discord.MessageID) error {
+			close(deleting)
+			<-release
+			return nil
+		},
+	}
+	svc := NewService(client, &InMemoryMetrics{}, slog.Default())
+	svc.now = func() time.Time { return mockClock }
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := svc.ExecuteClean(context.Background(), 1, clean.Filter{Count: 1}, 0, "tester")
+		done <- err
+	}()
+	<-deleting
+
+	if _, err := svc.ExecuteClean(context.Background(), 1, clean.Filter{Count: 1}, 0, "tester"); !errors.Is(err, clean.ErrChannelBusy) {
+		t.Fatalf("a second clean of the channel = %v, want ErrChannelBusy", err)
+	}
+	if _, err := svc.Purge(context.Background(), 9, 1, clean.PurgePolicy{}); !errors.Is(err, clean.ErrChannelBusy) {
+		t.Fatalf("a purge of the channel being cleaned = %v, want ErrChannelBusy", err)
+	}
+	if _, err := svc.ExecuteClean(context.Background(), 1, clean.Filter{Count: 1, Preview: true}, 0, "tester"); err != nil {
+		t.Fatalf("a preview should not wait for the running clean: %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("first clean: %v", err)
+	}
+	client.deleteMessagesFunc = func([]discord.MessageID) error { return nil }
+	if _, err := svc.ExecuteClean(context.Background(), 1, clean.Filter{Count: 1}, 0, "tester"); err != nil {
+		t.Fatalf("a clean after the first finished: %v", err)
+	}
+}
+
+func TestExecuteClean_ChannelBusyAcrossServices(t *testing.T) {
+	t.Parallel()
+	mockClock := time.Now()
+	deleting := make(chan struct{})
+	release := make(chan struct{})
+
+	client := &mockClient{
+		messagesFunc: func(limit uint) ([]discord.Message, error) {
+			return []discord.Message{{ID: 1, Content: "spam", Timestamp: discord.NewTimestamp(mockClock)}}, nil
+		},
+		deleteMessagesFunc: func([]discord.MessageID) error {
+			close(deleting)
+			<-release
+			return nil
+		},
+	}
+	locks := new(keylock.Mutex[discord.ChannelID])
+	cleaner := NewService(client, &InMemoryMetrics{}, slog.Default(), WithChannelLocks(locks))
+	cleaner.now = func() time.Time { return mockClock }
+	purger := NewService(client, &InMemoryMetrics{}, slog.Default(), WithChannelLocks(locks))
+	other := NewService(client, &InMemoryMetrics{}, slog.Default())
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := cleaner.ExecuteClean(context.Background(), 1, clean.Filter{Count: 1}, 0, "tester")
+		done <- err
+	}()
+	<-deleting
+
+	if _, err := purger.Purge(context.Background(), 9, 1, clean.PurgePolicy{}); !errors.Is(err, clean.ErrChannelBusy) {
+		t.Fatalf("a purge sharing the locks of the channel being cleaned = %v, want ErrChannelBusy", err)
+	}
+	if _, err := purger.Purge(context.Background(), 9, 2, clean.PurgePolicy{}); err != nil {
+		t.Fatalf("a purge of another channel should not wait: %v", err)
+	}
+	if _, err := other.Purge(context.Background(), 9, 1, clean.PurgePolicy{}); err != nil {
+		t.Fatalf("a service with its own locks should not wait: %v", err)
 	}
 
-	svc.Close() // Gracefully waits for audit log dispatch
-	if !auditLogged.Load() {
-		t.Errorf("audit log was not dispatched")
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("clean: %v", err)
 	}
 }
@@ -0,0 +1,619 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/observability"
+	"github.com/small-frappuccino/discordcore/pkg/permissions"
+	"github.com/small-frappuccino/discordcore/pkg/system"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const (
+	commandName       = "admin"
+	tokenGroupName    = "token"
+	rotateSubcommand  = "rotate"
+	rotateModalPrefix = "admin_token_rotate|"
+	tokenInputID      = "admin_token_value"
+
+	errorsSubcommand     = "errors"
+	errorsSubsystemOpt   = "subsystem"
+	sinceOpt             = "since"
+	errorsDefaultWindow  = "24h"
+	errorsPageSize       = 10
+	errorsMessageMaxChar = 240
+
+	auditSubcommand    = "audit"
+	auditUserOpt       = "user"
+	auditCommandOpt    = "command"
+	auditDefaultWindow = "7d"
+	auditPageSize      = 10
+
+	permcheckSubcommand = "permcheck"
+	permcheckUserOpt    = "user"
+	permcheckChannelOpt = "channel"
+)
+
+// TokenStager persists a replacement bot token for validation and promotion
+// by the runtime supervisor. *files.ConfigManager satisfies it.
+type TokenStager interface {
+	StagePendingBotToken(ctx context.Context, botInstanceID, token string) error
+}
+
+// ErrorLog lists recent error log entries. *observability.ErrorJournal
+// satisfies it.
+type ErrorLog interface {
+	Recent(ctx context.Context, filter observability.ErrorRecordFilter) ([]observability.ErrorRecord, error)
+}
+
+// CommandAuditLog lists audited privileged command executions.
+// *postgres.Store satisfies it.
+type CommandAuditLog interface {
+	ListCommandAudit(ctx context.Context, filter system.CommandAuditFilter) ([]system.CommandAuditRecord, error)
+}
+
+// CommandGroup serves /admin for a single bot instance.
+type CommandGroup struct {
+	tokens    TokenStager
+	errors    ErrorLog
+	audit     CommandAuditLog
+	lockdown  LockdownStore
+	retention RetentionStore
+	lockouts  LockoutLifter
+	logger    *slog.Logger
+}
+
+// Option configures optional /admin dependencies.
+type Option func(*CommandGroup)
+
+// WithErrorLog sets the source browsed by /admin errors. Without it the
+// subcommand reports that no error log is available.
+func WithErrorLog(log ErrorLog) Option {
+	return func(g *CommandGroup) { g.errors = log }
+}
+
+// WithCommandAudit sets the audit trail browsed by /admin audit.
+func WithCommandAudit(log CommandAuditLog) Option {
+	return func(g *CommandGroup) { g.audit = log }
+}
+
+// NewCommandGroup builds the /admin command tree.
+func NewCommandGroup(tokens TokenStager, logger *slog.Logger, opts ...Option) cmd.CommandGroup {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	g := &CommandGroup{tokens: tokens, logger: logger}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// lookbackWindows are the lookback choices offered by /admin errors and
+// /admin audit.
+var lookbackWindows = map[string]time.Duration{
+	"1h":  time.Hour,
+	"6h":  6 * time.Hour,
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// Register fulfills cmd.CommandGroup.
+func (g *CommandGroup) Register(guildID string, botProfileID string) []api.CreateCommandData {
+	return []api.CreateCommandData{
+		{
+			Name:                     commandName,
+			Description:              "Bot owner operations",
+			DefaultMemberPermissions: discord.NewPermissions(discord.PermissionAdministrator),
+			Options: []discord.CommandOption{
+				&discord.SubcommandGroupOption{
+					OptionName:  tokenGroupName,
+					Description: "Manage this bot's credentials",
+					Subcommands: []*discord.SubcommandOption{
+						{
+							OptionName:  rotateSubcommand,
+							Description: "Swap in a new bot token and reconnect without a restart",
+						},
+					},
+				},
+				&discord.SubcommandOption{
+					OptionName:  errorsSubcommand,
+					Description: "Browse recent error log entries",
+					Options: []discord.CommandOptionValue{
+						&discord.StringOption{
+							OptionName:  errorsSubsystemOpt,
+							Description: "Only show errors from this subsystem",
+						},
+						lookbackOption("How far back to look (default 24h)"),
+					},
+				},
+				&discord.SubcommandOption{
+					OptionName:  auditSubcommand,
+					Description: "Browse privileged commands run in this server",
+					Options: []discord.CommandOptionValue{
+						&discord.UserOption{
+							OptionName:  auditUserOpt,
+							Description: "Only show commands run by this user",
+						},
+						&discord.StringOption{
+							OptionName:  auditCommandOpt,
+							Description: "Only show this command, e.g. ban or admin token",
+						},
+						lookbackOption("How far back to look (default 7d)"),
+					},
+				},
+				&discord.SubcommandOption{
+					OptionName:  lockdownSubcommand,
+					Description: "Emergency kill switch: pause mass commands and webhook edits",
+					Options: []discord.CommandOptionValue{
+						&discord.BooleanOption{
+							OptionName:  lockdownEnableOpt,
+							Description: "Engage (default) or lift the lockdown",
+						},
+					},
+				},
+				&discord.SubcommandOption{
+					OptionName:  permcheckSubcommand,
+					Description: "Show a member's effective permissions in a channel",
+					Options: []discord.CommandOptionValue{
+						&discord.UserOption{
+							OptionName:  permcheckUserOpt,
+							Description: "Member to check",
+							Required:    true,
+						},
+						&discord.ChannelOption{
+							OptionName:  permcheckChannelOpt,
+							Description: "Channel to check",
+							Required:    true,
+						},
+					},
+				},
+				retentionCommandOption(),
+				liftLockoutCommandOption(),
+			},
+		},
+	}
+}
+
+// Handle fulfills cmd.CommandGroup. botProfileID is the bot instance whose
+// token /admin token rotate replaces.
+func (g *CommandGroup) Handle(guildID string, botProfileID string) map[string]cmd.CommandHandler {
+	return map[string]cmd.CommandHandler{
+		commandName: func(ctx *cmd.Context) error {
+			return g.handleCommand(ctx, botProfileID)
+		},
+		rotateModalPrefix: func(ctx *cmd.Context) error {
+			return g.handleRotateModal(ctx, botProfileID)
+		},
+		lockdownLiftRoute: g.handleLockdownLift,
+	}
+}
+
+func (g *CommandGroup) handleCommand(ctx *cmd.Context, botInstanceID string) error {
+	data, ok := ctx.Event.Data.(*discord.CommandInteraction)
+	if !ok || len(data.Options) == 0 {
+		return nil
+	}
+	group := data.Options[0]
+	switch group.Name {
+	case errorsSubcommand:
+		return g.handleErrors(ctx, group.Options)
+	case auditSubcommand:
+		return g.handleAudit(ctx, group.Options)
+	case permcheckSubcommand:
+		return g.handlePermcheck(ctx, group.Options)
+	case lockdownSubcommand:
+		return g.handleLockdown(ctx, group.Options)
+	case retentionSubcommand:
+		return g.handleRetention(ctx, group.Options)
+	case liftLockoutSubcommand:
+		return g.handleLiftLockout(ctx, group.Options)
+	}
+	if group.Name != tokenGroupName || len(group.Options) == 0 || group.Options[0].Name != rotateSubcommand {
+		return respondEphemeral(ctx, "Unknown admin command.")
+	}
+
+	if allowed, err := g.authorizeOwner(ctx); !allowed {
+		return err
+	}
+	if files.NormalizeBotInstanceID(botInstanceID) == "" {
+		return respondEphemeral(ctx, "This bot runs from an environment token, which must be rotated by redeploying.")
+	}
+
+	comps := discord.ContainerComponents{
+		&discord.ActionRowComponent{
+			&discord.TextInputComponent{
+				CustomID:     discord.ComponentID(tokenInputID),
+				Label:        "New bot token",
+				Style:        discord.TextInputShortStyle,
+				Placeholder:  "Paste the token from the Developer Portal",
+				Required:     true,
+				LengthLimits: [2]int{50, 100},
+			},
+		},
+	}
+	return ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, api.InteractionResponse{
+		Type: api.ModalResponse,
+		Data: &api.InteractionResponseData{
+			CustomID:   option.NewNullableString(rotateModalPrefix + ctx.UserID.String()),
+			Title:      option.NewNullableString("Rotate bot token"),
+			Components: &comps,
+		},
+	})
+}
+
+func (g *CommandGroup) handleRotateModal(ctx *cmd.Context, botInstanceID string) error {
+	data, ok := ctx.Event.Data.(*discord.ModalInteraction)
+	if !ok {
+		return nil
+	}
+	// The modal is bound to the user who opened it.
+	if strings.TrimPrefix(string(data.CustomID), rotateModalPrefix) != ctx.UserID.String() {
+		return respondEphemeral(ctx, "Only the person who opened this form can submit it.")
+	}
+	if allowed, err := g.authorizeOwner(ctx); !allowed {
+		return err
+	}
+
+	token := modalValue(data, tokenInputID)
+	if err := g.tokens.StagePendingBotToken(ctx, botInstanceID, token); err != nil {
+		g.logger.Warn("Mitigated service degradation: Bot token rotation rejected",
+			slog.String("botInstanceID", botInstanceID),
+			slog.String("user_id", ctx.UserID.String()),
+			slog.String("error", err.Error()),
+		)
+		switch {
+		case errors.Is(err, files.ErrInvalidBotToken):
+			return respondEphemeral(ctx, "That does not look like a bot token. Nothing was changed.")
+		case errors.Is(err, files.ErrUnknownBotInstance):
+			return respondEphemeral(ctx, "This bot instance has no stored token to replace.")
+		default:
+			return respondEphemeral(ctx, "Failed to stage the new token. Nothing was changed.")
+		}
+	}
+
+	g.logger.Info("Architectural state transition: Bot token rotation staged",
+		slog.String("botInstanceID", botInstanceID),
+		slog.String("user_id", ctx.UserID.String()),
+	)
+	return respondEphemeral(ctx, "New token staged. It will be validated and the bot will reconnect with it shortly; the current token stays active if validation fails.")
+}
+
+func (g *CommandGroup) handleErrors(ctx *cmd.Context, opts []discord.CommandInteractionOption) error {
+	if allowed, err := g.authorizeOwner(ctx); !allowed {
+		return err
+	}
+	if g.errors == nil {
+		return respondEphemeral(ctx, "No error log is available in this process.")
+	}
+
+	window := errorsDefaultWindow
+	var subsystem string
+	for _, opt := range opts {
+		switch opt.Name {
+		case errorsSubsystemOpt:
+			subsystem = strings.TrimSpace(opt.String())
+		case sinceOpt:
+			if _, ok := lookbackWindows[opt.String()]; ok {
+				window = opt.String()
+			}
+		}
+	}
+
+	filter := observability.ErrorRecordFilter{
+		Subsystem: subsystem,
+		Since:     time.Now().Add(-lookbackWindows[window]),
+		Limit:     errorsPageSize,
+	}
+	records, err := g.errors.Recent(ctx, filter)
+	if err != nil {
+		// A failed archive lookup still leaves the in-memory entries.
+		g.logger.Warn("Mitigated service degradation: Error log archive lookup failed",
+			slog.String("error", err.Error()),
+		)
+	}
+	return respondEphemeralEmbed(ctx, buildErrorsEmbed(records, subsystem, window))
+}
+
+func (g *CommandGroup) handleAudit(ctx *cmd.Context, opts []discord.CommandInteractionOption) error {
+	if allowed, err := g.authorizeOwner(ctx); !allowed {
+		return err
+	}
+	if g.audit == nil {
+		return respondEphemeral(ctx, "The command audit trail needs a database and is not available.")
+	}
+
+	window := auditDefaultWindow
+	auditFilter := system.CommandAuditFilter{Limit: auditPageSize}
+	if ctx.GuildID.IsValid() {
+		auditFilter.GuildID = ctx.GuildID.String()
+	}
+	for _, opt := range opts {
+		switch opt.Name {
+		case auditUserOpt:
+			if id, err := opt.SnowflakeValue(); err == nil && id.IsValid() {
+				auditFilter.UserID = id.String()
+			}
+		case auditCommandOpt:
+			auditFilter.Command = strings.TrimPrefix(strings.TrimSpace(opt.String()), "/")
+		case sinceOpt:
+			if _, ok := lookbackWindows[opt.String()]; ok {
+				window = opt.String()
+			}
+		}
+	}
+	auditFilter.Since = time.Now().Add(-lookbackWindows[window])
+
+	records, err := g.audit.ListCommandAudit(ctx, auditFilter)
+	if err != nil {
+		g.logger.Warn("Mitigated service degradation: Command audit lookup failed",
+			slog.String("error", err.Error()),
+		)
+		return respondEphemeral(ctx, "Failed to read the command audit trail. Try again later.")
+	}
+	return respondEphemeralEmbed(ctx, buildAuditEmbed(records, window))
+}
+
+func buildAuditEmbed(records []system.CommandAuditRecord, window string) discord.Embed {
+	embed := discord.Embed{
+		Title: "Command audit",
+		Color: discord.Color(theme.Info()),
+		Footer: &discord.EmbedFooter{
+			Text: fmt.Sprintf("Last %s · newest first", window),
+		},
+	}
+	if len(records) == 0 {
+		embed.Description = fmt.Sprintf("No privileged commands matched in the last %s.", window)
+		return embed
+	}
+
+	var b strings.Builder
+	for _, rec := range records {
+		line := fmt.Sprintf("%s <@%s> `/%s`", logging.DiscordTimestamp(rec.At, logging.TimestampShortDateTime), rec.UserID, rec.Command)
+		if rec.Options != "" && rec.Options != "{}" {
+			line += " " + truncate(rec.Options, errorsMessageMaxChar)
+		}
+		if rec.Outcome != "ok" {
+			line += "\n-# " + rec.Outcome
+			if rec.Error != "" {
+				line += ": " + truncate(rec.Error, errorsMessageMaxChar)
+			}
+		}
+		if b.Len()+len(line)+1 > 4000 {
+			break
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(line)
+	}
+	embed.Description = b.String()
+	return embed
+}
+
+func (g *CommandGroup) handlePermcheck(ctx *cmd.Context, opts []discord.CommandInteractionOption) error {
+	if allowed, err := g.authorizeOwner(ctx); !allowed {
+		return err
+	}
+	if !ctx.GuildID.IsValid() {
+		return respondEphemeral(ctx, "Run this command inside a server.")
+	}
+
+	var userID discord.UserID
+	var channelID discord.ChannelID
+	for _, opt := range opts {
+		id, err := opt.SnowflakeValue()
+		if err != nil {
+			continue
+		}
+		switch opt.Name {
+		case permcheckUserOpt:
+			userID = discord.UserID(id)
+		case permcheckChannelOpt:
+			channelID = discord.ChannelID(id)
+		}
+	}
+	if !userID.IsValid() || !channelID.IsValid() {
+		return respondEphemeral(ctx, "Both a member and a channel are required.")
+	}
+
+	report, err := resolvePermissionReport(ctx.Client, ctx.GuildID, userID, channelID)
+	if err != nil {
+		g.logger.Warn("Mitigated service degradation: Permission check lookup failed",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("user_id", userID.String()),
+			slog.String("channel_id", channelID.String()),
+			slog.String("error", err.Error()),
+		)
+		return respondEphemeral(ctx, "Could not load that member or channel. Check that both belong to this server.")
+	}
+	return respondEphemeralEmbed(ctx, buildPermcheckEmbed(report))
+}
+
+// permissionReport is the outcome of /admin permcheck.
+type permissionReport struct {
+	permissions.Resolution
+	UserID    discord.UserID
+	ChannelID discord.ChannelID
+}
+
+func resolvePermissionReport(src permissions.Source, guildID discord.GuildID, userID discord.UserID, channelID discord.ChannelID) (permissionReport, error) {
+	res, err := permissions.ResolveInChannel(src, guildID, userID, channelID)
+	if err != nil {
+		return permissionReport{}, err
+	}
+	return permissionReport{Resolution: res, UserID: userID, ChannelID: channelID}, nil
+}
+
+func buildPermcheckEmbed(report permissionReport) discord.Embed {
+	embed := discord.Embed{
+		Title:       "Effective permissions",
+		Description: fmt.Sprintf("<@%s> in <#%s>", report.UserID, report.ChannelID),
+		Color:       discord.Color(theme.Info()),
+	}
+	switch {
+	case report.Owner:
+		embed.Description += "\nServer owner: every permission is granted and overwrites do not apply."
+		return embed
+	case report.Base&permissions.Administrator != 0:
+		embed.Description += "\nAdministrator: every permission is granted and overwrites do not apply."
+		return embed
+	}
+
+	embed.Fields = append(embed.Fields, discord.EmbedField{
+		Name:  "Granted",
+		Value: permissionList(report.Effective),
+	})
+	if removed := report.Base &^ report.Effective; removed != 0 {
+		embed.Fields = append(embed.Fields, discord.EmbedField{Name: "Denied by channel overwrites", Value: permissionList(removed)})
+	}
+	if added := report.Effective &^ report.Base; added != 0 {
+		embed.Fields = append(embed.Fields, discord.EmbedField{Name: "Allowed by channel overwrites", Value: permissionList(added)})
+	}
+	return embed
+}
+
+func permissionList(perms int64) string {
+	names := permissions.Names(perms)
+	if len(names) == 0 {
+		return "None"
+	}
+	return truncate(strings.Join(names, ", "), 1024)
+}
+
+func lookbackOption(description string) *discord.StringOption {
+	return &discord.StringOption{
+		OptionName:  sinceOpt,
+		Description: description,
+		Choices: []discord.StringChoice{
+			{Name: "Last hour", Value: "1h"},
+			{Name: "Last 6 hours", Value: "6h"},
+			{Name: "Last 24 hours", Value: "24h"},
+			{Name: "Last 7 days", Value: "7d"},
+			{Name: "Last 30 days", Value: "30d"},
+		},
+	}
+}
+
+func buildErrorsEmbed(records []observability.ErrorRecord, subsystem, window string) discord.Embed {
+	scope := "all subsystems"
+	if subsystem != "" {
+		scope = "`" + subsystem + "`"
+	}
+	embed := discord.Embed{
+		Title: "Recent errors",
+		Color: discord.Color(theme.Error()),
+		Footer: &discord.EmbedFooter{
+			Text: fmt.Sprintf("Last %s · newest first", window),
+		},
+	}
+	if len(records) == 0 {
+		embed.Color = discord.Color(theme.Success())
+		embed.Description = fmt.Sprintf("No errors recorded for %s in the last %s.", scope, window)
+		return embed
+	}
+
+	var b strings.Builder
+	for _, rec := range records {
+		line := fmt.Sprintf("%s `%s` %s", logging.DiscordTimestamp(rec.At, logging.TimestampShortDateTime), rec.Subsystem, truncate(rec.Message, errorsMessageMaxChar))
+		if rec.Detail != "" {
+			line += "\n-# " + truncate(rec.Detail, errorsMessageMaxChar)
+		}
+		if b.Len()+len(line)+1 > 4000 {
+			break
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(line)
+	}
+	embed.Description = b.String()
+	return embed
+}
+
+func truncate(s string, limit int) string {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "`", "'")
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit-1]) + "…"
+}
+
+// authorizeOwner replies with a denial and reports false unless the invoking
+// user owns the Discord application.
+func (g *CommandGroup) authorizeOwner(ctx *cmd.Context) (bool, error) {
+	app, err := ctx.Client.CurrentApplication()
+	if err != nil {
+		g.logger.Warn("Mitigated service degradation: Could not resolve application owner",
+			slog.String("error", err.Error()),
+		)
+		return false, respondEphemeral(ctx, "Could not verify bot ownership. Try again later.")
+	}
+	if !isApplicationOwner(app, ctx.UserID) {
+		return false, respondEphemeral(ctx, "Only the bot owner can use this command.")
+	}
+	return true, nil
+}
+
+// isApplicationOwner reports whether userID owns app, either directly or as
+// owner of the team that holds it.
+func isApplicationOwner(app *discord.Application, userID discord.UserID) bool {
+	if app == nil || !userID.IsValid() {
+		return false
+	}
+	if app.Team != nil {
+		return app.Team.OwnerID == userID
+	}
+	return app.Owner != nil && app.Owner.ID == userID
+}
+
+func modalValue(data *discord.ModalInteraction, customID string) string {
+	if data == nil {
+		return ""
+	}
+	if input, ok := data.Components.Find(discord.ComponentID(customID)).(*discord.TextInputComponent); ok {
+		return input.Value
+	}
+	return ""
+}
+
+func respondEphemeralEmbed(ctx *cmd.Context, embed discord.Embed) error {
+	err := ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, api.InteractionResponse{
+		Type: api.MessageInteractionWithSource,
+		Data: &api.InteractionResponseData{
+			Embeds: &[]discord.Embed{embed},
+			Flags:  discord.EphemeralMessage,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("respond admin interaction: %w", err)
+	}
+	return nil
+}
+
+func respondEphemeral(ctx *cmd.Context, content string) error {
+	err := ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, api.InteractionResponse{
+		Type: api.MessageInteractionWithSource,
+		Data: &api.InteractionResponseData{
+			Content: option.NewNullableString(content),
+			Flags:   discord.EphemeralMessage,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("respond admin interaction: %w", err)
+	}
+	return nil
+}
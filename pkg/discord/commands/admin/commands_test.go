@@ -0,0 +1,189 @@
+package admin
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/observability"
+	"github.com/small-frappuccino/discordcore/pkg/system"
+)
+
+func TestIsApplicationOwner(t *testing.T) {
+	t.Parallel()
+
+	owner := discord.UserID(100)
+	teamOwner := discord.UserID(200)
+
+	solo := &discord.Application{Owner: &discord.User{ID: owner}}
+	if !isApplicationOwner(solo, owner) {
+		t.Fatal("expected application owner to be allowed")
+	}
+	if isApplicationOwner(solo, teamOwner) {
+		t.Fatal("expected other users to be rejected")
+	}
+
+	team := &discord.Application{
+		Owner: &discord.User{ID: owner},
+		Team:  &discord.Team{OwnerID: teamOwner},
+	}
+	if !isApplicationOwner(team, teamOwner) {
+		t.Fatal("expected team owner to be allowed")
+	}
+	if isApplicationOwner(team, owner) {
+		t.Fatal("expected team-held application to ignore the placeholder owner")
+	}
+	if isApplicationOwner(nil, owner) || isApplicationOwner(solo, 0) {
+		t.Fatal("expected missing application or user to be rejected")
+	}
+}
+
+func TestModalValue(t *testing.T) {
+	t.Parallel()
+
+	data := &discord.ModalInteraction{
+		CustomID: discord.ComponentID(rotateModalPrefix + "100"),
+		Components: discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.TextInputComponent{CustomID: tokenInputID, Value: "a.b.c"},
+			},
+		},
+	}
+	if got := modalValue(data, tokenInputID); got != "a.b.c" {
+		t.Fatalf("expected submitted token, got %q", got)
+	}
+	if got := modalValue(data, "missing"); got != "" {
+		t.Fatalf("expected empty value for unknown input, got %q", got)
+	}
+}
+
+func TestRegisterDeclaresRotateSubcommand(t *testing.T) {
+	t.Parallel()
+
+	cmds := NewCommandGroup(nil, nil).Register("g1", "main")
+	if len(cmds) != 1 || cmds[0].Name != commandName {
+		t.Fatalf("expected a single /admin command, got %+v", cmds)
+	}
+	group, ok := cmds[0].Options[0].(*discord.SubcommandGroupOption)
+	if !ok || group.OptionName != tokenGroupName || len(group.Subcommands) != 1 || group.Subcommands[0].OptionName != rotateSubcommand {
+		t.Fatalf("expected /admin token rotate, got %+v", cmds[0].Options)
+	}
+}
+
+func TestBuildErrorsEmbed(t *testing.T) {
+	t.Parallel()
+
+	empty := buildErrorsEmbed(nil, "qotd", "1h")
+	if !strings.Contains(empty.Description, "No errors recorded for `qotd` in the last 1h") {
+		t.Fatalf("unexpected empty description %q", empty.Description)
+	}
+
+	at := time.Unix(1_700_000_000, 0)
+	embed := buildErrorsEmbed([]observability.ErrorRecord{
+		{At: at, Subsystem: "database", Message: "insert `messages` failed", Detail: "guild_id=1"},
+	}, "", "24h")
+	want := "<t:1700000000:f> `database` insert 'messages' failed\n-# guild_id=1"
+	if embed.Description != want {
+		t.Fatalf("description = %q, want %q", embed.Description, want)
+	}
+}
+
+func TestBuildAuditEmbed(t *testing.T) {
+	t.Parallel()
+
+	embed := buildAuditEmbed([]system.CommandAuditRecord{
+		{At: time.Unix(1_700_000_000, 0), UserID: "42", Command: "ban", Options: `{"user":"7"}`, Outcome: "error", Error: "missing permissions"},
+	}, "7d")
+	want := "<t:1700000000:f> <@42> `/ban` {\"user\":\"7\"}\n-# error: missing permissions"
+	if embed.Description != want {
+		t.Fatalf("description = %q, want %q", embed.Description, want)
+	}
+}
+
+type fakePermissionSource struct {
+	guild    discord.Guild
+	member   discord.Member
+	channels map[discord.ChannelID]discord.Channel
+}
+
+func (f fakePermissionSource) Guild(discord.GuildID) (*discord.Guild, error) { return &f.guild, nil }
+
+func (f fakePermissionSource) Member(discord.GuildID, discord.UserID) (*discord.Member, error) {
+	return &f.member, nil
+}
+
+func (f fakePermissionSource) Channel(id discord.ChannelID) (*discord.Channel, error) {
+	ch := f.channels[id]
+	return &ch, nil
+}
+
+func TestResolvePermissionReportUsesThreadParentOverwrites(t *testing.T) {
+	t.Parallel()
+
+	const guildID, userID, modRole = discord.GuildID(1), discord.UserID(2), discord.RoleID(3)
+	src := fakePermissionSource{
+		guild: discord.Guild{
+			ID:      guildID,
+			OwnerID: 99,
+			Roles: []discord.Role{
+				{ID: discord.RoleID(guildID), Permissions: discord.PermissionViewChannel | discord.PermissionSendMessages},
+				{ID: modRole, Permissions: discord.PermissionManageMessages},
+			},
+		},
+		member: discord.Member{User: discord.User{ID: userID}, RoleIDs: []discord.RoleID{modRole}},
+		channels: map[discord.ChannelID]discord.Channel{
+			10: {ID: 10, GuildID: guildID, Type: discord.GuildText, Overwrites: []discord.Overwrite{
+				{ID: discord.Snowflake(modRole), Type: discord.OverwriteRole, Deny: discord.PermissionManageMessages},
+			}},
+			11: {ID: 11, GuildID: guildID, Type: discord.GuildPublicThread, ParentID: 10},
+		},
+	}
+
+	report, err := resolvePermissionReport(src, guildID, userID, 11)
+	if err != nil {
+		t.Fatalf("resolvePermissionReport: %v", err)
+	}
+	if report.Effective&int64(discord.PermissionManageMessages) != 0 {
+		t.Fatalf("expected parent overwrite to deny Manage Messages, got %b", report.Effective)
+	}
+
+	embed := buildPermcheckEmbed(report)
+	if len(embed.Fields) != 2 || embed.Fields[1].Value != "Manage Messages" {
+		t.Fatalf("expected denied field listing Manage Messages, got %+v", embed.Fields)
+	}
+}
+
+func TestBuildLockdownEmbedListsChecklist(t *testing.T) {
+	t.Parallel()
+
+	embed := buildLockdownEmbed(false)
+	if embed.Title != "Bot lockdown engaged" || len(embed.Fields) != 1 {
+		t.Fatalf("unexpected lockdown embed: %+v", embed)
+	}
+	for i, step := range lockdownChecklist {
+		if !strings.Contains(embed.Fields[0].Value, step) {
+			t.Fatalf("checklist step %d missing from %q", i+1, embed.Fields[0].Value)
+		}
+	}
+	if got := buildLockdownEmbed(true).Title; got != "Bot lockdown is already engaged" {
+		t.Fatalf("unexpected title for repeated lockdown: %q", got)
+	}
+	if _, ok := NewCommandGroup(nil, nil).Handle("g1", "main")[lockdownLiftRoute]; !ok {
+		t.Fatal("expected the lift button route to be handled")
+	}
+}
+
+func TestBuildRetentionEmbed(t *testing.T) {
+	t.Parallel()
+
+	var policy files.RetentionConfig
+	policy.SetDays(files.RetentionLogEvents, 30)
+	embed := buildRetentionEmbed(policy)
+	for _, want := range []string{"**Message cache**: 7 days (default)", "**Avatar history**: 90 days (default)", "**Log archive**: 30 days\n"} {
+		if !strings.Contains(embed.Description+"\n", want) {
+			t.Fatalf("expected %q in %q", want, embed.Description)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+/*
+Package admin implements owner-only operational slash commands.
+
+The commands act on the bot process itself rather than on guild settings, so
+every invocation is gated on the Discord application owner instead of guild
+permissions. Secrets such as replacement bot tokens are collected through
+ephemeral modals and never echoed back into the channel.
+*/
+package admin
@@ -0,0 +1,144 @@
+package admin
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const (
+	lockdownSubcommand = "lockdown-bot"
+	lockdownEnableOpt  = "enable"
+	lockdownLiftRoute  = "admin_lockdown_lift|"
+)
+
+// LockdownStore reads and persists the emergency lockdown flag.
+// *files.ConfigManager satisfies it.
+type LockdownStore interface {
+	Config() *files.BotConfig
+	UpdateRuntimeConfig(fn func(*files.RuntimeConfig) error) (files.RuntimeConfig, error)
+}
+
+// WithLockdown enables /admin lockdown-bot. Without it the subcommand reports
+// that the kill switch is unavailable.
+func WithLockdown(store LockdownStore) Option {
+	return func(g *CommandGroup) { g.lockdown = store }
+}
+
+// lockdownChecklist lists the response steps posted when the lockdown is
+// engaged. The lockdown itself changes no credentials.
+var lockdownChecklist = []string{
+	"Rotate the bot token in the Developer Portal, then run `/admin token rotate`.",
+	"Review `/admin audit` for privileged commands you do not recognize.",
+	"Check the server audit log for bans, kicks and role changes made through the bot.",
+	"Check the application's team members and OAuth2 redirects in the Developer Portal.",
+	"Review server webhooks and delete any you did not create.",
+	"Lift the lockdown once the account is secured.",
+}
+
+func (g *CommandGroup) handleLockdown(ctx *cmd.Context, opts []discord.CommandInteractionOption) error {
+	if allowed, err := g.authorizeOwner(ctx); !allowed {
+		return err
+	}
+	if g.lockdown == nil {
+		return respondEphemeral(ctx, "The lockdown switch is not available in this process.")
+	}
+
+	enable := true
+	for _, opt := range opts {
+		if opt.Name == lockdownEnableOpt {
+			if v, err := opt.BoolValue(); err == nil {
+				enable = v
+			}
+		}
+	}
+	return g.setLockdown(ctx, enable)
+}
+
+// handleLockdownLift serves the lift button under the lockdown checklist.
+func (g *CommandGroup) handleLockdownLift(ctx *cmd.Context) error {
+	if allowed, err := g.authorizeOwner(ctx); !allowed {
+		return err
+	}
+	if g.lockdown == nil {
+		return respondEphemeral(ctx, "The lockdown switch is not available in this process.")
+	}
+	return g.setLockdown(ctx, false)
+}
+
+func (g *CommandGroup) setLockdown(ctx *cmd.Context, engaged bool) error {
+	wasEngaged := g.lockdown.Config().LockdownActive()
+	if wasEngaged != engaged {
+		if _, err := g.lockdown.UpdateRuntimeConfig(func(rc *files.RuntimeConfig) error {
+			rc.BotLockdown = engaged
+			return nil
+		}); err != nil {
+			g.logger.Error("Blocking structural failure: Bot lockdown state could not be persisted",
+				slog.Bool("engaged", engaged),
+				slog.String("user_id", ctx.UserID.String()),
+				slog.String("error", err.Error()),
+			)
+			return respondEphemeral(ctx, "Failed to save the lockdown state. Nothing was changed.")
+		}
+		g.logger.Warn("Architectural state transition: Bot lockdown toggled",
+			slog.Bool("engaged", engaged),
+			slog.String("user_id", ctx.UserID.String()),
+			slog.String("guild_id", ctx.GuildID.String()),
+		)
+	}
+
+	if !engaged {
+		return respondEphemeral(ctx, "Lockdown lifted. Mass commands, auto-purge and webhooks are enabled again.")
+	}
+	return respondLockdownChecklist(ctx, wasEngaged)
+}
+
+func respondLockdownChecklist(ctx *cmd.Context, alreadyEngaged bool) error {
+	embed := buildLockdownEmbed(alreadyEngaged)
+	components := discord.ContainerComponents{
+		&discord.ActionRowComponent{
+			&discord.ButtonComponent{
+				Label:    "Lift lockdown",
+				CustomID: lockdownLiftRoute,
+				Style:    discord.DangerButtonStyle(),
+			},
+		},
+	}
+	err := ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, api.InteractionResponse{
+		Type: api.MessageInteractionWithSource,
+		Data: &api.InteractionResponseData{
+			Embeds:     &[]discord.Embed{embed},
+			Components: &components,
+			Flags:      discord.EphemeralMessage,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("respond admin interaction: %w", err)
+	}
+	return nil
+}
+
+func buildLockdownEmbed(alreadyEngaged bool) discord.Embed {
+	title := "Bot lockdown engaged"
+	if alreadyEngaged {
+		title = "Bot lockdown is already engaged"
+	}
+	var steps strings.Builder
+	for i, step := range lockdownChecklist {
+		fmt.Fprintf(&steps, "%d. %s\n", i+1, step)
+	}
+	return discord.Embed{
+		Title:       title,
+		Description: "Mass moderation commands and auto-purge are disabled, and webhook edits and webhook log delivery are paused on every server. Logs are sent as the bot meanwhile. No credentials were changed.",
+		Color:       discord.Color(theme.Danger()),
+		Fields: []discord.EmbedField{
+			{Name: "Response checklist", Value: steps.String()},
+		},
+	}
+}
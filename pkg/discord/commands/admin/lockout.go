@@ -0,0 +1,75 @@
+package admin
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+)
+
+const (
+	liftLockoutSubcommand = "lift-lockout"
+	liftLockoutUserOpt    = "moderator"
+)
+
+// LockoutLifter ends a moderator's destructive action lockout early.
+// *moderation.Service of pkg/discord/moderation satisfies it.
+type LockoutLifter interface {
+	LiftLockout(guildID discord.GuildID, moderatorID discord.UserID) bool
+}
+
+// WithLockoutLifter enables /admin lift-lockout. Without it the subcommand
+// reports that lockouts cannot be lifted.
+func WithLockoutLifter(lifter LockoutLifter) Option {
+	return func(g *CommandGroup) { g.lockouts = lifter }
+}
+
+func liftLockoutCommandOption() *discord.SubcommandOption {
+	return &discord.SubcommandOption{
+		OptionName:  liftLockoutSubcommand,
+		Description: "Let a moderator locked out by the ban and kick limit act again",
+		Options: []discord.CommandOptionValue{
+			&discord.UserOption{
+				OptionName:  liftLockoutUserOpt,
+				Description: "Moderator to let act again",
+				Required:    true,
+			},
+		},
+	}
+}
+
+func (g *CommandGroup) handleLiftLockout(ctx *cmd.Context, opts []discord.CommandInteractionOption) error {
+	if allowed, err := g.authorizeOwner(ctx); !allowed {
+		return err
+	}
+	if g.lockouts == nil {
+		return respondEphemeral(ctx, "Moderator lockouts cannot be lifted in this process.")
+	}
+	if !ctx.GuildID.IsValid() {
+		return respondEphemeral(ctx, "Run this command inside a server.")
+	}
+
+	var moderatorID discord.UserID
+	for _, opt := range opts {
+		if opt.Name != liftLockoutUserOpt {
+			continue
+		}
+		if id, err := opt.SnowflakeValue(); err == nil {
+			moderatorID = discord.UserID(id)
+		}
+	}
+	if !moderatorID.IsValid() {
+		return respondEphemeral(ctx, "A moderator is required.")
+	}
+
+	if !g.lockouts.LiftLockout(ctx.GuildID, moderatorID) {
+		return respondEphemeral(ctx, fmt.Sprintf("<@%s> is not locked out on this server.", moderatorID))
+	}
+	g.logger.Warn("Architectural state transition: Moderator lockout lifted by the bot owner",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("moderator_id", moderatorID.String()),
+		slog.String("user_id", ctx.UserID.String()),
+	)
+	return respondEphemeral(ctx, fmt.Sprintf("Lifted the lockout of <@%s>. Their recent bans and kicks no longer count toward the limit.", moderatorID))
+}
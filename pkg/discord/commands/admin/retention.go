@@ -0,0 +1,147 @@
+package admin
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const (
+	retentionSubcommand = "retention"
+	retentionDataOpt    = "data"
+	retentionDaysOpt    = "days"
+)
+
+// RetentionStore reads and persists the retention policy of a guild.
+// *files.ConfigManager satisfies it.
+type RetentionStore interface {
+	GuildConfig(guildID string) *files.GuildConfig
+	UpdateGuildConfig(guildID string, fn func(*files.GuildConfig) error) error
+}
+
+// WithRetention enables /admin retention. Without it the subcommand reports
+// that the policy cannot be changed.
+func WithRetention(store RetentionStore) Option {
+	return func(g *CommandGroup) { g.retention = store }
+}
+
+// retentionLabels names each data set in the command and its embed.
+var retentionLabels = map[files.RetentionData]string{
+	files.RetentionMessageCache:  "Message cache",
+	files.RetentionAvatarHistory: "Avatar history",
+	files.RetentionLogEvents:     "Log archive",
+}
+
+func retentionCommandOption() *discord.SubcommandOption {
+	choices := make([]discord.StringChoice, 0, len(retentionLabels))
+	for _, data := range files.RetentionDataSets() {
+		choices = append(choices, discord.StringChoice{Name: retentionLabels[data], Value: string(data)})
+	}
+	return &discord.SubcommandOption{
+		OptionName:  retentionSubcommand,
+		Description: "Show or change how long this server's data is kept",
+		Options: []discord.CommandOptionValue{
+			&discord.StringOption{
+				OptionName:  retentionDataOpt,
+				Description: "Data to change",
+				Choices:     choices,
+			},
+			&discord.IntegerOption{
+				OptionName:  retentionDaysOpt,
+				Description: "Days to keep it, 0 for the default",
+				Min:         option.NewInt(0),
+				Max:         option.NewInt(files.MaxRetentionDays),
+			},
+		},
+	}
+}
+
+func (g *CommandGroup) handleRetention(ctx *cmd.Context, opts []discord.CommandInteractionOption) error {
+	if allowed, err := g.authorizeOwner(ctx); !allowed {
+		return err
+	}
+	if g.retention == nil {
+		return respondEphemeral(ctx, "The retention policy is not available in this process.")
+	}
+	if !ctx.GuildID.IsValid() {
+		return respondEphemeral(ctx, "Run this command inside a server.")
+	}
+	guildID := ctx.GuildID.String()
+
+	var data files.RetentionData
+	days := -1
+	for _, opt := range opts {
+		switch opt.Name {
+		case retentionDataOpt:
+			data = files.RetentionData(opt.String())
+		case retentionDaysOpt:
+			if v, err := opt.IntValue(); err == nil {
+				days = int(v)
+			}
+		}
+	}
+
+	if data == "" && days < 0 {
+		var policy files.RetentionConfig
+		if guild := g.retention.GuildConfig(guildID); guild != nil {
+			policy = guild.Retention
+		}
+		return respondEphemeralEmbed(ctx, buildRetentionEmbed(policy))
+	}
+	if _, ok := retentionLabels[data]; !ok || days < 0 || days > files.MaxRetentionDays {
+		return respondEphemeral(ctx, fmt.Sprintf("Pick the data to change and keep it between 1 and %d days, or 0 for the default.", files.MaxRetentionDays))
+	}
+
+	var policy files.RetentionConfig
+	if err := g.retention.UpdateGuildConfig(guildID, func(gc *files.GuildConfig) error {
+		gc.Retention.SetDays(data, days)
+		policy = gc.Retention
+		return nil
+	}); err != nil {
+		g.logger.Error("Blocking structural failure: Retention policy could not be persisted",
+			slog.String("guild_id", guildID),
+			slog.String("data", string(data)),
+			slog.String("error", err.Error()),
+		)
+		return respondEphemeral(ctx, "Failed to save the retention policy. Nothing was changed.")
+	}
+	g.logger.Info("Architectural state transition: Retention policy changed",
+		slog.String("guild_id", guildID),
+		slog.String("data", string(data)),
+		slog.Int("days", policy.Days(data)),
+		slog.String("user_id", ctx.UserID.String()),
+	)
+	return respondEphemeralEmbed(ctx, buildRetentionEmbed(policy))
+}
+
+func buildRetentionEmbed(policy files.RetentionConfig) discord.Embed {
+	var b strings.Builder
+	for _, data := range files.RetentionDataSets() {
+		fmt.Fprintf(&b, "**%s**: %s", retentionLabels[data], retentionDays(policy.Days(data)))
+		if !policy.Overrides(data) {
+			b.WriteString(" (default)")
+		}
+		b.WriteByte('\n')
+	}
+	return discord.Embed{
+		Title:       "Data retention",
+		Description: strings.TrimSuffix(b.String(), "\n"),
+		Color:       discord.Color(theme.Info()),
+		Footer: &discord.EmbedFooter{
+			Text: "Older data is pruned nightly",
+		},
+	}
+}
+
+func retentionDays(days int) string {
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
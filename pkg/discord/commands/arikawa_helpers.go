@@ -28,6 +28,19 @@ func (l ArikawaOptionList) ChannelID(name string) string {
 	return ""
 }
 
+// UserID gets a user ID option.
+func (l ArikawaOptionList) UserID(name string) string {
+	for _, opt := range l {
+		if opt.Name == name {
+			uID, _ := opt.SnowflakeValue()
+			if uID != 0 {
+				return uID.String()
+			}
+		}
+	}
+	return ""
+}
+
 // RoleID gets a role ID option.
 func (l ArikawaOptionList) RoleID(name string) string {
 	for _, opt := range l {
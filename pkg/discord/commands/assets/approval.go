@@ -0,0 +1,270 @@
+package assets
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/permissions"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const (
+	// approvalRoute prefixes the custom IDs of the buttons under an approval
+	// request. The rest is the decision and, past a second "|", the sticker.
+	approvalRoute = "assets:approval|"
+
+	decisionApprove = "approve"
+	decisionReject  = "reject"
+
+	// stickerFormatGIF is missing from arikawa's sticker formats.
+	stickerFormatGIF discord.StickerFormatType = 4
+)
+
+// Client is the part of *api.Client new stickers are submitted through.
+type Client interface {
+	SendMessageComplex(channelID discord.ChannelID, data api.SendMessageData) (*discord.Message, error)
+}
+
+// stickerClient is the part of *api.Client a decision acts through. Arikawa
+// has no sticker endpoints, so they are requested directly.
+type stickerClient interface {
+	RequestJSON(to interface{}, method, url string, opts ...httputil.RequestOption) error
+	FastRequest(method, url string, opts ...httputil.RequestOption) error
+}
+
+// SubmitSticker holds a newly uploaded sticker for staff approval or, when
+// cfg does not require it, announces it right away. sticker.User is the
+// uploader.
+func SubmitSticker(client Client, cfg files.AssetsConfig, sticker discord.Sticker) error {
+	if cfg.RequireApproval {
+		channelID, err := discord.ParseSnowflake(cfg.ApprovalChannelID)
+		if err != nil {
+			return fmt.Errorf("SubmitSticker: approval channel: %w", err)
+		}
+		if _, err := client.SendMessageComplex(discord.ChannelID(channelID), approvalRequest(sticker)); err != nil {
+			return fmt.Errorf("SubmitSticker: post approval request: %w", err)
+		}
+		return nil
+	}
+	return announceSticker(client, cfg, sticker)
+}
+
+func announceSticker(client Client, cfg files.AssetsConfig, sticker discord.Sticker) error {
+	if strings.TrimSpace(cfg.AnnounceChannelID) == "" {
+		return nil
+	}
+	channelID, err := discord.ParseSnowflake(cfg.AnnounceChannelID)
+	if err != nil {
+		return fmt.Errorf("announceSticker: announce channel: %w", err)
+	}
+	embed := stickerEmbed(sticker)
+	embed.Title = "New sticker: " + sticker.Name
+	embed.Color = discord.Color(theme.Success())
+	if _, err := client.SendMessageComplex(discord.ChannelID(channelID), api.SendMessageData{
+		Embeds: []discord.Embed{embed},
+	}); err != nil {
+		return fmt.Errorf("announceSticker: %w", err)
+	}
+	return nil
+}
+
+// StickerImageURL returns where the sticker's image is served, or "" for
+// Lottie stickers, which Discord cannot render as an image.
+func StickerImageURL(sticker discord.Sticker) string {
+	switch sticker.FormatType {
+	case discord.StickerFormatPNG, discord.StickerFormatAPNG:
+		return sticker.StickerURLWithType(discord.PNGImage)
+	case stickerFormatGIF:
+		return sticker.StickerURLWithType(discord.GIFImage)
+	default:
+		return ""
+	}
+}
+
+// stickerEmbed describes the sticker, its uploader and its image.
+func stickerEmbed(sticker discord.Sticker) discord.Embed {
+	embed := discord.Embed{
+		Description: sticker.Description,
+		Footer:      &discord.EmbedFooter{Text: "Sticker ID: " + sticker.ID.String()},
+	}
+	if url := StickerImageURL(sticker); url != "" {
+		embed.Image = &discord.EmbedImage{URL: url}
+	}
+	if sticker.User != nil && sticker.User.ID.IsValid() {
+		embed.Fields = append(embed.Fields, discord.EmbedField{Name: "Uploaded by", Value: sticker.User.ID.Mention(), Inline: true})
+	}
+	if sticker.Tags != "" {
+		embed.Fields = append(embed.Fields, discord.EmbedField{Name: "Tags", Value: sticker.Tags, Inline: true})
+	}
+	return embed
+}
+
+func approvalRequest(sticker discord.Sticker) api.SendMessageData {
+	embed := stickerEmbed(sticker)
+	embed.Title = "Sticker awaiting approval: " + sticker.Name
+	embed.Color = discord.Color(theme.Warning())
+	return api.SendMessageData{
+		Embeds: []discord.Embed{embed},
+		Components: discord.ContainerComponents{
+			&discord.ActionRowComponent{
+				&discord.ButtonComponent{
+					Label:    "Approve",
+					CustomID: discord.ComponentID(approvalRoute + decisionApprove + "|" + sticker.ID.String()),
+					Style:    discord.SuccessButtonStyle(),
+				},
+				&discord.ButtonComponent{
+					Label:    "Reject",
+					CustomID: discord.ComponentID(approvalRoute + decisionReject + "|" + sticker.ID.String()),
+					Style:    discord.DangerButtonStyle(),
+				},
+			},
+		},
+	}
+}
+
+// handleApproval applies a staff decision on a held sticker and closes the
+// request: approved stickers are announced, rejected ones deleted.
+func (g *CommandGroup) handleApproval(ctx *cmd.Context) error {
+	data, ok := ctx.Event.Data.(discord.ComponentInteraction)
+	if !ok || !ctx.GuildID.IsValid() {
+		return nil
+	}
+	decision, rawID, _ := strings.Cut(strings.TrimPrefix(string(data.ID()), approvalRoute), "|")
+	id, err := discord.ParseSnowflake(rawID)
+	if err != nil || (decision != decisionApprove && decision != decisionReject) {
+		return respondEphemeral(ctx, "This approval request is out of date.")
+	}
+	stickerID := discord.StickerID(id)
+	// Custom IDs can be replayed by anyone who sees the request.
+	if !canManageStickers(ctx) {
+		return respondEphemeral(ctx, "You need the Manage Expressions permission to review stickers.")
+	}
+
+	var outcome string
+	switch decision {
+	case decisionApprove:
+		sticker, err := fetchSticker(ctx.Client, ctx.GuildID, stickerID)
+		if unknownSticker(err) {
+			return g.closeRequest(ctx, theme.Muted(), "Deleted before review")
+		}
+		if err != nil {
+			g.logFailure(ctx, decision, stickerID, err)
+			return respondEphemeral(ctx, "Could not read the sticker. Try again in a moment.")
+		}
+		cfg := files.AssetsConfig{}
+		if gcfg := g.store.GuildConfig(ctx.GuildID.String()); gcfg != nil {
+			cfg = gcfg.Assets
+		}
+		if err := announceSticker(ctx.Client, cfg, *sticker); err != nil {
+			g.logFailure(ctx, decision, stickerID, err)
+			return respondEphemeral(ctx, "The sticker could not be announced. Check that the bot can post in the announcement channel.")
+		}
+		outcome = "Approved by " + ctx.UserID.Mention()
+
+	case decisionReject:
+		reason := api.AuditLogReason("Rejected in sticker review by " + ctx.UserID.String())
+		err := deleteSticker(ctx.Client, ctx.GuildID, stickerID, reason)
+		if err != nil && !unknownSticker(err) {
+			g.logFailure(ctx, decision, stickerID, err)
+			return respondEphemeral(ctx, "The sticker could not be deleted. The bot needs the Manage Expressions permission.")
+		}
+		outcome = "Rejected by " + ctx.UserID.Mention()
+	}
+
+	g.logger.Info("Architectural state transition: Sticker review decided",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("sticker_id", stickerID.String()),
+		slog.String("decision", decision),
+		slog.String("user_id", ctx.UserID.String()),
+	)
+	color := theme.Success()
+	if decision == decisionReject {
+		color = theme.Danger()
+	}
+	return g.closeRequest(ctx, color, outcome)
+}
+
+// closeRequest records outcome on the approval request and drops its
+// buttons.
+func (g *CommandGroup) closeRequest(ctx *cmd.Context, color theme.Color, outcome string) error {
+	var embeds []discord.Embed
+	if ctx.Event.Message != nil {
+		embeds = append(embeds, ctx.Event.Message.Embeds...)
+	}
+	if len(embeds) == 0 {
+		embeds = []discord.Embed{{}}
+	}
+	embeds[0].Color = discord.Color(color)
+	embeds[0].Fields = append(embeds[0].Fields, discord.EmbedField{Name: "Decision", Value: outcome})
+	components := discord.ContainerComponents{}
+	err := ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, api.InteractionResponse{
+		Type: api.UpdateMessage,
+		Data: &api.InteractionResponseData{
+			Embeds:     &embeds,
+			Components: &components,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("respond sticker review: %w", err)
+	}
+	return nil
+}
+
+func (g *CommandGroup) logFailure(ctx *cmd.Context, decision string, stickerID discord.StickerID, err error) {
+	g.logger.Error("Blocking structural failure: Sticker review aborted",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("sticker_id", stickerID.String()),
+		slog.String("decision", decision),
+		slog.String("error", err.Error()),
+	)
+}
+
+// canManageStickers reports whether the member behind a component
+// interaction may manage the guild's stickers.
+func canManageStickers(ctx *cmd.Context) bool {
+	if ctx.Client == nil {
+		return false
+	}
+	res, err := permissions.ResolveInChannel(ctx.Client, ctx.GuildID, ctx.UserID, ctx.Event.ChannelID)
+	if err != nil {
+		slog.Warn("Mitigated service degradation: Could not resolve member permissions for a sticker review",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+	return permissions.Has(res.Effective, int64(discord.PermissionManageEmojisAndStickers))
+}
+
+func stickerEndpoint(guildID discord.GuildID, stickerID discord.StickerID) string {
+	return api.EndpointGuilds + guildID.String() + "/stickers/" + stickerID.String()
+}
+
+func fetchSticker(client stickerClient, guildID discord.GuildID, stickerID discord.StickerID) (*discord.Sticker, error) {
+	var sticker discord.Sticker
+	if err := client.RequestJSON(&sticker, "GET", stickerEndpoint(guildID, stickerID)); err != nil {
+		return nil, fmt.Errorf("fetchSticker: %w", err)
+	}
+	return &sticker, nil
+}
+
+func deleteSticker(client stickerClient, guildID discord.GuildID, stickerID discord.StickerID, reason api.AuditLogReason) error {
+	if err := client.FastRequest("DELETE", stickerEndpoint(guildID, stickerID), httputil.WithHeaders(reason.Header())); err != nil {
+		return fmt.Errorf("deleteSticker: %w", err)
+	}
+	return nil
+}
+
+// unknownSticker reports whether err means the sticker no longer exists.
+func unknownSticker(err error) bool {
+	var httpErr *httputil.HTTPError
+	return errors.As(err, &httpErr) && (httpErr.Status == http.StatusNotFound || httpErr.Code == 10060)
+}
@@ -0,0 +1,84 @@
+package assets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+type recordingClient struct {
+	sent map[discord.ChannelID][]api.SendMessageData
+}
+
+func (c *recordingClient) SendMessageComplex(channelID discord.ChannelID, data api.SendMessageData) (*discord.Message, error) {
+	if c.sent == nil {
+		c.sent = make(map[discord.ChannelID][]api.SendMessageData)
+	}
+	c.sent[channelID] = append(c.sent[channelID], data)
+	return &discord.Message{ChannelID: channelID}, nil
+}
+
+func TestSubmitSticker(t *testing.T) {
+	t.Parallel()
+	sticker := discord.Sticker{ID: 5, Name: "party", Tags: "tada", FormatType: discord.StickerFormatAPNG, User: &discord.User{ID: 7}}
+
+	client := &recordingClient{}
+	held := files.AssetsConfig{RequireApproval: true, ApprovalChannelID: "10", AnnounceChannelID: "20"}
+	if err := SubmitSticker(client, held, sticker); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if len(client.sent) != 1 || len(client.sent[10]) != 1 {
+		t.Fatalf("expected only an approval request while approval is required, got %+v", client.sent)
+	}
+	request := client.sent[10][0]
+	row := request.Components[0].(*discord.ActionRowComponent)
+	approve, reject := (*row)[0].(*discord.ButtonComponent), (*row)[1].(*discord.ButtonComponent)
+	if approve.CustomID != approvalRoute+"approve|5" || reject.CustomID != approvalRoute+"reject|5" {
+		t.Fatalf("unexpected buttons %q %q", approve.CustomID, reject.CustomID)
+	}
+	if embed := request.Embeds[0]; embed.Image == nil || !strings.HasSuffix(embed.Image.URL, "/5.png") {
+		t.Fatalf("expected the sticker image, got %+v", embed.Image)
+	}
+
+	client = &recordingClient{}
+	if err := SubmitSticker(client, files.AssetsConfig{AnnounceChannelID: "20"}, sticker); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if len(client.sent[20]) != 1 || len(client.sent[20][0].Components) != 0 {
+		t.Fatalf("expected an announcement without approval, got %+v", client.sent)
+	}
+
+	client = &recordingClient{}
+	if err := SubmitSticker(client, files.AssetsConfig{}, sticker); err != nil || len(client.sent) != 0 {
+		t.Fatalf("expected nothing to be posted for ungoverned stickers, got %+v, %v", client.sent, err)
+	}
+}
+
+func TestStickerImageURL(t *testing.T) {
+	t.Parallel()
+	if got := StickerImageURL(discord.Sticker{ID: 5, FormatType: stickerFormatGIF}); !strings.HasSuffix(got, "/5.gif") {
+		t.Fatalf("expected a GIF URL, got %q", got)
+	}
+	if got := StickerImageURL(discord.Sticker{ID: 5, FormatType: discord.StickerFormatLottie}); got != "" {
+		t.Fatalf("expected no image for Lottie stickers, got %q", got)
+	}
+}
+
+func TestApplyRequireApproval(t *testing.T) {
+	t.Parallel()
+	cfg := files.AssetsConfig{ApprovalChannelID: "10", AnnounceChannelID: "20"}
+	applyRequireApproval(&cfg, true, 0, 30)
+	if !cfg.RequireApproval || cfg.ApprovalChannelID != "10" || cfg.AnnounceChannelID != "30" {
+		t.Fatalf("expected approval on with the announcement moved, got %+v", cfg)
+	}
+	if got := describeAssets(cfg); got != "New stickers are held for approval in <#10>. They are announced in <#30> once approved." {
+		t.Fatalf("unexpected description %q", got)
+	}
+	applyRequireApproval(&cfg, false, 0, 0)
+	if cfg.RequireApproval || cfg.ApprovalChannelID != "10" {
+		t.Fatalf("expected approval off with the channel kept, got %+v", cfg)
+	}
+}
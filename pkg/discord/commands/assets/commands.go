@@ -0,0 +1,196 @@
+package assets
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+const (
+	assetsGroupName        = "assets"
+	requireApprovalCommand = "require-approval"
+	enabledOpt             = "enabled"
+	channelOpt             = "channel"
+	announceChannelOpt     = "announce_channel"
+)
+
+// Store reads and persists the asset settings of a guild.
+// *files.ConfigManager satisfies it.
+type Store interface {
+	GuildConfig(guildID string) *files.GuildConfig
+	UpdateGuildConfig(guildID string, fn func(*files.GuildConfig) error) error
+}
+
+// CommandGroup serves the sticker approval buttons and, as a section of
+// /config, /config assets.
+type CommandGroup struct {
+	store  Store
+	logger *slog.Logger
+}
+
+// NewCommandGroup builds the approval button routes and the /config assets
+// section.
+func NewCommandGroup(store Store, logger *slog.Logger) *CommandGroup {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &CommandGroup{store: store, logger: logger}
+}
+
+// Register fulfills cmd.CommandGroup. /config assets is registered by the
+// /config command, which this group is a section of.
+func (g *CommandGroup) Register(guildID string, botProfileID string) []api.CreateCommandData {
+	return nil
+}
+
+// Handle fulfills cmd.CommandGroup.
+func (g *CommandGroup) Handle(guildID string, botProfileID string) map[string]cmd.CommandHandler {
+	return map[string]cmd.CommandHandler{
+		approvalRoute: g.handleApproval,
+	}
+}
+
+// ConfigOption describes /config assets.
+func (g *CommandGroup) ConfigOption() *discord.SubcommandGroupOption {
+	textChannels := []discord.ChannelType{discord.GuildText, discord.GuildAnnouncement}
+	return &discord.SubcommandGroupOption{
+		OptionName:  assetsGroupName,
+		Description: "Govern stickers uploaded to the server",
+		Subcommands: []*discord.SubcommandOption{
+			{
+				OptionName:  requireApprovalCommand,
+				Description: "Hold new stickers for staff approval before they are announced",
+				Options: []discord.CommandOptionValue{
+					&discord.BooleanOption{
+						OptionName:  enabledOpt,
+						Description: "Require approval for new stickers",
+						Required:    true,
+					},
+					&discord.ChannelOption{
+						OptionName:   channelOpt,
+						Description:  "Staff channel approval requests are posted to",
+						ChannelTypes: textChannels,
+					},
+					&discord.ChannelOption{
+						OptionName:   announceChannelOpt,
+						Description:  "Channel approved stickers are announced in",
+						ChannelTypes: textChannels,
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure runs a /config assets subcommand.
+func (g *CommandGroup) Configure(ctx *cmd.Context, group discord.CommandInteractionOption) error {
+	if len(group.Options) == 0 || group.Options[0].Name != requireApprovalCommand {
+		return respondEphemeral(ctx, "Unknown config command.")
+	}
+	return g.handleRequireApproval(ctx, group.Options[0].Options)
+}
+
+func (g *CommandGroup) handleRequireApproval(ctx *cmd.Context, opts []discord.CommandInteractionOption) error {
+	var (
+		enabled            bool
+		approval, announce discord.ChannelID
+	)
+	for _, opt := range opts {
+		switch opt.Name {
+		case enabledOpt:
+			enabled, _ = opt.BoolValue()
+		case channelOpt:
+			if id, err := opt.SnowflakeValue(); err == nil {
+				approval = discord.ChannelID(id)
+			}
+		case announceChannelOpt:
+			if id, err := opt.SnowflakeValue(); err == nil {
+				announce = discord.ChannelID(id)
+			}
+		}
+	}
+
+	var saved files.AssetsConfig
+	err := g.store.UpdateGuildConfig(ctx.GuildID.String(), func(cfg *files.GuildConfig) error {
+		applyRequireApproval(&cfg.Assets, enabled, approval, announce)
+		if cfg.Assets.RequireApproval && strings.TrimSpace(cfg.Assets.ApprovalChannelID) == "" {
+			return errNoApprovalChannel
+		}
+		saved = cfg.Assets
+		return nil
+	})
+	if errors.Is(err, errNoApprovalChannel) {
+		return respondEphemeral(ctx, "Choose the staff channel approval requests are posted to.")
+	}
+	if err != nil {
+		g.logger.Error("Blocking structural failure: Sticker approval settings could not be saved",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("error", err.Error()),
+		)
+		return respondEphemeral(ctx, "Failed to save the sticker settings. Nothing was changed.")
+	}
+
+	g.logger.Info("Architectural state transition: Sticker approval settings updated",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.Bool("require_approval", saved.RequireApproval),
+		slog.String("approval_channel_id", saved.ApprovalChannelID),
+		slog.String("announce_channel_id", saved.AnnounceChannelID),
+	)
+	return respondEphemeral(ctx, describeAssets(saved))
+}
+
+// errNoApprovalChannel aborts the config update when approval would be
+// required with nowhere to post the requests.
+var errNoApprovalChannel = errors.New("no sticker approval channel")
+
+// applyRequireApproval turns approval on or off, moving the approval and
+// announcement channels when they are given.
+func applyRequireApproval(cfg *files.AssetsConfig, enabled bool, approval, announce discord.ChannelID) {
+	cfg.RequireApproval = enabled
+	if approval.IsValid() {
+		cfg.ApprovalChannelID = approval.String()
+	}
+	if announce.IsValid() {
+		cfg.AnnounceChannelID = announce.String()
+	}
+}
+
+func describeAssets(cfg files.AssetsConfig) string {
+	var b strings.Builder
+	if cfg.RequireApproval {
+		fmt.Fprintf(&b, "New stickers are held for approval in <#%s>.", cfg.ApprovalChannelID)
+	} else {
+		b.WriteString("New stickers no longer need approval.")
+	}
+	if cfg.AnnounceChannelID != "" {
+		fmt.Fprintf(&b, " They are announced in <#%s>", cfg.AnnounceChannelID)
+		if cfg.RequireApproval {
+			b.WriteString(" once approved")
+		}
+		b.WriteString(".")
+	} else {
+		b.WriteString(" They are not announced.")
+	}
+	return b.String()
+}
+
+func respondEphemeral(ctx *cmd.Context, content string) error {
+	err := ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, api.InteractionResponse{
+		Type: api.MessageInteractionWithSource,
+		Data: &api.InteractionResponseData{
+			Content: option.NewNullableString(content),
+			Flags:   discord.EphemeralMessage,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("respond config interaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+/*
+Package assets governs the stickers members upload to a guild.
+
+It provides the assets section of /config, which decides whether new
+stickers are held for staff approval and where they are announced, and
+serves the approval request the logging bot posts for each held sticker.
+Approving a sticker announces it; rejecting it deletes the sticker from the
+guild.
+*/
+package assets
@@ -2,8 +2,11 @@ package clean
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"regexp"
+	"time"
 
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
@@ -11,28 +14,65 @@ import (
 
 	coreclean "github.com/small-frappuccino/discordcore/pkg/clean"
 	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/permissions"
 )
 
 // CleanExecutor defines the execution bounds for a concrete deletion service.
 type CleanExecutor interface {
-	ExecuteClean(ctx context.Context, channelID discord.ChannelID, filter coreclean.Filter, auditChannelID discord.ChannelID, requestedBy string) (int, error)
+	ExecuteClean(ctx context.Context, channelID discord.ChannelID, filter coreclean.Filter, auditChannelID discord.ChannelID, requestedBy string) (coreclean.Outcome, error)
 }
 
 // CleanCommandGroup bridges the Discord Slash Command interaction to the bounded clean executor.
 type CleanCommandGroup struct {
 	cleanExecutor CleanExecutor
+	pending       *pendingCleans
+	log           CleanLogStore
+	lockdown      LockdownReader
+	now           func() time.Time
 }
 
+// Option configures optional clean command features.
+type Option func(*CleanCommandGroup)
+
 // NewCleanCommand initializes a router-compatible clean interaction handler.
-func NewCleanCommand(executor CleanExecutor) cmd.CommandGroup {
-	return &CleanCommandGroup{
+func NewCleanCommand(executor CleanExecutor, opts ...Option) cmd.CommandGroup {
+	g := &CleanCommandGroup{
 		cleanExecutor: executor,
+		pending:       newPendingCleans(time.Now),
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(g)
 	}
+	return g
+}
+
+// LockdownReader reports whether the bot lockdown is engaged.
+// *files.ConfigManager satisfies it.
+type LockdownReader interface {
+	Config() *files.BotConfig
+}
+
+// WithLockdown refuses to clean while the bot lockdown read from cfg is
+// engaged, like the other mass moderation commands.
+func WithLockdown(cfg LockdownReader) Option {
+	return func(g *CleanCommandGroup) { g.lockdown = cfg }
 }
 
+// lockedDown reports whether the bot lockdown pauses cleaning.
+func (c *CleanCommandGroup) lockedDown() bool {
+	return c.lockdown != nil && c.lockdown.Config().LockdownActive()
+}
+
+// lockdownMessage tells the moderator why nothing was cleaned.
+const lockdownMessage = "Mass moderation commands are disabled while the bot lockdown is engaged."
+
 // Register returns the blueprints for the clean commands.
 func (c *CleanCommandGroup) Register(guildID string, botProfileID string) []api.CreateCommandData {
-	return []api.CreateCommandData{
+	commands := []api.CreateCommandData{
 		{
 			Name:                     "clean",
 			Description:              "Delete recent messages in this channel",
@@ -40,10 +80,10 @@ func (c *CleanCommandGroup) Register(guildID string, botProfileID string) []api.
 			Options: []discord.CommandOption{
 				&discord.IntegerOption{
 					OptionName:  "count",
-					Description: "How many matching messages to remove (max 100)",
+					Description: fmt.Sprintf("How many matching messages to remove (max %d, or %d with deep)", coreclean.CleanMaxDeleteCount, coreclean.CleanDeepMaxDeleteCount),
 					Required:    true,
 					Min:         option.NewInt(1),
-					Max:         option.NewInt(100),
+					Max:         option.NewInt(coreclean.CleanDeepMaxDeleteCount),
 				},
 				&discord.UserOption{
 					OptionName:  "user",
@@ -65,16 +105,62 @@ func (c *CleanCommandGroup) Register(guildID string, botProfileID string) []api.
 					Description: "Newer message ID bound",
 					Required:    false,
 				},
+				&discord.BooleanOption{
+					OptionName:  "bots",
+					Description: "Only remove messages sent by bots",
+					Required:    false,
+				},
+				&discord.StringOption{
+					OptionName:  "regex",
+					Description: "Only remove messages matching this regular expression",
+					Required:    false,
+					MaxLength:   option.NewInt(coreclean.MaxPatternLength),
+				},
+				&discord.StringOption{
+					OptionName:  "has",
+					Description: "Only remove messages with this kind of content",
+					Required:    false,
+					Choices: []discord.StringChoice{
+						{Name: "Attachments", Value: string(coreclean.ContentAttachments)},
+						{Name: "Links", Value: string(coreclean.ContentLinks)},
+						{Name: "Embeds", Value: string(coreclean.ContentEmbeds)},
+					},
+				},
+				&discord.BooleanOption{
+					OptionName:  "archive",
+					Description: "Post a JSON archive of the messages to the message-delete log first",
+					Required:    false,
+				},
+				&discord.BooleanOption{
+					OptionName:  "preview",
+					Description: "List the matching messages without deleting anything",
+					Required:    false,
+				},
+				&discord.BooleanOption{
+					OptionName:  "deep",
+					Description: "Search further back and slowly delete messages older than 14 days",
+					Required:    false,
+				},
 			},
 		},
 	}
+	if c.log != nil {
+		commands = append(commands, cleanExportCommand())
+	}
+	return commands
 }
 
 // Handle exposes the O(1) routing dictionary.
 func (c *CleanCommandGroup) Handle(guildID string, botProfileID string) map[string]cmd.CommandHandler {
-	return map[string]cmd.CommandHandler{
-		"clean": c.handleClean,
+	handlers := map[string]cmd.CommandHandler{
+		"clean":           c.handleClean,
+		cleanConfirmRoute: c.handleConfirm,
+		cleanCancelRoute:  c.handleCancel,
 	}
+	if c.log != nil {
+		handlers[cleanExportName] = c.handleExport
+	}
+	return handlers
 }
 
 // EphemeralError satisfies the standard error interface while retaining sufficient metadata to render private UI feedback to the calling user without exposing stack traces.
@@ -109,6 +195,9 @@ func (c *CleanCommandGroup) handleClean(ctx *cmd.Context) error {
 	if !ctx.GuildID.IsValid() {
 		return &EphemeralError{UserMessage: "This command must be used in a server.", InternalErr: fmt.Errorf("missing guild_id")}
 	}
+	if c.lockedDown() {
+		return respondEphemeral(ctx, lockdownMessage, nil)
+	}
 
 	// We no longer lookup from configManager directly. We assume middleware or DI handles it, or we fetch from DI.
 	// But since we need config, we could have it in DI or context.
@@ -119,7 +208,8 @@ func (c *CleanCommandGroup) handleClean(ctx *cmd.Context) error {
 	// Let's rely on DI for config if needed, but let's just do the clean logic.
 
 	var count int
-	var userID, contains, fromID, toID string
+	var userID, contains, fromID, toID, pattern, has string
+	var botsOnly, archive, preview, deep bool
 
 	if ctx.Event != nil && ctx.Event.Data != nil && ctx.Event.Data.InteractionType() == discord.CommandInteractionType {
 		cmdData := ctx.Event.Data.(*discord.CommandInteraction)
@@ -156,12 +246,73 @@ func (c *CleanCommandGroup) handleClean(ctx *cmd.Context) error {
 					return &EphemeralError{UserMessage: "Invalid format for to.", InternalErr: fmt.Errorf("structural anomaly: expected StringOptionType for to")}
 				}
 				toID = opt.String()
+			case "bots":
+				if opt.Type != discord.BooleanOptionType {
+					return &EphemeralError{UserMessage: "Invalid format for bots.", InternalErr: fmt.Errorf("structural anomaly: expected BooleanOptionType for bots")}
+				}
+				val, err := opt.BoolValue()
+				if err == nil {
+					botsOnly = val
+				}
+			case "regex":
+				if opt.Type != discord.StringOptionType {
+					return &EphemeralError{UserMessage: "Invalid format for regex.", InternalErr: fmt.Errorf("structural anomaly: expected StringOptionType for regex")}
+				}
+				pattern = opt.String()
+			case "has":
+				if opt.Type != discord.StringOptionType {
+					return &EphemeralError{UserMessage: "Invalid format for has.", InternalErr: fmt.Errorf("structural anomaly: expected StringOptionType for has")}
+				}
+				has = opt.String()
+			case "archive":
+				if opt.Type != discord.BooleanOptionType {
+					return &EphemeralError{UserMessage: "Invalid format for archive.", InternalErr: fmt.Errorf("structural anomaly: expected BooleanOptionType for archive")}
+				}
+				val, err := opt.BoolValue()
+				if err == nil {
+					archive = val
+				}
+			case "preview":
+				if opt.Type != discord.BooleanOptionType {
+					return &EphemeralError{UserMessage: "Invalid format for preview.", InternalErr: fmt.Errorf("structural anomaly: expected BooleanOptionType for preview")}
+				}
+				val, err := opt.BoolValue()
+				if err == nil {
+					preview = val
+				}
+			case "deep":
+				if opt.Type != discord.BooleanOptionType {
+					return &EphemeralError{UserMessage: "Invalid format for deep.", InternalErr: fmt.Errorf("structural anomaly: expected BooleanOptionType for deep")}
+				}
+				val, err := opt.BoolValue()
+				if err == nil {
+					deep = val
+				}
 			}
 		}
 	}
 
-	if count < 1 || count > 100 {
-		return &EphemeralError{UserMessage: "Count must be between 1 and 100.", InternalErr: fmt.Errorf("invalid count %d", count)}
+	maxCount := coreclean.CleanMaxDeleteCount
+	if deep {
+		maxCount = coreclean.CleanDeepMaxDeleteCount
+	}
+	if count < 1 || count > maxCount {
+		msg := fmt.Sprintf("Count must be between 1 and %d.", maxCount)
+		if !deep && count <= coreclean.CleanDeepMaxDeleteCount {
+			msg = fmt.Sprintf("Count must be between 1 and %d. Enable deep to remove up to %d.", maxCount, coreclean.CleanDeepMaxDeleteCount)
+		}
+		return &EphemeralError{UserMessage: msg, InternalErr: fmt.Errorf("invalid count %d", count)}
+	}
+	// A guild in test mode sees what a clean would remove, as a preview.
+	testMode := inTestMode(ctx)
+	if testMode {
+		preview = true
+	}
+
+	// The command's default member permissions are guild-wide; a channel
+	// overwrite that denies Manage Messages must still stop the clean.
+	if err := ensureManageMessagesInChannel(ctx); err != nil {
+		return err
 	}
 
 	filter := coreclean.Filter{
@@ -170,17 +321,67 @@ func (c *CleanCommandGroup) handleClean(ctx *cmd.Context) error {
 		Contains: contains,
 		FromID:   fromID,
 		ToID:     toID,
+		BotsOnly: botsOnly,
+		Has:      coreclean.ContentKind(has),
+	}
+	if err := applyPattern(&filter, pattern); err != nil {
+		return err
+	}
+	switch filter.Has {
+	case "", coreclean.ContentAttachments, coreclean.ContentLinks, coreclean.ContentEmbeds:
+	default:
+		return &EphemeralError{UserMessage: "Choose attachments, links or embeds for has.", InternalErr: fmt.Errorf("unknown content kind %q", has)}
+	}
+	protection := cleanConfig(ctx)
+	filter.GuildID = ctx.GuildID.String()
+	filter.IncludePinned = protection.AllowPinned
+	filter.ProtectedUserIDs = protection.ProtectedUserIDs
+	filter.ProtectedRoleIDs = protection.ProtectedRoleIDs
+	filter.Preview = preview
+	filter.Deep = deep
+	if archive && !preview {
+		filter.ArchiveChannelID = messageDeleteLogChannel(ctx)
+		if filter.ArchiveChannelID == "" {
+			return &EphemeralError{
+				UserMessage: "Set a message-delete log channel before cleaning with archive.",
+				InternalErr: fmt.Errorf("no message delete log channel in guild %s", ctx.GuildID),
+			}
+		}
 	}
 
 	var auditChannel discord.ChannelID
 	// Audit channel logic usually from ConfigManager. Since DI is strict, we might need to get it from DI or just omit.
 	// Let's assume DI has it or we just omit for now to conform to the purified signature.
 
-	deleted, err := c.cleanExecutor.ExecuteClean(context.Background(), ctx.Event.ChannelID, filter, auditChannel, ctx.UserID.String())
+	request := pendingClean{
+		channelID:    ctx.Event.ChannelID,
+		filter:       filter,
+		auditChannel: auditChannel,
+		requestedBy:  ctx.UserID,
+		testMode:     testMode,
+	}
+	if !preview && protection.NeedsConfirmation(count) {
+		return c.askConfirmation(ctx, request)
+	}
+	return c.runClean(ctx, request)
+}
+
+// runClean executes request and reports the outcome in the interaction's
+// original response.
+func (c *CleanCommandGroup) runClean(ctx *cmd.Context, request pendingClean) error {
+	filter := request.filter
+	if filter.Deep && !filter.Preview {
+		filter.Progress = c.deepProgress(ctx)
+	}
+	// The clean outlives the interaction, but keeps its log publisher.
+	outcome, err := c.cleanExecutor.ExecuteClean(context.WithoutCancel(ctx), request.channelID, filter, request.auditChannel, request.requestedBy.String())
+	if errors.Is(err, coreclean.ErrChannelBusy) {
+		return &EphemeralError{UserMessage: "A clean of this channel is already running. Try again once it finishes.", InternalErr: err}
+	}
 	if err != nil {
 		slog.Error("Blocking structural failure restricted to operational scope: execute clean failed",
 			slog.String("guild_id", ctx.GuildID.String()),
-			slog.String("channel_id", ctx.Event.ChannelID.String()),
+			slog.String("channel_id", request.channelID.String()),
 			slog.String("error", err.Error()),
 		)
 		return &EphemeralError{UserMessage: "Failed to clean messages.", InternalErr: err}
@@ -188,13 +389,26 @@ func (c *CleanCommandGroup) handleClean(ctx *cmd.Context) error {
 
 	slog.Info("Operational telemetry: ExecuteClean completed successfully",
 		slog.String("guild_id", ctx.GuildID.String()),
-		slog.String("channel_id", ctx.Event.ChannelID.String()),
-		slog.Int("deleted_count", deleted),
+		slog.String("channel_id", request.channelID.String()),
+		slog.Int("deleted_count", outcome.Deleted),
+		slog.Int("skipped_pinned", outcome.SkippedPinned),
+		slog.Int("skipped_protected", outcome.SkippedProtected),
+		slog.Bool("preview", request.filter.Preview),
 	)
 
-	msg := fmt.Sprintf("Cleaned %d message(s).", deleted)
+	msg := outcomeMessage(outcome)
+	if request.filter.Preview {
+		msg = previewMessage(outcome)
+		if request.testMode {
+			msg = coremod.TestModeBanner + "\n" + msg
+		}
+	} else {
+		c.saveLog(ctx, request, outcome)
+	}
 	_, editErr := ctx.Client.EditInteractionResponse(ctx.Event.AppID, ctx.Event.Token, api.EditInteractionResponseData{
-		Content: option.NewNullableString(msg),
+		Content:         option.NewNullableString(msg),
+		Components:      &discord.ContainerComponents{},
+		AllowedMentions: &api.AllowedMentions{Parse: []api.AllowedMentionType{}},
 	})
 	if editErr != nil {
 		return fmt.Errorf("failed to edit interaction response: %w", editErr)
@@ -202,3 +416,91 @@ func (c *CleanCommandGroup) handleClean(ctx *cmd.Context) error {
 
 	return nil
 }
+
+// outcomeMessage tells the moderator what was removed and what protections
+// kept in place.
+func outcomeMessage(outcome coreclean.Outcome) string {
+	msg := fmt.Sprintf("Cleaned %d message(s).", outcome.Deleted)
+	if outcome.SkippedPinned > 0 {
+		msg += fmt.Sprintf(" Kept %d pinned message(s).", outcome.SkippedPinned)
+	}
+	if outcome.SkippedProtected > 0 {
+		msg += fmt.Sprintf(" Kept %d message(s) from protected members.", outcome.SkippedProtected)
+	}
+	return msg
+}
+
+// cleanConfig returns the guild's /clean protections.
+func cleanConfig(ctx *cmd.Context) files.CleanConfig {
+	gcfg := guildConfig(ctx)
+	if gcfg == nil {
+		return files.CleanConfig{}
+	}
+	return gcfg.Clean
+}
+
+// inTestMode reports whether the invoking guild is in test mode.
+func inTestMode(ctx *cmd.Context) bool {
+	gcfg := guildConfig(ctx)
+	return gcfg != nil && gcfg.TestMode
+}
+
+func guildConfig(ctx *cmd.Context) *files.GuildConfig {
+	if ctx.DI == nil {
+		return nil
+	}
+	cfgProv := ctx.DI.ConfigProvider()
+	if cfgProv == nil {
+		return nil
+	}
+	return cfgProv.GuildConfig(ctx.GuildID.String())
+}
+
+// messageDeleteLogChannel resolves where archives go, following the same
+// fallbacks as message delete logs.
+func messageDeleteLogChannel(ctx *cmd.Context) string {
+	if ctx.DI == nil {
+		return ""
+	}
+	cfgProv := ctx.DI.ConfigProvider()
+	if cfgProv == nil {
+		return ""
+	}
+	return logging.ResolveGuildLogChannel(logging.LogEventMessageDelete, cfgProv.GuildConfig(ctx.GuildID.String()))
+}
+
+// applyPattern compiles a user-supplied regular expression into the filter.
+func applyPattern(filter *coreclean.Filter, pattern string) error {
+	if pattern == "" {
+		return nil
+	}
+	if len(pattern) > coreclean.MaxPatternLength {
+		return &EphemeralError{
+			UserMessage: fmt.Sprintf("The regex must be at most %d characters.", coreclean.MaxPatternLength),
+			InternalErr: fmt.Errorf("pattern length %d", len(pattern)),
+		}
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return &EphemeralError{UserMessage: "The regex is not valid.", InternalErr: err}
+	}
+	filter.Pattern = re
+	return nil
+}
+
+func ensureManageMessagesInChannel(ctx *cmd.Context) error {
+	if ctx.Client == nil {
+		return &EphemeralError{UserMessage: "Could not verify your permissions in this channel.", InternalErr: fmt.Errorf("missing api client")}
+	}
+	res, err := permissions.ResolveInChannel(ctx.Client, ctx.GuildID, ctx.UserID, ctx.Event.ChannelID)
+	if err != nil {
+		return &EphemeralError{UserMessage: "Could not verify your permissions in this channel.", InternalErr: err}
+	}
+	if !permissions.Has(res.Effective, int64(discord.PermissionManageMessages)) {
+		return &EphemeralError{
+			UserMessage: "You need the Manage Messages permission in this channel.",
+			InternalErr: fmt.Errorf("user %s lacks manage messages in channel %s", ctx.UserID, ctx.Event.ChannelID),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package clean
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+
+	coreclean "github.com/small-frappuccino/discordcore/pkg/clean"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+)
+
+const (
+	cleanExportName = "clean-export"
+
+	// cleanExportDefaultDays and cleanExportMaxDays bound how far back an
+	// export reaches.
+	cleanExportDefaultDays = 7
+	cleanExportMaxDays     = 90
+
+	// previewListLimit and previewSnippetLength keep a preview within one
+	// Discord message.
+	previewListLimit     = 15
+	previewSnippetLength = 80
+)
+
+// CleanLogStore keeps the messages each clean deleted for later export.
+// *postgres.Store satisfies it.
+type CleanLogStore interface {
+	SaveCleanLog(ctx context.Context, guildID string, archive coreclean.Archive) error
+	ListCleanLogs(ctx context.Context, guildID, channelID string, since time.Time) ([]coreclean.Archive, error)
+}
+
+// WithCleanLog records deleted messages in store and adds /clean-export.
+func WithCleanLog(store CleanLogStore) Option {
+	return func(g *CleanCommandGroup) { g.log = store }
+}
+
+func cleanExportCommand() api.CreateCommandData {
+	return api.CreateCommandData{
+		Name:                     cleanExportName,
+		Description:              "Export the messages removed by recent cleans",
+		DefaultMemberPermissions: discord.NewPermissions(discord.PermissionManageMessages),
+		Options: []discord.CommandOption{
+			&discord.ChannelOption{
+				OptionName:  "channel",
+				Description: "Only export cleans in this channel",
+				Required:    false,
+			},
+			&discord.IntegerOption{
+				OptionName:  "days",
+				Description: fmt.Sprintf("How many days back to export (default %d)", cleanExportDefaultDays),
+				Required:    false,
+				Min:         option.NewInt(1),
+				Max:         option.NewInt(cleanExportMaxDays),
+			},
+		},
+	}
+}
+
+// saveLog records the messages a clean deleted. A failure is logged only:
+// the messages are already gone and the moderator still needs the result.
+// Guilds whose privacy profile keeps no message text get the metadata alone.
+func (c *CleanCommandGroup) saveLog(ctx *cmd.Context, request pendingClean, outcome coreclean.Outcome) {
+	if c.log == nil || len(outcome.Messages) == 0 {
+		return
+	}
+	archive := coreclean.NewArchive(request.channelID.String(), request.requestedBy.String(), outcome.Messages, c.now())
+	if !cacheMessageContent(ctx) {
+		for i := range archive.Messages {
+			archive.Messages[i].Content = ""
+			archive.Messages[i].Attachments = nil
+		}
+	}
+	if err := c.log.SaveCleanLog(context.Background(), ctx.GuildID.String(), archive); err != nil {
+		slog.Warn("Mitigated service degradation: Clean log not saved; the deleted messages cannot be exported",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("channel_id", request.channelID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+func cacheMessageContent(ctx *cmd.Context) bool {
+	if ctx.DI == nil {
+		return true
+	}
+	cfgProv := ctx.DI.ConfigProvider()
+	if cfgProv == nil {
+		return true
+	}
+	gcfg := cfgProv.GuildConfig(ctx.GuildID.String())
+	if gcfg == nil {
+		return true
+	}
+	return gcfg.Privacy.Policy().CacheMessageContent
+}
+
+// handleExport sends the recorded cleans as a JSON file, newest first.
+func (c *CleanCommandGroup) handleExport(ctx *cmd.Context) error {
+	if !ctx.GuildID.IsValid() {
+		return &EphemeralError{UserMessage: "This command must be used in a server.", InternalErr: fmt.Errorf("missing guild_id")}
+	}
+
+	var channelID string
+	days := cleanExportDefaultDays
+	for _, opt := range ctx.Options {
+		switch opt.Name {
+		case "channel":
+			if val, err := opt.SnowflakeValue(); err == nil {
+				channelID = val.String()
+			}
+		case "days":
+			if val, err := opt.IntValue(); err == nil {
+				days = min(max(int(val), 1), cleanExportMaxDays)
+			}
+		}
+	}
+
+	since := c.now().Add(-time.Duration(days) * 24 * time.Hour)
+	logs, err := c.log.ListCleanLogs(context.Background(), ctx.GuildID.String(), channelID, since)
+	if err != nil {
+		return &EphemeralError{UserMessage: "Failed to load the clean log.", InternalErr: err}
+	}
+	if len(logs) == 0 {
+		return respondEphemeral(ctx, fmt.Sprintf("No cleans were recorded in the last %d day(s).", days), nil)
+	}
+
+	data, err := json.MarshalIndent(logs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("CleanCommandGroup.handleExport: %w", err)
+	}
+	name := fmt.Sprintf("clean-log-%s-%s.json", ctx.GuildID, c.now().UTC().Format("20060102-150405"))
+	return respondEphemeral(ctx,
+		fmt.Sprintf("Messages removed by %d clean(s) in the last %d day(s).", len(logs), days),
+		[]sendpart.File{{Name: name, Reader: bytes.NewReader(data)}},
+	)
+}
+
+// previewMessage lists what a clean would remove.
+func previewMessage(outcome coreclean.Outcome) string {
+	if len(outcome.Messages) == 0 {
+		return "No messages match. Nothing would be deleted."
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Preview: %d message(s) would be deleted.", len(outcome.Messages))
+	if outcome.SkippedPinned > 0 || outcome.SkippedProtected > 0 {
+		fmt.Fprintf(&b, " %d pinned and %d protected message(s) would be kept.", outcome.SkippedPinned, outcome.SkippedProtected)
+	}
+	b.WriteString("\n")
+	for i, m := range outcome.Messages {
+		if i == previewListLimit {
+			fmt.Fprintf(&b, "…and %d more\n", len(outcome.Messages)-previewListLimit)
+			break
+		}
+		fmt.Fprintf(&b, "- <@%s>: %s\n", m.AuthorID, previewSnippet(m))
+	}
+	return b.String()
+}
+
+func previewSnippet(m coreclean.Message) string {
+	text := strings.Join(strings.Fields(m.Content), " ")
+	if runes := []rune(text); len(runes) > previewSnippetLength {
+		text = string(runes[:previewSnippetLength]) + "…"
+	}
+	switch {
+	case text != "":
+		return logging.InlineCode(text)
+	case m.HasAttachments:
+		return "*attachment*"
+	case m.HasEmbeds:
+		return "*embed*"
+	default:
+		return "*no text*"
+	}
+}
+
+func respondEphemeral(ctx *cmd.Context, content string, files []sendpart.File) error {
+	err := ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, api.InteractionResponse{
+		Type: api.MessageInteractionWithSource,
+		Data: &api.InteractionResponseData{
+			Content:         option.NewNullableString(content),
+			Files:           files,
+			Flags:           discord.EphemeralMessage,
+			AllowedMentions: &api.AllowedMentions{Parse: []api.AllowedMentionType{}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("respond clean interaction: %w", err)
+	}
+	return nil
+}
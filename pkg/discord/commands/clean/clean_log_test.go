@@ -0,0 +1,40 @@
+package clean
+
+import (
+	"strings"
+	"testing"
+
+	coreclean "github.com/small-frappuccino/discordcore/pkg/clean"
+)
+
+func TestPreviewMessage(t *testing.T) {
+	t.Parallel()
+
+	if got := previewMessage(coreclean.Outcome{}); !strings.Contains(got, "Nothing would be deleted") {
+		t.Fatalf("empty preview = %q", got)
+	}
+
+	messages := make([]coreclean.Message, previewListLimit+3)
+	for i := range messages {
+		messages[i] = coreclean.Message{ID: "1", AuthorID: "42", Content: strings.Repeat("`spam` ", 30)}
+	}
+	messages[0] = coreclean.Message{ID: "2", AuthorID: "7", HasAttachments: true}
+	got := previewMessage(coreclean.Outcome{Messages: messages, SkippedPinned: 1})
+
+	for _, want := range []string{
+		"18 message(s) would be deleted",
+		"1 pinned and 0 protected",
+		"- <@7>: *attachment*",
+		"…and 3 more",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("preview missing %q:\n%s", want, got)
+		}
+	}
+	if len(got) > 2000 {
+		t.Fatalf("preview is %d characters, over Discord's limit", len(got))
+	}
+	if strings.Count(got, "`")%2 != 0 {
+		t.Fatalf("snippets leave unbalanced code spans:\n%s", got)
+	}
+}
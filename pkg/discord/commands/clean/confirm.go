@@ -0,0 +1,167 @@
+package clean
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+
+	coreclean "github.com/small-frappuccino/discordcore/pkg/clean"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+)
+
+const (
+	cleanConfirmRoute = "clean_confirm|"
+	cleanCancelRoute  = "clean_cancel|"
+
+	// cleanConfirmTTL bounds how long a clean waits for its confirmation.
+	cleanConfirmTTL = 5 * time.Minute
+)
+
+// pendingClean is a validated /clean waiting to run.
+type pendingClean struct {
+	channelID    discord.ChannelID
+	filter       coreclean.Filter
+	auditChannel discord.ChannelID
+	requestedBy  discord.UserID
+	// testMode marks a clean turned into a preview by the guild's test mode.
+	testMode bool
+	expires  time.Time
+}
+
+// pendingCleans holds cleans awaiting confirmation, keyed by the interaction
+// that requested them. They live in memory only: a restart drops them and
+// the moderator runs /clean again.
+type pendingCleans struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	entries map[string]pendingClean
+}
+
+func newPendingCleans(now func() time.Time) *pendingCleans {
+	return &pendingCleans{now: now, entries: make(map[string]pendingClean)}
+}
+
+func (p *pendingCleans) put(key string, request pendingClean) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	for k, entry := range p.entries {
+		if now.After(entry.expires) {
+			delete(p.entries, k)
+		}
+	}
+	request.expires = now.Add(cleanConfirmTTL)
+	p.entries[key] = request
+}
+
+// take removes and returns the clean stored under key if userID requested
+// it and it has not expired. The prompt is ephemeral, so nobody else should
+// see the buttons in the first place.
+func (p *pendingCleans) take(key string, userID discord.UserID) (pendingClean, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	request, ok := p.entries[key]
+	if !ok || request.requestedBy != userID {
+		return pendingClean{}, false
+	}
+	delete(p.entries, key)
+	if p.now().After(request.expires) {
+		return pendingClean{}, false
+	}
+	return request, true
+}
+
+// askConfirmation parks request and shows the moderator confirm and cancel
+// buttons instead of cleaning right away.
+func (c *CleanCommandGroup) askConfirmation(ctx *cmd.Context, request pendingClean) error {
+	key := ctx.Event.ID.String()
+	c.pending.put(key, request)
+
+	components := discord.ContainerComponents{
+		&discord.ActionRowComponent{
+			&discord.ButtonComponent{
+				Label:    "Delete messages",
+				CustomID: discord.ComponentID(cleanConfirmRoute + key),
+				Style:    discord.DangerButtonStyle(),
+			},
+			&discord.ButtonComponent{
+				Label:    "Cancel",
+				CustomID: discord.ComponentID(cleanCancelRoute + key),
+				Style:    discord.SecondaryButtonStyle(),
+			},
+		},
+	}
+	err := ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, api.InteractionResponse{
+		Type: api.MessageInteractionWithSource,
+		Data: &api.InteractionResponseData{
+			Content: option.NewNullableString(fmt.Sprintf(
+				"This clean removes up to %d messages. Confirm within %d minutes to go ahead.",
+				request.filter.Count, int(cleanConfirmTTL.Minutes()),
+			)),
+			Components: &components,
+			Flags:      discord.EphemeralMessage,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("respond clean confirmation: %w", err)
+	}
+	return nil
+}
+
+// handleConfirm runs a parked clean once its requester confirms it.
+func (c *CleanCommandGroup) handleConfirm(ctx *cmd.Context) error {
+	request, ok := c.pending.take(componentKey(ctx, cleanConfirmRoute), ctx.UserID)
+	if !ok {
+		return updateMessage(ctx, "This clean request expired. Run /clean again.")
+	}
+	// Permissions, and the lockdown, may have changed while the confirmation
+	// was pending.
+	if err := ensureManageMessagesInChannel(ctx); err != nil {
+		return err
+	}
+	if c.lockedDown() {
+		return updateMessage(ctx, lockdownMessage)
+	}
+
+	err := ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, api.InteractionResponse{
+		Type: api.DeferredMessageUpdate,
+	})
+	if err != nil {
+		return fmt.Errorf("defer clean confirmation: %w", err)
+	}
+	return c.runClean(ctx, request)
+}
+
+// handleCancel drops a parked clean.
+func (c *CleanCommandGroup) handleCancel(ctx *cmd.Context) error {
+	c.pending.take(componentKey(ctx, cleanCancelRoute), ctx.UserID)
+	return updateMessage(ctx, "Clean cancelled. No messages were deleted.")
+}
+
+func componentKey(ctx *cmd.Context, route string) string {
+	data, ok := ctx.Event.Data.(discord.ComponentInteraction)
+	if !ok {
+		return ""
+	}
+	return strings.TrimPrefix(string(data.ID()), route)
+}
+
+// updateMessage replaces the confirmation prompt and its buttons with content.
+func updateMessage(ctx *cmd.Context, content string) error {
+	err := ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, api.InteractionResponse{
+		Type: api.UpdateMessage,
+		Data: &api.InteractionResponseData{
+			Content:    option.NewNullableString(content),
+			Components: &discord.ContainerComponents{},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("update clean confirmation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package clean
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	coreclean "github.com/small-frappuccino/discordcore/pkg/clean"
+)
+
+func TestPendingCleans(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 6, 20, 12, 0, 0, 0, time.UTC)
+	pending := newPendingCleans(func() time.Time { return now })
+
+	pending.put("1", pendingClean{filter: coreclean.Filter{Count: 80}, requestedBy: 7})
+	if _, ok := pending.take("1", 8); ok {
+		t.Fatal("another user took the pending clean")
+	}
+	request, ok := pending.take("1", 7)
+	if !ok || request.filter.Count != 80 {
+		t.Fatalf("take = %+v, %v", request, ok)
+	}
+	if _, ok := pending.take("1", 7); ok {
+		t.Fatal("a confirmed clean was handed out twice")
+	}
+
+	pending.put("2", pendingClean{requestedBy: discord.UserID(7)})
+	now = now.Add(cleanConfirmTTL + time.Second)
+	if _, ok := pending.take("2", 7); ok {
+		t.Fatal("an expired clean was handed out")
+	}
+}
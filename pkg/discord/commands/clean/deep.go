@@ -0,0 +1,43 @@
+package clean
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+)
+
+// deepProgressInterval spaces the progress edits of a deep clean so they do
+// not compete with the deletions for rate limit.
+const deepProgressInterval = 10 * time.Second
+
+// deepProgress reports how far a deep clean got through its old messages in
+// the interaction's response, at most once per deepProgressInterval. The
+// final count is left to the outcome message.
+func (c *CleanCommandGroup) deepProgress(ctx *cmd.Context) func(done, total int) {
+	last := c.now()
+	return func(done, total int) {
+		now := c.now()
+		if done == total || now.Sub(last) < deepProgressInterval {
+			return
+		}
+		last = now
+		_, err := ctx.Client.EditInteractionResponse(ctx.Event.AppID, ctx.Event.Token, api.EditInteractionResponseData{
+			Content: option.NewNullableString(deepProgressMessage(done, total)),
+		})
+		if err != nil {
+			slog.Warn("Mitigated service degradation: Deep clean progress not reported",
+				slog.String("guild_id", ctx.GuildID.String()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+func deepProgressMessage(done, total int) string {
+	return fmt.Sprintf("Deleting messages older than 14 days one at a time: %d of %d done…", done, total)
+}
@@ -0,0 +1,38 @@
+package cmd
+
+// RegistrationScope says where Discord is told about a command.
+type RegistrationScope string
+
+const (
+	// ScopeGlobal registers the command for the whole application, in every
+	// guild and in DMs. Discord can take a while to show changes to it. It
+	// is the scope of commands that do not name one.
+	ScopeGlobal RegistrationScope = "global"
+	// ScopeGuild registers the command in each guild the bot instance
+	// serves. Changes show at once, and the command is absent from DMs.
+	ScopeGuild RegistrationScope = "guild"
+	// ScopeDevGuild registers the command only in the development guild
+	// named by RuntimeConfig.CommandDevGuildID, for commands not ready for
+	// every server.
+	ScopeDevGuild RegistrationScope = "dev_guild"
+)
+
+// Valid reports whether s names a registration scope.
+func (s RegistrationScope) Valid() bool {
+	switch s {
+	case ScopeGlobal, ScopeGuild, ScopeDevGuild:
+		return true
+	default:
+		return false
+	}
+}
+
+// ScopedCommandGroup is implemented by command groups with commands that are
+// not registered globally. Groups without it register every command
+// globally.
+type ScopedCommandGroup interface {
+	CommandGroup
+	// RegistrationScope returns where the named command of the group is
+	// registered.
+	RegistrationScope(commandName string) RegistrationScope
+}
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"errors"
+
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/utils/json/option"
@@ -46,6 +48,15 @@ func (ctx *InteractionContext) RespondMessage(content string) error {
 	return ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, data)
 }
 
+// Respond sends the response b assembled, spreading it over follow-ups
+// when it does not fit one message.
+func (ctx *InteractionContext) Respond(b *ResponseBuilder) error {
+	if ctx.Client == nil {
+		return errors.New("InteractionContext.Respond: nil client")
+	}
+	return b.Respond(ctx.Client, ctx.Event)
+}
+
 // StringOption retrieves the string value of a command option by its name.
 // It returns true if the option is found and successfully cast to a string,
 // or false if the option is missing or possesses a different fundamental type.
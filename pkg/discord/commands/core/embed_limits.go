@@ -0,0 +1,186 @@
+package core
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+// Discord's per-embed limits SanitizeEmbed fits an embed into.
+const (
+	MaxEmbedTitle       = 256
+	MaxEmbedDescription = 4096
+	MaxEmbedFields      = 25
+	MaxEmbedFieldName   = 256
+	MaxEmbedFieldValue  = 1024
+	MaxEmbedFooter      = 2048
+	MaxEmbedAuthor      = 256
+)
+
+// continuationSuffix marks the title or field name of text carried over
+// from the previous embed or field.
+const continuationSuffix = " (cont.)"
+
+// SanitizeEmbed fits embed into Discord's limits. Titles, names and the
+// footer are truncated with an ellipsis. Descriptions and field values too
+// long for one embed or field are split, preferring line breaks, and carried
+// on in continuation fields and embeds titled "(cont.)", which also take the
+// fields past the 25 or 6000-character caps. The author and images stay on
+// the first embed, the footer and timestamp move to the last.
+//
+// Lengths are measured in bytes, which is never less than the characters
+// Discord counts, so the result fits whatever the script.
+func SanitizeEmbed(embed discord.Embed) []discord.Embed {
+	embed.Title = truncateText(embed.Title, MaxEmbedTitle)
+	if embed.Author != nil {
+		author := *embed.Author
+		author.Name = truncateText(author.Name, MaxEmbedAuthor)
+		embed.Author = &author
+	}
+	footer := embed.Footer
+	if footer != nil {
+		f := *footer
+		f.Text = truncateText(f.Text, MaxEmbedFooter)
+		footer = &f
+	}
+	timestamp := embed.Timestamp
+
+	var fields []discord.EmbedField
+	for _, field := range embed.Fields {
+		fields = append(fields, splitField(field)...)
+	}
+	descriptions := splitText(embed.Description, MaxEmbedDescription)
+
+	first := embed
+	first.Description, first.Fields, first.Footer, first.Timestamp = "", nil, nil, discord.Timestamp{}
+	out := []discord.Embed{first}
+	next := func() *discord.Embed {
+		out = append(out, discord.Embed{
+			Title: continuedName(embed.Title, MaxEmbedTitle),
+			Color: embed.Color,
+		})
+		return &out[len(out)-1]
+	}
+
+	for i, description := range descriptions {
+		current := &out[len(out)-1]
+		if i > 0 {
+			current = next()
+		}
+		current.Description = description
+	}
+	for _, field := range fields {
+		current := &out[len(out)-1]
+		if len(current.Fields) == MaxEmbedFields ||
+			embedTextBytes(*current)+len(field.Name)+len(field.Value) > MaxMessageEmbedText {
+			current = next()
+		}
+		current.Fields = append(current.Fields, field)
+	}
+	if footer != nil || timestamp.IsValid() {
+		current := &out[len(out)-1]
+		if footer != nil && embedTextBytes(*current)+len(footer.Text) > MaxMessageEmbedText {
+			current = next()
+		}
+		current.Footer, current.Timestamp = footer, timestamp
+	}
+	return out
+}
+
+// FieldsForLines lays lines out as fields named name, one line per row,
+// starting a continuation field whenever the next line no longer fits.
+// Blank lines are dropped; no lines yield no fields.
+func FieldsForLines(name string, lines []string) []discord.EmbedField {
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return splitField(discord.EmbedField{Name: name, Value: strings.Join(kept, "\n")})
+}
+
+// splitField splits the value of field over as many fields as it needs.
+func splitField(field discord.EmbedField) []discord.EmbedField {
+	name := truncateText(field.Name, MaxEmbedFieldName)
+	if field.Value == "" {
+		// Discord rejects empty values; a zero-width space keeps the field.
+		field.Name, field.Value = name, "\u200b"
+		return []discord.EmbedField{field}
+	}
+	chunks := splitText(field.Value, MaxEmbedFieldValue)
+	out := make([]discord.EmbedField, 0, len(chunks))
+	for i, chunk := range chunks {
+		f := discord.EmbedField{Name: name, Value: chunk, Inline: field.Inline}
+		if i > 0 {
+			f.Name = continuedName(field.Name, MaxEmbedFieldName)
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// splitText cuts s into pieces of at most limit bytes, at the last line
+// break that fits when there is one and otherwise at a rune boundary.
+func splitText(s string, limit int) []string {
+	var out []string
+	for len(s) > limit {
+		cut := strings.LastIndexByte(s[:limit+1], '\n')
+		if cut > 0 {
+			out = append(out, s[:cut])
+			s = s[cut+1:]
+			continue
+		}
+		cut = limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		out = append(out, s[:cut])
+		s = s[cut:]
+	}
+	if s != "" {
+		out = append(out, s)
+	}
+	return out
+}
+
+// truncateText shortens s to at most limit bytes, ending it with an
+// ellipsis when anything was cut.
+func truncateText(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	const ellipsis = "…"
+	cut := limit - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + ellipsis
+}
+
+// continuedName is name marked as a continuation, within limit bytes.
+func continuedName(name string, limit int) string {
+	if name == "" {
+		return ""
+	}
+	return truncateText(name, limit-len(continuationSuffix)) + continuationSuffix
+}
+
+// embedTextBytes is EmbedTextLength in bytes.
+func embedTextBytes(embed discord.Embed) int {
+	n := len(embed.Title) + len(embed.Description)
+	for _, field := range embed.Fields {
+		n += len(field.Name) + len(field.Value)
+	}
+	if embed.Footer != nil {
+		n += len(embed.Footer.Text)
+	}
+	if embed.Author != nil {
+		n += len(embed.Author.Name)
+	}
+	return n
+}
@@ -0,0 +1,149 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+func TestSanitizeEmbed_WithinLimits(t *testing.T) {
+	t.Parallel()
+
+	embed := discord.Embed{
+		Title:       "Audit",
+		Description: "Nothing to report.",
+		Fields:      []discord.EmbedField{{Name: "Cases", Value: "3"}},
+		Footer:      &discord.EmbedFooter{Text: "footer"},
+	}
+	out := SanitizeEmbed(embed)
+	if len(out) != 1 || out[0].Description != embed.Description || len(out[0].Fields) != 1 || out[0].Footer == nil {
+		t.Fatalf("an embed within limits should come back as is, got %+v", out)
+	}
+}
+
+func TestSanitizeEmbed_Truncates(t *testing.T) {
+	t.Parallel()
+
+	out := SanitizeEmbed(discord.Embed{
+		Title:  strings.Repeat("t", 300),
+		Author: &discord.EmbedAuthor{Name: strings.Repeat("é", 200)},
+		Fields: []discord.EmbedField{{Name: strings.Repeat("n", 300), Value: ""}},
+	})
+	if len(out) != 1 {
+		t.Fatalf("expected one embed, got %d", len(out))
+	}
+	if len(out[0].Title) > MaxEmbedTitle || !strings.HasSuffix(out[0].Title, "…") {
+		t.Errorf("title not truncated: %d bytes", len(out[0].Title))
+	}
+	if name := out[0].Author.Name; len(name) > MaxEmbedAuthor || !strings.HasSuffix(name, "…") || !utf8.ValidString(name) {
+		t.Errorf("author not truncated on a rune boundary: %q", name)
+	}
+	if f := out[0].Fields[0]; len(f.Name) > MaxEmbedFieldName || f.Value == "" {
+		t.Errorf("field not fitted: %d byte name, value %q", len(f.Name), f.Value)
+	}
+}
+
+func TestSanitizeEmbed_Continues(t *testing.T) {
+	t.Parallel()
+
+	var lines []string
+	for range 600 {
+		lines = append(lines, "a line of the description")
+	}
+	fields := make([]discord.EmbedField, 30)
+	for i := range fields {
+		fields[i] = discord.EmbedField{Name: "Field", Value: strings.Repeat("v", 500)}
+	}
+	fields[0].Value = strings.Repeat("w", 1500)
+	stamp := discord.NewTimestamp(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+
+	out := SanitizeEmbed(discord.Embed{
+		Title:       "Members",
+		Description: strings.Join(lines, "\n"),
+		Color:       0x3498db,
+		Fields:      fields,
+		Footer:      &discord.EmbedFooter{Text: "page 1"},
+		Timestamp:   stamp,
+	})
+
+	var all []discord.EmbedField
+	for i, embed := range out {
+		if embed.Color != 0x3498db {
+			t.Errorf("embed %d lost the color", i)
+		}
+		if i > 0 && embed.Title != "Members (cont.)" {
+			t.Errorf("embed %d title = %q", i, embed.Title)
+		}
+		if len(embed.Description) > MaxEmbedDescription || len(embed.Fields) > MaxEmbedFields || EmbedTextLength(embed) > MaxMessageEmbedText {
+			t.Errorf("embed %d exceeds the limits", i)
+		}
+		if strings.HasPrefix(embed.Description, "\n") || strings.HasSuffix(embed.Description, "\n") {
+			t.Errorf("embed %d description was not split at a line break", i)
+		}
+		for _, f := range embed.Fields {
+			if len(f.Value) > MaxEmbedFieldValue {
+				t.Errorf("embed %d has a %d byte field", i, len(f.Value))
+			}
+		}
+		all = append(all, embed.Fields...)
+		if (embed.Footer != nil) != (i == len(out)-1) || embed.Timestamp.IsValid() != (i == len(out)-1) {
+			t.Errorf("embed %d: the footer and timestamp belong on the last embed only", i)
+		}
+	}
+	if len(all) != 31 {
+		t.Fatalf("expected the long field to split into two, for 31 fields, got %d", len(all))
+	}
+	if all[1].Name != "Field (cont.)" {
+		t.Fatalf("expected a continuation field, got %q", all[1].Name)
+	}
+}
+
+func TestFieldsForLines(t *testing.T) {
+	t.Parallel()
+
+	if fields := FieldsForLines("Empty", []string{" ", ""}); fields != nil {
+		t.Fatalf("blank lines should yield no fields, got %+v", fields)
+	}
+	fields := FieldsForLines("Keys", []string{strings.Repeat("a", 600), strings.Repeat("b", 600), "c"})
+	if len(fields) != 2 || fields[0].Value != strings.Repeat("a", 600) || fields[1].Value != strings.Repeat("b", 600)+"\nc" {
+		t.Fatalf("expected lines to stay whole across fields, got %+v", fields)
+	}
+	if fields[1].Name != "Keys (cont.)" {
+		t.Fatalf("expected a continuation name, got %q", fields[1].Name)
+	}
+}
+
+// BenchmarkSanitizeEmbed measures fitting an embed into Discord's limits,
+// for one already within them and for one that has to be split.
+func BenchmarkSanitizeEmbed(b *testing.B) {
+	small := discord.Embed{
+		Title:       "Member Joined",
+		Description: "<@123456789012345678> joined the server",
+		Fields: []discord.EmbedField{
+			{Name: "Account Age", Value: "2 years", Inline: true},
+			{Name: "Member Count", Value: "1234", Inline: true},
+		},
+		Footer: &discord.EmbedFooter{Text: "User ID: 123456789012345678"},
+	}
+	large := small
+	large.Description = strings.Repeat("a long line of message content\n", 300)
+	large.Fields = nil
+	for range 40 {
+		large.Fields = append(large.Fields, discord.EmbedField{Name: "Field", Value: strings.Repeat("value ", 200)})
+	}
+
+	for _, bc := range []struct {
+		name  string
+		embed discord.Embed
+	}{{"within_limits", small}, {"split", large}} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				SanitizeEmbed(bc.embed)
+			}
+		})
+	}
+}
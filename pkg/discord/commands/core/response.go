@@ -0,0 +1,182 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+)
+
+// Discord's per-message limits that ResponseBuilder spreads a response
+// across.
+const (
+	MaxMessageEmbeds    = 10
+	MaxMessageFiles     = 10
+	MaxMessageEmbedText = 6000
+)
+
+// ResponseClient is the part of *api.Client a ResponseBuilder sends through.
+type ResponseClient interface {
+	RespondInteraction(id discord.InteractionID, token string, resp api.InteractionResponse) error
+	EditInteractionResponse(appID discord.AppID, token string, data api.EditInteractionResponseData) (*discord.Message, error)
+	FollowUpInteraction(appID discord.AppID, token string, data api.InteractionResponseData) (*discord.Message, error)
+}
+
+// ResponseBuilder assembles an interaction response from text, embeds and
+// files, such as transcripts, CSV exports or charts. A response carrying
+// more embeds or files than one message allows is split into a first
+// message and follow-ups, in order.
+type ResponseBuilder struct {
+	content    string
+	embeds     []discord.Embed
+	files      []sendpart.File
+	components *discord.ContainerComponents
+	flags      discord.MessageFlags
+}
+
+// NewResponse starts an empty response.
+func NewResponse() *ResponseBuilder {
+	return &ResponseBuilder{}
+}
+
+// Content sets the text of the first message.
+func (b *ResponseBuilder) Content(content string) *ResponseBuilder {
+	b.content = content
+	return b
+}
+
+// Embeds appends embeds to the response, each fitted to Discord's limits
+// by SanitizeEmbed.
+func (b *ResponseBuilder) Embeds(embeds ...discord.Embed) *ResponseBuilder {
+	for _, embed := range embeds {
+		b.embeds = append(b.embeds, SanitizeEmbed(embed)...)
+	}
+	return b
+}
+
+// File attaches the contents of r to the response as name.
+func (b *ResponseBuilder) File(name string, r io.Reader) *ResponseBuilder {
+	b.files = append(b.files, sendpart.File{Name: name, Reader: r})
+	return b
+}
+
+// Components sets the components of the first message.
+func (b *ResponseBuilder) Components(components discord.ContainerComponents) *ResponseBuilder {
+	b.components = &components
+	return b
+}
+
+// Ephemeral shows every message of the response to the invoker only.
+func (b *ResponseBuilder) Ephemeral() *ResponseBuilder {
+	b.flags |= discord.EphemeralMessage
+	return b
+}
+
+// Messages splits the response into the messages it is sent as. Embeds fill
+// each message up to MaxMessageEmbeds and MaxMessageEmbedText. Files go
+// MaxMessageFiles to a message, starting with the first.
+func (b *ResponseBuilder) Messages() []api.InteractionResponseData {
+	var messages []api.InteractionResponseData
+	var current []discord.Embed
+	var currentText int
+	flush := func() {
+		embeds := current
+		messages = append(messages, api.InteractionResponseData{Embeds: &embeds, Flags: b.flags})
+		current, currentText = nil, 0
+	}
+	for _, embed := range b.embeds {
+		n := EmbedTextLength(embed)
+		if len(current) > 0 && (len(current) == MaxMessageEmbeds || currentText+n > MaxMessageEmbedText) {
+			flush()
+		}
+		current = append(current, embed)
+		currentText += n
+	}
+	if len(current) > 0 {
+		flush()
+	}
+
+	for i := 0; i < len(b.files); i += MaxMessageFiles {
+		idx := i / MaxMessageFiles
+		if idx == len(messages) {
+			messages = append(messages, api.InteractionResponseData{Flags: b.flags})
+		}
+		messages[idx].Files = b.files[i:min(i+MaxMessageFiles, len(b.files))]
+	}
+
+	if len(messages) == 0 {
+		messages = append(messages, api.InteractionResponseData{Flags: b.flags})
+	}
+	if b.content != "" {
+		messages[0].Content = option.NewNullableString(b.content)
+	}
+	messages[0].Components = b.components
+	return messages
+}
+
+// Respond answers the interaction with the first message and sends the rest
+// as follow-ups.
+func (b *ResponseBuilder) Respond(client ResponseClient, event *discord.InteractionEvent) error {
+	if client == nil || event == nil {
+		return errors.New("ResponseBuilder.Respond: nil client or interaction")
+	}
+	messages := b.Messages()
+	first := messages[0]
+	if err := client.RespondInteraction(event.ID, event.Token, api.InteractionResponse{
+		Type: api.MessageInteractionWithSource,
+		Data: &first,
+	}); err != nil {
+		return fmt.Errorf("ResponseBuilder.Respond: %w", err)
+	}
+	return b.followUp(client, event, messages[1:])
+}
+
+// EditDeferred replaces the deferred response of the interaction with the
+// first message and sends the rest as follow-ups.
+func (b *ResponseBuilder) EditDeferred(client ResponseClient, event *discord.InteractionEvent) error {
+	if client == nil || event == nil {
+		return errors.New("ResponseBuilder.EditDeferred: nil client or interaction")
+	}
+	messages := b.Messages()
+	first := messages[0]
+	if _, err := client.EditInteractionResponse(event.AppID, event.Token, api.EditInteractionResponseData{
+		Content:    first.Content,
+		Embeds:     first.Embeds,
+		Components: first.Components,
+		Files:      first.Files,
+	}); err != nil {
+		return fmt.Errorf("ResponseBuilder.EditDeferred: %w", err)
+	}
+	return b.followUp(client, event, messages[1:])
+}
+
+func (b *ResponseBuilder) followUp(client ResponseClient, event *discord.InteractionEvent, messages []api.InteractionResponseData) error {
+	for i, data := range messages {
+		if _, err := client.FollowUpInteraction(event.AppID, event.Token, data); err != nil {
+			return fmt.Errorf("ResponseBuilder: follow-up %d of %d: %w", i+1, len(messages), err)
+		}
+	}
+	return nil
+}
+
+// EmbedTextLength counts the characters of embed that Discord holds against
+// the text limit of a message: title, description, field names and values,
+// footer text and author name.
+func EmbedTextLength(embed discord.Embed) int {
+	n := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+	for _, field := range embed.Fields {
+		n += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+	if embed.Footer != nil {
+		n += utf8.RuneCountInString(embed.Footer.Text)
+	}
+	if embed.Author != nil {
+		n += utf8.RuneCountInString(embed.Author.Name)
+	}
+	return n
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+type fakeResponseClient struct {
+	responses []api.InteractionResponse
+	edits     []api.EditInteractionResponseData
+	followUps []api.InteractionResponseData
+	followErr error
+}
+
+func (f *fakeResponseClient) RespondInteraction(_ discord.InteractionID, _ string, resp api.InteractionResponse) error {
+	f.responses = append(f.responses, resp)
+	return nil
+}
+
+func (f *fakeResponseClient) EditInteractionResponse(_ discord.AppID, _ string, data api.EditInteractionResponseData) (*discord.Message, error) {
+	f.edits = append(f.edits, data)
+	return &discord.Message{}, nil
+}
+
+func (f *fakeResponseClient) FollowUpInteraction(_ discord.AppID, _ string, data api.InteractionResponseData) (*discord.Message, error) {
+	if f.followErr != nil {
+		return nil, f.followErr
+	}
+	f.followUps = append(f.followUps, data)
+	return &discord.Message{}, nil
+}
+
+func TestResponseBuilder_Messages(t *testing.T) {
+	t.Parallel()
+
+	b := NewResponse().Content("Export ready").Ephemeral()
+	for range 12 {
+		b.Embeds(discord.Embed{Title: "page"})
+	}
+	b.Embeds(discord.Embed{Description: strings.Repeat("x", 4000)}, discord.Embed{Description: strings.Repeat("y", 4000)})
+	for range 11 {
+		b.File("export.csv", strings.NewReader("a,b"))
+	}
+
+	messages := b.Messages()
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(messages))
+	}
+	wantEmbeds := []int{10, 3, 1}
+	wantFiles := []int{10, 1, 0}
+	for i, msg := range messages {
+		if len(*msg.Embeds) != wantEmbeds[i] || len(msg.Files) != wantFiles[i] {
+			t.Errorf("message %d: %d embeds, %d files", i, len(*msg.Embeds), len(msg.Files))
+		}
+		if msg.Flags&discord.EphemeralMessage == 0 {
+			t.Errorf("message %d is not ephemeral", i)
+		}
+	}
+	if messages[0].Content == nil || messages[0].Content.Val != "Export ready" || messages[1].Content != nil {
+		t.Fatal("expected the content on the first message only")
+	}
+}
+
+func TestResponseBuilder_FilesWithoutEmbeds(t *testing.T) {
+	t.Parallel()
+
+	messages := NewResponse().File("transcript.html", strings.NewReader("<html>")).Messages()
+	if len(messages) != 1 || len(messages[0].Files) != 1 || messages[0].Embeds != nil {
+		t.Fatalf("expected one message with the file, got %+v", messages)
+	}
+	if messages := NewResponse().Messages(); len(messages) != 1 {
+		t.Fatalf("an empty response should still be one message, got %d", len(messages))
+	}
+}
+
+func TestResponseBuilder_Send(t *testing.T) {
+	t.Parallel()
+	event := &discord.InteractionEvent{ID: 1, AppID: 2, Token: "token"}
+	b := NewResponse()
+	for range 15 {
+		b.Embeds(discord.Embed{Title: "page"})
+	}
+
+	client := &fakeResponseClient{}
+	if err := b.Respond(client, event); err != nil {
+		t.Fatalf("Respond: %v", err)
+	}
+	if len(client.responses) != 1 || len(*client.responses[0].Data.Embeds) != 10 || len(client.followUps) != 1 {
+		t.Fatalf("expected a response of 10 embeds and one follow-up, got %d responses, %d follow-ups", len(client.responses), len(client.followUps))
+	}
+
+	client = &fakeResponseClient{}
+	if err := b.EditDeferred(client, event); err != nil {
+		t.Fatalf("EditDeferred: %v", err)
+	}
+	if len(client.edits) != 1 || len(client.followUps) != 1 || len(*client.followUps[0].Embeds) != 5 {
+		t.Fatalf("expected an edit and a follow-up of 5 embeds, got %d edits, %d follow-ups", len(client.edits), len(client.followUps))
+	}
+
+	client = &fakeResponseClient{followErr: errors.New("rate limited")}
+	if err := b.Respond(client, event); err == nil || !strings.Contains(err.Error(), "follow-up 1 of 1") {
+		t.Fatalf("expected the follow-up failure to be returned, got %v", err)
+	}
+}
@@ -24,11 +24,18 @@ func ResolveFeatureForCommandPath(path string) string {
 		strings.HasPrefix(path, "unlock"),
 		strings.HasPrefix(path, "massban"),
 		strings.HasPrefix(path, "mute"),
+		strings.HasPrefix(path, "stage"),
+		strings.HasPrefix(path, "moderation:"),
+		strings.HasPrefix(path, "massaction:"),
 		strings.HasPrefix(path, "reaction_block"):
 		return "moderation"
 	case strings.HasPrefix(path, "rolepanel"),
 		strings.HasPrefix(path, "role"):
 		return "roles"
+	case strings.HasPrefix(path, "assets:"):
+		// Sticker approval requests are posted by the logging bot, which
+		// therefore serves their buttons.
+		return "logging"
 	case strings.HasPrefix(path, "partner"):
 		return "partners"
 	case strings.HasPrefix(path, "embed"):
@@ -41,3 +48,59 @@ func ResolveFeatureForCommandPath(path string) string {
 		return "commands"
 	}
 }
+
+// privilegedCommandRoots are commands that change bot or guild configuration
+// outside the moderation feature.
+var privilegedCommandRoots = map[string]bool{
+	"admin":   true,
+	"config":  true,
+	"logging": true,
+	"runtime": true,
+}
+
+// IsPrivilegedCommandPath reports whether executions of the command at path
+// belong in the command audit trail: owner operations, configuration changes,
+// and moderation actions.
+func IsPrivilegedCommandPath(path string) bool {
+	root, _, _ := strings.Cut(strings.TrimSpace(path), " ")
+	root = strings.TrimSuffix(root, "|")
+	if privilegedCommandRoots[root] {
+		return true
+	}
+	return ResolveFeatureForCommandPath(root) == "moderation"
+}
+
+// readOnlyCommandPaths are full command paths that only read state, for
+// commands whose leaves are not named after what they do. Naming the leaves
+// keeps subcommands added later from being let through unchecked.
+var readOnlyCommandPaths = map[string]bool{
+	"clean-export": true,
+	"logs search":  true,
+}
+
+// readOnlySubcommands are subcommand leaves that only display or export state,
+// whichever command they belong to.
+var readOnlySubcommands = map[string]bool{
+	"list":            true,
+	"view":            true,
+	"preview":         true,
+	"export":          true,
+	"export_template": true,
+	"errors":          true,
+	"audit":           true,
+	"permcheck":       true,
+}
+
+// IsReadOnlyCommandPath reports whether the slash command at path, given as
+// the space-separated root and subcommands, leaves bot, guild and database
+// state untouched, so it may run on a read-only instance.
+func IsReadOnlyCommandPath(path string) bool {
+	fields := strings.Fields(path)
+	if len(fields) == 0 {
+		return false
+	}
+	if readOnlyCommandPaths[strings.Join(fields, " ")] {
+		return true
+	}
+	return len(fields) > 1 && readOnlySubcommands[fields[len(fields)-1]]
+}
@@ -13,6 +13,7 @@ var validFeatureKeys = map[string]bool{
 	"embeds":     true,
 	"tickets":    true,
 	"stats":      true,
+	"logging":    true,
 	"commands":   true, // Fallback
 }
 
@@ -34,6 +35,8 @@ func TestResolveFeatureForCommandPath(t *testing.T) {
 
 		// Edge cases & Fallbacks
 		{"Exact match without args", "ban", "moderation"},
+		{"Moderation modal route", "moderation:reason|", "moderation"},
+		{"Sticker approval route", "assets:approval|", "logging"},
 		{"Unknown path triggers fallback", "leveling stats", "commands"},
 		{"Empty string", "", "commands"},
 		{"Malformed payload", "     ban", "commands"}, // HasPrefix is strict, shouldn't trim automatically
@@ -90,3 +93,49 @@ func BenchmarkResolveFeatureForCommandPath(b *testing.B) {
 		_ = ResolveFeatureForCommandPath(paths[i%pathsLen])
 	}
 }
+
+func TestIsPrivilegedCommandPath(t *testing.T) {
+	t.Parallel()
+	for path, want := range map[string]bool{
+		"admin token rotate": true,
+		"logging avatar":     true,
+		"ban":                true,
+		"massban":            true,
+		"qotd add":           false,
+		"stats":              false,
+		"rolepanel post":     false,
+		"":                   false,
+	} {
+		if got := IsPrivilegedCommandPath(path); got != want {
+			t.Errorf("IsPrivilegedCommandPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestIsReadOnlyCommandPath(t *testing.T) {
+	t.Parallel()
+	for path, want := range map[string]bool{
+		"warnings list":         true,
+		"clean-export":          true,
+		"warnings remove":       false,
+		"warnings clear":        false,
+		"logs purge":            false,
+		"case view":             true,
+		"note list":             true,
+		"rolepanel button list": true,
+		"embed export":          true,
+		"admin errors":          true,
+		"logs search":           true,
+		"case delete":           false,
+		"note add":              false,
+		"stats add":             false,
+		"ban":                   false,
+		"list":                  false,
+		"admin token rotate":    false,
+		"":                      false,
+	} {
+		if got := IsReadOnlyCommandPath(path); got != want {
+			t.Errorf("IsReadOnlyCommandPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package guildconfig
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+const (
+	commandsGroupName = "commands"
+	disableCommand    = "disable"
+	enableCommand     = "enable"
+	listCommand       = "list"
+	commandOpt        = "command"
+)
+
+// errUnchanged aborts the config update when the command already is in the
+// requested state.
+var errUnchanged = errors.New("command already in the requested state")
+
+func commandsOption() *discord.SubcommandGroupOption {
+	name := func(description string) []discord.CommandOptionValue {
+		return []discord.CommandOptionValue{
+			&discord.StringOption{
+				OptionName:  commandOpt,
+				Description: description,
+				Required:    true,
+				MaxLength:   option.NewInt(files.MaxCommandNameLength),
+			},
+		}
+	}
+	return &discord.SubcommandGroupOption{
+		OptionName:  commandsGroupName,
+		Description: "Turn the bot's commands off or on for this server",
+		Subcommands: []*discord.SubcommandOption{
+			{
+				OptionName:  disableCommand,
+				Description: "Stop a command from running on this server",
+				Options:     name("Command to disable, such as stats"),
+			},
+			{
+				OptionName:  enableCommand,
+				Description: "Let a disabled command run again",
+				Options:     name("Command to enable"),
+			},
+			{
+				OptionName:  listCommand,
+				Description: "Show the commands disabled on this server",
+			},
+		},
+	}
+}
+
+func (g *CommandGroup) configureCommands(ctx *cmd.Context, group discord.CommandInteractionOption) error {
+	if len(group.Options) == 0 {
+		return respondEphemeral(ctx, "Unknown config command.")
+	}
+	sub := group.Options[0]
+	guildID := ctx.GuildID.String()
+	if sub.Name == listCommand {
+		var disabled []string
+		if cfg := g.store.GuildConfig(guildID); cfg != nil {
+			disabled = cfg.DisabledCommands
+		}
+		return respondEphemeral(ctx, describeDisabled(disabled))
+	}
+	if sub.Name != disableCommand && sub.Name != enableCommand {
+		return respondEphemeral(ctx, "Unknown config command.")
+	}
+
+	var name string
+	for _, opt := range sub.Options {
+		if opt.Name == commandOpt {
+			name = normalizeCommandName(opt.String())
+		}
+	}
+	if !files.ValidCommandName(name) {
+		return respondEphemeral(ctx, "Give the name of a command, such as `stats`.")
+	}
+	if name == files.ConfigCommandName {
+		return respondEphemeral(ctx, "/config cannot be disabled.")
+	}
+
+	disable := sub.Name == disableCommand
+	err := g.store.UpdateGuildConfig(guildID, func(cfg *files.GuildConfig) error {
+		if !setCommandDisabled(cfg, name, disable) {
+			return errUnchanged
+		}
+		return nil
+	})
+	if errors.Is(err, errUnchanged) {
+		if disable {
+			return respondEphemeral(ctx, fmt.Sprintf("/%s is already disabled.", name))
+		}
+		return respondEphemeral(ctx, fmt.Sprintf("/%s is not disabled.", name))
+	}
+	if err != nil {
+		g.logger.Error("Blocking structural failure: Command visibility could not be saved",
+			slog.String("guild_id", guildID),
+			slog.String("command", name),
+			slog.String("error", err.Error()),
+		)
+		return respondEphemeral(ctx, "Failed to save the command settings. Nothing was changed.")
+	}
+
+	g.logger.Info("Architectural state transition: Guild command visibility updated",
+		slog.String("guild_id", guildID),
+		slog.String("command", name),
+		slog.Bool("disabled", disable),
+		slog.String("user_id", ctx.UserID.String()),
+	)
+	if disable {
+		// The router refuses it. Guild commands are synced again without
+		// it, but a global command stays in Discord's list everywhere.
+		return respondEphemeral(ctx, fmt.Sprintf("/%s is now disabled on this server. Running it is refused, and it leaves the command list unless it is registered globally.", name))
+	}
+	return respondEphemeral(ctx, fmt.Sprintf("/%s is enabled again.", name))
+}
+
+// normalizeCommandName accepts a command as typed, with or without its
+// slash and in any case.
+func normalizeCommandName(raw string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(raw), "/"))
+}
+
+// setCommandDisabled turns name off or on in cfg, reporting whether that
+// changed anything.
+func setCommandDisabled(cfg *files.GuildConfig, name string, disable bool) bool {
+	if cfg.CommandDisabled(name) == disable {
+		return false
+	}
+	if disable {
+		cfg.DisabledCommands = append(cfg.DisabledCommands, name)
+		slices.Sort(cfg.DisabledCommands)
+		return true
+	}
+	cfg.DisabledCommands = slices.DeleteFunc(cfg.DisabledCommands, func(n string) bool { return n == name })
+	if len(cfg.DisabledCommands) == 0 {
+		cfg.DisabledCommands = nil
+	}
+	return true
+}
+
+func describeDisabled(names []string) string {
+	if len(names) == 0 {
+		return "No commands are disabled on this server."
+	}
+	listed := make([]string, len(names))
+	for i, name := range names {
+		listed[i] = "/" + name
+	}
+	return "Disabled on this server: " + strings.Join(listed, ", ")
+}
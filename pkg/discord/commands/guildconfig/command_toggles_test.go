@@ -0,0 +1,56 @@
+package guildconfig
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+type stubSection struct{}
+
+func (stubSection) ConfigOption() *discord.SubcommandGroupOption {
+	return &discord.SubcommandGroupOption{OptionName: "assets"}
+}
+
+func (stubSection) Configure(*cmd.Context, discord.CommandInteractionOption) error { return nil }
+
+func TestRegisterIncludesSections(t *testing.T) {
+	t.Parallel()
+	data := NewCommandGroup(nil, nil, stubSection{}).Register("", "")
+	if len(data) != 1 || data[0].Name != files.ConfigCommandName {
+		t.Fatalf("expected a single /config command, got %+v", data)
+	}
+	var groups []string
+	for _, opt := range data[0].Options {
+		groups = append(groups, opt.Name())
+	}
+	if !slices.Equal(groups, []string{commandsGroupName, "assets"}) {
+		t.Fatalf("unexpected /config groups %v", groups)
+	}
+}
+
+func TestSetCommandDisabled(t *testing.T) {
+	t.Parallel()
+	cfg := &files.GuildConfig{}
+	if !setCommandDisabled(cfg, "stats", true) || !setCommandDisabled(cfg, "qotd", true) {
+		t.Fatal("expected disabling to change the config")
+	}
+	if setCommandDisabled(cfg, "stats", true) {
+		t.Fatal("disabling a disabled command should change nothing")
+	}
+	if got := describeDisabled(cfg.DisabledCommands); got != "Disabled on this server: /qotd, /stats" {
+		t.Fatalf("unexpected description %q", got)
+	}
+	if !setCommandDisabled(cfg, "stats", false) || !setCommandDisabled(cfg, "qotd", false) || cfg.DisabledCommands != nil {
+		t.Fatalf("expected both commands enabled again, got %v", cfg.DisabledCommands)
+	}
+	if setCommandDisabled(cfg, "stats", false) {
+		t.Fatal("enabling an enabled command should change nothing")
+	}
+	if got := normalizeCommandName(" /Stats "); got != "stats" {
+		t.Fatalf("normalizeCommandName = %q", got)
+	}
+}
@@ -0,0 +1,101 @@
+package guildconfig
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// Store reads and persists the settings of a guild.
+// *files.ConfigManager satisfies it.
+type Store interface {
+	GuildConfig(guildID string) *files.GuildConfig
+	UpdateGuildConfig(guildID string, fn func(*files.GuildConfig) error) error
+}
+
+// Section is a subcommand group of /config, provided by the feature it
+// configures.
+type Section interface {
+	// ConfigOption describes the group.
+	ConfigOption() *discord.SubcommandGroupOption
+	// Configure runs the invoked subcommand of group. The interaction is
+	// known to come from a guild.
+	Configure(ctx *cmd.Context, group discord.CommandInteractionOption) error
+}
+
+// CommandGroup serves /config.
+type CommandGroup struct {
+	store    Store
+	logger   *slog.Logger
+	sections []Section
+}
+
+// NewCommandGroup builds /config from the commands section and sections.
+func NewCommandGroup(store Store, logger *slog.Logger, sections ...Section) cmd.CommandGroup {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &CommandGroup{store: store, logger: logger, sections: sections}
+}
+
+// Register fulfills cmd.CommandGroup.
+func (g *CommandGroup) Register(guildID string, botProfileID string) []api.CreateCommandData {
+	options := []discord.CommandOption{commandsOption()}
+	for _, section := range g.sections {
+		options = append(options, section.ConfigOption())
+	}
+	return []api.CreateCommandData{
+		{
+			Name:                     files.ConfigCommandName,
+			Description:              "Configure server features",
+			DefaultMemberPermissions: discord.NewPermissions(discord.PermissionManageGuild),
+			Options:                  options,
+		},
+	}
+}
+
+// Handle fulfills cmd.CommandGroup.
+func (g *CommandGroup) Handle(guildID string, botProfileID string) map[string]cmd.CommandHandler {
+	return map[string]cmd.CommandHandler{
+		files.ConfigCommandName: g.handleCommand,
+	}
+}
+
+func (g *CommandGroup) handleCommand(ctx *cmd.Context) error {
+	data, ok := ctx.Event.Data.(*discord.CommandInteraction)
+	if !ok || len(data.Options) == 0 {
+		return nil
+	}
+	if !ctx.GuildID.IsValid() {
+		return respondEphemeral(ctx, "Run this command inside a server.")
+	}
+	group := data.Options[0]
+	if group.Name == commandsGroupName {
+		return g.configureCommands(ctx, group)
+	}
+	for _, section := range g.sections {
+		if section.ConfigOption().OptionName == group.Name {
+			return section.Configure(ctx, group)
+		}
+	}
+	return respondEphemeral(ctx, "Unknown config command.")
+}
+
+func respondEphemeral(ctx *cmd.Context, content string) error {
+	err := ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, api.InteractionResponse{
+		Type: api.MessageInteractionWithSource,
+		Data: &api.InteractionResponseData{
+			Content: option.NewNullableString(content),
+			Flags:   discord.EphemeralMessage,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("respond config interaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+/*
+Package guildconfig serves /config, the command server admins change the
+bot's settings for their server with.
+
+Each subcommand group of /config is a Section provided by the feature it
+configures, such as /config assets. The commands section lives here: it
+turns top-level slash commands off for the server, and the command router
+refuses a disabled command before dispatching it.
+*/
+package guildconfig
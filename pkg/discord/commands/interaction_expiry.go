@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"errors"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+)
+
+const (
+	// InteractionTokenLifetime is how long after an interaction is created
+	// Discord accepts edits of its response and follow-ups.
+	InteractionTokenLifetime = 15 * time.Minute
+
+	// InteractionExpiryMargin is how much of the token lifetime must be left
+	// for a delivery to still go through the interaction. It covers the time
+	// the request itself takes and clock drift.
+	InteractionExpiryMargin = time.Minute
+)
+
+// Discord JSON error codes returned for an interaction whose token no longer
+// works.
+const (
+	errCodeUnknownWebhook      = 10015
+	errCodeInvalidWebhookToken = 50027
+)
+
+// InteractionDeliveryClient is the part of *api.Client DeliverFinal sends
+// through.
+type InteractionDeliveryClient interface {
+	EditInteractionResponse(appID discord.AppID, token string, data api.EditInteractionResponseData) (*discord.Message, error)
+	SendMessageComplex(channelID discord.ChannelID, data api.SendMessageData) (*discord.Message, error)
+}
+
+// InteractionTokenExpiring reports whether the token of i has less than
+// InteractionExpiryMargin left at now, judged from the creation time in its
+// ID.
+func InteractionTokenExpiring(i *discord.InteractionEvent, now time.Time) bool {
+	if i == nil || !i.ID.IsValid() {
+		return true
+	}
+	return now.After(i.ID.Time().Add(InteractionTokenLifetime - InteractionExpiryMargin))
+}
+
+// DeliverFinal delivers the final result of a long operation started by i.
+// While the interaction token is valid it edits the interaction response
+// with data; once the token is about to expire, or the edit is rejected
+// because it already has, it posts data in the channel of i instead,
+// mentioning the invoker. It reports whether the channel was used.
+func DeliverFinal(client InteractionDeliveryClient, i *discord.InteractionEvent, data api.EditInteractionResponseData, now time.Time) (viaChannel bool, err error) {
+	if client == nil || i == nil {
+		return false, errors.New("DeliverFinal: nil client or interaction")
+	}
+	if !InteractionTokenExpiring(i, now) {
+		_, err := client.EditInteractionResponse(i.AppID, i.Token, data)
+		if err == nil || !isExpiredTokenError(err) {
+			return false, err
+		}
+	}
+	if !i.ChannelID.IsValid() {
+		return true, errors.New("DeliverFinal: interaction token expired and the interaction has no channel")
+	}
+	if _, err := client.SendMessageComplex(i.ChannelID, channelDelivery(data, i.SenderID())); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// DeliverFinal delivers the final result of a long operation started by the
+// command; see the DeliverFinal function.
+func (c *ArikawaContext) DeliverFinal(data api.EditInteractionResponseData) error {
+	if c.Client == nil {
+		return errors.New("cannot deliver: nil client")
+	}
+	_, err := DeliverFinal(c.Client, c.Interaction, data, time.Now())
+	return err
+}
+
+// channelDelivery turns an interaction response edit into a channel message
+// mentioning userID. Components are left out: the handlers behind them
+// answer the interaction that is gone.
+func channelDelivery(data api.EditInteractionResponseData, userID discord.UserID) api.SendMessageData {
+	out := api.SendMessageData{Files: data.Files}
+	if data.Content != nil {
+		out.Content = data.Content.Val
+	}
+	if data.Embeds != nil {
+		out.Embeds = *data.Embeds
+	}
+	if userID.IsValid() {
+		mention := userID.Mention()
+		if out.Content == "" {
+			out.Content = mention
+		} else {
+			out.Content = mention + " " + out.Content
+		}
+		out.AllowedMentions = &api.AllowedMentions{Users: []discord.UserID{userID}}
+	} else {
+		out.AllowedMentions = &api.AllowedMentions{Parse: []api.AllowedMentionType{}}
+	}
+	return out
+}
+
+func isExpiredTokenError(err error) bool {
+	var httpErr *httputil.HTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	return httpErr.Code == errCodeUnknownWebhook || httpErr.Code == errCodeInvalidWebhookToken
+}
@@ -0,0 +1,100 @@
+package commands_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDeliveryClient struct {
+	editErr error
+	edits   []api.EditInteractionResponseData
+	sent    map[discord.ChannelID][]api.SendMessageData
+}
+
+func (f *fakeDeliveryClient) EditInteractionResponse(_ discord.AppID, _ string, data api.EditInteractionResponseData) (*discord.Message, error) {
+	if f.editErr != nil {
+		return nil, f.editErr
+	}
+	f.edits = append(f.edits, data)
+	return &discord.Message{}, nil
+}
+
+func (f *fakeDeliveryClient) SendMessageComplex(channelID discord.ChannelID, data api.SendMessageData) (*discord.Message, error) {
+	if f.sent == nil {
+		f.sent = make(map[discord.ChannelID][]api.SendMessageData)
+	}
+	f.sent[channelID] = append(f.sent[channelID], data)
+	return &discord.Message{}, nil
+}
+
+func TestDeliverFinal(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	interaction := &discord.InteractionEvent{
+		ID:        discord.InteractionID(discord.NewSnowflake(createdAt)),
+		AppID:     10,
+		ChannelID: 30,
+		Token:     "token",
+		User:      &discord.User{ID: 40},
+	}
+	data := api.EditInteractionResponseData{
+		Content: option.NewNullableString("Export finished."),
+		Embeds:  &[]discord.Embed{{Title: "Export"}},
+	}
+
+	t.Run("token valid", func(t *testing.T) {
+		t.Parallel()
+		client := &fakeDeliveryClient{}
+		viaChannel, err := commands.DeliverFinal(client, interaction, data, createdAt.Add(5*time.Minute))
+		require.NoError(t, err)
+		assert.False(t, viaChannel)
+		assert.Len(t, client.edits, 1)
+		assert.Empty(t, client.sent)
+	})
+
+	t.Run("token about to expire", func(t *testing.T) {
+		t.Parallel()
+		client := &fakeDeliveryClient{}
+		now := createdAt.Add(commands.InteractionTokenLifetime - commands.InteractionExpiryMargin/2)
+		assert.True(t, commands.InteractionTokenExpiring(interaction, now))
+
+		viaChannel, err := commands.DeliverFinal(client, interaction, data, now)
+		require.NoError(t, err)
+		assert.True(t, viaChannel)
+		assert.Empty(t, client.edits)
+		require.Len(t, client.sent[30], 1)
+		msg := client.sent[30][0]
+		assert.Equal(t, "<@40> Export finished.", msg.Content)
+		assert.Equal(t, []discord.UserID{40}, msg.AllowedMentions.Users)
+		assert.Len(t, msg.Embeds, 1)
+	})
+
+	t.Run("edit rejected as expired", func(t *testing.T) {
+		t.Parallel()
+		client := &fakeDeliveryClient{editErr: &httputil.HTTPError{Status: http.StatusUnauthorized, Code: 50027}}
+		viaChannel, err := commands.DeliverFinal(client, interaction, data, createdAt.Add(5*time.Minute))
+		require.NoError(t, err)
+		assert.True(t, viaChannel)
+		assert.Len(t, client.sent[30], 1)
+	})
+
+	t.Run("other edit failures are returned", func(t *testing.T) {
+		t.Parallel()
+		client := &fakeDeliveryClient{editErr: errors.New("connection reset")}
+		viaChannel, err := commands.DeliverFinal(client, interaction, data, createdAt.Add(5*time.Minute))
+		require.Error(t, err)
+		assert.False(t, viaChannel)
+		assert.Empty(t, client.sent)
+	})
+}
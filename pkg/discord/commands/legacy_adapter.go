@@ -33,6 +33,20 @@ func (la *LegacyAdapter) Register(guildID string, botProfileID string) []api.Cre
 	return data
 }
 
+// RegistrationScope fulfills cmd.ScopedCommandGroup, reading the scope of
+// commands that provide one.
+func (la *LegacyAdapter) RegistrationScope(commandName string) cmd.RegistrationScope {
+	for _, c := range la.commands {
+		if c.Name() != commandName {
+			continue
+		}
+		if p, ok := c.(RegistrationScopeProvider); ok {
+			return p.RegistrationScope()
+		}
+	}
+	return cmd.ScopeGlobal
+}
+
 // Handle exposes the O(1) routing dictionary.
 func (la *LegacyAdapter) Handle(guildID string, botProfileID string) map[string]cmd.CommandHandler {
 	m := make(map[string]cmd.CommandHandler)
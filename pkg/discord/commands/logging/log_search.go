@@ -0,0 +1,375 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const (
+	// logSearchRoute prefixes the custom IDs of the /logs search page
+	// buttons. The rest carries the page and the search's filters.
+	logSearchRoute = "logs:search|"
+
+	logSearchPageSize   = 10
+	logSearchTimeout    = 5 * time.Second
+	logSearchDateLayout = "2006-01-02"
+
+	// maxSearchTitle and maxSearchSummary keep a full page within an embed
+	// description.
+	maxSearchTitle   = 100
+	maxSearchSummary = 180
+)
+
+// LogArchive finds archived log events. *postgres.Store satisfies it.
+type LogArchive interface {
+	SearchLogEvents(ctx context.Context, filter logging.ArchiveFilter) ([]logging.ArchivedEvent, error)
+}
+
+// searchableLogEvents are the events /logs search can filter by, in the
+// order the choices are offered.
+var searchableLogEvents = []discord.StringChoice{
+	{Name: "Member joins", Value: string(logging.LogEventMemberJoin)},
+	{Name: "Member leaves", Value: string(logging.LogEventMemberLeave)},
+	{Name: "Avatar changes", Value: string(logging.LogEventAvatarChange)},
+	{Name: "Name changes", Value: string(logging.LogEventNameChange)},
+	{Name: "Role changes", Value: string(logging.LogEventRoleChange)},
+	{Name: "Message edits", Value: string(logging.LogEventMessageEdit)},
+	{Name: "Message deletions", Value: string(logging.LogEventMessageDelete)},
+	{Name: "AutoMod actions", Value: string(logging.LogEventAutomodAction)},
+	{Name: "Moderation actions", Value: string(logging.LogEventModerationCase)},
+	{Name: "Thread changes", Value: string(logging.LogEventThreadChange)},
+	{Name: "Server changes", Value: string(logging.LogEventServerChange)},
+	{Name: "Voice changes", Value: string(logging.LogEventVoiceChange)},
+	{Name: "Asset changes", Value: string(logging.LogEventAssetChange)},
+	{Name: "Presence changes", Value: string(logging.LogEventPresenceChange)},
+}
+
+type logsRootCommand struct {
+	configManager config.Provider
+	archive       LogArchive
+}
+
+func (c *logsRootCommand) Name() string              { return "logs" }
+func (c *logsRootCommand) Description() string       { return "Search the events the bot has logged" }
+func (c *logsRootCommand) RequiresGuild() bool       { return true }
+func (c *logsRootCommand) RequiresPermissions() bool { return true }
+
+func (c *logsRootCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionViewAuditLog
+}
+
+func (c *logsRootCommand) Options() []discord.CommandOption {
+	return []discord.CommandOption{
+		&discord.SubcommandOption{
+			OptionName:  "search",
+			Description: "Find logged events by member, kind, channel or date",
+			Options: []discord.CommandOptionValue{
+				&discord.UserOption{
+					OptionName:  "user",
+					Description: "Events about or caused by this member",
+				},
+				&discord.StringOption{
+					OptionName:  "type",
+					Description: "Only this kind of event",
+					Choices:     searchableLogEvents,
+				},
+				&discord.ChannelOption{
+					OptionName:  "channel",
+					Description: "Events in this channel, or logged to it",
+				},
+				&discord.StringOption{
+					OptionName:  "from",
+					Description: "First day to include, as YYYY-MM-DD",
+				},
+				&discord.StringOption{
+					OptionName:  "to",
+					Description: "Last day to include, as YYYY-MM-DD",
+				},
+			},
+		},
+	}
+}
+
+func (c *logsRootCommand) Handle(ctx *commands.ArikawaContext) error {
+	data, ok := ctx.Interaction.Data.(*discord.CommandInteraction)
+	if !ok || len(data.Options) == 0 || data.Options[0].Name != "search" {
+		return nil
+	}
+	opts := commands.ArikawaOptionList(data.Options[0].Options)
+
+	loc := c.configManager.GuildConfig(ctx.GuildID.String()).Location()
+	search, err := newLogSearch(opts.String("from"), opts.String("to"), loc, time.Now())
+	if err != nil {
+		return ctx.Respond(api.InteractionResponseData{
+			Content: option.NewNullableString(err.Error()),
+			Flags:   discord.EphemeralMessage,
+		})
+	}
+	search.userID = opts.UserID("user")
+	search.eventType = logging.LogEventType(opts.String("type"))
+	search.channelID = opts.ChannelID("channel")
+
+	embed, components, err := runLogSearch(ctx.Context(), c.archive, ctx.GuildID.String(), search)
+	if err != nil {
+		slog.Warn("Mitigated service degradation: Log archive search failed",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("error", err.Error()),
+		)
+		return ctx.Respond(api.InteractionResponseData{
+			Content: option.NewNullableString("The log archive could not be searched. Try again in a moment."),
+			Flags:   discord.EphemeralMessage,
+		})
+	}
+	embeds := []discord.Embed{embed}
+	return ctx.Respond(api.InteractionResponseData{
+		Embeds:     &embeds,
+		Components: &components,
+		Flags:      discord.EphemeralMessage,
+	})
+}
+
+// handleSearchPage turns a /logs search result to the page its button
+// carries.
+func (g *commandGroup) handleSearchPage(ctx *cmd.Context) error {
+	data, ok := ctx.Event.Data.(discord.ComponentInteraction)
+	if !ok {
+		return nil
+	}
+	search, ok := parseLogSearch(string(data.ID()))
+	if !ok {
+		return respondComponentError(ctx, "This search is out of date. Run `/logs search` again.")
+	}
+	// The results are ephemeral, but custom IDs can be replayed by anyone.
+	if !hasPermission(ctx, discord.PermissionViewAuditLog) {
+		return respondComponentError(ctx, "You need the View Audit Log permission to search the logs.")
+	}
+
+	embed, components, err := runLogSearch(ctx.Context, g.archive, ctx.GuildID.String(), search)
+	if err != nil {
+		slog.Warn("Mitigated service degradation: Log archive search failed",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("error", err.Error()),
+		)
+		return respondComponentError(ctx, "The log archive could not be searched. Try again in a moment.")
+	}
+	embeds := []discord.Embed{embed}
+	return ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, api.InteractionResponse{
+		Type: api.UpdateMessage,
+		Data: &api.InteractionResponseData{
+			Embeds:     &embeds,
+			Components: &components,
+		},
+	})
+}
+
+// logSearch is one page of a log archive search. It round-trips through the
+// custom IDs of the page buttons, so later pages keep the same filters.
+type logSearch struct {
+	page      int
+	userID    string
+	eventType logging.LogEventType
+	channelID string
+	since     time.Time
+	until     time.Time
+}
+
+// newLogSearch starts a search over the days from and to, both inclusive
+// and read in loc. Without a last day the search ends at now, so later
+// pages do not shift as new events are logged.
+func newLogSearch(from, to string, loc *time.Location, now time.Time) (logSearch, error) {
+	search := logSearch{until: now.Truncate(time.Second)}
+	if from = strings.TrimSpace(from); from != "" {
+		day, err := time.ParseInLocation(logSearchDateLayout, from, loc)
+		if err != nil {
+			return logSearch{}, fmt.Errorf("`from` must be a date such as %s.", now.In(loc).Format(logSearchDateLayout))
+		}
+		search.since = day
+	}
+	if to = strings.TrimSpace(to); to != "" {
+		day, err := time.ParseInLocation(logSearchDateLayout, to, loc)
+		if err != nil {
+			return logSearch{}, fmt.Errorf("`to` must be a date such as %s.", now.In(loc).Format(logSearchDateLayout))
+		}
+		search.until = day.AddDate(0, 0, 1)
+	}
+	if !search.since.IsZero() && !search.until.After(search.since) {
+		return logSearch{}, errors.New("`from` must not be after `to`.")
+	}
+	return search, nil
+}
+
+func (s logSearch) filter(guildID string) logging.ArchiveFilter {
+	return logging.ArchiveFilter{
+		GuildID:   guildID,
+		UserID:    s.userID,
+		EventType: s.eventType,
+		ChannelID: s.channelID,
+		Since:     s.since,
+		Until:     s.until,
+		Offset:    s.page * logSearchPageSize,
+		// One more than a page tells whether there is a next one.
+		Limit: logSearchPageSize + 1,
+	}
+}
+
+// customID encodes s, turned to page, as a button's custom ID. Times are
+// Unix seconds in base 36 to stay within Discord's 100 characters.
+func (s logSearch) customID(page int) string {
+	return logSearchRoute + strings.Join([]string{
+		strconv.Itoa(page),
+		s.userID,
+		string(s.eventType),
+		s.channelID,
+		encodeSearchTime(s.since),
+		encodeSearchTime(s.until),
+	}, "|")
+}
+
+func parseLogSearch(customID string) (logSearch, bool) {
+	parts := strings.Split(strings.TrimPrefix(customID, logSearchRoute), "|")
+	if len(parts) != 6 {
+		return logSearch{}, false
+	}
+	page, err := strconv.Atoi(parts[0])
+	if err != nil || page < 0 {
+		return logSearch{}, false
+	}
+	since, ok := decodeSearchTime(parts[4])
+	if !ok {
+		return logSearch{}, false
+	}
+	until, ok := decodeSearchTime(parts[5])
+	if !ok {
+		return logSearch{}, false
+	}
+	return logSearch{
+		page:      page,
+		userID:    parts[1],
+		eventType: logging.LogEventType(parts[2]),
+		channelID: parts[3],
+		since:     since,
+		until:     until,
+	}, true
+}
+
+func encodeSearchTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return strconv.FormatInt(t.Unix(), 36)
+}
+
+func decodeSearchTime(s string) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, true
+	}
+	sec, err := strconv.ParseInt(s, 36, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(sec, 0).UTC(), true
+}
+
+func runLogSearch(ctx context.Context, archive LogArchive, guildID string, search logSearch) (discord.Embed, discord.ContainerComponents, error) {
+	ctx, cancel := context.WithTimeout(ctx, logSearchTimeout)
+	defer cancel()
+	events, err := archive.SearchLogEvents(ctx, search.filter(guildID))
+	if err != nil {
+		return discord.Embed{}, nil, fmt.Errorf("search log archive: %w", err)
+	}
+	hasMore := len(events) > logSearchPageSize
+	if hasMore {
+		events = events[:logSearchPageSize]
+	}
+	return renderLogSearch(search, events), renderLogSearchComponents(search, hasMore), nil
+}
+
+func renderLogSearch(search logSearch, events []logging.ArchivedEvent) discord.Embed {
+	var b strings.Builder
+	for _, event := range events {
+		fmt.Fprintf(&b, "%s · `%s` · <#%s>\n**%s**\n",
+			logging.DiscordTimestamp(event.At, logging.TimestampShortDateTime),
+			event.EventType,
+			event.LogChannelID,
+			clipSearchText(event.Title, maxSearchTitle),
+		)
+		if summary := clipSearchText(strings.Join(strings.Fields(event.Summary), " "), maxSearchSummary); summary != "" {
+			b.WriteString("> " + summary + "\n")
+		}
+	}
+	description := b.String()
+	if description == "" {
+		description = "No logged events match this search."
+		if search.page > 0 {
+			description = "No more logged events match this search."
+		}
+	}
+
+	period := "Until " + logging.DiscordTimestamp(search.until, logging.TimestampShortDateTime)
+	if !search.since.IsZero() {
+		period = "From " + logging.DiscordTimestamp(search.since, logging.TimestampShortDateTime) + " until " + logging.DiscordTimestamp(search.until, logging.TimestampShortDateTime)
+	}
+	filters := []string{period}
+	if search.userID != "" {
+		filters = append(filters, "Member: <@"+search.userID+">")
+	}
+	if search.eventType != "" {
+		filters = append(filters, "Type: `"+string(search.eventType)+"`")
+	}
+	if search.channelID != "" {
+		filters = append(filters, "Channel: <#"+search.channelID+">")
+	}
+
+	return discord.Embed{
+		Title:       "Log Search",
+		Description: description,
+		Color:       discord.Color(theme.Info()),
+		Fields:      []discord.EmbedField{{Name: "Filters", Value: strings.Join(filters, "\n")}},
+		Footer:      &discord.EmbedFooter{Text: fmt.Sprintf("Page %d", search.page+1)},
+	}
+}
+
+func renderLogSearchComponents(search logSearch, hasMore bool) discord.ContainerComponents {
+	if search.page == 0 && !hasMore {
+		return discord.ContainerComponents{}
+	}
+	return discord.ContainerComponents{
+		&discord.ActionRowComponent{
+			&discord.ButtonComponent{
+				Label:    "Previous",
+				CustomID: discord.ComponentID(search.customID(max(search.page-1, 0))),
+				Style:    discord.SecondaryButtonStyle(),
+				Disabled: search.page == 0,
+			},
+			&discord.ButtonComponent{
+				Label:    "Next",
+				CustomID: discord.ComponentID(search.customID(search.page + 1)),
+				Style:    discord.SecondaryButtonStyle(),
+				Disabled: !hasMore,
+			},
+		},
+	}
+}
+
+// clipSearchText cuts s to limit characters.
+func clipSearchText(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit-1]) + "…"
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+)
+
+func TestNewLogSearch(t *testing.T) {
+	t.Parallel()
+	loc := time.FixedZone("UTC-3", -3*60*60)
+	now := time.Date(2026, 5, 10, 12, 0, 0, 500, time.UTC)
+
+	search, err := newLogSearch("2026-05-01", "2026-05-03", loc, now)
+	if err != nil {
+		t.Fatalf("newLogSearch: %v", err)
+	}
+	if want := time.Date(2026, 5, 1, 3, 0, 0, 0, time.UTC); !search.since.Equal(want) {
+		t.Fatalf("expected the search to start at local midnight %v, got %v", want, search.since)
+	}
+	if want := time.Date(2026, 5, 4, 3, 0, 0, 0, time.UTC); !search.until.Equal(want) {
+		t.Fatalf("expected the last day to be included up to %v, got %v", want, search.until)
+	}
+
+	search, err = newLogSearch("", "", loc, now)
+	if err != nil || !search.since.IsZero() || !search.until.Equal(now.Truncate(time.Second)) {
+		t.Fatalf("expected an open search to end now, got %+v, %v", search, err)
+	}
+	if _, err := newLogSearch("05/01/2026", "", loc, now); err == nil {
+		t.Fatal("expected a malformed date to be rejected")
+	}
+	if _, err := newLogSearch("2026-05-04", "2026-05-03", loc, now); err == nil {
+		t.Fatal("expected a range ending before it starts to be rejected")
+	}
+}
+
+func TestLogSearchCustomID(t *testing.T) {
+	t.Parallel()
+	search := logSearch{
+		userID:    "1296000000000000007",
+		eventType: logging.LogEventModerationCase,
+		channelID: "1296000000000000008",
+		since:     time.Date(2026, 5, 1, 3, 0, 0, 0, time.UTC),
+		until:     time.Date(2026, 5, 4, 3, 0, 0, 0, time.UTC),
+	}
+	id := search.customID(12)
+	if len(id) > 100 {
+		t.Fatalf("custom ID %q exceeds Discord's 100 character limit", id)
+	}
+	got, ok := parseLogSearch(id)
+	search.page = 12
+	if !ok || got != search {
+		t.Fatalf("expected %+v back from %q, got %+v", search, id, got)
+	}
+
+	open, ok := parseLogSearch(logSearch{until: search.until}.customID(0))
+	if !ok || !open.since.IsZero() || open.userID != "" || !open.until.Equal(search.until) {
+		t.Fatalf("expected an unfiltered search to round-trip, got %+v", open)
+	}
+	for _, bad := range []string{logSearchRoute + "x|||||", logSearchRoute + "1|2", logSearchRoute + "-1|||||"} {
+		if _, ok := parseLogSearch(bad); ok {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestRenderLogSearch(t *testing.T) {
+	t.Parallel()
+	search := logSearch{page: 1, userID: "7", until: time.Unix(1_800_000_000, 0)}
+	embed := renderLogSearch(search, []logging.ArchivedEvent{{
+		At:           time.Unix(1_790_000_000, 0),
+		EventType:    logging.LogEventMessageDelete,
+		LogChannelID: "50",
+		Title:        "Message Deleted",
+		Summary:      "User: <@7>\nMessage: " + strings.Repeat("a", 400),
+	}})
+	if !strings.Contains(embed.Description, "<t:1790000000:f> · `message_delete` · <#50>\n**Message Deleted**\n> User: <@7> Message: aaa") {
+		t.Fatalf("unexpected result line:\n%s", embed.Description)
+	}
+	if !strings.Contains(embed.Fields[0].Value, "Member: <@7>") || embed.Footer.Text != "Page 2" {
+		t.Fatalf("unexpected filters or footer: %+v", embed)
+	}
+
+	if rows := renderLogSearchComponents(logSearch{}, false); len(rows) != 0 {
+		t.Fatalf("expected no buttons for a single page, got %d rows", len(rows))
+	}
+	row := *renderLogSearchComponents(logSearch{}, true)[0].(*discord.ActionRowComponent)
+	prev, next := row[0].(*discord.ButtonComponent), row[1].(*discord.ButtonComponent)
+	if !prev.Disabled || next.Disabled || !strings.HasPrefix(string(next.CustomID), logSearchRoute+"1|") {
+		t.Fatalf("unexpected first page buttons: %+v, %+v", prev, next)
+	}
+}
@@ -3,6 +3,8 @@ package logging
 import (
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
@@ -11,6 +13,7 @@ import (
 	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
 	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
 	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
 )
 
 // LoggingCommands wiring.
@@ -18,11 +21,44 @@ type LoggingCommands struct {
 	configManager config.Provider
 }
 
+// Option configures the logging command group.
+type Option func(*commandGroup)
+
+// WithLogArchive adds /logs search over the log events kept in archive.
+func WithLogArchive(archive LogArchive) Option {
+	return func(g *commandGroup) { g.archive = archive }
+}
+
 // NewLoggingCommands returns the root logging command tree.
-func NewLoggingCommands(configManager config.Provider) cmd.CommandGroup {
-	return commands.NewLegacyAdapter(&loggingRootCommand{
-		configManager: configManager,
-	})
+func NewLoggingCommands(configManager config.Provider, opts ...Option) cmd.CommandGroup {
+	g := &commandGroup{configManager: configManager}
+	for _, opt := range opts {
+		opt(g)
+	}
+	cmds := []commands.ArikawaCommand{&loggingRootCommand{configManager: configManager}}
+	if g.archive != nil {
+		cmds = append(cmds, &logsRootCommand{configManager: configManager, archive: g.archive})
+	}
+	g.CommandGroup = commands.NewLegacyAdapter(cmds...)
+	return g
+}
+
+// commandGroup adds the route panel's and the log search's components to
+// the slash commands.
+type commandGroup struct {
+	cmd.CommandGroup
+	configManager config.Provider
+	archive       LogArchive
+}
+
+// Handle handles.
+func (g *commandGroup) Handle(guildID string, botProfileID string) map[string]cmd.CommandHandler {
+	routes := g.CommandGroup.Handle(guildID, botProfileID)
+	routes[logRouteRoute] = g.handleRouteComponent
+	if g.archive != nil {
+		routes[logSearchRoute] = g.handleSearchPage
+	}
+	return routes
 }
 
 // RegisterCommands is deprecated.
@@ -141,6 +177,118 @@ func (c *loggingRootCommand) Options() []discord.CommandOption {
 				},
 			},
 		},
+		&discord.SubcommandOption{
+			OptionName:  "display_names",
+			Description: "Choose which user name log messages show",
+			Options: []discord.CommandOptionValue{
+				&discord.StringOption{
+					OptionName:  "style",
+					Description: "Name to show for users",
+					Required:    true,
+					Choices: []discord.StringChoice{
+						{Name: "Server nickname, then display name", Value: string(logging.DisplayNameNickname)},
+						{Name: "Display name", Value: string(logging.DisplayNameGlobal)},
+						{Name: "Username", Value: string(logging.DisplayNameUsername)},
+					},
+				},
+			},
+		},
+		&discord.SubcommandOption{
+			OptionName:  "threads",
+			Description: "Configure thread creation, archive and deletion logging",
+			Options: []discord.CommandOptionValue{
+				&discord.ChannelOption{
+					OptionName:   "channel",
+					Description:  "Channel to send thread logs to",
+					Required:     true,
+					ChannelTypes: []discord.ChannelType{discord.GuildText},
+				},
+				&discord.BooleanOption{
+					OptionName:  "auto_join",
+					Description: "Join new threads so edits and deletions in them are logged too",
+					Required:    false,
+				},
+			},
+		},
+		&discord.SubcommandOption{
+			OptionName:  "user_threads",
+			Description: "Collect the logs about each member in a thread of their own",
+			Options: []discord.CommandOptionValue{
+				&discord.BooleanOption{
+					OptionName:  "enabled",
+					Description: "Route avatar, role, name and moderation logs into a thread per member",
+					Required:    true,
+				},
+			},
+		},
+		&discord.SubcommandOption{
+			OptionName:  "webhooks",
+			Description: "Post logs through a webhook in each log channel, under a name and avatar of your choice",
+			Options: []discord.CommandOptionValue{
+				&discord.BooleanOption{
+					OptionName:  "enabled",
+					Description: "Post logs through webhooks instead of as the bot",
+					Required:    true,
+				},
+				&discord.StringOption{
+					OptionName:  "username",
+					Description: "Name log messages are posted under; leave empty to keep the current one",
+					Required:    false,
+					MaxLength:   option.NewInt(80),
+				},
+				&discord.StringOption{
+					OptionName:  "avatar_url",
+					Description: "Image URL for the avatar log messages are posted with",
+					Required:    false,
+				},
+			},
+		},
+		&discord.SubcommandOption{
+			OptionName:  "language",
+			Description: "Choose the language log messages are written in",
+			Options: []discord.CommandOptionValue{
+				&discord.StringOption{
+					OptionName:  "language",
+					Description: "Language for log messages",
+					Required:    true,
+					Choices: []discord.StringChoice{
+						{Name: "English", Value: string(logging.LogLanguageEnglish)},
+						{Name: "Português (Brasil)", Value: string(logging.LogLanguagePortuguese)},
+					},
+				},
+				&discord.StringOption{
+					OptionName:  "event",
+					Description: "Only change this kind of log; leave empty for all without their own language",
+					Required:    false,
+					Choices: []discord.StringChoice{
+						{Name: "Member joins", Value: string(logging.LogEventMemberJoin)},
+						{Name: "Member leaves", Value: string(logging.LogEventMemberLeave)},
+						{Name: "Avatar changes", Value: string(logging.LogEventAvatarChange)},
+						{Name: "Name changes", Value: string(logging.LogEventNameChange)},
+						{Name: "Role changes", Value: string(logging.LogEventRoleChange)},
+						{Name: "Message edits", Value: string(logging.LogEventMessageEdit)},
+						{Name: "Message deletions", Value: string(logging.LogEventMessageDelete)},
+						{Name: "AutoMod actions", Value: string(logging.LogEventAutomodAction)},
+						{Name: "Moderation actions", Value: string(logging.LogEventModerationCase)},
+					},
+				},
+			},
+		},
+		&discord.SubcommandOption{
+			OptionName:  "route",
+			Description: "Send each kind of log to its own channel, or turn it off",
+		},
+		&discord.SubcommandOption{
+			OptionName:  "timezone",
+			Description: "Set the time zone exports give times in",
+			Options: []discord.CommandOptionValue{
+				&discord.StringOption{
+					OptionName:  "zone",
+					Description: "IANA time zone, such as Europe/Berlin; leave empty for UTC",
+					Required:    false,
+				},
+			},
+		},
 	}
 }
 
@@ -167,6 +315,20 @@ func (c *loggingRootCommand) Handle(ctx *commands.ArikawaContext) error {
 		return c.handleExit(ctx, subcommand.Options)
 	case "warnings":
 		return c.handleWarnings(ctx, subcommand.Options)
+	case "display_names":
+		return c.handleDisplayNames(ctx, subcommand.Options)
+	case "threads":
+		return c.handleThreads(ctx, subcommand.Options)
+	case "user_threads":
+		return c.handleUserThreads(ctx, subcommand.Options)
+	case "webhooks":
+		return c.handleWebhooks(ctx, subcommand.Options)
+	case "language":
+		return c.handleLanguage(ctx, subcommand.Options)
+	case "route":
+		return c.handleRoute(ctx)
+	case "timezone":
+		return c.handleTimezone(ctx, subcommand.Options)
 	}
 	return nil
 }
@@ -372,3 +534,160 @@ func (c *loggingRootCommand) handleWarnings(ctx *commands.ArikawaContext, opts [
 		Content: option.NewNullableString("Moderation action logs will now be sent to <#" + channelID + ">\nScope: `" + scope + "`"),
 	})
 }
+
+func (c *loggingRootCommand) handleDisplayNames(ctx *commands.ArikawaContext, opts []discord.CommandInteractionOption) error {
+	style := logging.ParseDisplayNameStyle(commands.ArikawaOptionList(opts).String("style"))
+
+	err := c.configManager.UpdateGuildConfig(ctx.GuildID.String(), func(cfg *files.GuildConfig) error {
+		cfg.DisplayNameStyle = string(style)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Operational telemetry: Logging display name style updated", slog.String("style", string(style)))
+	return ctx.Respond(api.InteractionResponseData{
+		Content: option.NewNullableString("Log messages will now show users by `" + string(style) + "`."),
+	})
+}
+
+func (c *loggingRootCommand) handleThreads(ctx *commands.ArikawaContext, opts []discord.CommandInteractionOption) error {
+	parsedOpts := commands.ArikawaOptionList(opts)
+	channelID := parsedOpts.ChannelID("channel")
+	autoJoin := parsedOpts.Bool("auto_join")
+
+	err := c.configManager.UpdateGuildConfig(ctx.GuildID.String(), func(cfg *files.GuildConfig) error {
+		cfg.Channels.ThreadLogging = channelID
+		if parsedOpts.HasOption("auto_join") {
+			cfg.ThreadAutoJoin = autoJoin
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Operational telemetry: Logging channel updated", slog.String("channel_id", channelID))
+	content := "Thread logs will now be sent to <#" + channelID + ">"
+	if parsedOpts.HasOption("auto_join") {
+		content += fmt.Sprintf("\nAuto-join new threads: `%t`", autoJoin)
+	}
+	return ctx.Respond(api.InteractionResponseData{
+		Content: option.NewNullableString(content),
+	})
+}
+
+func (c *loggingRootCommand) handleUserThreads(ctx *commands.ArikawaContext, opts []discord.CommandInteractionOption) error {
+	enabled := commands.ArikawaOptionList(opts).Bool("enabled")
+
+	err := c.configManager.UpdateGuildConfig(ctx.GuildID.String(), func(cfg *files.GuildConfig) error {
+		cfg.UserLogThreads = enabled
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Operational telemetry: Member log threads updated", slog.Bool("enabled", enabled))
+	content := "Member logs will now be sent to the log channels directly."
+	if enabled {
+		content = "Avatar, role, name and moderation logs will now go to a thread per member under their log channel."
+	}
+	return ctx.Respond(api.InteractionResponseData{
+		Content: option.NewNullableString(content),
+	})
+}
+
+func (c *loggingRootCommand) handleWebhooks(ctx *commands.ArikawaContext, opts []discord.CommandInteractionOption) error {
+	parsedOpts := commands.ArikawaOptionList(opts)
+	enabled := parsedOpts.Bool("enabled")
+
+	var hooks files.LogWebhookConfig
+	err := c.configManager.UpdateGuildConfig(ctx.GuildID.String(), func(cfg *files.GuildConfig) error {
+		cfg.LogWebhooks.Enabled = enabled
+		if parsedOpts.HasOption("username") {
+			cfg.LogWebhooks.Username = strings.TrimSpace(parsedOpts.String("username"))
+		}
+		if parsedOpts.HasOption("avatar_url") {
+			cfg.LogWebhooks.AvatarURL = strings.TrimSpace(parsedOpts.String("avatar_url"))
+		}
+		hooks = cfg.LogWebhooks
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Operational telemetry: Log webhooks updated", slog.Bool("enabled", enabled))
+	content := "Logs will now be posted as the bot."
+	if enabled {
+		content = "Logs will now be posted through a webhook in each log channel. The bot needs Manage Webhooks there; until it has it, logs are posted as the bot."
+		if hooks.Username != "" {
+			content += "\nName: `" + hooks.Username + "`"
+		}
+		if hooks.AvatarURL != "" {
+			content += "\nAvatar: <" + hooks.AvatarURL + ">"
+		}
+	}
+	return ctx.Respond(api.InteractionResponseData{
+		Content: option.NewNullableString(content),
+	})
+}
+
+func (c *loggingRootCommand) handleLanguage(ctx *commands.ArikawaContext, opts []discord.CommandInteractionOption) error {
+	parsedOpts := commands.ArikawaOptionList(opts)
+	language := logging.ParseLogLanguage(parsedOpts.String("language"))
+	key := parsedOpts.String("event")
+	if key == "" {
+		key = logging.LogLanguageDefaultKey
+	}
+
+	err := c.configManager.UpdateGuildConfig(ctx.GuildID.String(), func(cfg *files.GuildConfig) error {
+		if cfg.LogLanguages == nil {
+			cfg.LogLanguages = make(map[string]string)
+		}
+		cfg.LogLanguages[key] = string(language)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Operational telemetry: Logging language updated", slog.String("event", key), slog.String("language", string(language)))
+	scope := "Log messages"
+	if key != logging.LogLanguageDefaultKey {
+		scope = "`" + key + "` log messages"
+	}
+	return ctx.Respond(api.InteractionResponseData{
+		Content: option.NewNullableString(scope + " will now be written in `" + string(language) + "`."),
+	})
+}
+
+func (c *loggingRootCommand) handleTimezone(ctx *commands.ArikawaContext, opts []discord.CommandInteractionOption) error {
+	zone := strings.TrimSpace(commands.ArikawaOptionList(opts).String("zone"))
+	if zone != "" {
+		if _, err := time.LoadLocation(zone); err != nil {
+			return ctx.Respond(api.InteractionResponseData{
+				Content: option.NewNullableString("Unknown time zone `" + logging.EscapeMarkdown(zone) + "`. Use an IANA name such as `Europe/Berlin`."),
+				Flags:   discord.EphemeralMessage,
+			})
+		}
+	}
+
+	err := c.configManager.UpdateGuildConfig(ctx.GuildID.String(), func(cfg *files.GuildConfig) error {
+		cfg.Timezone = zone
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Operational telemetry: Guild time zone updated", slog.String("timezone", zone))
+	if zone == "" {
+		zone = "UTC"
+	}
+	return ctx.Respond(api.InteractionResponseData{
+		Content: option.NewNullableString("Exports will now give times in `" + zone + "`."),
+	})
+}
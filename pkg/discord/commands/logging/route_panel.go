@@ -0,0 +1,228 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/permissions"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const (
+	// logRouteRoute prefixes the custom IDs of the /logging route panel. The
+	// rest names the control and, past a second "|", the event it edits.
+	logRouteRoute = "logging:route|"
+
+	routeActionEvent   = "event"
+	routeActionChannel = "channel"
+	routeActionDisable = "disable"
+	routeActionReset   = "reset"
+)
+
+func (c *loggingRootCommand) handleRoute(ctx *commands.ArikawaContext) error {
+	gcfg := c.configManager.GuildConfig(ctx.GuildID.String())
+	embeds := []discord.Embed{renderRouteEmbed(gcfg, "")}
+	components := renderRouteComponents("")
+	return ctx.Respond(api.InteractionResponseData{
+		Embeds:     &embeds,
+		Components: &components,
+		Flags:      discord.EphemeralMessage,
+	})
+}
+
+// handleRouteComponent applies a change made in the route panel and redraws
+// it in place.
+func (g *commandGroup) handleRouteComponent(ctx *cmd.Context) error {
+	data, ok := ctx.Event.Data.(discord.ComponentInteraction)
+	if !ok {
+		return nil
+	}
+	action, event, _ := strings.Cut(strings.TrimPrefix(string(data.ID()), logRouteRoute), "|")
+	if sel, ok := data.(*discord.StringSelectInteraction); ok && action == routeActionEvent && len(sel.Values) > 0 {
+		event = sel.Values[0]
+	}
+	eventType := logging.LogEventType(event)
+	if !slices.Contains(logging.RoutableLogEvents(), eventType) {
+		return respondComponentError(ctx, "This panel is out of date. Run `/logging route` again.")
+	}
+
+	if action != routeActionEvent {
+		// The panel is ephemeral, but custom IDs can be replayed by anyone.
+		if !hasPermission(ctx, discord.PermissionManageGuild) {
+			return respondComponentError(ctx, "You need the Manage Server permission to change log routing.")
+		}
+		var route string
+		switch action {
+		case routeActionChannel:
+			sel, ok := data.(*discord.ChannelSelectInteraction)
+			if !ok || len(sel.Values) == 0 {
+				return nil
+			}
+			route = sel.Values[0].String()
+		case routeActionDisable:
+			route = logging.LogRouteDisabled
+		case routeActionReset:
+		default:
+			return respondComponentError(ctx, "This panel is out of date. Run `/logging route` again.")
+		}
+		err := g.configManager.UpdateGuildConfig(ctx.GuildID.String(), func(cfg *files.GuildConfig) error {
+			setLogRoute(&cfg.Channels, eventType, route)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("logging route: update guild config: %w", err)
+		}
+		slog.Info("Operational telemetry: Log route updated",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("event", event),
+			slog.String("route", route),
+		)
+	}
+
+	gcfg := g.configManager.GuildConfig(ctx.GuildID.String())
+	embeds := []discord.Embed{renderRouteEmbed(gcfg, eventType)}
+	components := renderRouteComponents(eventType)
+	return ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, api.InteractionResponse{
+		Type: api.UpdateMessage,
+		Data: &api.InteractionResponseData{
+			Embeds:     &embeds,
+			Components: &components,
+		},
+	})
+}
+
+// setLogRoute sends eventType to route, a channel ID or
+// logging.LogRouteDisabled. An empty route drops the override so the event
+// goes back to its shared channel.
+func setLogRoute(channels *files.ChannelsConfig, eventType logging.LogEventType, route string) {
+	if route == "" {
+		delete(channels.LogRoutes, string(eventType))
+		if len(channels.LogRoutes) == 0 {
+			channels.LogRoutes = nil
+		}
+		return
+	}
+	if channels.LogRoutes == nil {
+		channels.LogRoutes = make(map[string]string)
+	}
+	channels.LogRoutes[string(eventType)] = route
+}
+
+// hasPermission reports whether the member behind a component interaction
+// holds perm in its channel.
+func hasPermission(ctx *cmd.Context, perm discord.Permissions) bool {
+	if ctx.Client == nil {
+		return false
+	}
+	res, err := permissions.ResolveInChannel(ctx.Client, ctx.GuildID, ctx.UserID, ctx.Event.ChannelID)
+	if err != nil {
+		slog.Warn("Mitigated service degradation: Could not resolve member permissions for a logging component",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+	return permissions.Has(res.Effective, int64(perm))
+}
+
+func respondComponentError(ctx *cmd.Context, message string) error {
+	return ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, api.InteractionResponse{
+		Type: api.MessageInteractionWithSource,
+		Data: &api.InteractionResponseData{
+			Content: option.NewNullableString(message),
+			Flags:   discord.EphemeralMessage,
+		},
+	})
+}
+
+// renderRouteEmbed lists where every routable event is sent. Events without
+// an override of their own are marked as using their shared channel.
+func renderRouteEmbed(gcfg *files.GuildConfig, selected logging.LogEventType) discord.Embed {
+	var routes map[string]string
+	if gcfg != nil {
+		routes = gcfg.Channels.LogRoutes
+	}
+	var b strings.Builder
+	for _, eventType := range logging.RoutableLogEvents() {
+		marker := ""
+		if eventType == selected {
+			marker = "▸ "
+		}
+		target := "not logged"
+		if channelID := logging.ResolveGuildLogChannel(eventType, gcfg); channelID != "" {
+			target = "<#" + channelID + ">"
+		}
+		switch route := routes[string(eventType)]; {
+		case route == logging.LogRouteDisabled:
+			target = "disabled"
+		case route == "" && target != "not logged":
+			target += " (shared)"
+		}
+		fmt.Fprintf(&b, "%s`%s` → %s\n", marker, eventType, target)
+	}
+	description := "Pick an event to send it to a channel of its own or turn it off."
+	if selected != "" {
+		description = "Choose a channel for `" + string(selected) + "`, turn it off, or send it back to its shared channel."
+	}
+	return discord.Embed{
+		Title:       "Log Routing",
+		Description: description,
+		Color:       discord.Color(theme.Info()),
+		Fields:      []discord.EmbedField{{Name: "Events", Value: b.String()}},
+	}
+}
+
+func renderRouteComponents(selected logging.LogEventType) discord.ContainerComponents {
+	events := logging.RoutableLogEvents()
+	options := make([]discord.SelectOption, 0, len(events))
+	for _, eventType := range events {
+		options = append(options, discord.SelectOption{
+			Label:   string(eventType),
+			Value:   string(eventType),
+			Default: eventType == selected,
+		})
+	}
+	components := discord.ContainerComponents{
+		&discord.ActionRowComponent{
+			&discord.StringSelectComponent{
+				CustomID:    discord.ComponentID(logRouteRoute + routeActionEvent),
+				Options:     options,
+				Placeholder: "Select an event",
+			},
+		},
+	}
+	if selected == "" {
+		return components
+	}
+	suffix := "|" + string(selected)
+	return append(components,
+		&discord.ActionRowComponent{
+			&discord.ChannelSelectComponent{
+				CustomID:     discord.ComponentID(logRouteRoute + routeActionChannel + suffix),
+				Placeholder:  "Send " + string(selected) + " to…",
+				ChannelTypes: []discord.ChannelType{discord.GuildText},
+			},
+		},
+		&discord.ActionRowComponent{
+			&discord.ButtonComponent{
+				Label:    "Disable",
+				CustomID: discord.ComponentID(logRouteRoute + routeActionDisable + suffix),
+				Style:    discord.DangerButtonStyle(),
+			},
+			&discord.ButtonComponent{
+				Label:    "Use shared channel",
+				CustomID: discord.ComponentID(logRouteRoute + routeActionReset + suffix),
+				Style:    discord.SecondaryButtonStyle(),
+			},
+		},
+	)
+}
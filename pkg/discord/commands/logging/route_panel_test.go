@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+)
+
+func TestSetLogRoute(t *testing.T) {
+	t.Parallel()
+	var channels files.ChannelsConfig
+	setLogRoute(&channels, logging.LogEventNameChange, "55")
+	setLogRoute(&channels, logging.LogEventMemberLeave, logging.LogRouteDisabled)
+	if channels.LogRoutes["name_change"] != "55" || channels.LogRoutes["member_leave"] != logging.LogRouteDisabled {
+		t.Fatalf("unexpected routes %v", channels.LogRoutes)
+	}
+	setLogRoute(&channels, logging.LogEventNameChange, "")
+	setLogRoute(&channels, logging.LogEventMemberLeave, "")
+	if channels.LogRoutes != nil {
+		t.Fatalf("expected resetting every event to drop the map, got %v", channels.LogRoutes)
+	}
+}
+
+func TestRenderRoutePanel(t *testing.T) {
+	t.Parallel()
+	gcfg := &files.GuildConfig{Channels: files.ChannelsConfig{
+		AvatarLogging: "10",
+		LogRoutes: map[string]string{
+			string(logging.LogEventNameChange):  "20",
+			string(logging.LogEventMemberLeave): logging.LogRouteDisabled,
+		},
+	}}
+	events := renderRouteEmbed(gcfg, "").Fields[0].Value
+	for _, want := range []string{"`avatar_change` → <#10> (shared)", "`name_change` → <#20>\n", "`member_leave` → disabled", "`role_change` → not logged"} {
+		if !strings.Contains(events, want) {
+			t.Errorf("expected %q in the event list:\n%s", want, events)
+		}
+	}
+
+	if got := len(renderRouteComponents("")); got != 1 {
+		t.Fatalf("expected only the event select before an event is picked, got %d rows", got)
+	}
+	rows := renderRouteComponents(logging.LogEventNameChange)
+	if len(rows) != 3 {
+		t.Fatalf("expected the event, channel and button rows, got %d", len(rows))
+	}
+	channelSelect := (*rows[1].(*discord.ActionRowComponent))[0].(*discord.ChannelSelectComponent)
+	if channelSelect.CustomID != "logging:route|channel|name_change" || len(channelSelect.CustomID) > 100 {
+		t.Fatalf("unexpected channel select ID %q", channelSelect.CustomID)
+	}
+	for _, eventType := range logging.RoutableLogEvents() {
+		if id := logRouteRoute + routeActionDisable + "|" + string(eventType); len(id) > 100 {
+			t.Fatalf("custom ID %q exceeds Discord's 100 character limit", id)
+		}
+	}
+}
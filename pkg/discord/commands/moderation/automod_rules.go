@@ -0,0 +1,366 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+const (
+	automodKindRegex = "regex"
+	automodKindWords = "words"
+)
+
+var (
+	errAutomodRuleExists  = errors.New("automod rule already exists")
+	errAutomodRuleLimit   = errors.New("automod rule limit reached")
+	errAutomodRuleMissing = errors.New("automod rule not found")
+)
+
+// flagChannelTypes are the channels a rule can post flags to.
+var flagChannelTypes = []discord.ChannelType{discord.GuildText, discord.GuildAnnouncement}
+
+// AutomodCommand encapsulates the `/automod` slash command execution, which
+// manages the content rules the bot checks every message against.
+type AutomodCommand struct {
+	metrics Metrics
+	logger  *slog.Logger
+}
+
+func (c *AutomodCommand) Name() string        { return "automod" }
+func (c *AutomodCommand) Description() string { return "Manage the bot's message content rules" }
+func (c *AutomodCommand) Options() []discord.CommandOption {
+	nameOption := &discord.StringOption{
+		OptionName:  "name",
+		Description: "Rule name",
+		Required:    true,
+		MaxLength:   option.NewInt(32),
+	}
+	exemptOptions := func(verb string) []discord.CommandOptionValue {
+		return []discord.CommandOptionValue{
+			nameOption,
+			&discord.RoleOption{OptionName: "role", Description: "Role whose holders to " + verb},
+			&discord.ChannelOption{OptionName: "channel", Description: "Channel or category to " + verb},
+		}
+	}
+	return []discord.CommandOption{
+		&discord.SubcommandOption{
+			OptionName:  "add",
+			Description: "Add a rule matching a regular expression or a list of words",
+			Options: []discord.CommandOptionValue{
+				nameOption,
+				&discord.StringOption{
+					OptionName:  "kind",
+					Description: "How to read the pattern",
+					Required:    true,
+					Choices: []discord.StringChoice{
+						{Name: "Regular expression", Value: automodKindRegex},
+						{Name: "Words, separated by commas", Value: automodKindWords},
+					},
+				},
+				&discord.StringOption{
+					OptionName:  "pattern",
+					Description: "Regular expression or comma-separated words to match",
+					Required:    true,
+					MaxLength:   option.NewInt(files.MaxAutomodPatternLength),
+				},
+				&discord.BooleanOption{OptionName: "delete", Description: "Delete matching messages (default true)"},
+				&discord.BooleanOption{OptionName: "warn", Description: "Warn the author"},
+				&discord.IntegerOption{
+					OptionName:  "timeout_minutes",
+					Description: "Time the author out for this long",
+					Min:         option.NewInt(1),
+					Max:         option.NewInt(files.MaxAutomodTimeoutMinutes),
+				},
+				&discord.ChannelOption{
+					OptionName:   "flag_channel",
+					Description:  "Post matching messages here for review",
+					ChannelTypes: flagChannelTypes,
+				},
+			},
+		},
+		&discord.SubcommandOption{
+			OptionName:  "remove",
+			Description: "Remove a rule",
+			Options:     []discord.CommandOptionValue{nameOption},
+		},
+		&discord.SubcommandOption{
+			OptionName:  "list",
+			Description: "Show the rules",
+		},
+		&discord.SubcommandOption{
+			OptionName:  "exempt",
+			Description: "Exempt a role or channel from a rule",
+			Options:     exemptOptions("exempt"),
+		},
+		&discord.SubcommandOption{
+			OptionName:  "unexempt",
+			Description: "Lift the exemption of a role or channel",
+			Options:     exemptOptions("check again"),
+		},
+	}
+}
+
+func (c *AutomodCommand) RequiresGuild() bool       { return true }
+func (c *AutomodCommand) RequiresPermissions() bool { return true }
+func (c *AutomodCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionManageGuild
+}
+
+func (c *AutomodCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("automod")
+
+	if ctx.Interaction == nil || ctx.Interaction.Data == nil || ctx.Interaction.Data.InteractionType() != discord.CommandInteractionType {
+		return nil
+	}
+	cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
+	if len(cmdData.Options) == 0 {
+		return respondEphemeral(ctx, "Choose add, remove, list, exempt or unexempt.")
+	}
+	sub := cmdData.Options[0]
+	if sub.Name == "list" {
+		var rules []files.AutomodRule
+		if ctx.GuildConfig != nil {
+			rules = ctx.GuildConfig.AutomodRules
+		}
+		return respondEphemeral(ctx, automodRuleList(rules))
+	}
+	if ctx.Config == nil {
+		return respondEphemeral(ctx, "Configuration is unavailable; nothing was changed.")
+	}
+
+	var name, roleID, channelID string
+	for _, opt := range sub.Options {
+		switch opt.Name {
+		case "name":
+			name = strings.TrimSpace(opt.String())
+		case "role", "channel":
+			val, err := opt.SnowflakeValue()
+			if err != nil || !val.IsValid() {
+				continue
+			}
+			if opt.Name == "role" {
+				roleID = val.String()
+			} else {
+				channelID = val.String()
+			}
+		}
+	}
+
+	var rule files.AutomodRule
+	if sub.Name == "add" {
+		var err error
+		if rule, err = automodRuleFromOptions(sub.Options); err != nil {
+			return respondEphemeral(ctx, fmt.Sprintf("Nothing was changed: %v.", err))
+		}
+	}
+	if (sub.Name == "exempt" || sub.Name == "unexempt") && roleID == "" && channelID == "" {
+		return respondEphemeral(ctx, "Choose a role or a channel.")
+	}
+
+	changed := true
+	err := ctx.Config.UpdateGuildConfig(ctx.GuildID.String(), func(cfg *files.GuildConfig) error {
+		switch sub.Name {
+		case "add":
+			return addAutomodRule(cfg, rule)
+		case "remove":
+			return removeAutomodRule(cfg, name)
+		default:
+			idx := slices.IndexFunc(cfg.AutomodRules, func(r files.AutomodRule) bool { return r.Name == name })
+			if idx < 0 {
+				return errAutomodRuleMissing
+			}
+			changed = setAutomodExemption(&cfg.AutomodRules[idx], roleID, channelID, sub.Name == "exempt")
+			return nil
+		}
+	})
+	switch {
+	case errors.Is(err, errAutomodRuleExists):
+		return respondEphemeral(ctx, fmt.Sprintf("A rule named `%s` already exists. Remove it first to replace it.", rule.Name))
+	case errors.Is(err, errAutomodRuleLimit):
+		return respondEphemeral(ctx, fmt.Sprintf("This server already has %d rules, the most allowed.", files.MaxAutomodRules))
+	case errors.Is(err, errAutomodRuleMissing):
+		return respondEphemeral(ctx, fmt.Sprintf("There is no rule named `%s`.", name))
+	case err != nil:
+		c.logger.Error("Blocking structural failure: Automod rules could not be saved",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("action", sub.Name),
+			slog.String("error", err.Error()),
+		)
+		return respondEphemeral(ctx, "Failed to save the automod rules.")
+	}
+	if !changed {
+		return respondEphemeral(ctx, "Nothing was changed.")
+	}
+
+	c.logger.Info("Architectural state transition: Automod rules updated",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("action", sub.Name),
+		slog.String("rule", name),
+		slog.String("user_id", ctx.UserID.String()),
+	)
+	switch sub.Name {
+	case "add":
+		return respondEphemeral(ctx, fmt.Sprintf("Added rule `%s`: %s.", rule.Name, automodRuleActions(rule)))
+	case "remove":
+		return respondEphemeral(ctx, fmt.Sprintf("Removed rule `%s`.", name))
+	case "exempt":
+		return respondEphemeral(ctx, fmt.Sprintf("Rule `%s` now leaves %s alone.", name, automodTargets(roleID, channelID)))
+	default:
+		return respondEphemeral(ctx, fmt.Sprintf("Rule `%s` checks %s again.", name, automodTargets(roleID, channelID)))
+	}
+}
+
+// automodRuleFromOptions builds the rule the add subcommand describes.
+// Without any action chosen, the rule deletes matching messages.
+func automodRuleFromOptions(opts []discord.CommandInteractionOption) (files.AutomodRule, error) {
+	var rule files.AutomodRule
+	var kind, pattern string
+	deleteMessages := true
+	for _, opt := range opts {
+		switch opt.Name {
+		case "name":
+			rule.Name = strings.TrimSpace(opt.String())
+		case "kind":
+			kind = opt.String()
+		case "pattern":
+			pattern = strings.TrimSpace(opt.String())
+		case "delete":
+			if val, err := opt.BoolValue(); err == nil {
+				deleteMessages = val
+			}
+		case "warn":
+			if val, err := opt.BoolValue(); err == nil && val {
+				rule.Actions = append(rule.Actions, files.AutomodActionWarn)
+			}
+		case "timeout_minutes":
+			if val, err := opt.IntValue(); err == nil && val > 0 {
+				rule.TimeoutMinutes = int(val)
+				rule.Actions = append(rule.Actions, files.AutomodActionTimeout)
+			}
+		case "flag_channel":
+			if val, err := opt.SnowflakeValue(); err == nil && val.IsValid() {
+				rule.FlagChannelID = val.String()
+				rule.Actions = append(rule.Actions, files.AutomodActionFlag)
+			}
+		}
+	}
+	if deleteMessages {
+		rule.Actions = append([]string{files.AutomodActionDelete}, rule.Actions...)
+	}
+	if kind == automodKindWords {
+		for _, word := range strings.Split(pattern, ",") {
+			if word = strings.TrimSpace(word); word != "" {
+				rule.Words = append(rule.Words, word)
+			}
+		}
+	} else {
+		rule.Pattern = pattern
+	}
+	if err := rule.Validate(); err != nil {
+		return files.AutomodRule{}, err
+	}
+	return rule, nil
+}
+
+func addAutomodRule(cfg *files.GuildConfig, rule files.AutomodRule) error {
+	if slices.ContainsFunc(cfg.AutomodRules, func(r files.AutomodRule) bool { return r.Name == rule.Name }) {
+		return errAutomodRuleExists
+	}
+	if len(cfg.AutomodRules) >= files.MaxAutomodRules {
+		return errAutomodRuleLimit
+	}
+	cfg.AutomodRules = append(cfg.AutomodRules, rule)
+	return nil
+}
+
+func removeAutomodRule(cfg *files.GuildConfig, name string) error {
+	n := len(cfg.AutomodRules)
+	cfg.AutomodRules = slices.DeleteFunc(cfg.AutomodRules, func(r files.AutomodRule) bool { return r.Name == name })
+	if len(cfg.AutomodRules) == n {
+		return errAutomodRuleMissing
+	}
+	return nil
+}
+
+// setAutomodExemption adds or removes the given IDs from the exemptions of
+// rule and reports whether anything changed.
+func setAutomodExemption(rule *files.AutomodRule, roleID, channelID string, exempt bool) bool {
+	update := func(ids []string, id string) ([]string, bool) {
+		if id == "" || slices.Contains(ids, id) == exempt {
+			return ids, false
+		}
+		if exempt {
+			return append(ids, id), true
+		}
+		return slices.DeleteFunc(ids, func(v string) bool { return v == id }), true
+	}
+	var roles, channels bool
+	rule.ExemptRoleIDs, roles = update(rule.ExemptRoleIDs, roleID)
+	rule.ExemptChannelIDs, channels = update(rule.ExemptChannelIDs, channelID)
+	return roles || channels
+}
+
+func automodTargets(roleID, channelID string) string {
+	var parts []string
+	if roleID != "" {
+		parts = append(parts, "<@&"+roleID+">")
+	}
+	if channelID != "" {
+		parts = append(parts, "<#"+channelID+">")
+	}
+	return strings.Join(parts, " and ")
+}
+
+// automodRuleActions describes what rule does to a matching message.
+func automodRuleActions(rule files.AutomodRule) string {
+	var parts []string
+	for _, action := range rule.Actions {
+		switch action {
+		case files.AutomodActionDelete:
+			parts = append(parts, "deletes the message")
+		case files.AutomodActionWarn:
+			parts = append(parts, "warns the author")
+		case files.AutomodActionTimeout:
+			parts = append(parts, fmt.Sprintf("times the author out for %d minute(s)", rule.TimeoutMinutes))
+		case files.AutomodActionFlag:
+			parts = append(parts, "flags it in <#"+rule.FlagChannelID+">")
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func automodRuleList(rules []files.AutomodRule) string {
+	if len(rules) == 0 {
+		return "No automod rules are set. Add one with /automod add."
+	}
+	var b strings.Builder
+	b.WriteString("Automod rules, checked in order:\n")
+	for _, rule := range rules {
+		if len(rule.Words) > 0 {
+			fmt.Fprintf(&b, "- `%s` matches the words %s and %s", rule.Name, strings.Join(rule.Words, ", "), automodRuleActions(rule))
+		} else {
+			fmt.Fprintf(&b, "- `%s` matches `%s` and %s", rule.Name, rule.Pattern, automodRuleActions(rule))
+		}
+		var exempt []string
+		for _, id := range rule.ExemptRoleIDs {
+			exempt = append(exempt, "<@&"+id+">")
+		}
+		for _, id := range rule.ExemptChannelIDs {
+			exempt = append(exempt, "<#"+id+">")
+		}
+		if len(exempt) > 0 {
+			b.WriteString("; exempt: " + strings.Join(exempt, " "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
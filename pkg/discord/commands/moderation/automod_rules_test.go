@@ -0,0 +1,86 @@
+package moderation
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+func TestAutomodRuleFromOptions(t *testing.T) {
+	t.Parallel()
+
+	rule, err := automodRuleFromOptions([]discord.CommandInteractionOption{
+		{Name: "name", Type: discord.StringOptionType, Value: []byte(`"slurs"`)},
+		{Name: "kind", Type: discord.StringOptionType, Value: []byte(`"words"`)},
+		{Name: "pattern", Type: discord.StringOptionType, Value: []byte(`"foo, bar ,,baz"`)},
+		{Name: "warn", Type: discord.BooleanOptionType, Value: []byte(`true`)},
+		{Name: "flag_channel", Type: discord.ChannelOptionType, Value: []byte(`"55"`)},
+	})
+	if err != nil {
+		t.Fatalf("automodRuleFromOptions: %v", err)
+	}
+	if !slices.Equal(rule.Words, []string{"foo", "bar", "baz"}) || rule.Pattern != "" {
+		t.Fatalf("unexpected match %+v", rule)
+	}
+	if !slices.Equal(rule.Actions, []string{files.AutomodActionDelete, files.AutomodActionWarn, files.AutomodActionFlag}) || rule.FlagChannelID != "55" {
+		t.Fatalf("unexpected actions %+v", rule)
+	}
+
+	rule, err = automodRuleFromOptions([]discord.CommandInteractionOption{
+		{Name: "name", Type: discord.StringOptionType, Value: []byte(`"invites"`)},
+		{Name: "kind", Type: discord.StringOptionType, Value: []byte(`"regex"`)},
+		{Name: "pattern", Type: discord.StringOptionType, Value: []byte(`"discord\\.gg/\\w+"`)},
+		{Name: "delete", Type: discord.BooleanOptionType, Value: []byte(`false`)},
+		{Name: "timeout_minutes", Type: discord.IntegerOptionType, Value: []byte(`15`)},
+	})
+	if err != nil {
+		t.Fatalf("automodRuleFromOptions: %v", err)
+	}
+	if rule.Pattern != `discord\.gg/\w+` || !slices.Equal(rule.Actions, []string{files.AutomodActionTimeout}) || rule.TimeoutMinutes != 15 {
+		t.Fatalf("unexpected rule %+v", rule)
+	}
+
+	if _, err := automodRuleFromOptions([]discord.CommandInteractionOption{
+		{Name: "name", Type: discord.StringOptionType, Value: []byte(`"broken"`)},
+		{Name: "kind", Type: discord.StringOptionType, Value: []byte(`"regex"`)},
+		{Name: "pattern", Type: discord.StringOptionType, Value: []byte(`"("`)},
+	}); err == nil {
+		t.Fatal("expected an invalid pattern to be rejected")
+	}
+}
+
+func TestAutomodRules_AddRemoveExempt(t *testing.T) {
+	t.Parallel()
+	var cfg files.GuildConfig
+	rule := files.AutomodRule{Name: "invites", Pattern: "discord.gg", Actions: []string{files.AutomodActionDelete}}
+
+	if err := addAutomodRule(&cfg, rule); err != nil {
+		t.Fatalf("addAutomodRule: %v", err)
+	}
+	if err := addAutomodRule(&cfg, rule); !errors.Is(err, errAutomodRuleExists) {
+		t.Fatalf("expected a duplicate to be refused, got %v", err)
+	}
+
+	if !setAutomodExemption(&cfg.AutomodRules[0], "1", "2", true) || setAutomodExemption(&cfg.AutomodRules[0], "1", "", true) {
+		t.Fatal("exempting should report only new IDs")
+	}
+	list := automodRuleList(cfg.AutomodRules)
+	if !strings.Contains(list, "`invites`") || !strings.Contains(list, "<@&1>") || !strings.Contains(list, "<#2>") {
+		t.Fatalf("unexpected list %q", list)
+	}
+	if !setAutomodExemption(&cfg.AutomodRules[0], "1", "", false) || len(cfg.AutomodRules[0].ExemptRoleIDs) != 0 {
+		t.Fatalf("unexpected exemptions %+v", cfg.AutomodRules[0])
+	}
+
+	if err := removeAutomodRule(&cfg, "other"); !errors.Is(err, errAutomodRuleMissing) {
+		t.Fatalf("expected a missing rule, got %v", err)
+	}
+	if err := removeAutomodRule(&cfg, "invites"); err != nil || len(cfg.AutomodRules) != 0 {
+		t.Fatalf("removeAutomodRule: %v, %+v", err, cfg.AutomodRules)
+	}
+}
@@ -0,0 +1,76 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+// banPoolOptionName is the /ban option sharing the ban with the server's ban
+// pools.
+const banPoolOptionName = "pool"
+
+// BanPoolStore records bans shared with ban pools. The runtime applies them
+// in the other guilds of each pool. *postgres.Store satisfies it.
+type BanPoolStore interface {
+	CreatePooledBan(ctx context.Context, ban coremod.PooledBan) (coremod.PooledBan, error)
+}
+
+// share hands the ban of userID to the server's ban pools and returns what
+// the invoker is told about it. The ban itself already happened, so failures
+// only change the reply.
+func (c *BanCommand) share(ctx *commands.ArikawaContext, userID discord.UserID, reason string, caseNumber int64) string {
+	if c.pools == nil {
+		return ""
+	}
+	var pools []string
+	if ctx.GuildConfig != nil {
+		pools = ctx.GuildConfig.BanPools
+	}
+	if len(pools) == 0 {
+		return " This server is in no ban pool, so the ban was not shared."
+	}
+	if !files.GuildEntitled(ctx.GuildID.String(), files.EntitlementCrossGuildSync) {
+		return " This server is not entitled to ban pools, so the ban was not shared."
+	}
+	_, err := c.pools.CreatePooledBan(context.Background(), coremod.PooledBan{
+		Pools:         pools,
+		OriginGuildID: ctx.GuildID.String(),
+		OriginCase:    caseNumber,
+		UserID:        userID.String(),
+		ModeratorID:   ctx.UserID.String(),
+		Reason:        reason,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		c.logger.Warn("Mitigated service degradation: Ban could not be shared with ban pools",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("target_id", userID.String()),
+			slog.String("error", err.Error()),
+		)
+		return " The ban could not be shared with the ban pools."
+	}
+	c.logger.Info("Architectural state transition: Ban shared with ban pools",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("target_id", userID.String()),
+		slog.Any("pools", pools),
+	)
+	return fmt.Sprintf(" Shared with ban pool(s) %s.", banPoolList(pools))
+}
+
+// banPoolList renders pool names for a reply.
+func banPoolList(pools []string) string {
+	quoted := make([]string, len(pools))
+	for i, pool := range pools {
+		quoted[i] = "`" + pool + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
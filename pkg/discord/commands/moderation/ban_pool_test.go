@@ -0,0 +1,60 @@
+package moderation
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+type fakeBanPoolStore struct {
+	shared []coremod.PooledBan
+}
+
+func (s *fakeBanPoolStore) CreatePooledBan(_ context.Context, ban coremod.PooledBan) (coremod.PooledBan, error) {
+	ban.ID = int64(len(s.shared) + 1)
+	s.shared = append(s.shared, ban)
+	return ban, nil
+}
+
+func TestBanCommand_SharesWithBanPools(t *testing.T) {
+	t.Parallel()
+	store := &fakeBanPoolStore{}
+	ban := &BanCommand{pools: store, metrics: NopMetrics{}, logger: slog.Default()}
+
+	var hasOption bool
+	for _, opt := range ban.Options() {
+		hasOption = hasOption || opt.Name() == banPoolOptionName
+	}
+	if !hasOption {
+		t.Fatal("/ban must expose the pool option when ban pools are enabled")
+	}
+	for _, opt := range (&BanCommand{}).Options() {
+		if opt.Name() == banPoolOptionName {
+			t.Fatal("/ban must not expose the pool option without ban pools")
+		}
+	}
+
+	ctx := &commands.ArikawaContext{GuildID: 10, UserID: 20, GuildConfig: &files.GuildConfig{GuildID: "10"}}
+	if got := ban.share(ctx, 30, "spam", 4); !strings.Contains(got, "no ban pool") || len(store.shared) != 0 {
+		t.Fatalf("share without pools: reply %q, shared %+v", got, store.shared)
+	}
+
+	ctx.GuildConfig.BanPools = []string{"network", "partners"}
+	got := ban.share(ctx, 30, "spam", 4)
+	if got != " Shared with ban pool(s) `network`, `partners`." {
+		t.Fatalf("share reply = %q", got)
+	}
+	if len(store.shared) != 1 {
+		t.Fatalf("shared %d bans, want 1", len(store.shared))
+	}
+	shared := store.shared[0]
+	if shared.OriginGuildID != "10" || shared.OriginCase != 4 || shared.UserID != "30" ||
+		shared.ModeratorID != "20" || shared.Reason != "spam" || len(shared.Pools) != 2 {
+		t.Fatalf("unexpected pooled ban %+v", shared)
+	}
+}
@@ -0,0 +1,156 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+// banPageSize is the most bans Discord returns per request.
+const banPageSize = 1000
+
+// banPager fetches the bans of a guild after the given user ID, in ID order.
+type banPager func(after discord.UserID) ([]discord.Ban, error)
+
+// collectBans walks every page of a guild's ban list.
+func collectBans(page banPager) ([]coremod.BanRecord, error) {
+	var (
+		out   []coremod.BanRecord
+		after discord.UserID
+	)
+	for {
+		bans, err := page(after)
+		if err != nil {
+			return nil, err
+		}
+		for _, ban := range bans {
+			out = append(out, coremod.BanRecord{UserID: ban.User.ID.String(), Username: ban.User.Tag(), Reason: ban.Reason})
+			after = max(after, ban.User.ID)
+		}
+		if len(bans) < banPageSize {
+			return out, nil
+		}
+	}
+}
+
+// clientBanPager pages through the ban list with the REST client. The
+// client's Bans method stops at Discord's default page.
+func clientBanPager(client *api.Client, guildID discord.GuildID) banPager {
+	return func(after discord.UserID) ([]discord.Ban, error) {
+		var param struct {
+			After discord.UserID `schema:"after,omitempty"`
+			Limit uint           `schema:"limit"`
+		}
+		param.After = after
+		param.Limit = banPageSize
+
+		var bans []discord.Ban
+		return bans, client.RequestJSON(&bans, "GET",
+			api.EndpointGuilds+guildID.String()+"/bans",
+			httputil.WithSchema(client, param),
+		)
+	}
+}
+
+// BanlistCommand encapsulates the `/banlist` slash command execution.
+type BanlistCommand struct {
+	cases   CaseStore
+	metrics Metrics
+	logger  *slog.Logger
+}
+
+func (c *BanlistCommand) Name() string        { return "banlist" }
+func (c *BanlistCommand) Description() string { return "Audit the server's ban list" }
+func (c *BanlistCommand) Options() []discord.CommandOption {
+	return []discord.CommandOption{
+		&discord.SubcommandOption{
+			OptionName:  "export",
+			Description: "Download every ban with its moderator and date when known",
+			Options:     []discord.CommandOptionValue{exportFormatOption()},
+		},
+	}
+}
+
+func (c *BanlistCommand) RequiresGuild() bool       { return true }
+func (c *BanlistCommand) RequiresPermissions() bool { return true }
+func (c *BanlistCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionBanMembers
+}
+
+func (c *BanlistCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("banlist")
+
+	if ctx.Interaction == nil || ctx.Interaction.Data == nil || ctx.Interaction.Data.InteractionType() != discord.CommandInteractionType {
+		return nil
+	}
+	if err := ctx.Defer(discord.EphemeralMessage); err != nil {
+		return err
+	}
+	cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
+	if len(cmdData.Options) == 0 || cmdData.Options[0].Name != "export" {
+		return respondEphemeral(ctx, "Choose export.")
+	}
+	format := exportFormatCSV
+	for _, opt := range cmdData.Options[0].Options {
+		if opt.Name == "format" {
+			format = opt.String()
+		}
+	}
+
+	guildID := ctx.GuildID.String()
+	bans, err := collectBans(clientBanPager(ctx.Client, ctx.GuildID))
+	if err != nil {
+		c.logger.Error("Blocking structural failure: Ban list could not be fetched",
+			slog.String("guild_id", guildID),
+			slog.String("error", err.Error()),
+		)
+		return respondEphemeral(ctx, "Failed to fetch the ban list. Make sure the bot can ban members.")
+	}
+	if len(bans) == 0 {
+		return respondEphemeral(ctx, "This server has no bans.")
+	}
+	if c.cases != nil {
+		cases, err := c.cases.ListModerationCases(context.Background(), guildID, coremod.CaseFilter{Action: coremod.CaseActionBan})
+		if err != nil {
+			c.logger.Warn("Mitigated service degradation: Ban cases could not be loaded; exporting bans without moderators",
+				slog.String("guild_id", guildID),
+				slog.String("error", err.Error()),
+			)
+		}
+		bans = coremod.AttachBanCases(bans, cases)
+	}
+
+	data, err := encodeBans(bans, format, ctx.GuildConfig.Location())
+	if err != nil {
+		return fmt.Errorf("BanlistCommand.Handle: %w", err)
+	}
+	c.logger.Info("Architectural state transition: Ban list exported",
+		slog.String("guild_id", guildID),
+		slog.String("user_id", ctx.UserID.String()),
+		slog.Int("bans", len(bans)),
+	)
+	name := fmt.Sprintf("bans-%s-%s.%s", guildID, time.Now().UTC().Format("20060102-150405"), format)
+	_, err = ctx.Client.EditInteractionResponse(ctx.Interaction.AppID, ctx.Interaction.Token, api.EditInteractionResponseData{
+		Content: option.NewNullableString(fmt.Sprintf("%d ban(s).", len(bans))),
+		Files:   []sendpart.File{{Name: name, Reader: bytes.NewReader(data)}},
+	})
+	return err
+}
+
+func encodeBans(bans []coremod.BanRecord, format string, loc *time.Location) ([]byte, error) {
+	if format == exportFormatJSON {
+		return coremod.BansJSON(bans)
+	}
+	return coremod.BansCSV(bans, loc)
+}
@@ -0,0 +1,43 @@
+package moderation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+func TestCollectBans_Paginates(t *testing.T) {
+	t.Parallel()
+	var afters []discord.UserID
+	page := func(after discord.UserID) ([]discord.Ban, error) {
+		afters = append(afters, after)
+		if after > 0 {
+			return []discord.Ban{{User: discord.User{ID: after + 1, Username: "last"}}}, nil
+		}
+		bans := make([]discord.Ban, banPageSize)
+		for i := range bans {
+			bans[i] = discord.Ban{User: discord.User{ID: discord.UserID(i + 1)}, Reason: "spam"}
+		}
+		return bans, nil
+	}
+
+	bans, err := collectBans(page)
+	if err != nil {
+		t.Fatalf("collectBans: %v", err)
+	}
+	if len(bans) != banPageSize+1 || len(afters) != 2 || afters[1] != banPageSize {
+		t.Fatalf("got %d bans over pages after %v", len(bans), afters)
+	}
+	if bans[0].Reason != "spam" || bans[banPageSize].UserID != "1001" {
+		t.Fatalf("unexpected records %+v, %+v", bans[0], bans[banPageSize])
+	}
+}
+
+func TestCollectBans_Error(t *testing.T) {
+	t.Parallel()
+	boom := errors.New("missing permissions")
+	if _, err := collectBans(func(discord.UserID) ([]discord.Ban, error) { return nil, boom }); !errors.Is(err, boom) {
+		t.Fatalf("collectBans error = %v", err)
+	}
+}
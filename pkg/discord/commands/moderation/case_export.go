@@ -0,0 +1,138 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+
+	discordautomod "github.com/small-frappuccino/discordcore/pkg/discord/automod"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+const (
+	// caseExportDefaultDays and caseExportMaxDays bound how far back an
+	// export reaches.
+	caseExportDefaultDays = 30
+	caseExportMaxDays     = 365
+
+	exportFormatCSV  = "csv"
+	exportFormatJSON = "json"
+)
+
+// caseExportActions are the action types /case export can filter by.
+var caseExportActions = []string{
+	caseActionBan, caseActionSoftban, caseActionKick, caseActionTimeout,
+	coremod.CaseActionLock, coremod.CaseActionUnlock,
+	discordautomod.CaseActionBlock, discordautomod.CaseActionTimeout,
+}
+
+func caseExportOption() *discord.SubcommandOption {
+	actions := make([]discord.StringChoice, 0, len(caseExportActions))
+	for _, action := range caseExportActions {
+		actions = append(actions, discord.StringChoice{Name: action, Value: action})
+	}
+	return &discord.SubcommandOption{
+		OptionName:  "export",
+		Description: "Download moderation cases as a file",
+		Options: []discord.CommandOptionValue{
+			&discord.IntegerOption{
+				OptionName:  "days",
+				Description: fmt.Sprintf("How many days back to export (default %d)", caseExportDefaultDays),
+				Min:         option.NewInt(1),
+				Max:         option.NewInt(caseExportMaxDays),
+			},
+			&discord.StringOption{
+				OptionName:  "action",
+				Description: "Only export cases of this action",
+				Choices:     actions,
+			},
+			&discord.UserOption{
+				OptionName:  "moderator",
+				Description: "Only export cases taken by this moderator",
+			},
+			exportFormatOption(),
+		},
+	}
+}
+
+// exportFormatOption lets an export choose between CSV and JSON.
+func exportFormatOption() *discord.StringOption {
+	return &discord.StringOption{
+		OptionName:  "format",
+		Description: "File format (default CSV)",
+		Choices: []discord.StringChoice{
+			{Name: "CSV", Value: exportFormatCSV},
+			{Name: "JSON", Value: exportFormatJSON},
+		},
+	}
+}
+
+// handleExport sends the cases matching the subcommand's filters as a file,
+// newest first.
+func (c *CaseCommand) handleExport(ctx *commands.ArikawaContext, opts []discord.CommandInteractionOption) error {
+	days := caseExportDefaultDays
+	format := exportFormatCSV
+	var filter coremod.CaseFilter
+	for _, opt := range opts {
+		switch opt.Name {
+		case "days":
+			if val, err := opt.IntValue(); err == nil {
+				days = min(max(int(val), 1), caseExportMaxDays)
+			}
+		case "action":
+			filter.Action = opt.String()
+		case "moderator":
+			if val, err := opt.SnowflakeValue(); err == nil {
+				filter.ModeratorID = val.String()
+			}
+		case "format":
+			format = opt.String()
+		}
+	}
+	now := time.Now()
+	filter.Since = now.Add(-time.Duration(days) * 24 * time.Hour)
+
+	cases, err := c.cases.store.ListModerationCases(context.Background(), ctx.GuildID.String(), filter)
+	if err != nil {
+		c.logFailure(ctx, "export", 0, err)
+		return respondEphemeral(ctx, "Failed to load the cases.")
+	}
+	if len(cases) == 0 {
+		return respondEphemeral(ctx, fmt.Sprintf("No cases match in the last %d day(s).", days))
+	}
+	data, err := encodeCases(cases, format, ctx.GuildConfig.Location())
+	if err != nil {
+		return fmt.Errorf("CaseCommand.handleExport: %w", err)
+	}
+
+	c.logger.Info("Architectural state transition: Moderation cases exported",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("user_id", ctx.UserID.String()),
+		slog.Int("cases", len(cases)),
+	)
+	content := fmt.Sprintf("%d case(s) from the last %d day(s).", len(cases), days)
+	if len(cases) == coremod.MaxCaseExport {
+		content += fmt.Sprintf(" Only the newest %d are included; narrow the filters for older ones.", coremod.MaxCaseExport)
+	}
+	name := fmt.Sprintf("cases-%s-%s.%s", ctx.GuildID, now.UTC().Format("20060102-150405"), format)
+	_, err = ctx.Client.EditInteractionResponse(ctx.Interaction.AppID, ctx.Interaction.Token, api.EditInteractionResponseData{
+		Content: option.NewNullableString(content),
+		Files:   []sendpart.File{{Name: name, Reader: bytes.NewReader(data)}},
+	})
+	return err
+}
+
+func encodeCases(cases []coremod.Case, format string, loc *time.Location) ([]byte, error) {
+	if format == exportFormatJSON {
+		return coremod.CasesJSON(cases)
+	}
+	return coremod.CasesCSV(cases, loc)
+}
@@ -0,0 +1,43 @@
+package moderation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+func TestCaseCommand_ExportSubcommand(t *testing.T) {
+	t.Parallel()
+	var export *discord.SubcommandOption
+	for _, opt := range (&CaseCommand{}).Options() {
+		if sub, ok := opt.(*discord.SubcommandOption); ok && sub.OptionName == "export" {
+			export = sub
+		}
+	}
+	if export == nil {
+		t.Fatal("/case has no export subcommand")
+	}
+	for _, opt := range export.Options {
+		if sopt, ok := opt.(*discord.StringOption); ok && sopt.OptionName == "action" && len(sopt.Choices) != len(caseExportActions) {
+			t.Fatalf("action choices = %d, want %d", len(sopt.Choices), len(caseExportActions))
+		}
+	}
+}
+
+func TestEncodeCases(t *testing.T) {
+	t.Parallel()
+	cases := []coremod.Case{{CaseNumber: 1, Action: caseActionBan, UserID: "1"}}
+
+	csvData, err := encodeCases(cases, exportFormatCSV, time.UTC)
+	if err != nil || !strings.HasPrefix(string(csvData), "case_number,") {
+		t.Fatalf("CSV export = %q, err=%v", csvData, err)
+	}
+	jsonData, err := encodeCases(cases, exportFormatJSON, time.UTC)
+	if err != nil || !strings.HasPrefix(string(jsonData), "[") {
+		t.Fatalf("JSON export = %q, err=%v", jsonData, err)
+	}
+}
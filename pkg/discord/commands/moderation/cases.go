@@ -0,0 +1,440 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	discordlogging "github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+// Case actions recorded for moderation slash commands.
+const (
+	caseActionBan     = coremod.CaseActionBan
+	caseActionKick    = coremod.CaseActionKick
+	caseActionTimeout = "timeout"
+	caseActionSoftban = "softban"
+	// caseActionWarn labels warning cases. The warning store records them
+	// together with the warning, so only test mode posts one from here.
+	caseActionWarn = coremod.CaseActionWarn
+)
+
+// CaseStore persists numbered moderation cases. *postgres.Store satisfies it.
+type CaseStore interface {
+	NextModerationCaseNumber(ctx context.Context, guildID string) (int64, error)
+	CreateModerationCase(ctx context.Context, c coremod.Case) (coremod.Case, error)
+	GetModerationCase(ctx context.Context, guildID string, caseNumber int64) (coremod.Case, bool, error)
+	ListModerationCases(ctx context.Context, guildID string, filter coremod.CaseFilter) ([]coremod.Case, error)
+	UpdateModerationCaseReason(ctx context.Context, guildID string, caseNumber int64, reason string) (coremod.Case, bool, error)
+	VoidModerationCase(ctx context.Context, guildID string, caseNumber int64, voidedBy string, voidedAt time.Time) (coremod.Case, bool, error)
+	SetModerationCaseLogMessage(ctx context.Context, guildID string, caseNumber int64, channelID, messageID string) error
+}
+
+// caseLog records actions taken through slash commands as cases and keeps
+// their log embeds in step with later edits. A nil *caseLog records nothing.
+// Case logs go through the log publisher of the interaction's context, which
+// moves the logs of cases about a member into the member's log thread when
+// the guild keeps one.
+type caseLog struct {
+	store  CaseStore
+	logger *slog.Logger
+}
+
+// record stores a case for an action the invoker has just taken and posts its
+// embed to the guild's moderation case channel. The action already happened,
+// so failures are only logged.
+func (l *caseLog) record(ctx *commands.ArikawaContext, action string, target discord.UserID, reason string) (coremod.Case, bool) {
+	return l.recordNoticed(ctx, action, target, reason, notice{})
+}
+
+// recordNoticed records an action announced by notifyTarget under the case
+// number it reserved, noting a DM that could not be delivered.
+func (l *caseLog) recordNoticed(ctx *commands.ArikawaContext, action string, target discord.UserID, reason string, n notice) (coremod.Case, bool) {
+	return l.create(ctx, coremod.Case{Action: action, UserID: target.String(), Reason: reason, CaseNumber: n.caseNumber, Extra: n.extra})
+}
+
+// recordTimed records an action announced by notifyTarget that ends on its
+// own at until, such as a timeout, so its expiry can be followed up in the
+// case channel.
+func (l *caseLog) recordTimed(ctx *commands.ArikawaContext, action string, target discord.UserID, reason string, until time.Time, n notice) (coremod.Case, bool) {
+	return l.create(ctx, coremod.Case{Action: action, UserID: target.String(), Reason: reason, CaseNumber: n.caseNumber, Extra: n.extra, ExpiresAt: until})
+}
+
+// reserve allocates a case number ahead of the action it will record. It
+// returns zero when no number could be reserved; the case then gets one when
+// it is created.
+func (l *caseLog) reserve(ctx *commands.ArikawaContext) int64 {
+	if l == nil || inTestMode(ctx) {
+		return 0
+	}
+	number, err := l.store.NextModerationCaseNumber(context.Background(), ctx.GuildID.String())
+	if err != nil {
+		l.logger.Warn("Mitigated service degradation: Moderation case number could not be reserved",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("error", err.Error()),
+		)
+		return 0
+	}
+	return number
+}
+
+// recordChannel records a channel action. An invalid channelID records an
+// action on every channel.
+func (l *caseLog) recordChannel(ctx *commands.ArikawaContext, action string, channelID discord.ChannelID, reason string) (coremod.Case, bool) {
+	c := coremod.Case{Action: action, Reason: reason}
+	if channelID.IsValid() {
+		c.ChannelID = channelID.String()
+	}
+	return l.create(ctx, c)
+}
+
+// recordQuiet stores a case for one target of a mass action without posting
+// its embed; the summary and report of the run already list every target.
+func (l *caseLog) recordQuiet(ctx *commands.ArikawaContext, action string, target discord.UserID, reason string) (coremod.Case, bool) {
+	if l == nil || inTestMode(ctx) {
+		return coremod.Case{}, false
+	}
+	return l.persist(ctx, coremod.Case{Action: action, UserID: target.String(), Reason: reason})
+}
+
+func (l *caseLog) create(ctx *commands.ArikawaContext, c coremod.Case) (coremod.Case, bool) {
+	if l == nil {
+		return coremod.Case{}, false
+	}
+	if inTestMode(ctx) {
+		c.GuildID = ctx.GuildID.String()
+		c.ModeratorID = ctx.UserID.String()
+		c.Source = coremod.CaseSourceManual
+		l.postSimulated(ctx, c)
+		return coremod.Case{}, false
+	}
+	c, ok := l.persist(ctx, c)
+	if !ok {
+		return coremod.Case{}, false
+	}
+
+	pub, channelID, ok := caseLogTarget(ctx)
+	if !ok {
+		return c, true
+	}
+	// The log is published rather than queued so the case keeps its
+	// location, which later edits rewrite.
+	msg, err := pub.Publish(ctx.Context(), casePost(c, channelID))
+	if err != nil {
+		l.logger.Warn("Mitigated service degradation: Moderation case log could not be posted",
+			slog.String("guild_id", c.GuildID),
+			slog.Int64("case_number", c.CaseNumber),
+			slog.String("error", err.Error()),
+		)
+		return c, true
+	}
+	c.LogChannelID = msg.ChannelID.String()
+	c.LogMessageID = msg.ID.String()
+	if err := l.store.SetModerationCaseLogMessage(context.Background(), c.GuildID, c.CaseNumber, c.LogChannelID, c.LogMessageID); err != nil {
+		l.logger.Warn("Mitigated service degradation: Moderation case log location could not be saved",
+			slog.String("guild_id", c.GuildID),
+			slog.Int64("case_number", c.CaseNumber),
+			slog.String("error", err.Error()),
+		)
+	}
+	return c, true
+}
+
+// persist stores c as a manual case taken by the invoker.
+func (l *caseLog) persist(ctx *commands.ArikawaContext, c coremod.Case) (coremod.Case, bool) {
+	c.GuildID = ctx.GuildID.String()
+	c.ModeratorID = ctx.UserID.String()
+	c.Source = coremod.CaseSourceManual
+	created, err := l.store.CreateModerationCase(context.Background(), c)
+	if err != nil {
+		l.logger.Warn("Mitigated service degradation: Moderation case could not be recorded",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("target_id", c.UserID),
+			slog.String("channel_id", c.ChannelID),
+			slog.String("action", c.Action),
+			slog.String("error", err.Error()),
+		)
+		return coremod.Case{}, false
+	}
+	return created, true
+}
+
+// postSimulated posts the embed of an action a guild in test mode only
+// simulated, under the test mode banner. The case is not stored, so it has
+// no number.
+func (l *caseLog) postSimulated(ctx *commands.ArikawaContext, c coremod.Case) {
+	pub, channelID, ok := caseLogTarget(ctx)
+	if !ok {
+		return
+	}
+	c.CreatedAt = time.Now()
+	pub.Notify(coremod.WithTestMode(ctx.Context()), casePost(c, channelID))
+}
+
+// caseLogTarget returns the log publisher of the interaction and the
+// moderation case channel of the invoking guild, reporting false when
+// either is missing.
+func caseLogTarget(ctx *commands.ArikawaContext) (discordlogging.Publisher, discord.ChannelID, bool) {
+	if ctx.GuildConfig == nil {
+		return nil, 0, false
+	}
+	pub := discordlogging.PublisherFrom(ctx.Context())
+	channelID, err := discord.ParseSnowflake(ctx.GuildConfig.Channels.ModerationCase)
+	if pub == nil || err != nil || !channelID.IsValid() {
+		return nil, 0, false
+	}
+	return pub, discord.ChannelID(channelID), true
+}
+
+// casePost describes the log of c posted to channelID.
+func casePost(c coremod.Case, channelID discord.ChannelID) discordlogging.LogPost {
+	return discordlogging.LogPost{
+		GuildID:   c.GuildID,
+		ChannelID: channelID,
+		EventType: logging.LogEventModerationCase,
+		UserID:    c.UserID,
+		ActorID:   c.ModeratorID,
+		Embed:     caseEmbed(c),
+	}
+}
+
+// refresh rewrites the log embed of c, if one was posted.
+func (l *caseLog) refresh(ctx *commands.ArikawaContext, c coremod.Case) {
+	if c.LogMessageID == "" || ctx.Client == nil {
+		return
+	}
+	channelID, errC := discord.ParseSnowflake(c.LogChannelID)
+	messageID, errM := discord.ParseSnowflake(c.LogMessageID)
+	if errC != nil || errM != nil {
+		return
+	}
+	if _, err := ctx.Client.EditEmbeds(discord.ChannelID(channelID), discord.MessageID(messageID), caseEmbed(c)); err != nil {
+		l.logger.Warn("Mitigated service degradation: Moderation case log could not be updated",
+			slog.String("guild_id", c.GuildID),
+			slog.Int64("case_number", c.CaseNumber),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// caseEmbed renders the log embed of c.
+func caseEmbed(c coremod.Case) discord.Embed {
+	payload := discordmod.ModerationLogPayload{
+		Action:     c.Action,
+		TargetID:   c.UserID,
+		Reason:     c.Reason,
+		CaseNumber: c.CaseNumber,
+		ActorID:    c.ModeratorID,
+	}
+	var extra []string
+	switch c.Source {
+	case coremod.CaseSourceAutomod:
+		extra = append(extra, "Taken by AutoMod.")
+	case coremod.CaseSourceExternal:
+		extra = append(extra, "Taken outside discordcore.")
+	}
+	if c.Extra != "" {
+		extra = append(extra, c.Extra)
+	}
+	payload.Extra = strings.Join(extra, "\n")
+	if c.TargetsChannels() {
+		payload.TargetID = ""
+		payload.TargetLabel = "All channels"
+		if c.ChannelID != "" {
+			payload.TargetLabel = fmt.Sprintf("<#%s> (`%s`)", c.ChannelID, c.ChannelID)
+		}
+	}
+	if c.Action == coremod.CaseActionPrune {
+		payload.TargetID = ""
+		payload.TargetLabel = "Inactive members"
+	}
+	embed := discordmod.BuildModerationEmbed(payload, discord.Color(theme.Danger()), c.CreatedAt)
+	if c.Voided() {
+		embed.Title += " (voided)"
+		embed.Color = discord.Color(theme.Muted())
+		embed.Fields = append(embed.Fields, discord.EmbedField{
+			Name:  "Voided",
+			Value: fmt.Sprintf("By <@%s> %s", c.VoidedBy, logging.DiscordTimestamp(c.VoidedAt, logging.TimestampRelative)),
+		})
+	}
+	return embed
+}
+
+// caseSuffix names the case recorded for an action in the invoker's reply.
+func caseSuffix(c coremod.Case, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(" (case #%d)", c.CaseNumber)
+}
+
+// CaseCommand encapsulates the `/case` slash command execution.
+type CaseCommand struct {
+	cases   *caseLog
+	metrics Metrics
+	logger  *slog.Logger
+}
+
+func (c *CaseCommand) Name() string        { return "case" }
+func (c *CaseCommand) Description() string { return "View, edit, void or export moderation cases" }
+func (c *CaseCommand) Options() []discord.CommandOption {
+	caseOption := func() *discord.IntegerOption {
+		return &discord.IntegerOption{OptionName: "case", Description: "Case number", Required: true, Min: option.NewInt(1)}
+	}
+	return []discord.CommandOption{
+		&discord.SubcommandOption{
+			OptionName:  "view",
+			Description: "Show a moderation case",
+			Options:     []discord.CommandOptionValue{caseOption()},
+		},
+		&discord.SubcommandOption{
+			OptionName:  "edit",
+			Description: "Replace the reason of a moderation case",
+			Options: []discord.CommandOptionValue{
+				caseOption(),
+				&discord.StringOption{
+					OptionName:  "reason",
+					Description: "New reason",
+					Required:    true,
+					MaxLength:   option.NewInt(maxReasonLength),
+				},
+			},
+		},
+		&discord.SubcommandOption{
+			OptionName:  "delete",
+			Description: "Void a moderation case; it stays on record but no longer counts",
+			Options:     []discord.CommandOptionValue{caseOption()},
+		},
+		caseExportOption(),
+	}
+}
+
+func (c *CaseCommand) RequiresGuild() bool       { return true }
+func (c *CaseCommand) RequiresPermissions() bool { return true }
+func (c *CaseCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionModerateMembers
+}
+
+func (c *CaseCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("case")
+
+	if ctx.Interaction == nil || ctx.Interaction.Data == nil || ctx.Interaction.Data.InteractionType() != discord.CommandInteractionType {
+		return nil
+	}
+	if err := ctx.Defer(discord.EphemeralMessage); err != nil {
+		return err
+	}
+	cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
+	if len(cmdData.Options) == 0 {
+		return respondEphemeral(ctx, "Choose view, edit, delete or export.")
+	}
+	sub := cmdData.Options[0]
+	if sub.Name == "export" {
+		return c.handleExport(ctx, sub.Options)
+	}
+
+	var (
+		caseNumber int64
+		rawReason  string
+	)
+	for _, opt := range sub.Options {
+		switch opt.Name {
+		case "case":
+			if val, err := opt.IntValue(); err == nil {
+				caseNumber = val
+			}
+		case "reason":
+			rawReason = opt.String()
+		}
+	}
+	if caseNumber <= 0 {
+		return respondEphemeral(ctx, "Invalid case number.")
+	}
+
+	guildID := ctx.GuildID.String()
+	store := c.cases.store
+	bg := context.Background()
+	switch sub.Name {
+	case "view":
+		found, ok, err := store.GetModerationCase(bg, guildID, caseNumber)
+		if err != nil {
+			c.logFailure(ctx, "view", caseNumber, err)
+			return respondEphemeral(ctx, "Failed to load the case.")
+		}
+		if !ok {
+			return respondEphemeral(ctx, fmt.Sprintf("No case has number #%d.", caseNumber))
+		}
+		_, err = ctx.Client.EditInteractionResponse(ctx.Interaction.AppID, ctx.Interaction.Token, api.EditInteractionResponseData{
+			Embeds: &[]discord.Embed{caseEmbed(found)},
+		})
+		return err
+	case "edit":
+		reason, err := validateReason(rawReason)
+		if err != nil {
+			return respondEphemeral(ctx, fmt.Sprintf("Nothing was changed: %v.", err))
+		}
+		current, ok, err := store.GetModerationCase(bg, guildID, caseNumber)
+		if err != nil {
+			c.logFailure(ctx, "edit", caseNumber, err)
+			return respondEphemeral(ctx, "Failed to load the case.")
+		}
+		if !ok {
+			return respondEphemeral(ctx, fmt.Sprintf("No case has number #%d.", caseNumber))
+		}
+		if current.Voided() {
+			return respondEphemeral(ctx, fmt.Sprintf("Case #%d is voided and can no longer be edited.", caseNumber))
+		}
+		updated, ok, err := store.UpdateModerationCaseReason(bg, guildID, caseNumber, reason)
+		if err != nil || !ok {
+			if err != nil {
+				c.logFailure(ctx, "edit", caseNumber, err)
+			}
+			return respondEphemeral(ctx, "Failed to update the case.")
+		}
+		c.cases.refresh(ctx, updated)
+		c.logger.Info("Architectural state transition: Moderation case reason edited",
+			slog.String("guild_id", guildID),
+			slog.Int64("case_number", caseNumber),
+			slog.String("user_id", ctx.UserID.String()),
+		)
+		return respondEphemeral(ctx, fmt.Sprintf("Updated the reason of case #%d.", caseNumber))
+	case "delete":
+		voided, ok, err := store.VoidModerationCase(bg, guildID, caseNumber, ctx.UserID.String(), time.Now())
+		if err != nil {
+			c.logFailure(ctx, "delete", caseNumber, err)
+			return respondEphemeral(ctx, "Failed to void the case.")
+		}
+		if !ok {
+			return respondEphemeral(ctx, fmt.Sprintf("No active case has number #%d.", caseNumber))
+		}
+		c.cases.refresh(ctx, voided)
+		c.logger.Info("Architectural state transition: Moderation case voided",
+			slog.String("guild_id", guildID),
+			slog.Int64("case_number", caseNumber),
+			slog.String("user_id", ctx.UserID.String()),
+		)
+		return respondEphemeral(ctx, fmt.Sprintf("Voided case #%d.", caseNumber))
+	default:
+		return respondEphemeral(ctx, "Choose view, edit, delete or export.")
+	}
+}
+
+func (c *CaseCommand) logFailure(ctx *commands.ArikawaContext, action string, caseNumber int64, err error) {
+	c.logger.Error("Blocking structural failure: Moderation case command aborted",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("action", action),
+		slog.Int64("case_number", caseNumber),
+		slog.String("error", err.Error()),
+	)
+}
@@ -0,0 +1,184 @@
+package moderation
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	discordlogging "github.com/small-frappuccino/discordcore/pkg/discord/logging"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+type fakeCaseStore struct {
+	created  []coremod.Case
+	reserved int64
+}
+
+func (f *fakeCaseStore) NextModerationCaseNumber(context.Context, string) (int64, error) {
+	f.reserved++
+	return int64(len(f.created)) + f.reserved, nil
+}
+
+func (f *fakeCaseStore) CreateModerationCase(_ context.Context, c coremod.Case) (coremod.Case, error) {
+	if c.CaseNumber == 0 {
+		c.CaseNumber = int64(len(f.created)) + f.reserved + 1
+	}
+	f.created = append(f.created, c)
+	return c, nil
+}
+
+func (f *fakeCaseStore) GetModerationCase(context.Context, string, int64) (coremod.Case, bool, error) {
+	return coremod.Case{}, false, nil
+}
+
+func (f *fakeCaseStore) ListModerationCases(context.Context, string, coremod.CaseFilter) ([]coremod.Case, error) {
+	return f.created, nil
+}
+
+func (f *fakeCaseStore) UpdateModerationCaseReason(context.Context, string, int64, string) (coremod.Case, bool, error) {
+	return coremod.Case{}, false, nil
+}
+
+func (f *fakeCaseStore) VoidModerationCase(context.Context, string, int64, string, time.Time) (coremod.Case, bool, error) {
+	return coremod.Case{}, false, nil
+}
+
+func (f *fakeCaseStore) SetModerationCaseLogMessage(context.Context, string, int64, string, string) error {
+	return nil
+}
+
+func TestCaseLog_RecordsInvokerAsModerator(t *testing.T) {
+	t.Parallel()
+	store := &fakeCaseStore{}
+	l := &caseLog{store: store, logger: slog.Default()}
+	ctx := &commands.ArikawaContext{GuildID: discord.GuildID(1), UserID: discord.UserID(2)}
+
+	c, ok := l.record(ctx, caseActionKick, discord.UserID(3), "spam")
+	if !ok || c.CaseNumber != 1 {
+		t.Fatalf("expected case #1 to be recorded, got %+v (ok=%v)", c, ok)
+	}
+	got := store.created[0]
+	if got.ModeratorID != "2" || got.UserID != "3" || got.Source != coremod.CaseSourceManual || got.Action != caseActionKick {
+		t.Fatalf("unexpected case: %+v", got)
+	}
+	if suffix := caseSuffix(c, ok); suffix != " (case #1)" {
+		t.Fatalf("caseSuffix = %q", suffix)
+	}
+
+	var disabled *caseLog
+	if _, ok := disabled.record(ctx, caseActionBan, discord.UserID(3), ""); ok {
+		t.Fatal("a nil case log must not record")
+	}
+}
+
+func TestCaseEmbed_MarksVoidedCases(t *testing.T) {
+	t.Parallel()
+	c := coremod.Case{CaseNumber: 4, Action: caseActionBan, UserID: "3", ModeratorID: "2", Reason: "raid"}
+	embed := caseEmbed(c)
+	if strings.Contains(embed.Title, "voided") {
+		t.Fatalf("active case rendered as voided: %q", embed.Title)
+	}
+	if !hasField(embed, "Case", "#4") {
+		t.Fatalf("expected case number field, got %+v", embed.Fields)
+	}
+
+	c.VoidedAt = time.Unix(1_700_000_000, 0)
+	c.VoidedBy = "5"
+	embed = caseEmbed(c)
+	if !strings.Contains(embed.Title, "voided") || !hasField(embed, "Voided", "By <@5> <t:1700000000:R>") {
+		t.Fatalf("expected voided rendering, got %q %+v", embed.Title, embed.Fields)
+	}
+}
+
+func hasField(embed discord.Embed, name, value string) bool {
+	for _, f := range embed.Fields {
+		if f.Name == name && f.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCaseLog_TestModeRecordsNothing(t *testing.T) {
+	t.Parallel()
+	store := &fakeCaseStore{}
+	l := &caseLog{store: store, logger: slog.Default()}
+	ctx := &commands.ArikawaContext{GuildID: discord.GuildID(1), UserID: discord.UserID(2), GuildConfig: &files.GuildConfig{GuildID: "1", TestMode: true}}
+
+	if c, ok := l.record(ctx, caseActionBan, discord.UserID(3), "trial"); ok || c.CaseNumber != 0 {
+		t.Fatalf("expected no case in test mode, got %+v (ok=%v)", c, ok)
+	}
+	if len(store.created) != 0 {
+		t.Fatalf("test mode stored cases %+v", store.created)
+	}
+}
+
+func TestCaseLog_RecordQuietStoresMassActionCases(t *testing.T) {
+	t.Parallel()
+	store := &fakeCaseStore{}
+	l := &caseLog{store: store, logger: slog.Default()}
+	ctx := &commands.ArikawaContext{GuildID: discord.GuildID(1), UserID: discord.UserID(2)}
+
+	if c, ok := l.recordQuiet(ctx, caseActionBan, discord.UserID(3), "raid"); !ok || c.CaseNumber != 1 {
+		t.Fatalf("expected case #1 for a massban target, got %+v (ok=%v)", c, ok)
+	}
+	if got := store.created[0]; got.Action != caseActionBan || got.ModeratorID != "2" || got.UserID != "3" {
+		t.Fatalf("unexpected case: %+v", got)
+	}
+
+	ctx.GuildConfig = &files.GuildConfig{GuildID: "1", TestMode: true}
+	if _, ok := l.recordQuiet(ctx, caseActionBan, discord.UserID(4), "raid"); ok || len(store.created) != 1 {
+		t.Fatalf("test mode stored a massban case: %+v", store.created)
+	}
+}
+
+type fakeLogPublisher struct {
+	published []discordlogging.LogPost
+	notified  []discordlogging.LogPost
+	testMode  []bool
+}
+
+func (f *fakeLogPublisher) Notify(ctx context.Context, post discordlogging.LogPost) {
+	f.notified = append(f.notified, post)
+	f.testMode = append(f.testMode, coremod.InTestMode(ctx))
+}
+
+func (f *fakeLogPublisher) Publish(_ context.Context, post discordlogging.LogPost) (*discord.Message, error) {
+	f.published = append(f.published, post)
+	return &discord.Message{ID: 30, ChannelID: post.ChannelID}, nil
+}
+
+func TestCaseLog_PostsThroughInteractionPublisher(t *testing.T) {
+	t.Parallel()
+	pub := &fakeLogPublisher{}
+	l := &caseLog{store: &fakeCaseStore{}, logger: slog.Default()}
+	ctx := &commands.ArikawaContext{
+		GuildID:     discord.GuildID(1),
+		UserID:      discord.UserID(2),
+		GuildConfig: &files.GuildConfig{GuildID: "1", Channels: files.ChannelsConfig{ModerationCase: "20"}},
+	}
+	ctx.WithContext(discordlogging.WithPublisher(context.Background(), pub))
+
+	c, ok := l.record(ctx, caseActionBan, discord.UserID(3), "spam")
+	if !ok || c.LogChannelID != "20" || c.LogMessageID != "30" {
+		t.Fatalf("expected the case to keep its log location, got %+v (ok=%v)", c, ok)
+	}
+	if len(pub.published) != 1 {
+		t.Fatalf("expected one published case log, got %+v", pub.published)
+	}
+	if got := pub.published[0]; got.ChannelID != 20 || got.UserID != "3" || got.ActorID != "2" || got.EventType != logging.LogEventModerationCase {
+		t.Fatalf("unexpected case log %+v", got)
+	}
+
+	ctx.GuildConfig.TestMode = true
+	l.record(ctx, caseActionBan, discord.UserID(4), "trial")
+	if len(pub.published) != 1 || len(pub.notified) != 1 || !pub.testMode[0] {
+		t.Fatalf("expected the simulated case queued in test mode, got published %d, notified %+v", len(pub.published), pub.notified)
+	}
+}
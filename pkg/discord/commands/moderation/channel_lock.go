@@ -0,0 +1,394 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/small-frappuccino/discordcore/pkg/keylock"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+// ChannelLockStore persists the overwrites channels had before a lock.
+// *postgres.Store satisfies it.
+type ChannelLockStore interface {
+	SaveChannelLock(ctx context.Context, lock coremod.ChannelLock) (bool, error)
+	GetChannelLock(ctx context.Context, guildID, channelID string) (coremod.ChannelLock, bool, error)
+	ListChannelLocks(ctx context.Context, guildID string) iter.Seq2[coremod.ChannelLock, error]
+	DeleteChannelLock(ctx context.Context, guildID, channelID string) error
+}
+
+// overwriteClient is the part of *api.Client that edits channel overwrites.
+type overwriteClient interface {
+	EditChannelPermission(channelID discord.ChannelID, overwriteID discord.Snowflake, data api.EditChannelPermissionData) error
+	DeleteChannelPermission(channelID discord.ChannelID, overwriteID discord.Snowflake, reason api.AuditLogReason) error
+}
+
+// lockableChannelTypes are the channels members can post in directly.
+// Threads follow their parent channel and categories do not propagate
+// overwrites to existing children, so neither is locked.
+var lockableChannelTypes = []discord.ChannelType{
+	discord.GuildText,
+	discord.GuildAnnouncement,
+	discord.GuildForum,
+	discord.GuildVoice,
+	discord.GuildStageVoice,
+}
+
+func lockable(t discord.ChannelType) bool {
+	for _, lt := range lockableChannelTypes {
+		if lt == t {
+			return true
+		}
+	}
+	return false
+}
+
+// channelLocker denies and restores Send Messages for @everyone. The
+// overwrite a channel had before its lock is stored first, so unlocking puts
+// back exactly what was there, including no overwrite at all.
+//
+// /lock and /unlock hold guilds for their whole run: an unlock restoring a
+// channel between a lock's record and its overwrite would leave the channel
+// locked with nothing to unlock it.
+type channelLocker struct {
+	store  ChannelLockStore
+	now    func() time.Time
+	guilds keylock.Mutex[discord.GuildID]
+}
+
+// lock denies Send Messages in ch. It reports false when ch was already
+// locked, either by an earlier lock or by its own overwrite.
+func (l *channelLocker) lock(client overwriteClient, guildID discord.GuildID, ch discord.Channel, actorID discord.UserID, reason string) (bool, error) {
+	everyone := discord.Snowflake(guildID)
+	rec := coremod.ChannelLock{
+		GuildID:   guildID.String(),
+		ChannelID: ch.ID.String(),
+		LockedBy:  actorID.String(),
+		LockedAt:  l.now(),
+	}
+	var allow, deny discord.Permissions
+	for _, ow := range ch.Overwrites {
+		if ow.ID == everyone && ow.Type == discord.OverwriteRole {
+			rec.HadOverwrite = true
+			allow, deny = ow.Allow, ow.Deny
+			rec.Allow, rec.Deny = uint64(allow), uint64(deny)
+		}
+	}
+	if deny.Has(discord.PermissionSendMessages) {
+		return false, nil
+	}
+
+	bg := context.Background()
+	saved, err := l.store.SaveChannelLock(bg, rec)
+	if err != nil || !saved {
+		return false, err
+	}
+	if err := client.EditChannelPermission(ch.ID, everyone, api.EditChannelPermissionData{
+		Type:           discord.OverwriteRole,
+		Allow:          allow &^ discord.PermissionSendMessages,
+		Deny:           deny | discord.PermissionSendMessages,
+		AuditLogReason: api.AuditLogReason(reason),
+	}); err != nil {
+		// The channel is unchanged, so the record must not outlive the attempt.
+		if derr := l.store.DeleteChannelLock(bg, rec.GuildID, rec.ChannelID); derr != nil {
+			err = fmt.Errorf("%w (lock record kept: %v)", err, derr)
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// sendDenied reports whether ch already denies Send Messages to @everyone,
+// so locking it would change nothing. It stands in for lock in test mode.
+func sendDenied(guildID discord.GuildID, ch discord.Channel) bool {
+	for _, ow := range ch.Overwrites {
+		if ow.ID == discord.Snowflake(guildID) && ow.Type == discord.OverwriteRole {
+			return ow.Deny.Has(discord.PermissionSendMessages)
+		}
+	}
+	return false
+}
+
+// unlock restores the overwrite recorded by lock and forgets the record.
+func (l *channelLocker) unlock(client overwriteClient, rec coremod.ChannelLock, reason string) error {
+	guildID, err := discord.ParseSnowflake(rec.GuildID)
+	if err != nil {
+		return err
+	}
+	channelID, err := discord.ParseSnowflake(rec.ChannelID)
+	if err != nil {
+		return err
+	}
+	if rec.HadOverwrite {
+		err = client.EditChannelPermission(discord.ChannelID(channelID), guildID, api.EditChannelPermissionData{
+			Type:           discord.OverwriteRole,
+			Allow:          discord.Permissions(rec.Allow),
+			Deny:           discord.Permissions(rec.Deny),
+			AuditLogReason: api.AuditLogReason(reason),
+		})
+	} else {
+		err = client.DeleteChannelPermission(discord.ChannelID(channelID), guildID, api.AuditLogReason(reason))
+	}
+	if err != nil {
+		return err
+	}
+	return l.store.DeleteChannelLock(context.Background(), rec.GuildID, rec.ChannelID)
+}
+
+func channelLockOptions(verb string) []discord.CommandOption {
+	return []discord.CommandOption{
+		&discord.ChannelOption{
+			OptionName:   "channel",
+			Description:  fmt.Sprintf("Channel to %s; defaults to this one", verb),
+			ChannelTypes: lockableChannelTypes,
+		},
+		&discord.BooleanOption{
+			OptionName:  "all",
+			Description: fmt.Sprintf("%s every channel in the server", strings.ToUpper(verb[:1])+verb[1:]),
+		},
+		reasonOption(fmt.Sprintf("Reason for the %s", verb)),
+	}
+}
+
+type channelLockArgs struct {
+	channelID discord.ChannelID
+	all       bool
+	reason    string
+}
+
+func parseChannelLockArgs(ctx *commands.ArikawaContext) channelLockArgs {
+	args := channelLockArgs{channelID: ctx.Interaction.ChannelID}
+	cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
+	for _, opt := range cmdData.Options {
+		switch opt.Name {
+		case "channel":
+			if val, err := opt.SnowflakeValue(); err == nil && val.IsValid() {
+				args.channelID = discord.ChannelID(val)
+			}
+		case "all":
+			if val, err := opt.BoolValue(); err == nil {
+				args.all = val
+			}
+		case "reason":
+			args.reason = strings.TrimSpace(opt.String())
+		}
+	}
+	return args
+}
+
+// LockCommand encapsulates the `/lock` slash command execution.
+type LockCommand struct {
+	locker  *channelLocker
+	cases   *caseLog
+	metrics Metrics
+	logger  *slog.Logger
+}
+
+func (c *LockCommand) Name() string        { return "lock" }
+func (c *LockCommand) Description() string { return "Stop members from sending messages in a channel" }
+func (c *LockCommand) Options() []discord.CommandOption {
+	return channelLockOptions("lock")
+}
+
+func (c *LockCommand) RequiresGuild() bool       { return true }
+func (c *LockCommand) RequiresPermissions() bool { return true }
+func (c *LockCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionManageChannels
+}
+
+func (c *LockCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("lock")
+
+	if ctx.Interaction == nil || ctx.Interaction.Data == nil || ctx.Interaction.Data.InteractionType() != discord.CommandInteractionType {
+		return nil
+	}
+	if err := ctx.Defer(discord.EphemeralMessage); err != nil {
+		return err
+	}
+	args := parseChannelLockArgs(ctx)
+	defer c.locker.guilds.Lock(ctx.GuildID)()
+
+	var targets []discord.Channel
+	if args.all {
+		channels, err := ctx.Client.Channels(ctx.GuildID)
+		if err != nil {
+			logChannelLockFailure(c.logger, ctx, "lock", args.channelID, err)
+			return respondEphemeral(ctx, "Failed to load the server's channels.")
+		}
+		for _, ch := range channels {
+			if lockable(ch.Type) {
+				targets = append(targets, ch)
+			}
+		}
+	} else {
+		ch, err := ctx.Client.Channel(args.channelID)
+		if err != nil || ch.GuildID != ctx.GuildID {
+			return respondEphemeral(ctx, "Invalid channel specified.")
+		}
+		if !lockable(ch.Type) {
+			return respondEphemeral(ctx, "That channel cannot be locked.")
+		}
+		targets = []discord.Channel{*ch}
+	}
+
+	c.logger.Info("Architectural state transition: Executing moderation action from slash command",
+		slog.String("command", "lock"),
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.Int("channels", len(targets)),
+	)
+	var locked, failed int
+	for _, ch := range targets {
+		if inTestMode(ctx) {
+			if !sendDenied(ctx.GuildID, ch) {
+				locked++
+			}
+			continue
+		}
+		ok, err := c.locker.lock(ctx.Client, ctx.GuildID, ch, ctx.UserID, args.reason)
+		switch {
+		case err != nil:
+			failed++
+			logChannelLockFailure(c.logger, ctx, "lock", ch.ID, err)
+		case ok:
+			locked++
+		}
+	}
+
+	if locked == 0 {
+		if failed > 0 {
+			return respondEphemeral(ctx, "Failed to lock the channel.")
+		}
+		return respondEphemeral(ctx, "Already locked; nothing was changed.")
+	}
+	var caseChannel discord.ChannelID
+	if !args.all {
+		caseChannel = targets[0].ID
+	}
+	recorded, ok := c.cases.recordChannel(ctx, coremod.CaseActionLock, caseChannel, args.reason)
+	if !args.all {
+		return respondEphemeral(ctx, fmt.Sprintf("Locked <#%s>%s.", caseChannel, caseSuffix(recorded, ok)))
+	}
+	return respondEphemeral(ctx, fmt.Sprintf("Locked %d channels%s%s.", locked, failureSuffix(failed), caseSuffix(recorded, ok)))
+}
+
+// UnlockCommand encapsulates the `/unlock` slash command execution. Only
+// channels locked with /lock can be unlocked, since only their previous
+// overwrites are known.
+type UnlockCommand struct {
+	locker  *channelLocker
+	cases   *caseLog
+	metrics Metrics
+	logger  *slog.Logger
+}
+
+func (c *UnlockCommand) Name() string { return "unlock" }
+func (c *UnlockCommand) Description() string {
+	return "Restore a channel locked with /lock to how it was before"
+}
+func (c *UnlockCommand) Options() []discord.CommandOption {
+	return channelLockOptions("unlock")
+}
+
+func (c *UnlockCommand) RequiresGuild() bool       { return true }
+func (c *UnlockCommand) RequiresPermissions() bool { return true }
+func (c *UnlockCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionManageChannels
+}
+
+func (c *UnlockCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("unlock")
+
+	if ctx.Interaction == nil || ctx.Interaction.Data == nil || ctx.Interaction.Data.InteractionType() != discord.CommandInteractionType {
+		return nil
+	}
+	if err := ctx.Defer(discord.EphemeralMessage); err != nil {
+		return err
+	}
+	args := parseChannelLockArgs(ctx)
+	defer c.locker.guilds.Lock(ctx.GuildID)()
+	guildID := ctx.GuildID.String()
+	bg := context.Background()
+
+	var locks []coremod.ChannelLock
+	if args.all {
+		for rec, err := range c.locker.store.ListChannelLocks(bg, guildID) {
+			if err != nil {
+				logChannelLockFailure(c.logger, ctx, "unlock", args.channelID, err)
+				return respondEphemeral(ctx, "Failed to load the locked channels.")
+			}
+			locks = append(locks, rec)
+		}
+	} else {
+		rec, found, err := c.locker.store.GetChannelLock(bg, guildID, args.channelID.String())
+		if err != nil {
+			logChannelLockFailure(c.logger, ctx, "unlock", args.channelID, err)
+			return respondEphemeral(ctx, "Failed to load the channel lock.")
+		}
+		if found {
+			locks = append(locks, rec)
+		}
+	}
+	if len(locks) == 0 {
+		if args.all {
+			return respondEphemeral(ctx, "No channels are locked.")
+		}
+		return respondEphemeral(ctx, fmt.Sprintf("<#%s> was not locked with /lock.", args.channelID))
+	}
+
+	c.logger.Info("Architectural state transition: Executing moderation action from slash command",
+		slog.String("command", "unlock"),
+		slog.String("guild_id", guildID),
+		slog.Int("channels", len(locks)),
+	)
+	var unlocked, failed int
+	for _, rec := range locks {
+		if inTestMode(ctx) {
+			unlocked++
+			continue
+		}
+		if err := c.locker.unlock(ctx.Client, rec, args.reason); err != nil {
+			failed++
+			channelID, _ := discord.ParseSnowflake(rec.ChannelID)
+			logChannelLockFailure(c.logger, ctx, "unlock", discord.ChannelID(channelID), err)
+			continue
+		}
+		unlocked++
+	}
+
+	if unlocked == 0 {
+		return respondEphemeral(ctx, "Failed to unlock the channel.")
+	}
+	var caseChannel discord.ChannelID
+	if !args.all {
+		caseChannel = args.channelID
+	}
+	recorded, ok := c.cases.recordChannel(ctx, coremod.CaseActionUnlock, caseChannel, args.reason)
+	if !args.all {
+		return respondEphemeral(ctx, fmt.Sprintf("Unlocked <#%s>%s.", caseChannel, caseSuffix(recorded, ok)))
+	}
+	return respondEphemeral(ctx, fmt.Sprintf("Unlocked %d channels%s%s.", unlocked, failureSuffix(failed), caseSuffix(recorded, ok)))
+}
+
+func logChannelLockFailure(logger *slog.Logger, ctx *commands.ArikawaContext, action string, channelID discord.ChannelID, err error) {
+	logger.Error("Blocking structural failure: Channel lock operation aborted",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("channel_id", channelID.String()),
+		slog.String("action", action),
+		slog.String("error", err.Error()),
+	)
+}
+
+func failureSuffix(failed int) string {
+	if failed == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d failed)", failed)
+}
@@ -0,0 +1,165 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+type memoryChannelLocks struct {
+	locks map[string]coremod.ChannelLock
+}
+
+func (m *memoryChannelLocks) SaveChannelLock(_ context.Context, lock coremod.ChannelLock) (bool, error) {
+	if _, ok := m.locks[lock.ChannelID]; ok {
+		return false, nil
+	}
+	m.locks[lock.ChannelID] = lock
+	return true, nil
+}
+
+func (m *memoryChannelLocks) GetChannelLock(_ context.Context, _, channelID string) (coremod.ChannelLock, bool, error) {
+	lock, ok := m.locks[channelID]
+	return lock, ok, nil
+}
+
+func (m *memoryChannelLocks) ListChannelLocks(context.Context, string) iter.Seq2[coremod.ChannelLock, error] {
+	return func(yield func(coremod.ChannelLock, error) bool) {
+		for _, lock := range m.locks {
+			if !yield(lock, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (m *memoryChannelLocks) DeleteChannelLock(_ context.Context, _, channelID string) error {
+	delete(m.locks, channelID)
+	return nil
+}
+
+// overwriteRecorder applies overwrite edits to a single channel.
+type overwriteRecorder struct {
+	ch      *discord.Channel
+	editErr error
+}
+
+func (r *overwriteRecorder) EditChannelPermission(_ discord.ChannelID, id discord.Snowflake, data api.EditChannelPermissionData) error {
+	if r.editErr != nil {
+		return r.editErr
+	}
+	r.remove(id)
+	r.ch.Overwrites = append(r.ch.Overwrites, discord.Overwrite{ID: id, Type: data.Type, Allow: data.Allow, Deny: data.Deny})
+	return nil
+}
+
+func (r *overwriteRecorder) DeleteChannelPermission(_ discord.ChannelID, id discord.Snowflake, _ api.AuditLogReason) error {
+	r.remove(id)
+	return nil
+}
+
+func (r *overwriteRecorder) remove(id discord.Snowflake) {
+	kept := r.ch.Overwrites[:0]
+	for _, ow := range r.ch.Overwrites {
+		if ow.ID != id {
+			kept = append(kept, ow)
+		}
+	}
+	r.ch.Overwrites = kept
+}
+
+func TestChannelLockerRestoresPreviousOverwrite(t *testing.T) {
+	t.Parallel()
+	const guildID = discord.GuildID(1)
+	everyone := discord.Snowflake(guildID)
+
+	cases := []struct {
+		name       string
+		overwrites []discord.Overwrite
+	}{
+		{"no overwrite", nil},
+		{"existing overwrite", []discord.Overwrite{{
+			ID:    everyone,
+			Type:  discord.OverwriteRole,
+			Allow: discord.PermissionSendMessages | discord.PermissionAddReactions,
+			Deny:  discord.PermissionAttachFiles,
+		}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ch := &discord.Channel{ID: 10, GuildID: guildID, Type: discord.GuildText, Overwrites: append([]discord.Overwrite(nil), tc.overwrites...)}
+			client := &overwriteRecorder{ch: ch}
+			store := &memoryChannelLocks{locks: map[string]coremod.ChannelLock{}}
+			locker := &channelLocker{store: store, now: time.Now}
+
+			ok, err := locker.lock(client, guildID, *ch, 5, "raid")
+			if err != nil || !ok {
+				t.Fatalf("lock: ok=%v, err=%v", ok, err)
+			}
+			if len(ch.Overwrites) != 1 || !ch.Overwrites[0].Deny.Has(discord.PermissionSendMessages) || ch.Overwrites[0].Allow.Has(discord.PermissionSendMessages) {
+				t.Fatalf("locked overwrites = %+v", ch.Overwrites)
+			}
+			if ok, err := locker.lock(client, guildID, *ch, 5, "again"); err != nil || ok {
+				t.Fatalf("relock: ok=%v, err=%v", ok, err)
+			}
+
+			rec, found, _ := store.GetChannelLock(context.Background(), "1", "10")
+			if !found {
+				t.Fatal("lock record missing")
+			}
+			if err := locker.unlock(client, rec, "over"); err != nil {
+				t.Fatalf("unlock: %v", err)
+			}
+			if len(ch.Overwrites) != len(tc.overwrites) || (len(tc.overwrites) == 1 && ch.Overwrites[0] != tc.overwrites[0]) {
+				t.Fatalf("restored overwrites = %+v, want %+v", ch.Overwrites, tc.overwrites)
+			}
+			if len(store.locks) != 0 {
+				t.Fatalf("lock record kept after unlock: %+v", store.locks)
+			}
+		})
+	}
+}
+
+func TestChannelLockerDropsRecordWhenEditFails(t *testing.T) {
+	t.Parallel()
+	ch := &discord.Channel{ID: 10, GuildID: 1, Type: discord.GuildText}
+	store := &memoryChannelLocks{locks: map[string]coremod.ChannelLock{}}
+	locker := &channelLocker{store: store, now: time.Now}
+
+	_, err := locker.lock(&overwriteRecorder{ch: ch, editErr: errors.New("missing permissions")}, 1, *ch, 5, "")
+	if err == nil {
+		t.Fatal("expected the edit error")
+	}
+	if len(store.locks) != 0 {
+		t.Fatalf("lock record kept for an unchanged channel: %+v", store.locks)
+	}
+}
+
+func TestCaseEmbedNamesLockedChannel(t *testing.T) {
+	t.Parallel()
+	one := caseEmbed(coremod.Case{CaseNumber: 3, Action: coremod.CaseActionLock, ChannelID: "10", ModeratorID: "2"})
+	all := caseEmbed(coremod.Case{CaseNumber: 4, Action: coremod.CaseActionLock, ModeratorID: "2"})
+
+	target := func(e discord.Embed) string {
+		for _, f := range e.Fields {
+			if f.Name == "Target" {
+				return f.Value
+			}
+		}
+		return ""
+	}
+	if got := target(one); !strings.Contains(got, "<#10>") {
+		t.Fatalf("single channel target = %q", got)
+	}
+	if got := target(all); got != "All channels" {
+		t.Fatalf("all channels target = %q", got)
+	}
+}
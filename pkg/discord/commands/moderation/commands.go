@@ -2,8 +2,10 @@ package moderation
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/diamondburned/arikawa/v3/api"
@@ -12,8 +14,11 @@ import (
 
 	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
 	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	discordlogging "github.com/small-frappuccino/discordcore/pkg/discord/logging"
 	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
 	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/task"
 )
 
 // Metrics defines observability hooks for moderation commands.
@@ -32,19 +37,155 @@ type InMemoryMetrics struct{}
 func (m *InMemoryMetrics) RecordCommandExec(name string)    {}
 func (m *InMemoryMetrics) Attach(ctx context.Context) error { return nil }
 
+// Option configures optional moderation command dependencies.
+type Option func(*groupOptions)
+
+type groupOptions struct {
+	warnings WarningStore
+	cases    CaseStore
+	notes    NoteStore
+	locks    ChannelLockStore
+	reports  discordmod.TransparencySource
+	raids    *discordmod.RaidGuard
+	pools    BanPoolStore
+	tasks    *task.TaskRouter
+}
+
+// WithWarnings enables /warn and /warnings backed by store. Without it neither
+// command is registered.
+func WithWarnings(store WarningStore) Option {
+	return func(o *groupOptions) { o.warnings = store }
+}
+
+// WithCases records bans, massbans, softbans, kicks and timeouts as numbered
+// cases backed by store and enables /case. Without it no cases are recorded.
+func WithCases(store CaseStore) Option {
+	return func(o *groupOptions) { o.cases = store }
+}
+
+// WithNotes enables /note backed by store. Without it the command is not
+// registered.
+func WithNotes(store NoteStore) Option {
+	return func(o *groupOptions) { o.notes = store }
+}
+
+// WithChannelLocks enables /lock and /unlock, storing the overwrites channels
+// had before a lock in store. Without it neither command is registered.
+func WithChannelLocks(store ChannelLockStore) Option {
+	return func(o *groupOptions) { o.locks = store }
+}
+
+// WithTransparency enables /transparency, compiling reports from src. Without
+// it the command is not registered.
+func WithTransparency(src discordmod.TransparencySource) Option {
+	return func(o *groupOptions) { o.reports = src }
+}
+
+// WithRaidMode enables /raidmode acting through guard. Without it the
+// command is not registered.
+func WithRaidMode(guard *discordmod.RaidGuard) Option {
+	return func(o *groupOptions) { o.raids = guard }
+}
+
+// WithBanPools adds the pool option to /ban, sharing bans with the other
+// guilds of the server's ban pools through store. Without it /ban has no
+// such option.
+func WithBanPools(store BanPoolStore) Option {
+	return func(o *groupOptions) { o.pools = store }
+}
+
+// WithTaskRouter runs /massban as tasks on router, which tells the moderator
+// when a run fails. Without it each run gets its own goroutine.
+func WithTaskRouter(router *task.TaskRouter) Option {
+	return func(o *groupOptions) { o.tasks = router }
+}
+
 // NewCommandGroup aggregates the moderation commands.
-func NewCommandGroup(svc *discordmod.Service, metrics Metrics, logger *slog.Logger) cmd.CommandGroup {
+func NewCommandGroup(svc *discordmod.Service, metrics Metrics, logger *slog.Logger, opts ...Option) cmd.CommandGroup {
 	if metrics == nil {
 		metrics = NopMetrics{}
 	}
 	if logger == nil {
 		logger = slog.Default()
 	}
-	return commands.NewLegacyAdapter(
-		&BanCommand{service: svc, metrics: metrics, logger: logger},
-		&TimeoutCommand{service: svc, metrics: metrics, logger: logger},
-		&MassBanCommand{service: svc, metrics: metrics, logger: logger},
-	)
+	var o groupOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var cases *caseLog
+	if o.cases != nil {
+		cases = &caseLog{store: o.cases, logger: logger}
+	}
+	ban := &BanCommand{service: svc, cases: cases, pools: o.pools, metrics: metrics, logger: logger}
+	kick := &KickCommand{service: svc, cases: cases, metrics: metrics, logger: logger}
+	softban := &SoftbanCommand{service: svc, cases: cases, metrics: metrics, logger: logger}
+	massBan := NewMassBanCommand(svc, metrics, logger)
+	massBan.cases = cases
+	if o.tasks != nil {
+		massBan.useTaskRouter(o.tasks)
+	}
+	cmds := []commands.ArikawaCommand{
+		ban,
+		softban,
+		kick,
+		&TimeoutCommand{service: svc, cases: cases, metrics: metrics, logger: logger},
+		massBan,
+		&BanlistCommand{cases: o.cases, metrics: metrics, logger: logger},
+		&ProtectCommand{metrics: metrics, logger: logger},
+		&TestModeCommand{metrics: metrics, logger: logger},
+		&AutomodCommand{metrics: metrics, logger: logger},
+		&StageCommand{metrics: metrics, logger: logger},
+	}
+	if o.warnings != nil {
+		cmds = append(cmds,
+			&WarnCommand{service: svc, store: o.warnings, cases: cases, metrics: metrics, logger: logger},
+			&WarningsCommand{store: o.warnings, notes: o.notes, metrics: metrics, logger: logger},
+		)
+	}
+	if o.notes != nil {
+		cmds = append(cmds, &NoteCommand{store: o.notes, metrics: metrics, logger: logger})
+	}
+	if o.locks != nil {
+		locker := &channelLocker{store: o.locks, now: time.Now}
+		cmds = append(cmds,
+			&LockCommand{locker: locker, cases: cases, metrics: metrics, logger: logger},
+			&UnlockCommand{locker: locker, cases: cases, metrics: metrics, logger: logger},
+		)
+	}
+	if cases != nil {
+		cmds = append(cmds, &CaseCommand{cases: cases, metrics: metrics, logger: logger})
+	}
+	if o.reports != nil {
+		cmds = append(cmds, &TransparencyCommand{src: o.reports, metrics: metrics, logger: logger, now: time.Now})
+	}
+	if o.raids != nil {
+		cmds = append(cmds, &RaidModeCommand{guard: o.raids, metrics: metrics, logger: logger})
+	}
+	return &commandGroup{
+		CommandGroup: commands.NewLegacyAdapter(cmds...),
+		runner:       massBan.runner,
+		ban:          ban,
+		softban:      softban,
+		kick:         kick,
+	}
+}
+
+// commandGroup adds the mass-action cancel button and reason modal routes to
+// the slash commands.
+type commandGroup struct {
+	cmd.CommandGroup
+	runner  *massActionRunner
+	ban     *BanCommand
+	softban *SoftbanCommand
+	kick    *KickCommand
+}
+
+// Handle handles.
+func (g *commandGroup) Handle(guildID string, botProfileID string) map[string]cmd.CommandHandler {
+	routes := g.CommandGroup.Handle(guildID, botProfileID)
+	routes[massActionCancelRoute] = g.runner.HandleCancel
+	routes[reasonModalRoute] = g.handleReasonModal
+	return routes
 }
 
 // NewBanCommand is deprecated.
@@ -61,6 +202,8 @@ func NewBanCommand(svc *discordmod.Service, metrics Metrics, logger *slog.Logger
 // BanCommand encapsulates the `/ban` slash command execution.
 type BanCommand struct {
 	service *discordmod.Service
+	cases   *caseLog
+	pools   BanPoolStore
 	metrics Metrics
 	logger  *slog.Logger
 }
@@ -68,18 +211,22 @@ type BanCommand struct {
 func (c *BanCommand) Name() string        { return "ban" }
 func (c *BanCommand) Description() string { return "Ban a user from the server" }
 func (c *BanCommand) Options() []discord.CommandOption {
-	return []discord.CommandOption{
+	opts := []discord.CommandOption{
 		&discord.UserOption{
 			OptionName:  "user",
 			Description: "User to ban",
 			Required:    true,
 		},
-		&discord.StringOption{
-			OptionName:  "reason",
-			Description: "Reason for the ban",
-			Required:    false,
-		},
+		reasonOption("Reason for the ban"),
+		deleteMessagesOption(),
 	}
+	if c.pools != nil {
+		opts = append(opts, &discord.BooleanOption{
+			OptionName:  banPoolOptionName,
+			Description: "Also ban the user in the servers sharing this server's ban pools",
+		})
+	}
+	return opts
 }
 
 func (c *BanCommand) RequiresGuild() bool       { return true }
@@ -97,6 +244,8 @@ func (c *BanCommand) Handle(ctx *commands.ArikawaContext) error {
 
 	var userID discord.UserID
 	var reason string
+	var pool bool
+	deleteDays := banDeleteDays(ctx)
 
 	if ctx.Interaction != nil && ctx.Interaction.Data != nil && ctx.Interaction.Data.InteractionType() == discord.CommandInteractionType {
 		cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
@@ -108,7 +257,15 @@ func (c *BanCommand) Handle(ctx *commands.ArikawaContext) error {
 					userID = discord.UserID(val)
 				}
 			case "reason":
-				reason = opt.String()
+				reason = strings.TrimSpace(opt.String())
+			case deleteMessagesOptionName:
+				if val, err := opt.IntValue(); err == nil {
+					deleteDays = int(val)
+				}
+			case banPoolOptionName:
+				if val, err := opt.BoolValue(); err == nil {
+					pool = val
+				}
 			}
 		}
 	}
@@ -117,14 +274,33 @@ func (c *BanCommand) Handle(ctx *commands.ArikawaContext) error {
 		return respondEphemeral(ctx, "Invalid user specified.")
 	}
 
+	if msg, ok := authorizeTarget(ctx, c.service, c.logger, userID); !ok {
+		return respondEphemeral(ctx, msg)
+	}
+
+	if reason == "" {
+		return openReasonModal(ctx, reasonModalRequest{Action: "ban", Target: userID, DeleteDays: deleteDays, Pool: pool})
+	}
+	return c.execute(ctx, userID, deleteDays, reason, pool)
+}
+
+// execute bans userID once the target is authorized and the reason is known.
+// With pool set the ban is also shared with the server's ban pools.
+func (c *BanCommand) execute(ctx *commands.ArikawaContext, userID discord.UserID, deleteDays int, reason string, pool bool) error {
+	if msg, ok := reserveActions(ctx, c.service, c.logger, 1); !ok {
+		return respondEphemeral(ctx, msg)
+	}
 	c.logger.Info("Architectural state transition: Executing moderation action from slash command",
 		slog.String("command", "ban"),
 		slog.String("guild_id", ctx.GuildID.String()),
 		slog.String("target_id", userID.String()),
+		slog.Int("delete_days", deleteDays),
 	)
 
-	err := c.service.Ban(context.Background(), ctx.GuildID, userID, 0, reason)
+	n := notifyTarget(ctx, c.cases, c.logger, caseActionBan, userID, reason, time.Time{})
+	err := c.service.Ban(actionContext(ctx), ctx.GuildID, userID, deleteDays*secondsPerDay, reason)
 	if err != nil {
+		c.service.ReleaseActions(ctx.GuildID, ctx.UserID, 1)
 		c.logger.Error("Blocking structural failure: Ban command execution aborted",
 			slog.String("guild_id", ctx.GuildID.String()),
 			slog.String("error", err.Error()),
@@ -132,12 +308,135 @@ func (c *BanCommand) Handle(ctx *commands.ArikawaContext) error {
 		return respondEphemeral(ctx, "Failed to ban the user.")
 	}
 
-	return respondEphemeral(ctx, fmt.Sprintf("Successfully banned user %s.", userID))
+	recorded, ok := c.cases.recordNoticed(ctx, caseActionBan, userID, reason, n)
+	var shared string
+	if pool && !inTestMode(ctx) {
+		shared = c.share(ctx, userID, reason, recorded.CaseNumber)
+	}
+	return respondEphemeral(ctx, fmt.Sprintf("Successfully banned user %s%s.%s%s", userID, caseSuffix(recorded, ok), dmSuffix(n), shared))
+}
+
+// KickCommand encapsulates the `/kick` slash command execution.
+type KickCommand struct {
+	service *discordmod.Service
+	cases   *caseLog
+	metrics Metrics
+	logger  *slog.Logger
+}
+
+func (c *KickCommand) Name() string        { return "kick" }
+func (c *KickCommand) Description() string { return "Kick a member from the server" }
+func (c *KickCommand) Options() []discord.CommandOption {
+	return []discord.CommandOption{
+		&discord.UserOption{
+			OptionName:  "user",
+			Description: "Member to kick",
+			Required:    true,
+		},
+		reasonOption("Reason for the kick"),
+	}
+}
+
+func (c *KickCommand) RequiresGuild() bool       { return true }
+func (c *KickCommand) RequiresPermissions() bool { return true }
+func (c *KickCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionKickMembers
+}
+
+func (c *KickCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("kick")
+
+	var userID discord.UserID
+	var reason string
+
+	if ctx.Interaction != nil && ctx.Interaction.Data != nil && ctx.Interaction.Data.InteractionType() == discord.CommandInteractionType {
+		cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
+		for _, opt := range cmdData.Options {
+			switch opt.Name {
+			case "user":
+				if val, err := opt.SnowflakeValue(); err == nil {
+					userID = discord.UserID(val)
+				}
+			case "reason":
+				reason = strings.TrimSpace(opt.String())
+			}
+		}
+	}
+
+	if !userID.IsValid() {
+		return respondEphemeral(ctx, "Invalid user specified.")
+	}
+
+	if msg, ok := authorizeTarget(ctx, c.service, c.logger, userID); !ok {
+		return respondEphemeral(ctx, msg)
+	}
+
+	if reason == "" {
+		return openReasonModal(ctx, reasonModalRequest{Action: "kick", Target: userID})
+	}
+	return c.execute(ctx, userID, reason)
+}
+
+// execute kicks userID once the target is authorized and the reason is known.
+func (c *KickCommand) execute(ctx *commands.ArikawaContext, userID discord.UserID, reason string) error {
+	if msg, ok := reserveActions(ctx, c.service, c.logger, 1); !ok {
+		return respondEphemeral(ctx, msg)
+	}
+	c.logger.Info("Architectural state transition: Executing moderation action from slash command",
+		slog.String("command", "kick"),
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("target_id", userID.String()),
+	)
+
+	n := notifyTarget(ctx, c.cases, c.logger, caseActionKick, userID, reason, time.Time{})
+	if err := c.service.Kick(actionContext(ctx), ctx.GuildID, userID, api.AuditLogReason(reason)); err != nil {
+		c.service.ReleaseActions(ctx.GuildID, ctx.UserID, 1)
+		c.logger.Error("Blocking structural failure: Kick command execution aborted",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("error", err.Error()),
+		)
+		return respondEphemeral(ctx, "Failed to kick the member.")
+	}
+
+	recorded, ok := c.cases.recordNoticed(ctx, caseActionKick, userID, reason, n)
+	return respondEphemeral(ctx, fmt.Sprintf("Successfully kicked user %s%s.%s", userID, caseSuffix(recorded, ok), dmSuffix(n)))
+}
+
+const (
+	deleteMessagesOptionName = "delete_messages"
+	maxBanDeleteDays         = 7
+	secondsPerDay            = 24 * 60 * 60
+)
+
+// deleteMessagesOption is the ban option selecting how many days of the
+// target's messages Discord removes along with the ban.
+func deleteMessagesOption() discord.CommandOption {
+	return &discord.IntegerOption{
+		OptionName:  deleteMessagesOptionName,
+		Description: "Days of the user's messages to delete (0-7, defaults to the server setting)",
+		Required:    false,
+		Min:         option.NewInt(0),
+		Max:         option.NewInt(maxBanDeleteDays),
+	}
+}
+
+// banDeleteDays reads the guild's default message deletion window for bans.
+func banDeleteDays(ictx *commands.ArikawaContext) int {
+	if ictx.Config == nil {
+		return 0
+	}
+	cfg := ictx.Config.Config()
+	if cfg == nil {
+		return 0
+	}
+	days := cfg.ResolveRuntimeConfig(ictx.GuildID.String()).BanDeleteMessageDays
+	return min(max(days, 0), maxBanDeleteDays)
 }
 
 // TimeoutCommand encapsulates the `/timeout` slash command execution.
 type TimeoutCommand struct {
 	service *discordmod.Service
+	cases   *caseLog
 	metrics Metrics
 	logger  *slog.Logger
 }
@@ -204,7 +503,12 @@ func (c *TimeoutCommand) Handle(ctx *commands.ArikawaContext) error {
 		return respondEphemeral(ctx, "Invalid user specified.")
 	}
 
-	until := discord.NewTimestamp(time.Now().Add(time.Duration(minutes) * time.Minute))
+	end := time.Now().Add(time.Duration(minutes) * time.Minute)
+	until := discord.NewTimestamp(end)
+
+	if msg, ok := authorizeTarget(ctx, c.service, c.logger, userID); !ok {
+		return respondEphemeral(ctx, msg)
+	}
 
 	c.logger.Info("Architectural state transition: Executing moderation action from slash command",
 		slog.String("command", "timeout"),
@@ -212,7 +516,9 @@ func (c *TimeoutCommand) Handle(ctx *commands.ArikawaContext) error {
 		slog.String("target_id", userID.String()),
 	)
 
-	err := c.service.Timeout(context.Background(), ctx.GuildID, userID, until)
+	reason := fmt.Sprintf("Timed out for %d minutes", minutes)
+	n := notifyTarget(ctx, c.cases, c.logger, caseActionTimeout, userID, reason, end)
+	err := c.service.Timeout(actionContext(ctx), ctx.GuildID, userID, until)
 	if err != nil {
 		c.logger.Error("Blocking structural failure: Timeout command execution aborted",
 			slog.String("guild_id", ctx.GuildID.String()),
@@ -221,79 +527,128 @@ func (c *TimeoutCommand) Handle(ctx *commands.ArikawaContext) error {
 		return respondEphemeral(ctx, "Failed to timeout the user.")
 	}
 
-	return respondEphemeral(ctx, fmt.Sprintf("Successfully timed out user %s.", userID))
+	recorded, ok := c.cases.recordTimed(ctx, caseActionTimeout, userID, reason, end, n)
+	return respondEphemeral(ctx, fmt.Sprintf("Successfully timed out user %s%s.%s", userID, caseSuffix(recorded, ok), dmSuffix(n)))
 }
 
-func respondEphemeral(ctx *commands.ArikawaContext, msg string) error {
-	_, err := ctx.Client.EditInteractionResponse(ctx.Interaction.AppID, ctx.Interaction.Token, api.EditInteractionResponseData{
-		Content: option.NewNullableString(msg),
-	})
-	return err
+// authorizeTarget runs the shared hierarchy check for one target and returns
+// the message to show the invoker when the action is refused.
+func authorizeTarget(ctx *commands.ArikawaContext, svc *discordmod.Service, logger *slog.Logger, target discord.UserID) (string, bool) {
+	err := svc.Authorize(context.Background(), ctx.GuildID, ctx.UserID, target, protectedTargets(ctx))
+	switch {
+	case err == nil:
+		return "", true
+	case errors.Is(err, discordmod.ErrTargetIsOwner):
+		return "You cannot moderate the server owner.", false
+	case errors.Is(err, discordmod.ErrTargetProtected):
+		return "This member is protected from moderation commands on this server.", false
+	case errors.Is(err, discordmod.ErrActorOutranked):
+		return "You cannot moderate a member whose highest role is equal to or above yours.", false
+	case errors.Is(err, discordmod.ErrBotOutranked):
+		return "My highest role must be above the member's highest role.", false
+	case errors.Is(err, coremod.ErrActionRateExceeded):
+		return lockoutMessage(err), false
+	default:
+		logger.Warn("Mitigated service degradation: Moderation hierarchy check failed",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("target_id", target.String()),
+			slog.String("error", err.Error()),
+		)
+		return "Could not verify the role hierarchy for this action.", false
+	}
 }
 
-// MassBanCommand encapsulates the `/massban` execution utilizing core logic.
-type MassBanCommand struct {
-	service *discordmod.Service
-	metrics Metrics
-	logger  *slog.Logger
+// reserveActions counts n destructive actions by the invoker against the
+// guild's per-moderator limit and returns the message to show when the
+// invoker is locked out. The lockout that starts here is announced in the
+// moderation case channel. Actions that then fail are handed back with
+// svc.ReleaseActions.
+func reserveActions(ctx *commands.ArikawaContext, svc *discordmod.Service, logger *slog.Logger, n int) (string, bool) {
+	limit := moderatorActionLimit(ctx)
+	err := svc.ReserveActions(context.Background(), ctx.GuildID, ctx.UserID, n, limit)
+	switch {
+	case err == nil:
+		return "", true
+	case errors.Is(err, coremod.ErrActionRateExceeded):
+		var rateErr *coremod.ActionRateError
+		if errors.As(err, &rateErr) && rateErr.Tripped {
+			alertLockout(ctx, limit, rateErr.Until)
+		}
+		return lockoutMessage(err), false
+	default:
+		logger.Warn("Mitigated service degradation: Moderator action limit check failed",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("error", err.Error()),
+		)
+		return "Could not verify your moderation limits for this action.", false
+	}
 }
 
-func NewMassBanCommand(svc *discordmod.Service, metrics Metrics, logger *slog.Logger) *MassBanCommand {
-	if metrics == nil {
-		metrics = NopMetrics{}
+// moderatorActionLimit reads the guild's destructive action limit.
+func moderatorActionLimit(ictx *commands.ArikawaContext) coremod.ActionRateLimit {
+	if ictx.Config == nil {
+		return coremod.ActionRateLimit{}
 	}
-	if logger == nil {
-		logger = slog.Default()
+	cfg := ictx.Config.Config()
+	if cfg == nil {
+		return coremod.ActionRateLimit{}
+	}
+	rc := cfg.ResolveRuntimeConfig(ictx.GuildID.String())
+	return coremod.ActionRateLimit{
+		Max:    rc.ModeratorActionLimit,
+		Window: time.Duration(rc.ModeratorActionWindowMinutes) * time.Minute,
 	}
-	return &MassBanCommand{service: svc, metrics: metrics, logger: logger}
 }
 
-func (c *MassBanCommand) Name() string        { return "massban" }
-func (c *MassBanCommand) Description() string { return "Ban multiple users at once" }
-func (c *MassBanCommand) Options() []discord.CommandOption {
-	return []discord.CommandOption{
-		&discord.StringOption{
-			OptionName:  "users",
-			Description: "Comma separated list of user IDs",
-			Required:    true,
-		},
+// alertLockout posts the lockout of the invoker to the moderation case
+// channel, so the rest of the staff learns of it.
+func alertLockout(ctx *commands.ArikawaContext, limit coremod.ActionRateLimit, until time.Time) {
+	pub, channelID, ok := caseLogTarget(ctx)
+	if !ok {
+		return
 	}
+	pub.Notify(ctx.Context(), discordlogging.LogPost{
+		GuildID:   ctx.GuildID.String(),
+		ChannelID: channelID,
+		EventType: logging.LogEventModerationCase,
+		ActorID:   ctx.UserID.String(),
+		Embed:     discordmod.LockoutEmbed(ctx.UserID, limit, until),
+	})
 }
 
-func (c *MassBanCommand) RequiresGuild() bool       { return true }
-func (c *MassBanCommand) RequiresPermissions() bool { return true }
-func (c *MassBanCommand) DefaultMemberPermissions() discord.Permissions {
-	return discord.PermissionBanMembers
+func lockoutMessage(err error) string {
+	var rateErr *coremod.ActionRateError
+	if errors.As(err, &rateErr) {
+		return fmt.Sprintf("You have exceeded this server's limit on bans and kicks. Your moderation actions are paused until %s.", logging.DiscordTimestamp(rateErr.Until, logging.TimestampRelative))
+	}
+	return "You have exceeded this server's limit on bans and kicks."
 }
 
-func (c *MassBanCommand) Handle(ctx *commands.ArikawaContext) error {
-	c.metrics.RecordCommandExec("massban")
+// inTestMode reports whether the invoking guild is in test mode.
+func inTestMode(ctx *commands.ArikawaContext) bool {
+	return ctx.GuildConfig != nil && ctx.GuildConfig.TestMode
+}
 
-	var rawUsers string
-	if ctx.Interaction != nil && ctx.Interaction.Data != nil && ctx.Interaction.Data.InteractionType() == discord.CommandInteractionType {
-		cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
-		for _, opt := range cmdData.Options {
-			if opt.Name == "users" {
-				rawUsers = opt.String()
-			}
-		}
+// actionContext is the context moderation actions run under, simulating them
+// while the invoking guild is in test mode.
+func actionContext(ctx *commands.ArikawaContext) context.Context {
+	if inTestMode(ctx) {
+		return coremod.WithTestMode(context.Background())
 	}
+	return context.Background()
+}
 
-	// Delegate ID normalization to the purely Discord-agnostic core package
-	validIDs, _ := coremod.ParseMemberIDs(rawUsers)
-
-	c.logger.Info("Architectural state transition: Executing mass moderation action from slash command",
-		slog.String("command", "massban"),
-		slog.String("guild_id", ctx.GuildID.String()),
-		slog.Int("target_count", len(validIDs)),
-	)
-
-	for _, idStr := range validIDs {
-		sf, err := discord.ParseSnowflake(idStr)
-		if err == nil {
-			_ = c.service.Ban(context.Background(), ctx.GuildID, discord.UserID(sf), 0, "Massban")
-		}
+func respondEphemeral(ctx *commands.ArikawaContext, msg string) error {
+	if inTestMode(ctx) {
+		msg += "\n-# Test mode is on: moderation actions are only simulated."
 	}
+	return editResponse(ctx, msg)
+}
 
-	return respondEphemeral(ctx, fmt.Sprintf("Massban processed %d users.", len(validIDs)))
+// editResponse edits the deferred response to msg as is.
+func editResponse(ctx *commands.ArikawaContext, msg string) error {
+	_, err := ctx.Client.EditInteractionResponse(ctx.Interaction.AppID, ctx.Interaction.Token, api.EditInteractionResponseData{
+		Content: option.NewNullableString(msg),
+	})
+	return err
 }
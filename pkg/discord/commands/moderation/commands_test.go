@@ -27,6 +27,9 @@ func (m *mockClient) Ban(guildID discord.GuildID, userID discord.UserID, data ap
 func (m *mockClient) Kick(guildID discord.GuildID, userID discord.UserID, reason api.AuditLogReason) error {
 	return nil
 }
+func (m *mockClient) Unban(guildID discord.GuildID, userID discord.UserID, reason api.AuditLogReason) error {
+	return nil
+}
 func (m *mockClient) ModifyMember(guildID discord.GuildID, userID discord.UserID, data api.ModifyMemberData) error {
 	m.timeoutCalled = true
 	return nil
@@ -90,3 +93,23 @@ func TestMassBanCommand_Parity(t *testing.T) {
 		t.Errorf("expected massban name")
 	}
 }
+
+// TestSoftbanCommand_DefaultsToDeletingMessages ensures a softban removes at
+// least a day of messages when the guild keeps messages on ban.
+func TestSoftbanCommand_DefaultsToDeletingMessages(t *testing.T) {
+	t.Parallel()
+
+	if got := softbanDeleteDays(&commands.ArikawaContext{}); got != 1 {
+		t.Fatalf("softbanDeleteDays() = %d, want 1", got)
+	}
+	cmd := &SoftbanCommand{}
+	var hasDelete bool
+	for _, opt := range cmd.Options() {
+		if opt.Name() == deleteMessagesOptionName {
+			hasDelete = true
+		}
+	}
+	if !hasDelete {
+		t.Fatal("softban must expose the delete_messages option")
+	}
+}
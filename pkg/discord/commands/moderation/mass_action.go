@@ -0,0 +1,248 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const (
+	// massActionCancelRoute is the component route of the cancel button shown
+	// under a running mass action. The run ID follows the separator.
+	massActionCancelRoute = "massaction:cancel|"
+	// massActionProgressEvery is how many processed targets pass between
+	// progress edits of the interaction response.
+	massActionProgressEvery = 25
+	progressBarWidth        = 20
+)
+
+// massActionJob describes one mass action for massActionRunner.Run.
+type massActionJob struct {
+	// Title names the action in the progress embed, e.g. "Massban".
+	Title string
+	// Verb describes a completed target in the summary, e.g. "banned".
+	Verb    string
+	Targets []string
+	// Preset holds results known before the run, such as unparsable input.
+	Preset []coremod.MassActionResult
+	Step   coremod.MassActionStep
+}
+
+type massActionRun struct {
+	owner  discord.UserID
+	cancel context.CancelFunc
+}
+
+// massActionRunner executes mass actions behind a progress embed with a
+// cancel button, and tracks running actions so the button can stop them.
+type massActionRunner struct {
+	logger *slog.Logger
+	seq    atomic.Uint64
+
+	mu   sync.Mutex
+	runs map[string]massActionRun
+}
+
+func newMassActionRunner(logger *slog.Logger) *massActionRunner {
+	return &massActionRunner{logger: logger, runs: make(map[string]massActionRun)}
+}
+
+// Run executes job to completion, editing ictx's interaction response with
+// progress and finally with a summary and a CSV report. It returns the
+// outcome for every target, presets included. Only the cancel button stops a
+// run early: progress edits pause once the interaction token is about to
+// expire, and the summary then goes to the channel instead.
+func (r *massActionRunner) Run(ictx *commands.ArikawaContext, job massActionJob) []coremod.MassActionResult {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runID := r.register(ictx.UserID, cancel)
+	defer r.release(runID)
+
+	total := len(job.Targets)
+	r.edit(ictx, api.EditInteractionResponseData{
+		Embeds:     &[]discord.Embed{progressEmbed(job.Title, 0, total)},
+		Components: cancelComponents(runID),
+	})
+
+	results := coremod.RunMassAction(ctx, job.Targets, coremod.MassActionOptions{
+		PerSecond:     massActionRate(ictx),
+		ProgressEvery: massActionProgressEvery,
+		OnProgress: func(done, total int) {
+			if ictx.Interaction != nil && commands.InteractionTokenExpiring(ictx.Interaction, time.Now()) {
+				return
+			}
+			r.edit(ictx, api.EditInteractionResponseData{
+				Embeds:     &[]discord.Embed{progressEmbed(job.Title, done, total)},
+				Components: cancelComponents(runID),
+			})
+		},
+	}, job.Step)
+	results = append(job.Preset, results...)
+
+	summary := fmt.Sprintf("%s finished: %s.", job.Title, coremod.SummarizeMassAction(job.Verb, results))
+	r.logger.Info("Operational telemetry: Mass action completed",
+		slog.String("action", job.Title),
+		slog.String("guild_id", ictx.GuildID.String()),
+		slog.String("summary", summary),
+	)
+
+	data := api.EditInteractionResponseData{
+		Embeds:     &[]discord.Embed{summaryEmbed(job.Title, summary, results)},
+		Components: &discord.ContainerComponents{},
+	}
+	if report, err := coremod.MassActionReportCSV(results); err == nil {
+		name := strings.ToLower(strings.ReplaceAll(job.Title, " ", "-")) + "-report.csv"
+		data.Files = []sendpart.File{{Name: name, Reader: bytes.NewReader(report)}}
+	}
+	r.deliver(ictx, data)
+	return results
+}
+
+// Cancel stops runID when userID started it.
+func (r *massActionRunner) Cancel(runID string, userID discord.UserID) bool {
+	r.mu.Lock()
+	run, ok := r.runs[runID]
+	r.mu.Unlock()
+	if !ok || run.owner != userID {
+		return false
+	}
+	run.cancel()
+	return true
+}
+
+// HandleCancel serves the cancel button.
+func (r *massActionRunner) HandleCancel(ctx *cmd.Context) error {
+	data, ok := ctx.Event.Data.(discord.ComponentInteraction)
+	if !ok {
+		return nil
+	}
+	runID := strings.TrimPrefix(string(data.ID()), massActionCancelRoute)
+
+	resp := api.InteractionResponse{Type: api.DeferredMessageUpdate}
+	if !r.Cancel(runID, ctx.UserID) {
+		resp = api.InteractionResponse{
+			Type: api.MessageInteractionWithSource,
+			Data: &api.InteractionResponseData{
+				Content: option.NewNullableString("This action has already finished or was started by someone else."),
+				Flags:   discord.EphemeralMessage,
+			},
+		}
+	}
+	return ctx.Client.RespondInteraction(ctx.Event.ID, ctx.Event.Token, resp)
+}
+
+func (r *massActionRunner) register(owner discord.UserID, cancel context.CancelFunc) string {
+	runID := strconv.FormatUint(r.seq.Add(1), 36) + "-" + strconv.FormatInt(time.Now().UnixNano()%1e6, 36)
+	r.mu.Lock()
+	r.runs[runID] = massActionRun{owner: owner, cancel: cancel}
+	r.mu.Unlock()
+	return runID
+}
+
+func (r *massActionRunner) release(runID string) {
+	r.mu.Lock()
+	delete(r.runs, runID)
+	r.mu.Unlock()
+}
+
+func (r *massActionRunner) edit(ictx *commands.ArikawaContext, data api.EditInteractionResponseData) {
+	if ictx.Client == nil || ictx.Interaction == nil {
+		return
+	}
+	if _, err := ictx.Client.EditInteractionResponse(ictx.Interaction.AppID, ictx.Interaction.Token, data); err != nil {
+		r.logger.Warn("Mitigated service degradation: Mass action response could not be updated",
+			slog.String("guild_id", ictx.GuildID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// deliver posts the summary of a run, in the channel once the interaction
+// token is about to expire.
+func (r *massActionRunner) deliver(ictx *commands.ArikawaContext, data api.EditInteractionResponseData) {
+	if ictx.Client == nil || ictx.Interaction == nil {
+		return
+	}
+	viaChannel, err := commands.DeliverFinal(ictx.Client, ictx.Interaction, data, time.Now())
+	if err != nil {
+		r.logger.Warn("Mitigated service degradation: Mass action summary could not be delivered",
+			slog.String("guild_id", ictx.GuildID.String()),
+			slog.Bool("via_channel", viaChannel),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// massActionRate reads the guild's configured actions per second.
+func massActionRate(ictx *commands.ArikawaContext) int {
+	if ictx.Config == nil {
+		return coremod.DefaultMassActionRate
+	}
+	cfg := ictx.Config.Config()
+	if cfg == nil {
+		return coremod.DefaultMassActionRate
+	}
+	if rate := cfg.ResolveRuntimeConfig(ictx.GuildID.String()).MassActionsPerSecond; rate > 0 {
+		return rate
+	}
+	return coremod.DefaultMassActionRate
+}
+
+func cancelComponents(runID string) *discord.ContainerComponents {
+	return &discord.ContainerComponents{
+		&discord.ActionRowComponent{
+			&discord.ButtonComponent{
+				Label:    "Cancel",
+				CustomID: discord.ComponentID(massActionCancelRoute + runID),
+				Style:    discord.DangerButtonStyle(),
+			},
+		},
+	}
+}
+
+func progressEmbed(title string, done, total int) discord.Embed {
+	return discord.Embed{
+		Title:       title + " in progress",
+		Description: fmt.Sprintf("`%s` %d/%d", progressBar(done, total), done, total),
+		Color:       discord.Color(theme.Info()),
+	}
+}
+
+func summaryEmbed(title, summary string, results []coremod.MassActionResult) discord.Embed {
+	color := theme.Success()
+	for _, r := range results {
+		if r.Status == coremod.MassActionFailed || r.Status == coremod.MassActionCancelled {
+			color = theme.Warning()
+			break
+		}
+	}
+	return discord.Embed{
+		Title:       title + " finished",
+		Description: summary + "\nThe attached report lists the outcome for every entry.",
+		Color:       discord.Color(color),
+	}
+}
+
+func progressBar(done, total int) string {
+	filled := progressBarWidth
+	if total > 0 {
+		filled = done * progressBarWidth / total
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+}
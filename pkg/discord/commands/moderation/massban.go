@@ -0,0 +1,314 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/task"
+)
+
+const (
+	// maxMassBanFileSize bounds an uploaded ID list; 1 MiB holds roughly
+	// 50,000 snowflakes.
+	maxMassBanFileSize = 1 << 20
+
+	// massBanTaskType is the task type massbans run as on a task router.
+	massBanTaskType = "moderation.massban"
+)
+
+// MassBanCommand encapsulates the `/massban` execution utilizing core logic.
+type MassBanCommand struct {
+	service    *discordmod.Service
+	cases      *caseLog
+	metrics    Metrics
+	logger     *slog.Logger
+	runner     *massActionRunner
+	httpClient *http.Client
+	tasks      *task.TaskRouter
+}
+
+// massBanRun is the payload of a massban task.
+type massBanRun struct {
+	ictx       *commands.ArikawaContext
+	validIDs   []string
+	invalid    []string
+	reason     string
+	deleteDays int
+}
+
+func NewMassBanCommand(svc *discordmod.Service, metrics Metrics, logger *slog.Logger) *MassBanCommand {
+	if metrics == nil {
+		metrics = NopMetrics{}
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &MassBanCommand{service: svc, metrics: metrics, logger: logger, runner: newMassActionRunner(logger)}
+}
+
+// useTaskRouter runs massbans as tasks on router, one at a time per guild
+// and within the guild's share of the workers. A run that fails reports
+// back to the moderator who started it.
+func (c *MassBanCommand) useTaskRouter(router *task.TaskRouter) {
+	c.tasks = router
+	router.RegisterHandler(massBanTaskType, func(_ context.Context, payload any) error {
+		r, ok := payload.(massBanRun)
+		if !ok {
+			return fmt.Errorf("massban task: unexpected payload %T", payload)
+		}
+		c.run(r.ictx, r.validIDs, r.invalid, r.reason, r.deleteDays)
+		return nil
+	})
+}
+
+func (c *MassBanCommand) Name() string        { return "massban" }
+func (c *MassBanCommand) Description() string { return "Ban multiple users at once" }
+func (c *MassBanCommand) Options() []discord.CommandOption {
+	return []discord.CommandOption{
+		&discord.StringOption{
+			OptionName:  "users",
+			Description: "Comma separated list of user IDs",
+			Required:    false,
+		},
+		&discord.AttachmentOption{
+			OptionName:  "file",
+			Description: "A .txt or .csv file of user IDs",
+			Required:    false,
+		},
+		reasonOption("Reason for the bans"),
+		deleteMessagesOption(),
+	}
+}
+
+func (c *MassBanCommand) RequiresGuild() bool       { return true }
+func (c *MassBanCommand) RequiresPermissions() bool { return true }
+func (c *MassBanCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionBanMembers
+}
+
+func (c *MassBanCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("massban")
+	if ctx.Config != nil && ctx.Config.Config().LockdownActive() {
+		return respondEphemeral(ctx, "Mass moderation commands are disabled while the bot lockdown is engaged.")
+	}
+
+	var (
+		rawUsers   string
+		reason     = "Massban"
+		attachment *discord.Attachment
+		deleteDays = banDeleteDays(ctx)
+	)
+	if ctx.Interaction != nil && ctx.Interaction.Data != nil && ctx.Interaction.Data.InteractionType() == discord.CommandInteractionType {
+		cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
+		for _, opt := range cmdData.Options {
+			switch opt.Name {
+			case "users":
+				rawUsers = opt.String()
+			case "reason":
+				if r := strings.TrimSpace(opt.String()); r != "" {
+					reason = r
+				}
+			case "file":
+				if sf, err := opt.SnowflakeValue(); err == nil {
+					if att, ok := cmdData.Resolved.Attachments[discord.AttachmentID(sf)]; ok {
+						attachment = &att
+					}
+				}
+			case deleteMessagesOptionName:
+				if val, err := opt.IntValue(); err == nil {
+					deleteDays = int(val)
+				}
+			}
+		}
+	}
+
+	if attachment != nil {
+		content, err := c.fetchIDList(context.Background(), *attachment)
+		if err != nil {
+			c.logger.Warn("Mitigated service degradation: Massban ID list could not be read",
+				slog.String("guild_id", ctx.GuildID.String()),
+				slog.String("filename", attachment.Filename),
+				slog.String("error", err.Error()),
+			)
+			return respondEphemeral(ctx, fmt.Sprintf("Could not read %s: %v", attachment.Filename, err))
+		}
+		rawUsers += "\n" + content
+	}
+
+	// Delegate ID normalization to the purely Discord-agnostic core package
+	validIDs, invalid := coremod.ParseMemberIDs(rawUsers)
+	if len(validIDs) == 0 {
+		return respondEphemeral(ctx, "Provide user IDs in `users` or attach a .txt/.csv file of IDs.")
+	}
+	if len(validIDs) > coremod.MaxMassActionTargets {
+		return respondEphemeral(ctx, fmt.Sprintf("A massban takes at most %d users; this list has %d. Split it across several runs.", coremod.MaxMassActionTargets, len(validIDs)))
+	}
+
+	c.logger.Info("Architectural state transition: Executing mass moderation action from slash command",
+		slog.String("command", "massban"),
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.Int("target_count", len(validIDs)),
+	)
+
+	eta := coremod.EstimateMassActionDuration(len(validIDs), massActionRate(ctx)).Round(time.Second)
+	if err := respondEphemeral(ctx, fmt.Sprintf("Massban queued for %d users; expect it to take about %s.", len(validIDs), eta)); err != nil {
+		return err
+	}
+	c.start(massBanRun{ictx: ctx, validIDs: validIDs, invalid: invalid, reason: reason, deleteDays: deleteDays})
+	return nil
+}
+
+// start runs r on the task router when there is one, and on its own
+// goroutine otherwise.
+func (c *MassBanCommand) start(r massBanRun) {
+	if c.tasks == nil {
+		go c.run(r.ictx, r.validIDs, r.invalid, r.reason, r.deleteDays)
+		return
+	}
+	t := task.Task{
+		Type:    massBanTaskType,
+		Payload: r,
+		Options: task.TaskOptions{
+			GroupKey: "massban:" + r.ictx.GuildID.String(),
+			GuildID:  r.ictx.GuildID.String(),
+			// A retry would ban the whole list again.
+			MaxAttempts: 1,
+		},
+		Requester: r.ictx.Requester(),
+	}
+	if r.ictx.Client != nil {
+		// The run posts its own summary and report; the requester only needs
+		// telling when it dies before it can.
+		notifier := commands.NewTaskNotifier(r.ictx.Client, c.logger)
+		t.OnComplete = func(ctx context.Context, done task.Completion) {
+			if done.Err != nil {
+				notifier.Notify(ctx, done)
+			}
+		}
+	}
+	if err := c.tasks.Dispatch(context.Background(), t); err != nil {
+		c.logger.Warn("Mitigated service degradation: Massban could not be queued; running it directly",
+			slog.String("guild_id", r.ictx.GuildID.String()),
+			slog.String("error", err.Error()),
+		)
+		go c.run(r.ictx, r.validIDs, r.invalid, r.reason, r.deleteDays)
+	}
+}
+
+// run checks the role hierarchy for every target up front, then bans the
+// permitted ones through the shared mass-action runner.
+func (c *MassBanCommand) run(ictx *commands.ArikawaContext, validIDs, invalid []string, reason string, deleteDays int) {
+	preset := make([]coremod.MassActionResult, 0, len(invalid))
+	for _, raw := range invalid {
+		preset = append(preset, coremod.MassActionResult{Target: raw, Status: coremod.MassActionInvalid, Detail: "not a user ID"})
+	}
+
+	targets := make([]discord.UserID, 0, len(validIDs))
+	for _, idStr := range validIDs {
+		if sf, err := discord.ParseSnowflake(idStr); err == nil {
+			targets = append(targets, discord.UserID(sf))
+		}
+	}
+
+	denied, err := c.service.AuthorizeMany(context.Background(), ictx.GuildID, ictx.UserID, targets, protectedTargets(ictx))
+	if errors.Is(err, coremod.ErrActionRateExceeded) {
+		_ = respondEphemeral(ictx, lockoutMessage(err))
+		return
+	}
+	if err != nil {
+		c.logger.Warn("Mitigated service degradation: Moderation hierarchy check failed",
+			slog.String("guild_id", ictx.GuildID.String()),
+			slog.String("error", err.Error()),
+		)
+		_ = respondEphemeral(ictx, "Could not verify the role hierarchy for this action.")
+		return
+	}
+
+	reserved := len(targets) - len(denied)
+	if msg, ok := reserveActions(ictx, c.service, c.logger, reserved); !ok {
+		_ = respondEphemeral(ictx, msg)
+		return
+	}
+
+	results := c.runner.Run(ictx, massActionJob{
+		Title:   "Massban",
+		Verb:    "banned",
+		Targets: validIDs,
+		Preset:  preset,
+		Step: func(ctx context.Context, target string) error {
+			sf, err := discord.ParseSnowflake(target)
+			if err != nil {
+				return err
+			}
+			if refusal := denied[discord.UserID(sf)]; refusal != nil {
+				return fmt.Errorf("%w: %v", coremod.ErrMassActionSkipped, refusal)
+			}
+			if inTestMode(ictx) {
+				ctx = coremod.WithTestMode(ctx)
+			}
+			if err := c.service.Ban(ctx, ictx.GuildID, discord.UserID(sf), deleteDays*secondsPerDay, reason); err != nil {
+				return err
+			}
+			c.cases.recordQuiet(ictx, caseActionBan, discord.UserID(sf), reason)
+			return nil
+		},
+	})
+	// Bans that failed or were cancelled do not count toward the limit.
+	var banned int
+	for _, r := range results {
+		if r.Status == coremod.MassActionDone {
+			banned++
+		}
+	}
+	c.service.ReleaseActions(ictx.GuildID, ictx.UserID, reserved-banned)
+}
+
+// fetchIDList downloads an uploaded ID list after checking its type and size.
+func (c *MassBanCommand) fetchIDList(ctx context.Context, att discord.Attachment) (string, error) {
+	switch strings.ToLower(path.Ext(att.Filename)) {
+	case ".txt", ".csv":
+	default:
+		return "", fmt.Errorf("only .txt and .csv files are supported")
+	}
+	if att.Size > maxMassBanFileSize {
+		return "", fmt.Errorf("file is larger than %d KiB", maxMassBanFileSize>>10)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, string(att.URL), nil)
+	if err != nil {
+		return "", fmt.Errorf("MassBanCommand.fetchIDList: %w", err)
+	}
+	client := c.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("download returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxMassBanFileSize+1))
+	if err != nil {
+		return "", fmt.Errorf("download failed: %w", err)
+	}
+	if len(data) > maxMassBanFileSize {
+		return "", fmt.Errorf("file is larger than %d KiB", maxMassBanFileSize>>10)
+	}
+	return string(data), nil
+}
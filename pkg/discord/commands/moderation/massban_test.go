@@ -0,0 +1,70 @@
+package moderation
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+func TestMassBanCommand_FetchIDList(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("user_id\r\n123456789012345678\r\n234567890123456789\r\n"))
+	}))
+	defer srv.Close()
+
+	cmd := NewMassBanCommand(nil, nil, nil)
+	cmd.httpClient = srv.Client()
+
+	content, err := cmd.fetchIDList(context.Background(), discord.Attachment{Filename: "ids.CSV", Size: 60, URL: srv.URL})
+	if err != nil {
+		t.Fatalf("fetchIDList: %v", err)
+	}
+	if !strings.Contains(content, "234567890123456789") {
+		t.Fatalf("unexpected content %q", content)
+	}
+
+	if _, err := cmd.fetchIDList(context.Background(), discord.Attachment{Filename: "ids.png", URL: srv.URL}); err == nil {
+		t.Fatal("expected unsupported extension to be rejected")
+	}
+	if _, err := cmd.fetchIDList(context.Background(), discord.Attachment{Filename: "ids.txt", Size: maxMassBanFileSize + 1, URL: srv.URL}); err == nil {
+		t.Fatal("expected oversized file to be rejected")
+	}
+}
+
+func TestMassActionRunner_CancelRequiresOwner(t *testing.T) {
+	t.Parallel()
+	r := newMassActionRunner(slog.Default())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runID := r.register(discord.UserID(1), cancel)
+
+	if r.Cancel(runID, discord.UserID(2)) {
+		t.Fatal("expected another user's cancel to be refused")
+	}
+	if ctx.Err() != nil {
+		t.Fatal("run cancelled by a non-owner")
+	}
+	if !r.Cancel(runID, discord.UserID(1)) || ctx.Err() == nil {
+		t.Fatal("expected the owner to cancel the run")
+	}
+	r.release(runID)
+	if r.Cancel(runID, discord.UserID(1)) {
+		t.Fatal("expected a released run to be unknown")
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	t.Parallel()
+	if got := progressBar(5, 10); got != strings.Repeat("█", 10)+strings.Repeat("░", 10) {
+		t.Fatalf("progressBar(5, 10) = %q", got)
+	}
+	if got := progressBar(0, 0); got != strings.Repeat("█", progressBarWidth) {
+		t.Fatalf("progressBar(0, 0) = %q", got)
+	}
+}
@@ -0,0 +1,194 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const (
+	notesListLimit = 10
+	maxNoteLength  = 1000
+)
+
+// NoteStore persists moderator notes. *postgres.Store satisfies it.
+type NoteStore interface {
+	CreateModerationNote(ctx context.Context, guildID, userID, authorID, content string, createdAt time.Time) (coremod.Note, error)
+	ListModerationNotes(ctx context.Context, guildID, userID string, limit int) iter.Seq2[coremod.Note, error]
+	DeleteModerationNote(ctx context.Context, guildID string, noteID int64) (coremod.Note, bool, error)
+}
+
+// NoteCommand encapsulates the `/note` slash command execution. Every reply
+// is ephemeral so notes are only ever shown to the moderator who asked.
+type NoteCommand struct {
+	store   NoteStore
+	metrics Metrics
+	logger  *slog.Logger
+}
+
+func (c *NoteCommand) Name() string        { return "note" }
+func (c *NoteCommand) Description() string { return "Keep private moderator notes about members" }
+func (c *NoteCommand) Options() []discord.CommandOption {
+	return []discord.CommandOption{
+		&discord.SubcommandOption{
+			OptionName:  "add",
+			Description: "Add a note about a member",
+			Options: []discord.CommandOptionValue{
+				&discord.UserOption{OptionName: "user", Description: "Member the note is about", Required: true},
+				&discord.StringOption{OptionName: "content", Description: "Note text", Required: true, MaxLength: option.NewInt(maxNoteLength)},
+			},
+		},
+		&discord.SubcommandOption{
+			OptionName:  "list",
+			Description: "List the most recent notes about a member",
+			Options: []discord.CommandOptionValue{
+				&discord.UserOption{OptionName: "user", Description: "Member to look up", Required: true},
+			},
+		},
+		&discord.SubcommandOption{
+			OptionName:  "delete",
+			Description: "Delete a single note",
+			Options: []discord.CommandOptionValue{
+				&discord.IntegerOption{OptionName: "id", Description: "ID of the note, as shown by /note list", Required: true, Min: option.NewInt(1)},
+			},
+		},
+	}
+}
+
+func (c *NoteCommand) RequiresGuild() bool       { return true }
+func (c *NoteCommand) RequiresPermissions() bool { return true }
+func (c *NoteCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionModerateMembers
+}
+
+func (c *NoteCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("note")
+
+	if ctx.Interaction == nil || ctx.Interaction.Data == nil || ctx.Interaction.Data.InteractionType() != discord.CommandInteractionType {
+		return nil
+	}
+	if err := ctx.Defer(discord.EphemeralMessage); err != nil {
+		return err
+	}
+	cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
+	if len(cmdData.Options) == 0 {
+		return respondEphemeral(ctx, "Choose add, list or delete.")
+	}
+	sub := cmdData.Options[0]
+
+	var (
+		userID  discord.UserID
+		content string
+		noteID  int64
+	)
+	for _, opt := range sub.Options {
+		switch opt.Name {
+		case "user":
+			if val, err := opt.SnowflakeValue(); err == nil {
+				userID = discord.UserID(val)
+			}
+		case "content":
+			content = strings.TrimSpace(opt.String())
+		case "id":
+			if val, err := opt.IntValue(); err == nil {
+				noteID = val
+			}
+		}
+	}
+
+	guildID := ctx.GuildID.String()
+	bg := context.Background()
+	switch sub.Name {
+	case "add":
+		if !userID.IsValid() {
+			return respondEphemeral(ctx, "Invalid user specified.")
+		}
+		if content == "" {
+			return respondEphemeral(ctx, "Nothing was saved: the note is empty.")
+		}
+		note, err := c.store.CreateModerationNote(bg, guildID, userID.String(), ctx.UserID.String(), content, time.Now())
+		if err != nil {
+			c.logFailure(ctx, "add", err)
+			return respondEphemeral(ctx, "Failed to save the note.")
+		}
+		return respondEphemeral(ctx, fmt.Sprintf("Saved note %d about <@%s>.", note.ID, userID))
+	case "list":
+		if !userID.IsValid() {
+			return respondEphemeral(ctx, "Invalid user specified.")
+		}
+		notes, err := recentNotes(c.store, guildID, userID)
+		if err != nil {
+			c.logFailure(ctx, "list", err)
+			return respondEphemeral(ctx, "Failed to load notes.")
+		}
+		return core.NewResponse().Embeds(buildNotesEmbed(userID, notes)).EditDeferred(ctx.Client, ctx.Interaction)
+	case "delete":
+		note, found, err := c.store.DeleteModerationNote(bg, guildID, noteID)
+		if err != nil {
+			c.logFailure(ctx, "delete", err)
+			return respondEphemeral(ctx, "Failed to delete the note.")
+		}
+		if !found {
+			return respondEphemeral(ctx, fmt.Sprintf("No note has ID %d.", noteID))
+		}
+		return respondEphemeral(ctx, fmt.Sprintf("Deleted note %d about <@%s>.", note.ID, note.UserID))
+	default:
+		return respondEphemeral(ctx, "Choose add, list or delete.")
+	}
+}
+
+func (c *NoteCommand) logFailure(ctx *commands.ArikawaContext, action string, err error) {
+	c.logger.Error("Blocking structural failure: Moderator note operation aborted",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("action", action),
+		slog.String("error", err.Error()),
+	)
+}
+
+// recentNotes loads the notesListLimit most recent notes about userID.
+func recentNotes(store NoteStore, guildID string, userID discord.UserID) ([]coremod.Note, error) {
+	var notes []coremod.Note
+	for n, err := range store.ListModerationNotes(context.Background(), guildID, userID.String(), notesListLimit) {
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, n)
+	}
+	return notes, nil
+}
+
+// buildNotesEmbed lists notes about userID. Ten notes of maxNoteLength can
+// outgrow one embed, so it is sent through core.ResponseBuilder, which splits
+// it.
+func buildNotesEmbed(userID discord.UserID, notes []coremod.Note) discord.Embed {
+	embed := discord.Embed{
+		Title: "Moderator notes",
+		Color: discord.Color(theme.Info()),
+	}
+	if len(notes) == 0 {
+		embed.Description = fmt.Sprintf("There are no notes about <@%s>.", userID)
+		return embed
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Most recent notes about <@%s>:\n", userID)
+	for _, n := range notes {
+		fmt.Fprintf(&b, "**%d** %s by <@%s>: %s\n", n.ID, logging.DiscordTimestamp(n.CreatedAt, logging.TimestampRelative), n.AuthorID, n.Content)
+	}
+	embed.Description = b.String()
+	if len(notes) == notesListLimit {
+		embed.Footer = &discord.EmbedFooter{Text: fmt.Sprintf("Showing the %d most recent notes", notesListLimit)}
+	}
+	return embed
+}
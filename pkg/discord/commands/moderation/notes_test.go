@@ -0,0 +1,59 @@
+package moderation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+func TestNoteCommand_RequiresModeratorPermission(t *testing.T) {
+	t.Parallel()
+	c := &NoteCommand{}
+	if !c.RequiresPermissions() || c.DefaultMemberPermissions() != discord.PermissionModerateMembers {
+		t.Fatal("notes must be restricted to moderators")
+	}
+}
+
+func TestBuildNotesEmbed(t *testing.T) {
+	t.Parallel()
+	empty := buildNotesEmbed(discord.UserID(5), nil)
+	if !strings.Contains(empty.Description, "no notes") {
+		t.Fatalf("unexpected empty embed: %+v", empty)
+	}
+
+	notes := []coremod.Note{
+		{ID: 12, AuthorID: "9", Content: "alt account", CreatedAt: time.Unix(1_700_000_000, 0)},
+	}
+	embed := buildNotesEmbed(discord.UserID(5), notes)
+	if !strings.Contains(embed.Description, "**12**") || !strings.Contains(embed.Description, "alt account") {
+		t.Fatalf("note missing from embed: %q", embed.Description)
+	}
+	if embed.Footer != nil {
+		t.Fatalf("unexpected footer for a short list: %+v", embed.Footer)
+	}
+}
+
+func TestBuildNotesEmbed_FullListFitsDiscordLimits(t *testing.T) {
+	t.Parallel()
+	notes := make([]coremod.Note, notesListLimit)
+	for i := range notes {
+		notes[i] = coremod.Note{ID: int64(i + 1), AuthorID: "9", Content: strings.Repeat("x", maxNoteLength), CreatedAt: time.Unix(1_700_000_000, 0)}
+	}
+	msgs := core.NewResponse().Embeds(buildNotesEmbed(discord.UserID(5), notes)).Messages()
+	var shown int
+	for _, m := range msgs {
+		for _, e := range *m.Embeds {
+			if len(e.Description) > core.MaxEmbedDescription {
+				t.Fatalf("embed description of %d characters exceeds the limit", len(e.Description))
+			}
+			shown += strings.Count(e.Description, strings.Repeat("x", 100))
+		}
+	}
+	if want := notesListLimit * maxNoteLength / 100; shown < want {
+		t.Fatalf("notes were cut: found %d of %d chunks", shown, want)
+	}
+}
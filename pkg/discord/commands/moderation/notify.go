@@ -0,0 +1,119 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+// errCannotDMUser is the Discord error code returned when a user does not
+// accept DMs from the bot.
+const errCannotDMUser = 50007
+
+// Case notes recorded when the member could not be notified.
+const (
+	dmClosedNote = "Could not DM the member: their DMs are closed."
+	dmFailedNote = "Could not DM the member."
+)
+
+// notice carries what notifyTarget learned over to the case of the action:
+// the case number reserved for it and a note when the DM failed.
+type notice struct {
+	caseNumber int64
+	extra      string
+}
+
+// notifyTarget DMs target about action before it is applied, when the guild
+// enables punishment DMs. The case number is reserved first so the message
+// can quote it; if the action then fails, that number is simply skipped. An
+// undelivered DM never blocks the action. Members are not notified of actions
+// simulated in test mode.
+func notifyTarget(ctx *commands.ArikawaContext, cases *caseLog, logger *slog.Logger, action string, target discord.UserID, reason string, until time.Time) notice {
+	if ctx.GuildConfig == nil || !ctx.GuildConfig.PunishmentDM.Enabled || ctx.Client == nil || inTestMode(ctx) {
+		return notice{}
+	}
+	n := notice{caseNumber: cases.reserve(ctx)}
+
+	guildName := "this server"
+	if guild, err := ctx.Client.Guild(ctx.GuildID); err == nil && guild.Name != "" {
+		guildName = guild.Name
+	}
+	embed := punishmentEmbed(guildName, action, reason, n.caseNumber, ctx.GuildConfig.PunishmentDM.AppealInstructions, until)
+
+	dm, err := ctx.Client.CreatePrivateChannel(target)
+	if err == nil {
+		_, err = ctx.Client.SendEmbeds(dm.ID, embed)
+	}
+	if err != nil {
+		n.extra = dmFailureNote(err)
+		logger.Warn("Mitigated service degradation: Member could not be notified of a moderation action",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("target_id", target.String()),
+			slog.String("action", action),
+			slog.String("error", err.Error()),
+		)
+	}
+	return n
+}
+
+func dmFailureNote(err error) string {
+	var httpErr *httputil.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Code == errCannotDMUser {
+		return dmClosedNote
+	}
+	return dmFailedNote
+}
+
+// dmSuffix tells the invoker when the member was not notified.
+func dmSuffix(n notice) string {
+	if n.extra == "" {
+		return ""
+	}
+	return " " + n.extra
+}
+
+// punishmentEmbed renders the DM announcing action to its target. until is
+// the end of a timeout and zero for other actions.
+func punishmentEmbed(guildName, action, reason string, caseNumber int64, appeal string, until time.Time) discord.Embed {
+	guildName = logging.EscapeMarkdown(guildName)
+	reason = logging.EscapeUserText(reason)
+	var description string
+	switch action {
+	case caseActionBan:
+		description = fmt.Sprintf("You are being banned from **%s**.", guildName)
+	case caseActionKick:
+		description = fmt.Sprintf("You are being kicked from **%s**.", guildName)
+	case caseActionTimeout:
+		description = fmt.Sprintf("You are being timed out in **%s**.", guildName)
+	default:
+		description = fmt.Sprintf("A moderator of **%s** took action against you: %s.", guildName, action)
+	}
+	if reason == "" {
+		reason = "No reason provided"
+	}
+
+	fields := []discord.EmbedField{{Name: "Reason", Value: reason}}
+	if !until.IsZero() {
+		fields = append(fields, discord.EmbedField{Name: "Ends", Value: logging.DiscordTimestamp(until, logging.TimestampLongDateTime), Inline: true})
+	}
+	if caseNumber > 0 {
+		fields = append(fields, discord.EmbedField{Name: "Case", Value: fmt.Sprintf("#%d", caseNumber), Inline: true})
+	}
+	if appeal != "" {
+		fields = append(fields, discord.EmbedField{Name: "Appeal", Value: appeal})
+	}
+	return discord.Embed{
+		Title:       "Moderation notice",
+		Description: description,
+		Color:       discord.Color(theme.Danger()),
+		Fields:      fields,
+	}
+}
@@ -0,0 +1,76 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+func TestPunishmentEmbed(t *testing.T) {
+	t.Parallel()
+	until := time.Unix(1_700_000_000, 0)
+	embed := punishmentEmbed("Cafe", caseActionTimeout, "spam", 12, "Reply to the appeal form.", until)
+	if !strings.Contains(embed.Description, "timed out in **Cafe**") {
+		t.Fatalf("unexpected description %q", embed.Description)
+	}
+	for _, want := range [][2]string{
+		{"Reason", "spam"},
+		{"Ends", "<t:1700000000:F>"},
+		{"Case", "#12"},
+		{"Appeal", "Reply to the appeal form."},
+	} {
+		if !hasField(embed, want[0], want[1]) {
+			t.Fatalf("expected %s field %q, got %+v", want[0], want[1], embed.Fields)
+		}
+	}
+
+	embed = punishmentEmbed("Cafe", caseActionBan, "", 0, "", time.Time{})
+	if len(embed.Fields) != 1 || !hasField(embed, "Reason", "No reason provided") {
+		t.Fatalf("expected only the reason field, got %+v", embed.Fields)
+	}
+}
+
+func TestDMFailureNote(t *testing.T) {
+	t.Parallel()
+	closed := fmt.Errorf("send: %w", &httputil.HTTPError{Status: 403, Code: errCannotDMUser})
+	if got := dmFailureNote(closed); got != dmClosedNote {
+		t.Fatalf("closed DMs: got %q", got)
+	}
+	if got := dmFailureNote(errors.New("timeout")); got != dmFailedNote {
+		t.Fatalf("other failures: got %q", got)
+	}
+}
+
+func TestCaseLog_RecordNoticedKeepsReservedNumber(t *testing.T) {
+	t.Parallel()
+	store := &fakeCaseStore{}
+	l := &caseLog{store: store, logger: slog.Default()}
+	ctx := &commands.ArikawaContext{GuildID: discord.GuildID(1), UserID: discord.UserID(2)}
+
+	n := notice{caseNumber: l.reserve(ctx), extra: dmClosedNote}
+	c, ok := l.recordNoticed(ctx, caseActionBan, discord.UserID(3), "raid", n)
+	if !ok || c.CaseNumber != 1 || c.Extra != dmClosedNote {
+		t.Fatalf("expected case #1 with the DM note, got %+v (ok=%v)", c, ok)
+	}
+	if !hasField(caseEmbed(c), "Details", dmClosedNote) {
+		t.Fatalf("expected the DM note in the case embed, got %+v", caseEmbed(c).Fields)
+	}
+
+	c.Source = coremod.CaseSourceAutomod
+	if !hasField(caseEmbed(c), "Details", "Taken by AutoMod.\n"+dmClosedNote) {
+		t.Fatalf("expected both notes in the case embed, got %+v", caseEmbed(c).Fields)
+	}
+
+	var disabled *caseLog
+	if disabled.reserve(ctx) != 0 {
+		t.Fatal("a nil case log must not reserve numbers")
+	}
+}
@@ -0,0 +1,184 @@
+package moderation
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+// protectedTargets returns the users and roles the guild protected from
+// moderation.
+func protectedTargets(ctx *commands.ArikawaContext) coremod.ProtectedTargets {
+	if ctx.GuildConfig == nil {
+		return coremod.ProtectedTargets{}
+	}
+	p := ctx.GuildConfig.ModerationProtection
+	return coremod.ProtectedTargets{UserIDs: p.UserIDs, RoleIDs: p.RoleIDs}
+}
+
+// ProtectCommand encapsulates the `/protect` slash command execution.
+type ProtectCommand struct {
+	metrics Metrics
+	logger  *slog.Logger
+}
+
+func (c *ProtectCommand) Name() string        { return "protect" }
+func (c *ProtectCommand) Description() string { return "Protect members from moderation commands" }
+func (c *ProtectCommand) Options() []discord.CommandOption {
+	targetOptions := func(verb string) []discord.CommandOptionValue {
+		return []discord.CommandOptionValue{
+			&discord.UserOption{OptionName: "user", Description: "User to " + verb},
+			&discord.RoleOption{OptionName: "role", Description: "Role whose holders to " + verb},
+		}
+	}
+	return []discord.CommandOption{
+		&discord.SubcommandOption{
+			OptionName:  "add",
+			Description: "Protect a user or the holders of a role",
+			Options:     targetOptions("protect"),
+		},
+		&discord.SubcommandOption{
+			OptionName:  "remove",
+			Description: "Lift the protection of a user or role",
+			Options:     targetOptions("unprotect"),
+		},
+		&discord.SubcommandOption{
+			OptionName:  "list",
+			Description: "Show the protected users and roles",
+		},
+	}
+}
+
+func (c *ProtectCommand) RequiresGuild() bool       { return true }
+func (c *ProtectCommand) RequiresPermissions() bool { return true }
+func (c *ProtectCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionManageGuild
+}
+
+func (c *ProtectCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("protect")
+
+	if ctx.Interaction == nil || ctx.Interaction.Data == nil || ctx.Interaction.Data.InteractionType() != discord.CommandInteractionType {
+		return nil
+	}
+	cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
+	if len(cmdData.Options) == 0 {
+		return respondEphemeral(ctx, "Choose add, remove or list.")
+	}
+	sub := cmdData.Options[0]
+	if sub.Name == "list" {
+		return respondEphemeral(ctx, protectionList(protectedTargets(ctx)))
+	}
+
+	var userID, roleID string
+	for _, opt := range sub.Options {
+		val, err := opt.SnowflakeValue()
+		if err != nil || !val.IsValid() {
+			continue
+		}
+		switch opt.Name {
+		case "user":
+			userID = val.String()
+		case "role":
+			roleID = val.String()
+		}
+	}
+	if userID == "" && roleID == "" {
+		return respondEphemeral(ctx, "Choose a user or a role.")
+	}
+	if ctx.Config == nil {
+		return respondEphemeral(ctx, "Configuration is unavailable; nothing was changed.")
+	}
+
+	var changed bool
+	err := ctx.Config.UpdateGuildConfig(ctx.GuildID.String(), func(cfg *files.GuildConfig) error {
+		switch sub.Name {
+		case "add":
+			changed = addProtection(&cfg.ModerationProtection, userID, roleID)
+		case "remove":
+			changed = removeProtection(&cfg.ModerationProtection, userID, roleID)
+		}
+		return nil
+	})
+	if err != nil {
+		c.logger.Error("Blocking structural failure: Moderation protection could not be saved",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("error", err.Error()),
+		)
+		return respondEphemeral(ctx, "Failed to save the protection list.")
+	}
+	if !changed {
+		return respondEphemeral(ctx, "Nothing was changed.")
+	}
+
+	c.logger.Info("Architectural state transition: Moderation protection updated",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("action", sub.Name),
+		slog.String("target_user_id", userID),
+		slog.String("target_role_id", roleID),
+		slog.String("user_id", ctx.UserID.String()),
+	)
+	if sub.Name == "add" {
+		return respondEphemeral(ctx, "Protected "+protectionTargets(userID, roleID)+" from moderation commands.")
+	}
+	return respondEphemeral(ctx, "Lifted the protection of "+protectionTargets(userID, roleID)+".")
+}
+
+// addProtection adds the given IDs and reports whether any was new.
+func addProtection(cfg *files.ModerationProtectionConfig, userID, roleID string) bool {
+	var changed bool
+	if userID != "" && !slices.Contains(cfg.UserIDs, userID) {
+		cfg.UserIDs = append(cfg.UserIDs, userID)
+		changed = true
+	}
+	if roleID != "" && !slices.Contains(cfg.RoleIDs, roleID) {
+		cfg.RoleIDs = append(cfg.RoleIDs, roleID)
+		changed = true
+	}
+	return changed
+}
+
+// removeProtection removes the given IDs and reports whether any was listed.
+func removeProtection(cfg *files.ModerationProtectionConfig, userID, roleID string) bool {
+	users, roles := len(cfg.UserIDs), len(cfg.RoleIDs)
+	if userID != "" {
+		cfg.UserIDs = slices.DeleteFunc(cfg.UserIDs, func(id string) bool { return id == userID })
+	}
+	if roleID != "" {
+		cfg.RoleIDs = slices.DeleteFunc(cfg.RoleIDs, func(id string) bool { return id == roleID })
+	}
+	return len(cfg.UserIDs) != users || len(cfg.RoleIDs) != roles
+}
+
+func protectionTargets(userID, roleID string) string {
+	var parts []string
+	if userID != "" {
+		parts = append(parts, "<@"+userID+">")
+	}
+	if roleID != "" {
+		parts = append(parts, "<@&"+roleID+">")
+	}
+	return strings.Join(parts, " and ")
+}
+
+func protectionList(p coremod.ProtectedTargets) string {
+	if len(p.UserIDs) == 0 && len(p.RoleIDs) == 0 {
+		return "No users or roles are protected. The server owner and the bot always are."
+	}
+	var b strings.Builder
+	b.WriteString("Protected from moderation commands:\n")
+	for _, id := range p.UserIDs {
+		fmt.Fprintf(&b, "- <@%s>\n", id)
+	}
+	for _, id := range p.RoleIDs {
+		fmt.Fprintf(&b, "- <@&%s>\n", id)
+	}
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package moderation
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+func TestProtection_AddRemove(t *testing.T) {
+	t.Parallel()
+	var cfg files.ModerationProtectionConfig
+
+	if !addProtection(&cfg, "1", "2") || addProtection(&cfg, "1", "") {
+		t.Fatalf("add should report only new IDs, got %+v", cfg)
+	}
+	if !slices.Equal(cfg.UserIDs, []string{"1"}) || !slices.Equal(cfg.RoleIDs, []string{"2"}) {
+		t.Fatalf("unexpected protection %+v", cfg)
+	}
+	if removeProtection(&cfg, "3", "") {
+		t.Fatal("removing an unlisted user must report no change")
+	}
+	if !removeProtection(&cfg, "", "2") || len(cfg.RoleIDs) != 0 || len(cfg.UserIDs) != 1 {
+		t.Fatalf("unexpected protection after remove %+v", cfg)
+	}
+}
+
+func TestProtectedTargets_FromGuildConfig(t *testing.T) {
+	t.Parallel()
+	if got := protectedTargets(&commands.ArikawaContext{}); len(got.UserIDs) != 0 || len(got.RoleIDs) != 0 {
+		t.Fatalf("expected no protection without a guild config, got %+v", got)
+	}
+	ctx := &commands.ArikawaContext{GuildConfig: &files.GuildConfig{
+		ModerationProtection: files.ModerationProtectionConfig{UserIDs: []string{"1"}, RoleIDs: []string{"2"}},
+	}}
+	got := protectedTargets(ctx)
+	if !got.Protects("1", nil) || !got.Protects("5", &coremod.Member{RoleIDs: []string{"2"}}) {
+		t.Fatalf("unexpected targets %+v", got)
+	}
+	if list := protectionList(got); !strings.Contains(list, "<@1>") || !strings.Contains(list, "<@&2>") {
+		t.Fatalf("unexpected list %q", list)
+	}
+}
+
+func TestAuthorizeTarget_ProtectionWithoutGuildContexts(t *testing.T) {
+	t.Parallel()
+	svc := discordmod.NewService(&mockClient{}, nil)
+	ctx := &commands.ArikawaContext{
+		GuildID:     discord.GuildID(1),
+		UserID:      discord.UserID(2),
+		GuildConfig: &files.GuildConfig{ModerationProtection: files.ModerationProtectionConfig{UserIDs: []string{"5"}}},
+	}
+
+	msg, ok := authorizeTarget(ctx, svc, slog.Default(), discord.UserID(5))
+	if ok || !strings.Contains(msg, "protected") {
+		t.Fatalf("a protected target = (%q, %v), want the protection refusal", msg, ok)
+	}
+	msg, ok = authorizeTarget(ctx, svc, slog.Default(), discord.UserID(6))
+	if ok || strings.Contains(msg, "protected") {
+		t.Fatalf("an unchecked target = (%q, %v), want a refusal for the missing check", msg, ok)
+	}
+}
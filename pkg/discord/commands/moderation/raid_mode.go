@@ -0,0 +1,192 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+// maxRaidModeAccountAgeDays bounds the minimum account age /raidmode accepts.
+const maxRaidModeAccountAgeDays = 365
+
+// RaidModeCommand encapsulates the `/raidmode` slash command execution.
+type RaidModeCommand struct {
+	guard   *discordmod.RaidGuard
+	metrics Metrics
+	logger  *slog.Logger
+}
+
+func (c *RaidModeCommand) Name() string        { return "raidmode" }
+func (c *RaidModeCommand) Description() string { return "Lock the server down during a raid" }
+func (c *RaidModeCommand) Options() []discord.CommandOption {
+	return []discord.CommandOption{
+		&discord.SubcommandOption{
+			OptionName:  "on",
+			Description: "Raise verification, pause invites and turn away new accounts",
+			Options: []discord.CommandOptionValue{
+				&discord.IntegerOption{
+					OptionName:  "minutes",
+					Description: "How long raid mode lasts (default from the server config)",
+					Min:         option.NewInt(1),
+					Max:         option.NewInt(files.MaxRaidModeMinutes),
+				},
+				&discord.IntegerOption{
+					OptionName:  "min_account_age_days",
+					Description: "Kick joining accounts younger than this; 0 kicks nobody",
+					Min:         option.NewInt(0),
+					Max:         option.NewInt(maxRaidModeAccountAgeDays),
+				},
+				&discord.StringOption{
+					OptionName:  "reason",
+					Description: "Reason shown in the audit log",
+					MaxLength:   option.NewInt(maxReasonLength),
+				},
+			},
+		},
+		&discord.SubcommandOption{
+			OptionName:  "off",
+			Description: "Lift raid mode and restore the server settings",
+		},
+		&discord.SubcommandOption{
+			OptionName:  "status",
+			Description: "Show whether raid mode is on",
+		},
+	}
+}
+
+func (c *RaidModeCommand) RequiresGuild() bool       { return true }
+func (c *RaidModeCommand) RequiresPermissions() bool { return true }
+func (c *RaidModeCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionManageGuild
+}
+
+func (c *RaidModeCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("raidmode")
+
+	if ctx.Interaction == nil || ctx.Interaction.Data == nil || ctx.Interaction.Data.InteractionType() != discord.CommandInteractionType {
+		return nil
+	}
+	if err := ctx.Defer(discord.EphemeralMessage); err != nil {
+		return err
+	}
+	cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
+	if len(cmdData.Options) == 0 {
+		return respondEphemeral(ctx, "Choose on, off or status.")
+	}
+	sub := cmdData.Options[0]
+	bg := context.Background()
+
+	switch sub.Name {
+	case "status":
+		mode, active, err := c.guard.Active(bg, ctx.GuildID)
+		if err != nil {
+			c.logFailure(ctx, sub.Name, err)
+			return respondEphemeral(ctx, "Failed to load the raid mode.")
+		}
+		if !active {
+			return respondEphemeral(ctx, "Raid mode is off.")
+		}
+		return respondEphemeral(ctx, raidModeSummary(mode))
+
+	case "off":
+		lifted, err := c.guard.Disable(bg, ctx.GuildID, "Raid mode lifted by "+ctx.UserID.String())
+		if err != nil && !lifted {
+			c.logFailure(ctx, sub.Name, err)
+			return respondEphemeral(ctx, "Failed to lift raid mode.")
+		}
+		if !lifted {
+			return respondEphemeral(ctx, "Raid mode is not on.")
+		}
+		c.logger.Info("Architectural state transition: Raid mode lifted",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("user_id", ctx.UserID.String()),
+		)
+		if err != nil {
+			c.logFailure(ctx, sub.Name, err)
+			return respondEphemeral(ctx, "Raid mode is off, but some settings could not be restored. Check the verification level and invites.")
+		}
+		return respondEphemeral(ctx, "Raid mode is off. Verification and invites are back to how they were.")
+	}
+
+	// Raid mode changes the guild's own settings, which test mode cannot
+	// simulate.
+	if inTestMode(ctx) {
+		return respondEphemeral(ctx, "Raid mode cannot be turned on while test mode is on.")
+	}
+	var cfg files.RaidModeConfig
+	if ctx.GuildConfig != nil {
+		cfg = ctx.GuildConfig.RaidMode
+	}
+	duration, minAge := cfg.Duration(), cfg.MinAccountAge()
+	reason := "Raid mode"
+	for _, opt := range sub.Options {
+		switch opt.Name {
+		case "minutes":
+			if val, err := opt.IntValue(); err == nil && val > 0 {
+				duration = time.Duration(val) * time.Minute
+			}
+		case "min_account_age_days":
+			if val, err := opt.IntValue(); err == nil && val >= 0 {
+				minAge = time.Duration(val) * 24 * time.Hour
+			}
+		case "reason":
+			if val := strings.TrimSpace(opt.String()); val != "" {
+				reason = "Raid mode: " + val
+			}
+		}
+	}
+
+	mode, err := c.guard.Enable(bg, ctx.GuildID, ctx.UserID, duration, minAge, reason)
+	if errors.Is(err, discordmod.ErrRaidModeActive) {
+		return respondEphemeral(ctx, "Raid mode is already on. "+raidModeSummary(mode))
+	}
+	if err != nil {
+		c.logFailure(ctx, sub.Name, err)
+		return respondEphemeral(ctx, "Failed to turn on raid mode. The bot needs the Manage Server permission.")
+	}
+	c.logger.Info("Architectural state transition: Raid mode enabled",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("user_id", ctx.UserID.String()),
+		slog.Time("expires_at", mode.ExpiresAt),
+		slog.Duration("min_account_age", mode.MinAccountAge),
+	)
+	return respondEphemeral(ctx, "Raid mode is on. "+raidModeSummary(mode))
+}
+
+func (c *RaidModeCommand) logFailure(ctx *commands.ArikawaContext, action string, err error) {
+	c.logger.Error("Blocking structural failure: Raid mode operation aborted",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("action", action),
+		slog.String("error", err.Error()),
+	)
+}
+
+// raidModeSummary describes what an active raid mode does and when it ends.
+func raidModeSummary(mode coremod.RaidMode) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "It lifts itself %s.", logging.DiscordTimestamp(mode.ExpiresAt, logging.TimestampRelative))
+	if mode.RaisedVerification {
+		b.WriteString("\n- Verification is raised to High.")
+	}
+	if mode.InvitesPaused {
+		b.WriteString("\n- Invites are paused.")
+	} else {
+		b.WriteString("\n- Invites could not be paused.")
+	}
+	if mode.MinAccountAge > 0 {
+		fmt.Fprintf(&b, "\n- Accounts younger than %d day(s) are kicked when they join.", int(mode.MinAccountAge.Hours()/24))
+	}
+	return b.String()
+}
@@ -0,0 +1,25 @@
+package moderation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+func TestRaidModeSummary(t *testing.T) {
+	t.Parallel()
+	expires := time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC)
+
+	got := raidModeSummary(coremod.RaidMode{ExpiresAt: expires, RaisedVerification: true, InvitesPaused: true, MinAccountAge: 72 * time.Hour})
+	for _, want := range []string{"<t:1772370000:R>", "Verification is raised", "Invites are paused", "younger than 3 day(s)"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("summary %q lacks %q", got, want)
+		}
+	}
+	got = raidModeSummary(coremod.RaidMode{ExpiresAt: expires})
+	if !strings.Contains(got, "could not be paused") || strings.Contains(got, "kicked") {
+		t.Fatalf("unexpected summary %q", got)
+	}
+}
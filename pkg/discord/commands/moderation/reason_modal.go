@@ -0,0 +1,158 @@
+package moderation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
+)
+
+const (
+	// reasonModalRoute prefixes the custom ID of the reason modal. The action,
+	// target and action parameters follow, separated by "|". The ban pool
+	// flag is appended only when set, so modals opened before it existed
+	// still parse.
+	reasonModalRoute = "moderation:reason|"
+	reasonInputID    = "reason"
+	// maxReasonLength matches the limit Discord enforces on audit log reasons.
+	maxReasonLength = 512
+)
+
+// reasonOption is the optional free-text reason shared by moderation
+// commands. Leaving it empty on /ban, /softban or /kick opens the reason
+// modal instead.
+func reasonOption(description string) discord.CommandOption {
+	return &discord.StringOption{
+		OptionName:  "reason",
+		Description: description,
+		Required:    false,
+		MaxLength:   option.NewInt(maxReasonLength),
+	}
+}
+
+// reasonModalRequest is the pending action a reason modal completes. It
+// round-trips through the modal's custom ID.
+type reasonModalRequest struct {
+	Action     string
+	Target     discord.UserID
+	DeleteDays int
+	Pool       bool
+}
+
+func (r reasonModalRequest) customID() string {
+	id := reasonModalRoute + r.Action + "|" + r.Target.String() + "|" + strconv.Itoa(r.DeleteDays)
+	if r.Pool {
+		id += "|pool"
+	}
+	return id
+}
+
+func parseReasonModalID(customID string) (reasonModalRequest, error) {
+	parts := strings.Split(strings.TrimPrefix(customID, reasonModalRoute), "|")
+	if len(parts) != 3 && (len(parts) != 4 || parts[3] != "pool") {
+		return reasonModalRequest{}, fmt.Errorf("malformed reason modal id %q", customID)
+	}
+	target, err := discord.ParseSnowflake(parts[1])
+	if err != nil || !target.IsValid() {
+		return reasonModalRequest{}, fmt.Errorf("malformed reason modal target %q", parts[1])
+	}
+	days, err := strconv.Atoi(parts[2])
+	if err != nil || days < 0 || days > maxBanDeleteDays {
+		return reasonModalRequest{}, fmt.Errorf("malformed reason modal delete window %q", parts[2])
+	}
+	return reasonModalRequest{Action: parts[0], Target: discord.UserID(target), DeleteDays: days, Pool: len(parts) == 4}, nil
+}
+
+// validateReason trims reason and checks it fits an audit log entry.
+func validateReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	switch {
+	case reason == "":
+		return "", fmt.Errorf("a reason is required")
+	case utf8.RuneCountInString(reason) > maxReasonLength:
+		return "", fmt.Errorf("the reason must be at most %d characters", maxReasonLength)
+	}
+	return reason, nil
+}
+
+// openReasonModal answers the slash command with a modal asking for the
+// reason, so long reasons are not typed into a single-line option.
+func openReasonModal(ctx *commands.ArikawaContext, req reasonModalRequest) error {
+	comps := discord.ContainerComponents{
+		&discord.ActionRowComponent{
+			&discord.TextInputComponent{
+				CustomID:     reasonInputID,
+				Label:        "Reason",
+				Style:        discord.TextInputParagraphStyle,
+				Placeholder:  "Recorded in the audit log",
+				Required:     true,
+				LengthLimits: [2]int{1, maxReasonLength},
+			},
+		},
+	}
+	return ctx.Client.RespondInteraction(ctx.Interaction.ID, ctx.Interaction.Token, api.InteractionResponse{
+		Type: api.ModalResponse,
+		Data: &api.InteractionResponseData{
+			CustomID:   option.NewNullableString(req.customID()),
+			Title:      option.NewNullableString(fmt.Sprintf("Reason for %s", req.Action)),
+			Components: &comps,
+		},
+	})
+}
+
+// handleReasonModal completes a ban, softban or kick once its reason is
+// submitted. The hierarchy is checked again because roles may have changed
+// while the modal was open.
+func (g *commandGroup) handleReasonModal(ctx *cmd.Context) error {
+	data, ok := ctx.Event.Data.(*discord.ModalInteraction)
+	if !ok {
+		return nil
+	}
+	ictx, err := commands.NewArikawaContextFromCmd(ctx)
+	if err != nil {
+		return err
+	}
+	if err := ictx.Defer(discord.EphemeralMessage); err != nil {
+		return err
+	}
+
+	req, err := parseReasonModalID(string(data.CustomID))
+	if err != nil {
+		return respondEphemeral(ictx, "This form is no longer valid. Run the command again.")
+	}
+	var raw string
+	if input, ok := data.Components.Find(reasonInputID).(*discord.TextInputComponent); ok {
+		raw = input.Value
+	}
+	reason, err := validateReason(raw)
+	if err != nil {
+		return respondEphemeral(ictx, fmt.Sprintf("Nothing was done: %v.", err))
+	}
+
+	switch req.Action {
+	case "ban":
+		if msg, ok := authorizeTarget(ictx, g.ban.service, g.ban.logger, req.Target); !ok {
+			return respondEphemeral(ictx, msg)
+		}
+		return g.ban.execute(ictx, req.Target, req.DeleteDays, reason, req.Pool)
+	case "softban":
+		if msg, ok := authorizeTarget(ictx, g.softban.service, g.softban.logger, req.Target); !ok {
+			return respondEphemeral(ictx, msg)
+		}
+		return g.softban.execute(ictx, req.Target, req.DeleteDays, reason)
+	case "kick":
+		if msg, ok := authorizeTarget(ictx, g.kick.service, g.kick.logger, req.Target); !ok {
+			return respondEphemeral(ictx, msg)
+		}
+		return g.kick.execute(ictx, req.Target, reason)
+	default:
+		return respondEphemeral(ictx, "This form is no longer valid. Run the command again.")
+	}
+}
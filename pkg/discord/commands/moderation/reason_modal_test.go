@@ -0,0 +1,71 @@
+package moderation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReasonModalID_RoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, req := range []reasonModalRequest{
+		{Action: "ban", Target: 123456789012345678, DeleteDays: 3},
+		{Action: "ban", Target: 123456789012345678, DeleteDays: 7, Pool: true},
+	} {
+		got, err := parseReasonModalID(req.customID())
+		if err != nil {
+			t.Fatalf("parseReasonModalID: %v", err)
+		}
+		if got != req {
+			t.Fatalf("round trip: got %+v, want %+v", got, req)
+		}
+		if len(req.customID()) > 100 {
+			t.Fatalf("custom ID exceeds Discord's 100 character limit: %q", req.customID())
+		}
+	}
+
+	for _, bad := range []string{"moderation:reason|ban|x|0", "moderation:reason|ban|1|9", "moderation:reason|ban", "moderation:reason|ban|1|0|x"} {
+		if _, err := parseReasonModalID(bad); err == nil {
+			t.Errorf("parseReasonModalID(%q): expected error", bad)
+		}
+	}
+}
+
+func TestValidateReason(t *testing.T) {
+	t.Parallel()
+	if got, err := validateReason("  spam  "); err != nil || got != "spam" {
+		t.Fatalf("validateReason: got %q, %v", got, err)
+	}
+	if _, err := validateReason("   "); err == nil {
+		t.Fatal("expected blank reason to be rejected")
+	}
+	if _, err := validateReason(strings.Repeat("é", maxReasonLength)); err != nil {
+		t.Fatalf("expected %d characters to be accepted: %v", maxReasonLength, err)
+	}
+	if _, err := validateReason(strings.Repeat("a", maxReasonLength+1)); err == nil {
+		t.Fatal("expected overlong reason to be rejected")
+	}
+}
+
+// FuzzParseReasonModalID feeds arbitrary custom IDs, which a client can
+// forge, to the modal route. Whatever parses must round-trip.
+func FuzzParseReasonModalID(f *testing.F) {
+	f.Add(reasonModalRequest{Action: "ban", Target: 123456789012345678, DeleteDays: 3}.customID())
+	f.Add(reasonModalRequest{Action: "ban", Target: 123456789012345678, DeleteDays: 7, Pool: true}.customID())
+	f.Add("moderation:reason|ban|-1|0")
+	f.Add("moderation:reason|||||")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, customID string) {
+		req, err := parseReasonModalID(customID)
+		if err != nil {
+			return
+		}
+		if !req.Target.IsValid() || req.DeleteDays < 0 || req.DeleteDays > maxBanDeleteDays {
+			t.Fatalf("parseReasonModalID(%q) accepted an out-of-range request %+v", customID, req)
+		}
+		again, err := parseReasonModalID(req.customID())
+		if err != nil || again != req {
+			t.Fatalf("round trip of %+v: got %+v, %v", req, again, err)
+		}
+	})
+}
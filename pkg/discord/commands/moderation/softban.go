@@ -0,0 +1,134 @@
+package moderation
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+)
+
+// SoftbanCommand encapsulates the `/softban` slash command execution. A
+// softban removes a member and deletes their recent messages without keeping
+// them banned.
+type SoftbanCommand struct {
+	service *discordmod.Service
+	cases   *caseLog
+	metrics Metrics
+	logger  *slog.Logger
+}
+
+func (c *SoftbanCommand) Name() string { return "softban" }
+func (c *SoftbanCommand) Description() string {
+	return "Ban and immediately unban a user to delete their recent messages"
+}
+func (c *SoftbanCommand) Options() []discord.CommandOption {
+	return []discord.CommandOption{
+		&discord.UserOption{
+			OptionName:  "user",
+			Description: "User to softban",
+			Required:    true,
+		},
+		reasonOption("Reason for the softban"),
+		deleteMessagesOption(),
+	}
+}
+
+func (c *SoftbanCommand) RequiresGuild() bool       { return true }
+func (c *SoftbanCommand) RequiresPermissions() bool { return true }
+func (c *SoftbanCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionBanMembers
+}
+
+func (c *SoftbanCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("softban")
+
+	var userID discord.UserID
+	var reason string
+	deleteDays := softbanDeleteDays(ctx)
+
+	if ctx.Interaction != nil && ctx.Interaction.Data != nil && ctx.Interaction.Data.InteractionType() == discord.CommandInteractionType {
+		cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
+		for _, opt := range cmdData.Options {
+			switch opt.Name {
+			case "user":
+				if val, err := opt.SnowflakeValue(); err == nil {
+					userID = discord.UserID(val)
+				}
+			case "reason":
+				reason = strings.TrimSpace(opt.String())
+			case deleteMessagesOptionName:
+				if val, err := opt.IntValue(); err == nil {
+					deleteDays = int(val)
+				}
+			}
+		}
+	}
+
+	// The reason modal has to be the first response, so the interaction is
+	// only deferred once it is clear no modal opens.
+	msg, authorized := "Invalid user specified.", false
+	if userID.IsValid() {
+		msg, authorized = authorizeTarget(ctx, c.service, c.logger, userID)
+	}
+	if authorized && reason == "" {
+		return openReasonModal(ctx, reasonModalRequest{Action: "softban", Target: userID, DeleteDays: deleteDays})
+	}
+	if err := ctx.Defer(discord.EphemeralMessage); err != nil {
+		return err
+	}
+	if !authorized {
+		return respondEphemeral(ctx, msg)
+	}
+	return c.execute(ctx, userID, deleteDays, reason)
+}
+
+// execute bans and unbans userID once the target is authorized and the reason
+// is known. The pair counts as a single action against the moderator limit.
+func (c *SoftbanCommand) execute(ctx *commands.ArikawaContext, userID discord.UserID, deleteDays int, reason string) error {
+	if msg, ok := reserveActions(ctx, c.service, c.logger, 1); !ok {
+		return respondEphemeral(ctx, msg)
+	}
+	c.logger.Info("Architectural state transition: Executing moderation action from slash command",
+		slog.String("command", "softban"),
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("target_id", userID.String()),
+		slog.Int("delete_days", deleteDays),
+	)
+
+	bg := actionContext(ctx)
+	if err := c.service.Ban(bg, ctx.GuildID, userID, deleteDays*secondsPerDay, reason); err != nil {
+		c.service.ReleaseActions(ctx.GuildID, ctx.UserID, 1)
+		c.logger.Error("Blocking structural failure: Softban command execution aborted",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("error", err.Error()),
+		)
+		return respondEphemeral(ctx, "Failed to softban the user.")
+	}
+
+	// The ban already removed the member and their messages, so the case is
+	// recorded even if lifting the ban fails.
+	recorded, ok := c.cases.record(ctx, caseActionSoftban, userID, reason)
+	if err := c.service.Unban(bg, ctx.GuildID, userID, api.AuditLogReason("Softban: "+reason)); err != nil {
+		c.logger.Error("Blocking structural failure: Softban left the user banned",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("target_id", userID.String()),
+			slog.String("error", err.Error()),
+		)
+		return respondEphemeral(ctx, fmt.Sprintf("Banned user %s%s, but the ban could not be lifted. Unban them manually.", userID, caseSuffix(recorded, ok)))
+	}
+	return respondEphemeral(ctx, fmt.Sprintf("Successfully softbanned user %s%s.", userID, caseSuffix(recorded, ok)))
+}
+
+// softbanDeleteDays defaults the deletion window to one day when the guild
+// keeps messages on ban, since removing messages is the point of a softban.
+func softbanDeleteDays(ictx *commands.ArikawaContext) int {
+	if days := banDeleteDays(ictx); days > 0 {
+		return days
+	}
+	return 1
+}
@@ -0,0 +1,170 @@
+package moderation
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+)
+
+// stageClient is the part of *api.Client /stage acts through. Arikawa does
+// not wrap Discord's voice state endpoint, so speakers are brought up and
+// sent back with a raw request.
+type stageClient interface {
+	FastRequest(method, url string, opts ...httputil.RequestOption) error
+	DeleteStageInstance(channelID discord.ChannelID, reason api.AuditLogReason) error
+}
+
+// setSuppressed sends userID in the stage channelID to the audience, or
+// brings them up to speak when suppress is false. The member must already
+// be in the stage.
+func setSuppressed(client stageClient, guildID discord.GuildID, channelID discord.ChannelID, userID discord.UserID, suppress bool) error {
+	return client.FastRequest("PATCH",
+		api.EndpointGuilds+guildID.String()+"/voice-states/"+userID.String(),
+		httputil.WithJSONBody(struct {
+			ChannelID discord.ChannelID `json:"channel_id"`
+			Suppress  bool              `json:"suppress"`
+		}{channelID, suppress}),
+	)
+}
+
+// StageCommand encapsulates the `/stage` slash command execution.
+type StageCommand struct {
+	metrics Metrics
+	logger  *slog.Logger
+}
+
+func (c *StageCommand) Name() string        { return "stage" }
+func (c *StageCommand) Description() string { return "Manage the speakers of a stage, or end it" }
+func (c *StageCommand) Options() []discord.CommandOption {
+	stageOption := &discord.ChannelOption{
+		OptionName:   "channel",
+		Description:  "Stage channel",
+		Required:     true,
+		ChannelTypes: []discord.ChannelType{discord.GuildStageVoice},
+	}
+	memberOptions := func(description string) []discord.CommandOptionValue {
+		return []discord.CommandOptionValue{
+			&discord.UserOption{OptionName: "user", Description: description, Required: true},
+			stageOption,
+		}
+	}
+	return []discord.CommandOption{
+		&discord.SubcommandOption{
+			OptionName:  "speaker",
+			Description: "Bring a member in the stage up to speak",
+			Options:     memberOptions("Member to bring up"),
+		},
+		&discord.SubcommandOption{
+			OptionName:  "audience",
+			Description: "Send a speaker back to the audience",
+			Options:     memberOptions("Speaker to send back"),
+		},
+		&discord.SubcommandOption{
+			OptionName:  "end",
+			Description: "End a live stage for everyone",
+			Options: []discord.CommandOptionValue{
+				stageOption,
+				&discord.StringOption{
+					OptionName:  "reason",
+					Description: "Reason shown in the audit log",
+					MaxLength:   option.NewInt(maxReasonLength),
+				},
+			},
+		},
+	}
+}
+
+func (c *StageCommand) RequiresGuild() bool       { return true }
+func (c *StageCommand) RequiresPermissions() bool { return true }
+func (c *StageCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionMuteMembers
+}
+
+func (c *StageCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("stage")
+
+	if ctx.Interaction == nil || ctx.Interaction.Data == nil || ctx.Interaction.Data.InteractionType() != discord.CommandInteractionType {
+		return nil
+	}
+	if err := ctx.Defer(discord.EphemeralMessage); err != nil {
+		return err
+	}
+	cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
+	if len(cmdData.Options) == 0 {
+		return respondEphemeral(ctx, "Choose speaker, audience or end.")
+	}
+	sub := cmdData.Options[0]
+
+	var (
+		channelID discord.ChannelID
+		userID    discord.UserID
+		reason    string
+	)
+	for _, opt := range sub.Options {
+		switch opt.Name {
+		case "channel":
+			if val, err := opt.SnowflakeValue(); err == nil {
+				channelID = discord.ChannelID(val)
+			}
+		case "user":
+			if val, err := opt.SnowflakeValue(); err == nil {
+				userID = discord.UserID(val)
+			}
+		case "reason":
+			reason = strings.TrimSpace(opt.String())
+		}
+	}
+	ch, err := ctx.Client.Channel(channelID)
+	if err != nil || ch.GuildID != ctx.GuildID || ch.Type != discord.GuildStageVoice {
+		return respondEphemeral(ctx, "Invalid stage channel specified.")
+	}
+	if sub.Name != "end" && !userID.IsValid() {
+		return respondEphemeral(ctx, "Invalid user specified.")
+	}
+
+	c.logger.Info("Architectural state transition: Executing moderation action from slash command",
+		slog.String("command", "stage "+sub.Name),
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("channel_id", channelID.String()),
+	)
+	switch sub.Name {
+	case "speaker", "audience":
+		suppress := sub.Name == "audience"
+		if !inTestMode(ctx) {
+			if err := setSuppressed(ctx.Client, ctx.GuildID, channelID, userID, suppress); err != nil {
+				c.logFailure(ctx, sub.Name, channelID, err)
+				return respondEphemeral(ctx, fmt.Sprintf("Failed to move <@%s>. They must be in <#%s>, and the bot needs Mute Members there.", userID, channelID))
+			}
+		}
+		if suppress {
+			return respondEphemeral(ctx, fmt.Sprintf("<@%s> is back in the audience of <#%s>.", userID, channelID))
+		}
+		return respondEphemeral(ctx, fmt.Sprintf("<@%s> is now a speaker in <#%s>.", userID, channelID))
+
+	case "end":
+		if !inTestMode(ctx) {
+			if err := ctx.Client.DeleteStageInstance(channelID, api.AuditLogReason(reason)); err != nil {
+				c.logFailure(ctx, sub.Name, channelID, err)
+				return respondEphemeral(ctx, fmt.Sprintf("Failed to end the stage in <#%s>. It may not be live, or the bot lacks the permissions to moderate it.", channelID))
+			}
+		}
+		return respondEphemeral(ctx, fmt.Sprintf("Ended the stage in <#%s>.", channelID))
+	}
+	return respondEphemeral(ctx, "Choose speaker, audience or end.")
+}
+
+func (c *StageCommand) logFailure(ctx *commands.ArikawaContext, action string, channelID discord.ChannelID, err error) {
+	c.logger.Error("Blocking structural failure: Stage operation aborted",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("channel_id", channelID.String()),
+		slog.String("action", action),
+		slog.String("error", err.Error()),
+	)
+}
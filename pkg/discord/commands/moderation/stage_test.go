@@ -0,0 +1,59 @@
+package moderation
+
+import (
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+)
+
+type recordingStageClient struct {
+	method, url string
+	ended       discord.ChannelID
+}
+
+func (c *recordingStageClient) FastRequest(method, url string, _ ...httputil.RequestOption) error {
+	c.method, c.url = method, url
+	return nil
+}
+
+func (c *recordingStageClient) DeleteStageInstance(channelID discord.ChannelID, _ api.AuditLogReason) error {
+	c.ended = channelID
+	return nil
+}
+
+func TestSetSuppressed(t *testing.T) {
+	t.Parallel()
+	client := &recordingStageClient{}
+	if err := setSuppressed(client, 1, 2, 3, false); err != nil {
+		t.Fatalf("setSuppressed: %v", err)
+	}
+	if client.method != "PATCH" || client.url != api.EndpointGuilds+"1/voice-states/3" {
+		t.Fatalf("unexpected request %s %s", client.method, client.url)
+	}
+}
+
+func TestStageCommandOptions(t *testing.T) {
+	t.Parallel()
+	subs := map[string]*discord.SubcommandOption{}
+	for _, opt := range (&StageCommand{}).Options() {
+		sub := opt.(*discord.SubcommandOption)
+		subs[sub.OptionName] = sub
+	}
+	for _, name := range []string{"speaker", "audience", "end"} {
+		sub, ok := subs[name]
+		if !ok {
+			t.Fatalf("missing /stage %s", name)
+		}
+		var stage bool
+		for _, opt := range sub.Options {
+			if ch, ok := opt.(*discord.ChannelOption); ok && ch.Required && len(ch.ChannelTypes) == 1 && ch.ChannelTypes[0] == discord.GuildStageVoice {
+				stage = true
+			}
+		}
+		if !stage {
+			t.Fatalf("expected /stage %s to take a required stage channel", name)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package moderation
+
+import (
+	"log/slog"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// TestModeCommand encapsulates the `/testmode` slash command execution.
+type TestModeCommand struct {
+	metrics Metrics
+	logger  *slog.Logger
+}
+
+func (c *TestModeCommand) Name() string { return "testmode" }
+func (c *TestModeCommand) Description() string {
+	return "Simulate moderation and automod actions instead of taking them"
+}
+func (c *TestModeCommand) Options() []discord.CommandOption {
+	return []discord.CommandOption{
+		&discord.BooleanOption{
+			OptionName:  "enable",
+			Description: "Turn test mode on or off (leave out to show whether it is on)",
+		},
+	}
+}
+
+func (c *TestModeCommand) RequiresGuild() bool       { return true }
+func (c *TestModeCommand) RequiresPermissions() bool { return true }
+func (c *TestModeCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionManageGuild
+}
+
+func (c *TestModeCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("testmode")
+
+	if ctx.Interaction == nil || ctx.Interaction.Data == nil || ctx.Interaction.Data.InteractionType() != discord.CommandInteractionType {
+		return nil
+	}
+	if err := ctx.Defer(discord.EphemeralMessage); err != nil {
+		return err
+	}
+	cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
+	var enable, set bool
+	for _, opt := range cmdData.Options {
+		if opt.Name != "enable" {
+			continue
+		}
+		if val, err := opt.BoolValue(); err == nil {
+			enable, set = val, true
+		}
+	}
+	if !set {
+		return editResponse(ctx, testModeStatus(inTestMode(ctx)))
+	}
+	if ctx.Config == nil {
+		return editResponse(ctx, "Configuration is unavailable; nothing was changed.")
+	}
+
+	var changed bool
+	err := ctx.Config.UpdateGuildConfig(ctx.GuildID.String(), func(cfg *files.GuildConfig) error {
+		changed = cfg.TestMode != enable
+		cfg.TestMode = enable
+		return nil
+	})
+	if err != nil {
+		c.logger.Error("Blocking structural failure: Test mode could not be saved",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("error", err.Error()),
+		)
+		return editResponse(ctx, "Failed to save test mode.")
+	}
+	if !changed {
+		return editResponse(ctx, testModeStatus(enable))
+	}
+
+	c.logger.Info("Architectural state transition: Test mode switched",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.Bool("enabled", enable),
+		slog.String("user_id", ctx.UserID.String()),
+	)
+	if enable {
+		return editResponse(ctx, "Test mode is now on. Moderation commands and automod will only simulate their actions, and what they would have done is logged with a TEST banner.")
+	}
+	return editResponse(ctx, "Test mode is now off. Moderation commands and automod act for real again.")
+}
+
+func testModeStatus(on bool) string {
+	if on {
+		return "Test mode is on: moderation and automod actions are only simulated."
+	}
+	return "Test mode is off."
+}
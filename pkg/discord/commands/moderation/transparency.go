@@ -0,0 +1,124 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+// Report periods offered by /transparency.
+const (
+	transparencyPreviousMonth = "previous_month"
+	transparencyThisMonth     = "this_month"
+)
+
+// TransparencyCommand encapsulates the `/transparency` slash command
+// execution.
+type TransparencyCommand struct {
+	src     discordmod.TransparencySource
+	metrics Metrics
+	logger  *slog.Logger
+	now     func() time.Time
+}
+
+func (c *TransparencyCommand) Name() string        { return "transparency" }
+func (c *TransparencyCommand) Description() string { return "Show or post the transparency report" }
+func (c *TransparencyCommand) Options() []discord.CommandOption {
+	return []discord.CommandOption{
+		&discord.StringOption{
+			OptionName:  "period",
+			Description: "Month to report on (default the previous month)",
+			Choices: []discord.StringChoice{
+				{Name: "Previous month", Value: transparencyPreviousMonth},
+				{Name: "This month so far", Value: transparencyThisMonth},
+			},
+		},
+		&discord.BooleanOption{
+			OptionName:  "post",
+			Description: "Post the report to the transparency channel instead of only showing it to you",
+		},
+	}
+}
+
+func (c *TransparencyCommand) RequiresGuild() bool       { return true }
+func (c *TransparencyCommand) RequiresPermissions() bool { return true }
+func (c *TransparencyCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionManageGuild
+}
+
+func (c *TransparencyCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("transparency")
+
+	if ctx.Interaction == nil || ctx.Interaction.Data == nil || ctx.Interaction.Data.InteractionType() != discord.CommandInteractionType {
+		return nil
+	}
+	if err := ctx.Defer(discord.EphemeralMessage); err != nil {
+		return err
+	}
+	cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
+	period := transparencyPreviousMonth
+	var post bool
+	for _, opt := range cmdData.Options {
+		switch opt.Name {
+		case "period":
+			period = opt.String()
+		case "post":
+			if val, err := opt.BoolValue(); err == nil {
+				post = val
+			}
+		}
+	}
+
+	from, to := transparencyPeriod(period, c.now())
+	report, err := discordmod.BuildTransparencyReport(context.Background(), c.src, ctx.GuildID.String(), from, to)
+	if err != nil {
+		c.logger.Error("Blocking structural failure: Transparency report could not be compiled",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("error", err.Error()),
+		)
+		return respondEphemeral(ctx, "Failed to compile the transparency report.")
+	}
+	embed := discordmod.TransparencyEmbed(report)
+
+	if post {
+		var channel string
+		if ctx.GuildConfig != nil {
+			channel = strings.TrimSpace(ctx.GuildConfig.Channels.Transparency)
+		}
+		channelID, err := discord.ParseSnowflake(channel)
+		if err != nil || !channelID.IsValid() {
+			return respondEphemeral(ctx, "No transparency channel is configured.")
+		}
+		if _, err := ctx.Client.SendEmbeds(discord.ChannelID(channelID), embed); err != nil {
+			c.logger.Warn("Mitigated service degradation: Transparency report not posted",
+				slog.String("guild_id", ctx.GuildID.String()),
+				slog.String("channel_id", channel),
+				slog.String("error", err.Error()),
+			)
+			return respondEphemeral(ctx, "Failed to post the report. Check that I can send messages there.")
+		}
+		return respondEphemeral(ctx, fmt.Sprintf("Posted the transparency report to <#%s>.", channel))
+	}
+	_, err = ctx.Client.EditInteractionResponse(ctx.Interaction.AppID, ctx.Interaction.Token, api.EditInteractionResponseData{
+		Embeds: &[]discord.Embed{embed},
+	})
+	return err
+}
+
+// transparencyPeriod returns the [from, to) range a report period covers.
+func transparencyPeriod(period string, now time.Time) (time.Time, time.Time) {
+	if period == transparencyThisMonth {
+		_, start := coremod.PreviousMonth(now)
+		return start, now.UTC()
+	}
+	return coremod.PreviousMonth(now)
+}
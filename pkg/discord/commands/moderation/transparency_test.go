@@ -0,0 +1,21 @@
+package moderation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTransparencyPeriod(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	march := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	from, to := transparencyPeriod(transparencyPreviousMonth, now)
+	if !from.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(march) {
+		t.Fatalf("previous month = %v to %v", from, to)
+	}
+	from, to = transparencyPeriod(transparencyThisMonth, now)
+	if !from.Equal(march) || !to.Equal(now) {
+		t.Fatalf("this month = %v to %v", from, to)
+	}
+}
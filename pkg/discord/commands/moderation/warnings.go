@@ -0,0 +1,379 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/json/option"
+
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+const warningsListLimit = 10
+
+// WarningStore persists member warnings. *postgres.Store satisfies it.
+type WarningStore interface {
+	CreateModerationWarning(ctx context.Context, guildID, userID, moderatorID, reason string, createdAt time.Time) (coremod.Warning, error)
+	ListModerationWarnings(ctx context.Context, guildID, userID string, limit int) iter.Seq2[coremod.Warning, error]
+	CountModerationWarnings(ctx context.Context, guildID, userID string) (int, error)
+	DeleteModerationWarning(ctx context.Context, guildID string, caseNumber int64) (coremod.Warning, bool, error)
+	ClearModerationWarnings(ctx context.Context, guildID, userID string) (int64, error)
+}
+
+// WarnCommand encapsulates the `/warn` slash command execution.
+type WarnCommand struct {
+	service *discordmod.Service
+	store   WarningStore
+	cases   *caseLog
+	metrics Metrics
+	logger  *slog.Logger
+}
+
+func (c *WarnCommand) Name() string        { return "warn" }
+func (c *WarnCommand) Description() string { return "Warn a member and record it as a case" }
+func (c *WarnCommand) Options() []discord.CommandOption {
+	return []discord.CommandOption{
+		&discord.UserOption{
+			OptionName:  "user",
+			Description: "Member to warn",
+			Required:    true,
+		},
+		&discord.StringOption{
+			OptionName:  "reason",
+			Description: "Reason for the warning",
+			Required:    true,
+			MaxLength:   option.NewInt(maxReasonLength),
+		},
+	}
+}
+
+func (c *WarnCommand) RequiresGuild() bool       { return true }
+func (c *WarnCommand) RequiresPermissions() bool { return true }
+func (c *WarnCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionModerateMembers
+}
+
+func (c *WarnCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("warn")
+
+	if err := ctx.Defer(discord.EphemeralMessage); err != nil {
+		return err
+	}
+
+	var userID discord.UserID
+	var rawReason string
+	if ctx.Interaction != nil && ctx.Interaction.Data != nil && ctx.Interaction.Data.InteractionType() == discord.CommandInteractionType {
+		cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
+		for _, opt := range cmdData.Options {
+			switch opt.Name {
+			case "user":
+				if val, err := opt.SnowflakeValue(); err == nil {
+					userID = discord.UserID(val)
+				}
+			case "reason":
+				rawReason = opt.String()
+			}
+		}
+	}
+
+	if !userID.IsValid() {
+		return respondEphemeral(ctx, "Invalid user specified.")
+	}
+	reason, err := validateReason(rawReason)
+	if err != nil {
+		return respondEphemeral(ctx, fmt.Sprintf("Nothing was done: %v.", err))
+	}
+	if msg, ok := authorizeTarget(ctx, c.service, c.logger, userID); !ok {
+		return respondEphemeral(ctx, msg)
+	}
+
+	warning, count, err := c.record(ctx, userID, reason)
+	if err != nil {
+		c.logger.Error("Blocking structural failure: Warning could not be recorded",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("target_id", userID.String()),
+			slog.String("error", err.Error()),
+		)
+		return respondEphemeral(ctx, "Failed to record the warning.")
+	}
+
+	c.logger.Info("Architectural state transition: Executing moderation action from slash command",
+		slog.String("command", "warn"),
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("target_id", userID.String()),
+		slog.Int64("case_number", warning.CaseNumber),
+	)
+
+	msg := fmt.Sprintf("Warned <@%s> (case #%d).", userID, warning.CaseNumber)
+	if inTestMode(ctx) {
+		msg = fmt.Sprintf("Simulated a warning for <@%s>.", userID)
+	}
+	if count < 0 {
+		return respondEphemeral(ctx, msg)
+	}
+	msg += fmt.Sprintf(" They now have %d warning%s.", count, plural(count))
+
+	if step, ok := ctx.GuildConfig.WarningEscalationFor(count); ok {
+		msg += "\n" + c.escalate(ctx, userID, count, step)
+	}
+	return respondEphemeral(ctx, msg)
+}
+
+// record stores a warning for userID and returns it with the member's
+// warning count, or -1 when the count is unavailable. In test mode nothing
+// is stored: a simulated case is posted and the count is the one the warning
+// would have brought, so escalation can be previewed.
+func (c *WarnCommand) record(ctx *commands.ArikawaContext, userID discord.UserID, reason string) (coremod.Warning, int, error) {
+	bg := context.Background()
+	guildID := ctx.GuildID.String()
+	var warning coremod.Warning
+	if inTestMode(ctx) {
+		if c.cases != nil {
+			c.cases.postSimulated(ctx, coremod.Case{
+				GuildID:     guildID,
+				Action:      caseActionWarn,
+				UserID:      userID.String(),
+				ModeratorID: ctx.UserID.String(),
+				Reason:      reason,
+				Source:      coremod.CaseSourceManual,
+			})
+		}
+	} else {
+		var err error
+		warning, err = c.store.CreateModerationWarning(bg, guildID, userID.String(), ctx.UserID.String(), reason, time.Now())
+		if err != nil {
+			return coremod.Warning{}, 0, err
+		}
+	}
+
+	count, err := c.store.CountModerationWarnings(bg, guildID, userID.String())
+	if err != nil {
+		c.logger.Warn("Mitigated service degradation: Warning count unavailable, escalation skipped",
+			slog.String("guild_id", guildID),
+			slog.String("target_id", userID.String()),
+			slog.String("error", err.Error()),
+		)
+		return warning, -1, nil
+	}
+	if inTestMode(ctx) {
+		count++
+	}
+	return warning, count, nil
+}
+
+// escalate applies step to userID through the escalation shared with
+// automod warnings and describes the outcome for the invoker.
+func (c *WarnCommand) escalate(ctx *commands.ArikawaContext, userID discord.UserID, count int, step files.WarningEscalationStep) string {
+	var done string
+	switch step.Action {
+	case files.WarningEscalationTimeout:
+		done = fmt.Sprintf("Escalation: timed out for %d minutes.", step.DurationMinutes)
+	case files.WarningEscalationKick, files.WarningEscalationBan:
+		if msg, ok := reserveActions(ctx, c.service, c.logger, 1); !ok {
+			return "Escalation skipped: " + msg
+		}
+		done = "Escalation: kicked."
+		if step.Action == files.WarningEscalationBan {
+			done = "Escalation: banned."
+		}
+	}
+	until, err := c.service.Escalate(actionContext(ctx), ctx.GuildID, userID, count, step, banDeleteDays(ctx))
+	if err != nil {
+		if step.Action != files.WarningEscalationTimeout {
+			c.service.ReleaseActions(ctx.GuildID, ctx.UserID, 1)
+		}
+		c.logger.Error("Blocking structural failure: Warning escalation aborted",
+			slog.String("guild_id", ctx.GuildID.String()),
+			slog.String("target_id", userID.String()),
+			slog.String("action", step.Action),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Sprintf("Escalation (%s) failed.", step.Action)
+	}
+
+	c.cases.recordTimed(ctx, step.Action, userID, discordmod.EscalationReason(count), until, notice{})
+	c.logger.Info("Architectural state transition: Warning escalation applied",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("target_id", userID.String()),
+		slog.String("action", step.Action),
+		slog.Int("warnings", count),
+	)
+	return done
+}
+
+// WarningsCommand encapsulates the `/warnings` slash command execution.
+type WarningsCommand struct {
+	store WarningStore
+	// notes, when set, adds the member's recent notes to the list.
+	notes   NoteStore
+	metrics Metrics
+	logger  *slog.Logger
+}
+
+func (c *WarningsCommand) Name() string        { return "warnings" }
+func (c *WarningsCommand) Description() string { return "Review and manage member warnings" }
+func (c *WarningsCommand) Options() []discord.CommandOption {
+	return []discord.CommandOption{
+		&discord.SubcommandOption{
+			OptionName:  "list",
+			Description: "List a member's most recent warnings",
+			Options: []discord.CommandOptionValue{
+				&discord.UserOption{OptionName: "user", Description: "Member to look up", Required: true},
+			},
+		},
+		&discord.SubcommandOption{
+			OptionName:  "remove",
+			Description: "Remove a single warning by case number",
+			Options: []discord.CommandOptionValue{
+				&discord.IntegerOption{OptionName: "case", Description: "Case number of the warning", Required: true, Min: option.NewInt(1)},
+			},
+		},
+		&discord.SubcommandOption{
+			OptionName:  "clear",
+			Description: "Remove every warning a member holds",
+			Options: []discord.CommandOptionValue{
+				&discord.UserOption{OptionName: "user", Description: "Member whose warnings to clear", Required: true},
+			},
+		},
+	}
+}
+
+func (c *WarningsCommand) RequiresGuild() bool       { return true }
+func (c *WarningsCommand) RequiresPermissions() bool { return true }
+func (c *WarningsCommand) DefaultMemberPermissions() discord.Permissions {
+	return discord.PermissionModerateMembers
+}
+
+func (c *WarningsCommand) Handle(ctx *commands.ArikawaContext) error {
+	c.metrics.RecordCommandExec("warnings")
+
+	if ctx.Interaction == nil || ctx.Interaction.Data == nil || ctx.Interaction.Data.InteractionType() != discord.CommandInteractionType {
+		return nil
+	}
+	if err := ctx.Defer(discord.EphemeralMessage); err != nil {
+		return err
+	}
+	cmdData := ctx.Interaction.Data.(*discord.CommandInteraction)
+	if len(cmdData.Options) == 0 {
+		return respondEphemeral(ctx, "Choose list, remove or clear.")
+	}
+	sub := cmdData.Options[0]
+
+	var (
+		userID     discord.UserID
+		caseNumber int64
+	)
+	for _, opt := range sub.Options {
+		switch opt.Name {
+		case "user":
+			if val, err := opt.SnowflakeValue(); err == nil {
+				userID = discord.UserID(val)
+			}
+		case "case":
+			if val, err := opt.IntValue(); err == nil {
+				caseNumber = val
+			}
+		}
+	}
+
+	guildID := ctx.GuildID.String()
+	bg := context.Background()
+	switch sub.Name {
+	case "list":
+		if !userID.IsValid() {
+			return respondEphemeral(ctx, "Invalid user specified.")
+		}
+		var warnings []coremod.Warning
+		for w, err := range c.store.ListModerationWarnings(bg, guildID, userID.String(), warningsListLimit) {
+			if err != nil {
+				c.logFailure(ctx, "list", err)
+				return respondEphemeral(ctx, "Failed to load warnings.")
+			}
+			warnings = append(warnings, w)
+		}
+		total, err := c.store.CountModerationWarnings(bg, guildID, userID.String())
+		if err != nil {
+			total = len(warnings)
+		}
+		resp := core.NewResponse().Embeds(buildWarningsEmbed(userID, warnings, total))
+		if c.notes != nil {
+			// Notes give the context behind the warnings; without them the
+			// list still stands on its own.
+			if notes, err := recentNotes(c.notes, guildID, userID); err != nil {
+				c.logFailure(ctx, "list notes", err)
+			} else {
+				resp.Embeds(buildNotesEmbed(userID, notes))
+			}
+		}
+		return resp.EditDeferred(ctx.Client, ctx.Interaction)
+	case "remove":
+		warning, found, err := c.store.DeleteModerationWarning(bg, guildID, caseNumber)
+		if err != nil {
+			c.logFailure(ctx, "remove", err)
+			return respondEphemeral(ctx, "Failed to remove the warning.")
+		}
+		if !found {
+			return respondEphemeral(ctx, fmt.Sprintf("No warning has case number #%d.", caseNumber))
+		}
+		return respondEphemeral(ctx, fmt.Sprintf("Removed warning #%d from <@%s>.", warning.CaseNumber, warning.UserID))
+	case "clear":
+		if !userID.IsValid() {
+			return respondEphemeral(ctx, "Invalid user specified.")
+		}
+		removed, err := c.store.ClearModerationWarnings(bg, guildID, userID.String())
+		if err != nil {
+			c.logFailure(ctx, "clear", err)
+			return respondEphemeral(ctx, "Failed to clear warnings.")
+		}
+		return respondEphemeral(ctx, fmt.Sprintf("Cleared %d warning%s from <@%s>.", removed, plural(int(removed)), userID))
+	default:
+		return respondEphemeral(ctx, "Choose list, remove or clear.")
+	}
+}
+
+func (c *WarningsCommand) logFailure(ctx *commands.ArikawaContext, action string, err error) {
+	c.logger.Error("Blocking structural failure: Warning maintenance aborted",
+		slog.String("guild_id", ctx.GuildID.String()),
+		slog.String("action", action),
+		slog.String("error", err.Error()),
+	)
+}
+
+func buildWarningsEmbed(userID discord.UserID, warnings []coremod.Warning, total int) discord.Embed {
+	embed := discord.Embed{
+		Title: fmt.Sprintf("Warnings (%d)", total),
+		Color: discord.Color(theme.Warning()),
+	}
+	if len(warnings) == 0 {
+		embed.Description = fmt.Sprintf("<@%s> has no warnings.", userID)
+		return embed
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Most recent warnings for <@%s>:\n", userID)
+	for _, w := range warnings {
+		fmt.Fprintf(&b, "**#%d** %s by <@%s>: %s\n", w.CaseNumber, logging.DiscordTimestamp(w.CreatedAt, logging.TimestampRelative), w.ModeratorID, w.Reason)
+	}
+	embed.Description = b.String()
+	if total > len(warnings) {
+		embed.Footer = &discord.EmbedFooter{Text: fmt.Sprintf("Showing %d of %d", len(warnings), total)}
+	}
+	return embed
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
@@ -0,0 +1,107 @@
+package moderation
+
+import (
+	"context"
+	"iter"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+func TestWarnCommand_EscalationAppliesConfiguredAction(t *testing.T) {
+	t.Parallel()
+	client := &mockClient{}
+	c := &WarnCommand{service: discordmod.NewService(client, nil), logger: slog.Default()}
+	ctx := &commands.ArikawaContext{GuildID: discord.GuildID(1), UserID: discord.UserID(2)}
+
+	got := c.escalate(ctx, discord.UserID(3), 3, files.WarningEscalationStep{Warnings: 3, Action: files.WarningEscalationTimeout, DurationMinutes: 60})
+	if !client.timeoutCalled || !strings.Contains(got, "60 minutes") {
+		t.Fatalf("expected timeout escalation, got %q (timeout called: %v)", got, client.timeoutCalled)
+	}
+
+	got = c.escalate(ctx, discord.UserID(3), 5, files.WarningEscalationStep{Warnings: 5, Action: files.WarningEscalationBan})
+	if !client.banCalled || got != "Escalation: banned." {
+		t.Fatalf("expected ban escalation, got %q (ban called: %v)", got, client.banCalled)
+	}
+}
+
+type fakeWarningStore struct {
+	warnings []coremod.Warning
+}
+
+func (f *fakeWarningStore) CreateModerationWarning(_ context.Context, guildID, userID, moderatorID, reason string, createdAt time.Time) (coremod.Warning, error) {
+	w := coremod.Warning{CaseNumber: int64(len(f.warnings) + 1), GuildID: guildID, UserID: userID, ModeratorID: moderatorID, Reason: reason, CreatedAt: createdAt}
+	f.warnings = append(f.warnings, w)
+	return w, nil
+}
+
+func (f *fakeWarningStore) ListModerationWarnings(context.Context, string, string, int) iter.Seq2[coremod.Warning, error] {
+	return func(func(coremod.Warning, error) bool) {}
+}
+
+func (f *fakeWarningStore) CountModerationWarnings(_ context.Context, _, userID string) (int, error) {
+	var n int
+	for _, w := range f.warnings {
+		if w.UserID == userID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeWarningStore) DeleteModerationWarning(context.Context, string, int64) (coremod.Warning, bool, error) {
+	return coremod.Warning{}, false, nil
+}
+
+func (f *fakeWarningStore) ClearModerationWarnings(context.Context, string, string) (int64, error) {
+	return 0, nil
+}
+
+func TestWarnCommand_TestModeStoresNothing(t *testing.T) {
+	t.Parallel()
+	store := &fakeWarningStore{warnings: []coremod.Warning{{CaseNumber: 1, GuildID: "1", UserID: "3"}}}
+	c := &WarnCommand{store: store, cases: &caseLog{store: &fakeCaseStore{}, logger: slog.Default()}, logger: slog.Default()}
+	ctx := &commands.ArikawaContext{GuildID: discord.GuildID(1), UserID: discord.UserID(2), GuildConfig: &files.GuildConfig{GuildID: "1", TestMode: true}}
+
+	_, count, err := c.record(ctx, discord.UserID(3), "trial")
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected the simulated warning to preview a count of 2, got %d", count)
+	}
+	if stored, _ := store.CountModerationWarnings(context.Background(), "1", "3"); stored != 1 {
+		t.Fatalf("test mode changed the stored warning count to %d", stored)
+	}
+
+	ctx.GuildConfig.TestMode = false
+	if w, count, err := c.record(ctx, discord.UserID(3), "real"); err != nil || count != 2 || w.CaseNumber != 2 {
+		t.Fatalf("expected a stored warning outside test mode, got %+v, %d, %v", w, count, err)
+	}
+}
+
+func TestBuildWarningsEmbed(t *testing.T) {
+	t.Parallel()
+	empty := buildWarningsEmbed(discord.UserID(5), nil, 0)
+	if !strings.Contains(empty.Description, "no warnings") || empty.Footer != nil {
+		t.Fatalf("unexpected empty embed: %+v", empty)
+	}
+
+	warnings := []coremod.Warning{
+		{CaseNumber: 7, ModeratorID: "9", Reason: "spam", CreatedAt: time.Unix(1_700_000_000, 0)},
+	}
+	embed := buildWarningsEmbed(discord.UserID(5), warnings, 4)
+	if !strings.Contains(embed.Description, "**#7**") || !strings.Contains(embed.Description, "spam") {
+		t.Fatalf("warning missing from embed: %q", embed.Description)
+	}
+	if embed.Footer == nil || embed.Footer.Text != "Showing 1 of 4" {
+		t.Fatalf("expected truncation footer, got %+v", embed.Footer)
+	}
+}
@@ -1,6 +1,12 @@
 package roles
 
-import "testing"
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
 
 func TestParseRolePanelButtonEmoji(t *testing.T) {
 	t.Parallel()
@@ -58,3 +64,32 @@ func TestParseRolePanelButtonEmoji(t *testing.T) {
 		})
 	}
 }
+
+// FuzzParseRolePanelButtonEmoji checks that any admin-typed emoji either
+// errors or yields a custom emoji with a numeric ID or a bounded glyph.
+func FuzzParseRolePanelButtonEmoji(f *testing.F) {
+	f.Add("<:wave:123456789012345678>")
+	f.Add("<a:spin:123456789012345678>")
+	f.Add("👋")
+	f.Add("<:broken>")
+	f.Add("   ")
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		name, id, animated, err := parseRolePanelButtonEmoji(raw)
+		if err != nil {
+			return
+		}
+		if id == "" {
+			if animated {
+				t.Fatalf("parseRolePanelButtonEmoji(%q): a unicode glyph cannot be animated", raw)
+			}
+			if utf8.RuneCountInString(name) > files.RolePanelLabelMaxLen {
+				t.Fatalf("parseRolePanelButtonEmoji(%q): glyph %q exceeds the label limit", raw, name)
+			}
+			return
+		}
+		if strings.Trim(id, "0123456789") != "" {
+			t.Fatalf("parseRolePanelButtonEmoji(%q): custom emoji ID %q is not numeric", raw, id)
+		}
+	})
+}
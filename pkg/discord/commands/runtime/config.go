@@ -26,6 +26,7 @@ type restartHint string
 const (
 	restartRequired    restartHint = "restart required"
 	restartRecommended restartHint = "restart recommended"
+	restartNone        restartHint = "applies immediately"
 )
 
 // spec details the structural metadata and visual presentation hints for a single config key.
@@ -81,6 +82,18 @@ func buildAllSpecs() []spec {
 	sps = append(sps, spec{
 		Key: "moderation_logging", Group: "MODERATION", Type: vtBool, DefaultHint: "true",
 		ShortHelp: "Enable/disable moderation case embeds", RestartHint: restartRecommended,
+	}, spec{
+		Key: "mass_actions_per_second", Group: "MODERATION", Type: vtInt, DefaultHint: "2",
+		ShortHelp: "Actions per second for mass moderation commands (0 = default)", RestartHint: restartNone, MaxInputLen: 4,
+	}, spec{
+		Key: "ban_delete_message_days", Group: "MODERATION", Type: vtInt, DefaultHint: "0",
+		ShortHelp: "Days of messages (0-7) deleted by bans without an explicit option", RestartHint: restartNone, MaxInputLen: 1,
+	}, spec{
+		Key: "moderator_action_limit", Group: "MODERATION", Type: vtInt, DefaultHint: "0",
+		ShortHelp: "Bans/kicks per moderator per window before lockout (0 = off)", RestartHint: restartNone, MaxInputLen: 4,
+	}, spec{
+		Key: "moderator_action_window_minutes", Group: "MODERATION", Type: vtInt, DefaultHint: "10",
+		ShortHelp: "Window and lockout length in minutes for moderator_action_limit", RestartHint: restartNone, MaxInputLen: 4,
 	})
 
 	// PRESENCE WATCH
@@ -288,6 +301,14 @@ func getValue(rc files.RuntimeConfig, k runtimeKey) (string, bool) {
 		return fmtBool(rc.DisableUserLogs), true
 	case "moderation_logging":
 		return fmtBool(rc.ModerationLoggingEnabled()), true
+	case "mass_actions_per_second":
+		return strconv.Itoa(rc.MassActionsPerSecond), true
+	case "ban_delete_message_days":
+		return strconv.Itoa(rc.BanDeleteMessageDays), true
+	case "moderator_action_limit":
+		return strconv.Itoa(rc.ModeratorActionLimit), true
+	case "moderator_action_window_minutes":
+		return strconv.Itoa(rc.ModeratorActionWindowMinutes), true
 	case "presence_watch_user_id":
 		return rc.PresenceWatchUserID, true
 	case "presence_watch_bot":
@@ -336,6 +357,18 @@ func resetValue(rc files.RuntimeConfig, k runtimeKey) (files.RuntimeConfig, bool
 	case "moderation_logging":
 		rc.ModerationLogging = nil
 		return rc, true
+	case "mass_actions_per_second":
+		rc.MassActionsPerSecond = 0
+		return rc, true
+	case "ban_delete_message_days":
+		rc.BanDeleteMessageDays = 0
+		return rc, true
+	case "moderator_action_limit":
+		rc.ModeratorActionLimit = 0
+		return rc, true
+	case "moderator_action_window_minutes":
+		rc.ModeratorActionWindowMinutes = 0
+		return rc, true
 	case "presence_watch_user_id":
 		rc.PresenceWatchUserID = ""
 		return rc, true
@@ -434,9 +467,25 @@ func setValue(rc files.RuntimeConfig, sp spec, raw string) (files.RuntimeConfig,
 		if err != nil {
 			return rc, fmt.Errorf("setValue: %w", err)
 		}
-		if sp.Key == "message_cache_ttl_hours" {
+		switch sp.Key {
+		case "message_cache_ttl_hours":
 			rc.MessageCacheTTLHours = v
 			return rc, nil
+		case "mass_actions_per_second":
+			rc.MassActionsPerSecond = v
+			return rc, nil
+		case "ban_delete_message_days":
+			if v > 7 {
+				return rc, fmt.Errorf("setValue: must be between 0 and 7")
+			}
+			rc.BanDeleteMessageDays = v
+			return rc, nil
+		case "moderator_action_limit":
+			rc.ModeratorActionLimit = v
+			return rc, nil
+		case "moderator_action_window_minutes":
+			rc.ModeratorActionWindowMinutes = v
+			return rc, nil
 		}
 		return rc, fmt.Errorf("not an int key")
 	case vtDate:
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/core"
 	"github.com/small-frappuccino/discordcore/pkg/files"
 )
 
@@ -23,70 +24,13 @@ const (
 	cidButtonReload = customIDPrefix + "action:reload"
 )
 
-// fieldsForLines rigorously chunks grouped text configurations to ensure strict compliance
-// with Discord's REST API limitations of exactly 1024 bytes per EmbedField value.
+// fieldsForLines lays out the lines of a settings group, showing a
+// placeholder for a group without any.
 func fieldsForLines(name string, lines []string) []discord.EmbedField {
-	if len(lines) == 0 {
-		return []discord.EmbedField{{Name: name, Value: "(no keys)"}}
-	}
-
-	const maxValueLen = 1024
-	var out []discord.EmbedField
-	curName := name
-	curVal := ""
-
-	flush := func() {
-		if curVal == "" {
-			return
-		}
-		out = append(out, discord.EmbedField{
-			Name:   curName,
-			Value:  curVal,
-			Inline: false,
-		})
-		curName = name + " (cont.)"
-		curVal = ""
-	}
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		for len(line) > 0 {
-			if curVal != "" {
-				if len(curVal)+1+len(line) <= maxValueLen {
-					curVal += "\n" + line
-					break
-				}
-				flush()
-			}
-
-			if len(line) <= maxValueLen {
-				curVal = line
-				break
-			}
-
-			chunkBytes := 0
-			for i, r := range line {
-				runeBytes := len(string(r))
-				if chunkBytes+runeBytes > maxValueLen {
-					curVal = line[:i]
-					line = line[i:]
-					flush()
-					break
-				}
-				chunkBytes += runeBytes
-			}
-		}
-	}
-	flush()
-
-	if len(out) == 0 {
-		out = append(out, discord.EmbedField{Name: name, Value: "(no keys)"})
+	if fields := core.FieldsForLines(name, lines); len(fields) > 0 {
+		return fields
 	}
-	return out
+	return []discord.EmbedField{{Name: name, Value: "(no keys)"}}
 }
 
 // formatForEmbed provides a visually condensed representation of a state field.
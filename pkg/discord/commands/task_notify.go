@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/task"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+// interactionFollowUpWindow is how long after dispatch a task may still
+// follow up on the interaction of the command that started it. The margin
+// also covers the time between the command and the dispatch.
+const interactionFollowUpWindow = InteractionTokenLifetime - InteractionExpiryMargin
+
+// Requester returns who ran the command and where, for tasks the command
+// dispatches to report back to.
+func (c *ArikawaContext) Requester() task.Requester {
+	r := task.Requester{UserID: c.UserID.String()}
+	if c.GuildID.IsValid() {
+		r.GuildID = c.GuildID.String()
+	}
+	if c.Interaction != nil {
+		if c.Interaction.ChannelID.IsValid() {
+			r.ChannelID = c.Interaction.ChannelID.String()
+		}
+		r.ApplicationID = c.Interaction.AppID.String()
+		r.InteractionToken = c.Interaction.Token
+	}
+	return r
+}
+
+// TaskNotifyClient is the part of *api.Client a TaskNotifier sends through.
+type TaskNotifyClient interface {
+	FollowUpInteraction(appID discord.AppID, token string, data api.InteractionResponseData) (*discord.Message, error)
+	SendMessageComplex(channelID discord.ChannelID, data api.SendMessageData) (*discord.Message, error)
+}
+
+// TaskNotifier tells the requester of a task how it ended with a summary
+// embed: privately as a follow-up to their command while the interaction
+// allows it, then in the channel they ran it from, mentioning them. Its
+// Notify method is a task.CompletionFunc.
+type TaskNotifier struct {
+	client TaskNotifyClient
+	logger *slog.Logger
+}
+
+// NewTaskNotifier creates a TaskNotifier sending through client.
+func NewTaskNotifier(client TaskNotifyClient, logger *slog.Logger) *TaskNotifier {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &TaskNotifier{client: client, logger: logger}
+}
+
+// Notify reports c to its requester.
+func (n *TaskNotifier) Notify(_ context.Context, c task.Completion) {
+	if n == nil || n.client == nil {
+		return
+	}
+	embed := TaskCompletionEmbed(c)
+	r := c.Requester
+
+	if r.InteractionToken != "" && c.Duration < interactionFollowUpWindow {
+		appID, err := discord.ParseSnowflake(r.ApplicationID)
+		if err == nil && appID.IsValid() {
+			_, err = n.client.FollowUpInteraction(discord.AppID(appID), r.InteractionToken, api.InteractionResponseData{
+				Embeds: &[]discord.Embed{embed},
+				Flags:  discord.EphemeralMessage,
+			})
+			if err == nil {
+				return
+			}
+			n.logger.Debug("Task completion follow-up failed; falling back to the channel",
+				slog.String("guild_id", r.GuildID),
+				slog.String("task", c.Type),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	channelID, err := discord.ParseSnowflake(r.ChannelID)
+	if err != nil || !channelID.IsValid() {
+		return
+	}
+	data := api.SendMessageData{Embeds: []discord.Embed{embed}}
+	if userID, err := discord.ParseSnowflake(r.UserID); err == nil && userID.IsValid() {
+		data.Content = "<@" + r.UserID + ">"
+		data.AllowedMentions = &api.AllowedMentions{Users: []discord.UserID{discord.UserID(userID)}}
+	}
+	if _, err := n.client.SendMessageComplex(discord.ChannelID(channelID), data); err != nil {
+		n.logger.Warn("Mitigated service degradation: Task completion could not be reported",
+			slog.String("guild_id", r.GuildID),
+			slog.String("user_id", r.UserID),
+			slog.String("task", c.Type),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// TaskCompletionEmbed summarizes how a task ended.
+func TaskCompletionEmbed(c task.Completion) discord.Embed {
+	embed := discord.Embed{
+		Title:       "Task completed",
+		Description: c.Summary,
+		Color:       discord.Color(theme.Success()),
+		Fields: []discord.EmbedField{
+			{Name: "Task", Value: "`" + c.Type + "`", Inline: true},
+			{Name: "Duration", Value: c.Duration.Round(time.Second).String(), Inline: true},
+		},
+		Timestamp: discord.NowTimestamp(),
+	}
+	if c.Err != nil {
+		embed.Title = "Task failed"
+		embed.Color = discord.Color(theme.Error())
+		embed.Fields = append(embed.Fields, discord.EmbedField{Name: "Error", Value: logging.TruncateString(c.Err.Error(), 1024)})
+	}
+	if c.Attempts > 1 {
+		embed.Fields = append(embed.Fields, discord.EmbedField{Name: "Attempts", Value: fmt.Sprint(c.Attempts), Inline: true})
+	}
+	if embed.Description == "" && c.Err == nil {
+		embed.Description = "The task finished without reporting a summary."
+	}
+	return embed
+}
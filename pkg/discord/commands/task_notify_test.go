@@ -0,0 +1,75 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands"
+	"github.com/small-frappuccino/discordcore/pkg/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNotifyClient struct {
+	followUpErr error
+	followUps   []api.InteractionResponseData
+	sent        map[discord.ChannelID][]api.SendMessageData
+}
+
+func (f *fakeNotifyClient) FollowUpInteraction(_ discord.AppID, _ string, data api.InteractionResponseData) (*discord.Message, error) {
+	if f.followUpErr != nil {
+		return nil, f.followUpErr
+	}
+	f.followUps = append(f.followUps, data)
+	return &discord.Message{}, nil
+}
+
+func (f *fakeNotifyClient) SendMessageComplex(channelID discord.ChannelID, data api.SendMessageData) (*discord.Message, error) {
+	if f.sent == nil {
+		f.sent = make(map[discord.ChannelID][]api.SendMessageData)
+	}
+	f.sent[channelID] = append(f.sent[channelID], data)
+	return &discord.Message{}, nil
+}
+
+func TestTaskNotifier_Notify(t *testing.T) {
+	t.Parallel()
+
+	ctx, err := commands.NewArikawaContext(discord.InteractionEvent{
+		AppID:     10,
+		GuildID:   20,
+		ChannelID: 30,
+		Token:     "token",
+		User:      &discord.User{ID: 40},
+	}, nil)
+	require.NoError(t, err)
+	requester := ctx.Requester()
+	assert.Equal(t, task.Requester{GuildID: "20", ChannelID: "30", UserID: "40", ApplicationID: "10", InteractionToken: "token"}, requester)
+
+	client := &fakeNotifyClient{}
+	notifier := commands.NewTaskNotifier(client, nil)
+
+	notifier.Notify(context.Background(), task.Completion{Type: "export", Requester: requester, Summary: "exported 3 cases", Duration: time.Minute})
+	require.Len(t, client.followUps, 1)
+	embed := (*client.followUps[0].Embeds)[0]
+	assert.Equal(t, "Task completed", embed.Title)
+	assert.Equal(t, "exported 3 cases", embed.Description)
+	assert.Equal(t, discord.EphemeralMessage, client.followUps[0].Flags)
+
+	// Past the interaction window the requester is mentioned in the channel.
+	notifier.Notify(context.Background(), task.Completion{Type: "export", Requester: requester, Err: errors.New("boom"), Attempts: 3, Duration: time.Hour})
+	require.Len(t, client.sent[30], 1)
+	msg := client.sent[30][0]
+	assert.Equal(t, "<@40>", msg.Content)
+	assert.Equal(t, "Task failed", msg.Embeds[0].Title)
+	assert.True(t, strings.Contains(msg.Embeds[0].Fields[2].Value, "boom"))
+
+	client.followUpErr = errors.New("unknown webhook")
+	notifier.Notify(context.Background(), task.Completion{Type: "export", Requester: requester})
+	assert.Len(t, client.sent[30], 2, "a failed follow-up should fall back to the channel")
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/diamondburned/arikawa/v3/state"
 	"github.com/diamondburned/arikawa/v3/utils/json/option"
 	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
 	discordtickets "github.com/small-frappuccino/discordcore/pkg/discord/tickets"
 	pkgtickets "github.com/small-frappuccino/discordcore/pkg/tickets"
 )
@@ -34,7 +35,7 @@ func NewTicketRouter(st *state.State, svc *discordtickets.Service, mgr *pkgticke
 		config: cm,
 		logger: logger,
 	}
-	st.AddHandler(r.HandleInteraction)
+	st.AddHandler(perf.GuardGatewayHandler("tickets.interaction", r.HandleInteraction))
 	return r
 }
 
@@ -3,6 +3,7 @@ package commands
 import (
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/commands/cmd"
 )
 
 // ArikawaCommand defines the strict contract for an Arikawa-native slash command.
@@ -21,6 +22,12 @@ type DefaultMemberPermissionsProvider interface {
 	DefaultMemberPermissions() discord.Permissions
 }
 
+// RegistrationScopeProvider lets a command be registered somewhere other
+// than globally, e.g. only in the development guild while it is built.
+type RegistrationScopeProvider interface {
+	RegistrationScope() cmd.RegistrationScope
+}
+
 // ComponentHandler interface for components.
 type ComponentHandler interface {
 	HandleComponent(ctx *ArikawaContext) error
@@ -0,0 +1,33 @@
+package embeds
+
+import (
+	"errors"
+	"testing"
+	"unicode/utf8"
+)
+
+// FuzzParseAndValidateDiscohookJSON feeds arbitrary Discohook JSON to the
+// validator. Anything it accepts must be within the custom embed limits and
+// survive conversion into a custom embed.
+func FuzzParseAndValidateDiscohookJSON(f *testing.F) {
+	f.Add(`{"embeds":[{"title":"Rules","description":"Be nice","color":16711680}]}`)
+	f.Add(`{"embeds":[{"fields":[{"name":"a","value":"b","inline":true}],"footer":{"text":"f"}}]}`)
+	f.Add(`{"embeds":[{"color":-1}]}`)
+	f.Add(`{"embeds":[]}`)
+	f.Add(`{"content":"no embeds"}`)
+	f.Add(`[`)
+
+	f.Fuzz(func(t *testing.T, payload string) {
+		embed, err := ParseAndValidateDiscohookJSON([]byte(payload))
+		if err != nil {
+			if !errors.Is(err, ErrEmbedJSONValidation) {
+				t.Fatalf("ParseAndValidateDiscohookJSON(%q): error %v does not wrap ErrEmbedJSONValidation", payload, err)
+			}
+			return
+		}
+		if utf8.RuneCountInString(embed.Title) > CustomEmbedTitleMaxLen || embed.Color < 0 || embed.Color > CustomEmbedColorMax || len(embed.Fields) > CustomEmbedMaxFields {
+			t.Fatalf("ParseAndValidateDiscohookJSON(%q) accepted an embed outside the limits", payload)
+		}
+		ToCustomEmbedConfig(embed, "fuzz")
+	})
+}
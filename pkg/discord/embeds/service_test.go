@@ -1,6 +1,7 @@
 package embeds
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/diamondburned/arikawa/v3/api"
@@ -119,3 +120,25 @@ func TestCustomEmbedPostingSyncer(t *testing.T) {
 		t.Fatalf("expected only msg1 to remain in custom embed postings, got %+v", updated.Postings)
 	}
 }
+
+// BenchmarkRender measures rendering an embed shaped like a log entry, the
+// conversion every logged event goes through.
+func BenchmarkRender(b *testing.B) {
+	ce := files.CustomEmbedConfig{
+		Title:       "Message Edited",
+		Description: "  A message was edited in <#123456789012345678>  ",
+		Color:       0x5865F2,
+		AuthorName:  "user#0001",
+		FooterText:  "User ID: 123456789012345678",
+		Fields: []files.CustomEmbedFieldConfig{
+			{Name: "Before", Value: strings.Repeat("old text ", 40)},
+			{Name: "After", Value: strings.Repeat("new text ", 40)},
+			{Name: "Channel", Value: "<#123456789012345678>", Inline: true},
+			{Name: "Author", Value: "<@123456789012345678>", Inline: true},
+		},
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		Render(ce)
+	}
+}
@@ -0,0 +1,174 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+)
+
+const (
+	// archiveQueueCap bounds the log events waiting to be archived while the
+	// database is slow.
+	archiveQueueCap = 1000
+	archiveTimeout  = 5 * time.Second
+
+	// maxArchiveSummary keeps a summary to a few lines of search results.
+	maxArchiveSummary = 500
+)
+
+// logRef names who and where a log event is about, for the archive.
+type logRef struct {
+	UserID    string
+	ActorID   string
+	ChannelID string
+	// ContentFields names the embed fields quoting what members wrote. They
+	// are left out of the archive of guilds whose privacy profile keeps no
+	// message content.
+	ContentFields []string
+}
+
+// logArchive writes emitted log events to its store off the event path, in
+// arrival order. Events arriving while the queue is full are dropped.
+type logArchive struct {
+	store  logging.ArchiveStore
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	pending  []logging.ArchivedEvent
+	draining bool
+	closed   bool
+	drains   sync.WaitGroup
+}
+
+func (a *logArchive) add(event logging.ArchivedEvent) {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return
+	}
+	if len(a.pending) >= archiveQueueCap {
+		a.mu.Unlock()
+		a.logger.Warn("Mitigated service degradation: Log archive queue full, dropping event",
+			slog.String("guild_id", event.GuildID),
+			slog.String("event_type", string(event.EventType)),
+		)
+		return
+	}
+	a.pending = append(a.pending, event)
+	start := !a.draining
+	a.draining = true
+	if start {
+		a.drains.Add(1)
+	}
+	a.mu.Unlock()
+
+	if start {
+		go a.drain()
+	}
+}
+
+// flush stops taking events and waits until the queued ones are written or
+// ctx ends.
+func (a *logArchive) flush(ctx context.Context) error {
+	a.mu.Lock()
+	a.closed = true
+	a.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		a.drains.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (a *logArchive) drain() {
+	defer a.drains.Done()
+	for {
+		a.mu.Lock()
+		if len(a.pending) == 0 {
+			a.draining = false
+			a.mu.Unlock()
+			return
+		}
+		batch := a.pending
+		a.pending = nil
+		a.mu.Unlock()
+
+		for _, event := range batch {
+			ctx, cancel := context.WithTimeout(context.Background(), archiveTimeout)
+			err := a.store.AppendLogEvent(ctx, event)
+			cancel()
+			if err != nil {
+				a.logger.Warn("Mitigated service degradation: Failed to archive log event",
+					slog.String("guild_id", event.GuildID),
+					slog.String("event_type", string(event.EventType)),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}
+}
+
+// archivedEvent summarizes embed, as sent to logChannelID, for the archive.
+// Without keepContent, the fields ref names as content are left out.
+func archivedEvent(guildID string, logChannelID discord.ChannelID, eventType logging.LogEventType, ref logRef, embed discord.Embed, keepContent bool) logging.ArchivedEvent {
+	at := time.Now()
+	if embed.Timestamp.IsValid() {
+		at = embed.Timestamp.Time()
+	}
+	var omit []string
+	if !keepContent {
+		omit = ref.ContentFields
+	}
+	return logging.ArchivedEvent{
+		At:           at,
+		GuildID:      guildID,
+		EventType:    eventType,
+		UserID:       ref.UserID,
+		ActorID:      ref.ActorID,
+		ChannelID:    ref.ChannelID,
+		LogChannelID: logChannelID.String(),
+		Title:        embed.Title,
+		Summary:      embedSummary(embed, omit),
+	}
+}
+
+// embedSummary flattens the text of embed into a few lines: the
+// description, then one line per field not named in omit.
+func embedSummary(embed discord.Embed, omit []string) string {
+	var lines []string
+	if desc := strings.TrimSpace(embed.Description); desc != "" {
+		lines = append(lines, desc)
+	}
+	for _, field := range embed.Fields {
+		if slices.Contains(omit, field.Name) {
+			continue
+		}
+		value := strings.Join(strings.Fields(field.Value), " ")
+		if value == "" {
+			continue
+		}
+		if field.Name != "" {
+			value = field.Name + ": " + value
+		}
+		lines = append(lines, value)
+	}
+	summary := strings.Join(lines, "\n")
+	if utf8.RuneCountInString(summary) > maxArchiveSummary {
+		summary = string([]rune(summary)[:maxArchiveSummary-1]) + "…"
+	}
+	return summary
+}
@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+)
+
+type fakeArchiveStore struct {
+	mu     sync.Mutex
+	events []logging.ArchivedEvent
+}
+
+func (f *fakeArchiveStore) AppendLogEvent(_ context.Context, event logging.ArchivedEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeArchiveStore) archived() []logging.ArchivedEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]logging.ArchivedEvent(nil), f.events...)
+}
+
+func TestLogArchive(t *testing.T) {
+	t.Parallel()
+	store := &fakeArchiveStore{}
+	archive := &logArchive{store: store, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	at := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	embed := discord.Embed{
+		Title:     "Message Deleted",
+		Timestamp: discord.NewTimestamp(at),
+		Fields: []discord.EmbedField{
+			{Name: "User", Value: "<@7>"},
+			{Name: "Message", Value: "hello\nthere"},
+			{Name: "Empty", Value: " "},
+		},
+	}
+	archive.add(archivedEvent("1", 50, logging.LogEventMessageDelete, logRef{UserID: "7", ActorID: "9", ChannelID: "40"}, embed, true))
+
+	deadline := time.Now().Add(time.Second)
+	for len(store.archived()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	got := store.archived()
+	if len(got) != 1 {
+		t.Fatalf("expected the event to be archived, got %d", len(got))
+	}
+	want := logging.ArchivedEvent{
+		At: at, GuildID: "1", EventType: logging.LogEventMessageDelete,
+		UserID: "7", ActorID: "9", ChannelID: "40", LogChannelID: "50",
+		Title: "Message Deleted", Summary: "User: <@7>\nMessage: hello there",
+	}
+	if !got[0].At.Equal(want.At) {
+		t.Fatalf("expected the embed's timestamp, got %v", got[0].At)
+	}
+	got[0].At = want.At
+	if got[0] != want {
+		t.Fatalf("unexpected archived event:\n got %+v\nwant %+v", got[0], want)
+	}
+}
+
+func TestEmbedSummary(t *testing.T) {
+	t.Parallel()
+	long := embedSummary(discord.Embed{Description: strings.Repeat("é", 2*maxArchiveSummary)}, nil)
+	if n := len([]rune(long)); n != maxArchiveSummary || !strings.HasSuffix(long, "…") {
+		t.Fatalf("expected the summary cut to %d runes, got %d", maxArchiveSummary, n)
+	}
+	if got := embedSummary(discord.Embed{Title: "Only a title"}, nil); got != "" {
+		t.Fatalf("expected no summary for an embed without text, got %q", got)
+	}
+}
+
+func TestArchivedEvent_OmitsContentWithoutCaching(t *testing.T) {
+	t.Parallel()
+	embed := discord.Embed{
+		Title: "Message Edited",
+		Fields: []discord.EmbedField{
+			{Name: "User", Value: "<@7>"},
+			{Name: "Before", Value: "secret"},
+			{Name: "After", Value: "still secret"},
+		},
+	}
+	ref := logRef{UserID: "7", ContentFields: []string{"Before", "After"}}
+
+	if got := archivedEvent("1", 50, logging.LogEventMessageEdit, ref, embed, false).Summary; got != "User: <@7>" {
+		t.Fatalf("summary without content caching = %q", got)
+	}
+	if got := archivedEvent("1", 50, logging.LogEventMessageEdit, ref, embed, true).Summary; !strings.Contains(got, "secret") {
+		t.Fatalf("summary with content caching = %q", got)
+	}
+}
+
+func TestLogArchive_FlushWritesQueuedEvents(t *testing.T) {
+	t.Parallel()
+	store := &fakeArchiveStore{}
+	archive := &logArchive{store: store, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	for i := 0; i < 10; i++ {
+		archive.add(logging.ArchivedEvent{GuildID: "1"})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := archive.flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if n := len(store.archived()); n != 10 {
+		t.Fatalf("archived %d events before flush returned, want 10", n)
+	}
+	archive.add(logging.ArchivedEvent{GuildID: "1"})
+	if n := len(store.archived()); n != 10 {
+		t.Fatalf("archived an event after flush: %d", n)
+	}
+}
@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/embeds"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+// assetTitles names each change to each kind of asset.
+var assetTitles = map[members.AssetKind]map[members.AssetAction]string{
+	members.AssetSticker: {
+		members.AssetCreated: "Sticker Uploaded",
+		members.AssetUpdated: "Sticker Updated",
+		members.AssetDeleted: "Sticker Deleted",
+	},
+	members.AssetSound: {
+		members.AssetCreated: "Sound Uploaded",
+		members.AssetUpdated: "Sound Updated",
+		members.AssetDeleted: "Sound Deleted",
+	},
+}
+
+// OnAssetChange implements members.AssetSink, logging stickers and soundboard
+// sounds with who uploaded, edited or deleted them.
+func (l *Logger) OnAssetChange(ctx context.Context, intent members.AssetIntent) {
+	decision, ok := l.checkPolicy(logging.LogEventAssetChange, intent.GuildID, logging.RouteContext{
+		Target: l.routeSubject(intent.GuildID, intent.ActorID, false, nil),
+	})
+	if !ok {
+		return
+	}
+
+	channelID, err := discord.ParseSnowflake(decision.ChannelID)
+	if err != nil {
+		return
+	}
+
+	lang := l.language(intent.GuildID, logging.LogEventAssetChange)
+	ce, ok := assetChangeEmbed(lang, intent)
+	if !ok {
+		return
+	}
+	if intent.ActorID != "" {
+		label := "Changed By"
+		switch intent.Action {
+		case members.AssetCreated:
+			label = "Uploaded By"
+		case members.AssetDeleted:
+			label = "Deleted By"
+		}
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+			Name: lang.Text(label), Value: l.userLabel(intent.GuildID, intent.ActorID, l.cachedNames(intent.GuildID, intent.ActorID, "")), Inline: true,
+		})
+	}
+
+	embed := embeds.Render(ce)
+	embed.Timestamp = discord.NowTimestamp()
+	l.sendEmbed(ctx, intent.GuildID, discord.ChannelID(channelID), embed, logging.LogEventAssetChange, logRef{ActorID: intent.ActorID})
+}
+
+// assetChangeEmbed renders intent without its actor, reporting false for
+// assets or actions it does not know.
+func assetChangeEmbed(lang logging.LogLanguage, intent members.AssetIntent) (files.CustomEmbedConfig, bool) {
+	title, ok := assetTitles[intent.Kind][intent.Action]
+	if !ok {
+		return files.CustomEmbedConfig{}, false
+	}
+	ce := files.CustomEmbedConfig{
+		Title:        lang.Text(title),
+		ThumbnailURL: intent.ImageURL,
+	}
+	switch intent.Action {
+	case members.AssetCreated:
+		ce.Color = theme.Success()
+	case members.AssetUpdated:
+		ce.Color = theme.Info()
+	case members.AssetDeleted:
+		ce.Color = theme.Danger()
+	}
+	if intent.Name != "" {
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+			Name: lang.Text("Name"), Value: logging.EscapeUserText(intent.Name), Inline: true,
+		})
+	}
+	if intent.PreviousName != "" && intent.PreviousName != intent.Name {
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+			Name: lang.Text("Previous Name"), Value: logging.EscapeUserText(intent.PreviousName), Inline: true,
+		})
+	}
+	if intent.Kind == members.AssetSticker {
+		ce.FooterText = fmt.Sprintf(lang.Text("Sticker ID: %s"), intent.AssetID)
+	} else {
+		ce.FooterText = fmt.Sprintf(lang.Text("Sound ID: %s"), intent.AssetID)
+	}
+	return ce, true
+}
@@ -8,14 +8,18 @@ import (
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/small-frappuccino/discordcore/pkg/automod"
 	"github.com/small-frappuccino/discordcore/pkg/discord/embeds"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
 	"github.com/small-frappuccino/discordcore/pkg/files"
 	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/moderation"
 	"github.com/small-frappuccino/discordcore/pkg/theme"
 )
 
 // OnAutomodBlock implements automod.Sink for logging automod actions.
 func (l *Logger) OnAutomodBlock(ctx context.Context, guildID discord.GuildID, entry *automod.ExecutionEvent) {
-	decision, ok := l.checkPolicy(logging.LogEventAutomodAction, guildID.String())
+	decision, ok := l.checkPolicy(logging.LogEventAutomodAction, guildID.String(), logging.RouteContext{
+		Target: l.routeSubject(guildID.String(), entry.UserID.String(), false, nil),
+	})
 	if !ok {
 		return
 	}
@@ -25,38 +29,46 @@ func (l *Logger) OnAutomodBlock(ctx context.Context, guildID discord.GuildID, en
 		return
 	}
 
-	desc := "Blocked content detected (AutoMod)."
+	lang := l.language(guildID.String(), logging.LogEventAutomodAction)
+	desc := lang.Text("Blocked content detected (AutoMod).")
 	if entry.RuleTriggerType != 0 {
-		desc = fmt.Sprintf("AutoMod rule **%s** triggered.", entry.RuleID.String())
+		desc = fmt.Sprintf(lang.Text("AutoMod rule **%s** triggered."), entry.RuleID.String())
 	}
 
 	ce := files.CustomEmbedConfig{
-		Title:       "AutoMod • Action Executed",
+		Title:       lang.Text("AutoMod • Action Executed"),
 		Description: desc,
 		Color:       theme.AutomodAction(),
 		Fields: []files.CustomEmbedFieldConfig{
-			{Name: "User", Value: fmt.Sprintf("<@%s>", entry.UserID.String()), Inline: true},
+			{Name: lang.Text("User"), Value: fmt.Sprintf("<@%s>", entry.UserID.String()), Inline: true},
 		},
 	}
 
 	if entry.ChannelID.IsValid() {
 		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
-			Name: "Channel", Value: fmt.Sprintf("<#%s>", entry.ChannelID.String()), Inline: true,
+			Name: lang.Text("Channel"), Value: fmt.Sprintf("<#%s>", entry.ChannelID.String()), Inline: true,
 		})
 	}
 	if entry.MatchedKeyword != "" {
 		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
-			Name: "Keyword", Value: entry.MatchedKeyword, Inline: true,
+			Name: lang.Text("Keyword"), Value: entry.MatchedKeyword, Inline: true,
 		})
 	}
 	if entry.MatchedContent != "" {
 		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
-			Name: "Matched Content", Value: logging.TruncateString(entry.MatchedContent, 1000), Inline: false,
+			Name: lang.Text("Matched Content"), Value: logging.TruncateString(entry.MatchedContent, 1000), Inline: false,
 		})
 	}
 
 	embed := embeds.Render(ce)
 	embed.Timestamp = discord.NewTimestamp(time.Now())
+	if moderation.InTestMode(ctx) {
+		discordmod.MarkTestMode(&embed)
+	}
 
-	l.sendEmbed(ctx, discord.ChannelID(channelID), embed, logging.LogEventAutomodAction)
+	ref := logRef{UserID: entry.UserID.String(), ContentFields: []string{lang.Text("Matched Content")}}
+	if entry.ChannelID.IsValid() {
+		ref.ChannelID = entry.ChannelID.String()
+	}
+	l.sendEmbed(ctx, guildID.String(), discord.ChannelID(channelID), embed, logging.LogEventAutomodAction, ref)
 }
@@ -10,42 +10,70 @@ import (
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
 	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/small-frappuccino/discordcore/pkg/discord/cache"
 	"github.com/small-frappuccino/discordcore/pkg/discord/embeds"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
 	"github.com/small-frappuccino/discordcore/pkg/files"
 	"github.com/small-frappuccino/discordcore/pkg/logging"
 	"github.com/small-frappuccino/discordcore/pkg/members"
 	"github.com/small-frappuccino/discordcore/pkg/messages"
+	"github.com/small-frappuccino/discordcore/pkg/moderation"
 	"github.com/small-frappuccino/discordcore/pkg/theme"
 )
 
 // Logger implements the various EventSinks to handle logging natively via Arikawa,
 // decoupling embed creation from domain packages and reducing GC heap allocations.
 type Logger struct {
-	client  *api.Client
+	sender  *NotificationSender
 	config  *files.ConfigManager
 	state   *state.State
 	intents gateway.Intents
 	logger  *slog.Logger
+	threads *userThreads
+	archive *logArchive
 }
 
 // NewLogger creates a new event logger instance.
 func NewLogger(client *api.Client, config *files.ConfigManager, st *state.State, intents gateway.Intents, logger *slog.Logger) *Logger {
-	return &Logger{
-		client:  client,
+	l := &Logger{
+		sender:  NewNotificationSender(client, config, logger),
 		config:  config,
 		state:   st,
 		intents: intents,
 		logger:  logger,
 	}
+	if st != nil {
+		l.threads = &userThreads{client: st}
+	}
+	return l
+}
+
+// SetUnifiedCache sets the cache member log threads are kept in.
+func (l *Logger) SetUnifiedCache(uc *cache.UnifiedCache) {
+	if l.threads != nil {
+		l.threads.setCache(uc)
+	}
+}
+
+// SetArchive sets the store every emitted log event is archived in, where
+// /logs search finds it.
+func (l *Logger) SetArchive(store logging.ArchiveStore) {
+	if store == nil {
+		l.archive = nil
+		return
+	}
+	l.archive = &logArchive{store: store, logger: l.logger}
 }
 
-// checkPolicy evaluates whether the event should be logged.
-func (l *Logger) checkPolicy(eventType logging.LogEventType, guildID string) (logging.EmitDecision, bool) {
+// checkPolicy evaluates whether the event should be logged and applies the
+// guild's notification routes to the resolved channel.
+func (l *Logger) checkPolicy(eventType logging.LogEventType, guildID string, route logging.RouteContext) (logging.EmitDecision, bool) {
 	decision := logging.CheckFeatureEnabled(l.config, eventType, guildID)
 	if !decision.Enabled {
 		l.logger.Debug("Log event suppressed by configuration policy", slog.String("event_type", string(eventType)), slog.String("guild_id", guildID), slog.String("reason", string(decision.Reason)))
 		return decision, false
 	}
+	decision = logging.ApplyNotificationRoutes(decision, l.config.GuildConfig(guildID), route)
 
 	reason, mask, ok := logging.ValidateLogCapability(&arikawaDiscordAdapter{st: l.state}, uint64(l.intents), decision, guildID, l.config)
 	if !ok {
@@ -64,23 +92,154 @@ func (l *Logger) checkPolicy(eventType logging.LogEventType, guildID string) (lo
 	return decision, true
 }
 
-// sendEmbed safely sends a logging embed using Arikawa API.
-func (l *Logger) sendEmbed(ctx context.Context, channelID discord.ChannelID, embed discord.Embed, eventType logging.LogEventType) {
-	_, err := l.client.WithContext(ctx).SendMessageComplex(channelID, api.SendMessageData{
-		Embeds: []discord.Embed{embed},
-	})
+// routeSubject describes userID for notification routing. Roles and the bot
+// flag are completed from the state cabinet when the member is cached.
+func (l *Logger) routeSubject(guildID, userID string, isBot bool, roleIDs []string) *logging.RouteSubject {
+	if userID == "" {
+		return nil
+	}
+	subject := &logging.RouteSubject{UserID: userID, IsBot: isBot, RoleIDs: roleIDs}
+	if l.state == nil {
+		return subject
+	}
+	guildSF, err := discord.ParseSnowflake(guildID)
+	if err != nil {
+		return subject
+	}
+	userSF, err := discord.ParseSnowflake(userID)
 	if err != nil {
-		l.logger.Error("Failed to send event log embed",
-			slog.String("event_type", string(eventType)),
-			slog.Int64("channel_id", int64(channelID)),
-			slog.Any("error", err),
+		return subject
+	}
+	member, err := l.state.Cabinet.Member(discord.GuildID(guildSF), discord.UserID(userSF))
+	if err != nil || member == nil {
+		return subject
+	}
+	subject.IsBot = subject.IsBot || member.User.Bot
+	if subject.RoleIDs == nil {
+		subject.RoleIDs = make([]string, len(member.RoleIDs))
+		for i, roleID := range member.RoleIDs {
+			subject.RoleIDs[i] = roleID.String()
+		}
+	}
+	return subject
+}
+
+// userLabel renders a user label with the name the guild's display name
+// preference selects.
+func (l *Logger) userLabel(guildID, userID string, names logging.UserNames) string {
+	var style logging.DisplayNameStyle
+	if l.config != nil {
+		if gcfg := l.config.GuildConfig(guildID); gcfg != nil {
+			style = logging.ParseDisplayNameStyle(gcfg.DisplayNameStyle)
+		}
+	}
+	return logging.FormatUserLabel(logging.ResolveDisplayName(names, style), userID)
+}
+
+// language returns the language the guild wants eventType logged in.
+func (l *Logger) language(guildID string, eventType logging.LogEventType) logging.LogLanguage {
+	if l.config == nil {
+		return logging.LogLanguageEnglish
+	}
+	return logging.ResolveLogLanguage(eventType, l.config.GuildConfig(guildID))
+}
+
+// cachedNames completes names from the member cache. Message records only
+// keep the author's username, so nicknames and global names come from state.
+func (l *Logger) cachedNames(guildID, userID, username string) logging.UserNames {
+	names := logging.UserNames{Username: username}
+	if l.state == nil {
+		return names
+	}
+	guildSF, err := discord.ParseSnowflake(guildID)
+	if err != nil {
+		return names
+	}
+	userSF, err := discord.ParseSnowflake(userID)
+	if err != nil {
+		return names
+	}
+	member, err := l.state.Cabinet.Member(discord.GuildID(guildSF), discord.UserID(userSF))
+	if err != nil || member == nil {
+		return names
+	}
+	if member.User.Username != "" {
+		names.Username = member.User.Username
+	}
+	names.Discriminator = member.User.Discriminator
+	names.GlobalName = member.User.DisplayName
+	names.Nick = member.Nick
+	return names
+}
+
+// memberChannel returns where a log about userID goes: channelID itself, or
+// the member's thread under it when the guild routes member logs into
+// threads. It falls back to channelID when the thread cannot be opened.
+func (l *Logger) memberChannel(guildID string, channelID discord.ChannelID, eventType logging.LogEventType, userID string, names logging.UserNames) discord.ChannelID {
+	if l.threads == nil || userID == "" || !userThreadEvents[eventType] {
+		return channelID
+	}
+	if gcfg := l.config.GuildConfig(guildID); gcfg == nil || !gcfg.UserLogThreads {
+		return channelID
+	}
+	guildSF, err := discord.ParseSnowflake(guildID)
+	if err != nil {
+		return channelID
+	}
+	thread, err := l.threads.thread(discord.GuildID(guildSF), channelID, userID, logging.ResolveDisplayName(names, logging.DisplayNameUsername))
+	if err != nil {
+		l.logger.Warn("Mitigated service degradation: Member log thread could not be opened",
+			slog.String("guild_id", guildID),
+			slog.String("channel_id", channelID.String()),
+			slog.String("user_id", userID),
+			slog.String("error", err.Error()),
 		)
+		return channelID
 	}
+	return thread
+}
+
+// sendEmbed queues a logging embed on the notification sender, which paces
+// and coalesces deliveries per channel and reports failures itself, and
+// archives a summary of it described by ref.
+func (l *Logger) sendEmbed(_ context.Context, guildID string, channelID discord.ChannelID, embed discord.Embed, eventType logging.LogEventType, ref logRef) {
+	l.sender.Enqueue(guildID, channelID, eventType, embed)
+	l.archiveEmbed(guildID, channelID, embed, eventType, ref)
+}
+
+// archiveEmbed archives a summary of embed, posted to channelID, described
+// by ref.
+func (l *Logger) archiveEmbed(guildID string, channelID discord.ChannelID, embed discord.Embed, eventType logging.LogEventType, ref logRef) {
+	if l.archive == nil {
+		return
+	}
+	keepContent := l.config.GuildPrivacy(guildID).CacheMessageContent
+	l.archive.add(archivedEvent(guildID, channelID, eventType, ref, embed, keepContent))
+}
+
+// Close writes out the log events still queued for the archive, waiting at
+// most until ctx ends. Events logged afterwards are not archived.
+func (l *Logger) Close(ctx context.Context) error {
+	if l.archive == nil {
+		return nil
+	}
+	if err := l.archive.flush(ctx); err != nil {
+		return fmt.Errorf("Logger.Close: %w", err)
+	}
+	return nil
+}
+
+// DeliveryStats returns the delivery counters of the logger's notification
+// sender.
+func (l *Logger) DeliveryStats() NotificationSenderStats {
+	return l.sender.Stats()
 }
 
 // OnMemberJoin handles member join events.
 func (l *Logger) OnMemberJoin(ctx context.Context, intent members.MemberJoinIntent, accountAge time.Duration) {
-	decision, ok := l.checkPolicy(logging.LogEventMemberJoin, intent.GuildID)
+	decision, ok := l.checkPolicy(logging.LogEventMemberJoin, intent.GuildID, logging.RouteContext{
+		Target: l.routeSubject(intent.GuildID, intent.UserID, intent.Bot, intent.RoleIDs),
+	})
 	if !ok {
 		return
 	}
@@ -91,21 +250,21 @@ func (l *Logger) OnMemberJoin(ctx context.Context, intent members.MemberJoinInte
 		return
 	}
 
+	lang := l.language(intent.GuildID, logging.LogEventMemberJoin)
 	joinAgeText := logging.FormatDurationSmart(accountAge)
 	if joinAgeText == "" {
-		joinAgeText = "- ago"
-	} else {
-		joinAgeText = joinAgeText + " ago"
+		joinAgeText = "-"
 	}
+	joinAgeText = fmt.Sprintf(lang.Text("%s ago"), joinAgeText)
 
 	ce := files.CustomEmbedConfig{
-		Title:        "Member Joined",
-		Description:  logging.FormatUserLabel(intent.Username, intent.UserID),
+		Title:        lang.Text("Member Joined"),
+		Description:  l.userLabel(intent.GuildID, intent.UserID, intent.Names()),
 		Color:        theme.MemberJoin(),
 		ThumbnailURL: logging.FormatAvatarURL(intent.UserID, intent.AvatarHash),
 		Fields: []files.CustomEmbedFieldConfig{
 			{
-				Name:   "Account Created",
+				Name:   lang.Text("Account Created"),
 				Value:  joinAgeText,
 				Inline: true,
 			},
@@ -113,12 +272,14 @@ func (l *Logger) OnMemberJoin(ctx context.Context, intent members.MemberJoinInte
 	}
 	embed := embeds.Render(ce)
 	embed.Timestamp = discord.NowTimestamp()
-	l.sendEmbed(ctx, discord.ChannelID(channelID), embed, logging.LogEventMemberJoin)
+	l.sendEmbed(ctx, intent.GuildID, discord.ChannelID(channelID), embed, logging.LogEventMemberJoin, logRef{UserID: intent.UserID})
 }
 
 // OnMemberLeave handles member leave events.
 func (l *Logger) OnMemberLeave(ctx context.Context, intent members.MemberLeaveIntent, serverTime time.Duration, botTime time.Duration) {
-	decision, ok := l.checkPolicy(logging.LogEventMemberLeave, intent.GuildID)
+	decision, ok := l.checkPolicy(logging.LogEventMemberLeave, intent.GuildID, logging.RouteContext{
+		Target: l.routeSubject(intent.GuildID, intent.UserID, intent.Bot, nil),
+	})
 	if !ok {
 		return
 	}
@@ -128,14 +289,15 @@ func (l *Logger) OnMemberLeave(ctx context.Context, intent members.MemberLeaveIn
 		return
 	}
 
+	lang := l.language(intent.GuildID, logging.LogEventMemberLeave)
 	ce := files.CustomEmbedConfig{
-		Title:        "Member Left",
-		Description:  logging.FormatUserLabel(intent.Username, intent.UserID),
+		Title:        lang.Text("Member Left"),
+		Description:  l.userLabel(intent.GuildID, intent.UserID, intent.Names()),
 		Color:        theme.MemberLeave(),
 		ThumbnailURL: logging.FormatAvatarURL(intent.UserID, intent.AvatarHash),
 		Fields: []files.CustomEmbedFieldConfig{
 			{
-				Name:   "Time on Server",
+				Name:   lang.Text("Time on Server"),
 				Value:  "N/A", // This could be enriched by passing joinedAt from the domain event
 				Inline: true,
 			},
@@ -143,7 +305,7 @@ func (l *Logger) OnMemberLeave(ctx context.Context, intent members.MemberLeaveIn
 	}
 	embed := embeds.Render(ce)
 	embed.Timestamp = discord.NowTimestamp()
-	l.sendEmbed(ctx, discord.ChannelID(channelID), embed, logging.LogEventMemberLeave)
+	l.sendEmbed(ctx, intent.GuildID, discord.ChannelID(channelID), embed, logging.LogEventMemberLeave, logRef{UserID: intent.UserID})
 }
 
 // OnRoleUpdate handles role updates for a member.
@@ -152,7 +314,9 @@ func (l *Logger) OnRoleUpdate(ctx context.Context, intent members.RoleUpdateInte
 		return
 	}
 
-	decision, ok := l.checkPolicy(logging.LogEventRoleChange, intent.GuildID)
+	decision, ok := l.checkPolicy(logging.LogEventRoleChange, intent.GuildID, logging.RouteContext{
+		Target: l.routeSubject(intent.GuildID, intent.UserID, intent.Bot, nil),
+	})
 	if !ok {
 		return
 	}
@@ -162,9 +326,10 @@ func (l *Logger) OnRoleUpdate(ctx context.Context, intent members.RoleUpdateInte
 		return
 	}
 
-	targetLabel := logging.FormatUserLabel(intent.Username, intent.UserID)
+	lang := l.language(intent.GuildID, logging.LogEventRoleChange)
+	targetLabel := l.userLabel(intent.GuildID, intent.UserID, intent.Names())
 	ce := files.CustomEmbedConfig{
-		Title:       "Role Updated",
+		Title:       lang.Text("Role Updated"),
 		Description: targetLabel,
 		Color:       theme.MemberRoleUpdate(),
 	}
@@ -172,25 +337,25 @@ func (l *Logger) OnRoleUpdate(ctx context.Context, intent members.RoleUpdateInte
 	var fields []files.CustomEmbedFieldConfig
 	for _, r := range intent.AddedRoles {
 		fields = append(fields, files.CustomEmbedFieldConfig{
-			Name:   "Role",
+			Name:   lang.Text("Role"),
 			Value:  logging.FormatRoleLabel(r, ""),
 			Inline: true,
 		})
 		fields = append(fields, files.CustomEmbedFieldConfig{
-			Name:   "Action",
-			Value:  "Added",
+			Name:   lang.Text("Action"),
+			Value:  lang.Text("Added"),
 			Inline: true,
 		})
 	}
 	for _, r := range intent.RemovedRoles {
 		fields = append(fields, files.CustomEmbedFieldConfig{
-			Name:   "Role",
+			Name:   lang.Text("Role"),
 			Value:  logging.FormatRoleLabel(r, ""),
 			Inline: true,
 		})
 		fields = append(fields, files.CustomEmbedFieldConfig{
-			Name:   "Action",
-			Value:  "Removed",
+			Name:   lang.Text("Action"),
+			Value:  lang.Text("Removed"),
 			Inline: true,
 		})
 	}
@@ -198,7 +363,8 @@ func (l *Logger) OnRoleUpdate(ctx context.Context, intent members.RoleUpdateInte
 	ce.Fields = fields
 	embed := embeds.Render(ce)
 	embed.Timestamp = discord.NowTimestamp()
-	l.sendEmbed(ctx, discord.ChannelID(channelID), embed, logging.LogEventRoleChange)
+	target := l.memberChannel(intent.GuildID, discord.ChannelID(channelID), logging.LogEventRoleChange, intent.UserID, intent.Names())
+	l.sendEmbed(ctx, intent.GuildID, target, embed, logging.LogEventRoleChange, logRef{UserID: intent.UserID})
 }
 
 // OnMessageUpdate handles message update events to satisfy messages.MessageSink.
@@ -211,7 +377,9 @@ func (l *Logger) OnMessageUpdate(ctx context.Context, intent messages.MessageUpd
 		return
 	}
 
-	decision, ok := l.checkPolicy(logging.LogEventMessageEdit, intent.GuildID)
+	decision, ok := l.checkPolicy(logging.LogEventMessageEdit, intent.GuildID, logging.RouteContext{
+		Target: l.routeSubject(intent.GuildID, cachedMessage.AuthorID, cachedMessage.AuthorBot, nil),
+	})
 	if !ok {
 		return
 	}
@@ -221,31 +389,36 @@ func (l *Logger) OnMessageUpdate(ctx context.Context, intent messages.MessageUpd
 		return
 	}
 
+	lang := l.language(intent.GuildID, logging.LogEventMessageEdit)
 	jumpURL := fmt.Sprintf("https://discord.com/channels/%s/%s/%s", intent.GuildID, intent.ChannelID, intent.MessageID)
-	desc := "[Jump to message](" + jumpURL + ")"
+	desc := "[" + lang.Text("Jump to message") + "](" + jumpURL + ")"
 
-	userField := logging.FormatUserLabel(cachedMessage.AuthorUsername, cachedMessage.AuthorID)
+	userField := l.userLabel(intent.GuildID, cachedMessage.AuthorID, l.cachedNames(intent.GuildID, cachedMessage.AuthorID, cachedMessage.AuthorUsername))
 	channelField := logging.FormatChannelLabel(intent.ChannelID)
-	messageTime := cachedMessage.Timestamp.Format("January 2, 2006 at 3:04 PM")
+	messageTime := logging.DiscordTimestamp(cachedMessage.Timestamp, logging.TimestampLongDateTime)
 
 	ce := files.CustomEmbedConfig{
-		Title:       "Message Edited",
+		Title:       lang.Text("Message Edited"),
 		Description: desc,
 		Color:       theme.MessageEdit(),
-		AuthorName:  "Message Edited",
+		AuthorName:  lang.Text("Message Edited"),
 		Fields: []files.CustomEmbedFieldConfig{
-			{Name: "User", Value: userField, Inline: true},
-			{Name: "Channel", Value: channelField, Inline: true},
-			{Name: "Message Timestamp", Value: messageTime, Inline: true},
-			{Name: "Before", Value: logging.TruncateString(cachedMessage.Content, 1000), Inline: false},
-			{Name: "After", Value: logging.TruncateString(intent.Content, 1000), Inline: false},
+			{Name: lang.Text("User"), Value: userField, Inline: true},
+			{Name: lang.Text("Channel"), Value: channelField, Inline: true},
+			{Name: lang.Text("Message Timestamp"), Value: messageTime, Inline: true},
+			{Name: lang.Text("Before"), Value: cachedContentField(lang, cachedMessage), Inline: false},
+			{Name: lang.Text("After"), Value: logging.TruncateString(logging.EscapeUserText(intent.Content), 1000), Inline: false},
 		},
-		FooterText: fmt.Sprintf("Message ID: %s", intent.MessageID),
+		FooterText: fmt.Sprintf(lang.Text("Message ID: %s"), intent.MessageID),
 	}
 
 	embed := embeds.Render(ce)
 	embed.Timestamp = discord.NowTimestamp()
-	l.sendEmbed(ctx, discord.ChannelID(logChannelID), embed, logging.LogEventMessageEdit)
+	l.sendEmbed(ctx, intent.GuildID, discord.ChannelID(logChannelID), embed, logging.LogEventMessageEdit, logRef{
+		UserID:        cachedMessage.AuthorID,
+		ChannelID:     intent.ChannelID,
+		ContentFields: []string{lang.Text("Before"), lang.Text("After")},
+	})
 }
 
 // OnMessageDelete handles message delete events to satisfy messages.MessageSink.
@@ -258,7 +431,10 @@ func (l *Logger) OnMessageDelete(ctx context.Context, intent messages.MessageDel
 		return
 	}
 
-	decision, ok := l.checkPolicy(logging.LogEventMessageDelete, intent.GuildID)
+	decision, ok := l.checkPolicy(logging.LogEventMessageDelete, intent.GuildID, logging.RouteContext{
+		Target: l.routeSubject(intent.GuildID, cachedMessage.AuthorID, cachedMessage.AuthorBot, nil),
+		Actor:  l.routeSubject(intent.GuildID, intent.ExecutorID, false, nil),
+	})
 	if !ok {
 		return
 	}
@@ -268,31 +444,50 @@ func (l *Logger) OnMessageDelete(ctx context.Context, intent messages.MessageDel
 		return
 	}
 
-	userField := logging.FormatUserLabel(cachedMessage.AuthorUsername, cachedMessage.AuthorID)
+	lang := l.language(intent.GuildID, logging.LogEventMessageDelete)
+	userField := l.userLabel(intent.GuildID, cachedMessage.AuthorID, l.cachedNames(intent.GuildID, cachedMessage.AuthorID, cachedMessage.AuthorUsername))
 	channelField := logging.FormatChannelLabel(intent.ChannelID)
-	messageTime := cachedMessage.Timestamp.Format("January 2, 2006 at 3:04 PM")
+	messageTime := logging.DiscordTimestamp(cachedMessage.Timestamp, logging.TimestampLongDateTime)
 
 	ce := files.CustomEmbedConfig{
-		Title:      "Message Deleted",
+		Title:      lang.Text("Message Deleted"),
 		Color:      theme.MessageDelete(),
-		AuthorName: "Message Deleted",
+		AuthorName: lang.Text("Message Deleted"),
 		Fields: []files.CustomEmbedFieldConfig{
-			{Name: "User", Value: userField, Inline: true},
-			{Name: "Channel", Value: channelField, Inline: true},
-			{Name: "Message Timestamp", Value: messageTime, Inline: true},
-			{Name: "Message", Value: logging.TruncateString(cachedMessage.Content, 1000), Inline: false},
+			{Name: lang.Text("User"), Value: userField, Inline: true},
+			{Name: lang.Text("Channel"), Value: channelField, Inline: true},
+			{Name: lang.Text("Message Timestamp"), Value: messageTime, Inline: true},
+			{Name: lang.Text("Message"), Value: cachedContentField(lang, cachedMessage), Inline: false},
 		},
-		FooterText: fmt.Sprintf("Message ID: %s", intent.MessageID),
+		FooterText: fmt.Sprintf(lang.Text("Message ID: %s"), intent.MessageID),
 	}
 
 	if intent.ExecutorID != "" {
-		ce.Description += fmt.Sprintf("\n**Deleted By:** <@%s>", intent.ExecutorID)
+		ce.Description += fmt.Sprintf("\n**%s:** <@%s>", lang.Text("Deleted By"), intent.ExecutorID)
 	}
 
 	embed := embeds.Render(ce)
 	embed.Timestamp = discord.NowTimestamp()
 
-	l.sendEmbed(ctx, discord.ChannelID(logChannelID), embed, logging.LogEventMessageDelete)
+	l.sendEmbed(ctx, intent.GuildID, discord.ChannelID(logChannelID), embed, logging.LogEventMessageDelete, logRef{
+		UserID:        cachedMessage.AuthorID,
+		ActorID:       intent.ExecutorID,
+		ChannelID:     intent.ChannelID,
+		ContentFields: []string{lang.Text("Message")},
+	})
+}
+
+// cachedContentField renders the cached text of a message. Guilds on a
+// restrictive privacy profile keep no text, so their logs only say how long
+// the message was, when that is known.
+func cachedContentField(lang logging.LogLanguage, cachedMessage *messages.CachedMessageData) string {
+	if cachedMessage.Content != "" {
+		return logging.TruncateString(logging.EscapeUserText(cachedMessage.Content), 1000)
+	}
+	if cachedMessage.ContentLength > 0 {
+		return fmt.Sprintf(lang.Text("*Content not stored (%d characters)*"), cachedMessage.ContentLength)
+	}
+	return lang.Text("*Content not stored*")
 }
 
 // OnMessageDeleteBulk handles bulk message deletions to satisfy messages.MessageSink.
@@ -305,7 +500,10 @@ func (l *Logger) OnMessageDeleteBulk(ctx context.Context, intent messages.Messag
 
 // OnModerationAction handles moderation actions (from our bot or external).
 func (l *Logger) OnModerationAction(ctx context.Context, intent members.ModerationActionIntent) {
-	decision, ok := l.checkPolicy(logging.LogEventModerationCase, intent.GuildID)
+	decision, ok := l.checkPolicy(logging.LogEventModerationCase, intent.GuildID, logging.RouteContext{
+		Target: l.routeSubject(intent.GuildID, intent.TargetUserID, intent.TargetBot, nil),
+		Actor:  l.routeSubject(intent.GuildID, intent.ModeratorID, false, nil),
+	})
 	if !ok {
 		return
 	}
@@ -315,28 +513,38 @@ func (l *Logger) OnModerationAction(ctx context.Context, intent members.Moderati
 		return
 	}
 
-	reason := intent.Reason
+	lang := l.language(intent.GuildID, logging.LogEventModerationCase)
+	reason := logging.EscapeUserText(intent.Reason)
 	if reason == "" {
-		reason = "No reason provided."
+		reason = lang.Text("No reason provided.")
 	}
 
 	ce := files.CustomEmbedConfig{
-		Title: fmt.Sprintf("Moderation Action: %s", intent.ActionType),
+		Title: fmt.Sprintf(lang.Text("Moderation Action: %s"), intent.ActionType),
 		Color: theme.Danger(),
-		Description: fmt.Sprintf("**Target:** %s\n**Moderator:** %s\n**Reason:** %s",
-			logging.FormatUserRef(intent.TargetUserID),
-			logging.FormatUserRef(intent.ModeratorID),
-			reason),
-		FooterText: fmt.Sprintf("Target ID: %s", intent.TargetUserID),
+		Description: fmt.Sprintf("**%s:** %s\n**%s:** %s\n**%s:** %s",
+			lang.Text("Target"), logging.FormatUserRef(intent.TargetUserID),
+			lang.Text("Moderator"), logging.FormatUserRef(intent.ModeratorID),
+			lang.Text("Reason"), reason),
+		FooterText: fmt.Sprintf(lang.Text("Target ID: %s"), intent.TargetUserID),
 	}
 	embed := embeds.Render(ce)
 	embed.Timestamp = discord.NowTimestamp()
-	l.sendEmbed(ctx, discord.ChannelID(logChannelID), embed, logging.LogEventModerationCase)
+	if moderation.InTestMode(ctx) {
+		discordmod.MarkTestMode(&embed)
+	}
+	target := l.memberChannel(intent.GuildID, discord.ChannelID(logChannelID), logging.LogEventModerationCase, intent.TargetUserID, l.cachedNames(intent.GuildID, intent.TargetUserID, ""))
+	l.sendEmbed(ctx, intent.GuildID, target, embed, logging.LogEventModerationCase, logRef{
+		UserID:  intent.TargetUserID,
+		ActorID: intent.ModeratorID,
+	})
 }
 
 // OnAvatarUpdate handles user avatar change events.
 func (l *Logger) OnAvatarUpdate(ctx context.Context, intent members.AvatarUpdateIntent) {
-	decision, ok := l.checkPolicy(logging.LogEventAvatarChange, intent.GuildID)
+	decision, ok := l.checkPolicy(logging.LogEventAvatarChange, intent.GuildID, logging.RouteContext{
+		Target: l.routeSubject(intent.GuildID, intent.UserID, intent.Bot, nil),
+	})
 	if !ok {
 		return
 	}
@@ -346,20 +554,21 @@ func (l *Logger) OnAvatarUpdate(ctx context.Context, intent members.AvatarUpdate
 		return
 	}
 
+	lang := l.language(intent.GuildID, logging.LogEventAvatarChange)
 	ce := files.CustomEmbedConfig{
-		Title:        "Avatar Updated",
+		Title:        lang.Text("Avatar Updated"),
 		Color:        theme.AvatarChange(),
 		ThumbnailURL: logging.FormatAvatarURL(intent.UserID, intent.NewAvatarHash),
 		Fields: []files.CustomEmbedFieldConfig{
-			{Name: "User", Value: logging.FormatUserLabel(intent.Username, intent.UserID), Inline: true},
+			{Name: lang.Text("User"), Value: l.userLabel(intent.GuildID, intent.UserID, intent.Names()), Inline: true},
 		},
-		FooterText: fmt.Sprintf("User ID: %s", intent.UserID),
+		FooterText: fmt.Sprintf(lang.Text("User ID: %s"), intent.UserID),
 	}
 
 	if intent.OldAvatarHash != "" {
 		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
-			Name:   "Previous Avatar",
-			Value:  "[See previous avatar](" + logging.FormatAvatarURL(intent.UserID, intent.OldAvatarHash) + ")",
+			Name:   lang.Text("Previous Avatar"),
+			Value:  "[" + lang.Text("See previous avatar") + "](" + logging.FormatAvatarURL(intent.UserID, intent.OldAvatarHash) + ")",
 			Inline: true,
 		})
 	}
@@ -367,5 +576,58 @@ func (l *Logger) OnAvatarUpdate(ctx context.Context, intent members.AvatarUpdate
 	embed := embeds.Render(ce)
 	embed.Timestamp = discord.NowTimestamp()
 
-	l.sendEmbed(ctx, discord.ChannelID(logChannelID), embed, logging.LogEventAvatarChange)
+	target := l.memberChannel(intent.GuildID, discord.ChannelID(logChannelID), logging.LogEventAvatarChange, intent.UserID, intent.Names())
+	l.sendEmbed(ctx, intent.GuildID, target, embed, logging.LogEventAvatarChange, logRef{UserID: intent.UserID})
+}
+
+// OnNameUpdate handles username, global name and nickname changes.
+func (l *Logger) OnNameUpdate(ctx context.Context, intent members.NameUpdateIntent) {
+	decision, ok := l.checkPolicy(logging.LogEventNameChange, intent.GuildID, logging.RouteContext{
+		Target: l.routeSubject(intent.GuildID, intent.UserID, intent.Bot, nil),
+	})
+	if !ok {
+		return
+	}
+
+	logChannelID, err := discord.ParseSnowflake(decision.ChannelID)
+	if err != nil {
+		return
+	}
+
+	lang := l.language(intent.GuildID, logging.LogEventNameChange)
+	ce := files.CustomEmbedConfig{
+		Title: lang.Text("Name changed"),
+		Color: theme.AvatarChange(),
+		Fields: []files.CustomEmbedFieldConfig{
+			{Name: lang.Text("User"), Value: l.userLabel(intent.GuildID, intent.UserID, intent.Names())},
+		},
+		FooterText: fmt.Sprintf(lang.Text("User ID: %s"), intent.UserID),
+	}
+	for _, change := range []struct{ name, old, new string }{
+		{"Username", intent.Old.Username, intent.New.Username},
+		{"Display name", intent.Old.GlobalName, intent.New.GlobalName},
+		{"Nickname", intent.Old.Nick, intent.New.Nick},
+	} {
+		if change.old == change.new {
+			continue
+		}
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+			Name:  lang.Text(change.name),
+			Value: nameChangeValue(lang, change.old) + " → " + nameChangeValue(lang, change.new),
+		})
+	}
+
+	embed := embeds.Render(ce)
+	embed.Timestamp = discord.NowTimestamp()
+
+	target := l.memberChannel(intent.GuildID, discord.ChannelID(logChannelID), logging.LogEventNameChange, intent.UserID, intent.Names())
+	l.sendEmbed(ctx, intent.GuildID, target, embed, logging.LogEventNameChange, logRef{UserID: intent.UserID})
+}
+
+// nameChangeValue shows one side of a name change.
+func nameChangeValue(lang logging.LogLanguage, name string) string {
+	if name == "" {
+		return lang.Text("*(none)*")
+	}
+	return logging.InlineCode(name)
 }
@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/embeds"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+// OnPresenceChange implements members.PresenceSink, logging a watched
+// member's custom status and activities next to what was logged before.
+func (l *Logger) OnPresenceChange(ctx context.Context, intent members.PresenceIntent) {
+	decision, ok := l.checkPolicy(logging.LogEventPresenceChange, intent.GuildID, logging.RouteContext{
+		Target: l.routeSubject(intent.GuildID, intent.UserID, intent.Bot, nil),
+	})
+	if !ok {
+		return
+	}
+
+	channelID, err := discord.ParseSnowflake(decision.ChannelID)
+	if err != nil {
+		return
+	}
+
+	lang := l.language(intent.GuildID, logging.LogEventPresenceChange)
+	ce, ok := presenceChangeEmbed(lang, intent)
+	if !ok {
+		return
+	}
+	ce.Fields = append([]files.CustomEmbedFieldConfig{{
+		Name: lang.Text("User"), Value: l.userLabel(intent.GuildID, intent.UserID, l.cachedNames(intent.GuildID, intent.UserID, intent.Username)),
+	}}, ce.Fields...)
+
+	embed := embeds.Render(ce)
+	embed.Timestamp = discord.NowTimestamp()
+	l.sendEmbed(ctx, intent.GuildID, discord.ChannelID(channelID), embed, logging.LogEventPresenceChange, logRef{UserID: intent.UserID})
+}
+
+// presenceChangeEmbed renders what changed in intent without the member,
+// reporting false when neither the custom status nor the activities did.
+func presenceChangeEmbed(lang logging.LogLanguage, intent members.PresenceIntent) (files.CustomEmbedConfig, bool) {
+	ce := files.CustomEmbedConfig{
+		Title:      lang.Text("Presence Updated"),
+		Color:      theme.Info(),
+		FooterText: fmt.Sprintf(lang.Text("User ID: %s"), intent.UserID),
+	}
+	if intent.CustomStatus != intent.PreviousCustomStatus {
+		status := func(s string) string {
+			if s == "" {
+				return lang.Text("None")
+			}
+			return logging.EscapeUserText(s)
+		}
+		ce.Fields = append(ce.Fields,
+			files.CustomEmbedFieldConfig{Name: lang.Text("Custom Status"), Value: status(intent.CustomStatus), Inline: true},
+			files.CustomEmbedFieldConfig{Name: lang.Text("Previous Custom Status"), Value: status(intent.PreviousCustomStatus), Inline: true},
+		)
+	}
+	if !slices.Equal(intent.Activities, intent.PreviousActivities) {
+		ce.Fields = append(ce.Fields,
+			files.CustomEmbedFieldConfig{Name: lang.Text("Activities"), Value: activityList(lang, intent.Activities), Inline: true},
+			files.CustomEmbedFieldConfig{Name: lang.Text("Previous Activities"), Value: activityList(lang, intent.PreviousActivities), Inline: true},
+		)
+	}
+	return ce, len(ce.Fields) > 0
+}
+
+func activityList(lang logging.LogLanguage, activities []members.PresenceActivity) string {
+	if len(activities) == 0 {
+		return lang.Text("None")
+	}
+	lines := make([]string, len(activities))
+	for i, activity := range activities {
+		lines[i] = lang.Text(activity.Verb) + " **" + logging.EscapeUserText(activity.Name) + "**"
+	}
+	return strings.Join(lines, "\n")
+}
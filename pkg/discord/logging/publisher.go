@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+	discordmod "github.com/small-frappuccino/discordcore/pkg/discord/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+// LogPost is a log posted on behalf of code outside the Logger, such as a
+// moderation case, an audit record or an automod flag.
+type LogPost struct {
+	GuildID   string
+	ChannelID discord.ChannelID
+	EventType logging.LogEventType
+	// UserID names the member the log is about. Member events about them
+	// land in their log thread when the guild keeps one per member.
+	UserID string
+	// ActorID names who acted, for the archive.
+	ActorID string
+	Embed   discord.Embed
+	// Files are attached to published posts. Queued posts carry none.
+	Files []sendpart.File
+}
+
+func (p LogPost) ref() logRef {
+	return logRef{UserID: p.UserID, ActorID: p.ActorID}
+}
+
+// Publisher posts logs with the guild's logging policies applied like the
+// Logger's own events: the mention policy of the event type, member log
+// threads, the test mode banner and the archive. *Logger satisfies it.
+type Publisher interface {
+	// Notify queues post on the notification sender, which paces it per
+	// channel, delivers it through the guild's log webhooks and reports
+	// failures itself.
+	Notify(ctx context.Context, post LogPost)
+	// Publish sends post right away as the bot and returns the message,
+	// for logs the bot edits later, which it could not do to a webhook's
+	// message, and for posts the caller has to know were delivered.
+	Publish(ctx context.Context, post LogPost) (*discord.Message, error)
+}
+
+var _ Publisher = (*Logger)(nil)
+
+type publisherKey struct{}
+
+// WithPublisher returns a copy of ctx carrying pub, so code handling an
+// interaction posts its logs through the runtime that received it.
+func WithPublisher(ctx context.Context, pub Publisher) context.Context {
+	return context.WithValue(ctx, publisherKey{}, pub)
+}
+
+// PublisherFrom returns the Publisher ctx carries, or nil.
+func PublisherFrom(ctx context.Context) Publisher {
+	pub, _ := ctx.Value(publisherKey{}).(Publisher)
+	return pub
+}
+
+// Notify implements Publisher.
+func (l *Logger) Notify(ctx context.Context, post LogPost) {
+	channelID, embed := l.prepare(ctx, post)
+	l.sendEmbed(ctx, post.GuildID, channelID, embed, post.EventType, post.ref())
+}
+
+// Publish implements Publisher.
+func (l *Logger) Publish(ctx context.Context, post LogPost) (*discord.Message, error) {
+	channelID, embed := l.prepare(ctx, post)
+	msg, err := l.sender.Post(ctx, post.GuildID, channelID, post.EventType, api.SendMessageData{
+		Embeds: []discord.Embed{embed},
+		Files:  post.Files,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Logger.Publish: %w", err)
+	}
+	l.archiveEmbed(post.GuildID, channelID, embed, post.EventType, post.ref())
+	return msg, nil
+}
+
+// prepare returns where post goes, the member's thread when the guild keeps
+// one, and its embed, marked when ctx is in test mode.
+func (l *Logger) prepare(ctx context.Context, post LogPost) (discord.ChannelID, discord.Embed) {
+	embed := post.Embed
+	if moderation.InTestMode(ctx) {
+		discordmod.MarkTestMode(&embed)
+	}
+	channelID := post.ChannelID
+	if post.UserID != "" {
+		channelID = l.memberChannel(post.GuildID, channelID, post.EventType, post.UserID, l.cachedNames(post.GuildID, post.UserID, ""))
+	}
+	return channelID, embed
+}
@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+func TestLoggerPublishAppliesLogPolicies(t *testing.T) {
+	t.Parallel()
+
+	l := NewLogger(nil, nil, nil, 0, slog.Default())
+	var sent []api.SendMessageData
+	l.sender.post = func(_ context.Context, channelID discord.ChannelID, data api.SendMessageData) (*discord.Message, error) {
+		sent = append(sent, data)
+		return &discord.Message{ID: 7, ChannelID: channelID}, nil
+	}
+
+	ctx := WithPublisher(moderation.WithTestMode(context.Background()), l)
+	msg, err := PublisherFrom(ctx).Publish(ctx, LogPost{
+		GuildID:   "1",
+		ChannelID: 42,
+		EventType: logging.LogEventModerationCase,
+		UserID:    "3",
+		Embed:     discord.Embed{Title: "Case", Description: "<@3> banned"},
+	})
+	if err != nil || msg == nil || msg.ID != 7 || msg.ChannelID != 42 {
+		t.Fatalf("expected the posted message, got %+v, %v", msg, err)
+	}
+	if len(sent) != 1 || len(sent[0].Embeds) != 1 {
+		t.Fatalf("expected one message with one embed, got %+v", sent)
+	}
+	if embed := sent[0].Embeds[0]; !strings.HasPrefix(embed.Title, "[TEST] ") || !strings.Contains(embed.Description, moderation.TestModeBanner) {
+		t.Fatalf("expected the test mode banner, got %+v", embed)
+	}
+	if mentions := sent[0].AllowedMentions; mentions == nil || mentions.Parse == nil || len(mentions.Parse) != 0 {
+		t.Fatalf("expected every ping suppressed, got %+v", mentions)
+	}
+}
+
+func TestPublisherFromWithoutPublisher(t *testing.T) {
+	t.Parallel()
+	if pub := PublisherFrom(context.Background()); pub != nil {
+		t.Fatalf("expected no publisher, got %T", pub)
+	}
+}
@@ -0,0 +1,334 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/api/webhook"
+	"github.com/diamondburned/arikawa/v3/discord"
+	discordwebhook "github.com/small-frappuccino/discordcore/pkg/discord/webhook"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/observability"
+)
+
+const (
+	// maxEmbedsPerMessage and maxEmbedCharsPerMessage are Discord's limits
+	// for a single message.
+	maxEmbedsPerMessage     = 10
+	maxEmbedCharsPerMessage = 6000
+
+	// defaultChannelPacing keeps each log channel well under Discord's
+	// per-channel limit of five messages every five seconds.
+	defaultChannelPacing = time.Second
+	defaultChannelQueue  = 500
+	notificationTimeout  = 15 * time.Second
+)
+
+// NotificationSender delivers log embeds to Discord. Every send goes through
+// it so guild mention policies are enforced in one place and a log channel
+// never pings anyone it was not configured to.
+//
+// Enqueued embeds are delivered per channel in arrival order. Embeds that
+// pile up while a channel is paced are coalesced into as few messages as
+// Discord's limits allow.
+//
+// Guilds with log webhooks enabled get their embeds through a webhook in
+// each log channel, falling back to sending as the bot when there is none.
+type NotificationSender struct {
+	config   *files.ConfigManager
+	logger   *slog.Logger
+	post     func(ctx context.Context, channelID discord.ChannelID, data api.SendMessageData) (*discord.Message, error)
+	webhooks *logWebhooks
+
+	pacing   time.Duration
+	queueCap int
+
+	mu       sync.Mutex
+	channels map[discord.ChannelID]*channelQueue
+
+	enqueued     atomic.Int64
+	dropped      atomic.Int64
+	messagesSent atomic.Int64
+	embedsSent   atomic.Int64
+	failed       atomic.Int64
+}
+
+// NotificationSenderStats reports delivery counters of a NotificationSender.
+type NotificationSenderStats struct {
+	Enqueued     int64 `json:"enqueued"`
+	Dropped      int64 `json:"dropped"`
+	MessagesSent int64 `json:"messages_sent"`
+	EmbedsSent   int64 `json:"embeds_sent"`
+	Failed       int64 `json:"failed"`
+	Pending      int   `json:"pending"`
+}
+
+type queuedEmbed struct {
+	guildID   string
+	eventType logging.LogEventType
+	mentions  files.LogMentionPolicy
+	embed     discord.Embed
+}
+
+type channelQueue struct {
+	pending  []queuedEmbed
+	draining bool
+	lastSent time.Time
+}
+
+// NewNotificationSender creates a sender posting through client.
+func NewNotificationSender(client *api.Client, config *files.ConfigManager, logger *slog.Logger) *NotificationSender {
+	s := &NotificationSender{
+		config: config,
+		logger: logger,
+		post: func(ctx context.Context, channelID discord.ChannelID, data api.SendMessageData) (*discord.Message, error) {
+			return client.WithContext(ctx).SendMessageComplex(channelID, data)
+		},
+		pacing:   defaultChannelPacing,
+		queueCap: defaultChannelQueue,
+		channels: make(map[discord.ChannelID]*channelQueue),
+	}
+	if client != nil {
+		s.webhooks = newLogWebhooks(client)
+	}
+	return s
+}
+
+// Send posts embeds to channelID right away with the allowed mentions
+// resolved for eventType in guildID, bypassing the queue.
+func (s *NotificationSender) Send(ctx context.Context, guildID string, channelID discord.ChannelID, eventType logging.LogEventType, embeds ...discord.Embed) error {
+	err := s.dispatch(ctx, guildID, channelID, api.SendMessageData{
+		Embeds:          embeds,
+		AllowedMentions: allowedMentions(s.mentionPolicy(guildID, eventType)),
+	})
+	if err != nil {
+		s.failed.Add(1)
+		observability.ReportOperationalAlert(observability.AlertSendFailure, "logging.notification_sender", err)
+		return fmt.Errorf("NotificationSender.Send: %w", err)
+	}
+	s.messagesSent.Add(1)
+	s.embedsSent.Add(int64(len(embeds)))
+	return nil
+}
+
+// Post sends data to channelID right away as the bot, bypassing the queue
+// and the guild's log webhooks, and returns the message. The allowed
+// mentions resolved for eventType in guildID replace those of data.
+func (s *NotificationSender) Post(ctx context.Context, guildID string, channelID discord.ChannelID, eventType logging.LogEventType, data api.SendMessageData) (*discord.Message, error) {
+	data.AllowedMentions = allowedMentions(s.mentionPolicy(guildID, eventType))
+	msg, err := s.post(ctx, channelID, data)
+	if err != nil {
+		s.failed.Add(1)
+		observability.ReportOperationalAlert(observability.AlertSendFailure, "logging.notification_sender", err)
+		return nil, fmt.Errorf("NotificationSender.Post: %w", err)
+	}
+	s.messagesSent.Add(1)
+	s.embedsSent.Add(int64(len(data.Embeds)))
+	return msg, nil
+}
+
+// Enqueue schedules embed for delivery to channelID. It never blocks; when
+// the channel's queue is full the embed is dropped and counted.
+func (s *NotificationSender) Enqueue(guildID string, channelID discord.ChannelID, eventType logging.LogEventType, embed discord.Embed) {
+	item := queuedEmbed{
+		guildID:   guildID,
+		eventType: eventType,
+		mentions:  s.mentionPolicy(guildID, eventType),
+		embed:     embed,
+	}
+
+	s.mu.Lock()
+	queue, ok := s.channels[channelID]
+	if !ok {
+		queue = &channelQueue{}
+		s.channels[channelID] = queue
+	}
+	if len(queue.pending) >= s.queueCap {
+		s.mu.Unlock()
+		s.dropped.Add(1)
+		s.logger.Warn("Mitigated service degradation: Log channel queue full, dropping embed",
+			slog.String("event_type", string(eventType)),
+			slog.Int64("channel_id", int64(channelID)),
+		)
+		return
+	}
+	queue.pending = append(queue.pending, item)
+	s.enqueued.Add(1)
+	start := !queue.draining
+	queue.draining = true
+	s.mu.Unlock()
+
+	if start {
+		go s.drain(channelID)
+	}
+}
+
+// Stats returns the sender's delivery counters.
+func (s *NotificationSender) Stats() NotificationSenderStats {
+	s.mu.Lock()
+	pending := 0
+	for _, queue := range s.channels {
+		pending += len(queue.pending)
+	}
+	s.mu.Unlock()
+	return NotificationSenderStats{
+		Enqueued:     s.enqueued.Load(),
+		Dropped:      s.dropped.Load(),
+		MessagesSent: s.messagesSent.Load(),
+		EmbedsSent:   s.embedsSent.Load(),
+		Failed:       s.failed.Load(),
+		Pending:      pending,
+	}
+}
+
+// drain delivers channelID's queue until it is empty. Only one drain runs per
+// channel at a time.
+func (s *NotificationSender) drain(channelID discord.ChannelID) {
+	for {
+		s.mu.Lock()
+		queue := s.channels[channelID]
+		wait := s.pacing - time.Since(queue.lastSent)
+		s.mu.Unlock()
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+
+		s.mu.Lock()
+		if len(queue.pending) == 0 {
+			queue.draining = false
+			delete(s.channels, channelID)
+			s.mu.Unlock()
+			return
+		}
+		var batch []queuedEmbed
+		batch, queue.pending = takeEmbedBatch(queue.pending)
+		s.mu.Unlock()
+
+		s.deliver(channelID, batch)
+
+		s.mu.Lock()
+		queue.lastSent = time.Now()
+		s.mu.Unlock()
+	}
+}
+
+func (s *NotificationSender) deliver(channelID discord.ChannelID, batch []queuedEmbed) {
+	embeds := make([]discord.Embed, len(batch))
+	for i, item := range batch {
+		embeds[i] = item.embed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+	defer cancel()
+	err := s.dispatch(ctx, batch[0].guildID, channelID, api.SendMessageData{
+		Embeds:          embeds,
+		AllowedMentions: allowedMentions(batch[0].mentions),
+	})
+	if err != nil {
+		s.failed.Add(int64(len(batch)))
+		observability.ReportOperationalAlert(observability.AlertSendFailure, "logging.notification_sender", err)
+		s.logger.Error("Failed to send event log embed",
+			slog.String("event_type", string(batch[0].eventType)),
+			slog.Int64("channel_id", int64(channelID)),
+			slog.Int("embeds", len(batch)),
+			slog.Any("error", err),
+		)
+		return
+	}
+	s.messagesSent.Add(1)
+	s.embedsSent.Add(int64(len(batch)))
+}
+
+// dispatch posts data to channelID, through the channel's webhook when
+// guildID delivers logs that way. A webhook that cannot be had or was
+// deleted does not lose the message; it is sent as the bot instead. A
+// webhook that is rate limited or slow is not bypassed, since posting as the
+// bot would only add load while Discord is struggling. While the bot lockdown
+// is engaged no webhook is used, so logs keep arriving as the bot.
+func (s *NotificationSender) dispatch(ctx context.Context, guildID string, channelID discord.ChannelID, data api.SendMessageData) error {
+	if hooks, ok := s.webhookConfig(guildID); ok {
+		err := s.webhooks.send(ctx, channelID, webhook.ExecuteData{
+			Username:        hooks.Username,
+			AvatarURL:       discord.URL(hooks.AvatarURL),
+			Embeds:          data.Embeds,
+			AllowedMentions: data.AllowedMentions,
+		})
+		if err == nil {
+			return nil
+		}
+		if classified := discordwebhook.ClassifyError("log webhook delivery", err); classified != nil && classified.Temporary {
+			return fmt.Errorf("NotificationSender.dispatch: %w", classified)
+		}
+		if !errors.Is(err, errNoWebhook) {
+			s.logger.Warn("Mitigated service degradation: Log webhook delivery failed, sending as the bot",
+				slog.String("guild_id", guildID),
+				slog.Int64("channel_id", int64(channelID)),
+				slog.Any("error", err),
+			)
+		}
+	}
+	_, err := s.post(ctx, channelID, data)
+	return err
+}
+
+func (s *NotificationSender) webhookConfig(guildID string) (files.LogWebhookConfig, bool) {
+	if s.webhooks == nil || s.config == nil || s.config.Config().LockdownActive() {
+		return files.LogWebhookConfig{}, false
+	}
+	gcfg := s.config.GuildConfig(guildID)
+	if gcfg == nil || !gcfg.LogWebhooks.Enabled {
+		return files.LogWebhookConfig{}, false
+	}
+	return gcfg.LogWebhooks, true
+}
+
+// takeEmbedBatch splits off the longest prefix of pending that fits in one
+// message: at most ten embeds, at most 6000 characters, and a single mention
+// policy so coalescing never widens who gets pinged.
+func takeEmbedBatch(pending []queuedEmbed) (batch, rest []queuedEmbed) {
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	n, chars := 0, 0
+	for n < len(pending) && n < maxEmbedsPerMessage {
+		item := pending[n]
+		length := item.embed.Length()
+		if n > 0 && (item.mentions != pending[0].mentions || chars+length > maxEmbedCharsPerMessage) {
+			break
+		}
+		chars += length
+		n++
+	}
+	return pending[:n:n], pending[n:]
+}
+
+func (s *NotificationSender) mentionPolicy(guildID string, eventType logging.LogEventType) files.LogMentionPolicy {
+	var gcfg *files.GuildConfig
+	if s.config != nil {
+		gcfg = s.config.GuildConfig(guildID)
+	}
+	return logging.ResolveMentionPolicy(eventType, gcfg)
+}
+
+// allowedMentions converts policy into Discord's allowlist. An empty Parse
+// list, rather than a nil one, is what tells Discord to ping nobody.
+func allowedMentions(policy files.LogMentionPolicy) *api.AllowedMentions {
+	parse := make([]api.AllowedMentionType, 0, 3)
+	if policy.Users {
+		parse = append(parse, api.AllowUserMention)
+	}
+	if policy.Roles {
+		parse = append(parse, api.AllowRoleMention)
+	}
+	if policy.Everyone {
+		parse = append(parse, api.AllowEveryoneMention)
+	}
+	return &api.AllowedMentions{Parse: parse}
+}
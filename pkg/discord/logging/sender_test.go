@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+)
+
+func TestTakeEmbedBatch(t *testing.T) {
+	t.Parallel()
+
+	pending := make([]queuedEmbed, 12)
+	batch, rest := takeEmbedBatch(pending)
+	if len(batch) != maxEmbedsPerMessage || len(rest) != 2 {
+		t.Fatalf("expected a full batch of %d, got %d with %d left", maxEmbedsPerMessage, len(batch), len(rest))
+	}
+
+	pending = []queuedEmbed{{}, {}, {mentions: files.LogMentionPolicy{Users: true}}, {}}
+	batch, rest = takeEmbedBatch(pending)
+	if len(batch) != 2 || len(rest) != 2 {
+		t.Fatalf("expected batch to stop at a mention policy change, got %d/%d", len(batch), len(rest))
+	}
+
+	large := discord.Embed{Description: strings.Repeat("x", 4000)}
+	pending = []queuedEmbed{{embed: large}, {embed: large}}
+	batch, rest = takeEmbedBatch(pending)
+	if len(batch) != 1 || len(rest) != 1 {
+		t.Fatalf("expected batch to respect the character limit, got %d/%d", len(batch), len(rest))
+	}
+}
+
+func TestNotificationSenderCoalescesQueuedEmbeds(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var messages [][]discord.Embed
+	release := make(chan struct{})
+	sender := NewNotificationSender(nil, nil, slog.Default())
+	sender.pacing = 0
+	sender.post = func(ctx context.Context, channelID discord.ChannelID, data api.SendMessageData) (*discord.Message, error) {
+		<-release
+		mu.Lock()
+		messages = append(messages, data.Embeds)
+		mu.Unlock()
+		return &discord.Message{}, nil
+	}
+
+	for i := 0; i < 13; i++ {
+		sender.Enqueue("g1", 42, logging.LogEventMessageDelete, discord.Embed{Title: "e"})
+	}
+	close(release)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for sender.Stats().EmbedsSent < 13 {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for delivery: %+v", sender.Stats())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(messages) > 3 {
+		t.Fatalf("expected queued embeds to be coalesced, got %d messages", len(messages))
+	}
+	for _, embeds := range messages {
+		if len(embeds) > maxEmbedsPerMessage {
+			t.Fatalf("message carries %d embeds", len(embeds))
+		}
+	}
+	if stats := sender.Stats(); stats.Enqueued != 13 || stats.MessagesSent != int64(len(messages)) || stats.Failed != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/embeds"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+	"github.com/small-frappuccino/discordcore/pkg/permissions"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+// OnRoleSettingsChange implements members.RoleSettingsSink, logging what
+// changed in a role's settings: its look side by side before and after, and
+// the permissions it gained or lost by name.
+func (l *Logger) OnRoleSettingsChange(ctx context.Context, intent members.RoleSettingsIntent) {
+	if intent.Before == intent.After {
+		return
+	}
+	decision, ok := l.checkPolicy(logging.LogEventServerChange, intent.GuildID, logging.RouteContext{
+		Target: l.routeSubject(intent.GuildID, intent.ActorID, false, nil),
+	})
+	if !ok {
+		return
+	}
+
+	channelID, err := discord.ParseSnowflake(decision.ChannelID)
+	if err != nil {
+		return
+	}
+
+	lang := l.language(intent.GuildID, logging.LogEventServerChange)
+	ce := roleSettingsEmbed(lang, intent)
+	if intent.ActorID != "" {
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+			Name: lang.Text("Changed By"), Value: l.userLabel(intent.GuildID, intent.ActorID, l.cachedNames(intent.GuildID, intent.ActorID, "")),
+		})
+	}
+
+	embed := embeds.Render(ce)
+	embed.Timestamp = discord.NowTimestamp()
+	l.sendEmbed(ctx, intent.GuildID, discord.ChannelID(channelID), embed, logging.LogEventServerChange, logRef{ActorID: intent.ActorID})
+}
+
+// roleSettingsEmbed renders the settings of the role that changed. The
+// embed takes the new color and icon.
+func roleSettingsEmbed(lang logging.LogLanguage, intent members.RoleSettingsIntent) files.CustomEmbedConfig {
+	ce := files.CustomEmbedConfig{
+		Title:        lang.Text("Role Edited"),
+		Description:  logging.FormatRoleLabel(intent.RoleID, intent.Name),
+		Color:        theme.MemberRoleUpdate(),
+		ThumbnailURL: intent.After.IconURL,
+		FooterText:   fmt.Sprintf(lang.Text("Role ID: %s"), intent.RoleID),
+	}
+	if intent.After.Color != 0 {
+		ce.Color = intent.After.Color
+	}
+	if before := renderRoleSettings(lang, intent.Before, intent.After); before != "" {
+		ce.Fields = append(ce.Fields,
+			files.CustomEmbedFieldConfig{Name: lang.Text("Before"), Value: before, Inline: true},
+			files.CustomEmbedFieldConfig{Name: lang.Text("After"), Value: renderRoleSettings(lang, intent.After, intent.Before), Inline: true},
+		)
+	}
+	granted := intent.After.Permissions &^ intent.Before.Permissions
+	revoked := intent.Before.Permissions &^ intent.After.Permissions
+	if granted != 0 {
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{Name: lang.Text("Permissions Granted"), Value: permissionList(granted)})
+	}
+	if revoked != 0 {
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{Name: lang.Text("Permissions Revoked"), Value: permissionList(revoked)})
+	}
+	return ce
+}
+
+// renderRoleSettings lists the attributes of settings that differ from
+// other, leaving permissions to their own fields.
+func renderRoleSettings(lang logging.LogLanguage, settings, other members.RoleSettings) string {
+	var lines []string
+	if settings.Name != other.Name {
+		lines = append(lines, lang.Text("Name")+": "+logging.EscapeUserText(settings.Name))
+	}
+	if settings.Color != other.Color {
+		value := lang.Text("Default")
+		if settings.Color != 0 {
+			value = fmt.Sprintf("`#%06X`", settings.Color)
+		}
+		lines = append(lines, lang.Text("Color")+": "+value)
+	}
+	if settings.IconURL != other.IconURL {
+		value := lang.Text("*(none)*")
+		if settings.IconURL != "" {
+			value = "[" + lang.Text("View icon") + "](" + settings.IconURL + ")"
+		}
+		lines = append(lines, lang.Text("Icon")+": "+value)
+	}
+	if settings.Emoji != other.Emoji {
+		value := lang.Text("*(none)*")
+		if settings.Emoji != "" {
+			value = settings.Emoji
+		}
+		lines = append(lines, lang.Text("Emoji")+": "+value)
+	}
+	if settings.Hoist != other.Hoist {
+		lines = append(lines, lang.Text("Shown Separately")+": "+yesNo(lang, settings.Hoist))
+	}
+	if settings.Mentionable != other.Mentionable {
+		lines = append(lines, lang.Text("Mentionable")+": "+yesNo(lang, settings.Mentionable))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// permissionList names the permissions in bits. Bits Discord added after
+// the names were written are shown as a hexadecimal mask.
+func permissionList(bits uint64) string {
+	names := permissions.Names(int64(bits))
+	if rest := permissions.Unnamed(int64(bits)); rest != 0 {
+		names = append(names, fmt.Sprintf("`%#x`", rest))
+	}
+	return strings.Join(names, ", ")
+}
+
+func yesNo(lang logging.LogLanguage, v bool) string {
+	if v {
+		return lang.Text("Yes")
+	}
+	return lang.Text("No")
+}
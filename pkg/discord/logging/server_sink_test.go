@@ -0,0 +1,153 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+)
+
+func TestRoleSettingsEmbed(t *testing.T) {
+	t.Parallel()
+	ce := roleSettingsEmbed(logging.LogLanguageEnglish, members.RoleSettingsIntent{
+		GuildID: "1",
+		RoleID:  "2",
+		Name:    "Mods",
+		Before:  members.RoleSettings{Color: 0x112233, Emoji: "🛡️"},
+		After:   members.RoleSettings{Color: 0x445566, Emoji: "🛡️", IconURL: "https://cdn.example/icon.png"},
+	})
+
+	if ce.Color != 0x445566 || ce.ThumbnailURL != "https://cdn.example/icon.png" {
+		t.Fatalf("expected the embed to take the new color and icon, got %#x %q", ce.Color, ce.ThumbnailURL)
+	}
+	if len(ce.Fields) != 2 {
+		t.Fatalf("expected before and after fields, got %+v", ce.Fields)
+	}
+	before, after := ce.Fields[0].Value, ce.Fields[1].Value
+	if !strings.Contains(before, "`#112233`") || !strings.Contains(before, "Icon: *(none)*") {
+		t.Fatalf("unexpected before field %q", before)
+	}
+	if !strings.Contains(after, "`#445566`") || !strings.Contains(after, "[View icon](https://cdn.example/icon.png)") {
+		t.Fatalf("unexpected after field %q", after)
+	}
+	if strings.Contains(before+after, "Emoji") {
+		t.Fatalf("expected the unchanged emoji to be left out, got %q / %q", before, after)
+	}
+}
+
+func TestRoleSettingsEmbedRename(t *testing.T) {
+	t.Parallel()
+	ce := roleSettingsEmbed(logging.LogLanguageEnglish, members.RoleSettingsIntent{
+		RoleID: "2",
+		Name:   "Moderators",
+		Before: members.RoleSettings{Name: "Mods"},
+		After:  members.RoleSettings{Name: "Moderators"},
+	})
+	if len(ce.Fields) != 2 || ce.Fields[0].Value != "Name: Mods" || ce.Fields[1].Value != "Name: Moderators" {
+		t.Fatalf("unexpected rename fields %+v", ce.Fields)
+	}
+}
+
+func TestRoleSettingsEmbedPermissions(t *testing.T) {
+	t.Parallel()
+	ce := roleSettingsEmbed(logging.LogLanguageEnglish, members.RoleSettingsIntent{
+		RoleID: "2",
+		Name:   "Mods",
+		Before: members.RoleSettings{Permissions: 1<<13 | 1<<1, Mentionable: true},
+		After:  members.RoleSettings{Permissions: 1<<13 | 1<<2 | 1<<40 | 1<<50, Hoist: true, Mentionable: true},
+	})
+
+	if ce.Title != "Role Edited" || len(ce.Fields) != 4 {
+		t.Fatalf("expected before, after, granted and revoked fields, got %+v", ce.Fields)
+	}
+	if before, after := ce.Fields[0].Value, ce.Fields[1].Value; before != "Shown Separately: No" || after != "Shown Separately: Yes" {
+		t.Fatalf("unexpected before/after %q / %q", before, after)
+	}
+	if granted := ce.Fields[2]; granted.Name != "Permissions Granted" || granted.Value != "Ban Members, Timeout Members, `0x4000000000000`" {
+		t.Fatalf("unexpected granted field %+v", granted)
+	}
+	if revoked := ce.Fields[3]; revoked.Name != "Permissions Revoked" || revoked.Value != "Kick Members" {
+		t.Fatalf("unexpected revoked field %+v", revoked)
+	}
+
+	// A change to permissions alone carries no before and after fields.
+	ce = roleSettingsEmbed(logging.LogLanguagePortuguese, members.RoleSettingsIntent{
+		RoleID: "2",
+		Before: members.RoleSettings{},
+		After:  members.RoleSettings{Permissions: 1 << 3},
+	})
+	if len(ce.Fields) != 1 || ce.Fields[0].Name != "Permissões concedidas" || ce.Fields[0].Value != "Administrator" {
+		t.Fatalf("unexpected permission-only embed %+v", ce.Fields)
+	}
+}
+
+func TestVoiceActionEmbed(t *testing.T) {
+	t.Parallel()
+	ce, ok := voiceActionEmbed(logging.LogLanguageEnglish, members.VoiceIntent{
+		Action: members.VoiceMembersMoved, ChannelID: "9", Count: 3,
+	})
+	if !ok || ce.Title != "Members Moved" || len(ce.Fields) != 2 || ce.Fields[1].Value != "3" {
+		t.Fatalf("unexpected move embed %+v", ce)
+	}
+	ce, ok = voiceActionEmbed(logging.LogLanguageEnglish, members.VoiceIntent{
+		Action: members.VoiceStageStarted, ChannelID: "4", Topic: "Town *hall*",
+	})
+	if !ok || ce.Title != "Stage Started" || len(ce.Fields) != 2 || !strings.Contains(ce.Fields[1].Value, `\*hall\*`) {
+		t.Fatalf("unexpected stage embed %+v", ce)
+	}
+	if _, ok := voiceActionEmbed(logging.LogLanguageEnglish, members.VoiceIntent{Action: "unknown"}); ok {
+		t.Fatal("expected unknown actions to be skipped")
+	}
+}
+
+func TestAssetChangeEmbed(t *testing.T) {
+	t.Parallel()
+	ce, ok := assetChangeEmbed(logging.LogLanguageEnglish, members.AssetIntent{
+		AssetID: "7", Kind: members.AssetSticker, Action: members.AssetUpdated,
+		Name: "party_*cat*", PreviousName: "cat", ImageURL: "https://media.example/7.png",
+	})
+	if !ok || ce.Title != "Sticker Updated" || ce.ThumbnailURL != "https://media.example/7.png" || ce.FooterText != "Sticker ID: 7" {
+		t.Fatalf("unexpected sticker embed %+v", ce)
+	}
+	if len(ce.Fields) != 2 || !strings.Contains(ce.Fields[0].Value, `\*cat\*`) || ce.Fields[1].Value != "cat" {
+		t.Fatalf("expected the new and previous names, got %+v", ce.Fields)
+	}
+	ce, ok = assetChangeEmbed(logging.LogLanguageEnglish, members.AssetIntent{
+		AssetID: "8", Kind: members.AssetSound, Action: members.AssetDeleted, Name: "airhorn", PreviousName: "airhorn",
+	})
+	if !ok || ce.Title != "Sound Deleted" || len(ce.Fields) != 1 || ce.FooterText != "Sound ID: 8" {
+		t.Fatalf("unexpected sound embed %+v", ce)
+	}
+	if _, ok := assetChangeEmbed(logging.LogLanguageEnglish, members.AssetIntent{Kind: "emoji", Action: members.AssetCreated}); ok {
+		t.Fatal("expected unknown assets to be skipped")
+	}
+}
+
+func TestPresenceChangeEmbed(t *testing.T) {
+	t.Parallel()
+	ce, ok := presenceChangeEmbed(logging.LogLanguageEnglish, members.PresenceIntent{
+		UserID:               "5",
+		CustomStatus:         "brb",
+		PreviousCustomStatus: "",
+		Activities:           []members.PresenceActivity{{Verb: "Playing", Name: "*Chess*"}},
+		PreviousActivities:   []members.PresenceActivity{{Verb: "Playing", Name: "*Chess*"}},
+	})
+	if !ok || ce.Title != "Presence Updated" || ce.FooterText != "User ID: 5" {
+		t.Fatalf("unexpected presence embed %+v", ce)
+	}
+	if len(ce.Fields) != 2 || ce.Fields[0].Value != "brb" || ce.Fields[1].Value != "None" {
+		t.Fatalf("expected only the custom status fields, got %+v", ce.Fields)
+	}
+
+	ce, ok = presenceChangeEmbed(logging.LogLanguagePortuguese, members.PresenceIntent{
+		Activities: []members.PresenceActivity{{Verb: "Listening to", Name: "Spotify"}, {Verb: "Playing", Name: "*Chess*"}},
+	})
+	if !ok || len(ce.Fields) != 2 || ce.Fields[0].Value != "Ouvindo **Spotify**\nJogando **\\*Chess\\***" || ce.Fields[1].Value != "Nenhum" {
+		t.Fatalf("expected translated activity lists, got %+v", ce.Fields)
+	}
+
+	if _, ok := presenceChangeEmbed(logging.LogLanguageEnglish, members.PresenceIntent{CustomStatus: "same", PreviousCustomStatus: "same"}); ok {
+		t.Fatal("expected an unchanged presence to be skipped")
+	}
+}
@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/embeds"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/messages"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+// OnThreadEvent implements messages.ThreadSink for thread lifecycle logging.
+func (l *Logger) OnThreadEvent(ctx context.Context, intent messages.ThreadIntent) {
+	if l.threads != nil && l.ownsMemberThread(intent) {
+		if intent.Action == messages.ThreadDeleted {
+			l.threads.forget(intent.GuildID, intent.ParentID, intent.Name)
+		}
+		// Member log threads are the bot's own bookkeeping, not activity
+		// worth logging.
+		return
+	}
+	decision, ok := l.checkPolicy(logging.LogEventThreadChange, intent.GuildID, logging.RouteContext{
+		Target: l.routeSubject(intent.GuildID, intent.OwnerID, false, nil),
+	})
+	if !ok {
+		return
+	}
+
+	channelID, err := discord.ParseSnowflake(decision.ChannelID)
+	if err != nil {
+		return
+	}
+
+	lang := l.language(intent.GuildID, logging.LogEventThreadChange)
+	var title string
+	color := theme.Info()
+	switch intent.Action {
+	case messages.ThreadCreated:
+		title = "Thread Created"
+		color = theme.Success()
+	case messages.ThreadArchived:
+		title = "Thread Archived"
+		color = theme.Muted()
+	case messages.ThreadDeleted:
+		title = "Thread Deleted"
+		color = theme.MessageDelete()
+	default:
+		return
+	}
+
+	// A deleted thread no longer resolves as a mention, so it goes by name.
+	thread := logging.FormatChannelLabel(intent.ThreadID)
+	if intent.Action == messages.ThreadDeleted {
+		thread = logging.EscapeUserText(intent.Name)
+		if thread == "" {
+			thread = lang.Text("*Unknown*")
+		}
+	}
+	ce := files.CustomEmbedConfig{
+		Title: lang.Text(title),
+		Color: color,
+		Fields: []files.CustomEmbedFieldConfig{
+			{Name: lang.Text("Thread"), Value: thread, Inline: true},
+		},
+		FooterText: fmt.Sprintf(lang.Text("Thread ID: %s"), intent.ThreadID),
+	}
+	if intent.ParentID != "" {
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+			Name: lang.Text("Parent Channel"), Value: logging.FormatChannelLabel(intent.ParentID), Inline: true,
+		})
+	}
+	if intent.OwnerID != "" {
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+			Name: lang.Text("Creator"), Value: l.userLabel(intent.GuildID, intent.OwnerID, l.cachedNames(intent.GuildID, intent.OwnerID, "")), Inline: true,
+		})
+	}
+	if intent.Action == messages.ThreadArchived && intent.AutoArchive > 0 {
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+			Name: lang.Text("Auto-archive"), Value: logging.FormatDurationSmart(time.Duration(intent.AutoArchive) * time.Minute), Inline: true,
+		})
+	}
+
+	embed := embeds.Render(ce)
+	embed.Timestamp = discord.NowTimestamp()
+	l.sendEmbed(ctx, intent.GuildID, discord.ChannelID(channelID), embed, logging.LogEventThreadChange, logRef{
+		UserID:    intent.OwnerID,
+		ChannelID: intent.ParentID,
+	})
+}
+
+// ownsMemberThread reports whether intent is about a member log thread the
+// bot opened.
+func (l *Logger) ownsMemberThread(intent messages.ThreadIntent) bool {
+	if _, ok := userThreadOwner(intent.Name); !ok || l.state == nil {
+		return false
+	}
+	me, err := l.state.Me()
+	return err == nil && me.ID.String() == intent.OwnerID
+}
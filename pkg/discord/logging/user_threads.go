@@ -0,0 +1,185 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/cache"
+	"github.com/small-frappuccino/discordcore/pkg/keylock"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+)
+
+const (
+	// maxThreadNameLength is Discord's limit on channel names.
+	maxThreadNameLength = 100
+
+	// maxArchivedThreadPages bounds the pages of archived threads searched
+	// for a member's thread before a new one is opened.
+	maxArchivedThreadPages = 10
+	archivedThreadPageSize = 100
+)
+
+// userThreadEvents are the log events about a single member that guilds can
+// route into a thread per member.
+var userThreadEvents = map[logging.LogEventType]bool{
+	logging.LogEventAvatarChange:   true,
+	logging.LogEventRoleChange:     true,
+	logging.LogEventNameChange:     true,
+	logging.LogEventModerationCase: true,
+}
+
+// userThreadClient is the part of *state.State user threads are found and
+// opened through.
+type userThreadClient interface {
+	Channels(guildID discord.GuildID) ([]discord.Channel, error)
+	PublicArchivedThreads(channelID discord.ChannelID, before discord.Timestamp, limit uint) (*api.ArchivedThreads, error)
+	StartThreadWithoutMessage(channelID discord.ChannelID, data api.StartThreadData) (*discord.Channel, error)
+}
+
+// userThreads resolves the thread a member's logs go to under a log channel,
+// opening it the first time the member is logged there. Resolved threads are
+// kept in the UnifiedCache; on a miss the guild's active threads, then the
+// channel's archived ones, are searched by name before a new one is opened,
+// since the cache only holds weak references and threads archive after a
+// week without logs.
+type userThreads struct {
+	client userThreadClient
+
+	// locks serializes misses per member and channel, so concurrent events
+	// about one member open a single thread without holding up the others.
+	locks keylock.Mutex[string]
+
+	mu    sync.Mutex
+	cache *cache.UnifiedCache
+}
+
+func (t *userThreads) setCache(uc *cache.UnifiedCache) {
+	t.mu.Lock()
+	t.cache = uc
+	t.mu.Unlock()
+}
+
+// thread returns the thread for userID under channelID, opening it named
+// after name when the member has none yet.
+func (t *userThreads) thread(guildID discord.GuildID, channelID discord.ChannelID, userID, name string) (discord.ChannelID, error) {
+	gid, cid := guildID.String(), channelID.String()
+	uc := t.userCache()
+	if uc != nil {
+		if th, ok := uc.GetUserLogThread(gid, cid, userID); ok {
+			return th.ID, nil
+		}
+	}
+	unlock := t.locks.Lock(gid + "/" + cid + "/" + userID)
+	defer unlock()
+	if uc != nil {
+		if th, ok := uc.GetUserLogThread(gid, cid, userID); ok {
+			return th.ID, nil
+		}
+	}
+
+	found := t.find(guildID, channelID, userID)
+	if found == nil {
+		th, err := t.client.StartThreadWithoutMessage(channelID, api.StartThreadData{
+			Name:                userThreadName(name, userID),
+			AutoArchiveDuration: discord.SevenDaysArchive,
+			Type:                discord.GuildPublicThread,
+			AuditLogReason:      api.AuditLogReason("Log thread for member " + userID),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("userThreads.thread: %w", err)
+		}
+		found = th
+	}
+	if uc != nil {
+		uc.SetUserLogThread(gid, cid, userID, found)
+	}
+	return found.ID, nil
+}
+
+func (t *userThreads) userCache() *cache.UnifiedCache {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cache
+}
+
+// find searches the active threads of guildID, then the archived threads of
+// channelID, for the thread of userID under channelID. Lookup failures count
+// as not found.
+func (t *userThreads) find(guildID discord.GuildID, channelID discord.ChannelID, userID string) *discord.Channel {
+	suffix := " (" + userID + ")"
+	owned := func(ch *discord.Channel) bool {
+		return ch.ParentID == channelID && ch.Type == discord.GuildPublicThread && strings.HasSuffix(ch.Name, suffix)
+	}
+	if channels, err := t.client.Channels(guildID); err == nil {
+		for i := range channels {
+			if owned(&channels[i]) {
+				return &channels[i]
+			}
+		}
+	}
+	var before discord.Timestamp
+	for range maxArchivedThreadPages {
+		page, err := t.client.PublicArchivedThreads(channelID, before, archivedThreadPageSize)
+		if err != nil || page == nil {
+			return nil
+		}
+		for i := range page.Threads {
+			if owned(&page.Threads[i]) {
+				return &page.Threads[i]
+			}
+		}
+		if !page.More || len(page.Threads) == 0 {
+			return nil
+		}
+		last := page.Threads[len(page.Threads)-1]
+		if last.ThreadMetadata == nil {
+			return nil
+		}
+		before = last.ThreadMetadata.ArchiveTimestamp
+	}
+	return nil
+}
+
+// forget drops the cached thread named name under channelID once it is
+// deleted. Threads that are not member log threads are ignored.
+func (t *userThreads) forget(guildID, channelID, name string) {
+	userID, ok := userThreadOwner(name)
+	if !ok {
+		return
+	}
+	if uc := t.userCache(); uc != nil {
+		uc.InvalidateUserLogThread(guildID, channelID, userID)
+	}
+}
+
+// userThreadName names a member's log thread. The user ID ends the name, so
+// the thread is found again after the member is renamed.
+func userThreadName(name, userID string) string {
+	suffix := " (" + userID + ")"
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Member"
+	}
+	if max := maxThreadNameLength - len(suffix); utf8.RuneCountInString(name) > max {
+		name = string([]rune(name)[:max-1]) + "…"
+	}
+	return name + suffix
+}
+
+// userThreadOwner returns the user ID a thread named by userThreadName ends
+// with.
+func userThreadOwner(name string) (string, bool) {
+	open := strings.LastIndex(name, " (")
+	if open < 0 || !strings.HasSuffix(name, ")") {
+		return "", false
+	}
+	userID := name[open+2 : len(name)-1]
+	if sf, err := discord.ParseSnowflake(userID); err != nil || !sf.IsValid() {
+		return "", false
+	}
+	return userID, true
+}
@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/cache"
+)
+
+type fakeThreadClient struct {
+	channels []discord.Channel
+	archived []discord.Channel
+	started  []api.StartThreadData
+}
+
+func (f *fakeThreadClient) Channels(discord.GuildID) ([]discord.Channel, error) {
+	return f.channels, nil
+}
+
+// PublicArchivedThreads serves the archived threads one per page, newest
+// first.
+func (f *fakeThreadClient) PublicArchivedThreads(channelID discord.ChannelID, before discord.Timestamp, _ uint) (*api.ArchivedThreads, error) {
+	for i, th := range f.archived {
+		if th.ParentID != channelID || (before.IsValid() && !th.ThreadMetadata.ArchiveTimestamp.Time().Before(before.Time())) {
+			continue
+		}
+		page := &api.ArchivedThreads{More: i < len(f.archived)-1}
+		page.Threads = []discord.Channel{th}
+		return page, nil
+	}
+	return &api.ArchivedThreads{}, nil
+}
+
+func (f *fakeThreadClient) StartThreadWithoutMessage(channelID discord.ChannelID, data api.StartThreadData) (*discord.Channel, error) {
+	f.started = append(f.started, data)
+	f.channels = append(f.channels, discord.Channel{ID: discord.ChannelID(900 + len(f.started)), ParentID: channelID, Type: data.Type, Name: data.Name})
+	// The cache holds weak references; the client keeps the thread alive.
+	return &f.channels[len(f.channels)-1], nil
+}
+
+func TestUserThreads(t *testing.T) {
+	t.Parallel()
+	client := &fakeThreadClient{channels: []discord.Channel{
+		{ID: 800, ParentID: 50, Type: discord.GuildPublicThread, Name: "bob (8)"},
+		{ID: 801, ParentID: 51, Type: discord.GuildPublicThread, Name: "alice (7)"},
+	}}
+	uc := cache.NewUnifiedCache(cache.CacheConfig{ChannelTTL: time.Minute})
+	threads := &userThreads{client: client}
+	threads.setCache(uc)
+
+	th, err := threads.thread(1, 50, "8", "bob")
+	if err != nil || th != 800 || len(client.started) != 0 {
+		t.Fatalf("expected the existing thread of bob, got %v, %v (started %d)", th, err, len(client.started))
+	}
+
+	th, err = threads.thread(1, 50, "7", "alice")
+	if err != nil || len(client.started) != 1 || client.started[0].Name != "alice (7)" {
+		t.Fatalf("expected a thread for alice under the log channel, got %v, %v, %+v", th, err, client.started)
+	}
+	cached, ok := uc.GetUserLogThread("1", "50", "7")
+	if !ok || cached.ID != th {
+		t.Fatalf("expected the new thread to be cached, got %v", cached)
+	}
+	if again, _ := threads.thread(1, 50, "7", "alice renamed"); again != th || len(client.started) != 1 {
+		t.Fatalf("expected alice's thread to be reused, got %v (started %d)", again, len(client.started))
+	}
+
+	threads.forget("1", "50", "alice (7)")
+	if _, ok := uc.GetUserLogThread("1", "50", "7"); ok {
+		t.Fatal("expected a deleted thread to leave the cache")
+	}
+	threads.forget("1", "50", "general")
+
+	// Archived threads are found again instead of being opened twice.
+	now := time.Now()
+	client.archived = []discord.Channel{
+		{ID: 700, ParentID: 50, Type: discord.GuildPublicThread, Name: "carol (6)", ThreadMetadata: &discord.ThreadMetadata{ArchiveTimestamp: discord.NewTimestamp(now)}},
+		{ID: 701, ParentID: 50, Type: discord.GuildPublicThread, Name: "dave (5)", ThreadMetadata: &discord.ThreadMetadata{ArchiveTimestamp: discord.NewTimestamp(now.Add(-time.Hour))}},
+	}
+	started := len(client.started)
+	if th, err := threads.thread(1, 50, "5", "dave"); err != nil || th != 701 || len(client.started) != started {
+		t.Fatalf("expected dave's archived thread, got %v, %v (started %d)", th, err, len(client.started)-started)
+	}
+}
+
+func TestUserThreadName(t *testing.T) {
+	t.Parallel()
+	if got := userThreadName("", "7"); got != "Member (7)" {
+		t.Fatalf("unexpected name %q", got)
+	}
+	long := userThreadName(strings.Repeat("é", 200), "1296000000000000007")
+	if n := len([]rune(long)); n != maxThreadNameLength {
+		t.Fatalf("expected the name cut to %d runes, got %d", maxThreadNameLength, n)
+	}
+	if userID, ok := userThreadOwner(long); !ok || userID != "1296000000000000007" {
+		t.Fatalf("expected the owner back from %q, got %q", long, userID)
+	}
+	if _, ok := userThreadOwner("help (wanted)"); ok {
+		t.Fatal("a thread not named after a user must have no owner")
+	}
+}
@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/discord/embeds"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	"github.com/small-frappuccino/discordcore/pkg/members"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+// OnVoiceAction implements members.VoiceSink, logging stages and moderators
+// moving or disconnecting members from voice.
+func (l *Logger) OnVoiceAction(ctx context.Context, intent members.VoiceIntent) {
+	decision, ok := l.checkPolicy(logging.LogEventVoiceChange, intent.GuildID, logging.RouteContext{
+		Target: l.routeSubject(intent.GuildID, intent.ActorID, false, nil),
+	})
+	if !ok {
+		return
+	}
+
+	channelID, err := discord.ParseSnowflake(decision.ChannelID)
+	if err != nil {
+		return
+	}
+
+	lang := l.language(intent.GuildID, logging.LogEventVoiceChange)
+	ce, ok := voiceActionEmbed(lang, intent)
+	if !ok {
+		return
+	}
+	if intent.ActorID != "" {
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+			Name: lang.Text("Moderator"), Value: l.userLabel(intent.GuildID, intent.ActorID, l.cachedNames(intent.GuildID, intent.ActorID, "")), Inline: true,
+		})
+	}
+
+	embed := embeds.Render(ce)
+	embed.Timestamp = discord.NowTimestamp()
+	l.sendEmbed(ctx, intent.GuildID, discord.ChannelID(channelID), embed, logging.LogEventVoiceChange, logRef{
+		ActorID:   intent.ActorID,
+		ChannelID: intent.ChannelID,
+	})
+}
+
+// voiceActionEmbed renders intent without its moderator, reporting false for
+// actions it does not know.
+func voiceActionEmbed(lang logging.LogLanguage, intent members.VoiceIntent) (files.CustomEmbedConfig, bool) {
+	var ce files.CustomEmbedConfig
+	switch intent.Action {
+	case members.VoiceStageStarted:
+		ce.Title, ce.Color = lang.Text("Stage Started"), theme.Success()
+	case members.VoiceStageEnded:
+		ce.Title, ce.Color = lang.Text("Stage Ended"), theme.Muted()
+	case members.VoiceMembersMoved:
+		ce.Title, ce.Color = lang.Text("Members Moved"), theme.Warning()
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+			Name: lang.Text("Moved To"), Value: logging.FormatChannelLabel(intent.ChannelID), Inline: true,
+		})
+	case members.VoiceMembersDisconnected:
+		ce.Title, ce.Color = lang.Text("Members Disconnected"), theme.MemberLeave()
+	default:
+		return ce, false
+	}
+	if intent.Action == members.VoiceStageStarted || intent.Action == members.VoiceStageEnded {
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+			Name: lang.Text("Stage"), Value: logging.FormatChannelLabel(intent.ChannelID), Inline: true,
+		})
+		if intent.Topic != "" {
+			ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+				Name: lang.Text("Topic"), Value: logging.EscapeUserText(intent.Topic), Inline: true,
+			})
+		}
+	}
+	if intent.Count > 0 {
+		ce.Fields = append(ce.Fields, files.CustomEmbedFieldConfig{
+			Name: lang.Text("Members"), Value: strconv.Itoa(intent.Count), Inline: true,
+		})
+	}
+	return ce, true
+}
@@ -0,0 +1,159 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/api/webhook"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+)
+
+const (
+	// logWebhookName names the webhooks the logging bot creates, and is how
+	// it recognises them again after a restart.
+	// Discord rejects webhook names containing "discord".
+	logWebhookName = "Log relay"
+	// webhookRetryAfter is how long a channel whose webhook could not be
+	// found or created is sent to as the bot before trying again, so a
+	// missing Manage Webhooks permission does not cost a request per log.
+	webhookRetryAfter = 10 * time.Minute
+)
+
+// errNoWebhook is returned while a channel that had no webhook waits to be
+// tried again.
+var errNoWebhook = errors.New("no log webhook for the channel")
+
+// channelWebhook is the webhook log embeds for a channel are posted through.
+// Threads have no webhooks of their own; their logs go through the parent
+// channel's webhook with threadID set.
+type channelWebhook struct {
+	hook     discord.Webhook
+	threadID discord.ChannelID
+	// failedAt is set when no webhook could be had for the channel.
+	failedAt time.Time
+}
+
+// logWebhooks finds or creates the webhook of each log channel once and
+// remembers it.
+type logWebhooks struct {
+	resolve func(ctx context.Context, channelID discord.ChannelID) (channelWebhook, error)
+	execute func(ctx context.Context, hook discord.Webhook, data webhook.ExecuteData) error
+	now     func() time.Time
+
+	mu    sync.Mutex
+	hooks map[discord.ChannelID]channelWebhook
+}
+
+func newLogWebhooks(client *api.Client) *logWebhooks {
+	return &logWebhooks{
+		resolve: func(ctx context.Context, channelID discord.ChannelID) (channelWebhook, error) {
+			return findLogWebhook(client.WithContext(ctx), channelID)
+		},
+		execute: func(ctx context.Context, hook discord.Webhook, data webhook.ExecuteData) error {
+			return webhook.FromAPI(hook.ID, hook.Token, client).WithContext(ctx).Execute(data)
+		},
+		now:   time.Now,
+		hooks: make(map[discord.ChannelID]channelWebhook),
+	}
+}
+
+// send posts data to channelID through its webhook. When the webhook turns
+// out to have been deleted it is forgotten, so the next send creates a new
+// one; the caller falls back to sending as the bot meanwhile.
+func (w *logWebhooks) send(ctx context.Context, channelID discord.ChannelID, data webhook.ExecuteData) error {
+	target, err := w.target(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	data.ThreadID = discord.CommandID(target.threadID)
+	err = w.execute(ctx, target.hook, data)
+	if unknownWebhook(err) {
+		w.forget(target.hook.ID)
+	}
+	return err
+}
+
+func (w *logWebhooks) target(ctx context.Context, channelID discord.ChannelID) (channelWebhook, error) {
+	w.mu.Lock()
+	target, ok := w.hooks[channelID]
+	w.mu.Unlock()
+	if ok {
+		if target.failedAt.IsZero() {
+			return target, nil
+		}
+		if w.now().Sub(target.failedAt) < webhookRetryAfter {
+			return channelWebhook{}, errNoWebhook
+		}
+	}
+
+	target, err := w.resolve(ctx, channelID)
+	if err != nil {
+		target = channelWebhook{failedAt: w.now()}
+	}
+	w.mu.Lock()
+	w.hooks[channelID] = target
+	w.mu.Unlock()
+	if err != nil {
+		return channelWebhook{}, fmt.Errorf("logWebhooks.target: %w", err)
+	}
+	return target, nil
+}
+
+// forget drops hookID from every channel that posts through it, a parent
+// channel and its threads alike.
+func (w *logWebhooks) forget(hookID discord.WebhookID) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for channelID, target := range w.hooks {
+		if target.hook.ID == hookID {
+			delete(w.hooks, channelID)
+		}
+	}
+}
+
+// findLogWebhook returns the webhook the bot made for channelID, or the
+// parent channel of a thread, creating it when there is none yet.
+func findLogWebhook(client *api.Client, channelID discord.ChannelID) (channelWebhook, error) {
+	var target channelWebhook
+	ch, err := client.Channel(channelID)
+	if err != nil {
+		return target, fmt.Errorf("findLogWebhook: %w", err)
+	}
+	parentID := channelID
+	switch ch.Type {
+	case discord.GuildPublicThread, discord.GuildPrivateThread, discord.GuildAnnouncementThread:
+		parentID, target.threadID = ch.ParentID, channelID
+	}
+
+	hooks, err := client.ChannelWebhooks(parentID)
+	if err != nil {
+		return target, fmt.Errorf("findLogWebhook: %w", err)
+	}
+	for _, hook := range hooks {
+		// Only webhooks created by an application come with a token the
+		// bot may use; ones members made by hand are left alone.
+		if hook.Name == logWebhookName && hook.Token != "" && hook.ApplicationID.IsValid() {
+			target.hook = hook
+			return target, nil
+		}
+	}
+
+	hook, err := client.CreateWebhook(parentID, api.CreateWebhookData{Name: logWebhookName})
+	if err != nil {
+		return target, fmt.Errorf("findLogWebhook: %w", err)
+	}
+	target.hook = *hook
+	return target, nil
+}
+
+// unknownWebhook reports whether err means the webhook no longer exists.
+func unknownWebhook(err error) bool {
+	var httpErr *httputil.HTTPError
+	return errors.As(err, &httpErr) && (httpErr.Status == http.StatusNotFound || httpErr.Code == 10015)
+}
@@ -0,0 +1,149 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/api/webhook"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+)
+
+func TestNotificationSenderDeliversThroughWebhooks(t *testing.T) {
+	t.Parallel()
+
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{
+		{GuildID: "1", LogWebhooks: files.LogWebhookConfig{Enabled: true, Username: "Logs", AvatarURL: "https://cdn.example.com/a.png"}},
+		{GuildID: "2"},
+	}})
+
+	sender := NewNotificationSender(nil, cfgMgr, slog.Default())
+	var botPosts int
+	sender.post = func(context.Context, discord.ChannelID, api.SendMessageData) (*discord.Message, error) {
+		botPosts++
+		return &discord.Message{}, nil
+	}
+	resolved := 0
+	var executed []webhook.ExecuteData
+	var deleted bool
+	sender.webhooks = &logWebhooks{
+		resolve: func(_ context.Context, channelID discord.ChannelID) (channelWebhook, error) {
+			resolved++
+			if channelID == 20 {
+				return channelWebhook{hook: discord.Webhook{ID: 5}, threadID: 20}, nil
+			}
+			return channelWebhook{hook: discord.Webhook{ID: 5}}, nil
+		},
+		execute: func(_ context.Context, _ discord.Webhook, data webhook.ExecuteData) error {
+			if deleted {
+				return &httputil.HTTPError{Status: http.StatusNotFound, Code: 10015}
+			}
+			executed = append(executed, data)
+			return nil
+		},
+		now:   time.Now,
+		hooks: make(map[discord.ChannelID]channelWebhook),
+	}
+
+	ctx := context.Background()
+	for _, channelID := range []discord.ChannelID{10, 10, 20} {
+		if err := sender.Send(ctx, "1", channelID, logging.LogEventMemberJoin, discord.Embed{Title: "e"}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	if botPosts != 0 || len(executed) != 3 || resolved != 2 {
+		t.Fatalf("expected cached webhook deliveries, got %d bot posts, %d executions, %d lookups", botPosts, len(executed), resolved)
+	}
+	if executed[0].Username != "Logs" || executed[0].AvatarURL != "https://cdn.example.com/a.png" || executed[2].ThreadID != 20 {
+		t.Fatalf("unexpected webhook payloads %+v", executed)
+	}
+
+	// Guilds without webhooks are posted to as the bot.
+	if err := sender.Send(ctx, "2", 30, logging.LogEventMemberJoin, discord.Embed{Title: "e"}); err != nil || botPosts != 1 {
+		t.Fatalf("expected a bot post, got %d (%v)", botPosts, err)
+	}
+
+	// A deleted webhook falls back to the bot and is looked up again next
+	// time, for the channel and its thread alike.
+	deleted = true
+	if err := sender.Send(ctx, "1", 10, logging.LogEventMemberJoin, discord.Embed{Title: "e"}); err != nil || botPosts != 2 {
+		t.Fatalf("expected a fallback bot post, got %d (%v)", botPosts, err)
+	}
+	if len(sender.webhooks.hooks) != 0 {
+		t.Fatalf("expected the deleted webhook to be forgotten, got %+v", sender.webhooks.hooks)
+	}
+}
+
+func TestLogWebhooksWaitsBeforeRetrying(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	lookups := 0
+	hooks := &logWebhooks{
+		resolve: func(context.Context, discord.ChannelID) (channelWebhook, error) {
+			lookups++
+			return channelWebhook{}, errors.New("missing permissions")
+		},
+		execute: func(context.Context, discord.Webhook, webhook.ExecuteData) error { return nil },
+		now:     func() time.Time { return now },
+		hooks:   make(map[discord.ChannelID]channelWebhook),
+	}
+
+	ctx := context.Background()
+	if err := hooks.send(ctx, 10, webhook.ExecuteData{}); err == nil || errors.Is(err, errNoWebhook) {
+		t.Fatalf("expected the lookup error, got %v", err)
+	}
+	if err := hooks.send(ctx, 10, webhook.ExecuteData{}); !errors.Is(err, errNoWebhook) || lookups != 1 {
+		t.Fatalf("expected the failure to be remembered, got %v after %d lookups", err, lookups)
+	}
+	now = now.Add(webhookRetryAfter)
+	hooks.send(ctx, 10, webhook.ExecuteData{})
+	if lookups != 2 {
+		t.Fatalf("expected a retry once the wait is over, got %d lookups", lookups)
+	}
+}
+
+func TestNotificationSenderKeepsTransientWebhookFailures(t *testing.T) {
+	t.Parallel()
+
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{
+		{GuildID: "1", LogWebhooks: files.LogWebhookConfig{Enabled: true}},
+	}})
+
+	sender := NewNotificationSender(nil, cfgMgr, slog.Default())
+	var botPosts int
+	sender.post = func(context.Context, discord.ChannelID, api.SendMessageData) (*discord.Message, error) {
+		botPosts++
+		return &discord.Message{}, nil
+	}
+	failure := error(&httputil.HTTPError{Status: http.StatusTooManyRequests})
+	sender.webhooks = &logWebhooks{
+		resolve: func(context.Context, discord.ChannelID) (channelWebhook, error) {
+			return channelWebhook{hook: discord.Webhook{ID: 5}}, nil
+		},
+		execute: func(context.Context, discord.Webhook, webhook.ExecuteData) error { return failure },
+		now:     time.Now,
+		hooks:   make(map[discord.ChannelID]channelWebhook),
+	}
+
+	ctx := context.Background()
+	for _, err := range []error{failure, context.DeadlineExceeded} {
+		failure = err
+		if err := sender.Send(ctx, "1", 10, logging.LogEventMemberJoin, discord.Embed{Title: "e"}); err == nil {
+			t.Fatalf("expected the webhook failure %v to be returned", failure)
+		}
+	}
+	if botPosts != 0 {
+		t.Fatalf("expected no bot posts for transient webhook failures, got %d", botPosts)
+	}
+}
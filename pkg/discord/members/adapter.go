@@ -75,3 +75,15 @@ func (a *ArikawaAdapter) RemoveRole(ctx context.Context, guildID, userID, roleID
 	}
 	return a.state.RemoveRole(discord.GuildID(gID), discord.UserID(uID), discord.RoleID(rID), "automated role removal")
 }
+
+func (a *ArikawaAdapter) Kick(ctx context.Context, guildID, userID, reason string) error {
+	gID, err := discord.ParseSnowflake(guildID)
+	if err != nil {
+		return err
+	}
+	uID, err := discord.ParseSnowflake(userID)
+	if err != nil {
+		return err
+	}
+	return a.state.Kick(discord.GuildID(gID), discord.UserID(uID), api.AuditLogReason(reason))
+}
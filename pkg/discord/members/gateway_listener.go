@@ -7,6 +7,7 @@ import (
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
 	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
 	"github.com/small-frappuccino/discordcore/pkg/members"
 	"github.com/small-frappuccino/discordcore/pkg/service"
 )
@@ -43,9 +44,9 @@ func NewGatewayListener(s *state.State, memberSvc *members.MemberEventService) *
 
 // Start registers the Arikawa event handlers.
 func (l *GatewayListener) Start(ctx context.Context) error {
-	l.cancelMemberAdd = l.state.AddHandler(l.handleMemberAdd)
-	l.cancelMemberRemove = l.state.AddHandler(l.handleMemberRemove)
-	l.cancelMemberUpdate = l.state.PreHandler.AddSyncHandler(l.handleMemberUpdate)
+	l.cancelMemberAdd = l.state.AddHandler(perf.GuardGatewayHandler("members.member_add", l.handleMemberAdd))
+	l.cancelMemberRemove = l.state.AddHandler(perf.GuardGatewayHandler("members.member_remove", l.handleMemberRemove))
+	l.cancelMemberUpdate = l.state.PreHandler.AddSyncHandler(perf.GuardGatewayHandler("members.member_update", l.handleMemberUpdate))
 
 	l.wg.Add(1)
 	go l.worker()
@@ -62,13 +63,16 @@ func (l *GatewayListener) handleMemberAdd(e *gateway.GuildMemberAddEvent) {
 		roles[i] = r.String()
 	}
 	intent := members.MemberJoinIntent{
-		GuildID:    e.GuildID.String(),
-		UserID:     e.User.ID.String(),
-		Username:   e.User.Username,
-		Bot:        e.User.Bot,
-		AvatarHash: e.User.Avatar,
-		RoleIDs:    roles,
-		JoinedAt:   e.Joined.Time(),
+		GuildID:       e.GuildID.String(),
+		UserID:        e.User.ID.String(),
+		Username:      e.User.Username,
+		GlobalName:    e.User.DisplayName,
+		Nick:          e.Nick,
+		Discriminator: e.User.Discriminator,
+		Bot:           e.User.Bot,
+		AvatarHash:    e.User.Avatar,
+		RoleIDs:       roles,
+		JoinedAt:      e.Joined.Time(),
 	}
 	l.memberService.IngestGuildMemberAdd(l.ctx, intent)
 }
@@ -78,11 +82,13 @@ func (l *GatewayListener) handleMemberRemove(e *gateway.GuildMemberRemoveEvent)
 		return
 	}
 	intent := members.MemberLeaveIntent{
-		GuildID:    e.GuildID.String(),
-		UserID:     e.User.ID.String(),
-		Username:   e.User.Username,
-		Bot:        e.User.Bot,
-		AvatarHash: e.User.Avatar,
+		GuildID:       e.GuildID.String(),
+		UserID:        e.User.ID.String(),
+		Username:      e.User.Username,
+		GlobalName:    e.User.DisplayName,
+		Discriminator: e.User.Discriminator,
+		Bot:           e.User.Bot,
+		AvatarHash:    e.User.Avatar,
 	}
 	l.memberService.IngestGuildMemberRemove(l.ctx, intent)
 }
@@ -115,12 +121,15 @@ func (l *GatewayListener) worker() {
 		}
 
 		intent := members.MemberUpdateIntent{
-			GuildID:    e.GuildID.String(),
-			UserID:     e.User.ID.String(),
-			Username:   e.User.Username,
-			Bot:        e.User.Bot,
-			RoleIDs:    roles,
-			AvatarHash: e.User.Avatar,
+			GuildID:       e.GuildID.String(),
+			UserID:        e.User.ID.String(),
+			Username:      e.User.Username,
+			GlobalName:    e.User.DisplayName,
+			Nick:          e.Nick,
+			Discriminator: e.User.Discriminator,
+			Bot:           e.User.Bot,
+			RoleIDs:       roles,
+			AvatarHash:    e.User.Avatar,
 		}
 
 		if payload.hasOldMember {
@@ -148,6 +157,7 @@ func (l *GatewayListener) Stop(ctx context.Context) error {
 	if l.cancelMemberUpdate != nil {
 		l.cancelMemberUpdate()
 	}
+	l.cancelMemberAdd, l.cancelMemberRemove, l.cancelMemberUpdate = nil, nil, nil
 
 	if l.updateQueue != nil {
 		close(l.updateQueue)
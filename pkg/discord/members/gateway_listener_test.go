@@ -76,6 +76,10 @@ func (m *mockMembersRepo) UpsertMemberJoinContext(ctx context.Context, guildID,
 	return nil
 }
 
+func (m *mockMembersRepo) MarkMemberLeftContext(ctx context.Context, guildID, userID string, at time.Time) error {
+	return nil
+}
+
 func (m *mockMembersRepo) MemberJoin(ctx context.Context, guildID, userID string) (time.Time, bool, error) {
 	return time.Time{}, false, nil
 }
@@ -3,8 +3,10 @@ package messages
 import (
 	"context"
 
+	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/gateway"
 	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
 	"github.com/small-frappuccino/discordcore/pkg/messages"
 	"github.com/small-frappuccino/discordcore/pkg/service"
 )
@@ -31,9 +33,9 @@ func NewGatewayListener(s *state.State, msgSvc *messages.MessageEventService) *G
 
 // Start registers the Arikawa event handlers.
 func (l *GatewayListener) Start(ctx context.Context) error {
-	l.cancelCreate = l.state.AddHandler(l.handleMessageCreate)
-	l.cancelUpdate = l.state.AddHandler(l.handleMessageUpdate)
-	l.cancelDelete = l.state.AddHandler(l.handleMessageDelete)
+	l.cancelCreate = l.state.AddHandler(perf.GuardGatewayHandler("messages.message_create", l.handleMessageCreate))
+	l.cancelUpdate = l.state.AddHandler(perf.GuardGatewayHandler("messages.message_update", l.handleMessageUpdate))
+	l.cancelDelete = l.state.AddHandler(perf.GuardGatewayHandler("messages.message_delete", l.handleMessageDelete))
 	return nil
 }
 
@@ -49,11 +51,47 @@ func (l *GatewayListener) handleMessageCreate(e *gateway.MessageCreateEvent) {
 		AuthorUsername: e.Author.Username,
 		AuthorBot:      e.Author.Bot,
 		Content:        e.Content,
+		CategoryID:     l.categoryOf(e.ChannelID),
+		Attachments:    len(e.Attachments),
 		Timestamp:      e.Timestamp.Time(),
 	}
+	for _, att := range e.Attachments {
+		intent.Files = append(intent.Files, messages.MessageAttachment{
+			Filename:    att.Filename,
+			ContentType: att.ContentType,
+			Size:        att.Size,
+			URL:         att.URL,
+		})
+	}
+	if e.Member != nil {
+		intent.AuthorRoleIDs = make([]string, len(e.Member.RoleIDs))
+		for i, roleID := range e.Member.RoleIDs {
+			intent.AuthorRoleIDs[i] = roleID.String()
+		}
+	}
 	l.messageService.IngestMessageCreate(l.ctx, intent)
 }
 
+// categoryOf returns the category of channelID from the state cache,
+// looking through a thread to its parent channel. It is empty when the
+// channel is not cached or has no category.
+func (l *GatewayListener) categoryOf(channelID discord.ChannelID) string {
+	ch, err := l.state.Cabinet.Channel(channelID)
+	if err != nil {
+		return ""
+	}
+	switch ch.Type {
+	case discord.GuildAnnouncementThread, discord.GuildPublicThread, discord.GuildPrivateThread:
+		if ch, err = l.state.Cabinet.Channel(ch.ParentID); err != nil {
+			return ""
+		}
+	}
+	if !ch.ParentID.IsValid() {
+		return ""
+	}
+	return ch.ParentID.String()
+}
+
 func (l *GatewayListener) handleMessageUpdate(e *gateway.MessageUpdateEvent) {
 	if !e.ID.IsValid() || !e.GuildID.IsValid() || !e.ChannelID.IsValid() {
 		return
@@ -90,6 +128,7 @@ func (l *GatewayListener) Stop(ctx context.Context) error {
 	if l.cancelDelete != nil {
 		l.cancelDelete()
 	}
+	l.cancelCreate, l.cancelUpdate, l.cancelDelete = nil, nil, nil
 	return nil
 }
 
@@ -0,0 +1,289 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/permissions"
+)
+
+// DefaultGuildContextTTL bounds how long a resolved guild context is reused
+// before roles and the bot member are fetched again.
+const DefaultGuildContextTTL = 2 * time.Minute
+
+var (
+	// ErrTargetIsOwner is returned when the target owns the guild.
+	ErrTargetIsOwner = errors.New("target is the guild owner")
+	// ErrActorOutranked is returned when the actor's highest role does not sit
+	// above the target's.
+	ErrActorOutranked = errors.New("actor does not outrank target")
+	// ErrBotOutranked is returned when the bot's highest role does not sit
+	// above the target's.
+	ErrBotOutranked = errors.New("bot does not outrank target")
+	// ErrTargetProtected is returned when the guild has protected the target
+	// from moderation.
+	ErrTargetProtected = errors.New("target is protected")
+	// ErrNoGuildContexts is returned when a Service has no guild contexts to
+	// check an action against. The action is refused rather than permitted
+	// unchecked.
+	ErrNoGuildContexts = errors.New("guild contexts are not attached")
+)
+
+// GuildContextSource defines the Discord reads a GuildContextCache needs.
+// *api.Client satisfies it.
+type GuildContextSource interface {
+	Guild(guildID discord.GuildID) (*discord.Guild, error)
+	Member(guildID discord.GuildID, userID discord.UserID) (*discord.Member, error)
+	Me() (*discord.User, error)
+}
+
+// GuildBotSource is implemented by a GuildContextSource serving guilds
+// through different bots. The cache then asks it for the bot of each guild
+// instead of keeping the one Me returns.
+type GuildBotSource interface {
+	GuildBot(guildID discord.GuildID) (*discord.User, error)
+}
+
+// GuildModerationContext is the guild state every hierarchy and permission
+// decision is made against: the role table, the owner and the bot member.
+type GuildModerationContext struct {
+	GuildID     discord.GuildID
+	OwnerID     discord.UserID
+	Roles       map[string]coremod.Role
+	Bot         *coremod.Member
+	BotPosition int
+	ResolvedAt  time.Time
+}
+
+// Position returns the highest role position held by member.
+func (g *GuildModerationContext) Position(member *coremod.Member) int {
+	return coremod.HighestRolePosition(member, g.GuildID.String(), g.Roles)
+}
+
+// BotPermissions returns the bot's guild-level permissions.
+func (g *GuildModerationContext) BotPermissions() int64 {
+	return permissions.Base(g.Bot, g.GuildID.String(), g.OwnerID.String(), g.Roles)
+}
+
+// CheckHierarchy reports whether actor may act on target, and whether the bot
+// can carry the action out. The guild owner may act on anyone and can never be
+// targeted. A nil target, such as a user who already left, only needs to be
+// someone other than the owner.
+func (g *GuildModerationContext) CheckHierarchy(actor, target *coremod.Member) error {
+	if target != nil && target.UserID == g.OwnerID.String() {
+		return ErrTargetIsOwner
+	}
+	if target == nil {
+		return nil
+	}
+	if actor == nil || actor.UserID != g.OwnerID.String() {
+		if !coremod.CanModerate(actor, target, g.GuildID.String(), g.Roles) {
+			return ErrActorOutranked
+		}
+	}
+	if g.Bot != nil && g.Bot.UserID != g.OwnerID.String() && g.BotPosition <= g.Position(target) {
+		return ErrBotOutranked
+	}
+	return nil
+}
+
+// memberBatchSize bounds the users handed to one MemberBatchResolver call, so
+// a mass action over tens of thousands of IDs is resolved chunk by chunk.
+const memberBatchSize = 1000
+
+// MemberBatchResolver resolves many members of one guild in a single call,
+// omitting users who are not members. *cache.CachedSession satisfies it.
+type MemberBatchResolver interface {
+	ResolveMembers(ctx context.Context, guildID string, ids []string) (map[string]*discord.Member, error)
+}
+
+type guildContextEntry struct {
+	ctx     *GuildModerationContext
+	expires time.Time
+}
+
+// GuildContextCache resolves GuildModerationContext values and keeps each one
+// for a TTL, so a burst of moderation actions in one guild costs a single set
+// of REST reads.
+//
+// Goroutine safety: every method is safe to call concurrently.
+type GuildContextCache struct {
+	src   GuildContextSource
+	batch MemberBatchResolver
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu      sync.Mutex
+	entries map[discord.GuildID]guildContextEntry
+	botID   discord.UserID
+}
+
+// NewGuildContextCache creates a cache over src. A non-positive ttl selects
+// DefaultGuildContextTTL.
+func NewGuildContextCache(src GuildContextSource, ttl time.Duration) *GuildContextCache {
+	if ttl <= 0 {
+		ttl = DefaultGuildContextTTL
+	}
+	return &GuildContextCache{
+		src:     src,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[discord.GuildID]guildContextEntry),
+	}
+}
+
+// Get returns the moderation context for guildID, resolving it when the
+// cached copy is missing or stale.
+func (c *GuildContextCache) Get(ctx context.Context, guildID discord.GuildID) (*GuildModerationContext, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	now := c.now()
+	c.mu.Lock()
+	entry, ok := c.entries[guildID]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.ctx, nil
+	}
+
+	resolved, err := c.resolve(guildID, now)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.entries[guildID] = guildContextEntry{ctx: resolved, expires: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return resolved, nil
+}
+
+// WithBatchResolver routes Members through batch instead of one Member call
+// per user.
+func (c *GuildContextCache) WithBatchResolver(batch MemberBatchResolver) *GuildContextCache {
+	c.batch = batch
+	return c
+}
+
+// Invalidate drops the cached context for guildID, e.g. after a role update.
+func (c *GuildContextCache) Invalidate(guildID discord.GuildID) {
+	c.mu.Lock()
+	delete(c.entries, guildID)
+	c.mu.Unlock()
+}
+
+// Member fetches userID's current membership. Members are never cached here;
+// their roles change far more often than the guild's role table.
+func (c *GuildContextCache) Member(guildID discord.GuildID, userID discord.UserID) (*coremod.Member, error) {
+	member, err := c.src.Member(guildID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("GuildContextCache.Member: %w", err)
+	}
+	member.User.ID = userID
+	return permissions.MemberFromDiscord(*member), nil
+}
+
+// Members fetches the current membership of every user in ids. Users who are
+// not guild members are absent from the result. With a batch resolver, ids
+// are resolved memberBatchSize at a time.
+func (c *GuildContextCache) Members(ctx context.Context, guildID discord.GuildID, ids []discord.UserID) (map[discord.UserID]*coremod.Member, error) {
+	out := make(map[discord.UserID]*coremod.Member, len(ids))
+	if c.batch != nil {
+		for chunk := range slices.Chunk(ids, memberBatchSize) {
+			raw := make([]string, 0, len(chunk))
+			for _, id := range chunk {
+				raw = append(raw, id.String())
+			}
+			resolved, err := c.batch.ResolveMembers(ctx, guildID.String(), raw)
+			if err != nil {
+				return nil, fmt.Errorf("GuildContextCache.Members: %w", err)
+			}
+			for _, id := range chunk {
+				if member, ok := resolved[id.String()]; ok {
+					m := *member
+					m.User.ID = id
+					out[id] = permissions.MemberFromDiscord(m)
+				}
+			}
+		}
+		return out, nil
+	}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		member, err := c.Member(guildID, id)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("GuildContextCache.Members: %w", err)
+		}
+		out[id] = member
+	}
+	return out, nil
+}
+
+func isNotFound(err error) bool {
+	var httpErr *httputil.HTTPError
+	return errors.As(err, &httpErr) && httpErr.Status == http.StatusNotFound
+}
+
+func (c *GuildContextCache) resolve(guildID discord.GuildID, now time.Time) (*GuildModerationContext, error) {
+	guild, err := c.src.Guild(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("GuildContextCache.resolve: fetch guild: %w", err)
+	}
+	botID, err := c.selfID(guildID)
+	if err != nil {
+		return nil, err
+	}
+	bot, err := c.Member(guildID, botID)
+	if err != nil {
+		return nil, fmt.Errorf("GuildContextCache.resolve: fetch bot member: %w", err)
+	}
+
+	resolved := &GuildModerationContext{
+		GuildID:    guildID,
+		OwnerID:    guild.OwnerID,
+		Roles:      permissions.RolesFromDiscord(guild.Roles),
+		Bot:        bot,
+		ResolvedAt: now,
+	}
+	resolved.BotPosition = resolved.Position(bot)
+	return resolved, nil
+}
+
+func (c *GuildContextCache) selfID(guildID discord.GuildID) (discord.UserID, error) {
+	if src, ok := c.src.(GuildBotSource); ok {
+		bot, err := src.GuildBot(guildID)
+		if err != nil {
+			return 0, fmt.Errorf("GuildContextCache.selfID: %w", err)
+		}
+		return bot.ID, nil
+	}
+	c.mu.Lock()
+	id := c.botID
+	c.mu.Unlock()
+	if id.IsValid() {
+		return id, nil
+	}
+	me, err := c.src.Me()
+	if err != nil {
+		return 0, fmt.Errorf("GuildContextCache.selfID: %w", err)
+	}
+	c.mu.Lock()
+	c.botID = me.ID
+	c.mu.Unlock()
+	return me.ID, nil
+}
@@ -0,0 +1,316 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+type fakeContextSource struct {
+	guild        discord.Guild
+	members      map[discord.UserID]discord.Member
+	guildFetches int
+}
+
+func (f *fakeContextSource) Guild(guildID discord.GuildID) (*discord.Guild, error) {
+	f.guildFetches++
+	g := f.guild
+	return &g, nil
+}
+
+func (f *fakeContextSource) Member(guildID discord.GuildID, userID discord.UserID) (*discord.Member, error) {
+	m, ok := f.members[userID]
+	if !ok {
+		return nil, &httputil.HTTPError{Status: http.StatusNotFound}
+	}
+	return &m, nil
+}
+
+func (f *fakeContextSource) Me() (*discord.User, error) {
+	return &discord.User{ID: 9}, nil
+}
+
+func newFakeContextSource() *fakeContextSource {
+	const guildID = 100
+	return &fakeContextSource{
+		guild: discord.Guild{
+			ID:      guildID,
+			OwnerID: 1,
+			Roles: []discord.Role{
+				{ID: guildID, Position: 0},
+				{ID: 10, Position: 1},
+				{ID: 20, Position: 2},
+				{ID: 30, Position: 3},
+			},
+		},
+		members: map[discord.UserID]discord.Member{
+			1: {},
+			2: {RoleIDs: []discord.RoleID{30}},
+			3: {RoleIDs: []discord.RoleID{10}},
+			4: {RoleIDs: []discord.RoleID{20}},
+			9: {RoleIDs: []discord.RoleID{20}},
+		},
+	}
+}
+
+func TestGuildContextCache_ReusesUntilExpiry(t *testing.T) {
+	t.Parallel()
+	src := newFakeContextSource()
+	cache := NewGuildContextCache(src, time.Minute)
+	now := time.Unix(1_700_000_000, 0)
+	cache.now = func() time.Time { return now }
+
+	first, err := cache.Get(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if first.BotPosition != 2 || first.OwnerID != 1 {
+		t.Fatalf("unexpected context: %+v", first)
+	}
+	if _, err := cache.Get(context.Background(), 100); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if src.guildFetches != 1 {
+		t.Fatalf("expected cached context, got %d guild fetches", src.guildFetches)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := cache.Get(context.Background(), 100); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	cache.Invalidate(100)
+	if _, err := cache.Get(context.Background(), 100); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if src.guildFetches != 3 {
+		t.Fatalf("expected refetch after expiry and invalidation, got %d guild fetches", src.guildFetches)
+	}
+}
+
+func TestService_Authorize(t *testing.T) {
+	t.Parallel()
+	svc := NewService(nil, nil).WithGuildContexts(NewGuildContextCache(newFakeContextSource(), 0))
+
+	tests := []struct {
+		name   string
+		actor  discord.UserID
+		target discord.UserID
+		want   error
+	}{
+		{name: "higher actor, lower target", actor: 2, target: 3},
+		{name: "owner bypasses hierarchy", actor: 1, target: 3},
+		{name: "target is owner", actor: 2, target: 1, want: ErrTargetIsOwner},
+		{name: "equal roles", actor: 4, target: 9, want: ErrActorOutranked},
+		{name: "lower actor", actor: 3, target: 2, want: ErrActorOutranked},
+		{name: "bot outranked", actor: 2, target: 4, want: ErrBotOutranked},
+		{name: "non-member target", actor: 3, target: 77},
+	}
+	for _, tt := range tests {
+		err := svc.Authorize(context.Background(), 100, tt.actor, tt.target, coremod.ProtectedTargets{})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestService_AuthorizeMany(t *testing.T) {
+	t.Parallel()
+	svc := NewService(nil, nil).WithGuildContexts(NewGuildContextCache(newFakeContextSource(), 0))
+
+	denied, err := svc.AuthorizeMany(context.Background(), 100, 2, []discord.UserID{1, 3, 4, 77}, coremod.ProtectedTargets{})
+	if err != nil {
+		t.Fatalf("AuthorizeMany: %v", err)
+	}
+	if len(denied) != 2 || !errors.Is(denied[1], ErrTargetIsOwner) || !errors.Is(denied[4], ErrBotOutranked) {
+		t.Fatalf("unexpected denials: %v", denied)
+	}
+}
+
+// countingBatch resolves every requested user as a member and records the
+// size of each call.
+type countingBatch struct {
+	calls []int
+}
+
+func (b *countingBatch) ResolveMembers(_ context.Context, _ string, ids []string) (map[string]*discord.Member, error) {
+	b.calls = append(b.calls, len(ids))
+	out := make(map[string]*discord.Member, len(ids))
+	for _, id := range ids {
+		out[id] = &discord.Member{RoleIDs: []discord.RoleID{10}}
+	}
+	return out, nil
+}
+
+func TestGuildContextCache_MembersBatchesPerChunk(t *testing.T) {
+	t.Parallel()
+	batch := &countingBatch{}
+	cache := NewGuildContextCache(newFakeContextSource(), 0).WithBatchResolver(batch)
+
+	ids := make([]discord.UserID, 2*memberBatchSize+1)
+	for i := range ids {
+		ids[i] = discord.UserID(1000 + i)
+	}
+	members, err := cache.Members(context.Background(), 100, ids)
+	if err != nil {
+		t.Fatalf("Members: %v", err)
+	}
+	if want := []int{memberBatchSize, memberBatchSize, 1}; !slices.Equal(batch.calls, want) {
+		t.Fatalf("batch calls = %v, want %v", batch.calls, want)
+	}
+	if len(members) != len(ids) || members[ids[0]].UserID != ids[0].String() {
+		t.Fatalf("expected every member resolved with its ID, got %d", len(members))
+	}
+}
+
+func TestService_AuthorizeProtectedTargets(t *testing.T) {
+	t.Parallel()
+	svc := NewService(nil, nil).WithGuildContexts(NewGuildContextCache(newFakeContextSource(), 0))
+	protected := coremod.ProtectedTargets{UserIDs: []string{"77"}, RoleIDs: []string{"10"}}
+
+	tests := []struct {
+		name   string
+		actor  discord.UserID
+		target discord.UserID
+		want   error
+	}{
+		{name: "holder of a protected role", actor: 2, target: 3, want: ErrTargetProtected},
+		{name: "protected non-member", actor: 2, target: 77, want: ErrTargetProtected},
+		{name: "owner overrides protection", actor: 1, target: 3},
+		{name: "unprotected member", actor: 2, target: 78},
+	}
+	for _, tt := range tests {
+		err := svc.Authorize(context.Background(), 100, tt.actor, tt.target, protected)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	denied, err := svc.AuthorizeMany(context.Background(), 100, 2, []discord.UserID{3, 4, 77, 78}, protected)
+	if err != nil {
+		t.Fatalf("AuthorizeMany: %v", err)
+	}
+	if len(denied) != 3 || !errors.Is(denied[3], ErrTargetProtected) || !errors.Is(denied[77], ErrTargetProtected) || !errors.Is(denied[4], ErrBotOutranked) {
+		t.Fatalf("unexpected denials: %v", denied)
+	}
+}
+
+func TestService_ReserveActionsLocksOutModerator(t *testing.T) {
+	t.Parallel()
+	var alerts int
+	svc := NewService(nil, nil).
+		WithGuildContexts(NewGuildContextCache(newFakeContextSource(), 0)).
+		WithRateGuard(coremod.NewActionRateGuard(), func(discord.GuildID, discord.UserID, time.Time) { alerts++ })
+	limit := coremod.ActionRateLimit{Max: 2, Window: time.Minute}
+
+	if err := svc.ReserveActions(context.Background(), 100, 2, 2, limit); err != nil {
+		t.Fatalf("ReserveActions: %v", err)
+	}
+	if err := svc.ReserveActions(context.Background(), 100, 2, 1, limit); !errors.Is(err, coremod.ErrActionRateExceeded) {
+		t.Fatalf("expected rate error, got %v", err)
+	}
+	if err := svc.Authorize(context.Background(), 100, 2, 3, coremod.ProtectedTargets{}); !errors.Is(err, coremod.ErrActionRateExceeded) {
+		t.Fatalf("expected locked out moderator to be refused, got %v", err)
+	}
+	if alerts != 1 {
+		t.Fatalf("expected one alert, got %d", alerts)
+	}
+	if err := svc.ReserveActions(context.Background(), 100, 1, 50, limit); err != nil {
+		t.Fatalf("owner must bypass the limit: %v", err)
+	}
+}
+
+func TestService_ReserveActionsGuardsByDefault(t *testing.T) {
+	t.Parallel()
+	svc := NewService(nil, nil).WithGuildContexts(NewGuildContextCache(newFakeContextSource(), 0))
+	limit := coremod.ActionRateLimit{Max: 1, Window: time.Minute}
+
+	if err := svc.ReserveActions(context.Background(), 100, 2, 1, limit); err != nil {
+		t.Fatalf("ReserveActions: %v", err)
+	}
+	if err := svc.ReserveActions(context.Background(), 100, 2, 1, limit); !errors.Is(err, coremod.ErrActionRateExceeded) {
+		t.Fatalf("expected the default guard to lock the moderator out, got %v", err)
+	}
+}
+
+func TestService_ReserveActionsWithoutContextsRefuses(t *testing.T) {
+	t.Parallel()
+	svc := NewService(nil, nil)
+	limit := coremod.ActionRateLimit{Max: 1, Window: time.Minute}
+
+	if err := svc.ReserveActions(context.Background(), 100, 2, 1, limit); !errors.Is(err, ErrNoGuildContexts) {
+		t.Fatalf("ReserveActions without contexts = %v, want ErrNoGuildContexts", err)
+	}
+}
+
+func TestService_ReleaseActionsHandsBackFailedActions(t *testing.T) {
+	t.Parallel()
+	svc := NewService(nil, nil).WithGuildContexts(NewGuildContextCache(newFakeContextSource(), 0))
+	limit := coremod.ActionRateLimit{Max: 2, Window: time.Minute}
+
+	for range 4 {
+		if err := svc.ReserveActions(context.Background(), 100, 2, 1, limit); err != nil {
+			t.Fatalf("ReserveActions after releasing the failed action: %v", err)
+		}
+		svc.ReleaseActions(100, 2, 1)
+	}
+	if _, locked := svc.LockedOut(100, 2); locked {
+		t.Fatal("released actions must not lock the moderator out")
+	}
+	for range 2 {
+		if err := svc.ReserveActions(context.Background(), 100, 2, 1, limit); err != nil {
+			t.Fatalf("ReserveActions: %v", err)
+		}
+	}
+	if err := svc.ReserveActions(context.Background(), 100, 2, 1, limit); !errors.Is(err, coremod.ErrActionRateExceeded) {
+		t.Fatalf("expected the kept actions to count, got %v", err)
+	}
+	if _, locked := svc.LockedOut(100, 2); !locked {
+		t.Fatal("expected LockedOut to report the lockout")
+	}
+}
+
+func TestService_AuthorizeWithoutContextsRefuses(t *testing.T) {
+	t.Parallel()
+	svc := NewService(nil, nil)
+
+	if err := svc.Authorize(context.Background(), 100, 2, 3, coremod.ProtectedTargets{}); !errors.Is(err, ErrNoGuildContexts) {
+		t.Fatalf("Authorize without contexts = %v, want ErrNoGuildContexts", err)
+	}
+	if _, err := svc.AuthorizeMany(context.Background(), 100, 2, []discord.UserID{3}, coremod.ProtectedTargets{}); !errors.Is(err, ErrNoGuildContexts) {
+		t.Fatalf("AuthorizeMany without contexts = %v, want ErrNoGuildContexts", err)
+	}
+}
+
+type guildBotSource struct {
+	*fakeContextSource
+	bots map[discord.GuildID]discord.UserID
+}
+
+func (s guildBotSource) Me() (*discord.User, error) {
+	return nil, errors.New("no single bot")
+}
+
+func (s guildBotSource) GuildBot(guildID discord.GuildID) (*discord.User, error) {
+	return &discord.User{ID: s.bots[guildID]}, nil
+}
+
+func TestGuildContextCache_BotPerGuild(t *testing.T) {
+	t.Parallel()
+	cache := NewGuildContextCache(guildBotSource{fakeContextSource: newFakeContextSource(), bots: map[discord.GuildID]discord.UserID{100: 2}}, 0)
+
+	gctx, err := cache.Get(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if gctx.BotPosition != 3 {
+		t.Fatalf("expected the position of the guild's own bot, got %d", gctx.BotPosition)
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
 )
 
 // ModerationLogPayload defines the cross-boundary data structure
@@ -28,7 +31,7 @@ type ModerationLogPayload struct {
 func BuildModerationEmbed(payload ModerationLogPayload, color discord.Color, timestamp time.Time) discord.Embed {
 	action := strings.TrimSpace(payload.Action)
 	targetID := strings.TrimSpace(payload.TargetID)
-	targetLabel := strings.TrimSpace(payload.TargetLabel)
+	targetLabel := logging.EscapeUserText(strings.TrimSpace(payload.TargetLabel))
 
 	targetValue := "Unknown"
 	switch {
@@ -40,7 +43,7 @@ func BuildModerationEmbed(payload ModerationLogPayload, color discord.Color, tim
 		targetValue = fmt.Sprintf("**%s** (<@%s>, `%s`)", targetLabel, targetID, targetID)
 	}
 
-	reason := strings.TrimSpace(payload.Reason)
+	reason := logging.EscapeUserText(strings.TrimSpace(payload.Reason))
 	if reason == "" {
 		reason = "No reason provided"
 	}
@@ -49,6 +52,10 @@ func BuildModerationEmbed(payload ModerationLogPayload, color discord.Color, tim
 		{Name: "Action", Value: action, Inline: true},
 	}
 
+	if payload.CaseNumber > 0 {
+		fields = append(fields, discord.EmbedField{Name: "Case", Value: fmt.Sprintf("#%d", payload.CaseNumber), Inline: true})
+	}
+
 	if payload.CaseID != "" {
 		fields = append(fields, discord.EmbedField{Name: "Case ID", Value: "`" + payload.CaseID + "`", Inline: true})
 	}
@@ -93,3 +100,33 @@ func BuildModerationEmbed(payload ModerationLogPayload, color discord.Color, tim
 		Timestamp:   discord.NewTimestamp(timestamp),
 	}
 }
+
+// LockoutEmbed renders the alert posted to the moderation case channel when
+// moderatorID exceeds limit and is locked out until until.
+func LockoutEmbed(moderatorID discord.UserID, limit coremod.ActionRateLimit, until time.Time) discord.Embed {
+	window := limit.Window
+	if window <= 0 {
+		window = coremod.DefaultActionRateWindow
+	}
+	return discord.Embed{
+		Title:       "Moderator locked out",
+		Description: fmt.Sprintf("<@%s> went over this server's limit of %d bans and kicks per %s. Their moderation actions are paused until %s. The bot owner can lift the pause early with `/admin lift-lockout`.", moderatorID, limit.Max, window, logging.DiscordTimestamp(until, logging.TimestampRelative)),
+		Color:       discord.Color(theme.Danger()),
+		Fields: []discord.EmbedField{
+			{Name: "Moderator", Value: fmt.Sprintf("<@%s> (`%s`)", moderatorID, moderatorID), Inline: true},
+			{Name: "Paused until", Value: logging.DiscordTimestamp(until, logging.TimestampLongDateTime), Inline: true},
+		},
+		Timestamp: discord.NowTimestamp(),
+	}
+}
+
+// MarkTestMode heads embed with the test mode banner, so a simulated action
+// is not mistaken for a real one in the log.
+func MarkTestMode(embed *discord.Embed) {
+	embed.Title = "[TEST] " + embed.Title
+	if embed.Description == "" {
+		embed.Description = coremod.TestModeBanner
+		return
+	}
+	embed.Description = coremod.TestModeBanner + "\n\n" + embed.Description
+}
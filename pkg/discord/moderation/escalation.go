@@ -0,0 +1,37 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// EscalationReason is the audit log reason of an escalation triggered by
+// reaching count warnings.
+func EscalationReason(count int) string {
+	return fmt.Sprintf("Automatic escalation after %d warnings", count)
+}
+
+// Escalate applies the warning escalation step a member reached at count
+// warnings, whoever issued the warning. Bans delete deleteDays of messages.
+// It returns when a timeout ends, or the zero time for kicks and bans.
+// Reserving the action against a moderator's limit is left to the caller.
+func (s *Service) Escalate(ctx context.Context, guildID discord.GuildID, userID discord.UserID, count int, step files.WarningEscalationStep, deleteDays int) (time.Time, error) {
+	reason := EscalationReason(count)
+	switch step.Action {
+	case files.WarningEscalationTimeout:
+		until := time.Now().Add(time.Duration(step.DurationMinutes) * time.Minute)
+		return until, s.Timeout(ctx, guildID, userID, discord.NewTimestamp(until))
+	case files.WarningEscalationKick:
+		return time.Time{}, s.Kick(ctx, guildID, userID, api.AuditLogReason(reason))
+	case files.WarningEscalationBan:
+		return time.Time{}, s.Ban(ctx, guildID, userID, deleteDays*86400, reason)
+	default:
+		return time.Time{}, fmt.Errorf("Service.Escalate: unknown action %q", step.Action)
+	}
+}
@@ -0,0 +1,40 @@
+package moderation
+
+import (
+	"fmt"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+// ExpiryEmbed renders the follow-up posted to the moderation case channel
+// when the timed action of c ends on its own. It links back to the case's log
+// embed when one was posted, so the channel shows the whole punishment.
+func ExpiryEmbed(c coremod.Case) discord.Embed {
+	title, what := "Punishment ended", c.Action
+	switch c.Action {
+	case "timeout", "automod_timeout":
+		title, what = "Timeout ended", "timeout"
+	case coremod.CaseActionBan:
+		title, what = "Ban lifted", "ban"
+	}
+
+	caseRef := fmt.Sprintf("case #%d", c.CaseNumber)
+	if c.LogChannelID != "" && c.LogMessageID != "" {
+		caseRef = fmt.Sprintf("[%s](https://discord.com/channels/%s/%s/%s)", caseRef, c.GuildID, c.LogChannelID, c.LogMessageID)
+	}
+	return discord.Embed{
+		Title:       title,
+		Description: fmt.Sprintf("The %s of <@%s> from %s expired.", what, c.UserID, caseRef),
+		Color:       discord.Color(theme.Success()),
+		Fields: []discord.EmbedField{
+			{Name: "Case", Value: fmt.Sprintf("#%d", c.CaseNumber), Inline: true},
+			{Name: "Member", Value: fmt.Sprintf("<@%s> (`%s`)", c.UserID, c.UserID), Inline: true},
+			{Name: "Issued", Value: logging.DiscordTimestamp(c.CreatedAt, logging.TimestampLongDateTime), Inline: true},
+		},
+		Timestamp: discord.NewTimestamp(c.ExpiresAt),
+	}
+}
@@ -0,0 +1,35 @@
+package moderation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+func TestExpiryEmbed(t *testing.T) {
+	t.Parallel()
+	c := coremod.Case{
+		GuildID:      "g1",
+		CaseNumber:   12,
+		Action:       "timeout",
+		UserID:       "u1",
+		LogChannelID: "c1",
+		LogMessageID: "m1",
+		CreatedAt:    time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+		ExpiresAt:    time.Date(2026, 10, 16, 13, 0, 0, 0, time.UTC),
+	}
+	embed := ExpiryEmbed(c)
+	if embed.Title != "Timeout ended" {
+		t.Fatalf("unexpected title %q", embed.Title)
+	}
+	if !strings.Contains(embed.Description, "[case #12](https://discord.com/channels/g1/c1/m1)") {
+		t.Fatalf("description does not link the case log: %q", embed.Description)
+	}
+
+	c.Action, c.LogMessageID = "automod_timeout", ""
+	if embed := ExpiryEmbed(c); embed.Title != "Timeout ended" || !strings.Contains(embed.Description, "from case #12 expired") {
+		t.Fatalf("unexpected embed without a case log: %+v", embed)
+	}
+}
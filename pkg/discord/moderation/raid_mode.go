@@ -0,0 +1,309 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+const (
+	// raidModeVerification is the verification level raid mode raises
+	// guilds to: members must have been registered for five minutes and in
+	// the guild for ten before they can talk.
+	raidModeVerification = discord.HighVerification
+
+	// maxInvitePause is the longest Discord lets invites stay paused. A
+	// longer raid mode keeps invites paused for its first day only.
+	maxInvitePause = 24 * time.Hour
+
+	// raidModeCacheTTL bounds how long the join gate trusts its copy of a
+	// guild's raid mode, so a change made by another process applies soon.
+	raidModeCacheTTL = 15 * time.Second
+)
+
+// ErrRaidModeActive is returned by Enable for a guild already in raid mode.
+var ErrRaidModeActive = errors.New("raid mode is already on")
+
+// RaidModeStore persists raid modes so they survive restarts and lift on
+// time. *postgres.Store satisfies it.
+type RaidModeStore interface {
+	SaveRaidMode(ctx context.Context, mode coremod.RaidMode) (bool, error)
+	GetRaidMode(ctx context.Context, guildID string) (coremod.RaidMode, bool, error)
+	ListExpiredRaidModes(ctx context.Context, now time.Time) ([]coremod.RaidMode, error)
+	DeleteRaidMode(ctx context.Context, guildID string) error
+}
+
+// RaidModeClient is the part of *api.Client raid mode needs. Invites are
+// paused through the incident actions endpoint, which the client has no
+// method for.
+type RaidModeClient interface {
+	Guild(guildID discord.GuildID) (*discord.Guild, error)
+	ModifyGuild(guildID discord.GuildID, data api.ModifyGuildData) (*discord.Guild, error)
+	Kick(guildID discord.GuildID, userID discord.UserID, reason api.AuditLogReason) error
+	FastRequest(method, url string, opts ...httputil.RequestOption) error
+}
+
+// RaidGuard turns raid mode on and off and screens members who join while
+// it is on. Raid mode raises the verification level, pauses invites and,
+// when a minimum account age is set, kicks younger accounts as they join.
+// What it changed is stored so lifting it restores the guild.
+//
+// Goroutine safety: every method is safe to call concurrently.
+type RaidGuard struct {
+	client RaidModeClient
+	store  RaidModeStore
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu     sync.Mutex
+	cached map[discord.GuildID]raidModeEntry
+}
+
+type raidModeEntry struct {
+	mode    coremod.RaidMode
+	active  bool
+	expires time.Time
+}
+
+// NewRaidGuard creates a guard acting through client and recording raid
+// modes in store.
+func NewRaidGuard(client RaidModeClient, store RaidModeStore, logger *slog.Logger) *RaidGuard {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &RaidGuard{
+		client: client,
+		store:  store,
+		logger: logger,
+		now:    time.Now,
+		cached: make(map[discord.GuildID]raidModeEntry),
+	}
+}
+
+// Enable puts guildID in raid mode for duration. Accounts younger than
+// minAccountAge are kicked when they join; zero lets every account in. A
+// guild already in raid mode fails with ErrRaidModeActive and is left as is.
+func (g *RaidGuard) Enable(ctx context.Context, guildID discord.GuildID, actorID discord.UserID, duration, minAccountAge time.Duration, reason string) (coremod.RaidMode, error) {
+	existing, found, err := g.store.GetRaidMode(ctx, guildID.String())
+	if err != nil {
+		return coremod.RaidMode{}, fmt.Errorf("RaidGuard.Enable: %w", err)
+	}
+	now := g.now()
+	if found {
+		if !existing.Expired(now) {
+			return existing, ErrRaidModeActive
+		}
+		// The expiry task has not got to it yet; lift it before starting over.
+		if err := g.Lift(ctx, existing, "Raid mode expired"); err != nil {
+			return coremod.RaidMode{}, fmt.Errorf("RaidGuard.Enable: %w", err)
+		}
+	}
+
+	guild, err := g.client.Guild(guildID)
+	if err != nil {
+		return coremod.RaidMode{}, fmt.Errorf("RaidGuard.Enable: fetch guild: %w", err)
+	}
+	mode := coremod.RaidMode{
+		GuildID:              guildID.String(),
+		EnabledBy:            actorID.String(),
+		EnabledAt:            now,
+		ExpiresAt:            now.Add(duration),
+		PreviousVerification: int(guild.Verification),
+		MinAccountAge:        minAccountAge,
+	}
+	if guild.Verification < raidModeVerification {
+		level := raidModeVerification
+		if _, err := g.client.ModifyGuild(guildID, api.ModifyGuildData{
+			Verification:   &level,
+			AuditLogReason: api.AuditLogReason(reason),
+		}); err != nil {
+			return coremod.RaidMode{}, fmt.Errorf("RaidGuard.Enable: raise verification: %w", err)
+		}
+		mode.RaisedVerification = true
+	}
+	pauseUntil := mode.ExpiresAt
+	if limit := now.Add(maxInvitePause); pauseUntil.After(limit) {
+		pauseUntil = limit
+	}
+	if err := g.pauseInvites(guildID, pauseUntil); err != nil {
+		// Raid mode still helps without it, so the moderator is told instead.
+		g.logger.Warn("Mitigated service degradation: Invites could not be paused for raid mode",
+			slog.String("guild_id", guildID.String()),
+			slog.String("error", err.Error()),
+		)
+	} else {
+		mode.InvitesPaused = true
+	}
+
+	saved, err := g.store.SaveRaidMode(ctx, mode)
+	if err != nil || !saved {
+		// Nothing records the changes, so nothing would ever undo them.
+		if rerr := g.restore(mode, reason); rerr != nil {
+			err = errors.Join(err, rerr)
+		}
+		if err == nil {
+			return coremod.RaidMode{}, ErrRaidModeActive
+		}
+		return coremod.RaidMode{}, fmt.Errorf("RaidGuard.Enable: %w", err)
+	}
+	g.remember(guildID, mode, true)
+	return mode, nil
+}
+
+// Disable lifts the raid mode of guildID. It reports false when the guild
+// was not in raid mode.
+func (g *RaidGuard) Disable(ctx context.Context, guildID discord.GuildID, reason string) (bool, error) {
+	mode, found, err := g.store.GetRaidMode(ctx, guildID.String())
+	if err != nil {
+		return false, fmt.Errorf("RaidGuard.Disable: %w", err)
+	}
+	if !found {
+		g.remember(guildID, coremod.RaidMode{}, false)
+		return false, nil
+	}
+	return true, g.Lift(ctx, mode, reason)
+}
+
+// Active returns the raid mode of guildID, if it is on.
+func (g *RaidGuard) Active(ctx context.Context, guildID discord.GuildID) (coremod.RaidMode, bool, error) {
+	mode, found, err := g.store.GetRaidMode(ctx, guildID.String())
+	if err != nil {
+		return coremod.RaidMode{}, false, fmt.Errorf("RaidGuard.Active: %w", err)
+	}
+	return mode, found && !mode.Expired(g.now()), nil
+}
+
+// Lift undoes what mode changed and forgets it. The record goes even when
+// Discord rejects part of the restore, since retrying would fail the same
+// way; the returned error tells staff what to put back by hand.
+func (g *RaidGuard) Lift(ctx context.Context, mode coremod.RaidMode, reason string) error {
+	guildID, err := discord.ParseSnowflake(mode.GuildID)
+	if err != nil {
+		return fmt.Errorf("RaidGuard.Lift: %w", err)
+	}
+	restoreErr := g.restore(mode, reason)
+	if err := g.store.DeleteRaidMode(ctx, mode.GuildID); err != nil {
+		return fmt.Errorf("RaidGuard.Lift: %w", errors.Join(restoreErr, err))
+	}
+	g.remember(discord.GuildID(guildID), coremod.RaidMode{}, false)
+	if restoreErr != nil {
+		return fmt.Errorf("RaidGuard.Lift: %w", restoreErr)
+	}
+	return nil
+}
+
+// Screen kicks user if it joined guildID during raid mode with an account
+// younger than the minimum age. It reports whether the user was kicked; in
+// test mode the kick is only logged.
+func (g *RaidGuard) Screen(ctx context.Context, guildID discord.GuildID, user discord.User) (bool, error) {
+	if user.Bot {
+		return false, nil
+	}
+	mode, active, err := g.cachedMode(ctx, guildID)
+	if err != nil || !active {
+		return false, err
+	}
+	now := g.now()
+	if mode.Expired(now) || !mode.TooYoung(user.ID.Time(), now) {
+		return false, nil
+	}
+	reason := fmt.Sprintf("Raid mode: account younger than %s", formatAccountAge(mode.MinAccountAge))
+	if coremod.InTestMode(ctx) {
+		g.logger.Info("Operational telemetry: Simulated raid mode kick in test mode",
+			slog.String("guild_id", guildID.String()),
+			slog.String("user_id", user.ID.String()),
+		)
+		return false, nil
+	}
+	if err := g.client.Kick(guildID, user.ID, api.AuditLogReason(reason)); err != nil {
+		return false, fmt.Errorf("RaidGuard.Screen: %w", err)
+	}
+	return true, nil
+}
+
+// restore puts back the verification level and invites mode changed. The
+// verification level is left alone when someone changed it since.
+func (g *RaidGuard) restore(mode coremod.RaidMode, reason string) error {
+	guildID, err := discord.ParseSnowflake(mode.GuildID)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	if mode.RaisedVerification {
+		guild, err := g.client.Guild(discord.GuildID(guildID))
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("fetch guild: %w", err))
+		case guild.Verification == raidModeVerification:
+			level := discord.Verification(mode.PreviousVerification)
+			if _, err := g.client.ModifyGuild(discord.GuildID(guildID), api.ModifyGuildData{
+				Verification:   &level,
+				AuditLogReason: api.AuditLogReason(reason),
+			}); err != nil {
+				errs = append(errs, fmt.Errorf("restore verification: %w", err))
+			}
+		}
+	}
+	if mode.InvitesPaused {
+		if err := g.pauseInvites(discord.GuildID(guildID), time.Time{}); err != nil {
+			errs = append(errs, fmt.Errorf("resume invites: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pauseInvites pauses the invites of guildID until the given time, or
+// resumes them for a zero time.
+func (g *RaidGuard) pauseInvites(guildID discord.GuildID, until time.Time) error {
+	var body struct {
+		InvitesDisabledUntil *discord.Timestamp `json:"invites_disabled_until"`
+	}
+	if !until.IsZero() {
+		ts := discord.NewTimestamp(until)
+		body.InvitesDisabledUntil = &ts
+	}
+	return g.client.FastRequest("PUT",
+		api.EndpointGuilds+guildID.String()+"/incident-actions",
+		httputil.WithJSONBody(body),
+	)
+}
+
+func (g *RaidGuard) cachedMode(ctx context.Context, guildID discord.GuildID) (coremod.RaidMode, bool, error) {
+	now := g.now()
+	g.mu.Lock()
+	entry, ok := g.cached[guildID]
+	g.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.mode, entry.active, nil
+	}
+	mode, found, err := g.store.GetRaidMode(ctx, guildID.String())
+	if err != nil {
+		return coremod.RaidMode{}, false, fmt.Errorf("RaidGuard.cachedMode: %w", err)
+	}
+	g.remember(guildID, mode, found)
+	return mode, found, nil
+}
+
+func (g *RaidGuard) remember(guildID discord.GuildID, mode coremod.RaidMode, active bool) {
+	g.mu.Lock()
+	g.cached[guildID] = raidModeEntry{mode: mode, active: active, expires: g.now().Add(raidModeCacheTTL)}
+	g.mu.Unlock()
+}
+
+// formatAccountAge renders an account age in whole days, or hours below a
+// day.
+func formatAccountAge(d time.Duration) string {
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%d hour(s)", int(d.Hours()))
+	}
+	return fmt.Sprintf("%d day(s)", int(d.Hours()/24))
+}
@@ -0,0 +1,142 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/api"
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/utils/httputil"
+
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+type fakeRaidClient struct {
+	verification discord.Verification
+	requests     []string
+	kicked       []discord.UserID
+	failRequests bool
+}
+
+func (c *fakeRaidClient) Guild(discord.GuildID) (*discord.Guild, error) {
+	return &discord.Guild{Verification: c.verification}, nil
+}
+
+func (c *fakeRaidClient) ModifyGuild(_ discord.GuildID, data api.ModifyGuildData) (*discord.Guild, error) {
+	c.verification = *data.Verification
+	return &discord.Guild{Verification: c.verification}, nil
+}
+
+func (c *fakeRaidClient) Kick(_ discord.GuildID, userID discord.UserID, _ api.AuditLogReason) error {
+	c.kicked = append(c.kicked, userID)
+	return nil
+}
+
+func (c *fakeRaidClient) FastRequest(method, url string, _ ...httputil.RequestOption) error {
+	if c.failRequests {
+		return errors.New("missing access")
+	}
+	c.requests = append(c.requests, method+" "+url)
+	return nil
+}
+
+type fakeRaidStore struct {
+	modes map[string]coremod.RaidMode
+}
+
+func (s *fakeRaidStore) SaveRaidMode(_ context.Context, mode coremod.RaidMode) (bool, error) {
+	if _, ok := s.modes[mode.GuildID]; ok {
+		return false, nil
+	}
+	s.modes[mode.GuildID] = mode
+	return true, nil
+}
+
+func (s *fakeRaidStore) GetRaidMode(_ context.Context, guildID string) (coremod.RaidMode, bool, error) {
+	mode, ok := s.modes[guildID]
+	return mode, ok, nil
+}
+
+func (s *fakeRaidStore) ListExpiredRaidModes(context.Context, time.Time) ([]coremod.RaidMode, error) {
+	return nil, nil
+}
+
+func (s *fakeRaidStore) DeleteRaidMode(_ context.Context, guildID string) error {
+	delete(s.modes, guildID)
+	return nil
+}
+
+func TestRaidGuard_EnableScreenDisable(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	client := &fakeRaidClient{verification: discord.LowVerification}
+	store := &fakeRaidStore{modes: map[string]coremod.RaidMode{}}
+	guard := NewRaidGuard(client, store, nil)
+	guard.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	mode, err := guard.Enable(ctx, 1, 7, time.Hour, 48*time.Hour, "raid")
+	if err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	if !mode.RaisedVerification || !mode.InvitesPaused || client.verification != discord.HighVerification {
+		t.Fatalf("unexpected raid mode %+v with verification %v", mode, client.verification)
+	}
+	if len(client.requests) != 1 || !strings.HasSuffix(client.requests[0], "/guilds/1/incident-actions") {
+		t.Fatalf("expected invites to be paused, got %v", client.requests)
+	}
+	if _, err := guard.Enable(ctx, 1, 7, time.Hour, 0, "again"); !errors.Is(err, ErrRaidModeActive) {
+		t.Fatalf("second Enable err = %v, want ErrRaidModeActive", err)
+	}
+
+	young := discord.User{ID: discord.UserID(discord.NewSnowflake(now.Add(-time.Hour)))}
+	old := discord.User{ID: discord.UserID(discord.NewSnowflake(now.Add(-72 * time.Hour)))}
+	bot := discord.User{ID: young.ID + 1, Bot: true}
+	for _, u := range []discord.User{young, old, bot} {
+		if _, err := guard.Screen(ctx, 1, u); err != nil {
+			t.Fatalf("Screen: %v", err)
+		}
+	}
+	if len(client.kicked) != 1 || client.kicked[0] != young.ID {
+		t.Fatalf("kicked = %v, want only the young account", client.kicked)
+	}
+
+	lifted, err := guard.Disable(ctx, 1, "over")
+	if err != nil || !lifted {
+		t.Fatalf("Disable: lifted=%v, err=%v", lifted, err)
+	}
+	if client.verification != discord.LowVerification || len(client.requests) != 2 {
+		t.Fatalf("expected verification and invites restored, got %v and %v", client.verification, client.requests)
+	}
+	if kicked, _ := guard.Screen(ctx, 1, young); kicked {
+		t.Fatal("nobody is kicked once raid mode is off")
+	}
+	if lifted, err := guard.Disable(ctx, 1, "over"); err != nil || lifted {
+		t.Fatalf("Disable without raid mode: lifted=%v, err=%v", lifted, err)
+	}
+}
+
+func TestRaidGuard_KeepsManualChanges(t *testing.T) {
+	t.Parallel()
+	client := &fakeRaidClient{verification: discord.VeryHighVerification, failRequests: true}
+	store := &fakeRaidStore{modes: map[string]coremod.RaidMode{}}
+	guard := NewRaidGuard(client, store, nil)
+	ctx := context.Background()
+
+	mode, err := guard.Enable(ctx, 1, 7, time.Hour, 0, "raid")
+	if err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	if mode.RaisedVerification || mode.InvitesPaused {
+		t.Fatalf("raid mode should change neither a higher level nor unpausable invites, got %+v", mode)
+	}
+	if _, err := guard.Disable(ctx, 1, "over"); err != nil {
+		t.Fatalf("Disable: %v", err)
+	}
+	if client.verification != discord.VeryHighVerification {
+		t.Fatalf("verification = %v, want it untouched", client.verification)
+	}
+}
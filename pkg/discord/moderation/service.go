@@ -2,12 +2,16 @@ package moderation
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/utils/json/option"
+
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
 )
 
 // Client defines the subset of arikawa API operations required for moderation.
@@ -16,15 +20,23 @@ import (
 type Client interface {
 	Ban(guildID discord.GuildID, userID discord.UserID, data api.BanData) error
 	Kick(guildID discord.GuildID, userID discord.UserID, reason api.AuditLogReason) error
+	Unban(guildID discord.GuildID, userID discord.UserID, reason api.AuditLogReason) error
 	ModifyMember(guildID discord.GuildID, userID discord.UserID, data api.ModifyMemberData) error
 }
 
 // Service provides high-level Discord moderation operations.
 type Service struct {
-	client Client
-	logger *slog.Logger
+	client   Client
+	logger   *slog.Logger
+	contexts *GuildContextCache
+	guard    *coremod.ActionRateGuard
+	onTrip   RateGuardAlert
 }
 
+// RateGuardAlert is called once when a moderator is locked out for exceeding
+// the destructive action limit.
+type RateGuardAlert func(guildID discord.GuildID, moderatorID discord.UserID, until time.Time)
+
 // NewService instantiates a new moderation service using the provided arikawa client.
 func NewService(client Client, logger *slog.Logger) *Service {
 	if logger == nil {
@@ -33,10 +45,213 @@ func NewService(client Client, logger *slog.Logger) *Service {
 	return &Service{
 		client: client,
 		logger: logger,
+		guard:  coremod.NewActionRateGuard(),
+	}
+}
+
+// WithGuildContexts attaches the guild context cache Authorize checks role
+// hierarchy against. Without one, Authorize refuses every action with
+// ErrNoGuildContexts.
+func (s *Service) WithGuildContexts(contexts *GuildContextCache) *Service {
+	s.contexts = contexts
+	return s
+}
+
+// WithRateGuard replaces the service's own rate guard with guard, so
+// services of several bots can count one moderator's actions together.
+// onTrip, when set, is called as a moderator is locked out.
+func (s *Service) WithRateGuard(guard *coremod.ActionRateGuard, onTrip RateGuardAlert) *Service {
+	s.guard = guard
+	s.onTrip = onTrip
+	return s
+}
+
+// ReserveActions counts n destructive actions by actorID against limit,
+// failing with an error wrapping coremod.ErrActionRateExceeded when the actor
+// is, or now becomes, locked out. The guild owner is never limited; telling
+// the owner apart takes the guild contexts, so without them the actions are
+// refused with ErrNoGuildContexts. Actions that then fail are handed back
+// with ReleaseActions.
+func (s *Service) ReserveActions(ctx context.Context, guildID discord.GuildID, actorID discord.UserID, n int, limit coremod.ActionRateLimit) error {
+	if s.guard == nil || limit.Max <= 0 {
+		return nil
+	}
+	if s.contexts == nil {
+		return fmt.Errorf("Service.ReserveActions: %w", ErrNoGuildContexts)
+	}
+	gctx, err := s.contexts.Get(ctx, guildID)
+	if err != nil {
+		return fmt.Errorf("Service.ReserveActions: %w", err)
+	}
+	if actorID == gctx.OwnerID {
+		return nil
+	}
+
+	err = s.guard.Record(guildID.String(), actorID.String(), n, limit)
+	var rateErr *coremod.ActionRateError
+	if errors.As(err, &rateErr) && rateErr.Tripped {
+		s.logger.Warn("Mitigated service degradation: Moderator locked out after exceeding the destructive action limit",
+			slog.String("guild_id", guildID.String()),
+			slog.String("moderator_id", actorID.String()),
+			slog.Int("limit", limit.Max),
+			slog.Duration("window", limit.Window),
+			slog.Time("until", rateErr.Until),
+		)
+		if s.onTrip != nil {
+			s.onTrip(guildID, actorID, rateErr.Until)
+		}
+	}
+	return err
+}
+
+// ReleaseActions hands back n actions ReserveActions counted for actorID that
+// were not carried out, so failed bans and kicks do not count toward a
+// lockout.
+func (s *Service) ReleaseActions(guildID discord.GuildID, actorID discord.UserID, n int) {
+	if s.guard == nil {
+		return
+	}
+	s.guard.Refund(guildID.String(), actorID.String(), n)
+}
+
+// LockedOut reports whether the rate guard has locked actorID out of
+// moderating guildID, and until when.
+func (s *Service) LockedOut(guildID discord.GuildID, actorID discord.UserID) (time.Time, bool) {
+	if s.guard == nil {
+		return time.Time{}, false
+	}
+	return s.guard.LockedUntil(guildID.String(), actorID.String())
+}
+
+// LiftLockout ends the lockout of actorID in guildID early and forgets
+// their recent actions, for the bot owner to clear a moderator the limit
+// caught by mistake. It reports whether actorID was locked out.
+func (s *Service) LiftLockout(guildID discord.GuildID, actorID discord.UserID) bool {
+	if s.guard == nil {
+		return false
+	}
+	_, locked := s.guard.LockedUntil(guildID.String(), actorID.String())
+	s.guard.Release(guildID.String(), actorID.String())
+	return locked
+}
+
+// checkLockout refuses actors the rate guard has locked out.
+func (s *Service) checkLockout(gctx *GuildModerationContext, actorID discord.UserID) error {
+	if s.guard == nil || actorID == gctx.OwnerID {
+		return nil
+	}
+	if until, locked := s.guard.LockedUntil(gctx.GuildID.String(), actorID.String()); locked {
+		return &coremod.ActionRateError{Until: until}
+	}
+	return nil
+}
+
+// Authorize verifies that actorID may moderate targetID and that the bot
+// outranks the target. Actors locked out by the rate guard are refused, and
+// so are targets covered by protected unless the actor owns the guild. Targets
+// who are not guild members, such as users being pre-emptively banned, only
+// need to be someone other than the owner and not protected by user ID.
+func (s *Service) Authorize(ctx context.Context, guildID discord.GuildID, actorID, targetID discord.UserID, protected coremod.ProtectedTargets) error {
+	if s.contexts == nil {
+		// Protection by user ID needs no guild state, so the invoker still
+		// learns why the action is refused.
+		if protected.Protects(targetID.String(), nil) {
+			return ErrTargetProtected
+		}
+		return fmt.Errorf("Service.Authorize: %w", ErrNoGuildContexts)
+	}
+	gctx, err := s.contexts.Get(ctx, guildID)
+	if err != nil {
+		return fmt.Errorf("Service.Authorize: %w", err)
+	}
+	if err := s.checkLockout(gctx, actorID); err != nil {
+		return err
+	}
+	actor, err := s.contexts.Member(guildID, actorID)
+	if err != nil {
+		return fmt.Errorf("Service.Authorize: resolve actor: %w", err)
+	}
+	target, err := s.contexts.Member(guildID, targetID)
+	if err != nil {
+		if !isNotFound(err) {
+			return fmt.Errorf("Service.Authorize: resolve target: %w", err)
+		}
+		if targetID == gctx.OwnerID {
+			return ErrTargetIsOwner
+		}
+		return checkProtected(gctx, actorID, targetID, nil, protected)
+	}
+	if targetID == gctx.OwnerID {
+		return ErrTargetIsOwner
+	}
+	if err := checkProtected(gctx, actorID, targetID, target, protected); err != nil {
+		return err
+	}
+	return gctx.CheckHierarchy(actor, target)
+}
+
+// AuthorizeMany runs Authorize for every target, resolving the targets in one
+// batch. The returned map holds only the refused targets and why; the error
+// is set when the check itself could not run.
+func (s *Service) AuthorizeMany(ctx context.Context, guildID discord.GuildID, actorID discord.UserID, targetIDs []discord.UserID, protected coremod.ProtectedTargets) (map[discord.UserID]error, error) {
+	if len(targetIDs) == 0 {
+		return nil, nil
+	}
+	if s.contexts == nil {
+		return nil, fmt.Errorf("Service.AuthorizeMany: %w", ErrNoGuildContexts)
+	}
+	gctx, err := s.contexts.Get(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("Service.AuthorizeMany: %w", err)
+	}
+	if err := s.checkLockout(gctx, actorID); err != nil {
+		return nil, err
+	}
+	actor, err := s.contexts.Member(guildID, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("Service.AuthorizeMany: resolve actor: %w", err)
+	}
+	targets, err := s.contexts.Members(ctx, guildID, targetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("Service.AuthorizeMany: resolve targets: %w", err)
 	}
+	denied := make(map[discord.UserID]error)
+	for _, id := range targetIDs {
+		target, ok := targets[id]
+		if id == gctx.OwnerID {
+			denied[id] = ErrTargetIsOwner
+			continue
+		}
+		if err := checkProtected(gctx, actorID, id, target, protected); err != nil {
+			denied[id] = err
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if err := gctx.CheckHierarchy(actor, target); err != nil {
+			denied[id] = err
+		}
+	}
+	return denied, nil
+}
+
+// checkProtected refuses targets the guild protected. The owner may still act
+// on them.
+func checkProtected(gctx *GuildModerationContext, actorID, targetID discord.UserID, target *coremod.Member, protected coremod.ProtectedTargets) error {
+	if actorID != gctx.OwnerID && protected.Protects(targetID.String(), target) {
+		return ErrTargetProtected
+	}
+	return nil
 }
 
-// Ban executes a guild ban against the target user.
+// maxDeleteMessageSeconds is the longest message history Discord deletes
+// alongside a ban.
+const maxDeleteMessageSeconds = 7 * 24 * 60 * 60
+
+// Ban executes a guild ban against the target user, deleting up to
+// deleteMessageSeconds of their message history (clamped to 0-7 days; Discord
+// applies the window in whole days).
 // The context must be strictly respected to prevent dangling goroutines
 // in the event of I/O failures.
 func (s *Service) Ban(ctx context.Context, guildID discord.GuildID, userID discord.UserID, deleteMessageSeconds int, reason string) error {
@@ -46,8 +261,10 @@ func (s *Service) Ban(ctx context.Context, guildID discord.GuildID, userID disco
 	default:
 	}
 
+	deleteMessageSeconds = min(max(deleteMessageSeconds, 0), maxDeleteMessageSeconds)
 	data := api.BanData{
-		DeleteDays: option.NewUint(uint(deleteMessageSeconds / 86400)),
+		DeleteDays:     option.NewUint(uint(deleteMessageSeconds / 86400)),
+		AuditLogReason: api.AuditLogReason(reason),
 	}
 
 	s.logger.Debug("Granular transient state inspection: Executing ban payload",
@@ -55,10 +272,10 @@ func (s *Service) Ban(ctx context.Context, guildID discord.GuildID, userID disco
 		slog.String("target_id", userID.String()),
 		slog.Int("delete_days", deleteMessageSeconds/86400),
 	)
+	if s.simulated(ctx, "ban", guildID, userID) {
+		return nil
+	}
 
-	// Arikawa requires reason via audit log reason header, which is typically handled by WithContext and api.WithReason,
-	// but for this abstract interface we assume the reason is either passed down or the caller wraps the context via api.WithReason.
-	// Since we strictly enforce arikawa, the context should already carry the audit log reason.
 	if err := s.client.Ban(guildID, userID, data); err != nil {
 		s.logger.Warn("Mitigated service degradation: Ban execution rejected by network or permissions",
 			slog.String("guild_id", guildID.String()),
@@ -71,6 +288,34 @@ func (s *Service) Ban(ctx context.Context, guildID discord.GuildID, userID disco
 	return nil
 }
 
+// Unban lifts a guild ban from the target user.
+func (s *Service) Unban(ctx context.Context, guildID discord.GuildID, userID discord.UserID, reason api.AuditLogReason) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	s.logger.Debug("Granular transient state inspection: Executing unban payload",
+		slog.String("guild_id", guildID.String()),
+		slog.String("target_id", userID.String()),
+	)
+	if s.simulated(ctx, "unban", guildID, userID) {
+		return nil
+	}
+
+	if err := s.client.Unban(guildID, userID, reason); err != nil {
+		s.logger.Warn("Mitigated service degradation: Unban execution rejected by network or permissions",
+			slog.String("guild_id", guildID.String()),
+			slog.String("target_id", userID.String()),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("failed to execute unban: %w", err)
+	}
+
+	return nil
+}
+
 // Kick removes a user from the guild.
 func (s *Service) Kick(ctx context.Context, guildID discord.GuildID, userID discord.UserID, reason api.AuditLogReason) error {
 	select {
@@ -83,6 +328,9 @@ func (s *Service) Kick(ctx context.Context, guildID discord.GuildID, userID disc
 		slog.String("guild_id", guildID.String()),
 		slog.String("target_id", userID.String()),
 	)
+	if s.simulated(ctx, "kick", guildID, userID) {
+		return nil
+	}
 
 	if err := s.client.Kick(guildID, userID, reason); err != nil {
 		s.logger.Warn("Mitigated service degradation: Kick execution rejected by network or permissions",
@@ -113,6 +361,9 @@ func (s *Service) Timeout(ctx context.Context, guildID discord.GuildID, userID d
 		slog.String("target_id", userID.String()),
 		slog.Time("until", until.Time()),
 	)
+	if s.simulated(ctx, "timeout", guildID, userID) {
+		return nil
+	}
 
 	if err := s.client.ModifyMember(guildID, userID, data); err != nil {
 		s.logger.Warn("Mitigated service degradation: Timeout execution rejected by network or permissions",
@@ -125,3 +376,17 @@ func (s *Service) Timeout(ctx context.Context, guildID discord.GuildID, userID d
 
 	return nil
 }
+
+// simulated reports whether ctx is in test mode, logging the action that was
+// not taken in its place.
+func (s *Service) simulated(ctx context.Context, action string, guildID discord.GuildID, userID discord.UserID) bool {
+	if !coremod.InTestMode(ctx) {
+		return false
+	}
+	s.logger.Info("Operational telemetry: Simulated moderation action in test mode",
+		slog.String("action", action),
+		slog.String("guild_id", guildID.String()),
+		slog.String("target_id", userID.String()),
+	)
+	return true
+}
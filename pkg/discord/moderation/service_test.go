@@ -6,13 +6,17 @@ import (
 
 	"github.com/diamondburned/arikawa/v3/api"
 	"github.com/diamondburned/arikawa/v3/discord"
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
 )
 
 type mockModerationClient struct {
 	Client
+	lastBan   api.BanData
+	lastUnban discord.UserID
 }
 
 func (m *mockModerationClient) Ban(guildID discord.GuildID, userID discord.UserID, data api.BanData) error {
+	m.lastBan = data
 	return nil
 }
 
@@ -20,6 +24,11 @@ func (m *mockModerationClient) Kick(guildID discord.GuildID, userID discord.User
 	return nil
 }
 
+func (m *mockModerationClient) Unban(guildID discord.GuildID, userID discord.UserID, reason api.AuditLogReason) error {
+	m.lastUnban = userID
+	return nil
+}
+
 func (m *mockModerationClient) ModifyMember(guildID discord.GuildID, userID discord.UserID, data api.ModifyMemberData) error {
 	return nil
 }
@@ -40,6 +49,54 @@ func TestService_ContextTimeout(t *testing.T) {
 	}
 }
 
+func TestService_BanClampsDeleteWindow(t *testing.T) {
+	t.Parallel()
+
+	client := &mockModerationClient{}
+	svc := NewService(client, nil)
+
+	tests := []struct {
+		seconds int
+		want    uint
+	}{
+		{seconds: 0, want: 0},
+		{seconds: 3 * 86400, want: 3},
+		{seconds: 30 * 86400, want: 7},
+		{seconds: -86400, want: 0},
+	}
+	for _, tt := range tests {
+		if err := svc.Ban(context.Background(), 123, 456, tt.seconds, ""); err != nil {
+			t.Fatalf("Ban(%d): %v", tt.seconds, err)
+		}
+		if got := *client.lastBan.DeleteDays; got != tt.want {
+			t.Errorf("Ban(%d): delete days %d, want %d", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestService_Unban(t *testing.T) {
+	t.Parallel()
+
+	client := &mockModerationClient{}
+	svc := NewService(client, nil)
+
+	if err := svc.Unban(context.Background(), 123, 456, "softban"); err != nil {
+		t.Fatalf("Unban: %v", err)
+	}
+	if client.lastUnban != 456 {
+		t.Fatalf("unbanned %v, want 456", client.lastUnban)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := svc.Unban(ctx, 123, 789, ""); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if client.lastUnban != 456 {
+		t.Fatal("Unban reached the client after cancellation")
+	}
+}
+
 func TestService_ExponentialBackoff(t *testing.T) {
 	t.Parallel()
 	// Simply verifying that Service wraps Client and constructor executes without panic.
@@ -49,3 +106,47 @@ func TestService_ExponentialBackoff(t *testing.T) {
 		t.Fatal("expected non-nil service")
 	}
 }
+
+func TestService_TestModeSimulates(t *testing.T) {
+	t.Parallel()
+
+	client := &mockModerationClient{}
+	svc := NewService(client, nil)
+	ctx := coremod.WithTestMode(context.Background())
+
+	if err := svc.Ban(ctx, 123, 456, 86400, "trial"); err != nil {
+		t.Fatalf("Ban: %v", err)
+	}
+	if err := svc.Unban(ctx, 123, 456, "trial"); err != nil {
+		t.Fatalf("Unban: %v", err)
+	}
+	if client.lastBan.DeleteDays != nil || client.lastUnban != 0 {
+		t.Fatal("test mode reached the client")
+	}
+}
+
+func TestService_LiftLockoutClearsLockedOut(t *testing.T) {
+	t.Parallel()
+	guard := coremod.NewActionRateGuard()
+	svc := NewService(&mockModerationClient{}, nil).WithRateGuard(guard, nil)
+	limit := coremod.ActionRateLimit{Max: 1}
+	if err := guard.Record("1", "2", 2, limit); err == nil {
+		t.Fatal("expected the actions to trip the limit")
+	}
+	if _, locked := svc.LockedOut(discord.GuildID(1), discord.UserID(2)); !locked {
+		t.Fatal("expected the moderator to be locked out")
+	}
+
+	if !svc.LiftLockout(discord.GuildID(1), discord.UserID(2)) {
+		t.Fatal("expected LiftLockout to report the lockout it lifted")
+	}
+	if _, locked := svc.LockedOut(discord.GuildID(1), discord.UserID(2)); locked {
+		t.Fatal("expected the lockout to be lifted")
+	}
+	if err := guard.Record("1", "2", 1, limit); err != nil {
+		t.Fatalf("expected the moderator to act again after the override: %v", err)
+	}
+	if svc.LiftLockout(discord.GuildID(1), discord.UserID(2)) {
+		t.Fatal("expected no lockout to lift")
+	}
+}
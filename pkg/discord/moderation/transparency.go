@@ -0,0 +1,59 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/theme"
+)
+
+// TransparencySource tallies a guild's moderation. *postgres.Store satisfies
+// it.
+type TransparencySource interface {
+	ModerationActionCounts(ctx context.Context, guildID string, from, to time.Time) (coremod.ActionCounts, error)
+}
+
+// BuildTransparencyReport compiles the transparency report of guildID over
+// [from, to).
+func BuildTransparencyReport(ctx context.Context, src TransparencySource, guildID string, from, to time.Time) (coremod.TransparencyReport, error) {
+	counts, err := src.ModerationActionCounts(ctx, guildID, from, to)
+	if err != nil {
+		return coremod.TransparencyReport{}, fmt.Errorf("BuildTransparencyReport: %w", err)
+	}
+	return coremod.NewTransparencyReport(from, to, counts), nil
+}
+
+// TransparencyEmbed renders report for a public channel. It carries counts
+// only, never members or moderators.
+func TransparencyEmbed(report coremod.TransparencyReport) discord.Embed {
+	embed := discord.Embed{
+		Title:     "Moderation transparency report",
+		Color:     discord.Color(theme.Info()),
+		Timestamp: discord.NewTimestamp(report.To),
+	}
+	last := report.To.Add(-time.Nanosecond)
+	embed.Description = fmt.Sprintf("Moderation actions taken from %s to %s (UTC).",
+		report.From.Format("January 2, 2006"), last.Format("January 2, 2006"))
+
+	if len(report.Categories) == 0 {
+		embed.Description += "\nNo moderation actions were needed."
+	} else {
+		var b strings.Builder
+		for _, category := range report.Categories {
+			fmt.Fprintf(&b, "%s: **%d**\n", category.Name, category.Count)
+		}
+		embed.Fields = append(embed.Fields,
+			discord.EmbedField{Name: "Actions", Value: b.String()},
+			discord.EmbedField{Name: "Total", Value: fmt.Sprint(report.Total()), Inline: true},
+		)
+	}
+	if report.Reversed > 0 {
+		embed.Fields = append(embed.Fields, discord.EmbedField{Name: "Reversed", Value: fmt.Sprint(report.Reversed), Inline: true})
+	}
+	return embed
+}
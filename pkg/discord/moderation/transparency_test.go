@@ -0,0 +1,48 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	coremod "github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+type fakeTransparencySource struct {
+	counts coremod.ActionCounts
+	err    error
+}
+
+func (f fakeTransparencySource) ModerationActionCounts(context.Context, string, time.Time, time.Time) (coremod.ActionCounts, error) {
+	return f.counts, f.err
+}
+
+func TestTransparencyReportEmbed(t *testing.T) {
+	t.Parallel()
+	from, to := coremod.PreviousMonth(time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC))
+	src := fakeTransparencySource{counts: coremod.ActionCounts{ByAction: map[string]int{"ban": 2, "kick": 1}, Voided: 1}}
+
+	report, err := BuildTransparencyReport(context.Background(), src, "g1", from, to)
+	if err != nil {
+		t.Fatalf("BuildTransparencyReport: %v", err)
+	}
+	embed := TransparencyEmbed(report)
+	if !strings.Contains(embed.Description, "February 1, 2026 to February 28, 2026") {
+		t.Fatalf("unexpected period %q", embed.Description)
+	}
+	if len(embed.Fields) != 3 || embed.Fields[0].Value != "Bans: **2**\nKicks: **1**\n" || embed.Fields[1].Value != "3" || embed.Fields[2].Value != "1" {
+		t.Fatalf("unexpected fields %+v", embed.Fields)
+	}
+
+	quiet := TransparencyEmbed(coremod.NewTransparencyReport(from, to, coremod.ActionCounts{}))
+	if len(quiet.Fields) != 0 || !strings.Contains(quiet.Description, "No moderation actions") {
+		t.Fatalf("unexpected quiet report %+v", quiet)
+	}
+
+	boom := errors.New("db down")
+	if _, err := BuildTransparencyReport(context.Background(), fakeTransparencySource{err: boom}, "g1", from, to); !errors.Is(err, boom) {
+		t.Fatalf("expected wrapped error, got %v", err)
+	}
+}
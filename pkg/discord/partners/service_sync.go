@@ -11,6 +11,7 @@ import (
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/utils/httputil"
 	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/keylock"
 )
 
 const (
@@ -36,8 +37,13 @@ func (r partnerSyncResult) HasIssues() bool {
 	return len(r.Dropped) > 0 || len(r.Failed) > 0
 }
 
+// errWebhooksPaused marks webhook postings skipped while the bot lockdown is
+// engaged; they stay on file and are retried on the next sync.
+var errWebhooksPaused = errors.New("webhook edits are paused by the bot lockdown")
+
 type partnerPostingSyncer struct {
 	configManager      *files.ConfigManager
+	guilds             keylock.Mutex[string]
 	editMessage        func(c *api.Client, channelID discord.ChannelID, messageID discord.MessageID, edit api.EditMessageData) error
 	editWebhookMessage func(c *api.Client, webhookID discord.WebhookID, webhookToken string, messageID discord.MessageID, edit api.EditMessageData) error
 	dropPostings       func(cm *files.ConfigManager, guildID string, messageIDs []string) error
@@ -76,6 +82,10 @@ func (s *partnerPostingSyncer) Sync(
 		}
 		var err error
 		if posting.WebhookID != "" && posting.WebhookToken != "" {
+			if s.configManager != nil && s.configManager.Config().LockdownActive() {
+				result.Failed = append(result.Failed, partnerSyncFailure{Posting: posting, Err: errWebhooksPaused})
+				continue
+			}
 			wID, _ := discord.ParseSnowflake(posting.WebhookID)
 			err = s.editWebhookMessage(client, discord.WebhookID(wID), posting.WebhookToken, discord.MessageID(msgID), edit)
 		} else {
@@ -117,6 +127,11 @@ func (s *partnerPostingSyncer) Sync(
 }
 
 func (s *partnerPostingSyncer) SyncConfig(guildID string, client *api.Client) error {
+	// Each partner command saves its change, then syncs. Syncs of one guild
+	// run one at a time so the last to edit the board is the last to have
+	// read it, and a board edited from an older read cannot overwrite a newer
+	// one.
+	defer s.guilds.Lock(guildID)()
 	cfg := s.configManager.GuildConfig(guildID)
 	if cfg == nil {
 		return errors.New("guild config not found")
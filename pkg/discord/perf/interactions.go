@@ -0,0 +1,122 @@
+package perf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/observability"
+)
+
+// InteractionAckDeadline is how long Discord waits for the first response to
+// an interaction before it shows the user "The application did not respond".
+const InteractionAckDeadline = 3 * time.Second
+
+const (
+	envInteractionAckThresholdMs     = "DISCORDCORE_INTERACTION_ACK_THRESHOLD_MS"
+	defaultInteractionAckThresholdMs = int64(2000)
+)
+
+var (
+	interactionThresholdOnce sync.Once
+	interactionThreshold     time.Duration
+
+	interactionMetricsMu sync.Mutex
+	interactionMetrics   map[string]*interactionAckMetrics
+)
+
+type interactionAckMetrics struct {
+	latency observability.Summary
+	slow    atomic.Int64
+	missed  atomic.Int64
+}
+
+func interactionAckThreshold() time.Duration {
+	interactionThresholdOnce.Do(func() {
+		ms := files.EnvInt64(envInteractionAckThresholdMs, defaultInteractionAckThresholdMs)
+		if ms <= 0 {
+			interactionThreshold = 0
+			return
+		}
+		interactionThreshold = time.Duration(ms) * time.Millisecond
+	})
+	return interactionThreshold
+}
+
+// ObserveInteractionAck records how long after Discord created an interaction
+// on route its first response went out. Acknowledgments slower than
+// DISCORDCORE_INTERACTION_ACK_THRESHOLD_MS, or past the deadline, are logged
+// and reported to the owner alert pipeline, which names the routes that
+// should defer their response or do less before it.
+func ObserveInteractionAck(route string, latency time.Duration) {
+	name := strings.TrimSpace(route)
+	if name == "" {
+		name = "unknown"
+	}
+
+	interactionMetricsMu.Lock()
+	if interactionMetrics == nil {
+		interactionMetrics = make(map[string]*interactionAckMetrics)
+	}
+	metrics, ok := interactionMetrics[name]
+	if !ok {
+		metrics = &interactionAckMetrics{}
+		interactionMetrics[name] = metrics
+	}
+	interactionMetricsMu.Unlock()
+	metrics.latency.Observe(latency)
+
+	var detail string
+	switch threshold := interactionAckThreshold(); {
+	case latency >= InteractionAckDeadline:
+		metrics.missed.Add(1)
+		detail = fmt.Sprintf("acknowledged after %s, past the %s deadline", latency.Round(time.Millisecond), InteractionAckDeadline)
+	case threshold > 0 && latency >= threshold:
+		detail = fmt.Sprintf("acknowledged after %s, %s before the deadline", latency.Round(time.Millisecond), (InteractionAckDeadline - latency).Round(time.Millisecond))
+	default:
+		return
+	}
+	metrics.slow.Add(1)
+
+	log.DiscordLogger().Warn("slow interaction acknowledgment",
+		"route", name,
+		"latency", latency,
+		"latency_ms", latency.Milliseconds(),
+		"missed_deadline", latency >= InteractionAckDeadline,
+	)
+	observability.ReportOperationalAlert(observability.AlertSlowInteraction, name, errors.New(detail))
+}
+
+// InteractionAckSnapshot describes the acknowledgment latencies of one
+// route. Slow counts acknowledgments at or over the threshold, missed
+// included; Missed counts those past the deadline.
+type InteractionAckSnapshot struct {
+	Latency observability.SummarySnapshot `json:"latency"`
+	Slow    int64                         `json:"slow"`
+	Missed  int64                         `json:"missed"`
+}
+
+// InteractionMetricsSnapshot is a snapshot of every route's acknowledgment
+// latencies.
+type InteractionMetricsSnapshot map[string]InteractionAckSnapshot
+
+// SnapshotInteractionMetrics returns a snapshot of every route's
+// acknowledgment latencies.
+func SnapshotInteractionMetrics() InteractionMetricsSnapshot {
+	interactionMetricsMu.Lock()
+	defer interactionMetricsMu.Unlock()
+	snapshot := make(InteractionMetricsSnapshot, len(interactionMetrics))
+	for name, metrics := range interactionMetrics {
+		snapshot[name] = InteractionAckSnapshot{
+			Latency: metrics.latency.Snapshot(),
+			Slow:    metrics.slow.Load(),
+			Missed:  metrics.missed.Load(),
+		}
+	}
+	return snapshot
+}
@@ -0,0 +1,34 @@
+package perf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/observability"
+)
+
+func TestObserveInteractionAck(t *testing.T) {
+	const route = "test.interaction_ack"
+	observability.DrainOperationalAlerts()
+	t.Cleanup(func() { observability.DrainOperationalAlerts() })
+
+	ObserveInteractionAck(route, 300*time.Millisecond)
+	ObserveInteractionAck(route, 2500*time.Millisecond)
+	ObserveInteractionAck(route, 3200*time.Millisecond)
+
+	got := SnapshotInteractionMetrics()[route]
+	if got.Latency.Count != 3 || got.Latency.MaxSeconds != 3.2 {
+		t.Fatalf("expected every acknowledgment in the summary, got %+v", got.Latency)
+	}
+	if got.Slow != 2 || got.Missed != 1 {
+		t.Fatalf("expected 2 slow and 1 missed acknowledgment, got %+v", got)
+	}
+
+	alerts, _ := observability.DrainOperationalAlerts()
+	if len(alerts) != 1 || alerts[0].Kind != observability.AlertSlowInteraction || alerts[0].Source != route || alerts[0].Count != 2 {
+		t.Fatalf("expected the slow acknowledgments to be reported once per route, got %+v", alerts)
+	}
+	if want := "acknowledged after 3.2s, past the 3s deadline"; alerts[0].LastMessage != want {
+		t.Fatalf("alert message = %q, want %q", alerts[0].LastMessage, want)
+	}
+}
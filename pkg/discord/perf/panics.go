@@ -0,0 +1,37 @@
+package perf
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/small-frappuccino/discordcore/pkg/log"
+	"github.com/small-frappuccino/discordcore/pkg/observability"
+)
+
+const gatewayPanicComponent = "gateway"
+
+// GuardGatewayHandler wraps a gateway event handler so a panic inside it is
+// recovered, logged with its stack and counted instead of crashing the
+// process. The returned function keeps fn's signature, so it can be passed to
+// arikawa's reflection-based AddHandler unchanged.
+func GuardGatewayHandler[E any](handler string, fn func(E)) func(E) {
+	return func(ev E) {
+		defer RecoverGatewayPanic(handler)
+		fn(ev)
+	}
+}
+
+// RecoverGatewayPanic recovers a panic raised by the named gateway handler.
+// It must be deferred directly by the handler; calling it any other way is a
+// no-op.
+func RecoverGatewayPanic(handler string) {
+	if r := recover(); r != nil {
+		observability.RecordRecoveredPanic(gatewayPanicComponent, handler)
+		log.DiscordLogger().Error("Blocking structural failure: Recovered panic in gateway handler",
+			slog.String("handler", handler),
+			slog.String("panic", fmt.Sprint(r)),
+			slog.String("stack", string(debug.Stack())),
+		)
+	}
+}
@@ -0,0 +1,34 @@
+package perf
+
+import (
+	"testing"
+
+	"github.com/small-frappuccino/discordcore/pkg/observability"
+)
+
+func TestGuardGatewayHandlerRecoversAndCounts(t *testing.T) {
+	const handler = "test.guard_recovers"
+	key := gatewayPanicComponent + "/" + handler
+	before := observability.SnapshotRecoveredPanics()[key]
+
+	var seen int
+	guarded := GuardGatewayHandler(handler, func(n *int) {
+		seen = *n
+		var nilMap map[string]int
+		nilMap["boom"] = 1
+	})
+	value := 7
+	guarded(&value)
+
+	if seen != 7 {
+		t.Fatalf("expected wrapped handler to receive the event, got %d", seen)
+	}
+	if got := observability.SnapshotRecoveredPanics()[key]; got != before+1 {
+		t.Fatalf("expected recovered panic counter %d, got %d", before+1, got)
+	}
+
+	GuardGatewayHandler(handler, func(*int) {})(&value)
+	if got := observability.SnapshotRecoveredPanics()[key]; got != before+1 {
+		t.Fatalf("expected clean run not to count a panic, got %d", got)
+	}
+}
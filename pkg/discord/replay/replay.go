@@ -0,0 +1,179 @@
+// Package replay captures gateway dispatch events as JSON lines and feeds
+// them back through a state's handlers. A capture taken from a live bot lets
+// logging and automod behaviour be regression tested against the event shapes
+// Discord really sends.
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/diamondburned/arikawa/v3/utils/ws"
+)
+
+const (
+	// dispatchOp is the gateway opcode of real events; heartbeats and other
+	// control frames are not captured.
+	dispatchOp ws.OpCode = 0
+
+	// maxLineSize bounds one captured event. GUILD_CREATE for a large guild
+	// is the biggest payload a capture holds.
+	maxLineSize = 64 << 20
+)
+
+// Record is one line of a capture.
+type Record struct {
+	Type ws.EventType    `json:"t"`
+	At   time.Time       `json:"at"`
+	Data json.RawMessage `json:"d"`
+}
+
+// Recorder writes the dispatch events a state receives to w, one Record per
+// line, in the order the gateway delivered them.
+type Recorder struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	now    func() time.Time
+	err    error
+	closed bool
+}
+
+// NewRecorder returns a Recorder writing to w. w is written from the gateway
+// event loop, so it should be buffered.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w), now: time.Now}
+}
+
+// Attach starts recording the events st receives and returns the function
+// that stops it. It hooks the session synchronously, ahead of the state's own
+// handlers, so the order of a capture is the order of the gateway. Attach
+// before Open to capture READY and the initial GUILD_CREATEs.
+func (r *Recorder) Attach(st *state.State) (detach func()) {
+	return st.Session.AddSyncHandler(func(ev gateway.Event) {
+		if ev != nil && ev.Op() == dispatchOp {
+			r.record(ev)
+		}
+	})
+}
+
+// Err returns the first error writing the capture hit. The Recorder stops
+// writing after it.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Close stops the Recorder. Once it returns nothing more is written, so w
+// can be flushed and closed. It returns the same error as Err.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	return r.err
+}
+
+func (r *Recorder) record(ev gateway.Event) {
+	data, err := json.Marshal(ev)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed || r.err != nil {
+		return
+	}
+	if err != nil {
+		r.err = fmt.Errorf("Recorder.record: marshal %s: %w", ev.EventType(), err)
+		return
+	}
+	if err := r.enc.Encode(Record{Type: ev.EventType(), At: r.now().UTC(), Data: data}); err != nil {
+		r.err = fmt.Errorf("Recorder.record: %w", err)
+	}
+}
+
+// ReadEvents decodes a capture into the gateway events it holds. Blank lines
+// are skipped; an event type arikawa does not know is an error, since the
+// handlers under test could never have seen it.
+func ReadEvents(r io.Reader) ([]gateway.Event, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), maxLineSize)
+	var events []gateway.Event
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		ev, err := decodeRecord(sc.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("ReadEvents: line %d: %w", line, err)
+		}
+		events = append(events, ev)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("ReadEvents: %w", err)
+	}
+	return events, nil
+}
+
+func decodeRecord(b []byte) (gateway.Event, error) {
+	var rec Record
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, err
+	}
+	if rec.Type == "" {
+		return nil, errors.New("record has no event type")
+	}
+	newEvent := gateway.OpUnmarshalers.Lookup(dispatchOp, rec.Type)
+	if newEvent == nil {
+		return nil, fmt.Errorf("unknown event %s", rec.Type)
+	}
+	ev := newEvent()
+	if len(rec.Data) > 0 {
+		if err := json.Unmarshal(rec.Data, ev); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", rec.Type, err)
+		}
+	}
+	return ev, nil
+}
+
+// Player feeds events to a state as if its gateway had received them: the
+// state updates its cabinet, then calls its handlers.
+//
+// Handlers added with AddHandler run on goroutines of their own, so the work
+// an event triggers may still be running when Next returns. When order
+// matters, wait for each event's effect before stepping to the next.
+type Player struct {
+	st     *state.State
+	events []gateway.Event
+	next   int
+}
+
+// NewPlayer returns a Player of events against st. st is never opened.
+func NewPlayer(st *state.State, events []gateway.Event) *Player {
+	return &Player{st: st, events: events}
+}
+
+// Next dispatches the next event and returns it, or returns false when all
+// of them have been played.
+func (p *Player) Next() (gateway.Event, bool) {
+	if p.next >= len(p.events) {
+		return nil, false
+	}
+	ev := p.events[p.next]
+	p.next++
+	p.st.Session.Call(ev)
+	return ev, true
+}
+
+// Rest dispatches every event not played yet.
+func (p *Player) Rest() {
+	for {
+		if _, ok := p.Next(); !ok {
+			return
+		}
+	}
+}
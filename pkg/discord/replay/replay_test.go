@@ -0,0 +1,95 @@
+package replay
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+	"github.com/diamondburned/arikawa/v3/gateway"
+	"github.com/diamondburned/arikawa/v3/state"
+)
+
+func TestRecorderRoundTrip(t *testing.T) {
+	t.Parallel()
+	st := state.New("Bot token")
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf)
+	rec.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+	detach := rec.Attach(st)
+
+	msg := discord.Message{ID: 111, ChannelID: 20, GuildID: 1, Author: discord.User{ID: 7, Username: "alice"}, Content: "hello"}
+	st.Session.Call(&gateway.MessageCreateEvent{Message: msg})
+	st.Session.Call(&gateway.HeartbeatAckEvent{})
+	st.Session.Call(&gateway.MessageDeleteEvent{ID: 111, ChannelID: 20, GuildID: 1})
+	detach()
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Recorder: %v", err)
+	}
+	st.Session.Call(&gateway.MessageDeleteEvent{ID: 112, ChannelID: 20, GuildID: 1})
+	rec.record(&gateway.MessageDeleteEvent{ID: 113, ChannelID: 20, GuildID: 1})
+
+	if !strings.Contains(buf.String(), `"at":"2026-10-16T12:00:00Z"`) {
+		t.Fatalf("expected records to carry their time, got %s", buf.String())
+	}
+	events, err := ReadEvents(&buf)
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected the two dispatches before detach and close, got %d events", len(events))
+	}
+	create, ok := events[0].(*gateway.MessageCreateEvent)
+	if !ok || create.ID != 111 || create.Author.Username != "alice" || create.Content != "hello" {
+		t.Fatalf("expected the message create back, got %#v", events[0])
+	}
+	if del, ok := events[1].(*gateway.MessageDeleteEvent); !ok || del.ID != 111 {
+		t.Fatalf("expected the message delete back, got %#v", events[1])
+	}
+}
+
+func TestReadEventsErrors(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"unknown type": `{"t":"NOT_AN_EVENT","d":{}}`,
+		"missing type": `{"d":{}}`,
+		"bad payload":  `{"t":"MESSAGE_DELETE","d":{"id":[]}}`,
+		"not json":     `MESSAGE_DELETE`,
+	}
+	for name, capture := range tests {
+		if _, err := ReadEvents(strings.NewReader("\n" + capture + "\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("%s: expected an error naming line 2, got %v", name, err)
+		}
+	}
+}
+
+func TestPlayer(t *testing.T) {
+	t.Parallel()
+	events, err := ReadEvents(strings.NewReader(`{"t":"CHANNEL_CREATE","d":{"id":"20","guild_id":"1","type":0,"name":"general"}}
+{"t":"MESSAGE_DELETE","d":{"id":"111","channel_id":"20","guild_id":"1"}}
+`))
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+	st := state.New("Bot token")
+	var seen []discord.ChannelID
+	st.AddSyncHandler(func(ev *gateway.MessageDeleteEvent) {
+		seen = append(seen, ev.ChannelID)
+	})
+
+	p := NewPlayer(st, events)
+	if ev, ok := p.Next(); !ok || ev.EventType() != "CHANNEL_CREATE" {
+		t.Fatalf("expected the channel create first, got %v", ev)
+	}
+	if ch, err := st.Cabinet.Channel(20); err != nil || ch.Name != "general" {
+		t.Fatalf("expected the state to cache the replayed channel, got %v, %v", ch, err)
+	}
+	p.Rest()
+	if len(seen) != 1 || seen[0] != 20 {
+		t.Fatalf("expected the delete to reach the handlers, got %v", seen)
+	}
+	if _, ok := p.Next(); ok {
+		t.Fatal("expected the player to be exhausted")
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/diamondburned/arikawa/v3/gateway"
 	"github.com/diamondburned/arikawa/v3/state"
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
 	domain "github.com/small-frappuccino/discordcore/pkg/stats"
 )
 
@@ -14,17 +15,17 @@ func RegisterEventHandlers(s *state.State, svc *domain.StatsService, logger *slo
 	if logger != nil {
 		logger.Info("Registered Arikawa event handlers for stats")
 	}
-	s.AddHandler(func(e *gateway.GuildMemberAddEvent) {
+	s.AddHandler(perf.GuardGatewayHandler("stats.member_add", func(e *gateway.GuildMemberAddEvent) {
 		handleArikawaGuildMemberAdd(svc, e)
-	})
+	}))
 
-	s.AddHandler(func(e *gateway.GuildMemberRemoveEvent) {
+	s.AddHandler(perf.GuardGatewayHandler("stats.member_remove", func(e *gateway.GuildMemberRemoveEvent) {
 		handleArikawaGuildMemberRemove(svc, e)
-	})
+	}))
 
-	s.AddHandler(func(e *gateway.GuildMemberUpdateEvent) {
+	s.AddHandler(perf.GuardGatewayHandler("stats.member_update", func(e *gateway.GuildMemberUpdateEvent) {
 		handleArikawaGuildMemberUpdate(svc, e)
-	})
+	}))
 }
 
 func handleArikawaGuildMemberAdd(svc *domain.StatsService, e *gateway.GuildMemberAddEvent) {
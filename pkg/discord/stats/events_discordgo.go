@@ -3,6 +3,7 @@ package stats
 import (
 	"log/slog"
 
+	"github.com/small-frappuccino/discordcore/pkg/discord/perf"
 	domain "github.com/small-frappuccino/discordcore/pkg/stats"
 	"github.com/small-frappuccino/discordgo"
 )
@@ -17,14 +18,17 @@ func RegisterDiscordGoEventHandlers(session *discordgo.Session, svc *domain.Stat
 		logger.Info("Registered DiscordGo event handlers for stats")
 	}
 	session.AddHandler(func(s *discordgo.Session, m *discordgo.GuildMemberAdd) {
+		defer perf.RecoverGatewayPanic("stats.member_add")
 		handleDiscordGoGuildMemberAdd(svc, m)
 	})
 
 	session.AddHandler(func(s *discordgo.Session, m *discordgo.GuildMemberRemove) {
+		defer perf.RecoverGatewayPanic("stats.member_remove")
 		handleDiscordGoGuildMemberRemove(svc, m)
 	})
 
 	session.AddHandler(func(s *discordgo.Session, m *discordgo.GuildMemberUpdate) {
+		defer perf.RecoverGatewayPanic("stats.member_update")
 		handleDiscordGoGuildMemberUpdate(svc, m)
 	})
 }
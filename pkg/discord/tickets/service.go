@@ -11,19 +11,39 @@ import (
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/state"
 	"github.com/diamondburned/arikawa/v3/utils/sendpart"
+	"github.com/small-frappuccino/discordcore/pkg/clean"
+	discordclean "github.com/small-frappuccino/discordcore/pkg/discord/clean"
 	pkgtickets "github.com/small-frappuccino/discordcore/pkg/tickets"
 	"golang.org/x/sync/errgroup"
 )
 
+// maxArchivedTicketMessages bounds the history archived before a ticket
+// channel is deleted.
+const maxArchivedTicketMessages = 1000
+
 // Service encapsulates the Arikawa-specific operations for tickets.
 type Service struct {
-	state  *state.State
-	logger *slog.Logger
+	state    *state.State
+	logger   *slog.Logger
+	archiver clean.Archiver
+}
+
+// Option configures optional Service behavior.
+type Option func(*Service)
+
+// WithArchiver snapshots a ticket's messages with archiver before its
+// channel is deleted. A failing archiver keeps the channel.
+func WithArchiver(archiver clean.Archiver) Option {
+	return func(s *Service) { s.archiver = archiver }
 }
 
 // NewService constructs the Discord ticket service.
-func NewService(state *state.State, logger *slog.Logger) *Service {
-	return &Service{state: state, logger: logger}
+func NewService(state *state.State, logger *slog.Logger, opts ...Option) *Service {
+	s := &Service{state: state, logger: logger}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // CreateTicketChannel spawns the ticket channel and applies initial permissions.
@@ -264,8 +284,20 @@ func (s *Service) ReopenTicket(ctx context.Context, ch *discord.Channel) error {
 	return nil
 }
 
-// DeleteTicket completely removes the channel.
+// DeleteTicket completely removes the channel, archiving its messages first
+// when an archiver is configured.
 func (s *Service) DeleteTicket(ctx context.Context, channelID discord.ChannelID) error {
+	if s.archiver != nil {
+		if err := s.archiveTicket(ctx, channelID); err != nil {
+			s.logger.Error("failed to archive ticket before deletion",
+				slog.String("channelID", channelID.String()),
+				slog.String("synthetic_fault_code", "500"),
+				slog.String("error", err.Error()),
+			)
+			return fmt.Errorf("archive ticket: %w", err)
+		}
+	}
+
 	err := s.state.Client.DeleteChannel(channelID, api.AuditLogReason(""))
 	if err != nil {
 		s.logger.Error("failed to delete ticket channel",
@@ -276,3 +308,26 @@ func (s *Service) DeleteTicket(ctx context.Context, channelID discord.ChannelID)
 	}
 	return err
 }
+
+func (s *Service) archiveTicket(ctx context.Context, channelID discord.ChannelID) error {
+	ch, err := s.state.Channel(channelID)
+	if err != nil {
+		return fmt.Errorf("fetch channel: %w", err)
+	}
+	messages, err := s.state.Client.Messages(channelID, maxArchivedTicketMessages)
+	if err != nil {
+		return fmt.Errorf("fetch messages: %w", err)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	converted := make([]clean.Message, 0, len(messages))
+	for _, m := range messages {
+		converted = append(converted, discordclean.MessageFrom(m))
+	}
+	return s.archiver(ctx, clean.Deletion{
+		GuildID:   ch.GuildID.String(),
+		ChannelID: channelID.String(),
+		Messages:  converted,
+	})
+}
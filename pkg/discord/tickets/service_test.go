@@ -3,18 +3,21 @@ package tickets
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/diamondburned/arikawa/v3/discord"
 	"github.com/diamondburned/arikawa/v3/state"
 	"github.com/diamondburned/arikawa/v3/utils/httputil/httpdriver"
+	"github.com/small-frappuccino/discordcore/pkg/clean"
 	"go.uber.org/goleak"
 )
 
@@ -170,3 +173,55 @@ func TestService_GenerateAndUploadTranscript_Deadlock(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
+
+func TestService_DeleteTicket_ArchivesFirst(t *testing.T) {
+	t.Parallel()
+
+	var deleted atomic.Bool
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/messages"):
+			json.NewEncoder(w).Encode([]discord.Message{{ID: 11, Content: "thanks"}, {ID: 10, Content: "help"}})
+		case r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(discord.Channel{ID: 1, GuildID: 5, Name: "ticket-1"})
+		case r.Method == http.MethodDelete:
+			deleted.Store(true)
+			json.NewEncoder(w).Encode(discord.Channel{ID: 1})
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer mockServer.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	var archived []clean.Deletion
+	fail := false
+	s := NewService(newMockClient(t, mockServer.URL), logger, WithArchiver(func(_ context.Context, d clean.Deletion) error {
+		if deleted.Load() {
+			t.Error("channel deleted before the archive")
+		}
+		if fail {
+			return errors.New("storage unavailable")
+		}
+		archived = append(archived, d)
+		return nil
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	fail = true
+	if err := s.DeleteTicket(ctx, 1); err == nil || deleted.Load() {
+		t.Fatalf("DeleteTicket with a failing archiver: err=%v, deleted=%v", err, deleted.Load())
+	}
+
+	fail = false
+	if err := s.DeleteTicket(ctx, 1); err != nil {
+		t.Fatalf("DeleteTicket: %v", err)
+	}
+	if !deleted.Load() {
+		t.Fatal("channel was not deleted")
+	}
+	if len(archived) != 1 || archived[0].GuildID != "5" || len(archived[0].Messages) != 2 || archived[0].Messages[1].Content != "help" {
+		t.Fatalf("archived %+v", archived)
+	}
+}
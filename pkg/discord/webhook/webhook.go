@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -150,6 +151,10 @@ func ParseWebhookURL(rawURL string) (discord.WebhookID, string, error) {
 		if err != nil {
 			return 0, "", fmt.Errorf("invalid webhook_id: %w", err)
 		}
+		// ParseSnowflake accepts "0" and "null", neither of which is a webhook.
+		if !sf.IsValid() {
+			return 0, "", fmt.Errorf("invalid webhook_id %q", webhookIDStr)
+		}
 
 		return discord.WebhookID(sf), webhookToken, nil
 	}
@@ -319,6 +324,18 @@ func decodeEmbeds(raw json.RawMessage) ([]discord.Embed, error) {
 	return []discord.Embed{embed}, nil
 }
 
+// ClassifyError sorts the error of a failed webhook request, so callers can
+// tell failures worth waiting out from ones that are not. Timeouts count as
+// temporary. It returns nil for a nil err.
+func ClassifyError(operation string, err error) *TargetValidationError {
+	if err == nil {
+		return nil
+	}
+	var classified *TargetValidationError
+	errors.As(wrapTargetValidationError(operation, err), &classified)
+	return classified
+}
+
 func wrapTargetValidationError(operation string, err error) error {
 	// Map specific HTTP status codes to internal operational failure classes for deterministic error handling.
 	var httpErr *httputil.HTTPError
@@ -369,6 +386,17 @@ func wrapTargetValidationError(operation string, err error) error {
 		}
 	}
 
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return &TargetValidationError{
+			Operation:  operation,
+			StatusCode: 0,
+			Class:      TargetValidationClassDiscordUnavailable,
+			Temporary:  true,
+			Cause:      err,
+		}
+	}
+
 	if strings.Contains(err.Error(), "HTTP 5") {
 		return &TargetValidationError{
 			Operation:  operation,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -142,6 +143,40 @@ func TestValidateMessageTarget_ErrorAssertions(t *testing.T) {
 	}
 }
 
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		err           error
+		wantClass     webhookPkg.TargetValidationClass
+		wantTemporary bool
+	}{
+		{"Rate Limited 429", &httputil.HTTPError{Status: http.StatusTooManyRequests}, webhookPkg.TargetValidationClassRateLimited, true},
+		{"Unavailable 502", &httputil.HTTPError{Status: http.StatusBadGateway}, webhookPkg.TargetValidationClassDiscordUnavailable, true},
+		{"Timeout", fmt.Errorf("execute: %w", context.DeadlineExceeded), webhookPkg.TargetValidationClassDiscordUnavailable, true},
+		{"Not Found 404", &httputil.HTTPError{Status: http.StatusNotFound}, webhookPkg.TargetValidationClassNotFound, false},
+		{"Unknown", errors.New("boom"), webhookPkg.TargetValidationClassUnknown, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classified := webhookPkg.ClassifyError("execute", tt.err)
+			if classified == nil {
+				t.Fatalf("expected a classification for %v", tt.err)
+			}
+			if classified.Class != tt.wantClass || classified.Temporary != tt.wantTemporary {
+				t.Fatalf("expected %s (temporary %v), got %s (temporary %v)", tt.wantClass, tt.wantTemporary, classified.Class, classified.Temporary)
+			}
+			if !errors.Is(classified, tt.err) && !errors.Is(classified.Cause, tt.err) {
+				t.Fatalf("expected the cause to be kept")
+			}
+		})
+	}
+	if webhookPkg.ClassifyError("execute", nil) != nil {
+		t.Fatal("expected no classification for a nil error")
+	}
+}
+
 func TestDecodeEmbeds_Fuzzing(t *testing.T) {
 	t.Parallel()
 	payloads := []string{
@@ -286,3 +321,42 @@ func TestWebhookConcurrentExecution(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+// FuzzDecodeEmbeds feeds arbitrary embed JSON, as pasted into the webhook
+// commands, to the decoder. A payload either errors or yields embeds.
+func FuzzDecodeEmbeds(f *testing.F) {
+	f.Add(`{"title":"object"}`)
+	f.Add(`[{"title":"array"}]`)
+	f.Add(`{"embeds":[{"title":"nested","fields":[{"name":"a","value":"b"}]}]}`)
+	f.Add(`{"embeds":[]}`)
+	f.Add(`{"embeds":null}`)
+	f.Add(`  [`)
+
+	f.Fuzz(func(t *testing.T, payload string) {
+		embeds, err := webhookPkg.ExportDecodeEmbeds(json.RawMessage(payload))
+		if err == nil && len(embeds) == 0 {
+			t.Fatalf("decodeEmbeds(%q) returned no embeds and no error", payload)
+		}
+	})
+}
+
+// FuzzParseWebhookURL checks that any URL an admin passes either errors or
+// yields a valid webhook ID and a token.
+func FuzzParseWebhookURL(f *testing.F) {
+	f.Add("https://discord.com/api/webhooks/123456789012345678/token")
+	f.Add("https://discord.com/api/v10/webhooks/1/t/messages/2")
+	f.Add("https://discord.com/api/webhooks//")
+	f.Add("https://discord.com/api/webhooks/0/token")
+	f.Add("https://discord.com/api/webhooks/null/token")
+	f.Add("%zz")
+
+	f.Fuzz(func(t *testing.T, rawURL string) {
+		id, token, err := webhookPkg.ParseWebhookURL(rawURL)
+		if err != nil {
+			return
+		}
+		if !id.IsValid() || token == "" {
+			t.Fatalf("ParseWebhookURL(%q) = %v, %q without an error", rawURL, id, token)
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package files
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AssetsConfig governs the stickers members upload to the guild.
+type AssetsConfig struct {
+	// RequireApproval holds new stickers for staff: each is posted to
+	// ApprovalChannelID with buttons to approve it, which announces it, or
+	// to reject it, which deletes it.
+	RequireApproval   bool   `json:"require_approval,omitempty"`
+	ApprovalChannelID string `json:"approval_channel_id,omitempty"`
+	// AnnounceChannelID is where new stickers are shown to members. Empty
+	// leaves them unannounced.
+	AnnounceChannelID string `json:"announce_channel_id,omitempty"`
+}
+
+// Governed reports whether new stickers are announced or held for approval.
+func (c AssetsConfig) Governed() bool {
+	return c.RequireApproval || strings.TrimSpace(c.AnnounceChannelID) != ""
+}
+
+func validateAssets(cfg AssetsConfig, guildIndex int) error {
+	approval := strings.TrimSpace(cfg.ApprovalChannelID)
+	if cfg.RequireApproval && approval == "" {
+		return NewValidationError(fmt.Sprintf("guilds[%d].assets.approval_channel_id", guildIndex), cfg.ApprovalChannelID, "an approval channel is required when stickers require approval")
+	}
+	if approval != "" && !isAllDigits(approval) {
+		return NewValidationError(fmt.Sprintf("guilds[%d].assets.approval_channel_id", guildIndex), cfg.ApprovalChannelID, "channel must be a numeric ID")
+	}
+	if announce := strings.TrimSpace(cfg.AnnounceChannelID); announce != "" && !isAllDigits(announce) {
+		return NewValidationError(fmt.Sprintf("guilds[%d].assets.announce_channel_id", guildIndex), cfg.AnnounceChannelID, "channel must be a numeric ID")
+	}
+	return nil
+}
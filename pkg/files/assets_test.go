@@ -0,0 +1,31 @@
+package files
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateBotConfigAssets(t *testing.T) {
+	t.Parallel()
+
+	for _, assets := range []AssetsConfig{
+		{RequireApproval: true},
+		{RequireApproval: true, ApprovalChannelID: "staff"},
+		{AnnounceChannelID: "#stickers"},
+	} {
+		cfg := &BotConfig{Guilds: []GuildConfig{{GuildID: "g1", Assets: assets}}}
+		var verr ValidationError
+		if err := validateBotConfig(cfg); !errors.As(err, &verr) || verr.Field == "" {
+			t.Fatalf("expected an assets validation error for %+v, got %v", assets, err)
+		}
+	}
+
+	cfg := &BotConfig{Guilds: []GuildConfig{{GuildID: "g1", Assets: AssetsConfig{
+		RequireApproval:   true,
+		ApprovalChannelID: "123",
+		AnnounceChannelID: "456",
+	}}}}
+	if err := validateBotConfig(cfg); err != nil {
+		t.Fatalf("expected sticker approval to validate, got %v", err)
+	}
+}
@@ -0,0 +1,251 @@
+package files
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+const (
+	// MaxAttachmentFilterEntries bounds the blocked extensions and content
+	// types of a guild or channel override.
+	MaxAttachmentFilterEntries = 50
+	// MaxAttachmentFilterOverrides bounds the channel overrides of a guild.
+	MaxAttachmentFilterOverrides = 50
+	// MaxAttachmentSizeMB is the largest upload Discord accepts.
+	MaxAttachmentSizeMB = 500
+
+	maxAttachmentExtensionLength = 16
+)
+
+// ExecutableExtensions are the file extensions BlockExecutables blocks.
+var ExecutableExtensions = []string{
+	"apk", "app", "bat", "cmd", "com", "cpl", "dll", "dmg", "exe", "hta", "jar",
+	"js", "jse", "lnk", "msi", "msp", "pif", "ps1", "reg", "scr", "sh", "vbe",
+	"vbs", "ws", "wsf",
+}
+
+// AttachmentRules are the checks one message's attachments must pass.
+type AttachmentRules struct {
+	// BlockedExtensions are file extensions, without the dot, matched
+	// case-insensitively.
+	BlockedExtensions []string `json:"blocked_extensions,omitempty"`
+	// BlockedContentTypes are MIME types such as application/zip, or whole
+	// families such as video/*.
+	BlockedContentTypes []string `json:"blocked_content_types,omitempty"`
+	// MaxSizeMB blocks files larger than this many megabytes; 0 allows any
+	// size.
+	MaxSizeMB int `json:"max_size_mb,omitempty"`
+}
+
+func (r AttachmentRules) empty() bool {
+	return len(r.BlockedExtensions) == 0 && len(r.BlockedContentTypes) == 0 && r.MaxSizeMB == 0
+}
+
+// BlocksExtension reports whether a file named filename is blocked by its
+// extension.
+func (r AttachmentRules) BlocksExtension(filename string) bool {
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(filename), "."))
+	if ext == "" {
+		return false
+	}
+	return slices.ContainsFunc(r.BlockedExtensions, func(blocked string) bool {
+		return normalizeAttachmentExtension(blocked) == ext
+	})
+}
+
+// BlocksContentType reports whether a file of contentType is blocked. Any
+// parameters, as in text/plain; charset=utf-8, are ignored.
+func (r AttachmentRules) BlocksContentType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+	mediaType = strings.TrimSpace(mediaType)
+	if mediaType == "" {
+		return false
+	}
+	family, _, _ := strings.Cut(mediaType, "/")
+	for _, blocked := range r.BlockedContentTypes {
+		blocked = strings.ToLower(strings.TrimSpace(blocked))
+		if blocked == mediaType || blocked == family+"/*" {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxSizeBytes returns the size limit in bytes, or 0 when there is none.
+func (r AttachmentRules) MaxSizeBytes() uint64 {
+	return uint64(r.MaxSizeMB) << 20
+}
+
+// AttachmentFilterOverride replaces the attachment rules of the guild in one
+// channel or category. Each field it sets replaces the guild's; a channel
+// where no file is blocked belongs in ExemptChannelIDs instead.
+type AttachmentFilterOverride struct {
+	ChannelID string `json:"channel_id"`
+	AttachmentRules
+}
+
+// AttachmentFilterConfig deletes messages carrying blocked files: those with
+// a blocked extension or content type, executables when BlockExecutables is
+// set, or files over the size limit. TimeoutMinutes, when set, also times
+// the author out.
+type AttachmentFilterConfig struct {
+	AttachmentRules
+	BlockExecutables bool                       `json:"block_executables,omitempty"`
+	ChannelOverrides []AttachmentFilterOverride `json:"channel_overrides,omitempty"`
+	TimeoutMinutes   int                        `json:"timeout_minutes,omitempty"`
+	ExemptRoleIDs    []string                   `json:"exempt_role_ids,omitempty"`
+	ExemptChannelIDs []string                   `json:"exempt_channel_ids,omitempty"`
+}
+
+// Enabled reports whether any check is set, for the guild or a channel.
+func (c AttachmentFilterConfig) Enabled() bool {
+	if c.BlockExecutables || !c.AttachmentRules.empty() {
+		return true
+	}
+	return slices.ContainsFunc(c.ChannelOverrides, func(o AttachmentFilterOverride) bool {
+		return !o.AttachmentRules.empty()
+	})
+}
+
+// Exempts reports whether a message in channelID, under categoryID, by a
+// member holding roleIDs is left alone by the filter.
+func (c AttachmentFilterConfig) Exempts(channelID, categoryID string, roleIDs []string) bool {
+	return AutomodRule{ExemptRoleIDs: c.ExemptRoleIDs, ExemptChannelIDs: c.ExemptChannelIDs}.Exempts(channelID, categoryID, roleIDs)
+}
+
+// RulesFor returns the rules applying in channelID, under categoryID: those
+// of the guild, with the fields set by the override of the channel, or
+// failing that of its category, replaced. BlockExecutables holds in every
+// channel.
+func (c AttachmentFilterConfig) RulesFor(channelID, categoryID string) AttachmentRules {
+	rules := c.AttachmentRules
+	override, ok := c.override(channelID)
+	if !ok && categoryID != "" {
+		override, ok = c.override(categoryID)
+	}
+	if ok {
+		if len(override.BlockedExtensions) > 0 {
+			rules.BlockedExtensions = override.BlockedExtensions
+		}
+		if len(override.BlockedContentTypes) > 0 {
+			rules.BlockedContentTypes = override.BlockedContentTypes
+		}
+		if override.MaxSizeMB != 0 {
+			rules.MaxSizeMB = override.MaxSizeMB
+		}
+	}
+	if c.BlockExecutables {
+		rules.BlockedExtensions = append(slices.Clip(rules.BlockedExtensions), ExecutableExtensions...)
+	}
+	return rules
+}
+
+func (c AttachmentFilterConfig) override(channelID string) (AttachmentFilterOverride, bool) {
+	for _, o := range c.ChannelOverrides {
+		if strings.TrimSpace(o.ChannelID) == channelID {
+			return o, true
+		}
+	}
+	return AttachmentFilterOverride{}, false
+}
+
+func normalizeAttachmentExtension(ext string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ext), "."))
+}
+
+func validateAttachmentFilter(cfg AttachmentFilterConfig, guildIndex int) error {
+	field := func(name string) string { return fmt.Sprintf("guilds[%d].attachment_filter.%s", guildIndex, name) }
+	if err := validateAttachmentRules(cfg.AttachmentRules, field); err != nil {
+		return err
+	}
+	if len(cfg.ChannelOverrides) > MaxAttachmentFilterOverrides {
+		return NewValidationError(field("channel_overrides"), len(cfg.ChannelOverrides),
+			fmt.Sprintf("at most %d channel overrides are allowed", MaxAttachmentFilterOverrides))
+	}
+	seen := make(map[string]struct{}, len(cfg.ChannelOverrides))
+	for idx, override := range cfg.ChannelOverrides {
+		overrideField := func(name string) string { return fmt.Sprintf("%s[%d].%s", field("channel_overrides"), idx, name) }
+		channelID := strings.TrimSpace(override.ChannelID)
+		if !isAllDigits(channelID) {
+			return NewValidationError(overrideField("channel_id"), override.ChannelID, "channel must be a numeric ID")
+		}
+		if _, dup := seen[channelID]; dup {
+			return NewValidationError(overrideField("channel_id"), override.ChannelID, "channel already has an override")
+		}
+		seen[channelID] = struct{}{}
+		if err := validateAttachmentRules(override.AttachmentRules, overrideField); err != nil {
+			return err
+		}
+	}
+	if cfg.TimeoutMinutes < 0 || cfg.TimeoutMinutes > MaxAutomodTimeoutMinutes {
+		return NewValidationError(field("timeout_minutes"), cfg.TimeoutMinutes,
+			fmt.Sprintf("timeout_minutes must be between 0 and %d", MaxAutomodTimeoutMinutes))
+	}
+	for idx, roleID := range cfg.ExemptRoleIDs {
+		if !isAllDigits(strings.TrimSpace(roleID)) {
+			return NewValidationError(fmt.Sprintf("%s[%d]", field("exempt_role_ids"), idx), roleID, "role must be a numeric ID")
+		}
+	}
+	for idx, channelID := range cfg.ExemptChannelIDs {
+		if !isAllDigits(strings.TrimSpace(channelID)) {
+			return NewValidationError(fmt.Sprintf("%s[%d]", field("exempt_channel_ids"), idx), channelID, "channel must be a numeric ID")
+		}
+	}
+	return nil
+}
+
+func validateAttachmentRules(rules AttachmentRules, field func(string) string) error {
+	if len(rules.BlockedExtensions) > MaxAttachmentFilterEntries {
+		return NewValidationError(field("blocked_extensions"), len(rules.BlockedExtensions),
+			fmt.Sprintf("at most %d extensions are allowed", MaxAttachmentFilterEntries))
+	}
+	for idx, ext := range rules.BlockedExtensions {
+		normalized := normalizeAttachmentExtension(ext)
+		if normalized == "" || len(normalized) > maxAttachmentExtensionLength || strings.ContainsAny(normalized, "./\\ ") {
+			return NewValidationError(fmt.Sprintf("%s[%d]", field("blocked_extensions"), idx), ext,
+				fmt.Sprintf("extensions are 1 to %d characters without dots or slashes", maxAttachmentExtensionLength))
+		}
+	}
+	if len(rules.BlockedContentTypes) > MaxAttachmentFilterEntries {
+		return NewValidationError(field("blocked_content_types"), len(rules.BlockedContentTypes),
+			fmt.Sprintf("at most %d content types are allowed", MaxAttachmentFilterEntries))
+	}
+	for idx, contentType := range rules.BlockedContentTypes {
+		family, subtype, ok := strings.Cut(strings.TrimSpace(contentType), "/")
+		if !ok || family == "" || subtype == "" || strings.ContainsAny(contentType, "; ") {
+			return NewValidationError(fmt.Sprintf("%s[%d]", field("blocked_content_types"), idx), contentType,
+				"content types look like application/zip or video/*")
+		}
+	}
+	if rules.MaxSizeMB < 0 || rules.MaxSizeMB > MaxAttachmentSizeMB {
+		return NewValidationError(field("max_size_mb"), rules.MaxSizeMB,
+			fmt.Sprintf("max_size_mb must be between 0 and %d", MaxAttachmentSizeMB))
+	}
+	return nil
+}
+
+func cloneAttachmentRules(in AttachmentRules) AttachmentRules {
+	out := in
+	out.BlockedExtensions = cloneStringSlice(in.BlockedExtensions)
+	out.BlockedContentTypes = cloneStringSlice(in.BlockedContentTypes)
+	return out
+}
+
+func cloneAttachmentFilterConfig(in AttachmentFilterConfig) AttachmentFilterConfig {
+	out := in
+	out.AttachmentRules = cloneAttachmentRules(in.AttachmentRules)
+	if in.ChannelOverrides != nil {
+		out.ChannelOverrides = make([]AttachmentFilterOverride, len(in.ChannelOverrides))
+		for i, override := range in.ChannelOverrides {
+			out.ChannelOverrides[i] = AttachmentFilterOverride{
+				ChannelID:       override.ChannelID,
+				AttachmentRules: cloneAttachmentRules(override.AttachmentRules),
+			}
+		}
+	}
+	out.ExemptRoleIDs = cloneStringSlice(in.ExemptRoleIDs)
+	out.ExemptChannelIDs = cloneStringSlice(in.ExemptChannelIDs)
+	return out
+}
@@ -0,0 +1,89 @@
+package files
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateAttachmentFilter(t *testing.T) {
+	t.Parallel()
+
+	valid := AttachmentFilterConfig{
+		AttachmentRules:  AttachmentRules{BlockedExtensions: []string{".zip"}, BlockedContentTypes: []string{"video/*"}, MaxSizeMB: 8},
+		BlockExecutables: true,
+		ChannelOverrides: []AttachmentFilterOverride{{ChannelID: "10", AttachmentRules: AttachmentRules{MaxSizeMB: 50}}},
+	}
+	if err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", AttachmentFilter: valid}}}); err != nil {
+		t.Fatalf("valid attachment filter rejected: %v", err)
+	}
+	if gc := (GuildConfig{AttachmentFilter: valid}); !gc.ModeratesContent() {
+		t.Fatal("an attachment filter should make the bot moderate content")
+	}
+
+	for field, filter := range map[string]AttachmentFilterConfig{
+		"guilds[0].attachment_filter.blocked_extensions[0]":    {AttachmentRules: AttachmentRules{BlockedExtensions: []string{"tar.gz"}}},
+		"guilds[0].attachment_filter.blocked_content_types[0]": {AttachmentRules: AttachmentRules{BlockedContentTypes: []string{"video"}}},
+		"guilds[0].attachment_filter.max_size_mb":              {AttachmentRules: AttachmentRules{MaxSizeMB: MaxAttachmentSizeMB + 1}},
+		"guilds[0].attachment_filter.channel_overrides[1].channel_id": {ChannelOverrides: []AttachmentFilterOverride{
+			{ChannelID: "10", AttachmentRules: AttachmentRules{MaxSizeMB: 1}},
+			{ChannelID: "10", AttachmentRules: AttachmentRules{MaxSizeMB: 2}},
+		}},
+		"guilds[0].attachment_filter.channel_overrides[0].max_size_mb": {ChannelOverrides: []AttachmentFilterOverride{
+			{ChannelID: "10", AttachmentRules: AttachmentRules{MaxSizeMB: -1}},
+		}},
+		"guilds[0].attachment_filter.timeout_minutes": {BlockExecutables: true, TimeoutMinutes: -1},
+	} {
+		var verr ValidationError
+		err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", AttachmentFilter: filter}}})
+		if !errors.As(err, &verr) || verr.Field != field {
+			t.Fatalf("expected validation error on %s, got %v", field, err)
+		}
+	}
+}
+
+func TestAttachmentFilterRulesFor(t *testing.T) {
+	t.Parallel()
+
+	cfg := AttachmentFilterConfig{
+		AttachmentRules:  AttachmentRules{BlockedExtensions: []string{"zip"}, MaxSizeMB: 8},
+		BlockExecutables: true,
+		ChannelOverrides: []AttachmentFilterOverride{
+			{ChannelID: "10", AttachmentRules: AttachmentRules{MaxSizeMB: 50}},
+			{ChannelID: "20", AttachmentRules: AttachmentRules{BlockedExtensions: []string{"png"}}},
+		},
+	}
+
+	guild := cfg.RulesFor("1", "")
+	if !guild.BlocksExtension("payload.EXE") || !guild.BlocksExtension("archive.zip") || guild.MaxSizeMB != 8 {
+		t.Fatalf("unexpected guild rules: %+v", guild)
+	}
+	if media := cfg.RulesFor("10", ""); media.MaxSizeMB != 50 || !media.BlocksExtension("archive.zip") {
+		t.Fatalf("expected channel 10 to raise the size limit only, got %+v", media)
+	}
+	// Category 20 replaces the extensions, but executables stay blocked.
+	art := cfg.RulesFor("3", "20")
+	if art.BlocksExtension("archive.zip") || !art.BlocksExtension("image.png") || !art.BlocksExtension("run.bat") {
+		t.Fatalf("unexpected rules under category 20: %+v", art)
+	}
+	if len(cfg.BlockedExtensions) != 1 {
+		t.Fatalf("RulesFor changed the guild's extensions: %v", cfg.BlockedExtensions)
+	}
+}
+
+func TestAttachmentRulesBlocksContentType(t *testing.T) {
+	t.Parallel()
+
+	rules := AttachmentRules{BlockedContentTypes: []string{"application/zip", "video/*"}}
+	for contentType, want := range map[string]bool{
+		"application/zip":           true,
+		"video/mp4":                 true,
+		"Video/WebM; codecs=vp9":    true,
+		"image/png":                 false,
+		"application/x-zip-archive": false,
+		"":                          false,
+	} {
+		if got := rules.BlocksContentType(contentType); got != want {
+			t.Errorf("BlocksContentType(%q) = %v, want %v", contentType, got, want)
+		}
+	}
+}
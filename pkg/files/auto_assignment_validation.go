@@ -3,6 +3,7 @@ package files
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 const (
@@ -50,6 +51,90 @@ func validateBotConfig(cfg *BotConfig) error {
 		if err := validateGuildAutoAssignmentOrder(&cfg.Guilds[idx], idx); err != nil {
 			return fmt.Errorf("validateBotConfig: %w", err)
 		}
+		if err := validateNotificationRoutes(cfg.Guilds[idx].NotificationRoutes, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validateAutoPurge(cfg.Guilds[idx].AutoPurge, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validateModerationProtection(cfg.Guilds[idx].ModerationProtection, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validateHealthReport(cfg.Guilds[idx].HealthReport, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validateRaidMode(cfg.Guilds[idx].RaidMode, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validateBanPools(cfg.Guilds[idx].BanPools, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validateMetricsExclusions(cfg.Guilds[idx].Stats.Exclusions, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validateAutomodRules(cfg.Guilds[idx].AutomodRules, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validateInviteFilter(cfg.Guilds[idx].InviteFilter, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validateSpamFilter(cfg.Guilds[idx].SpamFilter, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validateAttachmentFilter(cfg.Guilds[idx].AttachmentFilter, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validateJoinGate(cfg.Guilds[idx].JoinGate, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validateRetention(cfg.Guilds[idx].Retention, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validateLogWebhooks(cfg.Guilds[idx].LogWebhooks, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validateAssets(cfg.Guilds[idx].Assets, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validateDisabledCommands(cfg.Guilds[idx].DisabledCommands, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if err := validatePresenceWatch(cfg.Guilds[idx].PresenceWatch, idx); err != nil {
+			return fmt.Errorf("validateBotConfig: %w", err)
+		}
+		if tz := cfg.Guilds[idx].Timezone; tz != "" {
+			if _, err := time.LoadLocation(tz); err != nil {
+				return fmt.Errorf("validateBotConfig: %w", NewValidationError(
+					fmt.Sprintf("guilds[%d].timezone", idx),
+					tz,
+					"timezone must be an IANA zone name such as \"Europe/Berlin\"",
+				))
+			}
+		}
+		if privacy := cfg.Guilds[idx].Privacy; !privacy.Valid() {
+			return fmt.Errorf("validateBotConfig: %w", NewValidationError(
+				fmt.Sprintf("guilds[%d].privacy", idx),
+				privacy,
+				`privacy must be "standard", "hashed" or "minimal"`,
+			))
+		}
+		if confirm := cfg.Guilds[idx].Clean.ConfirmAbove; confirm < 0 {
+			return fmt.Errorf("validateBotConfig: %w", NewValidationError(
+				fmt.Sprintf("guilds[%d].clean.confirm_above", idx),
+				confirm,
+				"confirm_above must not be negative",
+			))
+		}
+		if appeal := cfg.Guilds[idx].PunishmentDM.AppealInstructions; len([]rune(appeal)) > maxAppealInstructionsLength {
+			return fmt.Errorf("validateBotConfig: %w", NewValidationError(
+				fmt.Sprintf("guilds[%d].punishment_dm.appeal_instructions", idx),
+				len([]rune(appeal)),
+				fmt.Sprintf("appeal_instructions must be at most %d characters", maxAppealInstructionsLength),
+			))
+		}
+	}
+	if err := validateConfigProfiles(cfg); err != nil {
+		return fmt.Errorf("validateBotConfig: %w", err)
 	}
 
 	return nil
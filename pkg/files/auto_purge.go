@@ -0,0 +1,73 @@
+package files
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AutoPurgeConfig lists the channels purged every night and the messages a
+// purge never removes. Pinned messages are always kept.
+type AutoPurgeConfig struct {
+	Channels []AutoPurgeChannelConfig `json:"channels,omitempty"`
+	// ExemptUserIDs and ExemptRoleIDs keep the messages of these users and
+	// of members holding these roles.
+	ExemptUserIDs []string `json:"exempt_user_ids,omitempty"`
+	ExemptRoleIDs []string `json:"exempt_role_ids,omitempty"`
+}
+
+// AutoPurgeChannelConfig is the purge policy of one channel. Exactly one of
+// MaxAgeDays and KeepLast is set.
+type AutoPurgeChannelConfig struct {
+	ChannelID string `json:"channel_id"`
+	// MaxAgeDays removes messages older than this many days.
+	MaxAgeDays int `json:"max_age_days,omitempty"`
+	// KeepLast removes everything but this many most recent messages.
+	KeepLast int `json:"keep_last,omitempty"`
+}
+
+// Enabled reports whether any channel is purged.
+func (c AutoPurgeConfig) Enabled() bool { return len(c.Channels) > 0 }
+
+func validateAutoPurge(cfg AutoPurgeConfig, guildIndex int) error {
+	seen := make(map[string]struct{}, len(cfg.Channels))
+	for idx, channel := range cfg.Channels {
+		fieldBase := fmt.Sprintf("guilds[%d].auto_purge.channels[%d]", guildIndex, idx)
+		channelID := strings.TrimSpace(channel.ChannelID)
+		if channelID == "" || !isAllDigits(channelID) {
+			return NewValidationError(fieldBase+".channel_id", channel.ChannelID, "channel must be a numeric ID")
+		}
+		if _, dup := seen[channelID]; dup {
+			return NewValidationError(fieldBase+".channel_id", channel.ChannelID, "channel already has a purge policy")
+		}
+		seen[channelID] = struct{}{}
+		if channel.MaxAgeDays < 0 || channel.KeepLast < 0 {
+			return NewValidationError(fieldBase, channel, "max_age_days and keep_last must not be negative")
+		}
+		if (channel.MaxAgeDays > 0) == (channel.KeepLast > 0) {
+			return NewValidationError(fieldBase, channel, "set exactly one of max_age_days and keep_last")
+		}
+	}
+	for idx, roleID := range cfg.ExemptRoleIDs {
+		if !isAllDigits(strings.TrimSpace(roleID)) {
+			return NewValidationError(fmt.Sprintf("guilds[%d].auto_purge.exempt_role_ids[%d]", guildIndex, idx), roleID, "role must be a numeric ID")
+		}
+	}
+	for idx, userID := range cfg.ExemptUserIDs {
+		if !isAllDigits(strings.TrimSpace(userID)) {
+			return NewValidationError(fmt.Sprintf("guilds[%d].auto_purge.exempt_user_ids[%d]", guildIndex, idx), userID, "user must be a numeric ID")
+		}
+	}
+	return nil
+}
+
+func cloneAutoPurgeConfig(in AutoPurgeConfig) AutoPurgeConfig {
+	var channels []AutoPurgeChannelConfig
+	if len(in.Channels) > 0 {
+		channels = append(channels, in.Channels...)
+	}
+	return AutoPurgeConfig{
+		Channels:      channels,
+		ExemptUserIDs: cloneStringSlice(in.ExemptUserIDs),
+		ExemptRoleIDs: cloneStringSlice(in.ExemptRoleIDs),
+	}
+}
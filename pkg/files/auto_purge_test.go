@@ -0,0 +1,39 @@
+package files
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateBotConfigRejectsInvalidAutoPurge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		purge AutoPurgeConfig
+		field string
+	}{
+		{name: "named channel", purge: AutoPurgeConfig{Channels: []AutoPurgeChannelConfig{{ChannelID: "general", KeepLast: 10}}}, field: "guilds[0].auto_purge.channels[0].channel_id"},
+		{name: "no policy", purge: AutoPurgeConfig{Channels: []AutoPurgeChannelConfig{{ChannelID: "1"}}}, field: "guilds[0].auto_purge.channels[0]"},
+		{name: "both policies", purge: AutoPurgeConfig{Channels: []AutoPurgeChannelConfig{{ChannelID: "1", MaxAgeDays: 7, KeepLast: 10}}}, field: "guilds[0].auto_purge.channels[0]"},
+		{name: "negative age", purge: AutoPurgeConfig{Channels: []AutoPurgeChannelConfig{{ChannelID: "1", MaxAgeDays: -1, KeepLast: 10}}}, field: "guilds[0].auto_purge.channels[0]"},
+		{name: "duplicate channel", purge: AutoPurgeConfig{Channels: []AutoPurgeChannelConfig{{ChannelID: "1", KeepLast: 10}, {ChannelID: "1", MaxAgeDays: 7}}}, field: "guilds[0].auto_purge.channels[1].channel_id"},
+		{name: "named exempt role", purge: AutoPurgeConfig{Channels: []AutoPurgeChannelConfig{{ChannelID: "1", KeepLast: 10}}, ExemptRoleIDs: []string{"staff"}}, field: "guilds[0].auto_purge.exempt_role_ids[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &BotConfig{Guilds: []GuildConfig{{GuildID: "g1", AutoPurge: tt.purge}}}
+			var verr ValidationError
+			if err := validateBotConfig(cfg); !errors.As(err, &verr) || verr.Field != tt.field {
+				t.Fatalf("expected validation error on %s, got %v", tt.field, err)
+			}
+		})
+	}
+
+	valid := AutoPurgeConfig{Channels: []AutoPurgeChannelConfig{{ChannelID: "1", MaxAgeDays: 30}, {ChannelID: "2", KeepLast: 50}}, ExemptUserIDs: []string{"3"}}
+	if err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", AutoPurge: valid}}}); err != nil {
+		t.Fatalf("valid auto-purge rejected: %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Actions an automod rule can take on a matching message.
+const (
+	AutomodActionDelete  = "delete"
+	AutomodActionWarn    = "warn"
+	AutomodActionTimeout = "timeout"
+	AutomodActionFlag    = "flag"
+)
+
+const (
+	// MaxAutomodRules bounds the rules of a guild, which are all checked
+	// against every message.
+	MaxAutomodRules = 25
+	// MaxAutomodPatternLength bounds a rule's pattern, or its word list
+	// joined by commas.
+	MaxAutomodPatternLength = 500
+	// MaxAutomodTimeoutMinutes is the longest timeout Discord allows.
+	MaxAutomodTimeoutMinutes = 28 * 24 * 60
+
+	maxAutomodRuleNameLength = 32
+)
+
+// AutomodRule is a content rule the bot checks every member message against.
+// A rule matches either a regular expression (RE2 syntax) or any of a list
+// of words, as whole words and ignoring case. The first rule a message
+// matches takes all of its actions; members holding an exempt role and
+// messages in an exempt channel or category are not checked.
+type AutomodRule struct {
+	Name             string   `json:"name"`
+	Pattern          string   `json:"pattern,omitempty"`
+	Words            []string `json:"words,omitempty"`
+	Actions          []string `json:"actions"`
+	TimeoutMinutes   int      `json:"timeout_minutes,omitempty"`
+	FlagChannelID    string   `json:"flag_channel_id,omitempty"`
+	ExemptRoleIDs    []string `json:"exempt_role_ids,omitempty"`
+	ExemptChannelIDs []string `json:"exempt_channel_ids,omitempty"`
+}
+
+// Expression returns the regular expression the rule matches message text
+// with. Words only match where they are not part of a longer word.
+func (r AutomodRule) Expression() string {
+	if len(r.Words) == 0 {
+		return r.Pattern
+	}
+	quoted := make([]string, 0, len(r.Words))
+	for _, word := range r.Words {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		expr := regexp.QuoteMeta(word)
+		// \b only holds next to a word character, so "c++" gets no
+		// boundary after it.
+		if isWordByte(word[0]) {
+			expr = `\b` + expr
+		}
+		if isWordByte(word[len(word)-1]) {
+			expr += `\b`
+		}
+		quoted = append(quoted, expr)
+	}
+	return `(?i)(?:` + strings.Join(quoted, "|") + `)`
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+// Takes reports whether the rule takes action.
+func (r AutomodRule) Takes(action string) bool {
+	return slices.Contains(r.Actions, action)
+}
+
+// Exempts reports whether a message in channelID, under categoryID, by a
+// member holding roleIDs is left alone by the rule.
+func (r AutomodRule) Exempts(channelID, categoryID string, roleIDs []string) bool {
+	if slices.Contains(r.ExemptChannelIDs, channelID) {
+		return true
+	}
+	if categoryID != "" && slices.Contains(r.ExemptChannelIDs, categoryID) {
+		return true
+	}
+	for _, roleID := range roleIDs {
+		if slices.Contains(r.ExemptRoleIDs, roleID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate reports why the rule cannot be used, or nil when it can.
+func (r AutomodRule) Validate() error {
+	if !isSlug(r.Name, maxAutomodRuleNameLength) {
+		return fmt.Errorf("rule names must be 1 to %d lowercase letters, digits, dashes or underscores", maxAutomodRuleNameLength)
+	}
+	switch {
+	case r.Pattern != "" && len(r.Words) > 0:
+		return errors.New("a rule matches either a pattern or words, not both")
+	case r.Pattern == "" && len(r.Words) == 0:
+		return errors.New("a rule needs a pattern or words to match")
+	}
+	for _, word := range r.Words {
+		if strings.TrimSpace(word) == "" {
+			return errors.New("words must not be blank")
+		}
+	}
+	if len(r.Pattern) > MaxAutomodPatternLength || len(strings.Join(r.Words, ",")) > MaxAutomodPatternLength {
+		return fmt.Errorf("patterns and word lists are limited to %d characters", MaxAutomodPatternLength)
+	}
+	re, err := regexp.Compile(r.Expression())
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	if re.MatchString("") {
+		return errors.New("the pattern matches empty text, so it would match every message")
+	}
+
+	if len(r.Actions) == 0 {
+		return errors.New("a rule needs at least one action")
+	}
+	for i, action := range r.Actions {
+		switch action {
+		case AutomodActionDelete, AutomodActionWarn, AutomodActionTimeout, AutomodActionFlag:
+		default:
+			return fmt.Errorf("unknown action %q", action)
+		}
+		if slices.Contains(r.Actions[:i], action) {
+			return fmt.Errorf("action %q is listed twice", action)
+		}
+	}
+	if r.Takes(AutomodActionTimeout) {
+		if r.TimeoutMinutes < 1 || r.TimeoutMinutes > MaxAutomodTimeoutMinutes {
+			return fmt.Errorf("timeouts must last 1 to %d minutes", MaxAutomodTimeoutMinutes)
+		}
+	} else if r.TimeoutMinutes != 0 {
+		return errors.New("timeout_minutes is set but the rule does not time out")
+	}
+	if r.Takes(AutomodActionFlag) {
+		if !isAllDigits(strings.TrimSpace(r.FlagChannelID)) {
+			return errors.New("flagging needs a numeric flag channel ID")
+		}
+	} else if r.FlagChannelID != "" {
+		return errors.New("flag_channel_id is set but the rule does not flag")
+	}
+
+	for _, id := range r.ExemptRoleIDs {
+		if !isAllDigits(strings.TrimSpace(id)) {
+			return fmt.Errorf("exempt role %q must be a numeric ID", id)
+		}
+	}
+	for _, id := range r.ExemptChannelIDs {
+		if !isAllDigits(strings.TrimSpace(id)) {
+			return fmt.Errorf("exempt channel %q must be a numeric ID", id)
+		}
+	}
+	return nil
+}
+
+// ModeratesContent reports whether the bot checks the messages of the guild
+// against automod rules, the invite filter, the spam filter or the
+// attachment filter.
+func (gc *GuildConfig) ModeratesContent() bool {
+	return len(gc.AutomodRules) > 0 || gc.InviteFilter.Enabled || gc.SpamFilter.Enabled() || gc.AttachmentFilter.Enabled()
+}
+
+func validateAutomodRules(rules []AutomodRule, guildIndex int) error {
+	if len(rules) > MaxAutomodRules {
+		return NewValidationError(fmt.Sprintf("guilds[%d].automod_rules", guildIndex), len(rules),
+			fmt.Sprintf("at most %d automod rules are allowed", MaxAutomodRules))
+	}
+	for i, rule := range rules {
+		field := fmt.Sprintf("guilds[%d].automod_rules[%d]", guildIndex, i)
+		if err := rule.Validate(); err != nil {
+			return NewValidationError(field, rule.Name, err.Error())
+		}
+		if slices.ContainsFunc(rules[:i], func(other AutomodRule) bool { return other.Name == rule.Name }) {
+			return NewValidationError(field, rule.Name, "rule name is used twice")
+		}
+	}
+	return nil
+}
+
+func cloneAutomodRules(in []AutomodRule) []AutomodRule {
+	if in == nil {
+		return nil
+	}
+	out := make([]AutomodRule, len(in))
+	for i, rule := range in {
+		rule.Words = cloneStringSlice(rule.Words)
+		rule.Actions = cloneStringSlice(rule.Actions)
+		rule.ExemptRoleIDs = cloneStringSlice(rule.ExemptRoleIDs)
+		rule.ExemptChannelIDs = cloneStringSlice(rule.ExemptChannelIDs)
+		out[i] = rule
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+package files
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestAutomodRuleValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := []AutomodRule{
+		{Name: "invites", Pattern: `discord\.gg/\w+`, Actions: []string{AutomodActionDelete}},
+		{Name: "slurs", Words: []string{"foo", "bar.baz"}, Actions: []string{AutomodActionWarn, AutomodActionTimeout}, TimeoutMinutes: 10},
+		{Name: "links", Pattern: "https?://", Actions: []string{AutomodActionFlag}, FlagChannelID: "5", ExemptRoleIDs: []string{"6"}, ExemptChannelIDs: []string{"7"}},
+	}
+	for _, rule := range valid {
+		if err := rule.Validate(); err != nil {
+			t.Fatalf("valid rule %q rejected: %v", rule.Name, err)
+		}
+	}
+
+	invalid := map[string]AutomodRule{
+		"bad name":           {Name: "No Spaces", Pattern: "x", Actions: []string{AutomodActionDelete}},
+		"pattern and words":  {Name: "r", Pattern: "x", Words: []string{"y"}, Actions: []string{AutomodActionDelete}},
+		"nothing to match":   {Name: "r", Actions: []string{AutomodActionDelete}},
+		"bad regex":          {Name: "r", Pattern: "(", Actions: []string{AutomodActionDelete}},
+		"matches everything": {Name: "r", Pattern: "a*", Actions: []string{AutomodActionDelete}},
+		"no action":          {Name: "r", Pattern: "x"},
+		"unknown action":     {Name: "r", Pattern: "x", Actions: []string{"ban"}},
+		"timeout unset":      {Name: "r", Pattern: "x", Actions: []string{AutomodActionTimeout}},
+		"flag unset":         {Name: "r", Pattern: "x", Actions: []string{AutomodActionFlag}},
+		"named role":         {Name: "r", Pattern: "x", Actions: []string{AutomodActionDelete}, ExemptRoleIDs: []string{"staff"}},
+	}
+	for name, rule := range invalid {
+		if rule.Validate() == nil {
+			t.Errorf("%s: expected the rule to be rejected", name)
+		}
+	}
+}
+
+func TestAutomodRuleMatching(t *testing.T) {
+	t.Parallel()
+
+	rule := AutomodRule{Words: []string{"spam", "c++"}}
+	re := regexp.MustCompile(rule.Expression())
+	for text, want := range map[string]bool{
+		"buy SPAM now":     true,
+		"spammer":          false,
+		"learn c++ today":  true,
+		"nothing to see":   false,
+		"spam, spam, spam": true,
+	} {
+		if got := re.MatchString(text); got != want {
+			t.Errorf("match %q = %v, want %v", text, got, want)
+		}
+	}
+
+	rule = AutomodRule{ExemptRoleIDs: []string{"1"}, ExemptChannelIDs: []string{"2", "3"}}
+	if !rule.Exempts("9", "", []string{"1"}) || !rule.Exempts("2", "", nil) || !rule.Exempts("9", "3", nil) {
+		t.Fatal("expected exempt roles, channels and categories to be honored")
+	}
+	if rule.Exempts("9", "8", []string{"4"}) {
+		t.Fatal("unexpected exemption")
+	}
+}
+
+func TestValidateAutomodRules(t *testing.T) {
+	t.Parallel()
+
+	rule := AutomodRule{Name: "invites", Pattern: "discord.gg", Actions: []string{AutomodActionDelete}}
+	if err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", AutomodRules: []AutomodRule{rule}}}}); err != nil {
+		t.Fatalf("valid rules rejected: %v", err)
+	}
+	var verr ValidationError
+	err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", AutomodRules: []AutomodRule{rule, rule}}}})
+	if !errors.As(err, &verr) || verr.Field != "guilds[0].automod_rules[1]" {
+		t.Fatalf("expected a duplicate name to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package files
+
+import (
+	"fmt"
+	"slices"
+)
+
+// maxBanPoolNameLength bounds ban pool names so they fit a case embed line.
+const maxBanPoolNameLength = 32
+
+// ValidBanPoolName reports whether name can name a ban pool: 1 to 32
+// lowercase letters, digits, dashes or underscores.
+func ValidBanPoolName(name string) bool {
+	return isSlug(name, maxBanPoolNameLength)
+}
+
+// isSlug reports whether name is 1 to maxLen lowercase letters, digits,
+// dashes or underscores.
+func isSlug(name string, maxLen int) bool {
+	if name == "" || len(name) > maxLen {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+func validateBanPools(pools []string, guildIndex int) error {
+	for i, pool := range pools {
+		field := fmt.Sprintf("guilds[%d].ban_pools[%d]", guildIndex, i)
+		if !ValidBanPoolName(pool) {
+			return NewValidationError(field, pool,
+				fmt.Sprintf("ban pool names must be 1 to %d lowercase letters, digits, dashes or underscores", maxBanPoolNameLength))
+		}
+		if slices.Contains(pools[:i], pool) {
+			return NewValidationError(field, pool, "ban pool is listed twice")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package files
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateBanPools(t *testing.T) {
+	t.Parallel()
+
+	if err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", BanPools: []string{"network", "partner_servers-2"}}}}); err != nil {
+		t.Fatalf("valid ban pools rejected: %v", err)
+	}
+	for field, pools := range map[string][]string{
+		"guilds[0].ban_pools[0]": {"Network"},
+		"guilds[0].ban_pools[1]": {"network", "network"},
+	} {
+		var verr ValidationError
+		err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", BanPools: pools}}})
+		if !errors.As(err, &verr) || verr.Field != field {
+			t.Fatalf("expected validation error on %s for %v, got %v", field, pools, err)
+		}
+	}
+}
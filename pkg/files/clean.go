@@ -0,0 +1,21 @@
+package files
+
+// CleanConfig holds the per-guild protections applied by /clean.
+type CleanConfig struct {
+	// ProtectedRoleIDs lists roles whose members' messages are never cleaned.
+	ProtectedRoleIDs []string `json:"protected_role_ids,omitempty"`
+	// ProtectedUserIDs lists users whose messages are never cleaned.
+	ProtectedUserIDs []string `json:"protected_user_ids,omitempty"`
+	// AllowPinned lets /clean delete pinned messages. Pins are kept by
+	// default.
+	AllowPinned bool `json:"allow_pinned,omitempty"`
+	// ConfirmAbove asks the moderator to confirm any clean of more than this
+	// many messages. Zero never asks.
+	ConfirmAbove int `json:"confirm_above,omitempty"`
+}
+
+// NeedsConfirmation reports whether a clean of count messages must be
+// confirmed first.
+func (c CleanConfig) NeedsConfirmation(count int) bool {
+	return c.ConfirmAbove > 0 && count > c.ConfirmAbove
+}
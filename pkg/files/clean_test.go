@@ -0,0 +1,28 @@
+package files
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCleanConfigNeedsConfirmation(t *testing.T) {
+	t.Parallel()
+
+	if (CleanConfig{}).NeedsConfirmation(100) {
+		t.Fatal("a zero threshold must never ask for confirmation")
+	}
+	cfg := CleanConfig{ConfirmAbove: 50}
+	if cfg.NeedsConfirmation(50) || !cfg.NeedsConfirmation(51) {
+		t.Fatal("confirmation must start above the threshold")
+	}
+}
+
+func TestValidateBotConfigRejectsNegativeCleanConfirmation(t *testing.T) {
+	t.Parallel()
+
+	err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", Clean: CleanConfig{ConfirmAbove: -1}}}})
+	var verr ValidationError
+	if !errors.As(err, &verr) || verr.Field != "guilds[0].clean.confirm_above" {
+		t.Fatalf("expected clean.confirm_above validation error, got %v", err)
+	}
+}
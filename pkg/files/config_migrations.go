@@ -0,0 +1,99 @@
+package files
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// CurrentSchemaVersion is the settings layout this build reads and writes.
+// Bump it together with a new entry in schemaMigrations whenever a key is
+// renamed or moved.
+const CurrentSchemaVersion = 1
+
+// ErrSettingsSchemaTooNew reports a settings document written by a newer
+// build. Loading it would drop the keys this build does not know on the next
+// save.
+var ErrSettingsSchemaTooNew = errors.New("settings were written by a newer version")
+
+// SchemaMigration upgrades a decoded settings document from Version-1 to
+// Version in place.
+type SchemaMigration struct {
+	Version int
+	Summary string
+	Apply   func(doc map[string]any) error
+}
+
+// schemaMigrations is the chain run by MigrateSettings, in version order.
+// Renames the json tags no longer cover belong here rather than in an
+// UnmarshalJSON hook, so the document is rewritten once instead of on every
+// decode.
+var schemaMigrations = []SchemaMigration{
+	{
+		Version: 1,
+		Summary: "stamp documents written before settings were versioned",
+		Apply:   func(map[string]any) error { return nil },
+	},
+}
+
+// MigrateSettings brings doc, a settings document decoded into generic
+// values, up to CurrentSchemaVersion and returns the version it was written
+// in. Documents without a version predate versioning and start at 0.
+func MigrateSettings(doc map[string]any) (from int, err error) {
+	return migrateSettings(doc, schemaMigrations, CurrentSchemaVersion)
+}
+
+func migrateSettings(doc map[string]any, chain []SchemaMigration, current int) (int, error) {
+	from, err := settingsSchemaVersion(doc)
+	if err != nil {
+		return 0, fmt.Errorf("MigrateSettings: %w", err)
+	}
+	if from > current {
+		return from, fmt.Errorf("MigrateSettings: %w (schema %d, this build reads up to %d)", ErrSettingsSchemaTooNew, from, current)
+	}
+	for _, step := range chain {
+		if step.Version <= from || step.Version > current {
+			continue
+		}
+		if err := step.Apply(doc); err != nil {
+			return from, fmt.Errorf("MigrateSettings: schema %d (%s): %w", step.Version, step.Summary, err)
+		}
+		doc[schemaVersionKey] = step.Version
+	}
+	return from, nil
+}
+
+const schemaVersionKey = "schema_version"
+
+// settingsSchemaVersion reads the schema version of doc. The value arrives as
+// whichever number type the source format decodes to.
+func settingsSchemaVersion(doc map[string]any) (int, error) {
+	raw, ok := doc[schemaVersionKey]
+	if !ok || raw == nil {
+		return 0, nil
+	}
+	var version float64
+	switch v := raw.(type) {
+	case float64:
+		version = v
+	case int:
+		version = float64(v)
+	case int64:
+		version = float64(v)
+	case uint64:
+		version = float64(v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q", schemaVersionKey, v)
+		}
+		version = f
+	default:
+		return 0, fmt.Errorf("invalid %s %v", schemaVersionKey, raw)
+	}
+	if version < 0 || version != math.Trunc(version) {
+		return 0, fmt.Errorf("invalid %s %v", schemaVersionKey, raw)
+	}
+	return int(version), nil
+}
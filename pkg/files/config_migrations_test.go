@@ -0,0 +1,67 @@
+package files
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestMigrateSettingsRunsPendingSteps(t *testing.T) {
+	t.Parallel()
+
+	chain := []SchemaMigration{
+		{Version: 1, Summary: "noop", Apply: func(map[string]any) error { return nil }},
+		{Version: 2, Summary: "rename user_log", Apply: func(doc map[string]any) error {
+			if v, ok := doc["user_log"]; ok {
+				doc["user_logs"] = v
+				delete(doc, "user_log")
+			}
+			return nil
+		}},
+		{Version: 3, Summary: "not shipped yet", Apply: func(map[string]any) error {
+			return errors.New("must not run past current")
+		}},
+	}
+
+	tests := []struct {
+		name     string
+		doc      map[string]any
+		wantFrom int
+		wantKey  bool
+	}{
+		{name: "unversioned", doc: map[string]any{"user_log": "c1"}, wantFrom: 0, wantKey: true},
+		{name: "yaml int", doc: map[string]any{"schema_version": 1, "user_log": "c1"}, wantFrom: 1, wantKey: true},
+		{name: "json number", doc: map[string]any{"schema_version": json.Number("2"), "user_log": "c1"}, wantFrom: 2, wantKey: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			from, err := migrateSettings(tc.doc, chain, 2)
+			if err != nil {
+				t.Fatalf("migrateSettings: %v", err)
+			}
+			if from != tc.wantFrom {
+				t.Fatalf("expected from version %d, got %d", tc.wantFrom, from)
+			}
+			if _, renamed := tc.doc["user_logs"]; renamed != tc.wantKey {
+				t.Fatalf("expected rename applied=%v, got %+v", tc.wantKey, tc.doc)
+			}
+			if got, _ := settingsSchemaVersion(tc.doc); from < 2 && got != 2 {
+				t.Fatalf("expected the document stamped with version 2, got %d", got)
+			}
+		})
+	}
+}
+
+func TestMigrateSettingsRejectsNewerOrInvalidVersions(t *testing.T) {
+	t.Parallel()
+
+	if _, err := MigrateSettings(map[string]any{"schema_version": float64(CurrentSchemaVersion + 1)}); !errors.Is(err, ErrSettingsSchemaTooNew) {
+		t.Fatalf("expected ErrSettingsSchemaTooNew, got %v", err)
+	}
+	for _, bad := range []any{"1", float64(-1), 1.5} {
+		if _, err := MigrateSettings(map[string]any{"schema_version": bad}); err == nil {
+			t.Errorf("expected schema_version %v to be rejected", bad)
+		}
+	}
+}
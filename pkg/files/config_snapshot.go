@@ -148,14 +148,14 @@ func (mgr *ConfigManager) AddSubscriber(sub ConfigSubscriber) {
 }
 
 func (mgr *ConfigManager) notifySubscribers(ctx context.Context, oldCfg, newCfg *BotConfig) error {
-	mgr.mu.Lock()
+	mgr.mu.RLock()
 	if len(mgr.subscribers) == 0 {
-		mgr.mu.Unlock()
+		mgr.mu.RUnlock()
 		return nil
 	}
 	subs := make([]ConfigSubscriber, len(mgr.subscribers))
 	copy(subs, mgr.subscribers)
-	mgr.mu.Unlock()
+	mgr.mu.RUnlock()
 
 	eg, egCtx := errgroup.WithContext(ctx)
 	eg.SetLimit(10)
@@ -201,10 +201,15 @@ func cloneGuildConfigPtr(in *GuildConfig) *GuildConfig {
 
 func cloneBotConfig(in BotConfig) BotConfig {
 	return BotConfig{
+		SchemaVersion: in.SchemaVersion,
 		ConfigVersion: in.ConfigVersion,
 		Guilds:        cloneGuildConfigs(in.Guilds),
 		Features:      cloneFeatureToggles(in.Features),
 		RuntimeConfig: cloneRuntimeConfig(in.RuntimeConfig),
+		Profiles:      cloneConfigProfiles(in.Profiles),
+
+		PendingBotTokens: cloneEncryptedStringMap(in.PendingBotTokens),
+		GuildAccess:      cloneGuildAccessPolicy(in.GuildAccess),
 	}
 }
 
@@ -221,29 +226,64 @@ func cloneGuildConfigs(in []GuildConfig) []GuildConfig {
 
 func cloneGuildConfig(in GuildConfig) GuildConfig {
 	return GuildConfig{
-		GuildID:             in.GuildID,
-		ConfigVersion:       in.ConfigVersion,
-		FeatureRouting:      cloneStringMap(in.FeatureRouting),
-		BotInstanceTokens:   cloneEncryptedStringMap(in.BotInstanceTokens),
-		BotInstanceStatuses: cloneStringMap(in.BotInstanceStatuses),
-		Features:            cloneFeatureToggles(in.Features),
-		Channels:            in.Channels,
-		Roles:               cloneRolesConfig(in.Roles),
-		Stats:               cloneStatsConfig(in.Stats),
-		RolesCacheTTL:       in.RolesCacheTTL,
-		MemberCacheTTL:      in.MemberCacheTTL,
-		GuildCacheTTL:       in.GuildCacheTTL,
-		ChannelCacheTTL:     in.ChannelCacheTTL,
-		UserPrune:           cloneUserPruneConfig(in.UserPrune),
-		PartnerBoard:        clonePartnerBoardConfig(in.PartnerBoard),
-		ReactionBlocks:      cloneReactionBlockConfig(in.ReactionBlocks),
-		QOTD:                cloneQOTDConfig(in.QOTD),
-		Tickets:             cloneTicketsConfig(in.Tickets),
-		RolePanels:          cloneRolePanels(in.RolePanels),
-		CustomEmbeds:        cloneCustomEmbeds(in.CustomEmbeds),
-		RuntimeConfig:       cloneRuntimeConfig(in.RuntimeConfig),
-		LogModerationScope:  in.LogModerationScope,
-	}
+		GuildID:              in.GuildID,
+		ConfigVersion:        in.ConfigVersion,
+		Profile:              in.Profile,
+		FeatureRouting:       cloneStringMap(in.FeatureRouting),
+		BotInstanceTokens:    cloneEncryptedStringMap(in.BotInstanceTokens),
+		BotInstanceStatuses:  cloneStringMap(in.BotInstanceStatuses),
+		Features:             cloneFeatureToggles(in.Features),
+		Channels:             cloneChannelsConfig(in.Channels),
+		Roles:                cloneRolesConfig(in.Roles),
+		Stats:                cloneStatsConfig(in.Stats),
+		RolesCacheTTL:        in.RolesCacheTTL,
+		MemberCacheTTL:       in.MemberCacheTTL,
+		GuildCacheTTL:        in.GuildCacheTTL,
+		ChannelCacheTTL:      in.ChannelCacheTTL,
+		UserPrune:            cloneUserPruneConfig(in.UserPrune),
+		PartnerBoard:         clonePartnerBoardConfig(in.PartnerBoard),
+		ReactionBlocks:       cloneReactionBlockConfig(in.ReactionBlocks),
+		QOTD:                 cloneQOTDConfig(in.QOTD),
+		Tickets:              cloneTicketsConfig(in.Tickets),
+		RolePanels:           cloneRolePanels(in.RolePanels),
+		CustomEmbeds:         cloneCustomEmbeds(in.CustomEmbeds),
+		NotificationRoutes:   cloneNotificationRoutes(in.NotificationRoutes),
+		LogMentions:          cloneLogMentionPolicies(in.LogMentions),
+		LogLanguages:         cloneStringMap(in.LogLanguages),
+		RuntimeConfig:        cloneRuntimeConfig(in.RuntimeConfig),
+		LogModerationScope:   in.LogModerationScope,
+		DisplayNameStyle:     in.DisplayNameStyle,
+		Timezone:             in.Timezone,
+		ThreadAutoJoin:       in.ThreadAutoJoin,
+		UserLogThreads:       in.UserLogThreads,
+		LogWebhooks:          in.LogWebhooks,
+		Assets:               in.Assets,
+		DisabledCommands:     cloneStringSlice(in.DisabledCommands),
+		PresenceWatch:        clonePresenceWatchConfig(in.PresenceWatch),
+		WarningEscalation:    cloneWarningEscalation(in.WarningEscalation),
+		Privacy:              in.Privacy,
+		Clean:                cloneCleanConfig(in.Clean),
+		PunishmentDM:         in.PunishmentDM,
+		AutoPurge:            cloneAutoPurgeConfig(in.AutoPurge),
+		ModerationProtection: cloneModerationProtectionConfig(in.ModerationProtection),
+		HealthReport:         in.HealthReport,
+		RaidMode:             in.RaidMode,
+		BanPools:             cloneStringSlice(in.BanPools),
+		AutomodRules:         cloneAutomodRules(in.AutomodRules),
+		InviteFilter:         cloneInviteFilterConfig(in.InviteFilter),
+		SpamFilter:           cloneSpamFilterConfig(in.SpamFilter),
+		AttachmentFilter:     cloneAttachmentFilterConfig(in.AttachmentFilter),
+		JoinGate:             cloneJoinGateConfig(in.JoinGate),
+		Retention:            in.Retention,
+		TestMode:             in.TestMode,
+	}
+}
+
+func cloneCleanConfig(in CleanConfig) CleanConfig {
+	out := in
+	out.ProtectedRoleIDs = cloneStringSlice(in.ProtectedRoleIDs)
+	out.ProtectedUserIDs = cloneStringSlice(in.ProtectedUserIDs)
+	return out
 }
 
 func cloneReactionBlockConfig(in ReactionBlockConfig) ReactionBlockConfig {
@@ -267,6 +307,23 @@ func cloneReactionBlockConfig(in ReactionBlockConfig) ReactionBlockConfig {
 	return out
 }
 
+func cloneLogMentionPolicies(in map[string]LogMentionPolicy) map[string]LogMentionPolicy {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]LogMentionPolicy, len(in))
+	for key, value := range in {
+		out[key] = value
+	}
+	return out
+}
+
+func cloneChannelsConfig(in ChannelsConfig) ChannelsConfig {
+	out := in
+	out.LogRoutes = cloneStringMap(in.LogRoutes)
+	return out
+}
+
 func cloneStringMap(in map[string]string) map[string]string {
 	if len(in) == 0 {
 		return nil
@@ -306,6 +363,12 @@ func cloneRuntimeConfig(in RuntimeConfig) RuntimeConfig {
 		MessageDeleteOnLog:           in.MessageDeleteOnLog,
 		MessageCacheCleanup:          in.MessageCacheCleanup,
 		GlobalMaxWorkers:             in.GlobalMaxWorkers,
+		GatewayWatchdogIdleMinutes:   in.GatewayWatchdogIdleMinutes,
+		AuditLogPollMinutes:          in.AuditLogPollMinutes,
+		CommandDevGuildID:            in.CommandDevGuildID,
+		CommandScope:                 in.CommandScope,
+		OwnerAlertChannelID:          in.OwnerAlertChannelID,
+		StartupReportToOwner:         in.StartupReportToOwner,
 		BackfillChannelID:            in.BackfillChannelID,
 		BackfillStartDay:             in.BackfillStartDay,
 		BackfillInitialDate:          in.BackfillInitialDate,
@@ -315,6 +378,11 @@ func cloneRuntimeConfig(in RuntimeConfig) RuntimeConfig {
 		WebhookEmbedValidation:       in.WebhookEmbedValidation,
 		DisableInteractiveEphemeral:  in.DisableInteractiveEphemeral,
 		LogModerationScope:           in.LogModerationScope,
+		MassActionsPerSecond:         in.MassActionsPerSecond,
+		BanDeleteMessageDays:         in.BanDeleteMessageDays,
+		ModeratorActionLimit:         in.ModeratorActionLimit,
+		ModeratorActionWindowMinutes: in.ModeratorActionWindowMinutes,
+		BotLockdown:                  in.BotLockdown,
 	}
 }
 
@@ -347,7 +415,9 @@ func cloneAutoAssignmentConfig(in AutoAssignmentConfig) AutoAssignmentConfig {
 
 func cloneStatsConfig(in StatsConfig) StatsConfig {
 	return StatsConfig{
-		Channels: cloneStatsChannelConfigs(in.Channels),
+		Channels:           cloneStatsChannelConfigs(in.Channels),
+		HourlyMemberCounts: in.HourlyMemberCounts,
+		Exclusions:         cloneMetricsExclusionConfig(in.Exclusions),
 	}
 }
 
@@ -3,6 +3,8 @@ package files
 import (
 	"encoding/json"
 	"errors"
+	"strconv"
+	"sync"
 	"testing"
 )
 
@@ -77,6 +79,38 @@ func TestUpdateRuntimeConfigRollsBackOnSaveError(t *testing.T) {
 	}
 }
 
+func TestReadOnlyManagerRefusesUpdates(t *testing.T) {
+	t.Parallel()
+
+	mgr, store := newTransactionalTestManager(t, &BotConfig{
+		RuntimeConfig: RuntimeConfig{BotTheme: "halloween"},
+	}, nil)
+	mgr.SetReadOnly(true)
+
+	_, err := mgr.UpdateRuntimeConfig(func(rc *RuntimeConfig) error {
+		rc.BotTheme = "winter"
+		return nil
+	})
+	if !errors.Is(err, ErrConfigReadOnly) {
+		t.Fatalf("expected ErrConfigReadOnly, got %v", err)
+	}
+	if got := mgr.SnapshotConfig().RuntimeConfig.BotTheme; got != "halloween" {
+		t.Fatalf("expected in-memory config to be unchanged, got %q", got)
+	}
+	if got := store.cfg.RuntimeConfig.BotTheme; got != "halloween" {
+		t.Fatalf("expected stored config to be unchanged, got %q", got)
+	}
+
+	// Loading still works so the standby follows the primary's changes.
+	store.cfg.RuntimeConfig.BotTheme = "winter"
+	if err := mgr.LoadConfig(); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got := mgr.SnapshotConfig().RuntimeConfig.BotTheme; got != "winter" {
+		t.Fatalf("expected reloaded theme, got %q", got)
+	}
+}
+
 func TestCreateWebhookEmbedUpdateRollsBackOnSaveError(t *testing.T) {
 	t.Parallel()
 
@@ -97,3 +131,30 @@ func TestCreateWebhookEmbedUpdateRollsBackOnSaveError(t *testing.T) {
 		t.Fatalf("expected webhook updates rollback, got %+v", updates)
 	}
 }
+
+func TestSaveGuildConfigConcurrentWritersDoNotLoseUpdates(t *testing.T) {
+	t.Parallel()
+
+	mgr := NewConfigManagerWithStore(&mockConfigStore{}, nil)
+	if err := mgr.LoadConfig(); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	const writers = 32
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := mgr.SaveGuildConfig(GuildConfig{GuildID: strconv.Itoa(1000 + i)}); err != nil {
+				t.Errorf("save guild %d: %v", i, err)
+			}
+			_ = mgr.Config()
+		}(i)
+	}
+	wg.Wait()
+
+	if got := len(mgr.SnapshotConfig().Guilds); got != writers {
+		t.Fatalf("expected %d guilds after concurrent saves, got %d", writers, got)
+	}
+}
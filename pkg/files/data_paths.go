@@ -0,0 +1,78 @@
+package files
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/small-frappuccino/discordcore/pkg/sys"
+)
+
+// DataPaths is the on-disk layout of one application instance.
+type DataPaths struct {
+	Config string
+	Data   string
+	Cache  string
+	Logs   string
+}
+
+// dataDirOverride roots every path under one directory when set via SetDataDir.
+var dataDirOverride string
+
+// ResolveDataPaths computes the layout for appName. A non-empty root places
+// everything under it (config at the root, the rest in data/, cache/ and
+// logs/); otherwise the platform directories apply, honoring the XDG base
+// directory variables on Unix.
+func ResolveDataPaths(appName, root string) DataPaths {
+	if root = strings.TrimSpace(root); root != "" {
+		return DataPaths{
+			Config: root,
+			Data:   filepath.Join(root, "data"),
+			Cache:  filepath.Join(root, "cache"),
+			Logs:   filepath.Join(root, "logs"),
+		}
+	}
+	// Last-resort fallbacks if platform resolution fails unexpectedly.
+	return DataPaths{
+		Config: dirOr(sys.PlatformConfigDir(appName), filepath.Join(".", "config", appName)),
+		Data:   dirOr(sys.PlatformDataDir(appName), filepath.Join(".", "data", appName)),
+		Cache:  dirOr(sys.PlatformCacheDir(appName), filepath.Join(".", "cache", appName)),
+		Logs:   dirOr(sys.PlatformLogDir(appName), filepath.Join(".", "logs", appName)),
+	}
+}
+
+func dirOr(dir, fallback string) string {
+	if dir = strings.TrimSpace(dir); dir != "" {
+		return dir
+	}
+	return fallback
+}
+
+// CurrentDataPaths returns the layout for the effective bot name and any
+// directory set with SetDataDir.
+func CurrentDataPaths() DataPaths {
+	return ResolveDataPaths(EffectiveBotName(), dataDirOverride)
+}
+
+// SetDataDir roots all application paths under dir and recomputes the base
+// paths. An empty dir restores the platform layout.
+func SetDataDir(dir string) {
+	if dir = strings.TrimSpace(dir); dir != "" {
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+	}
+	dataDirOverride = dir
+	recomputeBasePaths()
+}
+
+// Ensure creates every base directory. Safe to call multiple times.
+func (p DataPaths) Ensure() error {
+	for _, d := range []string{p.Config, p.Data, p.Cache, p.Logs} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			return fmt.Errorf("DataPaths.Ensure: %w", err)
+		}
+	}
+	return nil
+}
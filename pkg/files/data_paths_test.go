@@ -0,0 +1,47 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveDataPathsUnderRoot(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	paths := ResolveDataPaths("bot", root)
+	want := DataPaths{
+		Config: root,
+		Data:   filepath.Join(root, "data"),
+		Cache:  filepath.Join(root, "cache"),
+		Logs:   filepath.Join(root, "logs"),
+	}
+	if paths != want {
+		t.Fatalf("ResolveDataPaths = %+v, want %+v", paths, want)
+	}
+	if err := paths.Ensure(); err != nil {
+		t.Fatalf("Ensure: %v", err)
+	}
+	for _, d := range []string{paths.Config, paths.Data, paths.Cache, paths.Logs} {
+		if info, err := os.Stat(d); err != nil || !info.IsDir() {
+			t.Fatalf("directory %s not created: %v", d, err)
+		}
+	}
+}
+
+func TestSetDataDirRecomputesBasePaths(t *testing.T) {
+	root := t.TempDir()
+	SetDataDir(root)
+	t.Cleanup(func() { SetDataDir("") })
+
+	if ApplicationSupportPath != root {
+		t.Fatalf("ApplicationSupportPath = %q, want %q", ApplicationSupportPath, root)
+	}
+	if ApplicationCachesPath != filepath.Join(root, "cache") {
+		t.Fatalf("ApplicationCachesPath = %q", ApplicationCachesPath)
+	}
+	if got := GetLogFilePath(); got != filepath.Join(root, "logs", "discordcore.log") {
+		t.Fatalf("GetLogFilePath = %q", got)
+	}
+}
@@ -0,0 +1,43 @@
+package files
+
+import (
+	"fmt"
+	"slices"
+)
+
+// MaxCommandNameLength is the longest name Discord accepts for a slash
+// command.
+const MaxCommandNameLength = 32
+
+// ConfigCommandName is /config, which disables the others and so can never
+// be disabled itself.
+const ConfigCommandName = "config"
+
+// ValidCommandName reports whether name can name a slash command: 1 to 32
+// lowercase letters, digits, dashes or underscores.
+func ValidCommandName(name string) bool {
+	return isSlug(name, MaxCommandNameLength)
+}
+
+// CommandDisabled reports whether the slash command name, a top-level
+// command such as "stats", is turned off in the guild.
+func (gc GuildConfig) CommandDisabled(name string) bool {
+	return slices.Contains(gc.DisabledCommands, name)
+}
+
+func validateDisabledCommands(names []string, guildIndex int) error {
+	for i, name := range names {
+		field := fmt.Sprintf("guilds[%d].disabled_commands[%d]", guildIndex, i)
+		if !ValidCommandName(name) {
+			return NewValidationError(field, name,
+				fmt.Sprintf("command names must be 1 to %d lowercase letters, digits, dashes or underscores", MaxCommandNameLength))
+		}
+		if name == ConfigCommandName {
+			return NewValidationError(field, name, "/config cannot be disabled")
+		}
+		if slices.Contains(names[:i], name) {
+			return NewValidationError(field, name, "command is listed twice")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package files
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateDisabledCommands(t *testing.T) {
+	t.Parallel()
+
+	guild := GuildConfig{GuildID: "g1", DisabledCommands: []string{"stats", "reaction_block"}}
+	if err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{guild}}); err != nil {
+		t.Fatalf("valid disabled commands rejected: %v", err)
+	}
+	if !guild.CommandDisabled("stats") || guild.CommandDisabled("qotd") {
+		t.Fatalf("CommandDisabled disagrees with %v", guild.DisabledCommands)
+	}
+	for field, names := range map[string][]string{
+		"guilds[0].disabled_commands[0]": {"Stats"},
+		"guilds[0].disabled_commands[1]": {"stats", "stats"},
+		"guilds[0].disabled_commands[2]": {"stats", "qotd", ConfigCommandName},
+	} {
+		var verr ValidationError
+		err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", DisabledCommands: names}}})
+		if !errors.As(err, &verr) || verr.Field != field {
+			t.Fatalf("expected validation error on %s for %v, got %v", field, names, err)
+		}
+	}
+}
@@ -23,14 +23,13 @@ func TokenHash(token string) string {
 	return hex.EncodeToString(hash[:16])
 }
 
+// TokenEnvNames lists the environment variables, in order of precedence, whose
+// bot token keys config encryption when PASTEBIN_ENCRYPTION_KEY is unset.
+var TokenEnvNames = []string{"DISCORDCORE_TOKEN", "DISCORD_TOKEN", "BOT_TOKEN"}
+
 // getEncryptionKey derives a 32-byte key from environment variables.
 func getEncryptionKey() []byte {
-	keys := []string{
-		"PASTEBIN_ENCRYPTION_KEY",
-		"DISCORDCORE_TOKEN",
-		"DISCORD_TOKEN",
-		"BOT_TOKEN",
-	}
+	keys := append([]string{"PASTEBIN_ENCRYPTION_KEY"}, TokenEnvNames...)
 	var secret string
 	for _, k := range keys {
 		if val := os.Getenv(k); val != "" {
@@ -135,3 +134,18 @@ func (es *EncryptedString) UnmarshalJSON(data []byte) error {
 	*es = EncryptedString(dec)
 	return nil
 }
+
+// ContentHashSaltEnv overrides the salt of message content hashes.
+const ContentHashSaltEnv = "DISCORDCORE_CONTENT_HASH_SALT"
+
+// ContentHashSalt returns the salt for message content hashes. Without an
+// override it is derived from the config encryption key, so hashes stay
+// comparable across restarts without another secret to manage.
+func ContentHashSalt() []byte {
+	if salt := EnvString(ContentHashSaltEnv, ""); salt != "" {
+		hash := sha256.Sum256([]byte(salt))
+		return hash[:]
+	}
+	hash := sha256.Sum256(append([]byte("content-hash:"), getEncryptionKey()...))
+	return hash[:]
+}
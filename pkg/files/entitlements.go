@@ -0,0 +1,145 @@
+package files
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Entitlements gating premium features. Hosted deployments decide which guilds
+// hold them through an EntitlementProvider; without a provider every guild is
+// entitled to everything, which is what self-hosted deployments want.
+const (
+	EntitlementCharts         = "charts"
+	EntitlementAITriage       = "ai_triage"
+	EntitlementCrossGuildSync = "cross_guild_sync"
+)
+
+// DefaultEntitlementTTL is how long a guild's entitlements are cached.
+const DefaultEntitlementTTL = 5 * time.Minute
+
+const (
+	// entitlementRetryTTL bounds how long a failed lookup is trusted before
+	// the provider is asked again.
+	entitlementRetryTTL = 30 * time.Second
+	// entitlementFetchTimeout bounds how long a feature resolution may block
+	// on the provider for a guild missing from the cache.
+	entitlementFetchTimeout = 2 * time.Second
+)
+
+// EntitlementProvider reports the entitlements a guild holds, e.g. from a
+// billing system.
+type EntitlementProvider interface {
+	GuildEntitlements(ctx context.Context, guildID string) ([]string, error)
+}
+
+type entitlementEntry struct {
+	granted map[string]struct{}
+	expires time.Time
+}
+
+// EntitlementCache memoizes an EntitlementProvider per guild so feature
+// resolution, which runs on every event, rarely reaches the provider. A failed
+// lookup keeps the guild's previous entitlements when there are any and denies
+// otherwise, retrying after a short delay.
+//
+// Goroutine safety: every method is safe to call concurrently.
+type EntitlementCache struct {
+	provider EntitlementProvider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]entitlementEntry
+}
+
+// NewEntitlementCache wraps provider, caching each guild's entitlements for
+// ttl (DefaultEntitlementTTL when non-positive).
+func NewEntitlementCache(provider EntitlementProvider, ttl time.Duration) *EntitlementCache {
+	if ttl <= 0 {
+		ttl = DefaultEntitlementTTL
+	}
+	return &EntitlementCache{
+		provider: provider,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[string]entitlementEntry),
+	}
+}
+
+// Entitled reports whether guildID holds entitlement.
+func (c *EntitlementCache) Entitled(guildID, entitlement string) bool {
+	now := c.now()
+	c.mu.Lock()
+	entry, ok := c.entries[guildID]
+	c.mu.Unlock()
+	if !ok || !now.Before(entry.expires) {
+		entry = c.fetch(guildID, entry, now)
+	}
+	_, granted := entry.granted[entitlement]
+	return granted
+}
+
+// fetch asks the provider for guildID outside the lock; concurrent misses for
+// the same guild may each fetch, and the last result wins.
+func (c *EntitlementCache) fetch(guildID string, previous entitlementEntry, now time.Time) entitlementEntry {
+	ctx, cancel := context.WithTimeout(context.Background(), entitlementFetchTimeout)
+	defer cancel()
+
+	entry := entitlementEntry{granted: previous.granted, expires: now.Add(entitlementRetryTTL)}
+	if list, err := c.provider.GuildEntitlements(ctx, guildID); err == nil {
+		entry.granted = make(map[string]struct{}, len(list))
+		for _, e := range list {
+			entry.granted[e] = struct{}{}
+		}
+		entry.expires = now.Add(c.ttl)
+	}
+
+	c.mu.Lock()
+	c.entries[guildID] = entry
+	c.mu.Unlock()
+	return entry
+}
+
+// Invalidate drops the cached entitlements of guildID, or of every guild when
+// guildID is empty, e.g. after a purchase.
+func (c *EntitlementCache) Invalidate(guildID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if guildID == "" {
+		clear(c.entries)
+		return
+	}
+	delete(c.entries, guildID)
+}
+
+var activeEntitlements atomic.Pointer[EntitlementCache]
+
+// SetEntitlementProvider installs the provider consulted by ResolveFeatures
+// and GuildEntitled, behind an EntitlementCache, and returns that cache. A nil
+// provider entitles every guild again.
+func SetEntitlementProvider(provider EntitlementProvider) *EntitlementCache {
+	if provider == nil {
+		activeEntitlements.Store(nil)
+		return nil
+	}
+	cache := NewEntitlementCache(provider, DefaultEntitlementTTL)
+	activeEntitlements.Store(cache)
+	return cache
+}
+
+// GuildEntitled reports whether guildID holds entitlement. Premium features
+// without a feature toggle check it directly.
+func GuildEntitled(guildID, entitlement string) bool {
+	return guildEntitled(activeEntitlements.Load(), guildID, entitlement)
+}
+
+// guildEntitled treats a missing cache, an empty entitlement and the global
+// scope (empty guildID) as entitled.
+func guildEntitled(cache *EntitlementCache, guildID, entitlement string) bool {
+	if cache == nil || entitlement == "" || guildID == "" {
+		return true
+	}
+	return cache.Entitled(guildID, entitlement)
+}
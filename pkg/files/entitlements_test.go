@@ -0,0 +1,64 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeEntitlementProvider struct {
+	granted []string
+	err     error
+	calls   int
+}
+
+func (f *fakeEntitlementProvider) GuildEntitlements(context.Context, string) ([]string, error) {
+	f.calls++
+	return f.granted, f.err
+}
+
+func TestEntitlementCache_CachesAndExpires(t *testing.T) {
+	t.Parallel()
+	provider := &fakeEntitlementProvider{granted: []string{EntitlementCharts}}
+	cache := NewEntitlementCache(provider, time.Minute)
+	now := time.Unix(1_700_000_000, 0)
+	cache.now = func() time.Time { return now }
+
+	if !cache.Entitled("g1", EntitlementCharts) || cache.Entitled("g1", EntitlementAITriage) {
+		t.Fatal("unexpected entitlements for g1")
+	}
+	if provider.calls != 1 {
+		t.Fatalf("expected one provider call, got %d", provider.calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	provider.err = errors.New("billing unavailable")
+	if !cache.Entitled("g1", EntitlementCharts) {
+		t.Fatal("expected a failed refresh to keep the previous entitlements")
+	}
+	if cache.Entitled("g2", EntitlementCharts) {
+		t.Fatal("expected a failed first lookup to deny")
+	}
+
+	provider.err = nil
+	provider.granted = nil
+	cache.Invalidate("g1")
+	if cache.Entitled("g1", EntitlementCharts) {
+		t.Fatal("expected invalidation to pick up the revoked entitlement")
+	}
+}
+
+func TestGuildEntitled_Defaults(t *testing.T) {
+	t.Parallel()
+	cache := NewEntitlementCache(&fakeEntitlementProvider{}, time.Minute)
+	if !guildEntitled(nil, "g1", EntitlementCharts) {
+		t.Fatal("expected every guild to be entitled without a provider")
+	}
+	if !guildEntitled(cache, "g1", "") || !guildEntitled(cache, "", EntitlementCharts) {
+		t.Fatal("expected free toggles and the global scope to be entitled")
+	}
+	if guildEntitled(cache, "g1", EntitlementCharts) {
+		t.Fatal("expected a guild without the entitlement to be denied")
+	}
+}
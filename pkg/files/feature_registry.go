@@ -21,8 +21,11 @@ package files
 // Accessor functions replace reflection to ensure compile-time safety
 // when interacting with FeatureToggles and ResolvedFeatureToggles.
 type toggleSpec struct {
-	ID          string
-	Default     bool
+	ID      string
+	Default bool
+	// Entitlement, when set, names the entitlement a guild must hold for
+	// the toggle to resolve to true.
+	Entitlement string
 	Get         func(ft *FeatureToggles) *bool
 	Set         func(ft *FeatureToggles, val *bool)
 	GetResolved func(rft *ResolvedFeatureToggles) bool
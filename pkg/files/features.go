@@ -160,8 +160,12 @@ func resolveFeatureBool(guildVal *bool, globalVal *bool, def bool) bool {
 	return def
 }
 
-// ResolveFeatures merges global and guild feature toggles with defaults.
+// ResolveFeatures merges global, profile and guild feature toggles with defaults.
+// A guild toggle beats its inherited profiles, which beat the global toggle.
+// Toggles requiring an entitlement resolve to false for guilds without it.
 func (cfg *BotConfig) ResolveFeatures(guildID string) ResolvedFeatureToggles {
+	entitlements := activeEntitlements.Load()
+
 	global := FeatureToggles{}
 	if cfg != nil {
 		global = cfg.Features
@@ -176,12 +180,22 @@ func (cfg *BotConfig) ResolveFeatures(guildID string) ResolvedFeatureToggles {
 			}
 		}
 	}
+	chain := cfg.guildProfileChain(guildID)
 
 	var out ResolvedFeatureToggles
 	for _, spec := range featureRegistry {
 		guildPtr := guild.LookupToggle(spec.ID)
+		for _, profile := range chain {
+			if guildPtr != nil {
+				break
+			}
+			guildPtr = profile.Features.LookupToggle(spec.ID)
+		}
 		globalPtr := global.LookupToggle(spec.ID)
 		resolved := resolveFeatureBool(guildPtr, globalPtr, spec.Default)
+		if resolved && !guildEntitled(entitlements, guildID, spec.Entitlement) {
+			resolved = false
+		}
 		spec.SetResolved(&out, resolved)
 	}
 
@@ -0,0 +1,34 @@
+package files
+
+import "slices"
+
+// GuildAccessPolicy restricts which guilds the bot may stay in. Guild IDs in
+// Deny are always refused; when Allow is non-empty, every guild missing from
+// it is refused too. The zero policy allows every guild.
+type GuildAccessPolicy struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// GuildAccessRefusal reports why guildID is not allowed under the bot's guild
+// access policy, or false when the bot may stay in it.
+func (cfg *BotConfig) GuildAccessRefusal(guildID string) (string, bool) {
+	if cfg == nil || guildID == "" {
+		return "", false
+	}
+	policy := cfg.GuildAccess
+	if slices.Contains(policy.Deny, guildID) {
+		return "guild is on the denylist", true
+	}
+	if len(policy.Allow) > 0 && !slices.Contains(policy.Allow, guildID) {
+		return "guild is not on the allowlist", true
+	}
+	return "", false
+}
+
+func cloneGuildAccessPolicy(in GuildAccessPolicy) GuildAccessPolicy {
+	return GuildAccessPolicy{
+		Allow: slices.Clone(in.Allow),
+		Deny:  slices.Clone(in.Deny),
+	}
+}
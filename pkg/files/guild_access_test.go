@@ -0,0 +1,33 @@
+package files
+
+import "testing"
+
+func TestBotConfigGuildAccessRefusal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		policy  GuildAccessPolicy
+		guildID string
+		refused bool
+	}{
+		{name: "empty policy allows", guildID: "1"},
+		{name: "allowlisted", policy: GuildAccessPolicy{Allow: []string{"1"}}, guildID: "1"},
+		{name: "missing from allowlist", policy: GuildAccessPolicy{Allow: []string{"1"}}, guildID: "2", refused: true},
+		{name: "denylisted", policy: GuildAccessPolicy{Deny: []string{"2"}}, guildID: "2", refused: true},
+		{name: "deny wins over allow", policy: GuildAccessPolicy{Allow: []string{"2"}, Deny: []string{"2"}}, guildID: "2", refused: true},
+		{name: "not on denylist", policy: GuildAccessPolicy{Deny: []string{"2"}}, guildID: "3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &BotConfig{GuildAccess: tt.policy}
+			reason, refused := cfg.GuildAccessRefusal(tt.guildID)
+			if refused != tt.refused {
+				t.Fatalf("refused = %v (%q), want %v", refused, reason, tt.refused)
+			}
+			if refused && reason == "" {
+				t.Fatal("expected a reason for the refusal")
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package files
 
 import (
 	"context"
+	"reflect"
 	"testing"
 )
 
@@ -16,7 +17,7 @@ func TestNewMinimalGuildConfigDisablesAllFeatures(t *testing.T) {
 	if guild.GuildID != "guild-new" {
 		t.Fatalf("expected guild id to be preserved, got %+v", guild)
 	}
-	if guild.Channels != (ChannelsConfig{}) {
+	if !reflect.DeepEqual(guild.Channels, ChannelsConfig{}) {
 		t.Fatalf("expected minimal guild to avoid channel bootstrap, got %+v", guild.Channels)
 	}
 	if len(guild.Roles.Allowed) != 0 ||
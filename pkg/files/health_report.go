@@ -0,0 +1,25 @@
+package files
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HealthReportConfig sets up the weekly server health digest posted to a
+// staff channel.
+type HealthReportConfig struct {
+	// ChannelID is the staff channel the digest goes to. Empty disables it.
+	ChannelID string `json:"channel_id,omitempty"`
+	// AttachFile adds the full report as a JSON file to the digest.
+	AttachFile bool `json:"attach_file,omitempty"`
+}
+
+// Enabled reports whether the digest is posted at all.
+func (c HealthReportConfig) Enabled() bool { return strings.TrimSpace(c.ChannelID) != "" }
+
+func validateHealthReport(cfg HealthReportConfig, guildIndex int) error {
+	if cfg.Enabled() && !isAllDigits(strings.TrimSpace(cfg.ChannelID)) {
+		return NewValidationError(fmt.Sprintf("guilds[%d].health_report.channel_id", guildIndex), cfg.ChannelID, "channel must be a numeric ID")
+	}
+	return nil
+}
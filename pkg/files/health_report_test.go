@@ -0,0 +1,23 @@
+package files
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateBotConfigRejectsInvalidHealthReportChannel(t *testing.T) {
+	t.Parallel()
+
+	cfg := &BotConfig{Guilds: []GuildConfig{{GuildID: "g1", HealthReport: HealthReportConfig{ChannelID: "staff"}}}}
+	var verr ValidationError
+	if err := validateBotConfig(cfg); !errors.As(err, &verr) || verr.Field != "guilds[0].health_report.channel_id" {
+		t.Fatalf("expected validation error on health_report.channel_id, got %v", err)
+	}
+
+	for _, report := range []HealthReportConfig{{}, {ChannelID: "123", AttachFile: true}} {
+		cfg := &BotConfig{Guilds: []GuildConfig{{GuildID: "g1", HealthReport: report}}}
+		if err := validateBotConfig(cfg); err != nil {
+			t.Fatalf("valid health report %+v rejected: %v", report, err)
+		}
+	}
+}
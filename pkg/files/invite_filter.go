@@ -0,0 +1,91 @@
+package files
+
+import (
+	"fmt"
+	"slices"
+)
+
+// InviteFilterRuleName names the invite filter in the cases and flags it
+// produces, as the rule name does for automod rules.
+const InviteFilterRuleName = "invite-filter"
+
+// InviteLinkPattern matches Discord invite links. Its first group is the
+// invite code.
+const InviteLinkPattern = `(?i)(?:https?://)?(?:www\.)?(?:discord(?:app)?\.com/invite|discord\.(?:gg|io|me|li))/([a-z0-9-]+)`
+
+const maxInviteCodeLength = 32
+
+// InviteFilterConfig makes the bot act on members posting invites to other
+// servers. Invites to the guild itself, dead invites and the allowed codes
+// are left alone. Actions are those of automod rules and default to
+// deleting the message.
+type InviteFilterConfig struct {
+	Enabled          bool     `json:"enabled,omitempty"`
+	Actions          []string `json:"actions,omitempty"`
+	TimeoutMinutes   int      `json:"timeout_minutes,omitempty"`
+	FlagChannelID    string   `json:"flag_channel_id,omitempty"`
+	AllowedCodes     []string `json:"allowed_codes,omitempty"`
+	ExemptRoleIDs    []string `json:"exempt_role_ids,omitempty"`
+	ExemptChannelIDs []string `json:"exempt_channel_ids,omitempty"`
+}
+
+// Rule returns the automod rule the filter enforces on messages with a
+// foreign invite.
+func (c InviteFilterConfig) Rule() AutomodRule {
+	actions := c.Actions
+	if len(actions) == 0 {
+		actions = []string{AutomodActionDelete}
+	}
+	return AutomodRule{
+		Name:             InviteFilterRuleName,
+		Pattern:          InviteLinkPattern,
+		Actions:          actions,
+		TimeoutMinutes:   c.TimeoutMinutes,
+		FlagChannelID:    c.FlagChannelID,
+		ExemptRoleIDs:    c.ExemptRoleIDs,
+		ExemptChannelIDs: c.ExemptChannelIDs,
+	}
+}
+
+// Allows reports whether invites with code are allowed. Codes are case
+// sensitive.
+func (c InviteFilterConfig) Allows(code string) bool {
+	return slices.Contains(c.AllowedCodes, code)
+}
+
+func validateInviteFilter(cfg InviteFilterConfig, guildIndex int) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if err := cfg.Rule().Validate(); err != nil {
+		return NewValidationError(fmt.Sprintf("guilds[%d].invite_filter", guildIndex), cfg.Actions, err.Error())
+	}
+	for i, code := range cfg.AllowedCodes {
+		if !validInviteCode(code) {
+			return NewValidationError(fmt.Sprintf("guilds[%d].invite_filter.allowed_codes[%d]", guildIndex, i), code,
+				fmt.Sprintf("invite codes are 1 to %d letters, digits or dashes, without the link", maxInviteCodeLength))
+		}
+	}
+	return nil
+}
+
+func validInviteCode(code string) bool {
+	if code == "" || len(code) > maxInviteCodeLength {
+		return false
+	}
+	for _, r := range code {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func cloneInviteFilterConfig(in InviteFilterConfig) InviteFilterConfig {
+	out := in
+	out.Actions = cloneStringSlice(in.Actions)
+	out.AllowedCodes = cloneStringSlice(in.AllowedCodes)
+	out.ExemptRoleIDs = cloneStringSlice(in.ExemptRoleIDs)
+	out.ExemptChannelIDs = cloneStringSlice(in.ExemptChannelIDs)
+	return out
+}
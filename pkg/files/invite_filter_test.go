@@ -0,0 +1,52 @@
+package files
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestValidateInviteFilter(t *testing.T) {
+	t.Parallel()
+
+	valid := InviteFilterConfig{Enabled: true, AllowedCodes: []string{"AbC-12"}, ExemptChannelIDs: []string{"1"}}
+	if err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", InviteFilter: valid}}}); err != nil {
+		t.Fatalf("valid invite filter rejected: %v", err)
+	}
+	if rule := valid.Rule(); !slices.Equal(rule.Actions, []string{AutomodActionDelete}) {
+		t.Fatalf("expected the filter to delete by default, got %v", rule.Actions)
+	}
+
+	for field, filter := range map[string]InviteFilterConfig{
+		"guilds[0].invite_filter":                  {Enabled: true, Actions: []string{AutomodActionTimeout}},
+		"guilds[0].invite_filter.allowed_codes[0]": {Enabled: true, AllowedCodes: []string{"discord.gg/abc"}},
+	} {
+		var verr ValidationError
+		err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", InviteFilter: filter}}})
+		if !errors.As(err, &verr) || verr.Field != field {
+			t.Fatalf("expected validation error on %s, got %v", field, err)
+		}
+	}
+}
+
+func TestInviteLinkPattern(t *testing.T) {
+	t.Parallel()
+
+	re := regexp.MustCompile(InviteLinkPattern)
+	for text, code := range map[string]string{
+		"https://discord.gg/AbC123":               "AbC123",
+		"join discordapp.com/invite/xyz now":      "xyz",
+		"http://www.discord.com/invite/vanity-ok": "vanity-ok",
+		"discord.gg is down":                      "",
+		"https://example.com/invite/abc":          "",
+	} {
+		var got string
+		if m := re.FindStringSubmatch(text); m != nil {
+			got = m[1]
+		}
+		if got != code {
+			t.Errorf("code in %q = %q, want %q", text, got, code)
+		}
+	}
+}
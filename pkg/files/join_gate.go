@@ -0,0 +1,94 @@
+package files
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Join gate actions.
+const (
+	JoinGateActionKick       = "kick"
+	JoinGateActionQuarantine = "quarantine"
+	JoinGateActionLog        = "log"
+)
+
+const (
+	// MaxJoinGatePatterns bounds the blocked name patterns of a guild.
+	MaxJoinGatePatterns = 20
+	// MaxJoinGatePatternLength bounds each blocked name pattern.
+	MaxJoinGatePatternLength = 200
+)
+
+// JoinGateConfig screens members as they join. Members whose account is
+// younger than MinAccountAgeDays, who have no avatar while RequireAvatar is
+// set, or whose username, global name or nickname matches a blocked pattern
+// fail the gate, unless they are on the bypass list. Action says what
+// happens to them; it defaults to logging only.
+type JoinGateConfig struct {
+	Enabled             bool     `json:"enabled,omitempty"`
+	MinAccountAgeDays   int      `json:"min_account_age_days,omitempty"`
+	RequireAvatar       bool     `json:"require_avatar,omitempty"`
+	BlockedNamePatterns []string `json:"blocked_name_patterns,omitempty"`
+	Action              string   `json:"action,omitempty"`
+	// QuarantineRoleID is the role given to members who fail the gate when
+	// Action is quarantine.
+	QuarantineRoleID string   `json:"quarantine_role_id,omitempty"`
+	BypassUserIDs    []string `json:"bypass_user_ids,omitempty"`
+}
+
+// EffectiveAction returns the action taken on members failing the gate.
+func (c JoinGateConfig) EffectiveAction() string {
+	if action := strings.ToLower(strings.TrimSpace(c.Action)); action != "" {
+		return action
+	}
+	return JoinGateActionLog
+}
+
+// MinAccountAge returns the youngest account let through the gate.
+func (c JoinGateConfig) MinAccountAge() time.Duration {
+	return time.Duration(c.MinAccountAgeDays) * 24 * time.Hour
+}
+
+func validateJoinGate(cfg JoinGateConfig, guildIndex int) error {
+	field := func(name string) string { return fmt.Sprintf("guilds[%d].join_gate.%s", guildIndex, name) }
+	if cfg.MinAccountAgeDays < 0 {
+		return NewValidationError(field("min_account_age_days"), cfg.MinAccountAgeDays, "minimum account age must not be negative")
+	}
+	switch cfg.EffectiveAction() {
+	case JoinGateActionKick, JoinGateActionLog:
+	case JoinGateActionQuarantine:
+		if !isAllDigits(strings.TrimSpace(cfg.QuarantineRoleID)) {
+			return NewValidationError(field("quarantine_role_id"), cfg.QuarantineRoleID, "quarantining needs a quarantine role")
+		}
+	default:
+		return NewValidationError(field("action"), cfg.Action, "action must be kick, quarantine or log")
+	}
+	if len(cfg.BlockedNamePatterns) > MaxJoinGatePatterns {
+		return NewValidationError(field("blocked_name_patterns"), len(cfg.BlockedNamePatterns),
+			fmt.Sprintf("at most %d name patterns are allowed", MaxJoinGatePatterns))
+	}
+	for idx, pattern := range cfg.BlockedNamePatterns {
+		name := fmt.Sprintf("%s[%d]", field("blocked_name_patterns"), idx)
+		if pattern == "" || len(pattern) > MaxJoinGatePatternLength {
+			return NewValidationError(name, pattern, fmt.Sprintf("patterns are 1 to %d characters", MaxJoinGatePatternLength))
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return NewValidationError(name, pattern, err.Error())
+		}
+	}
+	for idx, userID := range cfg.BypassUserIDs {
+		if !isAllDigits(strings.TrimSpace(userID)) {
+			return NewValidationError(fmt.Sprintf("%s[%d]", field("bypass_user_ids"), idx), userID, "user must be a numeric ID")
+		}
+	}
+	return nil
+}
+
+func cloneJoinGateConfig(in JoinGateConfig) JoinGateConfig {
+	out := in
+	out.BlockedNamePatterns = cloneStringSlice(in.BlockedNamePatterns)
+	out.BypassUserIDs = cloneStringSlice(in.BypassUserIDs)
+	return out
+}
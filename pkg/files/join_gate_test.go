@@ -0,0 +1,31 @@
+package files
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateJoinGate(t *testing.T) {
+	t.Parallel()
+
+	valid := JoinGateConfig{Enabled: true, MinAccountAgeDays: 3, BlockedNamePatterns: []string{`^spam\d+$`}, BypassUserIDs: []string{"1"}}
+	if err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", JoinGate: valid}}}); err != nil {
+		t.Fatalf("valid join gate rejected: %v", err)
+	}
+	if valid.EffectiveAction() != JoinGateActionLog {
+		t.Fatalf("expected the gate to log only by default, got %q", valid.EffectiveAction())
+	}
+
+	for field, gate := range map[string]JoinGateConfig{
+		"guilds[0].join_gate.action":                   {Enabled: true, Action: "ban"},
+		"guilds[0].join_gate.quarantine_role_id":       {Enabled: true, Action: JoinGateActionQuarantine},
+		"guilds[0].join_gate.blocked_name_patterns[0]": {Enabled: true, BlockedNamePatterns: []string{"("}},
+		"guilds[0].join_gate.bypass_user_ids[0]":       {Enabled: true, BypassUserIDs: []string{"@someone"}},
+	} {
+		var verr ValidationError
+		err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", JoinGate: gate}}})
+		if !errors.As(err, &verr) || verr.Field != field {
+			t.Fatalf("expected validation error on %s, got %v", field, err)
+		}
+	}
+}
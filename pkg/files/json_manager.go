@@ -14,9 +14,14 @@ import (
 )
 
 // JSONManager handles reading and writing JSON data to a file.
+//
+// Saves are atomic: payloads are written to a sibling temp file, fsynced, and renamed
+// over the target. When Backups is positive, the previous file contents are rotated
+// into FilePath.1 … FilePath.N before each replacement.
 type JSONManager struct {
 	FilePath    string
 	ProjectRoot string // Optional: for safe saving
+	Backups     int    // Optional: number of rotated backups retained on Save
 	mu          sync.Mutex
 }
 
@@ -53,10 +58,17 @@ func (m *JSONManager) Save(data any) (err error) {
 	if err != nil {
 		return fmt.Errorf("failed to marshal json: %w", err)
 	}
+	return m.SaveRaw(fileData)
+}
 
+// SaveRaw writes pre-encoded bytes to the managed file with the same atomic
+// replacement and backup rotation guarantees as Save. It lets callers persist
+// non-JSON encodings through a single durable write path.
+func (m *JSONManager) SaveRaw(fileData []byte) (err error) {
 	m.mu.Lock()
 	targetPath := m.FilePath
 	projectRoot := m.ProjectRoot
+	backups := m.Backups
 	m.mu.Unlock()
 
 	if projectRoot != "" {
@@ -125,6 +137,11 @@ func (m *JSONManager) Save(data any) (err error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if backups > 0 {
+		if err := rotateBackups(targetPath, backups); err != nil {
+			return fmt.Errorf("failed to rotate backups: %w", err)
+		}
+	}
 	if err := sys.ReplaceFile(tmpPath, targetPath); err != nil {
 		return fmt.Errorf("failed to replace file atomically: %w", err)
 	}
@@ -136,6 +153,48 @@ func (m *JSONManager) Save(data any) (err error) {
 	return nil
 }
 
+// BackupPath returns the path of the n-th rotated backup for targetPath.
+func BackupPath(targetPath string, n int) string {
+	return fmt.Sprintf("%s.%d", targetPath, n)
+}
+
+// MigrationBackupPath returns the path the copy of targetPath taken before
+// upgrading it from schema version from is kept at.
+func MigrationBackupPath(targetPath string, from int) string {
+	return fmt.Sprintf("%s.v%d.bak", targetPath, from)
+}
+
+// rotateBackups shifts targetPath.1 … targetPath.(keep-1) up by one slot, discarding the
+// oldest, and copies the current targetPath into targetPath.1. The live file is copied
+// rather than renamed so that it stays in place until the atomic replacement lands.
+func rotateBackups(targetPath string, keep int) error {
+	current, err := os.ReadFile(targetPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("read current file: %w", err)
+	}
+
+	if err := os.Remove(BackupPath(targetPath, keep)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove oldest backup: %w", err)
+	}
+	for i := keep - 1; i >= 1; i-- {
+		if err := os.Rename(BackupPath(targetPath, i), BackupPath(targetPath, i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("shift backup %d: %w", i, err)
+		}
+	}
+
+	fileMode := os.FileMode(0o644)
+	if info, err := os.Stat(targetPath); err == nil {
+		fileMode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(BackupPath(targetPath, 1), current, fileMode); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+	return nil
+}
+
 // safeJoin ensures that the joined path is within the base directory.
 func safeJoin(baseDir, relPath string) (string, error) {
 	cleanBase := filepath.Clean(baseDir)
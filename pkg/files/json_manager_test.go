@@ -46,3 +46,46 @@ func TestJSONManagerSaveWritesAtomically(t *testing.T) {
 		t.Fatalf("expected no temp files left behind, got %v", tmpMatches)
 	}
 }
+
+func TestJSONManagerSaveRotatesBackups(t *testing.T) {
+	t.Parallel()
+
+	type payload struct {
+		Count int `json:"count"`
+	}
+
+	path := filepath.Join(t.TempDir(), "settings.json")
+	manager := &JSONManager{FilePath: path, Backups: 2}
+
+	for i := 1; i <= 4; i++ {
+		if err := manager.Save(payload{Count: i}); err != nil {
+			t.Fatalf("save %d: %v", i, err)
+		}
+	}
+
+	read := func(p string) payload {
+		t.Helper()
+		raw, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatalf("read %s: %v", p, err)
+		}
+		var got payload
+		if err := json.Unmarshal(raw, &got); err != nil {
+			t.Fatalf("unmarshal %s: %v", p, err)
+		}
+		return got
+	}
+
+	if got := read(path); got.Count != 4 {
+		t.Fatalf("expected live file count=4, got %+v", got)
+	}
+	if got := read(BackupPath(path, 1)); got.Count != 3 {
+		t.Fatalf("expected backup 1 count=3, got %+v", got)
+	}
+	if got := read(BackupPath(path, 2)); got.Count != 2 {
+		t.Fatalf("expected backup 2 count=2, got %+v", got)
+	}
+	if _, err := os.Stat(BackupPath(path, 3)); !os.IsNotExist(err) {
+		t.Fatalf("expected no third backup, stat err=%v", err)
+	}
+}
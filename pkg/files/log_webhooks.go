@@ -0,0 +1,45 @@
+package files
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// maxWebhookUsernameLength is Discord's limit for a webhook message's name.
+const maxWebhookUsernameLength = 80
+
+// LogWebhookConfig makes the logging bot post log embeds through a webhook it
+// creates in each log channel, so they carry their own name and avatar.
+type LogWebhookConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Username and AvatarURL brand the messages. Empty keeps the webhook's
+	// own name and avatar.
+	Username  string `json:"username,omitempty"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+func validateLogWebhooks(cfg LogWebhookConfig, guildIndex int) error {
+	if cfg.Username != "" {
+		name := strings.TrimSpace(cfg.Username)
+		lower := strings.ToLower(name)
+		if name == "" || len([]rune(name)) > maxWebhookUsernameLength || strings.Contains(lower, "discord") || strings.Contains(lower, "clyde") {
+			return NewValidationError(
+				fmt.Sprintf("guilds[%d].log_webhooks.username", guildIndex),
+				cfg.Username,
+				fmt.Sprintf("username must be 1-%d characters and may not contain \"discord\" or \"clyde\"", maxWebhookUsernameLength),
+			)
+		}
+	}
+	if cfg.AvatarURL != "" {
+		u, err := url.Parse(cfg.AvatarURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return NewValidationError(
+				fmt.Sprintf("guilds[%d].log_webhooks.avatar_url", guildIndex),
+				cfg.AvatarURL,
+				"avatar_url must be an http(s) URL",
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package files
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateBotConfigLogWebhooks(t *testing.T) {
+	t.Parallel()
+
+	for _, hooks := range []LogWebhookConfig{
+		{Enabled: true, Username: "   "},
+		{Enabled: true, Username: "Discord Logs"},
+		{Enabled: true, AvatarURL: "ftp://example.com/a.png"},
+		{Enabled: true, AvatarURL: "not a url"},
+	} {
+		cfg := &BotConfig{Guilds: []GuildConfig{{GuildID: "g1", LogWebhooks: hooks}}}
+		var verr ValidationError
+		if err := validateBotConfig(cfg); !errors.As(err, &verr) || verr.Field == "" {
+			t.Fatalf("expected a log webhook validation error for %+v, got %v", hooks, err)
+		}
+	}
+
+	cfg := &BotConfig{Guilds: []GuildConfig{{GuildID: "g1", LogWebhooks: LogWebhookConfig{
+		Enabled:   true,
+		Username:  "Server Logs",
+		AvatarURL: "https://cdn.example.com/logs.png",
+	}}}}
+	if err := validateBotConfig(cfg); err != nil {
+		t.Fatalf("expected branded log webhooks to validate, got %v", err)
+	}
+}
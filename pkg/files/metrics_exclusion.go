@@ -0,0 +1,65 @@
+package files
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MetricsExclusionConfig keeps activity out of a guild's metrics as it is
+// recorded, so spam channels or staff chatter do not skew leaderboards.
+// Messages still reach the message cache and logs. Bot messages are never
+// counted to begin with.
+type MetricsExclusionConfig struct {
+	ChannelIDs []string `json:"channel_ids,omitempty"`
+	// CategoryIDs excludes every channel in the categories, and their
+	// threads.
+	CategoryIDs []string `json:"category_ids,omitempty"`
+	// RoleIDs excludes the activity of members holding any of the roles.
+	RoleIDs []string `json:"role_ids,omitempty"`
+}
+
+// Excludes reports whether activity in channelID, under categoryID, by a
+// member holding roleIDs stays out of the metrics. categoryID is empty for
+// channels outside a category.
+func (c MetricsExclusionConfig) Excludes(channelID, categoryID string, roleIDs []string) bool {
+	if slices.Contains(c.ChannelIDs, channelID) {
+		return true
+	}
+	if categoryID != "" && slices.Contains(c.CategoryIDs, categoryID) {
+		return true
+	}
+	for _, roleID := range roleIDs {
+		if slices.Contains(c.RoleIDs, roleID) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateMetricsExclusions(cfg MetricsExclusionConfig, guildIndex int) error {
+	lists := []struct {
+		field string
+		ids   []string
+	}{
+		{"channel_ids", cfg.ChannelIDs},
+		{"category_ids", cfg.CategoryIDs},
+		{"role_ids", cfg.RoleIDs},
+	}
+	for _, list := range lists {
+		for idx, id := range list.ids {
+			if !isAllDigits(strings.TrimSpace(id)) {
+				return NewValidationError(fmt.Sprintf("guilds[%d].stats.exclusions.%s[%d]", guildIndex, list.field, idx), id, "must be a numeric ID")
+			}
+		}
+	}
+	return nil
+}
+
+func cloneMetricsExclusionConfig(in MetricsExclusionConfig) MetricsExclusionConfig {
+	return MetricsExclusionConfig{
+		ChannelIDs:  cloneStringSlice(in.ChannelIDs),
+		CategoryIDs: cloneStringSlice(in.CategoryIDs),
+		RoleIDs:     cloneStringSlice(in.RoleIDs),
+	}
+}
@@ -0,0 +1,35 @@
+package files
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMetricsExclusionConfig(t *testing.T) {
+	t.Parallel()
+	cfg := MetricsExclusionConfig{ChannelIDs: []string{"1"}, CategoryIDs: []string{"2"}, RoleIDs: []string{"3"}}
+
+	for _, tc := range []struct {
+		channelID, categoryID string
+		roleIDs               []string
+		want                  bool
+	}{
+		{"1", "", nil, true},
+		{"10", "2", nil, true},
+		{"10", "20", []string{"30", "3"}, true},
+		{"10", "20", []string{"30"}, false},
+		{"10", "", nil, false},
+	} {
+		if got := cfg.Excludes(tc.channelID, tc.categoryID, tc.roleIDs); got != tc.want {
+			t.Errorf("Excludes(%q, %q, %v) = %v, want %v", tc.channelID, tc.categoryID, tc.roleIDs, got, tc.want)
+		}
+	}
+
+	var verr ValidationError
+	err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", Stats: StatsConfig{
+		Exclusions: MetricsExclusionConfig{CategoryIDs: []string{"general"}},
+	}}}})
+	if !errors.As(err, &verr) || verr.Field != "guilds[0].stats.exclusions.category_ids[0]" {
+		t.Fatalf("expected validation error on the category, got %v", err)
+	}
+}
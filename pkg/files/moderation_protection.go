@@ -0,0 +1,36 @@
+package files
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ModerationProtectionConfig lists users, and roles whose holders, moderation
+// commands refuse to ban, kick, time out or warn, on top of the guild owner
+// and the bot. Automod leaves their messages alone too. The guild owner can
+// still act on them.
+type ModerationProtectionConfig struct {
+	UserIDs []string `json:"user_ids,omitempty"`
+	RoleIDs []string `json:"role_ids,omitempty"`
+}
+
+func validateModerationProtection(cfg ModerationProtectionConfig, guildIndex int) error {
+	for idx, userID := range cfg.UserIDs {
+		if !isAllDigits(strings.TrimSpace(userID)) {
+			return NewValidationError(fmt.Sprintf("guilds[%d].moderation_protection.user_ids[%d]", guildIndex, idx), userID, "user must be a numeric ID")
+		}
+	}
+	for idx, roleID := range cfg.RoleIDs {
+		if !isAllDigits(strings.TrimSpace(roleID)) {
+			return NewValidationError(fmt.Sprintf("guilds[%d].moderation_protection.role_ids[%d]", guildIndex, idx), roleID, "role must be a numeric ID")
+		}
+	}
+	return nil
+}
+
+func cloneModerationProtectionConfig(in ModerationProtectionConfig) ModerationProtectionConfig {
+	return ModerationProtectionConfig{
+		UserIDs: cloneStringSlice(in.UserIDs),
+		RoleIDs: cloneStringSlice(in.RoleIDs),
+	}
+}
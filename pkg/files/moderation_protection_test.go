@@ -0,0 +1,34 @@
+package files
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateBotConfigRejectsInvalidModerationProtection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		protection ModerationProtectionConfig
+		field      string
+	}{
+		{name: "named user", protection: ModerationProtectionConfig{UserIDs: []string{"1", "admin"}}, field: "guilds[0].moderation_protection.user_ids[1]"},
+		{name: "named role", protection: ModerationProtectionConfig{RoleIDs: []string{"staff"}}, field: "guilds[0].moderation_protection.role_ids[0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &BotConfig{Guilds: []GuildConfig{{GuildID: "g1", ModerationProtection: tt.protection}}}
+			var verr ValidationError
+			if err := validateBotConfig(cfg); !errors.As(err, &verr) || verr.Field != tt.field {
+				t.Fatalf("expected validation error on %s, got %v", tt.field, err)
+			}
+		})
+	}
+
+	valid := ModerationProtectionConfig{UserIDs: []string{"1"}, RoleIDs: []string{"2"}}
+	if err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", ModerationProtection: valid}}}); err != nil {
+		t.Fatalf("valid protection rejected: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package files
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MatchesEvent reports whether the route applies to eventType.
+func (route NotificationRouteConfig) MatchesEvent(eventType string) bool {
+	if len(route.Events) == 0 {
+		return true
+	}
+	for _, event := range route.Events {
+		if strings.EqualFold(strings.TrimSpace(event), eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateNotificationRoutes(routes []NotificationRouteConfig, guildIndex int) error {
+	for idx, route := range routes {
+		fieldBase := fmt.Sprintf("guilds[%d].notification_routes[%d]", guildIndex, idx)
+		channelID := strings.TrimSpace(route.ChannelID)
+		if channelID == "" {
+			return NewValidationError(fieldBase+".channel_id", route.ChannelID, "route channel is required")
+		}
+		if !isAllDigits(channelID) {
+			return NewValidationError(fieldBase+".channel_id", route.ChannelID, "route channel must be a numeric ID")
+		}
+		for roleIdx, roleID := range route.TargetRoleIDs {
+			if !isAllDigits(strings.TrimSpace(roleID)) {
+				return NewValidationError(fmt.Sprintf("%s.target_role_ids[%d]", fieldBase, roleIdx), roleID, "role must be a numeric ID")
+			}
+		}
+	}
+	return nil
+}
+
+func cloneNotificationRoutes(in []NotificationRouteConfig) []NotificationRouteConfig {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]NotificationRouteConfig, 0, len(in))
+	for _, route := range in {
+		next := NotificationRouteConfig{
+			Events:        slices.Clone(route.Events),
+			TargetRoleIDs: slices.Clone(route.TargetRoleIDs),
+			ChannelID:     route.ChannelID,
+		}
+		if route.TargetIsBot != nil {
+			v := *route.TargetIsBot
+			next.TargetIsBot = &v
+		}
+		if route.ActorIsBot != nil {
+			v := *route.ActorIsBot
+			next.ActorIsBot = &v
+		}
+		out = append(out, next)
+	}
+	return out
+}
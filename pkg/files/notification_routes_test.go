@@ -0,0 +1,45 @@
+package files
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateBotConfigRejectsInvalidNotificationRoutes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		route NotificationRouteConfig
+		field string
+	}{
+		{name: "missing channel", route: NotificationRouteConfig{TargetRoleIDs: []string{"1"}}, field: "guilds[0].notification_routes[0].channel_id"},
+		{name: "named channel", route: NotificationRouteConfig{ChannelID: "vip-log"}, field: "guilds[0].notification_routes[0].channel_id"},
+		{name: "named role", route: NotificationRouteConfig{ChannelID: "1", TargetRoleIDs: []string{"vip"}}, field: "guilds[0].notification_routes[0].target_role_ids[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &BotConfig{Guilds: []GuildConfig{{GuildID: "g1", NotificationRoutes: []NotificationRouteConfig{tt.route}}}}
+			var verr ValidationError
+			if err := validateBotConfig(cfg); !errors.As(err, &verr) || verr.Field != tt.field {
+				t.Fatalf("expected validation error on %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestCloneNotificationRoutesIsDeep(t *testing.T) {
+	t.Parallel()
+
+	isBot := true
+	in := []NotificationRouteConfig{{Events: []string{"message_delete"}, ActorIsBot: &isBot, ChannelID: "1"}}
+	out := cloneNotificationRoutes(in)
+	out[0].Events[0] = "role_change"
+	*out[0].ActorIsBot = false
+
+	if in[0].Events[0] != "message_delete" || !*in[0].ActorIsBot {
+		t.Fatalf("clone shares state with the original: %+v", in[0])
+	}
+}
@@ -8,7 +8,6 @@ import (
 
 	"strings"
 
-	"github.com/small-frappuccino/discordcore/pkg/sys"
 	"github.com/small-frappuccino/discordcore/pkg/theme"
 )
 
@@ -34,6 +33,12 @@ func init() {
 	CurrentGitBranch = getCurrentGitBranch()
 
 	// Initialize base paths with a fallback bot name; SetBotName will recompute them once the session is available.
+	recomputeBasePaths()
+}
+
+// recomputeBasePaths refreshes the package-level base paths after the name or
+// data directory changes.
+func recomputeBasePaths() {
 	ApplicationSupportPath = GetApplicationSupportPath(CurrentGitBranch)
 	ApplicationCachesPath = GetApplicationCachesPath()
 }
@@ -68,9 +73,7 @@ func SetBotName(name string) {
 	DiscordBotName = sanitizeName(name)
 
 	// Recompute base paths now that we have a proper bot name.
-	ApplicationSupportPath = GetApplicationSupportPath(CurrentGitBranch)
-	ApplicationCachesPath = GetApplicationCachesPath()
-
+	recomputeBasePaths()
 }
 
 // SetAppName sets a configured application name and recomputes base paths.
@@ -82,8 +85,7 @@ func SetAppName(name string) {
 	ConfiguredAppName = sanitizeName(name)
 
 	// Recompute base paths to use configured name.
-	ApplicationSupportPath = GetApplicationSupportPath(CurrentGitBranch)
-	ApplicationCachesPath = GetApplicationCachesPath()
+	recomputeBasePaths()
 }
 
 // SetTheme sets the active theme by name. Empty name resets to default.
@@ -119,29 +121,21 @@ func EffectiveBotName() string {
 }
 
 // GetApplicationSupportPath returns the base path for configuration files using the unified OS rules:
-//   - Linux/Unix:  ~/.config/<AppName>
+//   - Linux/Unix:  $XDG_CONFIG_HOME/<AppName>, default ~/.config/<AppName>
 //   - macOS:       ~/Library/Preferences/<AppName>
 //   - Windows:     %APPDATA%/<AppName>
+//
+// SetDataDir replaces it with the configured data directory.
 func GetApplicationSupportPath(_ string) string {
-	app := EffectiveBotName()
-	if dir := strings.TrimSpace(sys.PlatformConfigDir(app)); dir != "" {
-		return dir
-	}
-	// Last-resort fallback if platform resolution fails unexpectedly.
-	return filepath.Join(".", "config", app)
+	return CurrentDataPaths().Config
 }
 
 // GetApplicationCachesPath returns the base path for cache files using the unified OS rules:
-//   - Linux/Unix:  ~/.cache/<AppName>
+//   - Linux/Unix:  $XDG_CACHE_HOME/<AppName>, default ~/.cache/<AppName>
 //   - macOS:       ~/Library/Caches/<AppName>
 //   - Windows:     %APPDATA%/<AppName>/Cache
 func GetApplicationCachesPath() string {
-	app := EffectiveBotName()
-	if dir := strings.TrimSpace(sys.PlatformCacheDir(app)); dir != "" {
-		return dir
-	}
-	// Last-resort fallback if platform resolution fails unexpectedly.
-	return filepath.Join(".", "cache", app)
+	return CurrentDataPaths().Cache
 }
 
 // Deprecated: MigrationCacheFilePath returns the path to the avatar cache JSON used only for migration.
@@ -161,16 +155,11 @@ func GetCustomRPCFilePath() string {
 }
 
 // GetLogFilePath returns the path to the main log file using the unified OS rules:
-//   - Linux/Unix:  ~/.log/<AppName>/discordcore.log
+//   - Linux/Unix:  $XDG_STATE_HOME/<AppName>/logs/discordcore.log, default ~/.log/<AppName>/discordcore.log
 //   - macOS:       ~/Library/Logs/<AppName>/discordcore.log
 //   - Windows:     %APPDATA%/<AppName>/Logs/discordcore.log
 func GetLogFilePath() string {
-	app := EffectiveBotName()
-	base := strings.TrimSpace(sys.PlatformLogDir(app))
-	if base == "" {
-		base = filepath.Join(".", "logs", app)
-	}
-	return filepath.Join(base, "discordcore.log")
+	return filepath.Join(CurrentDataPaths().Logs, "discordcore.log")
 }
 
 // EnsureCacheDirs creates base cache directories as needed.
@@ -139,6 +139,12 @@ func (mgr *ConfigManager) LoadConfig() error {
 			slog.Bool("order_migrated", orderMigrated),
 			slog.Int("duplicates", dupCount),
 		)
+		if mgr.ReadOnly() {
+			mgr.log().Info("Architectural state bypass: Keeping configuration normalization in memory on a read-only instance",
+				slog.String("path", mgr.ConfigPath()),
+			)
+			return nil
+		}
 		if saveErr := mgr.SaveConfig(); saveErr != nil {
 			errWrap := fmt.Errorf("save configuration after normalization: %w", saveErr)
 			EmitBlockingError(mgr.log(), "Failed to write structural corrections to configuration", errWrap, GenerateRequestID())
@@ -157,6 +163,21 @@ func (mgr *ConfigManager) LoadConfig() error {
 	return nil
 }
 
+// ErrConfigReadOnly is returned by every persisting method once SetReadOnly(true)
+// has been called.
+var ErrConfigReadOnly = errors.New("configuration is read-only on this instance")
+
+// SetReadOnly makes the manager refuse to persist changes. In-memory loads and
+// applies keep working so a standby can follow configuration it does not own.
+func (mgr *ConfigManager) SetReadOnly(readOnly bool) {
+	mgr.readOnly.Store(readOnly)
+}
+
+// ReadOnly reports whether SetReadOnly(true) is in effect.
+func (mgr *ConfigManager) ReadOnly() bool {
+	return mgr != nil && mgr.readOnly.Load()
+}
+
 // SaveConfig persists the active configuration to the filesystem.
 func (mgr *ConfigManager) SaveConfig() error {
 	mgr.mu.Lock()
@@ -172,14 +193,18 @@ func (mgr *ConfigManager) SaveConfig() error {
 }
 
 // SaveGuildConfig updates a specific guild configuration and persists the change immediately.
+// The replacement and the write happen in a single UpdateConfig transaction so that a
+// concurrent writer cannot interleave between them.
 func (mgr *ConfigManager) SaveGuildConfig(cfg GuildConfig) error {
 	mgr.log().Debug("Updating granular guild state",
 		slog.String("guildID", cfg.GuildID),
 	)
-	if err := mgr.AddGuildConfig(cfg); err != nil {
-		return fmt.Errorf("failed to update in-memory configuration: %w", err)
-	}
-	if err := mgr.SaveConfig(); err != nil {
+	if _, err := mgr.UpdateConfig(context.Background(), func(next *BotConfig) error {
+		next.Guilds = append(slices.DeleteFunc(next.Guilds, func(g GuildConfig) bool {
+			return g.GuildID == cfg.GuildID
+		}), cfg)
+		return nil
+	}); err != nil {
 		return fmt.Errorf("failed to persist guild configuration: %w", err)
 	}
 	return nil
@@ -192,6 +217,9 @@ func (mgr *ConfigManager) saveConfigLocked() error {
 	if mgr.store == nil {
 		return fmt.Errorf("config store is not configured")
 	}
+	if mgr.readOnly.Load() {
+		return ErrConfigReadOnly
+	}
 	if validationErr := validateBotConfig(mgr.config); validationErr != nil {
 		return wrapValidationError(validationErr)
 	}
@@ -559,6 +587,11 @@ func (mgr *ConfigManager) RegisterGuildForBot(session *discordgo.Session, guildI
 	}
 
 	if _, err := mgr.UpdateConfig(context.Background(), func(cfg *BotConfig) error {
+		// Re-check under the write lock: a concurrent registration may have landed
+		// while the Discord lookups above were in flight.
+		if slices.ContainsFunc(cfg.Guilds, func(g GuildConfig) bool { return g.GuildID == guildID }) {
+			return nil
+		}
 		cfg.Guilds = append(cfg.Guilds, guildCfg)
 		return nil
 	}); err != nil {
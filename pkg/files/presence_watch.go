@@ -0,0 +1,45 @@
+package files
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MaxPresenceWatchUsers bounds a guild's presence watchlist. Presence
+// updates arrive far more often than any other member event, so the
+// watchlist is meant for a handful of accounts, not for the whole server.
+const MaxPresenceWatchUsers = 25
+
+// PresenceWatchConfig lists the members whose custom status and activity
+// changes are logged. It only takes effect while the guild's
+// presence_watch.user feature is on.
+type PresenceWatchConfig struct {
+	UserIDs []string `json:"user_ids,omitempty"`
+}
+
+// Watches reports whether userID is on the watchlist.
+func (c PresenceWatchConfig) Watches(userID string) bool {
+	return userID != "" && slices.Contains(c.UserIDs, userID)
+}
+
+func validatePresenceWatch(cfg PresenceWatchConfig, guildIndex int) error {
+	if len(cfg.UserIDs) > MaxPresenceWatchUsers {
+		return NewValidationError(fmt.Sprintf("guilds[%d].presence_watch.user_ids", guildIndex), len(cfg.UserIDs),
+			fmt.Sprintf("at most %d users can be watched", MaxPresenceWatchUsers))
+	}
+	for idx, userID := range cfg.UserIDs {
+		field := fmt.Sprintf("guilds[%d].presence_watch.user_ids[%d]", guildIndex, idx)
+		if userID == "" || userID != strings.TrimSpace(userID) || !isAllDigits(userID) {
+			return NewValidationError(field, userID, "user must be a numeric ID")
+		}
+		if slices.Contains(cfg.UserIDs[:idx], userID) {
+			return NewValidationError(field, userID, "user is listed twice")
+		}
+	}
+	return nil
+}
+
+func clonePresenceWatchConfig(in PresenceWatchConfig) PresenceWatchConfig {
+	return PresenceWatchConfig{UserIDs: cloneStringSlice(in.UserIDs)}
+}
@@ -0,0 +1,35 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestValidatePresenceWatch(t *testing.T) {
+	t.Parallel()
+
+	watch := PresenceWatchConfig{UserIDs: []string{"10", "11"}}
+	if err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", PresenceWatch: watch}}}); err != nil {
+		t.Fatalf("valid watchlist rejected: %v", err)
+	}
+	if !watch.Watches("11") || watch.Watches("12") || watch.Watches("") {
+		t.Fatalf("Watches disagrees with %v", watch.UserIDs)
+	}
+
+	tooMany := make([]string, MaxPresenceWatchUsers+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprint(100 + i)
+	}
+	for field, ids := range map[string][]string{
+		"guilds[0].presence_watch.user_ids[0]": {"alice"},
+		"guilds[0].presence_watch.user_ids[1]": {"10", "10"},
+		"guilds[0].presence_watch.user_ids":    tooMany,
+	} {
+		var verr ValidationError
+		err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", PresenceWatch: PresenceWatchConfig{UserIDs: ids}}}})
+		if !errors.As(err, &verr) || verr.Field != field {
+			t.Fatalf("expected validation error on %s for %v, got %v", field, ids, err)
+		}
+	}
+}
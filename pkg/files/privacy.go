@@ -0,0 +1,83 @@
+package files
+
+import "strings"
+
+// PrivacyProfile selects how much member data a guild lets the bot retain.
+type PrivacyProfile string
+
+const (
+	// PrivacyStandard keeps every optional data set. It is the default.
+	PrivacyStandard PrivacyProfile = "standard"
+	// PrivacyHashed keeps everything but message text, which is replaced by
+	// a salted hash and its length: enough to spot repeated messages, not
+	// enough to read them.
+	PrivacyHashed PrivacyProfile = "hashed"
+	// PrivacyMinimal keeps only what moderation needs: message metadata for
+	// delete and edit logs, membership state, cases and warnings.
+	PrivacyMinimal PrivacyProfile = "minimal"
+)
+
+// PrivacyPolicy lists the optional data sets a profile allows.
+type PrivacyPolicy struct {
+	// CacheMessageContent stores message text, so delete and edit logs can
+	// show what was said.
+	CacheMessageContent bool
+	// HashMessageContent stores a salted hash and the length of message text
+	// in place of the text itself. It only applies without
+	// CacheMessageContent.
+	HashMessageContent bool
+	// AvatarHistory stores avatar hashes and the names members go by, so
+	// avatar and name changes can be logged.
+	AvatarHistory bool
+	// ActivityMetrics stores daily message and join/leave counts.
+	ActivityMetrics bool
+}
+
+// Valid reports whether p is a known profile. The empty profile is the
+// default and valid.
+func (p PrivacyProfile) Valid() bool {
+	switch p.normalized() {
+	case PrivacyStandard, PrivacyHashed, PrivacyMinimal:
+		return true
+	default:
+		return false
+	}
+}
+
+// Policy resolves p into the data sets it allows. Unknown profiles resolve to
+// the standard policy; validation rejects them before they are saved.
+func (p PrivacyProfile) Policy() PrivacyPolicy {
+	switch p.normalized() {
+	case PrivacyMinimal:
+		return PrivacyPolicy{}
+	case PrivacyHashed:
+		return PrivacyPolicy{
+			HashMessageContent: true,
+			AvatarHistory:      true,
+			ActivityMetrics:    true,
+		}
+	}
+	return PrivacyPolicy{
+		CacheMessageContent: true,
+		AvatarHistory:       true,
+		ActivityMetrics:     true,
+	}
+}
+
+func (p PrivacyProfile) normalized() PrivacyProfile {
+	normalized := PrivacyProfile(strings.ToLower(strings.TrimSpace(string(p))))
+	if normalized == "" {
+		return PrivacyStandard
+	}
+	return normalized
+}
+
+// GuildPrivacy returns the privacy policy for guildID. Guilds without a
+// config get the standard policy.
+func (mgr *ConfigManager) GuildPrivacy(guildID string) PrivacyPolicy {
+	gcfg := mgr.GuildConfig(guildID)
+	if gcfg == nil {
+		return PrivacyStandard.Policy()
+	}
+	return gcfg.Privacy.Policy()
+}
@@ -0,0 +1,63 @@
+package files
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPrivacyProfilePolicy(t *testing.T) {
+	t.Parallel()
+
+	full := PrivacyPolicy{CacheMessageContent: true, AvatarHistory: true, ActivityMetrics: true}
+	cases := []struct {
+		profile PrivacyProfile
+		valid   bool
+		want    PrivacyPolicy
+	}{
+		{"", true, full},
+		{PrivacyStandard, true, full},
+		{" Minimal ", true, PrivacyPolicy{}},
+		{PrivacyHashed, true, PrivacyPolicy{HashMessageContent: true, AvatarHistory: true, ActivityMetrics: true}},
+		{"strict", false, full},
+	}
+	for _, tc := range cases {
+		if got := tc.profile.Valid(); got != tc.valid {
+			t.Errorf("%q.Valid() = %v, want %v", tc.profile, got, tc.valid)
+		}
+		if got := tc.profile.Policy(); got != tc.want {
+			t.Errorf("%q.Policy() = %+v, want %+v", tc.profile, got, tc.want)
+		}
+	}
+}
+
+func TestValidateBotConfigRejectsUnknownPrivacyProfile(t *testing.T) {
+	t.Parallel()
+
+	err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", Privacy: "strict"}}})
+	var verr ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if verr.Field != "guilds[0].privacy" {
+		t.Fatalf("unexpected field %q", verr.Field)
+	}
+}
+
+func TestGuildPrivacy(t *testing.T) {
+	t.Parallel()
+
+	mgr, _ := newTransactionalTestManager(t, &BotConfig{Guilds: []GuildConfig{
+		{GuildID: "g1", Privacy: PrivacyMinimal},
+		{GuildID: "g2"},
+	}}, nil)
+
+	if got := mgr.GuildPrivacy("g1"); got != (PrivacyPolicy{}) {
+		t.Fatalf("minimal guild policy = %+v", got)
+	}
+	if got := mgr.GuildPrivacy("g2"); !got.CacheMessageContent || !got.AvatarHistory || !got.ActivityMetrics {
+		t.Fatalf("standard guild policy = %+v", got)
+	}
+	if got := mgr.GuildPrivacy("unknown"); !got.CacheMessageContent {
+		t.Fatalf("unconfigured guild policy = %+v", got)
+	}
+}
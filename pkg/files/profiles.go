@@ -0,0 +1,146 @@
+package files
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxProfileDepth bounds Extends chains so a malformed config cannot make
+// resolution walk indefinitely even if validation was bypassed.
+const maxProfileDepth = 8
+
+// ConfigProfile is a named template of feature toggles and runtime overrides
+// (e.g. "strict-moderation", "minimal-logging") that guilds opt into through
+// GuildConfig.Profile. A profile may extend another profile; the nearer
+// profile wins for every field it sets.
+//
+// Precedence, strongest first: guild, guild profile chain, global, default.
+type ConfigProfile struct {
+	Name          string         `json:"name"`
+	Extends       string         `json:"extends,omitempty"`
+	Description   string         `json:"description,omitempty"`
+	Features      FeatureToggles `json:"features,omitempty"`
+	RuntimeConfig RuntimeConfig  `json:"runtime_config,omitempty"`
+}
+
+// FindProfile returns the profile registered under name. Names are compared
+// case-insensitively after trimming.
+func (cfg *BotConfig) FindProfile(name string) (ConfigProfile, bool) {
+	name = strings.TrimSpace(name)
+	if cfg == nil || name == "" {
+		return ConfigProfile{}, false
+	}
+	for _, profile := range cfg.Profiles {
+		if strings.EqualFold(strings.TrimSpace(profile.Name), name) {
+			return profile, true
+		}
+	}
+	return ConfigProfile{}, false
+}
+
+// profileChain returns the profile named by name followed by its Extends
+// ancestors, nearest first. Unknown names end the chain; cycles are cut at the
+// first repeated profile.
+func (cfg *BotConfig) profileChain(name string) []ConfigProfile {
+	var chain []ConfigProfile
+	seen := make(map[string]bool)
+	for len(chain) < maxProfileDepth {
+		profile, ok := cfg.FindProfile(name)
+		if !ok {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(profile.Name))
+		if seen[key] {
+			break
+		}
+		seen[key] = true
+		chain = append(chain, profile)
+		name = profile.Extends
+	}
+	return chain
+}
+
+// guildProfileChain resolves the inherited profiles for guildID, nearest first.
+func (cfg *BotConfig) guildProfileChain(guildID string) []ConfigProfile {
+	if cfg == nil || guildID == "" {
+		return nil
+	}
+	for _, g := range cfg.Guilds {
+		if g.GuildID == guildID {
+			return cfg.profileChain(g.Profile)
+		}
+	}
+	return nil
+}
+
+func cloneConfigProfiles(in []ConfigProfile) []ConfigProfile {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]ConfigProfile, 0, len(in))
+	for _, profile := range in {
+		out = append(out, ConfigProfile{
+			Name:          profile.Name,
+			Extends:       profile.Extends,
+			Description:   profile.Description,
+			Features:      cloneFeatureToggles(profile.Features),
+			RuntimeConfig: cloneRuntimeConfig(profile.RuntimeConfig),
+		})
+	}
+	return out
+}
+
+// validateConfigProfiles rejects duplicate or empty profile names, dangling
+// Extends/Profile references, and inheritance cycles.
+func validateConfigProfiles(cfg *BotConfig) error {
+	names := make(map[string]int, len(cfg.Profiles))
+	for idx, profile := range cfg.Profiles {
+		key := strings.ToLower(strings.TrimSpace(profile.Name))
+		field := fmt.Sprintf("profiles[%d].name", idx)
+		if key == "" {
+			return NewValidationError(field, profile.Name, "profile name is required")
+		}
+		if prev, dup := names[key]; dup {
+			return NewValidationError(field, profile.Name, fmt.Sprintf("duplicates profiles[%d].name", prev))
+		}
+		names[key] = idx
+	}
+
+	for idx, profile := range cfg.Profiles {
+		if extends := strings.TrimSpace(profile.Extends); extends != "" {
+			if _, ok := names[strings.ToLower(extends)]; !ok {
+				return NewValidationError(fmt.Sprintf("profiles[%d].extends", idx), profile.Extends, "extends an unknown profile")
+			}
+		}
+
+		seen := map[string]bool{}
+		for current := profile; ; {
+			key := strings.ToLower(strings.TrimSpace(current.Name))
+			if seen[key] {
+				return NewValidationError(fmt.Sprintf("profiles[%d].extends", idx), profile.Extends, "profile inheritance forms a cycle")
+			}
+			seen[key] = true
+			if len(seen) > maxProfileDepth {
+				return NewValidationError(
+					fmt.Sprintf("profiles[%d].extends", idx),
+					profile.Extends,
+					fmt.Sprintf("profile inheritance exceeds %d levels", maxProfileDepth),
+				)
+			}
+			next, ok := cfg.FindProfile(current.Extends)
+			if !ok {
+				break
+			}
+			current = next
+		}
+	}
+
+	for idx, guild := range cfg.Guilds {
+		if name := strings.TrimSpace(guild.Profile); name != "" {
+			if _, ok := names[strings.ToLower(name)]; !ok {
+				return NewValidationError(fmt.Sprintf("guilds[%d].profile", idx), guild.Profile, "references an unknown profile")
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package files
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolveFeaturesInheritsProfileChain(t *testing.T) {
+	t.Parallel()
+
+	cfg := &BotConfig{
+		Features: FeatureToggles{Moderation: FeatureModerationToggles{Kick: boolPtr(true)}},
+		Profiles: []ConfigProfile{
+			{
+				Name:     "strict-moderation",
+				Features: FeatureToggles{Moderation: FeatureModerationToggles{Ban: boolPtr(false), Kick: boolPtr(false)}},
+			},
+			{
+				Name:     "minimal-logging",
+				Extends:  "Strict-Moderation",
+				Features: FeatureToggles{Moderation: FeatureModerationToggles{Ban: boolPtr(true)}},
+			},
+		},
+		Guilds: []GuildConfig{
+			{GuildID: "g-profile", Profile: "minimal-logging"},
+			{
+				GuildID:  "g-override",
+				Profile:  "minimal-logging",
+				Features: FeatureToggles{Moderation: FeatureModerationToggles{Kick: boolPtr(true)}},
+			},
+			{GuildID: "g-plain"},
+		},
+	}
+
+	inherited := cfg.ResolveFeatures("g-profile")
+	if !inherited.Moderation.Ban {
+		t.Fatal("expected nearest profile to win over its parent for ban")
+	}
+	if inherited.Moderation.Kick {
+		t.Fatal("expected parent profile kick=false to beat the global toggle")
+	}
+
+	if overridden := cfg.ResolveFeatures("g-override"); !overridden.Moderation.Kick {
+		t.Fatal("expected guild toggle to beat inherited profiles")
+	}
+	if plain := cfg.ResolveFeatures("g-plain"); !plain.Moderation.Kick || !plain.Moderation.Ban {
+		t.Fatalf("expected guild without profile to use global/defaults, got %+v", plain.Moderation)
+	}
+}
+
+func TestResolveRuntimeConfigInheritsProfileChain(t *testing.T) {
+	t.Parallel()
+
+	cfg := &BotConfig{
+		RuntimeConfig: RuntimeConfig{BotTheme: "global", MessageCacheTTLHours: 1},
+		Profiles: []ConfigProfile{
+			{Name: "base", RuntimeConfig: RuntimeConfig{BotTheme: "base", DisableMessageLogs: true, BackfillInitialDate: "2024-01-01"}},
+			{Name: "fleet", Extends: "base", RuntimeConfig: RuntimeConfig{MessageCacheTTLHours: 6}},
+		},
+		Guilds: []GuildConfig{{
+			GuildID:       "g1",
+			Profile:       "fleet",
+			RuntimeConfig: RuntimeConfig{BotTheme: "guild"},
+		}},
+	}
+
+	resolved := cfg.ResolveRuntimeConfig("g1")
+	if resolved.BotTheme != "guild" {
+		t.Fatalf("expected guild theme to win, got %q", resolved.BotTheme)
+	}
+	if resolved.MessageCacheTTLHours != 6 {
+		t.Fatalf("expected profile ttl 6, got %d", resolved.MessageCacheTTLHours)
+	}
+	if !resolved.DisableMessageLogs {
+		t.Fatal("expected parent profile to disable message logs")
+	}
+	if resolved.BackfillInitialDate != "" {
+		t.Fatalf("expected guild-only backfill date to ignore profiles, got %q", resolved.BackfillInitialDate)
+	}
+}
+
+func TestValidateConfigProfiles(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]*BotConfig{
+		"empty name": {Profiles: []ConfigProfile{{Name: " "}}},
+		"duplicate":  {Profiles: []ConfigProfile{{Name: "a"}, {Name: "A"}}},
+		"dangling":   {Profiles: []ConfigProfile{{Name: "a", Extends: "missing"}}},
+		"cycle":      {Profiles: []ConfigProfile{{Name: "a", Extends: "b"}, {Name: "b", Extends: "a"}}},
+		"guild ref":  {Guilds: []GuildConfig{{GuildID: "g1", Profile: "missing"}}},
+	}
+	for name, cfg := range cases {
+		var validationErr ValidationError
+		if err := validateBotConfig(cfg); !errors.As(err, &validationErr) {
+			t.Fatalf("%s: expected validation error, got %v", name, err)
+		}
+	}
+
+	valid := &BotConfig{
+		Profiles: []ConfigProfile{{Name: "a"}, {Name: "b", Extends: "a"}},
+		Guilds:   []GuildConfig{{GuildID: "g1", Profile: "b"}},
+	}
+	if err := validateBotConfig(valid); err != nil {
+		t.Fatalf("expected valid profiles to pass, got %v", err)
+	}
+}
+
+func TestCloneBotConfigPreservesProfiles(t *testing.T) {
+	t.Parallel()
+
+	in := &BotConfig{
+		Profiles: []ConfigProfile{{
+			Name:     "strict-moderation",
+			Features: FeatureToggles{Moderation: FeatureModerationToggles{Ban: boolPtr(false)}},
+		}},
+		Guilds: []GuildConfig{{GuildID: "g1", Profile: "strict-moderation"}},
+	}
+
+	out := CloneBotConfigPtr(in)
+	if len(out.Profiles) != 1 || out.Guilds[0].Profile != "strict-moderation" {
+		t.Fatalf("expected profiles to survive cloning, got %+v", out)
+	}
+	*out.Profiles[0].Features.Moderation.Ban = true
+	if *in.Profiles[0].Features.Moderation.Ban {
+		t.Fatal("expected cloned profile toggles to be independent of the source")
+	}
+}
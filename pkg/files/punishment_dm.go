@@ -0,0 +1,14 @@
+package files
+
+// maxAppealInstructionsLength is the Discord limit on an embed field value,
+// where the appeal instructions are shown.
+const maxAppealInstructionsLength = 1024
+
+// PunishmentDMConfig controls the direct message members receive before a
+// ban, kick or timeout is applied to them.
+type PunishmentDMConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// AppealInstructions tells members how to appeal. Empty leaves the
+	// appeal section out of the message.
+	AppealInstructions string `json:"appeal_instructions,omitempty"`
+}
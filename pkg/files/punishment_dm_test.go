@@ -0,0 +1,23 @@
+package files
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateBotConfigRejectsLongAppealInstructions(t *testing.T) {
+	t.Parallel()
+
+	guild := GuildConfig{GuildID: "g1", PunishmentDM: PunishmentDMConfig{Enabled: true, AppealInstructions: strings.Repeat("é", maxAppealInstructionsLength)}}
+	if err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{guild}}); err != nil {
+		t.Fatalf("instructions at the limit must be accepted: %v", err)
+	}
+
+	guild.PunishmentDM.AppealInstructions += "x"
+	err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{guild}})
+	var verr ValidationError
+	if !errors.As(err, &verr) || verr.Field != "guilds[0].punishment_dm.appeal_instructions" {
+		t.Fatalf("expected punishment_dm.appeal_instructions validation error, got %v", err)
+	}
+}
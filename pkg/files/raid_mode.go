@@ -0,0 +1,52 @@
+package files
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultRaidModeMinutes is how long raid mode lasts when neither the
+	// command nor the guild sets a duration.
+	DefaultRaidModeMinutes = 60
+	// MaxRaidModeMinutes bounds raid mode to one week.
+	MaxRaidModeMinutes = 7 * 24 * 60
+)
+
+// RaidModeConfig holds the defaults /raidmode on uses when the moderator
+// leaves them out.
+type RaidModeConfig struct {
+	// DurationMinutes is how long raid mode stays on before it lifts itself.
+	// Zero uses DefaultRaidModeMinutes.
+	DurationMinutes int `json:"duration_minutes,omitempty"`
+	// MinAccountAgeDays kicks accounts younger than this many days that join
+	// during raid mode. Zero kicks nobody. Setting it also has the bot ask
+	// for member join events, which kicking relies on.
+	MinAccountAgeDays int `json:"min_account_age_days,omitempty"`
+}
+
+// Duration returns how long raid mode lasts.
+func (c RaidModeConfig) Duration() time.Duration {
+	if c.DurationMinutes <= 0 {
+		return DefaultRaidModeMinutes * time.Minute
+	}
+	return time.Duration(c.DurationMinutes) * time.Minute
+}
+
+// MinAccountAge returns the youngest account allowed to join during raid
+// mode.
+func (c RaidModeConfig) MinAccountAge() time.Duration {
+	return time.Duration(c.MinAccountAgeDays) * 24 * time.Hour
+}
+
+func validateRaidMode(cfg RaidModeConfig, guildIndex int) error {
+	if cfg.DurationMinutes < 0 || cfg.DurationMinutes > MaxRaidModeMinutes {
+		return NewValidationError(fmt.Sprintf("guilds[%d].raid_mode.duration_minutes", guildIndex), cfg.DurationMinutes,
+			fmt.Sprintf("duration must be between 0 and %d minutes", MaxRaidModeMinutes))
+	}
+	if cfg.MinAccountAgeDays < 0 {
+		return NewValidationError(fmt.Sprintf("guilds[%d].raid_mode.min_account_age_days", guildIndex), cfg.MinAccountAgeDays,
+			"minimum account age must not be negative")
+	}
+	return nil
+}
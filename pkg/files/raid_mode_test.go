@@ -0,0 +1,30 @@
+package files
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRaidModeConfig(t *testing.T) {
+	t.Parallel()
+
+	if got := (RaidModeConfig{}).Duration(); got != time.Hour {
+		t.Fatalf("default duration = %v, want 1h", got)
+	}
+	cfg := RaidModeConfig{DurationMinutes: 30, MinAccountAgeDays: 3}
+	if cfg.Duration() != 30*time.Minute || cfg.MinAccountAge() != 72*time.Hour {
+		t.Fatalf("unexpected durations for %+v", cfg)
+	}
+
+	for field, raid := range map[string]RaidModeConfig{
+		"guilds[0].raid_mode.duration_minutes":     {DurationMinutes: MaxRaidModeMinutes + 1},
+		"guilds[0].raid_mode.min_account_age_days": {MinAccountAgeDays: -1},
+	} {
+		var verr ValidationError
+		err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", RaidMode: raid}}})
+		if !errors.As(err, &verr) || verr.Field != field {
+			t.Fatalf("expected validation error on %s, got %v", field, err)
+		}
+	}
+}
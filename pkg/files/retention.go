@@ -0,0 +1,105 @@
+package files
+
+import (
+	"fmt"
+	"time"
+)
+
+// RetentionData names a data set the retention policy prunes.
+type RetentionData string
+
+const (
+	// RetentionMessageCache covers cached messages, their edit history and
+	// the messages /clean logged.
+	RetentionMessageCache RetentionData = "message_cache"
+	// RetentionAvatarHistory covers the recorded avatar changes of members
+	// and the names stored for members not seen changing since.
+	RetentionAvatarHistory RetentionData = "avatar_history"
+	// RetentionLogEvents covers the archive /logs search reads.
+	RetentionLogEvents RetentionData = "log_events"
+)
+
+// MaxRetentionDays bounds how long a guild may keep any data set.
+const MaxRetentionDays = 3650
+
+// defaultRetentionDays is how long each data set is kept when the guild sets
+// nothing.
+var defaultRetentionDays = map[RetentionData]int{
+	RetentionMessageCache:  7,
+	RetentionAvatarHistory: 90,
+	RetentionLogEvents:     180,
+}
+
+// RetentionDataSets lists the data sets a retention policy covers.
+func RetentionDataSets() []RetentionData {
+	return []RetentionData{RetentionMessageCache, RetentionAvatarHistory, RetentionLogEvents}
+}
+
+// RetentionConfig sets how many days a guild keeps each data set. Zero keeps
+// the default.
+type RetentionConfig struct {
+	MessageCacheDays  int `json:"message_cache_days,omitempty"`
+	AvatarHistoryDays int `json:"avatar_history_days,omitempty"`
+	LogEventsDays     int `json:"log_events_days,omitempty"`
+}
+
+// DefaultRetentionDays returns how long data is kept when the guild sets
+// nothing, or 0 for an unknown data set.
+func DefaultRetentionDays(data RetentionData) int {
+	return defaultRetentionDays[data]
+}
+
+// Days returns how many days data is kept, the default when unset.
+func (c RetentionConfig) Days(data RetentionData) int {
+	if days := c.configured(data); days > 0 {
+		return days
+	}
+	return defaultRetentionDays[data]
+}
+
+// Duration returns how long data is kept.
+func (c RetentionConfig) Duration(data RetentionData) time.Duration {
+	return time.Duration(c.Days(data)) * 24 * time.Hour
+}
+
+// Overrides reports whether data is kept for other than the default.
+func (c RetentionConfig) Overrides(data RetentionData) bool {
+	return c.Days(data) != defaultRetentionDays[data]
+}
+
+// SetDays keeps data for days. Zero goes back to the default.
+func (c *RetentionConfig) SetDays(data RetentionData, days int) {
+	switch data {
+	case RetentionMessageCache:
+		c.MessageCacheDays = days
+	case RetentionAvatarHistory:
+		c.AvatarHistoryDays = days
+	case RetentionLogEvents:
+		c.LogEventsDays = days
+	}
+}
+
+func (c RetentionConfig) configured(data RetentionData) int {
+	switch data {
+	case RetentionMessageCache:
+		return c.MessageCacheDays
+	case RetentionAvatarHistory:
+		return c.AvatarHistoryDays
+	case RetentionLogEvents:
+		return c.LogEventsDays
+	}
+	return 0
+}
+
+func validateRetention(cfg RetentionConfig, guildIndex int) error {
+	for _, data := range RetentionDataSets() {
+		if days := cfg.configured(data); days < 0 || days > MaxRetentionDays {
+			return NewValidationError(
+				fmt.Sprintf("guilds[%d].retention.%s_days", guildIndex, data),
+				days,
+				fmt.Sprintf("retention must be between 0 (default) and %d days", MaxRetentionDays),
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package files
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetentionConfigDays(t *testing.T) {
+	t.Parallel()
+
+	var cfg RetentionConfig
+	if got := cfg.Days(RetentionMessageCache); got != 7 {
+		t.Fatalf("expected the 7 day message cache default, got %d", got)
+	}
+	cfg.SetDays(RetentionLogEvents, 30)
+	if cfg.LogEventsDays != 30 || !cfg.Overrides(RetentionLogEvents) {
+		t.Fatalf("expected log events kept for 30 days, got %+v", cfg)
+	}
+	if got := cfg.Duration(RetentionLogEvents); got != 30*24*time.Hour {
+		t.Fatalf("unexpected log events duration %v", got)
+	}
+	cfg.SetDays(RetentionLogEvents, 0)
+	if cfg.Days(RetentionLogEvents) != DefaultRetentionDays(RetentionLogEvents) || cfg.Overrides(RetentionLogEvents) {
+		t.Fatalf("expected 0 to restore the default, got %+v", cfg)
+	}
+}
+
+func TestValidateBotConfigRejectsInvalidRetention(t *testing.T) {
+	t.Parallel()
+
+	for _, retention := range []RetentionConfig{{AvatarHistoryDays: -1}, {LogEventsDays: MaxRetentionDays + 1}} {
+		cfg := &BotConfig{Guilds: []GuildConfig{{GuildID: "g1", Retention: retention}}}
+		var verr ValidationError
+		if err := validateBotConfig(cfg); !errors.As(err, &verr) || verr.Field == "" {
+			t.Fatalf("expected a retention validation error for %+v, got %v", retention, err)
+		}
+	}
+
+	cfg := &BotConfig{Guilds: []GuildConfig{{GuildID: "g1", Retention: RetentionConfig{MessageCacheDays: 1, LogEventsDays: MaxRetentionDays}}}}
+	if err := validateBotConfig(cfg); err != nil {
+		t.Fatalf("valid retention rejected: %v", err)
+	}
+}
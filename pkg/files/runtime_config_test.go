@@ -308,12 +308,19 @@ func TestResolveRuntimeConfigAdoptsEveryGuildField(t *testing.T) {
 	// Fields not covered by the default "non-zero guild sentinel is adopted" rule;
 	// each has a dedicated assertion below.
 	exceptions := map[string]string{
-		"ModerationLogging":    "*bool with normalization (global defaults to non-nil)",
-		"BackfillInitialDate":  "GuildOnly: adopts the guild value even when zero, no global fallback",
-		"WebhookEmbedUpdates":  "slice merged via NormalizedWebhookEmbedUpdates (empty entries filtered)",
-		"PastebinDevKey":       "global-only credential, intentionally not per-guild overridable",
-		"PastebinUserName":     "global-only credential, intentionally not per-guild overridable",
-		"PastebinUserPassword": "global-only credential, intentionally not per-guild overridable",
+		"ModerationLogging":          "*bool with normalization (global defaults to non-nil)",
+		"BackfillInitialDate":        "GuildOnly: adopts the guild value even when zero, no global fallback",
+		"WebhookEmbedUpdates":        "slice merged via NormalizedWebhookEmbedUpdates (empty entries filtered)",
+		"PastebinDevKey":             "global-only credential, intentionally not per-guild overridable",
+		"PastebinUserName":           "global-only credential, intentionally not per-guild overridable",
+		"PastebinUserPassword":       "global-only credential, intentionally not per-guild overridable",
+		"GatewayWatchdogIdleMinutes": "global-only process setting, read once per bot runtime",
+		"AuditLogPollMinutes":        "global-only process setting, read once per bot runtime",
+		"CommandDevGuildID":          "global-only process setting, read when commands are registered",
+		"CommandScope":               "global-only process setting, read when commands are registered",
+		"OwnerAlertChannelID":        "global-only operator destination, not tied to any guild",
+		"StartupReportToOwner":       "global-only operator setting, read once per bot runtime start",
+		"BotLockdown":                "global-only kill switch toggled by /admin lockdown-bot",
 	}
 
 	recurse := map[reflect.Type]bool{
@@ -431,6 +438,76 @@ func TestResolveRuntimeConfigAdoptsEveryGuildField(t *testing.T) {
 				resolved.PastebinDevKey, resolved.PastebinUserName, resolved.PastebinUserPassword)
 		}
 	})
+
+	t.Run("GatewayWatchdogGlobalOnly", func(t *testing.T) {
+		cfg := &BotConfig{
+			RuntimeConfig: RuntimeConfig{GatewayWatchdogIdleMinutes: 5},
+			Guilds: []GuildConfig{{
+				GuildID:       testGuildID,
+				RuntimeConfig: RuntimeConfig{GatewayWatchdogIdleMinutes: 1},
+			}},
+		}
+		if got := cfg.ResolveRuntimeConfig(testGuildID).GatewayWatchdogIdleMinutes; got != 5 {
+			t.Fatalf("expected gateway watchdog idle minutes to remain global-only, got %d", got)
+		}
+	})
+
+	t.Run("AuditLogPollGlobalOnly", func(t *testing.T) {
+		cfg := &BotConfig{
+			RuntimeConfig: RuntimeConfig{AuditLogPollMinutes: 10},
+			Guilds: []GuildConfig{{
+				GuildID:       testGuildID,
+				RuntimeConfig: RuntimeConfig{AuditLogPollMinutes: 1},
+			}},
+		}
+		if got := cfg.ResolveRuntimeConfig(testGuildID).AuditLogPollMinutes; got != 10 {
+			t.Fatalf("expected audit log poll minutes to remain global-only, got %d", got)
+		}
+	})
+
+	t.Run("CommandRegistrationGlobalOnly", func(t *testing.T) {
+		cfg := &BotConfig{
+			RuntimeConfig: RuntimeConfig{CommandDevGuildID: "100", CommandScope: "dev_guild"},
+			Guilds: []GuildConfig{{
+				GuildID:       testGuildID,
+				RuntimeConfig: RuntimeConfig{CommandDevGuildID: "200", CommandScope: "guild"},
+			}},
+		}
+		resolved := cfg.ResolveRuntimeConfig(testGuildID)
+		if resolved.CommandDevGuildID != "100" || resolved.CommandScope != "dev_guild" {
+			t.Fatalf("expected command registration settings to remain global-only, got dev=%q scope=%q",
+				resolved.CommandDevGuildID, resolved.CommandScope)
+		}
+	})
+
+	t.Run("OwnerAlertChannelGlobalOnly", func(t *testing.T) {
+		cfg := &BotConfig{
+			RuntimeConfig: RuntimeConfig{OwnerAlertChannelID: "100"},
+			Guilds: []GuildConfig{{
+				GuildID:       testGuildID,
+				RuntimeConfig: RuntimeConfig{OwnerAlertChannelID: "200"},
+			}},
+		}
+		if got := cfg.ResolveRuntimeConfig(testGuildID).OwnerAlertChannelID; got != "100" {
+			t.Fatalf("expected owner alert channel to remain global-only, got %q", got)
+		}
+	})
+
+	t.Run("BotLockdownGlobalOnly", func(t *testing.T) {
+		cfg := &BotConfig{
+			Guilds: []GuildConfig{{
+				GuildID:       testGuildID,
+				RuntimeConfig: RuntimeConfig{BotLockdown: true},
+			}},
+		}
+		if cfg.ResolveRuntimeConfig(testGuildID).BotLockdown || cfg.LockdownActive() {
+			t.Fatal("expected a guild override not to engage the bot lockdown")
+		}
+		cfg.RuntimeConfig.BotLockdown = true
+		if !cfg.LockdownActive() {
+			t.Fatal("expected the global flag to engage the bot lockdown")
+		}
+	})
 }
 
 const (
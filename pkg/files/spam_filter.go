@@ -0,0 +1,60 @@
+package files
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SpamFilterConfig sets how many mentions and emojis one message may carry
+// before the bot deletes it as spam. A zero limit turns that check off.
+// TimeoutMinutes, when set, also times the author out.
+type SpamFilterConfig struct {
+	MaxMentions      int      `json:"max_mentions,omitempty"`
+	MaxEmojis        int      `json:"max_emojis,omitempty"`
+	TimeoutMinutes   int      `json:"timeout_minutes,omitempty"`
+	ExemptRoleIDs    []string `json:"exempt_role_ids,omitempty"`
+	ExemptChannelIDs []string `json:"exempt_channel_ids,omitempty"`
+}
+
+// Enabled reports whether any limit is set.
+func (c SpamFilterConfig) Enabled() bool {
+	return c.MaxMentions > 0 || c.MaxEmojis > 0
+}
+
+// Exempts reports whether a message in channelID, under categoryID, by a
+// member holding roleIDs is left alone by the filter.
+func (c SpamFilterConfig) Exempts(channelID, categoryID string, roleIDs []string) bool {
+	return AutomodRule{ExemptRoleIDs: c.ExemptRoleIDs, ExemptChannelIDs: c.ExemptChannelIDs}.Exempts(channelID, categoryID, roleIDs)
+}
+
+func validateSpamFilter(cfg SpamFilterConfig, guildIndex int) error {
+	field := func(name string) string { return fmt.Sprintf("guilds[%d].spam_filter.%s", guildIndex, name) }
+	if cfg.MaxMentions < 0 {
+		return NewValidationError(field("max_mentions"), cfg.MaxMentions, "max_mentions must not be negative")
+	}
+	if cfg.MaxEmojis < 0 {
+		return NewValidationError(field("max_emojis"), cfg.MaxEmojis, "max_emojis must not be negative")
+	}
+	if cfg.TimeoutMinutes < 0 || cfg.TimeoutMinutes > MaxAutomodTimeoutMinutes {
+		return NewValidationError(field("timeout_minutes"), cfg.TimeoutMinutes,
+			fmt.Sprintf("timeout_minutes must be between 0 and %d", MaxAutomodTimeoutMinutes))
+	}
+	for idx, roleID := range cfg.ExemptRoleIDs {
+		if !isAllDigits(strings.TrimSpace(roleID)) {
+			return NewValidationError(fmt.Sprintf("%s[%d]", field("exempt_role_ids"), idx), roleID, "role must be a numeric ID")
+		}
+	}
+	for idx, channelID := range cfg.ExemptChannelIDs {
+		if !isAllDigits(strings.TrimSpace(channelID)) {
+			return NewValidationError(fmt.Sprintf("%s[%d]", field("exempt_channel_ids"), idx), channelID, "channel must be a numeric ID")
+		}
+	}
+	return nil
+}
+
+func cloneSpamFilterConfig(in SpamFilterConfig) SpamFilterConfig {
+	out := in
+	out.ExemptRoleIDs = cloneStringSlice(in.ExemptRoleIDs)
+	out.ExemptChannelIDs = cloneStringSlice(in.ExemptChannelIDs)
+	return out
+}
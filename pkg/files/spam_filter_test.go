@@ -0,0 +1,30 @@
+package files
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateSpamFilter(t *testing.T) {
+	t.Parallel()
+
+	valid := SpamFilterConfig{MaxMentions: 5, TimeoutMinutes: 10, ExemptRoleIDs: []string{"1"}}
+	if err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", SpamFilter: valid}}}); err != nil {
+		t.Fatalf("valid spam filter rejected: %v", err)
+	}
+	if gc := (GuildConfig{SpamFilter: valid}); !gc.ModeratesContent() {
+		t.Fatal("a spam filter limit should make the bot moderate content")
+	}
+
+	for field, filter := range map[string]SpamFilterConfig{
+		"guilds[0].spam_filter.max_emojis":            {MaxEmojis: -1},
+		"guilds[0].spam_filter.timeout_minutes":       {MaxMentions: 5, TimeoutMinutes: MaxAutomodTimeoutMinutes + 1},
+		"guilds[0].spam_filter.exempt_channel_ids[0]": {MaxMentions: 5, ExemptChannelIDs: []string{"#general"}},
+	} {
+		var verr ValidationError
+		err := validateBotConfig(&BotConfig{Guilds: []GuildConfig{{GuildID: "g1", SpamFilter: filter}}})
+		if !errors.As(err, &verr) || verr.Field != field {
+			t.Fatalf("expected validation error on %s, got %v", field, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrUnknownBotInstance indicates a token rotation targeted an instance that
+	// holds no token in any guild.
+	ErrUnknownBotInstance = errors.New("bot instance has no configured token")
+	// ErrInvalidBotToken indicates a staged token is empty or malformed.
+	ErrInvalidBotToken = errors.New("bot token is empty or malformed")
+)
+
+// StagePendingBotToken records token as the replacement for botInstanceID's
+// current token. The active token keeps serving traffic until the pending one
+// is validated and promoted, so a typo never takes the instance offline.
+func (mgr *ConfigManager) StagePendingBotToken(ctx context.Context, botInstanceID, token string) error {
+	botInstanceID = NormalizeBotInstanceID(botInstanceID)
+	token = strings.TrimSpace(token)
+	if botInstanceID == "" {
+		return fmt.Errorf("StagePendingBotToken: %w", ErrUnknownBotInstance)
+	}
+	// Discord bot tokens are three dot-separated segments.
+	if strings.Count(token, ".") != 2 {
+		return fmt.Errorf("StagePendingBotToken: %w", ErrInvalidBotToken)
+	}
+
+	_, err := mgr.UpdateConfig(ctx, func(cfg *BotConfig) error {
+		if !hasBotInstanceToken(cfg, botInstanceID) {
+			return ErrUnknownBotInstance
+		}
+		if cfg.PendingBotTokens == nil {
+			cfg.PendingBotTokens = make(map[string]EncryptedString)
+		}
+		cfg.PendingBotTokens[botInstanceID] = EncryptedString(token)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("StagePendingBotToken: %w", err)
+	}
+	return nil
+}
+
+// PromotePendingBotToken swaps the staged token into every guild bound to
+// botInstanceID and clears the pending entry in a single transaction. It
+// reports false without error when the pending token no longer matches
+// expected, which means a newer rotation superseded the one being applied.
+func (mgr *ConfigManager) PromotePendingBotToken(ctx context.Context, botInstanceID, expected string) (bool, error) {
+	botInstanceID = NormalizeBotInstanceID(botInstanceID)
+	promoted := false
+
+	_, err := mgr.UpdateConfig(ctx, func(cfg *BotConfig) error {
+		pending, ok := cfg.PendingBotTokens[botInstanceID]
+		if !ok || string(pending) != expected {
+			return nil
+		}
+		for i := range cfg.Guilds {
+			guild := &cfg.Guilds[i]
+			if current, bound := guild.BotInstanceTokens[botInstanceID]; bound && current != "" {
+				guild.BotInstanceTokens[botInstanceID] = pending
+			}
+		}
+		delete(cfg.PendingBotTokens, botInstanceID)
+		promoted = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("PromotePendingBotToken: %w", err)
+	}
+	return promoted, nil
+}
+
+// DiscardPendingBotToken drops a staged token, typically after it failed
+// validation, provided it still matches expected.
+func (mgr *ConfigManager) DiscardPendingBotToken(ctx context.Context, botInstanceID, expected string) error {
+	botInstanceID = NormalizeBotInstanceID(botInstanceID)
+
+	_, err := mgr.UpdateConfig(ctx, func(cfg *BotConfig) error {
+		if pending, ok := cfg.PendingBotTokens[botInstanceID]; ok && string(pending) == expected {
+			delete(cfg.PendingBotTokens, botInstanceID)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("DiscardPendingBotToken: %w", err)
+	}
+	return nil
+}
+
+func hasBotInstanceToken(cfg *BotConfig, botInstanceID string) bool {
+	for _, guild := range cfg.Guilds {
+		if token, ok := guild.BotInstanceTokens[botInstanceID]; ok && token != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPendingBotTokenStageAndPromote(t *testing.T) {
+	t.Parallel()
+
+	const (
+		oldToken = "old.token.value"
+		newToken = "new.token.value"
+	)
+	mgr, store := newTransactionalTestManager(t, &BotConfig{Guilds: []GuildConfig{
+		{GuildID: "g1", BotInstanceTokens: map[string]EncryptedString{"alpha": oldToken}},
+		{GuildID: "g2", BotInstanceTokens: map[string]EncryptedString{"alpha": oldToken, "beta": "beta.token.value"}},
+	}}, nil)
+	ctx := context.Background()
+
+	if err := mgr.StagePendingBotToken(ctx, "gamma", newToken); !errors.Is(err, ErrUnknownBotInstance) {
+		t.Fatalf("expected unknown instance error, got %v", err)
+	}
+	if err := mgr.StagePendingBotToken(ctx, "alpha", "not-a-token"); !errors.Is(err, ErrInvalidBotToken) {
+		t.Fatalf("expected malformed token error, got %v", err)
+	}
+	if err := mgr.StagePendingBotToken(ctx, "alpha", newToken); err != nil {
+		t.Fatalf("stage token: %v", err)
+	}
+	if got := store.cfg.PendingBotTokens["alpha"]; got != newToken {
+		t.Fatalf("expected staged token to be persisted, got %q", got)
+	}
+
+	if promoted, err := mgr.PromotePendingBotToken(ctx, "alpha", "stale.token.value"); err != nil || promoted {
+		t.Fatalf("expected stale promotion to be skipped, got promoted=%v err=%v", promoted, err)
+	}
+	promoted, err := mgr.PromotePendingBotToken(ctx, "alpha", newToken)
+	if err != nil || !promoted {
+		t.Fatalf("expected promotion, got promoted=%v err=%v", promoted, err)
+	}
+
+	cfg := mgr.SnapshotConfig()
+	for _, guild := range cfg.Guilds {
+		if got := guild.BotInstanceTokens["alpha"]; got != newToken {
+			t.Fatalf("guild %s kept token %q after promotion", guild.GuildID, got)
+		}
+	}
+	if got := cfg.Guilds[1].BotInstanceTokens["beta"]; got != "beta.token.value" {
+		t.Fatalf("expected unrelated instance token to be untouched, got %q", got)
+	}
+	if _, pending := cfg.PendingBotTokens["alpha"]; pending {
+		t.Fatal("expected pending token to be cleared after promotion")
+	}
+}
+
+func TestDiscardPendingBotToken(t *testing.T) {
+	t.Parallel()
+
+	mgr, _ := newTransactionalTestManager(t, &BotConfig{
+		Guilds:           []GuildConfig{{GuildID: "g1", BotInstanceTokens: map[string]EncryptedString{"alpha": "old.token.value"}}},
+		PendingBotTokens: map[string]EncryptedString{"alpha": "bad.token.value"},
+	}, nil)
+
+	if err := mgr.DiscardPendingBotToken(context.Background(), "alpha", "bad.token.value"); err != nil {
+		t.Fatalf("discard: %v", err)
+	}
+	cfg := mgr.SnapshotConfig()
+	if len(cfg.PendingBotTokens) != 0 {
+		t.Fatalf("expected pending tokens to be empty, got %v", cfg.PendingBotTokens)
+	}
+	if got := cfg.Guilds[0].BotInstanceTokens["alpha"]; got != "old.token.value" {
+		t.Fatalf("expected active token to be kept, got %q", got)
+	}
+}
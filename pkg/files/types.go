@@ -36,6 +36,19 @@ type RuntimeConfig struct {
 	// false: do not send moderation logs
 	ModerationLogging  *bool  `json:"moderation_logging,omitempty"`
 	LogModerationScope string `json:"log_moderation_scope,omitempty"` // discordcore, all_bots, all
+	// Actions per second performed by mass moderation commands such as
+	// massban. 0 means "use the default".
+	MassActionsPerSecond int `json:"mass_actions_per_second,omitempty"`
+	// Days of message history (0-7) deleted when /ban or /massban run
+	// without an explicit delete_messages option.
+	BanDeleteMessageDays int `json:"ban_delete_message_days,omitempty"`
+	// Destructive actions (bans, kicks) one moderator may take per window
+	// before being locked out for a window; 0 disables the guard.
+	ModeratorActionLimit         int `json:"moderator_action_limit,omitempty"`
+	ModeratorActionWindowMinutes int `json:"moderator_action_window_minutes,omitempty"`
+	// BotLockdown is the emergency kill switch set by /admin lockdown-bot. It
+	// is read from the global runtime config only; guild overrides are ignored.
+	BotLockdown bool `json:"bot_lockdown,omitempty"`
 
 	// PRESENCE WATCH
 	PresenceWatchUserID string `json:"presence_watch_user_id,omitempty"`
@@ -50,7 +63,40 @@ type RuntimeConfig struct {
 	// 0 means "use the runtime default budget".
 	GlobalMaxWorkers int `json:"global_max_workers,omitempty"`
 
+	// GATEWAY WATCHDOG (global only)
+	// Minutes without a dispatched gateway event, while the gateway still
+	// reports connected, before the session is reconnected.
+	// The watchdog is opt-in: 0 or negative leaves it disabled.
+	GatewayWatchdogIdleMinutes int `json:"gateway_watchdog_idle_minutes,omitempty"`
+
+	// AUDIT LOG POLLER (global only)
+	// Minutes between audit log checks for bans, kicks and prunes made
+	// outside discordcore, in guilds whose log_moderation_scope asks for them.
+	// 0 means "use the runtime default"; negative disables the poller.
+	AuditLogPollMinutes int `json:"audit_log_poll_minutes,omitempty"`
+
+	// COMMAND REGISTRATION (global only)
+	// CommandDevGuildID is the development guild, the only one commands
+	// scoped to it are registered in.
+	CommandDevGuildID string `json:"command_dev_guild_id,omitempty"`
+	// CommandScope, when set, registers every command with that scope
+	// ("global", "guild" or "dev_guild") instead of its own. Development
+	// builds set "dev_guild" so command changes show at once.
+	CommandScope string `json:"command_scope,omitempty"`
+
+	// Channel receiving operational alert summaries (send failures, database
+	// errors, dead-lettered tasks, crashed services). Empty sends them as a
+	// direct message to the application owner.
+	OwnerAlertChannelID string `json:"owner_alert_channel_id,omitempty"`
+	// Also send each runtime's startup summary of recovered scheduled work
+	// to the owner alert destination. The summary is always logged.
+	StartupReportToOwner bool `json:"startup_report_to_owner,omitempty"`
+
 	// BACKFILL (ENTRY/EXIT)
+	// These keys are stored and shown by /config runtime, but this module
+	// ships no backfill runner and no command to start one: nothing reads
+	// them apart from BackfillChannelID asking for member data. They stay so
+	// configs that set them keep loading.
 	BackfillChannelID   string `json:"backfill_channel_id,omitempty"`
 	BackfillStartDay    string `json:"backfill_start_day,omitempty"` // YYYY-MM-DD, default: today UTC when empty
 	BackfillInitialDate string `json:"backfill_initial_date,omitempty"`
@@ -236,7 +282,28 @@ type ChannelsConfig struct {
 	AutomodAction  string `json:"automod_action,omitempty"`
 	ModerationCase string `json:"moderation_case,omitempty"`
 	CleanAction    string `json:"clean_action,omitempty"`
+	ThreadLogging  string `json:"thread_logging,omitempty"`
 	EntryBackfill  string `json:"entry_backfill,omitempty"`
+	// CommandAudit mirrors the privileged command audit trail.
+	CommandAudit string `json:"command_audit,omitempty"`
+	// Transparency receives the monthly public moderation summary.
+	Transparency string `json:"transparency,omitempty"`
+	// ServerLog receives changes to the server itself, such as a role's
+	// color or permissions.
+	ServerLog string `json:"server_log,omitempty"`
+	// VoiceLog receives stages starting and ending and members moved or
+	// disconnected from voice by moderators. Empty falls back to ServerLog.
+	VoiceLog string `json:"voice_log,omitempty"`
+	// AssetLog receives stickers and soundboard sounds being uploaded,
+	// edited and deleted. Empty falls back to ServerLog.
+	AssetLog string `json:"asset_log,omitempty"`
+	// PresenceLog receives the custom status and activity changes of
+	// watched members.
+	PresenceLog string `json:"presence_log,omitempty"`
+	// LogRoutes sends single log events, keyed by event type, to a channel
+	// of their own ahead of the fields above. The value "disabled" turns
+	// the event off.
+	LogRoutes map[string]string `json:"log_routes,omitempty"`
 }
 
 // UnmarshalJSON unmarshals json.
@@ -274,6 +341,12 @@ type StatsChannelConfig struct {
 // StatsConfig groups the periodic stats channel updates for a guild.
 type StatsConfig struct {
 	Channels []StatsChannelConfig `json:"channels,omitempty"`
+	// HourlyMemberCounts records the member count every hour next to the
+	// daily count.
+	HourlyMemberCounts bool `json:"hourly_member_counts,omitempty"`
+	// Exclusions keeps channels, categories and roles out of the activity
+	// metrics.
+	Exclusions MetricsExclusionConfig `json:"exclusions,omitempty"`
 }
 
 // AutoAssignmentConfig defines automatic role assignment rules.
@@ -468,6 +541,17 @@ type ReactionBlockConfig struct {
 	Rules []ReactionBlockRuleConfig `json:"rules,omitempty"`
 }
 
+// NotificationRouteConfig redirects matching log events to ChannelID instead
+// of the channel configured for the event. Every condition that is set must
+// hold; Events, when empty, matches every event type.
+type NotificationRouteConfig struct {
+	Events        []string `json:"events,omitempty"`
+	TargetRoleIDs []string `json:"target_role_ids,omitempty"`
+	TargetIsBot   *bool    `json:"target_is_bot,omitempty"`
+	ActorIsBot    *bool    `json:"actor_is_bot,omitempty"`
+	ChannelID     string   `json:"channel_id"`
+}
+
 // TicketsCategoryConfig maps a ticket category name to its assigned Role ID.
 type TicketsCategoryConfig struct {
 	Name   string `json:"name,omitempty"`
@@ -481,10 +565,18 @@ type TicketsConfig struct {
 	Categories          []TicketsCategoryConfig `json:"categories,omitempty"`
 }
 
+// LogMentionPolicy lists the mention kinds allowed to ping from a log message.
+type LogMentionPolicy struct {
+	Users    bool `json:"users,omitempty"`
+	Roles    bool `json:"roles,omitempty"`
+	Everyone bool `json:"everyone,omitempty"`
+}
+
 // GuildConfig holds the configuration for a specific guild.
 type GuildConfig struct {
 	GuildID             string                     `json:"guild_id"`
 	ConfigVersion       int64                      `json:"config_version"`
+	Profile             string                     `json:"profile,omitempty"`
 	BotInstanceTokens   map[string]EncryptedString `json:"bot_instance_tokens,omitempty"`
 	BotInstanceStatuses map[string]string          `json:"bot_instance_statuses,omitempty"`
 	FeatureRouting      map[string]string          `json:"feature_routing,omitempty"`
@@ -508,11 +600,117 @@ type GuildConfig struct {
 	RolePanels     []RolePanelConfig   `json:"role_panels,omitempty"`
 	CustomEmbeds   []CustomEmbedConfig `json:"custom_embeds,omitempty"`
 
+	// NotificationRoutes are evaluated in order; the first match wins.
+	NotificationRoutes []NotificationRouteConfig `json:"notification_routes,omitempty"`
+
+	// LogMentions controls which mentions in log messages may ping, keyed by
+	// log event type, with "default" covering unlisted events. Mentions
+	// still render either way; without an entry nothing pings.
+	LogMentions map[string]LogMentionPolicy `json:"log_mentions,omitempty"`
+
+	// LogLanguages sets the language of log embeds, "en" (default) or
+	// "pt-BR", keyed by log event type with "default" covering unlisted
+	// events. It is independent of the language commands answer in.
+	LogLanguages map[string]string `json:"log_languages,omitempty"`
+
 	// RuntimeConfig allows per-guild overrides for certain settings.
 	RuntimeConfig RuntimeConfig `json:"runtime_config,omitempty"`
 
 	// LogModerationScope determines what moderation events are logged.
 	LogModerationScope string `json:"log_moderation_scope,omitempty"`
+
+	// DisplayNameStyle selects the user name shown in log embeds: "nickname"
+	// (default), "global" or "username".
+	DisplayNameStyle string `json:"display_name_style,omitempty"`
+
+	// Timezone is the IANA zone, e.g. "Europe/Berlin", that times are shown
+	// in where Discord cannot localize them, such as exports. Empty means
+	// UTC.
+	Timezone string `json:"timezone,omitempty"`
+
+	// ThreadAutoJoin makes the logging bot join new threads, so their
+	// messages are cached for edit and delete logs like any channel's.
+	ThreadAutoJoin bool `json:"thread_auto_join,omitempty"`
+
+	// UserLogThreads routes avatar, role and name change logs and
+	// moderation action logs about a member into a thread of their own
+	// under the log channel, opened the first time the member is logged.
+	UserLogThreads bool `json:"user_log_threads,omitempty"`
+
+	// LogWebhooks delivers log embeds through per-channel webhooks instead
+	// of as the bot.
+	LogWebhooks LogWebhookConfig `json:"log_webhooks,omitempty"`
+
+	// Assets holds new stickers for staff approval and announces them.
+	Assets AssetsConfig `json:"assets,omitempty"`
+
+	// DisabledCommands lists top-level slash commands, such as "stats",
+	// that the guild turned off with /config commands. The router refuses
+	// them before dispatch.
+	DisabledCommands []string `json:"disabled_commands,omitempty"`
+
+	// PresenceWatch lists the members whose custom status and activity
+	// changes are logged when presence_watch.user is on.
+	PresenceWatch PresenceWatchConfig `json:"presence_watch,omitempty"`
+
+	// WarningEscalation lists actions applied automatically as members
+	// accumulate warnings, e.g. a timeout at 3 and a ban at 5.
+	WarningEscalation []WarningEscalationStep `json:"warning_escalation,omitempty"`
+
+	// Privacy limits which optional member data is stored: "standard"
+	// (default), "hashed" or "minimal".
+	Privacy PrivacyProfile `json:"privacy,omitempty"`
+
+	// Clean protects messages from /clean and sets when it must be confirmed.
+	Clean CleanConfig `json:"clean,omitempty"`
+
+	// PunishmentDM notifies members by DM before they are banned, kicked or
+	// timed out.
+	PunishmentDM PunishmentDMConfig `json:"punishment_dm,omitempty"`
+
+	// AutoPurge deletes old messages from channels every night.
+	AutoPurge AutoPurgeConfig `json:"auto_purge,omitempty"`
+
+	// ModerationProtection puts members beyond the reach of moderation
+	// commands.
+	ModerationProtection ModerationProtectionConfig `json:"moderation_protection,omitempty"`
+
+	// HealthReport posts a weekly activity and bot health digest to staff.
+	HealthReport HealthReportConfig `json:"health_report,omitempty"`
+
+	// RaidMode sets how long /raidmode lasts and which new accounts it
+	// turns away.
+	RaidMode RaidModeConfig `json:"raid_mode,omitempty"`
+
+	// BanPools names the ban pools the guild shares bans with. Bans issued
+	// with the pool flag reach every other guild of the bot listing one of
+	// the same pools.
+	BanPools []string `json:"ban_pools,omitempty"`
+
+	// AutomodRules are the content rules /automod manages, checked against
+	// every member message in order.
+	AutomodRules []AutomodRule `json:"automod_rules,omitempty"`
+
+	// InviteFilter acts on invites to other servers posted by members.
+	InviteFilter InviteFilterConfig `json:"invite_filter,omitempty"`
+
+	// SpamFilter deletes messages carrying too many mentions or emojis.
+	SpamFilter SpamFilterConfig `json:"spam_filter,omitempty"`
+
+	// AttachmentFilter deletes messages carrying blocked files.
+	AttachmentFilter AttachmentFilterConfig `json:"attachment_filter,omitempty"`
+
+	// JoinGate screens members as they join.
+	JoinGate JoinGateConfig `json:"join_gate,omitempty"`
+
+	// Retention sets how long cached messages, avatar history and archived
+	// log events are kept before the nightly pruning removes them.
+	Retention RetentionConfig `json:"retention,omitempty"`
+
+	// TestMode simulates moderation and automod actions: they are logged
+	// with a test banner, but nothing is changed on Discord and no cases are
+	// recorded. It lets staff trial configuration on a live server.
+	TestMode bool `json:"test_mode,omitempty"`
 }
 
 // UnmarshalJSON unmarshals json.
@@ -559,6 +757,10 @@ func (gc *GuildConfig) UnmarshalJSON(data []byte) error {
 
 // BotConfig holds the configuration for the bot.
 type BotConfig struct {
+	// SchemaVersion is the settings layout the document was written in. The
+	// file store migrates older documents up to CurrentSchemaVersion on load.
+	SchemaVersion int `json:"schema_version,omitempty"`
+
 	ConfigVersion int64         `json:"config_version"`
 	Guilds        []GuildConfig `json:"guilds"`
 
@@ -571,6 +773,18 @@ type BotConfig struct {
 	//
 	// NOTE: These are NOT environment variables. They are persisted in the active config store.
 	RuntimeConfig RuntimeConfig `json:"runtime_config,omitempty"`
+
+	// Profiles are named templates that guilds inherit via GuildConfig.Profile.
+	Profiles []ConfigProfile `json:"profiles,omitempty"`
+
+	// PendingBotTokens holds staged replacement tokens keyed by bot instance ID.
+	// The supervisor validates each one and promotes it into the guilds' bot
+	// instance tokens, reconnecting the affected runtime without a restart.
+	PendingBotTokens map[string]EncryptedString `json:"pending_bot_tokens,omitempty"`
+
+	// GuildAccess lists the guilds the bot may join. Bot runtimes leave any
+	// other guild as soon as Discord announces it.
+	GuildAccess GuildAccessPolicy `json:"guild_access,omitempty"`
 }
 
 // CustomRPCConfig holds profiles for local Discord Rich Presence.
@@ -627,6 +841,13 @@ type RPCButtonConfig struct {
 	URL   string `json:"url,omitempty"`
 }
 
+// LockdownActive reports whether the emergency lockdown is engaged, pausing
+// mass moderation commands (massban and /clean), auto-purge, pooled ban
+// delivery, webhook edits and log delivery through webhooks.
+func (cfg *BotConfig) LockdownActive() bool {
+	return cfg != nil && cfg.RuntimeConfig.BotLockdown
+}
+
 // ResolveRuntimeConfig returns the runtime configuration for a guild,
 // falling back to the global one if the field is not defined (zero-value).
 func (cfg *BotConfig) ResolveRuntimeConfig(guildID string) RuntimeConfig {
@@ -639,10 +860,12 @@ func (cfg *BotConfig) ResolveRuntimeConfig(guildID string) RuntimeConfig {
 	}
 
 	var guildRC RuntimeConfig
+	var profile string
 	found := false
 	for _, g := range cfg.Guilds {
 		if g.GuildID == guildID {
 			guildRC = g.RuntimeConfig
+			profile = g.Profile
 			found = true
 			break
 		}
@@ -652,10 +875,24 @@ func (cfg *BotConfig) ResolveRuntimeConfig(guildID string) RuntimeConfig {
 		return global
 	}
 
-	// Manual merging logic. Fields that are zero-value in guildRC will use global values.
-	// This is better than a generic library for such a small struct and specific rules.
+	// Layer the profile chain from its farthest ancestor inwards, then the guild.
 	resolved := global
+	chain := cfg.profileChain(profile)
+	for i := len(chain) - 1; i >= 0; i-- {
+		resolved = mergeRuntimeConfig(resolved, chain[i].RuntimeConfig)
+	}
+	resolved = mergeRuntimeConfig(resolved, guildRC)
 
+	// BackfillInitialDate is GuildOnly: it must be set in the guild config
+	// and does not fall back to the global config or a profile.
+	resolved.BackfillInitialDate = guildRC.BackfillInitialDate
+	return resolved
+}
+
+// mergeRuntimeConfig overlays guildRC (a guild or profile layer) onto resolved.
+// Manual merging logic. Fields that are zero-value in guildRC keep the base values.
+// This is better than a generic library for such a small struct and specific rules.
+func mergeRuntimeConfig(resolved, guildRC RuntimeConfig) RuntimeConfig {
 	if guildRC.Database.Driver != "" {
 		resolved.Database.Driver = guildRC.Database.Driver
 	}
@@ -706,6 +943,18 @@ func (cfg *BotConfig) ResolveRuntimeConfig(guildID string) RuntimeConfig {
 	if guildRC.LogModerationScope != "" {
 		resolved.LogModerationScope = guildRC.LogModerationScope
 	}
+	if guildRC.MassActionsPerSecond != 0 {
+		resolved.MassActionsPerSecond = guildRC.MassActionsPerSecond
+	}
+	if guildRC.BanDeleteMessageDays != 0 {
+		resolved.BanDeleteMessageDays = guildRC.BanDeleteMessageDays
+	}
+	if guildRC.ModeratorActionLimit != 0 {
+		resolved.ModeratorActionLimit = guildRC.ModeratorActionLimit
+	}
+	if guildRC.ModeratorActionWindowMinutes != 0 {
+		resolved.ModeratorActionWindowMinutes = guildRC.ModeratorActionWindowMinutes
+	}
 	if guildRC.PresenceWatchUserID != "" {
 		resolved.PresenceWatchUserID = guildRC.PresenceWatchUserID
 	}
@@ -733,10 +982,6 @@ func (cfg *BotConfig) ResolveRuntimeConfig(guildID string) RuntimeConfig {
 		resolved.BackfillStartDay = guildRC.BackfillStartDay
 	}
 
-	// BackfillInitialDate is GuildOnly: it must be set in the guild config
-	// and does not fall back to the global config.
-	resolved.BackfillInitialDate = guildRC.BackfillInitialDate
-
 	if guildRC.MimuWelcomeString != "" {
 		resolved.MimuWelcomeString = guildRC.MimuWelcomeString
 	}
@@ -783,7 +1028,10 @@ type ConfigSubscriber func(ctx context.Context, oldCfg, newCfg *BotConfig) error
 //
 // Concurrency: ConfigManager is safe for concurrent use by multiple goroutines.
 // Readers should treat Config() and GuildConfig() results as read-only snapshots;
-// persist changes through the existing update helpers.
+// persist changes through the existing update helpers. Mutations and saves are
+// serialized by mu, so concurrent writers from the runtime panel and services
+// never interleave a store write; reads are served lock-free from the published
+// snapshot.
 type ConfigManager struct {
 	configFilePath  string
 	logsDirPath     string
@@ -796,6 +1044,7 @@ type ConfigManager struct {
 	indexMisses     atomic.Uint64
 	indexDuplicates atomic.Uint64
 	subscribers     []ConfigSubscriber
+	readOnly        atomic.Bool
 	mu              sync.RWMutex
 }
 
@@ -890,6 +1139,19 @@ func (gc *GuildConfig) ChannelCacheTTLDuration() time.Duration {
 	return d
 }
 
+// Location returns the guild's time zone, or UTC when none is set or the
+// name no longer loads.
+func (gc *GuildConfig) Location() *time.Location {
+	if gc == nil || gc.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(gc.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
 // SetRolesCacheTTL sets the roles cache TTL per guild (e.g., "5m", "1h") and persists the setting.
 func (mgr *ConfigManager) SetRolesCacheTTL(guildID string, ttl string) error {
 	if guildID == "" {
@@ -0,0 +1,59 @@
+package files
+
+import "strings"
+
+// Warning escalation actions.
+const (
+	WarningEscalationTimeout = "timeout"
+	WarningEscalationKick    = "kick"
+	WarningEscalationBan     = "ban"
+)
+
+// WarningEscalationStep applies Action automatically when a member reaches
+// Warnings active warnings.
+type WarningEscalationStep struct {
+	Warnings int    `json:"warnings"`
+	Action   string `json:"action"`
+	// DurationMinutes is the timeout length; it is ignored by other actions.
+	DurationMinutes int `json:"duration_minutes,omitempty"`
+}
+
+// WarningEscalationFor returns the step triggered by reaching count warnings.
+// Steps fire only on the exact count, so each applies once as warnings
+// accumulate; when several steps share a count the last one wins.
+func (gc *GuildConfig) WarningEscalationFor(count int) (WarningEscalationStep, bool) {
+	if gc == nil || count <= 0 {
+		return WarningEscalationStep{}, false
+	}
+	var (
+		found WarningEscalationStep
+		ok    bool
+	)
+	for _, step := range gc.WarningEscalation {
+		if step.Warnings != count {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(step.Action)) {
+		case WarningEscalationTimeout:
+			if step.DurationMinutes <= 0 {
+				continue
+			}
+			step.Action = WarningEscalationTimeout
+		case WarningEscalationKick:
+			step.Action = WarningEscalationKick
+		case WarningEscalationBan:
+			step.Action = WarningEscalationBan
+		default:
+			continue
+		}
+		found, ok = step, true
+	}
+	return found, ok
+}
+
+func cloneWarningEscalation(in []WarningEscalationStep) []WarningEscalationStep {
+	if len(in) == 0 {
+		return nil
+	}
+	return append([]WarningEscalationStep(nil), in...)
+}
@@ -0,0 +1,29 @@
+package files
+
+import "testing"
+
+func TestWarningEscalationFor(t *testing.T) {
+	t.Parallel()
+
+	gc := &GuildConfig{WarningEscalation: []WarningEscalationStep{
+		{Warnings: 3, Action: "Timeout", DurationMinutes: 60},
+		{Warnings: 4, Action: "timeout"},
+		{Warnings: 5, Action: "ban"},
+		{Warnings: 6, Action: "mute"},
+	}}
+
+	if step, ok := gc.WarningEscalationFor(3); !ok || step.Action != WarningEscalationTimeout || step.DurationMinutes != 60 {
+		t.Fatalf("expected a 60 minute timeout at 3 warnings, got %+v, %v", step, ok)
+	}
+	if step, ok := gc.WarningEscalationFor(5); !ok || step.Action != WarningEscalationBan {
+		t.Fatalf("expected a ban at 5 warnings, got %+v, %v", step, ok)
+	}
+	for _, count := range []int{0, 1, 4, 6, 7} {
+		if step, ok := gc.WarningEscalationFor(count); ok {
+			t.Errorf("count %d: expected no escalation, got %+v", count, step)
+		}
+	}
+	if _, ok := (*GuildConfig)(nil).WarningEscalationFor(3); ok {
+		t.Fatal("expected nil config to never escalate")
+	}
+}
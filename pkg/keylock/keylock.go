@@ -0,0 +1,86 @@
+// Package keylock serializes work on one key, such as a guild, channel or
+// user, while work on other keys runs freely.
+package keylock
+
+import "sync"
+
+// Mutex is a set of mutual exclusion locks, one per key. Locks are created
+// on first use and forgotten once no caller holds or waits for them, so
+// keys that come and go do not accumulate.
+//
+// The zero value is ready to use. A Mutex must not be copied after first
+// use.
+type Mutex[K comparable] struct {
+	mu    sync.Mutex
+	locks map[K]*entry
+}
+
+type entry struct {
+	mu sync.Mutex
+	// refs counts the callers holding or waiting for mu, guarded by the
+	// Mutex's own mu.
+	refs int
+}
+
+// Lock locks key, waiting until no other caller holds it. The returned
+// function unlocks it; calling it again does nothing.
+func (m *Mutex[K]) Lock(key K) (unlock func()) {
+	e := m.acquire(key)
+	e.mu.Lock()
+	return m.unlocker(key, e)
+}
+
+// TryLock locks key if no other caller holds it, without waiting. When ok is
+// false the key is held elsewhere and unlock is nil.
+func (m *Mutex[K]) TryLock(key K) (unlock func(), ok bool) {
+	e := m.acquire(key)
+	if !e.mu.TryLock() {
+		m.release(key, e)
+		return nil, false
+	}
+	return m.unlocker(key, e), true
+}
+
+// acquire returns the entry of key, counting the caller as one of its
+// users.
+func (m *Mutex[K]) acquire(key K) *entry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locks == nil {
+		m.locks = make(map[K]*entry)
+	}
+	e, ok := m.locks[key]
+	if !ok {
+		e = &entry{}
+		m.locks[key] = e
+	}
+	e.refs++
+	return e
+}
+
+// release uncounts a user of e and forgets e once it has none left.
+func (m *Mutex[K]) release(key K, e *entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e.refs--
+	if e.refs == 0 {
+		delete(m.locks, key)
+	}
+}
+
+func (m *Mutex[K]) unlocker(key K, e *entry) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			e.mu.Unlock()
+			m.release(key, e)
+		})
+	}
+}
+
+// len reports how many keys are held or waited for.
+func (m *Mutex[K]) len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.locks)
+}
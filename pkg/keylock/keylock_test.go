@@ -0,0 +1,78 @@
+package keylock
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMutexSerializesOneKey(t *testing.T) {
+	t.Parallel()
+	var m Mutex[string]
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	inside, most := 0, 0
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := m.Lock("guild")
+			defer unlock()
+			mu.Lock()
+			inside++
+			most = max(most, inside)
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			inside--
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if most != 1 {
+		t.Fatalf("expected one holder of a key at a time, saw %d", most)
+	}
+	if n := m.len(); n != 0 {
+		t.Fatalf("expected unused keys to be forgotten, %d remain", n)
+	}
+}
+
+func TestMutexKeysAreIndependent(t *testing.T) {
+	t.Parallel()
+	var m Mutex[int]
+	unlock := m.Lock(1)
+	defer unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.Lock(2)()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("locking another key should not wait for a held one")
+	}
+}
+
+func TestMutexTryLock(t *testing.T) {
+	t.Parallel()
+	var m Mutex[string]
+	unlock, ok := m.TryLock("guild")
+	if !ok {
+		t.Fatal("expected a free key to be locked")
+	}
+	if again, ok := m.TryLock("guild"); ok || again != nil {
+		t.Fatal("expected a held key to be refused")
+	}
+	unlock()
+	unlock()
+	if n := m.len(); n != 0 {
+		t.Fatalf("expected the key to be forgotten once unlocked, %d remain", n)
+	}
+	relock, ok := m.TryLock("guild")
+	if !ok {
+		t.Fatal("expected the key to be free again")
+	}
+	relock()
+}
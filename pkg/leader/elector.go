@@ -0,0 +1,184 @@
+// Package leader elects a single active instance among several processes
+// sharing one database, so a standby can take over when the leader stops.
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Default lease timings. A leader renews three times per TTL, so a takeover
+// happens at most one TTL after the leader stops renewing.
+//
+// A leader whose renewals fail steps down once the next attempt would come
+// later than the TTL minus a tenth of it, so it never keeps acting on a
+// lease a follower may already have taken over, even with some clock drift
+// between the instances and the database.
+const (
+	DefaultTTL           = 15 * time.Second
+	DefaultRetryInterval = 5 * time.Second
+)
+
+// ErrLeadershipLost is returned by Hold once the lease can no longer be
+// trusted, either because another holder took it or because renewals failed
+// for nearly a full TTL.
+var ErrLeadershipLost = errors.New("leadership lost")
+
+// LeaseStore persists leases. *postgres.Store satisfies it.
+type LeaseStore interface {
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
+
+// Option configures an Elector.
+type Option func(*Elector)
+
+// WithTTL sets how long a lease stays valid without renewal.
+func WithTTL(ttl time.Duration) Option {
+	return func(e *Elector) {
+		if ttl > 0 {
+			e.ttl = ttl
+		}
+	}
+}
+
+// WithRetryInterval sets how often a follower retries to take the lease.
+func WithRetryInterval(d time.Duration) Option {
+	return func(e *Elector) {
+		if d > 0 {
+			e.retry = d
+		}
+	}
+}
+
+// WithLogger sets the logger used for leadership transitions.
+func WithLogger(logger *slog.Logger) Option {
+	return func(e *Elector) {
+		if logger != nil {
+			e.logger = logger
+		}
+	}
+}
+
+// Elector competes for one named lease on behalf of holder.
+type Elector struct {
+	store  LeaseStore
+	name   string
+	holder string
+	ttl    time.Duration
+	retry  time.Duration
+	logger *slog.Logger
+	leader atomic.Bool
+	// renewed is when the lease was last acquired or renewed. Only Await
+	// and Hold touch it, and they run one after the other.
+	renewed time.Time
+}
+
+// NewElector creates an elector for the lease called name.
+func NewElector(store LeaseStore, name, holder string, opts ...Option) *Elector {
+	e := &Elector{
+		store:  store,
+		name:   name,
+		holder: holder,
+		ttl:    DefaultTTL,
+		retry:  DefaultRetryInterval,
+		logger: slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Holder returns the identity this elector competes as.
+func (e *Elector) Holder() string { return e.holder }
+
+// IsLeader reports whether the lease is currently held.
+func (e *Elector) IsLeader() bool { return e.leader.Load() }
+
+// Await blocks until the lease is acquired or ctx ends. Store errors are
+// logged and retried, since the current leader may simply be unreachable.
+func (e *Elector) Await(ctx context.Context) error {
+	for {
+		ok, err := e.store.AcquireLease(ctx, e.name, e.holder, e.ttl)
+		switch {
+		case err != nil:
+			e.logger.Warn("Mitigated service degradation: Leader lease acquisition failed; retrying",
+				slog.String("lease", e.name),
+				slog.String("error", err.Error()),
+			)
+		case ok:
+			e.renewed = time.Now()
+			e.leader.Store(true)
+			e.logger.Info("Architectural state transition: Leader lease acquired",
+				slog.String("lease", e.name),
+				slog.String("holder", e.holder),
+			)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Elector.Await: %w", ctx.Err())
+		case <-time.After(e.retry):
+		}
+	}
+}
+
+// Hold renews the lease until ctx ends, returning nil, or until the lease is
+// lost, returning ErrLeadershipLost. Hold does not release the lease; call
+// Release once the work it guards has stopped.
+func (e *Elector) Hold(ctx context.Context) error {
+	interval := e.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	safeFor := e.ttl - e.ttl/10
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		ok, err := e.store.AcquireLease(ctx, e.name, e.holder, e.ttl)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			if time.Since(e.renewed)+interval < safeFor {
+				e.logger.Warn("Mitigated service degradation: Leader lease renewal failed; retrying",
+					slog.String("lease", e.name),
+					slog.String("error", err.Error()),
+				)
+				continue
+			}
+			e.leader.Store(false)
+			return fmt.Errorf("Elector.Hold: %w: %w", ErrLeadershipLost, err)
+		case !ok:
+			e.leader.Store(false)
+			return fmt.Errorf("Elector.Hold: %w: lease %q taken by another holder", ErrLeadershipLost, e.name)
+		default:
+			e.renewed = time.Now()
+		}
+	}
+}
+
+// Release gives the lease up so a follower can take over immediately.
+func (e *Elector) Release(ctx context.Context) error {
+	if !e.leader.Swap(false) {
+		return nil
+	}
+	if err := e.store.ReleaseLease(ctx, e.name, e.holder); err != nil {
+		return fmt.Errorf("Elector.Release: %w", err)
+	}
+	e.logger.Info("Architectural state transition: Leader lease released",
+		slog.String("lease", e.name),
+		slog.String("holder", e.holder),
+	)
+	return nil
+}
@@ -0,0 +1,156 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryLeases mimics the database lease table on the local clock.
+type memoryLeases struct {
+	mu      sync.Mutex
+	holder  string
+	expires time.Time
+	failing bool
+}
+
+func (m *memoryLeases) AcquireLease(_ context.Context, _, holder string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failing {
+		return false, errors.New("database unavailable")
+	}
+	now := time.Now()
+	if m.holder != "" && m.holder != holder && now.Before(m.expires) {
+		return false, nil
+	}
+	m.holder, m.expires = holder, now.Add(ttl)
+	return true, nil
+}
+
+func (m *memoryLeases) ReleaseLease(_ context.Context, _, holder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.holder == holder {
+		m.holder = ""
+	}
+	return nil
+}
+
+func (m *memoryLeases) set(fn func(*memoryLeases)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fn(m)
+}
+
+func TestElectorFollowerTakesOverAfterRelease(t *testing.T) {
+	t.Parallel()
+	store := &memoryLeases{}
+	opts := []Option{WithTTL(300 * time.Millisecond), WithRetryInterval(10 * time.Millisecond)}
+	a := NewElector(store, "runtime", "a", opts...)
+	b := NewElector(store, "runtime", "b", opts...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.Await(ctx); err != nil {
+		t.Fatalf("a.Await: %v", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() { acquired <- b.Await(ctx) }()
+	select {
+	case err := <-acquired:
+		t.Fatalf("follower acquired a held lease: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := a.Release(ctx); err != nil {
+		t.Fatalf("a.Release: %v", err)
+	}
+	if err := <-acquired; err != nil {
+		t.Fatalf("b.Await: %v", err)
+	}
+	if a.IsLeader() || !b.IsLeader() {
+		t.Fatalf("leadership after handover: a=%v b=%v", a.IsLeader(), b.IsLeader())
+	}
+}
+
+func TestElectorHoldReportsLostLease(t *testing.T) {
+	t.Parallel()
+	store := &memoryLeases{}
+	e := NewElector(store, "runtime", "a", WithTTL(60*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := e.Await(ctx); err != nil {
+		t.Fatalf("Await: %v", err)
+	}
+	store.set(func(m *memoryLeases) { m.holder, m.expires = "b", time.Now().Add(time.Hour) })
+
+	if err := e.Hold(ctx); !errors.Is(err, ErrLeadershipLost) {
+		t.Fatalf("Hold = %v, want ErrLeadershipLost", err)
+	}
+	if e.IsLeader() {
+		t.Fatal("expected IsLeader to be false after losing the lease")
+	}
+}
+
+func TestElectorHoldToleratesBriefStoreOutage(t *testing.T) {
+	t.Parallel()
+	store := &memoryLeases{}
+	e := NewElector(store, "runtime", "a", WithTTL(90*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := e.Await(ctx); err != nil {
+		t.Fatalf("Await: %v", err)
+	}
+
+	holdCtx, stop := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- e.Hold(holdCtx) }()
+
+	// One failed renewal is shorter than the TTL and must not drop the lease.
+	store.set(func(m *memoryLeases) { m.failing = true })
+	time.Sleep(40 * time.Millisecond)
+	store.set(func(m *memoryLeases) { m.failing = false })
+	time.Sleep(60 * time.Millisecond)
+	stop()
+
+	if err := <-done; err != nil {
+		t.Fatalf("Hold = %v, want nil after cancellation", err)
+	}
+	if !e.IsLeader() {
+		t.Fatal("expected the lease to survive a brief outage")
+	}
+
+	// Renewals failing for nearly a whole TTL give leadership up.
+	store.set(func(m *memoryLeases) { m.failing = true })
+	if err := e.Hold(ctx); !errors.Is(err, ErrLeadershipLost) {
+		t.Fatalf("Hold = %v, want ErrLeadershipLost", err)
+	}
+}
+
+func TestElectorHoldStepsDownBeforeTheLeaseExpires(t *testing.T) {
+	t.Parallel()
+	store := &memoryLeases{}
+	ttl := 300 * time.Millisecond
+	e := NewElector(store, "runtime", "a", WithTTL(ttl))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := e.Await(ctx); err != nil {
+		t.Fatalf("Await: %v", err)
+	}
+	acquired := time.Now()
+
+	store.set(func(m *memoryLeases) { m.failing = true })
+	if err := e.Hold(ctx); !errors.Is(err, ErrLeadershipLost) {
+		t.Fatalf("Hold = %v, want ErrLeadershipLost", err)
+	}
+	if held := time.Since(acquired); held >= ttl {
+		t.Fatalf("stepped down %v after the last renewal, want less than the %v TTL", held, ttl)
+	}
+}
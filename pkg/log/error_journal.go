@@ -0,0 +1,110 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/small-frappuccino/discordcore/pkg/observability"
+)
+
+const maxJournalDetailLength = 1000
+
+// journalHandler copies error-level records into an error journal so they
+// can be browsed from Discord without access to the log files.
+type journalHandler struct {
+	journal *observability.ErrorJournal
+	attrs   []slog.Attr
+	group   string
+}
+
+// Enabled enableds.
+func (h *journalHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelError
+}
+
+// Handle handles.
+func (h *journalHandler) Handle(_ context.Context, r slog.Record) error {
+	if r.Level < slog.LevelError {
+		return nil
+	}
+	attrs := make([]slog.Attr, 0, len(h.attrs)+r.NumAttrs())
+	attrs = append(attrs, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		if h.group != "" {
+			a.Key = h.group + "." + a.Key
+		}
+		attrs = append(attrs, a)
+		return true
+	})
+	h.journal.Record(observability.ErrorRecord{
+		At:        r.Time,
+		Subsystem: journalSubsystem(attrs),
+		Message:   r.Message,
+		Detail:    journalDetail(attrs),
+	})
+	return nil
+}
+
+// WithAttrs withs attrs.
+func (h *journalHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := *h
+	next.attrs = make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	next.attrs = append(next.attrs, h.attrs...)
+	for _, a := range attrs {
+		if h.group != "" {
+			a.Key = h.group + "." + a.Key
+		}
+		next.attrs = append(next.attrs, a)
+	}
+	return &next
+}
+
+// WithGroup withs group.
+func (h *journalHandler) WithGroup(name string) slog.Handler {
+	next := *h
+	if h.group != "" {
+		name = h.group + "." + name
+	}
+	next.group = name
+	return &next
+}
+
+// journalSubsystem picks the most specific origin a record carries: an
+// explicit component, then the operation, then the logger category.
+func journalSubsystem(attrs []slog.Attr) string {
+	for _, key := range []string{"component", "operation", "category"} {
+		for i := len(attrs) - 1; i >= 0; i-- {
+			if attrs[i].Key == key {
+				if v := strings.TrimSpace(attrs[i].Value.String()); v != "" {
+					return v
+				}
+			}
+		}
+	}
+	return "application"
+}
+
+func journalDetail(attrs []slog.Attr) string {
+	var b strings.Builder
+	for _, a := range attrs {
+		switch a.Key {
+		case "service", "category", "component":
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%s=%v", a.Key, a.Value.Resolve())
+		if utf8.RuneCountInString(b.String()) >= maxJournalDetailLength {
+			break
+		}
+	}
+	detail := b.String()
+	if utf8.RuneCountInString(detail) > maxJournalDetailLength {
+		detail = string([]rune(detail)[:maxJournalDetailLength])
+	}
+	return detail
+}
@@ -0,0 +1,48 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/small-frappuccino/discordcore/pkg/observability"
+)
+
+func TestJournalHandlerRecordsErrorsOnly(t *testing.T) {
+	t.Parallel()
+
+	journal := observability.NewErrorJournal(10)
+	logger := slog.New(&journalHandler{journal: journal}).With(slog.String("category", "database"))
+
+	logger.Warn("slow query")
+	logger.Error("insert failed", slog.String("table", "messages"))
+	logger.With(slog.String("component", "qotd")).Error("publish failed", slog.String("guild_id", "1"))
+
+	got, err := journal.Recent(context.Background(), observability.ErrorRecordFilter{})
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected two error records, got %+v", got)
+	}
+	if got[0].Subsystem != "qotd" || got[0].Detail != "guild_id=1" {
+		t.Fatalf("expected component to name the subsystem, got %+v", got[0])
+	}
+	if got[1].Subsystem != "database" || !strings.Contains(got[1].Detail, "table=messages") {
+		t.Fatalf("expected category fallback with attributes, got %+v", got[1])
+	}
+}
+
+func TestJournalDetailKeepsRunesWhole(t *testing.T) {
+	t.Parallel()
+
+	detail := journalDetail([]slog.Attr{slog.String("reason", strings.Repeat("é", maxJournalDetailLength))})
+	if !utf8.ValidString(detail) {
+		t.Fatal("expected the truncated detail to stay valid UTF-8")
+	}
+	if n := utf8.RuneCountInString(detail); n != maxJournalDetailLength {
+		t.Fatalf("expected %d runes, got %d", maxJournalDetailLength, n)
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 	"log/slog"
 
+	"github.com/small-frappuccino/discordcore/pkg/observability"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
@@ -338,8 +339,9 @@ func (m *multiHandler) WithGroup(name string) slog.Handler {
 	return &multiHandler{handlers: out}
 }
 
-// buildCategoryLogger creates a slog.Logger that tees to file (JSON) and console (text)
-// and annotates every record with service and category attributes.
+// buildCategoryLogger creates a slog.Logger that tees to file (JSON) and console (text),
+// copies errors into the process error journal, and annotates every record with
+// service and category attributes.
 func buildCategoryLogger(category string, fileWriter *lumberjack.Logger, consoleWriter *os.File, levelVar *slog.LevelVar, botName string) *slog.Logger {
 	jsonHandler := slog.NewJSONHandler(fileWriter, &slog.HandlerOptions{
 		Level:     levelVar,
@@ -350,7 +352,9 @@ func buildCategoryLogger(category string, fileWriter *lumberjack.Logger, console
 		AddSource: true,
 	})
 
-	handler := &multiHandler{handlers: []slog.Handler{jsonHandler, textHandler}}
+	journal := &journalHandler{journal: observability.Errors()}
+
+	handler := &multiHandler{handlers: []slog.Handler{jsonHandler, textHandler, journal}}
 	base := slog.New(handler).With(
 		slog.String("service", botName),
 		slog.String("category", category),
@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"context"
+	"time"
+)
+
+// ArchivedEvent is the searchable summary of one log message the bot
+// emitted.
+type ArchivedEvent struct {
+	ID        int64
+	At        time.Time
+	GuildID   string
+	EventType LogEventType
+	// UserID is the member the event is about and ActorID whoever caused it,
+	// when known.
+	UserID  string
+	ActorID string
+	// ChannelID is the channel the event happened in and LogChannelID the
+	// channel or thread it was logged to.
+	ChannelID    string
+	LogChannelID string
+	Title        string
+	Summary      string
+}
+
+// ArchiveFilter narrows a log archive search. Zero fields do not filter;
+// events are returned newest first.
+type ArchiveFilter struct {
+	GuildID string
+	// UserID matches the subject or the actor of an event.
+	UserID    string
+	EventType LogEventType
+	// ChannelID matches the channel an event happened in or was logged to.
+	ChannelID string
+	Since     time.Time
+	Until     time.Time
+	Offset    int
+	Limit     int
+}
+
+// ArchiveStore keeps emitted log events. *postgres.Store satisfies it.
+type ArchiveStore interface {
+	AppendLogEvent(ctx context.Context, event ArchivedEvent) error
+}
@@ -0,0 +1,65 @@
+package logging
+
+import "strings"
+
+// DisplayNameStyle selects which of a user's names log embeds show.
+type DisplayNameStyle string
+
+const (
+	// DisplayNameNickname prefers the guild nickname, then the global display
+	// name, then the username. It is the default.
+	DisplayNameNickname DisplayNameStyle = "nickname"
+	// DisplayNameGlobal ignores guild nicknames.
+	DisplayNameGlobal DisplayNameStyle = "global"
+	// DisplayNameUsername always shows the unique username.
+	DisplayNameUsername DisplayNameStyle = "username"
+)
+
+// ParseDisplayNameStyle maps a configured value to a style, falling back to
+// DisplayNameNickname for empty or unknown values.
+func ParseDisplayNameStyle(raw string) DisplayNameStyle {
+	switch style := DisplayNameStyle(strings.ToLower(strings.TrimSpace(raw))); style {
+	case DisplayNameGlobal, DisplayNameUsername:
+		return style
+	default:
+		return DisplayNameNickname
+	}
+}
+
+// UserNames carries every name Discord knows a user by.
+type UserNames struct {
+	Username      string
+	Discriminator string
+	GlobalName    string
+	Nick          string
+}
+
+// Tag returns the username, suffixed with the discriminator for accounts
+// that have not migrated to unique usernames.
+func (n UserNames) Tag() string {
+	username := strings.TrimSpace(n.Username)
+	discriminator := strings.TrimSpace(n.Discriminator)
+	if username == "" || discriminator == "" || strings.Trim(discriminator, "0") == "" {
+		return username
+	}
+	return username + "#" + discriminator
+}
+
+// ResolveDisplayName picks the name to show for n under style. Names that are
+// not set are skipped, so every style ends at the username tag.
+func ResolveDisplayName(n UserNames, style DisplayNameStyle) string {
+	var candidates []string
+	switch style {
+	case DisplayNameUsername:
+	case DisplayNameGlobal:
+		candidates = []string{n.GlobalName}
+	default:
+		candidates = []string{n.Nick, n.GlobalName}
+	}
+	for _, name := range candidates {
+		if name = strings.TrimSpace(name); name != "" {
+			return name
+		}
+	}
+	return n.Tag()
+}
@@ -0,0 +1,43 @@
+package logging
+
+import "testing"
+
+func TestResolveDisplayName(t *testing.T) {
+	t.Parallel()
+	full := UserNames{Username: "alice", GlobalName: "Alice", Nick: "Al"}
+	legacy := UserNames{Username: "bob", Discriminator: "0420"}
+
+	tests := []struct {
+		name  string
+		names UserNames
+		style DisplayNameStyle
+		want  string
+	}{
+		{name: "nickname first", names: full, style: DisplayNameNickname, want: "Al"},
+		{name: "global skips nickname", names: full, style: DisplayNameGlobal, want: "Alice"},
+		{name: "username only", names: full, style: DisplayNameUsername, want: "alice"},
+		{name: "falls back to global name", names: UserNames{Username: "alice", GlobalName: "Alice"}, style: DisplayNameNickname, want: "Alice"},
+		{name: "legacy discriminator", names: legacy, style: DisplayNameNickname, want: "bob#0420"},
+		{name: "migrated discriminator", names: UserNames{Username: "carol", Discriminator: "0"}, style: DisplayNameUsername, want: "carol"},
+		{name: "blank nickname ignored", names: UserNames{Username: "dave", Nick: "  "}, style: DisplayNameNickname, want: "dave"},
+	}
+	for _, tt := range tests {
+		if got := ResolveDisplayName(tt.names, tt.style); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseDisplayNameStyle(t *testing.T) {
+	t.Parallel()
+	for raw, want := range map[string]DisplayNameStyle{
+		"":         DisplayNameNickname,
+		"bogus":    DisplayNameNickname,
+		"Global":   DisplayNameGlobal,
+		"username": DisplayNameUsername,
+	} {
+		if got := ParseDisplayNameStyle(raw); got != want {
+			t.Errorf("ParseDisplayNameStyle(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package logging
+
+import "strings"
+
+// markdownInline escapes the characters Discord reads as formatting anywhere
+// in a line. Backslash comes first so escapes added here are not escaped
+// again.
+var markdownInline = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"`", "\\`",
+	"|", `\|`,
+	"[", `\[`,
+	"]", `\]`,
+)
+
+// EscapeMarkdown makes s render as typed in a message or embed: inline
+// formatting, masked links, and the quote, heading and list markers that
+// start a line are escaped.
+func EscapeMarkdown(s string) string {
+	if s == "" {
+		return s
+	}
+	lines := strings.Split(markdownInline.Replace(s), "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" {
+			continue
+		}
+		switch trimmed[0] {
+		case '>', '#', '-':
+			indent := len(line) - len(trimmed)
+			lines[i] = line[:indent] + `\` + trimmed
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// EscapeMentions breaks every @ with a zero-width space, so user, role,
+// @everyone and @here mentions in s neither render nor ping.
+func EscapeMentions(s string) string {
+	return strings.ReplaceAll(s, "@", "@\u200b")
+}
+
+// EscapeUserText prepares user-supplied text, such as a username, reason or
+// message content, for a message or embed: it shows as typed and pings no
+// one.
+func EscapeUserText(s string) string {
+	return EscapeMentions(EscapeMarkdown(s))
+}
+
+// InlineCode wraps s in a code span. Backticks inside s, which would close
+// the span, become quotes.
+func InlineCode(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "'") + "`"
+}
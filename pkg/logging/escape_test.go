@@ -0,0 +1,37 @@
+package logging
+
+import "testing"
+
+func TestEscapeMarkdown(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"plain name", "plain name"},
+		{"**bold** _it_ ~~gone~~ ||spoiler||", `\*\*bold\*\* \_it\_ \~\~gone\~\~ \|\|spoiler\|\|`},
+		{"[free nitro](https://x.y)", `\[free nitro\](https://x.y)`},
+		{"`code`", "\\`code\\`"},
+		{`a\*b`, `a\\\*b`},
+		{"> quote\n# heading\n  - item\nnot-a-list", "\\> quote\n\\# heading\n  \\- item\nnot-a-list"},
+	}
+	for _, tt := range tests {
+		if got := EscapeMarkdown(tt.in); got != tt.want {
+			t.Errorf("EscapeMarkdown(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeUserText(t *testing.T) {
+	t.Parallel()
+
+	got := EscapeUserText("hi @everyone <@123> <@&456> *now*")
+	want := "hi @\u200beveryone <@\u200b123> <@\u200b&456> \\*now\\*"
+	if got != want {
+		t.Fatalf("EscapeUserText = %q, want %q", got, want)
+	}
+	if got := InlineCode("a`b"); got != "`a'b`" {
+		t.Fatalf("InlineCode = %q", got)
+	}
+}
@@ -9,7 +9,7 @@ import (
 // FormatUserLabel returns a standardized markdown label for a user.
 func FormatUserLabel(username, userID string) string {
 	userID = strings.TrimSpace(userID)
-	username = strings.TrimSpace(username)
+	username = EscapeUserText(strings.TrimSpace(username))
 	if userID == "" {
 		if username != "" {
 			return "**" + username + "**"
@@ -44,7 +44,7 @@ func FormatRoleLabel(roleID, roleName string) string {
 		return "<@&" + roleID + "> (`" + roleID + "`)"
 	}
 	if roleName != "" {
-		return "`" + roleName + "`"
+		return InlineCode(roleName)
 	}
 	return "Unknown"
 }
@@ -0,0 +1,169 @@
+package logging
+
+import (
+	"strings"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// LogLanguage selects the language log embeds are written in.
+type LogLanguage string
+
+const (
+	// LogLanguageEnglish is the default.
+	LogLanguageEnglish LogLanguage = "en"
+	// LogLanguagePortuguese is Brazilian Portuguese.
+	LogLanguagePortuguese LogLanguage = "pt-BR"
+)
+
+// LogLanguageDefaultKey selects the language for events without an entry of
+// their own in GuildConfig.LogLanguages.
+const LogLanguageDefaultKey = "default"
+
+// ParseLogLanguage maps a configured value to a language, falling back to
+// LogLanguageEnglish for empty or unknown values. Region and separator are
+// not significant, so "pt", "pt_br" and "PT-BR" all select Portuguese.
+func ParseLogLanguage(raw string) LogLanguage {
+	tag := strings.ToLower(strings.TrimSpace(raw))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	switch tag {
+	case "pt":
+		return LogLanguagePortuguese
+	default:
+		return LogLanguageEnglish
+	}
+}
+
+// ResolveLogLanguage returns the language log embeds for eventType are
+// written in for gcfg. It does not depend on the language commands answer
+// in.
+func ResolveLogLanguage(eventType LogEventType, gcfg *files.GuildConfig) LogLanguage {
+	if gcfg == nil || len(gcfg.LogLanguages) == 0 {
+		return LogLanguageEnglish
+	}
+	var fallback string
+	for key, language := range gcfg.LogLanguages {
+		switch key = strings.ToLower(strings.TrimSpace(key)); key {
+		case string(eventType):
+			return ParseLogLanguage(language)
+		case LogLanguageDefaultKey:
+			fallback = language
+		}
+	}
+	return ParseLogLanguage(fallback)
+}
+
+// Text translates the English log text s into lang. Text without a
+// translation is returned as it is, so a missing entry degrades to English.
+func (lang LogLanguage) Text(s string) string {
+	if translated, ok := logTranslations[lang][s]; ok {
+		return translated
+	}
+	return s
+}
+
+// logTranslations maps the English text of log embeds to other languages.
+// Entries with format verbs keep them in order, so callers can format the
+// translation like the original.
+var logTranslations = map[LogLanguage]map[string]string{
+	LogLanguagePortuguese: {
+		"Member Joined":                        "Membro entrou",
+		"Member Left":                          "Membro saiu",
+		"Account Created":                      "Conta criada",
+		"Time on Server":                       "Tempo no servidor",
+		"%s ago":                               "há %s",
+		"Role Updated":                         "Cargo atualizado",
+		"Role":                                 "Cargo",
+		"Action":                               "Ação",
+		"Added":                                "Adicionado",
+		"Removed":                              "Removido",
+		"Message Edited":                       "Mensagem editada",
+		"Message Deleted":                      "Mensagem apagada",
+		"Jump to message":                      "Ir para a mensagem",
+		"User":                                 "Usuário",
+		"Channel":                              "Canal",
+		"Message Timestamp":                    "Horário da mensagem",
+		"Before":                               "Antes",
+		"After":                                "Depois",
+		"Message":                              "Mensagem",
+		"Message ID: %s":                       "ID da mensagem: %s",
+		"Deleted By":                           "Apagada por",
+		"*Content not stored*":                 "*Conteúdo não armazenado*",
+		"*Content not stored (%d characters)*": "*Conteúdo não armazenado (%d caracteres)*",
+		"Moderation Action: %s":                "Ação de moderação: %s",
+		"Target":                               "Alvo",
+		"Moderator":                            "Moderador",
+		"Reason":                               "Motivo",
+		"No reason provided.":                  "Nenhum motivo informado.",
+		"Target ID: %s":                        "ID do alvo: %s",
+		"Avatar Updated":                       "Avatar atualizado",
+		"Previous Avatar":                      "Avatar anterior",
+		"See previous avatar":                  "Ver avatar anterior",
+		"User ID: %s":                          "ID do usuário: %s",
+		"Name changed":                         "Nome alterado",
+		"Username":                             "Nome de usuário",
+		"Display name":                         "Nome de exibição",
+		"Nickname":                             "Apelido",
+		"*(none)*":                             "*(nenhum)*",
+		"AutoMod • Action Executed":            "AutoMod • Ação executada",
+		"Blocked content detected (AutoMod).":  "Conteúdo bloqueado detectado (AutoMod).",
+		"AutoMod rule **%s** triggered.":       "Regra do AutoMod **%s** acionada.",
+		"Keyword":                              "Palavra-chave",
+		"Matched Content":                      "Conteúdo detectado",
+		"Thread Created":                       "Tópico criado",
+		"Thread Archived":                      "Tópico arquivado",
+		"Thread Deleted":                       "Tópico apagado",
+		"Thread":                               "Tópico",
+		"Thread ID: %s":                        "ID do tópico: %s",
+		"Parent Channel":                       "Canal de origem",
+		"Creator":                              "Criador",
+		"Auto-archive":                         "Arquivamento automático",
+		"*Unknown*":                            "*Desconhecido*",
+		"Role Edited":                          "Cargo editado",
+		"Permissions Granted":                  "Permissões concedidas",
+		"Permissions Revoked":                  "Permissões revogadas",
+		"Shown Separately":                     "Exibido separadamente",
+		"Mentionable":                          "Mencionável",
+		"Yes":                                  "Sim",
+		"No":                                   "Não",
+		"Changed By":                           "Alterado por",
+		"Color":                                "Cor",
+		"Icon":                                 "Ícone",
+		"Emoji":                                "Emoji",
+		"Default":                              "Padrão",
+		"View icon":                            "Ver ícone",
+		"Role ID: %s":                          "ID do cargo: %s",
+		"Stage Started":                        "Palco iniciado",
+		"Stage Ended":                          "Palco encerrado",
+		"Members Moved":                        "Membros movidos",
+		"Members Disconnected":                 "Membros desconectados",
+		"Stage":                                "Palco",
+		"Topic":                                "Tema",
+		"Moved To":                             "Movidos para",
+		"Members":                              "Membros",
+		"Sticker Uploaded":                     "Figurinha enviada",
+		"Sticker Updated":                      "Figurinha atualizada",
+		"Sticker Deleted":                      "Figurinha apagada",
+		"Sound Uploaded":                       "Som enviado",
+		"Sound Updated":                        "Som atualizado",
+		"Sound Deleted":                        "Som apagado",
+		"Name":                                 "Nome",
+		"Previous Name":                        "Nome anterior",
+		"Uploaded By":                          "Enviado por",
+		"Sticker ID: %s":                       "ID da figurinha: %s",
+		"Sound ID: %s":                         "ID do som: %s",
+		"Presence Updated":                     "Presença atualizada",
+		"Custom Status":                        "Status personalizado",
+		"Previous Custom Status":               "Status personalizado anterior",
+		"Activities":                           "Atividades",
+		"Previous Activities":                  "Atividades anteriores",
+		"None":                                 "Nenhum",
+		"Playing":                              "Jogando",
+		"Streaming":                            "Transmitindo",
+		"Listening to":                         "Ouvindo",
+		"Watching":                             "Assistindo",
+		"Competing in":                         "Competindo em",
+	},
+}
@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+func TestParseLogLanguage(t *testing.T) {
+	t.Parallel()
+
+	for raw, want := range map[string]LogLanguage{
+		"":        LogLanguageEnglish,
+		"en":      LogLanguageEnglish,
+		"pt":      LogLanguagePortuguese,
+		" PT_br":  LogLanguagePortuguese,
+		"pt-BR":   LogLanguagePortuguese,
+		"klingon": LogLanguageEnglish,
+	} {
+		if got := ParseLogLanguage(raw); got != want {
+			t.Errorf("ParseLogLanguage(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestResolveLogLanguage(t *testing.T) {
+	t.Parallel()
+
+	gcfg := &files.GuildConfig{LogLanguages: map[string]string{
+		"default":         "pt-BR",
+		"Moderation_Case": "en",
+	}}
+
+	if got := ResolveLogLanguage(LogEventModerationCase, gcfg); got != LogLanguageEnglish {
+		t.Fatalf("expected event entry to win, got %q", got)
+	}
+	if got := ResolveLogLanguage(LogEventMessageDelete, gcfg); got != LogLanguagePortuguese {
+		t.Fatalf("expected default entry, got %q", got)
+	}
+	if got := ResolveLogLanguage(LogEventMessageDelete, nil); got != LogLanguageEnglish {
+		t.Fatalf("expected English without config, got %q", got)
+	}
+}
+
+func TestLogLanguageText(t *testing.T) {
+	t.Parallel()
+
+	if got := LogLanguagePortuguese.Text("Member Joined"); got != "Membro entrou" {
+		t.Fatalf("expected a translation, got %q", got)
+	}
+	if got := LogLanguagePortuguese.Text("Not in the catalog"); got != "Not in the catalog" {
+		t.Fatalf("expected untranslated text back, got %q", got)
+	}
+	if got := LogLanguageEnglish.Text("Member Joined"); got != "Member Joined" {
+		t.Fatalf("expected English unchanged, got %q", got)
+	}
+
+	// Translations are used as format strings, so they must keep the verbs
+	// of the original.
+	for english, translated := range logTranslations[LogLanguagePortuguese] {
+		if strings.Count(english, "%") != strings.Count(translated, "%") {
+			t.Errorf("translation of %q changes its format verbs: %q", english, translated)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package logging
+
+import (
+	"strings"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// LogMentionDefaultKey selects the mention policy for events without an entry
+// of their own in GuildConfig.LogMentions.
+const LogMentionDefaultKey = "default"
+
+// ResolveMentionPolicy returns the mention policy for eventType in gcfg. The
+// zero policy, which suppresses every ping, applies when nothing is
+// configured.
+func ResolveMentionPolicy(eventType LogEventType, gcfg *files.GuildConfig) files.LogMentionPolicy {
+	if gcfg == nil || len(gcfg.LogMentions) == 0 {
+		return files.LogMentionPolicy{}
+	}
+	var fallback files.LogMentionPolicy
+	for key, policy := range gcfg.LogMentions {
+		switch key = strings.ToLower(strings.TrimSpace(key)); key {
+		case string(eventType):
+			return policy
+		case LogMentionDefaultKey:
+			fallback = policy
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,26 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+func TestResolveMentionPolicy(t *testing.T) {
+	t.Parallel()
+
+	gcfg := &files.GuildConfig{LogMentions: map[string]files.LogMentionPolicy{
+		"default":         {Roles: true},
+		"Moderation_Case": {Users: true},
+	}}
+
+	if got := ResolveMentionPolicy(LogEventModerationCase, gcfg); got != (files.LogMentionPolicy{Users: true}) {
+		t.Fatalf("expected event entry to win, got %+v", got)
+	}
+	if got := ResolveMentionPolicy(LogEventMessageDelete, gcfg); got != (files.LogMentionPolicy{Roles: true}) {
+		t.Fatalf("expected default entry, got %+v", got)
+	}
+	if got := ResolveMentionPolicy(LogEventMessageDelete, &files.GuildConfig{}); got != (files.LogMentionPolicy{}) {
+		t.Fatalf("expected pings suppressed without config, got %+v", got)
+	}
+}
@@ -2,6 +2,7 @@ package logging
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/small-frappuccino/discordcore/pkg/files"
@@ -21,8 +22,17 @@ type LogEventType string
 // LogEventMemberLeave defines log event member leave.
 // LogEventAvatarChange defines log event avatar change.
 // LogEventCleanAction defines log event clean action.
+// LogEventNameChange defines log event name change.
+// LogEventThreadChange defines log event thread change.
+// LogEventServerChange defines log event server change.
+// LogEventVoiceChange defines log event voice change.
+// LogEventAssetChange defines log event asset change.
+// LogEventPresenceChange defines log event presence change.
+// LogEventCommandAudit labels the command audit records mirrored to a
+// guild's command audit channel; it has no capability of its own.
 const (
 	LogEventAvatarChange   LogEventType = "avatar_change"
+	LogEventNameChange     LogEventType = "name_change"
 	LogEventRoleChange     LogEventType = "role_change"
 	LogEventMemberJoin     LogEventType = "member_join"
 	LogEventMemberLeave    LogEventType = "member_leave"
@@ -33,6 +43,12 @@ const (
 	LogEventAutomodAction  LogEventType = "automod_action"
 	LogEventModerationCase LogEventType = "moderation_case"
 	LogEventCleanAction    LogEventType = "clean_action"
+	LogEventThreadChange   LogEventType = "thread_change"
+	LogEventServerChange   LogEventType = "server_change"
+	LogEventVoiceChange    LogEventType = "voice_change"
+	LogEventAssetChange    LogEventType = "asset_change"
+	LogEventPresenceChange LogEventType = "presence_change"
+	LogEventCommandAudit   LogEventType = "command_audit"
 )
 
 // LogEventCategory groups events by subsystem.
@@ -43,12 +59,14 @@ type LogEventCategory string
 // LogCategoryUser defines log category user.
 // LogCategoryReaction defines log category reaction.
 // LogCategoryMessage defines log category message.
+// LogCategoryServer defines log category server.
 const (
 	LogCategoryUser       LogEventCategory = "user"
 	LogCategoryMessage    LogEventCategory = "message"
 	LogCategoryReaction   LogEventCategory = "reaction"
 	LogCategoryAutomod    LogEventCategory = "automod"
 	LogCategoryModeration LogEventCategory = "moderation"
+	LogCategoryServer     LogEventCategory = "server"
 )
 
 // EmitReason is a deterministic reason for a should-emit decision.
@@ -133,6 +151,20 @@ var logEventCapabilities = map[LogEventType]LogEventCapability{
 		RequiresChannel:     true,
 		Toggles:             []string{"runtime_config.disable_user_logs", "features.logging.avatar_logging"},
 	},
+	LogEventNameChange: {
+		EventType:           LogEventNameChange,
+		Category:            LogCategoryUser,
+		RequiredIntentsMask: 0,
+		RequiresChannel:     true,
+		Toggles:             []string{"runtime_config.disable_user_logs", "features.logging.avatar_logging"},
+	},
+	LogEventPresenceChange: {
+		EventType:           LogEventPresenceChange,
+		Category:            LogCategoryUser,
+		RequiredIntentsMask: (1 << 8),
+		RequiresChannel:     true,
+		Toggles:             []string{"runtime_config.disable_user_logs", "features.presence_watch.user", "features.presence_watch.bot"},
+	},
 	LogEventRoleChange: {
 		EventType:           LogEventRoleChange,
 		Category:            LogCategoryUser,
@@ -175,6 +207,31 @@ var logEventCapabilities = map[LogEventType]LogEventCapability{
 		RequiresChannel:     true,
 		Toggles:             []string{"runtime_config.disable_message_logs", "features.logging.message_delete"},
 	},
+	LogEventThreadChange: {
+		EventType:           LogEventThreadChange,
+		Category:            LogCategoryMessage,
+		RequiredIntentsMask: (1 << 0),
+		RequiresChannel:     true,
+		Toggles:             []string{"runtime_config.disable_message_logs"},
+	},
+	LogEventServerChange: {
+		EventType:           LogEventServerChange,
+		Category:            LogCategoryServer,
+		RequiredIntentsMask: (1 << 0),
+		RequiresChannel:     true,
+	},
+	LogEventVoiceChange: {
+		EventType:           LogEventVoiceChange,
+		Category:            LogCategoryServer,
+		RequiredIntentsMask: (1 << 0),
+		RequiresChannel:     true,
+	},
+	LogEventAssetChange: {
+		EventType:           LogEventAssetChange,
+		Category:            LogCategoryServer,
+		RequiredIntentsMask: (1 << 0),
+		RequiresChannel:     true,
+	},
 	LogEventReactionMetric: {
 		EventType:           LogEventReactionMetric,
 		Category:            LogCategoryReaction,
@@ -218,6 +275,23 @@ func LogEventCapabilities() map[LogEventType]LogEventCapability {
 	return out
 }
 
+// LogRouteDisabled is the files.ChannelsConfig.LogRoutes value that turns
+// an event off whatever other channels are configured.
+const LogRouteDisabled = "disabled"
+
+// RoutableLogEvents returns the events that are sent to a log channel, and
+// so can be given one of their own, sorted by name.
+func RoutableLogEvents() []LogEventType {
+	events := make([]LogEventType, 0, len(logEventCapabilities))
+	for eventType, capability := range logEventCapabilities {
+		if capability.RequiresChannel {
+			events = append(events, eventType)
+		}
+	}
+	slices.Sort(events)
+	return events
+}
+
 // ResolveLogChannel returns the resolved channel ID for an event in a guild.
 // Resolution is deterministic and event-specific.
 func ResolveLogChannel(eventType LogEventType, guildID string, configManager *files.ConfigManager) string {
@@ -228,6 +302,12 @@ func ResolveLogChannel(eventType LogEventType, guildID string, configManager *fi
 	return resolveLogChannelForGuild(eventType, gcfg)
 }
 
+// ResolveGuildLogChannel is ResolveLogChannel for callers that already hold
+// the guild configuration.
+func ResolveGuildLogChannel(eventType LogEventType, gcfg *files.GuildConfig) string {
+	return resolveLogChannelForGuild(eventType, gcfg)
+}
+
 // CheckFeatureEnabled only verifies if the configuration allows the event to be emitted.
 // It DOES NOT check intents or permissions. It should be used by the domain layer to determine if
 // an event should be processed.
@@ -314,11 +394,11 @@ func ValidateLogCapability(state DiscordAdapter, currentIntents uint64, decision
 // gated off, or ("", false) when neither toggle blocks emission.
 func evaluateEventToggle(eventType LogEventType, rc files.RuntimeConfig, features files.ResolvedFeatureToggles) (EmitReason, bool) {
 	switch eventType {
-	case LogEventAvatarChange:
+	case LogEventAvatarChange, LogEventNameChange:
 		if rc.DisableUserLogs {
 			return EmitReasonRuntimeDisableUserLogs, true
 		}
-	case LogEventRoleChange:
+	case LogEventRoleChange, LogEventPresenceChange:
 		if rc.DisableUserLogs {
 			return EmitReasonRuntimeDisableUserLogs, true
 		}
@@ -338,7 +418,7 @@ func evaluateEventToggle(eventType LogEventType, rc files.RuntimeConfig, feature
 		if rc.DisableMessageLogs {
 			return EmitReasonRuntimeDisableMessageLogs, true
 		}
-	case LogEventMessageDelete:
+	case LogEventMessageDelete, LogEventThreadChange:
 		if rc.DisableMessageLogs {
 			return EmitReasonRuntimeDisableMessageLogs, true
 		}
@@ -348,6 +428,8 @@ func evaluateEventToggle(eventType LogEventType, rc files.RuntimeConfig, feature
 		}
 	case LogEventAutomodAction:
 		// No runtime config disable override for automod logs.
+	case LogEventServerChange, LogEventVoiceChange, LogEventAssetChange:
+		// Server, voice and asset changes are only gated by their channel.
 	case LogEventModerationCase:
 		if !rc.ModerationLoggingEnabled() {
 			return EmitReasonRuntimeModerationLoggingOff, true
@@ -393,11 +475,19 @@ func resolveLogChannelForGuild(eventType LogEventType, gcfg *files.GuildConfig)
 		return ""
 	}
 	channels := gcfg.Channels
+	if route := strings.TrimSpace(channels.LogRoutes[string(eventType)]); route != "" {
+		if route == LogRouteDisabled {
+			return ""
+		}
+		return route
+	}
 	switch eventType {
-	case LogEventAvatarChange:
+	case LogEventAvatarChange, LogEventNameChange:
 		return firstNonEmptyChannel(channels.AvatarLogging)
 	case LogEventRoleChange:
 		return firstNonEmptyChannel(channels.RoleUpdate)
+	case LogEventPresenceChange:
+		return firstNonEmptyChannel(channels.PresenceLog)
 	case LogEventMemberJoin:
 		return firstNonEmptyChannel(channels.MemberJoin, channels.MemberLeave)
 	case LogEventMemberLeave:
@@ -406,6 +496,14 @@ func resolveLogChannelForGuild(eventType LogEventType, gcfg *files.GuildConfig)
 		return firstNonEmptyChannel(channels.MessageEdit, channels.MessageDelete)
 	case LogEventMessageDelete:
 		return firstNonEmptyChannel(channels.MessageDelete, channels.MessageEdit)
+	case LogEventThreadChange:
+		return firstNonEmptyChannel(channels.ThreadLogging)
+	case LogEventServerChange:
+		return firstNonEmptyChannel(channels.ServerLog)
+	case LogEventVoiceChange:
+		return firstNonEmptyChannel(channels.VoiceLog, channels.ServerLog)
+	case LogEventAssetChange:
+		return firstNonEmptyChannel(channels.AssetLog, channels.ServerLog)
 	case LogEventAutomodAction:
 		return firstNonEmptyChannel(channels.AutomodAction)
 	case LogEventModerationCase:
@@ -442,6 +540,17 @@ func IsSharedModerationChannel(channelID string, gcfg *files.GuildConfig) bool {
 		gcfg.Channels.MessageDelete,
 		gcfg.Channels.AutomodAction,
 		gcfg.Channels.CleanAction,
+		gcfg.Channels.ThreadLogging,
+		gcfg.Channels.ServerLog,
+		gcfg.Channels.VoiceLog,
+		gcfg.Channels.AssetLog,
+		gcfg.Channels.PresenceLog,
+		gcfg.Channels.CommandAudit,
+	}
+	for eventType, route := range gcfg.Channels.LogRoutes {
+		if eventType != string(LogEventModerationCase) {
+			sharedCandidates = append(sharedCandidates, route)
+		}
 	}
 	for _, candidate := range sharedCandidates {
 		if strings.TrimSpace(candidate) == channelID {
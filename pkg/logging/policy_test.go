@@ -3,6 +3,7 @@ package logging
 import (
 	"fmt"
 	"net/http"
+	"slices"
 	"testing"
 	"time"
 
@@ -33,6 +34,7 @@ func TestFormatUserLabel(t *testing.T) {
 		{"", "12345", "<@12345> (`12345`)"},
 		{"alice", "12345", "**alice** (<@12345>, `12345`)"},
 		{" alice ", " 12345 ", "**alice** (<@12345>, `12345`)"},
+		{"__al*ce__", "12345", `**\_\_al\*ce\_\_** (<@12345>, ` + "`12345`)"},
 	}
 
 	for _, tt := range tests {
@@ -207,6 +209,7 @@ func TestResolveLogChannel(t *testing.T) {
 	// Test resolutions for all events
 	eventsToChannels := map[LogEventType]string{
 		LogEventAvatarChange:    "avatar_ch",
+		LogEventNameChange:      "avatar_ch",
 		LogEventRoleChange:      "role_ch",
 		LogEventMemberJoin:      "join_ch",
 		LogEventMemberLeave:     "leave_ch",
@@ -226,6 +229,48 @@ func TestResolveLogChannel(t *testing.T) {
 	}
 }
 
+func TestResolveGuildLogChannel_Routes(t *testing.T) {
+	t.Parallel()
+	gcfg := &files.GuildConfig{Channels: files.ChannelsConfig{
+		AvatarLogging:  "avatar_ch",
+		MemberJoin:     "join_ch",
+		MemberLeave:    "leave_ch",
+		ModerationCase: "mod_ch",
+		LogRoutes: map[string]string{
+			string(LogEventNameChange):  "names_ch",
+			string(LogEventMemberLeave): LogRouteDisabled,
+		},
+	}}
+
+	for evt, expected := range map[LogEventType]string{
+		LogEventAvatarChange: "avatar_ch",
+		LogEventNameChange:   "names_ch",
+		LogEventMemberJoin:   "join_ch",
+		// A disabled event does not fall back to the join channel.
+		LogEventMemberLeave: "",
+	} {
+		if got := ResolveGuildLogChannel(evt, gcfg); got != expected {
+			t.Errorf("ResolveGuildLogChannel(%s) = %q; expected %q", evt, got, expected)
+		}
+	}
+
+	gcfg.Channels.LogRoutes[string(LogEventRoleChange)] = "mod_ch"
+	if !IsSharedModerationChannel("mod_ch", gcfg) {
+		t.Error("expected a routed event to share the moderation channel")
+	}
+}
+
+func TestRoutableLogEvents(t *testing.T) {
+	t.Parallel()
+	events := RoutableLogEvents()
+	if !slices.IsSorted(events) {
+		t.Fatalf("expected sorted events, got %v", events)
+	}
+	if slices.Contains(events, LogEventReactionMetric) || !slices.Contains(events, LogEventThreadChange) {
+		t.Fatalf("expected only events sent to a channel, got %v", events)
+	}
+}
+
 func TestCheckFeatureEnabled_Errors(t *testing.T) {
 	t.Parallel()
 	// Unknown event
@@ -267,6 +312,7 @@ func TestCheckFeatureEnabled_Toggles(t *testing.T) {
 		expected  EmitReason
 	}{
 		{LogEventAvatarChange, files.RuntimeConfig{DisableUserLogs: true}, EmitReasonRuntimeDisableUserLogs},
+		{LogEventNameChange, files.RuntimeConfig{DisableUserLogs: true}, EmitReasonRuntimeDisableUserLogs},
 		{LogEventRoleChange, files.RuntimeConfig{DisableUserLogs: true}, EmitReasonRuntimeDisableUserLogs},
 		{LogEventMemberJoin, files.RuntimeConfig{DisableEntryExitLogs: true}, EmitReasonRuntimeDisableEntryExitLogs},
 		{LogEventMemberLeave, files.RuntimeConfig{DisableEntryExitLogs: true}, EmitReasonRuntimeDisableEntryExitLogs},
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// RouteSubject describes a participant of a log event as far as notification
+// routing is concerned.
+type RouteSubject struct {
+	UserID  string
+	IsBot   bool
+	RoleIDs []string
+}
+
+// RouteContext carries the participants of a log event. A nil subject is
+// unknown, and any route condition on it does not match.
+type RouteContext struct {
+	Target *RouteSubject
+	Actor  *RouteSubject
+}
+
+// RouteLogChannel returns the channel of the first notification route of gcfg
+// that matches eventType and route, or false when none does.
+func RouteLogChannel(eventType LogEventType, gcfg *files.GuildConfig, route RouteContext) (string, bool) {
+	if gcfg == nil {
+		return "", false
+	}
+	for _, candidate := range gcfg.NotificationRoutes {
+		channelID := strings.TrimSpace(candidate.ChannelID)
+		if channelID == "" || !candidate.MatchesEvent(string(eventType)) {
+			continue
+		}
+		if routeConditionsHold(candidate, route) {
+			return channelID, true
+		}
+	}
+	return "", false
+}
+
+// ApplyNotificationRoutes points an enabled, channel-bound decision at the
+// channel of the first matching notification route. Routes only redirect
+// events that would be emitted anyway; they never enable a disabled one.
+func ApplyNotificationRoutes(decision EmitDecision, gcfg *files.GuildConfig, route RouteContext) EmitDecision {
+	if !decision.Enabled || !decision.Capability.RequiresChannel {
+		return decision
+	}
+	if channelID, ok := RouteLogChannel(decision.EventType, gcfg, route); ok {
+		decision.ChannelID = channelID
+	}
+	return decision
+}
+
+func routeConditionsHold(candidate files.NotificationRouteConfig, route RouteContext) bool {
+	if len(candidate.TargetRoleIDs) > 0 {
+		if route.Target == nil || !slices.ContainsFunc(candidate.TargetRoleIDs, func(roleID string) bool {
+			return slices.Contains(route.Target.RoleIDs, strings.TrimSpace(roleID))
+		}) {
+			return false
+		}
+	}
+	if candidate.TargetIsBot != nil && (route.Target == nil || route.Target.IsBot != *candidate.TargetIsBot) {
+		return false
+	}
+	if candidate.ActorIsBot != nil && (route.Actor == nil || route.Actor.IsBot != *candidate.ActorIsBot) {
+		return false
+	}
+	return true
+}
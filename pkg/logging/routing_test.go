@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+func TestRouteLogChannel(t *testing.T) {
+	t.Parallel()
+
+	yes := true
+	gcfg := &files.GuildConfig{
+		Channels: files.ChannelsConfig{RoleUpdate: "100"},
+		NotificationRoutes: []files.NotificationRouteConfig{
+			{TargetRoleIDs: []string{"vip"}, ChannelID: "200"},
+			{Events: []string{string(LogEventMessageDelete)}, ActorIsBot: &yes, ChannelID: "300"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		eventType LogEventType
+		route     RouteContext
+		want      string
+		wantOK    bool
+	}{
+		{
+			name:      "target has role",
+			eventType: LogEventRoleChange,
+			route:     RouteContext{Target: &RouteSubject{UserID: "1", RoleIDs: []string{"other", "vip"}}},
+			want:      "200",
+			wantOK:    true,
+		},
+		{
+			name:      "target without role",
+			eventType: LogEventRoleChange,
+			route:     RouteContext{Target: &RouteSubject{UserID: "1"}},
+		},
+		{
+			name:      "bot actor on listed event",
+			eventType: LogEventMessageDelete,
+			route:     RouteContext{Actor: &RouteSubject{UserID: "2", IsBot: true}},
+			want:      "300",
+			wantOK:    true,
+		},
+		{
+			name:      "bot actor on other event",
+			eventType: LogEventMessageEdit,
+			route:     RouteContext{Actor: &RouteSubject{UserID: "2", IsBot: true}},
+		},
+		{
+			name:      "unknown actor",
+			eventType: LogEventMessageDelete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := RouteLogChannel(tt.eventType, gcfg, tt.route)
+			if got != tt.want || ok != tt.wantOK {
+				t.Fatalf("RouteLogChannel() = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestApplyNotificationRoutesKeepsDisabledDecisions(t *testing.T) {
+	t.Parallel()
+
+	gcfg := &files.GuildConfig{NotificationRoutes: []files.NotificationRouteConfig{{ChannelID: "200"}}}
+	decision := EmitDecision{
+		EventType:  LogEventRoleChange,
+		Reason:     EmitReasonNoChannelConfigured,
+		Capability: logEventCapabilities[LogEventRoleChange],
+	}
+	if got := ApplyNotificationRoutes(decision, gcfg, RouteContext{}); got.ChannelID != "" {
+		t.Fatalf("expected disabled decision to stay unrouted, got %q", got.ChannelID)
+	}
+
+	decision.Enabled = true
+	decision.ChannelID = "100"
+	if got := ApplyNotificationRoutes(decision, gcfg, RouteContext{}); got.ChannelID != "200" {
+		t.Fatalf("expected enabled decision to be routed, got %q", got.ChannelID)
+	}
+}
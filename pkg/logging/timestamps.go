@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"strconv"
+	"time"
+)
+
+// TimestampStyle selects how Discord renders a timestamp tag in the
+// reader's own locale and time zone.
+type TimestampStyle byte
+
+// Discord timestamp styles, e.g. for 16 October 2026 12:00:
+// TimestampShortTime "12:00", TimestampLongTime "12:00:00",
+// TimestampShortDate "16/10/2026", TimestampLongDate "16 October 2026",
+// TimestampShortDateTime "16 October 2026 12:00",
+// TimestampLongDateTime "Friday, 16 October 2026 12:00" and
+// TimestampRelative "in 2 hours".
+const (
+	TimestampShortTime     TimestampStyle = 't'
+	TimestampLongTime      TimestampStyle = 'T'
+	TimestampShortDate     TimestampStyle = 'd'
+	TimestampLongDate      TimestampStyle = 'D'
+	TimestampShortDateTime TimestampStyle = 'f'
+	TimestampLongDateTime  TimestampStyle = 'F'
+	TimestampRelative      TimestampStyle = 'R'
+)
+
+// DiscordTimestamp returns the <t:unix:style> tag for t.
+func DiscordTimestamp(t time.Time, style TimestampStyle) string {
+	return "<t:" + strconv.FormatInt(t.Unix(), 10) + ":" + string(style) + ">"
+}
+
+// FormatLocalTime renders t in loc for text Discord does not localize, such
+// as exports and transcripts. The result is RFC 3339, so it stays sortable
+// and carries its offset. A nil loc is UTC, and the zero time is empty.
+func FormatLocalTime(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format(time.RFC3339)
+}
@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiscordTimestamp(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	if got := DiscordTimestamp(at, TimestampRelative); got != "<t:1792152000:R>" {
+		t.Fatalf("DiscordTimestamp = %q", got)
+	}
+	if got := DiscordTimestamp(at.In(time.FixedZone("X", 3600)), TimestampLongDateTime); got != "<t:1792152000:F>" {
+		t.Fatalf("the tag should not depend on the zone of t, got %q", got)
+	}
+}
+
+func TestFormatLocalTime(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	tests := []struct {
+		loc  *time.Location
+		want string
+	}{
+		{nil, "2026-10-16T12:00:00Z"},
+		{berlin, "2026-10-16T14:00:00+02:00"},
+	}
+	for _, tt := range tests {
+		if got := FormatLocalTime(at, tt.loc); got != tt.want {
+			t.Errorf("FormatLocalTime(%v) = %q, want %q", tt.loc, got, tt.want)
+		}
+	}
+	if got := FormatLocalTime(time.Time{}, berlin); got != "" {
+		t.Errorf("the zero time should render empty, got %q", got)
+	}
+}
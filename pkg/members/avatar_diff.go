@@ -0,0 +1,126 @@
+package members
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+)
+
+// AvatarSnapshotStore is the subset of Repository used to detect avatar
+// changes by comparing live members against their persisted snapshots.
+type AvatarSnapshotStore interface {
+	// GetAvatars returns the stored avatar hashes of userIDs, keyed by user
+	// ID. Users without a stored avatar are absent.
+	GetAvatars(ctx context.Context, guildID string, userIDs []string) (map[string]string, error)
+	UpsertGuildMemberSnapshotsContext(ctx context.Context, guildID string, snapshots []Snapshot, updatedAt time.Time) error
+}
+
+// LiveAvatar is a member's current avatar as reported by Discord.
+type LiveAvatar struct {
+	UserID     string
+	Username   string
+	GlobalName string
+	Nick       string
+	// Discriminator is "0" for accounts on unique usernames.
+	Discriminator string
+	Bot           bool
+	AvatarHash    string
+	// NickUnknown marks an entry built from a user rather than a member,
+	// which carries no nickname.
+	NickUnknown bool
+}
+
+// names returns the names of the live member.
+func (m LiveAvatar) names() MemberNames {
+	return MemberNames{Username: m.Username, GlobalName: m.GlobalName, Nick: m.Nick}
+}
+
+// AvatarDiffResult counts the outcome of an avatar diff pass.
+type AvatarDiffResult struct {
+	Checked int
+	Changed int
+	Seeded  int
+}
+
+// DiffAvatarSnapshots compares live against the avatars stored for guildID
+// and reports every difference to sink. It is the detection path used when
+// the Presences intent is not granted, so avatar changes are only seen by
+// polling or from member and user update events.
+//
+// The stored avatars of all live members are loaded in one query. Members
+// without a stored avatar are recorded without emitting anything: there is
+// no previous value to diff against. Snapshots are written before the sink
+// is called so a failed write never causes the same change to be reported
+// twice.
+func DiffAvatarSnapshots(ctx context.Context, store AvatarSnapshotStore, sink MemberSink, guildID string, live iter.Seq2[LiveAvatar, error], at time.Time) (AvatarDiffResult, error) {
+	var result AvatarDiffResult
+	if store == nil || guildID == "" {
+		return result, nil
+	}
+
+	var listed []LiveAvatar
+	for member, err := range live {
+		if err != nil {
+			return result, fmt.Errorf("DiffAvatarSnapshots: list live members: %w", err)
+		}
+		if err := ctx.Err(); err != nil {
+			return result, fmt.Errorf("DiffAvatarSnapshots: %w", err)
+		}
+		if member.UserID == "" {
+			continue
+		}
+		listed = append(listed, member)
+	}
+	if len(listed) == 0 {
+		return result, nil
+	}
+	userIDs := make([]string, len(listed))
+	for i, member := range listed {
+		userIDs[i] = member.UserID
+	}
+	stored, err := store.GetAvatars(ctx, guildID, userIDs)
+	if err != nil {
+		return result, fmt.Errorf("DiffAvatarSnapshots: load avatars: %w", err)
+	}
+
+	var snapshots []Snapshot
+	var changes []AvatarUpdateIntent
+	for _, member := range listed {
+		result.Checked++
+		previous, ok := stored[member.UserID]
+		if ok && previous == member.AvatarHash {
+			continue
+		}
+
+		snapshots = append(snapshots, Snapshot{UserID: member.UserID, HasAvatar: true, AvatarHash: member.AvatarHash})
+		if !ok {
+			result.Seeded++
+			continue
+		}
+		changes = append(changes, AvatarUpdateIntent{
+			GuildID:       guildID,
+			UserID:        member.UserID,
+			Username:      member.Username,
+			GlobalName:    member.GlobalName,
+			Nick:          member.Nick,
+			Discriminator: member.Discriminator,
+			Bot:           member.Bot,
+			OldAvatarHash: previous,
+			NewAvatarHash: member.AvatarHash,
+		})
+	}
+
+	if len(snapshots) > 0 {
+		if err := store.UpsertGuildMemberSnapshotsContext(ctx, guildID, snapshots, at); err != nil {
+			return result, fmt.Errorf("DiffAvatarSnapshots: %w", err)
+		}
+	}
+	for _, change := range changes {
+		if sink != nil {
+			sink.OnAvatarUpdate(ctx, change)
+		}
+		result.Changed++
+	}
+	return result, nil
+}
@@ -0,0 +1,80 @@
+package members
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeAvatarStore struct {
+	avatars map[string]string
+	written []Snapshot
+	loads   int
+}
+
+func (f *fakeAvatarStore) GetAvatars(ctx context.Context, guildID string, userIDs []string) (map[string]string, error) {
+	f.loads++
+	out := make(map[string]string)
+	for _, id := range userIDs {
+		if hash, ok := f.avatars[id]; ok {
+			out[id] = hash
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAvatarStore) UpsertGuildMemberSnapshotsContext(ctx context.Context, guildID string, snapshots []Snapshot, updatedAt time.Time) error {
+	f.written = append(f.written, snapshots...)
+	return nil
+}
+
+type recordingAvatarSink struct {
+	NopMemberSink
+	updates []AvatarUpdateIntent
+}
+
+func (s *recordingAvatarSink) OnAvatarUpdate(ctx context.Context, intent AvatarUpdateIntent) {
+	s.updates = append(s.updates, intent)
+}
+
+func TestDiffAvatarSnapshots(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeAvatarStore{avatars: map[string]string{
+		"same":    "a1",
+		"changed": "old",
+	}}
+	sink := &recordingAvatarSink{}
+	live := func(yield func(LiveAvatar, error) bool) {
+		for _, m := range []LiveAvatar{
+			{UserID: "same", AvatarHash: "a1"},
+			{UserID: "changed", Username: "alice", AvatarHash: "new"},
+			{UserID: "unseen", AvatarHash: "first"},
+		} {
+			if !yield(m, nil) {
+				return
+			}
+		}
+	}
+
+	result, err := DiffAvatarSnapshots(context.Background(), store, sink, "g1", live, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Checked != 3 || result.Changed != 1 || result.Seeded != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if store.loads != 1 {
+		t.Fatalf("expected the stored avatars to be loaded in one query, got %d", store.loads)
+	}
+	if len(sink.updates) != 1 {
+		t.Fatalf("expected one avatar update, got %+v", sink.updates)
+	}
+	got := sink.updates[0]
+	if got.GuildID != "g1" || got.UserID != "changed" || got.Username != "alice" || got.OldAvatarHash != "old" || got.NewAvatarHash != "new" {
+		t.Fatalf("unexpected avatar update: %+v", got)
+	}
+	if len(store.written) != 2 {
+		t.Fatalf("expected changed and unseen members to be stored, got %+v", store.written)
+	}
+}
@@ -1,35 +1,62 @@
 package members
 
-import "time"
+import (
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+)
 
 // MemberJoinIntent represents a user joining a guild.
 type MemberJoinIntent struct {
-	GuildID    string
-	UserID     string
-	Username   string
-	Bot        bool
-	AvatarHash string
-	RoleIDs    []string
-	JoinedAt   time.Time
+	GuildID       string
+	UserID        string
+	Username      string
+	GlobalName    string
+	Nick          string
+	Discriminator string
+	Bot           bool
+	AvatarHash    string
+	RoleIDs       []string
+	JoinedAt      time.Time
+}
+
+// Names returns the names the user is known by in the guild.
+func (i MemberJoinIntent) Names() logging.UserNames {
+	return logging.UserNames{Username: i.Username, Discriminator: i.Discriminator, GlobalName: i.GlobalName, Nick: i.Nick}
 }
 
 // MemberLeaveIntent represents a user leaving a guild.
 type MemberLeaveIntent struct {
-	GuildID    string
-	UserID     string
-	Username   string
-	Bot        bool
-	AvatarHash string
+	GuildID       string
+	UserID        string
+	Username      string
+	GlobalName    string
+	Discriminator string
+	Bot           bool
+	AvatarHash    string
+}
+
+// Names returns the names the user is known by in the guild.
+func (i MemberLeaveIntent) Names() logging.UserNames {
+	return logging.UserNames{Username: i.Username, Discriminator: i.Discriminator, GlobalName: i.GlobalName}
 }
 
 // RoleUpdateIntent represents a role update for a member.
 type RoleUpdateIntent struct {
-	GuildID      string
-	UserID       string
-	Username     string
-	Bot          bool
-	AddedRoles   []string
-	RemovedRoles []string
+	GuildID       string
+	UserID        string
+	Username      string
+	GlobalName    string
+	Nick          string
+	Discriminator string
+	Bot           bool
+	AddedRoles    []string
+	RemovedRoles  []string
+}
+
+// Names returns the names the user is known by in the guild.
+func (i RoleUpdateIntent) Names() logging.UserNames {
+	return logging.UserNames{Username: i.Username, Discriminator: i.Discriminator, GlobalName: i.GlobalName, Nick: i.Nick}
 }
 
 // AvatarUpdateIntent represents a change in the user's avatar.
@@ -37,11 +64,34 @@ type AvatarUpdateIntent struct {
 	GuildID       string
 	UserID        string
 	Username      string
+	GlobalName    string
+	Nick          string
+	Discriminator string
 	Bot           bool
 	OldAvatarHash string
 	NewAvatarHash string
 }
 
+// Names returns the names the user is known by in the guild.
+func (i AvatarUpdateIntent) Names() logging.UserNames {
+	return logging.UserNames{Username: i.Username, Discriminator: i.Discriminator, GlobalName: i.GlobalName, Nick: i.Nick}
+}
+
+// NameUpdateIntent represents a change in the names a member goes by.
+type NameUpdateIntent struct {
+	GuildID       string
+	UserID        string
+	Discriminator string
+	Bot           bool
+	Old           MemberNames
+	New           MemberNames
+}
+
+// Names returns the names the user is known by in the guild now.
+func (i NameUpdateIntent) Names() logging.UserNames {
+	return logging.UserNames{Username: i.New.Username, Discriminator: i.Discriminator, GlobalName: i.New.GlobalName, Nick: i.New.Nick}
+}
+
 // ModerationActionIntent represents an action applied to a member.
 type ModerationActionIntent struct {
 	GuildID        string
@@ -55,12 +105,133 @@ type ModerationActionIntent struct {
 
 // MemberUpdateIntent represents a raw member update event for ingestion.
 type MemberUpdateIntent struct {
-	GuildID    string
-	UserID     string
-	Username   string
-	Bot        bool
-	RoleIDs    []string
-	AvatarHash string
-	OldRoleIDs []string
-	OldAvatar  string
+	GuildID       string
+	UserID        string
+	Username      string
+	GlobalName    string
+	Nick          string
+	Discriminator string
+	Bot           bool
+	RoleIDs       []string
+	AvatarHash    string
+	OldRoleIDs    []string
+	OldAvatar     string
+}
+
+// Names returns the names the user is known by in the guild.
+func (i MemberUpdateIntent) Names() logging.UserNames {
+	return logging.UserNames{Username: i.Username, Discriminator: i.Discriminator, GlobalName: i.GlobalName, Nick: i.Nick}
+}
+
+// RoleSettings are the settings of a role that its update log compares: its
+// name, how it shows next to member names and what it grants.
+type RoleSettings struct {
+	// Name is what the role is called.
+	Name string
+	// Color is the RGB color of the role; 0 leaves names uncolored.
+	Color int
+	// IconURL points at the role icon image, if it has one.
+	IconURL string
+	// Emoji is the unicode emoji shown in place of an icon.
+	Emoji string
+	// Permissions are the permission bits the role grants.
+	Permissions uint64
+	// Hoist shows the role's members separately in the member list.
+	Hoist bool
+	// Mentionable lets anyone mention the role.
+	Mentionable bool
+}
+
+// RoleSettingsIntent represents a guild role's settings changing. ActorID
+// is empty when the audit log did not name who changed it.
+type RoleSettingsIntent struct {
+	GuildID string
+	RoleID  string
+	Name    string
+	ActorID string
+	Before  RoleSettings
+	After   RoleSettings
+}
+
+// VoiceAction is what happened in a guild's voice channels.
+type VoiceAction string
+
+const (
+	VoiceStageStarted        VoiceAction = "stage_started"
+	VoiceStageEnded          VoiceAction = "stage_ended"
+	VoiceMembersMoved        VoiceAction = "members_moved"
+	VoiceMembersDisconnected VoiceAction = "members_disconnected"
+)
+
+// VoiceIntent represents a stage starting or ending, or a moderator moving
+// or disconnecting members from voice. Moves and disconnects come from the
+// audit log, which only counts the members involved.
+type VoiceIntent struct {
+	GuildID string
+	// ChannelID is the stage channel, or the channel members were moved to.
+	// It is empty for disconnects.
+	ChannelID string
+	ActorID   string
+	Action    VoiceAction
+	// Topic is the stage's topic.
+	Topic string
+	// Count is how many members were moved or disconnected.
+	Count int
+}
+
+// AssetKind is the kind of guild asset an AssetIntent is about.
+type AssetKind string
+
+const (
+	AssetSticker AssetKind = "sticker"
+	AssetSound   AssetKind = "sound"
+)
+
+// AssetAction is what happened to a guild asset.
+type AssetAction string
+
+const (
+	AssetCreated AssetAction = "created"
+	AssetUpdated AssetAction = "updated"
+	AssetDeleted AssetAction = "deleted"
+)
+
+// AssetIntent represents a sticker or soundboard sound being uploaded, edited
+// or deleted. ActorID is whoever did it, the uploader for new assets.
+type AssetIntent struct {
+	GuildID string
+	AssetID string
+	Kind    AssetKind
+	Action  AssetAction
+	ActorID string
+	// Name is the asset's name; PreviousName is set when an update renamed
+	// it.
+	Name         string
+	PreviousName string
+	// ImageURL shows a sticker. It is empty for sounds and for stickers
+	// Discord cannot render as an image.
+	ImageURL string
+}
+
+// PresenceActivity is one of a member's activities other than the custom
+// status. Verb is how Discord introduces it: "Playing", "Streaming",
+// "Listening to", "Watching" or "Competing in".
+type PresenceActivity struct {
+	Verb string
+	Name string
+}
+
+// PresenceIntent represents a watched member's custom status or activities
+// changing. The Previous fields hold what was last reported, which may be
+// several changes back when updates were coalesced.
+type PresenceIntent struct {
+	GuildID  string
+	UserID   string
+	Username string
+	Bot      bool
+
+	CustomStatus         string
+	PreviousCustomStatus string
+	Activities           []PresenceActivity
+	PreviousActivities   []PresenceActivity
 }
@@ -0,0 +1,168 @@
+package members
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
+	"github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/service"
+)
+
+// discordEpochMs is the start of Discord snowflake time, in Unix
+// milliseconds.
+const discordEpochMs = 1420070400000
+
+// maxCompiledNamePatterns bounds the compiled name pattern cache; it is
+// emptied when edited join gates leave it full of stale patterns.
+const maxCompiledNamePatterns = 512
+
+// namePatterns caches the compiled blocked name patterns of join gates by
+// source, so joins do not compile them again. Invalid patterns are cached as
+// nil.
+var namePatterns = struct {
+	mu       sync.Mutex
+	compiled map[string]*regexp.Regexp
+}{compiled: make(map[string]*regexp.Regexp)}
+
+// namePattern returns the compiled, case-insensitive form of pattern, or nil
+// when it does not compile.
+func namePattern(pattern string) *regexp.Regexp {
+	namePatterns.mu.Lock()
+	defer namePatterns.mu.Unlock()
+	if re, ok := namePatterns.compiled[pattern]; ok {
+		return re
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		re = nil
+	}
+	if len(namePatterns.compiled) >= maxCompiledNamePatterns {
+		namePatterns.compiled = make(map[string]*regexp.Regexp)
+	}
+	namePatterns.compiled[pattern] = re
+	return re
+}
+
+// JoinGateFailure returns why m fails the join gate of cfg, or "" when it
+// passes. Bypassed users always pass.
+func JoinGateFailure(cfg files.JoinGateConfig, m MemberJoinIntent, now time.Time) string {
+	if !cfg.Enabled || slices.Contains(cfg.BypassUserIDs, m.UserID) {
+		return ""
+	}
+	if minAge := cfg.MinAccountAge(); minAge > 0 {
+		if created, ok := accountCreatedAt(m.UserID); ok && now.Sub(created) < minAge {
+			return fmt.Sprintf("account younger than %d days", cfg.MinAccountAgeDays)
+		}
+	}
+	if cfg.RequireAvatar && m.AvatarHash == "" {
+		return "no avatar"
+	}
+	for _, pattern := range cfg.BlockedNamePatterns {
+		re := namePattern(pattern)
+		if re == nil {
+			continue
+		}
+		for _, name := range []string{m.Username, m.GlobalName, m.Nick} {
+			if name != "" && re.MatchString(name) {
+				return fmt.Sprintf("name %q matches a blocked pattern", name)
+			}
+		}
+	}
+	return ""
+}
+
+// accountCreatedAt reads when the account with userID was created from its
+// snowflake.
+func accountCreatedAt(userID string) (time.Time, bool) {
+	snowflake, err := strconv.ParseUint(userID, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(int64(snowflake>>22) + discordEpochMs), true
+}
+
+// gatesJoins reports whether this service screens the joins of guild, which
+// falls to the bot that moderates it.
+func (mes *MemberEventService) gatesJoins(guild *files.GuildConfig) bool {
+	if guild == nil || !guild.JoinGate.Enabled {
+		return false
+	}
+	if files.NormalizeBotInstanceID(mes.botInstanceID) == "" {
+		return true
+	}
+	resolvedID, _ := files.ResolveFeatureBotInstanceID(*guild, "moderation")
+	return resolvedID == mes.botInstanceID
+}
+
+// screenJoin applies the join gate of guild to m. It reports whether the
+// member was kicked or quarantined, in which case the rest of the join
+// handling is skipped or limited. A guild in test mode only gets the log of
+// what the gate would have done.
+func (mes *MemberEventService) screenJoin(ctx context.Context, guild *files.GuildConfig, m MemberJoinIntent) (kicked, quarantined bool) {
+	failure := JoinGateFailure(guild.JoinGate, m, time.Now())
+	if failure == "" {
+		return false, false
+	}
+	action := guild.JoinGate.EffectiveAction()
+	reason := "Join gate: " + failure
+
+	var err error
+	switch {
+	case guild.TestMode:
+		ctx = moderation.WithTestMode(ctx)
+	case action == files.JoinGateActionKick:
+		err = mes.kickMember(ctx, m.GuildID, m.UserID, reason)
+		kicked = err == nil
+	case action == files.JoinGateActionQuarantine:
+		err = mes.guildMemberRoleAdd(ctx, m.GuildID, m.UserID, guild.JoinGate.QuarantineRoleID)
+		quarantined = err == nil
+	}
+	if err != nil {
+		mes.logger.Warn("Mitigated service degradation: Join gate action failed",
+			slog.String("guildID", m.GuildID),
+			slog.String("userID", m.UserID),
+			slog.String("action", action),
+			slog.String("error", err.Error()),
+		)
+		action = files.JoinGateActionLog
+	}
+	mes.logger.Info("Architectural state transition: Member failed the join gate",
+		slog.String("guildID", m.GuildID),
+		slog.String("userID", m.UserID),
+		slog.String("action", action),
+		slog.String("reason", failure),
+		slog.Bool("test_mode", guild.TestMode),
+	)
+
+	if mes.sink != nil {
+		var botID string
+		if mes.discordAdapter != nil {
+			botID, _ = mes.discordAdapter.Me()
+		}
+		mes.sink.OnModerationAction(ctx, ModerationActionIntent{
+			GuildID:        m.GuildID,
+			ActionType:     "Join gate " + action,
+			TargetUserID:   m.UserID,
+			TargetUsername: m.Username,
+			Reason:         failure,
+			ModeratorID:    botID,
+		})
+	}
+	return kicked, quarantined
+}
+
+func (mes *MemberEventService) kickMember(ctx context.Context, guildID, userID, reason string) error {
+	if mes.discordAdapter == nil {
+		return fmt.Errorf("discord adapter is nil")
+	}
+	return service.RunErrWithTimeoutContext(ctx, service.DependencyTimeout, func(runCtx context.Context) error {
+		return mes.discordAdapter.Kick(runCtx, guildID, userID, reason)
+	})
+}
@@ -0,0 +1,96 @@
+package members
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/config"
+	"github.com/small-frappuccino/discordcore/pkg/files"
+)
+
+// snowflakeAt builds a user ID created at t.
+func snowflakeAt(t time.Time) string {
+	return strconv.FormatUint(uint64(t.UnixMilli()-discordEpochMs)<<22, 10)
+}
+
+func TestJoinGateFailure(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cfg := files.JoinGateConfig{
+		Enabled:             true,
+		MinAccountAgeDays:   7,
+		RequireAvatar:       true,
+		BlockedNamePatterns: []string{`free\s*nitro`},
+		BypassUserIDs:       []string{snowflakeAt(now.Add(-time.Hour))},
+	}
+	old := snowflakeAt(now.Add(-30 * 24 * time.Hour))
+
+	for name, tc := range map[string]struct {
+		intent MemberJoinIntent
+		fails  bool
+	}{
+		"passes":        {MemberJoinIntent{UserID: old, Username: "alice", AvatarHash: "a"}, false},
+		"young account": {MemberJoinIntent{UserID: snowflakeAt(now.Add(-48 * time.Hour)), Username: "bob", AvatarHash: "a"}, true},
+		"no avatar":     {MemberJoinIntent{UserID: old, Username: "carol"}, true},
+		"blocked name":  {MemberJoinIntent{UserID: old, Username: "dave", GlobalName: "FREE Nitro", AvatarHash: "a"}, true},
+		"bypassed":      {MemberJoinIntent{UserID: cfg.BypassUserIDs[0], Username: "erin"}, false},
+	} {
+		if got := JoinGateFailure(cfg, tc.intent, now) != ""; got != tc.fails {
+			t.Errorf("%s: failed = %t, want %t", name, got, tc.fails)
+		}
+	}
+	cfg.Enabled = false
+	if JoinGateFailure(cfg, MemberJoinIntent{UserID: old}, now) != "" {
+		t.Error("a disabled gate should let everyone through")
+	}
+}
+
+func TestMemberEventService_JoinGate(t *testing.T) {
+	t.Parallel()
+	store := &config.MemoryConfigStore{}
+	_ = store.Save(&files.BotConfig{Guilds: []files.GuildConfig{
+		{GuildID: "1", JoinGate: files.JoinGateConfig{Enabled: true, RequireAvatar: true, Action: files.JoinGateActionKick}},
+		{GuildID: "2", JoinGate: files.JoinGateConfig{Enabled: true, RequireAvatar: true, Action: files.JoinGateActionQuarantine, QuarantineRoleID: "50"}},
+		{GuildID: "3", TestMode: true, JoinGate: files.JoinGateConfig{Enabled: true, RequireAvatar: true, Action: files.JoinGateActionKick}},
+	}})
+	mgr := files.NewConfigManagerWithStore(store, nil)
+	if err := mgr.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	sink := &mockMemberSink{}
+	adapter := &mockDiscordAdapter{}
+	svc := NewMemberEventServiceForBot(EventServiceDeps{
+		ConfigManager:  mgr,
+		Sink:           sink,
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		DiscordAdapter: adapter,
+	})
+
+	svc.IngestGuildMemberAdd(context.Background(), MemberJoinIntent{GuildID: "1", UserID: "42", Username: "faceless"})
+	svc.IngestGuildMemberAdd(context.Background(), MemberJoinIntent{GuildID: "1", UserID: "43", Username: "pictured", AvatarHash: "a"})
+	if len(adapter.kicked) != 1 || adapter.kicked[0] != "42" {
+		t.Fatalf("expected only the member without an avatar to be kicked, got %v", adapter.kicked)
+	}
+
+	svc.IngestGuildMemberAdd(context.Background(), MemberJoinIntent{GuildID: "2", UserID: "44", Username: "faceless"})
+	if adapter.addRoleCalls != 1 {
+		t.Fatalf("expected the quarantine role to be given, got %d role additions", adapter.addRoleCalls)
+	}
+
+	// A guild in test mode only logs what the gate would do.
+	svc.IngestGuildMemberAdd(context.Background(), MemberJoinIntent{GuildID: "3", UserID: "45", Username: "faceless"})
+	if len(adapter.kicked) != 1 {
+		t.Fatalf("expected no kick in test mode, got %v", adapter.kicked)
+	}
+
+	if len(sink.moderationActions) != 3 {
+		t.Fatalf("expected every gate action to be logged, got %+v", sink.moderationActions)
+	}
+	if a := sink.moderationActions[0]; a.ActionType != "Join gate kick" || a.Reason != "no avatar" || a.ModeratorID != "99999" {
+		t.Fatalf("unexpected logged action %+v", a)
+	}
+}
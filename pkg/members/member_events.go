@@ -27,6 +27,7 @@ type DiscordAdapter interface {
 	MemberJoinedAt(ctx context.Context, guildID, userID string) (time.Time, error)
 	AddRole(ctx context.Context, guildID, userID, roleID string) error
 	RemoveRole(ctx context.Context, guildID, userID, roleID string) error
+	Kick(ctx context.Context, guildID, userID, reason string) error
 }
 
 // MemberEventService manages member join/leave events
@@ -190,6 +191,15 @@ func (mes *MemberEventService) IngestGuildMemberAdd(ctx context.Context, m Membe
 	if mes.configManager == nil {
 		return
 	}
+	// Members the join gate kicks are gone before anything else applies to
+	// them; quarantined members are not given the automatic role.
+	quarantined := false
+	if guild := mes.configManager.GuildConfig(m.GuildID); mes.gatesJoins(guild) {
+		var kicked bool
+		if kicked, quarantined = mes.screenJoin(ctx, guild, m); kicked {
+			return
+		}
+	}
 	if !mes.handlesGuild(m.GuildID) {
 		return
 	}
@@ -203,7 +213,7 @@ func (mes *MemberEventService) IngestGuildMemberAdd(ctx context.Context, m Membe
 	}
 
 	// Composite automatic role assignment (per-guild config).
-	if mes.discordAdapter != nil && guildConfig.Roles.AutoAssignment.Enabled {
+	if !quarantined && mes.discordAdapter != nil && guildConfig.Roles.AutoAssignment.Enabled {
 		targetRoleID := guildConfig.Roles.AutoAssignment.TargetRoleID
 		required := guildConfig.Roles.AutoAssignment.RequiredRoles
 		if targetRoleID != "" && len(required) >= 2 {
@@ -217,23 +227,10 @@ func (mes *MemberEventService) IngestGuildMemberAdd(ctx context.Context, m Membe
 		}
 	}
 
-	// Logging is now delegated to Sink
-	emit := logging.CheckFeatureEnabled(mes.configManager, logging.LogEventMemberJoin, m.GuildID)
-	if !emit.Enabled {
-		if emit.Reason == logging.EmitReasonNoChannelConfigured {
-			mes.logger.Info("User entry/leave channel not configured for guild, member join notification not sent", "guildID", m.GuildID, "userID", m.UserID)
-		} else {
-			mes.logger.Debug("Member join notification suppressed by policy", "guildID", m.GuildID, "userID", m.UserID, "reason", emit.Reason)
-		}
-		return
-	}
-
-	// Calculate how long the account has existed
-	accountAge := mes.calculateAccountAge(m.UserID)
-
+	// Persist absolute join time to Postgres store (best effort). Membership
+	// is recorded whether or not joins are logged, so member counts and
+	// retention do not depend on the log channels.
 	joinedAt := m.JoinedAt
-
-	// Persist absolute join time to Postgres store (best effort)
 	if mes.membersRepo != nil && mes.systemRepo != nil && !joinedAt.IsZero() {
 		if err := service.RunErrWithTimeoutContext(ctx, service.DependencyTimeout, func(runCtx context.Context) error {
 			return mes.membersRepo.UpsertMemberJoinContext(runCtx, m.GuildID, m.UserID, joinedAt)
@@ -247,6 +244,20 @@ func (mes *MemberEventService) IngestGuildMemberAdd(ctx context.Context, m Membe
 		}
 	}
 
+	// Logging is now delegated to Sink
+	emit := logging.CheckFeatureEnabled(mes.configManager, logging.LogEventMemberJoin, m.GuildID)
+	if !emit.Enabled {
+		if emit.Reason == logging.EmitReasonNoChannelConfigured {
+			mes.logger.Info("User entry/leave channel not configured for guild, member join notification not sent", "guildID", m.GuildID, "userID", m.UserID)
+		} else {
+			mes.logger.Debug("Member join notification suppressed by policy", "guildID", m.GuildID, "userID", m.UserID, "reason", emit.Reason)
+		}
+		return
+	}
+
+	// Calculate how long the account has existed
+	accountAge := mes.calculateAccountAge(m.UserID)
+
 	// Register precise member join timestamp in memory
 	if !joinedAt.IsZero() {
 		mes.joinMu.Lock()
@@ -307,6 +318,15 @@ func (mes *MemberEventService) IngestGuildMemberRemove(ctx context.Context, m Me
 
 	botTime := mes.getBotTimeOnServer(ctx, m.GuildID)
 
+	// Record the departure so persisted membership stays current.
+	if mes.membersRepo != nil {
+		if err := service.RunErrWithTimeoutContext(ctx, service.DependencyTimeout, func(runCtx context.Context) error {
+			return mes.membersRepo.MarkMemberLeftContext(runCtx, m.GuildID, m.UserID, time.Now().UTC())
+		}); err != nil {
+			mes.logger.Warn("Failed to persist member departure", "guildID", m.GuildID, "userID", m.UserID, "error", err)
+		}
+	}
+
 	// Increment daily member leave metric
 	if mes.systemRepo != nil {
 		if err := service.RunErrWithTimeoutContext(ctx, service.DependencyTimeout, func(runCtx context.Context) error {
@@ -379,33 +399,18 @@ func (mes *MemberEventService) IngestGuildMemberUpdate(ctx context.Context, m Me
 	}
 
 	if mes.sink != nil {
-		// Compare roles
-		var addedRoles, removedRoles []string
-		oldRolesMap := make(map[string]bool, len(m.OldRoleIDs))
-		for _, r := range m.OldRoleIDs {
-			oldRolesMap[r] = true
-		}
-		newRolesMap := make(map[string]bool, len(m.RoleIDs))
-		for _, r := range m.RoleIDs {
-			newRolesMap[r] = true
-			if !oldRolesMap[r] {
-				addedRoles = append(addedRoles, r)
-			}
-		}
-		for r := range oldRolesMap {
-			if !newRolesMap[r] {
-				removedRoles = append(removedRoles, r)
-			}
-		}
-
+		addedRoles, removedRoles := DiffRoles(m.OldRoleIDs, m.RoleIDs)
 		if len(addedRoles) > 0 || len(removedRoles) > 0 {
 			mes.sink.OnRoleUpdate(ctx, RoleUpdateIntent{
-				GuildID:      m.GuildID,
-				UserID:       m.UserID,
-				Username:     m.Username,
-				Bot:          m.Bot,
-				AddedRoles:   addedRoles,
-				RemovedRoles: removedRoles,
+				GuildID:       m.GuildID,
+				UserID:        m.UserID,
+				Username:      m.Username,
+				GlobalName:    m.GlobalName,
+				Nick:          m.Nick,
+				Discriminator: m.Discriminator,
+				Bot:           m.Bot,
+				AddedRoles:    addedRoles,
+				RemovedRoles:  removedRoles,
 			})
 		}
 
@@ -415,6 +420,9 @@ func (mes *MemberEventService) IngestGuildMemberUpdate(ctx context.Context, m Me
 				GuildID:       m.GuildID,
 				UserID:        m.UserID,
 				Username:      m.Username,
+				GlobalName:    m.GlobalName,
+				Nick:          m.Nick,
+				Discriminator: m.Discriminator,
 				Bot:           m.Bot,
 				OldAvatarHash: m.OldAvatar,
 				NewAvatarHash: m.AvatarHash,
@@ -23,6 +23,7 @@ type mockMembersRepo struct {
 	joinErr      error
 	memberJoinAt time.Time
 	memberJoinOk bool
+	leftUserID   string
 }
 
 func (m *mockMembersRepo) UpsertMemberJoinContext(ctx context.Context, guildID, userID string, joinedAt time.Time) error {
@@ -38,6 +39,13 @@ func (m *mockMembersRepo) MemberJoin(ctx context.Context, guildID, userID string
 	return m.memberJoinAt, m.memberJoinOk, m.joinErr
 }
 
+func (m *mockMembersRepo) MarkMemberLeftContext(ctx context.Context, guildID, userID string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.leftUserID = userID
+	return nil
+}
+
 type mockSystemRepo struct {
 	system.Repository
 	mu           sync.Mutex
@@ -89,6 +97,8 @@ type mockMemberSink struct {
 	avatarUpdateUser    string
 	oldAvatar           string
 	newAvatar           string
+	moderationActions   []ModerationActionIntent
+	nameUpdates         []NameUpdateIntent
 }
 
 func (m *mockMemberSink) OnMemberJoin(ctx context.Context, intent MemberJoinIntent, accountAge time.Duration) {
@@ -121,7 +131,16 @@ func (m *mockMemberSink) OnAvatarUpdate(ctx context.Context, intent AvatarUpdate
 	m.newAvatar = intent.NewAvatarHash
 }
 
+func (m *mockMemberSink) OnNameUpdate(ctx context.Context, intent NameUpdateIntent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nameUpdates = append(m.nameUpdates, intent)
+}
+
 func (m *mockMemberSink) OnModerationAction(ctx context.Context, intent ModerationActionIntent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.moderationActions = append(m.moderationActions, intent)
 }
 
 type mockDiscordAdapter struct {
@@ -130,6 +149,7 @@ type mockDiscordAdapter struct {
 	removeRoleCalls int
 	memberCalls     int
 	meCalls         int
+	kicked          []string
 }
 
 func (m *mockDiscordAdapter) Me() (string, error) {
@@ -160,6 +180,13 @@ func (m *mockDiscordAdapter) RemoveRole(ctx context.Context, guildID, userID, ro
 	return nil
 }
 
+func (m *mockDiscordAdapter) Kick(ctx context.Context, guildID, userID, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.kicked = append(m.kicked, userID)
+	return nil
+}
+
 func setupTestService(t *testing.T) (*MemberEventService, *mockMembersRepo, *mockSystemRepo, *mockMemberSink, *mockDiscordAdapter) {
 	t.Helper()
 	store := &config.MemoryConfigStore{}
@@ -388,6 +415,12 @@ func TestMemberEventService_IngestGuildMemberRemove_StoreFallback(t *testing.T)
 		t.Errorf("expected daily member leave metric incremented")
 	}
 	sRepo.mu.Unlock()
+
+	mRepo.mu.Lock()
+	if mRepo.leftUserID != "99999" {
+		t.Errorf("expected the departure to be persisted")
+	}
+	mRepo.mu.Unlock()
 }
 
 func TestMemberEventService_IngestGuildMemberUpdate(t *testing.T) {
@@ -486,6 +519,7 @@ func TestNopMemberSink(t *testing.T) {
 	sink.OnMemberLeave(context.Background(), MemberLeaveIntent{}, 0, 0)
 	sink.OnRoleUpdate(context.Background(), RoleUpdateIntent{})
 	sink.OnAvatarUpdate(context.Background(), AvatarUpdateIntent{})
+	sink.OnNameUpdate(context.Background(), NameUpdateIntent{})
 	sink.OnModerationAction(context.Background(), ModerationActionIntent{})
 }
 
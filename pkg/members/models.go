@@ -41,3 +41,59 @@ type PresenceInput struct {
 	SeenAt   time.Time
 	IsBot    bool
 }
+
+// Retention follows the members, bots excluded, who joined a guild over a
+// period.
+type Retention struct {
+	// Members is the member count at the end of the period.
+	Members int64
+	// Joined counts the members who joined in the period, and Retained those
+	// of them still present.
+	Joined   int64
+	Retained int64
+}
+
+// Rate returns the share of joiners still present. ok is false when nobody
+// joined.
+func (r Retention) Rate() (rate float64, ok bool) {
+	if r.Joined == 0 {
+		return 0, false
+	}
+	return float64(r.Retained) / float64(r.Joined), true
+}
+
+// Member count granularities. A daily count holds the last snapshot taken
+// that day.
+const (
+	CountDaily  = "day"
+	CountHourly = "hour"
+)
+
+// CountBucket returns the start of the day or hour at falls in, in UTC.
+func CountBucket(granularity string, at time.Time) time.Time {
+	at = at.UTC()
+	if granularity == CountHourly {
+		return at.Truncate(time.Hour)
+	}
+	return time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// MemberCount is one point of a guild's member count time series, read from
+// the stored membership.
+type MemberCount struct {
+	GuildID     string
+	Granularity string
+	Bucket      time.Time
+	Members     int64
+	Humans      int64
+	Bots        int64
+}
+
+// NetGrowth returns how many members a series gained from its first point to
+// its last. The series must be in bucket order.
+func NetGrowth(series []MemberCount) int64 {
+	if len(series) < 2 {
+		return 0
+	}
+	return series[len(series)-1].Members - series[0].Members
+}
@@ -0,0 +1,26 @@
+package members
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountBucketAndNetGrowth(t *testing.T) {
+	t.Parallel()
+	at := time.Date(2026, 5, 4, 13, 42, 0, 0, time.FixedZone("UTC-3", -3*3600))
+
+	if got := CountBucket(CountDaily, at); !got.Equal(time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("daily bucket = %v", got)
+	}
+	if got := CountBucket(CountHourly, at); !got.Equal(time.Date(2026, 5, 4, 16, 0, 0, 0, time.UTC)) {
+		t.Fatalf("hourly bucket = %v", got)
+	}
+
+	series := []MemberCount{{Members: 100}, {Members: 90}, {Members: 112}}
+	if got := NetGrowth(series); got != 12 {
+		t.Fatalf("NetGrowth = %d, want 12", got)
+	}
+	if got := NetGrowth(series[:1]); got != 0 {
+		t.Fatalf("NetGrowth of one point = %d, want 0", got)
+	}
+}
@@ -0,0 +1,76 @@
+package members
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+)
+
+// MemberNames are the names a member goes by in a guild.
+type MemberNames struct {
+	Username   string
+	GlobalName string
+	Nick       string
+}
+
+// NameSnapshotStore persists the last names seen for each member, so name
+// changes can be told from the previous values.
+type NameSnapshotStore interface {
+	GetMemberNames(ctx context.Context, guildID, userID string) (names MemberNames, ok bool, err error)
+	UpsertMemberNames(ctx context.Context, guildID, userID string, names MemberNames, updatedAt time.Time) error
+}
+
+// DiffMemberNames compares live against the names stored for guildID and
+// reports every difference to sink, returning how many changed. Like
+// DiffAvatarSnapshots, members seen for the first time are only recorded,
+// and the new names are stored before sink is called. Entries marked
+// NickUnknown keep the stored nickname.
+func DiffMemberNames(ctx context.Context, store NameSnapshotStore, sink MemberSink, guildID string, live iter.Seq2[LiveAvatar, error], at time.Time) (int, error) {
+	if store == nil || guildID == "" {
+		return 0, nil
+	}
+
+	changed := 0
+	for member, err := range live {
+		if err != nil {
+			return changed, fmt.Errorf("DiffMemberNames: list live members: %w", err)
+		}
+		if err := ctx.Err(); err != nil {
+			return changed, fmt.Errorf("DiffMemberNames: %w", err)
+		}
+		if member.UserID == "" {
+			continue
+		}
+
+		stored, ok, err := store.GetMemberNames(ctx, guildID, member.UserID)
+		if err != nil {
+			return changed, fmt.Errorf("DiffMemberNames: load %s names: %w", member.UserID, err)
+		}
+		current := member.names()
+		if member.NickUnknown {
+			current.Nick = stored.Nick
+		}
+		if ok && stored == current {
+			continue
+		}
+		if err := store.UpsertMemberNames(ctx, guildID, member.UserID, current, at); err != nil {
+			return changed, fmt.Errorf("DiffMemberNames: %w", err)
+		}
+		if !ok {
+			continue
+		}
+		changed++
+		if sink != nil {
+			sink.OnNameUpdate(ctx, NameUpdateIntent{
+				GuildID:       guildID,
+				UserID:        member.UserID,
+				Discriminator: member.Discriminator,
+				Bot:           member.Bot,
+				Old:           stored,
+				New:           current,
+			})
+		}
+	}
+	return changed, nil
+}
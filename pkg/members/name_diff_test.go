@@ -0,0 +1,72 @@
+package members
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeNameStore struct {
+	names   map[string]MemberNames
+	written int
+}
+
+func (f *fakeNameStore) GetMemberNames(ctx context.Context, guildID, userID string) (MemberNames, bool, error) {
+	names, ok := f.names[userID]
+	return names, ok, nil
+}
+
+func (f *fakeNameStore) UpsertMemberNames(ctx context.Context, guildID, userID string, names MemberNames, updatedAt time.Time) error {
+	f.names[userID] = names
+	f.written++
+	return nil
+}
+
+type recordingNameSink struct {
+	NopMemberSink
+	updates []NameUpdateIntent
+}
+
+func (s *recordingNameSink) OnNameUpdate(ctx context.Context, intent NameUpdateIntent) {
+	s.updates = append(s.updates, intent)
+}
+
+func TestDiffMemberNames(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeNameStore{names: map[string]MemberNames{
+		"same":    {Username: "bob", Nick: "Bobby"},
+		"renamed": {Username: "alice", GlobalName: "Alice", Nick: "Al"},
+		"user":    {Username: "carol", Nick: "Caz"},
+	}}
+	sink := &recordingNameSink{}
+	live := func(yield func(LiveAvatar, error) bool) {
+		for _, m := range []LiveAvatar{
+			{UserID: "same", Username: "bob", Nick: "Bobby"},
+			{UserID: "renamed", Username: "alice", GlobalName: "Alice", Nick: "Ally"},
+			{UserID: "user", Username: "carol2", NickUnknown: true},
+			{UserID: "unseen", Username: "dave"},
+		} {
+			if !yield(m, nil) {
+				return
+			}
+		}
+	}
+
+	changed, err := DiffMemberNames(context.Background(), store, sink, "g1", live, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if changed != 2 || len(sink.updates) != 2 {
+		t.Fatalf("expected two name changes, got %d: %+v", changed, sink.updates)
+	}
+	if got := sink.updates[0]; got.UserID != "renamed" || got.Old.Nick != "Al" || got.New.Nick != "Ally" {
+		t.Fatalf("unexpected nickname change: %+v", got)
+	}
+	if got := sink.updates[1]; got.Old.Username != "carol" || got.New.Username != "carol2" || got.New.Nick != "Caz" {
+		t.Fatalf("a user update should keep the stored nickname: %+v", got)
+	}
+	if store.written != 3 || store.names["unseen"].Username != "dave" {
+		t.Fatalf("expected changed and unseen members to be stored, got %d writes", store.written)
+	}
+}
@@ -0,0 +1,111 @@
+package members
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"time"
+)
+
+// RoleSnapshotStore is the subset of Repository used to reconcile persisted
+// member state after downtime.
+type RoleSnapshotStore interface {
+	GetActiveGuildMemberStatesContext(ctx context.Context, guildID string) iter.Seq2[CurrentState, error]
+	UpsertMemberRoles(guildID, userID string, roles []string, at time.Time) error
+	UpsertMemberPresenceContext(ctx context.Context, input PresenceInput) error
+	MarkMemberLeftContext(ctx context.Context, guildID, userID string, at time.Time) error
+}
+
+// LiveMember is a member as currently reported by Discord.
+type LiveMember struct {
+	UserID   string
+	Roles    []string
+	JoinedAt time.Time
+	IsBot    bool
+}
+
+// ReconcileResult counts the snapshot corrections applied by a reconciliation
+// pass.
+type ReconcileResult struct {
+	Checked      int
+	RolesUpdated int
+	MarkedLeft   int
+	Added        int
+}
+
+// ReconcileRoleSnapshots brings the persisted member snapshots for guildID in
+// line with live. Members whose roles changed while the bot was offline get
+// their stored roles replaced, stored members absent from live are marked as
+// left, and live members missing from the store are recorded as present.
+// Nothing is emitted to log sinks: the point is that the next gateway
+// diff starts from the real state instead of reporting changes that happened
+// during the outage as if they were new.
+//
+// live must cover the whole guild; a partial listing would mark the missing
+// members as left.
+func ReconcileRoleSnapshots(ctx context.Context, store RoleSnapshotStore, guildID string, live iter.Seq2[LiveMember, error], at time.Time) (ReconcileResult, error) {
+	var result ReconcileResult
+	if store == nil || guildID == "" {
+		return result, nil
+	}
+
+	current := make(map[string]LiveMember)
+	for member, err := range live {
+		if err != nil {
+			return result, fmt.Errorf("ReconcileRoleSnapshots: list live members: %w", err)
+		}
+		current[member.UserID] = member
+	}
+
+	for stored, err := range store.GetActiveGuildMemberStatesContext(ctx, guildID) {
+		if err != nil {
+			return result, fmt.Errorf("ReconcileRoleSnapshots: %w", err)
+		}
+		if err := ctx.Err(); err != nil {
+			return result, fmt.Errorf("ReconcileRoleSnapshots: %w", err)
+		}
+		result.Checked++
+
+		member, present := current[stored.UserID]
+		delete(current, stored.UserID)
+		if !present {
+			if err := store.MarkMemberLeftContext(ctx, guildID, stored.UserID, at); err != nil {
+				return result, fmt.Errorf("ReconcileRoleSnapshots: mark %s left: %w", stored.UserID, err)
+			}
+			result.MarkedLeft++
+			continue
+		}
+		if sameRoleSet(stored.Roles, member.Roles) {
+			continue
+		}
+		if err := store.UpsertMemberRoles(guildID, stored.UserID, member.Roles, at); err != nil {
+			return result, fmt.Errorf("ReconcileRoleSnapshots: update %s roles: %w", stored.UserID, err)
+		}
+		result.RolesUpdated++
+	}
+
+	// What is left joined while nobody was recording it.
+	for userID, member := range current {
+		if err := ctx.Err(); err != nil {
+			return result, fmt.Errorf("ReconcileRoleSnapshots: %w", err)
+		}
+		input := PresenceInput{GuildID: guildID, UserID: userID, JoinedAt: member.JoinedAt, SeenAt: at, IsBot: member.IsBot}
+		if err := store.UpsertMemberPresenceContext(ctx, input); err != nil {
+			return result, fmt.Errorf("ReconcileRoleSnapshots: record %s present: %w", userID, err)
+		}
+		result.Added++
+	}
+	return result, nil
+}
+
+func sameRoleSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	left := slices.Clone(a)
+	right := slices.Clone(b)
+	slices.Sort(left)
+	slices.Sort(right)
+	return slices.Equal(left, right)
+}
@@ -0,0 +1,82 @@
+package members
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"testing"
+	"time"
+)
+
+type fakeRoleSnapshotStore struct {
+	active  []CurrentState
+	updated map[string][]string
+	left    []string
+	added   []PresenceInput
+}
+
+func (f *fakeRoleSnapshotStore) GetActiveGuildMemberStatesContext(ctx context.Context, guildID string) iter.Seq2[CurrentState, error] {
+	return func(yield func(CurrentState, error) bool) {
+		for _, state := range f.active {
+			if !yield(state, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (f *fakeRoleSnapshotStore) UpsertMemberRoles(guildID, userID string, roles []string, at time.Time) error {
+	if f.updated == nil {
+		f.updated = make(map[string][]string)
+	}
+	f.updated[userID] = roles
+	return nil
+}
+
+func (f *fakeRoleSnapshotStore) UpsertMemberPresenceContext(ctx context.Context, input PresenceInput) error {
+	f.added = append(f.added, input)
+	return nil
+}
+
+func (f *fakeRoleSnapshotStore) MarkMemberLeftContext(ctx context.Context, guildID, userID string, at time.Time) error {
+	f.left = append(f.left, userID)
+	return nil
+}
+
+func TestReconcileRoleSnapshots(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeRoleSnapshotStore{active: []CurrentState{
+		{UserID: "unchanged", Roles: []string{"r1", "r2"}},
+		{UserID: "changed", Roles: []string{"r1"}},
+		{UserID: "gone", Roles: []string{"r1"}},
+	}}
+	live := func(yield func(LiveMember, error) bool) {
+		for _, m := range []LiveMember{
+			{UserID: "unchanged", Roles: []string{"r2", "r1"}},
+			{UserID: "changed", Roles: []string{"r1", "r3"}},
+			{UserID: "new", JoinedAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), IsBot: true},
+		} {
+			if !yield(m, nil) {
+				return
+			}
+		}
+	}
+
+	result, err := ReconcileRoleSnapshots(context.Background(), store, "g1", live, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Checked != 3 || result.RolesUpdated != 1 || result.MarkedLeft != 1 || result.Added != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if !slices.Equal(store.updated["changed"], []string{"r1", "r3"}) || len(store.updated) != 1 {
+		t.Fatalf("expected only the changed member to be rewritten, got %+v", store.updated)
+	}
+	if !slices.Equal(store.left, []string{"gone"}) {
+		t.Fatalf("expected absent member to be marked left, got %v", store.left)
+	}
+	if len(store.added) != 1 || store.added[0].UserID != "new" || !store.added[0].IsBot || store.added[0].JoinedAt.IsZero() {
+		t.Fatalf("expected the unrecorded member to be added, got %+v", store.added)
+	}
+}
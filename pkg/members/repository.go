@@ -20,3 +20,14 @@ type Repository interface {
 	MarkMemberLeftContext(ctx context.Context, guildID, userID string, at time.Time) error
 	UpsertMemberRoles(guildID, userID string, roles []string, at time.Time) error
 }
+
+// RetentionRepository reads membership history from the stored presence.
+type RetentionRepository interface {
+	GuildMemberRetention(ctx context.Context, guildID string, from, to time.Time) (Retention, error)
+}
+
+// MemberCountRepository keeps the member count time series.
+type MemberCountRepository interface {
+	RecordGuildMemberCount(ctx context.Context, guildID, granularity string, at time.Time) (MemberCount, error)
+	ListGuildMemberCounts(ctx context.Context, guildID, granularity string, from, to time.Time) ([]MemberCount, error)
+}
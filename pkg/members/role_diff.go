@@ -0,0 +1,28 @@
+package members
+
+// DiffRoles returns the roles in newRoles but not in oldRoles, and those in
+// oldRoles but not in newRoles, each in the order of its own list and
+// without repeats.
+func DiffRoles(oldRoles, newRoles []string) (added, removed []string) {
+	oldSet := make(map[string]struct{}, len(oldRoles))
+	for _, r := range oldRoles {
+		oldSet[r] = struct{}{}
+	}
+	newSet := make(map[string]struct{}, len(newRoles))
+	for _, r := range newRoles {
+		if _, seen := newSet[r]; seen {
+			continue
+		}
+		newSet[r] = struct{}{}
+		if _, ok := oldSet[r]; !ok {
+			added = append(added, r)
+		}
+	}
+	for _, r := range oldRoles {
+		if _, ok := newSet[r]; !ok {
+			removed = append(removed, r)
+			newSet[r] = struct{}{}
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,40 @@
+package members
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestDiffRoles(t *testing.T) {
+	t.Parallel()
+	added, removed := DiffRoles([]string{"1", "2", "3", "3"}, []string{"4", "2", "5", "4"})
+	if !slices.Equal(added, []string{"4", "5"}) {
+		t.Fatalf("unexpected added roles %v", added)
+	}
+	if !slices.Equal(removed, []string{"1", "3"}) {
+		t.Fatalf("unexpected removed roles %v", removed)
+	}
+	if added, removed := DiffRoles([]string{"1"}, []string{"1"}); added != nil || removed != nil {
+		t.Fatalf("expected no difference, got %v and %v", added, removed)
+	}
+}
+
+// BenchmarkDiffRoles measures the role comparison run on every member
+// update, for a typical member and for one holding many roles.
+func BenchmarkDiffRoles(b *testing.B) {
+	for _, n := range []int{8, 128} {
+		oldRoles := make([]string, n)
+		newRoles := make([]string, n)
+		for i := range n {
+			oldRoles[i] = fmt.Sprintf("%d", 100000+i)
+			newRoles[i] = fmt.Sprintf("%d", 100001+i)
+		}
+		b.Run(fmt.Sprintf("roles=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				DiffRoles(oldRoles, newRoles)
+			}
+		})
+	}
+}
@@ -19,10 +19,37 @@ type MemberSink interface {
 	// OnAvatarUpdate is emitted when a user's avatar changes.
 	OnAvatarUpdate(ctx context.Context, intent AvatarUpdateIntent)
 
+	// OnNameUpdate is emitted when a user's username, global name or
+	// nickname changes.
+	OnNameUpdate(ctx context.Context, intent NameUpdateIntent)
+
 	// OnModerationAction is emitted when a moderation action occurs.
 	OnModerationAction(ctx context.Context, intent ModerationActionIntent)
 }
 
+// RoleSettingsSink receives changes to the settings of guild roles.
+type RoleSettingsSink interface {
+	OnRoleSettingsChange(ctx context.Context, intent RoleSettingsIntent)
+}
+
+// VoiceSink receives stages starting and ending and members being moved or
+// disconnected from voice.
+type VoiceSink interface {
+	OnVoiceAction(ctx context.Context, intent VoiceIntent)
+}
+
+// AssetSink receives stickers and soundboard sounds being uploaded, edited
+// and deleted.
+type AssetSink interface {
+	OnAssetChange(ctx context.Context, intent AssetIntent)
+}
+
+// PresenceSink receives the custom status and activity changes of watched
+// members.
+type PresenceSink interface {
+	OnPresenceChange(ctx context.Context, intent PresenceIntent)
+}
+
 // NopMemberSink is a no-operation implementation of MemberSink.
 type NopMemberSink struct{}
 
@@ -32,4 +59,5 @@ func (NopMemberSink) OnMemberLeave(ctx context.Context, intent MemberLeaveIntent
 }
 func (NopMemberSink) OnRoleUpdate(ctx context.Context, intent RoleUpdateIntent)             {}
 func (NopMemberSink) OnAvatarUpdate(ctx context.Context, intent AvatarUpdateIntent)         {}
+func (NopMemberSink) OnNameUpdate(ctx context.Context, intent NameUpdateIntent)             {}
 func (NopMemberSink) OnModerationAction(ctx context.Context, intent ModerationActionIntent) {}
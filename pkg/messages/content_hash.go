@@ -0,0 +1,24 @@
+package messages
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"unicode/utf8"
+)
+
+// HashContent returns the salted hash stored in place of message text for
+// guilds that do not keep it. The guild is part of the hash, so equal text
+// only matches within one guild.
+func HashContent(salt []byte, guildID, content string) string {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(guildID))
+	mac.Write([]byte{0})
+	mac.Write([]byte(content))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ContentLength counts the characters of content, as Discord does.
+func ContentLength(content string) int {
+	return utf8.RuneCountInString(content)
+}
@@ -4,8 +4,10 @@ import "time"
 
 // CachedMessageData provides a pure representation of the deleted or edited message state.
 type CachedMessageData struct {
-	ID             string
-	Content        string
+	ID      string
+	Content string
+	// ContentLength is set when only a hash of the text was stored.
+	ContentLength  int
 	AuthorID       string
 	AuthorUsername string
 	AuthorBot      bool
@@ -39,6 +41,29 @@ type MessageDeleteBulkIntent struct {
 	MessageIDs []string
 }
 
+// ThreadAction is what happened to a thread.
+type ThreadAction string
+
+const (
+	ThreadCreated  ThreadAction = "created"
+	ThreadArchived ThreadAction = "archived"
+	ThreadDeleted  ThreadAction = "deleted"
+)
+
+// ThreadIntent represents a thread being created, archived or deleted.
+// Name and OwnerID may be empty for deletions of threads the bot never saw.
+type ThreadIntent struct {
+	GuildID  string
+	ThreadID string
+	ParentID string
+	OwnerID  string
+	Name     string
+	Action   ThreadAction
+	// AutoArchive is the inactivity, in minutes, after which Discord
+	// archives the thread.
+	AutoArchive int
+}
+
 // MessageCreateIntent represents a message being created.
 type MessageCreateIntent struct {
 	GuildID        string
@@ -48,10 +73,25 @@ type MessageCreateIntent struct {
 	AuthorID       string
 	AuthorUsername string
 	AuthorBot      bool
-	Attachments    int
-	Embeds         int
-	Stickers       int
-	Timestamp      time.Time
+	// AuthorRoleIDs and CategoryID let guilds keep roles and categories out
+	// of their metrics. CategoryID is the category of the channel, or of the
+	// parent of a thread, and empty when unknown.
+	AuthorRoleIDs []string
+	CategoryID    string
+	Attachments   int
+	// Files describes the attachments, for filters that check them.
+	Files     []MessageAttachment
+	Embeds    int
+	Stickers  int
+	Timestamp time.Time
+}
+
+// MessageAttachment describes a file attached to a message.
+type MessageAttachment struct {
+	Filename    string
+	ContentType string
+	Size        uint64
+	URL         string
 }
 
 // AuditLogMessageDeleteEntry represents a cached deletion audit log.
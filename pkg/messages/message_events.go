@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"log/slog"
 	"strconv"
 	"strings"
@@ -21,8 +22,12 @@ import (
 
 // CachedMessage stores message data for comparison
 type CachedMessage struct {
-	ID             string
-	Content        string
+	ID      string
+	Content string
+	// ContentHash and ContentLength are set when only a hash of the text
+	// was stored.
+	ContentHash    string
+	ContentLength  int
 	AuthorID       string
 	AuthorUsername string
 	AuthorAvatar   string
@@ -95,6 +100,7 @@ type MessageEventService struct {
 	configManager *files.ConfigManager
 	botInstanceID string
 	sink          MessageSink
+	inspector     MessageCreateInspector
 	store         Repository
 	systemRepo    system.Repository
 	activity      *service.RuntimeActivity
@@ -120,6 +126,10 @@ type MessageEventService struct {
 	// DiscordAdapter provides a pure domain interface for Discord API operations
 	// without leaking the underlying gateway or state SDK types.
 	discordAdapter DiscordAdapter
+
+	// contentSalt is the salt stored content hashes were made with, so an
+	// edit can be told apart from a resend of the same text.
+	contentSalt []byte
 }
 
 // DiscordAdapter defines the required Discord API interactions for message events.
@@ -137,6 +147,10 @@ const (
 	messageEventRetryMaxAttempts    = 4
 	messageEventRetryTTL            = 5 * time.Second
 
+	// messageDeleteIdempotencyTTL keeps the durable key of a delete log long
+	// enough to cover a restart or a failover replaying the same event.
+	messageDeleteIdempotencyTTL = 10 * time.Minute
+
 	taskTypeMessageUpdateProcess = "message_event.process_update"
 	taskTypeMessageDeleteProcess = "message_event.process_delete"
 )
@@ -161,11 +175,14 @@ type MessageDeleteTaskPayload struct {
 type EventServiceDeps struct {
 	ConfigManager  *files.ConfigManager
 	Sink           MessageSink
+	Inspector      MessageCreateInspector
 	Store          Repository
 	SystemRepo     system.Repository
 	BotInstanceID  string
 	Logger         *slog.Logger
 	DiscordAdapter DiscordAdapter
+	// ContentSalt is the salt of the stored content hashes; see HashContent.
+	ContentSalt []byte
 }
 
 // NewMessageEventServiceForBot creates a message event service scoped to a bot
@@ -175,6 +192,7 @@ func NewMessageEventServiceForBot(deps EventServiceDeps) *MessageEventService {
 		configManager: deps.ConfigManager,
 		botInstanceID: files.NormalizeBotInstanceID(deps.BotInstanceID),
 		sink:          deps.Sink,
+		inspector:     deps.Inspector,
 		store:         deps.Store,
 		systemRepo:    deps.SystemRepo,
 		logger:        deps.Logger,
@@ -186,6 +204,7 @@ func NewMessageEventServiceForBot(deps EventServiceDeps) *MessageEventService {
 		}),
 		lifecycle:      service.NewBaseLifecycle("message event service"),
 		discordAdapter: deps.DiscordAdapter,
+		contentSalt:    deps.ContentSalt,
 		auditCache:     newAuditCacheState(2*time.Second, 15*time.Second),
 	}
 }
@@ -325,6 +344,7 @@ func (mes *MessageEventService) IngestMessageCreate(ctx context.Context, m Messa
 	)
 	defer done()
 
+	text := m.Content
 	if m.Content == "" {
 		// Build a concise summary for non-text messages so we can still cache deletes/edits
 		extra := ""
@@ -362,6 +382,11 @@ func (mes *MessageEventService) IngestMessageCreate(ctx context.Context, m Messa
 		mes.logger.Debug("MessageCreate: DM detected; skipping cache", "channelID", m.ChannelID)
 		return
 	}
+	if text != "" || len(m.Files) > 0 {
+		inspected := m
+		inspected.GuildID, inspected.Content = guildID, text
+		mes.inspectMessageCreate(ctx, inspected)
+	}
 	if !mes.handlesGuild(guildID) {
 		return
 	}
@@ -511,7 +536,8 @@ func (mes *MessageEventService) dispatchMessageUpdateTask(m MessageUpdateIntent)
 		Payload: payload,
 		Options: task.TaskOptions{
 			GroupKey:       group,
-			IdempotencyKey: fmt.Sprintf("msg_update:%s:%s", group, m.MessageID),
+			GuildID:        m.GuildID,
+			IdempotencyKey: fmt.Sprintf("msg_update:%s:%s:%x", group, m.MessageID, contentHash(m.Content)),
 			IdempotencyTTL: messageEventRetryTTL,
 			MaxAttempts:    messageEventRetryMaxAttempts,
 			InitialBackoff: messageEventRetryInitialBackoff,
@@ -520,6 +546,14 @@ func (mes *MessageEventService) dispatchMessageUpdateTask(m MessageUpdateIntent)
 	})
 }
 
+// contentHash tells edits of one message apart, so a second edit is not
+// taken for a repeat of the first while its idempotency key is held.
+func contentHash(content string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(content))
+	return h.Sum64()
+}
+
 func (mes *MessageEventService) dispatchMessageDeleteTask(m MessageDeleteIntent) error {
 	if mes.taskRouter == nil || m.MessageID == "" {
 		return nil
@@ -540,11 +574,15 @@ func (mes *MessageEventService) dispatchMessageDeleteTask(m MessageDeleteIntent)
 		Payload: payload,
 		Options: task.TaskOptions{
 			GroupKey:       group,
+			GuildID:        m.GuildID,
 			IdempotencyKey: fmt.Sprintf("msg_delete:%s:%s", group, m.MessageID),
-			IdempotencyTTL: messageEventRetryTTL,
+			IdempotencyTTL: messageDeleteIdempotencyTTL,
 			MaxAttempts:    messageEventRetryMaxAttempts,
 			InitialBackoff: messageEventRetryInitialBackoff,
 			MaxBackoff:     messageEventRetryMaxBackoff,
+			// A message is deleted once, so a second delete log is always a
+			// duplicate, even from another process or after a restart.
+			DurableIdempotency: true,
 		},
 	})
 }
@@ -620,7 +658,12 @@ func (mes *MessageEventService) processMessageUpdate(ctx context.Context, m Mess
 		return nil
 	}
 	// Check that the content actually changed (compare effective strings)
-	if cached.Content == m.Content {
+	changed, known := mes.contentChanged(cached, m.Content)
+	if !known {
+		mes.logger.Debug("MessageUpdate: original content not stored; skipping notification", "guildID", cached.GuildID, "channelID", cached.ChannelID, "messageID", m.MessageID, "userID", cached.AuthorID)
+		return nil
+	}
+	if !changed {
 		mes.logger.Debug("MessageUpdate: content unchanged; skipping notification", "guildID", cached.GuildID, "channelID", cached.ChannelID, "messageID", m.MessageID, "userID", cached.AuthorID)
 		return nil
 	}
@@ -631,6 +674,7 @@ func (mes *MessageEventService) processMessageUpdate(ctx context.Context, m Mess
 		cd := &CachedMessageData{
 			ID:             cached.ID,
 			Content:        cached.Content,
+			ContentLength:  cached.ContentLength,
 			AuthorID:       cached.AuthorID,
 			AuthorUsername: cached.AuthorUsername,
 			AuthorBot:      cached.AuthorBot,
@@ -660,6 +704,30 @@ func (mes *MessageEventService) processMessageUpdate(ctx context.Context, m Mess
 	return nil
 }
 
+// contentChanged reports whether content differs from what cached holds.
+// Guilds that keep no text are compared through the stored hash; known is
+// false when neither the text nor its hash was stored, so no edit can be
+// told apart from Discord resending the same message.
+func (mes *MessageEventService) contentChanged(cached *CachedMessage, content string) (changed, known bool) {
+	switch {
+	case cached.Content != "" || mes.keepsContent(cached.GuildID):
+		return cached.Content != content, true
+	case cached.ContentHash != "" && mes.contentSalt != nil:
+		return HashContent(mes.contentSalt, cached.GuildID, content) != cached.ContentHash, true
+	default:
+		return false, false
+	}
+}
+
+// keepsContent reports whether guildID stores message text, so an empty
+// cached text means the message had none.
+func (mes *MessageEventService) keepsContent(guildID string) bool {
+	if mes.configManager == nil {
+		return true
+	}
+	return mes.configManager.GuildPrivacy(guildID).CacheMessageContent
+}
+
 func (mes *MessageEventService) processMessageDelete(ctx context.Context, m MessageDeleteIntent, allowWait bool) error {
 	if m.MessageID == "" {
 		return nil
@@ -730,6 +798,7 @@ func (mes *MessageEventService) processMessageDelete(ctx context.Context, m Mess
 		cd := &CachedMessageData{
 			ID:             cached.ID,
 			Content:        cached.Content,
+			ContentLength:  cached.ContentLength,
 			AuthorID:       cached.AuthorID,
 			AuthorUsername: cached.AuthorUsername,
 			AuthorBot:      cached.AuthorBot,
@@ -789,6 +858,8 @@ func (mes *MessageEventService) lookupCachedMessage(ctx context.Context, guildID
 			return &CachedMessage{
 				ID:             rec.MessageID,
 				Content:        rec.Content,
+				ContentHash:    rec.ContentHash,
+				ContentLength:  rec.ContentLength,
 				AuthorID:       rec.AuthorID,
 				AuthorUsername: rec.AuthorUsername,
 				AuthorAvatar:   rec.AuthorAvatar,
@@ -865,6 +936,9 @@ func (mes *MessageEventService) persistMessageCreate(guildID string, m MessageCr
 		Day:       now,
 		Count:     1,
 	}
+	if mes.excludedFromMetrics(guildID, m) {
+		metric.Count = 0
+	}
 
 	if mes.messageCreateWriter != nil {
 		if err := mes.messageCreateWriter.Enqueue(record, version, metric); err == nil {
@@ -882,11 +956,24 @@ func (mes *MessageEventService) persistMessageCreate(guildID string, m MessageCr
 			mes.logger.Warn("MessageCreate: failed to persist message version", "guildID", guildID, "channelID", m.ChannelID, "messageID", m.MessageID, "userID", m.AuthorID, "error", err)
 		}
 	}
-	if err := mes.store.IncrementDailyMessageCountsContext(context.Background(), []DailyCountDelta{{GuildID: guildID, ChannelID: m.ChannelID, UserID: m.AuthorID, Day: now, Count: 1}}); err != nil {
+	if metric.Count == 0 {
+		return
+	}
+	if err := mes.store.IncrementDailyMessageCountsContext(context.Background(), []DailyCountDelta{metric}); err != nil {
 		mes.logger.Warn("MessageCreate: failed to increment daily message metric", "guildID", guildID, "channelID", m.ChannelID, "messageID", m.MessageID, "userID", m.AuthorID, "error", err)
 	}
 }
 
+// excludedFromMetrics reports whether the guild keeps m out of its activity
+// metrics. The message is still cached.
+func (mes *MessageEventService) excludedFromMetrics(guildID string, m MessageCreateIntent) bool {
+	if mes.configManager == nil {
+		return false
+	}
+	gcfg := mes.configManager.GuildConfig(guildID)
+	return gcfg != nil && gcfg.Stats.Exclusions.Excludes(m.ChannelID, m.CategoryID, m.AuthorRoleIDs)
+}
+
 func (mes *MessageEventService) persistMessageUpdate(updated *CachedMessage, content string) {
 	if mes == nil || mes.store == nil || updated == nil {
 		return
@@ -983,6 +1070,24 @@ func (mes *MessageEventService) persistMessageDelete(cached *CachedMessage, dele
 	}
 }
 
+// inspectMessageCreate hands m to the inspector when this bot moderates its
+// guild.
+func (mes *MessageEventService) inspectMessageCreate(ctx context.Context, m MessageCreateIntent) {
+	if mes.inspector == nil || mes.configManager == nil {
+		return
+	}
+	guild := mes.configManager.GuildConfig(m.GuildID)
+	if guild == nil {
+		return
+	}
+	if files.NormalizeBotInstanceID(mes.botInstanceID) != "" {
+		if resolvedID, _ := files.ResolveFeatureBotInstanceID(*guild, "moderation"); resolvedID != mes.botInstanceID {
+			return
+		}
+	}
+	mes.inspector.InspectMessageCreate(ctx, guild, m)
+}
+
 func (mes *MessageEventService) handlesGuild(guildID string) bool {
 	if mes == nil || mes.configManager == nil {
 		return false
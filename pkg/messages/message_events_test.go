@@ -834,6 +834,75 @@ func TestMessageEventService_TaskRouterAsynchronousHandling(t *testing.T) {
 	}
 }
 
+func TestMessageEventService_UpdateTasksKeyedByContent(t *testing.T) {
+	t.Parallel()
+
+	tr := task.NewRouter(task.Defaults())
+	defer tr.Close()
+	tr.RegisterHandler(taskTypeMessageUpdateProcess, func(context.Context, any) error { return nil })
+	svc := &MessageEventService{taskRouter: tr}
+
+	edit := MessageUpdateIntent{MessageID: "999", GuildID: "111", ChannelID: "222", Content: "first"}
+	if err := svc.dispatchMessageUpdateTask(edit); err != nil {
+		t.Fatalf("first edit: %v", err)
+	}
+	if err := svc.dispatchMessageUpdateTask(edit); !errors.Is(err, task.ErrDuplicateTask) {
+		t.Fatalf("repeated edit = %v, want ErrDuplicateTask", err)
+	}
+	edit.Content = "second"
+	if err := svc.dispatchMessageUpdateTask(edit); err != nil {
+		t.Fatalf("second edit: %v", err)
+	}
+}
+
+// taskKeyStore is an IdempotencyStore holding its claims in memory.
+type taskKeyStore struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+func (s *taskKeyStore) ClaimTaskKey(_ context.Context, key string, _ time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys[key] {
+		return false, nil
+	}
+	s.keys[key] = true
+	return true, nil
+}
+
+func (s *taskKeyStore) ReleaseTaskKey(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, key)
+	return nil
+}
+
+func (s *taskKeyStore) PurgeExpiredTaskKeys(context.Context) (int64, error) { return 0, nil }
+
+func TestMessageEventService_DeleteTaskSurvivesRestart(t *testing.T) {
+	t.Parallel()
+
+	keys := &taskKeyStore{keys: make(map[string]bool)}
+	dispatch := func() error {
+		cfg := task.Defaults()
+		cfg.IdempotencyStore = keys
+		tr := task.NewRouter(cfg)
+		defer tr.Close()
+		tr.RegisterHandler(taskTypeMessageDeleteProcess, func(context.Context, any) error { return nil })
+		svc := &MessageEventService{taskRouter: tr}
+		return svc.dispatchMessageDeleteTask(MessageDeleteIntent{MessageID: "999", GuildID: "111", ChannelID: "222"})
+	}
+
+	if err := dispatch(); err != nil {
+		t.Fatalf("first dispatch: %v", err)
+	}
+	// A new router stands in for a restarted process.
+	if err := dispatch(); !errors.Is(err, task.ErrDuplicateTask) {
+		t.Fatalf("second dispatch = %v, want ErrDuplicateTask", err)
+	}
+}
+
 func TestLookupCachedMessage_PollingAndCancellation(t *testing.T) {
 	t.Parallel()
 
@@ -1055,3 +1124,117 @@ func TestDeleteOnLogEnabled(t *testing.T) {
 		t.Errorf("expected true")
 	}
 }
+
+func TestMessageEventService_MetricsExclusions(t *testing.T) {
+	t.Parallel()
+
+	store := &mockRepository{}
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{{
+		GuildID: "111",
+		Stats: files.StatsConfig{Exclusions: files.MetricsExclusionConfig{
+			ChannelIDs:  []string{"333"},
+			CategoryIDs: []string{"444"},
+			RoleIDs:     []string{"555"},
+		}},
+	}}})
+	svc := NewMessageEventServiceForBot(EventServiceDeps{
+		ConfigManager: cfgMgr,
+		Sink:          &mockMessageSink{},
+		Store:         store,
+		Logger:        slog.Default(),
+	})
+
+	for _, m := range []MessageCreateIntent{
+		{MessageID: "1", ChannelID: "333", AuthorID: "123", Content: "spam"},
+		{MessageID: "2", ChannelID: "222", CategoryID: "444", AuthorID: "123", Content: "staff"},
+		{MessageID: "3", ChannelID: "222", AuthorID: "123", AuthorRoleIDs: []string{"555"}, Content: "bot commands"},
+		{MessageID: "4", ChannelID: "222", CategoryID: "666", AuthorID: "123", AuthorRoleIDs: []string{"777"}, Content: "hello"},
+	} {
+		m.GuildID = "111"
+		svc.persistMessageCreate("111", m)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if len(store.deltas) != 1 || store.deltas[0].ChannelID != "222" || store.deltas[0].Count != 1 {
+		t.Fatalf("expected only the last message to be counted, got %+v", store.deltas)
+	}
+}
+
+type recordingInspector struct {
+	mu       sync.Mutex
+	messages []MessageCreateIntent
+}
+
+func (r *recordingInspector) InspectMessageCreate(_ context.Context, _ *files.GuildConfig, m MessageCreateIntent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, m)
+}
+
+func TestMessageEventService_InspectsModeratedGuilds(t *testing.T) {
+	t.Parallel()
+
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	cfgMgr.ApplyConfig(&files.BotConfig{Guilds: []files.GuildConfig{
+		{
+			GuildID:           "111",
+			BotInstanceTokens: map[string]files.EncryptedString{"main": "token", "other": "token"},
+			FeatureRouting:    map[string]string{"moderation": "main", "logging": "other"},
+		},
+		{
+			GuildID:           "112",
+			BotInstanceTokens: map[string]files.EncryptedString{"other": "token"},
+			FeatureRouting:    map[string]string{"moderation": "other"},
+		},
+	}})
+	inspector := &recordingInspector{}
+	svc := NewMessageEventServiceForBot(EventServiceDeps{
+		ConfigManager: cfgMgr,
+		Sink:          &mockMessageSink{},
+		Inspector:     inspector,
+		BotInstanceID: "main",
+		Logger:        slog.Default(),
+	})
+
+	for _, guildID := range []string{"111", "112"} {
+		svc.IngestMessageCreate(context.Background(), MessageCreateIntent{
+			GuildID:   guildID,
+			ChannelID: "222",
+			MessageID: "999",
+			AuthorID:  "123",
+			Content:   "hello",
+		})
+	}
+
+	inspector.mu.Lock()
+	defer inspector.mu.Unlock()
+	if len(inspector.messages) != 1 || inspector.messages[0].GuildID != "111" || inspector.messages[0].Content != "hello" {
+		t.Fatalf("expected only the message of the moderated guild to be inspected, got %+v", inspector.messages)
+	}
+}
+
+func TestMessageEventService_ContentChangedComparesStoredHash(t *testing.T) {
+	t.Parallel()
+	cfgMgr := files.NewConfigManagerWithStore(&config.MemoryConfigStore{}, nil)
+	_ = cfgMgr.AddGuildConfig(files.GuildConfig{GuildID: "1", Privacy: files.PrivacyHashed})
+	_ = cfgMgr.AddGuildConfig(files.GuildConfig{GuildID: "2", Privacy: files.PrivacyMinimal})
+	_ = cfgMgr.AddGuildConfig(files.GuildConfig{GuildID: "3"})
+	salt := []byte("salt")
+	svc := NewMessageEventServiceForBot(EventServiceDeps{ConfigManager: cfgMgr, Logger: slog.Default(), ContentSalt: salt})
+
+	hashed := &CachedMessage{GuildID: "1", ContentHash: HashContent(salt, "1", "hello"), ContentLength: 5}
+	if changed, known := svc.contentChanged(hashed, "hello"); !known || changed {
+		t.Fatalf("resent text must match its hash, got changed=%v known=%v", changed, known)
+	}
+	if changed, known := svc.contentChanged(hashed, "hello!"); !known || !changed {
+		t.Fatalf("edited text must differ from the hash, got changed=%v known=%v", changed, known)
+	}
+	if _, known := svc.contentChanged(&CachedMessage{GuildID: "2"}, "hello"); known {
+		t.Fatal("without text or hash the edit cannot be judged")
+	}
+	if changed, known := svc.contentChanged(&CachedMessage{GuildID: "3"}, "caption"); !known || !changed {
+		t.Fatalf("a guild keeping text had an empty message, got changed=%v known=%v", changed, known)
+	}
+}
@@ -10,9 +10,13 @@ type Record struct {
 	AuthorUsername string
 	AuthorAvatar   string
 	Content        string
-	CachedAt       time.Time
-	ExpiresAt      time.Time
-	HasExpiry      bool
+	// ContentHash and ContentLength replace Content for guilds that store
+	// only a hash of message text; see HashContent.
+	ContentHash   string
+	ContentLength int
+	CachedAt      time.Time
+	ExpiresAt     time.Time
+	HasExpiry     bool
 }
 
 type DeleteKey struct {
@@ -0,0 +1,92 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CacheReconcileStore is the message cache access needed to reconcile cached
+// messages after downtime.
+type CacheReconcileStore interface {
+	CachedMessagesSinceContext(ctx context.Context, guildID string, since time.Time, limit int) ([]Record, error)
+	UpsertMessagesContext(ctx context.Context, records []Record) error
+	DeleteMessagesContext(ctx context.Context, keys []DeleteKey) error
+}
+
+// FetchResult is the live state of a cached message.
+type FetchResult struct {
+	Exists  bool
+	Content string
+}
+
+// MessageFetcher looks up a message on Discord. A message that no longer
+// exists must be reported as FetchResult{Exists: false} with a nil error.
+type MessageFetcher func(ctx context.Context, channelID, messageID string) (FetchResult, error)
+
+// CacheReconcileResult counts the cache corrections applied by a
+// reconciliation pass.
+type CacheReconcileResult struct {
+	Checked int
+	Updated int
+	Removed int
+	Failed  int
+}
+
+// ReconcileCachedMessages re-checks the messages cached for guildID since
+// since against Discord. Messages deleted while the bot was offline are
+// dropped from the cache so they can never surface in a later delete log, and
+// messages edited during the outage get their cached content refreshed so the
+// next edit log diffs against what users actually saw. No log is emitted for
+// either correction: the original events were missed and are not replayed.
+//
+// Lookup failures other than "not found" leave the record untouched.
+func ReconcileCachedMessages(ctx context.Context, store CacheReconcileStore, guildID string, since time.Time, limit int, fetch MessageFetcher) (CacheReconcileResult, error) {
+	var result CacheReconcileResult
+	if store == nil || fetch == nil || guildID == "" {
+		return result, nil
+	}
+
+	cached, err := store.CachedMessagesSinceContext(ctx, guildID, since, limit)
+	if err != nil {
+		return result, fmt.Errorf("ReconcileCachedMessages: %w", err)
+	}
+
+	var stale []DeleteKey
+	var refreshed []Record
+	for _, record := range cached {
+		if err := ctx.Err(); err != nil {
+			return result, fmt.Errorf("ReconcileCachedMessages: %w", err)
+		}
+		result.Checked++
+
+		live, err := fetch(ctx, record.ChannelID, record.MessageID)
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		if !live.Exists {
+			stale = append(stale, DeleteKey{GuildID: record.GuildID, MessageID: record.MessageID})
+			continue
+		}
+		// Guilds on a restrictive privacy profile cache no text to refresh.
+		if record.Content != "" && live.Content != record.Content {
+			record.Content = live.Content
+			refreshed = append(refreshed, record)
+		}
+	}
+
+	if len(refreshed) > 0 {
+		if err := store.UpsertMessagesContext(ctx, refreshed); err != nil {
+			return result, fmt.Errorf("ReconcileCachedMessages: refresh cached content: %w", err)
+		}
+		result.Updated = len(refreshed)
+	}
+	if len(stale) > 0 {
+		if err := store.DeleteMessagesContext(ctx, stale); err != nil {
+			return result, fmt.Errorf("ReconcileCachedMessages: drop deleted messages: %w", err)
+		}
+		result.Removed = len(stale)
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package messages
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeCacheReconcileStore struct {
+	cached  []Record
+	upserts []Record
+	deletes []DeleteKey
+}
+
+func (f *fakeCacheReconcileStore) CachedMessagesSinceContext(ctx context.Context, guildID string, since time.Time, limit int) ([]Record, error) {
+	return f.cached, nil
+}
+
+func (f *fakeCacheReconcileStore) UpsertMessagesContext(ctx context.Context, records []Record) error {
+	f.upserts = append(f.upserts, records...)
+	return nil
+}
+
+func (f *fakeCacheReconcileStore) DeleteMessagesContext(ctx context.Context, keys []DeleteKey) error {
+	f.deletes = append(f.deletes, keys...)
+	return nil
+}
+
+func TestReconcileCachedMessages(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeCacheReconcileStore{cached: []Record{
+		{GuildID: "g1", ChannelID: "c1", MessageID: "same", Content: "hello"},
+		{GuildID: "g1", ChannelID: "c1", MessageID: "edited", Content: "before"},
+		{GuildID: "g1", ChannelID: "c1", MessageID: "deleted", Content: "bye"},
+		{GuildID: "g1", ChannelID: "c1", MessageID: "unreachable", Content: "?"},
+		{GuildID: "g1", ChannelID: "c1", MessageID: "hashed", ContentHash: "5f3a", ContentLength: 3},
+	}}
+	fetch := func(ctx context.Context, channelID, messageID string) (FetchResult, error) {
+		switch messageID {
+		case "same":
+			return FetchResult{Exists: true, Content: "hello"}, nil
+		case "edited":
+			return FetchResult{Exists: true, Content: "after"}, nil
+		case "hashed":
+			return FetchResult{Exists: true, Content: "new"}, nil
+		case "deleted":
+			return FetchResult{}, nil
+		default:
+			return FetchResult{}, errors.New("rate limited")
+		}
+	}
+
+	result, err := ReconcileCachedMessages(context.Background(), store, "g1", time.Time{}, 100, fetch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != (CacheReconcileResult{Checked: 5, Updated: 1, Removed: 1, Failed: 1}) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if len(store.upserts) != 1 || store.upserts[0].MessageID != "edited" || store.upserts[0].Content != "after" {
+		t.Fatalf("expected edited message content to be refreshed, got %+v", store.upserts)
+	}
+	if len(store.deletes) != 1 || store.deletes[0].MessageID != "deleted" {
+		t.Fatalf("expected deleted message to be dropped, got %+v", store.deletes)
+	}
+}
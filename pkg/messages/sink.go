@@ -2,6 +2,8 @@ package messages
 
 import (
 	"context"
+
+	"github.com/small-frappuccino/discordcore/pkg/files"
 )
 
 // MessageSink receives validated message domain events.
@@ -11,3 +13,15 @@ type MessageSink interface {
 	OnMessageUpdate(ctx context.Context, intent MessageUpdateIntent, cachedMessage *CachedMessageData)
 	OnMessageDeleteBulk(ctx context.Context, intent MessageDeleteBulkIntent)
 }
+
+// ThreadSink receives thread lifecycle events.
+type ThreadSink interface {
+	OnThreadEvent(ctx context.Context, intent ThreadIntent)
+}
+
+// MessageCreateInspector checks new member messages against a guild's
+// content rules and acts on the ones that break them. It is called for the
+// guilds the bot moderates, whether or not it also logs them.
+type MessageCreateInspector interface {
+	InspectMessageCreate(ctx context.Context, guild *files.GuildConfig, m MessageCreateIntent)
+}
@@ -1,53 +1,30 @@
 package moderation
 
+import (
+	"slices"
+
+	"github.com/small-frappuccino/discordcore/pkg/permissions"
+)
+
 // Role defines the properties of a guild role necessary for evaluating hierarchy
 // and permissions in a Discord-agnostic manner.
-type Role struct {
-	ID          string
-	Position    int
-	Permissions int64
-}
+type Role = permissions.Role
 
 // Member defines the properties of a guild member necessary for evaluating permissions.
-type Member struct {
-	UserID  string
-	RoleIDs []string
-}
+type Member = permissions.Member
 
 const (
 	// PermissionAdministrator is the equivalent of the Discord Administrator flag (0x00000008).
-	PermissionAdministrator int64 = 0x00000008
+	PermissionAdministrator = permissions.Administrator
 )
 
 // HasPermission evaluates if a member possesses a specific bitwise permission.
 // It checks all roles the member has, including the implicit @everyone role (guildID).
 // If the member has a role with the Administrator flag (0x00000008), this function
-// will always return true, short-circuiting other evaluations.
+// will always return true, short-circuiting other evaluations. Channel overwrites
+// are not considered; use permissions.Effective for channel-scoped checks.
 func HasPermission(member *Member, guildID string, rolesByID map[string]Role, requiredPerm int64) bool {
-	if member == nil {
-		return false
-	}
-
-	var accumulatedPerms int64
-
-	// Accumulate permissions from the @everyone role.
-	if everyoneRole, ok := rolesByID[guildID]; ok {
-		accumulatedPerms |= everyoneRole.Permissions
-	}
-
-	// Accumulate permissions from all assigned roles.
-	for _, roleID := range member.RoleIDs {
-		if role, ok := rolesByID[roleID]; ok {
-			accumulatedPerms |= role.Permissions
-		}
-	}
-
-	// Administrator flag overrides any other permission checks.
-	if (accumulatedPerms & PermissionAdministrator) != 0 {
-		return true
-	}
-
-	return (accumulatedPerms & requiredPerm) != 0
+	return permissions.Base(member, guildID, "", rolesByID)&requiredPerm != 0
 }
 
 // HighestRolePosition calculates the highest position of any role a member has.
@@ -84,3 +61,27 @@ func CanModerate(actor, target *Member, guildID string, rolesByID map[string]Rol
 
 	return actorPos > targetPos
 }
+
+// ProtectedTargets lists the users, and the holders of roles, a guild has
+// put beyond the reach of moderation commands.
+type ProtectedTargets struct {
+	UserIDs []string
+	RoleIDs []string
+}
+
+// Protects reports whether userID is protected, directly or through a role of
+// member. member may be nil for users who are not in the guild.
+func (p ProtectedTargets) Protects(userID string, member *Member) bool {
+	if slices.Contains(p.UserIDs, userID) {
+		return true
+	}
+	if member == nil {
+		return false
+	}
+	for _, roleID := range member.RoleIDs {
+		if slices.Contains(p.RoleIDs, roleID) {
+			return true
+		}
+	}
+	return false
+}
@@ -128,3 +128,27 @@ func TestCanModerate(t *testing.T) {
 		})
 	}
 }
+
+func TestProtectedTargets_Protects(t *testing.T) {
+	t.Parallel()
+	protected := ProtectedTargets{UserIDs: []string{"user1"}, RoleIDs: []string{"role_staff"}}
+
+	tests := []struct {
+		name     string
+		userID   string
+		member   *Member
+		expected bool
+	}{
+		{name: "protected user", userID: "user1", expected: true},
+		{name: "holder of a protected role", userID: "user2", member: &Member{UserID: "user2", RoleIDs: []string{"role_x", "role_staff"}}, expected: true},
+		{name: "unprotected member", userID: "user3", member: &Member{UserID: "user3", RoleIDs: []string{"role_x"}}},
+		{name: "non-member", userID: "user4"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := protected.Protects(tc.userID, tc.member); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package moderation
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// PooledBan is a ban a guild shared with the other guilds of its ban pools.
+// Pools lists the pools of the origin guild when the ban was issued;
+// OriginCase is the case it was recorded under there, zero when none was.
+type PooledBan struct {
+	ID            int64
+	Pools         []string
+	OriginGuildID string
+	OriginCase    int64
+	UserID        string
+	ModeratorID   string
+	Reason        string
+	CreatedAt     time.Time
+}
+
+// SharedPool returns the first pool of b that subscribed also lists, or ""
+// when they share none.
+func (b PooledBan) SharedPool(subscribed []string) string {
+	for _, pool := range b.Pools {
+		if slices.Contains(subscribed, pool) {
+			return pool
+		}
+	}
+	return ""
+}
+
+// Attribution says where a pooled ban came from, for the case it is
+// recorded under in a receiving guild.
+func (b PooledBan) Attribution(pool string) string {
+	s := fmt.Sprintf("Shared through ban pool `%s` by server `%s`", pool, b.OriginGuildID)
+	if b.OriginCase > 0 {
+		s += fmt.Sprintf(" (case #%d there)", b.OriginCase)
+	}
+	return s + "."
+}
@@ -0,0 +1,22 @@
+package moderation
+
+import "testing"
+
+func TestPooledBan_Attribution(t *testing.T) {
+	t.Parallel()
+	b := PooledBan{Pools: []string{"network", "partners"}, OriginGuildID: "100", OriginCase: 12}
+
+	if got := b.SharedPool([]string{"partners", "network"}); got != "network" {
+		t.Fatalf("SharedPool = %q, want the first pool of the ban", got)
+	}
+	if got := b.SharedPool([]string{"other"}); got != "" {
+		t.Fatalf("SharedPool = %q, want none", got)
+	}
+	if got, want := b.Attribution("network"), "Shared through ban pool `network` by server `100` (case #12 there)."; got != want {
+		t.Fatalf("Attribution = %q, want %q", got, want)
+	}
+	b.OriginCase = 0
+	if got, want := b.Attribution("network"), "Shared through ban pool `network` by server `100`."; got != want {
+		t.Fatalf("Attribution = %q, want %q", got, want)
+	}
+}
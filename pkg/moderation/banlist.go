@@ -0,0 +1,90 @@
+package moderation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+)
+
+// BanRecord is one entry of a guild's ban list. Moderator, case number and
+// date are only known for bans recorded as cases.
+type BanRecord struct {
+	UserID      string     `json:"user_id"`
+	Username    string     `json:"username,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	ModeratorID string     `json:"moderator_id,omitempty"`
+	CaseNumber  int64      `json:"case_number,omitempty"`
+	BannedAt    *time.Time `json:"banned_at,omitempty"`
+}
+
+// AttachBanCases fills in each ban from the newest active ban case of the
+// same user. A reason given to Discord wins over the case reason.
+func AttachBanCases(bans []BanRecord, cases []Case) []BanRecord {
+	latest := make(map[string]Case, len(cases))
+	for _, c := range cases {
+		if c.Action != CaseActionBan || c.Voided() || c.UserID == "" {
+			continue
+		}
+		if prev, ok := latest[c.UserID]; !ok || c.CaseNumber > prev.CaseNumber {
+			latest[c.UserID] = c
+		}
+	}
+	out := make([]BanRecord, len(bans))
+	for i, ban := range bans {
+		if c, ok := latest[ban.UserID]; ok {
+			ban.ModeratorID = c.ModeratorID
+			ban.CaseNumber = c.CaseNumber
+			bannedAt := c.CreatedAt.UTC()
+			ban.BannedAt = &bannedAt
+			if ban.Reason == "" {
+				ban.Reason = c.Reason
+			}
+		}
+		out[i] = ban
+	}
+	return out
+}
+
+// BansJSON encodes bans as an indented JSON array.
+func BansJSON(bans []BanRecord) ([]byte, error) {
+	if bans == nil {
+		bans = []BanRecord{}
+	}
+	data, err := json.MarshalIndent(bans, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("BansJSON: %w", err)
+	}
+	return data, nil
+}
+
+// BansCSV encodes bans as a CSV document with a header row, giving times in
+// loc.
+func BansCSV(bans []BanRecord, loc *time.Location) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"user_id", "username", "reason", "moderator_id", "case_number", "banned_at"}); err != nil {
+		return nil, fmt.Errorf("BansCSV: %w", err)
+	}
+	for _, ban := range bans {
+		var caseNumber, bannedAt string
+		if ban.CaseNumber > 0 {
+			caseNumber = strconv.FormatInt(ban.CaseNumber, 10)
+		}
+		if ban.BannedAt != nil {
+			bannedAt = logging.FormatLocalTime(*ban.BannedAt, loc)
+		}
+		if err := w.Write([]string{ban.UserID, ban.Username, ban.Reason, ban.ModeratorID, caseNumber, bannedAt}); err != nil {
+			return nil, fmt.Errorf("BansCSV: %w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("BansCSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,46 @@
+package moderation
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAttachBanCases(t *testing.T) {
+	t.Parallel()
+	older := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(48 * time.Hour)
+	cases := []Case{
+		{CaseNumber: 2, Action: CaseActionBan, UserID: "1", ModeratorID: "m2", Reason: "spam", CreatedAt: newer},
+		{CaseNumber: 1, Action: CaseActionBan, UserID: "1", ModeratorID: "m1", CreatedAt: older},
+		{CaseNumber: 3, Action: CaseActionBan, UserID: "2", ModeratorID: "m1", CreatedAt: newer, VoidedAt: newer},
+		{CaseNumber: 4, Action: "kick", UserID: "3", ModeratorID: "m1", CreatedAt: newer},
+	}
+	bans := AttachBanCases([]BanRecord{
+		{UserID: "1"},
+		{UserID: "2", Reason: "raid"},
+		{UserID: "3"},
+	}, cases)
+
+	if bans[0].ModeratorID != "m2" || bans[0].CaseNumber != 2 || bans[0].Reason != "spam" || !bans[0].BannedAt.Equal(newer) {
+		t.Fatalf("ban with cases = %+v", bans[0])
+	}
+	if bans[1].ModeratorID != "" || bans[1].BannedAt != nil || bans[1].Reason != "raid" {
+		t.Fatalf("voided cases must be ignored, got %+v", bans[1])
+	}
+	if bans[2].CaseNumber != 0 {
+		t.Fatalf("non-ban cases must be ignored, got %+v", bans[2])
+	}
+
+	data, err := BansCSV(bans, nil)
+	if err != nil {
+		t.Fatalf("BansCSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 || lines[1] != "1,,spam,m2,2,2026-01-04T00:00:00Z" || lines[2] != "2,,raid,,," {
+		t.Fatalf("unexpected CSV:\n%s", data)
+	}
+	if data, err := BansJSON(nil); err != nil || string(data) != "[]" {
+		t.Fatalf("BansJSON(nil) = %q, %v", data, err)
+	}
+}
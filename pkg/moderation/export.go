@@ -0,0 +1,114 @@
+package moderation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+)
+
+// MaxCaseExport bounds how many cases one export returns.
+const MaxCaseExport = 5000
+
+// CaseFilter narrows a case listing. Zero fields match every case.
+type CaseFilter struct {
+	Since       time.Time
+	Action      string
+	ModeratorID string
+}
+
+// CaseExport is the exported form of a case. It leaves out the message
+// content AutoMod cases carry.
+type CaseExport struct {
+	CaseNumber  int64      `json:"case_number"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Action      string     `json:"action"`
+	Source      string     `json:"source"`
+	UserID      string     `json:"user_id,omitempty"`
+	ChannelID   string     `json:"channel_id,omitempty"`
+	ModeratorID string     `json:"moderator_id,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	VoidedAt    *time.Time `json:"voided_at,omitempty"`
+	VoidedBy    string     `json:"voided_by,omitempty"`
+}
+
+// NewCaseExport converts c for export.
+func NewCaseExport(c Case) CaseExport {
+	out := CaseExport{
+		CaseNumber:  c.CaseNumber,
+		CreatedAt:   c.CreatedAt.UTC(),
+		Action:      c.Action,
+		Source:      c.Source,
+		UserID:      c.UserID,
+		ChannelID:   c.ChannelID,
+		ModeratorID: c.ModeratorID,
+		Reason:      c.Reason,
+		VoidedBy:    c.VoidedBy,
+	}
+	if c.Voided() {
+		voidedAt := c.VoidedAt.UTC()
+		out.VoidedAt = &voidedAt
+	}
+	return out
+}
+
+// CasesJSON encodes cases as an indented JSON array.
+func CasesJSON(cases []Case) ([]byte, error) {
+	out := make([]CaseExport, 0, len(cases))
+	for _, c := range cases {
+		out = append(out, NewCaseExport(c))
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("CasesJSON: %w", err)
+	}
+	return data, nil
+}
+
+// CasesCSV encodes cases as a CSV document with a header row, giving times
+// in loc. Cells that a spreadsheet would read as a formula are escaped.
+func CasesCSV(cases []Case, loc *time.Location) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := []string{"case_number", "created_at", "action", "source", "user_id", "channel_id", "moderator_id", "reason", "voided_at", "voided_by"}
+	if err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("CasesCSV: %w", err)
+	}
+	for _, c := range cases {
+		e := NewCaseExport(c)
+		var voidedAt string
+		if e.VoidedAt != nil {
+			voidedAt = logging.FormatLocalTime(*e.VoidedAt, loc)
+		}
+		row := []string{
+			strconv.FormatInt(e.CaseNumber, 10), logging.FormatLocalTime(e.CreatedAt, loc), e.Action, e.Source,
+			e.UserID, e.ChannelID, e.ModeratorID, e.Reason, voidedAt, e.VoidedBy,
+		}
+		for i, cell := range row {
+			row[i] = csvCell(cell)
+		}
+		if err := w.Write(row); err != nil {
+			return nil, fmt.Errorf("CasesCSV: %w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("CasesCSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// csvCell prefixes cell with a quote when it starts with a character that
+// makes spreadsheets evaluate it as a formula, so a reason such as
+// "=HYPERLINK(...)" is shown as text.
+func csvCell(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
@@ -0,0 +1,60 @@
+package moderation
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCaseExports(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2026, 4, 2, 10, 30, 0, 0, time.UTC)
+	cases := []Case{
+		{CaseNumber: 3, CreatedAt: created, Action: "ban", Source: CaseSourceManual, UserID: "1", ModeratorID: "2", Reason: "raid, again"},
+		{CaseNumber: 4, CreatedAt: created, Action: "automod_block", Source: CaseSourceAutomod, UserID: "1", MatchedContent: "secret", VoidedAt: created.Add(time.Hour), VoidedBy: "2"},
+	}
+
+	csvData, err := CasesCSV(cases, nil)
+	if err != nil {
+		t.Fatalf("CasesCSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(csvData)), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "case_number,") {
+		t.Fatalf("unexpected CSV:\n%s", csvData)
+	}
+	if lines[1] != `3,2026-04-02T10:30:00Z,ban,manual,1,,2,"raid, again",,` {
+		t.Fatalf("unexpected CSV row %q", lines[1])
+	}
+	tokyo := time.FixedZone("JST", 9*60*60)
+	zoned, err := CasesCSV(cases[:1], tokyo)
+	if err != nil {
+		t.Fatalf("CasesCSV: %v", err)
+	}
+	if !strings.Contains(string(zoned), "3,2026-04-02T19:30:00+09:00,") {
+		t.Fatalf("expected the time in the guild's zone, got:\n%s", zoned)
+	}
+
+	formula, err := CasesCSV([]Case{{CaseNumber: 5, CreatedAt: created, Action: "warn", Reason: "=HYPERLINK(\"x\")"}}, nil)
+	if err != nil {
+		t.Fatalf("CasesCSV: %v", err)
+	}
+	if !strings.Contains(string(formula), `"'=HYPERLINK(""x"")"`) {
+		t.Fatalf("expected the formula to be escaped, got:\n%s", formula)
+	}
+
+	jsonData, err := CasesJSON(cases)
+	if err != nil {
+		t.Fatalf("CasesJSON: %v", err)
+	}
+	if strings.Contains(string(jsonData), "secret") {
+		t.Fatal("exports must not carry AutoMod message content")
+	}
+	var decoded []CaseExport
+	if err := json.Unmarshal(jsonData, &decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(decoded) != 2 || decoded[0].VoidedAt != nil || decoded[1].VoidedAt == nil {
+		t.Fatalf("unexpected JSON export %+v", decoded)
+	}
+}
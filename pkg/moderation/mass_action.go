@@ -0,0 +1,142 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultMassActionRate is the number of actions per second a mass action
+// performs when no rate is configured.
+const DefaultMassActionRate = 2
+
+// MaxMassActionTargets caps the targets of a single mass action. At the
+// default rate the largest run takes well over an hour; bigger lists have to
+// be split across runs.
+const MaxMassActionTargets = 10_000
+
+// EstimateMassActionDuration is how long n targets take at perSecond actions
+// per second, falling back to DefaultMassActionRate like RunMassAction.
+func EstimateMassActionDuration(n, perSecond int) time.Duration {
+	if perSecond <= 0 {
+		perSecond = DefaultMassActionRate
+	}
+	return time.Duration(n) * time.Second / time.Duration(perSecond)
+}
+
+// Statuses recorded per target of a mass action.
+const (
+	MassActionDone      = "done"
+	MassActionSkipped   = "skipped"
+	MassActionFailed    = "failed"
+	MassActionInvalid   = "invalid"
+	MassActionCancelled = "cancelled"
+)
+
+// ErrMassActionSkipped marks a step that deliberately did not act on its
+// target, such as a member outside the invoker's role hierarchy.
+var ErrMassActionSkipped = errors.New("skipped")
+
+// MassActionResult is the outcome of one target.
+type MassActionResult struct {
+	Target string
+	Status string
+	Detail string
+}
+
+// MassActionStep acts on a single target. Returning an error wrapping
+// ErrMassActionSkipped records the target as skipped rather than failed.
+type MassActionStep func(ctx context.Context, target string) error
+
+// MassActionOptions tunes RunMassAction.
+type MassActionOptions struct {
+	// PerSecond caps the actions started per second; zero selects
+	// DefaultMassActionRate.
+	PerSecond int
+	// ProgressEvery is the number of targets between OnProgress calls.
+	ProgressEvery int
+	// OnProgress is called with the processed and total counts.
+	OnProgress func(done, total int)
+}
+
+// RunMassAction applies step to each target in order, throttled to the
+// configured rate. Cancelling ctx stops the run; the remaining targets are
+// reported as cancelled.
+func RunMassAction(ctx context.Context, targets []string, opts MassActionOptions, step MassActionStep) []MassActionResult {
+	rate := opts.PerSecond
+	if rate <= 0 {
+		rate = DefaultMassActionRate
+	}
+	ticker := time.NewTicker(time.Second / time.Duration(rate))
+	defer ticker.Stop()
+
+	results := make([]MassActionResult, 0, len(targets))
+	for i, target := range targets {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+			case <-ticker.C:
+			}
+		}
+		if ctx.Err() != nil {
+			for _, rest := range targets[i:] {
+				results = append(results, MassActionResult{Target: rest, Status: MassActionCancelled})
+			}
+			break
+		}
+
+		result := MassActionResult{Target: target, Status: MassActionDone}
+		if err := step(ctx, target); err != nil {
+			result.Status = MassActionFailed
+			if errors.Is(err, ErrMassActionSkipped) {
+				result.Status = MassActionSkipped
+			}
+			result.Detail = err.Error()
+		}
+		results = append(results, result)
+
+		done := i + 1
+		if opts.OnProgress != nil && opts.ProgressEvery > 0 && done%opts.ProgressEvery == 0 && done < len(targets) {
+			opts.OnProgress(done, len(targets))
+		}
+	}
+	return results
+}
+
+// SummarizeMassAction renders a one-line tally, using verb for completed
+// targets, e.g. "banned".
+func SummarizeMassAction(verb string, results []MassActionResult) string {
+	counts := make(map[string]int, 5)
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	summary := fmt.Sprintf("%d %s", counts[MassActionDone], verb)
+	for _, status := range []string{MassActionSkipped, MassActionFailed, MassActionInvalid, MassActionCancelled} {
+		if counts[status] > 0 {
+			summary += fmt.Sprintf(", %d %s", counts[status], status)
+		}
+	}
+	return summary
+}
+
+// MassActionReportCSV encodes results as a CSV document with a header row.
+func MassActionReportCSV(results []MassActionResult) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"target", "status", "detail"}); err != nil {
+		return nil, fmt.Errorf("MassActionReportCSV: %w", err)
+	}
+	for _, r := range results {
+		if err := w.Write([]string{r.Target, r.Status, r.Detail}); err != nil {
+			return nil, fmt.Errorf("MassActionReportCSV: %w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("MassActionReportCSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,89 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunMassAction(t *testing.T) {
+	t.Parallel()
+	var progress []int
+	results := RunMassAction(context.Background(), []string{"a", "b", "c", "d"}, MassActionOptions{
+		PerSecond:     1000,
+		ProgressEvery: 2,
+		OnProgress:    func(done, total int) { progress = append(progress, done) },
+	}, func(ctx context.Context, target string) error {
+		switch target {
+		case "b":
+			return fmt.Errorf("%w: outranked", ErrMassActionSkipped)
+		case "c":
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	want := []string{MassActionDone, MassActionSkipped, MassActionFailed, MassActionDone}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Fatalf("result %d: status %q, want %q", i, r.Status, want[i])
+		}
+	}
+	if len(progress) != 1 || progress[0] != 2 {
+		t.Fatalf("unexpected progress calls %v", progress)
+	}
+	if got := SummarizeMassAction("banned", results); got != "2 banned, 1 skipped, 1 failed" {
+		t.Fatalf("summary = %q", got)
+	}
+}
+
+func TestRunMassAction_Throttles(t *testing.T) {
+	t.Parallel()
+	start := time.Now()
+	RunMassAction(context.Background(), []string{"a", "b", "c"}, MassActionOptions{PerSecond: 20}, func(context.Context, string) error { return nil })
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("three actions at 20/s took %s, want at least 100ms", elapsed)
+	}
+}
+
+func TestRunMassAction_CancelMarksRemaining(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	results := RunMassAction(ctx, []string{"a", "b", "c"}, MassActionOptions{PerSecond: 1000}, func(_ context.Context, target string) error {
+		if target == "a" {
+			cancel()
+		}
+		return nil
+	})
+	if len(results) != 3 || results[0].Status != MassActionDone || results[1].Status != MassActionCancelled || results[2].Status != MassActionCancelled {
+		t.Fatalf("unexpected results %+v", results)
+	}
+}
+
+func TestMassActionReportCSV(t *testing.T) {
+	t.Parallel()
+	report, err := MassActionReportCSV([]MassActionResult{
+		{Target: "1", Status: MassActionDone},
+		{Target: "2", Status: MassActionSkipped, Detail: "skipped: actor, not outranking"},
+	})
+	if err != nil {
+		t.Fatalf("MassActionReportCSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(report)), "\n")
+	if len(lines) != 3 || lines[0] != "target,status,detail" || lines[2] != `2,skipped,"skipped: actor, not outranking"` {
+		t.Fatalf("unexpected report:\n%s", report)
+	}
+}
+
+func TestEstimateMassActionDuration(t *testing.T) {
+	t.Parallel()
+	if got := EstimateMassActionDuration(MaxMassActionTargets, 0); got != 5000*time.Second {
+		t.Fatalf("default rate estimate = %v", got)
+	}
+	if got := EstimateMassActionDuration(30, 10); got != 3*time.Second {
+		t.Fatalf("configured rate estimate = %v", got)
+	}
+}
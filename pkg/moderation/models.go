@@ -11,3 +11,110 @@ type Warning struct {
 	Reason      string
 	CreatedAt   time.Time
 }
+
+// Note is a free-form remark a moderator left about a member. Notes are not
+// cases: they carry no case number and never affect escalation.
+type Note struct {
+	ID        int64
+	GuildID   string
+	UserID    string
+	AuthorID  string
+	Content   string
+	CreatedAt time.Time
+}
+
+// Case sources distinguish actions taken by moderators from those Discord's
+// native AutoMod took on its own, from bans another guild shared through a
+// ban pool, and from actions taken outside discordcore that were found in the
+// guild's audit log.
+const (
+	CaseSourceManual   = "manual"
+	CaseSourceAutomod  = "automod"
+	CaseSourceBanPool  = "ban_pool"
+	CaseSourceExternal = "external"
+)
+
+// Case is a numbered moderation record. Cases share their numbering with
+// warnings, so every action in a guild has a unique case number. The AutoMod
+// fields are set only for cases with CaseSourceAutomod. LogChannelID and
+// LogMessageID locate the case's log embed, when one was posted. Extra holds
+// a note shown with the case, such as a failed DM to the member. ExpiresAt
+// is when a timed action such as a timeout ends, and zero for lasting ones.
+type Case struct {
+	ID             int64
+	GuildID        string
+	CaseNumber     int64
+	Action         string
+	UserID         string
+	ModeratorID    string
+	Reason         string
+	Source         string
+	ChannelID      string
+	MessageID      string
+	RuleID         string
+	MatchedKeyword string
+	MatchedContent string
+	Content        string
+	Extra          string
+	LogChannelID   string
+	LogMessageID   string
+	ExpiresAt      time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	VoidedAt       time.Time
+	VoidedBy       string
+}
+
+// Voided reports whether the case was voided. Voided cases are kept for the
+// record but no longer count against the member.
+func (c Case) Voided() bool { return !c.VoidedAt.IsZero() }
+
+// Timed reports whether the action of c ends on its own.
+func (c Case) Timed() bool { return !c.ExpiresAt.IsZero() }
+
+// CaseActionBan is the action of ban cases, which the ban list export looks
+// up.
+const CaseActionBan = "ban"
+
+// CaseActionWarn is the action of warning cases, stored alongside the
+// warning that shares their number.
+const CaseActionWarn = "warn"
+
+// CaseActionKick is the action of kick cases, which the audit log poller
+// records alongside the kick command.
+const CaseActionKick = "kick"
+
+// Channel case actions target a channel instead of a member, so their cases
+// carry a ChannelID, or neither ID when every channel was affected.
+const (
+	CaseActionLock   = "lock"
+	CaseActionUnlock = "unlock"
+)
+
+// TargetsChannels reports whether c records an action on channels rather
+// than on a member.
+func (c Case) TargetsChannels() bool {
+	return c.Action == CaseActionLock || c.Action == CaseActionUnlock
+}
+
+// CaseActionPrune records a prune of inactive members. It names no single
+// member; Extra says how many were removed.
+const CaseActionPrune = "prune"
+
+// NamesMember reports whether c must carry the ID of the member it acted on.
+func (c Case) NamesMember() bool {
+	return !c.TargetsChannels() && c.Action != CaseActionPrune
+}
+
+// ChannelLock records the @everyone overwrite a channel had before it was
+// locked, so unlocking restores it exactly. HadOverwrite is false when the
+// channel had no @everyone overwrite at all.
+type ChannelLock struct {
+	GuildID      string
+	ChannelID    string
+	HadOverwrite bool
+	Allow        uint64
+	Deny         uint64
+	LockedBy     string
+	LockedAt     time.Time
+}
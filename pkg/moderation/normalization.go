@@ -6,12 +6,13 @@ import (
 )
 
 // ParseMemberIDs extracts and cleans user IDs from a raw input string.
-// It splits the input by common delimiters (comma, semicolon, space, newline, tab),
+// It splits the input by common delimiters (comma, semicolon, space, newline,
+// carriage return, tab),
 // removes duplicates, and filters out blatantly invalid snowflake formats.
 func ParseMemberIDs(input string) ([]string, []string) {
 	// Identify delimiters to split the massive string without panicking.
 	rawIDs := strings.FieldsFunc(input, func(r rune) bool {
-		return r == ',' || r == ';' || r == ' ' || r == '\n' || r == '\t'
+		return r == ',' || r == ';' || r == ' ' || r == '\n' || r == '\r' || r == '\t'
 	})
 
 	unique := make(map[string]struct{})
@@ -1,6 +1,8 @@
 package moderation
 
 import (
+	"slices"
+	"strings"
 	"testing"
 	"unicode/utf8"
 )
@@ -23,12 +25,19 @@ func FuzzParseMemberIDs(f *testing.F) {
 		// A panic here will automatically fail the fuzz test.
 		valid, invalid := ParseMemberIDs(input)
 
-		// Ensure that the output arrays are cleanly instantiated.
-		if valid == nil {
-			valid = []string{}
+		// Valid IDs come back sorted, without repeats, and as snowflakes.
+		if !slices.IsSorted(valid) || !slices.IsSorted(invalid) {
+			t.Fatalf("ParseMemberIDs(%q) returned unsorted output %v, %v", input, valid, invalid)
 		}
-		if invalid == nil {
-			invalid = []string{}
+		for i, id := range valid {
+			if !isValidSnowflake(id) || (i > 0 && valid[i-1] == id) {
+				t.Fatalf("ParseMemberIDs(%q) returned %q among valid IDs %v", input, id, valid)
+			}
+		}
+		for _, id := range invalid {
+			if isValidSnowflake(id) || strings.TrimSpace(id) == "" {
+				t.Fatalf("ParseMemberIDs(%q) rejected %q", input, id)
+			}
 		}
 	})
 }
@@ -0,0 +1,30 @@
+package moderation
+
+import "time"
+
+// RaidMode records a guild's active raid mode and what it changed, so
+// lifting it restores the guild. PreviousVerification is the verification
+// level before raid mode raised it and only meaningful with
+// RaisedVerification. MinAccountAge is the youngest account allowed to stay
+// after joining; zero lets every account in.
+type RaidMode struct {
+	GuildID              string
+	EnabledBy            string
+	EnabledAt            time.Time
+	ExpiresAt            time.Time
+	PreviousVerification int
+	RaisedVerification   bool
+	InvitesPaused        bool
+	MinAccountAge        time.Duration
+}
+
+// Expired reports whether r should have been lifted by now.
+func (r RaidMode) Expired(now time.Time) bool {
+	return !r.ExpiresAt.After(now)
+}
+
+// TooYoung reports whether an account created at createdAt is kicked when it
+// joins at now.
+func (r RaidMode) TooYoung(createdAt, now time.Time) bool {
+	return r.MinAccountAge > 0 && now.Sub(createdAt) < r.MinAccountAge
+}
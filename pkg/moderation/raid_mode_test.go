@@ -0,0 +1,25 @@
+package moderation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRaidMode(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := RaidMode{ExpiresAt: now.Add(time.Hour), MinAccountAge: 7 * 24 * time.Hour}
+
+	if r.Expired(now) || !r.Expired(now.Add(time.Hour)) {
+		t.Fatal("raid mode should expire exactly at ExpiresAt")
+	}
+	if !r.TooYoung(now.Add(-6*24*time.Hour), now) {
+		t.Fatal("a six-day-old account should be too young")
+	}
+	if r.TooYoung(now.Add(-8*24*time.Hour), now) {
+		t.Fatal("an eight-day-old account should be let in")
+	}
+	if (RaidMode{}).TooYoung(now, now) {
+		t.Fatal("without a minimum age no account is too young")
+	}
+}
@@ -0,0 +1,141 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultActionRateWindow is the window an ActionRateLimit counts over when
+// none is configured.
+const DefaultActionRateWindow = 10 * time.Minute
+
+// ErrActionRateExceeded is wrapped by every ActionRateError.
+var ErrActionRateExceeded = errors.New("moderator action rate exceeded")
+
+// ActionRateLimit caps the destructive actions one moderator may take in a
+// guild per window. A non-positive Max disables the limit.
+type ActionRateLimit struct {
+	Max    int
+	Window time.Duration
+}
+
+// ActionRateError reports a refused action. Tripped is set on the refusal
+// that started the lockout, so callers alert once per lockout.
+type ActionRateError struct {
+	Until   time.Time
+	Tripped bool
+}
+
+func (e *ActionRateError) Error() string {
+	return fmt.Sprintf("%v; locked until %s", ErrActionRateExceeded, e.Until.Format(time.RFC3339))
+}
+
+func (e *ActionRateError) Unwrap() error { return ErrActionRateExceeded }
+
+type rateGuardKey struct {
+	guildID     string
+	moderatorID string
+}
+
+// ActionRateGuard counts destructive actions per moderator and locks a
+// moderator out for one window once they exceed the limit, bounding the damage
+// a compromised moderator account can do.
+//
+// Goroutine safety: every method is safe to call concurrently.
+type ActionRateGuard struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	actions map[rateGuardKey][]time.Time
+	locked  map[rateGuardKey]time.Time
+}
+
+// NewActionRateGuard creates an empty guard.
+func NewActionRateGuard() *ActionRateGuard {
+	return &ActionRateGuard{
+		now:     time.Now,
+		actions: make(map[rateGuardKey][]time.Time),
+		locked:  make(map[rateGuardKey]time.Time),
+	}
+}
+
+// Record counts n actions by moderatorID. It returns an *ActionRateError
+// without counting them when the moderator is locked out or the actions would
+// exceed limit; exceeding the limit starts a lockout lasting one window.
+func (g *ActionRateGuard) Record(guildID, moderatorID string, n int, limit ActionRateLimit) error {
+	if limit.Max <= 0 || n <= 0 {
+		return nil
+	}
+	if limit.Window <= 0 {
+		limit.Window = DefaultActionRateWindow
+	}
+	key := rateGuardKey{guildID: guildID, moderatorID: moderatorID}
+	now := g.now()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if until, ok := g.locked[key]; ok {
+		if now.Before(until) {
+			return &ActionRateError{Until: until}
+		}
+		delete(g.locked, key)
+	}
+
+	cutoff := now.Add(-limit.Window)
+	recent := g.actions[key]
+	for len(recent) > 0 && !recent[0].After(cutoff) {
+		recent = recent[1:]
+	}
+	if len(recent)+n > limit.Max {
+		until := now.Add(limit.Window)
+		g.locked[key] = until
+		delete(g.actions, key)
+		return &ActionRateError{Until: until, Tripped: true}
+	}
+	for range n {
+		recent = append(recent, now)
+	}
+	g.actions[key] = recent
+	return nil
+}
+
+// LockedUntil reports whether moderatorID is locked out, and until when.
+func (g *ActionRateGuard) LockedUntil(guildID, moderatorID string) (time.Time, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	until, ok := g.locked[rateGuardKey{guildID: guildID, moderatorID: moderatorID}]
+	if !ok || !g.now().Before(until) {
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// Refund forgets the n most recent actions of moderatorID, for actions Record
+// counted that then failed. A lockout already started stays.
+func (g *ActionRateGuard) Refund(guildID, moderatorID string, n int) {
+	if n <= 0 {
+		return
+	}
+	key := rateGuardKey{guildID: guildID, moderatorID: moderatorID}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	recent := g.actions[key]
+	recent = recent[:max(len(recent)-n, 0)]
+	if len(recent) == 0 {
+		delete(g.actions, key)
+		return
+	}
+	g.actions[key] = recent
+}
+
+// Release lifts a lockout and forgets the moderator's recent actions.
+func (g *ActionRateGuard) Release(guildID, moderatorID string) {
+	key := rateGuardKey{guildID: guildID, moderatorID: moderatorID}
+	g.mu.Lock()
+	delete(g.locked, key)
+	delete(g.actions, key)
+	g.mu.Unlock()
+}
@@ -0,0 +1,67 @@
+package moderation
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestActionRateGuard_LocksOutAfterLimit(t *testing.T) {
+	t.Parallel()
+	g := NewActionRateGuard()
+	now := time.Unix(1_700_000_000, 0)
+	g.now = func() time.Time { return now }
+	limit := ActionRateLimit{Max: 3, Window: 10 * time.Minute}
+
+	for i := range 3 {
+		if err := g.Record("g", "m", 1, limit); err != nil {
+			t.Fatalf("action %d: %v", i, err)
+		}
+	}
+
+	var rateErr *ActionRateError
+	err := g.Record("g", "m", 1, limit)
+	if !errors.As(err, &rateErr) || !rateErr.Tripped || !errors.Is(err, ErrActionRateExceeded) {
+		t.Fatalf("expected tripped rate error, got %v", err)
+	}
+	if err := g.Record("g", "m", 1, limit); !errors.As(err, &rateErr) || rateErr.Tripped {
+		t.Fatalf("expected lockout without a second trip, got %v", err)
+	}
+	if err := g.Record("g", "other", 1, limit); err != nil {
+		t.Fatalf("other moderators must not be affected: %v", err)
+	}
+	if _, locked := g.LockedUntil("g", "m"); !locked {
+		t.Fatal("expected moderator to be locked out")
+	}
+
+	now = now.Add(11 * time.Minute)
+	if err := g.Record("g", "m", 1, limit); err != nil {
+		t.Fatalf("expected lockout to expire: %v", err)
+	}
+}
+
+func TestActionRateGuard_WindowSlidesAndRelease(t *testing.T) {
+	t.Parallel()
+	g := NewActionRateGuard()
+	now := time.Unix(1_700_000_000, 0)
+	g.now = func() time.Time { return now }
+	limit := ActionRateLimit{Max: 2, Window: time.Minute}
+
+	if err := g.Record("g", "m", 2, limit); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	now = now.Add(time.Minute)
+	if err := g.Record("g", "m", 2, limit); err != nil {
+		t.Fatalf("expected earlier actions to leave the window: %v", err)
+	}
+	if err := g.Record("g", "m", 1, limit); err == nil {
+		t.Fatal("expected limit to be exceeded")
+	}
+	g.Release("g", "m")
+	if err := g.Record("g", "m", 1, limit); err != nil {
+		t.Fatalf("expected release to lift the lockout: %v", err)
+	}
+	if err := g.Record("g", "m", 100, ActionRateLimit{}); err != nil {
+		t.Fatalf("zero limit must disable the guard: %v", err)
+	}
+}
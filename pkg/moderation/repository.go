@@ -9,7 +9,34 @@ import (
 type Repository interface {
 	NextModerationCaseNumber(ctx context.Context, guildID string) (int64, error)
 	CreateModerationWarning(ctx context.Context, guildID, userID, moderatorID, reason string, createdAt time.Time) (Warning, error)
+	CreateModerationWarningCase(ctx context.Context, c Case) (Warning, error)
+	CreateModerationCase(ctx context.Context, c Case) (Case, error)
+	GetModerationCase(ctx context.Context, guildID string, caseNumber int64) (Case, bool, error)
+	ListModerationCases(ctx context.Context, guildID string, filter CaseFilter) ([]Case, error)
+	ModerationActionCounts(ctx context.Context, guildID string, from, to time.Time) (ActionCounts, error)
+	UpdateModerationCaseReason(ctx context.Context, guildID string, caseNumber int64, reason string) (Case, bool, error)
+	VoidModerationCase(ctx context.Context, guildID string, caseNumber int64, voidedBy string, voidedAt time.Time) (Case, bool, error)
+	SetModerationCaseLogMessage(ctx context.Context, guildID string, caseNumber int64, channelID, messageID string) error
+	ListExpiredModerationCases(ctx context.Context, guildID string, now time.Time, limit int) ([]Case, error)
+	MarkModerationCaseExpiryLogged(ctx context.Context, guildID string, caseNumber int64, at time.Time) error
 	ListModerationWarnings(ctx context.Context, guildID, userID string, limit int) iter.Seq2[Warning, error]
+	CountModerationWarnings(ctx context.Context, guildID, userID string) (int, error)
+	DeleteModerationWarning(ctx context.Context, guildID string, caseNumber int64) (Warning, bool, error)
+	ClearModerationWarnings(ctx context.Context, guildID, userID string) (int64, error)
+	CreateModerationNote(ctx context.Context, guildID, userID, authorID, content string, createdAt time.Time) (Note, error)
+	ListModerationNotes(ctx context.Context, guildID, userID string, limit int) iter.Seq2[Note, error]
+	DeleteModerationNote(ctx context.Context, guildID string, noteID int64) (Note, bool, error)
+	SaveChannelLock(ctx context.Context, lock ChannelLock) (bool, error)
+	GetChannelLock(ctx context.Context, guildID, channelID string) (ChannelLock, bool, error)
+	ListChannelLocks(ctx context.Context, guildID string) iter.Seq2[ChannelLock, error]
+	DeleteChannelLock(ctx context.Context, guildID, channelID string) error
+	SaveRaidMode(ctx context.Context, mode RaidMode) (bool, error)
+	GetRaidMode(ctx context.Context, guildID string) (RaidMode, bool, error)
+	ListExpiredRaidModes(ctx context.Context, now time.Time) ([]RaidMode, error)
+	DeleteRaidMode(ctx context.Context, guildID string) error
+	CreatePooledBan(ctx context.Context, ban PooledBan) (PooledBan, error)
+	ListPendingPooledBans(ctx context.Context, guildID string, pools []string, since time.Time, limit int) ([]PooledBan, error)
+	MarkPooledBanDelivered(ctx context.Context, banID int64, guildID string, caseNumber int64, at time.Time) error
 	SetGuildOwnerID(ctx context.Context, guildID, ownerID string) error
 	GetGuildOwnerID(ctx context.Context, guildID string) (string, bool, error)
 }
@@ -0,0 +1,22 @@
+package moderation
+
+import "context"
+
+// TestModeBanner heads everything logged for an action a guild in test mode
+// only simulated.
+const TestModeBanner = "**TEST MODE** · simulated, nothing was changed on Discord"
+
+type testModeKey struct{}
+
+// WithTestMode returns a copy of ctx under which moderation and automod
+// actions are simulated: they are logged as usual but make no change on
+// Discord and record no cases or warnings.
+func WithTestMode(ctx context.Context) context.Context {
+	return context.WithValue(ctx, testModeKey{}, true)
+}
+
+// InTestMode reports whether actions taken under ctx are simulated.
+func InTestMode(ctx context.Context) bool {
+	on, _ := ctx.Value(testModeKey{}).(bool)
+	return on
+}
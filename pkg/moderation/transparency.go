@@ -0,0 +1,86 @@
+package moderation
+
+import "time"
+
+// ActionCounts tallies the moderation recorded in a guild over a period.
+// Voided cases are counted apart from ByAction.
+type ActionCounts struct {
+	ByAction map[string]int
+	Warnings int
+	Voided   int
+}
+
+// TransparencyCategory is one line of a transparency report.
+type TransparencyCategory struct {
+	Name  string
+	Count int
+}
+
+// transparencyCategories groups case actions under the names a public
+// report shows, in report order. Actions missing here land under "Other".
+var transparencyCategories = []struct {
+	name    string
+	actions []string
+}{
+	{name: "Bans", actions: []string{CaseActionBan, "softban"}},
+	{name: "Kicks", actions: []string{"kick"}},
+	{name: "Timeouts", actions: []string{"timeout", "automod_timeout"}},
+	{name: "Messages blocked by AutoMod", actions: []string{"automod_block"}},
+	{name: "Channel lockdowns", actions: []string{CaseActionLock}},
+}
+
+// TransparencyReport summarizes a guild's moderation over a period without
+// naming anyone.
+type TransparencyReport struct {
+	From, To   time.Time
+	Categories []TransparencyCategory
+	// Reversed counts actions later voided, which Categories leaves out.
+	Reversed int
+}
+
+// NewTransparencyReport groups counts into report categories. Categories with
+// nothing to report are left out, as are unlocks, which only undo a lockdown.
+func NewTransparencyReport(from, to time.Time, counts ActionCounts) TransparencyReport {
+	report := TransparencyReport{From: from, To: to, Reversed: counts.Voided}
+	grouped := map[string]bool{CaseActionUnlock: true}
+	for _, category := range transparencyCategories {
+		total := 0
+		for _, action := range category.actions {
+			total += counts.ByAction[action]
+			grouped[action] = true
+		}
+		report.add(category.name, total)
+	}
+	report.add("Warnings", counts.Warnings)
+	other := 0
+	for action, n := range counts.ByAction {
+		if !grouped[action] {
+			other += n
+		}
+	}
+	report.add("Other actions", other)
+	return report
+}
+
+func (r *TransparencyReport) add(name string, count int) {
+	if count > 0 {
+		r.Categories = append(r.Categories, TransparencyCategory{Name: name, Count: count})
+	}
+}
+
+// Total returns the number of actions the report counts.
+func (r TransparencyReport) Total() int {
+	total := 0
+	for _, category := range r.Categories {
+		total += category.Count
+	}
+	return total
+}
+
+// PreviousMonth returns the calendar month, in UTC, before the one holding
+// now, as a half-open [from, to) range.
+func PreviousMonth(now time.Time) (from, to time.Time) {
+	now = now.UTC()
+	to = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return to.AddDate(0, -1, 0), to
+}
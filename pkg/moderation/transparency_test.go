@@ -0,0 +1,38 @@
+package moderation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewTransparencyReport(t *testing.T) {
+	t.Parallel()
+	from, to := PreviousMonth(time.Date(2026, 3, 15, 12, 0, 0, 0, time.FixedZone("UTC+9", 9*3600)))
+	if !from.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("PreviousMonth = %v, %v", from, to)
+	}
+
+	report := NewTransparencyReport(from, to, ActionCounts{
+		ByAction: map[string]int{"ban": 2, "softban": 1, "automod_timeout": 3, "timeout": 1, "lock": 1, "unlock": 1, "custom": 2},
+		Warnings: 4,
+		Voided:   1,
+	})
+	want := []TransparencyCategory{
+		{Name: "Bans", Count: 3},
+		{Name: "Timeouts", Count: 4},
+		{Name: "Channel lockdowns", Count: 1},
+		{Name: "Warnings", Count: 4},
+		{Name: "Other actions", Count: 2},
+	}
+	if len(report.Categories) != len(want) {
+		t.Fatalf("Categories = %+v, want %+v", report.Categories, want)
+	}
+	for i := range want {
+		if report.Categories[i] != want[i] {
+			t.Fatalf("Categories[%d] = %+v, want %+v", i, report.Categories[i], want[i])
+		}
+	}
+	if report.Total() != 14 || report.Reversed != 1 {
+		t.Fatalf("Total = %d, Reversed = %d", report.Total(), report.Reversed)
+	}
+}
@@ -0,0 +1,102 @@
+package observability
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OperationalAlertKind classifies a failure that should reach the bot owner.
+type OperationalAlertKind string
+
+// AlertSendFailure defines a failed delivery of a message to Discord.
+// AlertDatabaseError defines a failed persistence call.
+// AlertTaskDeadLetter defines a task dropped after exhausting its retries.
+// AlertServiceCrash defines a service that could not be kept running.
+// AlertSlowInteraction defines an interaction acknowledged close to, or
+// past, Discord's 3-second deadline.
+const (
+	AlertSendFailure     OperationalAlertKind = "send_failure"
+	AlertDatabaseError   OperationalAlertKind = "database_error"
+	AlertTaskDeadLetter  OperationalAlertKind = "task_dead_letter"
+	AlertServiceCrash    OperationalAlertKind = "service_crash"
+	AlertSlowInteraction OperationalAlertKind = "slow_interaction"
+)
+
+// maxPendingAlertSources bounds the number of distinct kind/source pairs held
+// between drains, so a flood of unique sources cannot grow memory unbounded.
+const maxPendingAlertSources = 256
+
+// OperationalAlertSummary aggregates the reports of one kind from one source
+// since the last drain.
+type OperationalAlertSummary struct {
+	Kind        OperationalAlertKind
+	Source      string
+	Count       int64
+	LastMessage string
+	FirstAt     time.Time
+	LastAt      time.Time
+}
+
+// Like the recovered-panic tally, pending alerts are process-wide: the
+// failures come from many layers and the owner alert pipeline drains them
+// all in one place.
+var (
+	pendingAlertsMu sync.Mutex
+	pendingAlerts   map[string]*OperationalAlertSummary
+	droppedAlerts   int64
+)
+
+// ReportOperationalAlert records a failure for the owner alert pipeline.
+// Reports are aggregated per kind and source until drained.
+func ReportOperationalAlert(kind OperationalAlertKind, source string, err error) {
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+	now := time.Now()
+	key := string(kind) + "/" + source
+
+	pendingAlertsMu.Lock()
+	defer pendingAlertsMu.Unlock()
+	if pendingAlerts == nil {
+		pendingAlerts = make(map[string]*OperationalAlertSummary)
+	}
+	summary, ok := pendingAlerts[key]
+	if !ok {
+		if len(pendingAlerts) >= maxPendingAlertSources {
+			droppedAlerts++
+			return
+		}
+		summary = &OperationalAlertSummary{Kind: kind, Source: source, FirstAt: now}
+		pendingAlerts[key] = summary
+	}
+	summary.Count++
+	summary.LastMessage = message
+	summary.LastAt = now
+}
+
+// DrainOperationalAlerts returns and clears the pending alert summaries,
+// ordered by kind then source, along with the number of reports discarded
+// because too many distinct sources were pending.
+func DrainOperationalAlerts() ([]OperationalAlertSummary, int64) {
+	pendingAlertsMu.Lock()
+	pending := pendingAlerts
+	dropped := droppedAlerts
+	pendingAlerts = nil
+	droppedAlerts = 0
+	pendingAlertsMu.Unlock()
+
+	out := make([]OperationalAlertSummary, 0, len(pending))
+	for _, summary := range pending {
+		out = append(out, *summary)
+	}
+	slices.SortFunc(out, func(a, b OperationalAlertSummary) int {
+		if c := strings.Compare(string(a.Kind), string(b.Kind)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Source, b.Source)
+	})
+	return out, dropped
+}
@@ -0,0 +1,30 @@
+package observability
+
+import (
+	"errors"
+	"testing"
+)
+
+// Not parallel: the pending alert registry is process-wide.
+func TestDrainOperationalAlertsAggregatesPerSource(t *testing.T) {
+	DrainOperationalAlerts()
+
+	ReportOperationalAlert(AlertTaskDeadLetter, "notifications.member_join", errors.New("first"))
+	ReportOperationalAlert(AlertTaskDeadLetter, "notifications.member_join", errors.New("second"))
+	ReportOperationalAlert(AlertDatabaseError, "runtime_activity", errors.New("timeout"))
+
+	got, dropped := DrainOperationalAlerts()
+	if dropped != 0 || len(got) != 2 {
+		t.Fatalf("expected two summaries and nothing dropped, got %d (dropped %d)", len(got), dropped)
+	}
+	if got[0].Kind != AlertDatabaseError || got[1].Kind != AlertTaskDeadLetter {
+		t.Fatalf("expected summaries ordered by kind, got %+v", got)
+	}
+	if got[1].Count != 2 || got[1].LastMessage != "second" {
+		t.Fatalf("expected repeated reports to aggregate, got %+v", got[1])
+	}
+
+	if again, _ := DrainOperationalAlerts(); len(again) != 0 {
+		t.Fatalf("expected drain to clear pending alerts, got %+v", again)
+	}
+}
@@ -0,0 +1,172 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultErrorJournalCapacity is the number of error records kept in memory.
+const DefaultErrorJournalCapacity = 200
+
+// ErrorRecord is one error-level log entry kept for later inspection.
+type ErrorRecord struct {
+	At        time.Time `json:"at"`
+	Subsystem string    `json:"subsystem"`
+	Message   string    `json:"message"`
+	// Detail holds the remaining log attributes rendered as key=value pairs.
+	Detail string `json:"detail,omitempty"`
+}
+
+// ErrorRecordFilter narrows a lookup of error records. Zero fields do not
+// filter; records are always returned newest first.
+type ErrorRecordFilter struct {
+	Subsystem string
+	Since     time.Time
+	Before    time.Time
+	Limit     int
+}
+
+// Matches reports whether rec passes the filter, ignoring Limit.
+func (f ErrorRecordFilter) Matches(rec ErrorRecord) bool {
+	if f.Subsystem != "" && !strings.EqualFold(rec.Subsystem, f.Subsystem) {
+		return false
+	}
+	if !f.Since.IsZero() && rec.At.Before(f.Since) {
+		return false
+	}
+	if !f.Before.IsZero() && !rec.At.Before(f.Before) {
+		return false
+	}
+	return true
+}
+
+// ErrorArchive durably stores error records beyond the in-memory window.
+type ErrorArchive interface {
+	AppendErrorRecords(ctx context.Context, records []ErrorRecord) error
+	ListErrorRecords(ctx context.Context, filter ErrorRecordFilter) ([]ErrorRecord, error)
+}
+
+// ErrorJournal keeps the most recent error records in a ring buffer and,
+// once an archive is attached, hands them over to it on Flush.
+//
+// Goroutine safety: every method is safe to call concurrently.
+type ErrorJournal struct {
+	mu      sync.Mutex
+	ring    []ErrorRecord
+	next    int
+	size    int
+	unsaved []ErrorRecord
+	archive ErrorArchive
+}
+
+// NewErrorJournal creates a journal holding up to capacity records in memory.
+func NewErrorJournal(capacity int) *ErrorJournal {
+	if capacity <= 0 {
+		capacity = DefaultErrorJournalCapacity
+	}
+	return &ErrorJournal{ring: make([]ErrorRecord, capacity)}
+}
+
+var processErrors = NewErrorJournal(DefaultErrorJournalCapacity)
+
+// Errors returns the process-wide error journal fed by the application
+// logger.
+func Errors() *ErrorJournal {
+	return processErrors
+}
+
+// Record appends rec, evicting the oldest record when the ring is full.
+func (j *ErrorJournal) Record(rec ErrorRecord) {
+	if rec.At.IsZero() {
+		rec.At = time.Now()
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.ring[j.next] = rec
+	j.next = (j.next + 1) % len(j.ring)
+	if j.size < len(j.ring) {
+		j.size++
+	}
+	// Records that were never flushed are bounded by the ring as well, so a
+	// broken archive cannot grow memory without limit.
+	if len(j.unsaved) == len(j.ring) {
+		j.unsaved = j.unsaved[1:]
+	}
+	j.unsaved = append(j.unsaved, rec)
+}
+
+// SetArchive attaches the durable store used by Flush and Recent.
+func (j *ErrorJournal) SetArchive(archive ErrorArchive) {
+	j.mu.Lock()
+	j.archive = archive
+	j.mu.Unlock()
+}
+
+// Flush writes the records recorded since the previous flush to the archive.
+// Without an archive the records stay in memory only.
+func (j *ErrorJournal) Flush(ctx context.Context) error {
+	j.mu.Lock()
+	archive := j.archive
+	pending := j.unsaved
+	if archive == nil || len(pending) == 0 {
+		j.mu.Unlock()
+		return nil
+	}
+	j.unsaved = nil
+	j.mu.Unlock()
+
+	if err := archive.AppendErrorRecords(ctx, pending); err != nil {
+		j.mu.Lock()
+		j.unsaved = append(pending, j.unsaved...)
+		if excess := len(j.unsaved) - len(j.ring); excess > 0 {
+			j.unsaved = j.unsaved[excess:]
+		}
+		j.mu.Unlock()
+		return fmt.Errorf("ErrorJournal.Flush: %w", err)
+	}
+	return nil
+}
+
+// Recent returns records matching filter, newest first. The in-memory ring
+// answers first; the archive extends the result with older records when the
+// ring alone cannot fill the limit.
+func (j *ErrorJournal) Recent(ctx context.Context, filter ErrorRecordFilter) ([]ErrorRecord, error) {
+	j.mu.Lock()
+	archive := j.archive
+	var oldest time.Time
+	out := make([]ErrorRecord, 0, min(j.size, max(filter.Limit, 0)))
+	for i := 1; i <= j.size; i++ {
+		rec := j.ring[(j.next-i+len(j.ring))%len(j.ring)]
+		oldest = rec.At
+		if filter.Limit > 0 && len(out) >= filter.Limit {
+			continue
+		}
+		if filter.Matches(rec) {
+			out = append(out, rec)
+		}
+	}
+	j.mu.Unlock()
+
+	if archive == nil || (filter.Limit > 0 && len(out) >= filter.Limit) {
+		return out, nil
+	}
+	if !oldest.IsZero() && !filter.Since.IsZero() && oldest.Before(filter.Since) {
+		return out, nil
+	}
+
+	older := filter
+	if !oldest.IsZero() && (older.Before.IsZero() || oldest.Before(older.Before)) {
+		older.Before = oldest
+	}
+	if older.Limit > 0 {
+		older.Limit -= len(out)
+	}
+	archived, err := archive.ListErrorRecords(ctx, older)
+	if err != nil {
+		return out, fmt.Errorf("ErrorJournal.Recent: %w", err)
+	}
+	return append(out, archived...), nil
+}
@@ -0,0 +1,107 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type stubErrorArchive struct {
+	saved   []ErrorRecord
+	lastReq ErrorRecordFilter
+	fail    error
+}
+
+func (a *stubErrorArchive) AppendErrorRecords(_ context.Context, records []ErrorRecord) error {
+	if a.fail != nil {
+		return a.fail
+	}
+	a.saved = append(a.saved, records...)
+	return nil
+}
+
+func (a *stubErrorArchive) ListErrorRecords(_ context.Context, filter ErrorRecordFilter) ([]ErrorRecord, error) {
+	a.lastReq = filter
+	var out []ErrorRecord
+	for i := len(a.saved) - 1; i >= 0; i-- {
+		if filter.Matches(a.saved[i]) && (filter.Limit <= 0 || len(out) < filter.Limit) {
+			out = append(out, a.saved[i])
+		}
+	}
+	return out, nil
+}
+
+func TestErrorJournalRingEvictsOldest(t *testing.T) {
+	t.Parallel()
+
+	j := NewErrorJournal(3)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		j.Record(ErrorRecord{At: base.Add(time.Duration(i) * time.Minute), Subsystem: "qotd", Message: string(rune('a' + i))})
+	}
+
+	got, err := j.Recent(context.Background(), ErrorRecordFilter{})
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	if len(got) != 3 || got[0].Message != "e" || got[2].Message != "c" {
+		t.Fatalf("expected newest three records newest first, got %+v", got)
+	}
+
+	got, _ = j.Recent(context.Background(), ErrorRecordFilter{Since: base.Add(4 * time.Minute)})
+	if len(got) != 1 || got[0].Message != "e" {
+		t.Fatalf("expected since filter to keep only the newest record, got %+v", got)
+	}
+	got, _ = j.Recent(context.Background(), ErrorRecordFilter{Subsystem: "stats"})
+	if len(got) != 0 {
+		t.Fatalf("expected subsystem filter to exclude everything, got %+v", got)
+	}
+}
+
+func TestErrorJournalFallsBackToArchive(t *testing.T) {
+	t.Parallel()
+
+	j := NewErrorJournal(2)
+	archive := &stubErrorArchive{}
+	j.SetArchive(archive)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 4 {
+		j.Record(ErrorRecord{At: base.Add(time.Duration(i) * time.Minute), Subsystem: "stats", Message: string(rune('a' + i))})
+		if err := j.Flush(context.Background()); err != nil {
+			t.Fatalf("Flush: %v", err)
+		}
+	}
+
+	got, err := j.Recent(context.Background(), ErrorRecordFilter{Limit: 3})
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	if len(got) != 3 || got[0].Message != "d" || got[1].Message != "c" || got[2].Message != "b" {
+		t.Fatalf("expected ring records followed by older archived ones, got %+v", got)
+	}
+	if !archive.lastReq.Before.Equal(base.Add(2*time.Minute)) || archive.lastReq.Limit != 1 {
+		t.Fatalf("expected archive lookup bounded by the ring, got %+v", archive.lastReq)
+	}
+}
+
+func TestErrorJournalFlushRetainsOnFailure(t *testing.T) {
+	t.Parallel()
+
+	j := NewErrorJournal(4)
+	archive := &stubErrorArchive{fail: errors.New("db down")}
+	j.SetArchive(archive)
+	j.Record(ErrorRecord{Subsystem: "stats", Message: "a"})
+	if err := j.Flush(context.Background()); err == nil {
+		t.Fatal("expected flush error")
+	}
+
+	archive.fail = nil
+	j.Record(ErrorRecord{Subsystem: "stats", Message: "b"})
+	if err := j.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if len(archive.saved) != 2 || archive.saved[0].Message != "a" {
+		t.Fatalf("expected retried records to be saved in order, got %+v", archive.saved)
+	}
+}
@@ -0,0 +1,45 @@
+package observability
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Process-wide tally of panics that were recovered at a handler boundary.
+// Unlike the per-domain metrics this registry is global: panic isolation
+// wraps handlers in several layers (gateway events, task router, startup
+// orchestrator) and operators want one place to see all of them.
+var (
+	recoveredPanicsMu sync.Mutex
+	recoveredPanics   map[string]*atomic.Int64
+)
+
+// RecoveredPanicSnapshot is the count of recovered panics per handler,
+// keyed as "<component>/<handler>".
+type RecoveredPanicSnapshot map[string]int64
+
+// RecordRecoveredPanic counts one recovered panic for handler within
+// component (e.g. "gateway", "members.member_add").
+func RecordRecoveredPanic(component, handler string) {
+	GetOrCreateLabeledCounter(&recoveredPanicsMu, &recoveredPanics, component+"/"+handler).Add(1)
+}
+
+// SnapshotRecoveredPanics returns the current recovered-panic counters.
+func SnapshotRecoveredPanics() RecoveredPanicSnapshot {
+	recoveredPanicsMu.Lock()
+	defer recoveredPanicsMu.Unlock()
+	snapshot := make(RecoveredPanicSnapshot, len(recoveredPanics))
+	for key, counter := range recoveredPanics {
+		snapshot[key] = counter.Load()
+	}
+	return snapshot
+}
+
+// Total sums every handler's recovered panics.
+func (s RecoveredPanicSnapshot) Total() int64 {
+	var total int64
+	for _, n := range s {
+		total += n
+	}
+	return total
+}
@@ -0,0 +1,100 @@
+package permissions
+
+import (
+	"fmt"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+// RolesFromDiscord indexes guild roles by ID.
+func RolesFromDiscord(roles []discord.Role) map[string]Role {
+	out := make(map[string]Role, len(roles))
+	for _, role := range roles {
+		id := role.ID.String()
+		out[id] = Role{ID: id, Position: role.Position, Permissions: int64(role.Permissions)}
+	}
+	return out
+}
+
+// MemberFromDiscord converts a guild member.
+func MemberFromDiscord(member discord.Member) *Member {
+	roleIDs := make([]string, 0, len(member.RoleIDs))
+	for _, roleID := range member.RoleIDs {
+		roleIDs = append(roleIDs, roleID.String())
+	}
+	return &Member{UserID: member.User.ID.String(), RoleIDs: roleIDs}
+}
+
+// OverwritesFromDiscord converts a channel's permission overwrites.
+func OverwritesFromDiscord(overwrites []discord.Overwrite) []Overwrite {
+	out := make([]Overwrite, 0, len(overwrites))
+	for _, ow := range overwrites {
+		kind := OverwriteRole
+		if ow.Type == discord.OverwriteMember {
+			kind = OverwriteMember
+		}
+		out = append(out, Overwrite{
+			ID:    ow.ID.String(),
+			Type:  kind,
+			Allow: int64(ow.Allow),
+			Deny:  int64(ow.Deny),
+		})
+	}
+	return out
+}
+
+// Source is the Discord data ResolveInChannel reads. *api.Client satisfies it.
+type Source interface {
+	Guild(guildID discord.GuildID) (*discord.Guild, error)
+	Member(guildID discord.GuildID, userID discord.UserID) (*discord.Member, error)
+	Channel(channelID discord.ChannelID) (*discord.Channel, error)
+}
+
+// Resolution is a member's permissions in one channel.
+type Resolution struct {
+	// Owner is set when the member owns the guild.
+	Owner bool
+	// Base holds the guild-level permissions, before channel overwrites.
+	Base int64
+	// Effective holds the permissions in the channel.
+	Effective int64
+}
+
+// ResolveInChannel computes userID's permissions in channelID, applying the
+// channel's overwrites. Threads have no overwrites of their own and resolve
+// against their parent channel.
+func ResolveInChannel(src Source, guildID discord.GuildID, userID discord.UserID, channelID discord.ChannelID) (Resolution, error) {
+	guild, err := src.Guild(guildID)
+	if err != nil {
+		return Resolution{}, fmt.Errorf("ResolveInChannel: fetch guild: %w", err)
+	}
+	member, err := src.Member(guildID, userID)
+	if err != nil {
+		return Resolution{}, fmt.Errorf("ResolveInChannel: fetch member: %w", err)
+	}
+	channel, err := src.Channel(channelID)
+	if err != nil {
+		return Resolution{}, fmt.Errorf("ResolveInChannel: fetch channel: %w", err)
+	}
+	if channel.GuildID != guildID {
+		return Resolution{}, fmt.Errorf("ResolveInChannel: channel %s is not in guild %s", channelID, guildID)
+	}
+	if isThread(channel.Type) && channel.ParentID.IsValid() {
+		if channel, err = src.Channel(channel.ParentID); err != nil {
+			return Resolution{}, fmt.Errorf("ResolveInChannel: fetch thread parent: %w", err)
+		}
+	}
+
+	member.User.ID = userID
+	subject := MemberFromDiscord(*member)
+	base := Base(subject, guildID.String(), guild.OwnerID.String(), RolesFromDiscord(guild.Roles))
+	return Resolution{
+		Owner:     guild.OwnerID == userID,
+		Base:      base,
+		Effective: InChannel(base, subject, guildID.String(), OverwritesFromDiscord(channel.Overwrites)),
+	}, nil
+}
+
+func isThread(t discord.ChannelType) bool {
+	return t == discord.GuildPublicThread || t == discord.GuildPrivateThread || t == discord.GuildAnnouncementThread
+}
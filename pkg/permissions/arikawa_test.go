@@ -0,0 +1,58 @@
+package permissions
+
+import (
+	"testing"
+
+	"github.com/diamondburned/arikawa/v3/discord"
+)
+
+type fakeSource struct {
+	guild    discord.Guild
+	member   discord.Member
+	channels map[discord.ChannelID]discord.Channel
+}
+
+func (f fakeSource) Guild(discord.GuildID) (*discord.Guild, error) { return &f.guild, nil }
+
+func (f fakeSource) Member(discord.GuildID, discord.UserID) (*discord.Member, error) {
+	return &f.member, nil
+}
+
+func (f fakeSource) Channel(id discord.ChannelID) (*discord.Channel, error) {
+	ch := f.channels[id]
+	return &ch, nil
+}
+
+func TestResolveInChannel(t *testing.T) {
+	t.Parallel()
+
+	const guildID, userID = discord.GuildID(1), discord.UserID(2)
+	src := fakeSource{
+		guild: discord.Guild{
+			ID:      guildID,
+			OwnerID: 99,
+			Roles: []discord.Role{
+				{ID: discord.RoleID(guildID), Permissions: discord.PermissionViewChannel | discord.PermissionManageMessages},
+			},
+		},
+		member: discord.Member{User: discord.User{ID: userID}},
+		channels: map[discord.ChannelID]discord.Channel{
+			10: {ID: 10, GuildID: guildID, Type: discord.GuildText, Overwrites: []discord.Overwrite{
+				{ID: discord.Snowflake(userID), Type: discord.OverwriteMember, Deny: discord.PermissionManageMessages},
+			}},
+			20: {ID: 20, GuildID: 5, Type: discord.GuildText},
+		},
+	}
+
+	res, err := ResolveInChannel(src, guildID, userID, 10)
+	if err != nil {
+		t.Fatalf("ResolveInChannel: %v", err)
+	}
+	if !Has(res.Base, int64(discord.PermissionManageMessages)) || Has(res.Effective, int64(discord.PermissionManageMessages)) {
+		t.Fatalf("expected member overwrite to deny Manage Messages in the channel only, got %+v", res)
+	}
+
+	if _, err := ResolveInChannel(src, guildID, userID, 20); err == nil {
+		t.Fatal("expected a channel from another guild to be rejected")
+	}
+}
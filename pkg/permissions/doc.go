@@ -0,0 +1,11 @@
+/*
+Package permissions computes effective Discord permissions in a Discord-agnostic
+manner.
+
+Permissions are resolved the way Discord documents it: the guild base from the
+@everyone role and the member's roles, then the channel overwrites for
+@everyone, for the member's roles, and for the member itself. Bits use
+Discord's numeric values; the FromDiscord helpers convert arikawa guild,
+member and channel data into the package's plain types.
+*/
+package permissions
@@ -0,0 +1,72 @@
+package permissions
+
+// flagNames lists Discord permission bits in display order.
+var flagNames = []struct {
+	bit  int64
+	name string
+}{
+	{1 << 0, "Create Invite"},
+	{1 << 1, "Kick Members"},
+	{1 << 2, "Ban Members"},
+	{1 << 3, "Administrator"},
+	{1 << 4, "Manage Channels"},
+	{1 << 5, "Manage Server"},
+	{1 << 6, "Add Reactions"},
+	{1 << 7, "View Audit Log"},
+	{1 << 8, "Priority Speaker"},
+	{1 << 9, "Video"},
+	{1 << 10, "View Channel"},
+	{1 << 11, "Send Messages"},
+	{1 << 12, "Send TTS Messages"},
+	{1 << 13, "Manage Messages"},
+	{1 << 14, "Embed Links"},
+	{1 << 15, "Attach Files"},
+	{1 << 16, "Read Message History"},
+	{1 << 17, "Mention Everyone"},
+	{1 << 18, "Use External Emojis"},
+	{1 << 19, "View Server Insights"},
+	{1 << 20, "Connect"},
+	{1 << 21, "Speak"},
+	{1 << 22, "Mute Members"},
+	{1 << 23, "Deafen Members"},
+	{1 << 24, "Move Members"},
+	{1 << 25, "Use Voice Activity"},
+	{1 << 26, "Change Nickname"},
+	{1 << 27, "Manage Nicknames"},
+	{1 << 28, "Manage Roles"},
+	{1 << 29, "Manage Webhooks"},
+	{1 << 30, "Manage Expressions"},
+	{1 << 31, "Use Application Commands"},
+	{1 << 32, "Request to Speak"},
+	{1 << 33, "Manage Events"},
+	{1 << 34, "Manage Threads"},
+	{1 << 35, "Create Public Threads"},
+	{1 << 36, "Create Private Threads"},
+	{1 << 37, "Use External Stickers"},
+	{1 << 38, "Send Messages in Threads"},
+	{1 << 39, "Use Activities"},
+	{1 << 40, "Timeout Members"},
+	{1 << 41, "View Creator Monetization Analytics"},
+	{1 << 42, "Use Soundboard"},
+	{1 << 45, "Use External Sounds"},
+	{1 << 46, "Send Voice Messages"},
+}
+
+// Names returns the display names of the known bits set in perms.
+func Names(perms int64) []string {
+	var names []string
+	for _, flag := range flagNames {
+		if perms&flag.bit != 0 {
+			names = append(names, flag.name)
+		}
+	}
+	return names
+}
+
+// Unnamed returns the bits set in perms that Names has no name for.
+func Unnamed(perms int64) int64 {
+	for _, flag := range flagNames {
+		perms &^= flag.bit
+	}
+	return perms
+}
@@ -0,0 +1,128 @@
+package permissions
+
+// Role defines the properties of a guild role needed to evaluate hierarchy and
+// permissions.
+type Role struct {
+	ID          string
+	Position    int
+	Permissions int64
+}
+
+// Member defines the properties of a guild member needed to evaluate
+// permissions.
+type Member struct {
+	UserID  string
+	RoleIDs []string
+}
+
+// OverwriteType tells whether a channel overwrite targets a role or a member.
+type OverwriteType int
+
+// OverwriteRole targets a role; the guild ID targets @everyone.
+// OverwriteMember targets a single member.
+const (
+	OverwriteRole OverwriteType = iota
+	OverwriteMember
+)
+
+// Overwrite is a channel permission overwrite.
+type Overwrite struct {
+	ID    string
+	Type  OverwriteType
+	Allow int64
+	Deny  int64
+}
+
+const (
+	// Administrator is the equivalent of the Discord Administrator flag (0x00000008).
+	Administrator int64 = 0x00000008
+	// All has every permission bit set.
+	All int64 = 1<<63 - 1
+)
+
+// Base returns the guild-level permissions of member: the union of the
+// @everyone role (guildID) and every assigned role. The guild owner and
+// administrators receive All.
+func Base(member *Member, guildID, ownerID string, rolesByID map[string]Role) int64 {
+	if member == nil {
+		return 0
+	}
+	if ownerID != "" && member.UserID == ownerID {
+		return All
+	}
+
+	var perms int64
+	if everyone, ok := rolesByID[guildID]; ok {
+		perms |= everyone.Permissions
+	}
+	for _, roleID := range member.RoleIDs {
+		if role, ok := rolesByID[roleID]; ok {
+			perms |= role.Permissions
+		}
+	}
+	if perms&Administrator != 0 {
+		return All
+	}
+	return perms
+}
+
+// InChannel applies channel overwrites to base. Overwrites apply in Discord's
+// order: @everyone, then all of the member's roles together (denies before
+// allows), then the member's own overwrite. Administrators bypass overwrites.
+func InChannel(base int64, member *Member, guildID string, overwrites []Overwrite) int64 {
+	if member == nil {
+		return 0
+	}
+	if base&Administrator != 0 || base == All {
+		return All
+	}
+
+	perms := base
+	for _, ow := range overwrites {
+		if ow.Type == OverwriteRole && ow.ID == guildID {
+			perms &^= ow.Deny
+			perms |= ow.Allow
+			break
+		}
+	}
+
+	var roleAllow, roleDeny int64
+	for _, ow := range overwrites {
+		if ow.Type != OverwriteRole || ow.ID == guildID {
+			continue
+		}
+		for _, roleID := range member.RoleIDs {
+			if roleID == ow.ID {
+				roleAllow |= ow.Allow
+				roleDeny |= ow.Deny
+				break
+			}
+		}
+	}
+	perms &^= roleDeny
+	perms |= roleAllow
+
+	for _, ow := range overwrites {
+		if ow.Type == OverwriteMember && ow.ID == member.UserID {
+			perms &^= ow.Deny
+			perms |= ow.Allow
+			break
+		}
+	}
+	return perms
+}
+
+// Effective returns member's permissions in a channel with the given
+// overwrites. A nil overwrite list yields the guild-level permissions.
+func Effective(member *Member, guildID, ownerID string, rolesByID map[string]Role, overwrites []Overwrite) int64 {
+	return InChannel(Base(member, guildID, ownerID, rolesByID), member, guildID, overwrites)
+}
+
+// Has reports whether perms grants every bit of required. Administrator
+// grants everything.
+func Has(perms, required int64) bool {
+	if perms&Administrator != 0 {
+		return true
+	}
+	return perms&required == required
+}
@@ -0,0 +1,106 @@
+package permissions
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEffective(t *testing.T) {
+	t.Parallel()
+	const (
+		guildID        = "g"
+		view           = int64(1 << 10)
+		send           = int64(1 << 11)
+		manageMessages = int64(1 << 13)
+	)
+
+	roles := map[string]Role{
+		guildID: {ID: guildID, Permissions: view | send},
+		"mod":   {ID: "mod", Permissions: manageMessages},
+		"muted": {ID: "muted"},
+		"admin": {ID: "admin", Permissions: Administrator},
+	}
+
+	tests := []struct {
+		name       string
+		member     *Member
+		owner      string
+		overwrites []Overwrite
+		want       int64
+	}{
+		{
+			name:   "guild base",
+			member: &Member{UserID: "u", RoleIDs: []string{"mod"}},
+			want:   view | send | manageMessages,
+		},
+		{
+			name:       "everyone deny",
+			member:     &Member{UserID: "u"},
+			overwrites: []Overwrite{{ID: guildID, Type: OverwriteRole, Deny: send}},
+			want:       view,
+		},
+		{
+			name:   "role allow beats role deny",
+			member: &Member{UserID: "u", RoleIDs: []string{"mod", "muted"}},
+			overwrites: []Overwrite{
+				{ID: "muted", Type: OverwriteRole, Deny: send},
+				{ID: "mod", Type: OverwriteRole, Allow: send},
+			},
+			want: view | send | manageMessages,
+		},
+		{
+			name:   "member overwrite applies last",
+			member: &Member{UserID: "u", RoleIDs: []string{"mod"}},
+			overwrites: []Overwrite{
+				{ID: "mod", Type: OverwriteRole, Allow: send},
+				{ID: "u", Type: OverwriteMember, Deny: send | manageMessages},
+			},
+			want: view,
+		},
+		{
+			name:       "administrator bypasses overwrites",
+			member:     &Member{UserID: "u", RoleIDs: []string{"admin"}},
+			overwrites: []Overwrite{{ID: guildID, Type: OverwriteRole, Deny: view}},
+			want:       All,
+		},
+		{
+			name:       "owner bypasses overwrites",
+			member:     &Member{UserID: "owner"},
+			owner:      "owner",
+			overwrites: []Overwrite{{ID: "owner", Type: OverwriteMember, Deny: view}},
+			want:       All,
+		},
+		{
+			name:   "nil member",
+			member: nil,
+			want:   0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := Effective(tc.member, guildID, tc.owner, roles, tc.overwrites); got != tc.want {
+				t.Fatalf("Effective = %b, want %b", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHasAndNames(t *testing.T) {
+	t.Parallel()
+
+	perms := int64(1<<10 | 1<<13)
+	if !Has(perms, 1<<13) || Has(perms, 1<<13|1<<11) {
+		t.Fatal("Has must require every requested bit")
+	}
+	if !Has(Administrator, 1<<2) {
+		t.Fatal("expected administrator to satisfy any requirement")
+	}
+	if got := Names(perms); !slices.Equal(got, []string{"View Channel", "Manage Messages"}) {
+		t.Fatalf("Names = %v", got)
+	}
+	if got := Unnamed(perms | 1<<50); got != 1<<50 {
+		t.Fatalf("Unnamed = %#x, want %#x", got, int64(1<<50))
+	}
+}
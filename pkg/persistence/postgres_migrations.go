@@ -776,4 +776,323 @@ var postgresMigrations = []migration{
 			`DROP TABLE IF EXISTS user_preferences`,
 		},
 	},
+	{
+		Version: 29,
+		UpSQL: []string{
+			`CREATE TABLE IF NOT EXISTS error_log (
+				id          BIGSERIAL PRIMARY KEY,
+				occurred_at TIMESTAMPTZ NOT NULL,
+				subsystem   TEXT NOT NULL,
+				message     TEXT NOT NULL,
+				detail      TEXT NOT NULL DEFAULT ''
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_error_log_occurred ON error_log(occurred_at DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_error_log_subsystem ON error_log(subsystem, occurred_at DESC)`,
+		},
+		DownSQL: []string{
+			`DROP TABLE IF EXISTS error_log`,
+		},
+	},
+	{
+		Version: 30,
+		UpSQL: []string{
+			`CREATE TABLE IF NOT EXISTS command_audit (
+				id          BIGSERIAL PRIMARY KEY,
+				occurred_at TIMESTAMPTZ NOT NULL,
+				guild_id    TEXT NOT NULL DEFAULT '',
+				channel_id  TEXT NOT NULL DEFAULT '',
+				user_id     TEXT NOT NULL,
+				command     TEXT NOT NULL,
+				options     JSONB NOT NULL DEFAULT '{}'::jsonb,
+				outcome     TEXT NOT NULL,
+				error       TEXT NOT NULL DEFAULT '',
+				duration_ms BIGINT NOT NULL DEFAULT 0
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_command_audit_guild ON command_audit(guild_id, occurred_at DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_command_audit_user ON command_audit(user_id, occurred_at DESC)`,
+		},
+		DownSQL: []string{
+			`DROP TABLE IF EXISTS command_audit`,
+		},
+	},
+	{
+		Version: 31,
+		UpSQL: []string{
+			`CREATE TABLE IF NOT EXISTS moderation_case_records (
+				id              BIGINT PRIMARY KEY,
+				guild_id        TEXT NOT NULL,
+				case_number     BIGINT NOT NULL,
+				action          TEXT NOT NULL,
+				user_id         TEXT NOT NULL,
+				moderator_id    TEXT NOT NULL DEFAULT '',
+				reason          TEXT NOT NULL DEFAULT '',
+				source          TEXT NOT NULL,
+				channel_id      TEXT NOT NULL DEFAULT '',
+				message_id      TEXT NOT NULL DEFAULT '',
+				rule_id         TEXT NOT NULL DEFAULT '',
+				matched_keyword TEXT NOT NULL DEFAULT '',
+				matched_content TEXT NOT NULL DEFAULT '',
+				content         TEXT NOT NULL DEFAULT '',
+				created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_moderation_case_records_case ON moderation_case_records(guild_id, case_number)`,
+			`CREATE INDEX IF NOT EXISTS idx_moderation_case_records_user ON moderation_case_records(guild_id, user_id, created_at DESC)`,
+		},
+		DownSQL: []string{
+			`DROP TABLE IF EXISTS moderation_case_records`,
+		},
+	},
+	{
+		Version: 32,
+		UpSQL: []string{
+			`ALTER TABLE moderation_case_records ADD COLUMN IF NOT EXISTS log_channel_id TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE moderation_case_records ADD COLUMN IF NOT EXISTS log_message_id TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE moderation_case_records ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()`,
+			`ALTER TABLE moderation_case_records ADD COLUMN IF NOT EXISTS voided_at TIMESTAMPTZ`,
+			`ALTER TABLE moderation_case_records ADD COLUMN IF NOT EXISTS voided_by TEXT NOT NULL DEFAULT ''`,
+		},
+		DownSQL: []string{
+			`ALTER TABLE moderation_case_records DROP COLUMN IF EXISTS voided_by`,
+			`ALTER TABLE moderation_case_records DROP COLUMN IF EXISTS voided_at`,
+			`ALTER TABLE moderation_case_records DROP COLUMN IF EXISTS updated_at`,
+			`ALTER TABLE moderation_case_records DROP COLUMN IF EXISTS log_message_id`,
+			`ALTER TABLE moderation_case_records DROP COLUMN IF EXISTS log_channel_id`,
+		},
+	},
+	{
+		Version: 33,
+		UpSQL: []string{
+			`CREATE TABLE IF NOT EXISTS moderation_notes (
+				id         BIGSERIAL PRIMARY KEY,
+				guild_id   TEXT NOT NULL,
+				user_id    TEXT NOT NULL,
+				author_id  TEXT NOT NULL,
+				content    TEXT NOT NULL,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_moderation_notes_user ON moderation_notes(guild_id, user_id, created_at DESC)`,
+		},
+		DownSQL: []string{
+			`DROP INDEX IF EXISTS idx_moderation_notes_user`,
+			`DROP TABLE IF EXISTS moderation_notes`,
+		},
+	},
+	{
+		Version: 34,
+		UpSQL: []string{
+			`CREATE TABLE IF NOT EXISTS leader_leases (
+				name       TEXT PRIMARY KEY,
+				holder     TEXT NOT NULL,
+				expires_at TIMESTAMPTZ NOT NULL
+			)`,
+		},
+		DownSQL: []string{
+			`DROP TABLE IF EXISTS leader_leases`,
+		},
+	},
+	{
+		Version: 35,
+		UpSQL: []string{
+			`CREATE TABLE IF NOT EXISTS channel_locks (
+				guild_id      TEXT NOT NULL,
+				channel_id    TEXT NOT NULL,
+				had_overwrite BOOLEAN NOT NULL,
+				allow_bits    BIGINT NOT NULL DEFAULT 0,
+				deny_bits     BIGINT NOT NULL DEFAULT 0,
+				locked_by     TEXT NOT NULL DEFAULT '',
+				locked_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				PRIMARY KEY (guild_id, channel_id)
+			)`,
+		},
+		DownSQL: []string{
+			`DROP TABLE IF EXISTS channel_locks`,
+		},
+	},
+	{
+		Version: 36,
+		UpSQL: []string{
+			`ALTER TABLE messages ADD COLUMN IF NOT EXISTS content_hash TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE messages ADD COLUMN IF NOT EXISTS content_length INTEGER NOT NULL DEFAULT 0`,
+		},
+		DownSQL: []string{
+			`ALTER TABLE messages DROP COLUMN IF EXISTS content_length`,
+			`ALTER TABLE messages DROP COLUMN IF EXISTS content_hash`,
+		},
+	},
+	{
+		Version: 37,
+		UpSQL: []string{
+			`CREATE TABLE IF NOT EXISTS clean_logs (
+				id           BIGSERIAL PRIMARY KEY,
+				guild_id     TEXT NOT NULL,
+				channel_id   TEXT NOT NULL,
+				requested_by TEXT NOT NULL DEFAULT '',
+				messages     JSONB NOT NULL,
+				created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_clean_logs_guild_created ON clean_logs (guild_id, created_at)`,
+		},
+		DownSQL: []string{
+			`DROP TABLE IF EXISTS clean_logs`,
+		},
+	},
+	{
+		Version: 38,
+		UpSQL: []string{
+			`ALTER TABLE moderation_case_records ADD COLUMN IF NOT EXISTS extra TEXT NOT NULL DEFAULT ''`,
+		},
+		DownSQL: []string{
+			`ALTER TABLE moderation_case_records DROP COLUMN IF EXISTS extra`,
+		},
+	},
+	{
+		Version: 39,
+		UpSQL: []string{
+			`ALTER TABLE moderation_case_records ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ`,
+			`ALTER TABLE moderation_case_records ADD COLUMN IF NOT EXISTS expiry_logged_at TIMESTAMPTZ`,
+			`CREATE INDEX IF NOT EXISTS idx_moderation_case_records_expiry ON moderation_case_records (guild_id, expires_at)
+				WHERE expires_at IS NOT NULL AND expiry_logged_at IS NULL`,
+		},
+		DownSQL: []string{
+			`DROP INDEX IF EXISTS idx_moderation_case_records_expiry`,
+			`ALTER TABLE moderation_case_records DROP COLUMN IF EXISTS expiry_logged_at`,
+			`ALTER TABLE moderation_case_records DROP COLUMN IF EXISTS expires_at`,
+		},
+	},
+	{
+		Version: 40,
+		UpSQL: []string{
+			`CREATE TABLE IF NOT EXISTS raid_modes (
+				guild_id                TEXT PRIMARY KEY,
+				enabled_by              TEXT NOT NULL DEFAULT '',
+				enabled_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				expires_at              TIMESTAMPTZ NOT NULL,
+				previous_verification   INTEGER NOT NULL DEFAULT 0,
+				raised_verification     BOOLEAN NOT NULL DEFAULT FALSE,
+				invites_paused          BOOLEAN NOT NULL DEFAULT FALSE,
+				min_account_age_seconds BIGINT NOT NULL DEFAULT 0
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_raid_modes_expires ON raid_modes (expires_at)`,
+		},
+		DownSQL: []string{
+			`DROP TABLE IF EXISTS raid_modes`,
+		},
+	},
+	{
+		Version: 41,
+		UpSQL: []string{
+			`CREATE TABLE IF NOT EXISTS guild_member_counts (
+				guild_id    TEXT NOT NULL,
+				granularity TEXT NOT NULL,
+				bucket      TIMESTAMPTZ NOT NULL,
+				members     BIGINT NOT NULL,
+				humans      BIGINT NOT NULL,
+				bots        BIGINT NOT NULL,
+				recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				PRIMARY KEY (guild_id, granularity, bucket)
+			)`,
+		},
+		DownSQL: []string{
+			`DROP TABLE IF EXISTS guild_member_counts`,
+		},
+	},
+	{
+		Version: 42,
+		UpSQL: []string{
+			`CREATE TABLE IF NOT EXISTS pooled_bans (
+				id              BIGSERIAL PRIMARY KEY,
+				pools           TEXT[] NOT NULL,
+				origin_guild_id TEXT NOT NULL,
+				origin_case     BIGINT NOT NULL DEFAULT 0,
+				user_id         TEXT NOT NULL,
+				moderator_id    TEXT NOT NULL DEFAULT '',
+				reason          TEXT NOT NULL DEFAULT '',
+				created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_pooled_bans_pools ON pooled_bans USING GIN (pools)`,
+			`CREATE INDEX IF NOT EXISTS idx_pooled_bans_created ON pooled_bans (created_at)`,
+			`CREATE TABLE IF NOT EXISTS pooled_ban_deliveries (
+				ban_id       BIGINT NOT NULL REFERENCES pooled_bans (id) ON DELETE CASCADE,
+				guild_id     TEXT NOT NULL,
+				case_number  BIGINT NOT NULL DEFAULT 0,
+				delivered_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				PRIMARY KEY (ban_id, guild_id)
+			)`,
+		},
+		DownSQL: []string{
+			`DROP TABLE IF EXISTS pooled_ban_deliveries`,
+			`DROP TABLE IF EXISTS pooled_bans`,
+		},
+	},
+	{
+		Version: 43,
+		UpSQL: []string{
+			`CREATE TABLE IF NOT EXISTS activity_rollups (
+				guild_id     TEXT NOT NULL,
+				period       TEXT NOT NULL,
+				period_start DATE NOT NULL,
+				period_end   DATE NOT NULL,
+				messages     BIGINT NOT NULL DEFAULT 0,
+				joins        BIGINT NOT NULL DEFAULT 0,
+				leaves       BIGINT NOT NULL DEFAULT 0,
+				computed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				PRIMARY KEY (guild_id, period, period_start)
+			)`,
+		},
+		DownSQL: []string{
+			`DROP TABLE IF EXISTS activity_rollups`,
+		},
+	},
+	{
+		Version: 44,
+		UpSQL: []string{
+			`CREATE TABLE IF NOT EXISTS task_idempotency_keys (
+				key        TEXT PRIMARY KEY,
+				expires_at TIMESTAMPTZ NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_task_idempotency_keys_expires ON task_idempotency_keys (expires_at)`,
+		},
+		DownSQL: []string{
+			`DROP TABLE IF EXISTS task_idempotency_keys`,
+		},
+	},
+	{
+		Version: 45,
+		UpSQL: []string{
+			`CREATE TABLE IF NOT EXISTS member_names_current (
+				guild_id    TEXT NOT NULL,
+				user_id     TEXT NOT NULL,
+				username    TEXT NOT NULL DEFAULT '',
+				global_name TEXT NOT NULL DEFAULT '',
+				nick        TEXT NOT NULL DEFAULT '',
+				updated_at  TIMESTAMPTZ NOT NULL,
+				PRIMARY KEY (guild_id, user_id)
+			)`,
+		},
+		DownSQL: []string{
+			`DROP TABLE IF EXISTS member_names_current`,
+		},
+	},
+	{
+		Version: 46,
+		UpSQL: []string{
+			`CREATE TABLE IF NOT EXISTS log_events (
+				id             BIGSERIAL PRIMARY KEY,
+				occurred_at    TIMESTAMPTZ NOT NULL,
+				guild_id       TEXT NOT NULL,
+				event_type     TEXT NOT NULL,
+				user_id        TEXT NOT NULL DEFAULT '',
+				actor_id       TEXT NOT NULL DEFAULT '',
+				channel_id     TEXT NOT NULL DEFAULT '',
+				log_channel_id TEXT NOT NULL DEFAULT '',
+				title          TEXT NOT NULL DEFAULT '',
+				summary        TEXT NOT NULL DEFAULT ''
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_log_events_guild ON log_events(guild_id, occurred_at DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_log_events_user ON log_events(guild_id, user_id, occurred_at DESC)`,
+		},
+		DownSQL: []string{
+			`DROP TABLE IF EXISTS log_events`,
+		},
+	},
 }
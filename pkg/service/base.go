@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"log/slog"
+
+	"github.com/small-frappuccino/discordcore/pkg/observability"
 )
 
 // BaseService provides common functionality for all services. Services that
@@ -435,6 +437,7 @@ func (ms *ManagedService) HandleError(err error) {
 			}
 			if restartErr := ms.manager.RestartService(ctx, ms.name); restartErr != nil {
 				ms.log().Error("Failed to restart service after error", "service", ms.name, "err", restartErr)
+				observability.ReportOperationalAlert(observability.AlertServiceCrash, ms.name, restartErr)
 			}
 		})
 	}
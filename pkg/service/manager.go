@@ -11,6 +11,7 @@ import (
 
 	"log/slog"
 
+	"github.com/small-frappuccino/discordcore/pkg/observability"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -609,11 +610,14 @@ func (sm *ServiceManager) checkServiceHealth(info *ServiceInfo) {
 				sm.log().Warn("Attempting to restart unhealthy service", "service", info.Service.Name())
 				if err := sm.RestartService(ctx, info.Service.Name()); err != nil {
 					sm.log().Error("Failed to restart unhealthy service", "service", info.Service.Name(), "err", err)
+					observability.ReportOperationalAlert(observability.AlertServiceCrash, info.Service.Name(), err)
 				}
 			})
 		} else {
 			sm.mu.Unlock()
 			sm.log().Error("Service exceeded maximum restart attempts", "service", info.Service.Name())
+			observability.ReportOperationalAlert(observability.AlertServiceCrash, info.Service.Name(),
+				fmt.Errorf("unhealthy after %d restarts: %s", sm.maxRestarts, health.Message))
 		}
 	}
 }
@@ -9,6 +9,7 @@ import (
 
 	"log/slog"
 
+	"github.com/small-frappuccino/discordcore/pkg/observability"
 	"github.com/small-frappuccino/discordcore/pkg/system"
 )
 
@@ -101,8 +102,11 @@ func (ra *RuntimeActivity) MarkEvent(ctx context.Context, source string) {
 
 	if err := ra.runErr(ctx, ra.eventTimeout, func(runCtx context.Context) error {
 		return ra.store.SetLastEventForBot(runCtx, ra.botInstanceID, ra.now())
-	}); err != nil && ra.logger != nil {
-		ra.logger.LogAttrs(ctx, slog.LevelWarn, "Failed to persist last event timestamp", slog.String("source", source), slog.Any("error", err))
+	}); err != nil {
+		observability.ReportOperationalAlert(observability.AlertDatabaseError, "runtime_activity.last_event", err)
+		if ra.logger != nil {
+			ra.logger.LogAttrs(ctx, slog.LevelWarn, "Failed to persist last event timestamp", slog.String("source", source), slog.Any("error", err))
+		}
 	}
 }
 
@@ -166,8 +170,11 @@ func (ra *RuntimeActivity) attemptHeartbeat(ctx context.Context, failureMessage
 	err := ra.runErr(ctx, ra.heartbeatTimeout, func(runCtx context.Context) error {
 		return ra.store.SetHeartbeatForBot(runCtx, ra.botInstanceID, ra.now())
 	})
-	if err != nil && ra.logger != nil {
-		ra.logger.LogAttrs(ctx, slog.LevelWarn, failureMessage, slog.Any("error", err))
+	if err != nil {
+		observability.ReportOperationalAlert(observability.AlertDatabaseError, "runtime_activity.heartbeat", err)
+		if ra.logger != nil {
+			ra.logger.LogAttrs(ctx, slog.LevelWarn, failureMessage, slog.Any("error", err))
+		}
 	}
 	if ra.onHeartbeatTick != nil {
 		ra.onHeartbeatTick(err)
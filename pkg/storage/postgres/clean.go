@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/clean"
+)
+
+// maxCleanLogExport bounds how many cleans one export returns.
+const maxCleanLogExport = 50
+
+// SaveCleanLog keeps the messages a /clean deleted so they can be exported
+// later.
+func (s *Store) SaveCleanLog(ctx context.Context, guildID string, archive clean.Archive) error {
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" || archive.ChannelID == "" {
+		return fmt.Errorf("missing required fields for clean log")
+	}
+	if archive.CreatedAt.IsZero() {
+		archive.CreatedAt = time.Now()
+	}
+	messages, err := json.Marshal(archive.Messages)
+	if err != nil {
+		return fmt.Errorf("Store.SaveCleanLog: %w", err)
+	}
+	_, err = s.db.Exec(ctx,
+		`INSERT INTO clean_logs (guild_id, channel_id, requested_by, messages, created_at)
+         VALUES ($1, $2, $3, $4, $5)`,
+		guildID, archive.ChannelID, archive.RequestedBy, messages, archive.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("Store.SaveCleanLog: %w", err)
+	}
+	return nil
+}
+
+// ListCleanLogs returns the cleans of a guild since the given time, newest
+// first. An empty channelID covers every channel.
+func (s *Store) ListCleanLogs(ctx context.Context, guildID, channelID string, since time.Time) ([]clean.Archive, error) {
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" {
+		return nil, nil
+	}
+	rows, err := s.db.Query(ctx,
+		`SELECT channel_id, requested_by, messages, created_at
+         FROM clean_logs
+         WHERE guild_id=$1 AND ($2='' OR channel_id=$2) AND created_at >= $3
+         ORDER BY created_at DESC, id DESC
+         LIMIT $4`,
+		guildID, strings.TrimSpace(channelID), since.UTC(), maxCleanLogExport,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Store.ListCleanLogs: %w", err)
+	}
+	defer rows.Close()
+
+	var out []clean.Archive
+	for rows.Next() {
+		var archive clean.Archive
+		var messages []byte
+		if err := rows.Scan(&archive.ChannelID, &archive.RequestedBy, &messages, &archive.CreatedAt); err != nil {
+			return nil, fmt.Errorf("Store.ListCleanLogs: %w", err)
+		}
+		if err := json.Unmarshal(messages, &archive.Messages); err != nil {
+			return nil, fmt.Errorf("Store.ListCleanLogs: decode messages: %w", err)
+		}
+		archive.CreatedAt = archive.CreatedAt.UTC()
+		out = append(out, archive)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Store.ListCleanLogs: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/small-frappuccino/discordcore/pkg/clean"
+)
+
+func TestStore_CleanLogs(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+	now := time.Now()
+
+	archive := clean.Archive{
+		ChannelID:   "c1",
+		RequestedBy: "mod1",
+		CreatedAt:   now,
+		Messages:    []clean.ArchiveEntry{{ID: "1", AuthorID: "u1", Content: "spam", Timestamp: now.UTC()}},
+	}
+	mock.ExpectExec(`INSERT INTO clean_logs`).
+		WithArgs("g1", "c1", "mod1", pgxmock.AnyArg(), now.UTC()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectQuery(`SELECT .* FROM clean_logs`).
+		WithArgs("g1", "", now.Add(-time.Hour).UTC(), maxCleanLogExport).
+		WillReturnRows(pgxmock.NewRows([]string{"channel_id", "requested_by", "messages", "created_at"}).
+			AddRow("c1", "mod1", []byte(`[{"id":"1","author_id":"u1","content":"spam","timestamp":"2026-06-20T12:00:00Z"}]`), now))
+
+	if err := store.SaveCleanLog(context.Background(), "g1", archive); err != nil {
+		t.Fatalf("SaveCleanLog: %v", err)
+	}
+	logs, err := store.ListCleanLogs(context.Background(), "g1", "", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListCleanLogs: %v", err)
+	}
+	if len(logs) != 1 || logs[0].RequestedBy != "mod1" || len(logs[0].Messages) != 1 || logs[0].Messages[0].Content != "spam" {
+		t.Fatalf("ListCleanLogs = %+v", logs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
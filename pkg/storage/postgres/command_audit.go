@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/system"
+)
+
+// defaultCommandAuditLimit caps ListCommandAudit when the filter sets none.
+const defaultCommandAuditLimit = 50
+
+// AppendCommandAudit records one privileged command execution.
+func (s *Store) AppendCommandAudit(ctx context.Context, rec system.CommandAuditRecord) error {
+	if rec.At.IsZero() {
+		rec.At = time.Now()
+	}
+	options := strings.TrimSpace(rec.Options)
+	if options == "" {
+		options = "{}"
+	}
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO command_audit (occurred_at, guild_id, channel_id, user_id, command, options, outcome, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7, $8, $9)
+	`, rec.At.UTC(), rec.GuildID, rec.ChannelID, rec.UserID, rec.Command, options, rec.Outcome, rec.Error, rec.Duration.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("Store.AppendCommandAudit: %w", err)
+	}
+	return nil
+}
+
+// ListCommandAudit returns audited command executions matching filter,
+// newest first.
+func (s *Store) ListCommandAudit(ctx context.Context, filter system.CommandAuditFilter) ([]system.CommandAuditRecord, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	if guildID := strings.TrimSpace(filter.GuildID); guildID != "" {
+		args = append(args, guildID)
+		conds = append(conds, fmt.Sprintf("guild_id = $%d", len(args)))
+	}
+	if userID := strings.TrimSpace(filter.UserID); userID != "" {
+		args = append(args, userID)
+		conds = append(conds, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+	if command := strings.TrimSpace(filter.Command); command != "" {
+		// A command filter matches the command itself and any of its
+		// subcommands: "admin" covers "admin token rotate".
+		args = append(args, command, command+" %")
+		conds = append(conds, fmt.Sprintf("(command = $%d OR command LIKE $%d)", len(args)-1, len(args)))
+	}
+	if !filter.Since.IsZero() {
+		args = append(args, filter.Since.UTC())
+		conds = append(conds, fmt.Sprintf("occurred_at >= $%d", len(args)))
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultCommandAuditLimit
+	}
+	args = append(args, limit)
+
+	query := `SELECT occurred_at, guild_id, channel_id, user_id, command, options::text, outcome, error, duration_ms FROM command_audit`
+	if len(conds) > 0 {
+		query += ` WHERE ` + strings.Join(conds, " AND ")
+	}
+	query += fmt.Sprintf(` ORDER BY occurred_at DESC, id DESC LIMIT $%d`, len(args))
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Store.ListCommandAudit: %w", err)
+	}
+	defer rows.Close()
+
+	var out []system.CommandAuditRecord
+	for rows.Next() {
+		var (
+			rec        system.CommandAuditRecord
+			durationMS int64
+		)
+		if err := rows.Scan(&rec.At, &rec.GuildID, &rec.ChannelID, &rec.UserID, &rec.Command, &rec.Options, &rec.Outcome, &rec.Error, &durationMS); err != nil {
+			return nil, fmt.Errorf("Store.ListCommandAudit scan: %w", err)
+		}
+		rec.Duration = time.Duration(durationMS) * time.Millisecond
+		out = append(out, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Store.ListCommandAudit rows: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/small-frappuccino/discordcore/pkg/system"
+)
+
+func TestStore_ListCommandAudit_BuildsFilter(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock.ExpectQuery(`FROM command_audit WHERE guild_id = \$1 AND \(command = \$2 OR command LIKE \$3\) ORDER BY occurred_at DESC, id DESC LIMIT \$4`).
+		WithArgs("g1", "admin", "admin %", 10).
+		WillReturnRows(pgxmock.NewRows([]string{"occurred_at", "guild_id", "channel_id", "user_id", "command", "options", "outcome", "error", "duration_ms"}).
+			AddRow(at, "g1", "c1", "u1", "admin errors", `{"since": "1h"}`, "ok", "", int64(42)))
+
+	got, err := store.ListCommandAudit(context.Background(), system.CommandAuditFilter{GuildID: "g1", Command: "admin", Limit: 10})
+	if err != nil {
+		t.Fatalf("ListCommandAudit: %v", err)
+	}
+	if len(got) != 1 || got[0].Command != "admin errors" || got[0].Duration != 42*time.Millisecond {
+		t.Fatalf("unexpected records: %+v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_AppendCommandAudit_DefaultsOptions(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+
+	mock.ExpectExec("INSERT INTO command_audit").
+		WithArgs(pgxmock.AnyArg(), "g1", "c1", "u1", "ban", "{}", "ok", "", int64(0)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	err := store.AppendCommandAudit(context.Background(), system.CommandAuditRecord{GuildID: "g1", ChannelID: "c1", UserID: "u1", Command: "ban", Outcome: "ok"})
+	if err != nil {
+		t.Fatalf("AppendCommandAudit: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
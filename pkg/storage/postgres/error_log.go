@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/observability"
+)
+
+// defaultErrorRecordLimit caps ListErrorRecords when the filter sets none.
+const defaultErrorRecordLimit = 100
+
+// AppendErrorRecords persists a batch of error log entries in one statement.
+func (s *Store) AppendErrorRecords(ctx context.Context, records []observability.ErrorRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	occurred := make([]time.Time, len(records))
+	subsystems := make([]string, len(records))
+	messages := make([]string, len(records))
+	details := make([]string, len(records))
+	for i, rec := range records {
+		occurred[i] = rec.At.UTC()
+		subsystems[i] = rec.Subsystem
+		messages[i] = rec.Message
+		details[i] = rec.Detail
+	}
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO error_log (occurred_at, subsystem, message, detail)
+		SELECT * FROM unnest($1::timestamptz[], $2::text[], $3::text[], $4::text[])
+	`, occurred, subsystems, messages, details)
+	if err != nil {
+		return fmt.Errorf("Store.AppendErrorRecords: %w", err)
+	}
+	return nil
+}
+
+// ListErrorRecords returns persisted error log entries matching filter,
+// newest first.
+func (s *Store) ListErrorRecords(ctx context.Context, filter observability.ErrorRecordFilter) ([]observability.ErrorRecord, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	if subsystem := strings.TrimSpace(filter.Subsystem); subsystem != "" {
+		args = append(args, subsystem)
+		conds = append(conds, fmt.Sprintf("lower(subsystem) = lower($%d)", len(args)))
+	}
+	if !filter.Since.IsZero() {
+		args = append(args, filter.Since.UTC())
+		conds = append(conds, fmt.Sprintf("occurred_at >= $%d", len(args)))
+	}
+	if !filter.Before.IsZero() {
+		args = append(args, filter.Before.UTC())
+		conds = append(conds, fmt.Sprintf("occurred_at < $%d", len(args)))
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultErrorRecordLimit
+	}
+	args = append(args, limit)
+
+	query := `SELECT occurred_at, subsystem, message, detail FROM error_log`
+	if len(conds) > 0 {
+		query += ` WHERE ` + strings.Join(conds, " AND ")
+	}
+	query += fmt.Sprintf(` ORDER BY occurred_at DESC, id DESC LIMIT $%d`, len(args))
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Store.ListErrorRecords: %w", err)
+	}
+	defer rows.Close()
+
+	var out []observability.ErrorRecord
+	for rows.Next() {
+		var rec observability.ErrorRecord
+		if err := rows.Scan(&rec.At, &rec.Subsystem, &rec.Message, &rec.Detail); err != nil {
+			return nil, fmt.Errorf("Store.ListErrorRecords scan: %w", err)
+		}
+		out = append(out, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Store.ListErrorRecords rows: %w", err)
+	}
+	return out, nil
+}
+
+// PruneErrorRecords deletes error log entries older than before and returns
+// how many were removed.
+func (s *Store) PruneErrorRecords(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.db.Exec(ctx, `DELETE FROM error_log WHERE occurred_at < $1`, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("Store.PruneErrorRecords: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/small-frappuccino/discordcore/pkg/observability"
+)
+
+func TestStore_ListErrorRecords_BuildsFilter(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+
+	since := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	at := since.Add(time.Minute)
+	mock.ExpectQuery(`SELECT occurred_at, subsystem, message, detail FROM error_log WHERE lower\(subsystem\) = lower\(\$1\) AND occurred_at >= \$2 ORDER BY occurred_at DESC, id DESC LIMIT \$3`).
+		WithArgs("qotd", since, 5).
+		WillReturnRows(pgxmock.NewRows([]string{"occurred_at", "subsystem", "message", "detail"}).AddRow(at, "qotd", "publish failed", "guild_id=1"))
+
+	got, err := store.ListErrorRecords(context.Background(), observability.ErrorRecordFilter{Subsystem: "qotd", Since: since, Limit: 5})
+	if err != nil {
+		t.Fatalf("ListErrorRecords: %v", err)
+	}
+	if len(got) != 1 || got[0].Message != "publish failed" || !got[0].At.Equal(at) {
+		t.Fatalf("unexpected records: %+v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_AppendErrorRecords(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+
+	if err := store.AppendErrorRecords(context.Background(), nil); err != nil {
+		t.Fatalf("empty batch: %v", err)
+	}
+
+	mock.ExpectExec("INSERT INTO error_log").
+		WithArgs(pgxmock.AnyArg(), []string{"stats"}, []string{"boom"}, []string{""}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	err := store.AppendErrorRecords(context.Background(), []observability.ErrorRecord{{At: time.Now(), Subsystem: "stats", Message: "boom"}})
+	if err != nil {
+		t.Fatalf("AppendErrorRecords: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+)
+
+// defaultLogEventLimit caps SearchLogEvents when the filter sets none.
+const defaultLogEventLimit = 25
+
+// AppendLogEvent archives the summary of one emitted log message.
+func (s *Store) AppendLogEvent(ctx context.Context, event logging.ArchivedEvent) error {
+	guildID := strings.TrimSpace(event.GuildID)
+	if guildID == "" || event.EventType == "" {
+		return fmt.Errorf("missing required fields for log event")
+	}
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO log_events (occurred_at, guild_id, event_type, user_id, actor_id, channel_id, log_channel_id, title, summary)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, event.At.UTC(), guildID, string(event.EventType), event.UserID, event.ActorID, event.ChannelID, event.LogChannelID, event.Title, event.Summary)
+	if err != nil {
+		return fmt.Errorf("Store.AppendLogEvent: %w", err)
+	}
+	return nil
+}
+
+// SearchLogEvents returns archived log events matching filter, newest first.
+func (s *Store) SearchLogEvents(ctx context.Context, filter logging.ArchiveFilter) ([]logging.ArchivedEvent, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	if guildID := strings.TrimSpace(filter.GuildID); guildID != "" {
+		args = append(args, guildID)
+		conds = append(conds, fmt.Sprintf("guild_id = $%d", len(args)))
+	}
+	if userID := strings.TrimSpace(filter.UserID); userID != "" {
+		args = append(args, userID)
+		conds = append(conds, fmt.Sprintf("(user_id = $%d OR actor_id = $%d)", len(args), len(args)))
+	}
+	if filter.EventType != "" {
+		args = append(args, string(filter.EventType))
+		conds = append(conds, fmt.Sprintf("event_type = $%d", len(args)))
+	}
+	if channelID := strings.TrimSpace(filter.ChannelID); channelID != "" {
+		args = append(args, channelID)
+		conds = append(conds, fmt.Sprintf("(channel_id = $%d OR log_channel_id = $%d)", len(args), len(args)))
+	}
+	if !filter.Since.IsZero() {
+		args = append(args, filter.Since.UTC())
+		conds = append(conds, fmt.Sprintf("occurred_at >= $%d", len(args)))
+	}
+	if !filter.Until.IsZero() {
+		args = append(args, filter.Until.UTC())
+		conds = append(conds, fmt.Sprintf("occurred_at < $%d", len(args)))
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultLogEventLimit
+	}
+	offset := max(filter.Offset, 0)
+	args = append(args, limit, offset)
+
+	query := `SELECT id, occurred_at, guild_id, event_type, user_id, actor_id, channel_id, log_channel_id, title, summary FROM log_events`
+	if len(conds) > 0 {
+		query += ` WHERE ` + strings.Join(conds, " AND ")
+	}
+	query += fmt.Sprintf(` ORDER BY occurred_at DESC, id DESC LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Store.SearchLogEvents: %w", err)
+	}
+	defer rows.Close()
+
+	var out []logging.ArchivedEvent
+	for rows.Next() {
+		var (
+			event     logging.ArchivedEvent
+			eventType string
+		)
+		if err := rows.Scan(&event.ID, &event.At, &event.GuildID, &eventType, &event.UserID, &event.ActorID, &event.ChannelID, &event.LogChannelID, &event.Title, &event.Summary); err != nil {
+			return nil, fmt.Errorf("Store.SearchLogEvents scan: %w", err)
+		}
+		event.EventType = logging.LogEventType(eventType)
+		event.At = event.At.UTC()
+		out = append(out, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Store.SearchLogEvents rows: %w", err)
+	}
+	return out, nil
+}
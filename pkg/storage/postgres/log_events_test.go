@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/small-frappuccino/discordcore/pkg/logging"
+)
+
+func TestStore_SearchLogEvents_BuildsFilter(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+	at := since.Add(time.Hour)
+	mock.ExpectQuery(`FROM log_events WHERE guild_id = \$1 AND \(user_id = \$2 OR actor_id = \$2\) AND event_type = \$3 AND \(channel_id = \$4 OR log_channel_id = \$4\) AND occurred_at >= \$5 AND occurred_at < \$6 ORDER BY occurred_at DESC, id DESC LIMIT \$7 OFFSET \$8`).
+		WithArgs("g1", "u1", "message_delete", "c1", since, until, 11, 20).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "occurred_at", "guild_id", "event_type", "user_id", "actor_id", "channel_id", "log_channel_id", "title", "summary"}).
+			AddRow(int64(7), at, "g1", "message_delete", "u1", "", "c1", "l1", "Message Deleted", "Message: hi"))
+
+	got, err := store.SearchLogEvents(context.Background(), logging.ArchiveFilter{
+		GuildID:   "g1",
+		UserID:    "u1",
+		EventType: logging.LogEventMessageDelete,
+		ChannelID: "c1",
+		Since:     since,
+		Until:     until,
+		Offset:    20,
+		Limit:     11,
+	})
+	if err != nil {
+		t.Fatalf("SearchLogEvents: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != 7 || got[0].EventType != logging.LogEventMessageDelete || !got[0].At.Equal(at) {
+		t.Fatalf("unexpected events: %+v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_SearchLogEvents_Defaults(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+
+	mock.ExpectQuery(`FROM log_events WHERE guild_id = \$1 ORDER BY occurred_at DESC, id DESC LIMIT \$2 OFFSET \$3`).
+		WithArgs("g1", defaultLogEventLimit, 0).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "occurred_at", "guild_id", "event_type", "user_id", "actor_id", "channel_id", "log_channel_id", "title", "summary"}))
+
+	if _, err := store.SearchLogEvents(context.Background(), logging.ArchiveFilter{GuildID: "g1", Offset: -5}); err != nil {
+		t.Fatalf("SearchLogEvents: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_AppendLogEvent(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+
+	mock.ExpectExec("INSERT INTO log_events").
+		WithArgs(pgxmock.AnyArg(), "g1", "member_join", "u1", "", "", "l1", "Member Joined", "alice").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	err := store.AppendLogEvent(context.Background(), logging.ArchivedEvent{
+		GuildID: "g1", EventType: logging.LogEventMemberJoin, UserID: "u1", LogChannelID: "l1", Title: "Member Joined", Summary: "alice",
+	})
+	if err != nil {
+		t.Fatalf("AppendLogEvent: %v", err)
+	}
+	if err := store.AppendLogEvent(context.Background(), logging.ArchivedEvent{EventType: logging.LogEventMemberJoin}); err == nil {
+		t.Fatal("expected an event without a guild to be rejected")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
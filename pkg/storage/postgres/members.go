@@ -492,6 +492,70 @@ func (s *Store) GetAvatar(ctx context.Context, guildID, userID string) (hash str
 	return hash, updatedAt, true, nil
 }
 
+// GetAvatars returns the current avatar hashes stored for userIDs in one
+// query, keyed by user ID. Users without a stored avatar are absent.
+func (s *Store) GetAvatars(ctx context.Context, guildID string, userIDs []string) (map[string]string, error) {
+	if len(userIDs) == 0 {
+		return map[string]string{}, nil
+	}
+	rows, err := s.db.Query(ctx, `SELECT user_id, avatar_hash FROM avatars_current WHERE guild_id=$1 AND user_id = ANY($2)`, guildID, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("Store.GetAvatars: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := make(map[string]string, len(userIDs))
+	for rows.Next() {
+		var userID, avatarHash string
+		if err := rows.Scan(&userID, &avatarHash); err != nil {
+			return nil, fmt.Errorf("Store.GetAvatars: %w", err)
+		}
+		hashes[userID] = avatarHash
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Store.GetAvatars: %w", err)
+	}
+	return hashes, nil
+}
+
+// GetMemberNames returns the last names stored for a member.
+func (s *Store) GetMemberNames(ctx context.Context, guildID, userID string) (names members.MemberNames, ok bool, err error) {
+	err = s.db.QueryRow(ctx,
+		`SELECT username, global_name, nick FROM member_names_current WHERE guild_id=$1 AND user_id=$2`,
+		guildID, userID,
+	).Scan(&names.Username, &names.GlobalName, &names.Nick)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return members.MemberNames{}, false, nil
+		}
+		return members.MemberNames{}, false, fmt.Errorf("Store.GetMemberNames: %w", err)
+	}
+	return names, true, nil
+}
+
+// UpsertMemberNames stores the names a member goes by, keeping the newest
+// write when updates race.
+func (s *Store) UpsertMemberNames(ctx context.Context, guildID, userID string, names members.MemberNames, updatedAt time.Time) error {
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+	_, err := s.db.Exec(ctx,
+		`INSERT INTO member_names_current (guild_id, user_id, username, global_name, nick, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (guild_id, user_id) DO UPDATE SET
+			username = excluded.username,
+			global_name = excluded.global_name,
+			nick = excluded.nick,
+			updated_at = excluded.updated_at
+		 WHERE member_names_current.updated_at <= excluded.updated_at`,
+		guildID, userID, names.Username, names.GlobalName, names.Nick, updatedAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("Store.UpsertMemberNames: %w", err)
+	}
+	return nil
+}
+
 // GetActiveGuildMemberStatesContext streams current member states utilizing iter.Seq2, avoiding slice heap allocations.
 func (s *Store) GetActiveGuildMemberStatesContext(ctx context.Context, guildID string) iter.Seq2[members.CurrentState, error] {
 	return func(yield func(members.CurrentState, error) bool) {
@@ -616,6 +680,94 @@ func (s *Store) MarkMemberLeftContext(ctx context.Context, guildID, userID strin
 	return err
 }
 
+// GuildMemberRetention reads the stored membership of guildID: how many
+// members it had at to, and how many of those who joined in [from, to) are
+// still present. Bots are left out.
+func (s *Store) GuildMemberRetention(ctx context.Context, guildID string, from, to time.Time) (members.Retention, error) {
+	var retention members.Retention
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" {
+		return retention, nil
+	}
+	err := s.db.QueryRow(ctx,
+		`SELECT
+             COUNT(*) FILTER (WHERE joined_at < $3 AND (left_at IS NULL OR left_at >= $3)),
+             COUNT(*) FILTER (WHERE joined_at >= $2 AND joined_at < $3),
+             COUNT(*) FILTER (WHERE joined_at >= $2 AND joined_at < $3 AND left_at IS NULL)
+         FROM member_joins
+         WHERE guild_id=$1 AND is_bot IS NOT TRUE`,
+		guildID, from.UTC(), to.UTC(),
+	).Scan(&retention.Members, &retention.Joined, &retention.Retained)
+	if err != nil {
+		return members.Retention{}, fmt.Errorf("Store.GuildMemberRetention: %w", err)
+	}
+	return retention, nil
+}
+
+// RecordGuildMemberCount counts the members currently stored for guildID
+// and saves the count in the day or hour bucket at falls in. A later count
+// in the same bucket replaces it. Members whose bot flag is unknown count as
+// humans.
+func (s *Store) RecordGuildMemberCount(ctx context.Context, guildID, granularity string, at time.Time) (members.MemberCount, error) {
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" {
+		return members.MemberCount{}, fmt.Errorf("missing guild id for member count")
+	}
+	count := members.MemberCount{GuildID: guildID, Granularity: granularity, Bucket: members.CountBucket(granularity, at)}
+	err := s.db.QueryRow(ctx,
+		`INSERT INTO guild_member_counts (guild_id, granularity, bucket, members, humans, bots, recorded_at)
+         SELECT $1, $2, $3,
+                COUNT(*),
+                COUNT(*) FILTER (WHERE is_bot IS NOT TRUE),
+                COUNT(*) FILTER (WHERE is_bot),
+                $4
+         FROM member_joins
+         WHERE guild_id=$1 AND left_at IS NULL
+         ON CONFLICT (guild_id, granularity, bucket) DO UPDATE
+         SET members=excluded.members, humans=excluded.humans, bots=excluded.bots, recorded_at=excluded.recorded_at
+         RETURNING members, humans, bots`,
+		guildID, count.Granularity, count.Bucket, at.UTC(),
+	).Scan(&count.Members, &count.Humans, &count.Bots)
+	if err != nil {
+		return members.MemberCount{}, fmt.Errorf("Store.RecordGuildMemberCount: %w", err)
+	}
+	return count, nil
+}
+
+// ListGuildMemberCounts returns the member counts of guildID with buckets in
+// [from, to), oldest first.
+func (s *Store) ListGuildMemberCounts(ctx context.Context, guildID, granularity string, from, to time.Time) ([]members.MemberCount, error) {
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" {
+		return nil, nil
+	}
+	rows, err := s.db.Query(ctx,
+		`SELECT bucket, members, humans, bots
+         FROM guild_member_counts
+         WHERE guild_id=$1 AND granularity=$2 AND bucket >= $3 AND bucket < $4
+         ORDER BY bucket`,
+		guildID, granularity, from.UTC(), to.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Store.ListGuildMemberCounts: %w", err)
+	}
+	defer rows.Close()
+
+	var out []members.MemberCount
+	for rows.Next() {
+		count := members.MemberCount{GuildID: guildID, Granularity: granularity}
+		if err := rows.Scan(&count.Bucket, &count.Members, &count.Humans, &count.Bots); err != nil {
+			return nil, fmt.Errorf("Store.ListGuildMemberCounts: %w", err)
+		}
+		count.Bucket = count.Bucket.UTC()
+		out = append(out, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Store.ListGuildMemberCounts: %w", err)
+	}
+	return out, nil
+}
+
 // UpsertMemberRoles updates a member's roles.
 func (s *Store) UpsertMemberRoles(guildID, userID string, roles []string, at time.Time) error {
 	_, err := s.db.Exec(context.Background(), `
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -421,3 +422,185 @@ func TestStore_Members_UpsertMemberRoles(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
+
+func TestStore_Members_GuildMemberRetention(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+
+	to := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -7)
+	mock.ExpectQuery(`SELECT\s+COUNT\(\*\) FILTER .* FROM member_joins`).
+		WithArgs("g1", from, to).
+		WillReturnRows(pgxmock.NewRows([]string{"members", "joined", "retained"}).AddRow(int64(420), int64(12), int64(9)))
+
+	retention, err := store.GuildMemberRetention(context.Background(), "g1", from, to)
+	if err != nil {
+		t.Fatalf("GuildMemberRetention: %v", err)
+	}
+	if rate, ok := retention.Rate(); retention.Members != 420 || !ok || rate != 0.75 {
+		t.Fatalf("unexpected retention %+v", retention)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_Members_GuildMemberCounts(t *testing.T) {
+	t.Parallel()
+	at := time.Date(2026, 10, 12, 14, 30, 0, 0, time.UTC)
+	day := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+
+	t.Run("record", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`INSERT INTO guild_member_counts .* FROM member_joins .* ON CONFLICT`).
+			WithArgs("g1", members.CountHourly, at.Truncate(time.Hour), at).
+			WillReturnRows(pgxmock.NewRows([]string{"members", "humans", "bots"}).AddRow(int64(420), int64(415), int64(5)))
+
+		count, err := store.RecordGuildMemberCount(context.Background(), "g1", members.CountHourly, at)
+		if err != nil || count.Members != 420 || count.Bots != 5 || !count.Bucket.Equal(at.Truncate(time.Hour)) {
+			t.Fatalf("RecordGuildMemberCount: got %+v, err=%v", count, err)
+		}
+		if _, err := store.RecordGuildMemberCount(context.Background(), " ", members.CountDaily, at); err == nil {
+			t.Fatal("RecordGuildMemberCount without a guild should fail")
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`SELECT bucket, members, humans, bots\s+FROM guild_member_counts`).
+			WithArgs("g1", members.CountDaily, day.AddDate(0, 0, -2), day).
+			WillReturnRows(pgxmock.NewRows([]string{"bucket", "members", "humans", "bots"}).
+				AddRow(day.AddDate(0, 0, -2), int64(400), int64(396), int64(4)).
+				AddRow(day.AddDate(0, 0, -1), int64(410), int64(405), int64(5)))
+
+		series, err := store.ListGuildMemberCounts(context.Background(), "g1", members.CountDaily, day.AddDate(0, 0, -2), day)
+		if err != nil || len(series) != 2 || members.NetGrowth(series) != 10 {
+			t.Fatalf("ListGuildMemberCounts: got %+v, err=%v", series, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestStore_Members_MemberNames(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+	at := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT username, global_name, nick FROM member_names_current`).
+		WithArgs("g1", "u1").
+		WillReturnError(pgx.ErrNoRows)
+	mock.ExpectExec(`INSERT INTO member_names_current .* ON CONFLICT`).
+		WithArgs("g1", "u1", "alice", "Alice", "Al", at).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectQuery(`SELECT username, global_name, nick FROM member_names_current`).
+		WithArgs("g1", "u1").
+		WillReturnRows(pgxmock.NewRows([]string{"username", "global_name", "nick"}).AddRow("alice", "Alice", "Al"))
+
+	if _, ok, err := store.GetMemberNames(context.Background(), "g1", "u1"); ok || err != nil {
+		t.Fatalf("GetMemberNames before any write: ok=%v, err=%v", ok, err)
+	}
+	names := members.MemberNames{Username: "alice", GlobalName: "Alice", Nick: "Al"}
+	if err := store.UpsertMemberNames(context.Background(), "g1", "u1", names, at); err != nil {
+		t.Fatalf("UpsertMemberNames: %v", err)
+	}
+	got, ok, err := store.GetMemberNames(context.Background(), "g1", "u1")
+	if !ok || err != nil || got != names {
+		t.Fatalf("GetMemberNames: got %+v, ok=%v, err=%v", got, ok, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// BenchmarkStore_UpsertGuildMemberSnapshots_Batch measures building and
+// sending one snapshot batch, the write behind every member reconcile, with
+// every member's avatar changed so each statement of the batch runs.
+func BenchmarkStore_UpsertGuildMemberSnapshots_Batch(b *testing.B) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		b.Fatalf("failed to open stub db connection: %v", err)
+	}
+	defer mock.Close()
+
+	store, _ := NewStore(mock, nil)
+	now := time.Now().UTC()
+
+	const batchSize = 500
+	snapshots := make([]members.Snapshot, batchSize)
+	for i := range snapshots {
+		userID := strconv.Itoa(1000 + i)
+		snapshots[i] = members.Snapshot{
+			UserID:     userID,
+			AvatarHash: "new" + userID,
+			HasAvatar:  true,
+			Roles:      []string{"1", "2", "3", userID},
+			HasRoles:   true,
+			JoinedAt:   now.Add(-time.Duration(i) * time.Hour),
+			HasBot:     true,
+		}
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		b.StopTimer()
+		current := pgxmock.NewRows([]string{"user_id", "avatar_hash"})
+		for _, snapshot := range snapshots {
+			current.AddRow(snapshot.UserID, "old"+snapshot.UserID)
+		}
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT user_id, avatar_hash FROM avatars_current").WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).WillReturnRows(current)
+		mock.ExpectExec("INSERT INTO avatars_history").WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).WillReturnResult(pgxmock.NewResult("INSERT", batchSize))
+		mock.ExpectExec("INSERT INTO avatars_current").WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).WillReturnResult(pgxmock.NewResult("INSERT", batchSize))
+		mock.ExpectExec("UPDATE roles_current").WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).WillReturnResult(pgxmock.NewResult("UPDATE", batchSize))
+		mock.ExpectExec("INSERT INTO roles_current").WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).WillReturnResult(pgxmock.NewResult("INSERT", 4*batchSize))
+		mock.ExpectExec("INSERT INTO member_joins").WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).WillReturnResult(pgxmock.NewResult("INSERT", batchSize))
+		mock.ExpectCommit()
+		// The deferred rollback still runs after the commit.
+		mock.ExpectRollback()
+		b.StartTimer()
+
+		if err := store.UpsertGuildMemberSnapshotsContext(context.Background(), "guild1", snapshots, now); err != nil {
+			b.Fatalf("upsert: %v", err)
+		}
+	}
+}
+
+func TestStore_GetAvatarsLoadsInOneQuery(t *testing.T) {
+	t.Parallel()
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to open stub db connection: %v", err)
+	}
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+
+	mock.ExpectQuery("SELECT user_id, avatar_hash FROM avatars_current").
+		WithArgs("g1", []string{"1", "2", "3"}).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "avatar_hash"}).AddRow("1", "a").AddRow("3", "c"))
+
+	got, err := store.GetAvatars(context.Background(), "g1", []string{"1", "2", "3"})
+	if err != nil {
+		t.Fatalf("GetAvatars: %v", err)
+	}
+	if len(got) != 2 || got["1"] != "a" || got["3"] != "c" {
+		t.Fatalf("unexpected avatars %v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/small-frappuccino/discordcore/pkg/messages"
+	"github.com/small-frappuccino/discordcore/pkg/system"
 )
 
 // UpsertMessage inserts or updates a message record transactionally.
@@ -19,17 +20,19 @@ func (s *Store) UpsertMessage(m messages.Record) error {
 	}
 
 	_, err := s.db.Exec(context.Background(),
-		`INSERT INTO messages (guild_id, message_id, channel_id, author_id, author_username, author_avatar, content, cached_at, expires_at)
-         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`INSERT INTO messages (guild_id, message_id, channel_id, author_id, author_username, author_avatar, content, content_hash, content_length, cached_at, expires_at)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
          ON CONFLICT(guild_id, message_id) DO UPDATE SET
            channel_id=excluded.channel_id,
            author_id=excluded.author_id,
            author_username=excluded.author_username,
            author_avatar=excluded.author_avatar,
            content=excluded.content,
+           content_hash=excluded.content_hash,
+           content_length=excluded.content_length,
            cached_at=excluded.cached_at,
            expires_at=excluded.expires_at`,
-		m.GuildID, m.MessageID, m.ChannelID, m.AuthorID, m.AuthorUsername, m.AuthorAvatar, m.Content, m.CachedAt.UTC(), expires,
+		m.GuildID, m.MessageID, m.ChannelID, m.AuthorID, m.AuthorUsername, m.AuthorAvatar, m.Content, m.ContentHash, m.ContentLength, m.CachedAt.UTC(), expires,
 	)
 	return err
 }
@@ -48,6 +51,8 @@ func (s *Store) UpsertMessagesContext(ctx context.Context, records []messages.Re
 	authorUsernames := make([]string, len(normalized))
 	authorAvatars := make([]string, len(normalized))
 	contents := make([]string, len(normalized))
+	contentHashes := make([]string, len(normalized))
+	contentLengths := make([]int32, len(normalized))
 	cachedAts := make([]time.Time, len(normalized))
 	expiresAts := make([]*time.Time, len(normalized))
 
@@ -59,6 +64,8 @@ func (s *Store) UpsertMessagesContext(ctx context.Context, records []messages.Re
 		authorUsernames[i] = record.AuthorUsername
 		authorAvatars[i] = record.AuthorAvatar
 		contents[i] = record.Content
+		contentHashes[i] = record.ContentHash
+		contentLengths[i] = int32(record.ContentLength)
 		cachedAts[i] = record.CachedAt.UTC()
 		if record.HasExpiry {
 			t := record.ExpiresAt.UTC()
@@ -67,17 +74,19 @@ func (s *Store) UpsertMessagesContext(ctx context.Context, records []messages.Re
 	}
 
 	_, err := s.db.Exec(ctx,
-		`INSERT INTO messages (guild_id, message_id, channel_id, author_id, author_username, author_avatar, content, cached_at, expires_at)
-         SELECT * FROM UNNEST($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[], $7::text[], $8::timestamptz[], $9::timestamptz[])
+		`INSERT INTO messages (guild_id, message_id, channel_id, author_id, author_username, author_avatar, content, content_hash, content_length, cached_at, expires_at)
+         SELECT * FROM UNNEST($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[], $7::text[], $8::text[], $9::int[], $10::timestamptz[], $11::timestamptz[])
          ON CONFLICT(guild_id, message_id) DO UPDATE SET
            channel_id=excluded.channel_id,
            author_id=excluded.author_id,
            author_username=excluded.author_username,
            author_avatar=excluded.author_avatar,
            content=excluded.content,
+           content_hash=excluded.content_hash,
+           content_length=excluded.content_length,
            cached_at=excluded.cached_at,
            expires_at=excluded.expires_at`,
-		guildIDs, messageIDs, channelIDs, authorIDs, authorUsernames, authorAvatars, contents, cachedAts, expiresAts,
+		guildIDs, messageIDs, channelIDs, authorIDs, authorUsernames, authorAvatars, contents, contentHashes, contentLengths, cachedAts, expiresAts,
 	)
 	return err
 }
@@ -113,7 +122,7 @@ func normalizeMessageRecords(records []messages.Record) []messages.Record {
 // GetMessage returns a non-expired message if present.
 func (s *Store) GetMessage(ctx context.Context, guildID, messageID string) (*messages.Record, error) {
 	row := s.db.QueryRow(ctx,
-		`SELECT guild_id, message_id, channel_id, author_id, author_username, author_avatar, content, cached_at, expires_at
+		`SELECT guild_id, message_id, channel_id, author_id, author_username, author_avatar, content, content_hash, content_length, cached_at, expires_at
          FROM messages
          WHERE guild_id=$1 AND message_id=$2 AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)`,
 		guildID, messageID,
@@ -121,7 +130,7 @@ func (s *Store) GetMessage(ctx context.Context, guildID, messageID string) (*mes
 
 	var rec messages.Record
 	var expires *time.Time
-	if err := row.Scan(&rec.GuildID, &rec.MessageID, &rec.ChannelID, &rec.AuthorID, &rec.AuthorUsername, &rec.AuthorAvatar, &rec.Content, &rec.CachedAt, &expires); err != nil {
+	if err := row.Scan(&rec.GuildID, &rec.MessageID, &rec.ChannelID, &rec.AuthorID, &rec.AuthorUsername, &rec.AuthorAvatar, &rec.Content, &rec.ContentHash, &rec.ContentLength, &rec.CachedAt, &expires); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
@@ -134,6 +143,46 @@ func (s *Store) GetMessage(ctx context.Context, guildID, messageID string) (*mes
 	return &rec, nil
 }
 
+// CachedMessagesSinceContext returns up to limit non-expired messages of a
+// guild cached at or after since, most recent first.
+func (s *Store) CachedMessagesSinceContext(ctx context.Context, guildID string, since time.Time, limit int) ([]messages.Record, error) {
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" || limit <= 0 {
+		return nil, nil
+	}
+
+	rows, err := s.db.Query(ctx,
+		`SELECT guild_id, message_id, channel_id, author_id, author_username, author_avatar, content, content_hash, content_length, cached_at, expires_at
+         FROM messages
+         WHERE guild_id=$1 AND cached_at >= $2 AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+         ORDER BY cached_at DESC
+         LIMIT $3`,
+		guildID, since.UTC(), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Store.CachedMessagesSinceContext: %w", err)
+	}
+	defer rows.Close()
+
+	var out []messages.Record
+	for rows.Next() {
+		var rec messages.Record
+		var expires *time.Time
+		if err := rows.Scan(&rec.GuildID, &rec.MessageID, &rec.ChannelID, &rec.AuthorID, &rec.AuthorUsername, &rec.AuthorAvatar, &rec.Content, &rec.ContentHash, &rec.ContentLength, &rec.CachedAt, &expires); err != nil {
+			return nil, fmt.Errorf("Store.CachedMessagesSinceContext: %w", err)
+		}
+		if expires != nil {
+			rec.HasExpiry = true
+			rec.ExpiresAt = *expires
+		}
+		out = append(out, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Store.CachedMessagesSinceContext: %w", err)
+	}
+	return out, nil
+}
+
 // DeleteMessagesContext removes a batch of message records via UNNEST.
 func (s *Store) DeleteMessagesContext(ctx context.Context, keys []messages.DeleteKey) error {
 	normalized := normalizeMessageDeleteKeys(keys)
@@ -384,6 +433,12 @@ func (s *Store) IncrementDailyMessageCountsContext(ctx context.Context, deltas [
 		if err != nil {
 			return err
 		}
+		// Backfills write days a weekly or monthly rollup may already total.
+		if system.RollupMayCover(delta.Day, time.Now()) {
+			if err := s.InvalidateActivityRollups(ctx, delta.GuildID, delta.Day); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -38,7 +38,7 @@ func TestStore_Messages_UpsertMessage(t *testing.T) {
 		}
 
 		mock.ExpectExec(`INSERT INTO messages`).
-			WithArgs("123", "456", "789", "999", "username", "avatar", "hello", now.UTC(), expiry.UTC()).
+			WithArgs("123", "456", "789", "999", "username", "avatar", "hello", "", 0, now.UTC(), expiry.UTC()).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		err = store.UpsertMessage(rec)
@@ -74,7 +74,7 @@ func TestStore_Messages_UpsertMessage(t *testing.T) {
 		}
 
 		mock.ExpectExec(`INSERT INTO messages`).
-			WithArgs("123", "456", "789", "999", "username", "avatar", "hello", now.UTC(), nil).
+			WithArgs("123", "456", "789", "999", "username", "avatar", "hello", "", 0, now.UTC(), nil).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		err = store.UpsertMessage(rec)
@@ -145,7 +145,7 @@ func TestStore_Messages_UpsertMessagesContext(t *testing.T) {
 		expectedExpiresAts := []*time.Time{&expiryTime, nil}
 
 		mock.ExpectExec(`INSERT INTO messages`).
-			WithArgs(expectedGuilds, expectedMessages, expectedChannels, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), expectedCachedAts, expectedExpiresAts).
+			WithArgs(expectedGuilds, expectedMessages, expectedChannels, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), expectedCachedAts, expectedExpiresAts).
 			WillReturnResult(pgxmock.NewResult("INSERT", 2))
 
 		err := store.UpsertMessagesContext(context.Background(), records)
@@ -183,8 +183,8 @@ func TestStore_Messages_GetMessage(t *testing.T) {
 		now := time.Now()
 		expiry := now.Add(time.Hour)
 
-		rows := pgxmock.NewRows([]string{"guild_id", "message_id", "channel_id", "author_id", "author_username", "author_avatar", "content", "cached_at", "expires_at"}).
-			AddRow("123", "456", "789", "999", "user", "avatar", "hello", now, &expiry)
+		rows := pgxmock.NewRows([]string{"guild_id", "message_id", "channel_id", "author_id", "author_username", "author_avatar", "content", "content_hash", "content_length", "cached_at", "expires_at"}).
+			AddRow("123", "456", "789", "999", "user", "avatar", "hello", "", 0, now, &expiry)
 
 		mock.ExpectQuery(`SELECT guild_id, message_id`).
 			WithArgs("123", "456").
@@ -209,8 +209,8 @@ func TestStore_Messages_GetMessage(t *testing.T) {
 
 		now := time.Now()
 
-		rows := pgxmock.NewRows([]string{"guild_id", "message_id", "channel_id", "author_id", "author_username", "author_avatar", "content", "cached_at", "expires_at"}).
-			AddRow("123", "456", "789", "999", "user", "avatar", "hello", now, nil)
+		rows := pgxmock.NewRows([]string{"guild_id", "message_id", "channel_id", "author_id", "author_username", "author_avatar", "content", "content_hash", "content_length", "cached_at", "expires_at"}).
+			AddRow("123", "456", "789", "999", "user", "avatar", "hello", "", 0, now, nil)
 
 		mock.ExpectQuery(`SELECT guild_id, message_id`).
 			WithArgs("123", "456").
@@ -262,6 +262,37 @@ func TestStore_Messages_GetMessage(t *testing.T) {
 	})
 }
 
+func TestStore_Messages_CachedMessagesSinceContext(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+
+	now := time.Now()
+	since := now.Add(-time.Hour)
+	rows := pgxmock.NewRows([]string{"guild_id", "message_id", "channel_id", "author_id", "author_username", "author_avatar", "content", "content_hash", "content_length", "cached_at", "expires_at"}).
+		AddRow("123", "456", "789", "999", "user", "avatar", "hello", "", 0, now, nil).
+		AddRow("123", "457", "789", "999", "user", "avatar", "", "5f3a", 5, now, &now)
+
+	mock.ExpectQuery(`SELECT guild_id, message_id`).
+		WithArgs("123", since.UTC(), 50).
+		WillReturnRows(rows)
+
+	recs, err := store.CachedMessagesSinceContext(context.Background(), "123", since, 50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recs) != 2 || recs[0].HasExpiry || !recs[1].HasExpiry {
+		t.Fatalf("unexpected records: %+v", recs)
+	}
+	if recs[1].Content != "" || recs[1].ContentHash != "5f3a" || recs[1].ContentLength != 5 {
+		t.Fatalf("hashed record = %+v", recs[1])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
 func TestStore_Messages_DeleteMessagesContext(t *testing.T) {
 	t.Parallel()
 	t.Run("empty keys", func(t *testing.T) {
@@ -498,6 +529,28 @@ func TestStore_Messages_IncrementDailyMessageCountsContext(t *testing.T) {
 		}
 	})
 
+	t.Run("backfill invalidates rollups", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		old := time.Now().AddDate(0, -2, 0)
+		mock.ExpectExec(`INSERT INTO daily_message_metrics`).
+			WithArgs("123", old, 5).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec(`DELETE FROM activity_rollups`).
+			WithArgs("123", old.UTC()).
+			WillReturnResult(pgxmock.NewResult("DELETE", 2))
+
+		err := store.IncrementDailyMessageCountsContext(context.Background(), []messages.DailyCountDelta{{GuildID: "123", Day: old, Count: 5}})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
 	t.Run("db error", func(t *testing.T) {
 		mock, _ := pgxmock.NewPool()
 		defer mock.Close()
@@ -13,6 +13,14 @@ import (
 	"github.com/small-frappuccino/discordcore/pkg/moderation"
 )
 
+// nextCaseNumberSQL allocates the next case number of a guild. Warnings and
+// cases both draw from it, so numbers never collide across the two.
+const nextCaseNumberSQL = `INSERT INTO moderation_cases (guild_id, last_case_number)
+         VALUES ($1, 1)
+         ON CONFLICT(guild_id) DO UPDATE
+         SET last_case_number = moderation_cases.last_case_number + 1
+         RETURNING last_case_number`
+
 // NextModerationCaseNumber atomically increments and returns the next case number.
 func (s *Store) NextModerationCaseNumber(ctx context.Context, guildID string) (int64, error) {
 	guildID = strings.TrimSpace(guildID)
@@ -22,11 +30,7 @@ func (s *Store) NextModerationCaseNumber(ctx context.Context, guildID string) (i
 
 	var next int64
 	err := s.db.QueryRow(ctx,
-		`INSERT INTO moderation_cases (guild_id, last_case_number)
-         VALUES ($1, 1)
-         ON CONFLICT(guild_id) DO UPDATE
-         SET last_case_number = moderation_cases.last_case_number + 1
-         RETURNING last_case_number`,
+		nextCaseNumberSQL,
 		guildID,
 	).Scan(&next)
 	if err != nil {
@@ -35,24 +39,43 @@ func (s *Store) NextModerationCaseNumber(ctx context.Context, guildID string) (i
 	return next, nil
 }
 
-// CreateModerationWarning creates a moderation warning transactionally.
-func (s *Store) CreateModerationWarning(ctx context.Context, guildID, userID, moderatorID, reason string, createdAt time.Time) (warning moderation.Warning, err error) {
-	guildID = strings.TrimSpace(guildID)
-	userID = strings.TrimSpace(userID)
-	moderatorID = strings.TrimSpace(moderatorID)
-	reason = strings.TrimSpace(reason)
-	if guildID == "" || userID == "" || moderatorID == "" || reason == "" {
+// CreateModerationWarning creates a moderation warning transactionally,
+// together with the manual warn case that carries its number.
+func (s *Store) CreateModerationWarning(ctx context.Context, guildID, userID, moderatorID, reason string, createdAt time.Time) (moderation.Warning, error) {
+	return s.CreateModerationWarningCase(ctx, moderation.Case{
+		GuildID:     guildID,
+		UserID:      userID,
+		ModeratorID: moderatorID,
+		Reason:      reason,
+		Source:      moderation.CaseSourceManual,
+		CreatedAt:   createdAt,
+	})
+}
+
+// CreateModerationWarningCase records a warning and its case c in one
+// transaction, so the case number of every warning resolves through the case
+// records. c's action is always CaseActionWarn.
+func (s *Store) CreateModerationWarningCase(ctx context.Context, c moderation.Case) (warning moderation.Warning, err error) {
+	c.GuildID = strings.TrimSpace(c.GuildID)
+	c.UserID = strings.TrimSpace(c.UserID)
+	c.ModeratorID = strings.TrimSpace(c.ModeratorID)
+	c.Reason = strings.TrimSpace(c.Reason)
+	c.Action = moderation.CaseActionWarn
+	if c.GuildID == "" || c.UserID == "" || c.ModeratorID == "" || c.Reason == "" {
 		return moderation.Warning{}, fmt.Errorf("missing required fields for warning")
 	}
-	if createdAt.IsZero() {
-		createdAt = time.Now().UTC()
+	if c.Source == "" {
+		c.Source = moderation.CaseSourceManual
+	}
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now().UTC()
 	} else {
-		createdAt = createdAt.UTC()
+		c.CreatedAt = c.CreatedAt.UTC()
 	}
 
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		return moderation.Warning{}, fmt.Errorf("Store.CreateModerationWarning: %w", err)
+		return moderation.Warning{}, fmt.Errorf("Store.CreateModerationWarningCase: %w", err)
 	}
 	defer func() {
 		if rerr := tx.Rollback(ctx); rerr != nil && !errors.Is(rerr, pgx.ErrTxClosed) {
@@ -60,25 +83,20 @@ func (s *Store) CreateModerationWarning(ctx context.Context, guildID, userID, mo
 		}
 	}()
 
-	var caseNumber int64
 	if err := tx.QueryRow(ctx,
-		`INSERT INTO moderation_cases (guild_id, last_case_number)
-         VALUES ($1, 1)
-         ON CONFLICT(guild_id) DO UPDATE
-         SET last_case_number = moderation_cases.last_case_number + 1
-         RETURNING last_case_number`,
-		guildID,
-	).Scan(&caseNumber); err != nil {
+		nextCaseNumberSQL,
+		c.GuildID,
+	).Scan(&c.CaseNumber); err != nil {
 		return moderation.Warning{}, err
 	}
 
 	warning = moderation.Warning{
-		GuildID:     guildID,
-		UserID:      userID,
-		CaseNumber:  caseNumber,
-		ModeratorID: moderatorID,
-		Reason:      reason,
-		CreatedAt:   createdAt,
+		GuildID:     c.GuildID,
+		UserID:      c.UserID,
+		CaseNumber:  c.CaseNumber,
+		ModeratorID: c.ModeratorID,
+		Reason:      c.Reason,
+		CreatedAt:   c.CreatedAt,
 	}
 
 	if err := tx.QueryRow(ctx,
@@ -89,13 +107,358 @@ func (s *Store) CreateModerationWarning(ctx context.Context, guildID, userID, mo
 	).Scan(&warning.ID, &warning.CreatedAt); err != nil {
 		return moderation.Warning{}, err
 	}
+	if _, err := insertModerationCase(ctx, tx, c); err != nil {
+		return moderation.Warning{}, err
+	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return moderation.Warning{}, fmt.Errorf("Store.CreateModerationWarning: %w", err)
+		return moderation.Warning{}, fmt.Errorf("Store.CreateModerationWarningCase: %w", err)
 	}
 	return warning, nil
 }
 
+// CreateModerationCase records c in one transaction, allocating a case number
+// unless c carries one reserved through NextModerationCaseNumber.
+func (s *Store) CreateModerationCase(ctx context.Context, c moderation.Case) (created moderation.Case, err error) {
+	c.GuildID = strings.TrimSpace(c.GuildID)
+	c.UserID = strings.TrimSpace(c.UserID)
+	c.Action = strings.TrimSpace(c.Action)
+	c.Reason = strings.TrimSpace(c.Reason)
+	if c.GuildID == "" || c.Action == "" || (c.UserID == "" && c.NamesMember()) {
+		return moderation.Case{}, fmt.Errorf("missing required fields for moderation case")
+	}
+	if c.Source == "" {
+		c.Source = moderation.CaseSourceManual
+	}
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now().UTC()
+	} else {
+		c.CreatedAt = c.CreatedAt.UTC()
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return moderation.Case{}, fmt.Errorf("Store.CreateModerationCase: %w", err)
+	}
+	defer func() {
+		if rerr := tx.Rollback(ctx); rerr != nil && !errors.Is(rerr, pgx.ErrTxClosed) {
+			err = errors.Join(err, fmt.Errorf("rollback failed: %w", rerr))
+		}
+	}()
+
+	if c.CaseNumber <= 0 {
+		if err := tx.QueryRow(ctx, nextCaseNumberSQL, c.GuildID).Scan(&c.CaseNumber); err != nil {
+			return moderation.Case{}, err
+		}
+	}
+
+	if c, err = insertModerationCase(ctx, tx, c); err != nil {
+		return moderation.Case{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return moderation.Case{}, fmt.Errorf("Store.CreateModerationCase: %w", err)
+	}
+	c.UpdatedAt = c.CreatedAt
+	return c, nil
+}
+
+// insertModerationCase writes c, which already carries its case number,
+// inside tx.
+func insertModerationCase(ctx context.Context, tx pgx.Tx, c moderation.Case) (moderation.Case, error) {
+	var expiresAt *time.Time
+	if c.Timed() {
+		c.ExpiresAt = c.ExpiresAt.UTC()
+		expiresAt = &c.ExpiresAt
+		// A new timed action replaces the end of the member's earlier ones,
+		// which then never get an expiry follow-up of their own.
+		if _, err := tx.Exec(ctx,
+			`UPDATE moderation_case_records
+             SET expiry_logged_at=$3
+             WHERE guild_id=$1 AND user_id=$2 AND expires_at IS NOT NULL AND expiry_logged_at IS NULL`,
+			c.GuildID, c.UserID, c.CreatedAt,
+		); err != nil {
+			return moderation.Case{}, err
+		}
+	}
+
+	if err := tx.QueryRow(ctx,
+		`INSERT INTO moderation_case_records (id, guild_id, case_number, action, user_id, moderator_id, reason, source,
+             channel_id, message_id, rule_id, matched_keyword, matched_content, content, extra, expires_at, created_at)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+         RETURNING id, created_at`,
+		idgen.GenerateID(), c.GuildID, c.CaseNumber, c.Action, c.UserID, c.ModeratorID, c.Reason, c.Source,
+		c.ChannelID, c.MessageID, c.RuleID, c.MatchedKeyword, c.MatchedContent, c.Content, c.Extra, expiresAt, c.CreatedAt,
+	).Scan(&c.ID, &c.CreatedAt); err != nil {
+		return moderation.Case{}, err
+	}
+	return c, nil
+}
+
+// moderationCaseColumns lists the columns scanned by scanModerationCase, in
+// order.
+const moderationCaseColumns = `id, guild_id, case_number, action, user_id, moderator_id, reason, source,
+             channel_id, message_id, rule_id, matched_keyword, matched_content, content, extra,
+             log_channel_id, log_message_id, expires_at, created_at, updated_at, voided_at, voided_by`
+
+func scanModerationCase(row pgx.Row) (moderation.Case, error) {
+	var (
+		c         moderation.Case
+		expiresAt *time.Time
+		voidedAt  *time.Time
+	)
+	if err := row.Scan(&c.ID, &c.GuildID, &c.CaseNumber, &c.Action, &c.UserID, &c.ModeratorID, &c.Reason, &c.Source,
+		&c.ChannelID, &c.MessageID, &c.RuleID, &c.MatchedKeyword, &c.MatchedContent, &c.Content, &c.Extra,
+		&c.LogChannelID, &c.LogMessageID, &expiresAt, &c.CreatedAt, &c.UpdatedAt, &voidedAt, &c.VoidedBy); err != nil {
+		return moderation.Case{}, err
+	}
+	c.CreatedAt = c.CreatedAt.UTC()
+	c.UpdatedAt = c.UpdatedAt.UTC()
+	if expiresAt != nil {
+		c.ExpiresAt = expiresAt.UTC()
+	}
+	if voidedAt != nil {
+		c.VoidedAt = voidedAt.UTC()
+	}
+	return c, nil
+}
+
+// queryModerationCase runs a single-case query and reports ErrNoRows as not
+// found.
+func (s *Store) queryModerationCase(ctx context.Context, op, query string, args ...any) (moderation.Case, bool, error) {
+	c, err := scanModerationCase(s.db.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return moderation.Case{}, false, nil
+		}
+		return moderation.Case{}, false, fmt.Errorf("Store.%s: %w", op, err)
+	}
+	return c, true, nil
+}
+
+// GetModerationCase returns the case recorded under caseNumber, voided or not.
+func (s *Store) GetModerationCase(ctx context.Context, guildID string, caseNumber int64) (moderation.Case, bool, error) {
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" || caseNumber <= 0 {
+		return moderation.Case{}, false, fmt.Errorf("guildID or case number is invalid")
+	}
+	return s.queryModerationCase(ctx, "GetModerationCase",
+		`SELECT `+moderationCaseColumns+`
+         FROM moderation_case_records
+         WHERE guild_id=$1 AND case_number=$2`,
+		guildID, caseNumber,
+	)
+}
+
+// ListModerationCases returns the cases of a guild matching filter, newest
+// first and at most moderation.MaxCaseExport of them. Voided cases are
+// included.
+func (s *Store) ListModerationCases(ctx context.Context, guildID string, filter moderation.CaseFilter) ([]moderation.Case, error) {
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" {
+		return nil, nil
+	}
+	rows, err := s.db.Query(ctx,
+		`SELECT `+moderationCaseColumns+`
+         FROM moderation_case_records
+         WHERE guild_id=$1 AND created_at >= $2 AND ($3='' OR action=$3) AND ($4='' OR moderator_id=$4)
+         ORDER BY case_number DESC
+         LIMIT $5`,
+		guildID, filter.Since.UTC(), strings.TrimSpace(filter.Action), strings.TrimSpace(filter.ModeratorID), moderation.MaxCaseExport,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Store.ListModerationCases: %w", err)
+	}
+	defer rows.Close()
+
+	var out []moderation.Case
+	for rows.Next() {
+		c, err := scanModerationCase(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Store.ListModerationCases: %w", err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Store.ListModerationCases: %w", err)
+	}
+	return out, nil
+}
+
+// ModerationActionCounts tallies the cases and warnings recorded in guildID
+// within [from, to).
+func (s *Store) ModerationActionCounts(ctx context.Context, guildID string, from, to time.Time) (moderation.ActionCounts, error) {
+	guildID = strings.TrimSpace(guildID)
+	counts := moderation.ActionCounts{ByAction: make(map[string]int)}
+	if guildID == "" {
+		return counts, nil
+	}
+	rows, err := s.db.Query(ctx,
+		`SELECT action, COUNT(*) FILTER (WHERE voided_at IS NULL), COUNT(*) FILTER (WHERE voided_at IS NOT NULL)
+         FROM moderation_case_records
+         WHERE guild_id=$1 AND created_at >= $2 AND created_at < $3
+         GROUP BY action`,
+		guildID, from.UTC(), to.UTC(),
+	)
+	if err != nil {
+		return moderation.ActionCounts{}, fmt.Errorf("Store.ModerationActionCounts: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			action         string
+			active, voided int
+		)
+		if err := rows.Scan(&action, &active, &voided); err != nil {
+			return moderation.ActionCounts{}, fmt.Errorf("Store.ModerationActionCounts: %w", err)
+		}
+		if active > 0 {
+			counts.ByAction[action] = active
+		}
+		counts.Voided += voided
+	}
+	if err := rows.Err(); err != nil {
+		return moderation.ActionCounts{}, fmt.Errorf("Store.ModerationActionCounts: %w", err)
+	}
+
+	if err := s.db.QueryRow(ctx,
+		`SELECT COUNT(*) FROM moderation_warnings
+         WHERE guild_id=$1 AND created_at >= $2 AND created_at < $3`,
+		guildID, from.UTC(), to.UTC(),
+	).Scan(&counts.Warnings); err != nil {
+		return moderation.ActionCounts{}, fmt.Errorf("Store.ModerationActionCounts: %w", err)
+	}
+	return counts, nil
+}
+
+// UpdateModerationCaseReason replaces the reason of a case and returns the
+// updated case.
+func (s *Store) UpdateModerationCaseReason(ctx context.Context, guildID string, caseNumber int64, reason string) (moderation.Case, bool, error) {
+	guildID = strings.TrimSpace(guildID)
+	reason = strings.TrimSpace(reason)
+	if guildID == "" || caseNumber <= 0 || reason == "" {
+		return moderation.Case{}, false, fmt.Errorf("missing required fields for case update")
+	}
+	return s.queryModerationCase(ctx, "UpdateModerationCaseReason",
+		`UPDATE moderation_case_records
+         SET reason=$3, updated_at=NOW()
+         WHERE guild_id=$1 AND case_number=$2
+         RETURNING `+moderationCaseColumns,
+		guildID, caseNumber, reason,
+	)
+}
+
+// VoidModerationCase marks a case voided by voidedBy and returns it. Found is
+// false when no case has that number or the case was already voided.
+func (s *Store) VoidModerationCase(ctx context.Context, guildID string, caseNumber int64, voidedBy string, voidedAt time.Time) (moderation.Case, bool, error) {
+	guildID = strings.TrimSpace(guildID)
+	voidedBy = strings.TrimSpace(voidedBy)
+	if guildID == "" || caseNumber <= 0 || voidedBy == "" {
+		return moderation.Case{}, false, fmt.Errorf("missing required fields for case void")
+	}
+	if voidedAt.IsZero() {
+		voidedAt = time.Now()
+	}
+	return s.queryModerationCase(ctx, "VoidModerationCase",
+		`UPDATE moderation_case_records
+         SET voided_at=$3, voided_by=$4, updated_at=$3
+         WHERE guild_id=$1 AND case_number=$2 AND voided_at IS NULL
+         RETURNING `+moderationCaseColumns,
+		guildID, caseNumber, voidedAt.UTC(), voidedBy,
+	)
+}
+
+// SetModerationCaseLogMessage records where the log embed of a case was
+// posted, so later edits can update it.
+func (s *Store) SetModerationCaseLogMessage(ctx context.Context, guildID string, caseNumber int64, channelID, messageID string) error {
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" || caseNumber <= 0 {
+		return fmt.Errorf("guildID or case number is invalid")
+	}
+	if _, err := s.db.Exec(ctx,
+		`UPDATE moderation_case_records
+         SET log_channel_id=$3, log_message_id=$4
+         WHERE guild_id=$1 AND case_number=$2`,
+		guildID, caseNumber, strings.TrimSpace(channelID), strings.TrimSpace(messageID),
+	); err != nil {
+		return fmt.Errorf("Store.SetModerationCaseLogMessage: %w", err)
+	}
+	return nil
+}
+
+// ListExpiredModerationCases returns the timed cases of a guild that ended
+// by now and have not had their expiry logged, oldest first and at most
+// limit of them. Voided cases are left out.
+func (s *Store) ListExpiredModerationCases(ctx context.Context, guildID string, now time.Time, limit int) ([]moderation.Case, error) {
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" || limit <= 0 {
+		return nil, nil
+	}
+	rows, err := s.db.Query(ctx,
+		`SELECT `+moderationCaseColumns+`
+         FROM moderation_case_records
+         WHERE guild_id=$1 AND expires_at <= $2 AND expiry_logged_at IS NULL AND voided_at IS NULL
+         ORDER BY expires_at, case_number
+         LIMIT $3`,
+		guildID, now.UTC(), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Store.ListExpiredModerationCases: %w", err)
+	}
+	defer rows.Close()
+
+	var out []moderation.Case
+	for rows.Next() {
+		c, err := scanModerationCase(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Store.ListExpiredModerationCases: %w", err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Store.ListExpiredModerationCases: %w", err)
+	}
+	return out, nil
+}
+
+// CountPendingCaseExpiries counts the timed cases of a guild whose expiry
+// has not been logged: due ones ended by now and await the next expiry pass,
+// running ones end later. Voided cases are left out.
+func (s *Store) CountPendingCaseExpiries(ctx context.Context, guildID string, now time.Time) (due, running int, err error) {
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" {
+		return 0, 0, nil
+	}
+	err = s.db.QueryRow(ctx,
+		`SELECT COUNT(*) FILTER (WHERE expires_at <= $2), COUNT(*) FILTER (WHERE expires_at > $2)
+         FROM moderation_case_records
+         WHERE guild_id=$1 AND expires_at IS NOT NULL AND expiry_logged_at IS NULL AND voided_at IS NULL`,
+		guildID, now.UTC(),
+	).Scan(&due, &running)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Store.CountPendingCaseExpiries: %w", err)
+	}
+	return due, running, nil
+}
+
+// MarkModerationCaseExpiryLogged records that the expiry of a case was
+// handled, so it is not followed up again.
+func (s *Store) MarkModerationCaseExpiryLogged(ctx context.Context, guildID string, caseNumber int64, at time.Time) error {
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" || caseNumber <= 0 {
+		return fmt.Errorf("guildID or case number is invalid")
+	}
+	if _, err := s.db.Exec(ctx,
+		`UPDATE moderation_case_records
+         SET expiry_logged_at=$3
+         WHERE guild_id=$1 AND case_number=$2`,
+		guildID, caseNumber, at.UTC(),
+	); err != nil {
+		return fmt.Errorf("Store.MarkModerationCaseExpiryLogged: %w", err)
+	}
+	return nil
+}
+
 // ListModerationWarnings lists moderation warnings utilizing iter.Seq2.
 func (s *Store) ListModerationWarnings(ctx context.Context, guildID, userID string, limit int) iter.Seq2[moderation.Warning, error] {
 	return func(yield func(moderation.Warning, error) bool) {
@@ -143,6 +506,448 @@ func (s *Store) ListModerationWarnings(ctx context.Context, guildID, userID stri
 	}
 }
 
+// CountModerationWarnings returns how many warnings userID holds in guildID.
+func (s *Store) CountModerationWarnings(ctx context.Context, guildID, userID string) (int, error) {
+	guildID = strings.TrimSpace(guildID)
+	userID = strings.TrimSpace(userID)
+	if guildID == "" || userID == "" {
+		return 0, fmt.Errorf("guildID or userID is empty")
+	}
+	var count int
+	if err := s.db.QueryRow(ctx,
+		`SELECT COUNT(*) FROM moderation_warnings WHERE guild_id=$1 AND user_id=$2`,
+		guildID, userID,
+	).Scan(&count); err != nil {
+		return 0, fmt.Errorf("Store.CountModerationWarnings: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteModerationWarning removes the warning recorded under caseNumber and
+// returns it. The case number itself is not reused.
+func (s *Store) DeleteModerationWarning(ctx context.Context, guildID string, caseNumber int64) (moderation.Warning, bool, error) {
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" || caseNumber <= 0 {
+		return moderation.Warning{}, false, fmt.Errorf("guildID or case number is invalid")
+	}
+	var warning moderation.Warning
+	err := s.db.QueryRow(ctx,
+		`DELETE FROM moderation_warnings
+         WHERE guild_id=$1 AND case_number=$2
+         RETURNING id, guild_id, user_id, case_number, moderator_id, reason, created_at`,
+		guildID, caseNumber,
+	).Scan(&warning.ID, &warning.GuildID, &warning.UserID, &warning.CaseNumber, &warning.ModeratorID, &warning.Reason, &warning.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return moderation.Warning{}, false, nil
+		}
+		return moderation.Warning{}, false, fmt.Errorf("Store.DeleteModerationWarning: %w", err)
+	}
+	warning.CreatedAt = warning.CreatedAt.UTC()
+	return warning, true, nil
+}
+
+// ClearModerationWarnings removes every warning userID holds in guildID and
+// returns how many were removed.
+func (s *Store) ClearModerationWarnings(ctx context.Context, guildID, userID string) (int64, error) {
+	guildID = strings.TrimSpace(guildID)
+	userID = strings.TrimSpace(userID)
+	if guildID == "" || userID == "" {
+		return 0, fmt.Errorf("guildID or userID is empty")
+	}
+	tag, err := s.db.Exec(ctx,
+		`DELETE FROM moderation_warnings WHERE guild_id=$1 AND user_id=$2`,
+		guildID, userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("Store.ClearModerationWarnings: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// CreateModerationNote records a moderator note about userID.
+func (s *Store) CreateModerationNote(ctx context.Context, guildID, userID, authorID, content string, createdAt time.Time) (moderation.Note, error) {
+	note := moderation.Note{
+		GuildID:   strings.TrimSpace(guildID),
+		UserID:    strings.TrimSpace(userID),
+		AuthorID:  strings.TrimSpace(authorID),
+		Content:   strings.TrimSpace(content),
+		CreatedAt: createdAt.UTC(),
+	}
+	if note.GuildID == "" || note.UserID == "" || note.AuthorID == "" || note.Content == "" {
+		return moderation.Note{}, fmt.Errorf("missing required fields for note")
+	}
+	if createdAt.IsZero() {
+		note.CreatedAt = time.Now().UTC()
+	}
+	if err := s.db.QueryRow(ctx,
+		`INSERT INTO moderation_notes (guild_id, user_id, author_id, content, created_at)
+         VALUES ($1, $2, $3, $4, $5)
+         RETURNING id`,
+		note.GuildID, note.UserID, note.AuthorID, note.Content, note.CreatedAt,
+	).Scan(&note.ID); err != nil {
+		return moderation.Note{}, fmt.Errorf("Store.CreateModerationNote: %w", err)
+	}
+	return note, nil
+}
+
+// ListModerationNotes lists the most recent notes about userID, newest first.
+func (s *Store) ListModerationNotes(ctx context.Context, guildID, userID string, limit int) iter.Seq2[moderation.Note, error] {
+	return func(yield func(moderation.Note, error) bool) {
+		guildID = strings.TrimSpace(guildID)
+		userID = strings.TrimSpace(userID)
+		if guildID == "" || userID == "" {
+			return
+		}
+		limit = min(max(limit, 1), 25)
+
+		rows, err := s.db.Query(ctx,
+			`SELECT id, guild_id, user_id, author_id, content, created_at
+             FROM moderation_notes
+             WHERE guild_id=$1 AND user_id=$2
+             ORDER BY created_at DESC, id DESC
+             LIMIT $3`,
+			guildID, userID, limit,
+		)
+		if err != nil {
+			yield(moderation.Note{}, fmt.Errorf("Store.ListModerationNotes: %w", err))
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var note moderation.Note
+			if err := rows.Scan(&note.ID, &note.GuildID, &note.UserID, &note.AuthorID, &note.Content, &note.CreatedAt); err != nil {
+				yield(moderation.Note{}, err)
+				return
+			}
+			note.CreatedAt = note.CreatedAt.UTC()
+			if !yield(note, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(moderation.Note{}, fmt.Errorf("Store.ListModerationNotes: %w", err))
+		}
+	}
+}
+
+// DeleteModerationNote removes a single note and returns it.
+func (s *Store) DeleteModerationNote(ctx context.Context, guildID string, noteID int64) (moderation.Note, bool, error) {
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" || noteID <= 0 {
+		return moderation.Note{}, false, fmt.Errorf("guildID or note ID is invalid")
+	}
+	var note moderation.Note
+	err := s.db.QueryRow(ctx,
+		`DELETE FROM moderation_notes
+         WHERE guild_id=$1 AND id=$2
+         RETURNING id, guild_id, user_id, author_id, content, created_at`,
+		guildID, noteID,
+	).Scan(&note.ID, &note.GuildID, &note.UserID, &note.AuthorID, &note.Content, &note.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return moderation.Note{}, false, nil
+		}
+		return moderation.Note{}, false, fmt.Errorf("Store.DeleteModerationNote: %w", err)
+	}
+	note.CreatedAt = note.CreatedAt.UTC()
+	return note, true, nil
+}
+
+// SaveChannelLock records the overwrite a channel had before it was locked.
+// It reports false, without changing the stored record, when the channel is
+// already locked, so relocking never loses the original state.
+func (s *Store) SaveChannelLock(ctx context.Context, lock moderation.ChannelLock) (bool, error) {
+	lock.GuildID = strings.TrimSpace(lock.GuildID)
+	lock.ChannelID = strings.TrimSpace(lock.ChannelID)
+	if lock.GuildID == "" || lock.ChannelID == "" {
+		return false, fmt.Errorf("missing required fields for channel lock")
+	}
+	if lock.LockedAt.IsZero() {
+		lock.LockedAt = time.Now()
+	}
+	tag, err := s.db.Exec(ctx,
+		`INSERT INTO channel_locks (guild_id, channel_id, had_overwrite, allow_bits, deny_bits, locked_by, locked_at)
+         VALUES ($1, $2, $3, $4, $5, $6, $7)
+         ON CONFLICT (guild_id, channel_id) DO NOTHING`,
+		lock.GuildID, lock.ChannelID, lock.HadOverwrite, int64(lock.Allow), int64(lock.Deny), lock.LockedBy, lock.LockedAt.UTC(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("Store.SaveChannelLock: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// GetChannelLock returns the lock recorded for a channel, if any.
+func (s *Store) GetChannelLock(ctx context.Context, guildID, channelID string) (moderation.ChannelLock, bool, error) {
+	guildID = strings.TrimSpace(guildID)
+	channelID = strings.TrimSpace(channelID)
+	if guildID == "" || channelID == "" {
+		return moderation.ChannelLock{}, false, nil
+	}
+	lock, err := scanChannelLock(s.db.QueryRow(ctx,
+		`SELECT guild_id, channel_id, had_overwrite, allow_bits, deny_bits, locked_by, locked_at
+         FROM channel_locks
+         WHERE guild_id=$1 AND channel_id=$2`,
+		guildID, channelID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return moderation.ChannelLock{}, false, nil
+		}
+		return moderation.ChannelLock{}, false, fmt.Errorf("Store.GetChannelLock: %w", err)
+	}
+	return lock, true, nil
+}
+
+// ListChannelLocks lists every locked channel of a guild, oldest lock first.
+func (s *Store) ListChannelLocks(ctx context.Context, guildID string) iter.Seq2[moderation.ChannelLock, error] {
+	return func(yield func(moderation.ChannelLock, error) bool) {
+		guildID = strings.TrimSpace(guildID)
+		if guildID == "" {
+			return
+		}
+		rows, err := s.db.Query(ctx,
+			`SELECT guild_id, channel_id, had_overwrite, allow_bits, deny_bits, locked_by, locked_at
+             FROM channel_locks
+             WHERE guild_id=$1
+             ORDER BY locked_at, channel_id`,
+			guildID,
+		)
+		if err != nil {
+			yield(moderation.ChannelLock{}, fmt.Errorf("Store.ListChannelLocks: %w", err))
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			lock, err := scanChannelLock(rows)
+			if err != nil {
+				yield(moderation.ChannelLock{}, err)
+				return
+			}
+			if !yield(lock, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(moderation.ChannelLock{}, fmt.Errorf("Store.ListChannelLocks: %w", err))
+		}
+	}
+}
+
+// DeleteChannelLock forgets the lock of a channel once it has been restored.
+func (s *Store) DeleteChannelLock(ctx context.Context, guildID, channelID string) error {
+	if _, err := s.db.Exec(ctx,
+		`DELETE FROM channel_locks WHERE guild_id=$1 AND channel_id=$2`,
+		strings.TrimSpace(guildID), strings.TrimSpace(channelID),
+	); err != nil {
+		return fmt.Errorf("Store.DeleteChannelLock: %w", err)
+	}
+	return nil
+}
+
+func scanChannelLock(row pgx.Row) (moderation.ChannelLock, error) {
+	var (
+		lock        moderation.ChannelLock
+		allow, deny int64
+	)
+	if err := row.Scan(&lock.GuildID, &lock.ChannelID, &lock.HadOverwrite, &allow, &deny, &lock.LockedBy, &lock.LockedAt); err != nil {
+		return moderation.ChannelLock{}, err
+	}
+	lock.Allow, lock.Deny = uint64(allow), uint64(deny)
+	lock.LockedAt = lock.LockedAt.UTC()
+	return lock, nil
+}
+
+const raidModeColumns = `guild_id, enabled_by, enabled_at, expires_at, previous_verification,
+                raised_verification, invites_paused, min_account_age_seconds`
+
+// SaveRaidMode records a guild's raid mode. It reports false without
+// changing anything when the guild is already in raid mode.
+func (s *Store) SaveRaidMode(ctx context.Context, mode moderation.RaidMode) (bool, error) {
+	mode.GuildID = strings.TrimSpace(mode.GuildID)
+	if mode.GuildID == "" || mode.ExpiresAt.IsZero() {
+		return false, fmt.Errorf("missing required fields for raid mode")
+	}
+	if mode.EnabledAt.IsZero() {
+		mode.EnabledAt = time.Now()
+	}
+	tag, err := s.db.Exec(ctx,
+		`INSERT INTO raid_modes (`+raidModeColumns+`)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+         ON CONFLICT (guild_id) DO NOTHING`,
+		mode.GuildID, mode.EnabledBy, mode.EnabledAt.UTC(), mode.ExpiresAt.UTC(), mode.PreviousVerification,
+		mode.RaisedVerification, mode.InvitesPaused, int64(mode.MinAccountAge/time.Second),
+	)
+	if err != nil {
+		return false, fmt.Errorf("Store.SaveRaidMode: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// GetRaidMode returns the raid mode of a guild, if it is in one.
+func (s *Store) GetRaidMode(ctx context.Context, guildID string) (moderation.RaidMode, bool, error) {
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" {
+		return moderation.RaidMode{}, false, nil
+	}
+	mode, err := scanRaidMode(s.db.QueryRow(ctx,
+		`SELECT `+raidModeColumns+`
+         FROM raid_modes
+         WHERE guild_id=$1`,
+		guildID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return moderation.RaidMode{}, false, nil
+		}
+		return moderation.RaidMode{}, false, fmt.Errorf("Store.GetRaidMode: %w", err)
+	}
+	return mode, true, nil
+}
+
+// ListExpiredRaidModes returns every raid mode due to be lifted at now,
+// across guilds, oldest expiry first.
+func (s *Store) ListExpiredRaidModes(ctx context.Context, now time.Time) ([]moderation.RaidMode, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT `+raidModeColumns+`
+         FROM raid_modes
+         WHERE expires_at <= $1
+         ORDER BY expires_at, guild_id`,
+		now.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Store.ListExpiredRaidModes: %w", err)
+	}
+	defer rows.Close()
+
+	var out []moderation.RaidMode
+	for rows.Next() {
+		mode, err := scanRaidMode(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Store.ListExpiredRaidModes: %w", err)
+		}
+		out = append(out, mode)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Store.ListExpiredRaidModes: %w", err)
+	}
+	return out, nil
+}
+
+// DeleteRaidMode forgets the raid mode of a guild once it has been lifted.
+func (s *Store) DeleteRaidMode(ctx context.Context, guildID string) error {
+	if _, err := s.db.Exec(ctx,
+		`DELETE FROM raid_modes WHERE guild_id=$1`,
+		strings.TrimSpace(guildID),
+	); err != nil {
+		return fmt.Errorf("Store.DeleteRaidMode: %w", err)
+	}
+	return nil
+}
+
+func scanRaidMode(row pgx.Row) (moderation.RaidMode, error) {
+	var (
+		mode       moderation.RaidMode
+		minAgeSecs int64
+	)
+	if err := row.Scan(&mode.GuildID, &mode.EnabledBy, &mode.EnabledAt, &mode.ExpiresAt, &mode.PreviousVerification,
+		&mode.RaisedVerification, &mode.InvitesPaused, &minAgeSecs); err != nil {
+		return moderation.RaidMode{}, err
+	}
+	mode.EnabledAt = mode.EnabledAt.UTC()
+	mode.ExpiresAt = mode.ExpiresAt.UTC()
+	mode.MinAccountAge = time.Duration(minAgeSecs) * time.Second
+	return mode, nil
+}
+
+const pooledBanColumns = `id, pools, origin_guild_id, origin_case, user_id, moderator_id, reason, created_at`
+
+// CreatePooledBan shares a ban with the guilds of ban.Pools and returns it
+// with its ID.
+func (s *Store) CreatePooledBan(ctx context.Context, ban moderation.PooledBan) (moderation.PooledBan, error) {
+	ban.OriginGuildID = strings.TrimSpace(ban.OriginGuildID)
+	ban.UserID = strings.TrimSpace(ban.UserID)
+	ban.Reason = strings.TrimSpace(ban.Reason)
+	if ban.OriginGuildID == "" || ban.UserID == "" || len(ban.Pools) == 0 {
+		return moderation.PooledBan{}, fmt.Errorf("missing required fields for pooled ban")
+	}
+	if ban.CreatedAt.IsZero() {
+		ban.CreatedAt = time.Now()
+	}
+	ban.CreatedAt = ban.CreatedAt.UTC()
+	if err := s.db.QueryRow(ctx,
+		`INSERT INTO pooled_bans (pools, origin_guild_id, origin_case, user_id, moderator_id, reason, created_at)
+         VALUES ($1, $2, $3, $4, $5, $6, $7)
+         RETURNING id`,
+		ban.Pools, ban.OriginGuildID, ban.OriginCase, ban.UserID, ban.ModeratorID, ban.Reason, ban.CreatedAt,
+	).Scan(&ban.ID); err != nil {
+		return moderation.PooledBan{}, fmt.Errorf("Store.CreatePooledBan: %w", err)
+	}
+	return ban, nil
+}
+
+// ListPendingPooledBans returns up to limit bans shared since then through
+// any of pools that guildID has not applied yet, oldest first. Bans the
+// guild issued itself are left out.
+func (s *Store) ListPendingPooledBans(ctx context.Context, guildID string, pools []string, since time.Time, limit int) ([]moderation.PooledBan, error) {
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" || len(pools) == 0 {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := s.db.Query(ctx,
+		`SELECT `+pooledBanColumns+`
+         FROM pooled_bans b
+         WHERE pools && $2 AND origin_guild_id <> $1 AND created_at >= $3
+           AND NOT EXISTS (
+               SELECT 1 FROM pooled_ban_deliveries d
+               WHERE d.ban_id = b.id AND d.guild_id = $1
+           )
+         ORDER BY created_at, id
+         LIMIT $4`,
+		guildID, pools, since.UTC(), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Store.ListPendingPooledBans: %w", err)
+	}
+	defer rows.Close()
+
+	var out []moderation.PooledBan
+	for rows.Next() {
+		var ban moderation.PooledBan
+		if err := rows.Scan(&ban.ID, &ban.Pools, &ban.OriginGuildID, &ban.OriginCase, &ban.UserID,
+			&ban.ModeratorID, &ban.Reason, &ban.CreatedAt); err != nil {
+			return nil, fmt.Errorf("Store.ListPendingPooledBans: %w", err)
+		}
+		ban.CreatedAt = ban.CreatedAt.UTC()
+		out = append(out, ban)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Store.ListPendingPooledBans: %w", err)
+	}
+	return out, nil
+}
+
+// MarkPooledBanDelivered settles a pooled ban for guildID so it is not
+// applied again. caseNumber is the case it was recorded under there, zero
+// when it was skipped.
+func (s *Store) MarkPooledBanDelivered(ctx context.Context, banID int64, guildID string, caseNumber int64, at time.Time) error {
+	if _, err := s.db.Exec(ctx,
+		`INSERT INTO pooled_ban_deliveries (ban_id, guild_id, case_number, delivered_at)
+         VALUES ($1, $2, $3, $4)
+         ON CONFLICT (ban_id, guild_id) DO NOTHING`,
+		banID, strings.TrimSpace(guildID), caseNumber, at.UTC(),
+	); err != nil {
+		return fmt.Errorf("Store.MarkPooledBanDelivered: %w", err)
+	}
+	return nil
+}
+
 // SetGuildOwnerID sets or updates the cached owner ID for a guild.
 func (s *Store) SetGuildOwnerID(ctx context.Context, guildID, ownerID string) error {
 	if guildID == "" || ownerID == "" {
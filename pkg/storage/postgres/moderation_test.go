@@ -41,13 +41,21 @@ func TestStore_Moderation_CreateWarning(t *testing.T) {
 	defer mock.Close()
 	store, _ := NewStore(mock, nil)
 
+	caseArgs := make([]any, 17)
+	for i := range caseArgs {
+		caseArgs[i] = pgxmock.AnyArg()
+	}
 	mock.ExpectBegin()
 	mock.ExpectQuery("INSERT INTO moderation_cases").WithArgs(pgxmock.AnyArg()).WillReturnRows(pgxmock.NewRows([]string{"last_case_number"}).AddRow(int64(1)))
 	mock.ExpectQuery("INSERT INTO moderation_warnings").WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(int64(10), time.Now()))
+	mock.ExpectQuery("INSERT INTO moderation_case_records").WithArgs(caseArgs...).WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(int64(11), time.Now()))
 	mock.ExpectCommit()
 	mock.ExpectRollback()
 
-	store.CreateModerationWarning(context.Background(), "guild1", "user1", "mod1", "spam", time.Now())
+	w, err := store.CreateModerationWarning(context.Background(), "guild1", "user1", "mod1", "spam", time.Now())
+	if err != nil || w.CaseNumber != 1 {
+		t.Fatalf("CreateModerationWarning = %+v, %v", w, err)
+	}
 }
 
 func TestStore_Moderation_NextModerationCaseNumber_Errors(t *testing.T) {
@@ -232,3 +240,653 @@ func TestStore_Moderation_GuildOwner(t *testing.T) {
 		}
 	})
 }
+
+func TestStore_Moderation_CreateModerationCase(t *testing.T) {
+	t.Parallel()
+	t.Run("records case under shared numbering", func(t *testing.T) {
+		idgen.Init(1)
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		args := make([]any, 17)
+		for i := range args {
+			args[i] = pgxmock.AnyArg()
+		}
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO moderation_cases").WithArgs("guild1").WillReturnRows(pgxmock.NewRows([]string{"last_case_number"}).AddRow(int64(7)))
+		mock.ExpectQuery("INSERT INTO moderation_case_records").WithArgs(args...).WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(int64(10), time.Now()))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		c, err := store.CreateModerationCase(context.Background(), moderation.Case{
+			GuildID:        "guild1",
+			UserID:         "user1",
+			Action:         "automod_block",
+			Source:         moderation.CaseSourceAutomod,
+			MatchedKeyword: "spam",
+		})
+		if err != nil {
+			t.Fatalf("CreateModerationCase: %v", err)
+		}
+		if c.CaseNumber != 7 || c.ID != 10 {
+			t.Fatalf("unexpected case: %+v", c)
+		}
+	})
+
+	t.Run("keeps a reserved case number", func(t *testing.T) {
+		idgen.Init(1)
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		args := make([]any, 17)
+		for i := range args {
+			args[i] = pgxmock.AnyArg()
+		}
+		args[2] = int64(9)
+		args[14] = "Could not DM the member."
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO moderation_case_records").WithArgs(args...).WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(int64(11), time.Now()))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		c, err := store.CreateModerationCase(context.Background(), moderation.Case{
+			GuildID:    "guild1",
+			UserID:     "user1",
+			Action:     "ban",
+			CaseNumber: 9,
+			Extra:      "Could not DM the member.",
+		})
+		if err != nil {
+			t.Fatalf("CreateModerationCase: %v", err)
+		}
+		if c.CaseNumber != 9 {
+			t.Fatalf("reserved case number was replaced: %+v", c)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("timed case settles earlier ones", func(t *testing.T) {
+		idgen.Init(1)
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		created := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+		expires := created.Add(time.Hour)
+		args := make([]any, 17)
+		for i := range args {
+			args[i] = pgxmock.AnyArg()
+		}
+		args[15] = &expires
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE moderation_case_records\s+SET expiry_logged_at=\$3`).
+			WithArgs("guild1", "user1", created).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectQuery("INSERT INTO moderation_case_records").WithArgs(args...).WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(int64(12), created))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		c, err := store.CreateModerationCase(context.Background(), moderation.Case{
+			GuildID:    "guild1",
+			UserID:     "user1",
+			Action:     "timeout",
+			CaseNumber: 10,
+			ExpiresAt:  expires,
+			CreatedAt:  created,
+		})
+		if err != nil {
+			t.Fatalf("CreateModerationCase: %v", err)
+		}
+		if !c.ExpiresAt.Equal(expires) {
+			t.Fatalf("expiry was not kept: %+v", c)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		if _, err := store.CreateModerationCase(context.Background(), moderation.Case{GuildID: "g"}); err == nil {
+			t.Error("expected validation error")
+		}
+	})
+}
+
+func TestStore_Moderation_WarningMaintenance(t *testing.T) {
+	t.Parallel()
+	t.Run("count", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM moderation_warnings`).
+			WithArgs("g1", "u1").
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(3))
+
+		count, err := store.CountModerationWarnings(context.Background(), "g1", "u1")
+		if err != nil || count != 3 {
+			t.Fatalf("CountModerationWarnings: got %d, %v", count, err)
+		}
+	})
+
+	t.Run("delete missing case", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`DELETE FROM moderation_warnings`).
+			WithArgs("g1", int64(9)).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, found, err := store.DeleteModerationWarning(context.Background(), "g1", 9)
+		if err != nil || found {
+			t.Fatalf("DeleteModerationWarning: got found=%v, err=%v", found, err)
+		}
+	})
+
+	t.Run("delete existing case", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`DELETE FROM moderation_warnings`).
+			WithArgs("g1", int64(4)).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "guild_id", "user_id", "case_number", "moderator_id", "reason", "created_at"}).
+				AddRow(int64(1), "g1", "u1", int64(4), "mod1", "spam", time.Now()))
+
+		warning, found, err := store.DeleteModerationWarning(context.Background(), "g1", 4)
+		if err != nil || !found || warning.UserID != "u1" {
+			t.Fatalf("DeleteModerationWarning: got %+v, found=%v, err=%v", warning, found, err)
+		}
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectExec(`DELETE FROM moderation_warnings`).
+			WithArgs("g1", "u1").
+			WillReturnResult(pgxmock.NewResult("DELETE", 2))
+
+		removed, err := store.ClearModerationWarnings(context.Background(), "g1", "u1")
+		if err != nil || removed != 2 {
+			t.Fatalf("ClearModerationWarnings: got %d, %v", removed, err)
+		}
+	})
+}
+
+func TestStore_Moderation_CaseManagement(t *testing.T) {
+	t.Parallel()
+	caseColumns := []string{"id", "guild_id", "case_number", "action", "user_id", "moderator_id", "reason", "source",
+		"channel_id", "message_id", "rule_id", "matched_keyword", "matched_content", "content", "extra",
+		"log_channel_id", "log_message_id", "expires_at", "created_at", "updated_at", "voided_at", "voided_by"}
+	now := time.Now()
+	caseRow := func(reason string, voidedAt *time.Time, voidedBy string) *pgxmock.Rows {
+		return pgxmock.NewRows(caseColumns).AddRow(int64(1), "g1", int64(5), "ban", "u1", "mod1", reason, moderation.CaseSourceManual,
+			"", "", "", "", "", "", "", "c1", "m1", nil, now, now, voidedAt, voidedBy)
+	}
+
+	t.Run("get", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`SELECT .* FROM moderation_case_records`).
+			WithArgs("g1", int64(5)).
+			WillReturnRows(caseRow("spam", nil, ""))
+
+		c, found, err := store.GetModerationCase(context.Background(), "g1", 5)
+		if err != nil || !found || c.Reason != "spam" || c.LogMessageID != "m1" || c.Voided() {
+			t.Fatalf("GetModerationCase: got %+v, found=%v, err=%v", c, found, err)
+		}
+	})
+
+	t.Run("get missing", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`SELECT .* FROM moderation_case_records`).
+			WithArgs("g1", int64(6)).
+			WillReturnError(pgx.ErrNoRows)
+
+		if _, found, err := store.GetModerationCase(context.Background(), "g1", 6); err != nil || found {
+			t.Fatalf("GetModerationCase: got found=%v, err=%v", found, err)
+		}
+	})
+
+	t.Run("list with filter", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		since := now.Add(-30 * 24 * time.Hour)
+		mock.ExpectQuery(`SELECT .* FROM moderation_case_records\s+WHERE guild_id=\$1 AND created_at >= \$2`).
+			WithArgs("g1", since.UTC(), "ban", "mod1", moderation.MaxCaseExport).
+			WillReturnRows(caseRow("spam", nil, ""))
+
+		cases, err := store.ListModerationCases(context.Background(), "g1", moderation.CaseFilter{Since: since, Action: "ban", ModeratorID: " mod1 "})
+		if err != nil || len(cases) != 1 || cases[0].CaseNumber != 5 {
+			t.Fatalf("ListModerationCases: got %+v, err=%v", cases, err)
+		}
+	})
+
+	t.Run("action counts", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		from, to := moderation.PreviousMonth(now)
+		mock.ExpectQuery(`SELECT action, COUNT\(\*\) FILTER`).
+			WithArgs("g1", from, to).
+			WillReturnRows(pgxmock.NewRows([]string{"action", "active", "voided"}).
+				AddRow("ban", 3, 1).
+				AddRow("kick", 0, 2))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM moderation_warnings`).
+			WithArgs("g1", from, to).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(4))
+
+		counts, err := store.ModerationActionCounts(context.Background(), "g1", from, to)
+		if err != nil {
+			t.Fatalf("ModerationActionCounts: %v", err)
+		}
+		if len(counts.ByAction) != 1 || counts.ByAction["ban"] != 3 || counts.Voided != 3 || counts.Warnings != 4 {
+			t.Fatalf("unexpected counts %+v", counts)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("update reason", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`UPDATE moderation_case_records\s+SET reason`).
+			WithArgs("g1", int64(5), "raiding").
+			WillReturnRows(caseRow("raiding", nil, ""))
+
+		c, found, err := store.UpdateModerationCaseReason(context.Background(), "g1", 5, " raiding ")
+		if err != nil || !found || c.Reason != "raiding" {
+			t.Fatalf("UpdateModerationCaseReason: got %+v, found=%v, err=%v", c, found, err)
+		}
+	})
+
+	t.Run("void", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`UPDATE moderation_case_records\s+SET voided_at`).
+			WithArgs("g1", int64(5), now.UTC(), "mod2").
+			WillReturnRows(caseRow("spam", &now, "mod2"))
+
+		c, found, err := store.VoidModerationCase(context.Background(), "g1", 5, "mod2", now)
+		if err != nil || !found || !c.Voided() || c.VoidedBy != "mod2" {
+			t.Fatalf("VoidModerationCase: got %+v, found=%v, err=%v", c, found, err)
+		}
+	})
+
+	t.Run("set log message", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectExec(`UPDATE moderation_case_records\s+SET log_channel_id`).
+			WithArgs("g1", int64(5), "c1", "m1").
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		if err := store.SetModerationCaseLogMessage(context.Background(), "g1", 5, "c1", "m1"); err != nil {
+			t.Fatalf("SetModerationCaseLogMessage: %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("list expired", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		expired := now.Add(-time.Minute)
+		mock.ExpectQuery(`SELECT .* FROM moderation_case_records\s+WHERE guild_id=\$1 AND expires_at <= \$2 AND expiry_logged_at IS NULL`).
+			WithArgs("g1", now.UTC(), 50).
+			WillReturnRows(pgxmock.NewRows(caseColumns).AddRow(int64(1), "g1", int64(5), "timeout", "u1", "mod1", "spam", moderation.CaseSourceManual,
+				"", "", "", "", "", "", "", "c1", "m1", &expired, now, now, nil, ""))
+
+		cases, err := store.ListExpiredModerationCases(context.Background(), "g1", now, 50)
+		if err != nil || len(cases) != 1 || !cases[0].ExpiresAt.Equal(expired) {
+			t.Fatalf("ListExpiredModerationCases: got %+v, err=%v", cases, err)
+		}
+	})
+
+	t.Run("count pending expiries", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FILTER .* FROM moderation_case_records\s+WHERE guild_id=\$1 AND expires_at IS NOT NULL`).
+			WithArgs("g1", now.UTC()).
+			WillReturnRows(pgxmock.NewRows([]string{"due", "running"}).AddRow(2, 5))
+
+		due, running, err := store.CountPendingCaseExpiries(context.Background(), "g1", now)
+		if err != nil || due != 2 || running != 5 {
+			t.Fatalf("CountPendingCaseExpiries: due=%d running=%d err=%v", due, running, err)
+		}
+	})
+
+	t.Run("mark expiry logged", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectExec(`UPDATE moderation_case_records\s+SET expiry_logged_at`).
+			WithArgs("g1", int64(5), now.UTC()).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		if err := store.MarkModerationCaseExpiryLogged(context.Background(), "g1", 5, now); err != nil {
+			t.Fatalf("MarkModerationCaseExpiryLogged: %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestStore_Moderation_Notes(t *testing.T) {
+	t.Parallel()
+	noteColumns := []string{"id", "guild_id", "user_id", "author_id", "content", "created_at"}
+	now := time.Now()
+
+	t.Run("create", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`INSERT INTO moderation_notes`).
+			WithArgs("g1", "u1", "mod1", "alt of banned user", now.UTC()).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(7)))
+
+		note, err := store.CreateModerationNote(context.Background(), "g1", "u1", "mod1", " alt of banned user ", now)
+		if err != nil || note.ID != 7 || note.Content != "alt of banned user" {
+			t.Fatalf("CreateModerationNote: got %+v, err=%v", note, err)
+		}
+	})
+
+	t.Run("create rejects empty content", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		if _, err := store.CreateModerationNote(context.Background(), "g1", "u1", "mod1", "  ", now); err == nil {
+			t.Fatal("expected an error for an empty note")
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`SELECT .* FROM moderation_notes`).
+			WithArgs("g1", "u1", 25).
+			WillReturnRows(pgxmock.NewRows(noteColumns).
+				AddRow(int64(8), "g1", "u1", "mod2", "second", now).
+				AddRow(int64(7), "g1", "u1", "mod1", "first", now))
+
+		var ids []int64
+		for note, err := range store.ListModerationNotes(context.Background(), "g1", "u1", 100) {
+			if err != nil {
+				t.Fatalf("ListModerationNotes: %v", err)
+			}
+			ids = append(ids, note.ID)
+		}
+		if len(ids) != 2 || ids[0] != 8 || ids[1] != 7 {
+			t.Fatalf("ListModerationNotes ids = %v", ids)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`DELETE FROM moderation_notes`).
+			WithArgs("g1", int64(7)).
+			WillReturnRows(pgxmock.NewRows(noteColumns).AddRow(int64(7), "g1", "u1", "mod1", "first", now))
+		mock.ExpectQuery(`DELETE FROM moderation_notes`).
+			WithArgs("g1", int64(9)).
+			WillReturnError(pgx.ErrNoRows)
+
+		if note, found, err := store.DeleteModerationNote(context.Background(), "g1", 7); err != nil || !found || note.UserID != "u1" {
+			t.Fatalf("DeleteModerationNote: got %+v, found=%v, err=%v", note, found, err)
+		}
+		if _, found, err := store.DeleteModerationNote(context.Background(), "g1", 9); err != nil || found {
+			t.Fatalf("DeleteModerationNote missing: found=%v, err=%v", found, err)
+		}
+	})
+}
+
+func TestStore_Moderation_ChannelLocks(t *testing.T) {
+	t.Parallel()
+	lockColumns := []string{"guild_id", "channel_id", "had_overwrite", "allow_bits", "deny_bits", "locked_by", "locked_at"}
+	now := time.Now()
+
+	t.Run("save keeps the first record", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		lock := moderation.ChannelLock{GuildID: "g1", ChannelID: "c1", HadOverwrite: true, Allow: 1 << 10, Deny: 1 << 15, LockedBy: "mod1", LockedAt: now}
+		mock.ExpectExec(`INSERT INTO channel_locks`).
+			WithArgs("g1", "c1", true, int64(1<<10), int64(1<<15), "mod1", now.UTC()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec(`INSERT INTO channel_locks`).
+			WithArgs("g1", "c1", true, int64(1<<10), int64(1<<15), "mod1", now.UTC()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 0))
+
+		if saved, err := store.SaveChannelLock(context.Background(), lock); err != nil || !saved {
+			t.Fatalf("SaveChannelLock: saved=%v, err=%v", saved, err)
+		}
+		if saved, err := store.SaveChannelLock(context.Background(), lock); err != nil || saved {
+			t.Fatalf("SaveChannelLock on a locked channel: saved=%v, err=%v", saved, err)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`SELECT .* FROM channel_locks`).
+			WithArgs("g1", "c1").
+			WillReturnRows(pgxmock.NewRows(lockColumns).AddRow("g1", "c1", false, int64(0), int64(0), "mod1", now))
+		mock.ExpectQuery(`SELECT .* FROM channel_locks`).
+			WithArgs("g1", "c2").
+			WillReturnError(pgx.ErrNoRows)
+
+		lock, found, err := store.GetChannelLock(context.Background(), "g1", "c1")
+		if err != nil || !found || lock.HadOverwrite || lock.LockedBy != "mod1" {
+			t.Fatalf("GetChannelLock: got %+v, found=%v, err=%v", lock, found, err)
+		}
+		if _, found, err := store.GetChannelLock(context.Background(), "g1", "c2"); err != nil || found {
+			t.Fatalf("GetChannelLock missing: found=%v, err=%v", found, err)
+		}
+	})
+
+	t.Run("list and delete", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`SELECT .* FROM channel_locks`).
+			WithArgs("g1").
+			WillReturnRows(pgxmock.NewRows(lockColumns).
+				AddRow("g1", "c1", true, int64(0), int64(1<<11), "mod1", now).
+				AddRow("g1", "c2", false, int64(0), int64(0), "mod1", now))
+		mock.ExpectExec(`DELETE FROM channel_locks`).
+			WithArgs("g1", "c1").
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+		var channels []string
+		for lock, err := range store.ListChannelLocks(context.Background(), "g1") {
+			if err != nil {
+				t.Fatalf("ListChannelLocks: %v", err)
+			}
+			channels = append(channels, lock.ChannelID)
+		}
+		if len(channels) != 2 || channels[0] != "c1" {
+			t.Fatalf("ListChannelLocks channels = %v", channels)
+		}
+		if err := store.DeleteChannelLock(context.Background(), "g1", "c1"); err != nil {
+			t.Fatalf("DeleteChannelLock: %v", err)
+		}
+	})
+}
+
+func TestStore_Moderation_RaidModes(t *testing.T) {
+	t.Parallel()
+	raidColumns := []string{"guild_id", "enabled_by", "enabled_at", "expires_at", "previous_verification",
+		"raised_verification", "invites_paused", "min_account_age_seconds"}
+	now := time.Now()
+
+	t.Run("save keeps the first record", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mode := moderation.RaidMode{GuildID: "g1", EnabledBy: "mod1", EnabledAt: now, ExpiresAt: now.Add(time.Hour),
+			PreviousVerification: 1, RaisedVerification: true, InvitesPaused: true, MinAccountAge: 72 * time.Hour}
+		mock.ExpectExec(`INSERT INTO raid_modes`).
+			WithArgs("g1", "mod1", now.UTC(), now.Add(time.Hour).UTC(), 1, true, true, int64(72*3600)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec(`INSERT INTO raid_modes`).
+			WithArgs("g1", "mod1", now.UTC(), now.Add(time.Hour).UTC(), 1, true, true, int64(72*3600)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 0))
+
+		if saved, err := store.SaveRaidMode(context.Background(), mode); err != nil || !saved {
+			t.Fatalf("SaveRaidMode: saved=%v, err=%v", saved, err)
+		}
+		if saved, err := store.SaveRaidMode(context.Background(), mode); err != nil || saved {
+			t.Fatalf("SaveRaidMode during raid mode: saved=%v, err=%v", saved, err)
+		}
+		if _, err := store.SaveRaidMode(context.Background(), moderation.RaidMode{GuildID: "g1"}); err == nil {
+			t.Fatal("SaveRaidMode without an expiry should fail")
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`SELECT .* FROM raid_modes`).
+			WithArgs("g1").
+			WillReturnRows(pgxmock.NewRows(raidColumns).AddRow("g1", "mod1", now, now.Add(time.Hour), 2, true, false, int64(86400)))
+		mock.ExpectQuery(`SELECT .* FROM raid_modes`).
+			WithArgs("g2").
+			WillReturnError(pgx.ErrNoRows)
+
+		mode, found, err := store.GetRaidMode(context.Background(), "g1")
+		if err != nil || !found || mode.PreviousVerification != 2 || mode.InvitesPaused || mode.MinAccountAge != 24*time.Hour {
+			t.Fatalf("GetRaidMode: got %+v, found=%v, err=%v", mode, found, err)
+		}
+		if _, found, err := store.GetRaidMode(context.Background(), "g2"); err != nil || found {
+			t.Fatalf("GetRaidMode missing: found=%v, err=%v", found, err)
+		}
+	})
+
+	t.Run("list expired and delete", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`SELECT .* FROM raid_modes\s+WHERE expires_at <= \$1`).
+			WithArgs(now.UTC()).
+			WillReturnRows(pgxmock.NewRows(raidColumns).
+				AddRow("g1", "mod1", now.Add(-2*time.Hour), now.Add(-time.Hour), 0, true, true, int64(0)).
+				AddRow("g2", "mod2", now.Add(-time.Hour), now, 1, false, false, int64(0)))
+		mock.ExpectExec(`DELETE FROM raid_modes`).
+			WithArgs("g1").
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+		modes, err := store.ListExpiredRaidModes(context.Background(), now)
+		if err != nil || len(modes) != 2 || modes[0].GuildID != "g1" {
+			t.Fatalf("ListExpiredRaidModes: got %+v, err=%v", modes, err)
+		}
+		if err := store.DeleteRaidMode(context.Background(), "g1"); err != nil {
+			t.Fatalf("DeleteRaidMode: %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestStore_Moderation_PooledBans(t *testing.T) {
+	t.Parallel()
+	banColumns := []string{"id", "pools", "origin_guild_id", "origin_case", "user_id", "moderator_id", "reason", "created_at"}
+	now := time.Now()
+
+	t.Run("create", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		pools := []string{"network"}
+		mock.ExpectQuery(`INSERT INTO pooled_bans`).
+			WithArgs(pools, "g1", int64(4), "u1", "mod1", "spam", now.UTC()).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(9)))
+
+		ban, err := store.CreatePooledBan(context.Background(), moderation.PooledBan{
+			Pools: pools, OriginGuildID: "g1", OriginCase: 4, UserID: "u1", ModeratorID: "mod1", Reason: " spam ", CreatedAt: now,
+		})
+		if err != nil || ban.ID != 9 || ban.Reason != "spam" {
+			t.Fatalf("CreatePooledBan: got %+v, err=%v", ban, err)
+		}
+		if _, err := store.CreatePooledBan(context.Background(), moderation.PooledBan{OriginGuildID: "g1", UserID: "u1"}); err == nil {
+			t.Fatal("CreatePooledBan without a pool should fail")
+		}
+	})
+
+	t.Run("list pending and mark delivered", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		pools := []string{"network", "partners"}
+		since := now.Add(-24 * time.Hour)
+		mock.ExpectQuery(`SELECT .* FROM pooled_bans b\s+WHERE pools && \$2 AND origin_guild_id <> \$1`).
+			WithArgs("g2", pools, since.UTC(), 50).
+			WillReturnRows(pgxmock.NewRows(banColumns).
+				AddRow(int64(9), []string{"network"}, "g1", int64(4), "u1", "mod1", "spam", now))
+		mock.ExpectExec(`INSERT INTO pooled_ban_deliveries`).
+			WithArgs(int64(9), "g2", int64(17), now.UTC()).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		bans, err := store.ListPendingPooledBans(context.Background(), "g2", pools, since, 0)
+		if err != nil || len(bans) != 1 || bans[0].ID != 9 || bans[0].OriginGuildID != "g1" || bans[0].Pools[0] != "network" {
+			t.Fatalf("ListPendingPooledBans: got %+v, err=%v", bans, err)
+		}
+		if err := store.MarkPooledBanDelivered(context.Background(), 9, "g2", 17, now); err != nil {
+			t.Fatalf("MarkPooledBanDelivered: %v", err)
+		}
+		if bans, err := store.ListPendingPooledBans(context.Background(), "g2", nil, since, 0); err != nil || bans != nil {
+			t.Fatalf("ListPendingPooledBans without pools: got %+v, err=%v", bans, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RetentionCutoffs holds, per data set, the time before which rows are
+// pruned. A zero time keeps that data set untouched.
+type RetentionCutoffs struct {
+	MessageCache  time.Time
+	AvatarHistory time.Time
+	LogEvents     time.Time
+}
+
+// retentionBatchSize bounds how many rows one DELETE removes, so a large
+// backlog is pruned in short statements instead of one long lock.
+const retentionBatchSize = 5000
+
+// retentionTables pairs each pruned table with its timestamp column and the
+// cutoff that applies to it.
+var retentionTables = []struct {
+	table  string
+	column string
+	cutoff func(RetentionCutoffs) time.Time
+}{
+	{"messages", "cached_at", func(c RetentionCutoffs) time.Time { return c.MessageCache }},
+	{"messages_history", "created_at", func(c RetentionCutoffs) time.Time { return c.MessageCache }},
+	{"clean_logs", "created_at", func(c RetentionCutoffs) time.Time { return c.MessageCache }},
+	{"avatars_history", "changed_at", func(c RetentionCutoffs) time.Time { return c.AvatarHistory }},
+	{"member_names_current", "updated_at", func(c RetentionCutoffs) time.Time { return c.AvatarHistory }},
+	{"log_events", "occurred_at", func(c RetentionCutoffs) time.Time { return c.LogEvents }},
+}
+
+// PruneGuildRetention deletes the rows of guildID older than cutoffs and
+// returns how many were removed.
+func (s *Store) PruneGuildRetention(ctx context.Context, guildID string, cutoffs RetentionCutoffs) (int64, error) {
+	if guildID == "" {
+		return 0, fmt.Errorf("Store.PruneGuildRetention: guild ID is required")
+	}
+	removed, err := s.pruneRetention(ctx, "guild_id = $2", guildID, cutoffs)
+	if err != nil {
+		return removed, fmt.Errorf("Store.PruneGuildRetention: %w", err)
+	}
+	return removed, nil
+}
+
+// PruneRetentionExcept deletes the rows older than cutoffs of every guild
+// not in guildIDs, so guilds without a configuration still follow the
+// defaults.
+func (s *Store) PruneRetentionExcept(ctx context.Context, guildIDs []string, cutoffs RetentionCutoffs) (int64, error) {
+	if guildIDs == nil {
+		guildIDs = []string{}
+	}
+	removed, err := s.pruneRetention(ctx, "guild_id <> ALL($2)", guildIDs, cutoffs)
+	if err != nil {
+		return removed, fmt.Errorf("Store.PruneRetentionExcept: %w", err)
+	}
+	return removed, nil
+}
+
+func (s *Store) pruneRetention(ctx context.Context, scope string, scopeArg any, cutoffs RetentionCutoffs) (int64, error) {
+	var removed int64
+	for _, t := range retentionTables {
+		cutoff := t.cutoff(cutoffs)
+		if cutoff.IsZero() {
+			continue
+		}
+		query := fmt.Sprintf(
+			`DELETE FROM %[1]s WHERE ctid IN (SELECT ctid FROM %[1]s WHERE %[2]s < $1 AND %[3]s LIMIT %[4]d)`,
+			t.table, t.column, scope, retentionBatchSize,
+		)
+		for {
+			if err := ctx.Err(); err != nil {
+				return removed, fmt.Errorf("prune %s: %w", t.table, err)
+			}
+			tag, err := s.db.Exec(ctx, query, cutoff.UTC(), scopeArg)
+			if err != nil {
+				return removed, fmt.Errorf("prune %s: %w", t.table, err)
+			}
+			removed += tag.RowsAffected()
+			if tag.RowsAffected() < retentionBatchSize {
+				break
+			}
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestStore_PruneGuildRetention(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+
+	messages := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	events := messages.AddDate(0, -6, 0)
+	// A full batch is followed by another until one comes back short.
+	mock.ExpectExec(`DELETE FROM messages WHERE ctid IN \(SELECT ctid FROM messages WHERE cached_at < \$1 AND guild_id = \$2 LIMIT 5000\)`).
+		WithArgs(messages, "g1").WillReturnResult(pgxmock.NewResult("DELETE", retentionBatchSize))
+	mock.ExpectExec(`DELETE FROM messages WHERE ctid IN \(SELECT ctid FROM messages WHERE cached_at < \$1 AND guild_id = \$2 LIMIT 5000\)`).
+		WithArgs(messages, "g1").WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	mock.ExpectExec(`DELETE FROM messages_history WHERE ctid IN \(SELECT ctid FROM messages_history WHERE created_at < \$1 AND guild_id = \$2 LIMIT 5000\)`).
+		WithArgs(messages, "g1").WillReturnResult(pgxmock.NewResult("DELETE", 2))
+	mock.ExpectExec(`DELETE FROM clean_logs WHERE ctid IN \(SELECT ctid FROM clean_logs WHERE created_at < \$1 AND guild_id = \$2 LIMIT 5000\)`).
+		WithArgs(messages, "g1").WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec(`DELETE FROM log_events WHERE ctid IN \(SELECT ctid FROM log_events WHERE occurred_at < \$1 AND guild_id = \$2 LIMIT 5000\)`).
+		WithArgs(events, "g1").WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	removed, err := store.PruneGuildRetention(context.Background(), "g1", RetentionCutoffs{MessageCache: messages, LogEvents: events})
+	if err != nil {
+		t.Fatalf("PruneGuildRetention: %v", err)
+	}
+	if removed != retentionBatchSize+6 {
+		t.Fatalf("expected %d rows removed, got %d", retentionBatchSize+6, removed)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_PruneRetentionExcept(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+
+	cutoff := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`DELETE FROM avatars_history WHERE ctid IN \(SELECT ctid FROM avatars_history WHERE changed_at < \$1 AND guild_id <> ALL\(\$2\) LIMIT 5000\)`).
+		WithArgs(cutoff, []string{}).WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	mock.ExpectExec(`DELETE FROM member_names_current WHERE ctid IN \(SELECT ctid FROM member_names_current WHERE updated_at < \$1 AND guild_id <> ALL\(\$2\) LIMIT 5000\)`).
+		WithArgs(cutoff, []string{}).WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	removed, err := store.PruneRetentionExcept(context.Background(), nil, RetentionCutoffs{AvatarHistory: cutoff})
+	if err != nil || removed != 4 {
+		t.Fatalf("expected 4 rows removed, got %d, %v", removed, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -22,6 +22,7 @@ var requiredSchemaTables = []string{
 	"runtime_meta",
 	"moderation_cases",
 	"moderation_warnings",
+	"moderation_case_records",
 	"roles_current",
 	"persistent_cache",
 	"daily_message_metrics",
@@ -30,6 +31,8 @@ var requiredSchemaTables = []string{
 	"ticket_sequences",
 	"guild_configs",
 	"user_preferences",
+	"error_log",
+	"command_audit",
 	"qotd_questions", // included since we need it in reset
 }
 
@@ -76,6 +79,19 @@ func (s *Store) Init() error {
 	return nil
 }
 
+// Verify checks the schema without changing it, for instances connected to a
+// read-only replica where Init's repairs would fail.
+func (s *Store) Verify() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.validateSchema(ctx); err != nil {
+		s.log().Error("schema validation failed", slog.String("error", err.Error()))
+		return fmt.Errorf("validate schema: %w", err)
+	}
+	return nil
+}
+
 func (s *Store) ensureMemberJoinColumns(ctx context.Context) (err error) {
 	missing, err := s.missingColumns(ctx, "member_joins", []string{"last_seen_at", "is_bot", "left_at"})
 	if err != nil {
@@ -0,0 +1,140 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/members"
+	"github.com/small-frappuccino/discordcore/pkg/messages"
+	"github.com/small-frappuccino/discordcore/pkg/moderation"
+	"github.com/small-frappuccino/discordcore/pkg/storage/postgres"
+	"github.com/small-frappuccino/discordcore/pkg/testdb"
+)
+
+// NewTempStore returns a *postgres.Store on a freshly migrated schema of
+// its own, dropped when t ends. t is skipped when testdb.EnvDatabaseURL is
+// not set.
+func NewTempStore(t testing.TB) *postgres.Store {
+	t.Helper()
+	baseDSN, err := testdb.BaseDatabaseURLFromEnv()
+	if testdb.IsDatabaseURLNotConfigured(err) {
+		t.Skip("skipping storage test, " + testdb.EnvDatabaseURL + " not set")
+	}
+	pool, cleanup, err := testdb.OpenIsolatedDatabase(context.Background(), baseDSN)
+	if err != nil {
+		t.Fatalf("open isolated database: %v", err)
+	}
+	t.Cleanup(func() { _ = cleanup() })
+	store, err := postgres.NewStore(pool, nil)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	return store
+}
+
+// NewSeededStore is NewTempStore with guilds seeded into it.
+func NewSeededStore(t testing.TB, guilds ...*Guild) *postgres.Store {
+	t.Helper()
+	store := NewTempStore(t)
+	for _, guild := range guilds {
+		if err := guild.Seed(context.Background(), store); err != nil {
+			t.Fatalf("seed guild %s: %v", guild.ID, err)
+		}
+	}
+	return store
+}
+
+// Guild builds the stored data of one guild: moderation cases, member
+// snapshots with their avatars, and daily message counts. Times are given
+// relative to Now, so seeded data lines up with the clock a test fakes.
+type Guild struct {
+	ID  string
+	Now time.Time
+
+	Cases    []moderation.Case
+	Members  []members.Snapshot
+	Messages []messages.DailyCountDelta
+}
+
+// NewGuild starts an empty guild whose data is dated relative to now.
+func NewGuild(guildID string, now time.Time) *Guild {
+	return &Guild{ID: guildID, Now: now.UTC()}
+}
+
+// Case adds a manual case against userID, created ago before Now.
+func (g *Guild) Case(action, userID, moderatorID, reason string, ago time.Duration) *Guild {
+	g.Cases = append(g.Cases, moderation.Case{
+		GuildID:     g.ID,
+		Action:      action,
+		UserID:      userID,
+		ModeratorID: moderatorID,
+		Reason:      reason,
+		Source:      moderation.CaseSourceManual,
+		CreatedAt:   g.Now.Add(-ago),
+	})
+	return g
+}
+
+// Member adds a member with the given avatar hash and roles, joined ago
+// before Now. An empty hash is a member without an avatar.
+func (g *Guild) Member(userID, avatarHash string, ago time.Duration, roleIDs ...string) *Guild {
+	g.Members = append(g.Members, members.Snapshot{
+		UserID:     userID,
+		AvatarHash: avatarHash,
+		HasAvatar:  true,
+		Roles:      roleIDs,
+		HasRoles:   true,
+		JoinedAt:   g.Now.Add(-ago),
+		HasBot:     true,
+	})
+	return g
+}
+
+// MessageCount adds count messages on the day daysAgo days before Now.
+func (g *Guild) MessageCount(daysAgo, count int) *Guild {
+	day := g.Now.Truncate(24*time.Hour).AddDate(0, 0, -daysAgo)
+	g.Messages = append(g.Messages, messages.DailyCountDelta{GuildID: g.ID, Day: day, Count: count})
+	return g
+}
+
+// Seed writes the guild into store through its public methods. Cases are
+// numbered in the order they were added.
+func (g *Guild) Seed(ctx context.Context, store *postgres.Store) error {
+	for i, c := range g.Cases {
+		created, err := store.CreateModerationCase(ctx, c)
+		if err != nil {
+			return fmt.Errorf("Guild.Seed: case %d: %w", i+1, err)
+		}
+		g.Cases[i] = created
+	}
+	if err := store.UpsertGuildMemberSnapshotsContext(ctx, g.ID, g.Members, g.Now); err != nil {
+		return fmt.Errorf("Guild.Seed: members: %w", err)
+	}
+	if err := store.IncrementDailyMessageCountsContext(ctx, g.Messages); err != nil {
+		return fmt.Errorf("Guild.Seed: message counts: %w", err)
+	}
+	return nil
+}
+
+// GoldenGuild returns the representative data set features are tested
+// against: five members, one of them a moderator and one without an
+// avatar; a warning, a timeout and a ban spread over the last month; and a
+// week of message counts.
+func GoldenGuild(guildID string, now time.Time) *Guild {
+	const day = 24 * time.Hour
+	g := NewGuild(guildID, now).
+		Member("1001", "a_moderator", 400*day, "9001").
+		Member("1002", "avatar1002", 90*day).
+		Member("1003", "avatar1003", 30*day).
+		Member("1004", "", 7*day).
+		Member("1005", "avatar1005", 2*time.Hour).
+		Case("warn", "1003", "1001", "spam in #general", 20*day).
+		Case("timeout", "1003", "1001", "spam again", 10*day).
+		Case("ban", "1005", "1001", "raid account", time.Hour)
+	for daysAgo, count := range []int{120, 95, 143, 80, 64, 101, 77} {
+		g.MessageCount(daysAgo, count)
+	}
+	return g
+}
@@ -0,0 +1,46 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/small-frappuccino/discordcore/pkg/moderation"
+)
+
+func TestGoldenGuild(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	g := GoldenGuild("100", now)
+
+	if len(g.Members) != 5 || len(g.Cases) != 3 || len(g.Messages) != 7 {
+		t.Fatalf("unexpected golden data: %d members, %d cases, %d message days", len(g.Members), len(g.Cases), len(g.Messages))
+	}
+	for _, c := range g.Cases {
+		if c.GuildID != "100" || !c.CreatedAt.Before(now) {
+			t.Fatalf("cases must belong to the guild and predate now, got %+v", c)
+		}
+	}
+	if g.Messages[0].Day != time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC) {
+		t.Fatalf("expected message counts to start today, got %v", g.Messages[0].Day)
+	}
+}
+
+func TestGoldenGuildSeed(t *testing.T) {
+	t.Parallel()
+	now := time.Now().UTC()
+	store := NewSeededStore(t, GoldenGuild("100", now), GoldenGuild("200", now))
+	ctx := context.Background()
+
+	cases, err := store.ListModerationCases(ctx, "100", moderation.CaseFilter{Since: now.AddDate(0, -2, 0)})
+	if err != nil {
+		t.Fatalf("ListModerationCases: %v", err)
+	}
+	if len(cases) != 3 {
+		t.Fatalf("expected the three golden cases, got %d", len(cases))
+	}
+	hash, _, ok, err := store.GetAvatar(ctx, "200", "1002")
+	if err != nil || !ok || hash != "avatar1002" {
+		t.Fatalf("expected the seeded avatar, got %q, %v, %v", hash, ok, err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"iter"
+	"slices"
 	"strings"
 	"time"
 
@@ -189,6 +190,34 @@ func (s *Store) GetCacheEntriesByType(ctx context.Context, cacheType string) ite
 	}
 }
 
+// GetCacheEntriesByGuild streams every non-expired cache entry scoped to a single guild via iter.Seq2.
+// Entries persisted without a guild_id are never yielded.
+func (s *Store) GetCacheEntriesByGuild(ctx context.Context, guildID string) iter.Seq2[system.CacheEntryRecord, error] {
+	return func(yield func(system.CacheEntryRecord, error) bool) {
+		rows, err := s.db.Query(ctx, `SELECT cache_type, cache_key, data, expires_at FROM persistent_cache WHERE guild_id=$1 AND expires_at > $2`, guildID, time.Now().UTC())
+		if err != nil {
+			yield(system.CacheEntryRecord{}, fmt.Errorf("Store.GetCacheEntriesByGuild: %w", err))
+			return
+		}
+		defer rows.Close()
+
+		var entry system.CacheEntryRecord
+		for rows.Next() {
+			entry = system.CacheEntryRecord{GuildID: guildID}
+			if err := rows.Scan(&entry.CacheType, &entry.Key, &entry.Data, &entry.ExpiresAt); err != nil {
+				yield(system.CacheEntryRecord{}, fmt.Errorf("Store.GetCacheEntriesByGuild: %w", err))
+				return
+			}
+			if !yield(entry, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(system.CacheEntryRecord{}, fmt.Errorf("Store.GetCacheEntriesByGuild: %w", err))
+		}
+	}
+}
+
 // CleanupExpiredCacheEntries removes all expired cache entries.
 func (s *Store) CleanupExpiredCacheEntries(ctx context.Context) error {
 	_, err := s.db.Exec(ctx, `DELETE FROM persistent_cache WHERE expires_at <= $1`, time.Now().UTC())
@@ -222,7 +251,219 @@ func (s *Store) GetCacheStatsContext(ctx context.Context) (system.PersistentCach
 	return stats, nil
 }
 
-// PurgeGuildModerationData drops all moderation warnings and resets the case counter.
+// GuildActivity returns the daily activity counters of guildID for the days
+// in [from, to) and its topChannels busiest channels over those days.
+func (s *Store) GuildActivity(ctx context.Context, guildID string, from, to time.Time, topChannels int) (system.GuildActivity, error) {
+	var activity system.GuildActivity
+	guildID = strings.TrimSpace(guildID)
+	if guildID == "" {
+		return activity, nil
+	}
+	from, to = from.UTC(), to.UTC()
+
+	rows, err := s.db.Query(ctx,
+		`SELECT day, SUM(messages), SUM(joins), SUM(leaves) FROM (
+             SELECT day, count AS messages, 0 AS joins, 0 AS leaves FROM daily_message_metrics
+             WHERE guild_id=$1 AND day >= $2::date AND day < $3::date
+             UNION ALL
+             SELECT day, 0, count, 0 FROM daily_member_joins
+             WHERE guild_id=$1 AND day >= $2::date AND day < $3::date
+             UNION ALL
+             SELECT day, 0, 0, count FROM daily_member_leaves
+             WHERE guild_id=$1 AND day >= $2::date AND day < $3::date
+         ) activity
+         GROUP BY day
+         ORDER BY day`,
+		guildID, from, to,
+	)
+	if err != nil {
+		return system.GuildActivity{}, fmt.Errorf("Store.GuildActivity: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var day system.DailyActivity
+		if err := rows.Scan(&day.Day, &day.Messages, &day.Joins, &day.Leaves); err != nil {
+			return system.GuildActivity{}, fmt.Errorf("Store.GuildActivity: %w", err)
+		}
+		day.Day = day.Day.UTC()
+		activity.Days = append(activity.Days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return system.GuildActivity{}, fmt.Errorf("Store.GuildActivity: %w", err)
+	}
+	if topChannels <= 0 {
+		return activity, nil
+	}
+
+	rows, err = s.db.Query(ctx,
+		`SELECT channel_id, SUM(count) FROM daily_message_metrics
+         WHERE guild_id=$1 AND day >= $2::date AND day < $3::date
+         GROUP BY channel_id
+         ORDER BY 2 DESC, channel_id
+         LIMIT $4`,
+		guildID, from, to, topChannels,
+	)
+	if err != nil {
+		return system.GuildActivity{}, fmt.Errorf("Store.GuildActivity: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var channel system.ChannelActivity
+		if err := rows.Scan(&channel.ChannelID, &channel.Messages); err != nil {
+			return system.GuildActivity{}, fmt.Errorf("Store.GuildActivity: %w", err)
+		}
+		activity.TopChannels = append(activity.TopChannels, channel)
+	}
+	if err := rows.Err(); err != nil {
+		return system.GuildActivity{}, fmt.Errorf("Store.GuildActivity: %w", err)
+	}
+	return activity, nil
+}
+
+// GuildActivityTotals sums the activity of guildID over the days in
+// [from, to). Whole weeks and months are read from their rollups; the days
+// around them, and periods whose rollup is missing, from the daily counters.
+func (s *Store) GuildActivityTotals(ctx context.Context, guildID string, from, to time.Time) (system.ActivityTotals, error) {
+	var totals system.ActivityTotals
+	guildID = strings.TrimSpace(guildID)
+	spans := system.PlanActivitySpans(from, to)
+	if guildID == "" || len(spans) == 0 {
+		return totals, nil
+	}
+
+	type rollupKey struct {
+		period string
+		start  time.Time
+	}
+	rollups := make(map[rollupKey]system.ActivityTotals)
+	if slices.ContainsFunc(spans, func(span system.ActivitySpan) bool { return span.Period != "" }) {
+		rows, err := s.db.Query(ctx,
+			`SELECT period, period_start, messages, joins, leaves
+             FROM activity_rollups
+             WHERE guild_id=$1 AND period_start >= $2::date AND period_end <= $3::date`,
+			guildID, spans[0].Start, spans[len(spans)-1].End,
+		)
+		if err != nil {
+			return system.ActivityTotals{}, fmt.Errorf("Store.GuildActivityTotals: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var (
+				key rollupKey
+				sum system.ActivityTotals
+			)
+			if err := rows.Scan(&key.period, &key.start, &sum.Messages, &sum.Joins, &sum.Leaves); err != nil {
+				return system.ActivityTotals{}, fmt.Errorf("Store.GuildActivityTotals: %w", err)
+			}
+			key.start = key.start.UTC()
+			rollups[key] = sum
+		}
+		if err := rows.Err(); err != nil {
+			return system.ActivityTotals{}, fmt.Errorf("Store.GuildActivityTotals: %w", err)
+		}
+		rows.Close()
+	}
+
+	var days []time.Time
+	for _, span := range spans {
+		if sum, ok := rollups[rollupKey{span.Period, span.Start}]; ok {
+			totals.Messages += sum.Messages
+			totals.Joins += sum.Joins
+			totals.Leaves += sum.Leaves
+			continue
+		}
+		for d := span.Start; d.Before(span.End); d = d.AddDate(0, 0, 1) {
+			days = append(days, d)
+		}
+	}
+	if len(days) == 0 {
+		return totals, nil
+	}
+
+	var sum system.ActivityTotals
+	if err := s.db.QueryRow(ctx,
+		`SELECT
+             (SELECT COALESCE(SUM(count), 0) FROM daily_message_metrics WHERE guild_id=$1 AND day = ANY($2::date[])),
+             (SELECT COALESCE(SUM(count), 0) FROM daily_member_joins WHERE guild_id=$1 AND day = ANY($2::date[])),
+             (SELECT COALESCE(SUM(count), 0) FROM daily_member_leaves WHERE guild_id=$1 AND day = ANY($2::date[]))`,
+		guildID, days,
+	).Scan(&sum.Messages, &sum.Joins, &sum.Leaves); err != nil {
+		return system.ActivityTotals{}, fmt.Errorf("Store.GuildActivityTotals: %w", err)
+	}
+	totals.Messages += sum.Messages
+	totals.Joins += sum.Joins
+	totals.Leaves += sum.Leaves
+	return totals, nil
+}
+
+// RefreshActivityRollups computes the rollups of guildID for spans that do
+// not have one yet and reports how many it wrote. Existing rollups are kept;
+// InvalidateActivityRollups drops those a late write makes stale.
+func (s *Store) RefreshActivityRollups(ctx context.Context, guildID string, spans []system.ActivitySpan) (int64, error) {
+	guildID = strings.TrimSpace(guildID)
+	var periods []string
+	var starts, ends []time.Time
+	for _, span := range spans {
+		if span.Period == "" {
+			continue
+		}
+		periods = append(periods, span.Period)
+		starts = append(starts, span.Start.UTC())
+		ends = append(ends, span.End.UTC())
+	}
+	if guildID == "" || len(periods) == 0 {
+		return 0, nil
+	}
+	tag, err := s.db.Exec(ctx,
+		`INSERT INTO activity_rollups (guild_id, period, period_start, period_end, messages, joins, leaves)
+         SELECT $1, p.period, p.period_start, p.period_end,
+                (SELECT COALESCE(SUM(count), 0) FROM daily_message_metrics
+                 WHERE guild_id=$1 AND day >= p.period_start AND day < p.period_end),
+                (SELECT COALESCE(SUM(count), 0) FROM daily_member_joins
+                 WHERE guild_id=$1 AND day >= p.period_start AND day < p.period_end),
+                (SELECT COALESCE(SUM(count), 0) FROM daily_member_leaves
+                 WHERE guild_id=$1 AND day >= p.period_start AND day < p.period_end)
+         FROM unnest($2::text[], $3::date[], $4::date[]) AS p(period, period_start, period_end)
+         WHERE NOT EXISTS (
+             SELECT 1 FROM activity_rollups r
+             WHERE r.guild_id=$1 AND r.period=p.period AND r.period_start=p.period_start
+         )
+         ON CONFLICT (guild_id, period, period_start) DO NOTHING`,
+		guildID, periods, starts, ends,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("Store.RefreshActivityRollups: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// InvalidateActivityRollups drops the rollups of guildID covering day, so
+// the next refresh recomputes them.
+func (s *Store) InvalidateActivityRollups(ctx context.Context, guildID string, day time.Time) error {
+	if _, err := s.db.Exec(ctx,
+		`DELETE FROM activity_rollups
+         WHERE guild_id=$1 AND period_start <= $2::date AND period_end > $2::date`,
+		strings.TrimSpace(guildID), day.UTC(),
+	); err != nil {
+		return fmt.Errorf("Store.InvalidateActivityRollups: %w", err)
+	}
+	return nil
+}
+
+// guildModerationTables are the tables PurgeGuildModerationData clears.
+var guildModerationTables = []string{
+	"moderation_warnings",
+	"moderation_notes",
+	"moderation_cases",
+	"moderation_case_records",
+	"channel_locks",
+	"clean_logs",
+	"member_names_current",
+}
+
+// PurgeGuildModerationData drops all moderation warnings, notes and case
+// records, resets the case counter, and forgets channel locks, /clean logs
+// and the stored member names of guildID.
 func (s *Store) PurgeGuildModerationData(ctx context.Context, guildID string) error {
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
@@ -234,11 +475,10 @@ func (s *Store) PurgeGuildModerationData(ctx context.Context, guildID string) er
 		}
 	}()
 
-	if _, err := tx.Exec(ctx, `DELETE FROM moderation_warnings WHERE guild_id = $1`, guildID); err != nil {
-		return fmt.Errorf("delete moderation_warnings: %w", err)
-	}
-	if _, err := tx.Exec(ctx, `DELETE FROM moderation_cases WHERE guild_id = $1`, guildID); err != nil {
-		return fmt.Errorf("delete moderation_cases: %w", err)
+	for _, table := range guildModerationTables {
+		if _, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE guild_id = $1`, guildID); err != nil {
+			return fmt.Errorf("delete %s: %w", table, err)
+		}
 	}
 
 	if err := tx.Commit(ctx); err != nil {
@@ -286,3 +526,102 @@ func (s *Store) Metadata(ctx context.Context, key string) (time.Time, bool, erro
 func (s *Store) SetMetadata(ctx context.Context, key string, at time.Time) error {
 	return s.setRuntimeTimestamp(ctx, "meta_"+key, at)
 }
+
+// AcquireLease takes or renews the named lease for holder until ttl from now,
+// measured on the database clock. It reports false without error while
+// another holder's lease is still current.
+func (s *Store) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	var current string
+	err := s.db.QueryRow(ctx,
+		`INSERT INTO leader_leases (name, holder, expires_at)
+         VALUES ($1, $2, NOW() + make_interval(secs => $3))
+         ON CONFLICT(name) DO UPDATE SET holder=excluded.holder, expires_at=excluded.expires_at
+         WHERE leader_leases.holder = excluded.holder OR leader_leases.expires_at < NOW()
+         RETURNING holder`,
+		name, holder, ttl.Seconds(),
+	).Scan(&current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("Store.AcquireLease: %w", err)
+	}
+	return current == holder, nil
+}
+
+// ReleaseLease drops the named lease if holder still owns it, letting another
+// instance take over without waiting for expiry.
+func (s *Store) ReleaseLease(ctx context.Context, name, holder string) error {
+	if _, err := s.db.Exec(ctx, `DELETE FROM leader_leases WHERE name=$1 AND holder=$2`, name, holder); err != nil {
+		return fmt.Errorf("Store.ReleaseLease: %w", err)
+	}
+	return nil
+}
+
+// ClaimTaskKey takes the task idempotency key for ttl from now, measured on
+// the database clock. It reports false without error while an earlier claim
+// of the key is current.
+func (s *Store) ClaimTaskKey(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	var claimed string
+	err := s.db.QueryRow(ctx,
+		`INSERT INTO task_idempotency_keys (key, expires_at)
+         VALUES ($1, NOW() + make_interval(secs => $2))
+         ON CONFLICT(key) DO UPDATE SET expires_at=excluded.expires_at
+         WHERE task_idempotency_keys.expires_at < NOW()
+         RETURNING key`,
+		key, ttl.Seconds(),
+	).Scan(&claimed)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("Store.ClaimTaskKey: %w", err)
+	}
+	return true, nil
+}
+
+// ReleaseTaskKey drops the claim of a task idempotency key.
+func (s *Store) ReleaseTaskKey(ctx context.Context, key string) error {
+	if _, err := s.db.Exec(ctx, `DELETE FROM task_idempotency_keys WHERE key=$1`, key); err != nil {
+		return fmt.Errorf("Store.ReleaseTaskKey: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpiredTaskKeys drops task idempotency keys whose claim has expired.
+func (s *Store) PurgeExpiredTaskKeys(ctx context.Context) (int64, error) {
+	tag, err := s.db.Exec(ctx, `DELETE FROM task_idempotency_keys WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("Store.PurgeExpiredTaskKeys: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// CountTaskKeysByKind counts the current task idempotency claims by kind,
+// the part of each key before its first colon.
+func (s *Store) CountTaskKeysByKind(ctx context.Context) (map[string]int64, error) {
+	rows, err := s.db.Query(ctx,
+		`SELECT split_part(key, ':', 1), COUNT(*)
+         FROM task_idempotency_keys
+         WHERE expires_at >= NOW()
+         GROUP BY 1`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Store.CountTaskKeysByKind: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]int64)
+	for rows.Next() {
+		var kind string
+		var n int64
+		if err := rows.Scan(&kind, &n); err != nil {
+			return nil, fmt.Errorf("Store.CountTaskKeysByKind: %w", err)
+		}
+		out[kind] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Store.CountTaskKeysByKind: %w", err)
+	}
+	return out, nil
+}
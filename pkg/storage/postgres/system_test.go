@@ -168,6 +168,85 @@ func TestStore_System_RuntimeMeta(t *testing.T) {
 	}
 }
 
+func TestStore_System_Lease(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+	ctx := context.Background()
+
+	mock.ExpectQuery(`INSERT INTO leader_leases`).
+		WithArgs("runtime", "node-a", float64(15)).
+		WillReturnRows(pgxmock.NewRows([]string{"holder"}).AddRow("node-a"))
+	if ok, err := store.AcquireLease(ctx, "runtime", "node-a", 15*time.Second); err != nil || !ok {
+		t.Fatalf("AcquireLease as holder: ok=%v err=%v", ok, err)
+	}
+
+	// A current lease owned by someone else leaves the upsert without a row.
+	mock.ExpectQuery(`INSERT INTO leader_leases`).
+		WithArgs("runtime", "node-b", float64(15)).
+		WillReturnError(pgx.ErrNoRows)
+	if ok, err := store.AcquireLease(ctx, "runtime", "node-b", 15*time.Second); err != nil || ok {
+		t.Fatalf("AcquireLease while held: ok=%v err=%v", ok, err)
+	}
+
+	mock.ExpectExec(`DELETE FROM leader_leases`).
+		WithArgs("runtime", "node-a").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	if err := store.ReleaseLease(ctx, "runtime", "node-a"); err != nil {
+		t.Fatalf("ReleaseLease: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_System_TaskKeys(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+	ctx := context.Background()
+
+	mock.ExpectQuery(`INSERT INTO task_idempotency_keys`).
+		WithArgs("backfill:1:2024-01-02", float64(3600)).
+		WillReturnRows(pgxmock.NewRows([]string{"key"}).AddRow("backfill:1:2024-01-02"))
+	if ok, err := store.ClaimTaskKey(ctx, "backfill:1:2024-01-02", time.Hour); err != nil || !ok {
+		t.Fatalf("ClaimTaskKey on a free key: ok=%v err=%v", ok, err)
+	}
+
+	// A current claim leaves the upsert without a row.
+	mock.ExpectQuery(`INSERT INTO task_idempotency_keys`).
+		WithArgs("backfill:1:2024-01-02", float64(3600)).
+		WillReturnError(pgx.ErrNoRows)
+	if ok, err := store.ClaimTaskKey(ctx, "backfill:1:2024-01-02", time.Hour); err != nil || ok {
+		t.Fatalf("ClaimTaskKey while claimed: ok=%v err=%v", ok, err)
+	}
+
+	mock.ExpectExec(`DELETE FROM task_idempotency_keys WHERE key`).
+		WithArgs("backfill:1:2024-01-02").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	if err := store.ReleaseTaskKey(ctx, "backfill:1:2024-01-02"); err != nil {
+		t.Fatalf("ReleaseTaskKey: %v", err)
+	}
+
+	mock.ExpectExec(`DELETE FROM task_idempotency_keys WHERE expires_at`).
+		WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	if n, err := store.PurgeExpiredTaskKeys(ctx); err != nil || n != 3 {
+		t.Fatalf("PurgeExpiredTaskKeys: n=%d err=%v", n, err)
+	}
+
+	mock.ExpectQuery(`SELECT split_part\(key, ':', 1\), COUNT\(\*\)`).
+		WillReturnRows(pgxmock.NewRows([]string{"kind", "count"}).AddRow("backfill", int64(2)).AddRow("purge", int64(1)))
+	kinds, err := store.CountTaskKeysByKind(ctx)
+	if err != nil || kinds["backfill"] != 2 || kinds["purge"] != 1 {
+		t.Fatalf("CountTaskKeysByKind: kinds=%v err=%v", kinds, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestStore_System_UpsertCacheEntriesContext(t *testing.T) {
 	t.Parallel()
 	t.Run("empty entries", func(t *testing.T) {
@@ -290,6 +369,34 @@ func TestStore_System_GetCacheEntriesByType(t *testing.T) {
 	})
 }
 
+func TestStore_System_GetCacheEntriesByGuild(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+
+	now := time.Now()
+	rows := pgxmock.NewRows([]string{"cache_type", "cache_key", "data", "expires_at"}).
+		AddRow("guild", "guild:g1", "{}", now).
+		AddRow("member", "member:g1:u1", "{}", now)
+
+	mock.ExpectQuery(`SELECT cache_type, cache_key, data, expires_at FROM persistent_cache WHERE guild_id=`).
+		WithArgs("g1", pgxmock.AnyArg()).
+		WillReturnRows(rows)
+
+	var results []system.CacheEntryRecord
+	for entry, err := range store.GetCacheEntriesByGuild(context.Background(), "g1") {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		results = append(results, entry)
+	}
+
+	if len(results) != 2 || results[0].CacheType != "guild" || results[1].Key != "member:g1:u1" || results[1].GuildID != "g1" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
 func TestStore_System_CleanupExpiredCacheEntries(t *testing.T) {
 	t.Parallel()
 	mock, _ := pgxmock.NewPool()
@@ -356,9 +463,17 @@ func TestStore_System_PurgeGuildModerationData(t *testing.T) {
 		mock.ExpectExec(`DELETE FROM moderation_warnings WHERE guild_id =`).
 			WithArgs("g1").
 			WillReturnResult(pgxmock.NewResult("DELETE", 2))
+		mock.ExpectExec(`DELETE FROM moderation_notes WHERE guild_id =`).
+			WithArgs("g1").
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
 		mock.ExpectExec(`DELETE FROM moderation_cases WHERE guild_id =`).
 			WithArgs("g1").
 			WillReturnResult(pgxmock.NewResult("DELETE", 4))
+		for _, table := range []string{"moderation_case_records", "channel_locks", "clean_logs", "member_names_current"} {
+			mock.ExpectExec(`DELETE FROM ` + table + ` WHERE guild_id =`).
+				WithArgs("g1").
+				WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		}
 		mock.ExpectCommit()
 		mock.ExpectRollback()
 
@@ -411,3 +526,145 @@ func TestStore_System_IncrementDailyMemberEvents(t *testing.T) {
 		t.Errorf("IncrementDailyMemberLeaveContext: %v", err)
 	}
 }
+
+func TestStore_System_GuildActivity(t *testing.T) {
+	t.Parallel()
+	from := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+
+	t.Run("days and top channels", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`SELECT day, SUM\(messages\), SUM\(joins\), SUM\(leaves\) FROM`).
+			WithArgs("g1", from, to).
+			WillReturnRows(pgxmock.NewRows([]string{"day", "messages", "joins", "leaves"}).
+				AddRow(from, int64(120), int64(3), int64(1)).
+				AddRow(from.AddDate(0, 0, 1), int64(80), int64(0), int64(2)))
+		mock.ExpectQuery(`SELECT channel_id, SUM\(count\) FROM daily_message_metrics`).
+			WithArgs("g1", from, to, 5).
+			WillReturnRows(pgxmock.NewRows([]string{"channel_id", "sum"}).
+				AddRow("c1", int64(150)).
+				AddRow("c2", int64(50)))
+
+		activity, err := store.GuildActivity(context.Background(), "g1", from, to, 5)
+		if err != nil {
+			t.Fatalf("GuildActivity: %v", err)
+		}
+		if len(activity.Days) != 2 || activity.Days[0].Messages != 120 || activity.Days[1].Leaves != 2 {
+			t.Fatalf("unexpected days: %+v", activity.Days)
+		}
+		if len(activity.TopChannels) != 2 || activity.TopChannels[0].ChannelID != "c1" {
+			t.Fatalf("unexpected top channels: %+v", activity.TopChannels)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`SELECT day`).WillReturnError(errors.New("db error"))
+
+		if _, err := store.GuildActivity(context.Background(), "g1", from, to, 5); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestStore_System_GuildActivityTotals(t *testing.T) {
+	t.Parallel()
+	// June 2026 starts on a Monday, so the range reads one month and the
+	// four days around it.
+	from := time.Date(2026, 5, 30, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 7, 3, 0, 0, 0, 0, time.UTC)
+	june := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	rollupColumns := []string{"period", "period_start", "messages", "joins", "leaves"}
+	totalColumns := []string{"messages", "joins", "leaves"}
+
+	t.Run("rollups and edge days", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`SELECT period, period_start, messages, joins, leaves\s+FROM activity_rollups`).
+			WithArgs("g1", from, to).
+			WillReturnRows(pgxmock.NewRows(rollupColumns).AddRow(system.RollupMonth, june, int64(3000), int64(40), int64(10)))
+		edges := []time.Time{from, from.AddDate(0, 0, 1), june.AddDate(0, 1, 0), june.AddDate(0, 1, 1)}
+		mock.ExpectQuery(`daily_message_metrics WHERE guild_id=\$1 AND day = ANY`).
+			WithArgs("g1", edges).
+			WillReturnRows(pgxmock.NewRows(totalColumns).AddRow(int64(200), int64(5), int64(1)))
+
+		totals, err := store.GuildActivityTotals(context.Background(), "g1", from, to)
+		if err != nil {
+			t.Fatalf("GuildActivityTotals: %v", err)
+		}
+		if totals != (system.ActivityTotals{Messages: 3200, Joins: 45, Leaves: 11}) {
+			t.Fatalf("unexpected totals %+v", totals)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("missing rollup falls back to the daily counters", func(t *testing.T) {
+		mock, _ := pgxmock.NewPool()
+		defer mock.Close()
+		store, _ := NewStore(mock, nil)
+
+		mock.ExpectQuery(`FROM activity_rollups`).
+			WithArgs("g1", from, to).
+			WillReturnRows(pgxmock.NewRows(rollupColumns))
+		var days []time.Time
+		for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+			days = append(days, d)
+		}
+		mock.ExpectQuery(`day = ANY`).
+			WithArgs("g1", days).
+			WillReturnRows(pgxmock.NewRows(totalColumns).AddRow(int64(3200), int64(45), int64(11)))
+
+		totals, err := store.GuildActivityTotals(context.Background(), "g1", from, to)
+		if err != nil || totals.Messages != 3200 {
+			t.Fatalf("GuildActivityTotals: got %+v, err=%v", totals, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestStore_System_ActivityRollups(t *testing.T) {
+	t.Parallel()
+	mock, _ := pgxmock.NewPool()
+	defer mock.Close()
+	store, _ := NewStore(mock, nil)
+
+	week := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	month := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`INSERT INTO activity_rollups`).
+		WithArgs("g1", []string{system.RollupMonth, system.RollupWeek},
+			[]time.Time{month, week}, []time.Time{month.AddDate(0, 1, 0), week.AddDate(0, 0, 7)}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec(`DELETE FROM activity_rollups`).
+		WithArgs("g1", week.AddDate(0, 0, 2)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 2))
+
+	written, err := store.RefreshActivityRollups(context.Background(), "g1", []system.ActivitySpan{
+		{Period: system.RollupMonth, Start: month, End: month.AddDate(0, 1, 0)},
+		{Start: week.AddDate(0, 0, -1), End: week},
+		{Period: system.RollupWeek, Start: week, End: week.AddDate(0, 0, 7)},
+	})
+	if err != nil || written != 1 {
+		t.Fatalf("RefreshActivityRollups: written=%d, err=%v", written, err)
+	}
+	if err := store.InvalidateActivityRollups(context.Background(), "g1", week.AddDate(0, 0, 2)); err != nil {
+		t.Fatalf("InvalidateActivityRollups: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,40 @@
+//go:build !windows
+
+package sys
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// notifySocketEnv is the variable systemd sets for Type=notify units.
+const notifySocketEnv = "NOTIFY_SOCKET"
+
+// SdNotify sends state (e.g. "READY=1") to the service manager socket named by
+// NOTIFY_SOCKET. It reports false without error when the process is not
+// supervised by a notify-aware manager, so callers may invoke it unconditionally.
+func SdNotify(state string) (bool, error) {
+	socketPath := os.Getenv(notifySocketEnv)
+	if socketPath == "" {
+		return false, nil
+	}
+
+	// A leading '@' selects the Linux abstract namespace; net maps it for us.
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		return false, fmt.Errorf("SdNotify dial: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, fmt.Errorf("SdNotify write: %w", err)
+	}
+	return true, nil
+}
+
+// StatsDumpSignals returns the signals that request a runtime statistics dump.
+func StatsDumpSignals() []os.Signal {
+	return []os.Signal{syscall.SIGUSR1}
+}
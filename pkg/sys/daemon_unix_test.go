@@ -0,0 +1,42 @@
+//go:build !windows
+
+package sys
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+func TestSdNotifyWithoutSocketIsNoop(t *testing.T) {
+	t.Setenv(notifySocketEnv, "")
+
+	sent, err := SdNotify("READY=1")
+	if err != nil || sent {
+		t.Fatalf("expected unsupervised notify to be a no-op, got sent=%v err=%v", sent, err)
+	}
+}
+
+func TestSdNotifyWritesState(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets unavailable: %v", err)
+	}
+	defer conn.Close()
+	t.Setenv(notifySocketEnv, socketPath)
+
+	sent, err := SdNotify("READY=1")
+	if err != nil || !sent {
+		t.Fatalf("expected notify to be sent, got sent=%v err=%v", sent, err)
+	}
+
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("read notify datagram: %v", err)
+	}
+	if got := string(buf[:n]); got != "READY=1" {
+		t.Fatalf("unexpected notify payload %q", got)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package sys
+
+import "os"
+
+// SdNotify is a no-op on Windows, which has no systemd notify socket.
+func SdNotify(state string) (bool, error) {
+	return false, nil
+}
+
+// StatsDumpSignals returns nil on Windows, which has no SIGUSR1.
+func StatsDumpSignals() []os.Signal {
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 
 // macOS (darwin) unified filesystem layout for this repo:
 //   - Config: ~/Library/Preferences/<AppName>
+//   - Data:   ~/Library/Application Support/<AppName>
 //   - Cache:  ~/Library/Caches/<AppName>
 //   - Logs:   ~/Library/Logs/<AppName>
 //
@@ -23,6 +24,12 @@ func PlatformConfigDir(appName string) string {
 	return filepath.Join(home, "Library", "Preferences", sanitizeAppNameForPath(appName))
 }
 
+// PlatformDataDir returns the base directory for application data on macOS.
+func PlatformDataDir(appName string) string {
+	home := darwinHomeDir()
+	return filepath.Join(home, "Library", "Application Support", sanitizeAppNameForPath(appName))
+}
+
 // platformCacheDir returns the base directory for cache files on macOS.
 func PlatformCacheDir(appName string) string {
 	home := darwinHomeDir()
@@ -42,7 +49,7 @@ func darwinHomeDir() string {
 	}
 
 	// Best-effort fallbacks.
-	if h := strings.TrimSpace(os.os.Getenv("HOME")); h != "" {
+	if h := strings.TrimSpace(os.Getenv("HOME")); h != "" {
 		return h
 	}
 	if wd, err := os.Getwd(); err == nil && strings.TrimSpace(wd) != "" {
@@ -8,33 +8,58 @@ import (
 	"strings"
 )
 
-// Unix/Linux unified filesystem layout:
-//   - Config: ~/.config/<AppName>
-//   - Cache:  ~/.cache/<AppName>
-//   - Logs:   ~/.log/<AppName>
+// Unix/Linux unified filesystem layout, following the XDG base directory
+// variables when they are set:
+//   - Config: $XDG_CONFIG_HOME/<AppName>, default ~/.config/<AppName>
+//   - Data:   $XDG_DATA_HOME/<AppName>, default ~/.local/share/<AppName>
+//   - Cache:  $XDG_CACHE_HOME/<AppName>, default ~/.cache/<AppName>
+//   - Logs:   $XDG_STATE_HOME/<AppName>/logs, default ~/.log/<AppName>
 //
 // These helpers return base directories only; callers should create directories
 // (e.g., via os.MkdirAll) as needed.
 
 func PlatformConfigDir(appName string) string {
-	home := platformHomeDir()
-	return filepath.Join(home, ".config", sanitizeAppNameForPath(appName))
+	return filepath.Join(xdgBaseDir("XDG_CONFIG_HOME", ".config"), sanitizeAppNameForPath(appName))
+}
+
+func PlatformDataDir(appName string) string {
+	return filepath.Join(xdgBaseDir("XDG_DATA_HOME", filepath.Join(".local", "share")), sanitizeAppNameForPath(appName))
 }
 
 func PlatformCacheDir(appName string) string {
-	home := platformHomeDir()
-	return filepath.Join(home, ".cache", sanitizeAppNameForPath(appName))
+	return filepath.Join(xdgBaseDir("XDG_CACHE_HOME", ".cache"), sanitizeAppNameForPath(appName))
 }
 
 func PlatformLogDir(appName string) string {
-	home := platformHomeDir()
-	return filepath.Join(home, ".log", sanitizeAppNameForPath(appName))
+	if state := xdgEnvDir("XDG_STATE_HOME"); state != "" {
+		return filepath.Join(state, sanitizeAppNameForPath(appName), "logs")
+	}
+	return filepath.Join(platformHomeDir(), ".log", sanitizeAppNameForPath(appName))
+}
+
+// xdgBaseDir returns the directory named by the XDG variable env, or
+// homeRelative under the user's home directory when it is unset.
+func xdgBaseDir(env, homeRelative string) string {
+	if dir := xdgEnvDir(env); dir != "" {
+		return dir
+	}
+	return filepath.Join(platformHomeDir(), homeRelative)
+}
+
+// xdgEnvDir reads an XDG base directory variable. The specification requires
+// absolute paths, so relative values are ignored.
+func xdgEnvDir(env string) string {
+	dir := strings.TrimSpace(os.Getenv(env))
+	if dir == "" || !filepath.IsAbs(dir) {
+		return ""
+	}
+	return dir
 }
 
 // platformHomeDir resolves the user's home directory in a robust way across
 // Unix-like environments.
 func platformHomeDir() string {
-	if h := strings.TrimSpace(os.os.Getenv("HOME")); h != "" {
+	if h := strings.TrimSpace(os.Getenv("HOME")); h != "" {
 		return h
 	}
 	if h, err := os.UserHomeDir(); err == nil && strings.TrimSpace(h) != "" {
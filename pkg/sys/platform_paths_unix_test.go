@@ -0,0 +1,53 @@
+//go:build !windows && !darwin
+
+package sys
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPlatformPathsUnixDefaults(t *testing.T) {
+	t.Setenv("HOME", "/home/alice")
+	for _, env := range []string{"XDG_CONFIG_HOME", "XDG_DATA_HOME", "XDG_CACHE_HOME", "XDG_STATE_HOME"} {
+		t.Setenv(env, "")
+	}
+
+	cases := map[string]struct{ got, want string }{
+		"config": {PlatformConfigDir("bot"), "/home/alice/.config/bot"},
+		"data":   {PlatformDataDir("bot"), "/home/alice/.local/share/bot"},
+		"cache":  {PlatformCacheDir("bot"), "/home/alice/.cache/bot"},
+		"logs":   {PlatformLogDir("bot"), "/home/alice/.log/bot"},
+	}
+	for name, tc := range cases {
+		if tc.got != filepath.FromSlash(tc.want) {
+			t.Errorf("%s dir = %q, want %q", name, tc.got, tc.want)
+		}
+	}
+}
+
+func TestPlatformPathsUnixHonorXDG(t *testing.T) {
+	t.Setenv("HOME", "/home/alice")
+	t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	t.Setenv("XDG_DATA_HOME", "/xdg/data")
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+	t.Setenv("XDG_STATE_HOME", "/xdg/state")
+
+	cases := map[string]struct{ got, want string }{
+		"config": {PlatformConfigDir("bot"), "/xdg/config/bot"},
+		"data":   {PlatformDataDir("bot"), "/xdg/data/bot"},
+		"cache":  {PlatformCacheDir("bot"), "/xdg/cache/bot"},
+		"logs":   {PlatformLogDir("bot"), "/xdg/state/bot/logs"},
+	}
+	for name, tc := range cases {
+		if tc.got != tc.want {
+			t.Errorf("%s dir = %q, want %q", name, tc.got, tc.want)
+		}
+	}
+
+	// Relative values are invalid per the specification and fall back.
+	t.Setenv("XDG_CONFIG_HOME", "relative/config")
+	if got := PlatformConfigDir("bot"); got != "/home/alice/.config/bot" {
+		t.Errorf("relative XDG_CONFIG_HOME: config dir = %q", got)
+	}
+}
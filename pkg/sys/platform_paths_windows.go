@@ -10,6 +10,7 @@ import (
 
 // Windows unified filesystem layout (per repo requirements):
 //   - Config base: %APPDATA%/<AppName>
+//   - Data base:   %APPDATA%/<AppName>/Data
 //   - Cache base:  %APPDATA%/<AppName>/Cache
 //   - Logs base:   %APPDATA%/<AppName>/Logs
 //
@@ -20,6 +21,10 @@ func PlatformConfigDir(appName string) string {
 	return filepath.Join(base, sanitizeAppNameForPath(appName))
 }
 
+func PlatformDataDir(appName string) string {
+	return filepath.Join(PlatformConfigDir(appName), "Data")
+}
+
 func PlatformCacheDir(appName string) string {
 	return filepath.Join(PlatformConfigDir(appName), "Cache")
 }
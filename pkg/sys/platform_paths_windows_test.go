@@ -16,6 +16,11 @@ func TestPlatformPathsWindows(t *testing.T) {
 		t.Fatalf("unexpected config dir: %q", cfg)
 	}
 
+	expectedData := filepath.Join(expectedCfg, "Data")
+	if data := PlatformDataDir("Alice:Bot "); data != expectedData {
+		t.Fatalf("unexpected data dir: %q", data)
+	}
+
 	expectedCache := filepath.Join(expectedCfg, "Cache")
 	if cache := PlatformCacheDir("Alice:Bot "); cache != expectedCache {
 		t.Fatalf("unexpected cache dir: %q", cache)
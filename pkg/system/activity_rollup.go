@@ -0,0 +1,101 @@
+package system
+
+import "time"
+
+// Rollup periods. Weeks start on Monday and months on the first, in UTC.
+const (
+	RollupWeek  = "week"
+	RollupMonth = "month"
+)
+
+// ActivitySpan is a stretch of days [Start, End) that is read as a whole:
+// from the rollup of a week or month, or, with an empty Period, from the
+// daily counters.
+type ActivitySpan struct {
+	Period string
+	Start  time.Time
+	End    time.Time
+}
+
+// PlanActivitySpans splits the days in [from, to) into the months and weeks
+// that fit whole, preferring months, and runs of the days left at the edges.
+// Reading a month of activity this way takes a few rollups and a handful of
+// days instead of every daily row.
+func PlanActivitySpans(from, to time.Time) []ActivitySpan {
+	from, to = utcDay(from), utcDay(to)
+	var spans []ActivitySpan
+	for d := from; d.Before(to); {
+		if d.Day() == 1 {
+			if end := d.AddDate(0, 1, 0); !end.After(to) {
+				spans = append(spans, ActivitySpan{Period: RollupMonth, Start: d, End: end})
+				d = end
+				continue
+			}
+		}
+		if d.Weekday() == time.Monday {
+			if end := d.AddDate(0, 0, 7); !end.After(to) {
+				spans = append(spans, ActivitySpan{Period: RollupWeek, Start: d, End: end})
+				d = end
+				continue
+			}
+		}
+		next := d.AddDate(0, 0, 1)
+		if n := len(spans); n > 0 && spans[n-1].Period == "" && spans[n-1].End.Equal(d) {
+			spans[n-1].End = next
+		} else {
+			spans = append(spans, ActivitySpan{Start: d, End: next})
+		}
+		d = next
+	}
+	return spans
+}
+
+// CompleteRollups lists the weeks and months that start on or after since
+// and ended by now, oldest first. Only those can be rolled up; the current
+// ones still change.
+func CompleteRollups(since, now time.Time) []ActivitySpan {
+	since, today := utcDay(since), utcDay(now)
+	var spans []ActivitySpan
+	for d := time.Date(since.Year(), since.Month(), 1, 0, 0, 0, 0, time.UTC); ; d = d.AddDate(0, 1, 0) {
+		end := d.AddDate(0, 1, 0)
+		if end.After(today) {
+			break
+		}
+		if !d.Before(since) {
+			spans = append(spans, ActivitySpan{Period: RollupMonth, Start: d, End: end})
+		}
+	}
+	for d := weekStart(since); ; d = d.AddDate(0, 0, 7) {
+		end := d.AddDate(0, 0, 7)
+		if end.After(today) {
+			break
+		}
+		if !d.Before(since) {
+			spans = append(spans, ActivitySpan{Period: RollupWeek, Start: d, End: end})
+		}
+	}
+	return spans
+}
+
+// RollupMayCover reports whether a rollup may already hold day at now. Days
+// of the current week or month never do, so writes to them need no
+// invalidation; writes to earlier days, as backfills make, do.
+func RollupMayCover(day, now time.Time) bool {
+	today := utcDay(now)
+	earliest := weekStart(today)
+	if month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC); month.Before(earliest) {
+		earliest = month
+	}
+	return utcDay(day).Before(earliest)
+}
+
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// weekStart returns the Monday starting the week of day.
+func weekStart(day time.Time) time.Time {
+	day = utcDay(day)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
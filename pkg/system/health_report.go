@@ -0,0 +1,109 @@
+package system
+
+import "time"
+
+// DailyActivity is one day of a guild's activity counters.
+type DailyActivity struct {
+	Day      time.Time `json:"day"`
+	Messages int64     `json:"messages"`
+	Joins    int64     `json:"joins"`
+	Leaves   int64     `json:"leaves"`
+}
+
+// ChannelActivity counts the messages sent in one channel.
+type ChannelActivity struct {
+	ChannelID string `json:"channel_id"`
+	Messages  int64  `json:"messages"`
+}
+
+// GuildActivity is a guild's activity over a period: one entry per day with
+// any activity, and its busiest channels, busiest first.
+type GuildActivity struct {
+	Days        []DailyActivity   `json:"days"`
+	TopChannels []ChannelActivity `json:"top_channels"`
+}
+
+// ActivityTotals sums daily activity.
+type ActivityTotals struct {
+	Messages int64 `json:"messages"`
+	Joins    int64 `json:"joins"`
+	Leaves   int64 `json:"leaves"`
+}
+
+// NetGrowth returns joins minus leaves.
+func (t ActivityTotals) NetGrowth() int64 { return t.Joins - t.Leaves }
+
+// BotPerformance measures the bot's Discord API use over a period.
+type BotPerformance struct {
+	APICalls  int64 `json:"api_calls"`
+	CacheHits int64 `json:"cache_hits"`
+}
+
+// CacheHitRate returns the share of lookups served from cache, between 0
+// and 1. It is zero when nothing was looked up.
+func (p BotPerformance) CacheHitRate() float64 {
+	lookups := p.APICalls + p.CacheHits
+	if lookups == 0 {
+		return 0
+	}
+	return float64(p.CacheHits) / float64(lookups)
+}
+
+// HealthReport is a guild's weekly digest for its staff.
+type HealthReport struct {
+	GuildID string    `json:"guild_id"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	// Week and PreviousWeek total the activity of [From, To) and of the
+	// week before it.
+	Week         ActivityTotals    `json:"week"`
+	PreviousWeek ActivityTotals    `json:"previous_week"`
+	Days         []DailyActivity   `json:"days"`
+	TopChannels  []ChannelActivity `json:"top_channels"`
+	// Members is the member count at To, read from the stored membership.
+	// NewMembers counts the members who joined in the week and
+	// NewMembersRetained those of them still present.
+	Members            int64 `json:"members"`
+	NewMembers         int64 `json:"new_members"`
+	NewMembersRetained int64 `json:"new_members_retained"`
+	// ModerationActions counts the cases and warnings recorded in the week.
+	ModerationActions int `json:"moderation_actions"`
+	// Performance covers the whole bot, not just this guild.
+	Performance BotPerformance `json:"performance"`
+}
+
+// NewHealthReport splits activity, which spans the week before from as well,
+// into the report week and the one before it.
+func NewHealthReport(guildID string, from, to time.Time, activity GuildActivity) HealthReport {
+	report := HealthReport{GuildID: guildID, From: from, To: to, TopChannels: activity.TopChannels}
+	for _, day := range activity.Days {
+		totals := &report.PreviousWeek
+		if !day.Day.Before(from) {
+			totals = &report.Week
+			report.Days = append(report.Days, day)
+		}
+		totals.Messages += day.Messages
+		totals.Joins += day.Joins
+		totals.Leaves += day.Leaves
+	}
+	return report
+}
+
+// MessageTrend returns the change in messages from the previous week, as a
+// fraction. ok is false when the previous week had no messages to compare
+// against.
+func (r HealthReport) MessageTrend() (change float64, ok bool) {
+	if r.PreviousWeek.Messages == 0 {
+		return 0, false
+	}
+	return float64(r.Week.Messages-r.PreviousWeek.Messages) / float64(r.PreviousWeek.Messages), true
+}
+
+// Retention returns the share of the week's new members still present. ok
+// is false when nobody joined.
+func (r HealthReport) Retention() (rate float64, ok bool) {
+	if r.NewMembers == 0 {
+		return 0, false
+	}
+	return float64(r.NewMembersRetained) / float64(r.NewMembers), true
+}
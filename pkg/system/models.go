@@ -20,3 +20,28 @@ type PersistentCacheStats struct {
 	Total  int            `json:"total"`
 	ByType map[string]int `json:"by_type,omitempty"`
 }
+
+// CommandAuditRecord is one execution of a privileged slash command.
+type CommandAuditRecord struct {
+	At        time.Time
+	GuildID   string
+	ChannelID string
+	UserID    string
+	// Command is the full invoked path, e.g. "admin token rotate".
+	Command string
+	// Options holds the invocation options as a JSON object.
+	Options  string
+	Outcome  string
+	Error    string
+	Duration time.Duration
+}
+
+// CommandAuditFilter narrows a command audit lookup. Zero fields do not
+// filter; records are returned newest first.
+type CommandAuditFilter struct {
+	GuildID string
+	UserID  string
+	Command string
+	Since   time.Time
+	Limit   int
+}
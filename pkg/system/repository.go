@@ -16,6 +16,7 @@ type Repository interface {
 	UpsertCacheEntriesContext(ctx context.Context, entries []CacheEntryRecord) error
 	GetCacheEntry(ctx context.Context, key string) (cacheType, data string, expiresAt time.Time, ok bool, err error)
 	GetCacheEntriesByType(ctx context.Context, cacheType string) iter.Seq2[CacheEntry, error]
+	GetCacheEntriesByGuild(ctx context.Context, guildID string) iter.Seq2[CacheEntryRecord, error]
 	CleanupExpiredCacheEntries(ctx context.Context) error
 	GetCacheStatsContext(ctx context.Context) (PersistentCacheStats, error)
 	PurgeGuildModerationData(ctx context.Context, guildID string) error
@@ -26,3 +27,22 @@ type Repository interface {
 	Metadata(ctx context.Context, key string) (time.Time, bool, error)
 	SetMetadata(ctx context.Context, key string, at time.Time) error
 }
+
+// CommandAuditRepository persists the privileged command audit trail.
+type CommandAuditRepository interface {
+	AppendCommandAudit(ctx context.Context, rec CommandAuditRecord) error
+	ListCommandAudit(ctx context.Context, filter CommandAuditFilter) ([]CommandAuditRecord, error)
+}
+
+// ActivityRepository reads the daily activity counters.
+type ActivityRepository interface {
+	GuildActivity(ctx context.Context, guildID string, from, to time.Time, topChannels int) (GuildActivity, error)
+	GuildActivityTotals(ctx context.Context, guildID string, from, to time.Time) (ActivityTotals, error)
+}
+
+// ActivityRollupRepository maintains the weekly and monthly totals
+// GuildActivityTotals reads instead of the daily counters.
+type ActivityRollupRepository interface {
+	RefreshActivityRollups(ctx context.Context, guildID string, spans []ActivitySpan) (int64, error)
+	InvalidateActivityRollups(ctx context.Context, guildID string, day time.Time) error
+}
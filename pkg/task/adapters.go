@@ -142,6 +142,7 @@ func (a *NotificationAdapters) EnqueueMemberJoin(channelID discord.ChannelID, me
 		},
 		Options: TaskOptions{
 			GroupKey:       member.GuildID.String(),
+			GuildID:        member.GuildID.String(),
 			IdempotencyKey: fmt.Sprintf("join:%s:%s", member.GuildID, member.User.ID),
 			IdempotencyTTL: 10 * time.Second,
 			MaxAttempts:    3,
@@ -166,6 +167,7 @@ func (a *NotificationAdapters) EnqueueMemberLeave(channelID discord.ChannelID, m
 		},
 		Options: TaskOptions{
 			GroupKey:       member.GuildID.String(),
+			GuildID:        member.GuildID.String(),
 			IdempotencyKey: fmt.Sprintf("leave:%s:%s", member.GuildID, member.User.ID),
 			IdempotencyTTL: 10 * time.Second,
 			MaxAttempts:    3,
@@ -193,6 +195,7 @@ func (a *NotificationAdapters) EnqueueMessageEdit(channelID discord.ChannelID, o
 		},
 		Options: TaskOptions{
 			GroupKey:       group.String(),
+			GuildID:        group.String(),
 			IdempotencyKey: fmt.Sprintf("edit:%s:%s", group, original.ID),
 			IdempotencyTTL: 10 * time.Second,
 			MaxAttempts:    3,
@@ -216,11 +219,14 @@ func (a *NotificationAdapters) EnqueueMessageDelete(channelID discord.ChannelID,
 		},
 		Options: TaskOptions{
 			GroupKey:       deleted.GuildID.String(),
+			GuildID:        deleted.GuildID.String(),
 			IdempotencyKey: fmt.Sprintf("delete:%s:%s", deleted.GuildID, deleted.ID),
-			IdempotencyTTL: 10 * time.Second,
+			IdempotencyTTL: 10 * time.Minute,
 			MaxAttempts:    3,
 			InitialBackoff: 1 * time.Second,
 			MaxBackoff:     10 * time.Second,
+			// A message is deleted once; the key holds across restarts.
+			DurableIdempotency: true,
 		},
 	})
 }
@@ -237,6 +243,7 @@ func (a *NotificationAdapters) EnqueueProcessAvatarChange(guildID, userID, usern
 		},
 		Options: TaskOptions{
 			GroupKey:       guildID + ":" + userID,
+			GuildID:        guildID,
 			IdempotencyKey: fmt.Sprintf("avatar:%s:%s:%s", guildID, userID, newAvatar),
 			IdempotencyTTL: 60 * time.Second,
 			MaxAttempts:    3,
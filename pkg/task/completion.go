@@ -0,0 +1,94 @@
+//go:build !legacy
+// +build !legacy
+
+package task
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Requester identifies who dispatched a task and where from, so a completion
+// callback can report back to them. Any field may be empty.
+type Requester struct {
+	GuildID   string
+	ChannelID string
+	UserID    string
+
+	// ApplicationID and InteractionToken allow following up on the
+	// interaction that started the task while its token is valid.
+	ApplicationID    string
+	InteractionToken string
+}
+
+// Completion describes how a task with a completion callback ended.
+type Completion struct {
+	Type      string
+	Requester Requester
+
+	// Summary is what the handler reported through SetSummary, if anything.
+	Summary string
+
+	// Err is nil when the task succeeded, or the error of its last attempt.
+	Err      error
+	Attempts int
+
+	// Duration runs from dispatch to completion, retries included.
+	Duration time.Duration
+}
+
+// CompletionFunc is called once when a task succeeds or is dropped after its
+// last attempt. It runs on the worker of the task's group, so it should not
+// block for long.
+type CompletionFunc func(ctx context.Context, c Completion)
+
+type summaryKey struct{}
+
+type summaryHolder struct {
+	mu      sync.Mutex
+	summary string
+}
+
+// SetSummary records what the running task did for its completion callback.
+// Later calls replace the summary; it is kept across retries. It does nothing
+// for tasks without a callback.
+func SetSummary(ctx context.Context, summary string) {
+	if h, ok := ctx.Value(summaryKey{}).(*summaryHolder); ok {
+		h.mu.Lock()
+		h.summary = summary
+		h.mu.Unlock()
+	}
+}
+
+// complete reports the end of et to its completion callback, if it has one.
+func (tr *TaskRouter) complete(et *enqueuedTask, err error) {
+	if et.task.OnComplete == nil {
+		return
+	}
+	c := Completion{
+		Type:      et.task.Type,
+		Requester: et.task.Requester,
+		Err:       err,
+		Attempts:  et.attempt,
+		Duration:  tr.cfg.Clock.Now().Sub(et.dispatchedAt),
+	}
+	if et.summary != nil {
+		et.summary.mu.Lock()
+		c.Summary = et.summary.summary
+		et.summary.mu.Unlock()
+	}
+	ctx := tr.ctx
+	if ctx == nil || ctx.Err() != nil {
+		// The requester should still hear about a task the shutdown failed.
+		ctx = context.Background()
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				tr.cfg.Logger.Error("Task completion callback panic recovered", "type", et.task.Type, "panic", r)
+			}
+		}()
+		et.task.OnComplete(ctx, c)
+	}()
+}
@@ -0,0 +1,73 @@
+//go:build !legacy
+// +build !legacy
+
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRouter_CompletionCallback(t *testing.T) {
+	t.Parallel()
+
+	cfg := Defaults()
+	cfg.InitialBackoff = time.Millisecond
+	cfg.MaxBackoff = time.Millisecond
+	router := NewRouter(cfg)
+	defer router.Close()
+
+	errBoom := errors.New("boom")
+	router.RegisterHandler("export", func(ctx context.Context, payload any) error {
+		SetSummary(ctx, "exported 3 cases")
+		if payload == "fail" {
+			return errBoom
+		}
+		return nil
+	})
+
+	done := make(chan Completion, 2)
+	requester := Requester{GuildID: "1", ChannelID: "2", UserID: "3"}
+	for _, payload := range []string{"ok", "fail"} {
+		err := router.Dispatch(context.Background(), Task{
+			Type:       "export",
+			Payload:    payload,
+			Options:    TaskOptions{GroupKey: payload, MaxAttempts: 2},
+			Requester:  requester,
+			OnComplete: func(_ context.Context, c Completion) { done <- c },
+		})
+		if err != nil {
+			t.Fatalf("Dispatch(%s): %v", payload, err)
+		}
+	}
+
+	byResult := make(map[bool]Completion)
+	for range 2 {
+		select {
+		case c := <-done:
+			byResult[c.Err == nil] = c
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for completion callbacks")
+		}
+	}
+	if ok := byResult[true]; ok.Summary != "exported 3 cases" || ok.Attempts != 1 || ok.Requester != requester || ok.Type != "export" {
+		t.Fatalf("unexpected success completion %+v", ok)
+	}
+	if failed := byResult[false]; !errors.Is(failed.Err, errBoom) || failed.Attempts != 2 || failed.Summary != "exported 3 cases" {
+		t.Fatalf("unexpected failure completion %+v", failed)
+	}
+
+	select {
+	case c := <-done:
+		t.Fatalf("callback ran more than once per task: %+v", c)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestSetSummary_WithoutCallback(t *testing.T) {
+	t.Parallel()
+	// Handlers may call SetSummary unconditionally.
+	SetSummary(context.Background(), "ignored")
+}
@@ -18,9 +18,22 @@ Handlers returning errors are subjected to an exponential backoff formula combin
 with an underlying container/heap priority queue. Context cancellation from the Close()
 lifecycle propagates synchronously into executing tasks to immediately abort network I/O.
 
+# Fairness
+
+Tasks tagged with a GuildID share workers fairly between guilds: freed slots go
+to the guild running the fewest tasks, no guild runs more than GuildMaxParallel
+at once, and tasks waiting longer than PriorityBoostAge go first.
+
+# Completion
+
+Tasks may carry a Requester and an OnComplete callback. The callback runs once,
+when the task succeeds or is dropped after its last attempt, with any summary the
+handler recorded through SetSummary.
+
 # Invariants
 
 - Tasks with duplicate IdempotencyKey values within the TTL window are rejected synchronously with ErrDuplicateTask.
+- With DurableIdempotency and an IdempotencyStore, that rejection also holds across restarts and processes; an unreachable store falls back to the in-memory check.
 - Panic states within worker bounds are isolated, recovered, and logged without tearing down the routing engine.
 - Handlers MUST NOT spawn detached background routines. All logic must obey the passed context.Context.
 */
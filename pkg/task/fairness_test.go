@@ -0,0 +1,88 @@
+//go:build !legacy
+// +build !legacy
+
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+// queueWaiter starts acquiring a slot for guildID and waits until it is
+// queued. The returned channel is closed once the slot is granted.
+func queueWaiter(t *testing.T, l *ExecutionLimiter, guildID string, guildMax int, queuedAt time.Time, boostAge time.Duration, now time.Time) <-chan struct{} {
+	t.Helper()
+	want := l.Waiting() + 1
+	granted := make(chan struct{})
+	go func() {
+		l.acquire(&slotWaiter{guildID: guildID, guildMax: guildMax, queuedAt: queuedAt, boostAge: boostAge}, now)
+		close(granted)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for l.Waiting() < want {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the task to queue")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return granted
+}
+
+func expectGranted(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s was not granted a slot", what)
+	}
+}
+
+func TestExecutionLimiter_FairAcrossGuilds(t *testing.T) {
+	t.Parallel()
+	l := NewExecutionLimiter(2)
+	t0 := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	// Guild a holds both slots and has more work queued before guild b.
+	l.acquire(&slotWaiter{guildID: "a"}, t0)
+	l.acquire(&slotWaiter{guildID: "a"}, t0)
+	a3 := queueWaiter(t, l, "a", 0, t0, 0, t0)
+	b1 := queueWaiter(t, l, "b", 0, t0.Add(time.Second), 0, t0)
+
+	l.release("a", t0.Add(2*time.Second))
+	expectGranted(t, b1, "the idle guild")
+	select {
+	case <-a3:
+		t.Fatal("the busy guild should wait while the idle one runs")
+	default:
+	}
+	l.release("a", t0.Add(3*time.Second))
+	expectGranted(t, a3, "the busy guild")
+	l.release("a", t0)
+	l.release("b", t0)
+}
+
+func TestExecutionLimiter_GuildCapAndBoost(t *testing.T) {
+	t.Parallel()
+	l := newExecutionLimiter(0)
+	t0 := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	// Without a global cap a guild is still held to its own.
+	l.acquire(&slotWaiter{guildID: "a", guildMax: 1}, t0)
+	a2 := queueWaiter(t, l, "a", 1, t0, 0, t0)
+	l.release("a", t0)
+	expectGranted(t, a2, "the capped guild")
+	l.release("a", t0)
+
+	// A task waiting past its boost age goes ahead of an idler guild.
+	l = NewExecutionLimiter(2)
+	l.acquire(&slotWaiter{guildID: "a"}, t0)
+	l.acquire(&slotWaiter{guildID: "c"}, t0)
+	a2 = queueWaiter(t, l, "a", 0, t0, time.Minute, t0)
+	b1 := queueWaiter(t, l, "b", 0, t0.Add(50*time.Second), time.Minute, t0)
+	l.release("c", t0.Add(61*time.Second))
+	expectGranted(t, a2, "the boosted task")
+	l.release("a", t0.Add(62*time.Second))
+	expectGranted(t, b1, "the remaining task")
+	l.release("a", t0)
+	l.release("b", t0)
+}
@@ -0,0 +1,86 @@
+//go:build !legacy
+// +build !legacy
+
+package task
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// durableKeyTimeout bounds each call to the IdempotencyStore, so a slow
+// database delays dispatch by at most this long.
+const durableKeyTimeout = 5 * time.Second
+
+// IdempotencyStore persists the idempotency keys of tasks dispatched with
+// DurableIdempotency, so duplicates are rejected across restarts and across
+// processes sharing the store. *postgres.Store satisfies it.
+type IdempotencyStore interface {
+	// ClaimTaskKey takes key for ttl. It reports false without error while
+	// an earlier claim of key is current.
+	ClaimTaskKey(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// ReleaseTaskKey drops the claim of key.
+	ReleaseTaskKey(ctx context.Context, key string) error
+	// PurgeExpiredTaskKeys drops claims that have expired.
+	PurgeExpiredTaskKeys(ctx context.Context) (int64, error)
+}
+
+// IdempotencyKey joins parts into a key naming one unit of work, such as
+// IdempotencyKey("backfill", channelID, day).
+func IdempotencyKey(parts ...string) string {
+	return strings.Join(parts, ":")
+}
+
+// claimDurableKey claims the idempotency key of eff in the store when the
+// task asks for it. A store that cannot be reached is logged and skipped:
+// the in-memory reservation still guards this process.
+func (tr *TaskRouter) claimDurableKey(ctx context.Context, eff TaskOptions) error {
+	if tr.cfg.IdempotencyStore == nil || !eff.DurableIdempotency || eff.IdempotencyKey == "" {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, durableKeyTimeout)
+	defer cancel()
+	claimed, err := tr.cfg.IdempotencyStore.ClaimTaskKey(ctx, eff.IdempotencyKey, eff.IdempotencyTTL)
+	if err != nil {
+		tr.cfg.Logger.Warn("Durable idempotency key could not be claimed; relying on memory",
+			"idempotencyKey", eff.IdempotencyKey,
+			"err", err,
+		)
+		return nil
+	}
+	if !claimed {
+		return ErrDuplicateTask
+	}
+	return nil
+}
+
+// releaseDurableKey drops the stored claim of a task that was never queued.
+func (tr *TaskRouter) releaseDurableKey(eff TaskOptions) {
+	if tr.cfg.IdempotencyStore == nil || !eff.DurableIdempotency || eff.IdempotencyKey == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), durableKeyTimeout)
+	defer cancel()
+	if err := tr.cfg.IdempotencyStore.ReleaseTaskKey(ctx, eff.IdempotencyKey); err != nil {
+		tr.cfg.Logger.Warn("Durable idempotency key could not be released",
+			"idempotencyKey", eff.IdempotencyKey,
+			"err", err,
+		)
+	}
+}
+
+// purgeDurableKeys drops expired claims from the store.
+func (tr *TaskRouter) purgeDurableKeys() {
+	if tr.cfg.IdempotencyStore == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(tr.ctx, durableKeyTimeout)
+	defer cancel()
+	if _, err := tr.cfg.IdempotencyStore.PurgeExpiredTaskKeys(ctx); err != nil && ctx.Err() == nil {
+		tr.cfg.Logger.Warn("Expired durable idempotency keys could not be purged", "err", err)
+	}
+}
@@ -0,0 +1,139 @@
+//go:build !legacy
+// +build !legacy
+
+package task
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memoryIdempotencyStore struct {
+	mu       sync.Mutex
+	keys     map[string]bool
+	claimErr error
+	released []string
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{keys: make(map[string]bool)}
+}
+
+func (s *memoryIdempotencyStore) ClaimTaskKey(_ context.Context, key string, _ time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.claimErr != nil {
+		return false, s.claimErr
+	}
+	if s.keys[key] {
+		return false, nil
+	}
+	s.keys[key] = true
+	return true, nil
+}
+
+func (s *memoryIdempotencyStore) ReleaseTaskKey(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, key)
+	s.released = append(s.released, key)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) PurgeExpiredTaskKeys(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (s *memoryIdempotencyStore) claimed(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.keys[key]
+}
+
+func newDurableTestRouter(t *testing.T, store IdempotencyStore) *TaskRouter {
+	t.Helper()
+	cfg := Defaults()
+	cfg.IdempotencyStore = store
+	router := NewRouter(cfg)
+	t.Cleanup(router.Close)
+	router.RegisterHandler("backfill", func(context.Context, any) error { return nil })
+	return router
+}
+
+func durableTask(key string) Task {
+	return Task{
+		Type: "backfill",
+		Options: TaskOptions{
+			IdempotencyKey:     key,
+			IdempotencyTTL:     time.Hour,
+			DurableIdempotency: true,
+		},
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	if got := IdempotencyKey("backfill", "123", "2024-01-02"); got != "backfill:123:2024-01-02" {
+		t.Fatalf("IdempotencyKey = %q", got)
+	}
+}
+
+func TestRouter_DurableIdempotencySpansRouters(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryIdempotencyStore()
+	first := newDurableTestRouter(t, store)
+	second := newDurableTestRouter(t, store)
+
+	key := IdempotencyKey("backfill", "123", "2024-01-02")
+	if err := first.Dispatch(context.Background(), durableTask(key)); err != nil {
+		t.Fatalf("first dispatch: %v", err)
+	}
+	if !store.claimed(key) {
+		t.Fatal("expected the key to be claimed in the store")
+	}
+	// A second router, as after a restart, only knows of the key through the store.
+	if err := second.Dispatch(context.Background(), durableTask(key)); !errors.Is(err, ErrDuplicateTask) {
+		t.Fatalf("expected ErrDuplicateTask from the second router, got %v", err)
+	}
+	// The rejected dispatch must not leave an in-memory reservation behind.
+	second.cfg.IdempotencyStore = nil
+	if err := second.Dispatch(context.Background(), durableTask(key)); err != nil {
+		t.Fatalf("dispatch without the store: %v", err)
+	}
+}
+
+func TestRouter_DurableIdempotencyFailsOpen(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryIdempotencyStore()
+	store.claimErr = errors.New("database unavailable")
+	router := newDurableTestRouter(t, store)
+
+	if err := router.Dispatch(context.Background(), durableTask("backfill:1")); err != nil {
+		t.Fatalf("expected dispatch to go ahead when the store fails, got %v", err)
+	}
+	if err := router.Dispatch(context.Background(), durableTask("backfill:1")); !errors.Is(err, ErrDuplicateTask) {
+		t.Fatalf("expected the in-memory key to still reject duplicates, got %v", err)
+	}
+}
+
+func TestRouter_NonDurableKeyStaysInMemory(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryIdempotencyStore()
+	router := newDurableTestRouter(t, store)
+
+	task := durableTask("backfill:2")
+	task.Options.DurableIdempotency = false
+	if err := router.Dispatch(context.Background(), task); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if store.claimed("backfill:2") {
+		t.Fatal("expected a non-durable key to stay out of the store")
+	}
+}
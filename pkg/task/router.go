@@ -47,6 +47,14 @@ type TaskOptions struct {
 
 	// IdempotencyTTL determines the survival duration of the idempotency token in memory.
 	IdempotencyTTL time.Duration
+
+	// DurableIdempotency also records IdempotencyKey in the router's
+	// IdempotencyStore, so the key holds across restarts and processes.
+	DurableIdempotency bool
+
+	// GuildID names the guild the task works for, so the router can share
+	// workers fairly between guilds. Empty for tasks of no guild.
+	GuildID string
 }
 
 // EmptyPayload serves as a zero-allocation marker for tasks requiring no dynamic context.
@@ -57,6 +65,11 @@ type Task struct {
 	Type    string
 	Payload any
 	Options TaskOptions
+
+	// Requester and OnComplete let long-running tasks report back to whoever
+	// started them. Both are optional.
+	Requester  Requester
+	OnComplete CompletionFunc
 }
 
 // RouterConfig defines the holistic tuning parameters for the task orchestration layer.
@@ -71,6 +84,18 @@ type RouterConfig struct {
 	GlobalMaxWorkers   int
 	GroupMaxParallel   int
 
+	// GuildMaxParallel caps the tasks of one guild executing at once. If 0,
+	// a guild may use every worker.
+	GuildMaxParallel int
+
+	// PriorityBoostAge is how long a task may wait for a worker before it
+	// goes ahead of tasks of less busy guilds. If 0, tasks are never boosted.
+	PriorityBoostAge time.Duration
+
+	// IdempotencyStore persists the keys of tasks dispatched with
+	// DurableIdempotency. If nil, keys are only held in memory.
+	IdempotencyStore IdempotencyStore
+
 	// ExecutionLimiter enables resource sharing across multiple router topologies.
 	ExecutionLimiter *ExecutionLimiter
 
@@ -93,54 +118,142 @@ func Defaults() RouterConfig {
 		CleanupInterval:    2 * time.Minute,
 		GlobalMaxWorkers:   0,
 		GroupMaxParallel:   1,
+		PriorityBoostAge:   30 * time.Second,
 		Clock:              clock.RealClock{},
 		Logger:             slog.New(slog.NewTextHandler(io.Discard, nil)),
 	}
 }
 
-// ExecutionLimiter bounds the aggregate concurrency ceiling via a counting semaphore.
+// ExecutionLimiter bounds the aggregate concurrency ceiling and hands freed
+// slots out fairly. Waiting tasks of the guild running the fewest tasks go
+// first, so one guild's backlog cannot hold every slot; tasks that have
+// waited past their boost age go ahead of all others, oldest first.
 type ExecutionLimiter struct {
-	sem chan struct{}
+	mu       sync.Mutex
+	capacity int // 0 bounds only the tasks of each guild
+	running  int
+	byGuild  map[string]int
+	waiters  []*slotWaiter
+}
+
+// slotWaiter is a task waiting for an execution slot.
+type slotWaiter struct {
+	guildID  string
+	guildMax int
+	queuedAt time.Time
+	boostAge time.Duration
+	ready    chan struct{}
 }
 
-// NewExecutionLimiter allocates a fixed-capacity execution bounded semaphore.
+// NewExecutionLimiter allocates a fixed-capacity execution limiter.
 // A capacity of 0 or less returns a nil limiter, which signifies unbounded execution.
 func NewExecutionLimiter(maxWorkers int) *ExecutionLimiter {
 	if maxWorkers <= 0 {
 		return nil
 	}
-	return &ExecutionLimiter{
-		sem: make(chan struct{}, maxWorkers),
-	}
+	return newExecutionLimiter(maxWorkers)
+}
+
+func newExecutionLimiter(capacity int) *ExecutionLimiter {
+	return &ExecutionLimiter{capacity: capacity, byGuild: make(map[string]int)}
 }
 
-// Acquire blocks until a concurrency token becomes available within the semaphore.
+// Acquire blocks until a concurrency token becomes available.
 func (l *ExecutionLimiter) Acquire() {
-	if l == nil || l.sem == nil {
+	l.acquire(&slotWaiter{}, time.Time{})
+}
+
+// Release yields a concurrency token back to the pool.
+func (l *ExecutionLimiter) Release() {
+	l.release("", time.Time{})
+}
+
+// Capacity interrogates the upper bound of concurrent executions.
+func (l *ExecutionLimiter) Capacity() int {
+	if l == nil {
+		return 0
+	}
+	return l.capacity
+}
+
+// Waiting reports how many tasks wait for a slot.
+func (l *ExecutionLimiter) Waiting() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.waiters)
+}
+
+// acquire queues w and blocks until it is granted a slot.
+func (l *ExecutionLimiter) acquire(w *slotWaiter, now time.Time) {
+	if l == nil {
 		return
 	}
-	// Blocks until capacity is freed.
-	l.sem <- struct{}{}
+	w.ready = make(chan struct{})
+	l.mu.Lock()
+	l.waiters = append(l.waiters, w)
+	l.grantLocked(now)
+	l.mu.Unlock()
+	<-w.ready
 }
 
-// Release yields a concurrency token back to the semaphore pool.
-func (l *ExecutionLimiter) Release() {
-	if l == nil || l.sem == nil {
+// release frees a slot of guildID and grants freed slots to waiters.
+func (l *ExecutionLimiter) release(guildID string, now time.Time) {
+	if l == nil {
 		return
 	}
-	select {
-	case <-l.sem:
-	default:
-		// Evades panic on over-release, which indicates a severe architectural failure but shouldn't crash the loop.
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.running == 0 {
+		// Evades corruption on over-release, which indicates a severe architectural failure but shouldn't crash the loop.
+		return
+	}
+	l.running--
+	if l.byGuild[guildID] <= 1 {
+		delete(l.byGuild, guildID)
+	} else {
+		l.byGuild[guildID]--
 	}
+	l.grantLocked(now)
 }
 
-// Capacity interrogates the upper bound of the concurrency semaphore.
-func (l *ExecutionLimiter) Capacity() int {
-	if l == nil || l.sem == nil {
-		return 0
+func (l *ExecutionLimiter) grantLocked(now time.Time) {
+	for l.capacity == 0 || l.running < l.capacity {
+		next := -1
+		for i, w := range l.waiters {
+			if w.guildMax > 0 && l.byGuild[w.guildID] >= w.guildMax {
+				continue
+			}
+			if next < 0 || l.precedes(w, l.waiters[next], now) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return
+		}
+		w := l.waiters[next]
+		l.waiters = slices.Delete(l.waiters, next, next+1)
+		l.running++
+		l.byGuild[w.guildID]++
+		close(w.ready)
+	}
+}
+
+// precedes reports whether a should get a slot before b.
+func (l *ExecutionLimiter) precedes(a, b *slotWaiter, now time.Time) bool {
+	aBoosted := a.boostAge > 0 && now.Sub(a.queuedAt) >= a.boostAge
+	bBoosted := b.boostAge > 0 && now.Sub(b.queuedAt) >= b.boostAge
+	if aBoosted != bBoosted {
+		return aBoosted
+	}
+	if !aBoosted {
+		if ra, rb := l.byGuild[a.guildID], l.byGuild[b.guildID]; ra != rb {
+			return ra < rb
+		}
 	}
-	return cap(l.sem)
+	return a.queuedAt.Before(b.queuedAt)
 }
 
 // Operational errors for routing boundaries.
@@ -149,6 +262,7 @@ var (
 	ErrUnknownTaskType = errors.New("unknown task type")
 	ErrDuplicateTask   = errors.New("duplicate task (idempotency key present)")
 	ErrRetrySilent     = errors.New("retryable task error (silent)")
+	ErrTaskPanic       = errors.New("task handler panicked")
 	errTaskEnqueue     = errors.New("task enqueue failed")
 )
 
@@ -207,6 +321,7 @@ type TaskRouter struct {
 	cronDispatchAttempts int64
 	cronDispatchSuccess  int64
 	cronDispatchFailures int64
+	recoveredPanics      int64
 
 	latencyMu       sync.Mutex
 	latenciesByType map[string]*observability.Summary
@@ -226,6 +341,13 @@ type groupWorker struct {
 type enqueuedTask struct {
 	task    Task
 	attempt int
+
+	dispatchedAt time.Time
+	summary      *summaryHolder
+
+	// queuedAt is when the task last joined its group's queue, at dispatch
+	// or when its retry came due.
+	queuedAt time.Time
 }
 
 type scheduledRetry struct {
@@ -330,6 +452,8 @@ func NewRouter(cfg RouterConfig) *TaskRouter {
 		tr.execLimiter = cfg.ExecutionLimiter
 	} else if cfg.GlobalMaxWorkers > 0 {
 		tr.execLimiter = NewExecutionLimiter(cfg.GlobalMaxWorkers)
+	} else if cfg.GuildMaxParallel > 0 {
+		tr.execLimiter = newExecutionLimiter(0)
 	}
 
 	tr.wg.Add(1)
@@ -354,8 +478,16 @@ func (tr *TaskRouter) Dispatch(ctx context.Context, t Task) error {
 	if err != nil {
 		return fmt.Errorf("TaskRouter.Dispatch: %w", err)
 	}
+	if err := tr.claimDurableKey(ctx, eff); err != nil {
+		tr.rollbackInflight(eff)
+		return fmt.Errorf("TaskRouter.Dispatch: %w", err)
+	}
 
-	enq := &enqueuedTask{task: t, attempt: 1}
+	now := tr.cfg.Clock.Now()
+	enq := &enqueuedTask{task: t, attempt: 1, dispatchedAt: now, queuedAt: now}
+	if t.OnComplete != nil {
+		enq.summary = &summaryHolder{}
+	}
 	for i := 0; i < maxEnqueueAttempts; i++ {
 		gw, ok := tr.getOrCreateGroup(groupKey)
 		if !ok || gw == nil {
@@ -458,6 +590,7 @@ type Stats struct {
 	CronDispatchAttempts int64
 	CronDispatchSuccess  int64
 	CronDispatchFailures int64
+	RecoveredPanics      int64
 }
 
 // Stats aggregates immediate internal counters inside a thread-safe read envelope.
@@ -472,6 +605,7 @@ func (tr *TaskRouter) Stats() Stats {
 		CronDispatchAttempts: atomic.LoadInt64(&tr.cronDispatchAttempts),
 		CronDispatchSuccess:  atomic.LoadInt64(&tr.cronDispatchSuccess),
 		CronDispatchFailures: atomic.LoadInt64(&tr.cronDispatchFailures),
+		RecoveredPanics:      atomic.LoadInt64(&tr.recoveredPanics),
 	}
 }
 
@@ -709,12 +843,17 @@ func (tr *TaskRouter) ensureGroupLocked(key string) *groupWorker {
 	return gw
 }
 
-func (tr *TaskRouter) acquireExecSlot() {
-	tr.execLimiter.Acquire()
+func (tr *TaskRouter) acquireExecSlot(et *enqueuedTask) {
+	tr.execLimiter.acquire(&slotWaiter{
+		guildID:  et.task.Options.GuildID,
+		guildMax: tr.cfg.GuildMaxParallel,
+		queuedAt: et.queuedAt,
+		boostAge: tr.cfg.PriorityBoostAge,
+	}, tr.cfg.Clock.Now())
 }
 
-func (tr *TaskRouter) releaseExecSlot() {
-	tr.execLimiter.Release()
+func (tr *TaskRouter) releaseExecSlot(et *enqueuedTask) {
+	tr.execLimiter.release(et.task.Options.GuildID, tr.cfg.Clock.Now())
 }
 
 func (tr *TaskRouter) groupLoop(gw *groupWorker) {
@@ -744,18 +883,37 @@ func (tr *TaskRouter) groupLoop(gw *groupWorker) {
 			if handler == nil {
 				gw.endWork(tr.nowNs())
 				tr.cfg.Logger.Warn("Task dropped (handler not registered)", "type", enq.task.Type, "group", gw.key)
+				tr.complete(enq, ErrUnknownTaskType)
 				continue
 			}
 
 			// Implements execution slot limitation across all topological boundaries to prevent host saturation.
-			tr.acquireExecSlot()
+			tr.acquireExecSlot(enq)
 			startExec := tr.cfg.Clock.Now()
-			err := func() error {
-				defer tr.releaseExecSlot()
+			err := func() (err error) {
+				defer tr.releaseExecSlot(enq)
+				// A panicking handler fails its task like any other error instead of
+				// unwinding the group worker and stranding the rest of its queue.
+				defer func() {
+					if r := recover(); r != nil {
+						atomic.AddInt64(&tr.recoveredPanics, 1)
+						observability.RecordRecoveredPanic("task", enq.task.Type)
+						tr.cfg.Logger.Error("Task handler panic recovered",
+							"type", enq.task.Type,
+							"group", gw.key,
+							"panic", r,
+							"stack", string(debug.Stack()),
+						)
+						err = fmt.Errorf("%w: %v", ErrTaskPanic, r)
+					}
+				}()
 				ctx := tr.ctx
 				if ctx == nil {
 					ctx = context.Background()
 				}
+				if enq.summary != nil {
+					ctx = context.WithValue(ctx, summaryKey{}, enq.summary)
+				}
 				return handler(ctx, enq.task.Payload)
 			}()
 			execDuration := tr.cfg.Clock.Now().Sub(startExec)
@@ -817,8 +975,10 @@ func (tr *TaskRouter) groupLoop(gw *groupWorker) {
 						"attempts", enq.attempt,
 						"err", err,
 					)
+					observability.ReportOperationalAlert(observability.AlertTaskDeadLetter, enq.task.Type, err)
 				}
 			}
+			tr.complete(enq, err)
 		}
 	}
 }
@@ -837,6 +997,7 @@ func (tr *TaskRouter) scheduleRetry(groupKey string, et *enqueuedTask, delay tim
 		groupKey: groupKey,
 		task:     et,
 	}
+	et.queuedAt = item.at
 
 	tr.retryMu.Lock()
 	tr.retrySeq++
@@ -1042,6 +1203,11 @@ func (tr *TaskRouter) dropStaleGroup(groupKey string, gw *groupWorker) {
 }
 
 func (tr *TaskRouter) rollbackIdempotencyReservation(eff TaskOptions) {
+	tr.rollbackInflight(eff)
+	tr.releaseDurableKey(eff)
+}
+
+func (tr *TaskRouter) rollbackInflight(eff TaskOptions) {
 	if eff.IdempotencyKey == "" {
 		return
 	}
@@ -1132,6 +1298,7 @@ func (tr *TaskRouter) cleanupOnce() {
 	for _, gw := range toClose {
 		gw.finishStop()
 	}
+	tr.purgeDurableKeys()
 }
 
 func (tr *TaskRouter) runCronOnce() {
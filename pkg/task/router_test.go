@@ -391,6 +391,37 @@ func TestRouter_Observability(t *testing.T) {
 	}
 }
 
+func TestRouter_RecoversHandlerPanic(t *testing.T) {
+	t.Parallel()
+
+	router := NewRouter(Defaults())
+	defer router.Close()
+
+	done := make(chan struct{})
+	router.RegisterHandler("panic_test", func(ctx context.Context, payload any) error {
+		var m map[string]int
+		m["boom"] = 1
+		return nil
+	})
+	router.RegisterHandler("after_panic", func(ctx context.Context, payload any) error {
+		close(done)
+		return nil
+	})
+
+	opts := TaskOptions{GroupKey: "shared", MaxAttempts: 1}
+	_ = router.Dispatch(context.Background(), Task{Type: "panic_test", Options: opts})
+	_ = router.Dispatch(context.Background(), Task{Type: "after_panic", Options: opts})
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected group worker to survive the panic and run the next task")
+	}
+	if got := router.Stats().RecoveredPanics; got != 1 {
+		t.Fatalf("expected 1 recovered panic, got %d", got)
+	}
+}
+
 // FuzzRouter_QueueMutation validates thread safety and bounds against corrupted payload shapes.
 func FuzzRouter_QueueMutation(f *testing.F) {
 	f.Add("group_1", "idem_1")
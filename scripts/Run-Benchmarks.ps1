@@ -0,0 +1,29 @@
+param(
+    [string]$Output = "bench.txt",
+    [int]$Count = 6
+)
+
+$ErrorActionPreference = "Stop"
+
+# The hot paths checked before a release. Compare two runs with
+# benchstat old.txt new.txt (golang.org/x/perf/cmd/benchstat).
+$benchmarks = @(
+    @{ Package = "./pkg/discord/cache/"; Pattern = "BenchmarkCache_" },
+    @{ Package = "./pkg/members/"; Pattern = "BenchmarkDiffRoles" },
+    @{ Package = "./pkg/storage/postgres/"; Pattern = "BenchmarkStore_UpsertGuildMemberSnapshots_Batch" },
+    @{ Package = "./pkg/discord/embeds/"; Pattern = "BenchmarkRender" },
+    @{ Package = "./pkg/discord/commands/core/"; Pattern = "BenchmarkSanitizeEmbed" }
+)
+
+Set-Location (Join-Path $PSScriptRoot "..")
+Remove-Item -ErrorAction SilentlyContinue $Output
+
+foreach ($b in $benchmarks) {
+    Write-Host "Running $($b.Pattern) in $($b.Package)..."
+    go test -run '^$' -bench $b.Pattern -benchmem -count $Count $b.Package | Out-File -Append -Encoding utf8 $Output
+    if ($LASTEXITCODE -ne 0) {
+        throw "Benchmarks failed in $($b.Package)"
+    }
+}
+
+Write-Host "Results written to $Output"